@@ -0,0 +1,13 @@
+package api
+
+import (
+	"net/http"
+	"nofx/mcp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleAIThrottle in-flight and queued AI calls per provider, with queue wait metrics (admin)
+func (s *Server) handleAIThrottle(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": mcp.ThrottleStats()})
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// maxFailedAttempts failures within attemptWindow before a key is locked out
+	maxFailedAttempts = 5
+	// attemptWindow period over which failures are counted
+	attemptWindow = 15 * time.Minute
+	// attemptLockout how long a key stays locked once it hits maxFailedAttempts
+	attemptLockout = 30 * time.Minute
+)
+
+// attemptRecord failures of one key in the current window
+type attemptRecord struct {
+	failures    int
+	windowStart time.Time
+	lockedUntil time.Time
+}
+
+// attemptLimiter counts failed verification attempts per key (e.g. "email:..." or "ip:...")
+// and locks a key out after too many failures. The zero value is ready to use.
+type attemptLimiter struct {
+	mu      sync.Mutex
+	records map[string]*attemptRecord
+	now     func() time.Time // Overridable in tests
+}
+
+func (l *attemptLimiter) clock() time.Time {
+	if l.now != nil {
+		return l.now()
+	}
+	return time.Now()
+}
+
+// locked whether any of the keys is currently locked out
+func (l *attemptLimiter) locked(keys ...string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock()
+	for _, key := range keys {
+		if rec, ok := l.records[key]; ok && now.Before(rec.lockedUntil) {
+			return true
+		}
+	}
+	return false
+}
+
+// fail records a failed attempt for every key, locking those that reach maxFailedAttempts
+func (l *attemptLimiter) fail(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.records == nil {
+		l.records = make(map[string]*attemptRecord)
+	}
+	now := l.clock()
+	for key, rec := range l.records {
+		// Drop stale records so the map does not grow with every address that ever failed
+		if now.Sub(rec.windowStart) > attemptWindow && !now.Before(rec.lockedUntil) {
+			delete(l.records, key)
+		}
+	}
+	for _, key := range keys {
+		rec, ok := l.records[key]
+		if !ok || now.Sub(rec.windowStart) > attemptWindow {
+			rec = &attemptRecord{windowStart: now}
+			l.records[key] = rec
+		}
+		rec.failures++
+		if rec.failures >= maxFailedAttempts {
+			rec.lockedUntil = now.Add(attemptLockout)
+			rec.failures = 0
+			rec.windowStart = now
+		}
+	}
+}
+
+// reset clears the failures of the keys after a successful attempt
+func (l *attemptLimiter) reset(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range keys {
+		delete(l.records, key)
+	}
+}
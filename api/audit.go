@@ -0,0 +1,246 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxAuditPayloadBytes caps the stored request summary
+const maxAuditPayloadBytes = 4096
+
+// maxAuditBodyBytes bodies larger than this are passed through but not summarized
+const maxAuditBodyBytes = 1 << 20
+
+// auditActions maps route templates to audit action names
+// Unlisted mutating routes are recorded as "<method> <route>"
+var auditActions = map[string]string{
+	"POST /api/traders":                                   "trader.create",
+	"PUT /api/traders/:id":                                "trader.update",
+	"DELETE /api/traders/:id":                             "trader.delete",
+	"POST /api/traders/:id/start":                         "trader.start",
+	"POST /api/traders/:id/stop":                          "trader.stop",
+	"PUT /api/traders/:id/prompt":                         "trader.update_prompt",
+	"POST /api/traders/:id/sync-balance":                  "trader.sync_balance",
+	"POST /api/traders/:id/close-position":                "trader.close_position",
+	"PUT /api/traders/:id/competition":                    "trader.toggle_competition",
+	"PUT /api/traders/:id/paper-mode":                     "trader.toggle_paper_mode",
+	"DELETE /api/traders/:id/paper-positions":             "trader.reset_paper_positions",
+	"POST /api/traders/:id/duplicate":                     "trader.duplicate",
+	"POST /api/traders/import":                            "trader.import",
+	"PUT /api/traders/:id/copy":                           "trader.copy_update",
+	"DELETE /api/traders/:id/copy":                        "trader.copy_delete",
+	"POST /api/traders/:id/share-links":                   "trader.share_link_create",
+	"DELETE /api/traders/:id/share-links/:linkId":         "trader.share_link_revoke",
+	"PUT /api/traders/:id/decision-rules":                 "trader.decision_rules_update",
+	"PUT /api/traders/:id/grid-config":                    "trader.update_grid_config",
+	"POST /api/traders/:id/coin-pool/overrides":           "trader.coin_override_set",
+	"DELETE /api/traders/:id/coin-pool/overrides/:symbol": "trader.coin_override_remove",
+	"POST /api/coin-pools":                                "coin_pool.create",
+	"PUT /api/coin-pools/:id":                             "coin_pool.update",
+	"DELETE /api/coin-pools/:id":                          "coin_pool.delete",
+	"POST /api/traders/:id/experiments":                   "experiment.create",
+	"POST /api/experiments/:id/stop":                      "experiment.stop",
+	"DELETE /api/experiments/:id":                         "experiment.delete",
+	"PUT /api/models":                                     "model.update",
+	"POST /api/exchanges":                                 "exchange.create",
+	"PUT /api/exchanges":                                  "exchange.update",
+	"DELETE /api/exchanges/:id":                           "exchange.delete",
+	"POST /api/exchanges/:id/verify":                      "exchange.verify_key",
+	"POST /api/strategies":                                "strategy.create",
+	"PUT /api/strategies/:id":                             "strategy.update",
+	"DELETE /api/strategies/:id":                          "strategy.delete",
+	"POST /api/strategies/:id/activate":                   "strategy.activate",
+	"POST /api/strategies/:id/duplicate":                  "strategy.duplicate",
+	"POST /api/debates":                                   "debate.create",
+	"POST /api/debates/:id/start":                         "debate.start",
+	"POST /api/debates/:id/cancel":                        "debate.cancel",
+	"POST /api/debates/:id/execute":                       "debate.execute",
+	"DELETE /api/debates/:id":                             "debate.delete",
+	"POST /api/admin/crypto/rotate":                       "admin.crypto_rotate",
+	"PUT /api/admin/maintenance-windows":                  "admin.maintenance_windows_update",
+	"POST /api/admin/users/otp-reset":                     "admin.user_otp_reset",
+	"POST /api/admin/kill-switch":                         "admin.kill_switch_engage",
+	"DELETE /api/admin/kill-switch":                       "admin.kill_switch_release",
+	"POST /api/recovery-codes":                            "auth.recovery_codes_regenerate",
+	"PUT /api/privacy":                                    "privacy.update",
+	"POST /api/user/export":                               "user.export",
+	"POST /api/user/import":                               "user.import",
+	"POST /api/logout":                                    "auth.logout",
+}
+
+// sensitiveAuditKeys request fields that are never written to the audit log
+var sensitiveAuditKeys = []string{
+	"key", "secret", "passphrase", "password", "token", "private", "otp", "plaintext", "ciphertext",
+}
+
+// auditMiddleware records every mutating request on the protected group
+// Must be chained after authMiddleware so user identity is available
+func (s *Server) auditMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method := c.Request.Method
+		if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		// Copy the start of the body for the summary, the handler still reads the full stream
+		var body []byte
+		if c.Request.Body != nil {
+			body, _ = io.ReadAll(io.LimitReader(c.Request.Body, maxAuditBodyBytes+1))
+			c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+		}
+
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		action, ok := auditActions[method+" "+route]
+		if !ok {
+			action = method + " " + route
+		}
+
+		resourceID := c.Param("id")
+		if resourceID == "" {
+			resourceID = c.Query("trader_id")
+		}
+
+		status := c.Writer.Status()
+		entry := &store.AuditLog{
+			Timestamp:  start,
+			UserID:     c.GetString("user_id"),
+			Email:      c.GetString("email"),
+			IP:         c.ClientIP(),
+			Action:     action,
+			Method:     method,
+			Path:       c.Request.URL.Path,
+			ResourceID: resourceID,
+			Payload:    summarizeAuditPayload(body),
+			StatusCode: status,
+			Success:    status < http.StatusBadRequest,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if len(c.Errors) > 0 {
+			entry.Error = c.Errors.String()
+		} else if !entry.Success {
+			entry.Error = http.StatusText(status)
+		}
+
+		if err := s.store.Audit().Append(entry); err != nil {
+			logger.Warnf("⚠️ Failed to write audit log for %s: %v", action, err)
+		}
+	}
+}
+
+// summarizeAuditPayload returns the JSON body with sensitive fields redacted
+func summarizeAuditPayload(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	if len(body) > maxAuditBodyBytes {
+		return "<body over " + strconv.Itoa(maxAuditBodyBytes) + " bytes, not summarized>"
+	}
+	var parsed interface{}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "<non-json body, " + strconv.Itoa(len(body)) + " bytes>"
+	}
+	out, err := json.Marshal(redactAuditValue(parsed))
+	if err != nil {
+		return ""
+	}
+	if len(out) > maxAuditPayloadBytes {
+		return string(out[:maxAuditPayloadBytes]) + "...(truncated)"
+	}
+	return string(out)
+}
+
+func redactAuditValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, inner := range val {
+			if isSensitiveAuditKey(k) {
+				if str, ok := inner.(string); ok && str == "" {
+					continue // Keep empty values visible: they mean "unchanged"
+				}
+				val[k] = "[REDACTED]"
+				continue
+			}
+			val[k] = redactAuditValue(inner)
+		}
+		return val
+	case []interface{}:
+		for i := range val {
+			val[i] = redactAuditValue(val[i])
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+func isSensitiveAuditKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, s := range sensitiveAuditKeys {
+		if strings.Contains(lower, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleAuditLogs Query audit logs (admin)
+// Query params: user_id, action (exact or prefix like "trader."), resource_id,
+// success (true/false), start/end (RFC3339 or unix ms), limit, offset
+func (s *Server) handleAuditLogs(c *gin.Context) {
+	filter := store.AuditFilter{
+		UserID:     c.Query("user_id"),
+		Action:     c.Query("action"),
+		ResourceID: c.Query("resource_id"),
+	}
+	if v := c.Query("success"); v != "" {
+		success := v == "true"
+		filter.Success = &success
+	}
+	var err error
+	if filter.Start, err = parseAuditTime(c.Query("start")); err != nil {
+		SafeBadRequest(c, "Invalid start time")
+		return
+	}
+	if filter.End, err = parseAuditTime(c.Query("end")); err != nil {
+		SafeBadRequest(c, "Invalid end time")
+		return
+	}
+	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "100"))
+	filter.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	logs, total, err := s.store.Audit().Query(filter)
+	if err != nil {
+		SafeInternalError(c, "Query audit logs", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"logs":  logs,
+		"total": total,
+	})
+}
+
+func parseAuditTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSummarizeAuditPayload(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:  "Empty body",
+			input: "",
+		},
+		{
+			name:        "Exchange credentials are redacted",
+			input:       `{"exchange_type":"binance","api_key":"abcdef123456","secret_key":"s3cr3t","passphrase":""}`,
+			contains:    []string{`"exchange_type":"binance"`, `"api_key":"[REDACTED]"`, `"passphrase":""`},
+			notContains: []string{"abcdef123456", "s3cr3t"},
+		},
+		{
+			name:        "Nested model config",
+			input:       `{"models":{"deepseek":{"enabled":true,"api_key":"sk-xyz"}}}`,
+			contains:    []string{`"enabled":true`},
+			notContains: []string{"sk-xyz"},
+		},
+		{
+			name:     "Non-JSON body",
+			input:    "raw=1",
+			contains: []string{"non-json body"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summarizeAuditPayload([]byte(tt.input))
+			if tt.input == "" && got != "" {
+				t.Fatalf("expected empty summary, got %q", got)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(got, s) {
+					t.Errorf("summary %q missing %q", got, s)
+				}
+			}
+			for _, s := range tt.notContains {
+				if strings.Contains(got, s) {
+					t.Errorf("summary %q leaked %q", got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestAuditMiddlewarePassesFullBody(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	var received int
+	router := gin.New()
+	router.Use(s.auditMiddleware())
+	router.POST("/api/upload", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		received = len(body)
+		c.Status(http.StatusOK)
+	})
+
+	body := bytes.Repeat([]byte("a"), maxAuditBodyBytes+1000)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/upload", bytes.NewReader(body)))
+	if received != len(body) {
+		t.Errorf("handler received %d bytes, want %d", received, len(body))
+	}
+	if got := summarizeAuditPayload(body); !strings.Contains(got, "not summarized") {
+		t.Errorf("summary of oversized body = %q", got)
+	}
+}
@@ -37,6 +37,16 @@ func (s *Server) registerBacktestRoutes(router *gin.RouterGroup) {
 	router.GET("/decisions", s.handleBacktestDecisions)
 	router.GET("/export", s.handleBacktestExport)
 	router.GET("/klines", s.handleBacktestKlines)
+	router.POST("/sweeps", s.handleBacktestSweepStart)
+	router.GET("/sweeps", s.handleBacktestSweeps)
+	router.GET("/sweeps/:id", s.handleBacktestSweep)
+	router.POST("/sweeps/:id/stop", s.handleBacktestSweepStop)
+	router.DELETE("/sweeps/:id", s.handleBacktestSweepDelete)
+	router.POST("/walk-forward", s.handleWalkForwardStart)
+	router.GET("/walk-forward", s.handleWalkForwards)
+	router.GET("/walk-forward/:id", s.handleWalkForward)
+	router.POST("/walk-forward/:id/stop", s.handleWalkForwardStop)
+	router.DELETE("/walk-forward/:id", s.handleWalkForwardDelete)
 }
 
 type backtestStartRequest struct {
@@ -74,44 +84,7 @@ func (s *Server) handleBacktestStart(c *gin.Context) {
 	logger.Infof("📊 Backtest request - symbols from request: %v (count=%d), strategyID: %s",
 		cfg.Symbols, len(cfg.Symbols), cfg.StrategyID)
 
-	// Load strategy config if strategy_id is provided
-	if cfg.StrategyID != "" {
-		strategy, err := s.store.Strategy().Get(cfg.UserID, cfg.StrategyID)
-		if err != nil {
-			SafeBadRequest(c, "Failed to load strategy")
-			return
-		}
-		if strategy == nil {
-			SafeBadRequest(c, "Strategy not found")
-			return
-		}
-		var strategyConfig store.StrategyConfig
-		if err := json.Unmarshal([]byte(strategy.Config), &strategyConfig); err != nil {
-			SafeBadRequest(c, "Failed to parse strategy config")
-			return
-		}
-		cfg.SetLoadedStrategy(&strategyConfig)
-		logger.Infof("📊 Backtest using saved strategy: %s (%s)", strategy.Name, strategy.ID)
-		logger.Infof("📊 Strategy coin source: type=%s, use_ai500=%v, use_oi_top=%v, static_coins=%v",
-			strategyConfig.CoinSource.SourceType,
-			strategyConfig.CoinSource.UseAI500,
-			strategyConfig.CoinSource.UseOITop,
-			strategyConfig.CoinSource.StaticCoins)
-
-		// If no symbols provided, fetch from strategy's coin source
-		if len(cfg.Symbols) == 0 {
-			symbols, err := s.resolveStrategyCoins(&strategyConfig)
-			if err != nil {
-				SafeBadRequest(c, "Failed to resolve coins from strategy")
-				return
-			}
-			cfg.Symbols = symbols
-			logger.Infof("📊 Resolved %d coins from strategy: %v", len(symbols), symbols)
-		}
-	}
-
-	if err := s.hydrateBacktestAIConfig(&cfg); err != nil {
-		SafeBadRequest(c, "Failed to configure AI model")
+	if !s.prepareBacktestConfig(c, &cfg) {
 		return
 	}
 
@@ -573,6 +546,51 @@ func (s *Server) handleBacktestKlines(c *gin.Context) {
 	})
 }
 
+// prepareBacktestConfig loads the config's saved strategy and AI model, writing the error response on failure
+func (s *Server) prepareBacktestConfig(c *gin.Context, cfg *backtest.BacktestConfig) bool {
+	// Load strategy config if strategy_id is provided
+	if cfg.StrategyID != "" {
+		strategy, err := s.store.Strategy().Get(cfg.UserID, cfg.StrategyID)
+		if err != nil {
+			SafeBadRequest(c, "Failed to load strategy")
+			return false
+		}
+		if strategy == nil {
+			SafeBadRequest(c, "Strategy not found")
+			return false
+		}
+		var strategyConfig store.StrategyConfig
+		if err := json.Unmarshal([]byte(strategy.Config), &strategyConfig); err != nil {
+			SafeBadRequest(c, "Failed to parse strategy config")
+			return false
+		}
+		cfg.SetLoadedStrategy(&strategyConfig)
+		logger.Infof("📊 Backtest using saved strategy: %s (%s)", strategy.Name, strategy.ID)
+		logger.Infof("📊 Strategy coin source: type=%s, use_ai500=%v, use_oi_top=%v, static_coins=%v",
+			strategyConfig.CoinSource.SourceType,
+			strategyConfig.CoinSource.UseAI500,
+			strategyConfig.CoinSource.UseOITop,
+			strategyConfig.CoinSource.StaticCoins)
+
+		// If no symbols provided, fetch from strategy's coin source
+		if len(cfg.Symbols) == 0 {
+			symbols, err := s.resolveStrategyCoins(&strategyConfig)
+			if err != nil {
+				SafeBadRequest(c, "Failed to resolve coins from strategy")
+				return false
+			}
+			cfg.Symbols = symbols
+			logger.Infof("📊 Resolved %d coins from strategy: %v", len(symbols), symbols)
+		}
+	}
+
+	if err := s.hydrateBacktestAIConfig(cfg); err != nil {
+		SafeBadRequest(c, "Failed to configure AI model")
+		return false
+	}
+	return true
+}
+
 func queryInt(c *gin.Context, name string, fallback int) int {
 	if value := c.Query(name); value != "" {
 		if v, err := strconv.Atoi(value); err == nil {
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"nofx/backtest"
+	"nofx/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleBacktestSweepStart starts a parameter sweep: one backtest run per parameter combination
+func (s *Server) handleBacktestSweepStart(c *gin.Context) {
+	if s.backtestManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		return
+	}
+
+	var cfg backtest.SweepConfig
+	if err := c.ShouldBindJSON(&cfg); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if strings.TrimSpace(cfg.SweepID) == "" {
+		cfg.SweepID = "sweep_" + time.Now().UTC().Format("20060102_150405")
+	}
+	cfg.UserID = normalizeUserID(c.GetString("user_id"))
+	cfg.Base.UserID = cfg.UserID
+	cfg.Base.CustomPrompt = strings.TrimSpace(cfg.Base.CustomPrompt)
+
+	if !s.prepareBacktestConfig(c, &cfg.Base) {
+		return
+	}
+
+	sweep, err := s.backtestManager.StartSweep(cfg)
+	if err != nil {
+		SafeError(c, http.StatusBadRequest, "Failed to start backtest sweep", err)
+		return
+	}
+	logger.Infof("📊 Backtest sweep %s started: %d runs, symbols=%v", sweep.SweepID, len(sweep.Results), sweep.Symbols)
+	c.JSON(http.StatusOK, sweep)
+}
+
+// handleBacktestSweeps lists the user's sweeps, newest first
+func (s *Server) handleBacktestSweeps(c *gin.Context) {
+	if s.backtestManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		return
+	}
+	rawUserID := strings.TrimSpace(c.GetString("user_id"))
+	userID := normalizeUserID(rawUserID)
+	filterByUser := rawUserID != "" && rawUserID != "admin"
+
+	sweeps, err := s.backtestManager.ListSweeps()
+	if err != nil {
+		SafeInternalError(c, "List backtest sweeps", err)
+		return
+	}
+	items := make([]*backtest.Sweep, 0, len(sweeps))
+	for _, sweep := range sweeps {
+		if filterByUser {
+			owner := strings.TrimSpace(sweep.UserID)
+			if owner != "" && owner != userID {
+				continue
+			}
+		}
+		items = append(items, sweep)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"total": len(items),
+		"items": items,
+	})
+}
+
+// handleBacktestSweep returns a sweep with its ranked result matrix
+func (s *Server) handleBacktestSweep(c *gin.Context) {
+	sweep, ok := s.loadOwnedBacktestSweep(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, sweep)
+}
+
+// handleBacktestSweepStop stops the sweep's active runs and skips the remaining ones
+func (s *Server) handleBacktestSweepStop(c *gin.Context) {
+	sweep, ok := s.loadOwnedBacktestSweep(c)
+	if !ok {
+		return
+	}
+	if err := s.backtestManager.StopSweep(sweep.SweepID); err != nil {
+		SafeError(c, http.StatusBadRequest, "Failed to stop backtest sweep", err)
+		return
+	}
+	if stopped, err := s.backtestManager.GetSweep(sweep.SweepID); err == nil {
+		sweep = stopped
+	}
+	c.JSON(http.StatusOK, sweep)
+}
+
+// handleBacktestSweepDelete deletes the sweep together with its runs
+func (s *Server) handleBacktestSweepDelete(c *gin.Context) {
+	sweep, ok := s.loadOwnedBacktestSweep(c)
+	if !ok {
+		return
+	}
+	if err := s.backtestManager.DeleteSweep(sweep.SweepID); err != nil {
+		SafeInternalError(c, "Delete backtest sweep", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// loadOwnedBacktestSweep loads the sweep named by the :id path parameter, writing the error response on failure
+func (s *Server) loadOwnedBacktestSweep(c *gin.Context) (*backtest.Sweep, bool) {
+	if s.backtestManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		return nil, false
+	}
+	sweep, err := s.backtestManager.GetSweep(c.Param("id"))
+	if writeBacktestAccessError(c, err) {
+		return nil, false
+	}
+	if !ownsBacktestJob(c, sweep.UserID) {
+		return nil, false
+	}
+	return sweep, true
+}
+
+// ownsBacktestJob reports whether the user may access a sweep or walk-forward, writing the error response if not
+func ownsBacktestJob(c *gin.Context, owner string) bool {
+	userID := normalizeUserID(c.GetString("user_id"))
+	owner = strings.TrimSpace(owner)
+	if userID != "admin" && owner != "" && owner != userID {
+		writeBacktestAccessError(c, errBacktestForbidden)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"nofx/backtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleWalkForwardStart starts a walk-forward validation over rolling train/test windows
+func (s *Server) handleWalkForwardStart(c *gin.Context) {
+	if s.backtestManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		return
+	}
+
+	var cfg backtest.WalkForwardConfig
+	if err := c.ShouldBindJSON(&cfg); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if strings.TrimSpace(cfg.WalkForwardID) == "" {
+		cfg.WalkForwardID = "wf_" + time.Now().UTC().Format("20060102_150405")
+	}
+	cfg.UserID = normalizeUserID(c.GetString("user_id"))
+	cfg.Base.UserID = cfg.UserID
+	cfg.Base.CustomPrompt = strings.TrimSpace(cfg.Base.CustomPrompt)
+
+	if !s.prepareBacktestConfig(c, &cfg.Base) {
+		return
+	}
+
+	wf, err := s.backtestManager.StartWalkForward(cfg)
+	if err != nil {
+		SafeError(c, http.StatusBadRequest, "Failed to start walk-forward validation", err)
+		return
+	}
+	c.JSON(http.StatusOK, wf)
+}
+
+// handleWalkForwards lists the user's walk-forward validations, newest first
+func (s *Server) handleWalkForwards(c *gin.Context) {
+	if s.backtestManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		return
+	}
+	rawUserID := strings.TrimSpace(c.GetString("user_id"))
+	userID := normalizeUserID(rawUserID)
+	filterByUser := rawUserID != "" && rawUserID != "admin"
+
+	wfs, err := s.backtestManager.ListWalkForwards()
+	if err != nil {
+		SafeInternalError(c, "List walk-forward validations", err)
+		return
+	}
+	items := make([]*backtest.WalkForward, 0, len(wfs))
+	for _, wf := range wfs {
+		if filterByUser {
+			owner := strings.TrimSpace(wf.UserID)
+			if owner != "" && owner != userID {
+				continue
+			}
+		}
+		items = append(items, wf)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"total": len(items),
+		"items": items,
+	})
+}
+
+// handleWalkForward returns a walk-forward validation with its windows and summary
+func (s *Server) handleWalkForward(c *gin.Context) {
+	wf, ok := s.loadOwnedWalkForward(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, wf)
+}
+
+// handleWalkForwardStop stops the active runs and skips the remaining windows
+func (s *Server) handleWalkForwardStop(c *gin.Context) {
+	wf, ok := s.loadOwnedWalkForward(c)
+	if !ok {
+		return
+	}
+	if err := s.backtestManager.StopWalkForward(wf.WalkForwardID); err != nil {
+		SafeError(c, http.StatusBadRequest, "Failed to stop walk-forward validation", err)
+		return
+	}
+	if stopped, err := s.backtestManager.GetWalkForward(wf.WalkForwardID); err == nil {
+		wf = stopped
+	}
+	c.JSON(http.StatusOK, wf)
+}
+
+// handleWalkForwardDelete deletes the walk-forward validation together with its runs
+func (s *Server) handleWalkForwardDelete(c *gin.Context) {
+	wf, ok := s.loadOwnedWalkForward(c)
+	if !ok {
+		return
+	}
+	if err := s.backtestManager.DeleteWalkForward(wf.WalkForwardID); err != nil {
+		SafeInternalError(c, "Delete walk-forward validation", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// loadOwnedWalkForward loads the walk-forward named by the :id path parameter, writing the error response on failure
+func (s *Server) loadOwnedWalkForward(c *gin.Context) (*backtest.WalkForward, bool) {
+	if s.backtestManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "backtest manager unavailable"})
+		return nil, false
+	}
+	wf, err := s.backtestManager.GetWalkForward(c.Param("id"))
+	if writeBacktestAccessError(c, err) {
+		return nil, false
+	}
+	if !ownsBacktestJob(c, wf.UserID) {
+		return nil, false
+	}
+	return wf, true
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetCircuitBreaker Get trader circuit breaker state (trip reason, cooldown)
+func (s *Server) handleGetCircuitBreaker(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	state, err := s.store.CircuitBreaker().Get(traderID)
+	if err != nil {
+		SafeInternalError(c, "Get circuit breaker state", err)
+		return
+	}
+	if state == nil {
+		c.JSON(http.StatusOK, gin.H{"trader_id": traderID, "tripped": false, "in_cooldown": false})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trader_id":      state.TraderID,
+		"tripped":        state.Tripped,
+		"in_cooldown":    state.InCooldown(time.Now()),
+		"trip_reason":    state.TripReason,
+		"tripped_at":     state.TrippedAt,
+		"cooldown_until": state.CooldownUntil,
+		"peak_equity":    state.PeakEquity,
+	})
+}
@@ -0,0 +1,252 @@
+package api
+
+import (
+	"net/http"
+	"nofx/kernel"
+	"nofx/provider/nofxos"
+	"nofx/store"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CoinPoolRequest create/update custom coin pool request
+type CoinPoolRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	Symbols     []string `json:"symbols" binding:"required"`
+}
+
+// CoinOverrideRequest pin/blacklist a symbol for a trader
+type CoinOverrideRequest struct {
+	Symbol string `json:"symbol" binding:"required"`
+	Mode   string `json:"mode" binding:"required"` // pin/blacklist
+}
+
+// handleGetAI500Pool View current AI500 pool with scores
+func (s *Server) handleGetAI500Pool(c *gin.Context) {
+	coins, err := nofxos.DefaultClient().GetAI500List()
+	if err != nil {
+		SafeInternalError(c, "Get AI500 pool", err)
+		return
+	}
+
+	sort.Slice(coins, func(i, j int) bool { return coins[i].Score > coins[j].Score })
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 && limit < len(coins) {
+		coins = coins[:limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{"coins": coins, "count": len(coins)})
+}
+
+// handleGetOITopPool View current OI Top pool (open interest increase ranking)
+func (s *Server) handleGetOITopPool(c *gin.Context) {
+	positions, err := nofxos.DefaultClient().GetOITopPositions()
+	if err != nil {
+		SafeInternalError(c, "Get OI Top pool", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"positions": positions, "count": len(positions)})
+}
+
+// handleListCoinPools List user's custom coin pools
+func (s *Server) handleListCoinPools(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	pools, err := s.store.CoinPool().List(userID)
+	if err != nil {
+		SafeInternalError(c, "List coin pools", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pools": pools})
+}
+
+// handleCreateCoinPool Create custom coin pool
+func (s *Server) handleCreateCoinPool(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req CoinPoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	pool := &store.CoinPool{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	pool.SetSymbols(req.Symbols)
+	if len(pool.GetSymbols()) == 0 {
+		SafeBadRequest(c, "Coin pool must contain at least one symbol")
+		return
+	}
+
+	if err := s.store.CoinPool().Create(pool); err != nil {
+		SafeInternalError(c, "Create coin pool", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pool)
+}
+
+// handleUpdateCoinPool Update custom coin pool
+func (s *Server) handleUpdateCoinPool(c *gin.Context) {
+	userID := c.GetString("user_id")
+	poolID := c.Param("id")
+
+	var req CoinPoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	pool, err := s.store.CoinPool().Get(userID, poolID)
+	if err != nil {
+		SafeNotFound(c, "Coin pool")
+		return
+	}
+	pool.Name = req.Name
+	pool.Description = req.Description
+	pool.SetSymbols(req.Symbols)
+	if len(pool.GetSymbols()) == 0 {
+		SafeBadRequest(c, "Coin pool must contain at least one symbol")
+		return
+	}
+
+	if err := s.store.CoinPool().Update(pool); err != nil {
+		SafeInternalError(c, "Update coin pool", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pool)
+}
+
+// handleDeleteCoinPool Delete custom coin pool
+func (s *Server) handleDeleteCoinPool(c *gin.Context) {
+	userID := c.GetString("user_id")
+	poolID := c.Param("id")
+
+	if _, err := s.store.CoinPool().Get(userID, poolID); err != nil {
+		SafeNotFound(c, "Coin pool")
+		return
+	}
+	if err := s.store.CoinPool().Delete(userID, poolID); err != nil {
+		SafeInternalError(c, "Delete coin pool", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Coin pool deleted"})
+}
+
+// handleGetTraderCoinPool View trader's effective candidate coins and pin/blacklist overrides
+func (s *Server) handleGetTraderCoinPool(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	trader, err := s.store.Trader().Get(userID, traderID)
+	if err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	overrides, err := s.store.CoinPool().ListOverrides(traderID)
+	if err != nil {
+		SafeInternalError(c, "Get coin overrides", err)
+		return
+	}
+
+	var strategy *store.Strategy
+	if trader.StrategyID != "" {
+		strategy, err = s.store.Strategy().Get(userID, trader.StrategyID)
+	} else {
+		strategy, err = s.store.Strategy().GetActive(userID)
+	}
+	if err != nil {
+		SafeNotFound(c, "Strategy")
+		return
+	}
+	config, err := strategy.ParseConfig()
+	if err != nil {
+		SafeInternalError(c, "Parse strategy config", err)
+		return
+	}
+
+	engine := kernel.NewStrategyEngine(config)
+	engine.SetCoinPoolSource(s.store.CoinPool().ForTrader(userID, traderID))
+	candidates, err := engine.GetCandidateCoins()
+	if err != nil {
+		SafeInternalError(c, "Get candidate coins", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trader_id":      traderID,
+		"strategy_id":    strategy.ID,
+		"source_type":    config.CoinSource.SourceType,
+		"custom_pool_id": config.CoinSource.CustomPoolID,
+		"candidates":     candidates,
+		"overrides":      overrides,
+	})
+}
+
+// handleSetTraderCoinOverride Pin or blacklist a symbol for a trader
+func (s *Server) handleSetTraderCoinOverride(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req CoinOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if req.Mode != store.CoinOverridePin && req.Mode != store.CoinOverrideBlacklist {
+		SafeBadRequest(c, "Mode must be 'pin' or 'blacklist'")
+		return
+	}
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+	if err := s.store.CoinPool().SetOverride(traderID, req.Symbol, req.Mode); err != nil {
+		SafeInternalError(c, "Set coin override", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Coin override saved"})
+}
+
+// handleDeleteTraderCoinOverride Remove a trader's pin/blacklist for a symbol
+func (s *Server) handleDeleteTraderCoinOverride(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+	if err := s.store.CoinPool().RemoveOverride(traderID, c.Param("symbol")); err != nil {
+		SafeInternalError(c, "Remove coin override", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Coin override removed"})
+}
+
+// checkStrategyCoinPool verifies the custom coin pool a strategy config selects belongs to the user
+func (s *Server) checkStrategyCoinPool(c *gin.Context, cfg *store.StrategyConfig) bool {
+	if cfg.CoinSource.CustomPoolID == "" {
+		return true
+	}
+	if _, err := s.store.CoinPool().Get(c.GetString("user_id"), cfg.CoinSource.CustomPoolID); err != nil {
+		SafeBadRequest(c, "Coin pool not found")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"nofx/store"
+)
+
+func TestStrategyRejectsOtherUsersCoinPool(t *testing.T) {
+	s, call := newTestServer(t)
+	st := s.store
+
+	pool := &store.CoinPool{ID: "pool-u2", UserID: "u2", Name: "majors"}
+	pool.SetSymbols([]string{"BTC", "ETH"})
+	if err := st.CoinPool().Create(pool); err != nil {
+		t.Fatal(err)
+	}
+
+	create := func(userID string) int {
+		body := `{"name":"s","config":{"coin_source":{"source_type":"custom","custom_pool_id":"pool-u2"}}}`
+		return call(s.handleCreateStrategy, testRequest{method: http.MethodPost, target: "/api/strategies", body: body, userID: userID}).Code
+	}
+	if code := create("u1"); code != http.StatusBadRequest {
+		t.Errorf("other user's pool: expected 400, got %d", code)
+	}
+	if code := create("u2"); code != http.StatusOK {
+		t.Errorf("own pool: expected 200, got %d", code)
+	}
+
+	if _, err := st.CoinPool().ForTrader("u1", "t1").CustomPoolSymbols("pool-u2"); err == nil {
+		t.Error("a trader of u1 must not read u2's pool")
+	}
+	if symbols, err := st.CoinPool().ForTrader("u2", "t2").CustomPoolSymbols("pool-u2"); err != nil || len(symbols) != 2 {
+		t.Errorf("owner's trader: %v %v", symbols, err)
+	}
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"fmt"
+	"nofx/trader"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// inverseExchanges exchange types with coin-margined inverse contract support
+var inverseExchanges = map[string]bool{"binance": true, "bybit": true}
+
+// marginCoinPattern margin coin of an inverse trader (e.g. BTC, ETH)
+var marginCoinPattern = regexp.MustCompile(`^[A-Z0-9]{2,10}$`)
+
+// resolveContractType validates the contract type of a trader on an exchange account ("" means linear).
+// Returns the type and margin coin as stored (inverse defaults to BTC margin); on failure a 400 has
+// been written and ok is false.
+func (s *Server) resolveContractType(c *gin.Context, userID, exchangeID, contractType, marginCoin string) (string, string, bool) {
+	switch strings.ToLower(strings.TrimSpace(contractType)) {
+	case "", trader.ContractLinear:
+		return trader.ContractLinear, "", true
+	case trader.ContractInverse:
+	default:
+		SafeBadRequest(c, fmt.Sprintf("Invalid contract type: %s, must be linear or inverse", contractType))
+		return "", "", false
+	}
+
+	exchange, err := s.store.Exchange().GetByID(userID, exchangeID)
+	if err != nil {
+		SafeNotFound(c, "Exchange")
+		return "", "", false
+	}
+	if !inverseExchanges[exchange.ExchangeType] {
+		SafeBadRequest(c, fmt.Sprintf("Inverse contracts are not supported on %s, use Binance or Bybit", exchange.ExchangeType))
+		return "", "", false
+	}
+
+	marginCoin = strings.ToUpper(strings.TrimSpace(marginCoin))
+	if marginCoin == "" {
+		marginCoin = "BTC"
+	}
+	if !marginCoinPattern.MatchString(marginCoin) {
+		SafeBadRequest(c, fmt.Sprintf("Invalid margin coin: %s", marginCoin))
+		return "", "", false
+	}
+	return trader.ContractInverse, marginCoin, true
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetCopyTrade Get the leader a trader copies and the traders copying it
+func (s *Server) handleGetCopyTrade(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	link, err := s.store.CopyTrade().GetByFollower(traderID)
+	if err != nil {
+		SafeInternalError(c, "Get copy trading link", err)
+		return
+	}
+	followers, err := s.store.CopyTrade().ListByLeader(traderID)
+	if err != nil {
+		SafeInternalError(c, "List copy trading followers", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"link": link, "followers": followers})
+}
+
+// handleUpdateCopyTrade Make a trader copy the executed decisions of another trader of the same user
+func (s *Server) handleUpdateCopyTrade(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req struct {
+		LeaderID        string   `json:"leader_id" binding:"required"`
+		Enabled         *bool    `json:"enabled"`
+		MaxPositionUSD  float64  `json:"max_position_usd"`
+		MaxLeverage     int      `json:"max_leverage"`
+		SymbolBlacklist []string `json:"symbol_blacklist"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if req.MaxPositionUSD < 0 || req.MaxLeverage < 0 {
+		SafeBadRequest(c, "Caps must not be negative")
+		return
+	}
+	if req.LeaderID == traderID {
+		SafeBadRequest(c, "A trader cannot copy itself")
+		return
+	}
+
+	follower, err := s.store.Trader().GetFullConfig(userID, traderID)
+	if err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+	leader, err := s.store.Trader().GetFullConfig(userID, req.LeaderID)
+	if err != nil {
+		SafeNotFound(c, "Leader trader")
+		return
+	}
+	if isGridTrader(follower) || isGridTrader(leader) {
+		SafeBadRequest(c, "Copy trading does not support grid trading strategies")
+		return
+	}
+
+	// Links never chain: a leader follows no one and a follower leads no one
+	if leaderLink, err := s.store.CopyTrade().GetByFollower(req.LeaderID); err != nil {
+		SafeInternalError(c, "Get copy trading link", err)
+		return
+	} else if leaderLink != nil {
+		SafeBadRequest(c, "The leader trader is itself copying another trader")
+		return
+	}
+	if followers, err := s.store.CopyTrade().ListByLeader(traderID); err != nil {
+		SafeInternalError(c, "List copy trading followers", err)
+		return
+	} else if len(followers) > 0 {
+		SafeBadRequest(c, "This trader is copied by other traders and cannot follow a leader")
+		return
+	}
+
+	blacklist := make([]string, 0, len(req.SymbolBlacklist))
+	for _, symbol := range req.SymbolBlacklist {
+		if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+			blacklist = append(blacklist, symbol)
+		}
+	}
+	link := &store.CopyTradeLink{
+		FollowerID:      traderID,
+		LeaderID:        req.LeaderID,
+		UserID:          userID,
+		Enabled:         req.Enabled == nil || *req.Enabled,
+		MaxPositionUSD:  req.MaxPositionUSD,
+		MaxLeverage:     req.MaxLeverage,
+		SymbolBlacklist: strings.Join(blacklist, ","),
+	}
+	if existing, err := s.store.CopyTrade().GetByFollower(traderID); err == nil && existing != nil {
+		link.CreatedAt = existing.CreatedAt
+	}
+	if err := s.store.CopyTrade().Save(link); err != nil {
+		SafeInternalError(c, "Save copy trading link", err)
+		return
+	}
+	s.traderManager.SetCopyLink(traderID, link)
+
+	msg := fmt.Sprintf("Copying trader %s", leader.Trader.Name)
+	if !link.Enabled {
+		msg = fmt.Sprintf("Copy trading of %s paused", leader.Trader.Name)
+	}
+	logger.Infof("✓ Trader %s copy trading link updated: leader=%s enabled=%v", traderID, req.LeaderID, link.Enabled)
+	s.recordTraderEvent(traderID, store.TraderEventConfigChange, msg, map[string]interface{}{
+		"leader_id":        req.LeaderID,
+		"enabled":          link.Enabled,
+		"max_position_usd": link.MaxPositionUSD,
+		"max_leverage":     link.MaxLeverage,
+		"symbol_blacklist": link.SymbolBlacklist,
+	})
+	c.JSON(http.StatusOK, gin.H{"link": link})
+}
+
+// handleDeleteCopyTrade Stop copying, the trader trades its own AI decisions again
+func (s *Server) handleDeleteCopyTrade(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+	if err := s.store.CopyTrade().Delete(userID, traderID); err != nil {
+		SafeInternalError(c, "Delete copy trading link", err)
+		return
+	}
+	s.traderManager.SetCopyLink(traderID, nil)
+
+	s.recordTraderEvent(traderID, store.TraderEventConfigChange, "Stopped copy trading", nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Copy trading stopped"})
+}
+
+// isGridTrader reports whether the trader runs a grid trading strategy
+func isGridTrader(cfg *store.TraderFullConfig) bool {
+	if cfg.Strategy == nil {
+		return false
+	}
+	strategyConfig, err := cfg.Strategy.ParseConfig()
+	return err == nil && strategyConfig.StrategyType == "grid_trading"
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"nofx/config"
@@ -12,6 +13,7 @@ import (
 // CryptoHandler Encryption API handler
 type CryptoHandler struct {
 	cryptoService *crypto.CryptoService
+	keyRotator    *crypto.KeyRotator
 }
 
 // NewCryptoHandler Creates encryption handler
@@ -21,6 +23,11 @@ func NewCryptoHandler(cryptoService *crypto.CryptoService) *CryptoHandler {
 	}
 }
 
+// SetKeyRotator Sets key rotator used by the admin rotation endpoints
+func (h *CryptoHandler) SetKeyRotator(rotator *crypto.KeyRotator) {
+	h.keyRotator = rotator
+}
+
 // ==================== Crypto Config Endpoint ====================
 
 // HandleGetCryptoConfig Get crypto configuration
@@ -76,6 +83,59 @@ func (h *CryptoHandler) HandleDecryptSensitiveData(c *gin.Context) {
 	})
 }
 
+// ==================== Key Rotation Endpoints (admin) ====================
+
+// HandleStartKeyRotation Start re-encrypting all stored secrets under the current data key
+func (h *CryptoHandler) HandleStartKeyRotation(c *gin.Context) {
+	if h.keyRotator == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Key rotation not available"})
+		return
+	}
+	if err := h.keyRotator.Start(); err != nil {
+		if errors.Is(err, crypto.ErrRotationInProgress) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		SafeInternalError(c, "Start key rotation", err)
+		return
+	}
+	log.Printf("🔑 Key rotation started by %s", c.GetString("email"))
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":       "Key rotation started",
+		"dual_key_mode": h.cryptoService.HasPreviousKeys(),
+		"progress":      h.keyRotator.Progress(),
+	})
+}
+
+// HandleGetKeyRotationStatus Get key rotation progress
+func (h *CryptoHandler) HandleGetKeyRotationStatus(c *gin.Context) {
+	if h.keyRotator == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Key rotation not available"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"dual_key_mode": h.cryptoService.HasPreviousKeys(),
+		"progress":      h.keyRotator.Progress(),
+	})
+}
+
+// HandleVerifyKeys Verify every stored secret is encrypted under the current key
+func (h *CryptoHandler) HandleVerifyKeys(c *gin.Context) {
+	if h.keyRotator == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Key rotation not available"})
+		return
+	}
+	report, err := h.keyRotator.Verify()
+	if err != nil {
+		SafeInternalError(c, "Verify keys", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"ok":     report.OK(),
+		"report": report,
+	})
+}
+
 // ==================== Audit Log Query Endpoint ====================
 
 // Audit log functionality removed, not needed in current simplified implementation
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxCycleJobsListed recent cycle jobs returned
+const maxCycleJobsListed = 100
+
+// handleGetCycleJobs A trader's decision cycle queue: recent jobs and queued/late/failed counts
+// Pending jobs overdue by more than one scan interval count as late.
+func (s *Server) handleGetCycleJobs(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	traderCfg, err := s.store.Trader().Get(userID, traderID)
+	if err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	jobs, err := s.store.CycleJob().List(traderID, maxCycleJobsListed)
+	if err != nil {
+		SafeInternalError(c, "Get cycle jobs", err)
+		return
+	}
+	counts, err := s.store.CycleJob().Counts(traderID, traderCfg.ScanInterval(), time.Now())
+	if err != nil {
+		SafeInternalError(c, "Count cycle jobs", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"counts": counts, "jobs": jobs})
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"net/http"
+	"nofx/config"
+	"nofx/logger"
+	"nofx/store"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxDailyStatsDays is the longest date range /statistics/daily returns
+const maxDailyStatsDays = 731
+
+// handleDailyStatistics Daily statistics of a trader (trades, win rate, PnL, fees, max intraday drawdown)
+// Served from the rollup table; from/to (YYYY-MM-DD) default to the last year in the owner's timezone.
+func (s *Server) handleDailyStatistics(c *gin.Context) {
+	userID := c.GetString("user_id")
+	_, traderID, err := s.getTraderFromQuery(c)
+	if err != nil {
+		SafeBadRequest(c, "Invalid trader ID")
+		return
+	}
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	loc := time.UTC
+	if user, err := s.store.User().GetByID(userID); err == nil {
+		loc = user.Location()
+	}
+	to := time.Now().In(loc)
+	if v := c.Query("to"); v != "" {
+		if to, err = time.ParseInLocation(store.DailyStatsDateLayout, v, loc); err != nil {
+			SafeBadRequest(c, "Invalid to date, expected YYYY-MM-DD")
+			return
+		}
+	}
+	from := to.AddDate(-1, 0, 1)
+	if v := c.Query("from"); v != "" {
+		if from, err = time.ParseInLocation(store.DailyStatsDateLayout, v, loc); err != nil {
+			SafeBadRequest(c, "Invalid from date, expected YYYY-MM-DD")
+			return
+		}
+	}
+	if from.After(to) || to.Sub(from) > maxDailyStatsDays*24*time.Hour {
+		SafeBadRequest(c, "Invalid date range")
+		return
+	}
+
+	days, err := s.store.DailyStats().List(traderID, from.Format(store.DailyStatsDateLayout), to.Format(store.DailyStatsDateLayout))
+	if err != nil {
+		SafeInternalError(c, "Get daily statistics", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"trader_id": traderID,
+		"timezone":  loc.String(),
+		"from":      from.Format(store.DailyStatsDateLayout),
+		"to":        to.Format(store.DailyStatsDateLayout),
+		"days":      days,
+	})
+}
+
+// handleGetTimezone Get the timezone daily statistics are computed in
+func (s *Server) handleGetTimezone(c *gin.Context) {
+	user, err := s.store.User().GetByID(c.GetString("user_id"))
+	if err != nil {
+		SafeNotFound(c, "User")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"timezone": user.Location().String()})
+}
+
+// handleUpdateTimezone Set the timezone daily statistics are computed in, rebuilding them in the background
+func (s *Server) handleUpdateTimezone(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req struct {
+		Timezone string `json:"timezone" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	loc, err := time.LoadLocation(strings.TrimSpace(req.Timezone))
+	if err != nil {
+		SafeBadRequest(c, "Unknown timezone, expected an IANA name such as Asia/Shanghai")
+		return
+	}
+	if err := s.store.User().UpdateTimezone(userID, loc.String()); err != nil {
+		SafeInternalError(c, "Update timezone", err)
+		return
+	}
+
+	traders, err := s.store.Trader().List(userID)
+	if err != nil {
+		SafeInternalError(c, "List traders", err)
+		return
+	}
+	traderIDs := make([]string, 0, len(traders))
+	for _, t := range traders {
+		traderIDs = append(traderIDs, t.ID)
+	}
+	if err := s.store.DailyStats().DeleteByTraders(traderIDs); err != nil {
+		SafeInternalError(c, "Reset daily statistics", err)
+		return
+	}
+	if days := config.Get().DailyStatsBackfillDays; days > 0 {
+		go func() {
+			now := time.Now()
+			for _, traderID := range traderIDs {
+				if _, err := s.store.DailyStats().Rollup(traderID, loc, now.AddDate(0, 0, -days), now); err != nil {
+					logger.Warnf("⚠️ Daily statistics rebuild failed for trader %s: %v", traderID, err)
+				}
+			}
+		}()
+	}
+
+	c.JSON(http.StatusOK, gin.H{"timezone": loc.String()})
+}
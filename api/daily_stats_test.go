@@ -0,0 +1,42 @@
+package api
+
+import (
+	"math"
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+func TestRollupDays_LocalTimezone(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, loc)
+	to := time.Date(2026, 3, 2, 23, 0, 0, 0, loc)
+
+	// 2026-03-01 17:00 UTC is already March 2nd in UTC+8
+	positions := []*store.TraderPosition{
+		{ExitTime: time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC).UnixMilli(), RealizedPnL: 30, Fee: 1},
+		{ExitTime: time.Date(2026, 3, 1, 5, 0, 0, 0, time.UTC).UnixMilli(), RealizedPnL: -10, Fee: 1},
+		{ExitTime: time.Date(2026, 3, 1, 17, 0, 0, 0, time.UTC).UnixMilli(), RealizedPnL: 5, Fee: 0.5},
+	}
+	snapshots := []*store.EquitySnapshot{
+		{Timestamp: time.Date(2026, 3, 1, 1, 0, 0, 0, loc), TotalEquity: 1000},
+		{Timestamp: time.Date(2026, 3, 1, 8, 0, 0, 0, loc), TotalEquity: 1100},
+		{Timestamp: time.Date(2026, 3, 1, 12, 0, 0, 0, loc), TotalEquity: 990},
+		{Timestamp: time.Date(2026, 3, 1, 20, 0, 0, 0, loc), TotalEquity: 1020},
+	}
+
+	days := store.RollupDays("t1", loc, from, to, positions, snapshots)
+	if len(days) != 2 || days[0].Date != "2026-03-01" || days[1].Date != "2026-03-02" {
+		t.Fatalf("expected rows for March 1st and 2nd, got %+v", days)
+	}
+	first, second := days[0], days[1]
+	if first.Trades != 2 || first.Wins != 1 || first.Losses != 1 || first.WinRate != 50 || first.PnL != 20 || first.Fees != 2 {
+		t.Errorf("unexpected March 1st trade stats: %+v", first)
+	}
+	if first.StartEquity != 1000 || first.EndEquity != 1020 || math.Abs(first.MaxDrawdownPct-10) > 1e-9 {
+		t.Errorf("expected 1100 -> 990 = 10%% intraday drawdown, got %+v", first)
+	}
+	if second.Trades != 1 || second.PnL != 5 || second.MaxDrawdownPct != 0 {
+		t.Errorf("unexpected March 2nd stats: %+v", second)
+	}
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// handleGetApprovalMode Get a trader's decision approval settings
+func (s *Server) handleGetApprovalMode(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	cfg, err := s.store.DecisionApproval().GetConfig(traderID)
+	if err != nil {
+		SafeInternalError(c, "Get approval settings", err)
+		return
+	}
+	if cfg == nil {
+		cfg = &store.ApprovalConfig{TraderID: traderID, UserID: userID, WindowMinutes: store.DefaultApprovalWindowMinutes}
+	}
+	c.JSON(http.StatusOK, cfg)
+}
+
+// handleUpdateApprovalMode Configure which opening decisions wait for approval
+// Changes apply from the next decision cycle, no restart needed.
+func (s *Server) handleUpdateApprovalMode(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	var req struct {
+		Enabled        bool    `json:"enabled"`
+		MinNotionalUSD float64 `json:"min_notional_usd"`
+		MinConfidence  int     `json:"min_confidence"`
+		WindowMinutes  int     `json:"window_minutes"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if req.MinNotionalUSD < 0 {
+		SafeBadRequest(c, "min_notional_usd must not be negative")
+		return
+	}
+	if req.MinConfidence < 0 || req.MinConfidence > 100 {
+		SafeBadRequest(c, "min_confidence must be between 0 and 100")
+		return
+	}
+	if req.WindowMinutes == 0 {
+		req.WindowMinutes = store.DefaultApprovalWindowMinutes
+	}
+	if req.WindowMinutes < 1 || req.WindowMinutes > store.MaxApprovalWindowMinutes {
+		SafeBadRequest(c, fmt.Sprintf("window_minutes must be between 1 and %d", store.MaxApprovalWindowMinutes))
+		return
+	}
+
+	cfg := &store.ApprovalConfig{
+		TraderID:       traderID,
+		UserID:         userID,
+		Enabled:        req.Enabled,
+		MinNotionalUSD: req.MinNotionalUSD,
+		MinConfidence:  req.MinConfidence,
+		WindowMinutes:  req.WindowMinutes,
+	}
+	if err := s.store.DecisionApproval().SaveConfig(cfg); err != nil {
+		SafeInternalError(c, "Save approval settings", err)
+		return
+	}
+
+	logger.Infof("✓ Approval mode of trader %s updated (enabled=%v)", traderID, cfg.Enabled)
+	c.JSON(http.StatusOK, cfg)
+}
+
+// handleGetPendingDecisions Get trader's decisions queued for approval (pending and recently decided)
+func (s *Server) handleGetPendingDecisions(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	limit := 50
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 500 {
+		limit = l
+	}
+
+	// Expire first so a stopped trader's queue does not show stale pending decisions
+	if _, err := s.store.DecisionApproval().ExpireDue(traderID, time.Now()); err != nil {
+		SafeInternalError(c, "Expire pending decisions", err)
+		return
+	}
+	decisions, err := s.store.DecisionApproval().List(traderID, limit)
+	if err != nil {
+		SafeInternalError(c, "Get pending decisions", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"decisions": decisions})
+}
+
+// handleApprovePendingDecision Approve a queued decision and execute it right away
+func (s *Server) handleApprovePendingDecision(c *gin.Context) {
+	traderID, id, ok := s.pendingDecisionParams(c)
+	if !ok {
+		return
+	}
+
+	// Executed by the running trader, checked before approving so the decision stays pending otherwise
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil || !at.IsRunning() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Trader must be running to execute approved decisions"})
+		return
+	}
+
+	pending, ok := s.decidePendingDecision(c, traderID, id, store.PendingDecisionApproved)
+	if !ok {
+		return
+	}
+	execErr := at.ExecuteApproved(pending)
+	if updated, err := s.store.DecisionApproval().Get(traderID, id); err == nil {
+		pending = updated
+	}
+	if execErr != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Approved decision failed to execute", "decision": pending})
+		return
+	}
+
+	logger.Infof("✓ Pending decision #%d of trader %s approved and executed", id, traderID)
+	c.JSON(http.StatusOK, gin.H{"decision": pending})
+}
+
+// handleRejectPendingDecision Reject a queued decision, it is never executed
+func (s *Server) handleRejectPendingDecision(c *gin.Context) {
+	traderID, id, ok := s.pendingDecisionParams(c)
+	if !ok {
+		return
+	}
+	pending, ok := s.decidePendingDecision(c, traderID, id, store.PendingDecisionRejected)
+	if !ok {
+		return
+	}
+
+	logger.Infof("✓ Pending decision #%d of trader %s rejected", id, traderID)
+	c.JSON(http.StatusOK, gin.H{"decision": pending})
+}
+
+// pendingDecisionParams checks trader ownership and parses the pending decision ID, writing the error response
+func (s *Server) pendingDecisionParams(c *gin.Context) (string, int64, bool) {
+	traderID := c.Param("id")
+	if _, err := s.store.Trader().Get(c.GetString("user_id"), traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return "", 0, false
+	}
+	id, err := strconv.ParseInt(c.Param("decisionId"), 10, 64)
+	if err != nil {
+		SafeBadRequest(c, "Invalid decision ID")
+		return "", 0, false
+	}
+	return traderID, id, true
+}
+
+// decidePendingDecision approves or rejects a decision inside its approval window, writing the error response
+func (s *Server) decidePendingDecision(c *gin.Context, traderID string, id int64, status string) (*store.PendingDecision, bool) {
+	pending, err := s.store.DecisionApproval().Decide(traderID, id, status, time.Now())
+	switch {
+	case err == nil:
+		return pending, true
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		SafeNotFound(c, "Pending decision")
+	case errors.Is(err, store.ErrDecisionNotPending):
+		c.JSON(http.StatusConflict, gin.H{"error": "Decision is no longer pending (already decided or expired)"})
+	default:
+		SafeInternalError(c, "Decide pending decision", err)
+	}
+	return nil, false
+}
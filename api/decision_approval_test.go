@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"nofx/manager"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPendingDecisionApprovalFlow(t *testing.T) {
+	s, call := newTestServer(t)
+	s.traderManager = manager.NewTraderManager()
+	st := s.store
+	if err := st.Trader().Create(&store.Trader{ID: "t1", UserID: "alice", Name: "Alpha"}); err != nil {
+		t.Fatalf("create trader: %v", err)
+	}
+
+	queue := func(expiresAt time.Time) int64 {
+		p := &store.PendingDecision{TraderID: "t1", UserID: "alice", Symbol: "BTCUSDT", Action: "open_long",
+			NotionalUSD: 5000, Decision: `{"symbol":"BTCUSDT","action":"open_long"}`, ExpiresAt: expiresAt.UnixMilli()}
+		if err := st.DecisionApproval().Create(p); err != nil {
+			t.Fatalf("queue decision: %v", err)
+		}
+		return p.ID
+	}
+	decide := func(handler gin.HandlerFunc, user string, id int64) *httptest.ResponseRecorder {
+		return call(handler, testRequest{method: http.MethodPost, userID: user,
+			params: gin.Params{{Key: "id", Value: "t1"}, {Key: "decisionId", Value: strconv.FormatInt(id, 10)}}})
+	}
+
+	open := queue(time.Now().Add(10 * time.Minute))
+	stale := queue(time.Now().Add(-time.Minute))
+
+	if w := decide(s.handleRejectPendingDecision, "bob", open); w.Code != http.StatusNotFound {
+		t.Fatalf("other users must not decide, got %d", w.Code)
+	}
+	// Approving needs the running trader to execute the decision, it stays pending otherwise
+	if w := decide(s.handleApprovePendingDecision, "alice", open); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 without a running trader, got %d", w.Code)
+	}
+	if w := decide(s.handleRejectPendingDecision, "alice", stale); w.Code != http.StatusConflict {
+		t.Fatalf("expired decisions cannot be decided, got %d", w.Code)
+	}
+	if w := decide(s.handleRejectPendingDecision, "alice", open); w.Code != http.StatusOK {
+		t.Fatalf("reject failed: %d %s", w.Code, w.Body.String())
+	}
+	if w := decide(s.handleRejectPendingDecision, "alice", open); w.Code != http.StatusConflict {
+		t.Fatalf("decisions are decided once, got %d", w.Code)
+	}
+
+	w := call(s.handleGetPendingDecisions, testRequest{userID: "alice", params: gin.Params{{Key: "id", Value: "t1"}}})
+	var resp struct {
+		Decisions []store.PendingDecision `json:"decisions"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	statuses := map[int64]string{}
+	for _, d := range resp.Decisions {
+		statuses[d.ID] = d.Status
+	}
+	if statuses[open] != store.PendingDecisionRejected || statuses[stale] != store.PendingDecisionExpired {
+		t.Errorf("unexpected statuses: %v", statuses)
+	}
+}
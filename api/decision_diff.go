@@ -0,0 +1,239 @@
+package api
+
+import (
+	"net/http"
+	"nofx/store"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxDecisionDiffs cycles compared by one decision diff request
+const maxDecisionDiffs = 50
+
+// reasoningTopics keywords (lowercase, English and Chinese) identifying what a reasoning trace talks about
+var reasoningTopics = map[string][]string{
+	"trend":           {"trend", "uptrend", "downtrend", "趋势"},
+	"breakout":        {"breakout", "breakdown", "突破", "跌破"},
+	"support":         {"support", "支撑"},
+	"resistance":      {"resistance", "阻力", "压力位"},
+	"rsi":             {"rsi"},
+	"macd":            {"macd"},
+	"ema":             {"ema"},
+	"bollinger":       {"bollinger", "boll", "布林"},
+	"volatility":      {"atr", "volatility", "波动"},
+	"volume":          {"volume", "成交量"},
+	"open_interest":   {"open interest", "oi ", "持仓量"},
+	"funding":         {"funding", "资金费率"},
+	"netflow":         {"netflow", "inflow", "outflow", "资金流"},
+	"sentiment":       {"sentiment", "fear", "greed", "情绪"},
+	"btc_correlation": {"btc dominance", "btc lead", "correlation", "大盘"},
+	"risk":            {"risk", "drawdown", "margin", "风险", "回撤", "保证金"},
+	"stop_loss":       {"stop loss", "stop-loss", "止损"},
+	"take_profit":     {"take profit", "take-profit", "止盈"},
+	"liquidation":     {"liquidation", "强平", "爆仓"},
+}
+
+// decisionDiff what changed between two consecutive decision cycles of a trader
+type decisionDiff struct {
+	FromID    int64     `json:"from_id"`
+	ToID      int64     `json:"to_id"`
+	FromCycle int       `json:"from_cycle"`
+	ToCycle   int       `json:"to_cycle"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+
+	PositionsOpened  []store.PositionSnapshot `json:"positions_opened,omitempty"`
+	PositionsClosed  []store.PositionSnapshot `json:"positions_closed,omitempty"`
+	PositionsResized []positionResize         `json:"positions_resized,omitempty"`
+	ActionChanges    []decisionActionChange   `json:"action_changes,omitempty"`
+
+	CandidatesAdded   []string `json:"candidates_added,omitempty"`
+	CandidatesRemoved []string `json:"candidates_removed,omitempty"`
+	TopicsAdded       []string `json:"topics_added,omitempty"`   // Reasoning topics only the newer cycle mentions
+	TopicsDropped     []string `json:"topics_dropped,omitempty"` // Reasoning topics only the older cycle mentions
+
+	EquityChange float64 `json:"equity_change"`
+}
+
+// positionResize a position held in both cycles with a different size
+type positionResize struct {
+	Symbol  string  `json:"symbol"`
+	Side    string  `json:"side"`
+	FromAmt float64 `json:"from_amt"`
+	ToAmt   float64 `json:"to_amt"`
+}
+
+// decisionActionChange a symbol whose decided action or confidence changed, an empty action means not decided on
+type decisionActionChange struct {
+	Symbol          string `json:"symbol"`
+	FromAction      string `json:"from_action"`
+	ToAction        string `json:"to_action"`
+	FromConfidence  int    `json:"from_confidence"`
+	ToConfidence    int    `json:"to_confidence"`
+	ConfidenceDelta int    `json:"confidence_delta"`
+}
+
+// handleDecisionDiff Structured diffs between consecutive decision cycles, newest first (supports limit parameter)
+func (s *Server) handleDecisionDiff(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Query("trader_id")
+
+	limit := 10
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			SafeBadRequest(c, "Invalid limit")
+			return
+		}
+		limit = min(parsed, maxDecisionDiffs)
+	}
+
+	fullConfig, err := s.store.Trader().GetFullConfig(userID, traderID)
+	if err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	// One more record than diffs: each diff needs the cycle before it
+	records, err := s.store.Decision().GetLatestRecords(fullConfig.Trader.ID, limit+1)
+	if err != nil {
+		SafeInternalError(c, "Get decision log", err)
+		return
+	}
+
+	diffs := make([]decisionDiff, 0, len(records))
+	for i := len(records) - 1; i > 0; i-- {
+		diffs = append(diffs, diffDecisionRecords(records[i-1], records[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"trader_id": fullConfig.Trader.ID,
+		"diffs":     diffs,
+	})
+}
+
+// diffDecisionRecords compares a decision cycle with the one before it
+func diffDecisionRecords(prev, curr *store.DecisionRecord) decisionDiff {
+	diff := decisionDiff{
+		FromID:       prev.ID,
+		ToID:         curr.ID,
+		FromCycle:    prev.CycleNumber,
+		ToCycle:      curr.CycleNumber,
+		FromTime:     prev.Timestamp,
+		ToTime:       curr.Timestamp,
+		EquityChange: curr.AccountState.TotalBalance - prev.AccountState.TotalBalance,
+	}
+
+	// Positions, keyed by symbol and side
+	prevPositions := make(map[string]store.PositionSnapshot, len(prev.Positions))
+	for _, p := range prev.Positions {
+		prevPositions[p.Symbol+"_"+p.Side] = p
+	}
+	currKeys := make(map[string]bool, len(curr.Positions))
+	for _, p := range curr.Positions {
+		key := p.Symbol + "_" + p.Side
+		currKeys[key] = true
+		old, held := prevPositions[key]
+		switch {
+		case !held:
+			diff.PositionsOpened = append(diff.PositionsOpened, p)
+		case old.PositionAmt != p.PositionAmt:
+			diff.PositionsResized = append(diff.PositionsResized, positionResize{Symbol: p.Symbol, Side: p.Side, FromAmt: old.PositionAmt, ToAmt: p.PositionAmt})
+		}
+	}
+	for _, p := range prev.Positions {
+		if !currKeys[p.Symbol+"_"+p.Side] {
+			diff.PositionsClosed = append(diff.PositionsClosed, p)
+		}
+	}
+
+	// Actions, the first decision of each symbol in a cycle
+	prevActions, currActions := actionsBySymbol(prev.Decisions), actionsBySymbol(curr.Decisions)
+	symbols := make(map[string]bool)
+	for symbol := range prevActions {
+		symbols[symbol] = true
+	}
+	for symbol := range currActions {
+		symbols[symbol] = true
+	}
+	for _, symbol := range sortedSet(symbols) {
+		from, to := prevActions[symbol], currActions[symbol]
+		if from.Action == to.Action && from.Confidence == to.Confidence {
+			continue
+		}
+		diff.ActionChanges = append(diff.ActionChanges, decisionActionChange{
+			Symbol:          symbol,
+			FromAction:      from.Action,
+			ToAction:        to.Action,
+			FromConfidence:  from.Confidence,
+			ToConfidence:    to.Confidence,
+			ConfidenceDelta: to.Confidence - from.Confidence,
+		})
+	}
+
+	diff.CandidatesAdded, diff.CandidatesRemoved = setDiff(toSet(prev.CandidateCoins), toSet(curr.CandidateCoins))
+	diff.TopicsAdded, diff.TopicsDropped = setDiff(reasoningTopicsOf(prev.CoTTrace), reasoningTopicsOf(curr.CoTTrace))
+	return diff
+}
+
+func actionsBySymbol(actions []store.DecisionAction) map[string]store.DecisionAction {
+	bySymbol := make(map[string]store.DecisionAction, len(actions))
+	for _, a := range actions {
+		if _, seen := bySymbol[a.Symbol]; !seen {
+			bySymbol[a.Symbol] = a
+		}
+	}
+	return bySymbol
+}
+
+// reasoningTopicsOf the topics a reasoning trace mentions
+func reasoningTopicsOf(trace string) map[string]bool {
+	text := strings.ToLower(trace)
+	topics := make(map[string]bool)
+	for topic, keywords := range reasoningTopics {
+		for _, keyword := range keywords {
+			if strings.Contains(text, keyword) {
+				topics[topic] = true
+				break
+			}
+		}
+	}
+	return topics
+}
+
+// setDiff the sorted elements only in b (added) and only in a (removed)
+func setDiff(a, b map[string]bool) (added, removed []string) {
+	for k := range b {
+		if !a[k] {
+			added = append(added, k)
+		}
+	}
+	for k := range a {
+		if !b[k] {
+			removed = append(removed, k)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+func sortedSet(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"fmt"
+	"nofx/store"
+	"testing"
+)
+
+func TestDiffDecisionRecords(t *testing.T) {
+	prev := &store.DecisionRecord{
+		ID: 1, CycleNumber: 7,
+		CoTTrace:       "BTC holds support, RSI oversold, funding negative",
+		CandidateCoins: []string{"BTCUSDT", "ETHUSDT"},
+		AccountState:   store.AccountSnapshot{TotalBalance: 1000},
+		Positions: []store.PositionSnapshot{
+			{Symbol: "BTCUSDT", Side: "long", PositionAmt: 0.1},
+			{Symbol: "ETHUSDT", Side: "short", PositionAmt: 2},
+		},
+		Decisions: []store.DecisionAction{
+			{Symbol: "BTCUSDT", Action: "hold", Confidence: 70},
+			{Symbol: "ETHUSDT", Action: "hold", Confidence: 60},
+		},
+	}
+	curr := &store.DecisionRecord{
+		ID: 2, CycleNumber: 8,
+		CoTTrace:       "BTC broke resistance with rising volume, RSI strong",
+		CandidateCoins: []string{"BTCUSDT", "SOLUSDT"},
+		AccountState:   store.AccountSnapshot{TotalBalance: 1012.5},
+		Positions: []store.PositionSnapshot{
+			{Symbol: "BTCUSDT", Side: "long", PositionAmt: 0.15},
+			{Symbol: "SOLUSDT", Side: "long", PositionAmt: 10},
+		},
+		Decisions: []store.DecisionAction{
+			{Symbol: "BTCUSDT", Action: "hold", Confidence: 85},
+			{Symbol: "ETHUSDT", Action: "close_short", Confidence: 60},
+			{Symbol: "SOLUSDT", Action: "open_long", Confidence: 75},
+		},
+	}
+
+	diff := diffDecisionRecords(prev, curr)
+	if diff.FromCycle != 7 || diff.ToCycle != 8 || diff.EquityChange != 12.5 {
+		t.Errorf("cycles %d -> %d, equity change %v", diff.FromCycle, diff.ToCycle, diff.EquityChange)
+	}
+	if len(diff.PositionsOpened) != 1 || diff.PositionsOpened[0].Symbol != "SOLUSDT" ||
+		len(diff.PositionsClosed) != 1 || diff.PositionsClosed[0].Symbol != "ETHUSDT" {
+		t.Errorf("opened %+v, closed %+v", diff.PositionsOpened, diff.PositionsClosed)
+	}
+	if len(diff.PositionsResized) != 1 || diff.PositionsResized[0].ToAmt != 0.15 {
+		t.Errorf("resized %+v", diff.PositionsResized)
+	}
+
+	changes := fmt.Sprint(diff.ActionChanges)
+	if want := "[{BTCUSDT hold hold 70 85 15} {ETHUSDT hold close_short 60 60 0} {SOLUSDT  open_long 0 75 75}]"; changes != want {
+		t.Errorf("action changes = %s", changes)
+	}
+	if fmt.Sprint(diff.CandidatesAdded, diff.CandidatesRemoved) != "[SOLUSDT] [ETHUSDT]" {
+		t.Errorf("candidates +%v -%v", diff.CandidatesAdded, diff.CandidatesRemoved)
+	}
+	if fmt.Sprint(diff.TopicsAdded, diff.TopicsDropped) != "[resistance volume] [funding support]" {
+		t.Errorf("topics +%v -%v", diff.TopicsAdded, diff.TopicsDropped)
+	}
+}
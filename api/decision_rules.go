@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxDecisionRules rules a trader may configure
+const maxDecisionRules = 50
+
+// handleGetDecisionRules Get a trader's decision rules in evaluation order
+func (s *Server) handleGetDecisionRules(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	rules, err := s.store.DecisionRule().List(traderID)
+	if err != nil {
+		SafeInternalError(c, "Get decision rules", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rules": rules})
+}
+
+// handleUpdateDecisionRules Replace a trader's decision rules, evaluated in the order sent
+// Changes apply from the next decision cycle, no restart needed.
+func (s *Server) handleUpdateDecisionRules(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	var req struct {
+		Rules []struct {
+			Type    string  `json:"type"`
+			Symbols string  `json:"symbols"`
+			Side    string  `json:"side"`
+			Value   float64 `json:"value"`
+			Enabled *bool   `json:"enabled"` // Default true
+			Note    string  `json:"note"`
+		} `json:"rules"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if len(req.Rules) > maxDecisionRules {
+		SafeBadRequest(c, fmt.Sprintf("At most %d decision rules allowed", maxDecisionRules))
+		return
+	}
+
+	rules := make([]*store.DecisionRule, 0, len(req.Rules))
+	for i, r := range req.Rules {
+		rule := &store.DecisionRule{
+			Type:    r.Type,
+			Symbols: r.Symbols,
+			Side:    r.Side,
+			Value:   r.Value,
+			Enabled: r.Enabled == nil || *r.Enabled,
+			Note:    r.Note,
+		}
+		if err := rule.Validate(); err != nil {
+			SafeBadRequest(c, fmt.Sprintf("Rule #%d: %v", i+1, err))
+			return
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := s.store.DecisionRule().Replace(userID, traderID, rules); err != nil {
+		SafeInternalError(c, "Save decision rules", err)
+		return
+	}
+
+	s.recordTraderEvent(traderID, store.TraderEventConfigChange,
+		fmt.Sprintf("Decision rules updated (%d rules)", len(rules)), nil)
+	logger.Infof("✓ Trader %s decision rules updated: %d rules", traderID, len(rules))
+	c.JSON(http.StatusOK, gin.H{"rules": rules})
+}
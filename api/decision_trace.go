@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"nofx/store"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// decisionExecution one decision action with the exchange order and fills it produced
+type decisionExecution struct {
+	Action store.DecisionAction `json:"action"`
+	Order  *store.TraderOrder   `json:"order,omitempty"` // nil if the order was not synced (yet)
+	Fills  []*store.TraderFill  `json:"fills,omitempty"`
+}
+
+// handleDecisionTrace Decision record with the full execution trace of its actions (for post-mortems)
+func (s *Server) handleDecisionTrace(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Query("trader_id")
+
+	decisionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || decisionID <= 0 {
+		SafeBadRequest(c, "Invalid decision ID")
+		return
+	}
+
+	fullConfig, err := s.store.Trader().GetFullConfig(userID, traderID)
+	if err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	record, err := s.store.Decision().GetRecord(fullConfig.Trader.ID, decisionID)
+	if err != nil {
+		SafeNotFound(c, "Decision")
+		return
+	}
+
+	execution := make([]decisionExecution, 0, len(record.Decisions))
+	for _, action := range record.Decisions {
+		entry := decisionExecution{Action: action}
+		exchangeOrderID := action.ExchangeOrderID
+		if exchangeOrderID == "" && action.OrderID > 0 {
+			exchangeOrderID = strconv.FormatInt(action.OrderID, 10)
+		}
+		if exchangeOrderID != "" && fullConfig.Exchange != nil {
+			order, err := s.store.Order().GetOrderByExchangeID(fullConfig.Exchange.ID, exchangeOrderID)
+			if err != nil {
+				SafeInternalError(c, "Get decision order", err)
+				return
+			}
+			if order != nil {
+				entry.Order = order
+				if entry.Fills, err = s.store.Order().GetOrderFills(order.ID); err != nil {
+					SafeInternalError(c, "Get decision fills", err)
+					return
+				}
+			}
+		}
+		execution = append(execution, entry)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"decision":  record,
+		"execution": execution,
+	})
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"time"
+
+	"nofx/logger"
+	"nofx/store"
+)
+
+// adjustedReturns returns the cumulative return (%) at each point of an equity series, deposits/withdrawals excluded
+// The first point is net PnL over the capital put in (base plus earlier deposits), matching the trader's
+// account PnL. Later points chain time-weighted returns: each interval's return removes the transfers within
+// it, so cash flows neither show as profit nor dilute the returns before and after them.
+// times are Unix milliseconds in ascending order, transfers are in time order.
+func adjustedReturns(base float64, times []int64, equities []float64, transfers []*store.TraderIncome) []float64 {
+	returns := make([]float64, len(equities))
+	if len(equities) == 0 {
+		return returns
+	}
+
+	idx := 0
+	flowsUntil := func(ms int64) (net, deposits float64) {
+		for idx < len(transfers) && transfers[idx].Time <= ms {
+			net += transfers[idx].Amount
+			if transfers[idx].Amount > 0 {
+				deposits += transfers[idx].Amount
+			}
+			idx++
+		}
+		return net, deposits
+	}
+
+	net, deposits := flowsUntil(times[0])
+	growth := 1.0
+	if capital := base + deposits; capital > 0 {
+		growth = 1 + (equities[0]-base-net)/capital
+	}
+	returns[0] = (growth - 1) * 100
+
+	for i := 1; i < len(equities); i++ {
+		net, _ := flowsUntil(times[i])
+		if prev := equities[i-1]; prev > 0 {
+			growth *= (equities[i] - net) / prev
+		}
+		returns[i] = (growth - 1) * 100
+	}
+	return returns
+}
+
+// traderTransfers returns a trader's transfers since its latest initial balance sync (earlier ones are part of the balance)
+func (s *Server) traderTransfers(traderID string) []*store.TraderIncome {
+	since, err := s.store.TraderEvent().LastTime(traderID, store.TraderEventBalanceSync)
+	if err != nil {
+		logger.Infof("⚠️ Failed to load balance sync time for %s: %v", traderID, err)
+	}
+	var sinceMs int64
+	if !since.IsZero() {
+		sinceMs = since.UnixMilli()
+	}
+	incomes, err := s.store.Income().List(traderID, sinceMs)
+	if err != nil {
+		logger.Infof("⚠️ Failed to load transfers for %s: %v", traderID, err)
+		return nil
+	}
+	var transfers []*store.TraderIncome
+	for _, income := range incomes {
+		if income.IncomeType == store.IncomeTypeTransfer {
+			transfers = append(transfers, income)
+		}
+	}
+	return transfers
+}
+
+// unixMillis converts timestamps to Unix milliseconds
+func unixMillis(timestamps []time.Time) []int64 {
+	ms := make([]int64, len(timestamps))
+	for i, t := range timestamps {
+		ms[i] = t.UnixMilli()
+	}
+	return ms
+}
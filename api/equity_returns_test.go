@@ -0,0 +1,39 @@
+package api
+
+import (
+	"math"
+	"testing"
+
+	"nofx/store"
+)
+
+func TestAdjustedReturns(t *testing.T) {
+	times := []int64{1000, 2000, 3000, 4000}
+
+	// Without transfers the return is plain equity over initial balance
+	got := adjustedReturns(1000, times, []float64{1000, 1100, 1050, 1200}, nil)
+	for i, want := range []float64{0, 10, 5, 20} {
+		if math.Abs(got[i]-want) > 1e-9 {
+			t.Errorf("point %d: return = %.4f, want %.4f", i, got[i], want)
+		}
+	}
+
+	// A 1000 deposit before the third point and a 600 withdrawal before the last one
+	transfers := []*store.TraderIncome{
+		{IncomeType: store.IncomeTypeTransfer, Amount: 1000, Time: 2500},
+		{IncomeType: store.IncomeTypeTransfer, Amount: -600, Time: 3500},
+	}
+	got = adjustedReturns(1000, times, []float64{1000, 1100, 2100, 1500}, transfers)
+	// +10%, then flat across the deposit, then 1500+600 over 2100 is flat again
+	for i, want := range []float64{0, 10, 10, 10} {
+		if math.Abs(got[i]-want) > 1e-9 {
+			t.Errorf("point %d: return = %.4f, want %.4f", i, got[i], want)
+		}
+	}
+
+	// A deposit before the first point counts as capital put in
+	got = adjustedReturns(1000, []int64{3000}, []float64{2200}, transfers[:1])
+	if math.Abs(got[0]-10) > 1e-9 {
+		t.Errorf("first point return = %.4f, want 10", got[0])
+	}
+}
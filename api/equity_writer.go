@@ -0,0 +1,17 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleEquityWriter queue depth and insert counters of the async equity snapshot writer (admin)
+func (s *Server) handleEquityWriter(c *gin.Context) {
+	stats := s.store.Equity().WriterStats()
+	if stats == nil {
+		c.JSON(http.StatusOK, gin.H{"enabled": false})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"enabled": true, "stats": stats})
+}
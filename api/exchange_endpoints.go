@@ -0,0 +1,13 @@
+package api
+
+import (
+	"net/http"
+	"nofx/trader/endpoint"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleExchangeEndpoints latency of each API host of the exchanges offering several, and the one in use (admin)
+func (s *Server) handleExchangeEndpoints(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"exchanges": endpoint.Status()})
+}
@@ -0,0 +1,448 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"nofx/hook"
+	"nofx/logger"
+	"nofx/store"
+	"nofx/trader"
+	"nofx/trader/aster"
+	"nofx/trader/binance"
+	"nofx/trader/bitget"
+	"nofx/trader/bybit"
+	"nofx/trader/gate"
+	hyperliquidtrader "nofx/trader/hyperliquid"
+	"nofx/trader/kucoin"
+	"nofx/trader/lighter"
+	"nofx/trader/okx"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Exchange health status
+const (
+	ExchangeHealthOK           = "ok"
+	ExchangeHealthAuthFailed   = "auth_failed"   // API key invalid, expired or missing permissions
+	ExchangeHealthIPRestricted = "ip_restricted" // Server IP not in the key's whitelist
+	ExchangeHealthClockSkew    = "clock_skew"    // Local clock too far from exchange time (signed requests rejected)
+	ExchangeHealthUnreachable  = "unreachable"   // Network / exchange API unavailable
+	ExchangeHealthError        = "error"         // Other failure (e.g. incomplete configuration)
+)
+
+const (
+	exchangeHealthInterval     = 5 * time.Minute
+	exchangeHealthInitialDelay = 30 * time.Second
+	exchangeMaxClockSkew       = 5 * time.Second // Binance default recvWindow, other exchanges are similar or looser
+	exchangeHealthMaxAlerts    = 50
+)
+
+// exchangeTimeEndpoints public endpoints used for clock skew checks.
+// Responses without a server time field (hyperliquid, lighter) fall back to the HTTP Date header (1s resolution).
+var exchangeTimeEndpoints = map[string]string{
+	"binance":     "https://fapi.binance.com/fapi/v1/time",
+	"aster":       "https://fapi.asterdex.com/fapi/v1/time",
+	"bybit":       "https://api.bybit.com/v5/market/time",
+	"okx":         "https://www.okx.com/api/v5/public/time",
+	"bitget":      "https://api.bitget.com/api/v2/public/time",
+	"gate":        "https://api.gateio.ws/api/v4/spot/time",
+	"kucoin":      "https://api-futures.kucoin.com/api/v1/timestamp",
+	"hyperliquid": "https://api.hyperliquid.xyz/info",
+	"lighter":     "https://mainnet.zklighter.elliot.ai/api/v1/status",
+}
+
+// ExchangeHealth latest health check result of one exchange config
+type ExchangeHealth struct {
+	ExchangeID          string    `json:"exchange_id"`
+	ExchangeType        string    `json:"exchange_type"`
+	AccountName         string    `json:"account_name"`
+	Status              string    `json:"status"`
+	AuthOK              bool      `json:"auth_ok"`
+	IPAllowed           *bool     `json:"ip_allowed"` // nil when the exchange response doesn't tell
+	ClockSkewMs         *int64    `json:"clock_skew_ms"`
+	LatencyMs           int64     `json:"latency_ms"`
+	Error               string    `json:"error,omitempty"`
+	TraderIDs           []string  `json:"trader_ids"` // Traders using this exchange
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	CheckedAt           time.Time `json:"checked_at"`
+	LastOKAt            time.Time `json:"last_ok_at"`
+
+	userID string
+}
+
+// ExchangeHealthAlert raised when a healthy exchange config starts failing
+type ExchangeHealthAlert struct {
+	ExchangeID   string    `json:"exchange_id"`
+	ExchangeType string    `json:"exchange_type"`
+	AccountName  string    `json:"account_name"`
+	Status       string    `json:"status"`
+	Message      string    `json:"message"`
+	TraderIDs    []string  `json:"trader_ids"`
+	Time         time.Time `json:"time"`
+
+	userID string
+}
+
+// exchangeHealthChecker periodically validates enabled exchange configs
+type exchangeHealthChecker struct {
+	store   *store.Store
+	mu      sync.RWMutex
+	results map[string]*ExchangeHealth // exchange ID -> latest result
+	alerts  []*ExchangeHealthAlert     // newest last
+	stopCh  chan struct{}
+	once    sync.Once
+}
+
+func newExchangeHealthChecker(st *store.Store) *exchangeHealthChecker {
+	return &exchangeHealthChecker{
+		store:   st,
+		results: make(map[string]*ExchangeHealth),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Start runs checks in the background until Stop is called
+func (h *exchangeHealthChecker) Start() {
+	go func() {
+		timer := time.NewTimer(exchangeHealthInitialDelay)
+		defer timer.Stop()
+		for {
+			select {
+			case <-h.stopCh:
+				return
+			case <-timer.C:
+				h.checkAll()
+				timer.Reset(exchangeHealthInterval)
+			}
+		}
+	}()
+}
+
+// Stop stops background checks
+func (h *exchangeHealthChecker) Stop() {
+	h.once.Do(func() { close(h.stopCh) })
+}
+
+// checkAll checks every enabled exchange config of all users
+func (h *exchangeHealthChecker) checkAll() {
+	exchanges, err := h.store.Exchange().ListEnabled()
+	if err != nil {
+		logger.Warnf("⚠️ Exchange health check: failed to list exchanges: %v", err)
+		return
+	}
+
+	active := make(map[string]bool, len(exchanges))
+	for _, ex := range exchanges {
+		active[ex.ID] = true
+		h.check(ex)
+	}
+
+	// Drop results of exchanges that were deleted or disabled
+	h.mu.Lock()
+	for id := range h.results {
+		if !active[id] {
+			delete(h.results, id)
+		}
+	}
+	h.mu.Unlock()
+}
+
+// check validates one exchange config and records the result (raising an alert on failure transitions)
+func (h *exchangeHealthChecker) check(ex *store.Exchange) *ExchangeHealth {
+	result := &ExchangeHealth{
+		ExchangeID:   ex.ID,
+		ExchangeType: ex.ExchangeType,
+		AccountName:  ex.AccountName,
+		Status:       ExchangeHealthOK,
+		TraderIDs:    []string{},
+		CheckedAt:    time.Now().UTC(),
+		userID:       ex.UserID,
+	}
+	if traders, err := h.store.Trader().ListByExchangeID(ex.UserID, ex.ID); err == nil {
+		for _, t := range traders {
+			result.TraderIDs = append(result.TraderIDs, t.ID)
+		}
+	}
+
+	// 1. Clock skew (checked first: a skewed clock also makes signed requests fail)
+	if skew, err := exchangeClockSkew(ex.ExchangeType); err == nil {
+		skewMs := skew.Milliseconds()
+		result.ClockSkewMs = &skewMs
+	} else {
+		logger.Debugf("Exchange health check: clock skew unavailable for %s: %v", ex.ExchangeType, err)
+	}
+
+	// 2. Authentication (a signed balance query also validates IP whitelist)
+	start := time.Now()
+	client, err := newExchangeClient(ex, ex.UserID)
+	if err == nil {
+		_, err = client.GetBalance()
+	}
+	result.LatencyMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		result.Status = classifyExchangeError(err)
+		result.Error = err.Error()
+		if result.Status == ExchangeHealthIPRestricted {
+			allowed := false
+			result.IPAllowed = &allowed
+		}
+	} else {
+		result.AuthOK = true
+		allowed := true
+		result.IPAllowed = &allowed
+		if result.ClockSkewMs != nil && absInt64(*result.ClockSkewMs) > exchangeMaxClockSkew.Milliseconds() {
+			result.Status = ExchangeHealthClockSkew
+			result.Error = fmt.Sprintf("local clock differs from exchange time by %d ms (max %d ms)",
+				*result.ClockSkewMs, exchangeMaxClockSkew.Milliseconds())
+		}
+	}
+	h.record(result)
+	return result
+}
+
+// record stores a result and raises an alert when an exchange stops working
+func (h *exchangeHealthChecker) record(result *ExchangeHealth) {
+	h.mu.Lock()
+	prev := h.results[result.ExchangeID]
+	if result.Status == ExchangeHealthOK {
+		result.LastOKAt = result.CheckedAt
+	} else {
+		if prev != nil {
+			result.LastOKAt = prev.LastOKAt
+			result.ConsecutiveFailures = prev.ConsecutiveFailures
+		}
+		result.ConsecutiveFailures++
+	}
+	h.results[result.ExchangeID] = result
+
+	var alert *ExchangeHealthAlert
+	failing := result.Status != ExchangeHealthOK
+	wasFailing := prev != nil && prev.Status != ExchangeHealthOK
+	if failing && (!wasFailing || prev.Status != result.Status) {
+		alert = &ExchangeHealthAlert{
+			ExchangeID:   result.ExchangeID,
+			ExchangeType: result.ExchangeType,
+			AccountName:  result.AccountName,
+			Status:       result.Status,
+			Message:      result.Error,
+			TraderIDs:    result.TraderIDs,
+			Time:         result.CheckedAt,
+			userID:       result.userID,
+		}
+		h.alerts = append(h.alerts, alert)
+		if len(h.alerts) > exchangeHealthMaxAlerts {
+			h.alerts = h.alerts[len(h.alerts)-exchangeHealthMaxAlerts:]
+		}
+	}
+	h.mu.Unlock()
+
+	if alert != nil {
+		logger.Errorf("🚨 Exchange %s (%s/%s) health check failed [%s]: %s | affected traders: %v",
+			result.ExchangeID, result.ExchangeType, result.AccountName, result.Status, result.Error, result.TraderIDs)
+		hook.HookExec[hook.ExchangeHealthAlertResult](hook.EXCHANGE_HEALTH_ALERT,
+			result.userID, result.ExchangeID, result.Status, result.Error)
+	} else if !failing && prev != nil && prev.Status != ExchangeHealthOK {
+		logger.Infof("✅ Exchange %s (%s/%s) recovered", result.ExchangeID, result.ExchangeType, result.AccountName)
+	}
+}
+
+// forUser returns latest results and alerts for a user's exchanges
+func (h *exchangeHealthChecker) forUser(userID string) ([]*ExchangeHealth, []*ExchangeHealthAlert) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	results := make([]*ExchangeHealth, 0)
+	for _, r := range h.results {
+		if r.userID == userID {
+			results = append(results, r)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].ExchangeType != results[j].ExchangeType {
+			return results[i].ExchangeType < results[j].ExchangeType
+		}
+		return results[i].AccountName < results[j].AccountName
+	})
+
+	alerts := make([]*ExchangeHealthAlert, 0)
+	for i := len(h.alerts) - 1; i >= 0; i-- {
+		if h.alerts[i].userID == userID {
+			alerts = append(alerts, h.alerts[i])
+		}
+	}
+	return results, alerts
+}
+
+// classifyExchangeError maps exchange API errors to a health status
+func classifyExchangeError(err error) string {
+	msg := strings.ToLower(err.Error())
+	containsAny := func(keywords ...string) bool {
+		for _, k := range keywords {
+			if strings.Contains(msg, k) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case containsAny("whitelist", "white list", "unmatched ip", "invalid ip", "ip not allowed", "ip address", "10010", "50110", "40018"):
+		return ExchangeHealthIPRestricted
+	case containsAny("-1021", "recvwindow", "timestamp", "10002", "50102", "40008"):
+		return ExchangeHealthClockSkew
+	case containsAny("-2014", "-2015", "-2008", "api-key", "api key", "apikey", "signature", "passphrase",
+		"unauthorized", "401", "permission", "10003", "10004", "50111", "50113", "40037", "40009"):
+		return ExchangeHealthAuthFailed
+	case containsAny("timeout", "connection refused", "no such host", "connection reset", "eof", "tls", "503", "502"):
+		return ExchangeHealthUnreachable
+	default:
+		return ExchangeHealthError
+	}
+}
+
+var exchangeHealthHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// exchangeClockSkew returns local clock minus exchange server time (positive = local clock ahead)
+func exchangeClockSkew(exchangeType string) (time.Duration, error) {
+	endpoint, ok := exchangeTimeEndpoints[exchangeType]
+	if !ok {
+		return 0, fmt.Errorf("no time endpoint for %s", exchangeType)
+	}
+
+	sent := time.Now()
+	resp, err := exchangeHealthHTTPClient.Get(endpoint)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	received := time.Now()
+	local := sent.Add(received.Sub(sent) / 2)
+
+	var body interface{}
+	if resp.StatusCode == http.StatusOK && json.NewDecoder(resp.Body).Decode(&body) == nil {
+		if ms, ok := findServerTimeMs(body); ok {
+			return local.Sub(time.UnixMilli(ms)), nil
+		}
+	}
+
+	// Fallback: HTTP Date header (second resolution)
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, fmt.Errorf("no server time in response: %w", err)
+	}
+	// Date header truncates to the second: compare against the middle of that second
+	return local.Sub(serverTime.Add(500 * time.Millisecond)), nil
+}
+
+// findServerTimeMs finds a millisecond timestamp in common exchange time responses
+// (serverTime / time / ts / server_time / data, possibly nested or string encoded)
+func findServerTimeMs(v interface{}) (int64, bool) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for _, key := range []string{"serverTime", "server_time", "time", "ts", "data"} {
+			if child, ok := val[key]; ok {
+				if ms, ok := findServerTimeMs(child); ok {
+					return ms, true
+				}
+			}
+		}
+	case []interface{}:
+		if len(val) > 0 {
+			return findServerTimeMs(val[0])
+		}
+	case float64:
+		return plausibleTimeMs(int64(val))
+	case string:
+		if n, err := strconv.ParseInt(val, 10, 64); err == nil {
+			return plausibleTimeMs(n)
+		}
+	}
+	return 0, false
+}
+
+// plausibleTimeMs accepts only millisecond timestamps (rejects seconds and nanoseconds)
+func plausibleTimeMs(n int64) (int64, bool) {
+	if n > 1e12 && n < 1e14 {
+		return n, true
+	}
+	return 0, false
+}
+
+func absInt64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// newExchangeClient creates a temporary trader client from an exchange config
+func newExchangeClient(ex *store.Exchange, userID string) (trader.Trader, error) {
+	switch ex.ExchangeType {
+	case "binance":
+		return binance.NewFuturesTrader(string(ex.APIKey), string(ex.SecretKey), userID), nil
+	case "hyperliquid":
+		return hyperliquidtrader.NewHyperliquidTrader(string(ex.APIKey), ex.HyperliquidWalletAddr, ex.Testnet)
+	case "aster":
+		return aster.NewAsterTrader(ex.AsterUser, ex.AsterSigner, string(ex.AsterPrivateKey))
+	case "bybit":
+		return bybit.NewBybitTrader(string(ex.APIKey), string(ex.SecretKey)), nil
+	case "okx":
+		return okx.NewOKXTrader(string(ex.APIKey), string(ex.SecretKey), string(ex.Passphrase)), nil
+	case "bitget":
+		return bitget.NewBitgetTrader(string(ex.APIKey), string(ex.SecretKey), string(ex.Passphrase), ex.Testnet), nil
+	case "gate":
+		return gate.NewGateTrader(string(ex.APIKey), string(ex.SecretKey)), nil
+	case "kucoin":
+		return kucoin.NewKuCoinTrader(string(ex.APIKey), string(ex.SecretKey), string(ex.Passphrase)), nil
+	case "lighter":
+		if ex.LighterWalletAddr == "" || string(ex.LighterAPIKeyPrivateKey) == "" {
+			return nil, fmt.Errorf("Lighter requires wallet address and API Key private key")
+		}
+		// Lighter only supports mainnet
+		return lighter.NewLighterTraderV2(ex.LighterWalletAddr, string(ex.LighterAPIKeyPrivateKey), ex.LighterAPIKeyIndex, false)
+	default:
+		return nil, fmt.Errorf("unsupported exchange type: %s", ex.ExchangeType)
+	}
+}
+
+// handleExchangeHealth Get connectivity health of the user's enabled exchanges
+// ?refresh=true re-checks immediately instead of returning the last periodic result
+func (s *Server) handleExchangeHealth(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if c.Query("refresh") == "true" {
+		exchanges, err := s.store.Exchange().List(userID)
+		if err != nil {
+			SafeInternalError(c, "List exchanges", err)
+			return
+		}
+		for _, ex := range exchanges {
+			if ex.Enabled {
+				s.exchangeHealth.check(ex)
+			}
+		}
+	}
+
+	results, alerts := s.exchangeHealth.forUser(userID)
+	healthy := true
+	for _, r := range results {
+		if r.Status != ExchangeHealthOK {
+			healthy = false
+			break
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"healthy":   healthy,
+		"exchanges": results,
+		"alerts":    alerts,
+		"interval":  exchangeHealthInterval.String(),
+	})
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestClassifyExchangeError(t *testing.T) {
+	cases := map[string]string{
+		"<APIError> code=-2015, msg=Invalid API-key, IP, or permissions for action":                           ExchangeHealthAuthFailed,
+		"<APIError> code=-1021, msg=Timestamp for this request is outside of the recvWindow":                  ExchangeHealthClockSkew,
+		"bybit API error: retCode=10010, retMsg=Unmatched IP, please check your API key's bound IP addresses": ExchangeHealthIPRestricted,
+		"okx error 50110: Your IP is not in the whitelist":                                                    ExchangeHealthIPRestricted,
+		"Post \"https://api.bitget.com\": dial tcp: lookup api.bitget.com: no such host":                      ExchangeHealthUnreachable,
+		"Lighter requires wallet address and API Key private key":                                             ExchangeHealthAuthFailed,
+		"unexpected response": ExchangeHealthError,
+	}
+	for msg, want := range cases {
+		if got := classifyExchangeError(errors.New(msg)); got != want {
+			t.Errorf("classifyExchangeError(%q) = %s, want %s", msg, got, want)
+		}
+	}
+}
+
+func TestFindServerTimeMs(t *testing.T) {
+	cases := map[string]int64{
+		`{"serverTime":1700000000123}`:                                            1700000000123, // binance / aster
+		`{"retCode":0,"result":{"timeSecond":"1700000000"},"time":1700000000123}`: 1700000000123, // bybit
+		`{"code":"0","data":[{"ts":"1700000000123"}]}`:                            1700000000123, // okx
+		`{"code":"00000","data":{"serverTime":"1700000000123"}}`:                  1700000000123, // bitget
+		`{"server_time":1700000000123}`:                                           1700000000123, // gate
+		`{"code":"200000","data":1700000000123}`:                                  1700000000123, // kucoin
+	}
+	for body, want := range cases {
+		var v interface{}
+		if err := json.Unmarshal([]byte(body), &v); err != nil {
+			t.Fatalf("bad fixture %s: %v", body, err)
+		}
+		if got, ok := findServerTimeMs(v); !ok || got != want {
+			t.Errorf("findServerTimeMs(%s) = %d, %v; want %d", body, got, ok, want)
+		}
+	}
+
+	var v interface{}
+	_ = json.Unmarshal([]byte(`{"status":"ok","timestamp":1700000000}`), &v)
+	if _, ok := findServerTimeMs(v); ok {
+		t.Error("response without server time should not match")
+	}
+}
+
+func TestExchangeHealthChecker_AlertsOnTransition(t *testing.T) {
+	h := newExchangeHealthChecker(nil)
+	record := func(status string) {
+		h.record(&ExchangeHealth{ExchangeID: "ex1", Status: status, Error: status, userID: "u1"})
+	}
+
+	record(ExchangeHealthOK)
+	record(ExchangeHealthAuthFailed)
+	record(ExchangeHealthAuthFailed) // still failing: no new alert
+	record(ExchangeHealthIPRestricted)
+	record(ExchangeHealthOK)
+
+	results, alerts := h.forUser("u1")
+	if len(alerts) != 2 || alerts[0].Status != ExchangeHealthIPRestricted || alerts[1].Status != ExchangeHealthAuthFailed {
+		t.Fatalf("unexpected alerts (newest first): %+v", alerts)
+	}
+	if len(results) != 1 || results[0].Status != ExchangeHealthOK || results[0].ConsecutiveFailures != 0 {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if _, otherAlerts := h.forUser("u2"); len(otherAlerts) != 0 {
+		t.Fatal("alerts must be scoped to the exchange owner")
+	}
+}
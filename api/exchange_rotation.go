@@ -0,0 +1,232 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"nofx/config"
+	"nofx/crypto"
+	"nofx/logger"
+	"nofx/store"
+	"nofx/trader"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultCheckSymbol symbol queried for open orders when a trader's strategy has no static coins
+const defaultCheckSymbol = "BTCUSDT"
+
+// RotateExchangeKeysRequest new credentials of an exchange account, empty fields keep their current value
+type RotateExchangeKeysRequest struct {
+	APIKey                  string `json:"api_key"`
+	SecretKey               string `json:"secret_key"`
+	Passphrase              string `json:"passphrase"`
+	AsterSigner             string `json:"aster_signer"`
+	AsterPrivateKey         string `json:"aster_private_key"`
+	LighterAPIKeyPrivateKey string `json:"lighter_api_key_private_key"`
+	LighterAPIKeyIndex      *int   `json:"lighter_api_key_index,omitempty"`
+}
+
+func (r *RotateExchangeKeysRequest) hasCredentials() bool {
+	return r.APIKey != "" || r.SecretKey != "" || r.Passphrase != "" || r.AsterPrivateKey != "" || r.LighterAPIKeyPrivateKey != ""
+}
+
+// traderKeyCheck verification of one trader against the rotated keys
+type traderKeyCheck struct {
+	TraderID     string `json:"trader_id"`
+	Name         string `json:"name"`
+	Symbol       string `json:"symbol"` // Symbol the open order query ran for
+	BalanceOK    bool   `json:"balance_ok"`
+	OpenOrdersOK bool   `json:"open_orders_ok"`
+	Error        string `json:"error,omitempty"`
+	WasRunning   bool   `json:"was_running"`
+	Resumed      bool   `json:"resumed"`
+	SkipReason   string `json:"skip_reason,omitempty"` // Why a trader that was running stays stopped
+}
+
+func (r *traderKeyCheck) passed() bool { return r.BalanceOK && r.OpenOrdersOK }
+
+// handleRotateExchangeKeys Replace the keys of an exchange account, re-verify every trader using it and
+// resume the ones that were running once their checks pass
+func (s *Server) handleRotateExchangeKeys(c *gin.Context) {
+	userID := c.GetString("user_id")
+	exchangeID := c.Param("id")
+
+	ex, err := s.store.Exchange().GetByID(userID, exchangeID)
+	if err != nil {
+		SafeNotFound(c, "Exchange")
+		return
+	}
+
+	bodyBytes, err := c.GetRawData()
+	if err != nil {
+		SafeBadRequest(c, "Failed to read request body")
+		return
+	}
+	var req RotateExchangeKeysRequest
+	if !config.Get().TransportEncryption {
+		if err := json.Unmarshal(bodyBytes, &req); err != nil {
+			SafeBadRequest(c, "Invalid request format")
+			return
+		}
+	} else {
+		var encryptedPayload crypto.EncryptedPayload
+		if err := json.Unmarshal(bodyBytes, &encryptedPayload); err != nil || encryptedPayload.WrappedKey == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "This endpoint only supports encrypted transmission",
+				"code":    "ENCRYPTION_REQUIRED",
+				"message": "Encrypted transmission is required for security reasons",
+			})
+			return
+		}
+		decrypted, err := s.cryptoHandler.cryptoService.DecryptSensitiveData(&encryptedPayload)
+		if err != nil {
+			SafeBadRequest(c, "Failed to decrypt data")
+			return
+		}
+		if err := json.Unmarshal([]byte(decrypted), &req); err != nil {
+			SafeBadRequest(c, "Failed to parse decrypted data")
+			return
+		}
+	}
+	if !req.hasCredentials() {
+		SafeBadRequest(c, "No new credentials provided")
+		return
+	}
+
+	traders, err := s.store.Trader().ListByExchangeID(userID, exchangeID)
+	if err != nil {
+		SafeInternalError(c, "List traders of exchange", err)
+		return
+	}
+
+	// Stop the traders before the keys change under them, they reload with the new keys below
+	for _, t := range traders {
+		s.traderManager.RemoveTrader(t.ID)
+	}
+
+	asterSigner, lighterIndex := ex.AsterSigner, ex.LighterAPIKeyIndex
+	if req.AsterSigner != "" {
+		asterSigner = req.AsterSigner
+	}
+	if req.LighterAPIKeyIndex != nil {
+		lighterIndex = *req.LighterAPIKeyIndex
+	}
+	err = s.store.Exchange().Update(userID, exchangeID, ex.Enabled, req.APIKey, req.SecretKey, req.Passphrase, ex.Testnet,
+		ex.HyperliquidWalletAddr, ex.AsterUser, asterSigner, req.AsterPrivateKey, ex.LighterWalletAddr, "", req.LighterAPIKeyPrivateKey, lighterIndex)
+	if err != nil {
+		SafeInternalError(c, "Update exchange keys", err)
+		return
+	}
+	if ex, err = s.store.Exchange().GetByID(userID, exchangeID); err != nil {
+		SafeInternalError(c, "Reload exchange", err)
+		return
+	}
+
+	// Permissions of the new key, checked synchronously so a withdrawal enabled key never resumes a trader
+	status, message := checkExchangeKey(ex)
+	if err := s.store.Exchange().UpdateKeyVerification(exchangeID, status, message); err != nil {
+		logger.Warnf("⚠️ Failed to save exchange %s key verification: %v", exchangeID, err)
+	}
+	ex.KeyVerificationStatus = status
+
+	client, clientErr := newExchangeClient(ex, userID)
+	checks := make([]traderKeyCheck, 0, len(traders))
+	for _, t := range traders {
+		check := traderKeyCheck{TraderID: t.ID, Name: t.Name, WasRunning: t.IsRunning, Symbol: defaultCheckSymbol}
+		if fullConfig, err := s.store.Trader().GetFullConfig(userID, t.ID); err == nil {
+			check.Symbol = traderCheckSymbol(fullConfig.Strategy)
+		}
+		if clientErr != nil {
+			check.Error = clientErr.Error()
+		} else {
+			verifyTraderAccess(client, &check)
+		}
+		checks = append(checks, check)
+	}
+
+	if err := s.traderManager.LoadUserTradersFromStore(s.store, userID); err != nil {
+		logger.Infof("⚠️ Failed to reload user traders into memory: %v", err)
+	}
+
+	now := time.Now()
+	for i, t := range traders {
+		check := &checks[i]
+		if !check.WasRunning {
+			continue
+		}
+		state, _ := s.store.CircuitBreaker().Get(t.ID)
+		check.SkipReason = resumeBlockReason(check, t, ex, state, now)
+		if check.SkipReason == "" {
+			at, err := s.traderManager.GetTrader(t.ID)
+			if err != nil {
+				check.SkipReason = "Failed to load trader"
+				if loadErr := s.traderManager.GetLoadError(t.ID); loadErr != nil {
+					check.SkipReason += ": " + loadErr.Error()
+				}
+			} else {
+				go func() {
+					logger.Infof("▶️  Resuming trader %s (%s) after key rotation", t.ID, at.GetName())
+					if err := at.Run(); err != nil {
+						logger.Infof("❌ Trader %s runtime error: %v", at.GetName(), err)
+					}
+				}()
+				check.Resumed = true
+			}
+		}
+		if !check.Resumed {
+			// Stopped in memory, keep the database in line so a restart does not auto start it
+			if err := s.store.Trader().UpdateStatus(userID, t.ID, false); err != nil {
+				logger.Warnf("⚠️ Failed to update trader %s status: %v", t.ID, err)
+			}
+		}
+	}
+
+	logger.Infof("🔑 Exchange %s (%s/%s) keys rotated, %d traders verified", ex.ID, ex.ExchangeType, ex.AccountName, len(checks))
+	c.JSON(http.StatusOK, gin.H{
+		"exchange_id":              exchangeID,
+		"key_verification_status":  status,
+		"key_verification_message": message,
+		"traders":                  checks,
+	})
+}
+
+// traderCheckSymbol the symbol a trader's open orders are queried for
+func traderCheckSymbol(strategy *store.Strategy) string {
+	if strategy == nil {
+		return defaultCheckSymbol
+	}
+	cfg, err := strategy.ParseConfig()
+	if err != nil || len(cfg.CoinSource.StaticCoins) == 0 {
+		return defaultCheckSymbol
+	}
+	return cfg.CoinSource.StaticCoins[0]
+}
+
+// verifyTraderAccess runs the balance and open order queries a trader makes every cycle
+func verifyTraderAccess(client trader.Trader, check *traderKeyCheck) {
+	if _, err := client.GetBalance(); err != nil {
+		check.Error = "Balance query failed: " + err.Error()
+		return
+	}
+	check.BalanceOK = true
+	if _, err := client.GetOpenOrders(check.Symbol); err != nil {
+		check.Error = "Open order query failed: " + err.Error()
+		return
+	}
+	check.OpenOrdersOK = true
+}
+
+// resumeBlockReason why a trader that was running must stay stopped after a key rotation, empty when it can resume
+func resumeBlockReason(check *traderKeyCheck, t *store.Trader, ex *store.Exchange, state *store.CircuitBreakerState, now time.Time) string {
+	switch {
+	case !check.passed():
+		return "Verification failed"
+	case ex.KeyVerificationStatus == ExchangeKeyWithdrawalEnabled && !t.PaperMode:
+		return "API key has withdrawal permission"
+	case state.InCooldown(now):
+		return "Paused by circuit breaker until " + state.CooldownUntil.Format(time.RFC3339)
+	default:
+		return ""
+	}
+}
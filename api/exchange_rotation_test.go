@@ -0,0 +1,77 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"nofx/store"
+	"nofx/trader"
+)
+
+// stubRotatedExchange answers the queries a trader makes each cycle; any other call panics on the nil embedded Trader
+type stubRotatedExchange struct {
+	trader.Trader
+	balanceErr error
+	ordersErr  error
+	symbol     string
+}
+
+func (s *stubRotatedExchange) GetBalance() (map[string]interface{}, error) {
+	return map[string]interface{}{"totalWalletBalance": 1000.0}, s.balanceErr
+}
+
+func (s *stubRotatedExchange) GetOpenOrders(symbol string) ([]trader.OpenOrder, error) {
+	s.symbol = symbol
+	return nil, s.ordersErr
+}
+
+func TestVerifyTraderAccess(t *testing.T) {
+	ok := &stubRotatedExchange{}
+	check := traderKeyCheck{Symbol: "ETHUSDT"}
+	verifyTraderAccess(ok, &check)
+	if !check.passed() || check.Error != "" || ok.symbol != "ETHUSDT" {
+		t.Errorf("working keys: %+v, queried %q", check, ok.symbol)
+	}
+
+	check = traderKeyCheck{Symbol: "ETHUSDT"}
+	verifyTraderAccess(&stubRotatedExchange{balanceErr: errors.New("invalid api key")}, &check)
+	if check.BalanceOK || check.OpenOrdersOK || check.Error == "" {
+		t.Errorf("rejected key: %+v", check)
+	}
+
+	check = traderKeyCheck{Symbol: "ETHUSDT"}
+	verifyTraderAccess(&stubRotatedExchange{ordersErr: errors.New("ip not whitelisted")}, &check)
+	if !check.BalanceOK || check.OpenOrdersOK || check.passed() {
+		t.Errorf("open orders denied: %+v", check)
+	}
+}
+
+func TestResumeBlockReason(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	passed := &traderKeyCheck{BalanceOK: true, OpenOrdersOK: true}
+	live := &store.Trader{}
+	verified := &store.Exchange{KeyVerificationStatus: ExchangeKeyVerified}
+	withdrawal := &store.Exchange{KeyVerificationStatus: ExchangeKeyWithdrawalEnabled}
+	cooling := &store.CircuitBreakerState{Tripped: true, CooldownUntil: now.Add(time.Hour)}
+	cooledDown := &store.CircuitBreakerState{Tripped: true, CooldownUntil: now.Add(-time.Hour)}
+
+	if reason := resumeBlockReason(passed, live, verified, nil, now); reason != "" {
+		t.Errorf("a verified trader should resume: %s", reason)
+	}
+	if reason := resumeBlockReason(passed, live, verified, cooledDown, now); reason != "" {
+		t.Errorf("an elapsed cooldown should not block: %s", reason)
+	}
+	if resumeBlockReason(&traderKeyCheck{BalanceOK: true}, live, verified, nil, now) == "" {
+		t.Error("a failed open order check should block")
+	}
+	if resumeBlockReason(passed, live, withdrawal, nil, now) == "" {
+		t.Error("a withdrawal enabled key should block live trading")
+	}
+	if reason := resumeBlockReason(passed, &store.Trader{PaperMode: true}, withdrawal, nil, now); reason != "" {
+		t.Errorf("paper traders never send orders: %s", reason)
+	}
+	if resumeBlockReason(passed, live, verified, cooling, now) == "" {
+		t.Error("an active circuit breaker cooldown should block")
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"net/http"
+	"nofx/crypto"
+	"nofx/logger"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Secret preview status
+const (
+	SecretStatusConfigured    = "configured"
+	SecretStatusEmpty         = "empty"
+	SecretStatusUndecryptable = "undecryptable" // Stored value could not be decrypted with the current data key
+)
+
+// minPreviewLength secrets shorter than this are fully masked, 8 revealed characters would be most of them
+const minPreviewLength = 16
+
+// fullyMaskedSecretFields secrets never partially revealed, whatever their length (user-chosen, often short)
+var fullyMaskedSecretFields = map[string]bool{
+	"passphrase": true,
+}
+
+// secretPreview masked view of one stored secret
+type secretPreview struct {
+	Status  string `json:"status"`
+	Preview string `json:"preview,omitempty"` // First 4 / last 4 characters, "****" for passphrases and short values
+}
+
+// exchangeSecretFields stored secrets of an exchange account, keyed by request field name
+func exchangeSecretFields(exchange *store.Exchange) map[string]crypto.EncryptedString {
+	return map[string]crypto.EncryptedString{
+		"api_key":                     exchange.APIKey,
+		"secret_key":                  exchange.SecretKey,
+		"passphrase":                  exchange.Passphrase,
+		"aster_private_key":           exchange.AsterPrivateKey,
+		"lighter_private_key":         exchange.LighterPrivateKey,
+		"lighter_api_key_private_key": exchange.LighterAPIKeyPrivateKey,
+	}
+}
+
+// previewSecret masks a stored secret, decrypting it first if it is still in storage format
+// (values are decrypted on load, a storage-format value means that failed)
+// fullMask hides the value entirely.
+func previewSecret(cs *crypto.CryptoService, value string, fullMask bool) secretPreview {
+	if value == "" {
+		return secretPreview{Status: SecretStatusEmpty}
+	}
+	if cs != nil && cs.IsEncryptedStorageValue(value) {
+		decrypted, err := cs.DecryptFromStorage(value)
+		if err != nil {
+			return secretPreview{Status: SecretStatusUndecryptable}
+		}
+		value = decrypted
+	}
+	if fullMask || len(value) < minPreviewLength {
+		return secretPreview{Status: SecretStatusConfigured, Preview: "****"}
+	}
+	return secretPreview{
+		Status:  SecretStatusConfigured,
+		Preview: MaskSensitiveString(value),
+	}
+}
+
+// handleExchangeSecretPreview Masked previews of an exchange account's stored secrets
+// Lets the owner confirm which keys are configured without exposing them in full
+func (s *Server) handleExchangeSecretPreview(c *gin.Context) {
+	userID := c.GetString("user_id")
+	exchangeID := c.Param("id")
+
+	exchange, err := s.store.Exchange().GetByID(userID, exchangeID)
+	if err != nil {
+		SafeNotFound(c, "Exchange")
+		return
+	}
+
+	var cs *crypto.CryptoService
+	if s.cryptoHandler != nil {
+		cs = s.cryptoHandler.cryptoService
+	}
+	secrets := make(map[string]secretPreview)
+	for field, value := range exchangeSecretFields(exchange) {
+		secrets[field] = previewSecret(cs, string(value), fullyMaskedSecretFields[field])
+	}
+
+	logger.Infof("🔑 User %s viewed secret previews of exchange %s", userID, exchangeID)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{
+		"exchange_id":   exchange.ID,
+		"exchange_type": exchange.ExchangeType,
+		"account_name":  exchange.AccountName,
+		"secrets":       secrets,
+	})
+}
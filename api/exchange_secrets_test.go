@@ -0,0 +1,70 @@
+package api
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"nofx/crypto"
+	"testing"
+)
+
+type staticKeyProvider struct {
+	privateKey *rsa.PrivateKey
+	dataKey    []byte
+}
+
+func (p *staticKeyProvider) Name() string                                { return "static" }
+func (p *staticKeyProvider) LoadRSAPrivateKey() (*rsa.PrivateKey, error) { return p.privateKey, nil }
+func (p *staticKeyProvider) LoadDataKey() ([]byte, error)                { return p.dataKey, nil }
+
+func newTestCryptoService(t *testing.T, dataKey []byte) *crypto.CryptoService {
+	t.Helper()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, err := crypto.NewCryptoServiceWithProvider(&staticKeyProvider{privateKey: privateKey, dataKey: dataKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cs
+}
+
+func TestPreviewSecret(t *testing.T) {
+	cs := newTestCryptoService(t, bytes.Repeat([]byte{1}, 32))
+
+	if got := previewSecret(cs, "", false); got.Status != SecretStatusEmpty || got.Preview != "" {
+		t.Errorf("empty secret: %+v", got)
+	}
+
+	got := previewSecret(cs, "abcd1234567890wxyz", false)
+	if got.Status != SecretStatusConfigured || got.Preview != "abcd****wxyz" {
+		t.Errorf("plain secret: %+v", got)
+	}
+
+	// Revealing 8 characters of a short value would give most of it away
+	for _, short := range []string{"short", "abcd1234567890w"} {
+		if got := previewSecret(cs, short, false); got.Status != SecretStatusConfigured || got.Preview != "****" {
+			t.Errorf("short secret %q must be fully masked, got %+v", short, got)
+		}
+	}
+	if got := previewSecret(cs, "a-long-passphrase-1234", fullyMaskedSecretFields["passphrase"]); got.Preview != "****" {
+		t.Errorf("passphrases must be fully masked, got %q", got.Preview)
+	}
+
+	// A value left in storage format is decrypted server-side
+	encrypted, err := cs.EncryptForStorage("ABCDefghijklmnopWXYZ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := previewSecret(cs, encrypted, false); got.Preview != "ABCD****WXYZ" {
+		t.Errorf("encrypted secret: %+v", got)
+	}
+
+	// Encrypted with another data key: never leak the ciphertext
+	other := newTestCryptoService(t, bytes.Repeat([]byte{2}, 32))
+	foreign, _ := other.EncryptForStorage("ABCDefghijklmnopWXYZ")
+	if got := previewSecret(cs, foreign, false); got.Status != SecretStatusUndecryptable || got.Preview != "" {
+		t.Errorf("undecryptable secret: %+v", got)
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Exchange API key verification status (shown next to each exchange config)
+const (
+	ExchangeKeyPending           = "pending"            // Check running after the keys were saved
+	ExchangeKeyVerified          = "verified"           // Futures trading allowed, withdrawals disabled
+	ExchangeKeyMissingPermission = "missing_permission" // Key cannot trade futures
+	ExchangeKeyWithdrawalEnabled = "withdrawal_enabled" // Key can withdraw funds (danger, live trading is blocked)
+	ExchangeKeyUnverifiable      = "unverifiable"       // Key works but the exchange does not report its permissions
+	ExchangeKeyFailed            = "failed"             // Key rejected or exchange unreachable
+)
+
+// evaluateKeyPermissions maps API key permissions to a verification status and message
+func evaluateKeyPermissions(perms *trader.APIKeyPermissions) (string, string) {
+	switch {
+	case perms.Withdrawal:
+		return ExchangeKeyWithdrawalEnabled, "API key has withdrawal permission: disable it on the exchange, traders using this key cannot start"
+	case !perms.FuturesTrading:
+		return ExchangeKeyMissingPermission, "API key has no futures trading permission: enable it on the exchange"
+	default:
+		return ExchangeKeyVerified, ""
+	}
+}
+
+// checkExchangeKey verifies the permissions of an exchange config's API key
+func checkExchangeKey(ex *store.Exchange) (string, string) {
+	client, err := newExchangeClient(ex, ex.UserID)
+	if err != nil {
+		return ExchangeKeyFailed, err.Error()
+	}
+
+	checker, ok := client.(trader.PermissionTrader)
+	if !ok {
+		// DEX agent keys and exchanges without a permission endpoint: only check the key works
+		if _, err := client.GetBalance(); err != nil {
+			return ExchangeKeyFailed, err.Error()
+		}
+		return ExchangeKeyUnverifiable, "Key works, but " + ex.ExchangeType + " does not report API key permissions: make sure withdrawals are disabled"
+	}
+
+	perms, err := checker.GetAPIKeyPermissions()
+	if err != nil {
+		return ExchangeKeyFailed, err.Error()
+	}
+	return evaluateKeyPermissions(perms)
+}
+
+// verifyExchangeKeyAsync marks an exchange config pending and verifies its API key in the background
+func (s *Server) verifyExchangeKeyAsync(userID, exchangeID string) {
+	if err := s.store.Exchange().UpdateKeyVerification(exchangeID, ExchangeKeyPending, ""); err != nil {
+		logger.Warnf("⚠️ Failed to mark exchange %s key verification pending: %v", exchangeID, err)
+	}
+
+	go func() {
+		ex, err := s.store.Exchange().GetByID(userID, exchangeID)
+		if err != nil {
+			logger.Warnf("⚠️ API key verification: exchange %s not found: %v", exchangeID, err)
+			return
+		}
+
+		status, message := checkExchangeKey(ex)
+		if err := s.store.Exchange().UpdateKeyVerification(exchangeID, status, message); err != nil {
+			logger.Warnf("⚠️ Failed to save exchange %s key verification: %v", exchangeID, err)
+			return
+		}
+		switch status {
+		case ExchangeKeyVerified:
+			logger.Infof("✓ Exchange %s (%s/%s) API key verified", ex.ID, ex.ExchangeType, ex.AccountName)
+		case ExchangeKeyWithdrawalEnabled:
+			logger.Errorf("🚨 Exchange %s (%s/%s) API key has withdrawal permission", ex.ID, ex.ExchangeType, ex.AccountName)
+		default:
+			logger.Warnf("⚠️ Exchange %s (%s/%s) API key verification: %s %s", ex.ID, ex.ExchangeType, ex.AccountName, status, message)
+		}
+	}()
+}
+
+// handleVerifyExchange Re-run the API key permission check of an exchange config
+func (s *Server) handleVerifyExchange(c *gin.Context) {
+	userID := c.GetString("user_id")
+	exchangeID := c.Param("id")
+
+	if _, err := s.store.Exchange().GetByID(userID, exchangeID); err != nil {
+		SafeNotFound(c, "Exchange")
+		return
+	}
+
+	s.verifyExchangeKeyAsync(userID, exchangeID)
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":                 "API key verification started",
+		"key_verification_status": ExchangeKeyPending,
+	})
+}
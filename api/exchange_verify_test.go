@@ -0,0 +1,26 @@
+package api
+
+import (
+	"testing"
+
+	"nofx/trader"
+)
+
+func TestEvaluateKeyPermissions(t *testing.T) {
+	tests := []struct {
+		perms trader.APIKeyPermissions
+		want  string
+	}{
+		{trader.APIKeyPermissions{FuturesTrading: true}, ExchangeKeyVerified},
+		{trader.APIKeyPermissions{FuturesTrading: false}, ExchangeKeyMissingPermission},
+		// Withdrawal permission wins over everything else
+		{trader.APIKeyPermissions{FuturesTrading: true, Withdrawal: true}, ExchangeKeyWithdrawalEnabled},
+		{trader.APIKeyPermissions{Withdrawal: true}, ExchangeKeyWithdrawalEnabled},
+	}
+	for _, tt := range tests {
+		perms := tt.perms
+		if got, msg := evaluateKeyPermissions(&perms); got != tt.want {
+			t.Errorf("%+v: expected %s, got %s (%s)", tt.perms, tt.want, got, msg)
+		}
+	}
+}
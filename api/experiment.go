@@ -0,0 +1,175 @@
+package api
+
+import (
+	"net/http"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateExperimentRequest start strategy A/B experiment request
+type CreateExperimentRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	StrategyAID string  `json:"strategy_a_id" binding:"required"`
+	StrategyBID string  `json:"strategy_b_id" binding:"required"`
+	Mode        string  `json:"mode"`        // alternate (default) / split
+	SplitRatio  float64 `json:"split_ratio"` // Capital share of variant A in split mode (default 0.5)
+}
+
+// handleCreateExperiment Start a strategy A/B experiment on a trader
+func (s *Server) handleCreateExperiment(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req CreateExperimentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if req.StrategyAID == req.StrategyBID {
+		SafeBadRequest(c, "Experiment variants must use different strategies")
+		return
+	}
+	if req.Mode != "" && req.Mode != store.ExperimentModeAlternate && req.Mode != store.ExperimentModeSplit {
+		SafeBadRequest(c, "Mode must be 'alternate' or 'split'")
+		return
+	}
+	if req.Mode == store.ExperimentModeSplit && (req.SplitRatio < 0 || req.SplitRatio >= 1) {
+		SafeBadRequest(c, "Split ratio must be between 0 and 1")
+		return
+	}
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+	for _, strategyID := range []string{req.StrategyAID, req.StrategyBID} {
+		strategy, err := s.store.Strategy().Get(userID, strategyID)
+		if err != nil {
+			SafeNotFound(c, "Strategy")
+			return
+		}
+		config, err := strategy.ParseConfig()
+		if err != nil {
+			SafeInternalError(c, "Parse strategy config", err)
+			return
+		}
+		if config.StrategyType == "grid_trading" {
+			SafeBadRequest(c, "Grid strategies cannot be used in experiments")
+			return
+		}
+	}
+
+	exp := &store.StrategyExperiment{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		TraderID:    traderID,
+		Name:        req.Name,
+		StrategyAID: req.StrategyAID,
+		StrategyBID: req.StrategyBID,
+		Mode:        req.Mode,
+		SplitRatio:  req.SplitRatio,
+	}
+	if err := s.store.Experiment().Create(exp); err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, exp)
+}
+
+// handleListExperiments List a trader's strategy experiments
+func (s *Server) handleListExperiments(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	exps, err := s.store.Experiment().List(userID, traderID)
+	if err != nil {
+		SafeInternalError(c, "List experiments", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"experiments": exps})
+}
+
+// handleCompareExperiment Compare per-variant statistics of an experiment
+func (s *Server) handleCompareExperiment(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	exp, err := s.store.Experiment().Get(userID, c.Param("id"))
+	if err != nil {
+		SafeNotFound(c, "Experiment")
+		return
+	}
+
+	initialBalance := 0.0
+	if trader, err := s.store.Trader().Get(userID, exp.TraderID); err == nil {
+		initialBalance = trader.InitialBalance
+	}
+
+	variants, err := s.store.Experiment().GetVariantStats(exp, initialBalance)
+	if err != nil {
+		SafeInternalError(c, "Get experiment statistics", err)
+		return
+	}
+
+	// Strategy names for display (deleted strategies keep their ID only)
+	names := gin.H{}
+	for _, v := range variants {
+		if strategy, err := s.store.Strategy().Get(userID, v.StrategyID); err == nil {
+			names[v.Variant] = strategy.Name
+		}
+	}
+
+	// Leader by net PnL (return % in split mode, since capital differs); empty until both have closed trades
+	leader := ""
+	a, b := variants[0], variants[1]
+	if a.ClosedTrades > 0 && b.ClosedTrades > 0 {
+		scoreA, scoreB := a.NetPnL, b.NetPnL
+		if exp.Mode == store.ExperimentModeSplit {
+			scoreA, scoreB = a.ReturnPct, b.ReturnPct
+		}
+		if scoreA > scoreB {
+			leader = a.Variant
+		} else if scoreB > scoreA {
+			leader = b.Variant
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"experiment":     exp,
+		"variants":       variants,
+		"strategy_names": names,
+		"leader":         leader,
+	})
+}
+
+// handleStopExperiment Stop a running experiment (trader returns to its own strategy)
+func (s *Server) handleStopExperiment(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := s.store.Experiment().Stop(userID, c.Param("id")); err != nil {
+		SafeNotFound(c, "Running experiment")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Experiment stopped"})
+}
+
+// handleDeleteExperiment Delete an experiment and its recorded entries
+func (s *Server) handleDeleteExperiment(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := s.store.Experiment().Delete(userID, c.Param("id")); err != nil {
+		SafeNotFound(c, "Experiment")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Experiment deleted"})
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleUpdateGridConfig Update grid parameters of a trader's grid strategy and apply them to
+// the running trader without a restart. Omitted fields keep their current value.
+func (s *Server) handleUpdateGridConfig(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req struct {
+		UpperPrice      *float64 `json:"upper_price"`
+		LowerPrice      *float64 `json:"lower_price"`
+		UseATRBounds    *bool    `json:"use_atr_bounds"`
+		GridCount       *int     `json:"grid_count"`
+		TotalInvestment *float64 `json:"total_investment"`
+		Leverage        *int     `json:"leverage"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	fullConfig, err := s.store.Trader().GetFullConfig(userID, traderID)
+	if err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+	if fullConfig.Strategy == nil {
+		SafeBadRequest(c, "Trader is not using a grid strategy")
+		return
+	}
+	strategyConfig, err := fullConfig.Strategy.ParseConfig()
+	if err != nil || strategyConfig.StrategyType != "grid_trading" || strategyConfig.GridConfig == nil {
+		SafeBadRequest(c, "Trader is not using a grid strategy")
+		return
+	}
+	// The change is persisted to the strategy, so it must be the user's own
+	if fullConfig.Strategy.UserID != userID {
+		SafeForbidden(c, "Duplicate the strategy before changing its grid configuration")
+		return
+	}
+
+	gridConfig := *strategyConfig.GridConfig
+	if req.UpperPrice != nil {
+		gridConfig.UpperPrice = *req.UpperPrice
+	}
+	if req.LowerPrice != nil {
+		gridConfig.LowerPrice = *req.LowerPrice
+	}
+	if req.UseATRBounds != nil {
+		gridConfig.UseATRBounds = *req.UseATRBounds
+	}
+	if req.GridCount != nil {
+		gridConfig.GridCount = *req.GridCount
+	}
+	if req.TotalInvestment != nil {
+		gridConfig.TotalInvestment = *req.TotalInvestment
+	}
+	if req.Leverage != nil {
+		gridConfig.Leverage = *req.Leverage
+	}
+	if err := gridConfig.Validate(); err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+
+	// Apply to the loaded trader first: if the exchange rejects it, the stored strategy stays unchanged
+	appliedLive := false
+	if at, err := s.traderManager.GetTrader(traderID); err == nil && at.IsGridStrategy() {
+		liveConfig := gridConfig
+		if err := at.ReconfigureGrid(&liveConfig); err != nil {
+			SafeInternalError(c, "Reconfigure grid", err)
+			return
+		}
+		appliedLive = true
+	}
+
+	strategyConfig.GridConfig = &gridConfig
+	if err := fullConfig.Strategy.SetConfig(strategyConfig); err != nil {
+		SafeInternalError(c, "Serialize strategy configuration", err)
+		return
+	}
+	if err := s.store.Strategy().Update(fullConfig.Strategy); err != nil {
+		SafeInternalError(c, "Update strategy", err)
+		return
+	}
+
+	logger.Infof("✓ Trader %s grid configuration updated (applied live: %v)", traderID, appliedLive)
+	s.recordTraderEvent(traderID, store.TraderEventConfigChange, "Grid configuration updated", map[string]interface{}{
+		"grid_config":  gridConfig,
+		"applied_live": appliedLive,
+	})
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Grid configuration updated",
+		"grid_config":  gridConfig,
+		"applied_live": appliedLive,
+	})
+}
@@ -0,0 +1,303 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// minGridOrderNotional smallest order value (USDT, leverage included) a grid level is given;
+	// below it exchanges reject the orders
+	minGridOrderNotional = 10.0
+	minSuggestedGrids    = 5
+	maxSuggestedGrids    = 50
+	// defaultSuggestMaxLeverage leverage cap when the request doesn't set one
+	defaultSuggestMaxLeverage = 5
+)
+
+// detectGridRegime fetches the volatility and box data suggestions are based on (replaced in tests)
+var detectGridRegime = market.DetectRegime
+
+// gridSuggestRequest parameters of a grid suggestion
+type gridSuggestRequest struct {
+	Symbol      string  `json:"symbol" binding:"required"`
+	Investment  float64 `json:"investment" binding:"required"`
+	MarketType  string  `json:"market_type"`  // "futures" (default) or "spot"
+	MaxLeverage int     `json:"max_leverage"` // 0 = defaultSuggestMaxLeverage
+	AIModelID   string  `json:"ai_model_id"`  // Empty = heuristics only
+}
+
+// aiGridSuggestion parameters proposed by the AI
+type aiGridSuggestion struct {
+	UpperPrice   float64 `json:"upper_price"`
+	LowerPrice   float64 `json:"lower_price"`
+	GridCount    int     `json:"grid_count"`
+	Leverage     int     `json:"leverage"`
+	Distribution string  `json:"distribution"`
+	Reasoning    string  `json:"reasoning"`
+}
+
+// handleSuggestGridConfig Propose grid bounds, count and leverage for a symbol and investment from its
+// recent volatility (ATR, Bollinger width) and 1h boxes, optionally refined by the user's AI model.
+// Returns a GridStrategyConfig draft ready to be saved in a strategy.
+func (s *Server) handleSuggestGridConfig(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req gridSuggestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if req.Investment <= 0 {
+		SafeBadRequest(c, "Investment must be positive")
+		return
+	}
+	if req.MarketType != "" && req.MarketType != store.GridMarketFutures && req.MarketType != store.GridMarketSpot {
+		SafeBadRequest(c, "Market type must be futures or spot")
+		return
+	}
+	if req.MaxLeverage <= 0 {
+		req.MaxLeverage = defaultSuggestMaxLeverage
+	}
+	req.Symbol = market.Normalize(req.Symbol)
+
+	info, err := detectGridRegime(req.Symbol)
+	if err != nil || info.Box == nil || info.Box.CurrentPrice <= 0 || info.ATRPct <= 0 {
+		if err == nil {
+			err = fmt.Errorf("no volatility data")
+		}
+		SafeBadRequest(c, fmt.Sprintf("Market data unavailable for %s: %v", req.Symbol, err))
+		return
+	}
+
+	heuristic, reasoning, err := suggestGridConfig(&req, info)
+	if err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+
+	resp := gin.H{
+		"config":    heuristic,
+		"heuristic": heuristic,
+		"source":    "heuristic",
+		"reasoning": reasoning,
+		"market":    info,
+	}
+	if req.AIModelID != "" {
+		suggestion, err := s.askAIForGrid(userID, &req, info, heuristic)
+		if err == nil {
+			var draft *store.GridStrategyConfig
+			if draft, err = applyAIGridSuggestion(&req, info, heuristic, suggestion); err == nil {
+				resp["config"] = draft
+				resp["source"] = "ai"
+				resp["ai_reasoning"] = suggestion.Reasoning
+			}
+		}
+		if err != nil {
+			logger.Warnf("⚠️ AI grid suggestion for %s failed, returning heuristics: %v", req.Symbol, err)
+			resp["ai_error"] = err.Error()
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// suggestGridConfig deterministic grid parameters from the regime of a symbol
+// Bounds span a regime-dependent number of 1h ATRs around the price (the 10-day box when the market
+// is ranging inside it), levels are about half an ATR apart, and leverage follows the regime limits.
+func suggestGridConfig(req *gridSuggestRequest, info *market.RegimeInfo) (*store.GridStrategyConfig, []string, error) {
+	price := info.Box.CurrentPrice
+	atr := info.ATRPct / 100 * price
+	var reasoning []string
+
+	halfWidth := map[market.RegimeLevel]float64{
+		market.RegimeLevelNarrow:   6,
+		market.RegimeLevelStandard: 8,
+		market.RegimeLevelWide:     10,
+		market.RegimeLevelVolatile: 12,
+	}[info.Level]
+	if halfWidth == 0 {
+		halfWidth = 10
+	}
+	lower, upper := price-halfWidth*atr, price+halfWidth*atr
+	box := info.Box
+	if info.Regime == market.RegimeRanging && box.MidLower < price && price < box.MidUpper {
+		lower, upper = box.MidLower-atr, box.MidUpper+atr
+		reasoning = append(reasoning, fmt.Sprintf("Price is ranging inside its 10-day box (%s - %s), bounds follow it padded by one ATR",
+			formatSuggestPrice(box.MidLower), formatSuggestPrice(box.MidUpper)))
+	} else {
+		reasoning = append(reasoning, fmt.Sprintf("%s regime (%s volatility, ATR %.2f%%): bounds span ±%.0f hourly ATRs around the price",
+			info.Regime, info.Level, info.ATRPct, halfWidth))
+	}
+	lower = math.Max(lower, price*0.5)
+
+	spacing := math.Max(atr/2, price*0.003) // Levels closer than 0.3% barely cover fees
+	count := int(math.Round((upper - lower) / spacing))
+	count = max(minSuggestedGrids, min(count, maxSuggestedGrids))
+
+	leverage := map[market.RegimeLevel]int{
+		market.RegimeLevelNarrow:   2,
+		market.RegimeLevelStandard: 4,
+		market.RegimeLevelWide:     3,
+		market.RegimeLevelVolatile: 2,
+	}[info.Level]
+	if leverage == 0 {
+		leverage = 2
+	}
+	if info.Regime == market.RegimeTrending {
+		leverage = min(leverage, 2)
+	}
+	if req.MarketType == store.GridMarketSpot {
+		leverage = 1
+	}
+	leverage = max(1, min(leverage, req.MaxLeverage))
+
+	count, err := fitGridCount(count, req.Investment, leverage)
+	if err != nil {
+		return nil, nil, err
+	}
+	reasoning = append(reasoning, fmt.Sprintf("%d levels about %.2f%% apart at %dx leverage (%.2f USDT per level)",
+		count, (upper-lower)/float64(count)/price*100, leverage, req.Investment*float64(leverage)/float64(count)))
+
+	config := &store.GridStrategyConfig{
+		Symbol:             req.Symbol,
+		MarketType:         req.MarketType,
+		GridCount:          count,
+		TotalInvestment:    req.Investment,
+		Leverage:           leverage,
+		UpperPrice:         roundSuggestPrice(upper),
+		LowerPrice:         roundSuggestPrice(lower),
+		ATRMultiplier:      2.0,
+		Distribution:       "gaussian",
+		MaxDrawdownPct:     15,
+		StopLossPct:        5,
+		DailyLossLimitPct:  10,
+		UseMakerOnly:       true,
+		DirectionBiasRatio: 0.7,
+	}
+	if info.Regime != market.RegimeRanging {
+		config.Distribution = "uniform"
+	}
+	if info.Regime == market.RegimeTrending {
+		config.EnableDirectionAdjust = true
+		reasoning = append(reasoning, fmt.Sprintf("Price broke out of its %s box (%s): direction adjustment enabled", info.Breakout, info.BreakoutDirection))
+	}
+	return config, reasoning, nil
+}
+
+// fitGridCount lowers count until each level gets at least minGridOrderNotional
+func fitGridCount(count int, investment float64, leverage int) (int, error) {
+	affordable := int(investment * float64(leverage) / minGridOrderNotional)
+	if affordable < 2 {
+		return 0, fmt.Errorf("investment too small for a grid: at least %.0f USDT per level is needed", minGridOrderNotional)
+	}
+	return min(count, affordable), nil
+}
+
+// askAIForGrid asks the user's AI model for grid parameters, given the market data and the heuristic draft
+func (s *Server) askAIForGrid(userID string, req *gridSuggestRequest, info *market.RegimeInfo, heuristic *store.GridStrategyConfig) (*aiGridSuggestion, error) {
+	client, err := s.testRunAIClient(userID, req.AIModelID)
+	if err != nil {
+		return nil, err
+	}
+
+	systemPrompt := `You are a grid trading expert. Propose grid parameters for the given symbol from its market data.
+Keep the current price inside the bounds, space levels wide enough to cover fees, and prefer low leverage in volatile or trending markets.
+Reply with a single JSON object only:
+{"upper_price": number, "lower_price": number, "grid_count": integer, "leverage": integer, "distribution": "uniform"|"gaussian"|"pyramid", "reasoning": "short explanation"}`
+
+	box := info.Box
+	userPrompt := fmt.Sprintf(`Symbol: %s (%s)
+Investment: %.2f USDT, maximum leverage %d
+Current price: %s
+Regime: %s, volatility level %s, ATR14 (1h) %.2f%% of price, Bollinger width %.2f%%
+Breakout: %s %s
+Boxes (1h Donchian): 3 days %s - %s, 10 days %s - %s, 21 days %s - %s
+Heuristic proposal: lower %s, upper %s, %d levels, %dx leverage, %s distribution`,
+		req.Symbol, marketTypeOrDefault(req.MarketType), req.Investment, req.MaxLeverage, formatSuggestPrice(box.CurrentPrice),
+		info.Regime, info.Level, info.ATRPct, info.BollingerWidthPct, info.Breakout, info.BreakoutDirection,
+		formatSuggestPrice(box.ShortLower), formatSuggestPrice(box.ShortUpper), formatSuggestPrice(box.MidLower),
+		formatSuggestPrice(box.MidUpper), formatSuggestPrice(box.LongLower), formatSuggestPrice(box.LongUpper),
+		formatSuggestPrice(heuristic.LowerPrice), formatSuggestPrice(heuristic.UpperPrice), heuristic.GridCount,
+		heuristic.Leverage, heuristic.Distribution)
+
+	response, err := client.CallWithMessages(systemPrompt, userPrompt)
+	if err != nil {
+		return nil, fmt.Errorf("AI API call failed: %w", err)
+	}
+	return parseAIGridSuggestion(response)
+}
+
+// parseAIGridSuggestion extracts the JSON object of an AI reply (possibly wrapped in text or a code fence)
+func parseAIGridSuggestion(response string) (*aiGridSuggestion, error) {
+	start, end := strings.Index(response, "{"), strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON object in AI response")
+	}
+	var suggestion aiGridSuggestion
+	if err := json.Unmarshal([]byte(response[start:end+1]), &suggestion); err != nil {
+		return nil, fmt.Errorf("invalid AI response: %w", err)
+	}
+	return &suggestion, nil
+}
+
+// applyAIGridSuggestion merges the AI's parameters into the heuristic draft, rejecting bounds that don't
+// contain the price and clamping count and leverage to the same limits as the heuristics
+func applyAIGridSuggestion(req *gridSuggestRequest, info *market.RegimeInfo, heuristic *store.GridStrategyConfig, suggestion *aiGridSuggestion) (*store.GridStrategyConfig, error) {
+	price := info.Box.CurrentPrice
+	if suggestion.LowerPrice <= price*0.5 || suggestion.UpperPrice >= price*2 ||
+		suggestion.LowerPrice >= price || suggestion.UpperPrice <= price {
+		return nil, fmt.Errorf("AI bounds %s - %s don't fit the price %s", formatSuggestPrice(suggestion.LowerPrice),
+			formatSuggestPrice(suggestion.UpperPrice), formatSuggestPrice(price))
+	}
+
+	draft := *heuristic
+	draft.LowerPrice = roundSuggestPrice(suggestion.LowerPrice)
+	draft.UpperPrice = roundSuggestPrice(suggestion.UpperPrice)
+	if suggestion.Leverage > 0 && req.MarketType != store.GridMarketSpot {
+		draft.Leverage = min(suggestion.Leverage, req.MaxLeverage)
+	}
+	if suggestion.GridCount > 0 {
+		draft.GridCount = max(minSuggestedGrids, min(suggestion.GridCount, maxSuggestedGrids))
+	}
+	count, err := fitGridCount(draft.GridCount, draft.TotalInvestment, draft.Leverage)
+	if err != nil {
+		return nil, err
+	}
+	draft.GridCount = count
+	switch suggestion.Distribution {
+	case "uniform", "gaussian", "pyramid":
+		draft.Distribution = suggestion.Distribution
+	}
+	if err := draft.Validate(); err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+func marketTypeOrDefault(marketType string) string {
+	if marketType == "" {
+		return store.GridMarketFutures
+	}
+	return marketType
+}
+
+// roundSuggestPrice rounds a price to 5 significant digits
+func roundSuggestPrice(p float64) float64 {
+	if p <= 0 {
+		return 0
+	}
+	scale := math.Pow(10, 4-math.Floor(math.Log10(p)))
+	return math.Round(p*scale) / scale
+}
+
+func formatSuggestPrice(p float64) string {
+	return fmt.Sprintf("%g", roundSuggestPrice(p))
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"nofx/market"
+	"nofx/store"
+	"testing"
+)
+
+func testRegime(regime market.MarketRegime, level market.RegimeLevel) *market.RegimeInfo {
+	return &market.RegimeInfo{
+		Symbol: "BTCUSDT",
+		Regime: regime,
+		Level:  level,
+		ATRPct: 1, // 1000 at a price of 100000
+		Box: &market.BoxData{
+			ShortUpper: 102000, ShortLower: 98000,
+			MidUpper: 105000, MidLower: 96000,
+			LongUpper: 110000, LongLower: 90000,
+			CurrentPrice: 100000,
+		},
+	}
+}
+
+func TestSuggestGridConfig(t *testing.T) {
+	req := &gridSuggestRequest{Symbol: "BTCUSDT", Investment: 1000, MaxLeverage: 5}
+
+	// Ranging inside the 10-day box: bounds follow it, padded by one ATR
+	cfg, reasoning, err := suggestGridConfig(req, testRegime(market.RegimeRanging, market.RegimeLevelStandard))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.LowerPrice != 95000 || cfg.UpperPrice != 106000 || cfg.Leverage != 4 || cfg.Distribution != "gaussian" {
+		t.Errorf("ranging config = %+v", cfg)
+	}
+	if cfg.GridCount != 22 || len(reasoning) != 2 || cfg.Validate() != nil {
+		t.Errorf("grid count %d, reasoning %v", cfg.GridCount, reasoning)
+	}
+
+	// Trending: ATR based bounds, low leverage, direction adjustment
+	cfg, _, _ = suggestGridConfig(req, testRegime(market.RegimeTrending, market.RegimeLevelWide))
+	if cfg.LowerPrice != 90000 || cfg.UpperPrice != 110000 || cfg.Leverage != 2 || !cfg.EnableDirectionAdjust || cfg.Distribution != "uniform" {
+		t.Errorf("trending config = %+v", cfg)
+	}
+
+	// Spot grids never use leverage, and small investments get fewer levels
+	spot := &gridSuggestRequest{Symbol: "BTCUSDT", Investment: 100, MarketType: store.GridMarketSpot, MaxLeverage: 5}
+	cfg, _, _ = suggestGridConfig(spot, testRegime(market.RegimeRanging, market.RegimeLevelStandard))
+	if cfg.Leverage != 1 || cfg.GridCount != 10 {
+		t.Errorf("spot config = %+v", cfg)
+	}
+
+	if _, _, err := suggestGridConfig(&gridSuggestRequest{Investment: 10, MaxLeverage: 1}, testRegime(market.RegimeRanging, market.RegimeLevelNarrow)); err == nil {
+		t.Error("an investment too small for two levels should be rejected")
+	}
+}
+
+func TestApplyAIGridSuggestion(t *testing.T) {
+	req := &gridSuggestRequest{Symbol: "BTCUSDT", Investment: 1000, MaxLeverage: 3}
+	info := testRegime(market.RegimeRanging, market.RegimeLevelStandard)
+	heuristic, _, _ := suggestGridConfig(req, info)
+
+	suggestion, err := parseAIGridSuggestion("Here you go:\n```json\n" +
+		`{"upper_price": 104000.123, "lower_price": 97000, "grid_count": 80, "leverage": 10, "distribution": "pyramid", "reasoning": "tight box"}` + "\n```")
+	if err != nil {
+		t.Fatal(err)
+	}
+	draft, err := applyAIGridSuggestion(req, info, heuristic, suggestion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if draft.UpperPrice != 104000 || draft.LowerPrice != 97000 || draft.GridCount != maxSuggestedGrids ||
+		draft.Leverage != 3 || draft.Distribution != "pyramid" {
+		t.Errorf("draft = %+v", draft)
+	}
+	if heuristic.Distribution != "gaussian" {
+		t.Error("the heuristic draft must not be modified")
+	}
+
+	for _, bad := range []aiGridSuggestion{
+		{LowerPrice: 101000, UpperPrice: 110000}, // Price below the grid
+		{LowerPrice: 10000, UpperPrice: 110000},  // Absurdly wide
+	} {
+		if _, err := applyAIGridSuggestion(req, info, heuristic, &bad); err == nil {
+			t.Errorf("bounds %v - %v should be rejected", bad.LowerPrice, bad.UpperPrice)
+		}
+	}
+	if _, err := parseAIGridSuggestion("I cannot help with that"); err == nil {
+		t.Error("a reply without JSON should fail")
+	}
+}
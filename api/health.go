@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"nofx/market"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds each readiness dependency check
+const readinessTimeout = 3 * time.Second
+
+// ComponentStatus result of one readiness dependency check
+type ComponentStatus struct {
+	Name      string  `json:"name"`
+	Healthy   bool    `json:"healthy"`
+	Detail    string  `json:"detail,omitempty"`
+	LatencyMs float64 `json:"latency_ms"`
+}
+
+// handleLiveness GET /healthz: the process is up and serving requests, no dependency is checked
+// so orchestrators don't restart the container for an outage elsewhere
+func (s *Server) handleLiveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "time": time.Now().UTC()})
+}
+
+// handleReadiness GET /readyz: 200 when every dependency needed to trade is available, 503 otherwise,
+// with the status of each component
+func (s *Server) handleReadiness(c *gin.Context) {
+	components := s.checkReadiness(c.Request.Context())
+	status, code := "ready", http.StatusOK
+	for _, component := range components {
+		if !component.Healthy {
+			status, code = "not_ready", http.StatusServiceUnavailable
+			break
+		}
+	}
+	c.JSON(code, gin.H{"status": status, "components": components, "time": time.Now().UTC()})
+}
+
+// checkReadiness runs the dependency checks in order
+func (s *Server) checkReadiness(ctx context.Context) []ComponentStatus {
+	checks := []struct {
+		name  string
+		check func(ctx context.Context) (string, error)
+	}{
+		{"database", s.checkDatabase},
+		{"crypto", s.checkCrypto},
+		{"market_data", checkMarketData},
+		{"ai_provider", s.checkAIProvider},
+	}
+	components := make([]ComponentStatus, 0, len(checks))
+	for _, ch := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, readinessTimeout)
+		start := time.Now()
+		detail, err := ch.check(checkCtx)
+		cancel()
+		component := ComponentStatus{
+			Name:      ch.name,
+			Healthy:   err == nil,
+			Detail:    detail,
+			LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		}
+		if err != nil {
+			component.Detail = err.Error()
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+// checkDatabase the database answers a ping
+func (s *Server) checkDatabase(ctx context.Context) (string, error) {
+	if s.store == nil {
+		return "", fmt.Errorf("store not initialized")
+	}
+	if err := s.store.Ping(ctx); err != nil {
+		return "", err
+	}
+	return string(s.store.DBType()), nil
+}
+
+// checkCrypto the RSA key pair used to decrypt credentials is loaded
+func (s *Server) checkCrypto(_ context.Context) (string, error) {
+	if s.cryptoHandler == nil || s.cryptoHandler.cryptoService == nil {
+		return "", fmt.Errorf("crypto service not loaded")
+	}
+	cs := s.cryptoHandler.cryptoService
+	if cs.GetPublicKeyPEM() == "" {
+		return "", fmt.Errorf("RSA key pair not loaded")
+	}
+	if !cs.HasDataKey() {
+		return "RSA key loaded, no data encryption key", nil
+	}
+	return "RSA and data encryption keys loaded", nil
+}
+
+// checkMarketData at least one kline provider is outside its failure cooldown
+// Providers are probed by the market data health checks; a readiness request never waits on them.
+func checkMarketData(_ context.Context) (string, error) {
+	var healthy, down []string
+	for _, p := range market.Providers().Status() {
+		if p.Healthy {
+			healthy = append(healthy, p.Name)
+		} else {
+			down = append(down, fmt.Sprintf("%s (%s)", p.Name, p.LastError))
+		}
+	}
+	if len(healthy) == 0 {
+		return "", fmt.Errorf("no market data provider reachable: %s", strings.Join(down, ", "))
+	}
+	return "healthy: " + strings.Join(healthy, ", "), nil
+}
+
+// checkAIProvider at least one AI model is enabled
+func (s *Server) checkAIProvider(_ context.Context) (string, error) {
+	if s.store == nil {
+		return "", fmt.Errorf("store not initialized")
+	}
+	count, err := s.store.AIModel().CountEnabled()
+	if err != nil {
+		return "", err
+	}
+	if count == 0 {
+		return "", fmt.Errorf("no AI model configured")
+	}
+	return fmt.Sprintf("%d enabled AI models", count), nil
+}
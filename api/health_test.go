@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestHandleReadiness(t *testing.T) {
+	s, call := newTestServer(t)
+	st := s.store
+
+	ready := func() (int, map[string]ComponentStatus) {
+		w := call(s.handleReadiness, testRequest{target: "/readyz"})
+
+		var resp struct {
+			Components []ComponentStatus `json:"components"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		components := make(map[string]ComponentStatus)
+		for _, component := range resp.Components {
+			components[component.Name] = component
+		}
+		return w.Code, components
+	}
+
+	code, components := ready()
+	if code != http.StatusServiceUnavailable || len(components) != 4 {
+		t.Fatalf("status %d, components %+v", code, components)
+	}
+	if !components["database"].Healthy || components["crypto"].Healthy {
+		t.Errorf("database should be up and crypto missing: %+v", components)
+	}
+	if ai := components["ai_provider"]; ai.Healthy || ai.Detail != "no AI model configured" {
+		t.Errorf("ai_provider = %+v", ai)
+	}
+
+	if err := st.AIModel().Create("alice", "deepseek", "DeepSeek", "deepseek", true, "sk-test", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, components = ready(); !components["ai_provider"].Healthy {
+		t.Errorf("an enabled model should satisfy ai_provider: %+v", components["ai_provider"])
+	}
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+
+	"nofx/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ipAllowlistConfigKey system config key of the JSON encoded IPAllowlists
+const ipAllowlistConfigKey = "ip_allowlist"
+
+// IPAllowlists CIDRs (or single IPs) allowed to call the authenticated API, an empty list allows any address
+type IPAllowlists struct {
+	Trading []string `json:"trading"` // Every protected route
+	Admin   []string `json:"admin"`   // Admin routes, in addition to the trading list
+}
+
+// ipAllowlist parsed allowlists enforced by ipAllowlistMiddleware
+type ipAllowlist struct {
+	mu      sync.RWMutex
+	config  IPAllowlists
+	trading []netip.Prefix
+	admin   []netip.Prefix
+}
+
+// set validates and replaces both lists, nothing changes on error
+func (l *ipAllowlist) set(cfg IPAllowlists) error {
+	trading, err := parsePrefixes(cfg.Trading)
+	if err != nil {
+		return fmt.Errorf("trading allowlist: %w", err)
+	}
+	admin, err := parsePrefixes(cfg.Admin)
+	if err != nil {
+		return fmt.Errorf("admin allowlist: %w", err)
+	}
+	cfg.Trading, cfg.Admin = prefixStrings(trading), prefixStrings(admin)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config, l.trading, l.admin = cfg, trading, admin
+	return nil
+}
+
+func (l *ipAllowlist) get() IPAllowlists {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.config
+}
+
+// allowed whether ip passes the trading list and, for admin routes, the admin list
+func (l *ipAllowlist) allowed(admin bool, ip netip.Addr) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if !prefixesContain(l.trading, ip) {
+		return false
+	}
+	return !admin || prefixesContain(l.admin, ip)
+}
+
+// parsePrefixes parses CIDRs, a bare IP is a single address prefix
+func parsePrefixes(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid IP %q", entry)
+			}
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q", entry)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+func prefixStrings(prefixes []netip.Prefix) []string {
+	out := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		out[i] = prefix.String()
+	}
+	return out
+}
+
+// prefixesContain an empty list contains every address
+func prefixesContain(prefixes []netip.Prefix, ip netip.Addr) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	if !ip.IsValid() {
+		return false
+	}
+	ip = ip.Unmap()
+	for _, prefix := range prefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadIPAllowlist applies the allowlists stored in system config
+func (s *Server) loadIPAllowlist() {
+	value, err := s.store.GetSystemConfig(ipAllowlistConfigKey)
+	if err != nil || value == "" {
+		if err != nil {
+			logger.Errorf("Failed to load IP allowlist: %v", err)
+		}
+		return
+	}
+	var cfg IPAllowlists
+	if err := json.Unmarshal([]byte(value), &cfg); err != nil {
+		logger.Errorf("Failed to decode IP allowlist: %v", err)
+		return
+	}
+	if err := s.ipAllowlist.set(cfg); err != nil {
+		logger.Errorf("Failed to apply IP allowlist: %v", err)
+		return
+	}
+	if len(cfg.Trading) > 0 || len(cfg.Admin) > 0 {
+		logger.Infof("🔒 IP allowlist active: %d trading, %d admin entries", len(cfg.Trading), len(cfg.Admin))
+	}
+}
+
+// requestIP address the request came from
+// X-Forwarded-For is only honored from TRUSTED_PROXIES, otherwise anyone could claim an allowed address.
+func requestIP(c *gin.Context) netip.Addr {
+	addr, err := netip.ParseAddr(c.ClientIP())
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
+
+// ipAllowlistMiddleware rejects requests from addresses outside the allowlists (before authentication)
+func (s *Server) ipAllowlistMiddleware(admin bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := requestIP(c)
+		if !s.ipAllowlist.allowed(admin, ip) {
+			logger.Warnf("🚫 Blocked %s %s from %s: not in IP allowlist", c.Request.Method, c.Request.URL.Path, ip)
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access from this IP address is not allowed"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// handleGetIPAllowlist configured IP allowlists and the caller's address (admin)
+func (s *Server) handleGetIPAllowlist(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"allowlists": s.ipAllowlist.get(), "client_ip": requestIP(c).String()})
+}
+
+// handleUpdateIPAllowlist replaces the IP allowlists (admin)
+// Lists that would block the caller are rejected so an admin cannot lock themselves out.
+func (s *Server) handleUpdateIPAllowlist(c *gin.Context) {
+	var req IPAllowlists
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	var next ipAllowlist
+	if err := next.set(req); err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+	ip := requestIP(c)
+	if !next.allowed(true, ip) {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Allowlists would block your own address %s", ip)})
+		return
+	}
+
+	cfg := next.get()
+	value, err := json.Marshal(cfg)
+	if err != nil {
+		SafeInternalError(c, "Encode IP allowlist", err)
+		return
+	}
+	if err := s.store.SetSystemConfig(ipAllowlistConfigKey, string(value)); err != nil {
+		SafeInternalError(c, "Save IP allowlist", err)
+		return
+	}
+	if err := s.ipAllowlist.set(cfg); err != nil {
+		SafeInternalError(c, "Apply IP allowlist", err)
+		return
+	}
+	logger.Infof("🔒 IP allowlist updated by %s: %d trading, %d admin entries", c.GetString("user_id"), len(cfg.Trading), len(cfg.Admin))
+
+	c.JSON(http.StatusOK, gin.H{"allowlists": cfg, "client_ip": ip.String()})
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIPAllowlistParsing(t *testing.T) {
+	var l ipAllowlist
+	if err := l.set(IPAllowlists{Trading: []string{"10.0.0.0/8", " 203.0.113.7 ", "2001:db8::/32", ""}, Admin: []string{"10.1.2.3/16"}}); err != nil {
+		t.Fatal(err)
+	}
+	cfg := l.get()
+	if len(cfg.Trading) != 3 || cfg.Trading[1] != "203.0.113.7/32" || cfg.Admin[0] != "10.1.0.0/16" {
+		t.Errorf("entries should be normalized to prefixes, got %+v", cfg)
+	}
+
+	for _, c := range []struct {
+		ip    string
+		admin bool
+		want  bool
+	}{
+		{"10.9.9.9", false, true},
+		{"10.9.9.9", true, false},
+		{"10.1.5.5", true, true},
+		{"::ffff:203.0.113.7", false, true},
+		{"2001:db8::1", false, true},
+		{"198.51.100.1", false, false},
+	} {
+		if got := l.allowed(c.admin, netip.MustParseAddr(c.ip)); got != c.want {
+			t.Errorf("allowed(admin=%v, %s) = %v, want %v", c.admin, c.ip, got, c.want)
+		}
+	}
+
+	if err := l.set(IPAllowlists{Admin: []string{"not-an-ip"}}); err == nil {
+		t.Error("invalid entries should be rejected")
+	}
+	if len(l.get().Trading) != 3 {
+		t.Error("a rejected update must keep the previous lists")
+	}
+}
+
+func TestIPAllowlistMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &Server{}
+	if err := s.ipAllowlist.set(IPAllowlists{Trading: []string{"192.0.2.0/24"}}); err != nil {
+		t.Fatal(err)
+	}
+	router := gin.New()
+	router.GET("/api/ping", s.ipAllowlistMiddleware(false), func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+
+	serve := func(remote, forwarded string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/ping", nil)
+		req.RemoteAddr = remote
+		if forwarded != "" {
+			req.Header.Set("X-Forwarded-For", forwarded)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Without trusted proxies the forwarded header cannot be used to claim an allowed address
+	if err := router.SetTrustedProxies(nil); err != nil {
+		t.Fatal(err)
+	}
+	if code := serve("192.0.2.10:4000", ""); code != http.StatusOK {
+		t.Errorf("listed address should pass, got %d", code)
+	}
+	if code := serve("198.51.100.1:4000", "192.0.2.10"); code != http.StatusForbidden {
+		t.Errorf("spoofed X-Forwarded-For should be ignored, got %d", code)
+	}
+
+	if err := router.SetTrustedProxies([]string{"198.51.100.1"}); err != nil {
+		t.Fatal(err)
+	}
+	if code := serve("198.51.100.1:4000", "192.0.2.10"); code != http.StatusOK {
+		t.Errorf("client behind a trusted proxy should pass, got %d", code)
+	}
+	if code := serve("198.51.100.1:4000", "203.0.113.9"); code != http.StatusForbidden {
+		t.Errorf("unlisted client behind a trusted proxy should be blocked, got %d", code)
+	}
+}
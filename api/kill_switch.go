@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"nofx/logger"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetKillSwitch Current state of the system-wide kill switch
+func (s *Server) handleGetKillSwitch(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"kill_switch": s.traderManager.KillSwitch().State()})
+}
+
+// handleEngageKillSwitch Halt all traders of all users immediately (exchange API incident, bad deploy)
+// Optionally cancels their open orders and/or closes their positions. Stays engaged across restarts
+// until released.
+func (s *Server) handleEngageKillSwitch(c *gin.Context) {
+	var req struct {
+		Reason         string `json:"reason" binding:"required"`
+		CancelOrders   bool   `json:"cancel_orders"`
+		ClosePositions bool   `json:"close_positions"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Reason) == "" {
+		SafeBadRequest(c, "A reason is required")
+		return
+	}
+
+	state := trader.KillSwitchState{
+		Active:         true,
+		Reason:         strings.TrimSpace(req.Reason),
+		EngagedBy:      c.GetString("email"),
+		EngagedAt:      time.Now().UTC(),
+		CancelOrders:   req.CancelOrders,
+		ClosePositions: req.ClosePositions,
+	}
+	// Persist first: a restart right after engaging must not resume trading
+	if err := s.saveKillSwitch(state); err != nil {
+		SafeInternalError(c, "Save kill switch", err)
+		return
+	}
+	logger.Warnf("🛑 Kill switch engaged by %s (cancel orders: %v, close positions: %v): %s",
+		state.EngagedBy, state.CancelOrders, state.ClosePositions, state.Reason)
+
+	results := s.traderManager.EngageKillSwitch(state)
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"kill_switch": state,
+		"traders":     results,
+		"failed":      failed,
+	})
+}
+
+// handleReleaseKillSwitch Let traders run their cycles again
+func (s *Server) handleReleaseKillSwitch(c *gin.Context) {
+	if !s.traderManager.KillSwitch().State().Active {
+		c.JSON(http.StatusOK, gin.H{"message": "Kill switch is not engaged"})
+		return
+	}
+	if err := s.saveKillSwitch(trader.KillSwitchState{}); err != nil {
+		SafeInternalError(c, "Save kill switch", err)
+		return
+	}
+	s.traderManager.ReleaseKillSwitch()
+	logger.Warnf("✅ Kill switch released by %s", c.GetString("email"))
+	c.JSON(http.StatusOK, gin.H{"message": "Kill switch released"})
+}
+
+// saveKillSwitch persists the kill switch state in system config
+func (s *Server) saveKillSwitch(state trader.KillSwitchState) error {
+	value, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return s.store.SetSystemConfig(trader.KillSwitchConfigKey, string(value))
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleGetLimitEntries Get trader's AI limit entries (pending and recently closed)
+func (s *Server) handleGetLimitEntries(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	limit := 50
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 500 {
+		limit = l
+	}
+
+	entries, err := s.store.LimitEntry().List(traderID, limit)
+	if err != nil {
+		SafeInternalError(c, "Get limit entries", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
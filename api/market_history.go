@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"nofx/market"
+	"nofx/store"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxMarketHistoryPoints readings returned per request (30 days at one reading per 5 minutes)
+const maxMarketHistoryPoints = 8640
+
+// marketHistoryQuery parses symbol (required) and hours (default 168, max 2160) of a market history request
+func marketHistoryQuery(c *gin.Context) (string, time.Time, bool) {
+	symbol := c.Query("symbol")
+	if symbol == "" {
+		SafeBadRequest(c, "symbol is required")
+		return "", time.Time{}, false
+	}
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", "168"))
+	if err != nil || hours <= 0 {
+		hours = 168
+	}
+	if hours > 2160 {
+		hours = 2160
+	}
+	return market.Normalize(symbol), time.Now().Add(-time.Duration(hours) * time.Hour), true
+}
+
+// handleFundingHistory funding rate history of a symbol for charting alongside decisions
+// Query: symbol (required), hours (default 168, max 2160)
+func (s *Server) handleFundingHistory(c *gin.Context) {
+	symbol, since, ok := marketHistoryQuery(c)
+	if !ok {
+		return
+	}
+	points, err := s.store.MarketHistory().ListFunding(symbol, since, maxMarketHistoryPoints)
+	if err != nil {
+		SafeInternalError(c, "Get funding rate history", err)
+		return
+	}
+	if points == nil {
+		points = []*store.FundingRatePoint{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"symbol": symbol,
+		"points": points,
+	})
+}
+
+// handleOIHistory open interest history of a symbol for charting alongside decisions
+// Query: symbol (required), hours (default 168, max 2160)
+func (s *Server) handleOIHistory(c *gin.Context) {
+	symbol, since, ok := marketHistoryQuery(c)
+	if !ok {
+		return
+	}
+	points, err := s.store.MarketHistory().ListOpenInterest(symbol, since, maxMarketHistoryPoints)
+	if err != nil {
+		SafeInternalError(c, "Get open interest history", err)
+		return
+	}
+	if points == nil {
+		points = []*store.OpenInterestPoint{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"symbol": symbol,
+		"points": points,
+	})
+}
+
+// handleMarketDataProviders health of the kline providers in failover order
+func (s *Server) handleMarketDataProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": market.Providers().Status()})
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/mail"
+	"nofx/config"
+	"nofx/logger"
+	"nofx/notify"
+	"nofx/store"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetNotifier enables the email notification endpoints (nil: SMTP not configured)
+func (s *Server) SetNotifier(n *notify.Notifier) {
+	s.notifier = n
+}
+
+// notificationCodeHash stored form of an address verification code, bound to the user
+func notificationCodeHash(userID, code string) string {
+	sum := sha256.Sum256([]byte(userID + ":" + strings.TrimSpace(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+// notificationResponse settings as returned to the user
+func (s *Server) notificationResponse(settings *store.NotificationSettings) gin.H {
+	return gin.H{
+		"enabled":          s.notifier != nil,
+		"email":            settings.Email,
+		"email_verified":   settings.EmailVerified,
+		"pending":          settings.VerifyCodeHash != "",
+		"events":           settings.EventTypes(),
+		"available_events": store.NotificationEvents,
+		"daily_digest":     settings.DailyDigest,
+		"digest_hour":      config.Get().NotificationDigestHour,
+	}
+}
+
+// handleGetNotificationSettings Get current user's email notification settings
+func (s *Server) handleGetNotificationSettings(c *gin.Context) {
+	settings, err := s.store.Notification().Get(c.GetString("user_id"))
+	if err != nil {
+		SafeInternalError(c, "Get notification settings", err)
+		return
+	}
+	c.JSON(http.StatusOK, s.notificationResponse(settings))
+}
+
+// handleUpdateNotificationSettings Set the event types emailed and the daily digest option
+func (s *Server) handleUpdateNotificationSettings(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req struct {
+		Events      []string `json:"events"`
+		DailyDigest bool     `json:"daily_digest"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	for _, eventType := range req.Events {
+		known := false
+		for _, t := range store.NotificationEvents {
+			known = known || t == eventType
+		}
+		if !known {
+			SafeBadRequest(c, fmt.Sprintf("Unknown event type %q", eventType))
+			return
+		}
+	}
+	if len(req.Events) == 0 {
+		req.Events = store.DefaultNotificationEventTypes()
+	}
+
+	if err := s.store.Notification().UpdatePreferences(userID, req.Events, req.DailyDigest); err != nil {
+		SafeInternalError(c, "Update notification settings", err)
+		return
+	}
+	settings, err := s.store.Notification().Get(userID)
+	if err != nil {
+		SafeInternalError(c, "Get notification settings", err)
+		return
+	}
+	c.JSON(http.StatusOK, s.notificationResponse(settings))
+}
+
+// handleSetNotificationEmail Set the notification address and email it a verification code
+func (s *Server) handleSetNotificationEmail(c *gin.Context) {
+	if s.notifier == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Email notifications are not configured on this server"})
+		return
+	}
+	userID := c.GetString("user_id")
+
+	var req struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	addr, err := mail.ParseAddress(strings.TrimSpace(req.Email))
+	if err != nil || addr.Name != "" {
+		SafeBadRequest(c, "Invalid email address")
+		return
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		SafeInternalError(c, "Generate verification code", err)
+		return
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+	expiresAt := time.Now().Add(notify.VerificationCodeTTL)
+	if err := s.store.Notification().SetPendingEmail(userID, addr.Address, notificationCodeHash(userID, code), expiresAt); err != nil {
+		SafeInternalError(c, "Save notification email", err)
+		return
+	}
+	if err := s.notifier.SendVerification(addr.Address, code); err != nil {
+		logger.Warnf("⚠️ Failed to send verification email for user %s: %v", userID, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to send verification email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Verification code sent", "email": addr.Address, "expires_at": expiresAt})
+}
+
+// handleVerifyNotificationEmail Verify the notification address with the emailed code
+func (s *Server) handleVerifyNotificationEmail(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	verified, err := s.store.Notification().VerifyEmail(userID, notificationCodeHash(userID, req.Code), time.Now())
+	if err != nil {
+		SafeInternalError(c, "Verify notification email", err)
+		return
+	}
+	if !verified {
+		SafeBadRequest(c, "Invalid or expired verification code")
+		return
+	}
+	settings, err := s.store.Notification().Get(userID)
+	if err != nil {
+		SafeInternalError(c, "Get notification settings", err)
+		return
+	}
+	c.JSON(http.StatusOK, s.notificationResponse(settings))
+}
+
+// handleTestNotification Send a test email to the verified notification address
+func (s *Server) handleTestNotification(c *gin.Context) {
+	if s.notifier == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Email notifications are not configured on this server"})
+		return
+	}
+	userID := c.GetString("user_id")
+
+	settings, err := s.store.Notification().Get(userID)
+	if err != nil {
+		SafeInternalError(c, "Get notification settings", err)
+		return
+	}
+	if !settings.EmailVerified || settings.Email == "" {
+		SafeBadRequest(c, "Verify a notification email address first")
+		return
+	}
+	if err := s.notifier.SendTest(settings.Email); err != nil {
+		logger.Warnf("⚠️ Failed to send test email for user %s: %v", userID, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to send test email"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Test email sent", "email": settings.Email})
+}
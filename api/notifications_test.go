@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNotificationEmailVerification(t *testing.T) {
+	s, call := newTestServer(t)
+	st := s.store
+	post := func(handler gin.HandlerFunc, body gin.H) *httptest.ResponseRecorder {
+		return call(handler, testRequest{method: http.MethodPost, body: body, userID: "alice"})
+	}
+
+	// Without SMTP configured, no verification email can be sent
+	if w := post(s.handleSetNotificationEmail, gin.H{"email": "alice@example.com"}); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without a notifier, got %d", w.Code)
+	}
+
+	expires := time.Now().Add(time.Minute)
+	if err := st.Notification().SetPendingEmail("alice", "alice@example.com", notificationCodeHash("alice", "123456"), expires); err != nil {
+		t.Fatalf("set email: %v", err)
+	}
+	if w := post(s.handleVerifyNotificationEmail, gin.H{"code": "654321"}); w.Code != http.StatusBadRequest {
+		t.Fatalf("wrong code should be rejected, got %d", w.Code)
+	}
+	w := post(s.handleVerifyNotificationEmail, gin.H{"code": "123456"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("verify failed: %d %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		EmailVerified bool     `json:"email_verified"`
+		Events        []string `json:"events"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !resp.EmailVerified || len(resp.Events) != len(store.DefaultNotificationEvents) {
+		t.Errorf("expected verified address with default events, got %+v", resp)
+	}
+
+	// The pending code is discarded after too many wrong attempts
+	if err := st.Notification().SetPendingEmail("alice", "new@example.com", notificationCodeHash("alice", "111111"), expires); err != nil {
+		t.Fatalf("set email: %v", err)
+	}
+	for i := 0; i < store.MaxVerifyAttempts; i++ {
+		post(s.handleVerifyNotificationEmail, gin.H{"code": "000000"})
+	}
+	if w := post(s.handleVerifyNotificationEmail, gin.H{"code": "111111"}); w.Code != http.StatusBadRequest {
+		t.Errorf("code should be discarded after %d wrong attempts, got %d", store.MaxVerifyAttempts, w.Code)
+	}
+
+	if w := post(s.handleUpdateNotificationSettings, gin.H{"events": []string{"start"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown event type should be rejected, got %d", w.Code)
+	}
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate go run ../cmd/openapi-gen -pkg . -out openapi.json
+
+// openAPISpec the OpenAPI 3 specification generated from the routes and handlers of this package
+// Regenerate it with `go generate ./api` after changing a route, a request or a response type.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// swaggerUIPage renders the spec with Swagger UI, loaded from a CDN to keep it out of the binary
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>NOFX API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/api/docs/openapi.json", dom_id: "#swagger-ui", persistAuthorization: true });
+  </script>
+</body>
+</html>`
+
+// handleAPIDocs GET /api/docs: interactive API documentation
+func (s *Server) handleAPIDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
+
+// handleOpenAPISpec GET /api/docs/openapi.json: the OpenAPI 3 specification of the REST API
+func (s *Server) handleOpenAPISpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", openAPISpec)
+}
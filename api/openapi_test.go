@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestOpenAPISpecCoversRoutes fails when a route is added without running `go generate ./api`
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &Server{router: gin.New()}
+	s.setupRoutes()
+
+	var spec struct {
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			SecuritySchemes map[string]json.RawMessage `json:"securitySchemes"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatalf("embedded spec is not valid JSON: %v", err)
+	}
+	if spec.Components.SecuritySchemes["bearerAuth"] == nil || spec.Components.SecuritySchemes["cookieAuth"] == nil {
+		t.Error("the spec should define the bearer and cookie auth schemes")
+	}
+
+	// Any registers every method, only GET and POST are documented
+	methodCount := make(map[string]int)
+	for _, r := range s.router.Routes() {
+		methodCount[r.Path]++
+	}
+	for _, r := range s.router.Routes() {
+		method := strings.ToLower(r.Method)
+		if methodCount[r.Path] > 5 && method != "get" && method != "post" {
+			continue
+		}
+		path := openAPIPathOf(r.Path)
+		if _, ok := spec.Paths[path][method]; !ok {
+			t.Errorf("%s %s is missing from openapi.json, run go generate ./api", r.Method, r.Path)
+		}
+	}
+}
+
+// openAPIPathOf converts gin path parameters to OpenAPI templates
+func openAPIPathOf(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			segments[i] = "{" + seg[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleTogglePaperMode Switch a trader between paper mode (simulated fills) and live trading
+func (s *Server) handleTogglePaperMode(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req struct {
+		PaperMode bool `json:"paper_mode"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	fullConfig, err := s.store.Trader().GetFullConfig(userID, traderID)
+	if err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	// Switching mode while running would mix real and simulated positions in one cycle
+	if at, err := s.traderManager.GetTrader(traderID); err == nil {
+		if running, ok := at.GetStatus()["is_running"].(bool); ok && running {
+			c.JSON(http.StatusConflict, gin.H{"error": "Stop the trader before switching paper mode"})
+			return
+		}
+	}
+
+	if req.PaperMode && fullConfig.Trader.IsInverse() {
+		SafeBadRequest(c, "Paper mode does not support inverse contracts")
+		return
+	}
+	if req.PaperMode && fullConfig.Strategy != nil {
+		if cfg, err := fullConfig.Strategy.ParseConfig(); err == nil && cfg.StrategyType == "grid_trading" {
+			SafeBadRequest(c, "Paper mode does not support grid trading strategies")
+			return
+		}
+	}
+
+	if err := s.store.Trader().UpdatePaperMode(userID, traderID, req.PaperMode); err != nil {
+		SafeInternalError(c, "Update paper mode", err)
+		return
+	}
+
+	// Reload so the trader is rebuilt with (or without) the paper executor
+	s.traderManager.RemoveTrader(traderID)
+	if err := s.traderManager.LoadUserTradersFromStore(s.store, userID); err != nil {
+		logger.Infof("⚠️ Failed to reload user traders into memory: %v", err)
+	}
+
+	mode := "live"
+	if req.PaperMode {
+		mode = "paper"
+	}
+	logger.Infof("✓ Trader %s switched to %s mode", traderID, mode)
+	s.recordTraderEvent(traderID, store.TraderEventConfigChange, "Switched to "+mode+" mode", nil)
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Paper mode updated",
+		"paper_mode": req.PaperMode,
+	})
+}
+
+// handleGetPaperPositions Get trader's simulated positions and paper trading result
+func (s *Server) handleGetPaperPositions(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	traderRecord, err := s.store.Trader().Get(userID, traderID)
+	if err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	limit := 100
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 500 {
+		limit = l
+	}
+
+	positions, err := s.store.Paper().List(traderID, limit)
+	if err != nil {
+		SafeInternalError(c, "Get paper positions", err)
+		return
+	}
+	summary, err := s.store.Paper().Summary(traderID)
+	if err != nil {
+		SafeInternalError(c, "Get paper trading summary", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"paper_mode":      traderRecord.PaperMode,
+		"initial_balance": traderRecord.InitialBalance,
+		"wallet_balance":  traderRecord.InitialBalance + summary.RealizedPnL - summary.TotalFee - summary.OpenFee,
+		"summary":         summary,
+		"positions":       positions,
+	})
+}
+
+// handleResetPaperPositions Delete trader's simulated positions to start paper trading over
+func (s *Server) handleResetPaperPositions(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	if err := s.store.Paper().Reset(traderID); err != nil {
+		SafeInternalError(c, "Reset paper positions", err)
+		return
+	}
+
+	logger.Infof("✓ Paper positions reset for trader %s", traderID)
+	c.JSON(http.StatusOK, gin.H{"message": "Paper positions reset"})
+}
+
+// closePaperPosition closes a simulated position through the loaded paper mode trader
+func (s *Server) closePaperPosition(c *gin.Context, traderID, symbol, side string) {
+	at, err := s.traderManager.GetTrader(traderID)
+	if err != nil || !at.IsPaperMode() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Paper mode trader is not loaded"})
+		return
+	}
+
+	var result map[string]interface{}
+	switch side {
+	case "LONG":
+		result, err = at.GetUnderlyingTrader().CloseLong(symbol, 0)
+	case "SHORT":
+		result, err = at.GetUnderlyingTrader().CloseShort(symbol, 0)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "side must be LONG or SHORT"})
+		return
+	}
+	if err != nil {
+		SafeInternalError(c, "Close paper position", err)
+		return
+	}
+
+	logger.Infof("✅ Paper position closed: trader=%s, symbol=%s, side=%s", traderID, symbol, side)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Position closed successfully",
+		"symbol":  symbol,
+		"side":    side,
+		"result":  result,
+	})
+}
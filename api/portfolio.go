@@ -0,0 +1,20 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handlePortfolio Consolidated equity, netted symbol exposure, margin usage and combined PnL curve
+// of all the user's traders (cached for 30s, refresh=true recomputes)
+func (s *Server) handlePortfolio(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	portfolio, err := s.traderManager.GetPortfolio(s.store, userID, c.Query("refresh") == "true")
+	if err != nil {
+		SafeInternalError(c, "Get portfolio", err)
+		return
+	}
+	c.JSON(http.StatusOK, portfolio)
+}
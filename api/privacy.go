@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/http"
+	"nofx/auth"
+	"nofx/store"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// optionalAuthMiddleware sets user_id when a valid token is sent, without rejecting anonymous requests.
+// Public endpoints use it so owners still see their own private traders.
+func (s *Server) optionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenParts := strings.Split(authorizationHeader(c), " ")
+		if len(tokenParts) == 2 && tokenParts[0] == "Bearer" && !auth.IsTokenBlacklisted(tokenParts[1]) {
+			if claims, err := auth.ValidateJWT(tokenParts[1]); err == nil {
+				c.Set("user_id", claims.UserID)
+				c.Set("email", claims.Email)
+			}
+		}
+		c.Next()
+	}
+}
+
+// publicTraderAccess reports whether the requester may see a trader's public data: owners always
+// can, others only if the trader is shown in competition and its owner opted in to the leaderboard.
+// Returned settings are nil for the owner (real name), the owner's settings otherwise.
+func (s *Server) publicTraderAccess(c *gin.Context, traderID string) (*store.PrivacySettings, bool) {
+	traderRecord, err := s.store.Trader().GetByID(traderID)
+	if err != nil || traderRecord == nil {
+		return nil, false
+	}
+	if userID := c.GetString("user_id"); userID != "" && userID == traderRecord.UserID {
+		return nil, true
+	}
+	if !traderRecord.ShowInCompetition {
+		return nil, false
+	}
+	settings, err := s.store.Privacy().Get(traderRecord.UserID)
+	if err != nil || !settings.LeaderboardOptIn {
+		return nil, false
+	}
+	return settings, true
+}
+
+// filterPublicTraders keeps the trader IDs the requester may see
+func (s *Server) filterPublicTraders(c *gin.Context, traderIDs []string) []string {
+	visible := make([]string, 0, len(traderIDs))
+	for _, traderID := range traderIDs {
+		if traderID == "" {
+			continue
+		}
+		if _, ok := s.publicTraderAccess(c, traderID); ok {
+			visible = append(visible, traderID)
+		}
+	}
+	return visible
+}
+
+// handleGetPrivacySettings Get current user's leaderboard privacy settings
+func (s *Server) handleGetPrivacySettings(c *gin.Context) {
+	settings, err := s.store.Privacy().Get(c.GetString("user_id"))
+	if err != nil {
+		SafeInternalError(c, "Get privacy settings", err)
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// handleUpdatePrivacySettings Update current user's leaderboard opt-in and name anonymization
+func (s *Server) handleUpdatePrivacySettings(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req struct {
+		LeaderboardOptIn *bool `json:"leaderboard_opt_in"`
+		AnonymizeNames   *bool `json:"anonymize_names"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	settings, err := s.store.Privacy().Get(userID)
+	if err != nil {
+		SafeInternalError(c, "Get privacy settings", err)
+		return
+	}
+	if req.LeaderboardOptIn != nil {
+		settings.LeaderboardOptIn = *req.LeaderboardOptIn
+	}
+	if req.AnonymizeNames != nil {
+		settings.AnonymizeNames = *req.AnonymizeNames
+	}
+	if err := s.store.Privacy().Save(settings); err != nil {
+		SafeInternalError(c, "Update privacy settings", err)
+		return
+	}
+
+	// Public leaderboard is cached, apply the change immediately
+	s.traderManager.InvalidateCompetitionCache()
+
+	c.JSON(http.StatusOK, settings)
+}
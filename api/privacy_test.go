@@ -0,0 +1,55 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPublicTraderAccess(t *testing.T) {
+	s, _ := newTestServer(t)
+	st := s.store
+
+	for _, tr := range []*store.Trader{
+		{ID: "public_t", UserID: "alice", Name: "Alpha", ShowInCompetition: true},
+		{ID: "hidden_t", UserID: "alice", Name: "Hidden", ShowInCompetition: false},
+		{ID: "bob_t", UserID: "bob", Name: "Bravo", ShowInCompetition: true},
+	} {
+		if err := st.Trader().Create(tr); err != nil {
+			t.Fatalf("create trader: %v", err)
+		}
+	}
+	// gorm skips zero-value bools on create when the column has a default
+	_ = st.Trader().UpdateShowInCompetition("alice", "hidden_t", false)
+	_ = st.Privacy().Save(&store.PrivacySettings{UserID: "alice", LeaderboardOptIn: true, AnonymizeNames: true})
+
+	newCtx := func(userID string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		if userID != "" {
+			c.Set("user_id", userID)
+		}
+		return c
+	}
+
+	anon := newCtx("")
+	if settings, ok := s.publicTraderAccess(anon, "public_t"); !ok || settings.DisplayName("public_t", "Alpha") == "Alpha" {
+		t.Error("opted-in trader should be public with an anonymized name")
+	}
+	if _, ok := s.publicTraderAccess(anon, "hidden_t"); ok {
+		t.Error("trader hidden from competition must not be public")
+	}
+	if _, ok := s.publicTraderAccess(anon, "bob_t"); ok {
+		t.Error("trader of an owner who did not opt in must not be public")
+	}
+	if settings, ok := s.publicTraderAccess(newCtx("bob"), "bob_t"); !ok || settings.DisplayName("bob_t", "Bravo") != "Bravo" {
+		t.Error("owner should see their own private trader under its real name")
+	}
+
+	visible := s.filterPublicTraders(anon, []string{"public_t", "hidden_t", "bob_t", "missing"})
+	if len(visible) != 1 || visible[0] != "public_t" {
+		t.Errorf("unexpected visible traders: %v", visible)
+	}
+}
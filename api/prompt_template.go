@@ -0,0 +1,143 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxPromptTemplates templates a user can define
+const maxPromptTemplates = 50
+
+// promptTemplateRequest create/update body of a prompt template
+type promptTemplateRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Content     string `json:"content"`
+}
+
+// handleListPromptTemplates Built-in prompt templates and the user's own
+func (s *Server) handleListPromptTemplates(c *gin.Context) {
+	templates, err := s.store.PromptTemplate().List(c.GetString("user_id"))
+	if err != nil {
+		SafeInternalError(c, "List prompt templates", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+// handleGetPromptTemplate A prompt template with the content of its latest version
+func (s *Server) handleGetPromptTemplate(c *gin.Context) {
+	t, err := s.store.PromptTemplate().Get(c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		SafeNotFound(c, "Prompt template")
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// handleCreatePromptTemplate Create a named prompt template (version 1)
+func (s *Server) handleCreatePromptTemplate(c *gin.Context) {
+	userID := c.GetString("user_id")
+	var req promptTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	templates, err := s.store.PromptTemplate().List(userID)
+	if err != nil {
+		SafeInternalError(c, "List prompt templates", err)
+		return
+	}
+	if len(templates)-len(store.BuiltinPromptTemplates) >= maxPromptTemplates {
+		SafeBadRequest(c, fmt.Sprintf("At most %d prompt templates can be defined", maxPromptTemplates))
+		return
+	}
+
+	t := &store.PromptTemplate{UserID: userID, Name: req.Name, Description: req.Description, Content: req.Content}
+	if err := t.Validate(); err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+	if err := s.store.PromptTemplate().Create(t); err != nil {
+		SafeInternalError(c, "Create prompt template", err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// handleUpdatePromptTemplate Edit a prompt template, a changed content becomes a new version
+// Strategies pinned to an older version keep using it, the others use the new version once their traders reload.
+func (s *Server) handleUpdatePromptTemplate(c *gin.Context) {
+	userID := c.GetString("user_id")
+	existing, err := s.store.PromptTemplate().Get(userID, c.Param("id"))
+	if err != nil {
+		SafeNotFound(c, "Prompt template")
+		return
+	}
+	if existing.Builtin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Built-in prompt templates cannot be modified"})
+		return
+	}
+
+	var req promptTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	t := &store.PromptTemplate{ID: existing.ID, UserID: userID, Name: req.Name, Description: req.Description, Content: req.Content, CreatedAt: existing.CreatedAt}
+	if err := t.Validate(); err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+	if err := s.store.PromptTemplate().Update(t); err != nil {
+		SafeInternalError(c, "Update prompt template", err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// handleDeletePromptTemplate Delete a prompt template and all its versions, refused while it is selected
+func (s *Server) handleDeletePromptTemplate(c *gin.Context) {
+	if err := s.store.PromptTemplate().Delete(c.GetString("user_id"), c.Param("id")); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			SafeNotFound(c, "Prompt template")
+		case errors.Is(err, store.ErrPromptTemplateInUse):
+			c.JSON(http.StatusConflict, gin.H{"error": "Prompt template is used by a strategy or trader, select another template there first"})
+		default:
+			SafeInternalError(c, "Delete prompt template", err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Prompt template deleted"})
+}
+
+// handlePromptTemplateVersions All versions of a prompt template, newest first
+func (s *Server) handlePromptTemplateVersions(c *gin.Context) {
+	versions, err := s.store.PromptTemplate().Versions(c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			SafeNotFound(c, "Prompt template")
+			return
+		}
+		SafeInternalError(c, "List prompt template versions", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"versions": versions})
+}
+
+// resolveStrategyPromptTemplate loads the prompt template a strategy config selects, responding 400 when it
+// does not exist (anymore)
+func (s *Server) resolveStrategyPromptTemplate(c *gin.Context, cfg *store.StrategyConfig) bool {
+	if err := s.store.ResolvePromptTemplate(c.GetString("user_id"), cfg, ""); err != nil {
+		SafeBadRequest(c, "Prompt template not found")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultTranscriptRange exported when the request gives no start
+	defaultTranscriptRange = 7 * 24 * time.Hour
+	// maxTranscriptRange longest date range of one export
+	maxTranscriptRange = 93 * 24 * time.Hour
+	// transcriptBatchSize decision records loaded (and flushed to the client) at a time
+	transcriptBatchSize = 100
+)
+
+var (
+	walletAddressPattern = regexp.MustCompile(`0x[0-9a-fA-F]{40,64}`)
+	emailPattern         = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	longNumberPattern    = regexp.MustCompile(`\d{8,}`)
+)
+
+// promptTranscript one line of a transcript export: exactly what the AI was sent and answered in a cycle
+type promptTranscript struct {
+	ID           int64                  `json:"id"`
+	TraderID     string                 `json:"trader_id"`
+	CycleNumber  int                    `json:"cycle_number"`
+	Timestamp    time.Time              `json:"timestamp"`
+	AIModel      string                 `json:"ai_model"`
+	SystemPrompt string                 `json:"system_prompt"`
+	UserPrompt   string                 `json:"user_prompt"`
+	RawResponse  string                 `json:"raw_response"`
+	CoTTrace     string                 `json:"cot_trace,omitempty"`
+	Decisions    json.RawMessage        `json:"decisions,omitempty"` // Decisions parsed from the response
+	Actions      []store.DecisionAction `json:"actions,omitempty"`   // Executed actions and their results
+	Success      bool                   `json:"success"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+func newPromptTranscript(record *store.DecisionRecord) *promptTranscript {
+	t := &promptTranscript{
+		ID:           record.ID,
+		TraderID:     record.TraderID,
+		CycleNumber:  record.CycleNumber,
+		Timestamp:    record.Timestamp,
+		AIModel:      record.AIModel,
+		SystemPrompt: record.SystemPrompt,
+		UserPrompt:   record.InputPrompt,
+		RawResponse:  record.RawResponse,
+		CoTTrace:     record.CoTTrace,
+		Actions:      record.Decisions,
+		Success:      record.Success,
+		Error:        record.ErrorMessage,
+	}
+	if json.Valid([]byte(record.DecisionJSON)) {
+		t.Decisions = json.RawMessage(record.DecisionJSON)
+	}
+	return t
+}
+
+// transcriptRedactor hides account identifiers in exported transcripts: the trader's own IDs, wallet addresses and
+// email, plus anything shaped like a wallet address, email or account/order number. Prices and balances stay.
+type transcriptRedactor struct {
+	known *strings.Replacer
+}
+
+// newTranscriptRedactor builds a redactor for the identifiers of a trader, empty values are ignored
+func newTranscriptRedactor(fullConfig *store.TraderFullConfig, user *store.User) *transcriptRedactor {
+	var pairs []string
+	add := func(value, label string) {
+		if len(value) >= 4 {
+			pairs = append(pairs, value, label)
+		}
+	}
+	add(fullConfig.Trader.ID, "[TRADER_ID]")
+	add(fullConfig.Trader.UserID, "[USER_ID]")
+	if user != nil {
+		add(user.Email, "[EMAIL]")
+	}
+	if ex := fullConfig.Exchange; ex != nil {
+		add(ex.ID, "[EXCHANGE_ACCOUNT]")
+		for _, wallet := range []string{ex.HyperliquidWalletAddr, ex.AsterUser, ex.AsterSigner, ex.LighterWalletAddr} {
+			add(wallet, "[WALLET]")
+		}
+	}
+	return &transcriptRedactor{known: strings.NewReplacer(pairs...)}
+}
+
+// text redacts a prompt or response
+func (r *transcriptRedactor) text(s string) string {
+	if s == "" {
+		return s
+	}
+	s = r.known.Replace(s)
+	s = walletAddressPattern.ReplaceAllString(s, "[WALLET]")
+	s = emailPattern.ReplaceAllString(s, "[EMAIL]")
+	return redactLongNumbers(s)
+}
+
+// transcript redacts every free text field and order ID of a transcript line
+func (r *transcriptRedactor) transcript(t *promptTranscript) {
+	t.SystemPrompt = r.text(t.SystemPrompt)
+	t.UserPrompt = r.text(t.UserPrompt)
+	t.RawResponse = r.text(t.RawResponse)
+	t.CoTTrace = r.text(t.CoTTrace)
+	t.Error = r.text(t.Error)
+	if t.Decisions != nil {
+		t.Decisions = json.RawMessage(r.text(string(t.Decisions)))
+	}
+	for i := range t.Actions {
+		a := &t.Actions[i]
+		a.Reasoning = r.text(a.Reasoning)
+		a.Error = r.text(a.Error)
+		a.OrderID = 0
+		a.ExchangeOrderID = ""
+	}
+}
+
+// redactLongNumbers replaces runs of 8+ digits (account, user and order numbers), digits next to a decimal
+// point are amounts or prices and are kept
+func redactLongNumbers(s string) string {
+	matches := longNumberPattern.FindAllStringIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if (start > 0 && s[start-1] == '.') || (end < len(s) && s[end] == '.' && end+1 < len(s) && s[end+1] >= '0' && s[end+1] <= '9') {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString("[NUMBER]")
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// parseTranscriptRange reads the start/end (RFC3339) of an export, defaulting to the last week
+func parseTranscriptRange(startParam, endParam string, now time.Time) (time.Time, time.Time, error) {
+	end := now
+	if endParam != "" {
+		t, err := time.Parse(time.RFC3339, endParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end time, expected RFC3339")
+		}
+		end = t
+	}
+	start := end.Add(-defaultTranscriptRange)
+	if startParam != "" {
+		t, err := time.Parse(time.RFC3339, startParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start time, expected RFC3339")
+		}
+		start = t
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("start must be before end")
+	}
+	if end.Sub(start) > maxTranscriptRange {
+		return time.Time{}, time.Time{}, fmt.Errorf("date range is limited to %d days", int(maxTranscriptRange.Hours()/24))
+	}
+	return start, end, nil
+}
+
+// handleExportTranscripts Download the prompts, raw AI responses and parsed decisions of a trader's cycles as JSONL
+// Query: trader_id, start/end (RFC3339, default: the last 7 days), redact=true hides account identifiers
+func (s *Server) handleExportTranscripts(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Query("trader_id")
+
+	fullConfig, err := s.store.Trader().GetFullConfig(userID, traderID)
+	if err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+	start, end, err := parseTranscriptRange(c.Query("start"), c.Query("end"), time.Now())
+	if err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+
+	var redactor *transcriptRedactor
+	if c.Query("redact") == "true" {
+		user, _ := s.store.User().GetByID(userID)
+		redactor = newTranscriptRedactor(fullConfig, user)
+	}
+
+	filename := fmt.Sprintf("transcripts_%s_%s_%s.jsonl", traderID, start.Format("20060102"), end.Format("20060102"))
+	if redactor != nil {
+		filename = fmt.Sprintf("transcripts_%s_%s.jsonl", start.Format("20060102"), end.Format("20060102"))
+	}
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Status(http.StatusOK)
+
+	enc := json.NewEncoder(c.Writer)
+	count := 0
+	err = s.store.Decision().EachPromptedRecord(fullConfig.Trader.ID, start, end, transcriptBatchSize, func(record *store.DecisionRecord) error {
+		line := newPromptTranscript(record)
+		if redactor != nil {
+			redactor.transcript(line)
+		}
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+		if count++; count%transcriptBatchSize == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// Headers are already sent, the client sees a truncated file
+		logger.Warnf("⚠️ Transcript export of trader %s stopped after %d cycles: %v", traderID, count, err)
+		return
+	}
+	logger.Infof("📤 Exported %d transcripts of trader %s (%s - %s, redacted=%v)", count, traderID, start.Format(time.RFC3339), end.Format(time.RFC3339), redactor != nil)
+}
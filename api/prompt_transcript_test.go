@@ -0,0 +1,91 @@
+package api
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nofx/store"
+)
+
+func TestTranscriptRedactor(t *testing.T) {
+	fullConfig := &store.TraderFullConfig{
+		Trader:   &store.Trader{ID: "binance_u42_deepseek_1700000000", UserID: "u42-user"},
+		Exchange: &store.Exchange{ID: "ex-7f3a", HyperliquidWalletAddr: "0xAbC0000000000000000000000000000000000001"},
+	}
+	r := newTranscriptRedactor(fullConfig, &store.User{Email: "ops@example.com"})
+
+	prompt := "Trader binance_u42_deepseek_1700000000 of u42-user (ops@example.com), account ex-7f3a, " +
+		"wallet 0xabc0000000000000000000000000000000000002, UID 123456789, order 98765432101. " +
+		"Equity 12345678.50 USDT, BTC 64000.12345678, funding 0.00012345"
+	got := r.text(prompt)
+	for _, leaked := range []string{"binance_u42", "u42-user", "ops@example.com", "ex-7f3a", "0xabc", "123456789", "98765432101"} {
+		if strings.Contains(got, leaked) {
+			t.Errorf("%q leaked: %s", leaked, got)
+		}
+	}
+	for _, kept := range []string{"12345678.50", "64000.12345678", "0.00012345", "[TRADER_ID]", "[WALLET]", "[NUMBER]"} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("%q missing: %s", kept, got)
+		}
+	}
+
+	line := newPromptTranscript(&store.DecisionRecord{
+		DecisionJSON: `[{"symbol":"BTCUSDT","action":"hold","reasoning":"ask ops@example.com"}]`,
+		Decisions:    []store.DecisionAction{{Symbol: "BTCUSDT", OrderID: 123, ExchangeOrderID: "abc-1"}},
+	})
+	r.transcript(line)
+	if strings.Contains(string(line.Decisions), "ops@") || line.Actions[0].OrderID != 0 || line.Actions[0].ExchangeOrderID != "" {
+		t.Errorf("decisions %s, actions %+v", line.Decisions, line.Actions)
+	}
+}
+
+func TestParseTranscriptRange(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	start, end, err := parseTranscriptRange("", "", now)
+	if err != nil || !end.Equal(now) || end.Sub(start) != defaultTranscriptRange {
+		t.Errorf("default range %s - %s, %v", start, end, err)
+	}
+	if _, _, err := parseTranscriptRange("2026-02-01T00:00:00Z", "2026-02-15T00:00:00Z", now); err != nil {
+		t.Errorf("two weeks should be accepted: %v", err)
+	}
+	for _, bad := range [][2]string{
+		{"yesterday", ""},
+		{"2026-02-15T00:00:00Z", "2026-02-01T00:00:00Z"},
+		{"2025-01-01T00:00:00Z", "2026-02-01T00:00:00Z"},
+	} {
+		if _, _, err := parseTranscriptRange(bad[0], bad[1], now); err == nil {
+			t.Errorf("%v should be rejected", bad)
+		}
+	}
+}
+
+func TestEachPromptedRecord(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 1; i <= 5; i++ {
+		record := &store.DecisionRecord{TraderID: "t1", CycleNumber: i, Timestamp: base.Add(time.Duration(i) * time.Hour), InputPrompt: "prompt"}
+		if i == 3 {
+			record.InputPrompt = "" // Failed before calling the AI
+		}
+		if err := st.Decision().LogDecision(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	st.Decision().LogDecision(&store.DecisionRecord{TraderID: "t2", CycleNumber: 1, Timestamp: base.Add(time.Hour), InputPrompt: "prompt"})
+
+	var cycles []int
+	err = st.Decision().EachPromptedRecord("t1", base, base.Add(4*time.Hour), 2, func(r *store.DecisionRecord) error {
+		cycles = append(cycles, r.CycleNumber)
+		return nil
+	})
+	if err != nil || len(cycles) != 3 || cycles[0] != 1 || cycles[1] != 2 || cycles[2] != 4 {
+		t.Errorf("cycles %v, %v", cycles, err)
+	}
+}
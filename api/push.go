@@ -0,0 +1,148 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"nofx/logger"
+	"nofx/notify"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxPushSubscriptions browsers a user may subscribe at once
+const maxPushSubscriptions = 10
+
+// SetPushDispatcher enables the browser push endpoints with the server's VAPID public key (nil: disabled)
+func (s *Server) SetPushDispatcher(d *notify.PushDispatcher, publicKey string) {
+	s.pusher = d
+	s.vapidPublicKey = publicKey
+}
+
+// handleGetPushConfig VAPID public key browsers subscribe with (applicationServerKey)
+func (s *Server) handleGetPushConfig(c *gin.Context) {
+	subs, err := s.store.PushSubscription().List(c.GetString("user_id"))
+	if err != nil {
+		SafeInternalError(c, "List push subscriptions", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":       s.pusher != nil,
+		"public_key":    s.vapidPublicKey,
+		"subscriptions": subs,
+	})
+}
+
+// handlePushSubscribe Store a browser subscription (PushSubscription.toJSON())
+func (s *Server) handlePushSubscribe(c *gin.Context) {
+	if s.pusher == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Push notifications are disabled on this server"})
+		return
+	}
+	userID := c.GetString("user_id")
+
+	var req struct {
+		Endpoint string `json:"endpoint" binding:"required"`
+		Keys     struct {
+			P256dh string `json:"p256dh" binding:"required"`
+			Auth   string `json:"auth" binding:"required"`
+		} `json:"keys"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if u, err := url.Parse(req.Endpoint); err != nil || u.Scheme != "https" || u.Host == "" {
+		SafeBadRequest(c, "Invalid push endpoint")
+		return
+	}
+
+	subs, err := s.store.PushSubscription().List(userID)
+	if err != nil {
+		SafeInternalError(c, "List push subscriptions", err)
+		return
+	}
+	known := false
+	for _, sub := range subs {
+		known = known || sub.Endpoint == req.Endpoint
+	}
+	if !known && len(subs) >= maxPushSubscriptions {
+		SafeBadRequest(c, "Too many subscribed browsers, unsubscribe one first")
+		return
+	}
+
+	sub := &store.PushSubscription{
+		UserID:    userID,
+		Endpoint:  req.Endpoint,
+		P256dh:    req.Keys.P256dh,
+		Auth:      req.Keys.Auth,
+		UserAgent: c.Request.UserAgent(),
+	}
+	if err := s.store.PushSubscription().Save(sub); err != nil {
+		SafeInternalError(c, "Save push subscription", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Subscribed to push notifications"})
+}
+
+// handlePushUnsubscribe Remove a browser subscription
+func (s *Server) handlePushUnsubscribe(c *gin.Context) {
+	var req struct {
+		Endpoint string `json:"endpoint" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if err := s.store.PushSubscription().Delete(c.GetString("user_id"), req.Endpoint); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			SafeNotFound(c, "Push subscription")
+			return
+		}
+		SafeInternalError(c, "Delete push subscription", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Unsubscribed from push notifications"})
+}
+
+// handlePushTest Push a test notification to a subscribed browser
+func (s *Server) handlePushTest(c *gin.Context) {
+	if s.pusher == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Push notifications are disabled on this server"})
+		return
+	}
+	userID := c.GetString("user_id")
+
+	var req struct {
+		Endpoint string `json:"endpoint" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	subs, err := s.store.PushSubscription().List(userID)
+	if err != nil {
+		SafeInternalError(c, "List push subscriptions", err)
+		return
+	}
+	for _, sub := range subs {
+		if sub.Endpoint != req.Endpoint {
+			continue
+		}
+		if err := s.pusher.SendTest(sub); err != nil {
+			if errors.Is(err, notify.ErrSubscriptionGone) {
+				_ = s.store.PushSubscription().DeleteByEndpoint(sub.Endpoint)
+				c.JSON(http.StatusGone, gin.H{"error": "Push subscription expired, subscribe again"})
+				return
+			}
+			logger.Warnf("⚠️ Test push failed for user %s: %v", userID, err)
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to send test notification"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Test notification sent"})
+		return
+	}
+	SafeNotFound(c, "Push subscription")
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"net/http"
+	"nofx/auth"
+	"nofx/logger"
+	"nofx/store"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// issueRecoveryCodes generates a fresh set of recovery codes, replacing the previous ones
+// The plaintext codes are only returned here, the store keeps their hashes.
+func (s *Server) issueRecoveryCodes(userID string) ([]string, error) {
+	codes, err := auth.GenerateRecoveryCodes(auth.RecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, auth.HashRecoveryCode(code))
+	}
+	if err := s.store.RecoveryCode().Replace(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// verifySecondFactor checks an OTP code, or consumes a recovery code when no OTP code is sent
+// usedRecovery reports whether a recovery code was spent.
+func (s *Server) verifySecondFactor(user *store.User, otpCode, recoveryCode string) (ok bool, usedRecovery bool) {
+	if otpCode != "" {
+		return auth.VerifyOTP(user.OTPSecret, otpCode), false
+	}
+	if strings.TrimSpace(recoveryCode) == "" {
+		return false, false
+	}
+	consumed, err := s.store.RecoveryCode().Consume(user.ID, auth.HashRecoveryCode(recoveryCode))
+	if err != nil {
+		logger.Warnf("⚠️ Failed to check recovery code for user %s: %v", user.ID, err)
+		return false, false
+	}
+	if consumed {
+		logger.Infof("🔑 User %s used a recovery code", user.Email)
+	}
+	return consumed, consumed
+}
+
+// handleGetRecoveryCodes Number of unused recovery codes of the current user
+func (s *Server) handleGetRecoveryCodes(c *gin.Context) {
+	remaining, err := s.store.RecoveryCode().CountUnused(c.GetString("user_id"))
+	if err != nil {
+		SafeInternalError(c, "Count recovery codes", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"remaining": remaining})
+}
+
+// handleRegenerateRecoveryCodes Replace the current user's recovery codes (requires a current OTP code)
+func (s *Server) handleRegenerateRecoveryCodes(c *gin.Context) {
+	var req struct {
+		OTPCode string `json:"otp_code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	user, err := s.store.User().GetByID(c.GetString("user_id"))
+	if err != nil {
+		SafeNotFound(c, "User")
+		return
+	}
+	if !auth.VerifyOTP(user.OTPSecret, req.OTPCode) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Google Authenticator code error"})
+		return
+	}
+
+	codes, err := s.issueRecoveryCodes(user.ID)
+	if err != nil {
+		SafeInternalError(c, "Generate recovery codes", err)
+		return
+	}
+
+	logger.Infof("✓ User %s regenerated recovery codes", user.Email)
+	c.JSON(http.StatusOK, gin.H{
+		"recovery_codes": codes,
+		"message":        "Store these codes somewhere safe, previous codes no longer work",
+	})
+}
+
+// handleAdminResetOTP Reset a user's authenticator (admin-assisted recovery)
+// The user keeps their password and sets up OTP again at next login; recovery codes are discarded.
+func (s *Server) handleAdminResetOTP(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	user, err := s.store.User().GetByEmail(req.Email)
+	if err != nil {
+		SafeNotFound(c, "User")
+		return
+	}
+
+	otpSecret, err := auth.GenerateOTPSecret()
+	if err != nil {
+		SafeInternalError(c, "Generate OTP secret", err)
+		return
+	}
+	if err := s.store.User().ResetOTP(user.ID, otpSecret); err != nil {
+		SafeInternalError(c, "Reset OTP", err)
+		return
+	}
+	if err := s.store.RecoveryCode().DeleteByUser(user.ID); err != nil {
+		logger.Warnf("⚠️ Failed to delete recovery codes of user %s: %v", user.Email, err)
+	}
+
+	logger.Infof("✓ OTP of user %s reset by admin %s", user.Email, c.GetString("email"))
+	c.JSON(http.StatusOK, gin.H{"message": "OTP reset, the user must set up Google Authenticator again at next login"})
+}
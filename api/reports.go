@@ -0,0 +1,162 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"nofx/report"
+	"nofx/store"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxListedReports reports returned by the report list
+const maxListedReports = 100
+
+// SetReportGenerator enables generating performance reports on demand
+func (s *Server) SetReportGenerator(g *report.Generator) {
+	s.reports = g
+}
+
+// handleListReports List the user's generated performance reports, newest first
+func (s *Server) handleListReports(c *gin.Context) {
+	reports, err := s.store.Report().List(c.GetString("user_id"), maxListedReports)
+	if err != nil {
+		SafeInternalError(c, "List reports", err)
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+// handleGetReportSettings Get the user's scheduled performance reports
+func (s *Server) handleGetReportSettings(c *gin.Context) {
+	settings, err := s.store.Report().GetSettings(c.GetString("user_id"))
+	if err != nil {
+		SafeInternalError(c, "Get report settings", err)
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// handleUpdateReportSettings Schedule weekly and/or monthly reports and whether they are emailed
+func (s *Server) handleUpdateReportSettings(c *gin.Context) {
+	var req struct {
+		Weekly  bool `json:"weekly"`
+		Monthly bool `json:"monthly"`
+		Email   bool `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	settings := &store.ReportSettings{UserID: c.GetString("user_id"), Weekly: req.Weekly, Monthly: req.Monthly, Email: req.Email}
+	if err := s.store.Report().UpdateSettings(settings); err != nil {
+		SafeInternalError(c, "Update report settings", err)
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// handleGenerateReport Generate a report now, of the last complete period or of the period containing date
+func (s *Server) handleGenerateReport(c *gin.Context) {
+	if s.reports == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Performance reports are not available"})
+		return
+	}
+	userID := c.GetString("user_id")
+
+	var req struct {
+		Period   string `json:"period" binding:"required"`
+		TraderID string `json:"trader_id"` // Empty = all traders
+		Date     string `json:"date"`      // YYYY-MM-DD, empty = last complete period
+		Email    bool   `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if req.TraderID != "" {
+		if _, err := s.store.Trader().Get(userID, req.TraderID); err != nil {
+			SafeNotFound(c, "Trader")
+			return
+		}
+	}
+
+	loc := time.UTC
+	if user, err := s.store.User().GetByID(userID); err == nil {
+		loc = user.Location()
+	}
+	var start, end time.Time
+	var err error
+	if req.Date != "" {
+		day, parseErr := time.ParseInLocation(store.DailyStatsDateLayout, req.Date, loc)
+		if parseErr != nil {
+			SafeBadRequest(c, "Invalid date, expected YYYY-MM-DD")
+			return
+		}
+		start, end, err = report.PeriodAt(req.Period, day, loc)
+	} else {
+		start, end, err = report.LastPeriod(req.Period, time.Now(), loc)
+	}
+	if err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+
+	generated, err := s.reports.Generate(userID, req.TraderID, req.Period, start, end)
+	if err != nil {
+		SafeInternalError(c, "Generate report", err)
+		return
+	}
+	if req.Email {
+		if err := s.reports.Email(generated); err != nil {
+			c.JSON(http.StatusOK, gin.H{"report": generated, "email_error": err.Error()})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"report": generated})
+}
+
+// handleGetReport Get a report's content, HTML by default or its PDF with ?format=pdf
+func (s *Server) handleGetReport(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SafeBadRequest(c, "Invalid report ID")
+		return
+	}
+	rep, err := s.store.Report().Get(c.GetString("user_id"), id)
+	if err != nil {
+		SafeNotFound(c, "Report")
+		return
+	}
+
+	if c.Query("format") == "pdf" {
+		filename := fmt.Sprintf("nofx-%s-report-%s.pdf", rep.Period, rep.StartDate)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		c.Data(http.StatusOK, "application/pdf", rep.PDF)
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(rep.HTML))
+}
+
+// handleDeleteReport Delete a report
+func (s *Server) handleDeleteReport(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SafeBadRequest(c, "Invalid report ID")
+		return
+	}
+	if err := s.store.Report().Delete(c.GetString("user_id"), id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			SafeNotFound(c, "Report")
+			return
+		}
+		SafeInternalError(c, "Delete report", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Report deleted"})
+}
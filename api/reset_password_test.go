@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"nofx/auth"
+	"nofx/store"
+
+	"github.com/pquerna/otp/totp"
+)
+
+func newResetPasswordServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	s, _ := newTestServer(t)
+
+	secret, err := auth.GenerateOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, _ := auth.HashPassword("old-password")
+	if err := s.store.User().Create(&store.User{ID: "u1", Email: "alice@example.com", PasswordHash: hash, OTPSecret: secret, OTPVerified: true}); err != nil {
+		t.Fatal(err)
+	}
+	return s, secret
+}
+
+func resetPassword(s *Server, ip, email, code string) *httptest.ResponseRecorder {
+	body := `{"email":"` + email + `","new_password":"new-password","otp_code":"` + code + `"}`
+	return callHandler(s.handleResetPassword, testRequest{method: http.MethodPost, target: "/api/reset-password", body: body, remoteAddr: ip + ":12345"})
+}
+
+func TestResetPasswordUnknownEmailLooksLikeWrongCode(t *testing.T) {
+	s, _ := newResetPasswordServer(t)
+
+	unknown := resetPassword(s, "10.0.0.1", "nobody@example.com", "000000")
+	wrongCode := resetPassword(s, "10.0.0.2", "alice@example.com", "000000")
+	if unknown.Code != wrongCode.Code || unknown.Body.String() != wrongCode.Body.String() {
+		t.Errorf("unknown email %d %s, wrong code %d %s: responses must match",
+			unknown.Code, unknown.Body, wrongCode.Code, wrongCode.Body)
+	}
+}
+
+func TestResetPasswordLocksOutEmailAfterFailures(t *testing.T) {
+	s, secret := newResetPasswordServer(t)
+
+	// Spread over several addresses so only the per-email limit applies
+	for i := 0; i < maxFailedAttempts; i++ {
+		ip := "10.0.1." + string(rune('1'+i))
+		if w := resetPassword(s, ip, "alice@example.com", "000000"); w.Code != http.StatusBadRequest {
+			t.Fatalf("attempt %d: expected 400, got %d", i+1, w.Code)
+		}
+	}
+
+	code, _ := totp.GenerateCode(secret, time.Now())
+	if w := resetPassword(s, "10.0.2.1", "ALICE@example.com", code); w.Code != http.StatusTooManyRequests {
+		t.Errorf("locked email with a valid code: expected 429, got %d", w.Code)
+	}
+
+	// Once the lockout has passed the right code works again
+	s.resetAttempts.now = func() time.Time { return time.Now().Add(attemptLockout + time.Minute) }
+	if w := resetPassword(s, "10.0.2.1", "alice@example.com", code); w.Code != http.StatusOK {
+		t.Errorf("after lockout: expected 200, got %d %s", w.Code, w.Body)
+	}
+}
+
+func TestResetPasswordLocksOutClientIPAfterFailures(t *testing.T) {
+	s, secret := newResetPasswordServer(t)
+
+	// Different (unknown) emails from one address count against that address
+	for i := 0; i < maxFailedAttempts; i++ {
+		email := "probe" + string(rune('a'+i)) + "@example.com"
+		resetPassword(s, "10.0.3.1", email, "000000")
+	}
+
+	code, _ := totp.GenerateCode(secret, time.Now())
+	if w := resetPassword(s, "10.0.3.1", "alice@example.com", code); w.Code != http.StatusTooManyRequests {
+		t.Errorf("locked IP: expected 429, got %d", w.Code)
+	}
+	if w := resetPassword(s, "10.0.3.2", "alice@example.com", code); w.Code != http.StatusOK {
+		t.Errorf("other IP: expected 200, got %d %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"nofx/config"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authCookieName is the cookie carrying the JWT in cookie auth mode
+const authCookieName = "nofx_token"
+
+// authCookieMaxAge matches the JWT lifetime
+const authCookieMaxAge = 24 * 60 * 60
+
+// corsMiddleware CORS middleware
+// Without CORS_ALLOWED_ORIGINS any origin may call the API (no credentials); otherwise only the
+// listed origins are answered and preflights from other origins are rejected.
+func corsMiddleware(cfg *config.Config) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(cfg.CORSAllowedOrigins))
+	for _, origin := range cfg.CORSAllowedOrigins {
+		allowed[strings.ToLower(origin)] = true
+	}
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		origin := c.GetHeader("Origin")
+
+		if len(allowed) == 0 {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Add("Vary", "Origin")
+			if origin != "" && allowed[strings.ToLower(origin)] {
+				header.Set("Access-Control-Allow-Origin", origin)
+				if cfg.CORSAllowCredentials {
+					header.Set("Access-Control-Allow-Credentials", "true")
+				}
+			} else if origin != "" && c.Request.Method == "OPTIONS" {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+		}
+		header.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		header.Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+
+		if c.Request.Method == "OPTIONS" {
+			c.AbortWithStatus(http.StatusOK)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// securityHeadersMiddleware sets strict browser security headers (SECURITY_HEADERS)
+func securityHeadersMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.SecurityHeaders {
+			header := c.Writer.Header()
+			header.Set("X-Frame-Options", "DENY")
+			header.Set("X-Content-Type-Options", "nosniff")
+			header.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+			// Browsers ignore HSTS over plain HTTP, only send it where it takes effect
+			if cfg.HSTSMaxAgeSeconds > 0 && isHTTPS(c) {
+				header.Set("Strict-Transport-Security", "max-age="+strconv.Itoa(cfg.HSTSMaxAgeSeconds)+"; includeSubDomains")
+			}
+		}
+		c.Next()
+	}
+}
+
+// isHTTPS reports whether the request reached us (or the reverse proxy in front of us) over TLS
+func isHTTPS(c *gin.Context) bool {
+	return c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https")
+}
+
+// authorizationHeader returns the Authorization header, or the auth cookie as a bearer token
+// when cookie auth mode is enabled and no header is sent
+func authorizationHeader(c *gin.Context) string {
+	if header := c.GetHeader("Authorization"); header != "" || !config.Get().AuthCookie {
+		return header
+	}
+	if token, err := c.Cookie(authCookieName); err == nil && token != "" {
+		return "Bearer " + token
+	}
+	return ""
+}
+
+// setAuthCookie issues the JWT as an HttpOnly SameSite=Strict cookie in cookie auth mode
+func setAuthCookie(c *gin.Context, token string) {
+	if !config.Get().AuthCookie {
+		return
+	}
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(authCookieName, token, authCookieMaxAge, "/api", "", isHTTPS(c), true)
+}
+
+// clearAuthCookie removes the auth cookie on logout
+func clearAuthCookie(c *gin.Context) {
+	if !config.Get().AuthCookie {
+		return
+	}
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(authCookieName, "", -1, "/api", "", isHTTPS(c), true)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"nofx/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newSecurityTestRouter(cfg *config.Config) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(corsMiddleware(cfg), securityHeadersMiddleware(cfg))
+	router.GET("/api/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+	return router
+}
+
+func serveSecurityTest(router *gin.Engine, method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/ping", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSMiddleware_DefaultAllowsAnyOrigin(t *testing.T) {
+	router := newSecurityTestRouter(&config.Config{})
+
+	w := serveSecurityTest(router, http.MethodGet, "https://evil.example", nil)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("expected wildcard origin without configuration, got %q", got)
+	}
+	if w.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Error("credentials must never be allowed with a wildcard origin")
+	}
+}
+
+func TestCORSMiddleware_AllowedOrigins(t *testing.T) {
+	router := newSecurityTestRouter(&config.Config{
+		CORSAllowedOrigins:   []string{"https://app.example"},
+		CORSAllowCredentials: true,
+	})
+
+	w := serveSecurityTest(router, http.MethodGet, "https://APP.example", nil)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://APP.example" {
+		t.Errorf("allowed origin should be echoed, got %q", got)
+	}
+	if w.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Error("credentials should be allowed for a listed origin")
+	}
+
+	w = serveSecurityTest(router, http.MethodGet, "https://evil.example", nil)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("unlisted origin must not be allowed, got %q", got)
+	}
+	if w := serveSecurityTest(router, http.MethodOptions, "https://evil.example", nil); w.Code != http.StatusForbidden {
+		t.Errorf("preflight from unlisted origin should be rejected, got %d", w.Code)
+	}
+	if w := serveSecurityTest(router, http.MethodOptions, "https://app.example", nil); w.Code != http.StatusOK {
+		t.Errorf("preflight from listed origin should pass, got %d", w.Code)
+	}
+}
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	router := newSecurityTestRouter(&config.Config{SecurityHeaders: true, HSTSMaxAgeSeconds: 600})
+
+	w := serveSecurityTest(router, http.MethodGet, "", nil)
+	if w.Header().Get("X-Frame-Options") != "DENY" || w.Header().Get("X-Content-Type-Options") != "nosniff" {
+		t.Errorf("missing security headers: %v", w.Header())
+	}
+	if w.Header().Get("Strict-Transport-Security") != "" {
+		t.Error("HSTS must not be sent over plain HTTP")
+	}
+
+	w = serveSecurityTest(router, http.MethodGet, "", map[string]string{"X-Forwarded-Proto": "https"})
+	if got := w.Header().Get("Strict-Transport-Security"); got != "max-age=600; includeSubDomains" {
+		t.Errorf("unexpected HSTS header behind TLS proxy: %q", got)
+	}
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+	"nofx/provider/sentiment"
+	"nofx/store"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSentimentPoints readings returned per request
+const maxSentimentPoints = 5000
+
+// handleSentiment sentiment history for charting, plus the latest cached readings
+// Query: metric (fear_greed, social; default fear_greed), symbol (required for social), days (default 30, max 365)
+func (s *Server) handleSentiment(c *gin.Context) {
+	metric := c.DefaultQuery("metric", store.SentimentFearGreed)
+	if metric != store.SentimentFearGreed && metric != store.SentimentSocial {
+		SafeBadRequest(c, "metric must be fear_greed or social")
+		return
+	}
+
+	symbol := ""
+	if metric == store.SentimentSocial {
+		symbol = sentiment.BaseAsset(c.Query("symbol"))
+		if symbol == "" {
+			SafeBadRequest(c, "symbol is required for social sentiment")
+			return
+		}
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days <= 0 {
+		days = 30
+	}
+	if days > 365 {
+		days = 365
+	}
+
+	points, err := s.store.Sentiment().List(metric, symbol, time.Now().AddDate(0, 0, -days), maxSentimentPoints)
+	if err != nil {
+		SafeInternalError(c, "Get sentiment history", err)
+		return
+	}
+	symbols, err := s.store.Sentiment().Symbols(store.SentimentSocial)
+	if err != nil {
+		SafeInternalError(c, "Get sentiment symbols", err)
+		return
+	}
+	if points == nil {
+		points = []*store.SentimentPoint{}
+	}
+	if symbols == nil {
+		symbols = []string{}
+	}
+
+	resp := gin.H{
+		"enabled":        false,
+		"metric":         metric,
+		"symbol":         symbol,
+		"points":         points,
+		"social_symbols": symbols,
+	}
+	if service := s.traderManager.SentimentService(); service != nil {
+		resp["enabled"] = true
+		resp["latest"] = service.Snapshot()
+	}
+	c.JSON(http.StatusOK, resp)
+}
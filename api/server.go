@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -13,11 +14,13 @@ import (
 	"nofx/logger"
 	"nofx/manager"
 	"nofx/market"
+	"nofx/notify"
 	"nofx/provider/alpaca"
 	"nofx/provider/coinank/coinank_api"
 	"nofx/provider/coinank/coinank_enum"
 	"nofx/provider/hyperliquid"
 	"nofx/provider/twelvedata"
+	"nofx/report"
 	"nofx/store"
 	"nofx/trader"
 	"nofx/trader/aster"
@@ -35,6 +38,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // Server HTTP API server
@@ -45,6 +49,16 @@ type Server struct {
 	cryptoHandler   *CryptoHandler
 	backtestManager *backtest.Manager
 	debateHandler   *DebateHandler
+	exchangeHealth  *exchangeHealthChecker
+	ipAllowlist     ipAllowlist
+	resetAttempts   attemptLimiter
+	shareAttempts   attemptLimiter
+	settings        systemSettings
+	notifier        *notify.Notifier
+	pusher          *notify.PushDispatcher
+	reports         *report.Generator
+	webhooks        *notify.WebhookDispatcher
+	vapidPublicKey  string
 	httpServer      *http.Server
 	port            int
 }
@@ -56,11 +70,18 @@ func NewServer(traderManager *manager.TraderManager, st *store.Store, cryptoServ
 
 	router := gin.Default()
 
-	// Enable CORS
-	router.Use(corsMiddleware())
+	// Enable CORS and security headers
+	router.Use(corsMiddleware(config.Get()), securityHeadersMiddleware(config.Get()))
+
+	// Only honor X-Forwarded-For from known proxies, gin trusts every proxy by default
+	if err := router.SetTrustedProxies(config.Get().TrustedProxies); err != nil {
+		logger.Errorf("Invalid TRUSTED_PROXIES, forwarded client IPs are ignored: %v", err)
+		_ = router.SetTrustedProxies(nil)
+	}
 
 	// Create crypto handler
 	cryptoHandler := NewCryptoHandler(cryptoService)
+	cryptoHandler.SetKeyRotator(crypto.NewKeyRotator(cryptoService, st.SecretRotation()))
 
 	// Create debate store and handler
 	debateStore := store.NewDebateStore(st.GormDB())
@@ -77,8 +98,11 @@ func NewServer(traderManager *manager.TraderManager, st *store.Store, cryptoServ
 		cryptoHandler:   cryptoHandler,
 		backtestManager: backtestManager,
 		debateHandler:   debateHandler,
+		exchangeHealth:  newExchangeHealthChecker(st),
 		port:            port,
 	}
+	s.loadIPAllowlist()
+	s.loadSystemSettings()
 
 	// Setup routes
 	s.setupRoutes()
@@ -86,30 +110,22 @@ func NewServer(traderManager *manager.TraderManager, st *store.Store, cryptoServ
 	return s
 }
 
-// corsMiddleware CORS middleware
-func corsMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusOK)
-			return
-		}
-
-		c.Next()
-	}
-}
-
 // setupRoutes Setup routes
 func (s *Server) setupRoutes() {
+	// Liveness and readiness probes for orchestrators (outside /api, no authentication)
+	s.router.Any("/healthz", s.handleLiveness)
+	s.router.Any("/readyz", s.handleReadiness)
+
 	// API route group
 	api := s.router.Group("/api")
 	{
 		// Health check
 		api.Any("/health", s.handleHealth)
 
+		// OpenAPI specification and its Swagger UI (no authentication required)
+		api.GET("/docs", s.handleAPIDocs)
+		api.GET("/docs/openapi.json", s.handleOpenAPISpec)
+
 		// Admin login (used in admin mode, public)
 
 		// System supported models and exchanges (no authentication required)
@@ -125,16 +141,26 @@ func (s *Server) setupRoutes() {
 		api.POST("/crypto/decrypt", s.cryptoHandler.HandleDecryptSensitiveData)
 
 		// Public competition data (no authentication required)
-		api.GET("/traders", s.handlePublicTraderList)
-		api.GET("/competition", s.handlePublicCompetition)
-		api.GET("/top-traders", s.handleTopTraders)
-		api.GET("/equity-history", s.handleEquityHistory)
-		api.POST("/equity-history-batch", s.handleEquityHistoryBatch)
-		api.GET("/traders/:id/public-config", s.handleGetPublicTraderConfig)
+		// Only traders whose owners opted in are listed; a token lets owners see their own private traders
+		public := api.Group("/", s.optionalAuthMiddleware())
+		public.GET("/traders", s.handlePublicTraderList)
+		public.GET("/competition", s.handlePublicCompetition)
+		public.GET("/top-traders", s.handleTopTraders)
+		public.GET("/equity-history", s.handleEquityHistory)
+		public.POST("/equity-history-batch", s.handleEquityHistoryBatch)
+		public.GET("/traders/:id/public-config", s.handleGetPublicTraderConfig)
+
+		// Read-only dashboards shared through revocable signed links (no authentication required)
+		shared := api.Group("/shared/:token", s.shareLinkMiddleware())
+		shared.GET("/status", s.handleSharedStatus)
+		shared.GET("/decisions", s.handleSharedDecisions)
+		shared.GET("/equity-history", s.handleSharedEquityHistory)
 
 		// Market data (no authentication required)
 		api.GET("/klines", s.handleKlines)
 		api.GET("/symbols", s.handleSymbols)
+		api.GET("/market/funding-history", s.handleFundingHistory)
+		api.GET("/market/oi-history", s.handleOIHistory)
 
 		// Public strategy market (no authentication required)
 		api.GET("/strategies/public", s.handlePublicStrategies)
@@ -144,9 +170,10 @@ func (s *Server) setupRoutes() {
 		api.POST("/login", s.handleLogin)
 		api.POST("/verify-otp", s.handleVerifyOTP)
 		api.POST("/complete-registration", s.handleCompleteRegistration)
+		api.POST("/reset-password", s.handleResetPassword)
 
 		// Routes requiring authentication
-		protected := api.Group("/", s.authMiddleware())
+		protected := api.Group("/", s.ipAllowlistMiddleware(false), s.authMiddleware(), s.auditMiddleware())
 		{
 			// Logout (add to blacklist)
 			protected.POST("/logout", s.handleLogout)
@@ -154,19 +181,112 @@ func (s *Server) setupRoutes() {
 			// Server IP query (requires authentication, for whitelist configuration)
 			protected.GET("/server-ip", s.handleGetServerIP)
 
+			// Leaderboard privacy (opt-in, name anonymization)
+			protected.GET("/privacy", s.handleGetPrivacySettings)
+			protected.PUT("/privacy", s.handleUpdatePrivacySettings)
+
+			// Data portability: passphrase-encrypted export/import of the user's whole configuration
+			protected.POST("/user/export", s.handleExportUserData)
+			protected.POST("/user/import", s.handleImportUserData)
+
+			// Timezone of daily statistics
+			protected.GET("/timezone", s.handleGetTimezone)
+			protected.PUT("/timezone", s.handleUpdateTimezone)
+
+			// Email notifications (critical trader events, daily digest)
+			protected.GET("/notifications/settings", s.handleGetNotificationSettings)
+			protected.PUT("/notifications/settings", s.handleUpdateNotificationSettings)
+			protected.POST("/notifications/email", s.handleSetNotificationEmail)
+			protected.POST("/notifications/email/verify", s.handleVerifyNotificationEmail)
+			protected.POST("/notifications/test", s.handleTestNotification)
+
+			// Browser push notifications (Web Push)
+			protected.GET("/push/config", s.handleGetPushConfig)
+			protected.POST("/push/subscriptions", s.handlePushSubscribe)
+			protected.POST("/push/unsubscribe", s.handlePushUnsubscribe)
+			protected.POST("/push/test", s.handlePushTest)
+
+			// Outbound webhooks (HMAC-signed, templated JSON) for trader events
+			protected.GET("/webhooks", s.handleListWebhooks)
+			protected.POST("/webhooks", s.handleCreateWebhook)
+			protected.PUT("/webhooks/:id", s.handleUpdateWebhook)
+			protected.DELETE("/webhooks/:id", s.handleDeleteWebhook)
+			protected.POST("/webhooks/:id/test", s.handleTestWebhook)
+
+			// Weekly/monthly performance reports (HTML/PDF)
+			protected.GET("/reports", s.handleListReports)
+			protected.POST("/reports", s.handleGenerateReport)
+			protected.GET("/reports/settings", s.handleGetReportSettings)
+			protected.PUT("/reports/settings", s.handleUpdateReportSettings)
+			protected.GET("/reports/:id", s.handleGetReport)
+			protected.DELETE("/reports/:id", s.handleDeleteReport)
+
+			// OTP recovery codes
+			protected.GET("/recovery-codes", s.handleGetRecoveryCodes)
+			protected.POST("/recovery-codes", s.handleRegenerateRecoveryCodes)
+
 			// AI trader management
 			protected.GET("/my-traders", s.handleTraderList)
+			protected.GET("/portfolio", s.handlePortfolio)
 			protected.GET("/traders/:id/config", s.handleGetTraderConfig)
 			protected.POST("/traders", s.handleCreateTrader)
 			protected.PUT("/traders/:id", s.handleUpdateTrader)
 			protected.DELETE("/traders/:id", s.handleDeleteTrader)
+			protected.GET("/traders/trash", s.handleListTrash)
+			protected.POST("/traders/:id/restore", s.handleRestoreTrader)
+			protected.DELETE("/traders/trash/:id", s.handlePurgeTrader)
 			protected.POST("/traders/:id/start", s.handleStartTrader)
 			protected.POST("/traders/:id/stop", s.handleStopTrader)
 			protected.PUT("/traders/:id/prompt", s.handleUpdateTraderPrompt)
 			protected.POST("/traders/:id/sync-balance", s.handleSyncBalance)
+			protected.GET("/traders/:id/events", s.handleTraderEvents)
 			protected.POST("/traders/:id/close-position", s.handleClosePosition)
 			protected.PUT("/traders/:id/competition", s.handleToggleCompetition)
+			protected.PUT("/traders/:id/paper-mode", s.handleTogglePaperMode)
+			protected.GET("/traders/:id/paper-positions", s.handleGetPaperPositions)
+			protected.DELETE("/traders/:id/paper-positions", s.handleResetPaperPositions)
+			protected.POST("/traders/:id/duplicate", s.handleDuplicateTrader)
+			protected.POST("/traders/:id/migrate", s.handleMigrateTrader)
+			protected.GET("/traders/:id/circuit-breaker", s.handleGetCircuitBreaker)
+			protected.GET("/traders/:id/limit-entries", s.handleGetLimitEntries)
+			protected.GET("/traders/:id/copy", s.handleGetCopyTrade)
+			protected.PUT("/traders/:id/copy", s.handleUpdateCopyTrade)
+			protected.DELETE("/traders/:id/copy", s.handleDeleteCopyTrade)
+			protected.GET("/traders/:id/share-links", s.handleListShareLinks)
+			protected.POST("/traders/:id/share-links", s.handleCreateShareLink)
+			protected.DELETE("/traders/:id/share-links/:linkId", s.handleRevokeShareLink)
+			protected.GET("/traders/:id/decision-rules", s.handleGetDecisionRules)
+			protected.GET("/traders/:id/cycle-jobs", s.handleGetCycleJobs)
+			protected.GET("/traders/:id/logs", s.handleGetTraderLogs)
+			protected.GET("/traders/:id/chart", s.handleTraderChart)
+			protected.PUT("/traders/:id/decision-rules", s.handleUpdateDecisionRules)
+			protected.GET("/traders/:id/approval-mode", s.handleGetApprovalMode)
+			protected.PUT("/traders/:id/approval-mode", s.handleUpdateApprovalMode)
+			protected.GET("/traders/:id/pending-decisions", s.handleGetPendingDecisions)
+			protected.POST("/traders/:id/pending-decisions/:decisionId/approve", s.handleApprovePendingDecision)
+			protected.POST("/traders/:id/pending-decisions/:decisionId/reject", s.handleRejectPendingDecision)
+			protected.GET("/traders/:id/export", s.handleExportTrader)
+			protected.POST("/traders/import", s.handleImportTrader)
 			protected.GET("/traders/:id/grid-risk", s.handleGetGridRiskInfo)
+			protected.PUT("/traders/:id/grid-config", s.handleUpdateGridConfig)
+			protected.GET("/traders/:id/coin-pool", s.handleGetTraderCoinPool)
+			protected.POST("/traders/:id/coin-pool/overrides", s.handleSetTraderCoinOverride)
+			protected.DELETE("/traders/:id/coin-pool/overrides/:symbol", s.handleDeleteTraderCoinOverride)
+			protected.GET("/traders/:id/experiments", s.handleListExperiments)
+			protected.POST("/traders/:id/experiments", s.handleCreateExperiment)
+
+			// Strategy A/B experiments
+			protected.GET("/experiments/:id", s.handleCompareExperiment)
+			protected.POST("/experiments/:id/stop", s.handleStopExperiment)
+			protected.DELETE("/experiments/:id", s.handleDeleteExperiment)
+
+			// Coin pools (AI500 / OI Top view, custom pools)
+			protected.GET("/coin-pools/ai500", s.handleGetAI500Pool)
+			protected.GET("/coin-pools/oi-top", s.handleGetOITopPool)
+			protected.GET("/coin-pools", s.handleListCoinPools)
+			protected.POST("/coin-pools", s.handleCreateCoinPool)
+			protected.PUT("/coin-pools/:id", s.handleUpdateCoinPool)
+			protected.DELETE("/coin-pools/:id", s.handleDeleteCoinPool)
 
 			// AI model configuration
 			protected.GET("/models", s.handleGetModelConfigs)
@@ -174,22 +294,40 @@ func (s *Server) setupRoutes() {
 
 			// Exchange configuration
 			protected.GET("/exchanges", s.handleGetExchangeConfigs)
+			protected.GET("/exchanges/health", s.handleExchangeHealth)
+			protected.GET("/exchanges/:id/secrets/preview", s.handleExchangeSecretPreview)
 			protected.POST("/exchanges", s.handleCreateExchange)
 			protected.PUT("/exchanges", s.handleUpdateExchangeConfigs)
 			protected.DELETE("/exchanges/:id", s.handleDeleteExchange)
+			protected.POST("/exchanges/:id/verify", s.handleVerifyExchange)
+			protected.POST("/exchanges/:id/rotate-keys", s.handleRotateExchangeKeys)
 
 			// Strategy management
 			protected.GET("/strategies", s.handleGetStrategies)
 			protected.GET("/strategies/active", s.handleGetActiveStrategy)
 			protected.GET("/strategies/default-config", s.handleGetDefaultStrategyConfig)
+			protected.GET("/strategies/imports", s.handleStrategyImports)
+			protected.GET("/strategies/bundle-signer", s.handleStrategyBundleSigner)
+			protected.POST("/strategies/import", s.handleImportStrategyBundle)
 			protected.POST("/strategies/preview-prompt", s.handlePreviewPrompt)
 			protected.POST("/strategies/test-run", s.handleStrategyTestRun)
+			protected.POST("/strategies/test-run/stream", s.handleStrategyTestRunStream)
+			protected.POST("/strategies/grid/suggest", s.handleSuggestGridConfig)
 			protected.GET("/strategies/:id", s.handleGetStrategy)
 			protected.POST("/strategies", s.handleCreateStrategy)
 			protected.PUT("/strategies/:id", s.handleUpdateStrategy)
 			protected.DELETE("/strategies/:id", s.handleDeleteStrategy)
 			protected.POST("/strategies/:id/activate", s.handleActivateStrategy)
 			protected.POST("/strategies/:id/duplicate", s.handleDuplicateStrategy)
+			protected.GET("/strategies/:id/export", s.handleExportStrategyBundle)
+
+			// Prompt templates (versioned, selectable in strategies and traders)
+			protected.GET("/prompt-templates", s.handleListPromptTemplates)
+			protected.POST("/prompt-templates", s.handleCreatePromptTemplate)
+			protected.GET("/prompt-templates/:id", s.handleGetPromptTemplate)
+			protected.PUT("/prompt-templates/:id", s.handleUpdatePromptTemplate)
+			protected.DELETE("/prompt-templates/:id", s.handleDeletePromptTemplate)
+			protected.GET("/prompt-templates/:id/versions", s.handlePromptTemplateVersions)
 
 			// Debate Arena
 			protected.GET("/debates", s.debateHandler.HandleListDebates)
@@ -215,11 +353,40 @@ func (s *Server) setupRoutes() {
 			protected.GET("/open-orders", s.handleOpenOrders)      // Open orders from exchange (pending SL/TP)
 			protected.GET("/decisions", s.handleDecisions)
 			protected.GET("/decisions/latest", s.handleLatestDecisions)
+			protected.GET("/decisions/diff", s.handleDecisionDiff)
+			protected.GET("/decisions/transcripts", s.handleExportTranscripts) // Prompts and AI responses as JSONL
+			protected.GET("/decisions/:id/trace", s.handleDecisionTrace)
 			protected.GET("/statistics", s.handleStatistics)
+			protected.GET("/statistics/daily", s.handleDailyStatistics) // Daily rollup for calendar heatmaps
+			protected.GET("/symbols/restrictions", s.handleSymbolRestrictions) // Delisting/non-trading symbols and maintenance windows
+			protected.GET("/sentiment", s.handleSentiment)                     // Fear & Greed / social sentiment history
+			protected.GET("/market/providers", s.handleMarketDataProviders)    // Kline provider health and failover order
 
 			// Backtest routes
 			backtest := protected.Group("/backtest")
 			s.registerBacktestRoutes(backtest)
+
+			// Operator routes (restricted to ADMIN_EMAILS)
+			admin := protected.Group("/admin", s.ipAllowlistMiddleware(true), s.adminMiddleware())
+			{
+				admin.POST("/crypto/rotate", s.cryptoHandler.HandleStartKeyRotation)
+				admin.GET("/crypto/rotate/status", s.cryptoHandler.HandleGetKeyRotationStatus)
+				admin.POST("/crypto/verify", s.cryptoHandler.HandleVerifyKeys)
+				admin.GET("/audit", s.handleAuditLogs)
+				admin.GET("/maintenance-windows", s.handleGetMaintenanceWindows)
+				admin.PUT("/maintenance-windows", s.handleUpdateMaintenanceWindows)
+				admin.POST("/users/otp-reset", s.handleAdminResetOTP)
+				admin.GET("/ai-throttle", s.handleAIThrottle)
+				admin.GET("/exchange-endpoints", s.handleExchangeEndpoints)
+				admin.GET("/equity-writer", s.handleEquityWriter)
+				admin.GET("/ip-allowlist", s.handleGetIPAllowlist)
+				admin.PUT("/ip-allowlist", s.handleUpdateIPAllowlist)
+				admin.GET("/settings", s.handleGetSystemSettings)
+				admin.PUT("/settings", s.handleUpdateSystemSettings)
+				admin.GET("/kill-switch", s.handleGetKillSwitch)
+				admin.POST("/kill-switch", s.handleEngageKillSwitch)
+				admin.DELETE("/kill-switch", s.handleReleaseKillSwitch)
+			}
 		}
 	}
 }
@@ -234,12 +401,12 @@ func (s *Server) handleHealth(c *gin.Context) {
 
 // handleGetSystemConfig Get system configuration (configuration that client needs to know)
 func (s *Server) handleGetSystemConfig(c *gin.Context) {
-	cfg := config.Get()
+	settings := s.settings.get()
 
 	c.JSON(http.StatusOK, gin.H{
-		"registration_enabled": cfg.RegistrationEnabled,
-		"btc_eth_leverage":     10, // Default value
-		"altcoin_leverage":     5,  // Default value
+		"registration_enabled": settings.RegistrationEnabled,
+		"btc_eth_leverage":     settings.DefaultBTCETHLeverage,
+		"altcoin_leverage":     settings.DefaultAltcoinLeverage,
 	})
 }
 
@@ -404,14 +571,18 @@ func (s *Server) getTraderFromQuery(c *gin.Context) (*manager.TraderManager, str
 
 // AI trader management related structures
 type CreateTraderRequest struct {
-	Name                string  `json:"name" binding:"required"`
-	AIModelID           string  `json:"ai_model_id" binding:"required"`
-	ExchangeID          string  `json:"exchange_id" binding:"required"`
-	StrategyID          string  `json:"strategy_id"` // Strategy ID (new version)
-	InitialBalance      float64 `json:"initial_balance"`
-	ScanIntervalMinutes int     `json:"scan_interval_minutes"`
-	IsCrossMargin       *bool   `json:"is_cross_margin"`     // Pointer type, nil means use default value true
-	ShowInCompetition   *bool   `json:"show_in_competition"` // Pointer type, nil means use default value true
+	Name                string   `json:"name" binding:"required"`
+	AIModelID           string   `json:"ai_model_id" binding:"required"`
+	ExchangeID          string   `json:"exchange_id" binding:"required"`
+	StrategyID          string   `json:"strategy_id"` // Strategy ID (new version)
+	InitialBalance      float64  `json:"initial_balance"`
+	ScanIntervalMinutes int      `json:"scan_interval_minutes"`
+	ScanIntervalSeconds int      `json:"scan_interval_seconds"` // Sub-minute scanning, overrides minutes when > 0
+	IsCrossMargin       *bool    `json:"is_cross_margin"`       // Pointer type, nil means use default value true
+	ShowInCompetition   *bool    `json:"show_in_competition"`   // Pointer type, nil means use default value true
+	FallbackModelIDs    []string `json:"fallback_model_ids"`    // AI models tried in order when the primary model fails
+	ContractType        string   `json:"contract_type"`         // linear (default) or inverse (coin-margined, Binance/Bybit only)
+	MarginCoin          string   `json:"margin_coin"`           // Margin coin of inverse traders, default BTC
 	// The following fields are kept for backward compatibility, new version uses strategy config
 	BTCETHLeverage       int    `json:"btc_eth_leverage"`
 	AltcoinLeverage      int    `json:"altcoin_leverage"`
@@ -465,6 +636,10 @@ type SafeExchangeConfig struct {
 	AsterUser             string `json:"asterUser"`             // Aster username (not sensitive)
 	AsterSigner           string `json:"asterSigner"`           // Aster signer (not sensitive)
 	LighterWalletAddr     string `json:"lighterWalletAddr"`     // LIGHTER wallet address (not sensitive)
+	// API key permission check: pending, verified, missing_permission, withdrawal_enabled, unverifiable, failed
+	KeyVerificationStatus  string     `json:"key_verification_status"`
+	KeyVerificationMessage string     `json:"key_verification_message,omitempty"`
+	KeyVerifiedAt          *time.Time `json:"key_verified_at,omitempty"`
 }
 
 type UpdateModelConfigRequest struct {
@@ -478,6 +653,7 @@ type UpdateModelConfigRequest struct {
 
 type UpdateExchangeConfigRequest struct {
 	Exchanges map[string]struct {
+		AccountName             string `json:"account_name"` // Rename account (optional, unique per exchange type)
 		Enabled                 bool   `json:"enabled"`
 		APIKey                  string `json:"api_key"`
 		SecretKey               string `json:"secret_key"`
@@ -503,34 +679,30 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 		return
 	}
 
-	// Validate leverage values
-	if req.BTCETHLeverage < 0 || req.BTCETHLeverage > 50 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "BTC/ETH leverage must be between 1-50x"})
+	// Validate leverage values and trading symbol format
+	if err := validateTraderSettings(req.BTCETHLeverage, req.AltcoinLeverage, req.TradingSymbols); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if req.AltcoinLeverage < 0 || req.AltcoinLeverage > 20 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Altcoin leverage must be between 1-20x"})
+
+	// Bind trader to a specific exchange account
+	exchangeID, ok := s.resolveTraderExchange(c, userID, req.ExchangeID)
+	if !ok {
 		return
 	}
+	req.ExchangeID = exchangeID
 
-	// Validate trading symbol format
-	if req.TradingSymbols != "" {
-		symbols := strings.Split(req.TradingSymbols, ",")
-		for _, symbol := range symbols {
-			symbol = strings.TrimSpace(symbol)
-			if symbol != "" && !strings.HasSuffix(strings.ToUpper(symbol), "USDT") {
-				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid symbol format: %s, must end with USDT", symbol)})
-				return
-			}
-		}
+	fallbackModelIDs, ok := s.resolveFallbackModelIDs(c, userID, req.AIModelID, req.FallbackModelIDs)
+	if !ok {
+		return
 	}
 
-	// Generate trader ID (use short UUID prefix for readability)
-	exchangeIDShort := req.ExchangeID
-	if len(exchangeIDShort) > 8 {
-		exchangeIDShort = exchangeIDShort[:8]
+	contractType, marginCoin, ok := s.resolveContractType(c, userID, req.ExchangeID, req.ContractType, req.MarginCoin)
+	if !ok {
+		return
 	}
-	traderID := fmt.Sprintf("%s_%s_%d", exchangeIDShort, req.AIModelID, time.Now().Unix())
+
+	traderID := newTraderID(req.ExchangeID, req.AIModelID)
 
 	// Set default values
 	isCrossMargin := true // Default to cross margin mode
@@ -544,8 +716,9 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 	}
 
 	// Set leverage default values
-	btcEthLeverage := 10 // Default value
-	altcoinLeverage := 5 // Default value
+	settings := s.settings.get()
+	btcEthLeverage := settings.DefaultBTCETHLeverage
+	altcoinLeverage := settings.DefaultAltcoinLeverage
 	if req.BTCETHLeverage > 0 {
 		btcEthLeverage = req.BTCETHLeverage
 	}
@@ -559,14 +732,176 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 		systemPromptTemplate = req.SystemPromptTemplate
 	}
 
-	// Set scan interval default value
-	scanIntervalMinutes := req.ScanIntervalMinutes
-	if scanIntervalMinutes < 3 {
-		scanIntervalMinutes = 3 // Default 3 minutes, not allowed to be less than 3
+	// Set scan interval default value (3 minutes), clamped to system and AI provider floors
+	requestedInterval := time.Duration(req.ScanIntervalMinutes) * time.Minute
+	if req.ScanIntervalSeconds > 0 {
+		requestedInterval = time.Duration(req.ScanIntervalSeconds) * time.Second
+	} else if requestedInterval <= 0 {
+		requestedInterval = trader.DefaultMinScanInterval
+	}
+	scanIntervalMinutes, scanIntervalSeconds, scanWarnings := s.resolveScanInterval(userID, req.AIModelID, requestedInterval)
+
+	// Query exchange actual balance, override user input (in the margin coin for inverse traders)
+	actualBalance := s.queryExchangeEquity(userID, req.ExchangeID, marginCoin, req.InitialBalance)
+
+	// Create trader configuration (database entity)
+	logger.Infof("🔧 DEBUG: Starting to create trader config, ID=%s, Name=%s, AIModel=%s, Exchange=%s, StrategyID=%s", traderID, req.Name, req.AIModelID, req.ExchangeID, req.StrategyID)
+	traderRecord := &store.Trader{
+		ID:                   traderID,
+		UserID:               userID,
+		Name:                 req.Name,
+		AIModelID:            req.AIModelID,
+		ExchangeID:           req.ExchangeID,
+		StrategyID:           req.StrategyID, // Associated strategy ID (new version)
+		InitialBalance:       actualBalance,  // Use actual queried balance
+		BTCETHLeverage:       btcEthLeverage,
+		AltcoinLeverage:      altcoinLeverage,
+		TradingSymbols:       req.TradingSymbols,
+		UseAI500:             req.UseAI500,
+		UseOITop:             req.UseOITop,
+		CustomPrompt:         req.CustomPrompt,
+		OverrideBasePrompt:   req.OverrideBasePrompt,
+		SystemPromptTemplate: systemPromptTemplate,
+		IsCrossMargin:        isCrossMargin,
+		ShowInCompetition:    showInCompetition,
+		ScanIntervalMinutes:  scanIntervalMinutes,
+		ScanIntervalSeconds:  scanIntervalSeconds,
+		FallbackModelIDs:     fallbackModelIDs,
+		ContractType:         contractType,
+		MarginCoin:           marginCoin,
+		IsRunning:            false,
+	}
+
+	// Save to database
+	logger.Infof("🔧 DEBUG: Preparing to call CreateTrader")
+	err := s.store.Trader().Create(traderRecord)
+	if err != nil {
+		logger.Infof("❌ Failed to create trader: %v", err)
+		SafeInternalError(c, "Failed to create trader", err)
+		return
+	}
+	logger.Infof("🔧 DEBUG: CreateTrader succeeded")
+
+	// Immediately load new trader into TraderManager
+	logger.Infof("🔧 DEBUG: Preparing to call LoadUserTraders")
+	err = s.traderManager.LoadUserTradersFromStore(s.store, userID)
+	if err != nil {
+		logger.Infof("⚠️ Failed to load user traders into memory: %v", err)
+		// Don't return error here since trader was successfully created in database
+	}
+	logger.Infof("🔧 DEBUG: LoadUserTraders completed")
+
+	logger.Infof("✓ Trader created successfully: %s (model: %s, exchange: %s)", req.Name, req.AIModelID, req.ExchangeID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"trader_id":   traderID,
+		"trader_name": req.Name,
+		"ai_model":    req.AIModelID,
+		"is_running":  false,
+		"warnings":    scanWarnings,
+	})
+}
+
+// newTraderID generates trader ID (use short UUID prefix for readability)
+func newTraderID(exchangeID, aiModelID string) string {
+	exchangeIDShort := exchangeID
+	if len(exchangeIDShort) > 8 {
+		exchangeIDShort = exchangeIDShort[:8]
+	}
+	return fmt.Sprintf("%s_%s_%d", exchangeIDShort, aiModelID, time.Now().Unix())
+}
+
+// resolveTraderExchange resolves the exchange account a trader binds to (account UUID, or exchange type
+// when the user has a single account of it), responding with 400 if missing or ambiguous
+func (s *Server) resolveTraderExchange(c *gin.Context, userID, ref string) (string, bool) {
+	exchange, err := s.store.Exchange().Resolve(userID, ref)
+	if err != nil {
+		SafeBadRequest(c, err.Error())
+		return "", false
+	}
+	return exchange.ID, true
+}
+
+// validateTraderSettings checks leverage bounds (0 = default) and trading symbol format of a trader configuration
+func validateTraderSettings(btcEthLeverage, altcoinLeverage int, tradingSymbols string) error {
+	if btcEthLeverage < 0 || btcEthLeverage > 50 {
+		return fmt.Errorf("BTC/ETH leverage must be between 1-50x")
+	}
+	if altcoinLeverage < 0 || altcoinLeverage > 20 {
+		return fmt.Errorf("Altcoin leverage must be between 1-20x")
+	}
+	for _, symbol := range strings.Split(tradingSymbols, ",") {
+		symbol = strings.TrimSpace(symbol)
+		if symbol != "" && !strings.HasSuffix(strings.ToUpper(symbol), "USDT") {
+			return fmt.Errorf("Invalid symbol format: %s, must end with USDT", symbol)
+		}
+	}
+	return nil
+}
+
+// resolveScanInterval clamps a requested scan interval to the system and AI provider floors.
+// Returns the interval as stored (whole minutes, plus seconds when not a whole minute) and
+// warnings for the client (clamping, AI usage cost of sub-3-minute intervals).
+func (s *Server) resolveScanInterval(userID, aiModelID string, requested time.Duration) (minutes, seconds int, warnings []string) {
+	provider := ""
+	if model, err := s.store.AIModel().Get(userID, aiModelID); err == nil {
+		provider = model.Provider
+	}
+
+	interval, reason := trader.ClampScanInterval(requested, provider, time.Duration(config.Get().MinScanIntervalSeconds)*time.Second)
+	warnings = []string{}
+	if reason != "" {
+		warnings = append(warnings, reason)
+	}
+	if warning := trader.ScanIntervalCostWarning(interval, provider); warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	minutes = int((interval + time.Minute - 1) / time.Minute)
+	if interval%time.Minute != 0 {
+		seconds = int(interval / time.Second)
 	}
+	return minutes, seconds, warnings
+}
 
-	// Query exchange actual balance, override user input
-	actualBalance := req.InitialBalance // Default to use user input
+// resolveFallbackModelIDs validates a fallback chain against the user's AI models and joins it for storage.
+// On failure a 400 has been written and ok is false.
+func (s *Server) resolveFallbackModelIDs(c *gin.Context, userID, primaryID string, ids []string) (string, bool) {
+	if len(ids) == 0 {
+		return "", true
+	}
+	models, err := s.store.AIModel().List(userID)
+	if err != nil {
+		SafeInternalError(c, "Get AI models", err)
+		return "", false
+	}
+	known := make(map[string]bool, len(models))
+	for _, m := range models {
+		known[m.ID] = true
+	}
+
+	seen := make(map[string]bool, len(ids))
+	chain := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || id == primaryID || seen[id] {
+			continue
+		}
+		if !known[id] {
+			SafeBadRequest(c, fmt.Sprintf("Fallback AI model %s does not exist", id))
+			return "", false
+		}
+		seen[id] = true
+		chain = append(chain, id)
+	}
+	return strings.Join(chain, ","), true
+}
+
+// queryExchangeEquity queries the exchange's actual total equity for use as initial balance
+// marginCoin is set for inverse traders, whose equity is queried in that coin.
+// Returns fallback when the exchange is missing, disabled or the query fails
+func (s *Server) queryExchangeEquity(userID, exchangeID, marginCoin string, fallback float64) float64 {
+	actualBalance := fallback // Default to use user input
 	exchanges, err := s.store.Exchange().List(userID)
 	if err != nil {
 		logger.Infof("⚠️ Failed to get exchange config, using user input for initial balance: %v", err)
@@ -575,16 +910,16 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 	// Find matching exchange configuration
 	var exchangeCfg *store.Exchange
 	for _, ex := range exchanges {
-		if ex.ID == req.ExchangeID {
+		if ex.ID == exchangeID {
 			exchangeCfg = ex
 			break
 		}
 	}
 
 	if exchangeCfg == nil {
-		logger.Infof("⚠️ Exchange %s configuration not found, using user input for initial balance", req.ExchangeID)
+		logger.Infof("⚠️ Exchange %s configuration not found, using user input for initial balance", exchangeID)
 	} else if !exchangeCfg.Enabled {
-		logger.Infof("⚠️ Exchange %s not enabled, using user input for initial balance", req.ExchangeID)
+		logger.Infof("⚠️ Exchange %s not enabled, using user input for initial balance", exchangeID)
 	} else {
 		// Create temporary trader based on exchange type to query balance
 		var tempTrader trader.Trader
@@ -594,7 +929,11 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 		// Convert EncryptedString fields to string
 		switch exchangeCfg.ExchangeType {
 		case "binance":
-			tempTrader = binance.NewFuturesTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey), userID)
+			if marginCoin != "" {
+				tempTrader = binance.NewInverseFuturesTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey), marginCoin)
+			} else {
+				tempTrader = binance.NewFuturesTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey), userID)
+			}
 		case "hyperliquid":
 			tempTrader, createErr = hyperliquidtrader.NewHyperliquidTrader(
 				string(exchangeCfg.APIKey), // private key
@@ -608,10 +947,14 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 				string(exchangeCfg.AsterPrivateKey),
 			)
 		case "bybit":
-			tempTrader = bybit.NewBybitTrader(
-				string(exchangeCfg.APIKey),
-				string(exchangeCfg.SecretKey),
-			)
+			if marginCoin != "" {
+				tempTrader = bybit.NewBybitInverseTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey), marginCoin)
+			} else {
+				tempTrader = bybit.NewBybitTrader(
+					string(exchangeCfg.APIKey),
+					string(exchangeCfg.SecretKey),
+				)
+			}
 		case "okx":
 			tempTrader = okx.NewOKXTrader(
 				string(exchangeCfg.APIKey),
@@ -623,6 +966,7 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 				string(exchangeCfg.APIKey),
 				string(exchangeCfg.SecretKey),
 				string(exchangeCfg.Passphrase),
+				exchangeCfg.Testnet,
 			)
 		case "gate":
 			tempTrader = gate.NewGateTrader(
@@ -666,7 +1010,7 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 				for _, key := range balanceKeys {
 					if balance, ok := balanceInfo[key].(float64); ok && balance > 0 {
 						actualBalance = balance
-						logger.Infof("✓ Queried exchange total equity (%s): %.2f USDT (user input: %.2f USDT)", key, actualBalance, req.InitialBalance)
+						logger.Infof("✓ Queried exchange total equity (%s): %.2f USDT (user input: %.2f USDT)", key, actualBalance, fallback)
 						break
 					}
 				}
@@ -677,69 +1021,21 @@ func (s *Server) handleCreateTrader(c *gin.Context) {
 		}
 	}
 
-	// Create trader configuration (database entity)
-	logger.Infof("🔧 DEBUG: Starting to create trader config, ID=%s, Name=%s, AIModel=%s, Exchange=%s, StrategyID=%s", traderID, req.Name, req.AIModelID, req.ExchangeID, req.StrategyID)
-	traderRecord := &store.Trader{
-		ID:                   traderID,
-		UserID:               userID,
-		Name:                 req.Name,
-		AIModelID:            req.AIModelID,
-		ExchangeID:           req.ExchangeID,
-		StrategyID:           req.StrategyID, // Associated strategy ID (new version)
-		InitialBalance:       actualBalance,  // Use actual queried balance
-		BTCETHLeverage:       btcEthLeverage,
-		AltcoinLeverage:      altcoinLeverage,
-		TradingSymbols:       req.TradingSymbols,
-		UseAI500:             req.UseAI500,
-		UseOITop:             req.UseOITop,
-		CustomPrompt:         req.CustomPrompt,
-		OverrideBasePrompt:   req.OverrideBasePrompt,
-		SystemPromptTemplate: systemPromptTemplate,
-		IsCrossMargin:        isCrossMargin,
-		ShowInCompetition:    showInCompetition,
-		ScanIntervalMinutes:  scanIntervalMinutes,
-		IsRunning:            false,
-	}
-
-	// Save to database
-	logger.Infof("🔧 DEBUG: Preparing to call CreateTrader")
-	err = s.store.Trader().Create(traderRecord)
-	if err != nil {
-		logger.Infof("❌ Failed to create trader: %v", err)
-		SafeInternalError(c, "Failed to create trader", err)
-		return
-	}
-	logger.Infof("🔧 DEBUG: CreateTrader succeeded")
-
-	// Immediately load new trader into TraderManager
-	logger.Infof("🔧 DEBUG: Preparing to call LoadUserTraders")
-	err = s.traderManager.LoadUserTradersFromStore(s.store, userID)
-	if err != nil {
-		logger.Infof("⚠️ Failed to load user traders into memory: %v", err)
-		// Don't return error here since trader was successfully created in database
-	}
-	logger.Infof("🔧 DEBUG: LoadUserTraders completed")
-
-	logger.Infof("✓ Trader created successfully: %s (model: %s, exchange: %s)", req.Name, req.AIModelID, req.ExchangeID)
-
-	c.JSON(http.StatusCreated, gin.H{
-		"trader_id":   traderID,
-		"trader_name": req.Name,
-		"ai_model":    req.AIModelID,
-		"is_running":  false,
-	})
+	return actualBalance
 }
 
 // UpdateTraderRequest Update trader request
 type UpdateTraderRequest struct {
-	Name                string  `json:"name" binding:"required"`
-	AIModelID           string  `json:"ai_model_id" binding:"required"`
-	ExchangeID          string  `json:"exchange_id" binding:"required"`
-	StrategyID          string  `json:"strategy_id"` // Strategy ID (new version)
-	InitialBalance      float64 `json:"initial_balance"`
-	ScanIntervalMinutes int     `json:"scan_interval_minutes"`
-	IsCrossMargin       *bool   `json:"is_cross_margin"`
-	ShowInCompetition   *bool   `json:"show_in_competition"`
+	Name                string    `json:"name" binding:"required"`
+	AIModelID           string    `json:"ai_model_id" binding:"required"`
+	ExchangeID          string    `json:"exchange_id" binding:"required"`
+	StrategyID          string    `json:"strategy_id"` // Strategy ID (new version)
+	InitialBalance      float64   `json:"initial_balance"`
+	ScanIntervalMinutes int       `json:"scan_interval_minutes"`
+	ScanIntervalSeconds *int      `json:"scan_interval_seconds"` // Pointer type, nil means keep current value (0 clears it)
+	IsCrossMargin       *bool     `json:"is_cross_margin"`
+	ShowInCompetition   *bool     `json:"show_in_competition"`
+	FallbackModelIDs    *[]string `json:"fallback_model_ids"` // Pointer type, nil means keep current fallback chain
 	// The following fields are kept for backward compatibility, new version uses strategy config
 	BTCETHLeverage       int    `json:"btc_eth_leverage"`
 	AltcoinLeverage      int    `json:"altcoin_leverage"`
@@ -780,6 +1076,25 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 		return
 	}
 
+	exchangeID, ok := s.resolveTraderExchange(c, userID, req.ExchangeID)
+	if !ok {
+		return
+	}
+	req.ExchangeID = exchangeID
+	if existingTrader.IsInverse() && req.ExchangeID != existingTrader.ExchangeID {
+		// Contract type is fixed, the new account must support it
+		if _, _, ok := s.resolveContractType(c, userID, req.ExchangeID, existingTrader.ContractType, existingTrader.MarginCoin); !ok {
+			return
+		}
+	}
+
+	fallbackModelIDs := existingTrader.FallbackModelIDs // Keep original value
+	if req.FallbackModelIDs != nil {
+		if fallbackModelIDs, ok = s.resolveFallbackModelIDs(c, userID, req.AIModelID, *req.FallbackModelIDs); !ok {
+			return
+		}
+	}
+
 	// Set default values
 	isCrossMargin := existingTrader.IsCrossMargin // Keep original value
 	if req.IsCrossMargin != nil {
@@ -801,15 +1116,18 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 		altcoinLeverage = existingTrader.AltcoinLeverage // Keep original value
 	}
 
-	// Set scan interval, allow updates
-	scanIntervalMinutes := req.ScanIntervalMinutes
-	logger.Infof("📊 Update trader scan_interval: req=%d, existing=%d", req.ScanIntervalMinutes, existingTrader.ScanIntervalMinutes)
-	if scanIntervalMinutes <= 0 {
-		scanIntervalMinutes = existingTrader.ScanIntervalMinutes // Keep original value
-	} else if scanIntervalMinutes < 3 {
-		scanIntervalMinutes = 3
+	// Set scan interval, allow updates (seconds take precedence, minutes alone replace a seconds interval)
+	logger.Infof("📊 Update trader scan_interval: req=%d min, existing=%d min / %d s", req.ScanIntervalMinutes, existingTrader.ScanIntervalMinutes, existingTrader.ScanIntervalSeconds)
+	requestedInterval := existingTrader.ScanInterval() // Keep original value
+	if req.ScanIntervalSeconds != nil && *req.ScanIntervalSeconds > 0 {
+		requestedInterval = time.Duration(*req.ScanIntervalSeconds) * time.Second
+	} else if req.ScanIntervalMinutes > 0 {
+		requestedInterval = time.Duration(req.ScanIntervalMinutes) * time.Minute
+	} else if req.ScanIntervalSeconds != nil {
+		requestedInterval = time.Duration(existingTrader.ScanIntervalMinutes) * time.Minute
 	}
-	logger.Infof("📊 Final scan_interval_minutes: %d", scanIntervalMinutes)
+	scanIntervalMinutes, scanIntervalSeconds, scanWarnings := s.resolveScanInterval(userID, req.AIModelID, requestedInterval)
+	logger.Infof("📊 Final scan interval: %d min / %d s", scanIntervalMinutes, scanIntervalSeconds)
 
 	// Set system prompt template
 	systemPromptTemplate := req.SystemPromptTemplate
@@ -841,6 +1159,8 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 		IsCrossMargin:        isCrossMargin,
 		ShowInCompetition:    showInCompetition,
 		ScanIntervalMinutes:  scanIntervalMinutes,
+		ScanIntervalSeconds:  scanIntervalSeconds,
+		FallbackModelIDs:     fallbackModelIDs,
 		IsRunning:            existingTrader.IsRunning, // Keep original value
 	}
 
@@ -885,26 +1205,43 @@ func (s *Server) handleUpdateTrader(c *gin.Context) {
 	}
 
 	logger.Infof("✓ Trader updated successfully: %s (model: %s, exchange: %s, strategy: %s)", req.Name, req.AIModelID, req.ExchangeID, strategyID)
+	s.recordTraderEvent(traderID, store.TraderEventConfigChange, "Trader configuration updated", map[string]interface{}{
+		"ai_model_id":   req.AIModelID,
+		"exchange_id":   req.ExchangeID,
+		"strategy_id":   strategyID,
+		"scan_interval": (time.Duration(scanIntervalMinutes) * time.Minute).String(),
+	})
 
 	c.JSON(http.StatusOK, gin.H{
 		"trader_id":   traderID,
 		"trader_name": req.Name,
 		"ai_model":    req.AIModelID,
 		"message":     "Trader updated successfully",
+		"warnings":    scanWarnings,
 	})
 }
 
-// handleDeleteTrader Delete trader
+// handleDeleteTrader Move trader to the trash (restorable until purged)
 func (s *Server) handleDeleteTrader(c *gin.Context) {
 	userID := c.GetString("user_id")
 	traderID := c.Param("id")
 
-	// Delete from database
-	err := s.store.Trader().Delete(userID, traderID)
-	if err != nil {
+	// Followers of a deleted leader go back to their own decisions
+	followers, _ := s.store.CopyTrade().ListByLeader(traderID)
+
+	// Move to the trash in the database
+	if err := s.store.Trader().Delete(userID, traderID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			SafeNotFound(c, "Trader")
+			return
+		}
 		SafeInternalError(c, "Failed to delete trader", err)
 		return
 	}
+	s.recordTraderEvent(traderID, store.TraderEventDelete, "Trader moved to trash", nil)
+	for _, link := range followers {
+		s.traderManager.SetCopyLink(link.FollowerID, nil)
+	}
 
 	// If trader is running, stop it first
 	if trader, err := s.traderManager.GetTrader(traderID); err == nil {
@@ -917,9 +1254,10 @@ func (s *Server) handleDeleteTrader(c *gin.Context) {
 
 	// Remove trader from memory
 	s.traderManager.RemoveTrader(traderID)
+	logger.ForgetSource(traderID)
 
-	logger.Infof("✓ Trader deleted: %s", traderID)
-	c.JSON(http.StatusOK, gin.H{"message": "Trader deleted"})
+	logger.Infof("✓ Trader moved to trash: %s", traderID)
+	c.JSON(http.StatusOK, gin.H{"message": "Trader moved to trash"})
 }
 
 // handleStartTrader Start trader
@@ -928,12 +1266,27 @@ func (s *Server) handleStartTrader(c *gin.Context) {
 	traderID := c.Param("id")
 
 	// Verify trader belongs to current user
-	_, err := s.store.Trader().GetFullConfig(userID, traderID)
+	fullConfig, err := s.store.Trader().GetFullConfig(userID, traderID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist or no access permission"})
 		return
 	}
 
+	// Never trade live with a key that can withdraw funds
+	if fullConfig.Exchange != nil && fullConfig.Exchange.KeyVerificationStatus == ExchangeKeyWithdrawalEnabled && !fullConfig.Trader.PaperMode {
+		c.JSON(http.StatusConflict, gin.H{"error": "The exchange API key has withdrawal permission, disable it on the exchange and verify the key again"})
+		return
+	}
+
+	// Enforce circuit breaker cooldown before restart
+	if state, _ := s.store.CircuitBreaker().Get(traderID); state.InCooldown(time.Now()) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":          fmt.Sprintf("Trader paused by circuit breaker (%s), restart allowed after %s", state.TripReason, state.CooldownUntil.Format(time.RFC3339)),
+			"cooldown_until": state.CooldownUntil,
+		})
+		return
+	}
+
 	// Check if trader exists in memory and if it's running
 	existingTrader, _ := s.traderManager.GetTrader(traderID)
 	if existingTrader != nil {
@@ -1078,6 +1431,9 @@ func (s *Server) handleUpdateTraderPrompt(c *gin.Context) {
 		trader.SetOverrideBasePrompt(req.OverrideBasePrompt)
 		logger.Infof("✓ Updated trader %s custom prompt (override base=%v)", trader.GetName(), req.OverrideBasePrompt)
 	}
+	s.recordTraderEvent(traderID, store.TraderEventConfigChange, "Custom prompt updated", map[string]interface{}{
+		"override_base_prompt": req.OverrideBasePrompt,
+	})
 
 	c.JSON(http.StatusOK, gin.H{"message": "Custom prompt updated"})
 }
@@ -1107,12 +1463,14 @@ func (s *Server) handleToggleCompetition(c *gin.Context) {
 	if trader, err := s.traderManager.GetTrader(traderID); err == nil {
 		trader.SetShowInCompetition(req.ShowInCompetition)
 	}
+	s.traderManager.InvalidateCompetitionCache()
 
 	status := "shown"
 	if !req.ShowInCompetition {
 		status = "hidden"
 	}
 	logger.Infof("✓ Trader %s competition visibility updated: %s", traderID, status)
+	s.recordTraderEvent(traderID, store.TraderEventConfigChange, "Competition visibility: "+status, nil)
 	c.JSON(http.StatusOK, gin.H{
 		"message":             "Competition visibility updated",
 		"show_in_competition": req.ShowInCompetition,
@@ -1163,7 +1521,11 @@ func (s *Server) handleSyncBalance(c *gin.Context) {
 	// Convert EncryptedString fields to string
 	switch exchangeCfg.ExchangeType {
 	case "binance":
-		tempTrader = binance.NewFuturesTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey), userID)
+		if fullConfig.Trader.IsInverse() {
+			tempTrader = binance.NewInverseFuturesTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey), fullConfig.Trader.MarginCoin)
+		} else {
+			tempTrader = binance.NewFuturesTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey), userID)
+		}
 	case "hyperliquid":
 		tempTrader, createErr = hyperliquidtrader.NewHyperliquidTrader(
 			string(exchangeCfg.APIKey),
@@ -1177,10 +1539,14 @@ func (s *Server) handleSyncBalance(c *gin.Context) {
 			string(exchangeCfg.AsterPrivateKey),
 		)
 	case "bybit":
-		tempTrader = bybit.NewBybitTrader(
-			string(exchangeCfg.APIKey),
-			string(exchangeCfg.SecretKey),
-		)
+		if fullConfig.Trader.IsInverse() {
+			tempTrader = bybit.NewBybitInverseTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey), fullConfig.Trader.MarginCoin)
+		} else {
+			tempTrader = bybit.NewBybitTrader(
+				string(exchangeCfg.APIKey),
+				string(exchangeCfg.SecretKey),
+			)
+		}
 	case "okx":
 		tempTrader = okx.NewOKXTrader(
 			string(exchangeCfg.APIKey),
@@ -1192,6 +1558,7 @@ func (s *Server) handleSyncBalance(c *gin.Context) {
 			string(exchangeCfg.APIKey),
 			string(exchangeCfg.SecretKey),
 			string(exchangeCfg.Passphrase),
+			exchangeCfg.Testnet,
 		)
 	case "gate":
 		tempTrader = gate.NewGateTrader(
@@ -1277,6 +1644,9 @@ func (s *Server) handleSyncBalance(c *gin.Context) {
 	}
 
 	logger.Infof("✅ Synced balance: %.2f → %.2f USDT (%s %.2f%%)", oldBalance, actualBalance, changeType, changePercent)
+	s.recordTraderEvent(traderID, store.TraderEventBalanceSync,
+		fmt.Sprintf("Initial balance synced: %.2f → %.2f USDT (%+.2f%%)", oldBalance, actualBalance, changePercent),
+		map[string]interface{}{"old_balance": oldBalance, "new_balance": actualBalance})
 
 	c.JSON(http.StatusOK, gin.H{
 		"message":        "Balance synced successfully",
@@ -1311,6 +1681,12 @@ func (s *Server) handleClosePosition(c *gin.Context) {
 		return
 	}
 
+	// Paper mode positions are simulated, never close the real exchange position
+	if fullConfig.Trader.PaperMode {
+		s.closePaperPosition(c, traderID, req.Symbol, req.Side)
+		return
+	}
+
 	exchangeCfg := fullConfig.Exchange
 
 	if exchangeCfg == nil || !exchangeCfg.Enabled {
@@ -1326,7 +1702,11 @@ func (s *Server) handleClosePosition(c *gin.Context) {
 	// Convert EncryptedString fields to string
 	switch exchangeCfg.ExchangeType {
 	case "binance":
-		tempTrader = binance.NewFuturesTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey), userID)
+		if fullConfig.Trader.IsInverse() {
+			tempTrader = binance.NewInverseFuturesTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey), fullConfig.Trader.MarginCoin)
+		} else {
+			tempTrader = binance.NewFuturesTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey), userID)
+		}
 	case "hyperliquid":
 		tempTrader, createErr = hyperliquidtrader.NewHyperliquidTrader(
 			string(exchangeCfg.APIKey),
@@ -1340,10 +1720,14 @@ func (s *Server) handleClosePosition(c *gin.Context) {
 			string(exchangeCfg.AsterPrivateKey),
 		)
 	case "bybit":
-		tempTrader = bybit.NewBybitTrader(
-			string(exchangeCfg.APIKey),
-			string(exchangeCfg.SecretKey),
-		)
+		if fullConfig.Trader.IsInverse() {
+			tempTrader = bybit.NewBybitInverseTrader(string(exchangeCfg.APIKey), string(exchangeCfg.SecretKey), fullConfig.Trader.MarginCoin)
+		} else {
+			tempTrader = bybit.NewBybitTrader(
+				string(exchangeCfg.APIKey),
+				string(exchangeCfg.SecretKey),
+			)
+		}
 	case "okx":
 		tempTrader = okx.NewOKXTrader(
 			string(exchangeCfg.APIKey),
@@ -1355,6 +1739,7 @@ func (s *Server) handleClosePosition(c *gin.Context) {
 			string(exchangeCfg.APIKey),
 			string(exchangeCfg.SecretKey),
 			string(exchangeCfg.Passphrase),
+			exchangeCfg.Testnet,
 		)
 	case "gate":
 		tempTrader = gate.NewGateTrader(
@@ -1820,17 +2205,20 @@ func (s *Server) handleGetExchangeConfigs(c *gin.Context) {
 	safeExchanges := make([]SafeExchangeConfig, len(exchanges))
 	for i, exchange := range exchanges {
 		safeExchanges[i] = SafeExchangeConfig{
-			ID:                    exchange.ID,
-			ExchangeType:          exchange.ExchangeType,
-			AccountName:           exchange.AccountName,
-			Name:                  exchange.Name,
-			Type:                  exchange.Type,
-			Enabled:               exchange.Enabled,
-			Testnet:               exchange.Testnet,
-			HyperliquidWalletAddr: exchange.HyperliquidWalletAddr,
-			AsterUser:             exchange.AsterUser,
-			AsterSigner:           exchange.AsterSigner,
-			LighterWalletAddr:     exchange.LighterWalletAddr,
+			ID:                     exchange.ID,
+			ExchangeType:           exchange.ExchangeType,
+			AccountName:            exchange.AccountName,
+			Name:                   exchange.Name,
+			Type:                   exchange.Type,
+			Enabled:                exchange.Enabled,
+			Testnet:                exchange.Testnet,
+			HyperliquidWalletAddr:  exchange.HyperliquidWalletAddr,
+			AsterUser:              exchange.AsterUser,
+			AsterSigner:            exchange.AsterSigner,
+			LighterWalletAddr:      exchange.LighterWalletAddr,
+			KeyVerificationStatus:  exchange.KeyVerificationStatus,
+			KeyVerificationMessage: exchange.KeyVerificationMessage,
+			KeyVerifiedAt:          exchange.KeyVerifiedAt,
 		}
 	}
 
@@ -1897,6 +2285,21 @@ func (s *Server) handleUpdateExchangeConfigs(c *gin.Context) {
 		logger.Infof("🔓 Decrypted exchange config data (UserID: %s)", userID)
 	}
 
+	// Check renames before anything is written, so a taken name leaves every account unchanged
+	for exchangeID, exchangeData := range req.Exchanges {
+		if exchangeData.AccountName == "" {
+			continue
+		}
+		if err := s.store.Exchange().CheckAccountName(userID, exchangeID, exchangeData.AccountName); err != nil {
+			if errors.Is(err, store.ErrExchangeExists) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			SafeBadRequest(c, err.Error())
+			return
+		}
+	}
+
 	// Update each exchange's configuration and track traders that need reload
 	tradersToReload := make(map[string]bool)
 	for exchangeID, exchangeData := range req.Exchanges {
@@ -1906,11 +2309,27 @@ func (s *Server) handleUpdateExchangeConfigs(c *gin.Context) {
 			tradersToReload[t.ID] = true
 		}
 
+		// Rename first: the unique index may still reject a name taken concurrently
+		if exchangeData.AccountName != "" {
+			if err := s.store.Exchange().UpdateAccountName(userID, exchangeID, exchangeData.AccountName); err != nil {
+				if errors.Is(err, store.ErrExchangeExists) {
+					c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+					return
+				}
+				SafeBadRequest(c, err.Error())
+				return
+			}
+		}
 		err := s.store.Exchange().Update(userID, exchangeID, exchangeData.Enabled, exchangeData.APIKey, exchangeData.SecretKey, exchangeData.Passphrase, exchangeData.Testnet, exchangeData.HyperliquidWalletAddr, exchangeData.AsterUser, exchangeData.AsterSigner, exchangeData.AsterPrivateKey, exchangeData.LighterWalletAddr, exchangeData.LighterPrivateKey, exchangeData.LighterAPIKeyPrivateKey, exchangeData.LighterAPIKeyIndex)
 		if err != nil {
 			SafeInternalError(c, fmt.Sprintf("Update exchange %s", exchangeID), err)
 			return
 		}
+		// New credentials: check their permissions again
+		if exchangeData.APIKey != "" || exchangeData.SecretKey != "" || exchangeData.Passphrase != "" ||
+			exchangeData.AsterPrivateKey != "" || exchangeData.LighterAPIKeyPrivateKey != "" {
+			s.verifyExchangeKeyAsync(userID, exchangeID)
+		}
 	}
 
 	// Remove affected traders from memory BEFORE reloading to pick up new config
@@ -2018,15 +2437,25 @@ func (s *Server) handleCreateExchange(c *gin.Context) {
 		req.LighterWalletAddr, req.LighterPrivateKey, req.LighterAPIKeyPrivateKey, req.LighterAPIKeyIndex,
 	)
 	if err != nil {
+		if errors.Is(err, store.ErrExchangeExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		logger.Infof("❌ Failed to create exchange account: %v", err)
 		SafeInternalError(c, "Failed to create exchange account", err)
 		return
 	}
 
 	logger.Infof("✓ Created exchange account: type=%s, name=%s, id=%s", req.ExchangeType, req.AccountName, id)
+	keyStatus := ""
+	if req.APIKey != "" || req.AsterPrivateKey != "" || req.LighterAPIKeyPrivateKey != "" {
+		s.verifyExchangeKeyAsync(userID, id)
+		keyStatus = ExchangeKeyPending
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Exchange account created",
-		"id":      id,
+		"message":                 "Exchange account created",
+		"id":                      id,
+		"key_verification_status": keyStatus,
 	})
 }
 
@@ -2107,6 +2536,9 @@ func (s *Server) handleTraderList(c *gin.Context) {
 			"exchange_id":         trader.ExchangeID,
 			"is_running":          isRunning,
 			"show_in_competition": trader.ShowInCompetition,
+			"paper_mode":          trader.PaperMode,
+			"contract_type":       trader.ContractType,
+			"margin_coin":         trader.MarginCoin,
 			"initial_balance":     trader.InitialBalance,
 			"strategy_id":         trader.StrategyID,
 			"strategy_name":       strategyName,
@@ -2153,12 +2585,17 @@ func (s *Server) handleGetTraderConfig(c *gin.Context) {
 		"strategy_id":           traderConfig.StrategyID,
 		"initial_balance":       traderConfig.InitialBalance,
 		"scan_interval_minutes": traderConfig.ScanIntervalMinutes,
+		"scan_interval_seconds": traderConfig.ScanIntervalSeconds,
 		"btc_eth_leverage":      traderConfig.BTCETHLeverage,
 		"altcoin_leverage":      traderConfig.AltcoinLeverage,
 		"trading_symbols":       traderConfig.TradingSymbols,
 		"custom_prompt":         traderConfig.CustomPrompt,
 		"override_base_prompt":  traderConfig.OverrideBasePrompt,
 		"is_cross_margin":       traderConfig.IsCrossMargin,
+		"paper_mode":            traderConfig.PaperMode,
+		"fallback_model_ids":    traderConfig.FallbackModelIDList(),
+		"contract_type":         traderConfig.ContractType,
+		"margin_coin":           traderConfig.MarginCoin,
 		"use_ai500":             traderConfig.UseAI500,
 		"use_oi_top":            traderConfig.UseOITop,
 		"is_running":            isRunning,
@@ -2483,43 +2920,36 @@ func (s *Server) handleKlines(c *gin.Context) {
 		limit = 1500
 	}
 
-	var klines []market.Kline
-	exchangeLower := strings.ToLower(exchange)
+	klines, source, err := s.fetchKlines(symbol, interval, exchange, limit)
+	if err != nil {
+		SafeInternalError(c, "Get klines from "+source, err)
+		return
+	}
 
+	c.JSON(http.StatusOK, klines)
+}
+
+// fetchKlines fetches klines from the data source of an exchange type, returning the source name for errors
+func (s *Server) fetchKlines(symbol, interval, exchange string, limit int) ([]market.Kline, string, error) {
 	// Route to appropriate data source based on exchange type
-	switch exchangeLower {
+	switch strings.ToLower(exchange) {
 	case "alpaca":
 		// US Stocks via Alpaca
-		klines, err = s.getKlinesFromAlpaca(symbol, interval, limit)
-		if err != nil {
-			SafeInternalError(c, "Get klines from Alpaca", err)
-			return
-		}
+		klines, err := s.getKlinesFromAlpaca(symbol, interval, limit)
+		return klines, "Alpaca", err
 	case "forex", "metals":
 		// Forex and Metals via Twelve Data
-		klines, err = s.getKlinesFromTwelveData(symbol, interval, limit)
-		if err != nil {
-			SafeInternalError(c, "Get klines from TwelveData", err)
-			return
-		}
+		klines, err := s.getKlinesFromTwelveData(symbol, interval, limit)
+		return klines, "TwelveData", err
 	case "hyperliquid", "hyperliquid-xyz", "xyz":
 		// Hyperliquid native API - supports both crypto perps and stock perps (xyz dex)
-		klines, err = s.getKlinesFromHyperliquid(symbol, interval, limit)
-		if err != nil {
-			SafeInternalError(c, "Get klines from Hyperliquid", err)
-			return
-		}
+		klines, err := s.getKlinesFromHyperliquid(symbol, interval, limit)
+		return klines, "Hyperliquid", err
 	default:
 		// Crypto exchanges via CoinAnk
-		symbol = market.Normalize(symbol)
-		klines, err = s.getKlinesFromCoinank(symbol, interval, exchange, limit)
-		if err != nil {
-			SafeInternalError(c, "Get klines from CoinAnk", err)
-			return
-		}
+		klines, err := s.getKlinesFromCoinank(market.Normalize(symbol), interval, exchange, limit)
+		return klines, "CoinAnk", err
 	}
-
-	c.JSON(http.StatusOK, klines)
 }
 
 // getKlinesFromCoinank fetches kline data from coinank free/open API for multiple exchanges
@@ -2916,6 +3346,8 @@ func (s *Server) handleStatistics(c *gin.Context) {
 		SafeInternalError(c, "Get statistics", err)
 		return
 	}
+	stats.ExchangeErrors = trader.ExchangeErrorCounts()
+	stats.IncomeSynced = trader.SyncsIncome()
 
 	c.JSON(http.StatusOK, stats)
 }
@@ -2947,10 +3379,18 @@ func (s *Server) handleEquityHistory(c *gin.Context) {
 		SafeBadRequest(c, "Invalid trader ID")
 		return
 	}
+	if _, ok := s.publicTraderAccess(c, traderID); !ok {
+		SafeNotFound(c, "Trader")
+		return
+	}
 
-	// Get equity historical data from new equity table
-	// Every 3 minutes per cycle: 10000 records = about 20 days of data
-	snapshots, err := s.store.Equity().GetLatest(traderID, 10000)
+	s.writeEquityHistory(c, traderID)
+}
+
+// writeEquityHistory responds with the return rate history of a trader the requester may see
+func (s *Server) writeEquityHistory(c *gin.Context, traderID string) {
+	// Get full equity history (recent per-minute and compacted hourly tiers), resampled to at most 10000 points
+	snapshots, err := s.store.Equity().GetSeries(traderID, time.Time{}, time.Time{}, 10000)
 	if err != nil {
 		SafeInternalError(c, "Get historical data", err)
 		return
@@ -2970,6 +3410,10 @@ func (s *Server) handleEquityHistory(c *gin.Context) {
 		TotalPnLPct      float64 `json:"total_pnl_pct"`     // Total PnL percentage
 		PositionCount    int     `json:"position_count"`    // Position count
 		MarginUsedPct    float64 `json:"margin_used_pct"`   // Margin used percentage
+		FundingPnL       float64 `json:"funding_pnl"`       // Cumulative funding since first point
+		Commission       float64 `json:"commission"`        // Cumulative commission since first point
+		NetTransfers     float64 `json:"net_transfers"`     // Cumulative deposits minus withdrawals since first point
+		ReturnPct        float64 `json:"return_pct"`        // Return with deposits/withdrawals excluded (time-weighted)
 	}
 
 	// Use the balance of the first record as initial balance to calculate return rate
@@ -2978,8 +3422,40 @@ func (s *Server) handleEquityHistory(c *gin.Context) {
 		initialBalance = 1 // Avoid division by zero
 	}
 
+	// Funding/commission records in time order, accumulated as snapshots advance
+	incomes, err := s.store.Income().List(traderID, snapshots[0].Timestamp.UnixMilli())
+	if err != nil {
+		logger.Infof("⚠️ Failed to load income history for %s: %v", traderID, err)
+	}
+	incomeIdx := 0
+	var cumFunding, cumCommission, cumTransfers float64
+
+	// Return rate excluding cash flows, measured against the trader's initial balance
+	returnBase := snapshots[0].TotalEquity
+	if trader, err := s.store.Trader().GetByID(traderID); err == nil && trader.InitialBalance > 0 {
+		returnBase = trader.InitialBalance
+	}
+	timestamps := make([]time.Time, len(snapshots))
+	equities := make([]float64, len(snapshots))
+	for i, snap := range snapshots {
+		timestamps[i], equities[i] = snap.Timestamp, snap.TotalEquity
+	}
+	returns := adjustedReturns(returnBase, unixMillis(timestamps), equities, s.traderTransfers(traderID))
+
 	var history []EquityPoint
-	for _, snap := range snapshots {
+	for i, snap := range snapshots {
+		for incomeIdx < len(incomes) && incomes[incomeIdx].Time <= snap.Timestamp.UnixMilli() {
+			switch incomes[incomeIdx].IncomeType {
+			case store.IncomeTypeFunding:
+				cumFunding += incomes[incomeIdx].Amount
+			case store.IncomeTypeCommission:
+				cumCommission += incomes[incomeIdx].Amount
+			case store.IncomeTypeTransfer:
+				cumTransfers += incomes[incomeIdx].Amount
+			}
+			incomeIdx++
+		}
+
 		// Calculate PnL percentage
 		totalPnLPct := 0.0
 		if initialBalance > 0 {
@@ -2994,6 +3470,10 @@ func (s *Server) handleEquityHistory(c *gin.Context) {
 			TotalPnLPct:      totalPnLPct,
 			PositionCount:    snap.PositionCount,
 			MarginUsedPct:    snap.MarginUsedPct,
+			FundingPnL:       cumFunding,
+			Commission:       cumCommission,
+			NetTransfers:     cumTransfers,
+			ReturnPct:        returns[i],
 		})
 	}
 
@@ -3003,7 +3483,7 @@ func (s *Server) handleEquityHistory(c *gin.Context) {
 // authMiddleware JWT authentication middleware
 func (s *Server) authMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
+		authHeader := authorizationHeader(c)
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing Authorization header"})
 			c.Abort()
@@ -3043,9 +3523,22 @@ func (s *Server) authMiddleware() gin.HandlerFunc {
 	}
 }
 
+// adminMiddleware restricts operator endpoints to emails listed in ADMIN_EMAILS
+// Must be chained after authMiddleware
+func (s *Server) adminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !config.Get().IsAdmin(c.GetString("email")) {
+			SafeForbidden(c, "Admin access required")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 // handleLogout Add current token to blacklist
 func (s *Server) handleLogout(c *gin.Context) {
-	authHeader := c.GetHeader("Authorization")
+	authHeader := authorizationHeader(c)
 	if authHeader == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing Authorization header"})
 		return
@@ -3068,13 +3561,14 @@ func (s *Server) handleLogout(c *gin.Context) {
 		exp = time.Now().Add(24 * time.Hour)
 	}
 	auth.BlacklistToken(tokenString, exp)
+	clearAuthCookie(c)
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
 }
 
 // handleRegister Handle user registration request
 func (s *Server) handleRegister(c *gin.Context) {
 	// Check if registration is allowed
-	if !config.Get().RegistrationEnabled {
+	if !s.settings.get().RegistrationEnabled {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Registration is disabled"})
 		return
 	}
@@ -3209,6 +3703,7 @@ func (s *Server) handleCompleteRegistration(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
+	setAuthCookie(c, token)
 
 	// Initialize default model and exchange configs for user
 	err = s.initUserDefaultConfigs(user.ID)
@@ -3216,11 +3711,18 @@ func (s *Server) handleCompleteRegistration(c *gin.Context) {
 		logger.Infof("Failed to initialize user default configs: %v", err)
 	}
 
+	// One-time recovery codes in case the authenticator is lost (shown only once)
+	recoveryCodes, err := s.issueRecoveryCodes(user.ID)
+	if err != nil {
+		logger.Warnf("⚠️ Failed to generate recovery codes for user %s: %v", user.Email, err)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"token":   token,
-		"user_id": user.ID,
-		"email":   user.Email,
-		"message": "Registration completed",
+		"token":          token,
+		"user_id":        user.ID,
+		"email":          user.Email,
+		"recovery_codes": recoveryCodes,
+		"message":        "Registration completed",
 	})
 }
 
@@ -3273,14 +3775,15 @@ func (s *Server) handleLogin(c *gin.Context) {
 	})
 }
 
-// handleVerifyOTP Verify OTP and complete login
+// handleVerifyOTP Verify OTP (or a recovery code) and complete login
 func (s *Server) handleVerifyOTP(c *gin.Context) {
 	var req struct {
-		UserID  string `json:"user_id" binding:"required"`
-		OTPCode string `json:"otp_code" binding:"required"`
+		UserID       string `json:"user_id" binding:"required"`
+		OTPCode      string `json:"otp_code"`
+		RecoveryCode string `json:"recovery_code"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil || (req.OTPCode == "" && req.RecoveryCode == "") {
 		SafeBadRequest(c, "Invalid request parameters")
 		return
 	}
@@ -3293,7 +3796,8 @@ func (s *Server) handleVerifyOTP(c *gin.Context) {
 	}
 
 	// Verify OTP
-	if !auth.VerifyOTP(user.OTPSecret, req.OTPCode) {
+	ok, usedRecovery := s.verifySecondFactor(user, req.OTPCode, req.RecoveryCode)
+	if !ok {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Verification code error"})
 		return
 	}
@@ -3304,40 +3808,59 @@ func (s *Server) handleVerifyOTP(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
+	setAuthCookie(c, token)
 
-	c.JSON(http.StatusOK, gin.H{
+	resp := gin.H{
 		"token":   token,
 		"user_id": user.ID,
 		"email":   user.Email,
 		"message": "Login successful",
-	})
+	}
+	if usedRecovery {
+		remaining, _ := s.store.RecoveryCode().CountUnused(user.ID)
+		resp["used_recovery_code"] = true
+		resp["recovery_codes_remaining"] = remaining
+	}
+	c.JSON(http.StatusOK, resp)
 }
 
-// handleResetPassword Reset password (via email + OTP verification)
+// handleResetPassword Reset password (via email + OTP or recovery code verification)
+// Failed attempts are limited per email and per client IP, and an unknown email gets the same
+// response as a wrong code so the endpoint cannot be used to probe registered addresses.
 func (s *Server) handleResetPassword(c *gin.Context) {
 	var req struct {
-		Email       string `json:"email" binding:"required,email"`
-		NewPassword string `json:"new_password" binding:"required,min=6"`
-		OTPCode     string `json:"otp_code" binding:"required"`
+		Email        string `json:"email" binding:"required,email"`
+		NewPassword  string `json:"new_password" binding:"required,min=6"`
+		OTPCode      string `json:"otp_code"`
+		RecoveryCode string `json:"recovery_code"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil || (req.OTPCode == "" && req.RecoveryCode == "") {
 		SafeBadRequest(c, "Invalid request parameters")
 		return
 	}
 
-	// Query user
-	user, err := s.store.User().GetByEmail(req.Email)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Email does not exist"})
+	emailKey := "email:" + strings.ToLower(strings.TrimSpace(req.Email))
+	ipKey := "ip:" + c.ClientIP()
+	if s.resetAttempts.locked(emailKey, ipKey) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many failed attempts, please try again later"})
 		return
 	}
 
-	// Verify OTP
-	if !auth.VerifyOTP(user.OTPSecret, req.OTPCode) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Google Authenticator code error"})
+	// Query user and verify OTP, both failures look the same to the caller
+	user, err := s.store.User().GetByEmail(req.Email)
+	if err == nil {
+		if ok, _ := s.verifySecondFactor(user, req.OTPCode, req.RecoveryCode); !ok {
+			err = errors.New("second factor mismatch")
+		}
+	}
+	if err != nil {
+		s.resetAttempts.fail(emailKey, ipKey)
+		logger.Warnf("⚠️ Password reset failed for %s from %s", req.Email, c.ClientIP())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Email or verification code is incorrect"})
 		return
 	}
+	s.resetAttempts.reset(emailKey)
 
 	// Generate new password hash
 	newPasswordHash, err := auth.HashPassword(req.NewPassword)
@@ -3389,6 +3912,7 @@ func (s *Server) handleGetSupportedExchanges(c *gin.Context) {
 		{ExchangeType: "binance", Name: "Binance Futures", Type: "cex"},
 		{ExchangeType: "bybit", Name: "Bybit Futures", Type: "cex"},
 		{ExchangeType: "okx", Name: "OKX Futures", Type: "cex"},
+		{ExchangeType: "bitget", Name: "Bitget Futures", Type: "cex"},
 		{ExchangeType: "gate", Name: "Gate.io Futures", Type: "cex"},
 		{ExchangeType: "kucoin", Name: "KuCoin Futures", Type: "cex"},
 		{ExchangeType: "hyperliquid", Name: "Hyperliquid", Type: "dex"},
@@ -3408,29 +3932,69 @@ func (s *Server) Start() error {
 	logger.Infof("🌐 API server starting at http://localhost%s", addr)
 	logger.Infof("📊 API Documentation:")
 	logger.Infof("  • GET  /api/health           - Health check")
+	logger.Infof("  • GET  /api/docs             - OpenAPI specification (Swagger UI, raw spec at /api/docs/openapi.json)")
+	logger.Infof("  • GET  /healthz              - Liveness probe")
+	logger.Infof("  • GET  /readyz               - Readiness probe (database, crypto, market data, AI provider)")
 	logger.Infof("  • GET  /api/traders          - Public AI trader leaderboard top 50 (no auth required)")
 	logger.Infof("  • GET  /api/competition      - Public competition data (no auth required)")
 	logger.Infof("  • GET  /api/top-traders      - Top 5 trader data (no auth required, for performance comparison)")
 	logger.Infof("  • GET  /api/equity-history?trader_id=xxx - Public return rate historical data (no auth required, for competition)")
 	logger.Infof("  • GET  /api/equity-history-batch?trader_ids=a,b,c - Batch get historical data (no auth required, performance comparison optimization)")
 	logger.Infof("  • GET  /api/traders/:id/public-config - Public trader config (no auth required, no sensitive info)")
+	logger.Infof("  • GET  /api/shared/:token/{status,decisions,equity-history} - Read-only shared trader dashboard (no auth required)")
 	logger.Infof("  • POST /api/traders          - Create new AI trader")
 	logger.Infof("  • DELETE /api/traders/:id    - Delete AI trader")
 	logger.Infof("  • POST /api/traders/:id/start - Start AI trader")
 	logger.Infof("  • POST /api/traders/:id/stop  - Stop AI trader")
+	logger.Infof("  • GET  /api/traders/:id/events - Trader event timeline (starts, stops, errors, config changes, decisions)")
+	logger.Infof("  • PUT  /api/traders/:id/copy  - Copy the executed decisions of a leader trader")
+	logger.Infof("  • POST /api/traders/:id/share-links - Create a revocable read-only share link")
+	logger.Infof("  • PUT  /api/traders/:id/decision-rules - Rules that veto or cap AI decisions before execution")
+	logger.Infof("  • PUT  /api/traders/:id/approval-mode - Hold large or low-confidence opens for manual approval")
+	logger.Infof("  • POST /api/traders/:id/pending-decisions/:decisionId/{approve,reject} - Decide a held decision before it expires")
+	logger.Infof("  • GET  /api/traders/:id/cycle-jobs - Decision cycle queue: recent, queued, late and failed cycles")
+	logger.Infof("  • GET  /api/traders/:id/logs - Trader log lines (history=true: stored, follow=true: live SSE stream)")
+	logger.Infof("  • GET  /api/traders/:id/chart?symbol=BTCUSDT&tf=5m - Klines with the trader's entries, exits and SL/TP levels")
+	logger.Infof("  • POST /api/user/{export,import} - Passphrase-encrypted archive of traders, strategies, models, exchanges and decisions")
 	logger.Infof("  • GET  /api/models           - Get AI model config")
+	logger.Infof("  • GET  /api/admin/ai-throttle - In-flight/queued AI calls per provider and queue wait times (admin)")
+	logger.Infof("  • GET  /api/admin/exchange-endpoints - API host latencies and selection per exchange (admin)")
+	logger.Infof("  • GET  /api/admin/equity-writer - Queued equity snapshots, batch inserts and backpressure waits (admin)")
+	logger.Infof("  • PUT  /api/admin/settings - Registration, default leverages, OI filter and notification defaults, applied live (admin)")
+	logger.Infof("  • PUT  /api/admin/ip-allowlist - CIDRs allowed to call the trading and admin APIs (admin)")
 	logger.Infof("  • PUT  /api/models           - Update AI model config")
 	logger.Infof("  • GET  /api/exchanges        - Get exchange config")
+	logger.Infof("  • GET  /api/exchanges/health - Exchange connectivity health (auth, IP whitelist, clock skew)")
+	logger.Infof("  • GET  /api/exchanges/:id/secrets/preview - Masked previews of stored exchange secrets")
+	logger.Infof("  • POST /api/exchanges/:id/verify - Re-check API key permissions (futures trading, no withdrawals)")
+	logger.Infof("  • POST /api/exchanges/:id/rotate-keys - Replace API keys, re-verify and resume dependent traders")
 	logger.Infof("  • PUT  /api/exchanges        - Update exchange config")
 	logger.Infof("  • GET  /api/status?trader_id=xxx     - Specified trader's system status")
 	logger.Infof("  • GET  /api/account?trader_id=xxx    - Specified trader's account info")
 	logger.Infof("  • GET  /api/positions?trader_id=xxx  - Specified trader's position list")
+	logger.Infof("  • GET  /api/portfolio        - Consolidated equity, netted exposure and PnL curve of all my traders")
 	logger.Infof("  • GET  /api/decisions?trader_id=xxx  - Specified trader's decision log")
 	logger.Infof("  • GET  /api/decisions/latest?trader_id=xxx - Specified trader's latest decisions")
+	logger.Infof("  • GET  /api/decisions/transcripts?trader_id=xxx - Export prompts and AI responses as JSONL")
+	logger.Infof("  • GET  /api/decisions/:id/trace?trader_id=xxx - Decision with its order/fill execution trace")
 	logger.Infof("  • GET  /api/statistics?trader_id=xxx - Specified trader's statistics")
+	logger.Infof("  • GET  /api/statistics/daily?trader_id=xxx&from=&to= - Daily trades, win rate, PnL, fees and drawdown in the user's timezone")
+	logger.Infof("  • GET  /api/symbols/restrictions - Delisting/non-trading symbols and exchange maintenance windows")
+	logger.Infof("  • GET  /api/market/{funding-history,oi-history}?symbol=BTCUSDT&hours=168 - Recorded funding rate / open interest for charting")
+	logger.Infof("  • GET  /api/market/providers - Market data provider health (CoinAnk, Binance, Bybit failover chain)")
+	logger.Infof("  • GET  /api/sentiment?metric=fear_greed|social&symbol=BTC&days=30 - Market sentiment history for charting")
 	logger.Infof("  • GET  /api/performance?trader_id=xxx - Specified trader's AI learning performance analysis")
+	logger.Infof("  • GET  /api/strategies/:id/export - Signed strategy bundle (prompt, indicators, risk settings) for other installations")
+	logger.Infof("  • POST /api/strategies/import - Verify and import a signed strategy bundle (GET /api/strategies/imports: provenance)")
+	logger.Infof("  • POST /api/strategies/grid/suggest - Grid bounds, count and leverage proposed from volatility/box data (optionally by AI)")
+	logger.Infof("  • GET  /api/prompt-templates - Built-in and user prompt templates (CRUD, GET /:id/versions: version history)")
+	logger.Infof("  • POST /api/backtest/sweeps  - Backtest parameter sweep (leverage, grid count, ATR multiplier...) ranked by Sharpe/drawdown")
+	logger.Infof("  • POST /api/backtest/walk-forward - Walk-forward validation: optimize on rolling train windows, score out of sample")
 	logger.Info()
 
+	// Periodic exchange connectivity checks (alerts before a trader's next cycle fails)
+	s.exchangeHealth.Start()
+
 	s.httpServer = &http.Server{
 		Addr:    addr,
 		Handler: s.router,
@@ -3438,13 +4002,17 @@ func (s *Server) Start() error {
 	return s.httpServer.ListenAndServe()
 }
 
-// Shutdown Gracefully shutdown server
-func (s *Server) Shutdown() error {
+// Handler HTTP handler of all API routes, for serving without Start (e.g. in-process tests)
+func (s *Server) Handler() http.Handler {
+	return s.router
+}
+
+// Shutdown Gracefully shutdown server, in-flight requests get until ctx is done to finish
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.exchangeHealth.Stop()
 	if s.httpServer == nil {
 		return nil
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
 	return s.httpServer.Shutdown(ctx)
 }
 
@@ -3573,6 +4141,9 @@ func (s *Server) handleEquityHistoryBatch(c *gin.Context) {
 		}
 	}
 
+	// Private traders are omitted (top traders above are already public)
+	requestBody.TraderIDs = s.filterPublicTraders(c, requestBody.TraderIDs)
+
 	// Limit to maximum 20 traders to prevent oversized requests
 	if len(requestBody.TraderIDs) > 20 {
 		requestBody.TraderIDs = requestBody.TraderIDs[:20]
@@ -3617,9 +4188,9 @@ func (s *Server) getEquityHistoryForTraders(traderIDs []string, hours int) map[s
 		var err error
 
 		if hours > 0 {
-			// Filter by time range
+			// Filter by time range (resampled so long ranges stay chartable)
 			startTime := now.Add(-time.Duration(hours) * time.Hour)
-			snapshots, err = s.store.Equity().GetByTimeRange(traderID, startTime, now)
+			snapshots, err = s.store.Equity().GetSeries(traderID, startTime, now, 2000)
 		} else {
 			// Default: get latest 500 records
 			snapshots, err = s.store.Equity().GetLatest(traderID, 500)
@@ -3637,23 +4208,20 @@ func (s *Server) getEquityHistoryForTraders(traderIDs []string, hours int) map[s
 			initialBalance = snapshots[0].TotalEquity
 		}
 
-		// Build return rate historical data with PnL percentage
+		// Build return rate historical data (PnL percentage filled in below, once the series is complete)
 		history := make([]map[string]interface{}, 0, len(snapshots)+1)
+		timestamps := make([]time.Time, 0, len(snapshots)+1)
+		equities := make([]float64, 0, len(snapshots)+1)
 		var lastSnapshotTime time.Time
 		for _, snap := range snapshots {
-			// Calculate PnL percentage: (current_equity - initial_balance) / initial_balance * 100
-			pnlPct := 0.0
-			if initialBalance > 0 {
-				pnlPct = (snap.TotalEquity - initialBalance) / initialBalance * 100
-			}
-
 			history = append(history, map[string]interface{}{
-				"timestamp":     snap.Timestamp,
-				"total_equity":  snap.TotalEquity,
-				"total_pnl":     snap.UnrealizedPnL,
-				"total_pnl_pct": pnlPct,
-				"balance":       snap.Balance,
+				"timestamp":    snap.Timestamp,
+				"total_equity": snap.TotalEquity,
+				"total_pnl":    snap.UnrealizedPnL,
+				"balance":      snap.Balance,
 			})
+			timestamps = append(timestamps, snap.Timestamp)
+			equities = append(equities, snap.TotalEquity)
 			if snap.Timestamp.After(lastSnapshotTime) {
 				lastSnapshotTime = snap.Timestamp
 			}
@@ -3677,22 +4245,24 @@ func (s *Server) getEquityHistoryForTraders(traderIDs []string, hours int) map[s
 					if v, ok := accountInfo["wallet_balance"].(float64); ok {
 						walletBalance = v
 					}
-					pnlPct := 0.0
-					if initialBalance > 0 {
-						pnlPct = (totalEquity - initialBalance) / initialBalance * 100
-					}
 
 					history = append(history, map[string]interface{}{
-						"timestamp":     now,
-						"total_equity":  totalEquity,
-						"total_pnl":     totalPnL,
-						"total_pnl_pct": pnlPct,
-						"balance":       walletBalance,
+						"timestamp":    now,
+						"total_equity": totalEquity,
+						"total_pnl":    totalPnL,
+						"balance":      walletBalance,
 					})
+					timestamps = append(timestamps, now)
+					equities = append(equities, totalEquity)
 				}
 			}
 		}
 
+		// PnL percentage with deposits/withdrawals excluded (time-weighted across transfers)
+		for i, pct := range adjustedReturns(initialBalance, unixMillis(timestamps), equities, s.traderTransfers(traderID)) {
+			history[i]["total_pnl_pct"] = pct
+		}
+
 		histories[traderID] = history
 	}
 
@@ -3718,6 +4288,11 @@ func (s *Server) handleGetPublicTraderConfig(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist"})
 		return
 	}
+	privacy, ok := s.publicTraderAccess(c, traderID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trader does not exist"})
+		return
+	}
 
 	// Get trader status information
 	status := trader.GetStatus()
@@ -3725,7 +4300,7 @@ func (s *Server) handleGetPublicTraderConfig(c *gin.Context) {
 	// Only return public configuration information, not including sensitive data like API keys
 	result := map[string]interface{}{
 		"trader_id":   trader.GetID(),
-		"trader_name": trader.GetName(),
+		"trader_name": privacy.DisplayName(trader.GetID(), trader.GetName()),
 		"ai_model":    trader.GetAIModel(),
 		"exchange":    trader.GetExchange(),
 		"is_running":  status["is_running"],
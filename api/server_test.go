@@ -2,6 +2,8 @@ package api
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"nofx/store"
@@ -303,3 +305,57 @@ func TestPublicTraderListResponse_SystemPromptTemplate(t *testing.T) {
 		t.Errorf("Expected system_prompt_template='default', got %v", response["system_prompt_template"])
 	}
 }
+
+// TestHandleCreateExchange_DuplicateAccountName a second account with a taken name is a conflict
+func TestHandleCreateExchange_DuplicateAccountName(t *testing.T) {
+	s, call := newTestServer(t)
+
+	create := func(body string) *httptest.ResponseRecorder {
+		return call(s.handleCreateExchange, testRequest{method: http.MethodPost, target: "/api/exchanges", body: body, userID: "u1"})
+	}
+
+	if w := create(`{"exchange_type":"binance","account_name":"Main"}`); w.Code != http.StatusOK {
+		t.Fatalf("first account: expected success, got %d %s", w.Code, w.Body)
+	}
+	if w := create(`{"exchange_type":"binance","account_name":"Main"}`); w.Code != http.StatusConflict {
+		t.Errorf("duplicate account name: expected 409, got %d %s", w.Code, w.Body)
+	}
+	if w := create(`{"exchange_type":"bybit","account_name":"Main"}`); w.Code != http.StatusOK {
+		t.Errorf("same name on another exchange type: expected success, got %d %s", w.Code, w.Body)
+	}
+}
+
+// TestHandleUpdateExchangeConfigs_RenameConflict a taken name is rejected before any credentials are written
+func TestHandleUpdateExchangeConfigs_RenameConflict(t *testing.T) {
+	s, call := newTestServer(t)
+	st := s.store
+
+	mainID, err := st.Exchange().Create("u1", "binance", "Main", true, "old-key", "old-secret", "", false, "", "", "", "", "", "", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherID, err := st.Exchange().Create("u1", "binance", "Other", true, "other-key", "other-secret", "", false, "", "", "", "", "", "", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{"exchanges":{"` + otherID + `":{"account_name":"Main","enabled":true,"api_key":"new-key","secret_key":"new-secret"}}}`
+	w := call(s.handleUpdateExchangeConfigs, testRequest{method: http.MethodPut, target: "/api/exchanges", body: body, userID: "u1"})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d %s", w.Code, w.Body)
+	}
+
+	other, err := st.Exchange().GetByID("u1", otherID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.AccountName != "Other" || string(other.APIKey) != "other-key" {
+		t.Errorf("rejected update must leave the account unchanged: %s %s", other.AccountName, other.APIKey)
+	}
+
+	// The unique index backs the check
+	dup := &store.Exchange{ID: "dup", ExchangeType: "binance", AccountName: "Main", UserID: "u1", Name: "Binance", Type: "cex"}
+	if err := st.GormDB().Create(dup).Error; err == nil {
+		t.Errorf("duplicate of %s was inserted despite the unique index", mainID)
+	}
+}
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"nofx/auth"
+	"nofx/logger"
+	"nofx/store"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxShareLinkDays is the longest expiry a share link can be created with
+const maxShareLinkDays = 365
+
+// shareLinkResponse share link with its public token
+type shareLinkResponse struct {
+	*store.ShareLink
+	Token  string `json:"token"`
+	Active bool   `json:"active"`
+}
+
+// signShareToken builds the public token of a share link: "<link id>.<HMAC-SHA256 signature>"
+func signShareToken(secret []byte, linkID string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("share-link:" + linkID))
+	return linkID + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyShareToken checks the signature of a share token and returns its link ID
+func verifyShareToken(secret []byte, token string) (string, error) {
+	linkID, _, ok := strings.Cut(token, ".")
+	if !ok || linkID == "" {
+		return "", errors.New("malformed share token")
+	}
+	if !hmac.Equal([]byte(signShareToken(secret, linkID)), []byte(token)) {
+		return "", errors.New("invalid share token signature")
+	}
+	return linkID, nil
+}
+
+func newShareLinkResponse(link *store.ShareLink) shareLinkResponse {
+	return shareLinkResponse{
+		ShareLink: link,
+		Token:     signShareToken(auth.JWTSecret, link.ID),
+		Active:    link.Active(time.Now()),
+	}
+}
+
+// handleListShareLinks List the share links of a trader
+func (s *Server) handleListShareLinks(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	links, err := s.store.ShareLink().ListByTrader(userID, traderID)
+	if err != nil {
+		SafeInternalError(c, "List share links", err)
+		return
+	}
+
+	result := make([]shareLinkResponse, 0, len(links))
+	for _, link := range links {
+		result = append(result, newShareLinkResponse(link))
+	}
+	c.JSON(http.StatusOK, gin.H{"links": result})
+}
+
+// handleCreateShareLink Create a revocable read-only share link for a trader's dashboard
+func (s *Server) handleCreateShareLink(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req struct {
+		Label         string `json:"label"`
+		RedactPrompts *bool  `json:"redact_prompts"`  // Defaults to true
+		ExpiresInDays int    `json:"expires_in_days"` // 0 = never expires
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > maxShareLinkDays {
+		SafeBadRequest(c, fmt.Sprintf("expires_in_days must be between 0 and %d", maxShareLinkDays))
+		return
+	}
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	link := &store.ShareLink{
+		ID:            uuid.New().String(),
+		TraderID:      traderID,
+		UserID:        userID,
+		Label:         strings.TrimSpace(req.Label),
+		RedactPrompts: req.RedactPrompts == nil || *req.RedactPrompts,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		link.ExpiresAt = &expiresAt
+	}
+	if err := s.store.ShareLink().Create(link); err != nil {
+		SafeInternalError(c, "Create share link", err)
+		return
+	}
+
+	logger.Infof("✓ Share link %s created for trader %s (redact_prompts=%v)", link.ID, traderID, link.RedactPrompts)
+	s.recordTraderEvent(traderID, store.TraderEventConfigChange, "Read-only share link created", map[string]interface{}{
+		"share_link_id":  link.ID,
+		"label":          link.Label,
+		"redact_prompts": link.RedactPrompts,
+		"expires_at":     link.ExpiresAt,
+	})
+	c.JSON(http.StatusOK, newShareLinkResponse(link))
+}
+
+// handleRevokeShareLink Revoke a share link, its token stops working immediately
+func (s *Server) handleRevokeShareLink(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+	linkID := c.Param("linkId")
+
+	if err := s.store.ShareLink().Revoke(userID, traderID, linkID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			SafeNotFound(c, "Share link")
+			return
+		}
+		SafeInternalError(c, "Revoke share link", err)
+		return
+	}
+
+	s.recordTraderEvent(traderID, store.TraderEventConfigChange, "Read-only share link revoked", map[string]interface{}{
+		"share_link_id": linkID,
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Share link revoked"})
+}
+
+// shareLinkMiddleware resolves the share token in the path, rejecting invalid, revoked and expired links
+// Every failure answers 404 so the endpoints do not reveal whether a link ever existed.
+// Addresses presenting too many bad tokens are locked out before any lookup.
+func (s *Server) shareLinkMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ipKey := "ip:" + c.ClientIP()
+		if s.shareAttempts.locked(ipKey) {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many invalid share links, please try again later"})
+			return
+		}
+		linkID, err := verifyShareToken(auth.JWTSecret, c.Param("token"))
+		if err != nil {
+			s.shareAttempts.fail(ipKey)
+			SafeNotFound(c, "Share link")
+			c.Abort()
+			return
+		}
+		link, err := s.store.ShareLink().Get(linkID)
+		if err != nil || link == nil || !link.Active(time.Now()) {
+			s.shareAttempts.fail(ipKey)
+			SafeNotFound(c, "Share link")
+			c.Abort()
+			return
+		}
+		c.Set("share_link", link)
+		c.Next()
+	}
+}
+
+// sharedLink returns the share link resolved by shareLinkMiddleware
+func sharedLink(c *gin.Context) *store.ShareLink {
+	return c.MustGet("share_link").(*store.ShareLink)
+}
+
+// handleSharedStatus Read-only status of a shared trader
+func (s *Server) handleSharedStatus(c *gin.Context) {
+	link := sharedLink(c)
+
+	// Only traders already running in memory, anonymous hits must not load the owner's traders
+	trader, err := s.traderManager.GetTrader(link.TraderID)
+	if err != nil || !trader.IsRunning() {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	c.JSON(http.StatusOK, trader.GetStatus())
+}
+
+// handleSharedDecisions Latest decisions of a shared trader (newest first, supports limit parameter)
+func (s *Server) handleSharedDecisions(c *gin.Context) {
+	link := sharedLink(c)
+
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+			if limit > 100 {
+				limit = 100 // Max 100 to prevent abuse
+			}
+		}
+	}
+
+	records, err := s.store.Decision().GetLatestRecords(link.TraderID, limit)
+	if err != nil {
+		SafeInternalError(c, "Get decision log", err)
+		return
+	}
+
+	// GetLatestRecords returns oldest to newest, the shared list shows newest first
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+	if link.RedactPrompts {
+		for _, record := range records {
+			redactDecisionPrompts(record)
+		}
+	}
+
+	c.JSON(http.StatusOK, records)
+}
+
+// handleSharedEquityHistory Equity history of a shared trader
+func (s *Server) handleSharedEquityHistory(c *gin.Context) {
+	s.writeEquityHistory(c, sharedLink(c).TraderID)
+}
+
+// redactDecisionPrompts blanks the prompts and raw AI output of a decision record
+// Decisions, their reasoning and execution results stay visible.
+func redactDecisionPrompts(record *store.DecisionRecord) {
+	record.SystemPrompt = ""
+	record.InputPrompt = ""
+	record.CoTTrace = ""
+	record.RawResponse = ""
+}
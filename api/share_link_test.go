@@ -0,0 +1,112 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"nofx/auth"
+	"nofx/manager"
+	"nofx/store"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestShareToken_SignAndVerify(t *testing.T) {
+	secret := []byte("test-secret")
+	token := signShareToken(secret, "link-1")
+
+	linkID, err := verifyShareToken(secret, token)
+	if err != nil || linkID != "link-1" {
+		t.Fatalf("expected link-1, got %q (%v)", linkID, err)
+	}
+
+	// A different secret, a tampered link ID or a missing signature must be rejected
+	if _, err := verifyShareToken([]byte("other-secret"), token); err == nil {
+		t.Error("token signed with another secret should be rejected")
+	}
+	_, signature, _ := strings.Cut(token, ".")
+	if _, err := verifyShareToken(secret, "link-2."+signature); err == nil {
+		t.Error("token with a swapped link ID should be rejected")
+	}
+	for _, malformed := range []string{"", "link-1", ".abc"} {
+		if _, err := verifyShareToken(secret, malformed); err == nil {
+			t.Errorf("malformed token %q should be rejected", malformed)
+		}
+	}
+}
+
+func TestShareLinkActive(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	if !(&store.ShareLink{}).Active(now) || !(&store.ShareLink{ExpiresAt: &future}).Active(now) {
+		t.Error("links without expiry or expiring later should be active")
+	}
+	if (&store.ShareLink{ExpiresAt: &past}).Active(now) {
+		t.Error("expired link should not be active")
+	}
+	if (&store.ShareLink{RevokedAt: &past, ExpiresAt: &future}).Active(now) {
+		t.Error("revoked link should not be active")
+	}
+}
+
+func TestRedactDecisionPrompts(t *testing.T) {
+	record := &store.DecisionRecord{
+		SystemPrompt: "system",
+		InputPrompt:  "input",
+		CoTTrace:     "thinking",
+		RawResponse:  "raw",
+		Decisions:    []store.DecisionAction{{Symbol: "BTCUSDT", Action: "open_long", Reasoning: "breakout"}},
+	}
+	redactDecisionPrompts(record)
+
+	if record.SystemPrompt != "" || record.InputPrompt != "" || record.CoTTrace != "" || record.RawResponse != "" {
+		t.Errorf("prompts should be blanked: %+v", record)
+	}
+	if len(record.Decisions) != 1 || record.Decisions[0].Reasoning != "breakout" {
+		t.Error("decisions should stay visible")
+	}
+}
+
+func TestSharedStatus(t *testing.T) {
+	s, _ := newTestServer(t)
+	s.traderManager = manager.NewTraderManager()
+	if err := s.store.Trader().Create(&store.Trader{ID: "t1", UserID: "alice", Name: "Alpha", IsRunning: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.store.ShareLink().Create(&store.ShareLink{ID: "link-1", TraderID: "t1", UserID: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	router.GET("/shared/:token/status", s.shareLinkMiddleware(), s.handleSharedStatus)
+	get := func(token, ip string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/shared/"+token+"/status", nil)
+		req.RemoteAddr = ip + ":12345"
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// The trader is not loaded in memory: the anonymous hit must not load it
+	if code := get(signShareToken(auth.JWTSecret, "link-1"), "10.0.0.1"); code != http.StatusNotFound {
+		t.Errorf("trader not running: expected 404, got %d", code)
+	}
+	if _, err := s.traderManager.GetTrader("t1"); err == nil {
+		t.Error("shared status must not load the owner's traders")
+	}
+
+	for i := 0; i < maxFailedAttempts; i++ {
+		if code := get(signShareToken([]byte("guess"), "link-1"), "10.0.0.2"); code != http.StatusNotFound {
+			t.Fatalf("bad token %d: expected 404, got %d", i+1, code)
+		}
+	}
+	if code := get(signShareToken(auth.JWTSecret, "link-1"), "10.0.0.2"); code != http.StatusTooManyRequests {
+		t.Errorf("address guessing tokens should be locked out, got %d", code)
+	}
+	if code := get(signShareToken(auth.JWTSecret, "link-1"), "10.0.0.1"); code != http.StatusNotFound {
+		t.Errorf("other addresses are not locked out, got %d", code)
+	}
+}
@@ -9,12 +9,16 @@ import (
 	"nofx/market"
 	"nofx/mcp"
 	"nofx/store"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// minPromptTokenBudget budgets below this leave little room besides the system prompt
+const minPromptTokenBudget = 4000
+
 // validateStrategyConfig validates strategy configuration and returns warnings
 func validateStrategyConfig(config *store.StrategyConfig) []string {
 	var warnings []string
@@ -26,6 +30,35 @@ func validateStrategyConfig(config *store.StrategyConfig) []string {
 		warnings = append(warnings, "NofxOS API key is not configured. NofxOS data sources may not work properly.")
 	}
 
+	// Prompt templates are localized for supported languages only; others fall back to auto-detection
+	if config.Language != "" {
+		if _, ok := kernel.ParseLanguage(config.Language); !ok {
+			warnings = append(warnings, "Unsupported strategy language \""+config.Language+"\". Supported: zh, en. Prompt language will be detected from the role definition.")
+		}
+	}
+
+	if budget := config.PromptBudget.MaxPromptTokens; budget > 0 && budget < minPromptTokenBudget {
+		warnings = append(warnings, fmt.Sprintf("Prompt token budget %d is very low, most market context will be trimmed from the prompt.", budget))
+	}
+
+	switch config.Execution.Algo {
+	case "", store.ExecutionMarket, store.ExecutionTWAP, store.ExecutionIceberg:
+	default:
+		warnings = append(warnings, "Unsupported execution algorithm \""+config.Execution.Algo+"\". Supported: market, twap, iceberg. Entries will be opened with one market order.")
+	}
+
+	for _, name := range config.Indicators.MarketDataProviders {
+		if !market.IsProvider(name) {
+			warnings = append(warnings, fmt.Sprintf("Unknown market data provider %q, supported: %s. It will be ignored.", name, strings.Join(market.Providers().Names(), ", ")))
+		}
+	}
+
+	for _, w := range config.Indicators.Klines.TimeframeWeights {
+		if _, err := market.NormalizeTimeframe(w.Timeframe); err != nil {
+			warnings = append(warnings, fmt.Sprintf("Multi-timeframe summary: %v, it will be skipped.", err))
+		}
+	}
+
 	return warnings
 }
 
@@ -154,6 +187,9 @@ func (s *Server) handleCreateStrategy(c *gin.Context) {
 		SafeBadRequest(c, "Invalid request parameters")
 		return
 	}
+	if !s.resolveStrategyPromptTemplate(c, &req.Config) || !s.checkStrategyCoinPool(c, &req.Config) {
+		return
+	}
 
 	// Serialize configuration
 	configJSON, err := json.Marshal(req.Config)
@@ -224,6 +260,9 @@ func (s *Server) handleUpdateStrategy(c *gin.Context) {
 		SafeBadRequest(c, "Invalid request parameters")
 		return
 	}
+	if !s.resolveStrategyPromptTemplate(c, &req.Config) || !s.checkStrategyCoinPool(c, &req.Config) {
+		return
+	}
 
 	// Serialize configuration
 	configJSON, err := json.Marshal(req.Config)
@@ -395,6 +434,10 @@ func (s *Server) handlePreviewPrompt(c *gin.Context) {
 		req.PromptVariant = "balanced"
 	}
 
+	if !s.resolveStrategyPromptTemplate(c, &req.Config) {
+		return
+	}
+
 	// Create strategy engine to build prompt
 	engine := kernel.NewStrategyEngine(&req.Config)
 
@@ -417,28 +460,33 @@ func (s *Server) handlePreviewPrompt(c *gin.Context) {
 	})
 }
 
-// handleStrategyTestRun AI test run (does not execute trades, only returns AI analysis results)
-func (s *Server) handleStrategyTestRun(c *gin.Context) {
-	userID := c.GetString("user_id")
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
+// strategyTestRunRequest request body of the strategy test run endpoints
+type strategyTestRunRequest struct {
+	Config        store.StrategyConfig `json:"config" binding:"required"`
+	PromptVariant string               `json:"prompt_variant"`
+	AIModelID     string               `json:"ai_model_id"`
+	RunRealAI     bool                 `json:"run_real_ai"`
+	AsOf          *time.Time           `json:"as_of,omitempty"` // Replay the run at this past time (RFC 3339), empty = now
+}
 
-	var req struct {
-		Config        store.StrategyConfig `json:"config" binding:"required"`
-		PromptVariant string               `json:"prompt_variant"`
-		AIModelID     string               `json:"ai_model_id"`
-		RunRealAI     bool                 `json:"run_real_ai"`
-	}
+// strategyTestRun prompts built by a strategy test run
+type strategyTestRun struct {
+	Engine       *kernel.StrategyEngine
+	SystemPrompt string
+	UserPrompt   string
+	Candidates   []kernel.CandidateCoin
+	Notes        []string // What a historical run could not reconstruct
+}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		SafeBadRequest(c, "Invalid request parameters")
-		return
-	}
+// testRunProgress receives progress events of a strategy test run
+type testRunProgress func(event string, data any)
 
-	if req.PromptVariant == "" {
-		req.PromptVariant = "balanced"
+// buildStrategyTestRun fetches real market data and builds the prompts of a test run
+// progress (may be nil) is notified as candidates are selected and market data is fetched.
+// With AsOf set, the market data is rebuilt from the klines and recorded open interest of that time.
+func (s *Server) buildStrategyTestRun(req *strategyTestRunRequest, progress testRunProgress) (*strategyTestRun, error) {
+	if progress == nil {
+		progress = func(string, any) {}
 	}
 
 	// Create strategy engine to build prompt
@@ -447,13 +495,13 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 	// Get candidate coins
 	candidates, err := engine.GetCandidateCoins()
 	if err != nil {
-		logger.Errorf("[API Error] Failed to get candidate coins: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":       "Failed to get candidate coins",
-			"ai_response": "",
-		})
-		return
+		return nil, fmt.Errorf("failed to get candidate coins: %w", err)
 	}
+	symbols := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		symbols = append(symbols, c.Symbol)
+	}
+	progress("candidates", gin.H{"count": len(candidates), "symbols": symbols})
 
 	// Get timeframe configuration
 	timeframes := req.Config.Indicators.Klines.SelectedTimeframes
@@ -478,40 +526,46 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 	if klineCount <= 0 {
 		klineCount = 30
 	}
+	timeframes = engine.WithSummaryTimeframes(timeframes)
 
 	fmt.Printf("📊 Using timeframes: %v, primary: %s, kline count: %d\n", timeframes, primaryTimeframe, klineCount)
 
+	var notes []string
+	if req.AsOf != nil && req.Config.CoinSource.SourceType != "static" {
+		notes = append(notes, fmt.Sprintf("Candidate coins come from the current %s list, not the one at that time", req.Config.CoinSource.SourceType))
+	}
+
 	// Get real market data (using multiple timeframes)
 	marketDataMap := make(map[string]*market.Data)
-	for _, coin := range candidates {
-		data, err := market.GetWithTimeframes(coin.Symbol, timeframes, primaryTimeframe, klineCount)
+	var noDerivatives []string
+	for i, coin := range candidates {
+		var data *market.Data
+		var err error
+		if req.AsOf != nil {
+			data, err = market.GetWithTimeframesAt(coin.Symbol, timeframes, primaryTimeframe, klineCount, *req.AsOf)
+			if err == nil && !s.applyDerivativesHistory(data, *req.AsOf) {
+				noDerivatives = append(noDerivatives, coin.Symbol)
+			}
+		} else {
+			data, err = market.GetWithTimeframesFrom(coin.Symbol, timeframes, primaryTimeframe, klineCount, req.Config.Indicators.MarketDataProviders)
+		}
 		if err != nil {
 			// If getting data for a coin fails, log but continue
 			fmt.Printf("⚠️  Failed to get market data for %s: %v\n", coin.Symbol, err)
+			progress("market_data", gin.H{"symbol": coin.Symbol, "ok": false, "error": err.Error(), "done": i + 1, "total": len(candidates)})
 			continue
 		}
 		marketDataMap[coin.Symbol] = data
+		progress("market_data", gin.H{"symbol": coin.Symbol, "ok": true, "done": i + 1, "total": len(candidates)})
 	}
 
-	// Fetch quantitative data for each candidate coin
-	symbols := make([]string, 0, len(candidates))
-	for _, c := range candidates {
-		symbols = append(symbols, c.Symbol)
-	}
-	quantDataMap := engine.FetchQuantDataBatch(symbols)
-
-	// Fetch OI ranking data (market-wide position changes)
-	oiRankingData := engine.FetchOIRankingData()
-
-	// Fetch NetFlow ranking data (market-wide fund flow)
-	netFlowRankingData := engine.FetchNetFlowRankingData()
-
-	// Fetch Price ranking data (market-wide gainers/losers)
-	priceRankingData := engine.FetchPriceRankingData()
-
 	// Build real context (for generating User Prompt)
+	now := time.Now()
+	if req.AsOf != nil {
+		now = *req.AsOf
+	}
 	testContext := &kernel.Context{
-		CurrentTime:    time.Now().UTC().Format("2006-01-02 15:04:05 UTC"),
+		CurrentTime:    now.UTC().Format("2006-01-02 15:04:05 UTC"),
 		RuntimeMinutes: 0,
 		CallCount:      1,
 		Account: kernel.AccountInfo{
@@ -524,14 +578,30 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 			MarginUsedPct:    0,
 			PositionCount:    0,
 		},
-		Positions:          []kernel.PositionInfo{},
-		CandidateCoins:     candidates,
-		PromptVariant:      req.PromptVariant,
-		MarketDataMap:      marketDataMap,
-		QuantDataMap:       quantDataMap,
-		OIRankingData:      oiRankingData,
-		NetFlowRankingData: netFlowRankingData,
-		PriceRankingData:   priceRankingData,
+		Positions:      []kernel.PositionInfo{},
+		CandidateCoins: candidates,
+		PromptVariant:  req.PromptVariant,
+		MarketDataMap:  marketDataMap,
+	}
+
+	if req.AsOf == nil {
+		// Fetch quantitative data for each candidate coin
+		testContext.QuantDataMap = engine.FetchQuantDataBatch(symbols)
+
+		// Fetch OI ranking data (market-wide position changes)
+		testContext.OIRankingData = engine.FetchOIRankingData()
+
+		// Fetch NetFlow ranking data (market-wide fund flow)
+		testContext.NetFlowRankingData = engine.FetchNetFlowRankingData()
+
+		// Fetch Price ranking data (market-wide gainers/losers)
+		testContext.PriceRankingData = engine.FetchPriceRankingData()
+	} else {
+		// Only available live
+		notes = append(notes, "Quant data and the OI, NetFlow and price rankings only exist live and were left out")
+		if len(noDerivatives) > 0 {
+			notes = append(notes, "No open interest or funding rate was recorded at that time for "+strings.Join(noDerivatives, ", "))
+		}
 	}
 
 	// Build System Prompt
@@ -540,6 +610,51 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 	// Build User Prompt (using real market data)
 	userPrompt := engine.BuildUserPrompt(testContext)
 
+	return &strategyTestRun{
+		Engine:       engine,
+		SystemPrompt: systemPrompt,
+		UserPrompt:   userPrompt,
+		Candidates:   candidates,
+		Notes:        notes,
+	}, nil
+}
+
+// handleStrategyTestRun AI test run (does not execute trades, only returns AI analysis results)
+func (s *Server) handleStrategyTestRun(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req strategyTestRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	if req.PromptVariant == "" {
+		req.PromptVariant = "balanced"
+	}
+	if err := validateTestRunAsOf(req.AsOf, time.Now()); err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+	if !s.resolveStrategyPromptTemplate(c, &req.Config) {
+		return
+	}
+
+	run, err := s.buildStrategyTestRun(&req, nil)
+	if err != nil {
+		logger.Errorf("[API Error] Failed to get candidate coins: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":       "Failed to get candidate coins",
+			"ai_response": "",
+		})
+		return
+	}
+	systemPrompt, userPrompt, candidates := run.SystemPrompt, run.UserPrompt, run.Candidates
+
 	// If requesting real AI call
 	if req.RunRealAI && req.AIModelID != "" {
 		aiResponse, aiErr := s.runRealAITest(userID, req.AIModelID, systemPrompt, userPrompt)
@@ -550,6 +665,8 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 				"candidate_count": len(candidates),
 				"candidates":      candidates,
 				"prompt_variant":  req.PromptVariant,
+				"as_of":           req.AsOf,
+				"notes":           run.Notes,
 				"ai_response":     fmt.Sprintf("❌ AI call failed: %s", aiErr.Error()),
 				"ai_error":        aiErr.Error(),
 				"note":            "AI call error",
@@ -563,6 +680,8 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 			"candidate_count": len(candidates),
 			"candidates":      candidates,
 			"prompt_variant":  req.PromptVariant,
+			"as_of":           req.AsOf,
+			"notes":           run.Notes,
 			"ai_response":     aiResponse,
 			"note":            "✅ Real AI test run successful",
 		})
@@ -576,6 +695,8 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 		"candidate_count": len(candidates),
 		"candidates":      candidates,
 		"prompt_variant":  req.PromptVariant,
+		"as_of":           req.AsOf,
+		"notes":           run.Notes,
 		"ai_response":     "Please select an AI model and click 'Run Test' to perform real AI analysis.",
 		"note":            "AI model not selected or real AI call not enabled",
 	})
@@ -583,18 +704,34 @@ func (s *Server) handleStrategyTestRun(c *gin.Context) {
 
 // runRealAITest Execute real AI test call
 func (s *Server) runRealAITest(userID, modelID, systemPrompt, userPrompt string) (string, error) {
+	aiClient, err := s.testRunAIClient(userID, modelID)
+	if err != nil {
+		return "", err
+	}
+
+	// Call AI API
+	response, err := aiClient.CallWithMessages(systemPrompt, userPrompt)
+	if err != nil {
+		return "", fmt.Errorf("AI API call failed: %w", err)
+	}
+
+	return response, nil
+}
+
+// testRunAIClient creates the AI client of a user's model for test runs
+func (s *Server) testRunAIClient(userID, modelID string) (mcp.AIClient, error) {
 	// Get AI model configuration
 	model, err := s.store.AIModel().Get(userID, modelID)
 	if err != nil {
-		return "", fmt.Errorf("failed to get AI model: %w", err)
+		return nil, fmt.Errorf("failed to get AI model: %w", err)
 	}
 
 	if !model.Enabled {
-		return "", fmt.Errorf("AI model %s is not enabled", model.Name)
+		return nil, fmt.Errorf("AI model %s is not enabled", model.Name)
 	}
 
 	if model.APIKey == "" {
-		return "", fmt.Errorf("AI model %s is missing API Key", model.Name)
+		return nil, fmt.Errorf("AI model %s is missing API Key", model.Name)
 	}
 
 	// Create AI client
@@ -631,12 +768,6 @@ func (s *Server) runRealAITest(userID, modelID, systemPrompt, userPrompt string)
 		aiClient.SetAPIKey(apiKey, model.CustomAPIURL, model.CustomModelName)
 	}
 
-	// Call AI API
-	response, err := aiClient.CallWithMessages(systemPrompt, userPrompt)
-	if err != nil {
-		return "", fmt.Errorf("AI API call failed: %w", err)
-	}
-
-	return response, nil
+	return aiClient, nil
 }
 
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"nofx/config"
+	"nofx/logger"
+	"nofx/store"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportStrategyBundleRequest Import strategy from a signed bundle
+type ImportStrategyBundleRequest struct {
+	Bundle *store.StrategyBundle `json:"bundle" binding:"required"`
+	Name   string                `json:"name"` // Empty = bundle name
+}
+
+// localBundleSigner base64 public key of this installation's bundle signing key
+func (s *Server) localBundleSigner() (ed25519.PrivateKey, string, error) {
+	key, err := s.store.StrategyBundleSigningKey()
+	if err != nil {
+		return nil, "", err
+	}
+	return key, base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)), nil
+}
+
+// markTrustedImports sets Trusted on imports signed by this installation or a pinned signer
+func markTrustedImports(localSigner string, imports ...*store.StrategyImport) {
+	trusted := append([]string{localSigner}, config.Get().StrategyBundleTrustedSigners...)
+	for _, record := range imports {
+		record.Trusted = store.TrustedSigner(record.SignerKey, trusted)
+	}
+}
+
+// handleExportStrategyBundle Export strategy as a signed bundle
+func (s *Server) handleExportStrategyBundle(c *gin.Context) {
+	userID := c.GetString("user_id")
+	strategyID := c.Param("id")
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	bundle, err := s.store.Strategy().ExportBundle(userID, strategyID, strings.TrimSpace(c.Query("version")))
+	if err != nil {
+		SafeNotFound(c, "Strategy")
+		return
+	}
+	key, _, err := s.localBundleSigner()
+	if err != nil {
+		SafeInternalError(c, "Load bundle signing key", err)
+		return
+	}
+	if err := bundle.Sign(key); err != nil {
+		SafeInternalError(c, "Sign strategy bundle", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"strategy_%s.json\"", strategyID))
+	c.JSON(http.StatusOK, bundle)
+}
+
+// handleImportStrategyBundle Verify a signed bundle and create it as a new strategy
+func (s *Server) handleImportStrategyBundle(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req ImportStrategyBundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if _, err := req.Bundle.Verify(); err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+	_, localSigner, err := s.localBundleSigner()
+	if err != nil {
+		SafeInternalError(c, "Load bundle signing key", err)
+		return
+	}
+
+	strategy, record, err := s.store.Strategy().ImportBundle(userID, req.Bundle, strings.TrimSpace(req.Name), localSigner)
+	if err != nil {
+		SafeInternalError(c, "Failed to import strategy", err)
+		return
+	}
+
+	markTrustedImports(localSigner, record)
+
+	logger.Infof("✓ Strategy imported: %s (bundle: %s %s, signer: %s, trusted: %v)", strategy.ID, record.BundleName, record.StrategyVersion, record.SignerFingerprint, record.Trusted)
+	warning := ""
+	if !record.Trusted {
+		warning = "Bundle signed by an unknown key: it is unchanged since signing, but its author is not verified"
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"id":         strategy.ID,
+		"name":       strategy.Name,
+		"verified":   record.Trusted,
+		"warning":    warning,
+		"provenance": record,
+	})
+}
+
+// handleStrategyImports List imported bundles with their provenance
+func (s *Server) handleStrategyImports(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	imports, err := s.store.Strategy().ListImports(userID)
+	if err != nil {
+		SafeInternalError(c, "List strategy imports", err)
+		return
+	}
+	_, localSigner, err := s.localBundleSigner()
+	if err != nil {
+		SafeInternalError(c, "Load bundle signing key", err)
+		return
+	}
+	markTrustedImports(localSigner, imports...)
+	c.JSON(http.StatusOK, gin.H{"imports": imports})
+}
+
+// handleStrategyBundleSigner Get this installation's bundle signer, for verifying its bundles out of band
+func (s *Server) handleStrategyBundleSigner(c *gin.Context) {
+	_, signer, err := s.localBundleSigner()
+	if err != nil {
+		SafeInternalError(c, "Load bundle signing key", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"signer":      signer,
+		"fingerprint": store.SignerFingerprint(signer),
+	})
+}
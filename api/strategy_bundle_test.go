@@ -0,0 +1,131 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"nofx/store"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStrategyBundle_SignAndVerify(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := store.GetDefaultStrategyConfig("en")
+	configJSON, _ := json.Marshal(cfg)
+	bundle := &store.StrategyBundle{
+		Version:         store.StrategyBundleVersion,
+		Name:            "Trend",
+		StrategyVersion: "1.0.0",
+		ExportedAt:      time.Now().UTC().Truncate(time.Second),
+		Config:          configJSON,
+	}
+	if err := bundle.Sign(key); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	// A bundle shared as a file must still verify after a JSON round trip
+	data, _ := json.MarshalIndent(bundle, "", "  ")
+	var shared store.StrategyBundle
+	if err := json.Unmarshal(data, &shared); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := shared.Verify()
+	if err != nil || len(hash) != 64 {
+		t.Fatalf("expected valid bundle, got hash %q (%v)", hash, err)
+	}
+
+	tampered := shared
+	tampered.Config = json.RawMessage(strings.Replace(string(shared.Config), `"max_positions":`, `"max_positions":9`, 1))
+	if _, err := tampered.Verify(); err == nil {
+		t.Error("bundle with modified risk settings should be rejected")
+	}
+	// Claiming another signer without its private key must fail
+	_, other, _ := ed25519.GenerateKey(rand.Reader)
+	impostor := shared
+	if err := impostor.Sign(other); err != nil {
+		t.Fatal(err)
+	}
+	impostor.Signature = shared.Signature
+	if _, err := impostor.Verify(); err == nil {
+		t.Error("bundle with a swapped signer key should be rejected")
+	}
+	if store.SignerFingerprint(impostor.Signer) == store.SignerFingerprint(shared.Signer) {
+		t.Error("different signers should have different fingerprints")
+	}
+	unsigned := shared
+	unsigned.Signature = ""
+	if _, err := unsigned.Verify(); err == nil {
+		t.Error("unsigned bundle should be rejected")
+	}
+}
+
+func TestTrustedSigner(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	bundle := &store.StrategyBundle{Version: store.StrategyBundleVersion, Config: json.RawMessage(`{}`)}
+	if err := bundle.Sign(key); err != nil {
+		t.Fatal(err)
+	}
+	if store.TrustedSigner(bundle.Signer, nil) || store.TrustedSigner("", []string{""}) {
+		t.Error("signer must not be trusted without being pinned")
+	}
+	if !store.TrustedSigner(bundle.Signer, []string{bundle.Signer}) {
+		t.Error("signer pinned by key should be trusted")
+	}
+	if !store.TrustedSigner(bundle.Signer, []string{strings.ToUpper(store.SignerFingerprint(bundle.Signer))}) {
+		t.Error("signer pinned by fingerprint should be trusted")
+	}
+}
+
+func importBundle(t *testing.T, s *Server, bundle *store.StrategyBundle) map[string]interface{} {
+	t.Helper()
+	w := callHandler(s.handleImportStrategyBundle, testRequest{method: http.MethodPost, target: "/api/strategies/import",
+		body: ImportStrategyBundleRequest{Bundle: bundle}, userID: "u1"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("import: expected 201, got %d %s", w.Code, w.Body)
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestHandleImportStrategyBundle_UnknownSignerUnverified(t *testing.T) {
+	s, _ := newTestServer(t)
+	st := s.store
+
+	configJSON, _ := json.Marshal(store.GetDefaultStrategyConfig("en"))
+	newBundle := func() *store.StrategyBundle {
+		return &store.StrategyBundle{Version: store.StrategyBundleVersion, Name: "Trend", Config: configJSON}
+	}
+
+	// A valid signature by a key nobody pinned only proves integrity
+	_, stranger, _ := ed25519.GenerateKey(rand.Reader)
+	foreign := newBundle()
+	if err := foreign.Sign(stranger); err != nil {
+		t.Fatal(err)
+	}
+	resp := importBundle(t, s, foreign)
+	if resp["verified"] != false || resp["warning"] == "" {
+		t.Errorf("bundle from an unknown signer should import as unverified: %v", resp)
+	}
+
+	local, err := st.StrategyBundleSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	own := newBundle()
+	if err := own.Sign(local); err != nil {
+		t.Fatal(err)
+	}
+	resp = importBundle(t, s, own)
+	if resp["verified"] != true || resp["warning"] != "" {
+		t.Errorf("bundle signed by this installation should be verified: %v", resp)
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"fmt"
+	"nofx/market"
+	"time"
+)
+
+// derivativesLookback recorded open interest readings averaged into a historical test run
+const derivativesLookback = 24 * time.Hour
+
+// earliestTestRunTime Binance USDT-M futures klines start in September 2019
+var earliestTestRunTime = time.Date(2019, 9, 1, 0, 0, 0, 0, time.UTC)
+
+// validateTestRunAsOf checks the time a historical test run replays, nil is a live run
+func validateTestRunAsOf(asOf *time.Time, now time.Time) error {
+	if asOf == nil {
+		return nil
+	}
+	if asOf.After(now) {
+		return fmt.Errorf("as_of must not be in the future")
+	}
+	if asOf.Before(earliestTestRunTime) {
+		return fmt.Errorf("as_of must be after %s, no futures klines exist before", earliestTestRunTime.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// applyDerivativesHistory fills the open interest and funding rate of historical market data from the readings
+// recorded while traders were running, returns false when nothing was recorded for the symbol around that time
+func (s *Server) applyDerivativesHistory(data *market.Data, at time.Time) bool {
+	if s.store == nil {
+		return false
+	}
+	history := s.store.MarketHistory()
+	found := false
+	oi, err := history.ListOpenInterestBetween(data.Symbol, at.Add(-derivativesLookback), at, maxMarketHistoryPoints)
+	if err == nil && len(oi) > 0 {
+		sum := 0.0
+		for _, p := range oi {
+			sum += p.OpenInterest
+		}
+		data.OpenInterest = &market.OIData{Latest: oi[len(oi)-1].OpenInterest, Average: sum / float64(len(oi))}
+		found = true
+	}
+	funding, err := history.ListFundingBetween(data.Symbol, at.Add(-derivativesLookback), at, 1)
+	if err == nil && len(funding) > 0 {
+		data.FundingRate = funding[0].Rate
+		found = true
+	}
+	return found
+}
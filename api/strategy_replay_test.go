@@ -0,0 +1,54 @@
+package api
+
+import (
+	"nofx/market"
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+func TestValidateTestRunAsOf(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	crash := time.Date(2024, 8, 5, 3, 0, 0, 0, time.UTC)
+	future := now.Add(time.Hour)
+	tooEarly := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if err := validateTestRunAsOf(nil, now); err != nil {
+		t.Errorf("a live run needs no as_of: %v", err)
+	}
+	if err := validateTestRunAsOf(&crash, now); err != nil {
+		t.Errorf("a past time should be accepted: %v", err)
+	}
+	for _, asOf := range []*time.Time{&future, &tooEarly} {
+		if validateTestRunAsOf(asOf, now) == nil {
+			t.Errorf("%s should be rejected", asOf)
+		}
+	}
+}
+
+func TestApplyDerivativesHistory(t *testing.T) {
+	s, _ := newTestServer(t)
+	st := s.store
+
+	at := time.Date(2024, 8, 5, 3, 0, 0, 0, time.UTC)
+	history := st.MarketHistory()
+	for i, oi := range []float64{100, 200, 300} {
+		history.SaveOpenInterest(&store.OpenInterestPoint{Symbol: "BTCUSDT", Timestamp: at.Add(time.Duration(i-2) * time.Hour), OpenInterest: oi})
+	}
+	// Recorded after the replayed time, must not leak into it
+	history.SaveOpenInterest(&store.OpenInterestPoint{Symbol: "BTCUSDT", Timestamp: at.Add(time.Hour), OpenInterest: 900})
+	history.SaveFunding(&store.FundingRatePoint{Symbol: "BTCUSDT", Timestamp: at.Add(-time.Hour), Rate: -0.0005})
+	history.SaveFunding(&store.FundingRatePoint{Symbol: "BTCUSDT", Timestamp: at.Add(time.Hour), Rate: 0.0001})
+
+	data := &market.Data{Symbol: "BTCUSDT", OpenInterest: &market.OIData{}}
+	if !s.applyDerivativesHistory(data, at) {
+		t.Fatal("readings were recorded before the replayed time")
+	}
+	if data.OpenInterest.Latest != 300 || data.OpenInterest.Average != 200 || data.FundingRate != -0.0005 {
+		t.Errorf("open interest %+v, funding %v", *data.OpenInterest, data.FundingRate)
+	}
+
+	if s.applyDerivativesHistory(&market.Data{Symbol: "ETHUSDT", OpenInterest: &market.OIData{}}, at) {
+		t.Error("nothing was recorded for ETHUSDT")
+	}
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/mcp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Streaming strategy test run events, in the order they are emitted:
+//
+//	candidates    {count, symbols}
+//	market_data   {symbol, ok, error, done, total} (one per candidate)
+//	prompt_built  {system_prompt, user_prompt, system_prompt_chars, user_prompt_chars, estimated_tokens}
+//	ai_start      {ai_model_id, streaming}
+//	ai_token      {text} (only when the provider supports streaming)
+//	ai_response   {text, duration_ms}
+//	decisions     {reasoning, decisions}
+//	validation    {valid, error}
+//	error         {error} (ends the stream)
+//	done          {}
+//
+// Without run_real_ai the stream ends after prompt_built.
+
+// sseWriter writes server-sent events to a gin response
+type sseWriter struct {
+	c *gin.Context
+}
+
+// send writes one event, returns false once the client has gone away
+func (w *sseWriter) send(event string, data any) bool {
+	if w.c.Request.Context().Err() != nil {
+		return false
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		logger.Warnf("⚠️ Failed to marshal %s event: %v", event, err)
+		return true
+	}
+	fmt.Fprintf(w.c.Writer, "event: %s\ndata: %s\n\n", event, payload)
+	w.c.Writer.Flush()
+	return true
+}
+
+// handleStrategyTestRunStream AI test run streamed as server-sent events
+// Same request body as /strategies/test-run; progress is reported as soon as each step finishes
+func (s *Server) handleStrategyTestRunStream(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req strategyTestRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if req.PromptVariant == "" {
+		req.PromptVariant = "balanced"
+	}
+	if err := validateTestRunAsOf(req.AsOf, time.Now()); err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+	if !s.resolveStrategyPromptTemplate(c, &req.Config) {
+		return
+	}
+
+	// Set SSE headers
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := &sseWriter{c: c}
+	run, err := s.buildStrategyTestRun(&req, func(event string, data any) { w.send(event, data) })
+	if err != nil {
+		logger.Errorf("[API Error] Failed to get candidate coins: %v", err)
+		w.send("error", gin.H{"error": "Failed to get candidate coins"})
+		return
+	}
+
+	if !w.send("prompt_built", gin.H{
+		"system_prompt":       run.SystemPrompt,
+		"user_prompt":         run.UserPrompt,
+		"system_prompt_chars": len([]rune(run.SystemPrompt)),
+		"user_prompt_chars":   len([]rune(run.UserPrompt)),
+		"estimated_tokens":    (len(run.SystemPrompt) + len(run.UserPrompt)) / 4,
+		"as_of":               req.AsOf,
+		"notes":               run.Notes,
+	}) {
+		return
+	}
+
+	if !req.RunRealAI || req.AIModelID == "" {
+		w.send("done", gin.H{})
+		return
+	}
+
+	aiClient, err := s.testRunAIClient(userID, req.AIModelID)
+	if err != nil {
+		w.send("error", gin.H{"error": err.Error()})
+		return
+	}
+
+	streamer, streaming := aiClient.(mcp.StreamingCaller)
+	if !w.send("ai_start", gin.H{"ai_model_id": req.AIModelID, "streaming": streaming}) {
+		return
+	}
+
+	aiCallStart := time.Now()
+	var aiResponse string
+	if streaming {
+		aiResponse, err = streamer.CallWithMessagesStream(run.SystemPrompt, run.UserPrompt, func(chunk string) {
+			w.send("ai_token", gin.H{"text": chunk})
+		})
+	} else {
+		aiResponse, err = aiClient.CallWithMessages(run.SystemPrompt, run.UserPrompt)
+	}
+	if err != nil {
+		w.send("error", gin.H{"error": fmt.Sprintf("AI call failed: %s", err.Error())})
+		return
+	}
+	if !w.send("ai_response", gin.H{"text": aiResponse, "duration_ms": time.Since(aiCallStart).Milliseconds()}) {
+		return
+	}
+
+	decision, parseErr := run.Engine.ParseAIResponse(aiResponse, 1000.0)
+	if decision != nil {
+		w.send("decisions", gin.H{"reasoning": decision.CoTTrace, "decisions": decision.Decisions})
+	}
+	validation := gin.H{"valid": parseErr == nil}
+	if parseErr != nil {
+		validation["error"] = parseErr.Error()
+	}
+	w.send("validation", validation)
+	w.send("done", gin.H{})
+}
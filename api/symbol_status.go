@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"nofx/market"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handleSymbolRestrictions restricted symbols and maintenance windows traders currently honour
+func (s *Server) handleSymbolRestrictions(c *gin.Context) {
+	status := s.traderManager.SymbolStatusService()
+	if status == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"enabled":             false,
+			"restrictions":        []market.SymbolRestriction{},
+			"maintenance_windows": []market.MaintenanceWindow{},
+		})
+		return
+	}
+
+	resp := gin.H{
+		"enabled":             true,
+		"restrictions":        status.Restrictions(),
+		"maintenance_windows": status.MaintenanceWindows(),
+	}
+	if updatedAt := status.UpdatedAt(); !updatedAt.IsZero() {
+		resp["updated_at"] = updatedAt
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// handleGetMaintenanceWindows registered exchange maintenance windows
+func (s *Server) handleGetMaintenanceWindows(c *gin.Context) {
+	status := s.traderManager.SymbolStatusService()
+	if status == nil {
+		c.JSON(http.StatusOK, gin.H{"enabled": false, "windows": []market.MaintenanceWindow{}})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"enabled": true, "windows": status.MaintenanceWindows()})
+}
+
+// handleUpdateMaintenanceWindows replaces the exchange maintenance windows (traders skip cycles inside them)
+func (s *Server) handleUpdateMaintenanceWindows(c *gin.Context) {
+	status := s.traderManager.SymbolStatusService()
+	if status == nil {
+		SafeBadRequest(c, "Symbol status tracking is disabled (SYMBOL_STATUS_REFRESH_MINUTES=0)")
+		return
+	}
+
+	var req struct {
+		Windows []market.MaintenanceWindow `json:"windows"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	for _, w := range req.Windows {
+		if w.Start.IsZero() || !w.End.After(w.Start) {
+			SafeBadRequest(c, "Each maintenance window needs a start before its end")
+			return
+		}
+	}
+	if req.Windows == nil {
+		req.Windows = []market.MaintenanceWindow{}
+	}
+
+	value, err := json.Marshal(req.Windows)
+	if err != nil {
+		SafeInternalError(c, "Encode maintenance windows", err)
+		return
+	}
+	if err := s.store.SetSystemConfig(market.MaintenanceWindowsConfigKey, string(value)); err != nil {
+		SafeInternalError(c, "Save maintenance windows", err)
+		return
+	}
+	status.SetMaintenanceWindows(req.Windows)
+
+	c.JSON(http.StatusOK, gin.H{"enabled": true, "windows": status.MaintenanceWindows()})
+}
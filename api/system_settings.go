@@ -0,0 +1,259 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+
+	"nofx/config"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// systemSettingsConfigKey system config key of the JSON encoded settings operators changed, other keys keep their defaults
+const systemSettingsConfigKey = "system_settings"
+
+// SystemSettings runtime settings operators change through /api/admin/settings, applied without a restart
+type SystemSettings struct {
+	RegistrationEnabled       bool     `json:"registration_enabled"`
+	DefaultBTCETHLeverage     int      `json:"default_btc_eth_leverage"`    // Leverage of new traders that don't set one
+	DefaultAltcoinLeverage    int      `json:"default_altcoin_leverage"`    // Leverage of new traders that don't set one
+	MinOIValueMillions        float64  `json:"min_oi_value_millions"`       // Candidate coins with less open interest are skipped, 0 = off
+	DefaultNotificationEvents []string `json:"default_notification_events"` // Emailed to users who have not chosen any
+}
+
+// defaultSystemSettings settings before any override: .env and built-in defaults
+func defaultSystemSettings() SystemSettings {
+	return SystemSettings{
+		RegistrationEnabled:       config.Get().RegistrationEnabled,
+		DefaultBTCETHLeverage:     10,
+		DefaultAltcoinLeverage:    5,
+		MinOIValueMillions:        kernel.DefaultMinOIValueMillions,
+		DefaultNotificationEvents: store.DefaultNotificationEvents,
+	}
+}
+
+// settingSpec schema of one settings key, values are validated against it
+type settingSpec struct {
+	Key         string   `json:"key"`
+	Type        string   `json:"type"` // bool, int, float, string_list
+	Description string   `json:"description"`
+	Min         *float64 `json:"min,omitempty"`
+	Max         *float64 `json:"max,omitempty"`
+	Options     []string `json:"options,omitempty"` // Allowed elements of a string_list
+}
+
+func settingBound(v float64) *float64 { return &v }
+
+// systemSettingSpecs known settings keys, matching the SystemSettings JSON fields
+var systemSettingSpecs = []settingSpec{
+	{Key: "registration_enabled", Type: "bool", Description: "Allow new users to register"},
+	{Key: "default_btc_eth_leverage", Type: "int", Description: "BTC/ETH leverage of new traders that don't set one", Min: settingBound(1), Max: settingBound(50)},
+	{Key: "default_altcoin_leverage", Type: "int", Description: "Altcoin leverage of new traders that don't set one", Min: settingBound(1), Max: settingBound(20)},
+	{Key: "min_oi_value_millions", Type: "float", Description: "Candidate coins with less open interest value (million USD) are skipped, 0 = off", Min: settingBound(0), Max: settingBound(10000)},
+	{Key: "default_notification_events", Type: "string_list", Description: "Trader events emailed to users who have not chosen any", Options: store.NotificationEvents},
+}
+
+// validate checks that raw is a valid value of the key
+func (spec settingSpec) validate(raw json.RawMessage) error {
+	var number float64
+	switch spec.Type {
+	case "bool":
+		var v bool
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return fmt.Errorf("%s must be a boolean", spec.Key)
+		}
+		return nil
+	case "int":
+		var v int
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return fmt.Errorf("%s must be an integer", spec.Key)
+		}
+		number = float64(v)
+	case "float":
+		if err := json.Unmarshal(raw, &number); err != nil {
+			return fmt.Errorf("%s must be a number", spec.Key)
+		}
+	case "string_list":
+		var v []string
+		if err := json.Unmarshal(raw, &v); err != nil || len(v) == 0 {
+			return fmt.Errorf("%s must be a non-empty list", spec.Key)
+		}
+		for _, item := range v {
+			if !slices.Contains(spec.Options, item) {
+				return fmt.Errorf("%s: unknown value %q", spec.Key, item)
+			}
+		}
+		return nil
+	}
+	if spec.Min != nil && number < *spec.Min {
+		return fmt.Errorf("%s must be at least %g", spec.Key, *spec.Min)
+	}
+	if spec.Max != nil && number > *spec.Max {
+		return fmt.Errorf("%s must be at most %g", spec.Key, *spec.Max)
+	}
+	return nil
+}
+
+// systemSettings settings in effect: the defaults with the stored overrides applied
+type systemSettings struct {
+	writeMu   sync.Mutex // Serializes updates from read-merge to apply, so concurrent changes are not lost
+	mu        sync.RWMutex
+	overrides map[string]json.RawMessage
+	current   *SystemSettings // nil until set, defaults apply
+}
+
+// set validates the overrides and replaces them, nothing changes on error
+func (s *systemSettings) set(overrides map[string]json.RawMessage) (SystemSettings, error) {
+	settings := defaultSystemSettings()
+	for key, raw := range overrides {
+		spec, ok := findSettingSpec(key)
+		if !ok {
+			return settings, fmt.Errorf("unknown setting %q", key)
+		}
+		if err := spec.validate(raw); err != nil {
+			return settings, err
+		}
+	}
+	if len(overrides) > 0 {
+		merged, err := json.Marshal(overrides)
+		if err != nil {
+			return settings, err
+		}
+		if err := json.Unmarshal(merged, &settings); err != nil {
+			return settings, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides, s.current = overrides, &settings
+	return settings, nil
+}
+
+func (s *systemSettings) get() SystemSettings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.current == nil {
+		return defaultSystemSettings()
+	}
+	return *s.current
+}
+
+// keys overridden keys, sorted
+func (s *systemSettings) keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.overrides))
+	for key := range s.overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func findSettingSpec(key string) (settingSpec, bool) {
+	for _, spec := range systemSettingSpecs {
+		if spec.Key == key {
+			return spec, true
+		}
+	}
+	return settingSpec{}, false
+}
+
+// applySystemSettings pushes the settings read by other packages into the running services
+// Registration and default leverages are read from s.settings by the handlers directly.
+func applySystemSettings(settings SystemSettings) {
+	kernel.SetMinOIValueMillions(settings.MinOIValueMillions)
+	store.SetDefaultNotificationEvents(settings.DefaultNotificationEvents)
+}
+
+// loadSystemSettings applies the settings overrides stored in system config
+func (s *Server) loadSystemSettings() {
+	value, err := s.store.GetSystemConfig(systemSettingsConfigKey)
+	if err != nil || value == "" {
+		if err != nil {
+			logger.Errorf("Failed to load system settings: %v", err)
+		}
+		return
+	}
+	var overrides map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+		logger.Errorf("Failed to decode system settings: %v", err)
+		return
+	}
+	settings, err := s.settings.set(overrides)
+	if err != nil {
+		logger.Errorf("Failed to apply system settings: %v", err)
+		return
+	}
+	applySystemSettings(settings)
+	logger.Infof("⚙️ Loaded %d system settings overrides: %v", len(overrides), s.settings.keys())
+}
+
+// systemSettingsResponse settings in effect, the overridden keys and the schema of all keys
+func (s *Server) systemSettingsResponse() gin.H {
+	return gin.H{"settings": s.settings.get(), "overrides": s.settings.keys(), "schema": systemSettingSpecs}
+}
+
+// handleGetSystemSettings Runtime settings, which of them are overridden and their schema (admin)
+func (s *Server) handleGetSystemSettings(c *gin.Context) {
+	c.JSON(http.StatusOK, s.systemSettingsResponse())
+}
+
+// handleUpdateSystemSettings Change runtime settings (admin)
+// Body: {"key": value, ...}, only the given keys change and null resets a key to its default.
+func (s *Server) handleUpdateSystemSettings(c *gin.Context) {
+	var req map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&req); err != nil || len(req) == 0 {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	s.settings.writeMu.Lock()
+	defer s.settings.writeMu.Unlock()
+	s.settings.mu.RLock()
+	overrides := make(map[string]json.RawMessage, len(s.settings.overrides)+len(req))
+	for key, raw := range s.settings.overrides {
+		overrides[key] = raw
+	}
+	s.settings.mu.RUnlock()
+	for key, raw := range req {
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			delete(overrides, key)
+			continue
+		}
+		overrides[key] = raw
+	}
+
+	var next systemSettings
+	if _, err := next.set(overrides); err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+	value, err := json.Marshal(overrides)
+	if err != nil {
+		SafeInternalError(c, "Encode system settings", err)
+		return
+	}
+	if err := s.store.SetSystemConfig(systemSettingsConfigKey, string(value)); err != nil {
+		SafeInternalError(c, "Save system settings", err)
+		return
+	}
+	settings, err := s.settings.set(overrides)
+	if err != nil {
+		SafeInternalError(c, "Apply system settings", err)
+		return
+	}
+	applySystemSettings(settings)
+	logger.Infof("⚙️ System settings updated by %s: %v", c.GetString("email"), s.settings.keys())
+
+	c.JSON(http.StatusOK, s.systemSettingsResponse())
+}
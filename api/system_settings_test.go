@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"nofx/kernel"
+	"nofx/store"
+)
+
+func TestSystemSettingsUpdate(t *testing.T) {
+	s, call := newTestServer(t)
+	defer applySystemSettings(defaultSystemSettings())
+
+	put := func(body string) *httptest.ResponseRecorder {
+		return call(s.handleUpdateSystemSettings, testRequest{method: http.MethodPut, body: body})
+	}
+
+	for _, bad := range []string{
+		`{"unknown_key": 1}`,
+		`{"default_btc_eth_leverage": 80}`,
+		`{"default_altcoin_leverage": 2.5}`,
+		`{"registration_enabled": "no"}`,
+		`{"default_notification_events": ["not_an_event"]}`,
+	} {
+		if w := put(bad); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", bad, w.Code)
+		}
+	}
+
+	if w := put(`{"registration_enabled": false, "default_btc_eth_leverage": 20, "min_oi_value_millions": 0, "default_notification_events": ["halted"]}`); w.Code != http.StatusOK {
+		t.Fatalf("update failed: %d %s", w.Code, w.Body.String())
+	}
+	settings := s.settings.get()
+	if settings.RegistrationEnabled || settings.DefaultBTCETHLeverage != 20 || settings.DefaultAltcoinLeverage != 5 {
+		t.Errorf("settings = %+v", settings)
+	}
+	if kernel.MinOIValueMillions() != 0 || len(store.DefaultNotificationEventTypes()) != 1 {
+		t.Errorf("running services not updated: OI %v, events %v", kernel.MinOIValueMillions(), store.DefaultNotificationEventTypes())
+	}
+
+	// null resets a key, the other overrides stay and survive a restart
+	if w := put(`{"min_oi_value_millions": null}`); w.Code != http.StatusOK {
+		t.Fatalf("reset failed: %d %s", w.Code, w.Body.String())
+	}
+	restarted := &Server{store: s.store}
+	restarted.loadSystemSettings()
+	settings = restarted.settings.get()
+	if settings.MinOIValueMillions != kernel.DefaultMinOIValueMillions || settings.DefaultBTCETHLeverage != 20 || settings.RegistrationEnabled {
+		t.Errorf("reloaded settings = %+v", settings)
+	}
+	if keys := restarted.settings.keys(); len(keys) != 3 {
+		t.Errorf("overrides = %v", keys)
+	}
+
+	w := call(restarted.handleGetSystemConfig, testRequest{target: "/api/config"})
+	var cfg map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &cfg); err != nil || cfg["registration_enabled"] != false || cfg["btc_eth_leverage"] != float64(20) {
+		t.Errorf("system config = %v (%v)", cfg, err)
+	}
+}
+
+func TestSystemSettingsConcurrentUpdates(t *testing.T) {
+	s, call := newTestServer(t)
+	defer applySystemSettings(defaultSystemSettings())
+
+	bodies := []string{
+		`{"registration_enabled": false}`,
+		`{"default_btc_eth_leverage": 20}`,
+		`{"default_altcoin_leverage": 3}`,
+		`{"min_oi_value_millions": 5}`,
+	}
+	var wg sync.WaitGroup
+	for _, body := range bodies {
+		wg.Add(1)
+		go func(body string) {
+			defer wg.Done()
+			w := call(s.handleUpdateSystemSettings, testRequest{method: http.MethodPut, body: body})
+			if w.Code != http.StatusOK {
+				t.Errorf("%s: %d %s", body, w.Code, w.Body.String())
+			}
+		}(body)
+	}
+	wg.Wait()
+
+	// Every change survives, in memory and in the stored overrides
+	restarted := &Server{store: s.store}
+	restarted.loadSystemSettings()
+	for _, srv := range []*Server{s, restarted} {
+		if keys := srv.settings.keys(); len(keys) != len(bodies) {
+			t.Errorf("overrides = %v, want %d keys", keys, len(bodies))
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testRequest handler call made by API tests
+type testRequest struct {
+	method     string      // GET when empty
+	target     string      // "/" when empty
+	body       interface{} // string/[]byte sent as is, anything else encoded as JSON
+	userID     string      // authenticated user, anonymous when empty
+	params     gin.Params
+	remoteAddr string
+}
+
+// testCall calls a handler with the request and returns the recorded response
+type testCall func(handler gin.HandlerFunc, req testRequest) *httptest.ResponseRecorder
+
+// newTestServer creates a Server backed by a temporary store, closed when the test ends
+func newTestServer(t *testing.T) (*Server, testCall) {
+	t.Helper()
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return &Server{store: st}, callHandler
+}
+
+func callHandler(handler gin.HandlerFunc, req testRequest) *httptest.ResponseRecorder {
+	if req.method == "" {
+		req.method = http.MethodGet
+	}
+	if req.target == "" {
+		req.target = "/"
+	}
+	var body io.Reader
+	switch b := req.body.(type) {
+	case nil:
+	case string:
+		body = bytes.NewBufferString(b)
+	case []byte:
+		body = bytes.NewReader(b)
+	default:
+		raw, _ := json.Marshal(b)
+		body = bytes.NewReader(raw)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(req.method, req.target, body)
+	if body != nil {
+		c.Request.Header.Set("Content-Type", "application/json")
+	}
+	if req.remoteAddr != "" {
+		c.Request.RemoteAddr = req.remoteAddr
+	}
+	c.Params = req.params
+	if req.userID != "" {
+		c.Set("user_id", req.userID)
+	}
+	handler(c)
+	return w
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"nofx/market"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultChartBars = 500
+	maxChartBars     = 1500 // CoinAnk maximum per request
+	// chartEntryMatchWindow how long after an open decision its position may start (limit fills, slow confirmations)
+	chartEntryMatchWindow = 5 * time.Minute
+)
+
+// ChartMarker an entry or exit of one of the trader's positions
+type ChartMarker struct {
+	Time        int64   `json:"time"` // Unix milliseconds
+	Type        string  `json:"type"` // entry, exit
+	Side        string  `json:"side"` // LONG, SHORT
+	Price       float64 `json:"price"`
+	Quantity    float64 `json:"quantity"`
+	PositionID  int64   `json:"position_id"`
+	RealizedPnL float64 `json:"realized_pnl,omitempty"` // Exits only
+	Reason      string  `json:"reason,omitempty"`       // Close reason of exits
+}
+
+// ChartLevel a stop loss or take profit price a position had over a time span
+type ChartLevel struct {
+	Type       string  `json:"type"` // stop_loss, take_profit
+	Side       string  `json:"side"`
+	Price      float64 `json:"price"`
+	From       int64   `json:"from"` // Unix milliseconds, no earlier than the first kline
+	To         int64   `json:"to"`   // Unix milliseconds, 0 while the position is open
+	PositionID int64   `json:"position_id"`
+}
+
+// TraderChart klines of a symbol with the trader's trades on them
+type TraderChart struct {
+	Symbol    string         `json:"symbol"`
+	Timeframe string         `json:"timeframe"`
+	Exchange  string         `json:"exchange"`
+	Klines    []market.Kline `json:"klines"`
+	Markers   []ChartMarker  `json:"markers"`
+	Levels    []ChartLevel   `json:"levels"`
+}
+
+// handleTraderChart klines of a symbol overlaid with the trader's entries, exits and SL/TP levels
+// Query: symbol (required), tf (default 5m), limit (bars, default 500). Klines come from the data
+// source of the trader's exchange.
+func (s *Server) handleTraderChart(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	traderCfg, err := s.store.Trader().Get(userID, traderID)
+	if err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+	symbol := strings.ToUpper(strings.TrimSpace(c.Query("symbol")))
+	if symbol == "" {
+		SafeBadRequest(c, "symbol parameter is required")
+		return
+	}
+	tf, err := market.NormalizeTimeframe(c.DefaultQuery("tf", "5m"))
+	if err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+	limit := defaultChartBars
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			SafeBadRequest(c, "Invalid limit")
+			return
+		}
+		limit = min(n, maxChartBars)
+	}
+
+	exchangeType := "binance"
+	if exchange, err := s.store.Exchange().GetByID(userID, traderCfg.ExchangeID); err == nil && exchange.ExchangeType != "" {
+		exchangeType = exchange.ExchangeType
+	}
+	klines, source, err := s.fetchKlines(symbol, tf, exchangeType, limit)
+	if err != nil {
+		SafeInternalError(c, "Get klines from "+source, err)
+		return
+	}
+
+	chart := TraderChart{Symbol: symbol, Timeframe: tf, Exchange: exchangeType, Klines: klines, Markers: []ChartMarker{}, Levels: []ChartLevel{}}
+	if len(klines) == 0 {
+		c.JSON(http.StatusOK, chart)
+		return
+	}
+	duration, _ := market.TFDuration(tf)
+	from := klines[0].OpenTime
+	to := klines[len(klines)-1].OpenTime + duration.Milliseconds()
+
+	symbols := chartSymbols(symbol)
+	var positions []*store.TraderPosition
+	for _, sym := range symbols {
+		found, err := s.store.Position().GetSymbolPositionsInRange(traderID, sym, from, to)
+		if err != nil {
+			SafeInternalError(c, "Get positions", err)
+			return
+		}
+		positions = append(positions, found...)
+	}
+	// Opens decided shortly before the chart starts can still own a position on it
+	actions, err := s.store.Decision().GetActionsInRange(traderID, time.UnixMilli(from).Add(-24*time.Hour), time.UnixMilli(to))
+	if err != nil {
+		SafeInternalError(c, "Get decisions", err)
+		return
+	}
+
+	chart.Markers, chart.Levels = chartOverlay(symbols, from, to, positions, actions)
+	c.JSON(http.StatusOK, chart)
+}
+
+// chartSymbols spellings a symbol may be stored under (as requested, and normalized for crypto perps)
+func chartSymbols(symbol string) []string {
+	if normalized := market.Normalize(symbol); normalized != symbol {
+		return []string{symbol, normalized}
+	}
+	return []string{symbol}
+}
+
+// chartOverlay computes the markers and SL/TP levels of the positions in [from, to]
+// Each successful open decision with a stop loss or take profit is matched to the position of the
+// same symbol and side that started within chartEntryMatchWindow of it; its levels last until that
+// position closed. Opens without a matching position (rejected, never filled) draw nothing.
+func chartOverlay(symbols []string, from, to int64, positions []*store.TraderPosition, actions []store.DecisionAction) ([]ChartMarker, []ChartLevel) {
+	sort.Slice(positions, func(i, j int) bool { return positions[i].EntryTime < positions[j].EntryTime })
+
+	markers := []ChartMarker{}
+	for _, pos := range positions {
+		side := strings.ToUpper(pos.Side)
+		if pos.EntryTime >= from && pos.EntryTime <= to {
+			markers = append(markers, ChartMarker{Time: pos.EntryTime, Type: "entry", Side: side, Price: pos.EntryPrice, Quantity: pos.EntryQuantity, PositionID: pos.ID})
+		}
+		if pos.ExitTime > 0 && pos.ExitTime >= from && pos.ExitTime <= to {
+			markers = append(markers, ChartMarker{Time: pos.ExitTime, Type: "exit", Side: side, Price: pos.ExitPrice, Quantity: pos.EntryQuantity, PositionID: pos.ID, RealizedPnL: pos.RealizedPnL, Reason: pos.CloseReason})
+		}
+	}
+	sort.SliceStable(markers, func(i, j int) bool { return markers[i].Time < markers[j].Time })
+
+	isSymbol := make(map[string]bool, len(symbols))
+	for _, sym := range symbols {
+		isSymbol[sym] = true
+	}
+	levels := []ChartLevel{}
+	for _, action := range actions {
+		side := openSide(action.Action)
+		if side == "" || !action.Success || !isSymbol[strings.ToUpper(action.Symbol)] || (action.StopLoss <= 0 && action.TakeProfit <= 0) {
+			continue
+		}
+		at := action.Timestamp.UnixMilli()
+		pos := matchOpenedPosition(positions, side, at)
+		if pos == nil {
+			continue
+		}
+		start := max(at, pos.EntryTime, from)
+		for _, level := range []struct {
+			kind  string
+			price float64
+		}{{"stop_loss", action.StopLoss}, {"take_profit", action.TakeProfit}} {
+			if level.price > 0 {
+				levels = append(levels, ChartLevel{Type: level.kind, Side: side, Price: level.price, From: start, To: pos.ExitTime, PositionID: pos.ID})
+			}
+		}
+	}
+	return markers, levels
+}
+
+// openSide side opened by a decision action, "" for other actions
+func openSide(action string) string {
+	switch action {
+	case "open_long", "open_long_limit":
+		return "LONG"
+	case "open_short", "open_short_limit":
+		return "SHORT"
+	}
+	return ""
+}
+
+// matchOpenedPosition the first position of a side that started within the match window of at
+func matchOpenedPosition(positions []*store.TraderPosition, side string, at int64) *store.TraderPosition {
+	window := chartEntryMatchWindow.Milliseconds()
+	for _, pos := range positions {
+		if strings.ToUpper(pos.Side) == side && pos.EntryTime >= at-window && pos.EntryTime <= at+window {
+			return pos
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nofx/store"
+)
+
+func TestChartOverlay(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	ms := func(d time.Duration) int64 { return base.Add(d).UnixMilli() }
+	from, to := ms(0), ms(10*time.Hour)
+
+	positions := []*store.TraderPosition{
+		{ID: 2, Symbol: "BTCUSDT", Side: "SHORT", EntryPrice: 101, EntryQuantity: 1, EntryTime: ms(5 * time.Hour)},
+		{ID: 1, Symbol: "BTCUSDT", Side: "LONG", EntryPrice: 100, EntryQuantity: 2, EntryTime: ms(-time.Hour), ExitPrice: 105, ExitTime: ms(2 * time.Hour), RealizedPnL: 10, CloseReason: "take_profit"},
+	}
+	actions := []store.DecisionAction{
+		{Action: "open_long", Symbol: "BTCUSDT", Success: true, StopLoss: 95, TakeProfit: 105, Timestamp: base.Add(-time.Hour - time.Minute)},
+		{Action: "open_short", Symbol: "BTCUSDT", Success: true, StopLoss: 110, Timestamp: base.Add(5*time.Hour - 30*time.Second)},
+		{Action: "open_long", Symbol: "BTCUSDT", Success: false, StopLoss: 90, Timestamp: base.Add(3 * time.Hour)},
+		{Action: "open_long", Symbol: "ETHUSDT", Success: true, StopLoss: 90, Timestamp: base.Add(5 * time.Hour)},
+	}
+
+	markers, levels := chartOverlay([]string{"BTCUSDT"}, from, to, positions, actions)
+
+	// The first entry is before the chart, its exit and the short's entry are on it
+	if len(markers) != 2 || markers[0].Type != "exit" || markers[0].PositionID != 1 || markers[0].RealizedPnL != 10 ||
+		markers[1].Type != "entry" || markers[1].Side != "SHORT" || markers[1].Price != 101 {
+		t.Fatalf("unexpected markers: %+v", markers)
+	}
+
+	if len(levels) != 3 {
+		t.Fatalf("expected SL+TP of the long and SL of the short, got %+v", levels)
+	}
+	if sl := levels[0]; sl.Type != "stop_loss" || sl.Price != 95 || sl.From != from || sl.To != ms(2*time.Hour) || sl.PositionID != 1 {
+		t.Errorf("long stop loss should be clamped to the chart and end at the exit: %+v", sl)
+	}
+	if short := levels[2]; short.Side != "SHORT" || short.Price != 110 || short.From != ms(5*time.Hour) || short.To != 0 {
+		t.Errorf("open short's stop loss should start at entry and stay open: %+v", short)
+	}
+}
+
+func TestGetSymbolPositionsInRange(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+
+	for _, pos := range []*store.TraderPosition{
+		{TraderID: "t1", Symbol: "BTCUSDT", Side: "LONG", Quantity: 1, EntryPrice: 100, EntryTime: 1000, ExitTime: 2000, Status: "CLOSED"},
+		{TraderID: "t1", Symbol: "BTCUSDT", Side: "LONG", Quantity: 1, EntryPrice: 100, EntryTime: 5000, Status: "OPEN"},
+		{TraderID: "t1", Symbol: "BTCUSDT", Side: "LONG", Quantity: 1, EntryPrice: 100, EntryTime: 100, ExitTime: 500, Status: "CLOSED"},
+		{TraderID: "t1", Symbol: "ETHUSDT", Side: "LONG", Quantity: 1, EntryPrice: 100, EntryTime: 1500, Status: "OPEN"},
+	} {
+		if err := st.Position().Create(pos); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := st.Position().GetSymbolPositionsInRange("t1", "BTCUSDT", 1500, 6000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].EntryTime != 1000 || got[1].EntryTime != 5000 {
+		t.Errorf("expected the position closed in range and the open one, got %d", len(got))
+	}
+
+	cycle := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := st.Decision().LogDecision(&store.DecisionRecord{TraderID: "t1", Timestamp: cycle, InputPrompt: "long prompt",
+		Decisions: []store.DecisionAction{{Action: "open_long", Symbol: "BTCUSDT", StopLoss: 95}}}); err != nil {
+		t.Fatal(err)
+	}
+	actions, err := st.Decision().GetActionsInRange("t1", cycle.Add(-time.Hour), cycle.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 1 || actions[0].StopLoss != 95 || !actions[0].Timestamp.Equal(cycle) {
+		t.Errorf("actions should carry the cycle time, got %+v", actions)
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordTraderEvent appends an API-side change to a trader's event timeline (best effort)
+func (s *Server) recordTraderEvent(traderID, eventType, message string, details map[string]interface{}) {
+	if err := s.store.TraderEvent().Append(traderID, eventType, message, details); err != nil {
+		logger.Warnf("⚠️ Failed to record %s event of trader %s: %v", eventType, traderID, err)
+	}
+}
+
+// handleTraderEvents Chronological event feed of a trader (starts, stops, errors, config changes,
+// balance syncs, circuit breaker trips, decision summaries), newest first
+// Query: type (comma-separated), start/end (RFC3339), limit, offset
+func (s *Server) handleTraderEvents(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	filter := store.TraderEventFilter{TraderID: traderID}
+	if types := c.Query("type"); types != "" {
+		for _, t := range strings.Split(types, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				filter.Types = append(filter.Types, t)
+			}
+		}
+	}
+	for param, target := range map[string]*time.Time{"start": &filter.Start, "end": &filter.End} {
+		if v := c.Query(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				SafeBadRequest(c, "Invalid "+param+" time, expected RFC3339")
+				return
+			}
+			*target = t
+		}
+	}
+	if v, err := strconv.Atoi(c.Query("limit")); err == nil {
+		filter.Limit = v
+	}
+	if v, err := strconv.Atoi(c.Query("offset")); err == nil && v > 0 {
+		filter.Offset = v
+	}
+
+	events, total, err := s.store.TraderEvent().Query(filter)
+	if err != nil {
+		SafeInternalError(c, "Query trader events", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trader_id": traderID,
+		"events":    events,
+		"total":     total,
+	})
+}
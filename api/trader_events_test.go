@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandleTraderEvents(t *testing.T) {
+	s, call := newTestServer(t)
+	st := s.store
+
+	if err := st.Trader().Create(&store.Trader{ID: "t1", UserID: "alice", Name: "Alpha"}); err != nil {
+		t.Fatalf("create trader: %v", err)
+	}
+	s.recordTraderEvent("t1", store.TraderEventStart, "Trader started", nil)
+	s.recordTraderEvent("t1", store.TraderEventBalanceSync, "Initial balance synced", map[string]interface{}{"new_balance": 120.5})
+	s.recordTraderEvent("t1", store.TraderEventStop, "Trader stopped", nil)
+	s.recordTraderEvent("other", store.TraderEventStart, "Trader started", nil)
+
+	query := func(userID, rawQuery string) (*httptest.ResponseRecorder, []store.TraderEvent) {
+		w := call(s.handleTraderEvents, testRequest{target: "/api/traders/t1/events?" + rawQuery, userID: userID,
+			params: gin.Params{{Key: "id", Value: "t1"}}})
+
+		var resp struct {
+			Events []store.TraderEvent `json:"events"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp.Events
+	}
+
+	w, events := query("alice", "")
+	if w.Code != http.StatusOK || len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d (status %d)", len(events), w.Code)
+	}
+	if events[0].Type != store.TraderEventStop || events[2].Type != store.TraderEventStart {
+		t.Errorf("events should be newest first: %s ... %s", events[0].Type, events[2].Type)
+	}
+
+	_, events = query("alice", "type=balance_sync,stop")
+	if len(events) != 2 {
+		t.Errorf("type filter should return 2 events, got %d", len(events))
+	}
+	for _, e := range events {
+		if e.Type == store.TraderEventBalanceSync && e.Details != `{"new_balance":120.5}` {
+			t.Errorf("unexpected details: %s", e.Details)
+		}
+	}
+
+	if w, _ := query("bob", ""); w.Code != http.StatusNotFound {
+		t.Errorf("other users must not read the timeline, got status %d", w.Code)
+	}
+	if w, _ := query("alice", "start=yesterday"); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid start time should be rejected, got status %d", w.Code)
+	}
+}
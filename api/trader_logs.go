@@ -0,0 +1,117 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultTraderLogLines = 200
+	maxTraderLogLines     = 1000
+	logHeartbeatInterval  = 15 * time.Second // Keeps proxies from closing an idle stream
+)
+
+// handleGetTraderLogs A trader's captured log lines
+// Query: limit (default 200), level (minimum level, e.g. "warning"), history=true (stored lines,
+// paged with before=<id>), follow=true (SSE: recent lines as "log" events, then live lines).
+func (s *Server) handleGetTraderLogs(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if _, err := s.store.Trader().Get(userID, traderID); err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	limit := defaultTraderLogLines
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			SafeBadRequest(c, "Invalid limit")
+			return
+		}
+		limit = min(n, maxTraderLogLines)
+	}
+	level := c.Query("level")
+	if level != "" && !logger.ValidLevel(level) {
+		SafeBadRequest(c, "Invalid level")
+		return
+	}
+
+	if c.Query("history") == "true" {
+		var before int64
+		if v := c.Query("before"); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n <= 0 {
+				SafeBadRequest(c, "Invalid before")
+				return
+			}
+			before = n
+		}
+		logs, err := s.store.TraderLog().List(traderID, before, limit)
+		if err != nil {
+			SafeInternalError(c, "Get trader logs", err)
+			return
+		}
+		filtered := logs[:0]
+		for _, l := range logs {
+			if logger.LevelAtLeast(l.Level, level) {
+				filtered = append(filtered, l)
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"logs": filtered})
+		return
+	}
+
+	if c.Query("follow") != "true" {
+		c.JSON(http.StatusOK, gin.H{"logs": logger.Recent(traderID, limit, level)})
+		return
+	}
+
+	// Subscribe before reading the backlog so no line falls in between
+	live, cancel := logger.Subscribe(traderID)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := &sseWriter{c: c}
+	var lastSeq int64
+	for _, e := range logger.Recent(traderID, limit, level) {
+		if !w.send("log", e) {
+			return
+		}
+		lastSeq = e.Seq
+	}
+
+	heartbeat := time.NewTicker(logHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case e, ok := <-live:
+			if !ok {
+				w.send("end", gin.H{"reason": "trader removed"})
+				return
+			}
+			if e.Seq <= lastSeq || !logger.LevelAtLeast(e.Level, level) {
+				continue
+			}
+			if !w.send("log", e) {
+				return
+			}
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,495 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"nofx/logger"
+	"nofx/store"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Migration modes
+const (
+	migrationModeMirror = "mirror" // Reopen positions on the destination, then close them on the source
+	migrationModeClose  = "close"  // Only close positions on the source
+)
+
+// defaultMigrationMaxDeviationPct largest source/destination price gap a position is mirrored across
+const defaultMigrationMaxDeviationPct = 0.5
+
+// MigrateTraderRequest moves a trader to another exchange account
+type MigrateTraderRequest struct {
+	ExchangeID           string  `json:"exchange_id" binding:"required"`
+	Mode                 string  `json:"mode"`                    // mirror (default) or close
+	MaxPriceDeviationPct float64 `json:"max_price_deviation_pct"` // 0 = 0.5%
+	DryRun               bool    `json:"dry_run"`                 // Only check and report the plan
+}
+
+// migrationStep one logged step of a migration
+type migrationStep struct {
+	Step     string    `json:"step"` // plan/cancel/open/protect/close/rollback/switch
+	Symbol   string    `json:"symbol,omitempty"`
+	Side     string    `json:"side,omitempty"`
+	Quantity float64   `json:"quantity,omitempty"`
+	Price    float64   `json:"price,omitempty"`
+	Success  bool      `json:"success"`
+	Message  string    `json:"message,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// migrationPosition a source position and how it is reproduced on the destination
+type migrationPosition struct {
+	Symbol      string
+	Side        string // long/short
+	Quantity    float64
+	Leverage    int
+	SourcePrice float64
+	DestPrice   float64
+	StopLoss    float64
+	TakeProfit  float64
+	Shared      bool // The source position also holds other traders' quantity, only Quantity is closed
+}
+
+// positionMigrator moves the positions of one trader between two exchange clients
+type positionMigrator struct {
+	src, dst        trader.Trader
+	mode            string
+	maxDeviationPct float64
+	killSwitch      *trader.KillSwitch // Checked before every destination open
+	owned           map[string]float64 // Trader's open quantity by symbol_side, nil = every source position
+	steps           []migrationStep
+}
+
+func (m *positionMigrator) log(step migrationStep) {
+	step.Time = time.Now().UTC()
+	m.steps = append(m.steps, step)
+	status := "✓"
+	if !step.Success {
+		status = "❌"
+	}
+	logger.Infof("  🚚 Migration %s %s %s %s %.6f @ %.4f %s", status, step.Step, step.Symbol, step.Side, step.Quantity, step.Price, step.Message)
+}
+
+// plan reads the source positions and checks that each can be reproduced on the destination:
+// the destination lists the symbol and its price is within maxDeviationPct of the source's.
+// Positions of the account the trader does not hold (other traders, manual trades) are left alone.
+func (m *positionMigrator) plan() ([]migrationPosition, error) {
+	positions, err := m.src.GetPositions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source positions: %w", err)
+	}
+
+	var plans []migrationPosition
+	for _, pos := range positions {
+		p := migrationPosition{Leverage: 1}
+		p.Symbol, _ = pos["symbol"].(string)
+		p.Side, _ = pos["side"].(string)
+		if amt, ok := pos["positionAmt"].(float64); ok {
+			p.Quantity = math.Abs(amt)
+		}
+		if lev, ok := pos["leverage"].(float64); ok && lev >= 1 {
+			p.Leverage = int(lev)
+		}
+		if p.Symbol == "" || p.Quantity == 0 || (p.Side != "long" && p.Side != "short") {
+			continue
+		}
+		if m.owned != nil {
+			owned := m.owned[p.Symbol+"_"+p.Side]
+			if owned <= 0 {
+				continue
+			}
+			if owned < p.Quantity {
+				p.Quantity, p.Shared = owned, true
+			}
+		}
+		p.StopLoss, p.TakeProfit = sourceStops(m.src, p.Symbol, strings.ToUpper(p.Side))
+
+		if p.SourcePrice, err = m.src.GetMarketPrice(p.Symbol); err != nil {
+			return nil, fmt.Errorf("%s: failed to get source price: %w", p.Symbol, err)
+		}
+		if m.mode == migrationModeMirror {
+			if p.DestPrice, err = m.dst.GetMarketPrice(p.Symbol); err != nil {
+				return nil, fmt.Errorf("%s: not available on the destination exchange: %w", p.Symbol, err)
+			}
+			if _, err := m.dst.FormatQuantity(p.Symbol, p.Quantity); err != nil {
+				return nil, fmt.Errorf("%s: quantity %.6f cannot be traded on the destination exchange: %w", p.Symbol, p.Quantity, err)
+			}
+			if deviation := math.Abs(p.DestPrice-p.SourcePrice) / p.SourcePrice * 100; deviation > m.maxDeviationPct {
+				return nil, fmt.Errorf("%s: destination price %.4f is %.2f%% away from the source price %.4f (limit %.2f%%)",
+					p.Symbol, p.DestPrice, deviation, p.SourcePrice, m.maxDeviationPct)
+			}
+		}
+		m.log(migrationStep{Step: "plan", Symbol: p.Symbol, Side: p.Side, Quantity: p.Quantity, Price: p.DestPrice, Success: true,
+			Message: fmt.Sprintf("%dx, stop loss %.4f, take profit %.4f", p.Leverage, p.StopLoss, p.TakeProfit)})
+		plans = append(plans, p)
+	}
+	return plans, nil
+}
+
+// execute moves the planned positions, stopping at the first failure
+// A mirrored position is opened and protected on the destination before the source is closed; if its
+// stop loss cannot be placed the destination position is closed again and the source kept.
+func (m *positionMigrator) execute(plans []migrationPosition) error {
+	for _, p := range plans {
+		positionSide := strings.ToUpper(p.Side)
+		if m.mode == migrationModeMirror {
+			// The kill switch may be engaged while earlier positions move
+			if m.killSwitch != nil && m.killSwitch.State().Active {
+				msg := m.killSwitch.State().Message()
+				m.log(migrationStep{Step: "open", Symbol: p.Symbol, Side: p.Side, Quantity: p.Quantity, Message: msg})
+				return fmt.Errorf("%s %s not opened on the destination: %s", p.Symbol, p.Side, msg)
+			}
+			var order map[string]interface{}
+			var err error
+			if p.Side == "long" {
+				order, err = m.dst.OpenLong(p.Symbol, p.Quantity, p.Leverage)
+			} else {
+				order, err = m.dst.OpenShort(p.Symbol, p.Quantity, p.Leverage)
+			}
+			if err != nil {
+				m.log(migrationStep{Step: "open", Symbol: p.Symbol, Side: p.Side, Quantity: p.Quantity, Message: err.Error()})
+				return fmt.Errorf("failed to open %s %s on the destination: %w", p.Symbol, p.Side, err)
+			}
+			m.log(migrationStep{Step: "open", Symbol: p.Symbol, Side: p.Side, Quantity: p.Quantity, Price: p.DestPrice, Success: true,
+				Message: fmt.Sprintf("order %v", order["orderId"])})
+
+			if err := m.protect(p, positionSide); err != nil {
+				return err
+			}
+		}
+
+		closeQty := 0.0 // All
+		if p.Shared {
+			closeQty = p.Quantity
+		}
+		var err error
+		if p.Side == "long" {
+			_, err = m.src.CloseLong(p.Symbol, closeQty)
+		} else {
+			_, err = m.src.CloseShort(p.Symbol, closeQty)
+		}
+		if err != nil {
+			m.log(migrationStep{Step: "close", Symbol: p.Symbol, Side: p.Side, Quantity: p.Quantity, Message: err.Error()})
+			return fmt.Errorf("failed to close %s %s on the source: %w", p.Symbol, p.Side, err)
+		}
+		// Stop loss/take profit and other resting orders of the source position; a shared position
+		// keeps them, they may belong to the other traders
+		if !p.Shared {
+			if err := m.src.CancelAllOrders(p.Symbol); err != nil {
+				logger.Infof("  ⚠️ Failed to cancel source orders of %s: %v", p.Symbol, err)
+			}
+		}
+		m.log(migrationStep{Step: "close", Symbol: p.Symbol, Side: p.Side, Quantity: p.Quantity, Price: p.SourcePrice, Success: true})
+	}
+	return nil
+}
+
+// releaseSource cancels what the trader still has working on the source before it switches exchange,
+// so nothing fills there afterwards or is reconciled against the destination: resting limit entries
+// and iceberg child orders are cancelled and their rows closed, and the tracked emulated stop loss/take
+// profit pairs and ladders are forgotten (their orders go with the positions they protect).
+func (m *positionMigrator) releaseSource(st *store.Store, traderID string) error {
+	const reason = "trader migrated to another exchange"
+	gridTrader, canCancel := m.src.(trader.GridTrader)
+	cancel := func(symbol, orderID string) error {
+		if orderID == "" {
+			return nil
+		}
+		if !canCancel {
+			return fmt.Errorf("source exchange cannot cancel order %s of %s", orderID, symbol)
+		}
+		return gridTrader.CancelOrder(symbol, orderID)
+	}
+
+	pending, err := st.LimitEntry().ListPending(traderID)
+	if err != nil {
+		return err
+	}
+	for _, entry := range pending {
+		if err := cancel(entry.Symbol, entry.OrderID); err != nil {
+			m.log(migrationStep{Step: "cancel", Symbol: entry.Symbol, Side: entry.Side, Quantity: entry.Quantity, Price: entry.Price, Message: "limit entry: " + err.Error()})
+			return fmt.Errorf("failed to cancel the limit entry %s on the source: %w", entry.OrderID, err)
+		}
+		if err := st.LimitEntry().UpdateStatus(entry.ID, store.LimitEntryCancelled, entry.FilledQty, entry.AvgPrice, reason); err != nil {
+			return err
+		}
+		m.log(migrationStep{Step: "cancel", Symbol: entry.Symbol, Side: entry.Side, Quantity: entry.Quantity, Price: entry.Price, Success: true, Message: "limit entry"})
+	}
+
+	splits, err := st.SplitEntry().ListActive(traderID)
+	if err != nil {
+		return err
+	}
+	for _, entry := range splits {
+		if err := cancel(entry.Symbol, entry.OrderID); err != nil {
+			m.log(migrationStep{Step: "cancel", Symbol: entry.Symbol, Side: entry.Side, Quantity: entry.TotalQty - entry.FilledQty, Message: "split entry: " + err.Error()})
+			return fmt.Errorf("failed to cancel the split entry child order %s on the source: %w", entry.OrderID, err)
+		}
+		entry.OrderID, entry.Status, entry.Reason = "", store.SplitEntryCancelled, reason
+		if err := st.SplitEntry().Update(entry); err != nil {
+			return err
+		}
+		m.log(migrationStep{Step: "cancel", Symbol: entry.Symbol, Side: entry.Side, Quantity: entry.TotalQty - entry.FilledQty, Success: true, Message: "split entry"})
+	}
+
+	return st.Protection().DeleteTrader(traderID)
+}
+
+// protect reproduces the source stop loss/take profit on the destination position, closing it
+// again when the stop loss is rejected
+func (m *positionMigrator) protect(p migrationPosition, positionSide string) error {
+	if p.StopLoss > 0 {
+		if err := m.dst.SetStopLoss(p.Symbol, positionSide, p.Quantity, p.StopLoss); err != nil {
+			m.log(migrationStep{Step: "protect", Symbol: p.Symbol, Side: p.Side, Price: p.StopLoss, Message: "stop loss: " + err.Error()})
+			var closeErr error
+			if p.Side == "long" {
+				_, closeErr = m.dst.CloseLong(p.Symbol, 0)
+			} else {
+				_, closeErr = m.dst.CloseShort(p.Symbol, 0)
+			}
+			if closeErr != nil {
+				m.log(migrationStep{Step: "rollback", Symbol: p.Symbol, Side: p.Side, Quantity: p.Quantity, Message: closeErr.Error()})
+				return fmt.Errorf("stop loss of %s %s rejected on the destination and the position could not be closed, close it manually: %w", p.Symbol, p.Side, closeErr)
+			}
+			m.log(migrationStep{Step: "rollback", Symbol: p.Symbol, Side: p.Side, Quantity: p.Quantity, Price: p.DestPrice, Success: true})
+			return fmt.Errorf("stop loss of %s %s rejected on the destination: %w", p.Symbol, p.Side, err)
+		}
+	}
+	if p.TakeProfit > 0 {
+		if err := m.dst.SetTakeProfit(p.Symbol, positionSide, p.Quantity, p.TakeProfit); err != nil {
+			// The position is protected by its stop loss, the trader can set a new take profit
+			m.log(migrationStep{Step: "protect", Symbol: p.Symbol, Side: p.Side, Price: p.TakeProfit, Message: "take profit: " + err.Error()})
+			return nil
+		}
+	}
+	if p.StopLoss > 0 || p.TakeProfit > 0 {
+		m.log(migrationStep{Step: "protect", Symbol: p.Symbol, Side: p.Side, Price: p.StopLoss, Success: true})
+	}
+	return nil
+}
+
+// sourceStops returns the trigger prices of a position's resting stop loss/take profit orders (0 if none)
+func sourceStops(t trader.Trader, symbol, positionSide string) (stopLoss, takeProfit float64) {
+	orders, err := t.GetOpenOrders(symbol)
+	if err != nil {
+		return 0, 0
+	}
+	for _, o := range orders {
+		if o.PositionSide != "" && o.PositionSide != positionSide {
+			continue
+		}
+		orderType := strings.ToUpper(o.Type)
+		switch {
+		case strings.Contains(orderType, "TAKE_PROFIT"):
+			takeProfit = o.StopPrice
+		case strings.Contains(orderType, "STOP"):
+			stopLoss = o.StopPrice
+		}
+	}
+	return stopLoss, takeProfit
+}
+
+// handleMigrateTrader Move a trader to another exchange account (e.g. when an exchange restricts the
+// user's region). Positions are mirrored onto the destination (or only closed), then the trader's
+// exchange is switched. The trader is stopped during the migration and restarted if it was running.
+// Migrations are rejected while the kill switch is engaged.
+func (s *Server) handleMigrateTrader(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	var req MigrateTraderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = migrationModeMirror
+	}
+	if req.Mode != migrationModeMirror && req.Mode != migrationModeClose {
+		SafeBadRequest(c, "mode must be mirror or close")
+		return
+	}
+	if req.MaxPriceDeviationPct <= 0 {
+		req.MaxPriceDeviationPct = defaultMigrationMaxDeviationPct
+	}
+	if state := s.traderManager.KillSwitch().State(); state.Active {
+		c.JSON(http.StatusConflict, gin.H{"error": state.Message()})
+		return
+	}
+
+	fullConfig, err := s.store.Trader().GetFullConfig(userID, traderID)
+	if err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+	source := fullConfig.Exchange
+	if source == nil {
+		SafeBadRequest(c, "Trader's exchange does not exist")
+		return
+	}
+	if fullConfig.Trader.IsInverse() {
+		SafeBadRequest(c, "Inverse contract traders cannot be migrated")
+		return
+	}
+	if fullConfig.Strategy != nil {
+		// Grid levels are resting orders on the source, the grid is rebuilt rather than moved
+		if strategyConfig, err := fullConfig.Strategy.ParseConfig(); err == nil && strategyConfig.StrategyType == "grid_trading" {
+			SafeBadRequest(c, "Grid traders cannot be migrated, stop the grid and create the trader on the destination exchange")
+			return
+		}
+	}
+	dest, err := s.store.Exchange().Resolve(userID, req.ExchangeID)
+	if err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+	if dest.ID == source.ID {
+		SafeBadRequest(c, "Trader already uses this exchange account")
+		return
+	}
+	if !dest.Enabled {
+		SafeBadRequest(c, "Destination exchange is not enabled")
+		return
+	}
+	if dest.KeyVerificationStatus == ExchangeKeyWithdrawalEnabled && !fullConfig.Trader.PaperMode {
+		c.JSON(http.StatusConflict, gin.H{"error": "The destination API key has withdrawal permission, disable it on the exchange and verify the key again"})
+		return
+	}
+
+	migrator := &positionMigrator{mode: req.Mode, maxDeviationPct: req.MaxPriceDeviationPct, killSwitch: s.traderManager.KillSwitch()}
+	// Paper positions are simulated and stay with the trader
+	var plans []migrationPosition
+	if !fullConfig.Trader.PaperMode {
+		if migrator.src, err = newExchangeClient(source, userID); err != nil {
+			SafeInternalError(c, "Failed to connect to source exchange", err)
+			return
+		}
+		if migrator.dst, err = newExchangeClient(dest, userID); err != nil {
+			SafeInternalError(c, "Failed to connect to destination exchange", err)
+			return
+		}
+		owned, err := s.store.Position().GetOpenPositions(traderID)
+		if err != nil {
+			SafeInternalError(c, "Failed to get trader positions", err)
+			return
+		}
+		migrator.owned = make(map[string]float64, len(owned))
+		for _, pos := range owned {
+			migrator.owned[pos.Symbol+"_"+strings.ToLower(pos.Side)] += pos.Quantity
+		}
+		if plans, err = migrator.plan(); err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "steps": migrator.steps})
+			return
+		}
+	}
+	if req.DryRun {
+		c.JSON(http.StatusOK, gin.H{"message": "Migration plan checked", "dry_run": true, "steps": migrator.steps})
+		return
+	}
+
+	// Stop the trader so it does not trade while its positions move
+	wasRunning := false
+	if memTrader, memErr := s.traderManager.GetTrader(traderID); memErr == nil {
+		if running, ok := memTrader.GetStatus()["is_running"].(bool); ok && running {
+			wasRunning = true
+			memTrader.Stop()
+		}
+	}
+	s.traderManager.RemoveTrader(traderID)
+	logger.Infof("🚚 Migrating trader %s from %s to %s (%s, %d positions)", traderID, source.ExchangeType, dest.ExchangeType, req.Mode, len(plans))
+
+	if !fullConfig.Trader.PaperMode {
+		if err := migrator.releaseSource(s.store, traderID); err != nil {
+			// Nothing has moved yet, the trader stays stopped on the source
+			_ = s.store.Trader().UpdateStatus(userID, traderID, false)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Migration failed, trader stopped on the source exchange: " + err.Error(), "steps": migrator.steps})
+			return
+		}
+	}
+
+	if err := migrator.execute(plans); err != nil {
+		// Positions may now be split across both exchanges: keep the trader stopped on the source
+		_ = s.store.Trader().UpdateStatus(userID, traderID, false)
+		s.recordMigrationPositions(traderID, source, dest, migrator.steps)
+		s.recordTraderEvent(traderID, store.TraderEventError, "Exchange migration failed, trader stopped: "+err.Error(), map[string]interface{}{
+			"from_exchange_id": source.ID,
+			"to_exchange_id":   dest.ID,
+			"steps":            migrator.steps,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Migration failed, trader stopped on the source exchange: " + err.Error(), "steps": migrator.steps})
+		return
+	}
+	s.recordMigrationPositions(traderID, source, dest, migrator.steps)
+
+	if err := s.store.Trader().SwitchExchange(userID, traderID, source.ID, dest.ID); err != nil {
+		migrator.log(migrationStep{Step: "switch", Message: err.Error()})
+		_ = s.store.Trader().UpdateStatus(userID, traderID, false)
+		SafeInternalError(c, "Failed to switch trader exchange", err)
+		return
+	}
+	migrator.log(migrationStep{Step: "switch", Success: true, Message: source.ExchangeType + " -> " + dest.ExchangeType})
+	s.recordTraderEvent(traderID, store.TraderEventMigration, fmt.Sprintf("Migrated from %s to %s", source.ExchangeType, dest.ExchangeType), map[string]interface{}{
+		"from_exchange_id": source.ID,
+		"to_exchange_id":   dest.ID,
+		"mode":             req.Mode,
+		"steps":            migrator.steps,
+	})
+
+	if err := s.traderManager.LoadUserTradersFromStore(s.store, userID); err != nil {
+		logger.Infof("⚠️ Failed to reload user traders into memory: %v", err)
+	}
+	if wasRunning {
+		if reloadedTrader, getErr := s.traderManager.GetTrader(traderID); getErr == nil {
+			go func() {
+				logger.Infof("▶️ Restarting trader %s on %s...", traderID, dest.ExchangeType)
+				if runErr := reloadedTrader.Run(); runErr != nil {
+					logger.Infof("❌ Trader %s runtime error: %v", traderID, runErr)
+				}
+			}()
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Trader migrated",
+		"exchange_id": dest.ID,
+		"restarted":   wasRunning,
+		"steps":       migrator.steps,
+	})
+}
+
+// recordMigrationPositions records the migrated positions: source closes are recorded here because the
+// source's OrderSync no longer runs for the trader, destination opens only where no OrderSync will
+func (s *Server) recordMigrationPositions(traderID string, source, dest *store.Exchange, steps []migrationStep) {
+	builder := store.NewPositionBuilder(s.store.Position())
+	for _, step := range steps {
+		if !step.Success {
+			continue
+		}
+		var ex *store.Exchange
+		var action string
+		switch step.Step {
+		case "close":
+			ex, action = source, "close_"+step.Side
+		case "open", "rollback":
+			if trader.HasOrderSync(dest.ExchangeType) {
+				continue
+			}
+			ex, action = dest, "open_"+step.Side
+			if step.Step == "rollback" {
+				action = "close_" + step.Side
+			}
+		default:
+			continue
+		}
+		if err := builder.ProcessTrade(traderID, ex.ID, ex.ExchangeType, step.Symbol, strings.ToUpper(step.Side), action,
+			step.Quantity, step.Price, 0, 0, step.Time.UnixMilli(), "migration"); err != nil {
+			logger.Infof("  ⚠️ Failed to record migrated position %s %s: %v", step.Symbol, step.Side, err)
+		}
+	}
+}
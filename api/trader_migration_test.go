@@ -0,0 +1,227 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nofx/manager"
+	"nofx/store"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stubMigrationExchange one side of a migration; any other call panics on the nil embedded Trader
+type stubMigrationExchange struct {
+	trader.Trader
+	price     float64
+	positions []map[string]interface{}
+	stops     []trader.OpenOrder
+	slErr     error
+	calls     []string
+}
+
+func (s *stubMigrationExchange) GetPositions() ([]map[string]interface{}, error) {
+	return s.positions, nil
+}
+
+func (s *stubMigrationExchange) GetMarketPrice(string) (float64, error) {
+	return s.price, nil
+}
+
+func (s *stubMigrationExchange) GetOpenOrders(string) ([]trader.OpenOrder, error) {
+	return s.stops, nil
+}
+
+func (s *stubMigrationExchange) FormatQuantity(string, float64) (string, error) {
+	return "1", nil
+}
+
+func (s *stubMigrationExchange) CancelAllOrders(symbol string) error {
+	s.calls = append(s.calls, "cancel "+symbol)
+	return nil
+}
+
+func (s *stubMigrationExchange) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	s.calls = append(s.calls, "open_long "+symbol)
+	return map[string]interface{}{"orderId": int64(1)}, nil
+}
+
+func (s *stubMigrationExchange) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity > 0 {
+		s.calls = append(s.calls, fmt.Sprintf("close_long %s %.2f", symbol, quantity))
+	} else {
+		s.calls = append(s.calls, "close_long "+symbol)
+	}
+	return map[string]interface{}{"orderId": int64(2)}, nil
+}
+
+// stubCancellingExchange a source that can cancel single orders
+type stubCancellingExchange struct {
+	stubMigrationExchange
+}
+
+func (s *stubCancellingExchange) PlaceLimitOrder(*trader.LimitOrderRequest) (*trader.LimitOrderResult, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s *stubCancellingExchange) GetOrderBook(string, int) ([][]float64, [][]float64, error) {
+	return nil, nil, nil
+}
+
+func (s *stubCancellingExchange) CancelOrder(symbol, orderID string) error {
+	s.calls = append(s.calls, "cancel_order "+orderID)
+	return nil
+}
+
+func (s *stubMigrationExchange) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	s.calls = append(s.calls, "stop_loss "+symbol)
+	return s.slErr
+}
+
+func (s *stubMigrationExchange) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	s.calls = append(s.calls, "take_profit "+symbol)
+	return nil
+}
+
+func newMigrationSource() *stubMigrationExchange {
+	return &stubMigrationExchange{
+		price:     100,
+		positions: []map[string]interface{}{{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.5, "leverage": 5.0}},
+		stops: []trader.OpenOrder{
+			{PositionSide: "LONG", Type: "STOP_MARKET", StopPrice: 90},
+			{PositionSide: "LONG", Type: "TAKE_PROFIT_MARKET", StopPrice: 120},
+		},
+	}
+}
+
+func TestPositionMigrator_MirrorsThenClosesSource(t *testing.T) {
+	src, dst := newMigrationSource(), &stubMigrationExchange{price: 100.2}
+	m := &positionMigrator{src: src, dst: dst, mode: migrationModeMirror, maxDeviationPct: 0.5}
+
+	plans, err := m.plan()
+	if err != nil || len(plans) != 1 {
+		t.Fatalf("plan: %v %+v", err, plans)
+	}
+	if p := plans[0]; p.StopLoss != 90 || p.TakeProfit != 120 || p.Leverage != 5 {
+		t.Errorf("source protection and leverage should be carried over: %+v", p)
+	}
+	if err := m.execute(plans); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if got := strings.Join(dst.calls, ","); got != "open_long BTCUSDT,stop_loss BTCUSDT,take_profit BTCUSDT" {
+		t.Errorf("destination calls: %s", got)
+	}
+	if got := strings.Join(src.calls, ","); got != "close_long BTCUSDT,cancel BTCUSDT" {
+		t.Errorf("source calls: %s", got)
+	}
+}
+
+func TestPositionMigrator_PriceCheckAndRollback(t *testing.T) {
+	m := &positionMigrator{src: newMigrationSource(), dst: &stubMigrationExchange{price: 102}, mode: migrationModeMirror, maxDeviationPct: 0.5}
+	if _, err := m.plan(); err == nil || !strings.Contains(err.Error(), "away from the source price") {
+		t.Errorf("a 2%% price gap should stop the migration, got %v", err)
+	}
+
+	src, dst := newMigrationSource(), &stubMigrationExchange{price: 100, slErr: errors.New("rejected")}
+	m = &positionMigrator{src: src, dst: dst, mode: migrationModeMirror, maxDeviationPct: 0.5}
+	plans, _ := m.plan()
+	if err := m.execute(plans); err == nil {
+		t.Fatal("a rejected stop loss should fail the migration")
+	}
+	if got := strings.Join(dst.calls, ","); got != "open_long BTCUSDT,stop_loss BTCUSDT,close_long BTCUSDT" {
+		t.Errorf("unprotected destination position should be closed again: %s", got)
+	}
+	if len(src.calls) != 0 {
+		t.Errorf("source must be kept: %v", src.calls)
+	}
+}
+
+func TestPositionMigrator_StopsAtKillSwitch(t *testing.T) {
+	src, dst := newMigrationSource(), &stubMigrationExchange{price: 100}
+	killSwitch := trader.NewKillSwitch()
+	m := &positionMigrator{src: src, dst: dst, mode: migrationModeMirror, maxDeviationPct: 0.5, killSwitch: killSwitch}
+	plans, err := m.plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	killSwitch.Set(trader.KillSwitchState{Active: true, Reason: "exchange incident"})
+	if err := m.execute(plans); err == nil || !strings.Contains(err.Error(), "exchange incident") {
+		t.Fatalf("an engaged kill switch should stop the migration, got %v", err)
+	}
+	if len(dst.calls) != 0 || len(src.calls) != 0 {
+		t.Errorf("nothing should be opened or closed: dst=%v src=%v", dst.calls, src.calls)
+	}
+}
+
+func TestHandleMigrateTrader_RejectedWhileKillSwitchEngaged(t *testing.T) {
+	tm := manager.NewTraderManager()
+	tm.KillSwitch().Set(trader.KillSwitchState{Active: true, Reason: "maintenance"})
+	s := &Server{traderManager: tm}
+
+	w := callHandler(s.handleMigrateTrader, testRequest{method: http.MethodPost, target: "/api/traders/t1/migrate",
+		body: `{"exchange_id":"ex2"}`, userID: "u1", params: gin.Params{{Key: "id", Value: "t1"}}})
+
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "maintenance") {
+		t.Errorf("expected 409 with the kill switch reason, got %d %s", w.Code, w.Body)
+	}
+}
+
+func TestPositionMigrator_OnlyMovesTraderPositions(t *testing.T) {
+	src, dst := newMigrationSource(), &stubMigrationExchange{price: 100}
+	src.positions = append(src.positions, map[string]interface{}{"symbol": "ETHUSDT", "side": "long", "positionAmt": 2.0})
+	// Another trader on the account holds ETHUSDT and part of BTCUSDT
+	m := &positionMigrator{src: src, dst: dst, mode: migrationModeMirror, maxDeviationPct: 0.5, owned: map[string]float64{"BTCUSDT_long": 0.2}}
+
+	plans, err := m.plan()
+	if err != nil || len(plans) != 1 || plans[0].Symbol != "BTCUSDT" || plans[0].Quantity != 0.2 {
+		t.Fatalf("plan: %v %+v", err, plans)
+	}
+	if err := m.execute(plans); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if got := strings.Join(src.calls, ","); got != "close_long BTCUSDT 0.20" {
+		t.Errorf("only the trader's quantity should be closed, keeping the shared orders: %s", got)
+	}
+}
+
+func TestPositionMigrator_ReleaseSource(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+
+	if err := st.LimitEntry().Create(&store.LimitEntry{TraderID: "t1", Symbol: "SOLUSDT", Side: "long", OrderID: "L1", Price: 100, Quantity: 1, ExpiresAt: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SplitEntry().Create(&store.SplitEntry{TraderID: "t1", Symbol: "ETHUSDT", Side: "long", Algo: store.ExecutionIceberg, TotalQty: 2, Slices: 4, OrderID: "S1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Protection().Save("t1", store.ProtectionOCO, "BTCUSDT", "LONG", map[string]float64{"Quantity": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	src := &stubCancellingExchange{}
+	m := &positionMigrator{src: src}
+	if err := m.releaseSource(st, "t1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(src.calls, ","); got != "cancel_order L1,cancel_order S1" {
+		t.Errorf("source calls: %s", got)
+	}
+	if pending, _ := st.LimitEntry().ListPending("t1"); len(pending) != 0 {
+		t.Errorf("limit entry should be cancelled: %+v", pending)
+	}
+	if active, _ := st.SplitEntry().ListActive("t1"); len(active) != 0 {
+		t.Errorf("split entry should be cancelled: %+v", active)
+	}
+	if saved, _ := st.Protection().List("t1", store.ProtectionOCO); len(saved) != 0 {
+		t.Errorf("protections should be forgotten: %+v", saved)
+	}
+}
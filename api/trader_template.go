@@ -0,0 +1,237 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"nofx/logger"
+	"nofx/store"
+	"nofx/trader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DuplicateTraderRequest Duplicate trader request
+// Empty fields inherit the source trader's value
+type DuplicateTraderRequest struct {
+	Name       string `json:"name"`
+	AIModelID  string `json:"ai_model_id"`
+	ExchangeID string `json:"exchange_id"`
+}
+
+// ImportTraderRequest Import trader from exported template
+type ImportTraderRequest struct {
+	Template       *store.TraderTemplate `json:"template" binding:"required"`
+	Name           string                `json:"name"`
+	AIModelID      string                `json:"ai_model_id" binding:"required"`
+	ExchangeID     string                `json:"exchange_id" binding:"required"`
+	InitialBalance float64               `json:"initial_balance"`
+}
+
+// handleDuplicateTrader Copy trader configuration to a new trader
+func (s *Server) handleDuplicateTrader(c *gin.Context) {
+	userID := c.GetString("user_id")
+	sourceID := c.Param("id")
+
+	var req DuplicateTraderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	source, err := s.store.Trader().Get(userID, sourceID)
+	if err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	clone := duplicateTraderConfig(source)
+	if req.Name != "" {
+		clone.Name = req.Name
+	} else {
+		clone.Name = source.Name + " (copy)"
+	}
+	if req.AIModelID != "" {
+		clone.AIModelID = req.AIModelID
+	}
+	if req.ExchangeID != "" {
+		clone.ExchangeID = req.ExchangeID
+	}
+	if !s.validateTraderTargets(c, userID, clone.AIModelID, clone.ExchangeID) {
+		return
+	}
+	if clone.IsInverse() && clone.ExchangeID != source.ExchangeID {
+		if _, _, ok := s.resolveContractType(c, userID, clone.ExchangeID, clone.ContractType, clone.MarginCoin); !ok {
+			return
+		}
+	}
+
+	clone.ID = newTraderID(clone.ExchangeID, clone.AIModelID)
+	if clone.ExchangeID != source.ExchangeID {
+		// Different account: P&L baseline must come from the new exchange
+		clone.InitialBalance = s.queryExchangeEquity(userID, clone.ExchangeID, clone.MarginCoin, source.InitialBalance)
+	}
+
+	if err := s.store.Trader().Create(clone); err != nil {
+		SafeInternalError(c, "Failed to duplicate trader", err)
+		return
+	}
+	if err := s.traderManager.LoadUserTradersFromStore(s.store, userID); err != nil {
+		logger.Infof("⚠️ Failed to load user traders into memory: %v", err)
+	}
+
+	logger.Infof("✓ Trader duplicated: %s -> %s (model: %s, exchange: %s)", sourceID, clone.ID, clone.AIModelID, clone.ExchangeID)
+	c.JSON(http.StatusCreated, gin.H{
+		"trader_id":   clone.ID,
+		"trader_name": clone.Name,
+		"ai_model":    clone.AIModelID,
+		"is_running":  false,
+	})
+}
+
+// duplicateTraderConfig copies the configuration fields of a trader into a new, stopped trader record
+// Fields are copied one by one so identity, run state, timestamps and soft-delete state (and any
+// runtime field added later) are never carried over; the caller assigns the new ID.
+func duplicateTraderConfig(source *store.Trader) *store.Trader {
+	return &store.Trader{
+		UserID:              source.UserID,
+		Name:                source.Name,
+		AIModelID:           source.AIModelID,
+		ExchangeID:          source.ExchangeID,
+		StrategyID:          source.StrategyID,
+		InitialBalance:      source.InitialBalance,
+		ScanIntervalMinutes: source.ScanIntervalMinutes,
+		ScanIntervalSeconds: source.ScanIntervalSeconds,
+		IsCrossMargin:       source.IsCrossMargin,
+		ShowInCompetition:   source.ShowInCompetition,
+		PaperMode:           source.PaperMode,
+		FallbackModelIDs:    source.FallbackModelIDs,
+		ContractType:        source.ContractType,
+		MarginCoin:          source.MarginCoin,
+
+		BTCETHLeverage:       source.BTCETHLeverage,
+		AltcoinLeverage:      source.AltcoinLeverage,
+		TradingSymbols:       source.TradingSymbols,
+		UseAI500:             source.UseAI500,
+		UseOITop:             source.UseOITop,
+		CustomPrompt:         source.CustomPrompt,
+		OverrideBasePrompt:   source.OverrideBasePrompt,
+		SystemPromptTemplate: source.SystemPromptTemplate,
+	}
+}
+
+// handleExportTrader Export trader configuration as shareable JSON template
+func (s *Server) handleExportTrader(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	tpl, err := s.store.Trader().ExportTemplate(userID, traderID)
+	if err != nil {
+		SafeNotFound(c, "Trader")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"trader_%s.json\"", traderID))
+	c.JSON(http.StatusOK, tpl)
+}
+
+// handleImportTrader Create trader from exported JSON template
+func (s *Server) handleImportTrader(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req ImportTraderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	tpl := req.Template
+	if err := tpl.Validate(); err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+	// Same checks as creating a trader: out-of-range values are rejected, not stored
+	if err := validateTraderSettings(tpl.BTCETHLeverage, tpl.AltcoinLeverage, tpl.TradingSymbols); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !s.validateTraderTargets(c, userID, req.AIModelID, req.ExchangeID) {
+		return
+	}
+
+	name := req.Name
+	if name == "" {
+		name = req.Template.Name
+	}
+	if name == "" {
+		SafeBadRequest(c, "Trader name is required")
+		return
+	}
+
+	settings := s.settings.get()
+	if tpl.BTCETHLeverage == 0 {
+		tpl.BTCETHLeverage = settings.DefaultBTCETHLeverage
+	}
+	if tpl.AltcoinLeverage == 0 {
+		tpl.AltcoinLeverage = settings.DefaultAltcoinLeverage
+	}
+
+	// Same floors as a newly created trader: system minimum and the AI provider's rate limits
+	requestedInterval := tpl.ScanInterval()
+	if requestedInterval <= 0 {
+		requestedInterval = trader.DefaultMinScanInterval
+	}
+	var warnings []string
+	tpl.ScanIntervalMinutes, tpl.ScanIntervalSeconds, warnings = s.resolveScanInterval(userID, req.AIModelID, requestedInterval)
+
+	// Prompt templates are per installation, an unknown one falls back to the strategy's own prompt
+	if id := tpl.SystemPromptTemplate; id != "" && id != store.BuiltinPromptTemplateDefault {
+		if _, err := s.store.PromptTemplate().Get(userID, id); err != nil {
+			warnings = append(warnings, fmt.Sprintf("Prompt template %q not found, using the default prompt.", id))
+			tpl.SystemPromptTemplate = store.BuiltinPromptTemplateDefault
+		}
+	}
+	if tpl.Strategy != nil {
+		var strategyConfig store.StrategyConfig
+		json.Unmarshal(tpl.Strategy.Config, &strategyConfig) // Checked by Validate
+		warnings = append(warnings, validateStrategyConfig(&strategyConfig)...)
+	}
+
+	traderRecord := &store.Trader{
+		ID:             newTraderID(req.ExchangeID, req.AIModelID),
+		UserID:         userID,
+		Name:           name,
+		AIModelID:      req.AIModelID,
+		ExchangeID:     req.ExchangeID,
+		InitialBalance: s.queryExchangeEquity(userID, req.ExchangeID, "", req.InitialBalance),
+	}
+	if err := s.store.Trader().CreateFromTemplate(tpl, traderRecord); err != nil {
+		SafeInternalError(c, "Failed to import trader", err)
+		return
+	}
+	if err := s.traderManager.LoadUserTradersFromStore(s.store, userID); err != nil {
+		logger.Infof("⚠️ Failed to load user traders into memory: %v", err)
+	}
+
+	logger.Infof("✓ Trader imported: %s (model: %s, exchange: %s, strategy: %s)", traderRecord.ID, req.AIModelID, req.ExchangeID, traderRecord.StrategyID)
+	c.JSON(http.StatusCreated, gin.H{
+		"trader_id":   traderRecord.ID,
+		"trader_name": traderRecord.Name,
+		"ai_model":    traderRecord.AIModelID,
+		"strategy_id": traderRecord.StrategyID,
+		"is_running":  false,
+		"warnings":    warnings,
+	})
+}
+
+// validateTraderTargets ensures AI model and exchange belong to user, writes error response otherwise
+func (s *Server) validateTraderTargets(c *gin.Context, userID, aiModelID, exchangeID string) bool {
+	if _, err := s.store.AIModel().Get(userID, aiModelID); err != nil {
+		SafeBadRequest(c, "AI model not found")
+		return false
+	}
+	if _, err := s.store.Exchange().GetByID(userID, exchangeID); err != nil {
+		SafeBadRequest(c, "Exchange not found")
+		return false
+	}
+	return true
+}
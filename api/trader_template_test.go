@@ -0,0 +1,178 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"nofx/manager"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newDuplicateTraderServer(t *testing.T) (*Server, *store.Store) {
+	t.Helper()
+	s, _ := newTestServer(t)
+	s.traderManager = manager.NewTraderManager()
+	st := s.store
+
+	// Disabled model: the reload after duplicating skips the traders instead of starting them
+	if err := st.AIModel().Create("u1", "u1_deepseek", "DeepSeek AI", "deepseek", false, "sk-model", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.GormDB().Create(&store.Exchange{ID: "ex-1", ExchangeType: "binance", AccountName: "Default", UserID: "u1",
+		Name: "Binance", Type: "cex"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return s, st
+}
+
+func duplicateTrader(s *Server, userID, sourceID, body string) *httptest.ResponseRecorder {
+	return callHandler(s.handleDuplicateTrader, testRequest{method: http.MethodPost, target: "/api/traders/" + sourceID + "/duplicate",
+		body: body, userID: userID, params: gin.Params{{Key: "id", Value: sourceID}}})
+}
+
+func TestHandleDuplicateTrader_CopiesConfigOnly(t *testing.T) {
+	s, st := newDuplicateTraderServer(t)
+	source := &store.Trader{ID: "tr-1", UserID: "u1", Name: "BTC bot", AIModelID: "u1_deepseek", ExchangeID: "ex-1",
+		StrategyID: "st-1", InitialBalance: 1000, ScanIntervalSeconds: 30, IsRunning: true, PaperMode: true,
+		FallbackModelIDs: "u1_deepseek", CustomPrompt: "be careful", CreatedAt: time.Now().Add(-30 * 24 * time.Hour)}
+	if err := st.Trader().Create(source); err != nil {
+		t.Fatal(err)
+	}
+
+	w := duplicateTrader(s, "u1", "tr-1", `{}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d %s", w.Code, w.Body)
+	}
+	var resp struct {
+		TraderID string `json:"trader_id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	clone, err := st.Trader().Get("u1", resp.TraderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.ID == source.ID || clone.IsRunning || time.Since(clone.CreatedAt) > time.Hour {
+		t.Errorf("clone must get a new ID, be stopped and have its own timestamps: %+v", clone)
+	}
+	if clone.Name != "BTC bot (copy)" || clone.StrategyID != "st-1" || clone.InitialBalance != 1000 ||
+		clone.ScanIntervalSeconds != 30 || !clone.PaperMode || clone.FallbackModelIDs != "u1_deepseek" || clone.CustomPrompt != "be careful" {
+		t.Errorf("configuration should be copied: %+v", clone)
+	}
+	if got, _ := st.Trader().Get("u1", "tr-1"); got == nil || !got.IsRunning {
+		t.Error("source trader must be left untouched")
+	}
+}
+
+func TestHandleDuplicateTrader_OtherUsersTrader(t *testing.T) {
+	s, st := newDuplicateTraderServer(t)
+	if err := st.Trader().Create(&store.Trader{ID: "tr-1", UserID: "u1", Name: "BTC bot", AIModelID: "u1_deepseek", ExchangeID: "ex-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if w := duplicateTrader(s, "u2", "tr-1", `{}`); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for another user's trader, got %d", w.Code)
+	}
+}
+
+func TestHandleDuplicateTrader_UnknownTargets(t *testing.T) {
+	s, st := newDuplicateTraderServer(t)
+	if err := st.Trader().Create(&store.Trader{ID: "tr-1", UserID: "u1", Name: "BTC bot", AIModelID: "u1_deepseek", ExchangeID: "ex-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if w := duplicateTrader(s, "u1", "tr-1", `{"exchange_id":"missing"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown exchange, got %d", w.Code)
+	}
+}
+
+func importTrader(s *Server, userID, body string) *httptest.ResponseRecorder {
+	return callHandler(s.handleImportTrader, testRequest{method: http.MethodPost, target: "/api/traders/import", body: body, userID: userID})
+}
+
+func TestHandleImportTrader_ScanInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		seconds     int
+		wantMinutes int
+		wantSeconds int
+		wantWarning bool
+	}{
+		{"sub-minute interval kept", 450, 8, 450, false},
+		{"raised to the system minimum", 10, 3, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, st := newDuplicateTraderServer(t)
+			body := `{"ai_model_id":"u1_deepseek","exchange_id":"ex-1","initial_balance":1000,"template":{"version":1,"name":"imported",` +
+				`"scan_interval_minutes":1,"scan_interval_seconds":` + strconv.Itoa(tt.seconds) + `}}`
+			w := importTrader(s, "u1", body)
+			if w.Code != http.StatusCreated {
+				t.Fatalf("expected 201, got %d %s", w.Code, w.Body)
+			}
+			var resp struct {
+				TraderID string   `json:"trader_id"`
+				Warnings []string `json:"warnings"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			imported, err := st.Trader().Get("u1", resp.TraderID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if imported.ScanIntervalMinutes != tt.wantMinutes || imported.ScanIntervalSeconds != tt.wantSeconds {
+				t.Errorf("interval = %d min / %d s, want %d min / %d s", imported.ScanIntervalMinutes, imported.ScanIntervalSeconds, tt.wantMinutes, tt.wantSeconds)
+			}
+			if got := len(resp.Warnings) > 0; got != tt.wantWarning {
+				t.Errorf("warnings = %v, want warning: %v", resp.Warnings, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestHandleImportTrader_ValidatesSettings(t *testing.T) {
+	s, st := newDuplicateTraderServer(t)
+
+	rejected := map[string]string{
+		"leverage out of range": `{"version":1,"name":"x","btc_eth_leverage":100}`,
+		"invalid symbol":        `{"version":1,"name":"x","trading_symbols":"BTCUSD"}`,
+		"invalid grid":          `{"version":1,"name":"x","strategy":{"name":"g","config":{"strategy_type":"grid_trading","grid_config":{"grid_count":1}}}}`,
+	}
+	for name, tpl := range rejected {
+		w := importTrader(s, "u1", `{"ai_model_id":"u1_deepseek","exchange_id":"ex-1","template":`+tpl+`}`)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d %s", name, w.Code, w.Body)
+		}
+	}
+	if traders, _ := st.Trader().List("u1"); len(traders) != 0 {
+		t.Fatalf("rejected imports must not create traders: %+v", traders)
+	}
+
+	// Installation-specific prompt template falls back to the default with a warning
+	w := importTrader(s, "u1", `{"ai_model_id":"u1_deepseek","exchange_id":"ex-1","initial_balance":1000,`+
+		`"template":{"version":1,"name":"x","system_prompt_template":"tpl-elsewhere"}}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d %s", w.Code, w.Body)
+	}
+	var resp struct {
+		TraderID string   `json:"trader_id"`
+		Warnings []string `json:"warnings"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := st.Trader().Get("u1", resp.TraderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.SystemPromptTemplate != store.BuiltinPromptTemplateDefault || imported.BTCETHLeverage == 0 || len(resp.Warnings) == 0 {
+		t.Errorf("expected default prompt template, default leverage and a warning: %+v %v", imported, resp.Warnings)
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"nofx/config"
+	"nofx/logger"
+	"nofx/store"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// handleListTrash Traders in the trash, with when each will be purged (null when purging is disabled)
+func (s *Server) handleListTrash(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	traders, err := s.store.Trader().ListDeleted(userID)
+	if err != nil {
+		SafeInternalError(c, "Failed to get deleted traders", err)
+		return
+	}
+
+	retentionDays := config.Get().TraderTrashRetentionDays
+	result := make([]gin.H, 0, len(traders))
+	for _, t := range traders {
+		var purgeAt *time.Time
+		if retentionDays > 0 {
+			at := t.DeletedAt.Time.AddDate(0, 0, retentionDays)
+			purgeAt = &at
+		}
+		result = append(result, gin.H{
+			"trader_id":   t.ID,
+			"trader_name": t.Name,
+			"ai_model_id": t.AIModelID,
+			"exchange_id": t.ExchangeID,
+			"paper_mode":  t.PaperMode,
+			"created_at":  t.CreatedAt,
+			"deleted_at":  t.DeletedAt.Time,
+			"purge_at":    purgeAt,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"traders": result, "retention_days": retentionDays})
+}
+
+// handleRestoreTrader Move a trader out of the trash, stopped and with its history intact
+func (s *Server) handleRestoreTrader(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if err := s.store.Trader().Restore(userID, traderID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			SafeNotFound(c, "Deleted trader")
+			return
+		}
+		SafeInternalError(c, "Failed to restore trader", err)
+		return
+	}
+	s.recordTraderEvent(traderID, store.TraderEventRestore, "Trader restored from trash", nil)
+
+	if err := s.traderManager.LoadUserTradersFromStore(s.store, userID); err != nil {
+		logger.Infof("⚠️ Failed to load user traders into memory: %v", err)
+	}
+
+	logger.Infof("✓ Trader restored from trash: %s", traderID)
+	c.JSON(http.StatusOK, gin.H{"message": "Trader restored", "trader_id": traderID})
+}
+
+// handlePurgeTrader Permanently delete a trader in the trash and its associated data
+func (s *Server) handlePurgeTrader(c *gin.Context) {
+	userID := c.GetString("user_id")
+	traderID := c.Param("id")
+
+	if err := s.store.Trader().Purge(userID, traderID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			SafeNotFound(c, "Deleted trader")
+			return
+		}
+		SafeInternalError(c, "Failed to purge trader", err)
+		return
+	}
+
+	logger.Infof("✓ Trader purged: %s", traderID)
+	c.JSON(http.StatusOK, gin.H{"message": "Trader permanently deleted"})
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTraderTrash(t *testing.T) {
+	s, call := newTestServer(t)
+	st := s.store
+
+	for _, id := range []string{"t1", "t2"} {
+		if err := st.Trader().Create(&store.Trader{ID: id, UserID: "alice", Name: id, IsRunning: true}); err != nil {
+			t.Fatalf("create trader: %v", err)
+		}
+	}
+	if err := st.Equity().Save(&store.EquitySnapshot{TraderID: "t1", Timestamp: time.Now(), TotalEquity: 100}); err != nil {
+		t.Fatalf("save equity: %v", err)
+	}
+
+	callTrader := func(handler gin.HandlerFunc, userID, id string) *httptest.ResponseRecorder {
+		return call(handler, testRequest{userID: userID, params: gin.Params{{Key: "id", Value: id}}})
+	}
+	trash := func() []map[string]interface{} {
+		var resp struct {
+			Traders []map[string]interface{} `json:"traders"`
+		}
+		_ = json.Unmarshal(callTrader(s.handleListTrash, "alice", "").Body.Bytes(), &resp)
+		return resp.Traders
+	}
+
+	if err := st.Trader().Delete("bob", "t1"); err == nil {
+		t.Error("other users must not delete the trader")
+	}
+	if err := st.Trader().Delete("alice", "t1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if traders, _ := st.Trader().List("alice"); len(traders) != 1 || traders[0].ID != "t2" {
+		t.Errorf("deleted traders should be hidden from the trader list, got %d traders", len(traders))
+	}
+	if _, err := st.Trader().GetByID("t1"); err == nil {
+		t.Error("deleted traders should not be found by ID")
+	}
+	if items := trash(); len(items) != 1 || items[0]["trader_id"] != "t1" || items[0]["purge_at"] == nil {
+		t.Fatalf("trash should list t1 with its purge time, got %v", items)
+	}
+
+	// Restored traders come back stopped, with their history
+	if err := st.Trader().Restore("alice", "t1"); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	restored, err := st.Trader().GetByID("t1")
+	if err != nil || restored.IsRunning {
+		t.Fatalf("restored trader should be found and stopped, got %+v, %v", restored, err)
+	}
+	if n, _ := st.Equity().GetCount("t1"); n != 1 {
+		t.Errorf("equity history should survive the trash, got %d snapshots", n)
+	}
+	if err := st.Trader().Restore("alice", "t1"); err == nil {
+		t.Error("restoring a trader that is not in the trash should fail")
+	}
+
+	// Purging is only possible from the trash and removes associated data
+	if w := callTrader(s.handlePurgeTrader, "alice", "t2"); w.Code != http.StatusNotFound {
+		t.Errorf("purging a trader outside the trash should be 404, got %d", w.Code)
+	}
+	_ = st.Trader().Delete("alice", "t1")
+	if w := callTrader(s.handlePurgeTrader, "alice", "t1"); w.Code != http.StatusOK {
+		t.Fatalf("purge failed with status %d: %s", w.Code, w.Body.String())
+	}
+	if n, _ := st.Equity().GetCount("t1"); n != 0 || len(trash()) != 0 {
+		t.Errorf("purged trader should leave no equity snapshots (%d) or trash entry", n)
+	}
+
+	// Scheduled purge only removes traders deleted before the cutoff
+	_ = st.Trader().Delete("alice", "t2")
+	if n, err := st.Trader().PurgeDeletedBefore(time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Errorf("recently deleted traders should be kept, purged %d (%v)", n, err)
+	}
+	if n, err := st.Trader().PurgeDeletedBefore(time.Now().Add(time.Hour)); err != nil || n != 1 {
+		t.Errorf("expected 1 trader purged, got %d (%v)", n, err)
+	}
+}
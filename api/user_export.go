@@ -0,0 +1,152 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"nofx/crypto"
+	"nofx/logger"
+	"nofx/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	userArchiveFormat = "nofx-user-export"
+	// minArchivePassphrase the archive holds exchange keys, so short passphrases are refused
+	minArchivePassphrase = 12
+	// exportDecisionsPerTrader latest decisions of each trader included in an export
+	exportDecisionsPerTrader = 1000
+	// maxArchiveBytes decompressed size accepted on import
+	maxArchiveBytes = 256 << 20
+)
+
+// UserArchive passphrase-encrypted, gzip-compressed store.UserExport
+type UserArchive struct {
+	Format     string                     `json:"format"`
+	Version    int                        `json:"version"`
+	ExportedAt time.Time                  `json:"exported_at"`
+	Envelope   *crypto.PassphraseEnvelope `json:"envelope"`
+}
+
+// sealUserExport compresses the export and encrypts it with passphrase
+func sealUserExport(export *store.UserExport, passphrase string) (*UserArchive, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(export); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	envelope, err := crypto.SealWithPassphrase(buf.Bytes(), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &UserArchive{Format: userArchiveFormat, Version: export.Version, ExportedAt: export.ExportedAt, Envelope: envelope}, nil
+}
+
+// openUserArchive decrypts and decompresses an archive
+func openUserArchive(archive *UserArchive, passphrase string) (*store.UserExport, error) {
+	if archive.Format != userArchiveFormat || archive.Envelope == nil {
+		return nil, errors.New("not a NOFX export archive")
+	}
+	compressed, err := crypto.OpenWithPassphrase(archive.Envelope, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive content: %w", err)
+	}
+	defer zr.Close()
+
+	var export store.UserExport
+	if err := json.NewDecoder(io.LimitReader(zr, maxArchiveBytes)).Decode(&export); err != nil {
+		return nil, fmt.Errorf("invalid archive content: %w", err)
+	}
+	return &export, nil
+}
+
+// handleExportUserData download all of the user's configuration as an encrypted archive
+// Traders, strategies, AI models, exchange accounts (with their keys) and recent decisions, sealed
+// with the passphrase from the request body so it can be imported on another installation.
+func (s *Server) handleExportUserData(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req struct {
+		Passphrase       string `json:"passphrase" binding:"required"`
+		IncludeDecisions *bool  `json:"include_decisions"` // Default true
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if len(req.Passphrase) < minArchivePassphrase {
+		SafeBadRequest(c, fmt.Sprintf("Passphrase must be at least %d characters", minArchivePassphrase))
+		return
+	}
+	decisions := exportDecisionsPerTrader
+	if req.IncludeDecisions != nil && !*req.IncludeDecisions {
+		decisions = 0
+	}
+
+	export, err := s.store.ExportUser(userID, decisions)
+	if err != nil {
+		SafeInternalError(c, "Export user data", err)
+		return
+	}
+	archive, err := sealUserExport(export, req.Passphrase)
+	if err != nil {
+		SafeInternalError(c, "Encrypt export", err)
+		return
+	}
+
+	logger.Infof("📦 User %s exported %d traders, %d strategies, %d models, %d exchanges, %d decisions",
+		userID, len(export.Traders), len(export.Strategies), len(export.AIModels), len(export.Exchanges), len(export.Decisions))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"nofx_export_%s.json\"", export.ExportedAt.Format("20060102_150405")))
+	c.JSON(http.StatusOK, archive)
+}
+
+// handleImportUserData create the records of an encrypted archive for the current user
+// Imported traders stay stopped until started.
+func (s *Server) handleImportUserData(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req struct {
+		Passphrase string       `json:"passphrase" binding:"required"`
+		Archive    *UserArchive `json:"archive" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	export, err := openUserArchive(req.Archive, req.Passphrase)
+	if errors.Is(err, crypto.ErrWrongPassphrase) {
+		SafeBadRequest(c, "Wrong passphrase or corrupted archive")
+		return
+	}
+	if err != nil {
+		SafeBadRequest(c, err.Error())
+		return
+	}
+
+	result, err := s.store.ImportUser(userID, export)
+	if err != nil {
+		SafeInternalError(c, "Import user data", err)
+		return
+	}
+	if err := s.traderManager.LoadUserTradersFromStore(s.store, userID); err != nil {
+		logger.Warnf("⚠️ Failed to load imported traders into memory: %v", err)
+	}
+
+	logger.Infof("📦 User %s imported %d traders, %d strategies, %d models, %d exchanges, %d decisions (%d IDs renamed)",
+		userID, result.Traders, result.Strategies, result.AIModels, result.Exchanges, result.Decisions, len(result.RenamedIDs))
+	c.JSON(http.StatusCreated, result)
+}
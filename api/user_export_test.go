@@ -0,0 +1,102 @@
+package api
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"nofx/crypto"
+	"nofx/store"
+)
+
+func newUserExportTestStore(t *testing.T, name string) *store.Store {
+	t.Helper()
+	st, err := store.New(filepath.Join(t.TempDir(), name))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st
+}
+
+func TestUserExportRoundTrip(t *testing.T) {
+	src := newUserExportTestStore(t, "src.db")
+	if err := src.AIModel().Create("u1", "u1_deepseek", "DeepSeek AI", "deepseek", true, "sk-model", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.GormDB().Create(&store.Exchange{ID: "ex-1", ExchangeType: "binance", AccountName: "Default", UserID: "u1",
+		Name: "Binance", Type: "cex", APIKey: "api-key", SecretKey: "secret-key"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Strategy().Create(&store.Strategy{ID: "st-1", UserID: "u1", Name: "Trend", IsActive: true, Config: "{}"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Trader().Create(&store.Trader{ID: "tr-1", UserID: "u1", Name: "BTC bot", AIModelID: "u1_deepseek", ExchangeID: "ex-1",
+		StrategyID: "st-1", InitialBalance: 1000, IsRunning: true, FallbackModelIDs: "u1_deepseek"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Decision().LogDecision(&store.DecisionRecord{TraderID: "tr-1", CycleNumber: 7, InputPrompt: "prompt"}); err != nil {
+		t.Fatal(err)
+	}
+
+	export, err := src.ExportUser("u1", exportDecisionsPerTrader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := sealUserExport(export, "correct horse battery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openUserArchive(archive, "wrong passphrase!"); !errors.Is(err, crypto.ErrWrongPassphrase) {
+		t.Fatalf("expected ErrWrongPassphrase, got %v", err)
+	}
+	opened, err := openUserArchive(archive, "correct horse battery")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := newUserExportTestStore(t, "dst.db")
+	result, err := dst.ImportUser("u2", opened)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Traders != 1 || result.Strategies != 1 || result.AIModels != 1 || result.Exchanges != 1 || result.Decisions != 1 {
+		t.Errorf("unexpected import counts: %+v", result)
+	}
+
+	trader, err := dst.Trader().GetByID("tr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trader.UserID != "u2" || trader.IsRunning || trader.AIModelID != "u2_deepseek" || trader.FallbackModelIDs != "u2_deepseek" ||
+		trader.ExchangeID != "ex-1" || trader.StrategyID != "st-1" {
+		t.Errorf("trader should belong to u2, be stopped and reference the rebased model: %+v", trader)
+	}
+	exchange, err := dst.Exchange().GetByID("u2", "ex-1")
+	if err != nil || exchange.APIKey != "api-key" || exchange.SecretKey != "secret-key" {
+		t.Errorf("exchange keys should survive the round trip: %+v (%v)", exchange, err)
+	}
+	if strategy, err := dst.Strategy().Get("u2", "st-1"); err != nil || strategy.IsActive {
+		t.Errorf("strategy should be imported inactive: %+v (%v)", strategy, err)
+	}
+	if records, _ := dst.Decision().GetLatestRecords("tr-1", 10); len(records) != 1 || records[0].CycleNumber != 7 {
+		t.Errorf("decision should be imported for the trader, got %+v", records)
+	}
+
+	// Importing again on the same installation creates copies under new IDs
+	again, err := dst.ImportUser("u2", opened)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newTraderID := again.RenamedIDs["tr-1"]
+	if newTraderID == "" || again.RenamedIDs["ex-1"] == "" {
+		t.Fatalf("taken IDs should be renamed, got %+v", again.RenamedIDs)
+	}
+	copied, err := dst.Trader().GetByID(newTraderID)
+	if err != nil || copied.ExchangeID != again.RenamedIDs["ex-1"] || copied.StrategyID != again.RenamedIDs["st-1"] {
+		t.Errorf("copy should reference the copied exchange and strategy: %+v (%v)", copied, err)
+	}
+	if ex, err := dst.Exchange().GetByID("u2", again.RenamedIDs["ex-1"]); err != nil || ex.AccountName != "Default (imported)" {
+		t.Errorf("duplicate account name should be suffixed: %+v (%v)", ex, err)
+	}
+}
@@ -0,0 +1,252 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"nofx/crypto"
+	"nofx/notify"
+	"nofx/store"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxWebhooks webhooks a user may define
+const maxWebhooks = 10
+
+// SetWebhookDispatcher enables the webhook endpoints
+func (s *Server) SetWebhookDispatcher(d *notify.WebhookDispatcher) {
+	s.webhooks = d
+}
+
+// webhookRequest webhook definition sent by the dashboard
+type webhookRequest struct {
+	Name     string   `json:"name"`
+	URL      string   `json:"url" binding:"required"`
+	Secret   string   `json:"secret"` // Empty: generated on create, kept on update
+	Template string   `json:"template"`
+	Events   []string `json:"events"` // Empty = all store.WebhookEvents
+	TraderID string   `json:"trader_id"`
+	Enabled  *bool    `json:"enabled"` // Default true
+}
+
+// webhookResponse a webhook as returned to the user, without its secret
+func webhookResponse(hook *store.Webhook) gin.H {
+	return gin.H{
+		"id":           hook.ID,
+		"name":         hook.Name,
+		"url":          hook.URL,
+		"template":     hook.Template,
+		"events":       hook.EventTypes(),
+		"trader_id":    hook.TraderID,
+		"enabled":      hook.Enabled,
+		"has_secret":   hook.Secret != "",
+		"last_status":  hook.LastStatus,
+		"last_error":   hook.LastError,
+		"last_sent_at": hook.LastSentAt,
+		"created_at":   hook.CreatedAt,
+		"updated_at":   hook.UpdatedAt,
+	}
+}
+
+// newWebhookSecret random HMAC signing secret
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// applyWebhookRequest validates a webhook definition and copies it onto hook
+func (s *Server) applyWebhookRequest(c *gin.Context, req *webhookRequest, hook *store.Webhook) bool {
+	req.URL = strings.TrimSpace(req.URL)
+	if err := s.webhooks.ValidateURL(req.URL); err != nil {
+		SafeBadRequest(c, fmt.Sprintf("Webhook URL not allowed: %v", err))
+		return false
+	}
+	if len(req.Name) > 64 {
+		SafeBadRequest(c, "Webhook name is limited to 64 characters")
+		return false
+	}
+	if _, err := notify.RenderWebhookPayload(req.Template, notify.SampleWebhookAlert(hook.UserID)); err != nil {
+		SafeBadRequest(c, err.Error())
+		return false
+	}
+	for _, eventType := range req.Events {
+		known := false
+		for _, t := range store.WebhookEvents {
+			known = known || t == eventType
+		}
+		if !known {
+			SafeBadRequest(c, fmt.Sprintf("Unknown event type %q", eventType))
+			return false
+		}
+	}
+	if req.TraderID != "" {
+		if _, err := s.store.Trader().Get(hook.UserID, req.TraderID); err != nil {
+			SafeNotFound(c, "Trader")
+			return false
+		}
+	}
+
+	hook.Name = strings.TrimSpace(req.Name)
+	hook.URL = req.URL
+	hook.Template = req.Template
+	hook.Events = strings.Join(req.Events, ",")
+	hook.TraderID = req.TraderID
+	hook.Enabled = req.Enabled == nil || *req.Enabled
+	if req.Secret != "" {
+		hook.Secret = crypto.EncryptedString(req.Secret)
+	}
+	return true
+}
+
+// parseWebhookID reads the :id path parameter
+func parseWebhookID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		SafeBadRequest(c, "Invalid webhook ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// handleListWebhooks List the user's webhooks with the template variables and event types available
+func (s *Server) handleListWebhooks(c *gin.Context) {
+	hooks, err := s.store.Webhook().List(c.GetString("user_id"))
+	if err != nil {
+		SafeInternalError(c, "List webhooks", err)
+		return
+	}
+	list := make([]gin.H, 0, len(hooks))
+	for _, hook := range hooks {
+		list = append(list, webhookResponse(hook))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":          s.webhooks != nil,
+		"webhooks":         list,
+		"available_events": store.WebhookEvents,
+		"variables":        notify.WebhookVariables,
+		"default_template": notify.DefaultWebhookTemplate,
+	})
+}
+
+// handleCreateWebhook Create a webhook, its signing secret is only returned here
+func (s *Server) handleCreateWebhook(c *gin.Context) {
+	if s.webhooks == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Webhooks are not available"})
+		return
+	}
+	userID := c.GetString("user_id")
+
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	count, err := s.store.Webhook().Count(userID)
+	if err != nil {
+		SafeInternalError(c, "Count webhooks", err)
+		return
+	}
+	if count >= maxWebhooks {
+		SafeBadRequest(c, fmt.Sprintf("At most %d webhooks can be defined", maxWebhooks))
+		return
+	}
+	if req.Secret == "" {
+		if req.Secret, err = newWebhookSecret(); err != nil {
+			SafeInternalError(c, "Generate webhook secret", err)
+			return
+		}
+	}
+
+	hook := &store.Webhook{UserID: userID}
+	if !s.applyWebhookRequest(c, &req, hook) {
+		return
+	}
+	if err := s.store.Webhook().Create(hook); err != nil {
+		SafeInternalError(c, "Create webhook", err)
+		return
+	}
+	resp := webhookResponse(hook)
+	resp["secret"] = req.Secret
+	c.JSON(http.StatusOK, resp)
+}
+
+// handleUpdateWebhook Update a webhook, its secret is kept unless a new one is sent
+func (s *Server) handleUpdateWebhook(c *gin.Context) {
+	if s.webhooks == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Webhooks are not available"})
+		return
+	}
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	hook, err := s.store.Webhook().Get(c.GetString("user_id"), id)
+	if err != nil {
+		SafeNotFound(c, "Webhook")
+		return
+	}
+
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		SafeBadRequest(c, "Invalid request parameters")
+		return
+	}
+	if !s.applyWebhookRequest(c, &req, hook) {
+		return
+	}
+	if err := s.store.Webhook().Update(hook); err != nil {
+		SafeInternalError(c, "Update webhook", err)
+		return
+	}
+	c.JSON(http.StatusOK, webhookResponse(hook))
+}
+
+// handleDeleteWebhook Delete a webhook
+func (s *Server) handleDeleteWebhook(c *gin.Context) {
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	if err := s.store.Webhook().Delete(c.GetString("user_id"), id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			SafeNotFound(c, "Webhook")
+			return
+		}
+		SafeInternalError(c, "Delete webhook", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted"})
+}
+
+// handleTestWebhook Send a sample event to a webhook and report the endpoint's answer
+func (s *Server) handleTestWebhook(c *gin.Context) {
+	if s.webhooks == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Webhooks are not available"})
+		return
+	}
+	id, ok := parseWebhookID(c)
+	if !ok {
+		return
+	}
+	hook, err := s.store.Webhook().Get(c.GetString("user_id"), id)
+	if err != nil {
+		SafeNotFound(c, "Webhook")
+		return
+	}
+
+	status, err := s.webhooks.SendTest(hook)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("Webhook delivery failed: %v", err), "status": status})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Test event delivered", "status": status})
+}
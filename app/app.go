@@ -0,0 +1,312 @@
+// Package app boots a complete NOFX instance: encryption, database, background jobs,
+// market services, traders, backtests and the API server
+//
+// main is a thin wrapper around it; other Go programs (and tests) can embed a full
+// instance the same way:
+//
+//	a, err := app.New(app.Config{})
+//	if err != nil { ... }
+//	if err := a.Start(); err != nil { ... }
+//	defer a.Stop(5 * time.Second)
+//
+// Settings come from config.Get(); adjust them before New.
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"nofx/api"
+	"nofx/auth"
+	"nofx/backtest"
+	"nofx/config"
+	"nofx/crypto"
+	"nofx/kernel"
+	"nofx/lifecycle"
+	"nofx/logger"
+	"nofx/manager"
+	"nofx/market"
+	"nofx/mcp"
+	"nofx/store"
+	"nofx/trader"
+)
+
+// Config options of an instance, zero values match the standalone server
+type Config struct {
+	Store            *store.Store       // Database to use (nil: opened from the settings, closed by Stop)
+	KeyProvider      crypto.KeyProvider // Encryption keys (nil: from the configured secrets backend)
+	AIClient         mcp.AIClient       // Default AI client of backtests (nil: DeepSeek when DEEPSEEK_API_KEY is set)
+	DisableAPIServer bool               // Don't listen on the API port, serve Handler() instead
+}
+
+// App a NOFX instance
+type App struct {
+	settings         *config.Config
+	store            *store.Store
+	ownsStore        bool
+	disableAPIServer bool
+	lifecycle        *lifecycle.Manager
+	traderManager    *manager.TraderManager
+	backtestManager  *backtest.Manager
+	server           *api.Server
+	serverErr        chan error
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+}
+
+// New initializes an instance without starting anything that runs in the background
+func New(cfg Config) (*App, error) {
+	settings := config.Get()
+	a := &App{
+		settings:         settings,
+		store:            cfg.Store,
+		disableAPIServer: cfg.DisableAPIServer,
+		lifecycle:        lifecycle.New(),
+		serverErr:        make(chan error, 1),
+	}
+
+	// Initialize encryption service BEFORE database (so EncryptedString can decrypt on read)
+	logger.Info("🔐 Initializing encryption service...")
+	keyProvider := cfg.KeyProvider
+	if keyProvider == nil {
+		var err error
+		if keyProvider, err = crypto.NewKeyProvider(settings.SecretsBackend); err != nil {
+			return nil, fmt.Errorf("failed to initialize secrets backend: %w", err)
+		}
+	}
+	cryptoService, err := crypto.NewCryptoServiceWithProvider(keyProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize encryption service: %w", err)
+	}
+	crypto.SetGlobalCryptoService(cryptoService)
+	logger.Infof("✅ Encryption service initialized successfully (secrets backend: %s)", keyProvider.Name())
+
+	if a.store == nil {
+		if a.store, err = openStore(settings); err != nil {
+			return nil, err
+		}
+		a.ownsStore = true
+	}
+	backtest.UseDatabaseWithType(a.store.DB(), a.store.DBType() == store.DBTypePostgres)
+
+	// Initialize installation ID for experience improvement (anonymous statistics)
+	initInstallationID(a.store)
+
+	auth.SetJWTSecret(settings.JWTSecret)
+	logger.Info("🔑 JWT secret configured")
+	if err := market.Providers().SetOrder(settings.MarketDataProviders); err != nil {
+		logger.Warnf("⚠️ Invalid MARKET_DATA_PROVIDERS, using the default order: %v", err)
+	}
+	logger.Infof("📊 Market data providers (failover order): %s", strings.Join(market.Providers().Names(), ", "))
+
+	a.traderManager = manager.NewTraderManager()
+	a.traderManager.SetCircuitBreakerDefaults(store.CircuitBreakerConfig{
+		MaxDrawdownPct:       settings.CircuitBreakerMaxDrawdownPct,
+		MaxConsecutiveLosses: settings.CircuitBreakerMaxConsecutiveLosses,
+		CooldownMinutes:      settings.CircuitBreakerCooldownMinutes,
+	})
+	// Public leaderboard only lists traders of owners who opted in
+	a.traderManager.SetPrivacyStore(a.store.Privacy())
+	a.traderManager.SetMinScanInterval(time.Duration(settings.MinScanIntervalSeconds) * time.Second)
+	a.traderManager.SetCycleTimeout(time.Duration(settings.CycleTimeoutSeconds) * time.Second)
+	kernel.SetMarketDataFetchLimits(settings.MarketDataConcurrency, time.Duration(settings.MarketDataSymbolTimeoutSeconds)*time.Second)
+	// AI calls of all traders share per-provider concurrency limits
+	mcp.SetConcurrencyLimits(settings.AIMaxConcurrentCalls, settings.AIProviderConcurrency)
+
+	aiClient := cfg.AIClient
+	if aiClient == nil {
+		aiClient = newSharedMCPClient()
+	}
+	a.backtestManager = backtest.NewManager(aiClient)
+	a.server = api.NewServer(a.traderManager, a.store, cryptoService, a.backtestManager, settings.APIServerPort)
+	return a, nil
+}
+
+// Start starts the background jobs and market services, loads the traders (starting those marked running)
+// and starts serving the API
+// Call Stop even when Start fails, it releases whatever was started.
+func (a *App) Start() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.started || a.stopped {
+		return errors.New("app already started")
+	}
+	a.started = true
+	st, cfg, lc := a.store, a.settings, a.lifecycle
+
+	// Equity snapshots of all traders are inserted in batches
+	startEquityWriter(st, cfg, lc)
+	// Downsample old equity snapshots so the table doesn't grow unbounded
+	startEquityCompaction(st, cfg, lc)
+	// Per-trader log capture for the live log API, optionally persisted
+	startTraderLogCapture(st, cfg, lc)
+	// Daily statistics in each owner's timezone, for calendar heatmaps
+	startDailyStatsRollup(st, cfg, lc)
+	// Funding rate / open interest history for charting
+	startMarketHistory(st, cfg, lc)
+	// Probe failed market data providers so they rejoin the failover chain
+	startMarketDataHealthChecks(lc)
+	// Route exchange requests to the fastest API host
+	startEndpointSelection(cfg, lc)
+	// Permanently delete traders left in the trash past the retention period
+	startTraderTrashPurge(st, cfg, lc)
+	// Email, browser push and webhook alerts for trader events, daily performance digests
+	notifier, pusher, vapidPublicKey, webhooks := startNotifications(st, cfg, lc)
+	a.server.SetNotifier(notifier)
+	a.server.SetPushDispatcher(pusher, vapidPublicKey)
+	a.server.SetWebhookDispatcher(webhooks)
+	// Weekly/monthly HTML/PDF performance reports, stored and optionally emailed
+	a.server.SetReportGenerator(startPerformanceReports(st, cfg, lc))
+
+	// Shutdown order: stop taking requests, let traders finish their cycle, checkpoint
+	// backtests, stop market services, then flush background writers
+	lc.OnShutdown("API server", 5*time.Second, a.server.Shutdown)
+	lc.OnShutdown("traders", 12*time.Second, lifecycle.Stopper(a.traderManager.StopAll))
+	lc.OnShutdown("backtests", 5*time.Second, a.backtestManager.Shutdown)
+	startMarketServices(st, cfg, lc, a.traderManager)
+
+	// Copy trading: followers mirror the decisions their leader executed
+	decisionBus := trader.NewDecisionBus()
+	decisionBus.Start()
+	a.traderManager.SetDecisionBus(decisionBus)
+	lc.OnShutdown("copy trading bus", time.Second, lifecycle.Stopper(decisionBus.Stop))
+
+	if err := a.backtestManager.RestoreRuns(); err != nil {
+		logger.Warnf("⚠️ Failed to restore backtest history: %v", err)
+	}
+
+	// An engaged kill switch survives restarts: auto-started traders stay halted
+	loadKillSwitch(st, a.traderManager.KillSwitch())
+
+	// Load all traders from database to memory (may auto-start traders with IsRunning=true)
+	if err := a.traderManager.LoadTradersFromStore(st); err != nil {
+		return fmt.Errorf("failed to load traders: %w", err)
+	}
+	logTraders(st)
+
+	if !a.disableAPIServer {
+		go func() {
+			if err := a.server.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				a.serverErr <- err
+			}
+		}()
+	}
+	return nil
+}
+
+// Stop shuts the instance down in order, giving background workers up to workerTimeout to finish
+// The database is closed when New opened it. Calling Stop again does nothing.
+func (a *App) Stop(workerTimeout time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stopped {
+		return
+	}
+	a.stopped = true
+	a.lifecycle.Shutdown(workerTimeout)
+	if a.ownsStore {
+		if err := a.store.Close(); err != nil {
+			logger.Warnf("⚠️ Failed to close database: %v", err)
+		}
+	}
+}
+
+// Err receives the error of an API server that failed to listen or stopped serving
+func (a *App) Err() <-chan error {
+	return a.serverErr
+}
+
+// Handler the API routes, for serving them on another listener or calling them in-process
+func (a *App) Handler() http.Handler {
+	return a.server.Handler()
+}
+
+// Store the instance's database
+func (a *App) Store() *store.Store {
+	return a.store
+}
+
+// TraderManager the instance's traders
+func (a *App) TraderManager() *manager.TraderManager {
+	return a.traderManager
+}
+
+// BacktestManager the instance's backtests
+func (a *App) BacktestManager() *backtest.Manager {
+	return a.backtestManager
+}
+
+// Lifecycle registers extra subsystems of an embedding program for the ordered shutdown
+// Steps registered after Start stop after the instance's own subsystems.
+func (a *App) Lifecycle() *lifecycle.Manager {
+	return a.lifecycle
+}
+
+// openStore opens the configured database, creating the SQLite data directory if needed
+func openStore(cfg *config.Config) (*store.Store, error) {
+	if cfg.DBType == "sqlite" {
+		if dir := filepath.Dir(cfg.DBPath); dir != "." {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				logger.Errorf("Failed to create data directory: %v", err)
+			}
+		}
+	}
+
+	logger.Infof("📋 Initializing database (%s)...", cfg.DBType)
+	dbType := store.DBTypeSQLite
+	if cfg.DBType == "postgres" {
+		dbType = store.DBTypePostgres
+	}
+	st, err := store.NewWithConfig(store.DBConfig{
+		Type:     dbType,
+		Path:     cfg.DBPath,
+		Host:     cfg.DBHost,
+		Port:     cfg.DBPort,
+		User:     cfg.DBUser,
+		Password: cfg.DBPassword,
+		DBName:   cfg.DBName,
+		SSLMode:  cfg.DBSSLMode,
+		DSN:      cfg.DBURL,
+		SQLite: store.SQLiteOptions{
+			JournalMode:   cfg.DBSQLiteJournalMode,
+			Synchronous:   cfg.DBSQLiteSynchronous,
+			BusyTimeoutMs: cfg.DBSQLiteBusyTimeoutMs,
+			MaxOpenConns:  cfg.DBSQLiteMaxOpenConns,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+	return st, nil
+}
+
+// logTraders lists the default user's trader configurations
+func logTraders(st *store.Store) {
+	traders, err := st.Trader().List("default")
+	if err != nil {
+		logger.Warnf("⚠️ Failed to get trader list: %v", err)
+		return
+	}
+
+	logger.Info("🤖 AI Trader Configurations in Database:")
+	if len(traders) == 0 {
+		logger.Info("  (No trader configurations, please create via Web interface)")
+		return
+	}
+	for _, t := range traders {
+		status := "❌ Stopped"
+		if t.IsRunning {
+			status = "✅ Running"
+		}
+		logger.Infof("  • %s [%s] %s - AI Model: %s, Exchange: %s",
+			t.Name, t.ID[:8], status, t.AIModelID, t.ExchangeID)
+	}
+}
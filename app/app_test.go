@@ -0,0 +1,69 @@
+package app
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nofx/config"
+	"nofx/store"
+)
+
+type staticKeyProvider struct {
+	privateKey *rsa.PrivateKey
+}
+
+func (p *staticKeyProvider) Name() string                                { return "static" }
+func (p *staticKeyProvider) LoadRSAPrivateKey() (*rsa.PrivateKey, error) { return p.privateKey, nil }
+func (p *staticKeyProvider) LoadDataKey() ([]byte, error)                { return bytes.Repeat([]byte{1}, 32), nil }
+
+func TestAppBootsAndStops(t *testing.T) {
+	// No background network services in tests
+	cfg := config.Get()
+	cfg.RegimeRefreshMinutes = 0
+	cfg.SymbolStatusRefreshMinutes = 0
+	cfg.NewsRefreshMinutes = 0
+	cfg.SentimentRefreshMinutes = 0
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := store.New(filepath.Join(t.TempDir(), "nofx.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	a, err := New(Config{Store: st, KeyProvider: &staticKeyProvider{privateKey: privateKey}, DisableAPIServer: true})
+	if err != nil {
+		t.Fatalf("New should not error: %v", err)
+	}
+	if err := a.Start(); err != nil {
+		t.Fatalf("Start should not error: %v", err)
+	}
+	if err := a.Start(); err == nil {
+		t.Error("second Start should error")
+	}
+
+	rec := httptest.NewRecorder()
+	a.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("health check = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+
+	a.Stop(2 * time.Second)
+	a.Stop(2 * time.Second)
+	if stragglers := a.Lifecycle().Stragglers(); len(stragglers) > 0 {
+		t.Errorf("workers still running after Stop: %v", stragglers)
+	}
+	// A store passed in stays open
+	if _, err := st.GetSystemConfig("installation_id"); err != nil {
+		t.Errorf("store should stay open: %v", err)
+	}
+}
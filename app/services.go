@@ -0,0 +1,605 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"nofx/config"
+	"nofx/experience"
+	"nofx/lifecycle"
+	"nofx/logger"
+	"nofx/manager"
+	"nofx/market"
+	"nofx/mcp"
+	"nofx/notify"
+	"nofx/provider/news"
+	"nofx/provider/sentiment"
+	"nofx/report"
+	"nofx/store"
+	"nofx/trader"
+	"nofx/trader/endpoint"
+
+	"github.com/google/uuid"
+)
+
+// newSharedMCPClient creates a shared MCP AI client (for backtesting)
+func newSharedMCPClient() mcp.AIClient {
+	apiKey := os.Getenv("DEEPSEEK_API_KEY")
+	if apiKey == "" {
+		logger.Warn("⚠️ DEEPSEEK_API_KEY not set, AI features will be unavailable")
+		return nil
+	}
+	return mcp.NewDeepSeekClient()
+}
+
+// startEquityWriter moves equity snapshot inserts out of the trading loop into a batching writer
+// Queued snapshots are flushed when the traders have stopped.
+func startEquityWriter(st *store.Store, cfg *config.Config, lc *lifecycle.Manager) {
+	if cfg.EquityWriteFlushMs <= 0 {
+		logger.Info("💾 Equity snapshots inserted synchronously")
+		return
+	}
+	writer := st.Equity().StartWriter(store.EquityWriterOptions{
+		BatchSize:     cfg.EquityWriteBatchSize,
+		FlushInterval: time.Duration(cfg.EquityWriteFlushMs) * time.Millisecond,
+		QueueSize:     cfg.EquityWriteQueueSize,
+	})
+	lc.Go("equity writer", writer.Run)
+	logger.Infof("💾 Equity snapshots inserted in batches of up to %d (every %dms, queue %d)",
+		cfg.EquityWriteBatchSize, cfg.EquityWriteFlushMs, writer.Stats().QueueSize)
+}
+
+// startEquityCompaction periodically compacts equity snapshots according to the configured retention policy
+func startEquityCompaction(st *store.Store, cfg *config.Config, lc *lifecycle.Manager) {
+	if cfg.EquityCompactionIntervalMinutes <= 0 {
+		logger.Info("📉 Equity snapshot compaction disabled")
+		return
+	}
+
+	policy := store.DefaultEquityRetentionPolicy()
+	policy.Tiers[1].After = time.Duration(cfg.EquityMinuteRetentionDays) * 24 * time.Hour
+	policy.MaxAge = time.Duration(cfg.EquityMaxRetentionDays) * 24 * time.Hour
+	interval := time.Duration(cfg.EquityCompactionIntervalMinutes) * time.Minute
+
+	lc.Go("equity compaction", func(done <-chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			start := time.Now()
+			if deleted, err := st.Equity().Compact(policy, start.UTC()); err != nil {
+				logger.Warnf("⚠️ Equity snapshot compaction failed: %v", err)
+			} else if deleted > 0 {
+				logger.Infof("📉 Equity snapshots compacted: %d records removed in %v", deleted, time.Since(start).Round(time.Millisecond))
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+	logger.Infof("📉 Equity snapshot compaction enabled (1/min for %d days, then 1/hour, every %v)",
+		cfg.EquityMinuteRetentionDays, interval)
+}
+
+// startTraderLogCapture sizes the per-trader log buffers and, with a retention set, stores captured lines
+// Lines are written in batches by a background goroutine; when it falls behind, lines are dropped
+// from the database rather than slowing down logging. Lines still pending at shutdown are flushed.
+func startTraderLogCapture(st *store.Store, cfg *config.Config, lc *lifecycle.Manager) {
+	logger.SetCaptureLines(cfg.TraderLogBufferLines)
+	if cfg.TraderLogBufferLines <= 0 {
+		logger.Info("📜 Trader log capture disabled")
+		return
+	}
+	if cfg.TraderLogRetentionDays <= 0 {
+		return
+	}
+
+	const (
+		batchSize     = 200
+		flushInterval = 2 * time.Second
+	)
+	pending := make(chan logger.Entry, 4096)
+	logger.SetCaptureSink(func(e logger.Entry) {
+		select {
+		case pending <- e:
+		default:
+		}
+	})
+
+	lc.Go("trader log writer", func(done <-chan struct{}) {
+		flush := time.NewTicker(flushInterval)
+		defer flush.Stop()
+		prune := time.NewTicker(24 * time.Hour)
+		defer prune.Stop()
+		batch := make([]*store.TraderLog, 0, batchSize)
+		save := func() {
+			if len(batch) == 0 {
+				return
+			}
+			if err := st.TraderLog().SaveBatch(batch); err != nil {
+				logger.Warnf("⚠️ Failed to store trader logs: %v", err)
+			}
+			batch = make([]*store.TraderLog, 0, batchSize)
+		}
+		pruneOld := func() {
+			cutoff := time.Now().AddDate(0, 0, -cfg.TraderLogRetentionDays)
+			if _, err := st.TraderLog().DeleteBefore(cutoff); err != nil {
+				logger.Warnf("⚠️ Failed to prune trader logs: %v", err)
+			}
+		}
+		add := func(e logger.Entry) {
+			batch = append(batch, &store.TraderLog{
+				TraderID:  e.Source,
+				Timestamp: e.Time,
+				Level:     e.Level,
+				Message:   e.Message,
+			})
+			if len(batch) >= batchSize {
+				save()
+			}
+		}
+		pruneOld()
+		for {
+			select {
+			case e := <-pending:
+				add(e)
+			case <-flush.C:
+				save()
+			case <-prune.C:
+				pruneOld()
+			case <-done:
+				for {
+					select {
+					case e := <-pending:
+						add(e)
+					default:
+						save()
+						return
+					}
+				}
+			}
+		}
+	})
+	logger.Infof("📜 Trader logs stored for %d days", cfg.TraderLogRetentionDays)
+}
+
+// startDailyStatsRollup rolls up the daily statistics of all traders every hour
+// Each run recomputes the previous and the current local day, so a day is final shortly after
+// the owner's midnight. Traders without daily statistics (new, or timezone changed) are backfilled.
+func startDailyStatsRollup(st *store.Store, cfg *config.Config, lc *lifecycle.Manager) {
+	if cfg.DailyStatsBackfillDays <= 0 {
+		logger.Info("📅 Daily statistics rollup disabled")
+		return
+	}
+
+	rollup := func() {
+		traders, err := st.Trader().ListAll()
+		if err != nil {
+			logger.Warnf("⚠️ Daily statistics rollup: failed to list traders: %v", err)
+			return
+		}
+		now := time.Now()
+		locations := make(map[string]*time.Location)
+		for _, t := range traders {
+			loc, ok := locations[t.UserID]
+			if !ok {
+				loc = time.UTC
+				if user, err := st.User().GetByID(t.UserID); err == nil {
+					loc = user.Location()
+				}
+				locations[t.UserID] = loc
+			}
+			from := now.AddDate(0, 0, -1)
+			if latest, err := st.DailyStats().Latest(t.ID); err == nil && latest == nil {
+				from = now.AddDate(0, 0, -cfg.DailyStatsBackfillDays)
+			}
+			if _, err := st.DailyStats().Rollup(t.ID, loc, from, now); err != nil {
+				logger.Warnf("⚠️ Daily statistics rollup failed for trader %s: %v", t.ID, err)
+			}
+		}
+	}
+
+	lc.Go("daily statistics rollup", func(done <-chan struct{}) {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			rollup()
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+	logger.Infof("📅 Daily statistics rollup enabled (hourly, %d days backfilled)", cfg.DailyStatsBackfillDays)
+}
+
+// startMarketDataHealthChecks probes market data providers that failed every minute, so they rejoin the
+// failover chain once they recover
+func startMarketDataHealthChecks(lc *lifecycle.Manager) {
+	lc.Go("market data health checks", func(done <-chan struct{}) {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				market.Providers().CheckHealth()
+			}
+		}
+	})
+}
+
+// startEndpointSelection benchmarks the API hosts of exchanges offering several, on startup and then
+// periodically, so orders go to the lowest-latency healthy one
+func startEndpointSelection(cfg *config.Config, lc *lifecycle.Manager) {
+	if cfg.EndpointCheckIntervalMinutes <= 0 {
+		logger.Info("🌐 Exchange endpoint benchmarking disabled, using default API hosts")
+		return
+	}
+	interval := time.Duration(cfg.EndpointCheckIntervalMinutes) * time.Minute
+	lc.Go("exchange endpoint selection", func(done <-chan struct{}) {
+		endpoint.Benchmark()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				endpoint.Benchmark()
+			}
+		}
+	})
+	logger.Infof("🌐 Exchange endpoint benchmarking enabled (every %v)", interval)
+}
+
+// startMarketHistory stores the funding rates and open interest fetched by decision cycles, pruning expired history daily
+func startMarketHistory(st *store.Store, cfg *config.Config, lc *lifecycle.Manager) {
+	if cfg.MarketHistoryRetentionDays <= 0 {
+		logger.Info("📈 Funding rate / open interest history disabled")
+		return
+	}
+
+	market.SetDerivativesRecorder(func(r market.DerivativesReading) {
+		if err := st.MarketHistory().SaveFunding(&store.FundingRatePoint{
+			Symbol:    r.Symbol,
+			Timestamp: r.Time,
+			Rate:      r.FundingRate,
+		}); err != nil {
+			logger.Warnf("⚠️ Failed to record funding rate: %v", err)
+		}
+		if r.OpenInterest <= 0 {
+			return
+		}
+		if err := st.MarketHistory().SaveOpenInterest(&store.OpenInterestPoint{
+			Symbol:       r.Symbol,
+			Timestamp:    r.Time,
+			OpenInterest: r.OpenInterest,
+			ValueUSD:     r.OpenInterest * r.Price,
+		}); err != nil {
+			logger.Warnf("⚠️ Failed to record open interest: %v", err)
+		}
+	})
+
+	lc.Go("market history pruning", func(done <-chan struct{}) {
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+		for {
+			cutoff := time.Now().AddDate(0, 0, -cfg.MarketHistoryRetentionDays)
+			if _, err := st.MarketHistory().DeleteBefore(cutoff); err != nil {
+				logger.Warnf("⚠️ Failed to prune market history: %v", err)
+			}
+			select {
+			case <-done:
+				market.SetDerivativesRecorder(nil)
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+	logger.Infof("📈 Funding rate / open interest history stored for %d days", cfg.MarketHistoryRetentionDays)
+}
+
+// startTraderTrashPurge purges traders deleted more than the retention period ago, daily
+func startTraderTrashPurge(st *store.Store, cfg *config.Config, lc *lifecycle.Manager) {
+	if cfg.TraderTrashRetentionDays <= 0 {
+		logger.Info("🗑️ Trader trash purging disabled, deleted traders are kept until purged manually")
+		return
+	}
+
+	lc.Go("trader trash purging", func(done <-chan struct{}) {
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+		for {
+			cutoff := time.Now().AddDate(0, 0, -cfg.TraderTrashRetentionDays)
+			if purged, err := st.Trader().PurgeDeletedBefore(cutoff); err != nil {
+				logger.Warnf("⚠️ Failed to purge deleted traders: %v", err)
+			} else if purged > 0 {
+				logger.Infof("🗑️ Purged %d traders deleted more than %d days ago", purged, cfg.TraderTrashRetentionDays)
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+	logger.Infof("🗑️ Deleted traders purged after %d days in the trash", cfg.TraderTrashRetentionDays)
+}
+
+// startNotifications emails trader events to subscribed users and sends daily digests hourly when due,
+// pushes them to subscribed browsers and POSTs them to users' webhooks. Returns nil for channels that are
+// not configured.
+func startNotifications(st *store.Store, cfg *config.Config, lc *lifecycle.Manager) (*notify.Notifier, *notify.PushDispatcher, string, *notify.WebhookDispatcher) {
+	notifier := startEmailNotifications(st, cfg, lc)
+	pusher, publicKey := startPushNotifications(st, cfg, lc)
+	webhooks := notify.NewWebhookDispatcher(st, cfg.WebhookAllowPrivateNetworks)
+	lc.Go("webhooks", webhooks.Run)
+
+	trader.SetEventListener(func(e trader.Event) {
+		alert := notify.Alert{
+			UserID:     e.UserID,
+			TraderID:   e.TraderID,
+			TraderName: e.TraderName,
+			Type:       e.Type,
+			Message:    e.Message,
+			Details:    e.Details,
+			Time:       time.Now(),
+		}
+		if notifier != nil {
+			notifier.HandleEvent(alert)
+		}
+		if pusher != nil {
+			pusher.HandleEvent(alert)
+		}
+		webhooks.HandleEvent(alert)
+	})
+	lc.Go("trader event listener", func(done <-chan struct{}) {
+		<-done
+		trader.SetEventListener(nil)
+	})
+	return notifier, pusher, publicKey, webhooks
+}
+
+// startEmailNotifications starts the email worker and the daily digest job, nil when SMTP is not configured
+func startEmailNotifications(st *store.Store, cfg *config.Config, lc *lifecycle.Manager) *notify.Notifier {
+	if cfg.SMTPHost == "" || cfg.SMTPFrom == "" {
+		logger.Info("📧 Email notifications disabled (SMTP_HOST / SMTP_FROM not set)")
+		return nil
+	}
+
+	notifier := notify.NewNotifier(st, newSMTPMailer(cfg))
+	lc.Go("email notifications", notifier.Run)
+	lc.Go("daily digests", func(done <-chan struct{}) {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			if sent := notifier.SendDueDigests(time.Now(), cfg.NotificationDigestHour); sent > 0 {
+				logger.Infof("📧 Sent %d daily digests", sent)
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+	logger.Infof("📧 Email notifications enabled via %s:%d (daily digests at %02d:00 local time)", cfg.SMTPHost, cfg.SMTPPort, cfg.NotificationDigestHour)
+	return notifier
+}
+
+// newSMTPMailer creates the mailer of the configured SMTP server, nil when SMTP is not configured
+func newSMTPMailer(cfg *config.Config) *notify.SMTPMailer {
+	if cfg.SMTPHost == "" || cfg.SMTPFrom == "" {
+		return nil
+	}
+	return notify.NewSMTPMailer(notify.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+	})
+}
+
+// startPerformanceReports generates users' scheduled weekly/monthly reports hourly when due,
+// emailing them when SMTP is configured
+func startPerformanceReports(st *store.Store, cfg *config.Config, lc *lifecycle.Manager) *report.Generator {
+	var mailer notify.Mailer
+	if m := newSMTPMailer(cfg); m != nil {
+		mailer = m
+	}
+	generator := report.NewGenerator(st, mailer, cfg.NotificationDigestHour, cfg.ReportAICostPerMTokens)
+	lc.Go("performance reports", func(done <-chan struct{}) {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			if generated := generator.GenerateDue(time.Now()); generated > 0 {
+				logger.Infof("📊 Generated %d scheduled performance reports", generated)
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+	logger.Infof("📊 Scheduled performance reports generated at %02d:00 local time after each week/month", cfg.NotificationDigestHour)
+	return generator
+}
+
+// startPushNotifications starts the browser push worker, nil when disabled
+func startPushNotifications(st *store.Store, cfg *config.Config, lc *lifecycle.Manager) (*notify.PushDispatcher, string) {
+	if cfg.WebPushSubject == "off" {
+		logger.Info("🔔 Browser push notifications disabled")
+		return nil, ""
+	}
+	key, err := st.VAPIDKey()
+	if err != nil {
+		logger.Warnf("⚠️ Browser push notifications disabled: %v", err)
+		return nil, ""
+	}
+
+	sender := notify.NewWebPushSender(key, cfg.WebPushSubject)
+	pusher := notify.NewPushDispatcher(st, sender)
+	lc.Go("push notifications", pusher.Run)
+	logger.Info("🔔 Browser push notifications enabled")
+	return pusher, sender.PublicKey()
+}
+
+// startMarketServices starts the enabled market context services and hands them to the traders
+func startMarketServices(st *store.Store, cfg *config.Config, lc *lifecycle.Manager, traderManager *manager.TraderManager) {
+	// Directional traders scale leverage/position caps to each symbol's market regime
+	if cfg.RegimeRefreshMinutes > 0 {
+		regimeService := market.NewRegimeService(time.Duration(cfg.RegimeRefreshMinutes) * time.Minute)
+		regimeService.Start()
+		traderManager.SetRegimeService(regimeService)
+		lc.OnShutdown("market regime service", time.Second, lifecycle.Stopper(regimeService.Stop))
+	} else {
+		logger.Info("🧭 Market regime detection disabled")
+	}
+
+	// Delisting symbols are dropped from candidates, traders pause during exchange maintenance
+	if cfg.SymbolStatusRefreshMinutes > 0 {
+		symbolStatus := market.NewSymbolStatusService(time.Duration(cfg.SymbolStatusRefreshMinutes) * time.Minute)
+		symbolStatus.SetCloseBefore(time.Duration(cfg.DelistCloseHours) * time.Hour)
+		loadMaintenanceWindows(st, symbolStatus)
+		symbolStatus.Start()
+		traderManager.SetSymbolStatusService(symbolStatus)
+		lc.OnShutdown("symbol status service", time.Second, lifecycle.Stopper(symbolStatus.Stop))
+	} else {
+		logger.Info("📅 Symbol status tracking disabled")
+	}
+
+	// Headlines and macro calendar events for strategies that include them in prompts
+	if cfg.NewsRefreshMinutes > 0 && (len(cfg.NewsFeedURLs) > 0 || cfg.EconomicCalendarURL != "") {
+		newsService := news.NewService(time.Duration(cfg.NewsRefreshMinutes) * time.Minute)
+		for _, u := range cfg.NewsFeedURLs {
+			newsService.AddHeadlineSource(news.NewFeedSource(u))
+		}
+		if cfg.EconomicCalendarURL != "" {
+			newsService.AddCalendarSource(news.NewCalendarFeedSource(cfg.EconomicCalendarURL, cfg.EconomicCalendarCountries))
+		}
+		newsService.Start()
+		traderManager.SetNewsService(newsService)
+		lc.OnShutdown("news service", time.Second, lifecycle.Stopper(newsService.Stop))
+	} else {
+		logger.Info("📰 News & economic calendar context disabled")
+	}
+
+	// Fear & Greed index and social sentiment, recorded for charting
+	if sentimentService := startSentiment(st, cfg); sentimentService != nil {
+		traderManager.SetSentimentService(sentimentService)
+		lc.OnShutdown("sentiment service", time.Second, lifecycle.Stopper(sentimentService.Stop))
+	}
+}
+
+// startSentiment starts the sentiment service, storing every reading and pruning expired history
+// Returns nil when disabled.
+func startSentiment(st *store.Store, cfg *config.Config) *sentiment.Service {
+	fearGreed := !strings.EqualFold(cfg.FearGreedURL, "off")
+	if cfg.SentimentRefreshMinutes <= 0 || (!fearGreed && cfg.SocialSentimentURL == "") {
+		logger.Info("🧠 Market sentiment disabled")
+		return nil
+	}
+
+	service := sentiment.NewService(time.Duration(cfg.SentimentRefreshMinutes) * time.Minute)
+	if fearGreed {
+		service.SetFearGreedClient(sentiment.NewFearGreedClient(cfg.FearGreedURL))
+	}
+	if cfg.SocialSentimentURL != "" {
+		service.SetSocialSource(sentiment.NewHTTPSocialSource(cfg.SocialSentimentURL, cfg.SocialSentimentAPIKey), cfg.SocialSentimentSymbols)
+	}
+	service.SetRecorder(func(readings []sentiment.Reading) {
+		points := make([]*store.SentimentPoint, 0, len(readings))
+		for _, r := range readings {
+			points = append(points, &store.SentimentPoint{
+				Metric:    r.Metric,
+				Symbol:    r.Symbol,
+				Timestamp: r.Time,
+				Value:     r.Value,
+				Label:     r.Label,
+				Mentions:  r.Mentions,
+			})
+		}
+		if err := st.Sentiment().Save(points); err != nil {
+			logger.Warnf("⚠️ Failed to record sentiment: %v", err)
+		}
+		if cfg.SentimentRetentionDays > 0 {
+			cutoff := time.Now().AddDate(0, 0, -cfg.SentimentRetentionDays)
+			if _, err := st.Sentiment().DeleteBefore(cutoff); err != nil {
+				logger.Warnf("⚠️ Failed to prune sentiment history: %v", err)
+			}
+		}
+	})
+	service.Start()
+	return service
+}
+
+// loadMaintenanceWindows restores the exchange maintenance windows registered by operators
+func loadMaintenanceWindows(st *store.Store, symbolStatus *market.SymbolStatusService) {
+	value, err := st.GetSystemConfig(market.MaintenanceWindowsConfigKey)
+	if err != nil || value == "" {
+		if err != nil {
+			logger.Warnf("⚠️ Failed to load maintenance windows: %v", err)
+		}
+		return
+	}
+	var windows []market.MaintenanceWindow
+	if err := json.Unmarshal([]byte(value), &windows); err != nil {
+		logger.Warnf("⚠️ Invalid maintenance windows config: %v", err)
+		return
+	}
+	symbolStatus.SetMaintenanceWindows(windows)
+	logger.Infof("🛠 Loaded %d exchange maintenance windows", len(windows))
+}
+
+// loadKillSwitch restores a kill switch engaged by an operator before the restart
+func loadKillSwitch(st *store.Store, killSwitch *trader.KillSwitch) {
+	value, err := st.GetSystemConfig(trader.KillSwitchConfigKey)
+	if err != nil || value == "" {
+		if err != nil {
+			logger.Warnf("⚠️ Failed to load kill switch: %v", err)
+		}
+		return
+	}
+	var state trader.KillSwitchState
+	if err := json.Unmarshal([]byte(value), &state); err != nil {
+		logger.Warnf("⚠️ Invalid kill switch config: %v", err)
+		return
+	}
+	killSwitch.Set(state)
+	if state.Active {
+		logger.Warnf("🛑 Kill switch engaged by %s at %s is still active, traders are halted: %s",
+			state.EngagedBy, state.EngagedAt.Format(time.RFC3339), state.Reason)
+	}
+}
+
+// initInstallationID initializes the anonymous installation ID for experience improvement
+// This ID is persisted in database and used for anonymous usage statistics
+func initInstallationID(st *store.Store) {
+	const key = "installation_id"
+
+	// Try to load from database
+	installationID, err := st.GetSystemConfig(key)
+	if err != nil {
+		logger.Warnf("⚠️ Failed to load installation ID: %v", err)
+	}
+
+	// Generate new ID if not exists
+	if installationID == "" {
+		installationID = uuid.New().String()
+		if err := st.SetSystemConfig(key, installationID); err != nil {
+			logger.Warnf("⚠️ Failed to save installation ID: %v", err)
+		}
+		logger.Infof("📊 Generated new installation ID: %s", installationID[:8]+"...")
+	}
+
+	// Set installation ID in experience module
+	experience.SetInstallationID(installationID)
+}
@@ -25,17 +25,15 @@ type BacktestAccount struct {
 	initialBalance float64
 	cash           float64
 	feeRate        float64
-	slippageRate   float64
 	positions      map[string]*position
 	realizedPnL    float64
 }
 
-func NewBacktestAccount(initialBalance, feeBps, slippageBps float64) *BacktestAccount {
+func NewBacktestAccount(initialBalance, feeBps float64) *BacktestAccount {
 	return &BacktestAccount{
 		initialBalance: initialBalance,
 		cash:           initialBalance,
 		feeRate:        feeBps / 10000.0,
-		slippageRate:   slippageBps / 10000.0,
 		positions:      make(map[string]*position),
 	}
 }
@@ -59,7 +57,8 @@ func (acc *BacktestAccount) removePosition(pos *position) {
 	delete(acc.positions, key)
 }
 
-func (acc *BacktestAccount) Open(symbol, side string, quantity float64, leverage int, price float64, ts int64) (*position, float64, float64, error) {
+// Open opens or adds to a position, slippageBps moves the fill price against the order
+func (acc *BacktestAccount) Open(symbol, side string, quantity float64, leverage int, price, slippageBps float64, ts int64) (*position, float64, float64, error) {
 	if quantity <= 0 {
 		return nil, 0, 0, fmt.Errorf("quantity must be positive")
 	}
@@ -67,7 +66,7 @@ func (acc *BacktestAccount) Open(symbol, side string, quantity float64, leverage
 		return nil, 0, 0, fmt.Errorf("leverage must be positive")
 	}
 
-	execPrice := applySlippage(price, acc.slippageRate, side, true)
+	execPrice := applySlippage(price, slippageBps/10000.0, side, true)
 	notional := execPrice * quantity
 	margin := notional / float64(leverage)
 	fee := notional * acc.feeRate
@@ -106,7 +105,8 @@ func (acc *BacktestAccount) Open(symbol, side string, quantity float64, leverage
 	return pos, fee, execPrice, nil
 }
 
-func (acc *BacktestAccount) Close(symbol, side string, quantity float64, price float64) (float64, float64, float64, error) {
+// Close reduces or closes a position, slippageBps moves the fill price against the order
+func (acc *BacktestAccount) Close(symbol, side string, quantity float64, price, slippageBps float64) (float64, float64, float64, error) {
 	key := positionKey(symbol, side)
 	pos, ok := acc.positions[key]
 	if !ok || pos.Quantity <= epsilon {
@@ -121,7 +121,7 @@ func (acc *BacktestAccount) Close(symbol, side string, quantity float64, price f
 		}
 	}
 
-	execPrice := applySlippage(price, acc.slippageRate, side, false)
+	execPrice := applySlippage(price, slippageBps/10000.0, side, false)
 	closeNotional := execPrice * quantity // Notional at close price (for fee calculation)
 	closingFee := closeNotional * acc.feeRate
 
@@ -2,11 +2,50 @@ package backtest
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
+	"sync"
 
+	"nofx/logger"
 	"nofx/mcp"
 )
 
+// responseCacheDirName directory under backtestsRootDir holding AI responses shared by all runs
+const responseCacheDirName = "ai_responses"
+
+var (
+	responseCacheOnce sync.Once
+	responseCache     *mcp.ResponseCache
+)
+
+// sharedResponseCache returns the process wide AI response cache of backtests (nil if it cannot be opened)
+// Keyed by prompt hash + model, so sweeps and re-runs over the same snapshots reuse earlier responses.
+func sharedResponseCache() *mcp.ResponseCache {
+	responseCacheOnce.Do(func() {
+		cache, err := mcp.NewResponseCache(filepath.Join(backtestsRootDir, responseCacheDirName))
+		if err != nil {
+			logger.Infof("failed to open ai response cache: %v", err)
+			return
+		}
+		responseCache = cache
+	})
+	return responseCache
+}
+
+// attachResponseCache lets the client answer identical prompts from the shared response cache
+func attachResponseCache(cfg BacktestConfig, client mcp.AIClient) {
+	if cfg.NoResponseCache {
+		return
+	}
+	cacher, ok := client.(mcp.ResponseCacher)
+	if !ok {
+		return
+	}
+	if cache := sharedResponseCache(); cache != nil {
+		cacher.SetResponseCache(cache)
+	}
+}
+
 // configureMCPClient creates/clones an MCP client based on configuration (returns mcp.AIClient interface).
 // Note: mcp.New() returns an interface type; here we convert to concrete implementation before copying to avoid concurrent shared state.
 func configureMCPClient(cfg BacktestConfig, base mcp.AIClient) (mcp.AIClient, error) {
@@ -50,10 +50,14 @@ type BacktestConfig struct {
 	AICfg    AIConfig       `json:"ai"`
 	Leverage LeverageConfig `json:"leverage"`
 
+	Fees     *FeeSchedule    `json:"fees,omitempty"`     // Maker/taker fee schedule, overrides fee_bps
+	Slippage *SlippageConfig `json:"slippage,omitempty"` // Slippage model, overrides slippage_bps
+
 	SharedAICachePath         string `json:"ai_cache_path,omitempty"`
 	CheckpointIntervalBars    int    `json:"checkpoint_interval_bars,omitempty"`
 	CheckpointIntervalSeconds int    `json:"checkpoint_interval_seconds,omitempty"`
 	ReplayDecisionDir         string `json:"replay_decision_dir,omitempty"`
+	NoResponseCache           bool   `json:"no_response_cache,omitempty"` // Always call the AI instead of reusing responses to identical prompts
 
 	// Internal: loaded strategy config (set by Manager when StrategyID is provided)
 	loadedStrategy *store.StrategyConfig `json:"-"`
@@ -121,6 +125,9 @@ func (cfg *BacktestConfig) Validate() error {
 	if err := validateFillPolicy(cfg.FillPolicy); err != nil {
 		return err
 	}
+	if err := cfg.validateCosts(); err != nil {
+		return err
+	}
 
 	if cfg.CheckpointIntervalBars <= 0 {
 		cfg.CheckpointIntervalBars = 20
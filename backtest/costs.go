@@ -0,0 +1,171 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Fill liquidity of simulated orders, deciding which fee rate applies
+const (
+	LiquidityTaker = "taker" // Market orders (default, decisions fill immediately)
+	LiquidityMaker = "maker" // Resting limit orders
+)
+
+// Slippage models
+const (
+	SlippageModelFixed        = "fixed"         // Constant bps on every fill
+	SlippageModelVolumeImpact = "volume_impact" // Fixed bps plus square-root impact of the order's share of bar volume
+)
+
+const (
+	defaultImpactBps      = 10.0 // Impact at 1% of bar volume
+	defaultMaxSlippageBps = 50.0
+)
+
+// ExchangeFeeSchedule default (non-VIP) USDT perpetual fees of an exchange, in bps
+type ExchangeFeeSchedule struct {
+	MakerBps float64 `json:"maker_bps"`
+	TakerBps float64 `json:"taker_bps"`
+}
+
+// ExchangeFeeSchedules default fee tiers by exchange, used when a fee schedule names an exchange without rates
+var ExchangeFeeSchedules = map[string]ExchangeFeeSchedule{
+	"binance":     {MakerBps: 2, TakerBps: 5},
+	"bybit":       {MakerBps: 2, TakerBps: 5.5},
+	"okx":         {MakerBps: 2, TakerBps: 5},
+	"bitget":      {MakerBps: 2, TakerBps: 6},
+	"gate":        {MakerBps: 2, TakerBps: 5},
+	"kucoin":      {MakerBps: 2, TakerBps: 6},
+	"hyperliquid": {MakerBps: 1.5, TakerBps: 4.5},
+	"aster":       {MakerBps: 1, TakerBps: 3.5},
+	"lighter":     {MakerBps: 0, TakerBps: 0},
+}
+
+// FeeSchedule maker/taker fees of a backtest, overrides fee_bps
+type FeeSchedule struct {
+	Exchange  string  `json:"exchange,omitempty"`  // Fills missing rates from the exchange's default tier
+	MakerBps  float64 `json:"maker_bps,omitempty"` // 0 = exchange default
+	TakerBps  float64 `json:"taker_bps,omitempty"` // 0 = exchange default
+	Liquidity string  `json:"liquidity,omitempty"` // taker (default) | maker
+}
+
+// SlippageConfig slippage model of a backtest, overrides slippage_bps
+type SlippageConfig struct {
+	Model     string  `json:"model"`                // fixed | volume_impact
+	Bps       float64 `json:"bps,omitempty"`        // Fixed part, on every fill
+	ImpactBps float64 `json:"impact_bps,omitempty"` // volume_impact: extra bps at 1% of bar volume (default 10)
+	MaxBps    float64 `json:"max_bps,omitempty"`    // volume_impact: cap, also used for bars without volume (default 50)
+}
+
+// CostAssumptions resolved fee and slippage assumptions of a run, recorded with its metrics
+type CostAssumptions struct {
+	Exchange       string  `json:"exchange,omitempty"`
+	MakerBps       float64 `json:"maker_bps"`
+	TakerBps       float64 `json:"taker_bps"`
+	Liquidity      string  `json:"liquidity"`
+	FeeBps         float64 `json:"fee_bps"` // Rate applied to fills (maker or taker)
+	SlippageModel  string  `json:"slippage_model"`
+	SlippageBps    float64 `json:"slippage_bps"`
+	ImpactBps      float64 `json:"impact_bps,omitempty"`
+	MaxSlippageBps float64 `json:"max_slippage_bps,omitempty"`
+	FillPolicy     string  `json:"fill_policy"`
+}
+
+// validateCosts checks the fee schedule and slippage model
+func (cfg *BacktestConfig) validateCosts() error {
+	if cfg.FeeBps < 0 || cfg.SlippageBps < 0 {
+		return fmt.Errorf("fee_bps and slippage_bps cannot be negative")
+	}
+	if fees := cfg.Fees; fees != nil {
+		fees.Exchange = strings.ToLower(strings.TrimSpace(fees.Exchange))
+		if _, ok := ExchangeFeeSchedules[fees.Exchange]; fees.Exchange != "" && !ok {
+			return fmt.Errorf("no fee schedule for exchange '%s'", fees.Exchange)
+		}
+		if fees.MakerBps < 0 || fees.TakerBps < 0 {
+			return fmt.Errorf("maker_bps and taker_bps cannot be negative")
+		}
+		switch fees.Liquidity {
+		case "":
+			fees.Liquidity = LiquidityTaker
+		case LiquidityTaker, LiquidityMaker:
+		default:
+			return fmt.Errorf("unsupported liquidity '%s'", fees.Liquidity)
+		}
+	}
+	if slip := cfg.Slippage; slip != nil {
+		switch slip.Model {
+		case "":
+			slip.Model = SlippageModelFixed
+		case SlippageModelFixed, SlippageModelVolumeImpact:
+		default:
+			return fmt.Errorf("unsupported slippage model '%s'", slip.Model)
+		}
+		if slip.Bps < 0 || slip.ImpactBps < 0 || slip.MaxBps < 0 {
+			return fmt.Errorf("slippage bps cannot be negative")
+		}
+		if slip.Model == SlippageModelVolumeImpact {
+			if slip.ImpactBps == 0 {
+				slip.ImpactBps = defaultImpactBps
+			}
+			if slip.MaxBps == 0 {
+				slip.MaxBps = math.Max(defaultMaxSlippageBps, slip.Bps)
+			}
+		}
+	}
+	return nil
+}
+
+// CostAssumptions fee and slippage assumptions the run fills with
+// Without a fee schedule or slippage model the flat fee_bps/slippage_bps apply.
+func (cfg *BacktestConfig) CostAssumptions() CostAssumptions {
+	costs := CostAssumptions{
+		MakerBps:      cfg.FeeBps,
+		TakerBps:      cfg.FeeBps,
+		Liquidity:     LiquidityTaker,
+		SlippageModel: SlippageModelFixed,
+		SlippageBps:   cfg.SlippageBps,
+		FillPolicy:    cfg.FillPolicy,
+	}
+	if fees := cfg.Fees; fees != nil {
+		defaults := ExchangeFeeSchedules[fees.Exchange]
+		costs.Exchange = fees.Exchange
+		costs.MakerBps, costs.TakerBps = fees.MakerBps, fees.TakerBps
+		if costs.MakerBps == 0 {
+			costs.MakerBps = defaults.MakerBps
+		}
+		if costs.TakerBps == 0 {
+			costs.TakerBps = defaults.TakerBps
+		}
+		if fees.Liquidity != "" {
+			costs.Liquidity = fees.Liquidity
+		}
+	}
+	costs.FeeBps = costs.TakerBps
+	if costs.Liquidity == LiquidityMaker {
+		costs.FeeBps = costs.MakerBps
+	}
+	if slip := cfg.Slippage; slip != nil {
+		costs.SlippageModel = slip.Model
+		costs.SlippageBps = slip.Bps
+		if slip.Model == SlippageModelVolumeImpact {
+			costs.ImpactBps = slip.ImpactBps
+			costs.MaxSlippageBps = slip.MaxBps
+		}
+	}
+	return costs
+}
+
+// SlippageBpsFor slippage of a fill of quantity in a bar that traded barVolume (both in base asset)
+func (c CostAssumptions) SlippageBpsFor(quantity, barVolume float64) float64 {
+	if c.SlippageModel != SlippageModelVolumeImpact {
+		return c.SlippageBps
+	}
+	if barVolume <= 0 {
+		return c.MaxSlippageBps
+	}
+	// Square-root impact: 4x the volume share costs 2x the impact
+	participation := quantity / barVolume
+	bps := c.SlippageBps + c.ImpactBps*math.Sqrt(participation/0.01)
+	return math.Min(bps, c.MaxSlippageBps)
+}
@@ -0,0 +1,42 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCostAssumptions(t *testing.T) {
+	cfg := BacktestConfig{FeeBps: 5, SlippageBps: 2, FillPolicy: FillPolicyNextOpen}
+	if err := cfg.validateCosts(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	flat := cfg.CostAssumptions()
+	if flat.FeeBps != 5 || flat.SlippageModel != SlippageModelFixed || flat.SlippageBpsFor(100, 1) != 2 {
+		t.Errorf("flat fee/slippage should apply without a schedule: %+v", flat)
+	}
+
+	cfg.Fees = &FeeSchedule{Exchange: " Bybit ", Liquidity: LiquidityMaker}
+	cfg.Slippage = &SlippageConfig{Model: SlippageModelVolumeImpact, Bps: 1}
+	if err := cfg.validateCosts(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	costs := cfg.CostAssumptions()
+	if costs.Exchange != "bybit" || costs.TakerBps != 5.5 || costs.FeeBps != 2 {
+		t.Errorf("expected bybit maker tier, got %+v", costs)
+	}
+	// 1% of bar volume: fixed part plus the full impact
+	if got := costs.SlippageBpsFor(1, 100); math.Abs(got-(1+defaultImpactBps)) > 1e-9 {
+		t.Errorf("unexpected impact at 1%% participation: %v", got)
+	}
+	if got := costs.SlippageBpsFor(50, 100); got != defaultMaxSlippageBps {
+		t.Errorf("impact should be capped at max_bps, got %v", got)
+	}
+	if got := costs.SlippageBpsFor(1, 0); got != defaultMaxSlippageBps {
+		t.Errorf("bars without volume should use max_bps, got %v", got)
+	}
+
+	cfg.Fees = &FeeSchedule{Exchange: "nowhere"}
+	if err := cfg.validateCosts(); err == nil {
+		t.Errorf("unknown exchange should be rejected")
+	}
+}
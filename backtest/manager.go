@@ -15,22 +15,26 @@ import (
 )
 
 type Manager struct {
-	mu         sync.RWMutex
-	runners    map[string]*Runner
-	metadata   map[string]*RunMetadata
-	cancels    map[string]context.CancelFunc
-	mcpClient  mcp.AIClient
-	aiResolver AIConfigResolver
+	mu           sync.RWMutex
+	runners      map[string]*Runner
+	metadata     map[string]*RunMetadata
+	cancels      map[string]context.CancelFunc
+	sweeps       map[string]*sweepJob
+	walkForwards map[string]*walkForwardJob
+	mcpClient    mcp.AIClient
+	aiResolver   AIConfigResolver
 }
 
 type AIConfigResolver func(*BacktestConfig) error
 
 func NewManager(defaultClient mcp.AIClient) *Manager {
 	return &Manager{
-		runners:   make(map[string]*Runner),
-		metadata:  make(map[string]*RunMetadata),
-		cancels:   make(map[string]context.CancelFunc),
-		mcpClient: defaultClient,
+		runners:      make(map[string]*Runner),
+		metadata:     make(map[string]*RunMetadata),
+		cancels:      make(map[string]context.CancelFunc),
+		sweeps:       make(map[string]*sweepJob),
+		walkForwards: make(map[string]*walkForwardJob),
+		mcpClient:    defaultClient,
 	}
 }
 
@@ -266,6 +270,41 @@ func (m *Manager) Stop(runID string) error {
 	return nil
 }
 
+// Shutdown stops the running backtests so their checkpoints and metadata are persisted
+// (they can be resumed after a restart), waiting for them until ctx is done.
+// Paused runs are already checkpointed and left as they are.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.RLock()
+	active := make(map[string]*Runner)
+	for runID, runner := range m.runners {
+		if runner.Status() == RunStateRunning {
+			active[runID] = runner
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, runner := range active {
+		runner.Stop()
+	}
+	var pending []string
+	for runID, runner := range active {
+		select {
+		case <-runner.doneCh:
+		case <-ctx.Done():
+			select {
+			case <-runner.doneCh:
+			default:
+				pending = append(pending, runID)
+			}
+		}
+	}
+	if len(pending) > 0 {
+		sort.Strings(pending)
+		return fmt.Errorf("backtest runs still stopping: %s", strings.Join(pending, ", "))
+	}
+	return nil
+}
+
 func (m *Manager) Wait(runID string) error {
 	runner, ok := m.GetRunner(runID)
 	if !ok {
@@ -46,9 +46,27 @@ func CalculateMetrics(runID string, cfg *BacktestConfig, state *BacktestState) (
 
 	fillTradeMetrics(metrics, events)
 
+	costs := cfg.CostAssumptions()
+	metrics.CostAssumptions = &costs
+	metrics.TotalFees, metrics.TotalSlippage = executionCosts(events, costs.FeeBps)
+
 	return metrics, nil
 }
 
+// executionCosts fees and slippage paid by the run's fills
+// Close events carry the position's opening fee in Fee, so their own fee is recomputed from the fill value.
+func executionCosts(events []TradeEvent, feeBps float64) (fees, slippage float64) {
+	for _, evt := range events {
+		if strings.HasPrefix(evt.Action, "open") {
+			fees += evt.Fee
+		} else {
+			fees += evt.OrderValue * feeBps / 10000
+		}
+		slippage += evt.Slippage * evt.Quantity
+	}
+	return fees, slippage
+}
+
 func determineLiquidation(events []TradeEvent, state *BacktestState) bool {
 	if state != nil && state.Liquidated {
 		return true
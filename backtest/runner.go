@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"nofx/logger"
 	"os"
 	"path/filepath"
@@ -34,6 +35,7 @@ type Runner struct {
 	cfg            BacktestConfig
 	feed           *DataFeed
 	account        *BacktestAccount
+	costs          CostAssumptions
 	strategyEngine *kernel.StrategyEngine
 
 	decisionLogDir string
@@ -75,6 +77,7 @@ func NewRunner(cfg BacktestConfig, mcpClient mcp.AIClient) (*Runner, error) {
 	if err != nil {
 		return nil, err
 	}
+	attachResponseCache(cfg, client)
 
 	feed, err := NewDataFeed(cfg)
 	if err != nil {
@@ -86,7 +89,8 @@ func NewRunner(cfg BacktestConfig, mcpClient mcp.AIClient) (*Runner, error) {
 	}
 
 	dLogDir := decisionLogDir(cfg.RunID)
-	account := NewBacktestAccount(cfg.InitialBalance, cfg.FeeBps, cfg.SlippageBps)
+	costs := cfg.CostAssumptions()
+	account := NewBacktestAccount(cfg.InitialBalance, costs.FeeBps)
 
 	createdAt := time.Now().UTC()
 	state := &BacktestState{
@@ -125,6 +129,7 @@ func NewRunner(cfg BacktestConfig, mcpClient mcp.AIClient) (*Runner, error) {
 		cfg:            cfg,
 		feed:           feed,
 		account:        account,
+		costs:          costs,
 		strategyEngine: strategyEngine,
 		decisionLogDir: dLogDir,
 		mcpClient:      client,
@@ -651,7 +656,7 @@ func (r *Runner) executeDecision(dec kernel.Decision, priceMap map[string]float6
 		if qty <= 0 {
 			return actionRecord, nil, "", fmt.Errorf("invalid qty")
 		}
-		pos, fee, execPrice, err := r.account.Open(symbol, "long", qty, usedLeverage, fillPrice, ts)
+		pos, fee, execPrice, err := r.account.Open(symbol, "long", qty, usedLeverage, fillPrice, r.fillSlippageBps(symbol, ts, qty), ts)
 		if err != nil {
 			return actionRecord, nil, "", err
 		}
@@ -680,7 +685,7 @@ func (r *Runner) executeDecision(dec kernel.Decision, priceMap map[string]float6
 		if qty <= 0 {
 			return actionRecord, nil, "", fmt.Errorf("invalid qty")
 		}
-		pos, fee, execPrice, err := r.account.Open(symbol, "short", qty, usedLeverage, fillPrice, ts)
+		pos, fee, execPrice, err := r.account.Open(symbol, "short", qty, usedLeverage, fillPrice, r.fillSlippageBps(symbol, ts, qty), ts)
 		if err != nil {
 			return actionRecord, nil, "", err
 		}
@@ -710,7 +715,7 @@ func (r *Runner) executeDecision(dec kernel.Decision, priceMap map[string]float6
 			return actionRecord, nil, "", fmt.Errorf("invalid close qty")
 		}
 		posLev := r.account.positionLeverage(symbol, "long")
-		realized, fee, execPrice, err := r.account.Close(symbol, "long", qty, fillPrice)
+		realized, fee, execPrice, err := r.account.Close(symbol, "long", qty, fillPrice, r.fillSlippageBps(symbol, ts, qty))
 		if err != nil {
 			return actionRecord, nil, "", err
 		}
@@ -740,7 +745,7 @@ func (r *Runner) executeDecision(dec kernel.Decision, priceMap map[string]float6
 			return actionRecord, nil, "", fmt.Errorf("invalid close qty")
 		}
 		posLev := r.account.positionLeverage(symbol, "short")
-		realized, fee, execPrice, err := r.account.Close(symbol, "short", qty, fillPrice)
+		realized, fee, execPrice, err := r.account.Close(symbol, "short", qty, fillPrice, r.fillSlippageBps(symbol, ts, qty))
 		if err != nil {
 			return actionRecord, nil, "", err
 		}
@@ -939,6 +944,23 @@ func (r *Runner) executionPrice(symbol string, markPrice float64, ts int64) floa
 	return markPrice
 }
 
+// fillSlippageBps slippage of a fill of quantity, sized against the volume of the bar it fills in
+func (r *Runner) fillSlippageBps(symbol string, ts int64, quantity float64) float64 {
+	if r.costs.SlippageModel != SlippageModelVolumeImpact {
+		return r.costs.SlippageBps
+	}
+	curr, next := r.feed.decisionBarSnapshot(symbol, ts)
+	bar := curr
+	if r.cfg.FillPolicy == FillPolicyNextOpen && next != nil {
+		bar = next
+	}
+	volume := 0.0
+	if bar != nil {
+		volume = bar.Volume
+	}
+	return r.costs.SlippageBpsFor(quantity, volume)
+}
+
 func (r *Runner) totalMarginUsed() float64 {
 	sum := 0.0
 	for _, pos := range r.account.Positions() {
@@ -1059,7 +1081,8 @@ func (r *Runner) checkLiquidation(ts int64, priceMap map[string]float64, cycle i
 			continue
 		}
 
-		realized, fee, finalPrice, err := r.account.Close(pos.Symbol, pos.Side, pos.Quantity, execPrice)
+		// Forced closes pay the fixed slippage, the liquidation price already marks the worst case
+		realized, fee, finalPrice, err := r.account.Close(pos.Symbol, pos.Side, pos.Quantity, execPrice, r.costs.SlippageBps)
 		if err != nil {
 			return nil, "", err
 		}
@@ -1074,7 +1097,7 @@ func (r *Runner) checkLiquidation(ts int64, priceMap map[string]float64, cycle i
 			Quantity:        pos.Quantity,
 			Price:           finalPrice,
 			Fee:             fee,
-			Slippage:        0,
+			Slippage:        math.Abs(execPrice - finalPrice),
 			OrderValue:      finalPrice * pos.Quantity,
 			RealizedPnL:     realized - fee,
 			Leverage:        pos.Leverage,
@@ -336,7 +336,7 @@ func LoadRunIDs() ([]string, error) {
 	}
 	runIDs := make([]string, 0, len(entries))
 	for _, entry := range entries {
-		if entry.IsDir() {
+		if entry.IsDir() && entry.Name() != responseCacheDirName {
 			runIDs = append(runIDs, entry.Name())
 		}
 	}
@@ -559,3 +559,126 @@ func persistDecisionRecord(runID string, record *store.DecisionRecord) {
 	}
 	_ = saveDecisionRecordDB(runID, record)
 }
+
+// sweepPath sweeps are files next to the run directories, so they are never listed as runs
+func sweepPath(sweepID string) string {
+	return filepath.Join(backtestsRootDir, sweepID+".sweep.json")
+}
+
+// SaveSweep persists a sweep and its result matrix.
+func SaveSweep(sweep *Sweep) error {
+	if sweep == nil {
+		return fmt.Errorf("sweep is nil")
+	}
+	if usingDB() {
+		return saveSweepDB(sweep)
+	}
+	return writeJSONAtomic(sweepPath(sweep.SweepID), sweep)
+}
+
+// LoadSweep reads a persisted sweep.
+func LoadSweep(sweepID string) (*Sweep, error) {
+	if usingDB() {
+		return loadSweepDB(sweepID)
+	}
+	data, err := os.ReadFile(sweepPath(sweepID))
+	if err != nil {
+		return nil, err
+	}
+	var sweep Sweep
+	if err := json.Unmarshal(data, &sweep); err != nil {
+		return nil, err
+	}
+	return &sweep, nil
+}
+
+// LoadSweeps reads all persisted sweeps.
+func LoadSweeps() ([]*Sweep, error) {
+	if usingDB() {
+		return loadSweepsDB()
+	}
+	paths, err := filepath.Glob(filepath.Join(backtestsRootDir, "*.sweep.json"))
+	if err != nil {
+		return nil, err
+	}
+	sweeps := make([]*Sweep, 0, len(paths))
+	for _, path := range paths {
+		sweep, err := LoadSweep(strings.TrimSuffix(filepath.Base(path), ".sweep.json"))
+		if err != nil {
+			continue
+		}
+		sweeps = append(sweeps, sweep)
+	}
+	return sweeps, nil
+}
+
+func deleteSweep(sweepID string) error {
+	if usingDB() {
+		return deleteSweepDB(sweepID)
+	}
+	if err := os.Remove(sweepPath(sweepID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func walkForwardPath(id string) string {
+	return filepath.Join(backtestsRootDir, id+".walkforward.json")
+}
+
+// SaveWalkForward persists a walk-forward validation and its window results.
+func SaveWalkForward(wf *WalkForward) error {
+	if wf == nil {
+		return fmt.Errorf("walk-forward is nil")
+	}
+	if usingDB() {
+		return saveWalkForwardDB(wf)
+	}
+	return writeJSONAtomic(walkForwardPath(wf.WalkForwardID), wf)
+}
+
+// LoadWalkForward reads a persisted walk-forward validation.
+func LoadWalkForward(id string) (*WalkForward, error) {
+	if usingDB() {
+		return loadWalkForwardDB(id)
+	}
+	data, err := os.ReadFile(walkForwardPath(id))
+	if err != nil {
+		return nil, err
+	}
+	var wf WalkForward
+	if err := json.Unmarshal(data, &wf); err != nil {
+		return nil, err
+	}
+	return &wf, nil
+}
+
+// LoadWalkForwards reads all persisted walk-forward validations.
+func LoadWalkForwards() ([]*WalkForward, error) {
+	if usingDB() {
+		return loadWalkForwardsDB()
+	}
+	paths, err := filepath.Glob(filepath.Join(backtestsRootDir, "*.walkforward.json"))
+	if err != nil {
+		return nil, err
+	}
+	wfs := make([]*WalkForward, 0, len(paths))
+	for _, path := range paths {
+		wf, err := LoadWalkForward(strings.TrimSuffix(filepath.Base(path), ".walkforward.json"))
+		if err != nil {
+			continue
+		}
+		wfs = append(wfs, wf)
+	}
+	return wfs, nil
+}
+
+func deleteWalkForward(id string) error {
+	if usingDB() {
+		return deleteWalkForwardDB(id)
+	}
+	if err := os.Remove(walkForwardPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
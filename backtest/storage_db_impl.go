@@ -496,3 +496,121 @@ func deleteRunDB(runID string) error {
 	_, err := persistenceDB.Exec(convertQuery(`DELETE FROM backtest_runs WHERE run_id = ?`), runID)
 	return err
 }
+
+func saveSweepDB(sweep *Sweep) error {
+	data, err := json.Marshal(sweep)
+	if err != nil {
+		return err
+	}
+	userID := sweep.UserID
+	if userID == "" {
+		userID = "default"
+	}
+	_, err = persistenceDB.Exec(convertQuery(`
+		INSERT INTO backtest_sweeps (sweep_id, user_id, state, payload, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(sweep_id) DO UPDATE SET state=excluded.state, payload=excluded.payload, updated_at=excluded.updated_at
+	`), sweep.SweepID, userID, string(sweep.State), data, sweep.CreatedAt.UTC(), sweep.UpdatedAt.UTC())
+	return err
+}
+
+func loadSweepDB(sweepID string) (*Sweep, error) {
+	var payload []byte
+	err := persistenceDB.QueryRow(convertQuery(`SELECT payload FROM backtest_sweeps WHERE sweep_id = ?`), sweepID).Scan(&payload)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, os.ErrNotExist
+		}
+		return nil, err
+	}
+	var sweep Sweep
+	if err := json.Unmarshal(payload, &sweep); err != nil {
+		return nil, err
+	}
+	return &sweep, nil
+}
+
+func loadSweepsDB() ([]*Sweep, error) {
+	rows, err := persistenceDB.Query(`SELECT payload FROM backtest_sweeps ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var sweeps []*Sweep
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			return nil, err
+		}
+		var sweep Sweep
+		if err := json.Unmarshal(payload, &sweep); err != nil {
+			continue
+		}
+		sweeps = append(sweeps, &sweep)
+	}
+	return sweeps, rows.Err()
+}
+
+func deleteSweepDB(sweepID string) error {
+	_, err := persistenceDB.Exec(convertQuery(`DELETE FROM backtest_sweeps WHERE sweep_id = ?`), sweepID)
+	return err
+}
+
+func saveWalkForwardDB(wf *WalkForward) error {
+	data, err := json.Marshal(wf)
+	if err != nil {
+		return err
+	}
+	userID := wf.UserID
+	if userID == "" {
+		userID = "default"
+	}
+	_, err = persistenceDB.Exec(convertQuery(`
+		INSERT INTO backtest_walk_forwards (walk_forward_id, user_id, state, payload, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(walk_forward_id) DO UPDATE SET state=excluded.state, payload=excluded.payload, updated_at=excluded.updated_at
+	`), wf.WalkForwardID, userID, string(wf.State), data, wf.CreatedAt.UTC(), wf.UpdatedAt.UTC())
+	return err
+}
+
+func loadWalkForwardDB(id string) (*WalkForward, error) {
+	var payload []byte
+	err := persistenceDB.QueryRow(convertQuery(`SELECT payload FROM backtest_walk_forwards WHERE walk_forward_id = ?`), id).Scan(&payload)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, os.ErrNotExist
+		}
+		return nil, err
+	}
+	var wf WalkForward
+	if err := json.Unmarshal(payload, &wf); err != nil {
+		return nil, err
+	}
+	return &wf, nil
+}
+
+func loadWalkForwardsDB() ([]*WalkForward, error) {
+	rows, err := persistenceDB.Query(`SELECT payload FROM backtest_walk_forwards ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var wfs []*WalkForward
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			return nil, err
+		}
+		var wf WalkForward
+		if err := json.Unmarshal(payload, &wf); err != nil {
+			continue
+		}
+		wfs = append(wfs, &wf)
+	}
+	return wfs, rows.Err()
+}
+
+func deleteWalkForwardDB(id string) error {
+	_, err := persistenceDB.Exec(convertQuery(`DELETE FROM backtest_walk_forwards WHERE walk_forward_id = ?`), id)
+	return err
+}
@@ -0,0 +1,589 @@
+package backtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"nofx/logger"
+	"nofx/store"
+)
+
+// Sweep parameters, each mapped onto one field of the run's config or strategy
+const (
+	SweepParamLeverage        = "leverage"               // BTC/ETH and altcoin leverage (and grid leverage)
+	SweepParamBTCETHLeverage  = "btc_eth_leverage"       // BTC/ETH leverage only
+	SweepParamAltcoinLeverage = "altcoin_leverage"       // Altcoin leverage only
+	SweepParamGridCount       = "grid_count"             // Grid levels, requires a grid strategy
+	SweepParamATRMultiplier   = "atr_multiplier"         // Grid ATR bound multiplier, requires a grid strategy
+	SweepParamMinConfidence   = "min_confidence"         // Minimum AI confidence to open a position
+	SweepParamDecisionCadence = "decision_cadence_nbars" // Bars between AI decisions
+)
+
+// Sweep ranking orders
+const (
+	SweepRankSharpe      = "sharpe"       // Highest Sharpe ratio first, ties by lowest drawdown
+	SweepRankMaxDrawdown = "max_drawdown" // Lowest max drawdown first, ties by highest Sharpe ratio
+	SweepRankReturn      = "return"       // Highest total return first
+)
+
+const (
+	// MaxSweepCombinations caps how many runs one sweep may start, every run makes its own AI calls
+	MaxSweepCombinations = 64
+	defaultSweepParallel = 2
+	maxSweepParallel     = 8
+)
+
+// SweepParam one swept parameter, given as explicit values or as a min/max/step range
+type SweepParam struct {
+	Name   string    `json:"name"`
+	Values []float64 `json:"values,omitempty"`
+	Min    float64   `json:"min,omitempty"`
+	Max    float64   `json:"max,omitempty"`
+	Step   float64   `json:"step,omitempty"`
+}
+
+// SweepConfig input of a parameter sweep: a base run config and the parameters varied across runs
+type SweepConfig struct {
+	SweepID     string         `json:"sweep_id"`
+	UserID      string         `json:"user_id,omitempty"`
+	Label       string         `json:"label,omitempty"`
+	Base        BacktestConfig `json:"base"`
+	Params      []SweepParam   `json:"params"`
+	Parallelism int            `json:"parallelism,omitempty"` // Runs executed at once (default 2, max 8)
+	RankBy      string         `json:"rank_by,omitempty"`     // sharpe (default) | max_drawdown | return
+}
+
+// SweepResult one cell of the sweep matrix
+type SweepResult struct {
+	RunID  string             `json:"run_id"`
+	Params map[string]float64 `json:"params"`
+	State  RunState           `json:"state"`
+	Rank   int                `json:"rank,omitempty"` // 1 = best, 0 while the run has no metrics
+	Error  string             `json:"error,omitempty"`
+
+	Metrics *Metrics `json:"metrics,omitempty"`
+}
+
+// Sweep persisted state and result matrix of a parameter sweep
+type Sweep struct {
+	SweepID     string        `json:"sweep_id"`
+	UserID      string        `json:"user_id,omitempty"`
+	Label       string        `json:"label,omitempty"`
+	State       RunState      `json:"state"`
+	RankBy      string        `json:"rank_by"`
+	Parallelism int           `json:"parallelism"`
+	Params      []SweepParam  `json:"params"`
+	Symbols     []string      `json:"symbols"`
+	StartTS     int64         `json:"start_ts"`
+	EndTS       int64         `json:"end_ts"`
+	Results     []SweepResult `json:"results"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// sweepJob an active sweep and the means to stop it
+type sweepJob struct {
+	mu     sync.Mutex
+	sweep  *Sweep
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// snapshot copy of the sweep safe to hand out while runs are updating it
+func (j *sweepJob) snapshot() *Sweep {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	copied := *j.sweep
+	copied.Results = append([]SweepResult(nil), j.sweep.Results...)
+	return &copied
+}
+
+// Validate checks the sweep and fills in defaults, the base config is validated per run
+func (cfg *SweepConfig) Validate() error {
+	cfg.SweepID = strings.TrimSpace(cfg.SweepID)
+	if cfg.SweepID == "" {
+		return fmt.Errorf("sweep_id cannot be empty")
+	}
+	cfg.UserID = strings.TrimSpace(cfg.UserID)
+	if cfg.UserID == "" {
+		cfg.UserID = "default"
+	}
+	if len(cfg.Params) == 0 {
+		return fmt.Errorf("at least one sweep parameter is required")
+	}
+	if cfg.Parallelism <= 0 {
+		cfg.Parallelism = defaultSweepParallel
+	}
+	if cfg.Parallelism > maxSweepParallel {
+		cfg.Parallelism = maxSweepParallel
+	}
+	switch cfg.RankBy {
+	case "":
+		cfg.RankBy = SweepRankSharpe
+	case SweepRankSharpe, SweepRankMaxDrawdown, SweepRankReturn:
+	default:
+		return fmt.Errorf("unsupported rank_by '%s'", cfg.RankBy)
+	}
+
+	seen := make(map[string]bool, len(cfg.Params))
+	for i := range cfg.Params {
+		p := &cfg.Params[i]
+		p.Name = strings.ToLower(strings.TrimSpace(p.Name))
+		if seen[p.Name] {
+			return fmt.Errorf("sweep parameter '%s' given twice", p.Name)
+		}
+		seen[p.Name] = true
+		values, err := p.expand()
+		if err != nil {
+			return err
+		}
+		p.Values = values
+	}
+	if seen[SweepParamLeverage] && (seen[SweepParamBTCETHLeverage] || seen[SweepParamAltcoinLeverage]) {
+		return fmt.Errorf("leverage cannot be swept together with btc_eth_leverage/altcoin_leverage")
+	}
+
+	if seen[SweepParamGridCount] || seen[SweepParamATRMultiplier] {
+		strategy := cfg.Base.loadedStrategy
+		if strategy == nil || strategy.GridConfig == nil {
+			return fmt.Errorf("grid_count/atr_multiplier sweeps require a grid strategy (strategy_id)")
+		}
+	}
+
+	total := 1
+	for _, p := range cfg.Params {
+		total *= len(p.Values)
+		if total > MaxSweepCombinations {
+			return fmt.Errorf("sweep has too many combinations (max %d)", MaxSweepCombinations)
+		}
+	}
+	return nil
+}
+
+// expand resolves the parameter's values, rounding integer parameters and dropping duplicates
+func (p SweepParam) expand() ([]float64, error) {
+	var lowest float64
+	integer := true
+	switch p.Name {
+	case SweepParamLeverage, SweepParamBTCETHLeverage, SweepParamAltcoinLeverage, SweepParamDecisionCadence:
+		lowest = 1
+	case SweepParamGridCount:
+		lowest = 2
+	case SweepParamMinConfidence:
+		lowest = 0
+	case SweepParamATRMultiplier:
+		integer = false
+	default:
+		return nil, fmt.Errorf("unsupported sweep parameter '%s'", p.Name)
+	}
+
+	raw := p.Values
+	if len(raw) == 0 {
+		if p.Step <= 0 || p.Max < p.Min {
+			return nil, fmt.Errorf("sweep parameter '%s' needs values or a min/max range with a positive step", p.Name)
+		}
+		steps := int(math.Floor((p.Max-p.Min)/p.Step+1e-9)) + 1
+		if steps > MaxSweepCombinations {
+			return nil, fmt.Errorf("sweep parameter '%s' has too many values (max %d)", p.Name, MaxSweepCombinations)
+		}
+		for i := 0; i < steps; i++ {
+			raw = append(raw, p.Min+float64(i)*p.Step)
+		}
+	}
+
+	values := make([]float64, 0, len(raw))
+	seen := make(map[float64]bool, len(raw))
+	for _, v := range raw {
+		if integer {
+			v = math.Round(v)
+		} else {
+			v = math.Round(v*1e6) / 1e6
+		}
+		switch {
+		case p.Name == SweepParamATRMultiplier && v <= 0:
+			return nil, fmt.Errorf("atr_multiplier must be positive, got %v", v)
+		case integer && v < lowest:
+			return nil, fmt.Errorf("%s must be at least %v, got %v", p.Name, lowest, v)
+		case p.Name == SweepParamMinConfidence && v > 100:
+			return nil, fmt.Errorf("min_confidence must be at most 100, got %v", v)
+		}
+		if !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+// Combinations cartesian product of the parameter values, in parameter order
+func (cfg *SweepConfig) Combinations() []map[string]float64 {
+	combos := []map[string]float64{{}}
+	for _, p := range cfg.Params {
+		next := make([]map[string]float64, 0, len(combos)*len(p.Values))
+		for _, combo := range combos {
+			for _, v := range p.Values {
+				cell := make(map[string]float64, len(combo)+1)
+				for k, existing := range combo {
+					cell[k] = existing
+				}
+				cell[p.Name] = v
+				next = append(next, cell)
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// variantConfig run config of one sweep cell: the base config with the cell's parameters applied
+func (cfg *SweepConfig) variantConfig(runID string, params map[string]float64) (BacktestConfig, error) {
+	variant := cfg.Base
+	variant.RunID = runID
+	variant.UserID = cfg.UserID
+	variant.Symbols = append([]string(nil), cfg.Base.Symbols...)
+	variant.Timeframes = append([]string(nil), cfg.Base.Timeframes...)
+
+	var strategy *store.StrategyConfig
+	if cfg.Base.loadedStrategy != nil {
+		// Deep copy, the strategy holds pointers (grid config) shared by every cell
+		data, err := json.Marshal(cfg.Base.loadedStrategy)
+		if err != nil {
+			return variant, fmt.Errorf("failed to copy strategy: %w", err)
+		}
+		strategy = &store.StrategyConfig{}
+		if err := json.Unmarshal(data, strategy); err != nil {
+			return variant, fmt.Errorf("failed to copy strategy: %w", err)
+		}
+		variant.SetLoadedStrategy(strategy)
+	}
+
+	for name, v := range params {
+		switch name {
+		case SweepParamLeverage:
+			variant.Leverage.BTCETHLeverage = int(v)
+			variant.Leverage.AltcoinLeverage = int(v)
+			if strategy != nil && strategy.GridConfig != nil && !strategy.GridConfig.IsSpot() {
+				strategy.GridConfig.Leverage = int(v)
+			}
+		case SweepParamBTCETHLeverage:
+			variant.Leverage.BTCETHLeverage = int(v)
+		case SweepParamAltcoinLeverage:
+			variant.Leverage.AltcoinLeverage = int(v)
+		case SweepParamDecisionCadence:
+			variant.DecisionCadenceNBars = int(v)
+		case SweepParamMinConfidence:
+			if strategy == nil {
+				// Without a saved strategy the run's risk control is built from defaults, start from those
+				strategy = variant.ToStrategyConfig()
+				variant.SetLoadedStrategy(strategy)
+			}
+			strategy.RiskControl.MinConfidence = int(v)
+		case SweepParamGridCount:
+			strategy.GridConfig.GridCount = int(v)
+		case SweepParamATRMultiplier:
+			strategy.GridConfig.ATRMultiplier = v
+			strategy.GridConfig.UseATRBounds = true
+		}
+	}
+	return variant, nil
+}
+
+// sweepCellLabel run label listing the cell's parameters, e.g. "sweep_x: leverage=3 grid_count=20"
+func sweepCellLabel(sweepID string, params []SweepParam, cell map[string]float64) string {
+	parts := make([]string, 0, len(params))
+	for _, p := range params {
+		parts = append(parts, p.Name+"="+strconv.FormatFloat(cell[p.Name], 'f', -1, 64))
+	}
+	return sweepID + ": " + strings.Join(parts, " ")
+}
+
+// RankSweepResults orders results best first by rankBy and numbers their ranks
+// Results without metrics (failed or unfinished runs) go last, unranked.
+func RankSweepResults(results []SweepResult, rankBy string) {
+	better := func(a, b *Metrics) bool {
+		switch rankBy {
+		case SweepRankMaxDrawdown:
+			if a.MaxDrawdownPct != b.MaxDrawdownPct {
+				return a.MaxDrawdownPct < b.MaxDrawdownPct
+			}
+			return a.SharpeRatio > b.SharpeRatio
+		case SweepRankReturn:
+			if a.TotalReturnPct != b.TotalReturnPct {
+				return a.TotalReturnPct > b.TotalReturnPct
+			}
+			return a.MaxDrawdownPct < b.MaxDrawdownPct
+		default:
+			if a.SharpeRatio != b.SharpeRatio {
+				return a.SharpeRatio > b.SharpeRatio
+			}
+			return a.MaxDrawdownPct < b.MaxDrawdownPct
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Metrics, results[j].Metrics
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return better(a, b)
+	})
+	for i := range results {
+		results[i].Rank = 0
+		if results[i].Metrics != nil {
+			results[i].Rank = i + 1
+		}
+	}
+}
+
+// StartSweep starts one backtest run per parameter combination, at most Parallelism at a time
+func (m *Manager) StartSweep(cfg SweepConfig) (*Sweep, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if err := m.resolveAIConfig(&cfg.Base); err != nil {
+		return nil, err
+	}
+
+	combos := cfg.Combinations()
+	now := time.Now().UTC()
+	sweep := &Sweep{
+		SweepID:     cfg.SweepID,
+		UserID:      cfg.UserID,
+		Label:       strings.TrimSpace(cfg.Label),
+		State:       RunStateRunning,
+		RankBy:      cfg.RankBy,
+		Parallelism: cfg.Parallelism,
+		Params:      cfg.Params,
+		Symbols:     cfg.Base.Symbols,
+		StartTS:     cfg.Base.StartTS,
+		EndTS:       cfg.Base.EndTS,
+		Results:     make([]SweepResult, len(combos)),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	variants := make([]BacktestConfig, len(combos))
+	for i, cell := range combos {
+		runID := fmt.Sprintf("%s_%02d", cfg.SweepID, i+1)
+		variant, err := cfg.variantConfig(runID, cell)
+		if err != nil {
+			return nil, err
+		}
+		// Fail fast on a bad base config instead of once per run
+		if err := variant.Validate(); err != nil {
+			return nil, err
+		}
+		variants[i] = variant
+		sweep.Results[i] = SweepResult{RunID: runID, Params: cell, State: RunStateCreated}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &sweepJob{sweep: sweep, cancel: cancel, done: make(chan struct{})}
+
+	m.mu.Lock()
+	if existing, ok := m.sweeps[cfg.SweepID]; ok {
+		select {
+		case <-existing.done:
+		default:
+			m.mu.Unlock()
+			cancel()
+			return nil, fmt.Errorf("sweep %s is already active", cfg.SweepID)
+		}
+	}
+	m.sweeps[cfg.SweepID] = job
+	m.mu.Unlock()
+
+	if err := SaveSweep(sweep); err != nil {
+		cancel()
+		m.mu.Lock()
+		delete(m.sweeps, cfg.SweepID)
+		m.mu.Unlock()
+		return nil, err
+	}
+
+	logger.Infof("📊 Starting backtest sweep %s: %d runs, parallelism %d, ranked by %s",
+		cfg.SweepID, len(variants), cfg.Parallelism, cfg.RankBy)
+	go m.runSweep(ctx, job, variants, combos)
+	return job.snapshot(), nil
+}
+
+// runSweep executes the sweep's runs and ranks the finished matrix
+func (m *Manager) runSweep(ctx context.Context, job *sweepJob, variants []BacktestConfig, cells []map[string]float64) {
+	defer close(job.done)
+	defer job.cancel()
+
+	slots := make(chan struct{}, job.sweep.Parallelism)
+	var wg sync.WaitGroup
+	for i := range variants {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			m.updateSweepResult(job, variants[i].RunID, RunStateStopped, nil, "sweep stopped before the run started")
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			m.runSweepCell(ctx, job, variants[i], cells[i])
+		}(i)
+	}
+	wg.Wait()
+
+	job.mu.Lock()
+	if ctx.Err() != nil {
+		job.sweep.State = RunStateStopped
+	} else {
+		job.sweep.State = RunStateCompleted
+	}
+	RankSweepResults(job.sweep.Results, job.sweep.RankBy)
+	job.sweep.UpdatedAt = time.Now().UTC()
+	final := *job.sweep
+	job.mu.Unlock()
+
+	if err := SaveSweep(&final); err != nil {
+		logger.Infof("failed to save backtest sweep %s: %v", final.SweepID, err)
+	}
+	logger.Infof("📊 Backtest sweep %s %s", final.SweepID, final.State)
+}
+
+// runSweepCell runs one combination to its end and records its metrics
+func (m *Manager) runSweepCell(ctx context.Context, job *sweepJob, cfg BacktestConfig, cell map[string]float64) {
+	m.updateSweepResult(job, cfg.RunID, RunStateRunning, nil, "")
+	state, metrics, err := m.runToEnd(ctx, cfg, sweepCellLabel(job.sweep.SweepID, job.sweep.Params, cell))
+	msg := ""
+	if err != nil {
+		msg = err.Error()
+	}
+	m.updateSweepResult(job, cfg.RunID, state, metrics, msg)
+}
+
+// runToEnd starts a labelled run and waits for it to end, returning its final state and metrics (nil if none were saved)
+func (m *Manager) runToEnd(ctx context.Context, cfg BacktestConfig, label string) (RunState, *Metrics, error) {
+	runner, err := m.Start(ctx, cfg)
+	if err != nil {
+		return RunStateFailed, nil, err
+	}
+	if _, err := m.UpdateLabel(cfg.RunID, label); err != nil {
+		logger.Infof("failed to label backtest run %s: %v", cfg.RunID, err)
+	}
+	runErr := runner.Wait()
+	metrics, err := LoadMetrics(cfg.RunID)
+	if err != nil {
+		metrics = nil
+	}
+	return runner.Status(), metrics, runErr
+}
+
+// updateSweepResult records a cell's progress, re-ranks the matrix and persists it
+func (m *Manager) updateSweepResult(job *sweepJob, runID string, state RunState, metrics *Metrics, errMsg string) {
+	job.mu.Lock()
+	// Results are re-ordered by ranking, find the cell by run ID
+	for i := range job.sweep.Results {
+		result := &job.sweep.Results[i]
+		if result.RunID != runID {
+			continue
+		}
+		result.State = state
+		result.Error = errMsg
+		if metrics != nil {
+			result.Metrics = metrics
+		}
+	}
+	RankSweepResults(job.sweep.Results, job.sweep.RankBy)
+	job.sweep.UpdatedAt = time.Now().UTC()
+	snapshot := *job.sweep
+	snapshot.Results = append([]SweepResult(nil), job.sweep.Results...)
+	job.mu.Unlock()
+
+	if err := SaveSweep(&snapshot); err != nil {
+		logger.Infof("failed to save backtest sweep %s: %v", snapshot.SweepID, err)
+	}
+}
+
+// GetSweep current state of a sweep, active or persisted
+func (m *Manager) GetSweep(sweepID string) (*Sweep, error) {
+	m.mu.RLock()
+	job, ok := m.sweeps[sweepID]
+	m.mu.RUnlock()
+	if ok {
+		return job.snapshot(), nil
+	}
+	sweep, err := LoadSweep(sweepID)
+	if err != nil {
+		return nil, err
+	}
+	markInterrupted(sweep)
+	return sweep, nil
+}
+
+// ListSweeps all sweeps, newest first
+func (m *Manager) ListSweeps() ([]*Sweep, error) {
+	sweeps, err := LoadSweeps()
+	if err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	for i, sweep := range sweeps {
+		if job, ok := m.sweeps[sweep.SweepID]; ok {
+			sweeps[i] = job.snapshot()
+		} else {
+			markInterrupted(sweep)
+		}
+	}
+	m.mu.RUnlock()
+	sort.Slice(sweeps, func(i, j int) bool {
+		return sweeps[i].CreatedAt.After(sweeps[j].CreatedAt)
+	})
+	return sweeps, nil
+}
+
+// markInterrupted reports a sweep left running by a restart as stopped, its runs can be resumed individually
+func markInterrupted(sweep *Sweep) {
+	if sweep.State == RunStateRunning {
+		sweep.State = RunStateStopped
+	}
+}
+
+// StopSweep stops the sweep's active runs and skips the ones not started yet
+func (m *Manager) StopSweep(sweepID string) error {
+	m.mu.RLock()
+	job, ok := m.sweeps[sweepID]
+	m.mu.RUnlock()
+	if !ok {
+		if _, err := LoadSweep(sweepID); err != nil {
+			return err
+		}
+		return nil
+	}
+	job.cancel()
+	<-job.done
+	return nil
+}
+
+// DeleteSweep stops the sweep and deletes it together with its runs
+func (m *Manager) DeleteSweep(sweepID string) error {
+	if err := m.StopSweep(sweepID); err != nil {
+		return err
+	}
+	sweep, err := m.GetSweep(sweepID)
+	if err != nil {
+		return err
+	}
+	for _, result := range sweep.Results {
+		if err := m.Delete(result.RunID); err != nil {
+			logger.Infof("failed to delete sweep run %s: %v", result.RunID, err)
+		}
+	}
+	m.mu.Lock()
+	delete(m.sweeps, sweepID)
+	m.mu.Unlock()
+	return deleteSweep(sweepID)
+}
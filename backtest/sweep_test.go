@@ -0,0 +1,85 @@
+package backtest
+
+import (
+	"testing"
+
+	"nofx/store"
+)
+
+func TestSweepCombinationsAndVariants(t *testing.T) {
+	cfg := SweepConfig{
+		SweepID: "sweep_t",
+		Base: BacktestConfig{
+			Symbols: []string{"BTCUSDT"},
+			StartTS: 1, EndTS: 2,
+		},
+		Params: []SweepParam{
+			{Name: "grid_count", Min: 10, Max: 50, Step: 20},
+			{Name: "Leverage", Values: []float64{2, 2.4, 5}},
+		},
+	}
+	cfg.Base.SetLoadedStrategy(&store.StrategyConfig{GridConfig: &store.GridStrategyConfig{GridCount: 20, Leverage: 3}})
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if got := cfg.Params[1].Values; len(got) != 2 || got[0] != 2 || got[1] != 5 {
+		t.Fatalf("leverage values should be rounded and deduplicated, got %v", got)
+	}
+	combos := cfg.Combinations()
+	if len(combos) != 6 {
+		t.Fatalf("expected 3x2 combinations, got %d", len(combos))
+	}
+
+	variant, err := cfg.variantConfig("sweep_t_01", combos[len(combos)-1])
+	if err != nil {
+		t.Fatalf("variant: %v", err)
+	}
+	grid := variant.loadedStrategy.GridConfig
+	if grid.GridCount != 50 || grid.Leverage != 5 || variant.Leverage.AltcoinLeverage != 5 {
+		t.Errorf("variant not applied: grid=%+v leverage=%+v", grid, variant.Leverage)
+	}
+	if cfg.Base.loadedStrategy.GridConfig.GridCount != 20 {
+		t.Errorf("variant must not modify the base strategy")
+	}
+
+	noGrid := SweepConfig{SweepID: "x", Params: []SweepParam{{Name: "atr_multiplier", Values: []float64{1, 2}}}}
+	if err := noGrid.Validate(); err == nil {
+		t.Errorf("atr_multiplier sweep without a grid strategy should fail")
+	}
+	tooMany := SweepConfig{SweepID: "x", Params: []SweepParam{
+		{Name: "leverage", Min: 1, Max: 20, Step: 1},
+		{Name: "min_confidence", Min: 50, Max: 90, Step: 10},
+	}}
+	if err := tooMany.Validate(); err == nil {
+		t.Errorf("expected the combination cap to reject 100 runs")
+	}
+}
+
+func TestRankSweepResults(t *testing.T) {
+	results := []SweepResult{
+		{RunID: "a", Metrics: &Metrics{SharpeRatio: 1.2, MaxDrawdownPct: 20, TotalReturnPct: 30}},
+		{RunID: "failed"},
+		{RunID: "b", Metrics: &Metrics{SharpeRatio: 1.2, MaxDrawdownPct: 10, TotalReturnPct: 10}},
+		{RunID: "c", Metrics: &Metrics{SharpeRatio: 0.5, MaxDrawdownPct: 5, TotalReturnPct: 40}},
+	}
+	order := func() string {
+		s := ""
+		for _, r := range results {
+			s += r.RunID + " "
+		}
+		return s
+	}
+
+	RankSweepResults(results, SweepRankSharpe)
+	if got := order(); got != "b a c failed " || results[0].Rank != 1 || results[3].Rank != 0 {
+		t.Errorf("sharpe ranking: got %q", got)
+	}
+	RankSweepResults(results, SweepRankMaxDrawdown)
+	if got := order(); got != "c b a failed " {
+		t.Errorf("drawdown ranking: got %q", got)
+	}
+	RankSweepResults(results, SweepRankReturn)
+	if got := order(); got != "c a b failed " {
+		t.Errorf("return ranking: got %q", got)
+	}
+}
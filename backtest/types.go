@@ -91,6 +91,11 @@ type Metrics struct {
 	WorstSymbol    string                   `json:"worst_symbol"`
 	SymbolStats    map[string]SymbolMetrics `json:"symbol_stats"`
 	Liquidated     bool                     `json:"liquidated"`
+
+	// Execution costs paid and the assumptions they were simulated with, for reproducing the result
+	TotalFees       float64          `json:"total_fees"`
+	TotalSlippage   float64          `json:"total_slippage"` // Cost of adverse fill prices in USDT
+	CostAssumptions *CostAssumptions `json:"cost_assumptions,omitempty"`
 }
 
 // SymbolMetrics records performance for a single symbol.
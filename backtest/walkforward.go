@@ -0,0 +1,568 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nofx/logger"
+)
+
+// ============================================================================
+// Walk-Forward Validation
+// ============================================================================
+// History is split into rolling train/test windows. On each train window the
+// swept parameters are optimized (a sweep ranked by RankBy), then the best
+// combination is run on the following out-of-sample test window. Without
+// swept parameters the base config is run on both windows, which still shows
+// how far in-sample results carry over. A configuration whose test windows
+// hold up against its train windows is robust; one that only shines in-sample
+// is overfit.
+// ============================================================================
+
+const (
+	// MaxWalkForwardWindows caps the number of train/test windows
+	MaxWalkForwardWindows = 24
+	// MaxWalkForwardRuns caps the runs of all windows together (train candidates and test runs)
+	MaxWalkForwardRuns = 128
+)
+
+// WalkForwardConfig input of a walk-forward validation
+type WalkForwardConfig struct {
+	WalkForwardID string         `json:"walk_forward_id"`
+	UserID        string         `json:"user_id,omitempty"`
+	Label         string         `json:"label,omitempty"`
+	Base          BacktestConfig `json:"base"` // start_ts/end_ts span all windows
+	TrainHours    int            `json:"train_hours"`
+	TestHours     int            `json:"test_hours"`
+	StepHours     int            `json:"step_hours,omitempty"` // Window shift (default test_hours, i.e. back-to-back test windows)
+	Anchored      bool           `json:"anchored,omitempty"`   // Train windows all start at start_ts and grow
+	Params        []SweepParam   `json:"params,omitempty"`     // Optimized on every train window, optional
+	Parallelism   int            `json:"parallelism,omitempty"`
+	RankBy        string         `json:"rank_by,omitempty"`
+}
+
+// WalkForwardWindow one train/test window and its results
+type WalkForwardWindow struct {
+	Index        int                `json:"index"`
+	TrainStartTS int64              `json:"train_start_ts"`
+	TrainEndTS   int64              `json:"train_end_ts"`
+	TestStartTS  int64              `json:"test_start_ts"`
+	TestEndTS    int64              `json:"test_end_ts"`
+	State        RunState           `json:"state"`
+	Error        string             `json:"error,omitempty"`
+	BestParams   map[string]float64 `json:"best_params,omitempty"`
+	TrainRunID   string             `json:"train_run_id,omitempty"` // Run of the best train candidate
+	TestRunID    string             `json:"test_run_id,omitempty"`
+	TrainMetrics *Metrics           `json:"train_metrics,omitempty"`
+	TestMetrics  *Metrics           `json:"test_metrics,omitempty"`
+	Candidates   []SweepResult      `json:"candidates,omitempty"` // Ranked train sweep, only with swept parameters
+}
+
+// WalkForwardSummary out-of-sample metrics aggregated over completed windows
+type WalkForwardSummary struct {
+	Windows                 int     `json:"windows"`
+	CompletedWindows        int     `json:"completed_windows"`
+	ProfitableWindows       int     `json:"profitable_windows"`
+	ProfitableRatio         float64 `json:"profitable_ratio"`
+	AvgTestReturnPct        float64 `json:"avg_test_return_pct"`
+	CompoundedTestReturnPct float64 `json:"compounded_test_return_pct"`
+	AvgTestSharpe           float64 `json:"avg_test_sharpe"`
+	WorstTestDrawdownPct    float64 `json:"worst_test_drawdown_pct"`
+	AvgTrainReturnPct       float64 `json:"avg_train_return_pct"`
+	AvgTrainSharpe          float64 `json:"avg_train_sharpe"`
+	// Efficiency average test return / average train return, 0 when train windows lost money
+	// Around 0.5 or more suggests the edge survives out of sample.
+	Efficiency float64 `json:"efficiency"`
+	// ParamStability share of completed windows that picked the most common best parameters
+	ParamStability float64 `json:"param_stability,omitempty"`
+}
+
+// WalkForward persisted state and results of a walk-forward validation
+type WalkForward struct {
+	WalkForwardID string              `json:"walk_forward_id"`
+	UserID        string              `json:"user_id,omitempty"`
+	Label         string              `json:"label,omitempty"`
+	State         RunState            `json:"state"`
+	TrainHours    int                 `json:"train_hours"`
+	TestHours     int                 `json:"test_hours"`
+	StepHours     int                 `json:"step_hours"`
+	Anchored      bool                `json:"anchored"`
+	Params        []SweepParam        `json:"params,omitempty"`
+	RankBy        string              `json:"rank_by"`
+	Symbols       []string            `json:"symbols"`
+	StartTS       int64               `json:"start_ts"`
+	EndTS         int64               `json:"end_ts"`
+	Windows       []WalkForwardWindow `json:"windows"`
+	Summary       WalkForwardSummary  `json:"summary"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+}
+
+// walkForwardJob an active walk-forward validation and the means to stop it
+type walkForwardJob struct {
+	mu     sync.Mutex
+	wf     *WalkForward
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (j *walkForwardJob) snapshot() *WalkForward {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	copied := *j.wf
+	copied.Windows = append([]WalkForwardWindow(nil), j.wf.Windows...)
+	return &copied
+}
+
+// Validate checks the windows and swept parameters and fills in defaults
+func (cfg *WalkForwardConfig) Validate() error {
+	cfg.WalkForwardID = strings.TrimSpace(cfg.WalkForwardID)
+	if cfg.WalkForwardID == "" {
+		return fmt.Errorf("walk_forward_id cannot be empty")
+	}
+	cfg.UserID = strings.TrimSpace(cfg.UserID)
+	if cfg.UserID == "" {
+		cfg.UserID = "default"
+	}
+	if cfg.TrainHours <= 0 || cfg.TestHours <= 0 {
+		return fmt.Errorf("train_hours and test_hours must be positive")
+	}
+	if cfg.StepHours <= 0 {
+		cfg.StepHours = cfg.TestHours
+	}
+
+	windows := cfg.Windows()
+	if len(windows) == 0 {
+		return fmt.Errorf("start_ts/end_ts must span at least one train and test window (%dh + %dh)", cfg.TrainHours, cfg.TestHours)
+	}
+	if len(windows) > MaxWalkForwardWindows {
+		return fmt.Errorf("walk-forward has more than %d windows, increase step_hours", MaxWalkForwardWindows)
+	}
+
+	combinations := 1
+	if len(cfg.Params) > 0 {
+		sweep := cfg.trainSweep(0, windows[0])
+		if err := sweep.Validate(); err != nil {
+			return err
+		}
+		cfg.Params, cfg.RankBy, cfg.Parallelism = sweep.Params, sweep.RankBy, sweep.Parallelism
+		combinations = len(sweep.Combinations())
+	} else {
+		cfg.RankBy = SweepRankSharpe
+		cfg.Parallelism = 1
+	}
+	if runs := len(windows) * (combinations + 1); runs > MaxWalkForwardRuns {
+		return fmt.Errorf("walk-forward needs %d runs (max %d), reduce windows or swept values", runs, MaxWalkForwardRuns)
+	}
+	return nil
+}
+
+// Windows train/test windows covering the base config's time range
+func (cfg *WalkForwardConfig) Windows() []WalkForwardWindow {
+	train := int64(cfg.TrainHours) * 3600
+	test := int64(cfg.TestHours) * 3600
+	step := int64(cfg.StepHours) * 3600
+	if train <= 0 || test <= 0 || step <= 0 {
+		return nil
+	}
+	var windows []WalkForwardWindow
+	for offset := int64(0); ; offset += step {
+		trainStart := cfg.Base.StartTS + offset
+		if cfg.Anchored {
+			trainStart = cfg.Base.StartTS
+		}
+		trainEnd := cfg.Base.StartTS + offset + train
+		testEnd := trainEnd + test
+		if testEnd > cfg.Base.EndTS || len(windows) > MaxWalkForwardWindows {
+			break
+		}
+		windows = append(windows, WalkForwardWindow{
+			Index:        len(windows) + 1,
+			TrainStartTS: trainStart,
+			TrainEndTS:   trainEnd,
+			TestStartTS:  trainEnd,
+			TestEndTS:    testEnd,
+			State:        RunStateCreated,
+		})
+	}
+	return windows
+}
+
+// trainSweep sweep over the swept parameters restricted to one window's span
+func (cfg *WalkForwardConfig) trainSweep(index int, window WalkForwardWindow) SweepConfig {
+	base := cfg.Base
+	base.StartTS, base.EndTS = window.TrainStartTS, window.TrainEndTS
+	return SweepConfig{
+		SweepID:     fmt.Sprintf("%s_w%02d_train", cfg.WalkForwardID, index+1),
+		UserID:      cfg.UserID,
+		Base:        base,
+		Params:      cfg.Params,
+		Parallelism: cfg.Parallelism,
+		RankBy:      cfg.RankBy,
+	}
+}
+
+// SummarizeWalkForward aggregates the out-of-sample metrics of completed windows
+func SummarizeWalkForward(windows []WalkForwardWindow) WalkForwardSummary {
+	summary := WalkForwardSummary{Windows: len(windows)}
+	compounded := 1.0
+	picks := make(map[string]int)
+	for _, w := range windows {
+		if w.TestMetrics == nil || w.TrainMetrics == nil {
+			continue
+		}
+		summary.CompletedWindows++
+		test, train := w.TestMetrics, w.TrainMetrics
+		if test.TotalReturnPct > 0 {
+			summary.ProfitableWindows++
+		}
+		summary.AvgTestReturnPct += test.TotalReturnPct
+		summary.AvgTestSharpe += test.SharpeRatio
+		summary.AvgTrainReturnPct += train.TotalReturnPct
+		summary.AvgTrainSharpe += train.SharpeRatio
+		summary.WorstTestDrawdownPct = math.Max(summary.WorstTestDrawdownPct, test.MaxDrawdownPct)
+		compounded *= 1 + test.TotalReturnPct/100
+		if len(w.BestParams) > 0 {
+			picks[paramsKey(w.BestParams)]++
+		}
+	}
+	if summary.CompletedWindows == 0 {
+		return summary
+	}
+	n := float64(summary.CompletedWindows)
+	summary.ProfitableRatio = float64(summary.ProfitableWindows) / n
+	summary.AvgTestReturnPct /= n
+	summary.AvgTestSharpe /= n
+	summary.AvgTrainReturnPct /= n
+	summary.AvgTrainSharpe /= n
+	summary.CompoundedTestReturnPct = (compounded - 1) * 100
+	if summary.AvgTrainReturnPct > 0 {
+		summary.Efficiency = summary.AvgTestReturnPct / summary.AvgTrainReturnPct
+	}
+	mode := 0
+	for _, count := range picks {
+		if count > mode {
+			mode = count
+		}
+	}
+	if mode > 0 {
+		summary.ParamStability = float64(mode) / n
+	}
+	return summary
+}
+
+// paramsKey canonical form of a parameter combination, for comparing picks across windows
+func paramsKey(params map[string]float64) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%v", name, params[name]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// StartWalkForward starts a walk-forward validation, windows are processed one after another
+func (m *Manager) StartWalkForward(cfg WalkForwardConfig) (*WalkForward, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if err := m.resolveAIConfig(&cfg.Base); err != nil {
+		return nil, err
+	}
+	windows := cfg.Windows()
+	// Fail fast on a bad base config instead of once per window
+	probe := cfg.trainSweep(0, windows[0])
+	check, err := probe.variantConfig(probe.SweepID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := check.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	wf := &WalkForward{
+		WalkForwardID: cfg.WalkForwardID,
+		UserID:        cfg.UserID,
+		Label:         strings.TrimSpace(cfg.Label),
+		State:         RunStateRunning,
+		TrainHours:    cfg.TrainHours,
+		TestHours:     cfg.TestHours,
+		StepHours:     cfg.StepHours,
+		Anchored:      cfg.Anchored,
+		Params:        cfg.Params,
+		RankBy:        cfg.RankBy,
+		Symbols:       cfg.Base.Symbols,
+		StartTS:       cfg.Base.StartTS,
+		EndTS:         cfg.Base.EndTS,
+		Windows:       windows,
+		Summary:       WalkForwardSummary{Windows: len(windows)},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &walkForwardJob{wf: wf, cancel: cancel, done: make(chan struct{})}
+
+	m.mu.Lock()
+	if existing, ok := m.walkForwards[cfg.WalkForwardID]; ok {
+		select {
+		case <-existing.done:
+		default:
+			m.mu.Unlock()
+			cancel()
+			return nil, fmt.Errorf("walk-forward %s is already active", cfg.WalkForwardID)
+		}
+	}
+	m.walkForwards[cfg.WalkForwardID] = job
+	m.mu.Unlock()
+
+	if err := SaveWalkForward(wf); err != nil {
+		cancel()
+		m.mu.Lock()
+		delete(m.walkForwards, cfg.WalkForwardID)
+		m.mu.Unlock()
+		return nil, err
+	}
+
+	logger.Infof("📊 Starting walk-forward %s: %d windows (train %dh / test %dh, step %dh, anchored=%v), %d swept params",
+		cfg.WalkForwardID, len(windows), cfg.TrainHours, cfg.TestHours, cfg.StepHours, cfg.Anchored, len(cfg.Params))
+	go m.runWalkForward(ctx, job, cfg)
+	return job.snapshot(), nil
+}
+
+// runWalkForward optimizes and validates every window in turn
+func (m *Manager) runWalkForward(ctx context.Context, job *walkForwardJob, cfg WalkForwardConfig) {
+	defer close(job.done)
+	defer job.cancel()
+
+	windows := job.snapshot().Windows
+	for i, window := range windows {
+		if ctx.Err() != nil {
+			m.updateWalkForwardWindow(job, i, func(w *WalkForwardWindow) {
+				w.State = RunStateStopped
+			})
+			continue
+		}
+		m.updateWalkForwardWindow(job, i, func(w *WalkForwardWindow) { w.State = RunStateRunning })
+		m.runWalkForwardWindow(ctx, job, cfg, i, window)
+	}
+
+	job.mu.Lock()
+	if ctx.Err() != nil {
+		job.wf.State = RunStateStopped
+	} else {
+		job.wf.State = RunStateCompleted
+	}
+	job.wf.Summary = SummarizeWalkForward(job.wf.Windows)
+	job.wf.UpdatedAt = time.Now().UTC()
+	final := *job.wf
+	job.mu.Unlock()
+
+	if err := SaveWalkForward(&final); err != nil {
+		logger.Infof("failed to save walk-forward %s: %v", final.WalkForwardID, err)
+	}
+	logger.Infof("📊 Walk-forward %s %s: %d/%d windows, avg test return %.2f%%, efficiency %.2f",
+		final.WalkForwardID, final.State, final.Summary.CompletedWindows, final.Summary.Windows,
+		final.Summary.AvgTestReturnPct, final.Summary.Efficiency)
+}
+
+// runWalkForwardWindow picks the best train candidate of one window and runs it out of sample
+func (m *Manager) runWalkForwardWindow(ctx context.Context, job *walkForwardJob, cfg WalkForwardConfig, index int, window WalkForwardWindow) {
+	sweep := cfg.trainSweep(index, window)
+	fail := func(state RunState, err error) {
+		m.updateWalkForwardWindow(job, index, func(w *WalkForwardWindow) {
+			w.State = state
+			if err != nil {
+				w.Error = err.Error()
+			}
+		})
+	}
+
+	// Train: every combination of the swept parameters (or the base config alone) on the train span
+	cells := sweep.Combinations()
+	candidates := make([]SweepResult, len(cells))
+	slots := make(chan struct{}, sweep.Parallelism)
+	var wg sync.WaitGroup
+	for k, cell := range cells {
+		runID := fmt.Sprintf("%s_%02d", sweep.SweepID, k+1)
+		candidates[k] = SweepResult{RunID: runID, Params: cell, State: RunStateStopped}
+		variant, err := sweep.variantConfig(runID, cell)
+		if err != nil {
+			candidates[k].State, candidates[k].Error = RunStateFailed, err.Error()
+			continue
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(k int, variant BacktestConfig) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			label := fmt.Sprintf("%s w%02d train", cfg.WalkForwardID, index+1)
+			if len(cfg.Params) > 0 {
+				label = sweepCellLabel(label, cfg.Params, candidates[k].Params)
+			}
+			state, metrics, err := m.runToEnd(ctx, variant, label)
+			candidates[k].State, candidates[k].Metrics = state, metrics
+			if err != nil {
+				candidates[k].Error = err.Error()
+			}
+		}(k, variant)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		fail(RunStateStopped, nil)
+		return
+	}
+
+	RankSweepResults(candidates, sweep.RankBy)
+	best := candidates[0]
+	m.updateWalkForwardWindow(job, index, func(w *WalkForwardWindow) {
+		if len(cfg.Params) > 0 {
+			w.Candidates = candidates
+			w.BestParams = best.Params
+		}
+		w.TrainRunID, w.TrainMetrics = best.RunID, best.Metrics
+	})
+	if best.Metrics == nil {
+		fail(RunStateFailed, fmt.Errorf("no train run finished with metrics: %s", best.Error))
+		return
+	}
+
+	// Test: the best combination on the unseen span right after the train span
+	test := sweep
+	test.Base.StartTS, test.Base.EndTS = window.TestStartTS, window.TestEndTS
+	testRunID := fmt.Sprintf("%s_w%02d_test", cfg.WalkForwardID, index+1)
+	variant, err := test.variantConfig(testRunID, best.Params)
+	if err != nil {
+		fail(RunStateFailed, err)
+		return
+	}
+	label := fmt.Sprintf("%s w%02d test", cfg.WalkForwardID, index+1)
+	if len(cfg.Params) > 0 {
+		label = sweepCellLabel(label, cfg.Params, best.Params)
+	}
+	state, metrics, runErr := m.runToEnd(ctx, variant, label)
+	m.updateWalkForwardWindow(job, index, func(w *WalkForwardWindow) {
+		w.State, w.TestRunID, w.TestMetrics = state, testRunID, metrics
+		if runErr != nil {
+			w.Error = runErr.Error()
+		}
+	})
+}
+
+// updateWalkForwardWindow applies a change to one window, refreshes the summary and persists
+func (m *Manager) updateWalkForwardWindow(job *walkForwardJob, index int, update func(*WalkForwardWindow)) {
+	job.mu.Lock()
+	update(&job.wf.Windows[index])
+	job.wf.Summary = SummarizeWalkForward(job.wf.Windows)
+	job.wf.UpdatedAt = time.Now().UTC()
+	snapshot := *job.wf
+	snapshot.Windows = append([]WalkForwardWindow(nil), job.wf.Windows...)
+	job.mu.Unlock()
+
+	if err := SaveWalkForward(&snapshot); err != nil {
+		logger.Infof("failed to save walk-forward %s: %v", snapshot.WalkForwardID, err)
+	}
+}
+
+// GetWalkForward current state of a walk-forward validation, active or persisted
+func (m *Manager) GetWalkForward(id string) (*WalkForward, error) {
+	m.mu.RLock()
+	job, ok := m.walkForwards[id]
+	m.mu.RUnlock()
+	if ok {
+		return job.snapshot(), nil
+	}
+	wf, err := LoadWalkForward(id)
+	if err != nil {
+		return nil, err
+	}
+	if wf.State == RunStateRunning {
+		wf.State = RunStateStopped // Interrupted by a restart
+	}
+	return wf, nil
+}
+
+// ListWalkForwards all walk-forward validations, newest first
+func (m *Manager) ListWalkForwards() ([]*WalkForward, error) {
+	wfs, err := LoadWalkForwards()
+	if err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	for i, wf := range wfs {
+		if job, ok := m.walkForwards[wf.WalkForwardID]; ok {
+			wfs[i] = job.snapshot()
+		} else if wf.State == RunStateRunning {
+			wf.State = RunStateStopped
+		}
+	}
+	m.mu.RUnlock()
+	sort.Slice(wfs, func(i, j int) bool {
+		return wfs[i].CreatedAt.After(wfs[j].CreatedAt)
+	})
+	return wfs, nil
+}
+
+// StopWalkForward stops the active runs and skips the remaining windows
+func (m *Manager) StopWalkForward(id string) error {
+	m.mu.RLock()
+	job, ok := m.walkForwards[id]
+	m.mu.RUnlock()
+	if !ok {
+		_, err := LoadWalkForward(id)
+		return err
+	}
+	job.cancel()
+	<-job.done
+	return nil
+}
+
+// DeleteWalkForward stops the validation and deletes it together with its runs
+func (m *Manager) DeleteWalkForward(id string) error {
+	if err := m.StopWalkForward(id); err != nil {
+		return err
+	}
+	wf, err := m.GetWalkForward(id)
+	if err != nil {
+		return err
+	}
+	// Derive run IDs instead of reading them off the windows, runs of a stopped window are not recorded there
+	candidates := 1
+	for _, p := range wf.Params {
+		candidates *= len(p.Values)
+	}
+	for _, w := range wf.Windows {
+		runIDs := []string{fmt.Sprintf("%s_w%02d_test", wf.WalkForwardID, w.Index)}
+		for k := 1; k <= candidates; k++ {
+			runIDs = append(runIDs, fmt.Sprintf("%s_w%02d_train_%02d", wf.WalkForwardID, w.Index, k))
+		}
+		for _, runID := range runIDs {
+			if _, err := m.LoadMetadata(runID); err != nil {
+				continue // Never started
+			}
+			if err := m.Delete(runID); err != nil {
+				logger.Infof("failed to delete walk-forward run %s: %v", runID, err)
+			}
+		}
+	}
+	m.mu.Lock()
+	delete(m.walkForwards, id)
+	m.mu.Unlock()
+	return deleteWalkForward(id)
+}
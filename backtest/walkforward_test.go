@@ -0,0 +1,74 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWalkForwardWindows(t *testing.T) {
+	const day = 24 * 3600
+	cfg := WalkForwardConfig{
+		WalkForwardID: "wf_t",
+		Base:          BacktestConfig{StartTS: 1000, EndTS: 1000 + 10*day},
+		TrainHours:    72,
+		TestHours:     24,
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	windows := cfg.Windows()
+	// Rolling: 3d train + 1d test, shifted by 1d over 10d = 7 windows
+	if len(windows) != 7 {
+		t.Fatalf("expected 7 rolling windows, got %d", len(windows))
+	}
+	last := windows[len(windows)-1]
+	if last.TrainStartTS != 1000+6*day || last.TestStartTS != last.TrainEndTS || last.TestEndTS != cfg.Base.EndTS {
+		t.Errorf("unexpected last window: %+v", last)
+	}
+
+	cfg.Anchored = true
+	for _, w := range cfg.Windows() {
+		if w.TrainStartTS != cfg.Base.StartTS {
+			t.Fatalf("anchored train windows must start at start_ts: %+v", w)
+		}
+	}
+
+	tooShort := WalkForwardConfig{WalkForwardID: "x", Base: BacktestConfig{StartTS: 0, EndTS: 3 * day}, TrainHours: 72, TestHours: 24}
+	if err := tooShort.Validate(); err == nil {
+		t.Errorf("history shorter than one window should be rejected")
+	}
+	tooMany := cfg
+	tooMany.Params = []SweepParam{{Name: "leverage", Min: 1, Max: 20, Step: 1}}
+	if err := tooMany.Validate(); err == nil {
+		t.Errorf("7 windows x 21 runs should exceed the run cap")
+	}
+}
+
+func TestSummarizeWalkForward(t *testing.T) {
+	windows := []WalkForwardWindow{
+		{BestParams: map[string]float64{"leverage": 3},
+			TrainMetrics: &Metrics{TotalReturnPct: 20, SharpeRatio: 2},
+			TestMetrics:  &Metrics{TotalReturnPct: 10, SharpeRatio: 1, MaxDrawdownPct: 8}},
+		{BestParams: map[string]float64{"leverage": 3},
+			TrainMetrics: &Metrics{TotalReturnPct: 10, SharpeRatio: 1},
+			TestMetrics:  &Metrics{TotalReturnPct: -5, SharpeRatio: -0.5, MaxDrawdownPct: 12}},
+		{BestParams: map[string]float64{"leverage": 5},
+			TrainMetrics: &Metrics{TotalReturnPct: 30, SharpeRatio: 3},
+			TestMetrics:  &Metrics{TotalReturnPct: 5, SharpeRatio: 0.5, MaxDrawdownPct: 4}},
+		{State: RunStateFailed}, // Not completed, left out
+	}
+	s := SummarizeWalkForward(windows)
+	approx := func(got, want float64) bool { return math.Abs(got-want) < 1e-9 }
+	if s.Windows != 4 || s.CompletedWindows != 3 || s.ProfitableWindows != 2 {
+		t.Fatalf("unexpected counts: %+v", s)
+	}
+	if !approx(s.AvgTestReturnPct, 10.0/3) || !approx(s.AvgTrainReturnPct, 20) || !approx(s.Efficiency, 1.0/6) {
+		t.Errorf("unexpected averages: %+v", s)
+	}
+	if !approx(s.CompoundedTestReturnPct, (1.1*0.95*1.05-1)*100) || s.WorstTestDrawdownPct != 12 {
+		t.Errorf("unexpected compounding/drawdown: %+v", s)
+	}
+	if !approx(s.ParamStability, 2.0/3) {
+		t.Errorf("expected leverage=3 picked in 2 of 3 windows, got %v", s.ParamStability)
+	}
+}
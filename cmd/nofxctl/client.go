@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client calls the NOFX REST API with a profile's session token
+type Client struct {
+	server string
+	token  string
+	http   *http.Client
+}
+
+// NewClient creates a client for the server at baseURL (e.g. http://localhost:8080)
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		server: strings.TrimRight(baseURL, "/"),
+		token:  token,
+		http:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// APIError non-2xx response of the API
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Status == http.StatusUnauthorized {
+		return fmt.Sprintf("%s (HTTP 401), run nofxctl login", e.Message)
+	}
+	return fmt.Sprintf("%s (HTTP %d)", e.Message, e.Status)
+}
+
+// do sends body as JSON to /api+path and decodes the response into out (when not nil)
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.server+"/api"+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", c.server, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) != nil || apiErr.Error == "" {
+			apiErr.Error = strings.TrimSpace(string(data))
+		}
+		if apiErr.Error == "" {
+			apiErr.Error = http.StatusText(resp.StatusCode)
+		}
+		return &APIError{Status: resp.StatusCode, Message: apiErr.Error}
+	}
+	if out == nil {
+		return nil
+	}
+	if raw, ok := out.(*json.RawMessage); ok {
+		*raw = data
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// Trader entry of GET /api/my-traders
+type Trader struct {
+	ID           string  `json:"trader_id"`
+	Name         string  `json:"trader_name"`
+	AIModel      string  `json:"ai_model"`
+	ExchangeID   string  `json:"exchange_id"`
+	IsRunning    bool    `json:"is_running"`
+	PaperMode    bool    `json:"paper_mode"`
+	StrategyName string  `json:"strategy_name"`
+	Initial      float64 `json:"initial_balance"`
+}
+
+// DecisionAction one action of a decision cycle
+type DecisionAction struct {
+	Action     string  `json:"action"`
+	Symbol     string  `json:"symbol"`
+	Quantity   float64 `json:"quantity"`
+	Leverage   int     `json:"leverage"`
+	Price      float64 `json:"price"`
+	Confidence int     `json:"confidence"`
+	Success    bool    `json:"success"`
+	Error      string  `json:"error"`
+}
+
+// DecisionRecord decision cycle of GET /api/decisions/latest
+type DecisionRecord struct {
+	ID           int64     `json:"id"`
+	CycleNumber  int       `json:"cycle_number"`
+	Timestamp    time.Time `json:"timestamp"`
+	Success      bool      `json:"success"`
+	ErrorMessage string    `json:"error_message"`
+	AIModel      string    `json:"ai_model"`
+	AccountState struct {
+		TotalBalance          float64 `json:"total_balance"`
+		AvailableBalance      float64 `json:"available_balance"`
+		TotalUnrealizedProfit float64 `json:"total_unrealized_profit"`
+		PositionCount         int     `json:"position_count"`
+	} `json:"account_state"`
+	Decisions []DecisionAction `json:"decisions"`
+}
+
+// Statistics response of GET /api/statistics
+type Statistics struct {
+	TotalCycles         int              `json:"total_cycles"`
+	SuccessfulCycles    int              `json:"successful_cycles"`
+	FailedCycles        int              `json:"failed_cycles"`
+	TotalOpenPositions  int              `json:"total_open_positions"`
+	TotalClosePositions int              `json:"total_close_positions"`
+	RealizedPnL         float64          `json:"realized_pnl"`
+	TotalFunding        float64          `json:"total_funding"`
+	TotalCommission     float64          `json:"total_commission"`
+	NetPnL              float64          `json:"net_pnl"`
+	ExchangeErrors      map[string]int64 `json:"exchange_errors"`
+}
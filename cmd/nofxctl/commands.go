@@ -0,0 +1,592 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// loadConfig the saved profiles and the file they live in
+func (a *app) loadConfig() (*Config, string, error) {
+	path := a.configPath
+	if path == "" {
+		var err error
+		if path, err = configPath(); err != nil {
+			return nil, "", err
+		}
+	}
+	cfg, err := loadConfig(path)
+	return cfg, path, err
+}
+
+// client an API client for the selected profile's session
+func (a *app) client() (*Client, error) {
+	cfg, _, err := a.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	name, p, ok := cfg.profile(a.profileName)
+	if !ok || p.Token == "" {
+		return nil, fmt.Errorf("profile %q is not logged in, run nofxctl login", name)
+	}
+	server := p.Server
+	if a.server != "" {
+		server = a.server
+	}
+	return NewClient(server, p.Token), nil
+}
+
+// printRaw prints a response as indented JSON for --json
+func printRaw(w io.Writer, raw json.RawMessage) error {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (a *app) loginCommand() *cobra.Command {
+	var email, otp, recovery string
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in and save the session to the profile",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, path, err := a.loadConfig()
+			if err != nil {
+				return err
+			}
+			name, p, ok := cfg.profile(a.profileName)
+			if !ok {
+				p = &Profile{}
+			}
+			if a.server != "" {
+				p.Server = a.server
+			}
+			if p.Server == "" {
+				return fmt.Errorf("no server configured for profile %q, pass --server http://host:8080", name)
+			}
+
+			in := bufio.NewReader(cmd.InOrStdin())
+			if email == "" {
+				email = p.Email
+			}
+			if email == "" {
+				if email, err = prompt(cmd, in, "Email: "); err != nil {
+					return err
+				}
+			}
+			password, err := readSecret(cmd, in, "Password: ")
+			if err != nil {
+				return err
+			}
+
+			client := NewClient(p.Server, "")
+			var login struct {
+				UserID           string `json:"user_id"`
+				RequiresOTPSetup bool   `json:"requires_otp_setup"`
+			}
+			if err := client.do(cmd.Context(), http.MethodPost, "/login", map[string]string{"email": email, "password": password}, &login); err != nil {
+				return err
+			}
+			if login.RequiresOTPSetup {
+				return fmt.Errorf("two-factor authentication of %s is not set up yet, finish it in the web UI first", email)
+			}
+
+			if otp == "" && recovery == "" {
+				if otp, err = prompt(cmd, in, "Authenticator code: "); err != nil {
+					return err
+				}
+			}
+			var session struct {
+				Token                  string `json:"token"`
+				UserID                 string `json:"user_id"`
+				Email                  string `json:"email"`
+				RecoveryCodesRemaining *int   `json:"recovery_codes_remaining"`
+			}
+			verify := map[string]string{"user_id": login.UserID, "otp_code": otp, "recovery_code": recovery}
+			if err := client.do(cmd.Context(), http.MethodPost, "/verify-otp", verify, &session); err != nil {
+				return err
+			}
+
+			p.Email, p.UserID, p.Token = session.Email, session.UserID, session.Token
+			cfg.Profiles[name] = p
+			cfg.Current = name
+			if err := cfg.save(path); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Logged in to %s as %s (profile %s)\n", p.Server, p.Email, name)
+			if session.RecoveryCodesRemaining != nil {
+				fmt.Fprintf(cmd.OutOrStdout(), "Recovery code used, %d left\n", *session.RecoveryCodesRemaining)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&email, "email", "", "account email (default: the profile's)")
+	cmd.Flags().StringVar(&otp, "otp", "", "authenticator code (prompted when omitted)")
+	cmd.Flags().StringVar(&recovery, "recovery-code", "", "recovery code instead of an authenticator code")
+	return cmd
+}
+
+func (a *app) logoutCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logout",
+		Short: "Revoke the session and remove it from the profile",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, path, err := a.loadConfig()
+			if err != nil {
+				return err
+			}
+			name, p, ok := cfg.profile(a.profileName)
+			if !ok || p.Token == "" {
+				return fmt.Errorf("profile %q is not logged in", name)
+			}
+			if err := NewClient(p.Server, p.Token).do(cmd.Context(), http.MethodPost, "/logout", nil, nil); err != nil {
+				// Forget the token anyway, an expired session cannot be revoked
+				fmt.Fprintln(cmd.ErrOrStderr(), "Warning: server logout failed:", err)
+			}
+			p.Token = ""
+			if err := cfg.save(path); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Logged out of %s (profile %s)\n", p.Server, name)
+			return nil
+		},
+	}
+}
+
+func (a *app) profileCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "profile",
+		Aliases: []string{"profiles"},
+		Short:   "List saved profiles",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, _, err := a.loadConfig()
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "\tPROFILE\tSERVER\tEMAIL\tSESSION")
+			for _, name := range cfg.names() {
+				p := cfg.Profiles[name]
+				current, session := "", "logged out"
+				if name == cfg.Current {
+					current = "*"
+				}
+				if p.Token != "" {
+					session = "logged in"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", current, name, p.Server, p.Email, session)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "use <name>",
+		Short: "Switch the current profile",
+		Args:  cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			cfg, _, err := a.loadConfig()
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return cfg.names(), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, path, err := a.loadConfig()
+			if err != nil {
+				return err
+			}
+			if _, ok := cfg.Profiles[args[0]]; !ok {
+				return fmt.Errorf("no profile %q, create it with nofxctl login -p %s --server URL", args[0], args[0])
+			}
+			cfg.Current = args[0]
+			if err := cfg.save(path); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Using profile %s\n", args[0])
+			return nil
+		},
+	})
+	return cmd
+}
+
+func (a *app) tradersCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "traders",
+		Aliases: []string{"ls", "list"},
+		Short:   "List your traders",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			var raw json.RawMessage
+			if err := client.do(cmd.Context(), http.MethodGet, "/my-traders", nil, &raw); err != nil {
+				return err
+			}
+			if a.jsonOutput {
+				return printRaw(cmd.OutOrStdout(), raw)
+			}
+			var traders []Trader
+			if err := json.Unmarshal(raw, &traders); err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tNAME\tSTATUS\tMODE\tSTRATEGY\tAI MODEL")
+			for _, t := range traders {
+				status, mode := "stopped", "live"
+				if t.IsRunning {
+					status = "running"
+				}
+				if t.PaperMode {
+					mode = "paper"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", t.ID, t.Name, status, mode, t.StrategyName, t.AIModel)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func (a *app) startCommand() *cobra.Command {
+	return a.traderActionCommand("start", "Start a trader")
+}
+
+func (a *app) stopCommand() *cobra.Command {
+	return a.traderActionCommand("stop", "Stop a trader")
+}
+
+// traderActionCommand POST /api/traders/:id/<action>
+func (a *app) traderActionCommand(action, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:               action + " <trader>",
+		Short:             short + " (by ID, name or ID prefix)",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeTraders,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			t, err := resolveTrader(cmd.Context(), client, args[0])
+			if err != nil {
+				return err
+			}
+			var resp struct {
+				Message string `json:"message"`
+			}
+			if err := client.do(cmd.Context(), http.MethodPost, "/traders/"+url.PathEscape(t.ID)+"/"+action, nil, &resp); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", t.Name, resp.Message)
+			return nil
+		},
+	}
+}
+
+func (a *app) decisionsCommand() *cobra.Command {
+	var limit int
+	var follow bool
+	var interval time.Duration
+	cmd := &cobra.Command{
+		Use:               "decisions <trader>",
+		Aliases:           []string{"tail"},
+		Short:             "Show the latest decision cycles of a trader, -f to follow new ones",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeTraders,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			t, err := resolveTrader(cmd.Context(), client, args[0])
+			if err != nil {
+				return err
+			}
+			path := fmt.Sprintf("/decisions/latest?trader_id=%s&limit=%d", url.QueryEscape(t.ID), limit)
+
+			var lastID int64
+			for {
+				var raw json.RawMessage
+				if err := client.do(cmd.Context(), http.MethodGet, path, nil, &raw); err != nil {
+					if cmd.Context().Err() != nil {
+						return nil
+					}
+					return err
+				}
+				if a.jsonOutput && !follow {
+					return printRaw(cmd.OutOrStdout(), raw)
+				}
+				var records []DecisionRecord
+				if err := json.Unmarshal(raw, &records); err != nil {
+					return err
+				}
+				lastID = printNewDecisions(cmd.OutOrStdout(), records, lastID)
+				if !follow {
+					return nil
+				}
+
+				select {
+				case <-cmd.Context().Done():
+					return nil
+				case <-time.After(interval):
+				}
+			}
+		},
+	}
+	cmd.Flags().IntVarP(&limit, "limit", "n", 5, "cycles to show")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "keep polling and print new cycles")
+	cmd.Flags().DurationVar(&interval, "interval", 15*time.Second, "polling interval with --follow")
+	return cmd
+}
+
+// printNewDecisions prints the records newer than lastID oldest first, returns the newest ID printed
+func printNewDecisions(w io.Writer, records []DecisionRecord, lastID int64) int64 {
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	for _, r := range records {
+		if r.ID <= lastID {
+			continue
+		}
+		formatDecision(w, &r)
+		lastID = r.ID
+	}
+	return lastID
+}
+
+// formatDecision one cycle: a summary line, then one line per action
+func formatDecision(w io.Writer, r *DecisionRecord) {
+	status := "✓"
+	if !r.Success {
+		status = "✗"
+	}
+	fmt.Fprintf(w, "#%d  %s  %s  equity %.2f  available %.2f  unrealized %+.2f  positions %d\n",
+		r.CycleNumber, r.Timestamp.Local().Format("2006-01-02 15:04:05"), status,
+		r.AccountState.TotalBalance, r.AccountState.AvailableBalance, r.AccountState.TotalUnrealizedProfit, r.AccountState.PositionCount)
+	for _, d := range r.Decisions {
+		line := fmt.Sprintf("    %-12s %s", d.Action, d.Symbol)
+		if d.Quantity > 0 {
+			line += fmt.Sprintf("  %g @ %g", d.Quantity, d.Price)
+		}
+		if d.Leverage > 0 {
+			line += fmt.Sprintf("  %dx", d.Leverage)
+		}
+		if d.Confidence > 0 {
+			line += fmt.Sprintf("  (%d%%)", d.Confidence)
+		}
+		if d.Error != "" {
+			line += "  ✗ " + d.Error
+		}
+		fmt.Fprintln(w, line)
+	}
+	if r.ErrorMessage != "" {
+		fmt.Fprintf(w, "    error: %s\n", r.ErrorMessage)
+	}
+}
+
+func (a *app) closeCommand() *cobra.Command {
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "close <trader> <symbol> <long|short>",
+		Short: "Close a position at market",
+		Args:  cobra.ExactArgs(3),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return a.completeTraders(cmd, args, toComplete)
+			case 2:
+				return []string{"long", "short"}, cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			side := strings.ToUpper(args[2])
+			if side != "LONG" && side != "SHORT" {
+				return fmt.Errorf("side must be long or short, got %q", args[2])
+			}
+			symbol := strings.ToUpper(args[1])
+
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			t, err := resolveTrader(cmd.Context(), client, args[0])
+			if err != nil {
+				return err
+			}
+			if !yes {
+				answer, err := prompt(cmd, bufio.NewReader(cmd.InOrStdin()), fmt.Sprintf("Close %s %s of %s at market? [y/N] ", symbol, side, t.Name))
+				if err != nil {
+					return err
+				}
+				if a := strings.ToLower(answer); a != "y" && a != "yes" {
+					return fmt.Errorf("aborted")
+				}
+			}
+
+			var resp struct {
+				Message string `json:"message"`
+			}
+			body := map[string]string{"symbol": symbol, "side": side}
+			if err := client.do(cmd.Context(), http.MethodPost, "/traders/"+url.PathEscape(t.ID)+"/close-position", body, &resp); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", t.Name, resp.Message)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "do not ask for confirmation")
+	return cmd
+}
+
+func (a *app) statsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:               "stats <trader>",
+		Short:             "Show trading statistics of a trader",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: a.completeTraders,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := a.client()
+			if err != nil {
+				return err
+			}
+			t, err := resolveTrader(cmd.Context(), client, args[0])
+			if err != nil {
+				return err
+			}
+			var raw json.RawMessage
+			if err := client.do(cmd.Context(), http.MethodGet, "/statistics?trader_id="+url.QueryEscape(t.ID), nil, &raw); err != nil {
+				return err
+			}
+			if a.jsonOutput {
+				return printRaw(cmd.OutOrStdout(), raw)
+			}
+			var stats Statistics
+			if err := json.Unmarshal(raw, &stats); err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "Trader\t%s (%s)\n", t.Name, t.ID)
+			fmt.Fprintf(w, "Cycles\t%d (%d ok, %d failed)\n", stats.TotalCycles, stats.SuccessfulCycles, stats.FailedCycles)
+			fmt.Fprintf(w, "Positions\t%d opened, %d closed\n", stats.TotalOpenPositions, stats.TotalClosePositions)
+			fmt.Fprintf(w, "Realized PnL\t%+.2f\n", stats.RealizedPnL)
+			fmt.Fprintf(w, "Funding\t%+.2f\n", stats.TotalFunding)
+			fmt.Fprintf(w, "Commission\t%+.2f\n", stats.TotalCommission)
+			fmt.Fprintf(w, "Net PnL\t%+.2f\n", stats.NetPnL)
+			if len(stats.ExchangeErrors) > 0 {
+				classes := make([]string, 0, len(stats.ExchangeErrors))
+				for class := range stats.ExchangeErrors {
+					classes = append(classes, class)
+				}
+				sort.Strings(classes)
+				for i, class := range classes {
+					classes[i] = fmt.Sprintf("%s %d", class, stats.ExchangeErrors[class])
+				}
+				fmt.Fprintf(w, "Exchange errors\t%s\n", strings.Join(classes, ", "))
+			}
+			return w.Flush()
+		},
+	}
+}
+
+// resolveTrader finds a trader by exact ID, name (case-insensitive) or unique ID prefix
+func resolveTrader(ctx context.Context, client *Client, ref string) (*Trader, error) {
+	var traders []Trader
+	if err := client.do(ctx, http.MethodGet, "/my-traders", nil, &traders); err != nil {
+		return nil, err
+	}
+	return matchTrader(traders, ref)
+}
+
+func matchTrader(traders []Trader, ref string) (*Trader, error) {
+	var byName, byPrefix []*Trader
+	for i := range traders {
+		t := &traders[i]
+		if t.ID == ref {
+			return t, nil
+		}
+		if strings.EqualFold(t.Name, ref) {
+			byName = append(byName, t)
+		}
+		if strings.HasPrefix(t.ID, ref) {
+			byPrefix = append(byPrefix, t)
+		}
+	}
+	for _, matches := range [][]*Trader{byName, byPrefix} {
+		switch len(matches) {
+		case 0:
+			continue
+		case 1:
+			return matches[0], nil
+		default:
+			ids := make([]string, len(matches))
+			for i, t := range matches {
+				ids[i] = t.ID
+			}
+			return nil, fmt.Errorf("%q matches several traders (%s), use the ID", ref, strings.Join(ids, ", "))
+		}
+	}
+	return nil, fmt.Errorf("no trader %q, see nofxctl traders", ref)
+}
+
+// completeTraders shell completion of trader IDs, described by name
+func (a *app) completeTraders(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	client, err := a.client()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var traders []Trader
+	if err := client.do(cmd.Context(), http.MethodGet, "/my-traders", nil, &traders); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	completions := make([]string, 0, len(traders))
+	for _, t := range traders {
+		completions = append(completions, t.ID+"\t"+t.Name)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// prompt asks for one line of input
+func prompt(cmd *cobra.Command, in *bufio.Reader, label string) (string, error) {
+	fmt.Fprint(cmd.ErrOrStderr(), label)
+	line, err := in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// readSecret asks for a password without echoing it on a terminal, reads a plain line from piped input
+func readSecret(cmd *cobra.Command, in *bufio.Reader, label string) (string, error) {
+	if f, ok := cmd.InOrStdin().(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		fmt.Fprint(cmd.ErrOrStderr(), label)
+		secret, err := term.ReadPassword(int(f.Fd()))
+		fmt.Fprintln(cmd.ErrOrStderr())
+		if err != nil {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+		return string(secret), nil
+	}
+	return prompt(cmd, in, label)
+}
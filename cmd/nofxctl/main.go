@@ -0,0 +1,57 @@
+// nofxctl command line client of the NOFX REST API, for operators without the web UI
+// Usage: nofxctl login --server http://vps:8080 --email you@example.com, then nofxctl traders
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// app state shared by the commands, filled from the persistent flags
+type app struct {
+	profileName string
+	server      string
+	jsonOutput  bool
+	configPath  string
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := newRootCommand().ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	a := &app{}
+	root := &cobra.Command{
+		Use:           "nofxctl",
+		Short:         "Manage NOFX traders from the terminal",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.PersistentFlags().StringVarP(&a.profileName, "profile", "p", "", "saved profile to use (default: the current profile)")
+	root.PersistentFlags().StringVar(&a.server, "server", "", "server URL, overrides the profile's")
+	root.PersistentFlags().BoolVar(&a.jsonOutput, "json", false, "print raw JSON responses")
+
+	root.AddCommand(
+		a.loginCommand(),
+		a.logoutCommand(),
+		a.profileCommand(),
+		a.tradersCommand(),
+		a.startCommand(),
+		a.stopCommand(),
+		a.decisionsCommand(),
+		a.closeCommand(),
+		a.statsCommand(),
+	)
+	return root
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nofxctl", "config.json")
+	cfg, err := loadConfig(path)
+	if err != nil || cfg.Current != defaultProfile || len(cfg.Profiles) != 0 {
+		t.Fatalf("a missing file should be an empty config: %+v, %v", cfg, err)
+	}
+
+	cfg.Profiles["vps"] = &Profile{Server: "http://vps:8080", Email: "ops@example.com", Token: "jwt"}
+	cfg.Current = "vps"
+	if err := cfg.save(path); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("the config holds tokens and must be owner-only: %v, %v", info.Mode(), err)
+	}
+
+	loaded, err := loadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, p, ok := loaded.profile(""); !ok || name != "vps" || p.Token != "jwt" {
+		t.Errorf("current profile %s %+v", name, p)
+	}
+}
+
+func TestMatchTrader(t *testing.T) {
+	traders := []Trader{
+		{ID: "a1b2c3", Name: "BTC Trend"},
+		{ID: "a1f4e5", Name: "ETH Swing"},
+		{ID: "d7e8f9", Name: "btc trend"},
+	}
+	if tr, err := matchTrader(traders, "ETH swing"); err != nil || tr.ID != "a1f4e5" {
+		t.Errorf("by name: %+v, %v", tr, err)
+	}
+	if tr, err := matchTrader(traders, "d7"); err != nil || tr.ID != "d7e8f9" {
+		t.Errorf("by prefix: %+v, %v", tr, err)
+	}
+	if tr, err := matchTrader(traders, "a1b2c3"); err != nil || tr.Name != "BTC Trend" {
+		t.Errorf("by ID: %+v, %v", tr, err)
+	}
+	for _, ambiguous := range []string{"btc trend", "a1"} {
+		if _, err := matchTrader(traders, ambiguous); err == nil {
+			t.Errorf("%q should be ambiguous", ambiguous)
+		}
+	}
+	if _, err := matchTrader(traders, "zz"); err == nil {
+		t.Error("unknown trader should fail")
+	}
+}
+
+func TestPrintNewDecisions(t *testing.T) {
+	records := []DecisionRecord{
+		{ID: 12, CycleNumber: 3, Success: true, Decisions: []DecisionAction{{Action: "open_long", Symbol: "BTCUSDT", Quantity: 0.01, Price: 60000, Leverage: 5, Confidence: 80}}},
+		{ID: 11, CycleNumber: 2, ErrorMessage: "AI timeout"},
+		{ID: 10, CycleNumber: 1, Success: true},
+	}
+	var out bytes.Buffer
+	if last := printNewDecisions(&out, records, 10); last != 12 {
+		t.Errorf("newest printed ID %d", last)
+	}
+	text := out.String()
+	if strings.Contains(text, "#1 ") || strings.Index(text, "#2 ") > strings.Index(text, "#3 ") {
+		t.Errorf("should print cycles 2 then 3 only:\n%s", text)
+	}
+	if !strings.Contains(text, "open_long    BTCUSDT  0.01 @ 60000  5x  (80%)") || !strings.Contains(text, "error: AI timeout") {
+		t.Errorf("unexpected output:\n%s", text)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer jwt" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"Invalid token"}`))
+			return
+		}
+		w.Write([]byte(`[{"trader_id":"t1","trader_name":"Alpha","is_running":true}]`))
+	}))
+	defer srv.Close()
+
+	var traders []Trader
+	if err := NewClient(srv.URL+"/", "jwt").do(context.Background(), http.MethodGet, "/my-traders", nil, &traders); err != nil || len(traders) != 1 || !traders[0].IsRunning {
+		t.Fatalf("traders %+v, %v", traders, err)
+	}
+
+	err := NewClient(srv.URL, "expired").do(context.Background(), http.MethodGet, "/my-traders", nil, &traders)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnauthorized || !strings.Contains(err.Error(), "nofxctl login") {
+		t.Errorf("expected a 401 API error pointing to login, got %v", err)
+	}
+}
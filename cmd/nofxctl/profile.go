@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// defaultProfile profile used until another one is selected
+const defaultProfile = "default"
+
+// Profile a NOFX server and the session logged in to it
+type Profile struct {
+	Server string `json:"server"`
+	Email  string `json:"email,omitempty"`
+	UserID string `json:"user_id,omitempty"`
+	Token  string `json:"token,omitempty"`
+}
+
+// Config saved profiles, the file holds session tokens and is only readable by its owner
+type Config struct {
+	Current  string              `json:"current"`
+	Profiles map[string]*Profile `json:"profiles"`
+}
+
+// configPath $NOFXCTL_CONFIG, or nofxctl/config.json in the user config directory
+func configPath() (string, error) {
+	if path := os.Getenv("NOFXCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "nofxctl", "config.json"), nil
+}
+
+// loadConfig reads the saved profiles, a missing file is an empty config
+func loadConfig(path string) (*Config, error) {
+	cfg := &Config{Current: defaultProfile, Profiles: make(map[string]*Profile)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]*Profile)
+	}
+	if cfg.Current == "" {
+		cfg.Current = defaultProfile
+	}
+	return cfg, nil
+}
+
+// save writes the config atomically with owner-only permissions
+func (c *Config) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// profile the named profile, the current one when name is empty
+func (c *Config) profile(name string) (string, *Profile, bool) {
+	if name == "" {
+		name = c.Current
+	}
+	p, ok := c.Profiles[name]
+	return name, p, ok
+}
+
+func (c *Config) names() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// handlerDepth how deep calls passing the gin.Context are followed (error helpers, shared loaders)
+const handlerDepth = 3
+
+// docRoutePrefix "METHOD /path:" at the start of a handler comment, the spec already says it
+var docRoutePrefix = regexp.MustCompile(`^(GET|POST|PUT|DELETE|PATCH|ANY)\s+\S+\s*:?\s*`)
+
+// streamContentTypes Content-Type headers of handlers writing the response body themselves
+var streamContentTypes = map[string]string{
+	"text/event-stream":    "Server-sent event stream",
+	"application/x-ndjson": "Newline-delimited JSON stream",
+}
+
+type generator struct {
+	pkg        *loadedPackage
+	decls      map[*types.Func]*ast.FuncDecl
+	components map[string]any
+}
+
+func newGenerator(pkg *loadedPackage) *generator {
+	g := &generator{pkg: pkg, decls: make(map[*types.Func]*ast.FuncDecl), components: make(map[string]any)}
+	for _, f := range pkg.files {
+		for _, d := range f.Decls {
+			if fd, ok := d.(*ast.FuncDecl); ok {
+				if fn, ok := pkg.info.Defs[fd.Name].(*types.Func); ok {
+					g.decls[fn] = fd
+				}
+			}
+		}
+	}
+	return g
+}
+
+// handlerInfo what a handler reads and writes
+type handlerInfo struct {
+	query     []map[string]any
+	body      types.Type
+	responses map[string]any
+}
+
+func (g *generator) generate() (map[string]any, error) {
+	routes := g.collectRoutes()
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("no routes found in Server.setupRoutes")
+	}
+
+	paths := make(map[string]map[string]any)
+	operationIDs := make(map[string]int)
+	tags := make(map[string]bool)
+	for _, r := range routes {
+		if r.handler == nil {
+			return nil, fmt.Errorf("%s: handler not found", r.path)
+		}
+		path, params := openAPIPath(r.path)
+		if paths[path] == nil {
+			paths[path] = make(map[string]any)
+		}
+		for _, method := range r.methods {
+			op := g.operation(r, params)
+			id := operationID(r.handler.Name.Name)
+			if operationIDs[id]++; operationIDs[id] > 1 {
+				id += strconv.Itoa(operationIDs[id])
+			}
+			op["operationId"] = id
+			tags[op["tags"].([]string)[0]] = true
+			paths[path][method] = op
+		}
+	}
+
+	g.components["Error"] = errorSchema()
+	tagList := make([]map[string]any, 0, len(tags))
+	for _, name := range sortedKeys(tags) {
+		tagList = append(tagList, map[string]any{"name": name})
+	}
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "NOFX API",
+			"version":     "1.0.0",
+			"description": "REST API of the NOFX trading server. Generated from the api package by cmd/openapi-gen, do not edit by hand.",
+		},
+		"servers": []map[string]any{{"url": "/"}},
+		"tags":    tagList,
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas":         g.components,
+			"securitySchemes": g.securitySchemes(),
+		},
+	}, nil
+}
+
+// securitySchemes the JWT from /api/login, sent as a bearer token or in the auth cookie
+func (g *generator) securitySchemes() map[string]any {
+	cookie := "nofx_token"
+	if c, ok := g.pkg.types.Scope().Lookup("authCookieName").(*types.Const); ok {
+		cookie = constant.StringVal(c.Val())
+	}
+	return map[string]any{
+		"bearerAuth": map[string]any{
+			"type":         "http",
+			"scheme":       "bearer",
+			"bearerFormat": "JWT",
+			"description":  "Token returned by /api/login or /api/verify-otp: `Authorization: Bearer <token>`",
+		},
+		"cookieAuth": map[string]any{
+			"type":        "apiKey",
+			"in":          "cookie",
+			"name":        cookie,
+			"description": "The same JWT in an HttpOnly cookie, set at login when cookie auth is enabled",
+		},
+	}
+}
+
+func (g *generator) operation(r route, pathParams []string) map[string]any {
+	info := &handlerInfo{responses: make(map[string]any)}
+	g.inspectHandler(r.handler, info, make(map[*ast.FuncDecl]bool), 0)
+
+	summary, description := g.handlerDoc(r.handler)
+	op := map[string]any{
+		"summary": summary,
+		"tags":    []string{routeTag(r.path)},
+	}
+
+	var params []map[string]any
+	for _, name := range pathParams {
+		params = append(params, map[string]any{"name": name, "in": "path", "required": true, "schema": map[string]any{"type": "string"}})
+	}
+	params = append(params, info.query...)
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+	if info.body != nil {
+		op["requestBody"] = map[string]any{
+			"required": true,
+			"content":  map[string]any{"application/json": map[string]any{"schema": g.schemaFor(info.body)}},
+		}
+	}
+
+	switch r.access {
+	case accessOptional:
+		op["security"] = []map[string][]string{{}, {"bearerAuth": {}}, {"cookieAuth": {}}}
+		description = joinText(description, "Authentication is optional, owners also see their own private data.")
+	case accessUser, accessAdmin:
+		op["security"] = []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}}
+		addResponse(info.responses, http.StatusUnauthorized, errorSchema())
+		if r.access == accessAdmin {
+			description = joinText(description, "Operator only: the account email must be listed in ADMIN_EMAILS.")
+			addResponse(info.responses, http.StatusForbidden, errorSchema())
+		}
+	case accessShare:
+		op["security"] = []map[string][]string{}
+		description = joinText(description, "Authorized by the signed share link token in the path, invalid or revoked links answer 404.")
+		addResponse(info.responses, http.StatusNotFound, errorSchema())
+	default:
+		op["security"] = []map[string][]string{}
+	}
+	if description != "" {
+		op["description"] = description
+	}
+	if len(info.responses) == 0 {
+		info.responses["200"] = map[string]any{"description": "OK"}
+	}
+	op["responses"] = info.responses
+	return op
+}
+
+// inspectHandler collects query parameters, the JSON body and the JSON responses of a handler,
+// following calls to package functions that receive its gin.Context
+func (g *generator) inspectHandler(decl *ast.FuncDecl, info *handlerInfo, visited map[*ast.FuncDecl]bool, depth int) {
+	if decl == nil || decl.Body == nil || visited[decl] || depth > handlerDepth {
+		return
+	}
+	visited[decl] = true
+
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if ok && isGinContext(g.pkg.info.TypeOf(sel.X)) {
+			g.contextCall(sel.Sel.Name, call.Args, info)
+			return true
+		}
+		for _, arg := range call.Args {
+			if isGinContext(g.pkg.info.TypeOf(arg)) {
+				g.inspectHandler(g.funcDecl(call.Fun), info, visited, depth+1)
+				break
+			}
+		}
+		return true
+	})
+}
+
+// contextCall records what a gin.Context method call reads or writes
+func (g *generator) contextCall(name string, args []ast.Expr, info *handlerInfo) {
+	switch name {
+	case "Query", "DefaultQuery", "GetQuery", "QueryArray", "GetQueryArray":
+		param := g.constString(args[0])
+		if param == "" {
+			return
+		}
+		for _, q := range info.query {
+			if q["name"] == param {
+				return
+			}
+		}
+		schema := map[string]any{"type": "string"}
+		if strings.HasSuffix(name, "Array") {
+			schema = map[string]any{"type": "array", "items": schema}
+		}
+		if name == "DefaultQuery" && len(args) > 1 {
+			if def := g.constString(args[1]); def != "" {
+				schema["default"] = def
+			}
+		}
+		info.query = append(info.query, map[string]any{"name": param, "in": "query", "schema": schema})
+
+	case "ShouldBindJSON", "BindJSON", "ShouldBind":
+		if info.body != nil || len(args) == 0 {
+			return
+		}
+		target := g.pkg.info.TypeOf(args[0])
+		if ptr, ok := target.(*types.Pointer); ok {
+			target = ptr.Elem()
+		}
+		info.body = target
+
+	case "JSON", "IndentedJSON", "PureJSON", "AbortWithStatusJSON":
+		if len(args) != 2 {
+			return
+		}
+		schema := g.schemaForExpr(args[1])
+		tv := g.pkg.info.Types[args[0]]
+		if tv.Value == nil {
+			// Status computed at runtime, e.g. 200 or 503 from a readiness check
+			if _, ok := info.responses["default"]; !ok {
+				info.responses["default"] = jsonResponse("Response", schema)
+			}
+			return
+		}
+		code, _ := constant.Int64Val(tv.Value)
+		addResponse(info.responses, int(code), schema)
+
+	case "Header":
+		if len(args) == 2 && g.constString(args[0]) == "Content-Type" {
+			contentType := g.constString(args[1])
+			if description, ok := streamContentTypes[contentType]; ok {
+				info.responses["200"] = map[string]any{
+					"description": description,
+					"content":     map[string]any{contentType: map[string]any{"schema": map[string]any{"type": "string"}}},
+				}
+			}
+		}
+
+	case "Data":
+		if tv := g.pkg.info.Types[args[0]]; len(args) == 3 && tv.Value != nil {
+			if code, ok := constant.Int64Val(tv.Value); ok {
+				contentType := g.constString(args[1])
+				if contentType == "" {
+					contentType = "application/octet-stream"
+				}
+				key := strconv.FormatInt(code, 10)
+				if _, exists := info.responses[key]; !exists {
+					info.responses[key] = map[string]any{
+						"description": http.StatusText(int(code)),
+						"content":     map[string]any{contentType: map[string]any{"schema": map[string]any{"type": "string"}}},
+					}
+				}
+			}
+		}
+	}
+}
+
+// handlerDoc the summary (first line) and description (whole text, when longer) of a handler's doc comment
+func (g *generator) handlerDoc(decl *ast.FuncDecl) (string, string) {
+	name := decl.Name.Name
+	fallback := splitCamel(strings.TrimPrefix(strings.TrimPrefix(name, "handle"), "Handle"))
+	if decl.Doc == nil {
+		return fallback, ""
+	}
+	lines := strings.Split(strings.TrimSpace(decl.Doc.Text()), "\n")
+	lines[0] = capitalize(docRoutePrefix.ReplaceAllString(strings.TrimSpace(strings.TrimPrefix(lines[0], name)), ""))
+	lines[0] = strings.TrimRight(lines[0], ",;:")
+	if lines[0] == "" {
+		lines[0] = fallback
+	}
+	if len(lines) == 1 {
+		return lines[0], ""
+	}
+	return lines[0], strings.Join(strings.Fields(strings.Join(lines, " ")), " ")
+}
+
+// routeTag groups operations by the first path segment after /api
+func routeTag(path string) string {
+	rest, ok := strings.CutPrefix(path, "/api/")
+	if !ok {
+		return "health"
+	}
+	segment, _, _ := strings.Cut(rest, "/")
+	if segment == "" || strings.HasPrefix(segment, ":") {
+		return "system"
+	}
+	return segment
+}
+
+// operationID handleGetTraders -> getTraders
+func operationID(handler string) string {
+	id := strings.TrimPrefix(strings.TrimPrefix(handler, "handle"), "Handle")
+	if id == "" {
+		return handler
+	}
+	return strings.ToLower(id[:1]) + id[1:]
+}
+
+func addResponse(responses map[string]any, code int, schema map[string]any) {
+	key := strconv.Itoa(code)
+	if _, ok := responses[key]; ok {
+		return
+	}
+	responses[key] = jsonResponse(http.StatusText(code), schema)
+}
+
+// jsonResponse a JSON response, {"error": "..."} bodies referencing the shared Error schema
+func jsonResponse(description string, schema map[string]any) map[string]any {
+	if reflect.DeepEqual(schema, errorSchema()) {
+		schema = map[string]any{"$ref": "#/components/schemas/Error"}
+	}
+	return map[string]any{
+		"description": description,
+		"content":     map[string]any{"application/json": map[string]any{"schema": schema}},
+	}
+}
+
+func errorSchema() map[string]any {
+	return map[string]any{"type": "object", "properties": map[string]any{"error": map[string]any{"type": "string"}}}
+}
+
+func isGinContext(t types.Type) bool {
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "github.com/gin-gonic/gin" && named.Obj().Name() == "Context"
+}
+
+func joinText(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + "\n\n" + b
+}
+
+func capitalize(s string) string {
+	for i, r := range s {
+		return string(unicode.ToUpper(r)) + s[i+len(string(r)):]
+	}
+	return s
+}
+
+// splitCamel GetTraders -> Get traders
+func splitCamel(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte(' ')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,116 @@
+// Command openapi-gen generates the OpenAPI 3 specification of the REST API from the api package.
+//
+// Routes and their authentication come from Server.setupRoutes, request bodies from ShouldBindJSON
+// targets, query parameters from c.Query calls and responses from c.JSON calls, typed with go/types
+// so the schemas follow the Go structs and their json tags. Run through go generate in the api package:
+//
+//	go generate ./api
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+func main() {
+	dir := flag.String("pkg", ".", "directory of the api package")
+	out := flag.String("out", "openapi.json", "output file")
+	flag.Parse()
+
+	pkg, err := loadPackage(*dir)
+	if err != nil {
+		log.Fatalf("load %s: %v", *dir, err)
+	}
+	spec, err := newGenerator(pkg).generate()
+	if err != nil {
+		log.Fatalf("generate: %v", err)
+	}
+	data, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		log.Fatalf("encode: %v", err)
+	}
+	if err := os.WriteFile(*out, append(data, '\n'), 0644); err != nil {
+		log.Fatalf("write %s: %v", *out, err)
+	}
+}
+
+// listedPackage the fields of `go list -json` used here
+type listedPackage struct {
+	ImportPath string
+	Dir        string
+	Export     string
+	GoFiles    []string
+}
+
+// loadedPackage a parsed and type-checked package
+type loadedPackage struct {
+	fset  *token.FileSet
+	files []*ast.File
+	types *types.Package
+	info  *types.Info
+}
+
+// loadPackage parses the package in dir and type-checks it against the export data of its
+// dependencies, as built by `go list -export` (works offline from the build cache)
+func loadPackage(dir string) (*loadedPackage, error) {
+	cmd := exec.Command("go", "list", "-export", "-deps", "-json", ".")
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list: %w", err)
+	}
+
+	// Dependencies are listed first, the package itself last
+	exports := make(map[string]string)
+	var target listedPackage
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for dec.More() {
+		var p listedPackage
+		if err := dec.Decode(&p); err != nil {
+			return nil, fmt.Errorf("decode go list output: %w", err)
+		}
+		exports[p.ImportPath] = p.Export
+		target = p
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range target.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(target.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+		export := exports[path]
+		if export == "" {
+			return nil, fmt.Errorf("no export data for %s", path)
+		}
+		return os.Open(export)
+	})}
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	pkg, err := conf.Check(target.ImportPath, fset, files, info)
+	if err != nil {
+		return nil, fmt.Errorf("type-check: %w", err)
+	}
+	return &loadedPackage{fset: fset, files: files, types: pkg, info: info}, nil
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"path"
+	"strings"
+)
+
+// Access levels of a route, from the middleware of its group
+const (
+	accessPublic   = "public"
+	accessOptional = "optional"
+	accessUser     = "user"
+	accessAdmin    = "admin"
+	accessShare    = "share"
+)
+
+// middlewareAccess the access level each middleware constructor enforces
+var middlewareAccess = map[string]string{
+	"optionalAuthMiddleware": accessOptional,
+	"authMiddleware":         accessUser,
+	"adminMiddleware":        accessAdmin,
+	"shareLinkMiddleware":    accessShare,
+}
+
+// routeMethods gin.IRoutes registration methods, Any is documented as GET and POST
+var routeMethods = map[string][]string{
+	"GET":    {"get"},
+	"POST":   {"post"},
+	"PUT":    {"put"},
+	"DELETE": {"delete"},
+	"PATCH":  {"patch"},
+	"Any":    {"get", "post"},
+}
+
+// routeGroup a gin router group: path prefix and access level
+type routeGroup struct {
+	prefix string
+	access string
+}
+
+// route one registered endpoint
+type route struct {
+	methods []string
+	path    string
+	access  string
+	handler *ast.FuncDecl
+}
+
+// collectRoutes walks Server.setupRoutes, following the Group calls and the route helpers it calls
+func (g *generator) collectRoutes() []route {
+	setup := g.method("Server", "setupRoutes")
+	if setup == nil {
+		return nil
+	}
+	var routes []route
+	g.walkRoutes(setup.Body, make(map[types.Object]routeGroup), &routes)
+	return routes
+}
+
+func (g *generator) walkRoutes(body *ast.BlockStmt, groups map[types.Object]routeGroup, routes *[]route) {
+	// groupOf the group an expression refers to: s.router or a group variable
+	groupOf := func(expr ast.Expr) (routeGroup, bool) {
+		switch e := expr.(type) {
+		case *ast.SelectorExpr:
+			if e.Sel.Name == "router" {
+				return routeGroup{access: accessPublic}, true
+			}
+		case *ast.Ident:
+			group, ok := groups[g.pkg.info.Uses[e]]
+			return group, ok
+		}
+		return routeGroup{}, false
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for i, rhs := range n.Rhs {
+				call, ok := rhs.(*ast.CallExpr)
+				if !ok || i >= len(n.Lhs) {
+					continue
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok || sel.Sel.Name != "Group" || len(call.Args) == 0 {
+					continue
+				}
+				parent, ok := groupOf(sel.X)
+				if !ok {
+					continue
+				}
+				child := routeGroup{prefix: joinPath(parent.prefix, g.constString(call.Args[0])), access: parent.access}
+				for _, mw := range call.Args[1:] {
+					child.access = g.applyMiddleware(child.access, mw)
+				}
+				if id, ok := n.Lhs[i].(*ast.Ident); ok {
+					obj := g.pkg.info.Defs[id]
+					if obj == nil {
+						obj = g.pkg.info.Uses[id]
+					}
+					groups[obj] = child
+				}
+			}
+			return false
+
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if methods, ok := routeMethods[sel.Sel.Name]; ok && len(n.Args) >= 2 {
+				if group, ok := groupOf(sel.X); ok {
+					access := group.access
+					for _, mw := range n.Args[1 : len(n.Args)-1] {
+						access = g.applyMiddleware(access, mw)
+					}
+					*routes = append(*routes, route{
+						methods: methods,
+						path:    joinPath(group.prefix, g.constString(n.Args[0])),
+						access:  access,
+						handler: g.funcDecl(n.Args[len(n.Args)-1]),
+					})
+					return false
+				}
+			}
+			// A helper receiving groups, e.g. s.registerBacktestRoutes(backtest)
+			if decl := g.funcDecl(sel); decl != nil && decl.Body != nil {
+				bound := make(map[types.Object]routeGroup)
+				params := paramObjects(g.pkg.info, decl)
+				for i, arg := range n.Args {
+					if group, ok := groupOf(arg); ok && i < len(params) {
+						bound[params[i]] = group
+					}
+				}
+				if len(bound) > 0 {
+					g.walkRoutes(decl.Body, bound, routes)
+					return false
+				}
+			}
+		}
+		return true
+	})
+}
+
+// applyMiddleware the access level after a middleware, admin implies an authenticated user
+func (g *generator) applyMiddleware(access string, mw ast.Expr) string {
+	call, ok := mw.(*ast.CallExpr)
+	if !ok {
+		return access
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return access
+	}
+	level, ok := middlewareAccess[sel.Sel.Name]
+	if !ok || (access == accessAdmin && level == accessUser) {
+		return access
+	}
+	return level
+}
+
+// method the declaration of a method of a type in the package
+func (g *generator) method(recv, name string) *ast.FuncDecl {
+	for obj, decl := range g.decls {
+		if obj.Name() != name {
+			continue
+		}
+		sig := obj.Type().(*types.Signature)
+		if sig.Recv() != nil && strings.TrimPrefix(types.TypeString(sig.Recv().Type(), relativeTo(g.pkg.types)), "*") == recv {
+			return decl
+		}
+	}
+	return nil
+}
+
+// funcDecl the declaration of the function or method an expression refers to, nil outside the package
+func (g *generator) funcDecl(expr ast.Expr) *ast.FuncDecl {
+	var id *ast.Ident
+	switch e := expr.(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	default:
+		return nil
+	}
+	fn, ok := g.pkg.info.Uses[id].(*types.Func)
+	if !ok {
+		return nil
+	}
+	return g.decls[fn]
+}
+
+func paramObjects(info *types.Info, decl *ast.FuncDecl) []types.Object {
+	var params []types.Object
+	for _, field := range decl.Type.Params.List {
+		for _, name := range field.Names {
+			params = append(params, info.Defs[name])
+		}
+	}
+	return params
+}
+
+func relativeTo(pkg *types.Package) types.Qualifier {
+	return func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		return other.Name()
+	}
+}
+
+// constString the value of a constant string expression, "" when it isn't one
+func (g *generator) constString(expr ast.Expr) string {
+	tv, ok := g.pkg.info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return ""
+	}
+	return constant.StringVal(tv.Value)
+}
+
+// joinPath joins gin route paths the way RouterGroup does
+func joinPath(prefix, relative string) string {
+	if relative == "" {
+		return prefix
+	}
+	joined := path.Join(prefix, relative)
+	if strings.HasSuffix(relative, "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+	return joined
+}
+
+// openAPIPath converts gin parameters (:id, *path) to OpenAPI templates and returns their names
+func openAPIPath(ginPath string) (string, []string) {
+	var params []string
+	segments := strings.Split(ginPath, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			params = append(params, seg[1:])
+			segments[i] = "{" + seg[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strings"
+)
+
+// schemaForExpr the schema of a response value, expanding gin.H literals key by key
+func (g *generator) schemaForExpr(expr ast.Expr) map[string]any {
+	if lit, ok := expr.(*ast.CompositeLit); ok && isGinH(g.pkg.info.TypeOf(lit)) {
+		properties := make(map[string]any)
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			if key := g.constString(kv.Key); key != "" {
+				properties[key] = g.schemaForExpr(kv.Value)
+			}
+		}
+		return map[string]any{"type": "object", "properties": properties}
+	}
+	if t := g.pkg.info.TypeOf(expr); t != nil {
+		return g.schemaFor(t)
+	}
+	return map[string]any{}
+}
+
+// schemaFor the JSON schema of a Go type as encoding/json marshals it. Named structs become
+// components referenced by "<package>.<Type>".
+func (g *generator) schemaFor(t types.Type) map[string]any {
+	switch t := t.(type) {
+	case *types.Named:
+		obj := t.Obj()
+		if obj.Pkg() != nil {
+			switch obj.Pkg().Path() + "." + obj.Name() {
+			case "time.Time":
+				return map[string]any{"type": "string", "format": "date-time"}
+			case "time.Duration":
+				return map[string]any{"type": "integer", "description": "nanoseconds"}
+			case "encoding/json.RawMessage":
+				return map[string]any{}
+			case "github.com/gin-gonic/gin.H":
+				return map[string]any{"type": "object"}
+			}
+		}
+		if hasMethod(t, "MarshalJSON") {
+			return map[string]any{}
+		}
+		st, ok := t.Underlying().(*types.Struct)
+		if !ok || obj.Pkg() == nil {
+			return g.schemaFor(t.Underlying())
+		}
+		name := obj.Pkg().Name() + "." + obj.Name()
+		if _, ok := g.components[name]; !ok {
+			g.components[name] = map[string]any{} // Placeholder for recursive types
+			g.components[name] = g.structSchema(st)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+
+	case *types.Alias:
+		return g.schemaFor(types.Unalias(t))
+
+	case *types.Pointer:
+		return g.schemaFor(t.Elem())
+
+	case *types.Basic:
+		switch {
+		case t.Info()&types.IsBoolean != 0:
+			return map[string]any{"type": "boolean"}
+		case t.Info()&types.IsInteger != 0:
+			if t.Kind() == types.Int64 || t.Kind() == types.Uint64 {
+				return map[string]any{"type": "integer", "format": "int64"}
+			}
+			return map[string]any{"type": "integer"}
+		case t.Info()&types.IsFloat != 0:
+			return map[string]any{"type": "number"}
+		case t.Info()&types.IsString != 0:
+			return map[string]any{"type": "string"}
+		}
+		return map[string]any{}
+
+	case *types.Slice:
+		if b, ok := t.Elem().(*types.Basic); ok && b.Kind() == types.Byte {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": g.schemaFor(t.Elem())}
+
+	case *types.Array:
+		return map[string]any{"type": "array", "items": g.schemaFor(t.Elem())}
+
+	case *types.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+
+	case *types.Struct:
+		return g.structSchema(t)
+	}
+	// Interfaces: any JSON value
+	return map[string]any{}
+}
+
+// structSchema the properties of a struct, honoring json tags and flattening embedded structs.
+// Fields tagged binding:"required" are required.
+func (g *generator) structSchema(st *types.Struct) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+	g.addFields(st, properties, &required)
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (g *generator) addFields(st *types.Struct, properties map[string]any, required *[]string) {
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+		name, opts, _ := strings.Cut(tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if field.Embedded() && name == "" {
+			ft := field.Type()
+			if ptr, ok := ft.(*types.Pointer); ok {
+				ft = ptr.Elem()
+			}
+			if embedded, ok := ft.Underlying().(*types.Struct); ok {
+				g.addFields(embedded, properties, required)
+				continue
+			}
+		}
+		if !field.Exported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name()
+		}
+		schema := g.schemaFor(field.Type())
+		if strings.Contains(","+opts+",", ",string,") {
+			schema = map[string]any{"type": "string"}
+		}
+		properties[name] = schema
+		if strings.Contains(tag.Get("binding"), "required") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func isGinH(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "github.com/gin-gonic/gin" && named.Obj().Name() == "H"
+}
+
+func hasMethod(t types.Type, name string) bool {
+	for _, typ := range []types.Type{t, types.NewPointer(t)} {
+		set := types.NewMethodSet(typ)
+		for i := 0; i < set.Len(); i++ {
+			if set.At(i).Obj().Name() == name {
+				return true
+			}
+		}
+	}
+	return false
+}
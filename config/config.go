@@ -18,7 +18,8 @@ type Config struct {
 	APIServerPort       int
 	JWTSecret           string
 	RegistrationEnabled bool
-	MaxUsers            int // Maximum number of users allowed (0 = unlimited, default = 10)
+	MaxUsers            int      // Maximum number of users allowed (0 = unlimited, default = 10)
+	AdminEmails         []string // Emails allowed to call /api/admin endpoints (ADMIN_EMAILS, comma-separated)
 
 	// Database configuration
 	DBType     string // sqlite or postgres
@@ -29,17 +30,127 @@ type Config struct {
 	DBPassword string // PostgreSQL password
 	DBName     string // PostgreSQL database name
 	DBSSLMode  string // PostgreSQL SSL mode
+	DBURL      string // PostgreSQL connection string (DATABASE_URL), overrides the DB_HOST..DB_SSLMODE fields
+
+	// SQLite tuning (empty / 0 = store defaults)
+	DBSQLiteJournalMode   string // DB_SQLITE_JOURNAL_MODE, WAL (default) or DELETE for mounts without shared memory
+	DBSQLiteSynchronous   string // DB_SQLITE_SYNCHRONOUS, NORMAL (default with WAL) or FULL
+	DBSQLiteBusyTimeoutMs int    // DB_SQLITE_BUSY_TIMEOUT_MS, lock wait before "database is locked" (default 5000)
+	DBSQLiteMaxOpenConns  int    // DB_SQLITE_MAX_OPEN_CONNS, 1 (default) = single writer connection
 
 	// Security configuration
 	// TransportEncryption enables browser-side encryption for API keys
 	// Requires HTTPS or localhost. Set to false for HTTP access via IP.
 	TransportEncryption bool
 
+	// SecretsBackend selects where encryption keys are loaded from
+	// env (default), file, vault or aws-kms
+	SecretsBackend string
+
+	// CORS and browser security headers
+	CORSAllowedOrigins   []string // CORS_ALLOWED_ORIGINS, comma-separated, empty = any origin (no credentials)
+	CORSAllowCredentials bool     // CORS_ALLOW_CREDENTIALS, only honored for explicitly allowed origins
+	SecurityHeaders      bool     // SECURITY_HEADERS, send X-Frame-Options, nosniff, Referrer-Policy and HSTS
+	HSTSMaxAgeSeconds    int      // HSTS_MAX_AGE_SECONDS, sent on HTTPS requests only, 0 = no HSTS
+	TrustedProxies       []string // TRUSTED_PROXIES, comma-separated CIDRs whose X-Forwarded-For is honored, empty = none
+
+	// StrategyBundleTrustedSigners bundle signers shown as verified besides this installation
+	// (STRATEGY_BUNDLE_TRUSTED_SIGNERS, comma-separated base64 public keys or fingerprints)
+	StrategyBundleTrustedSigners []string
+
+	// AuthCookie also issues the JWT as an HttpOnly SameSite=Strict cookie (AUTH_COOKIE)
+	// and accepts it when no bearer token is sent
+	AuthCookie bool
+
 	// Experience improvement (anonymous usage statistics)
 	// Helps us understand product usage and improve the experience
 	// Set EXPERIENCE_IMPROVEMENT=false to disable
 	ExperienceImprovement bool
 
+	// Global circuit breaker defaults (strategy risk control overrides them)
+	CircuitBreakerMaxDrawdownPct       float64 // CIRCUIT_BREAKER_MAX_DRAWDOWN_PCT, 0 = disabled
+	CircuitBreakerMaxConsecutiveLosses int     // CIRCUIT_BREAKER_MAX_CONSECUTIVE_LOSSES, 0 = disabled
+	CircuitBreakerCooldownMinutes      int     // CIRCUIT_BREAKER_COOLDOWN_MINUTES
+
+	// Equity snapshot retention (compaction job downsamples old snapshots)
+	EquityMinuteRetentionDays       int // EQUITY_MINUTE_RETENTION_DAYS, keep 1 snapshot/min this long, then 1/hour
+	EquityMaxRetentionDays          int // EQUITY_MAX_RETENTION_DAYS, delete older snapshots, 0 = keep hourly history forever
+	EquityCompactionIntervalMinutes int // EQUITY_COMPACTION_INTERVAL_MINUTES, 0 = disabled
+	EquityWriteBatchSize            int // EQUITY_WRITE_BATCH_SIZE, snapshots inserted per batch
+	EquityWriteFlushMs              int // EQUITY_WRITE_FLUSH_MS, max delay before queued snapshots are inserted, 0 = insert each directly
+	EquityWriteQueueSize            int // EQUITY_WRITE_QUEUE_SIZE, queued snapshots before saving traders wait for the writer
+
+	// Market regime detection (scales directional leverage/position caps per symbol)
+	RegimeRefreshMinutes int // REGIME_REFRESH_MINUTES, 0 = disabled
+
+	// Contract status tracking (delistings are excluded from candidates, positions closed before delisting)
+	SymbolStatusRefreshMinutes int // SYMBOL_STATUS_REFRESH_MINUTES, 0 = disabled
+	DelistCloseHours           int // DELIST_CLOSE_HOURS, close positions this long before a scheduled delisting
+
+	// News & economic calendar context (injected into AI prompts of strategies that enable it)
+	NewsRefreshMinutes        int      // NEWS_REFRESH_MINUTES, 0 = disabled
+	NewsFeedURLs              []string // NEWS_FEED_URLS, comma-separated RSS/Atom feeds
+	EconomicCalendarURL       string   // ECONOMIC_CALENDAR_URL, ForexFactory-format JSON calendar
+	EconomicCalendarCountries []string // ECONOMIC_CALENDAR_COUNTRIES, comma-separated, empty = all
+
+	// Market sentiment (Fear & Greed index, social scores), stored as time series for charting
+	SentimentRefreshMinutes int      // SENTIMENT_REFRESH_MINUTES, 0 = disabled
+	SentimentRetentionDays  int      // SENTIMENT_RETENTION_DAYS, 0 = keep forever
+	FearGreedURL            string   // FEAR_GREED_URL, empty = alternative.me, "off" = disabled
+	SocialSentimentURL      string   // SOCIAL_SENTIMENT_URL, JSON scores endpoint (empty = disabled)
+	SocialSentimentAPIKey   string   // SOCIAL_SENTIMENT_API_KEY, sent as a bearer token
+	SocialSentimentSymbols  []string // SOCIAL_SENTIMENT_SYMBOLS, comma-separated base assets
+
+	// Per-trader log capture for the live log API
+	TraderLogBufferLines   int // TRADER_LOG_BUFFER_LINES, lines kept in memory per trader, 0 = disabled
+	TraderLogRetentionDays int // TRADER_LOG_RETENTION_DAYS, lines also stored in the database, 0 = not stored
+
+	// Daily statistics rollup (calendar heatmaps), in each owner's timezone
+	DailyStatsBackfillDays int // DAILY_STATS_BACKFILL_DAYS, history rolled up for traders without daily statistics, 0 = disabled
+
+	// Funding rate and open interest fetched by decision cycles, stored for charting
+	MarketHistoryRetentionDays int // MARKET_HISTORY_RETENTION_DAYS, 0 = not recorded
+
+	// Deleted traders stay in the trash (restorable, history kept) before being purged
+	TraderTrashRetentionDays int // TRADER_TRASH_RETENTION_DAYS, 0 = never purged
+
+	// Email notifications (critical trader events, daily digests), disabled without SMTP_HOST
+	SMTPHost               string // SMTP_HOST
+	SMTPPort               int    // SMTP_PORT, 465 = implicit TLS, otherwise STARTTLS when offered
+	SMTPUsername           string // SMTP_USERNAME, empty = no authentication
+	SMTPPassword           string // SMTP_PASSWORD
+	SMTPFrom               string // SMTP_FROM, sender address, SMTP_USERNAME when empty
+	NotificationDigestHour int    // NOTIFICATION_DIGEST_HOUR, local hour (0-23) daily digests and scheduled reports are sent at
+
+	// Weekly/monthly performance reports estimate the AI cost of decision cycles from their prompt sizes
+	ReportAICostPerMTokens float64 // REPORT_AI_COST_PER_MTOKENS, USD per million tokens, 0 = cost not estimated
+
+	// Browser (Web Push) notifications, VAPID key generated on first start
+	WebPushSubject string // WEB_PUSH_SUBJECT, mailto: or https: contact sent to push services, "off" = disabled
+
+	// User-defined outbound webhooks (HMAC-signed JSON) for trader events
+	WebhookAllowPrivateNetworks bool // WEBHOOK_ALLOW_PRIVATE_NETWORKS, allow webhook URLs on private/loopback addresses (self-hosted automation)
+
+	// Shortest trader scan interval allowed (AI provider rate limits may raise it further)
+	MinScanIntervalSeconds int // MIN_SCAN_INTERVAL_SECONDS
+
+	// Decision cycles still running after this are abandoned by the watchdog and logged as failed
+	CycleTimeoutSeconds int // CYCLE_TIMEOUT_SECONDS, 0 = each trader's scan interval minus 15s
+
+	// Market data fetching of decision cycles (symbols fetched concurrently)
+	MarketDataConcurrency          int // MARKET_DATA_CONCURRENCY, max symbols fetched in parallel
+	MarketDataSymbolTimeoutSeconds int // MARKET_DATA_SYMBOL_TIMEOUT_SECONDS, a symbol is skipped after this
+	// Kline providers in failover order, strategies may prefer others
+	MarketDataProviders []string // MARKET_DATA_PROVIDERS, e.g. "coinank,binance,bybit" (the default)
+
+	// Exchanges offering several API hosts (Bybit, OKX) are benchmarked on startup and then periodically,
+	// requests go to the lowest-latency healthy one
+	EndpointCheckIntervalMinutes int // ENDPOINT_CHECK_INTERVAL_MINUTES, 0 = default hosts only, no benchmarking
+
+	// In-flight AI calls shared by all traders, per provider (calls over the limit queue fairly across traders)
+	AIMaxConcurrentCalls  int            // AI_MAX_CONCURRENT_CALLS, limit of providers not listed below, 0 = unlimited
+	AIProviderConcurrency map[string]int // AI_PROVIDER_CONCURRENCY, e.g. "deepseek=4,claude=2"
+
 	// Market data provider API keys
 	AlpacaAPIKey    string // Alpaca API key for US stocks
 	AlpacaSecretKey string // Alpaca secret key
@@ -53,6 +164,8 @@ func Init() {
 		RegistrationEnabled:   true,
 		MaxUsers:              10,   // Default: 10 users allowed
 		ExperienceImprovement: true, // Default: enabled to help improve the product
+		SecurityHeaders:       true,
+		HSTSMaxAgeSeconds:     31536000,
 		// Database defaults
 		DBType:    "sqlite",
 		DBPath:    "data/data.db",
@@ -61,6 +174,32 @@ func Init() {
 		DBUser:    "postgres",
 		DBName:    "nofx",
 		DBSSLMode: "disable",
+		// Equity retention defaults: 1/min for 7 days, 1/hour after, compacted hourly
+		EquityMinuteRetentionDays:       7,
+		EquityCompactionIntervalMinutes: 60,
+		EquityWriteBatchSize:            100,
+		EquityWriteFlushMs:              1000,
+		EquityWriteQueueSize:            1000,
+		RegimeRefreshMinutes:            15,
+		SymbolStatusRefreshMinutes:      15,
+		DelistCloseHours:                24,
+		NewsRefreshMinutes:              15,
+		EconomicCalendarCountries:       []string{"USD"},
+		SentimentRefreshMinutes:         60,
+		SentimentRetentionDays:          365,
+		SocialSentimentSymbols:          []string{"BTC", "ETH", "SOL"},
+		TraderLogBufferLines:            1000,
+		DailyStatsBackfillDays:          365,
+		MarketHistoryRetentionDays:      90,
+		TraderTrashRetentionDays:        30,
+		SMTPPort:                        587,
+		NotificationDigestHour:          8,
+		WebPushSubject:                  "https://github.com/NoFxAiOS/nofx",
+		MinScanIntervalSeconds:          180,
+		EndpointCheckIntervalMinutes:    10,
+		MarketDataConcurrency:           8,
+		MarketDataSymbolTimeoutSeconds:  20,
+		AIMaxConcurrentCalls:            4,
 	}
 
 	// Load from environment variables
@@ -81,6 +220,14 @@ func Init() {
 		}
 	}
 
+	if v := os.Getenv("ADMIN_EMAILS"); v != "" {
+		for _, email := range strings.Split(v, ",") {
+			if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+				cfg.AdminEmails = append(cfg.AdminEmails, email)
+			}
+		}
+	}
+
 	if v := os.Getenv("API_SERVER_PORT"); v != "" {
 		if port, err := strconv.Atoi(v); err == nil && port > 0 {
 			cfg.APIServerPort = port
@@ -93,12 +240,276 @@ func Init() {
 		cfg.TransportEncryption = strings.ToLower(v) == "true"
 	}
 
+	// Secrets backend: env (default), file, vault, aws-kms
+	cfg.SecretsBackend = strings.ToLower(strings.TrimSpace(os.Getenv("SECRETS_BACKEND")))
+
+	// CORS: "*" or empty keeps the permissive default
+	if v := os.Getenv("CORS_ALLOWED_ORIGINS"); v != "" {
+		for _, origin := range strings.Split(v, ",") {
+			if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" && origin != "*" {
+				cfg.CORSAllowedOrigins = append(cfg.CORSAllowedOrigins, origin)
+			}
+		}
+	}
+	if v := os.Getenv("CORS_ALLOW_CREDENTIALS"); v != "" {
+		cfg.CORSAllowCredentials = strings.ToLower(v) == "true"
+	}
+	if v := os.Getenv("SECURITY_HEADERS"); v != "" {
+		cfg.SecurityHeaders = strings.ToLower(v) != "false"
+	}
+	if v := os.Getenv("HSTS_MAX_AGE_SECONDS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.HSTSMaxAgeSeconds = n
+		}
+	}
+	if v := os.Getenv("TRUSTED_PROXIES"); v != "" {
+		for _, proxy := range strings.Split(v, ",") {
+			if proxy = strings.TrimSpace(proxy); proxy != "" {
+				cfg.TrustedProxies = append(cfg.TrustedProxies, proxy)
+			}
+		}
+	}
+	if v := os.Getenv("STRATEGY_BUNDLE_TRUSTED_SIGNERS"); v != "" {
+		for _, signer := range strings.Split(v, ",") {
+			if signer = strings.TrimSpace(signer); signer != "" {
+				cfg.StrategyBundleTrustedSigners = append(cfg.StrategyBundleTrustedSigners, signer)
+			}
+		}
+	}
+	if v := os.Getenv("AUTH_COOKIE"); v != "" {
+		cfg.AuthCookie = strings.ToLower(v) == "true"
+	}
+
 	// Experience improvement: anonymous usage statistics
 	// Default enabled, set EXPERIENCE_IMPROVEMENT=false to disable
 	if v := os.Getenv("EXPERIENCE_IMPROVEMENT"); v != "" {
 		cfg.ExperienceImprovement = strings.ToLower(v) != "false"
 	}
 
+	// Circuit breaker defaults
+	if v := os.Getenv("CIRCUIT_BREAKER_MAX_DRAWDOWN_PCT"); v != "" {
+		if pct, err := strconv.ParseFloat(v, 64); err == nil && pct >= 0 {
+			cfg.CircuitBreakerMaxDrawdownPct = pct
+		}
+	}
+	if v := os.Getenv("CIRCUIT_BREAKER_MAX_CONSECUTIVE_LOSSES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.CircuitBreakerMaxConsecutiveLosses = n
+		}
+	}
+	if v := os.Getenv("CIRCUIT_BREAKER_COOLDOWN_MINUTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.CircuitBreakerCooldownMinutes = n
+		}
+	}
+
+	// Equity snapshot retention
+	if v := os.Getenv("EQUITY_MINUTE_RETENTION_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.EquityMinuteRetentionDays = n
+		}
+	}
+	if v := os.Getenv("EQUITY_MAX_RETENTION_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.EquityMaxRetentionDays = n
+		}
+	}
+	if v := os.Getenv("EQUITY_COMPACTION_INTERVAL_MINUTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.EquityCompactionIntervalMinutes = n
+		}
+	}
+	if v := os.Getenv("EQUITY_WRITE_BATCH_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.EquityWriteBatchSize = n
+		}
+	}
+	if v := os.Getenv("EQUITY_WRITE_FLUSH_MS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.EquityWriteFlushMs = n
+		}
+	}
+	if v := os.Getenv("EQUITY_WRITE_QUEUE_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.EquityWriteQueueSize = n
+		}
+	}
+
+	// Market regime detection
+	if v := os.Getenv("REGIME_REFRESH_MINUTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.RegimeRefreshMinutes = n
+		}
+	}
+
+	// Contract status tracking
+	if v := os.Getenv("SYMBOL_STATUS_REFRESH_MINUTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.SymbolStatusRefreshMinutes = n
+		}
+	}
+	if v := os.Getenv("DELIST_CLOSE_HOURS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.DelistCloseHours = n
+		}
+	}
+
+	// News & economic calendar
+	if v := os.Getenv("NEWS_REFRESH_MINUTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.NewsRefreshMinutes = n
+		}
+	}
+	if v := os.Getenv("NEWS_FEED_URLS"); v != "" {
+		for _, u := range strings.Split(v, ",") {
+			if u = strings.TrimSpace(u); u != "" {
+				cfg.NewsFeedURLs = append(cfg.NewsFeedURLs, u)
+			}
+		}
+	}
+	cfg.EconomicCalendarURL = strings.TrimSpace(os.Getenv("ECONOMIC_CALENDAR_URL"))
+	if v, ok := os.LookupEnv("ECONOMIC_CALENDAR_COUNTRIES"); ok {
+		cfg.EconomicCalendarCountries = nil
+		for _, c := range strings.Split(v, ",") {
+			if c = strings.ToUpper(strings.TrimSpace(c)); c != "" {
+				cfg.EconomicCalendarCountries = append(cfg.EconomicCalendarCountries, c)
+			}
+		}
+	}
+
+	// Market sentiment
+	if v := os.Getenv("SENTIMENT_REFRESH_MINUTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.SentimentRefreshMinutes = n
+		}
+	}
+	if v := os.Getenv("SENTIMENT_RETENTION_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.SentimentRetentionDays = n
+		}
+	}
+	cfg.FearGreedURL = strings.TrimSpace(os.Getenv("FEAR_GREED_URL"))
+	cfg.SocialSentimentURL = strings.TrimSpace(os.Getenv("SOCIAL_SENTIMENT_URL"))
+	cfg.SocialSentimentAPIKey = strings.TrimSpace(os.Getenv("SOCIAL_SENTIMENT_API_KEY"))
+	if v := os.Getenv("SOCIAL_SENTIMENT_SYMBOLS"); v != "" {
+		cfg.SocialSentimentSymbols = nil
+		for _, symbol := range strings.Split(v, ",") {
+			if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+				cfg.SocialSentimentSymbols = append(cfg.SocialSentimentSymbols, symbol)
+			}
+		}
+	}
+
+	// Trader log capture
+	if v := os.Getenv("TRADER_LOG_BUFFER_LINES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.TraderLogBufferLines = n
+		}
+	}
+	if v := os.Getenv("TRADER_LOG_RETENTION_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.TraderLogRetentionDays = n
+		}
+	}
+	if v := os.Getenv("DAILY_STATS_BACKFILL_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.DailyStatsBackfillDays = n
+		}
+	}
+	if v := os.Getenv("MARKET_HISTORY_RETENTION_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.MarketHistoryRetentionDays = n
+		}
+	}
+	if v := os.Getenv("TRADER_TRASH_RETENTION_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.TraderTrashRetentionDays = n
+		}
+	}
+
+	cfg.SMTPHost = strings.TrimSpace(os.Getenv("SMTP_HOST"))
+	if v := os.Getenv("SMTP_PORT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.SMTPPort = n
+		}
+	}
+	cfg.SMTPUsername = os.Getenv("SMTP_USERNAME")
+	cfg.SMTPPassword = os.Getenv("SMTP_PASSWORD")
+	cfg.SMTPFrom = strings.TrimSpace(os.Getenv("SMTP_FROM"))
+	if cfg.SMTPFrom == "" {
+		cfg.SMTPFrom = cfg.SMTPUsername
+	}
+	if v := os.Getenv("NOTIFICATION_DIGEST_HOUR"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 && n < 24 {
+			cfg.NotificationDigestHour = n
+		}
+	}
+	if v := os.Getenv("REPORT_AI_COST_PER_MTOKENS"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f >= 0 {
+			cfg.ReportAICostPerMTokens = f
+		}
+	}
+
+	if v := strings.TrimSpace(os.Getenv("WEB_PUSH_SUBJECT")); v != "" {
+		cfg.WebPushSubject = v
+	} else if cfg.SMTPFrom != "" {
+		cfg.WebPushSubject = "mailto:" + cfg.SMTPFrom
+	}
+	if v := os.Getenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS"); v != "" {
+		cfg.WebhookAllowPrivateNetworks = strings.ToLower(v) == "true"
+	}
+
+	if v := os.Getenv("MIN_SCAN_INTERVAL_SECONDS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.MinScanIntervalSeconds = n
+		}
+	}
+	if v := os.Getenv("CYCLE_TIMEOUT_SECONDS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.CycleTimeoutSeconds = n
+		}
+	}
+
+	if v := os.Getenv("MARKET_DATA_CONCURRENCY"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.MarketDataConcurrency = n
+		}
+	}
+	if v := os.Getenv("MARKET_DATA_SYMBOL_TIMEOUT_SECONDS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.MarketDataSymbolTimeoutSeconds = n
+		}
+	}
+	if v := os.Getenv("MARKET_DATA_PROVIDERS"); v != "" {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				cfg.MarketDataProviders = append(cfg.MarketDataProviders, name)
+			}
+		}
+	}
+	if v := os.Getenv("ENDPOINT_CHECK_INTERVAL_MINUTES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.EndpointCheckIntervalMinutes = n
+		}
+	}
+	if v := os.Getenv("AI_MAX_CONCURRENT_CALLS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.AIMaxConcurrentCalls = n
+		}
+	}
+	if v := os.Getenv("AI_PROVIDER_CONCURRENCY"); v != "" {
+		cfg.AIProviderConcurrency = make(map[string]int)
+		for _, entry := range strings.Split(v, ",") {
+			provider, limit, ok := strings.Cut(strings.TrimSpace(entry), "=")
+			if !ok {
+				continue
+			}
+			if n, err := strconv.Atoi(strings.TrimSpace(limit)); err == nil && n >= 0 {
+				cfg.AIProviderConcurrency[strings.ToLower(strings.TrimSpace(provider))] = n
+			}
+		}
+	}
+
 	// Market data provider API keys
 	cfg.AlpacaAPIKey = os.Getenv("ALPACA_API_KEY")
 	cfg.AlpacaSecretKey = os.Getenv("ALPACA_SECRET_KEY")
@@ -131,6 +542,24 @@ func Init() {
 	if v := os.Getenv("DB_SSLMODE"); v != "" {
 		cfg.DBSSLMode = v
 	}
+	cfg.DBSQLiteJournalMode = os.Getenv("DB_SQLITE_JOURNAL_MODE")
+	cfg.DBSQLiteSynchronous = os.Getenv("DB_SQLITE_SYNCHRONOUS")
+	if v := os.Getenv("DB_SQLITE_BUSY_TIMEOUT_MS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.DBSQLiteBusyTimeoutMs = n
+		}
+	}
+	if v := os.Getenv("DB_SQLITE_MAX_OPEN_CONNS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.DBSQLiteMaxOpenConns = n
+		}
+	}
+	if v := os.Getenv("DATABASE_URL"); v != "" {
+		cfg.DBURL = v
+		if scheme := strings.ToLower(v); strings.HasPrefix(scheme, "postgres://") || strings.HasPrefix(scheme, "postgresql://") {
+			cfg.DBType = "postgres"
+		}
+	}
 
 	global = cfg
 
@@ -148,6 +577,20 @@ func Init() {
 	}
 }
 
+// IsAdmin reports whether the email belongs to an operator listed in ADMIN_EMAILS
+func (c *Config) IsAdmin(email string) bool {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return false
+	}
+	for _, admin := range c.AdminEmails {
+		if admin == email {
+			return true
+		}
+	}
+	return false
+}
+
 // Get returns the global configuration
 func Get() *Config {
 	if global == nil {
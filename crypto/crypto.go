@@ -50,27 +50,47 @@ type CryptoService struct {
 	privateKey *rsa.PrivateKey
 	publicKey  *rsa.PublicKey
 	dataKey    []byte
+
+	// Keys from before a rotation, only used for decryption
+	previousPrivateKeys []*rsa.PrivateKey
+	previousDataKeys    [][]byte
 }
 
-// NewCryptoService creates crypto service (loads keys from environment variables)
+// NewCryptoService creates crypto service (keys come from the backend selected by SECRETS_BACKEND, default env)
 func NewCryptoService() (*CryptoService, error) {
+	provider, err := NewKeyProvider("")
+	if err != nil {
+		return nil, err
+	}
+	return NewCryptoServiceWithProvider(provider)
+}
+
+// NewCryptoServiceWithProvider creates crypto service using the given key provider
+func NewCryptoServiceWithProvider(provider KeyProvider) (*CryptoService, error) {
 	// 1. Load RSA private key
-	privateKey, err := loadRSAPrivateKeyFromEnv()
+	privateKey, err := provider.LoadRSAPrivateKey()
 	if err != nil {
-		return nil, fmt.Errorf("failed to load RSA private key: %w", err)
+		return nil, fmt.Errorf("failed to load RSA private key (%s backend): %w", provider.Name(), err)
 	}
 
 	// 2. Load AES data encryption key
-	dataKey, err := loadDataKeyFromEnv()
+	dataKey, err := provider.LoadDataKey()
 	if err != nil {
-		return nil, fmt.Errorf("failed to load data encryption key: %w", err)
+		return nil, fmt.Errorf("failed to load data encryption key (%s backend): %w", provider.Name(), err)
 	}
 
-	return &CryptoService{
+	cs := &CryptoService{
 		privateKey: privateKey,
 		publicKey:  &privateKey.PublicKey,
 		dataKey:    dataKey,
-	}, nil
+	}
+
+	// 3. Load previous keys (only present while a key rotation is in progress)
+	if err := cs.loadPreviousKeysFromEnv(); err != nil {
+		return nil, fmt.Errorf("failed to load previous keys: %w", err)
+	}
+
+	return cs, nil
 }
 
 // loadRSAPrivateKeyFromEnv loads RSA private key from environment variable
@@ -243,27 +263,39 @@ func (cs *CryptoService) DecryptFromStorage(value string, aadParts ...string) (s
 		return "", fmt.Errorf("failed to decode ciphertext: %w", err)
 	}
 
-	block, err := aes.NewCipher(cs.dataKey)
+	aad := composeAAD(aadParts)
+	plaintext, err := openWithKey(cs.dataKey, nonce, ciphertext, aad)
+	if err == nil {
+		return string(plaintext), nil
+	}
+
+	// Dual-key decryption during rotation: fall back to previous data keys
+	for _, key := range cs.previousDataKeys {
+		if plaintext, prevErr := openWithKey(key, nonce, ciphertext, aad); prevErr == nil {
+			return string(plaintext), nil
+		}
+	}
+
+	return "", fmt.Errorf("decryption failed: %w", err)
+}
+
+// openWithKey decrypts AES-GCM ciphertext with the given key
+func openWithKey(key, nonce, ciphertext, aad []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	gcm, err := cipher.NewGCM(block)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if len(nonce) != gcm.NonceSize() {
-		return "", fmt.Errorf("invalid nonce length: expected %d, got %d", gcm.NonceSize(), len(nonce))
-	}
-
-	aad := composeAAD(aadParts)
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, aad)
-	if err != nil {
-		return "", fmt.Errorf("decryption failed: %w", err)
+		return nil, fmt.Errorf("invalid nonce length: expected %d, got %d", gcm.NonceSize(), len(nonce))
 	}
 
-	return string(plaintext), nil
+	return gcm.Open(nil, nonce, ciphertext, aad)
 }
 
 func (cs *CryptoService) IsEncryptedStorageValue(value string) bool {
@@ -319,8 +351,16 @@ func (cs *CryptoService) DecryptPayload(payload *EncryptedPayload) ([]byte, erro
 		}
 	}
 
-	// 3. Decrypt AES key using RSA-OAEP
+	// 3. Decrypt AES key using RSA-OAEP (clients may still hold the previous public key during rotation)
 	aesKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, cs.privateKey, wrappedKey, nil)
+	if err != nil {
+		for _, prev := range cs.previousPrivateKeys {
+			if key, prevErr := rsa.DecryptOAEP(sha256.New(), rand.Reader, prev, wrappedKey, nil); prevErr == nil {
+				aesKey, err = key, nil
+				break
+			}
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("RSA decryption failed: %w", err)
 	}
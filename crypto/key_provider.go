@@ -0,0 +1,133 @@
+package crypto
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Secrets backend selection
+const (
+	EnvSecretsBackend = "SECRETS_BACKEND" // env (default), file, vault, aws-kms
+
+	EnvRSAPrivateKeyFile     = "RSA_PRIVATE_KEY_FILE"     // Path to RSA private key PEM file (file backend)
+	EnvDataEncryptionKeyFile = "DATA_ENCRYPTION_KEY_FILE" // Path to data encryption key file (file backend)
+)
+
+// Supported secrets backends
+const (
+	SecretsBackendEnv    = "env"
+	SecretsBackendFile   = "file"
+	SecretsBackendVault  = "vault"
+	SecretsBackendAWSKMS = "aws-kms"
+)
+
+// KeyProvider supplies the key material used by CryptoService
+// Implementations fetch the RSA transport key and the AES data key from
+// wherever the deployment keeps them (environment, files, Vault, KMS).
+type KeyProvider interface {
+	// Name returns backend name for logging
+	Name() string
+	// LoadRSAPrivateKey loads the RSA private key used for transport encryption
+	LoadRSAPrivateKey() (*rsa.PrivateKey, error)
+	// LoadDataKey loads the AES key used for storage encryption
+	LoadDataKey() ([]byte, error)
+}
+
+// NewKeyProvider creates key provider for the given backend name
+// Empty backend falls back to SECRETS_BACKEND env var, then to "env"
+func NewKeyProvider(backend string) (KeyProvider, error) {
+	if backend == "" {
+		backend = os.Getenv(EnvSecretsBackend)
+	}
+	switch strings.ToLower(strings.TrimSpace(backend)) {
+	case "", SecretsBackendEnv:
+		return &EnvKeyProvider{}, nil
+	case SecretsBackendFile:
+		return NewFileKeyProviderFromEnv()
+	case SecretsBackendVault:
+		return NewVaultKeyProviderFromEnv()
+	case SecretsBackendAWSKMS:
+		return NewAWSKMSKeyProviderFromEnv()
+	default:
+		return nil, fmt.Errorf("unsupported secrets backend: %s", backend)
+	}
+}
+
+// parseDataKey converts raw key text into an AES key (shared by all providers)
+func parseDataKey(keyStr string) ([]byte, error) {
+	keyStr = strings.TrimSpace(keyStr)
+	if keyStr == "" {
+		return nil, fmt.Errorf("data encryption key is empty")
+	}
+	if key, ok := decodePossibleKey(keyStr); ok {
+		return key, nil
+	}
+	// Not an encoded key, derive one from the passphrase
+	key, _ := normalizeAESKey([]byte(keyStr))
+	return key, nil
+}
+
+// parseRSAKeyText parses PEM text that may contain escaped newlines
+func parseRSAKeyText(keyPEM string) (*rsa.PrivateKey, error) {
+	keyPEM = strings.ReplaceAll(keyPEM, "\\n", "\n")
+	return ParseRSAPrivateKeyFromPEM([]byte(keyPEM))
+}
+
+// ============================================================================
+// Environment variable provider (default, backward compatible)
+// ============================================================================
+
+// EnvKeyProvider loads keys from RSA_PRIVATE_KEY and DATA_ENCRYPTION_KEY
+type EnvKeyProvider struct{}
+
+func (p *EnvKeyProvider) Name() string { return SecretsBackendEnv }
+
+func (p *EnvKeyProvider) LoadRSAPrivateKey() (*rsa.PrivateKey, error) {
+	return loadRSAPrivateKeyFromEnv()
+}
+
+func (p *EnvKeyProvider) LoadDataKey() ([]byte, error) {
+	return loadDataKeyFromEnv()
+}
+
+// ============================================================================
+// File provider
+// ============================================================================
+
+// FileKeyProvider loads keys from files on disk (e.g. mounted Docker/K8s secrets)
+type FileKeyProvider struct {
+	RSAKeyPath  string
+	DataKeyPath string
+}
+
+// NewFileKeyProviderFromEnv creates file provider from RSA_PRIVATE_KEY_FILE / DATA_ENCRYPTION_KEY_FILE
+func NewFileKeyProviderFromEnv() (*FileKeyProvider, error) {
+	p := &FileKeyProvider{
+		RSAKeyPath:  os.Getenv(EnvRSAPrivateKeyFile),
+		DataKeyPath: os.Getenv(EnvDataEncryptionKeyFile),
+	}
+	if p.RSAKeyPath == "" || p.DataKeyPath == "" {
+		return nil, fmt.Errorf("file secrets backend requires %s and %s", EnvRSAPrivateKeyFile, EnvDataEncryptionKeyFile)
+	}
+	return p, nil
+}
+
+func (p *FileKeyProvider) Name() string { return SecretsBackendFile }
+
+func (p *FileKeyProvider) LoadRSAPrivateKey() (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(p.RSAKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RSA key file: %w", err)
+	}
+	return ParseRSAPrivateKeyFromPEM(data)
+}
+
+func (p *FileKeyProvider) LoadDataKey() ([]byte, error) {
+	data, err := os.ReadFile(p.DataKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data key file: %w", err)
+	}
+	return parseDataKey(string(data))
+}
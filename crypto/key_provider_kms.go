@@ -0,0 +1,211 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWS KMS backend environment variables
+const (
+	EnvAWSRegion           = "AWS_REGION"
+	EnvAWSAccessKeyID      = "AWS_ACCESS_KEY_ID"
+	EnvAWSSecretAccessKey  = "AWS_SECRET_ACCESS_KEY"
+	EnvAWSSessionToken     = "AWS_SESSION_TOKEN"
+	EnvKMSEndpoint         = "KMS_ENDPOINT"                  // Optional endpoint override (VPC endpoint, LocalStack)
+	EnvKMSEncryptedDataKey = "KMS_ENCRYPTED_DATA_KEY"        // Base64 CiphertextBlob of the data key
+	EnvKMSEncryptedRSAKey  = "KMS_ENCRYPTED_RSA_PRIVATE_KEY" // Base64 CiphertextBlob of the RSA PEM
+)
+
+// AWSKMSKeyProvider decrypts envelope-encrypted keys with AWS KMS
+// Only KMS ciphertext blobs are stored in the environment; plaintext keys
+// exist in memory only. If KMS_ENCRYPTED_RSA_PRIVATE_KEY is not set, the
+// RSA key falls back to RSA_PRIVATE_KEY.
+type AWSKMSKeyProvider struct {
+	Region           string
+	AccessKeyID      string
+	SecretAccessKey  string
+	SessionToken     string
+	Endpoint         string
+	EncryptedDataKey string
+	EncryptedRSAKey  string
+	HTTPClient       *http.Client
+
+	now func() time.Time
+}
+
+// NewAWSKMSKeyProviderFromEnv creates KMS provider from AWS_* / KMS_* env vars
+func NewAWSKMSKeyProviderFromEnv() (*AWSKMSKeyProvider, error) {
+	p := &AWSKMSKeyProvider{
+		Region:           os.Getenv(EnvAWSRegion),
+		AccessKeyID:      os.Getenv(EnvAWSAccessKeyID),
+		SecretAccessKey:  os.Getenv(EnvAWSSecretAccessKey),
+		SessionToken:     os.Getenv(EnvAWSSessionToken),
+		Endpoint:         os.Getenv(EnvKMSEndpoint),
+		EncryptedDataKey: strings.TrimSpace(os.Getenv(EnvKMSEncryptedDataKey)),
+		EncryptedRSAKey:  strings.TrimSpace(os.Getenv(EnvKMSEncryptedRSAKey)),
+	}
+	if p.Region == "" || p.AccessKeyID == "" || p.SecretAccessKey == "" {
+		return nil, fmt.Errorf("aws-kms secrets backend requires %s, %s and %s", EnvAWSRegion, EnvAWSAccessKeyID, EnvAWSSecretAccessKey)
+	}
+	if p.EncryptedDataKey == "" {
+		return nil, fmt.Errorf("aws-kms secrets backend requires %s", EnvKMSEncryptedDataKey)
+	}
+	return p, nil
+}
+
+func (p *AWSKMSKeyProvider) Name() string { return SecretsBackendAWSKMS }
+
+func (p *AWSKMSKeyProvider) LoadRSAPrivateKey() (*rsa.PrivateKey, error) {
+	if p.EncryptedRSAKey == "" {
+		return loadRSAPrivateKeyFromEnv()
+	}
+	plaintext, err := p.decrypt(p.EncryptedRSAKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt RSA key with KMS: %w", err)
+	}
+	return parseRSAKeyText(string(plaintext))
+}
+
+func (p *AWSKMSKeyProvider) LoadDataKey() ([]byte, error) {
+	plaintext, err := p.decrypt(p.EncryptedDataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key with KMS: %w", err)
+	}
+	// KMS GenerateDataKey returns raw bytes; a wrapped text key is also accepted
+	switch len(plaintext) {
+	case 16, 24, 32:
+		return plaintext, nil
+	}
+	return parseDataKey(string(plaintext))
+}
+
+// decrypt calls KMS Decrypt for a base64 ciphertext blob
+func (p *AWSKMSKeyProvider) decrypt(ciphertextB64 string) ([]byte, error) {
+	payload, err := json.Marshal(map[string]string{"CiphertextBlob": ciphertextB64})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com/", p.Region)
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService.Decrypt")
+	p.sign(req, payload)
+
+	client := p.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("kms request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kms response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("kms returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		Plaintext string `json:"Plaintext"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse kms response: %w", err)
+	}
+	return base64.StdEncoding.DecodeString(result.Plaintext)
+}
+
+// sign adds AWS Signature Version 4 headers to the request
+func (p *AWSKMSKeyProvider) sign(req *http.Request, payload []byte) {
+	now := time.Now().UTC()
+	if p.now != nil {
+		now = p.now().UTC()
+	}
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if p.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.SessionToken)
+	}
+
+	host := req.URL.Host
+	headers := map[string]string{"host": host}
+	for k := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(req.Header.Get(k))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	payloadHash := sha256.Sum256(payload)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := dateStamp + "/" + p.Region + "/kms/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+p.SecretAccessKey), dateStamp)
+	key = hmacSHA256(key, p.Region)
+	key = hmacSHA256(key, "kms")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		p.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(values url.Values) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.ReplaceAll(values.Encode(), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
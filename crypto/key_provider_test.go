@@ -0,0 +1,128 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testKeyMaterial(t *testing.T) (privPEM, dataKey string) {
+	t.Helper()
+	privPEM, _, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateKeyPair: %v", err)
+	}
+	dataKey, err = GenerateDataKey()
+	if err != nil {
+		t.Fatalf("GenerateDataKey: %v", err)
+	}
+	return privPEM, dataKey
+}
+
+func TestFileKeyProvider(t *testing.T) {
+	privPEM, dataKey := testKeyMaterial(t)
+	dir := t.TempDir()
+	rsaPath := filepath.Join(dir, "rsa.pem")
+	dataPath := filepath.Join(dir, "data.key")
+	os.WriteFile(rsaPath, []byte(privPEM), 0600)
+	os.WriteFile(dataPath, []byte(dataKey+"\n"), 0600)
+
+	cs, err := NewCryptoServiceWithProvider(&FileKeyProvider{RSAKeyPath: rsaPath, DataKeyPath: dataPath})
+	if err != nil {
+		t.Fatalf("NewCryptoServiceWithProvider: %v", err)
+	}
+
+	enc, err := cs.EncryptForStorage("secret")
+	if err != nil {
+		t.Fatalf("EncryptForStorage: %v", err)
+	}
+	dec, err := cs.DecryptFromStorage(enc)
+	if err != nil || dec != "secret" {
+		t.Fatalf("round trip failed: %q, %v", dec, err)
+	}
+}
+
+func TestVaultKeyProvider_KVv2(t *testing.T) {
+	privPEM, dataKey := testKeyMaterial(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/secret/data/nofx" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"data": map[string]string{
+					"rsa_private_key":     privPEM,
+					"data_encryption_key": dataKey,
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	p := &VaultKeyProvider{Addr: server.URL, Token: "test-token", SecretPath: "secret/data/nofx"}
+	if _, err := NewCryptoServiceWithProvider(p); err != nil {
+		t.Fatalf("vault provider failed: %v", err)
+	}
+
+	bad := &VaultKeyProvider{Addr: server.URL, Token: "wrong", SecretPath: "secret/data/nofx"}
+	if _, err := bad.LoadDataKey(); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("expected 403 error, got %v", err)
+	}
+}
+
+func TestAWSKMSKeyProvider_Decrypt(t *testing.T) {
+	raw := make([]byte, 32)
+	for i := range raw {
+		raw[i] = byte(i)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "TrentService.Decrypt" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["CiphertextBlob"] != "blob" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"Plaintext": base64.StdEncoding.EncodeToString(raw)})
+	}))
+	defer server.Close()
+
+	p := &AWSKMSKeyProvider{
+		Region:           "us-east-1",
+		AccessKeyID:      "AKID",
+		SecretAccessKey:  "secret",
+		Endpoint:         server.URL + "/",
+		EncryptedDataKey: "blob",
+	}
+	key, err := p.LoadDataKey()
+	if err != nil {
+		t.Fatalf("LoadDataKey: %v", err)
+	}
+	if string(key) != string(raw) {
+		t.Fatalf("unexpected key bytes")
+	}
+}
+
+func TestNewKeyProvider_Unsupported(t *testing.T) {
+	if _, err := NewKeyProvider("bogus"); err == nil {
+		t.Fatal("expected error for unsupported backend")
+	}
+	p, err := NewKeyProvider("env")
+	if err != nil || p.Name() != SecretsBackendEnv {
+		t.Fatalf("expected env provider, got %v, %v", p, err)
+	}
+}
@@ -0,0 +1,151 @@
+package crypto
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Vault backend environment variables
+const (
+	EnvVaultAddr         = "VAULT_ADDR"           // e.g. https://vault.example.com:8200
+	EnvVaultToken        = "VAULT_TOKEN"          // Vault token with read access to the secret path
+	EnvVaultNamespace    = "VAULT_NAMESPACE"      // Optional Vault Enterprise namespace
+	EnvVaultSecretPath   = "VAULT_SECRET_PATH"    // KV path, e.g. secret/data/nofx (KV v2) or secret/nofx (KV v1)
+	EnvVaultRSAKeyField  = "VAULT_RSA_KEY_FIELD"  // Field holding the RSA PEM (default rsa_private_key)
+	EnvVaultDataKeyField = "VAULT_DATA_KEY_FIELD" // Field holding the data key (default data_encryption_key)
+)
+
+// VaultKeyProvider loads keys from a HashiCorp Vault KV secret
+type VaultKeyProvider struct {
+	Addr         string
+	Token        string
+	Namespace    string
+	SecretPath   string
+	RSAKeyField  string
+	DataKeyField string
+	HTTPClient   *http.Client
+
+	once   sync.Once
+	secret map[string]string
+	err    error
+}
+
+// NewVaultKeyProviderFromEnv creates Vault provider from VAULT_* env vars
+func NewVaultKeyProviderFromEnv() (*VaultKeyProvider, error) {
+	p := &VaultKeyProvider{
+		Addr:         strings.TrimRight(os.Getenv(EnvVaultAddr), "/"),
+		Token:        os.Getenv(EnvVaultToken),
+		Namespace:    os.Getenv(EnvVaultNamespace),
+		SecretPath:   strings.Trim(os.Getenv(EnvVaultSecretPath), "/"),
+		RSAKeyField:  os.Getenv(EnvVaultRSAKeyField),
+		DataKeyField: os.Getenv(EnvVaultDataKeyField),
+	}
+	if p.Addr == "" || p.Token == "" || p.SecretPath == "" {
+		return nil, fmt.Errorf("vault secrets backend requires %s, %s and %s", EnvVaultAddr, EnvVaultToken, EnvVaultSecretPath)
+	}
+	return p, nil
+}
+
+func (p *VaultKeyProvider) Name() string { return SecretsBackendVault }
+
+func (p *VaultKeyProvider) LoadRSAPrivateKey() (*rsa.PrivateKey, error) {
+	field := p.RSAKeyField
+	if field == "" {
+		field = "rsa_private_key"
+	}
+	value, err := p.field(field)
+	if err != nil {
+		return nil, err
+	}
+	return parseRSAKeyText(value)
+}
+
+func (p *VaultKeyProvider) LoadDataKey() ([]byte, error) {
+	field := p.DataKeyField
+	if field == "" {
+		field = "data_encryption_key"
+	}
+	value, err := p.field(field)
+	if err != nil {
+		return nil, err
+	}
+	return parseDataKey(value)
+}
+
+// field returns a single secret field, reading the secret from Vault once
+func (p *VaultKeyProvider) field(name string) (string, error) {
+	p.once.Do(func() {
+		p.secret, p.err = p.readSecret()
+	})
+	if p.err != nil {
+		return "", p.err
+	}
+	value, ok := p.secret[name]
+	if !ok || value == "" {
+		return "", fmt.Errorf("vault secret %s has no field %q", p.SecretPath, name)
+	}
+	return value, nil
+}
+
+// readSecret reads the KV secret, supporting both KV v1 and KV v2 response shapes
+func (p *VaultKeyProvider) readSecret() (map[string]string, error) {
+	client := p.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, p.Addr+"/v1/"+p.SecretPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", p.Token)
+	if p.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.Namespace)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var parsed struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse vault response: %w", err)
+	}
+
+	// KV v2 nests fields under data.data
+	fields := parsed.Data
+	if nested, ok := parsed.Data["data"]; ok {
+		var inner map[string]json.RawMessage
+		if err := json.Unmarshal(nested, &inner); err == nil {
+			fields = inner
+		}
+	}
+
+	secret := make(map[string]string, len(fields))
+	for k, raw := range fields {
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			secret[k] = s
+		}
+	}
+	return secret, nil
+}
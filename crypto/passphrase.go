@@ -0,0 +1,93 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt cost of new envelopes (interactive-login strength, ~100ms)
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+	// maxScryptMemory caps scrypt's 128*N*r working memory accepted when opening, so a crafted
+	// envelope cannot exhaust memory (new envelopes use 32 MiB)
+	maxScryptMemory = 64 << 20
+)
+
+// ErrWrongPassphrase the passphrase does not open the envelope (or it was tampered with)
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted data")
+
+// PassphraseEnvelope data encrypted with AES-256-GCM under a scrypt-derived key
+// Unlike storage encryption it is independent of the installation's data key, so it can be opened
+// on another installation that knows the passphrase.
+type PassphraseEnvelope struct {
+	KDF        string `json:"kdf"` // scrypt
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// SealWithPassphrase encrypts plaintext with a key derived from passphrase
+func SealWithPassphrase(plaintext []byte, passphrase string) (*PassphraseEnvelope, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase is required")
+	}
+	env := &PassphraseEnvelope{KDF: "scrypt", N: scryptN, R: scryptR, P: scryptP, Salt: make([]byte, 16)}
+	if _, err := rand.Read(env.Salt); err != nil {
+		return nil, err
+	}
+	gcm, err := env.cipher(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	env.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(env.Nonce); err != nil {
+		return nil, err
+	}
+	env.Ciphertext = gcm.Seal(nil, env.Nonce, plaintext, nil)
+	return env, nil
+}
+
+// OpenWithPassphrase decrypts an envelope, ErrWrongPassphrase when the passphrase does not match
+func OpenWithPassphrase(env *PassphraseEnvelope, passphrase string) ([]byte, error) {
+	if env == nil || env.KDF != "scrypt" {
+		return nil, errors.New("unsupported key derivation")
+	}
+	if env.N <= 1 || env.R <= 0 || env.R > 32 || env.P != 1 || env.N > maxScryptMemory/(128*env.R) {
+		return nil, fmt.Errorf("unsupported scrypt parameters N=%d r=%d p=%d", env.N, env.R, env.P)
+	}
+	gcm, err := env.cipher(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce length: expected %d, got %d", gcm.NonceSize(), len(env.Nonce))
+	}
+	plaintext, err := gcm.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+// cipher AES-GCM keyed with the scrypt derivation of passphrase
+func (env *PassphraseEnvelope) cipher(passphrase string) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), env.Salt, env.N, env.R, env.P, 32)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,47 @@
+package crypto
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPassphraseEnvelopeRoundTrip(t *testing.T) {
+	env, err := SealWithPassphrase([]byte("config"), "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := OpenWithPassphrase(env, "correct horse")
+	if err != nil || string(plaintext) != "config" {
+		t.Fatalf("open: %q %v", plaintext, err)
+	}
+	if _, err := OpenWithPassphrase(env, "wrong"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("wrong passphrase: expected ErrWrongPassphrase, got %v", err)
+	}
+}
+
+func TestOpenWithPassphraseRejectsOversizedScryptParameters(t *testing.T) {
+	env, err := SealWithPassphrase([]byte("config"), "pw")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		n, r, p int
+	}{
+		{"N over the memory cap", 1 << 20, 8, 1},
+		{"r over the memory cap", 1 << 15, 32, 1},
+		{"r out of range", 2, 1 << 40, 1},
+		{"parallel lanes", 1 << 15, 8, 16},
+		{"zero r", 1 << 15, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crafted := *env
+			crafted.N, crafted.R, crafted.P = tt.n, tt.r, tt.p
+			if _, err := OpenWithPassphrase(&crafted, "pw"); err == nil || errors.Is(err, ErrWrongPassphrase) {
+				t.Errorf("N=%d r=%d p=%d: expected a parameter error, got %v", tt.n, tt.r, tt.p, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package crypto
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Key rotation environment variables
+// During rotation, set the new keys as DATA_ENCRYPTION_KEY / RSA_PRIVATE_KEY and
+// keep the old ones here so existing ciphertext stays readable until re-encrypted.
+const (
+	EnvDataEncryptionKeyPrevious = "DATA_ENCRYPTION_KEY_PREVIOUS" // Comma-separated previous data keys
+	EnvRSAPrivateKeyPrevious     = "RSA_PRIVATE_KEY_PREVIOUS"     // Previous RSA private key (PEM, \n for newlines)
+)
+
+// loadPreviousKeysFromEnv loads decryption-only keys left over from a rotation
+func (cs *CryptoService) loadPreviousKeysFromEnv() error {
+	if v := strings.TrimSpace(os.Getenv(EnvDataEncryptionKeyPrevious)); v != "" {
+		for _, part := range strings.Split(v, ",") {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+			key, err := parseDataKey(part)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %w", EnvDataEncryptionKeyPrevious, err)
+			}
+			cs.AddPreviousDataKey(key)
+		}
+	}
+	if v := os.Getenv(EnvRSAPrivateKeyPrevious); v != "" {
+		key, err := parseRSAKeyText(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvRSAPrivateKeyPrevious, err)
+		}
+		cs.AddPreviousPrivateKey(key)
+	}
+	return nil
+}
+
+// AddPreviousDataKey registers a decryption-only data key
+func (cs *CryptoService) AddPreviousDataKey(key []byte) {
+	cs.previousDataKeys = append(cs.previousDataKeys, key)
+}
+
+// AddPreviousPrivateKey registers a decryption-only RSA private key
+func (cs *CryptoService) AddPreviousPrivateKey(key *rsa.PrivateKey) {
+	cs.previousPrivateKeys = append(cs.previousPrivateKeys, key)
+}
+
+// HasPreviousKeys reports whether the service is running in dual-key rotation mode
+func (cs *CryptoService) HasPreviousKeys() bool {
+	return len(cs.previousDataKeys) > 0 || len(cs.previousPrivateKeys) > 0
+}
+
+// IsEncryptedWithCurrentKey reports whether value decrypts with the current data key alone
+func (cs *CryptoService) IsEncryptedWithCurrentKey(value string, aadParts ...string) bool {
+	if !isEncryptedStorageValue(value) {
+		return false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(value, storagePrefix), storageDelimiter, 2)
+	if len(parts) != 2 {
+		return false
+	}
+	nonce, err := base64.StdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return false
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	_, err = openWithKey(cs.dataKey, nonce, ciphertext, composeAAD(aadParts))
+	return err == nil
+}
+
+// ReencryptForStorage re-encrypts a stored value under the current data key
+// Plaintext values are encrypted; values already under the current key are returned unchanged.
+func (cs *CryptoService) ReencryptForStorage(value string, aadParts ...string) (string, bool, error) {
+	if value == "" || cs.IsEncryptedWithCurrentKey(value, aadParts...) {
+		return value, false, nil
+	}
+	plaintext := value
+	if isEncryptedStorageValue(value) {
+		var err error
+		plaintext, err = cs.DecryptFromStorage(value, aadParts...)
+		if err != nil {
+			return "", false, err
+		}
+	}
+	encrypted, err := cs.EncryptForStorage(plaintext, aadParts...)
+	if err != nil {
+		return "", false, err
+	}
+	return encrypted, true, nil
+}
+
+// ============================================================================
+// KeyRotator - re-encrypts stored secrets under the current key
+// ============================================================================
+
+// SecretRef identifies one stored encrypted value
+type SecretRef struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	ID     string `json:"id"`
+}
+
+// RotationSource abstracts the storage that holds encrypted values
+// Implemented by the store package so crypto does not depend on the schema.
+type RotationSource interface {
+	// ForEachSecret calls fn with every non-empty stored secret (raw, still encrypted)
+	ForEachSecret(fn func(ref SecretRef, value string) error) error
+	// UpdateSecret replaces the stored value if it still equals oldValue
+	// Returns false when the row changed concurrently (the newer write already uses the current key)
+	UpdateSecret(ref SecretRef, oldValue, newValue string) (bool, error)
+}
+
+// Rotation states
+const (
+	RotationStateIdle      = "idle"
+	RotationStateRunning   = "running"
+	RotationStateVerifying = "verifying"
+	RotationStateCompleted = "completed"
+	RotationStateFailed    = "failed"
+)
+
+// RotationProgress rotation progress snapshot
+type RotationProgress struct {
+	State       string      `json:"state"`
+	Total       int         `json:"total"`
+	Processed   int         `json:"processed"`
+	Reencrypted int         `json:"reencrypted"`
+	Skipped     int         `json:"skipped"` // Already under current key or changed concurrently
+	Failed      int         `json:"failed"`
+	Failures    []SecretRef `json:"failures,omitempty"`
+	Verified    bool        `json:"verified"`
+	StartedAt   time.Time   `json:"started_at,omitempty"`
+	FinishedAt  time.Time   `json:"finished_at,omitempty"`
+	Error       string      `json:"error,omitempty"`
+}
+
+// VerifyReport result of a verification pass
+type VerifyReport struct {
+	Total     int         `json:"total"`
+	Current   int         `json:"current"`   // Decrypts with current key
+	Previous  int         `json:"previous"`  // Only decrypts with a previous key
+	Plaintext int         `json:"plaintext"` // Stored unencrypted
+	Broken    int         `json:"broken"`    // Cannot be decrypted at all
+	Pending   []SecretRef `json:"pending,omitempty"`
+}
+
+// OK reports whether every secret is encrypted under the current key
+func (r *VerifyReport) OK() bool {
+	return r.Previous == 0 && r.Plaintext == 0 && r.Broken == 0
+}
+
+// ErrRotationInProgress returned when a rotation is already running
+var ErrRotationInProgress = errors.New("key rotation already in progress")
+
+// KeyRotator re-encrypts all stored secrets with zero downtime
+// Reads keep working throughout because CryptoService decrypts with both
+// current and previous keys; each row is swapped with a compare-and-set update.
+type KeyRotator struct {
+	cs     *CryptoService
+	source RotationSource
+
+	mu       sync.RWMutex
+	progress RotationProgress
+}
+
+// NewKeyRotator creates key rotator
+func NewKeyRotator(cs *CryptoService, source RotationSource) *KeyRotator {
+	return &KeyRotator{
+		cs:       cs,
+		source:   source,
+		progress: RotationProgress{State: RotationStateIdle},
+	}
+}
+
+// Progress returns a copy of the current progress
+func (r *KeyRotator) Progress() RotationProgress {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p := r.progress
+	p.Failures = append([]SecretRef(nil), r.progress.Failures...)
+	return p
+}
+
+// Start runs Rotate in the background
+func (r *KeyRotator) Start() error {
+	if err := r.begin(); err != nil {
+		return err
+	}
+	go r.run()
+	return nil
+}
+
+// Rotate re-encrypts every secret, then runs a verification pass (blocking)
+func (r *KeyRotator) Rotate() (RotationProgress, error) {
+	if err := r.begin(); err != nil {
+		return r.Progress(), err
+	}
+	err := r.run()
+	return r.Progress(), err
+}
+
+func (r *KeyRotator) begin() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.progress.State == RotationStateRunning || r.progress.State == RotationStateVerifying {
+		return ErrRotationInProgress
+	}
+	r.progress = RotationProgress{State: RotationStateRunning, StartedAt: time.Now().UTC()}
+	return nil
+}
+
+func (r *KeyRotator) run() error {
+	// Count first so progress has a denominator
+	total := 0
+	if err := r.source.ForEachSecret(func(SecretRef, string) error {
+		total++
+		return nil
+	}); err != nil {
+		return r.fail(err)
+	}
+	r.update(func(p *RotationProgress) { p.Total = total })
+
+	err := r.source.ForEachSecret(func(ref SecretRef, value string) error {
+		newValue, changed, err := r.cs.ReencryptForStorage(value)
+		if err != nil {
+			r.update(func(p *RotationProgress) {
+				p.Processed++
+				p.Failed++
+				p.Failures = append(p.Failures, ref)
+			})
+			return nil
+		}
+		if !changed {
+			r.update(func(p *RotationProgress) { p.Processed++; p.Skipped++ })
+			return nil
+		}
+		updated, err := r.source.UpdateSecret(ref, value, newValue)
+		if err != nil {
+			return fmt.Errorf("failed to update %s.%s id=%s: %w", ref.Table, ref.Column, ref.ID, err)
+		}
+		r.update(func(p *RotationProgress) {
+			p.Processed++
+			if updated {
+				p.Reencrypted++
+			} else {
+				p.Skipped++
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		return r.fail(err)
+	}
+
+	r.update(func(p *RotationProgress) { p.State = RotationStateVerifying })
+	report, err := r.Verify()
+	if err != nil {
+		return r.fail(err)
+	}
+
+	r.update(func(p *RotationProgress) {
+		p.Verified = report.OK()
+		p.FinishedAt = time.Now().UTC()
+		if p.Verified {
+			p.State = RotationStateCompleted
+		} else {
+			p.State = RotationStateFailed
+			p.Error = fmt.Sprintf("verification failed: %d secrets not under current key", report.Previous+report.Plaintext+report.Broken)
+		}
+	})
+	if !report.OK() {
+		return errors.New(r.Progress().Error)
+	}
+	return nil
+}
+
+// Verify checks that every stored secret decrypts with the current key only
+// Safe to run at any time; once it reports OK the previous keys can be removed.
+func (r *KeyRotator) Verify() (*VerifyReport, error) {
+	report := &VerifyReport{}
+	err := r.source.ForEachSecret(func(ref SecretRef, value string) error {
+		report.Total++
+		switch {
+		case !isEncryptedStorageValue(value):
+			report.Plaintext++
+			report.Pending = append(report.Pending, ref)
+		case r.cs.IsEncryptedWithCurrentKey(value):
+			report.Current++
+		default:
+			if _, err := r.cs.DecryptFromStorage(value); err == nil {
+				report.Previous++
+			} else {
+				report.Broken++
+			}
+			report.Pending = append(report.Pending, ref)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (r *KeyRotator) update(fn func(p *RotationProgress)) {
+	r.mu.Lock()
+	fn(&r.progress)
+	r.mu.Unlock()
+}
+
+func (r *KeyRotator) fail(err error) error {
+	r.update(func(p *RotationProgress) {
+		p.State = RotationStateFailed
+		p.Error = err.Error()
+		p.FinishedAt = time.Now().UTC()
+	})
+	return err
+}
@@ -0,0 +1,88 @@
+package crypto
+
+import (
+	"testing"
+)
+
+type memorySource struct {
+	values map[SecretRef]string
+}
+
+func (m *memorySource) ForEachSecret(fn func(ref SecretRef, value string) error) error {
+	for ref, v := range m.values {
+		if err := fn(ref, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memorySource) UpdateSecret(ref SecretRef, oldValue, newValue string) (bool, error) {
+	if m.values[ref] != oldValue {
+		return false, nil
+	}
+	m.values[ref] = newValue
+	return true, nil
+}
+
+func newTestService(t *testing.T) *CryptoService {
+	t.Helper()
+	privPEM, dataKey := testKeyMaterial(t)
+	priv, err := ParseRSAPrivateKeyFromPEM([]byte(privPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := parseDataKey(dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &CryptoService{privateKey: priv, publicKey: &priv.PublicKey, dataKey: key}
+}
+
+func TestKeyRotator_RotateAndVerify(t *testing.T) {
+	oldCS := newTestService(t)
+	oldValue, _ := oldCS.EncryptForStorage("old-secret")
+
+	newCS := newTestService(t)
+	newCS.AddPreviousDataKey(oldCS.dataKey)
+	currentValue, _ := newCS.EncryptForStorage("new-secret")
+
+	// Dual-key decryption keeps old ciphertext readable
+	if got, err := newCS.DecryptFromStorage(oldValue); err != nil || got != "old-secret" {
+		t.Fatalf("dual-key decrypt failed: %q, %v", got, err)
+	}
+
+	src := &memorySource{values: map[SecretRef]string{
+		{Table: "exchanges", Column: "api_key", ID: "1"}:    oldValue,
+		{Table: "exchanges", Column: "secret_key", ID: "1"}: currentValue,
+		{Table: "ai_models", Column: "api_key", ID: "2"}:    "plain-secret",
+	}}
+
+	rotator := NewKeyRotator(newCS, src)
+	before, err := rotator.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.OK() || before.Previous != 1 || before.Plaintext != 1 {
+		t.Fatalf("unexpected pre-rotation report: %+v", before)
+	}
+
+	progress, err := rotator.Rotate()
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if progress.State != RotationStateCompleted || !progress.Verified {
+		t.Fatalf("unexpected progress: %+v", progress)
+	}
+	if progress.Total != 3 || progress.Reencrypted != 2 || progress.Skipped != 1 {
+		t.Fatalf("unexpected counts: %+v", progress)
+	}
+
+	// After rotation the old key is no longer needed
+	fresh := &CryptoService{privateKey: newCS.privateKey, publicKey: newCS.publicKey, dataKey: newCS.dataKey}
+	for ref, v := range src.values {
+		if _, err := fresh.DecryptFromStorage(v); err != nil {
+			t.Fatalf("%v not readable with current key only: %v", ref, err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package hook
+
+import "log"
+
+type PreDecisionResult struct {
+	Err    error
+	Skip   bool   // Skip this cycle's AI decision
+	Reason string // Why the cycle was skipped (recorded in the decision log)
+}
+
+func (r *PreDecisionResult) Error() error {
+	if r.Err != nil {
+		log.Printf("⚠️ Error executing PreDecisionResult: %v", r.Err)
+	}
+	return r.Err
+}
+
+type PostDecisionResult struct {
+	Err   error
+	Notes []string // Annotations recorded in the decision log
+}
+
+func (r *PostDecisionResult) Error() error {
+	if r.Err != nil {
+		log.Printf("⚠️ Error executing PostDecisionResult: %v", r.Err)
+	}
+	return r.Err
+}
+
+func (r *PostDecisionResult) GetResult() []string {
+	r.Error()
+	return r.Notes
+}
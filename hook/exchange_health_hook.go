@@ -0,0 +1,14 @@
+package hook
+
+import "log"
+
+type ExchangeHealthAlertResult struct {
+	Err error
+}
+
+func (r *ExchangeHealthAlertResult) Error() error {
+	if r.Err != nil {
+		log.Printf("⚠️ Error executing ExchangeHealthAlertResult: %v", r.Err)
+	}
+	return r.Err
+}
@@ -37,4 +37,9 @@ const (
 	NEW_BINANCE_TRADER = "NEW_BINANCE_TRADER" // func (userID string, client *futures.Client) *NewBinanceTraderResult
 	NEW_ASTER_TRADER   = "NEW_ASTER_TRADER"   // func (userID string, client *http.Client) *NewAsterTraderResult
 	SET_HTTP_CLIENT    = "SET_HTTP_CLIENT"    // func (client *http.Client) *SetHttpClientResult
+
+	EXCHANGE_HEALTH_ALERT = "EXCHANGE_HEALTH_ALERT" // func (userID, exchangeID, status, message string) *ExchangeHealthAlertResult
+
+	PRE_DECISION  = "PRE_DECISION"  // func (traderID string, ctx *kernel.Context) *PreDecisionResult
+	POST_DECISION = "POST_DECISION" // func (traderID string, decisions *[]kernel.Decision) *PostDecisionResult (edit or remove decisions in place)
 )
@@ -0,0 +1,66 @@
+package kernel
+
+import (
+	"nofx/store"
+	"testing"
+)
+
+type stubCoinPool struct {
+	pools       map[string][]string
+	pinned      []string
+	blacklisted []string
+}
+
+func (s *stubCoinPool) CustomPoolSymbols(poolID string) ([]string, error) {
+	return s.pools[poolID], nil
+}
+
+func (s *stubCoinPool) TraderOverrides() ([]string, []string, error) {
+	return s.pinned, s.blacklisted, nil
+}
+
+func TestGetCandidateCoins_CustomPoolWithOverrides(t *testing.T) {
+	engine := NewStrategyEngine(&store.StrategyConfig{
+		CoinSource: store.CoinSourceConfig{
+			SourceType:    "custom",
+			CustomPoolID:  "pool1",
+			ExcludedCoins: []string{"DOGEUSDT"},
+		},
+	})
+	engine.SetCoinPoolSource(&stubCoinPool{
+		pools:       map[string][]string{"pool1": {"BTCUSDT", "ETHUSDT", "SOLUSDT", "DOGEUSDT"}},
+		pinned:      []string{"sol", "XRPUSDT", "ETHUSDT"},
+		blacklisted: []string{"ETHUSDT"},
+	})
+
+	coins, err := engine.GetCandidateCoins()
+	if err != nil {
+		t.Fatalf("GetCandidateCoins: %v", err)
+	}
+
+	var got []string
+	for _, c := range coins {
+		got = append(got, c.Symbol)
+	}
+	want := []string{"SOLUSDT", "XRPUSDT", "BTCUSDT"}
+	if len(got) != len(want) {
+		t.Fatalf("candidates = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("candidates = %v, want %v", got, want)
+		}
+	}
+	if len(coins[0].Sources) != 2 || coins[0].Sources[0] != "pinned" || coins[0].Sources[1] != "custom" {
+		t.Errorf("pinned pool coin sources = %v", coins[0].Sources)
+	}
+}
+
+func TestGetCandidateCoins_CustomPoolRequiresSource(t *testing.T) {
+	engine := NewStrategyEngine(&store.StrategyConfig{
+		CoinSource: store.CoinSourceConfig{SourceType: "custom", CustomPoolID: "pool1"},
+	})
+	if _, err := engine.GetCandidateCoins(); err == nil {
+		t.Fatal("expected error without coin pool source")
+	}
+}
@@ -0,0 +1,75 @@
+package kernel
+
+import (
+	"fmt"
+	"math"
+	"nofx/logger"
+)
+
+// ============================================================================
+// Inverse (Coin-Margined) Contracts
+// ============================================================================
+// Traders on inverse contracts hold their balance, margin and PnL in the base
+// coin (e.g. BTC) and trade whole USD-denominated contracts. The account in the
+// context stays in the margin coin; sizing and position value caps work in USD
+// through EquityUSD. Opening decisions are checked against the contracts the
+// account can trade and rounded down to whole contracts.
+// ============================================================================
+
+// ContractInfo contract terms of a coin-margined (inverse) trader, nil for USDT-margined traders
+type ContractInfo struct {
+	MarginAsset   string             `json:"margin_asset"`   // Coin balances, margin and PnL are in (e.g. BTC)
+	AssetPrice    float64            `json:"asset_price"`    // USD price of MarginAsset
+	ContractSizes map[string]float64 `json:"contract_sizes"` // Tradable symbols -> USD face value of one contract
+}
+
+// Inverse reports whether the context is of an inverse contract trader
+func (ctx *Context) Inverse() bool {
+	return ctx.Contract != nil
+}
+
+// EquityUSD total equity in USD (converted from the margin coin for inverse contract traders)
+func (ctx *Context) EquityUSD() float64 {
+	if ctx.Contract == nil || ctx.Contract.AssetPrice <= 0 {
+		return ctx.Account.TotalEquity
+	}
+	return ctx.Account.TotalEquity * ctx.Contract.AssetPrice
+}
+
+// applyContractRules adapts opening decisions of an inverse trader to its contracts: symbols without a
+// contract margined in the account's coin and limit entries become wait, and position sizes are rounded
+// down to whole contracts (wait when below one)
+func applyContractRules(decisions []Decision, contract *ContractInfo) {
+	if contract == nil {
+		return
+	}
+	for i := range decisions {
+		d := &decisions[i]
+		if !d.IsOpen() {
+			continue
+		}
+
+		size, ok := contract.ContractSizes[d.Symbol]
+		var reason string
+		switch {
+		case !ok || size <= 0:
+			reason = fmt.Sprintf("no inverse contract margined in %s for %s", contract.MarginAsset, d.Symbol)
+		case d.IsLimitEntry():
+			reason = "limit entries are not supported on inverse contracts"
+		case d.PositionSizeUSD < size:
+			reason = fmt.Sprintf("position size %.2f USD is below one contract (%.0f USD)", d.PositionSizeUSD, size)
+		}
+		if reason != "" {
+			logger.Warnf("🪙 [Inverse Contract] %s %s rejected: %s", d.Symbol, d.Action, reason)
+			d.Reasoning = fmt.Sprintf("[rejected %s: %s] %s", d.Action, reason, d.Reasoning)
+			d.Action = "wait"
+			continue
+		}
+
+		if rounded := math.Floor(d.PositionSizeUSD/size) * size; rounded != d.PositionSizeUSD {
+			logger.Infof("🪙 [Inverse Contract] %s position size %.2f USD rounded down to %.0f contracts (%.0f USD)",
+				d.Symbol, d.PositionSizeUSD, rounded/size, rounded)
+			d.PositionSizeUSD = rounded
+		}
+	}
+}
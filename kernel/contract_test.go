@@ -0,0 +1,79 @@
+package kernel
+
+import (
+	"math"
+	"nofx/market"
+	"strings"
+	"testing"
+)
+
+func TestInverseLiquidationPrice(t *testing.T) {
+	// Coin-margined 10x long at 100 liquidates later than linear (~8.7% below vs ~9.6%)
+	if got := inverseLiquidationPrice(100, 10, true, 0.004); math.Abs(got-91.2727) > 1e-3 {
+		t.Errorf("long liquidation = %.4f, want 91.2727", got)
+	}
+	if got := inverseLiquidationPrice(100, 10, false, 0.004); math.Abs(got-110.6667) > 1e-3 {
+		t.Errorf("short liquidation = %.4f, want 110.6667", got)
+	}
+	// The coin collateral of a 1x short gains value as fast as the position loses
+	if got := inverseLiquidationPrice(100, 1, false, 0.004); !math.IsInf(got, 1) {
+		t.Errorf("1x short should never liquidate, got %.4f", got)
+	}
+}
+
+func TestApplyLiquidationGuard_Inverse(t *testing.T) {
+	marketData := map[string]*market.Data{"SOLUSDT": {Symbol: "SOLUSDT", CurrentPrice: 100}}
+	decisions := []Decision{{Symbol: "SOLUSDT", Action: "open_short", Leverage: 20, StopLoss: 108, TakeProfit: 80}}
+
+	applyLiquidationGuard(decisions, "binance", marketData, 0, true)
+
+	// Linear margin allows 9x here, coin margin liquidates further away
+	if decisions[0].Leverage != 10 {
+		t.Errorf("inverse short leverage should be lowered to 10x, got %dx", decisions[0].Leverage)
+	}
+	if liq := inverseLiquidationPrice(100, decisions[0].Leverage, false, 0.01); liq < 109 {
+		t.Errorf("adjusted short liquidates at %.4f, inside stop loss + buffer", liq)
+	}
+}
+
+func TestApplyContractRules(t *testing.T) {
+	contract := &ContractInfo{MarginAsset: "BTC", AssetPrice: 60000, ContractSizes: map[string]float64{"BTCUSDT": 100}}
+	decisions := []Decision{
+		{Symbol: "BTCUSDT", Action: "open_long", PositionSizeUSD: 1050},
+		{Symbol: "ETHUSDT", Action: "open_short", PositionSizeUSD: 1000},
+		{Symbol: "BTCUSDT", Action: "open_short", PositionSizeUSD: 50},
+		{Symbol: "BTCUSDT", Action: "open_long_limit", Price: 59000, PositionSizeUSD: 1000},
+		{Symbol: "ETHUSDT", Action: "close_long"},
+	}
+
+	applyContractRules(decisions, contract)
+
+	if decisions[0].Action != "open_long" || decisions[0].PositionSizeUSD != 1000 {
+		t.Errorf("size should be rounded down to 10 contracts, got %s %.2f", decisions[0].Action, decisions[0].PositionSizeUSD)
+	}
+	for i, want := range map[int]string{1: "no inverse contract", 2: "below one contract", 3: "limit entries"} {
+		if decisions[i].Action != "wait" || !strings.Contains(decisions[i].Reasoning, want) {
+			t.Errorf("decision %d should be rejected (%s), got %s: %s", i, want, decisions[i].Action, decisions[i].Reasoning)
+		}
+	}
+	if decisions[4].Action != "close_long" {
+		t.Errorf("close decisions should be unchanged, got %s", decisions[4].Action)
+	}
+
+	linear := []Decision{{Symbol: "ETHUSDT", Action: "open_long", PositionSizeUSD: 1050}}
+	applyContractRules(linear, nil)
+	if linear[0].Action != "open_long" || linear[0].PositionSizeUSD != 1050 {
+		t.Errorf("linear decisions should be unchanged, got %s %.2f", linear[0].Action, linear[0].PositionSizeUSD)
+	}
+}
+
+func TestContextEquityUSD(t *testing.T) {
+	ctx := &Context{Account: AccountInfo{TotalEquity: 0.5}}
+	if got := ctx.EquityUSD(); got != 0.5 {
+		t.Errorf("linear equity should be unchanged, got %.2f", got)
+	}
+	ctx.Contract = &ContractInfo{MarginAsset: "BTC", AssetPrice: 60000}
+	if got := ctx.EquityUSD(); got != 30000 {
+		t.Errorf("inverse equity = %.2f USD, want 30000", got)
+	}
+}
@@ -0,0 +1,119 @@
+package kernel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"nofx/logger"
+	"nofx/mcp"
+	"strings"
+)
+
+// ============================================================================
+// Structured Decision Output
+// ============================================================================
+// Providers with structured output / function calling return the decisions as
+// a JSON object conforming to decisionOutputSchema, so no text extraction is
+// needed. Providers without it (or a failed structured call) use the plain
+// text response and the tag / fence parser.
+// ============================================================================
+
+// structuredDecisionOutput JSON object returned by a schema-constrained call
+type structuredDecisionOutput struct {
+	Reasoning string     `json:"reasoning"`
+	Decisions []Decision `json:"decisions"`
+}
+
+// decisionActions actions accepted by validateDecision
+var decisionActions = []string{
+	"open_long", "open_short", "open_long_limit", "open_short_limit",
+	"close_long", "close_short", "hold", "wait",
+}
+
+// decisionOutputSchema formal JSON schema of the trading decision output
+var decisionOutputSchema = &mcp.OutputSchema{
+	Name:        "submit_trading_decisions",
+	Description: "Submit the chain of thought and the trading decisions of this cycle",
+	Schema: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"reasoning": map[string]any{
+				"type":        "string",
+				"description": "Chain of thought analysis behind the decisions",
+			},
+			"decisions": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"symbol":            map[string]any{"type": "string", "description": "Trading pair, e.g. BTCUSDT"},
+						"action":            map[string]any{"type": "string", "enum": decisionActions},
+						"leverage":          map[string]any{"type": "integer", "minimum": 1, "description": "Required for opening actions"},
+						"position_size_usd": map[string]any{"type": "number", "minimum": 0, "description": "Position notional in USDT, required for opening actions"},
+						"stop_loss":         map[string]any{"type": "number", "minimum": 0},
+						"take_profit":       map[string]any{"type": "number", "minimum": 0},
+						"take_profit_levels": map[string]any{
+							"type":        "array",
+							"description": "Optional partial take profits of market entries, each closing close_pct % of the position",
+							"items": map[string]any{
+								"type": "object",
+								"properties": map[string]any{
+									"price":     map[string]any{"type": "number", "minimum": 0},
+									"close_pct": map[string]any{"type": "number", "minimum": 0, "maximum": 100},
+								},
+								"required": []string{"price", "close_pct"},
+							},
+						},
+						"trailing_stop_pct": map[string]any{"type": "number", "minimum": 0, "description": "Trailing stop distance (%) of the part left open after the take profit levels"},
+						"price":             map[string]any{"type": "number", "minimum": 0, "description": "Limit price of open_long_limit / open_short_limit"},
+						"expiry_minutes":    map[string]any{"type": "integer", "minimum": 0, "description": "Cancel an unfilled limit entry after this many minutes"},
+						"confidence":        map[string]any{"type": "integer", "minimum": 0, "maximum": 100},
+						"risk_usd":          map[string]any{"type": "number", "minimum": 0},
+						"reasoning":         map[string]any{"type": "string"},
+					},
+					"required": []string{"symbol", "action", "reasoning"},
+				},
+			},
+		},
+		"required": []string{"reasoning", "decisions"},
+	},
+}
+
+// callAIForDecisions calls the AI, preferring schema-constrained output when the client supports it
+// structured reports whether the response is a decisionOutputSchema JSON object
+func callAIForDecisions(mcpClient mcp.AIClient, systemPrompt, userPrompt string) (response string, structured bool, err error) {
+	if caller, ok := mcpClient.(mcp.StructuredCaller); ok {
+		response, err = caller.CallWithSchema(systemPrompt, userPrompt, decisionOutputSchema)
+		if err == nil {
+			return response, true, nil
+		}
+		if !errors.Is(err, mcp.ErrStructuredOutputUnsupported) {
+			logger.Warnf("⚠️  Structured output call failed, falling back to text response: %v", err)
+		}
+	}
+	response, err = mcpClient.CallWithMessages(systemPrompt, userPrompt)
+	return response, false, err
+}
+
+// parseStructuredDecisionResponse parses and validates a decisionOutputSchema JSON object
+// Falls back to the text parser when the object doesn't decode (e.g. a provider ignoring the schema)
+func parseStructuredDecisionResponse(aiResponse string, accountEquity float64, btcEthLeverage, altcoinLeverage int, btcEthPosRatio, altcoinPosRatio, minRiskReward float64) (*FullDecision, error) {
+	var output structuredDecisionOutput
+	if err := json.Unmarshal([]byte(strings.TrimSpace(aiResponse)), &output); err != nil {
+		logger.Warnf("⚠️  Structured output doesn't match the decision schema (%v), using text parser", err)
+		return parseFullDecisionResponse(aiResponse, accountEquity, btcEthLeverage, altcoinLeverage, btcEthPosRatio, altcoinPosRatio, minRiskReward)
+	}
+	logger.Infof("✓ Parsed %d decisions from structured output", len(output.Decisions))
+
+	decision := &FullDecision{
+		CoTTrace:  strings.TrimSpace(output.Reasoning),
+		Decisions: output.Decisions,
+	}
+	if decision.Decisions == nil {
+		decision.Decisions = []Decision{}
+	}
+	if err := validateDecisions(decision.Decisions, accountEquity, btcEthLeverage, altcoinLeverage, btcEthPosRatio, altcoinPosRatio, minRiskReward); err != nil {
+		return decision, fmt.Errorf("decision validation failed: %w", err)
+	}
+	return decision, nil
+}
@@ -0,0 +1,72 @@
+package kernel
+
+import (
+	"errors"
+	"nofx/mcp"
+	"testing"
+	"time"
+)
+
+// schemaStubClient AI client with optional structured output
+type schemaStubClient struct {
+	structured    string
+	structuredErr error
+	text          string
+	textCalls     int
+}
+
+func (c *schemaStubClient) SetAPIKey(apiKey, customURL, customModel string) {}
+func (c *schemaStubClient) SetTimeout(timeout time.Duration)                {}
+func (c *schemaStubClient) CallWithRequest(req *mcp.Request) (string, error) {
+	return c.text, nil
+}
+func (c *schemaStubClient) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	c.textCalls++
+	return c.text, nil
+}
+func (c *schemaStubClient) CallWithSchema(systemPrompt, userPrompt string, schema *mcp.OutputSchema) (string, error) {
+	return c.structured, c.structuredErr
+}
+
+func TestCallAIForDecisions(t *testing.T) {
+	client := &schemaStubClient{structured: `{"reasoning":"r","decisions":[]}`, text: "text"}
+	response, structured, err := callAIForDecisions(client, "system", "user")
+	if err != nil || !structured || response != client.structured || client.textCalls != 0 {
+		t.Errorf("structured call should be used, got %q structured=%v err=%v", response, structured, err)
+	}
+
+	client = &schemaStubClient{structuredErr: mcp.ErrStructuredOutputUnsupported, text: "text"}
+	response, structured, err = callAIForDecisions(client, "system", "user")
+	if err != nil || structured || response != "text" || client.textCalls != 1 {
+		t.Errorf("unsupported provider should fall back to text, got %q structured=%v err=%v", response, structured, err)
+	}
+
+	client = &schemaStubClient{structuredErr: errors.New("status 400"), text: "text"}
+	if _, structured, _ = callAIForDecisions(client, "system", "user"); structured || client.textCalls != 1 {
+		t.Error("failed structured call should fall back to text")
+	}
+}
+
+func TestParseStructuredDecisionResponse(t *testing.T) {
+	response := `{"reasoning":"BTC breaks out","decisions":[
+		{"symbol":"BTCUSDT","action":"open_long","leverage":5,"position_size_usd":1000,"stop_loss":90000,"take_profit":110000,"confidence":80,"reasoning":"breakout"},
+		{"symbol":"ETHUSDT","action":"wait","reasoning":"no setup"}]}`
+
+	decision, err := parseStructuredDecisionResponse(response, 1000, 10, 5, 5, 1, 3)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if decision.CoTTrace != "BTC breaks out" || len(decision.Decisions) != 2 {
+		t.Fatalf("unexpected decision: %+v", decision)
+	}
+	if d := decision.Decisions[0]; d.Symbol != "BTCUSDT" || d.Leverage != 5 || d.PositionSizeUSD != 1000 {
+		t.Errorf("unexpected first decision: %+v", d)
+	}
+
+	// A response that ignores the schema still goes through the text parser
+	text := "<reasoning>flat</reasoning><decision>```json\n[{\"symbol\":\"BTCUSDT\",\"action\":\"wait\",\"reasoning\":\"flat\"}]\n```</decision>"
+	decision, err = parseStructuredDecisionResponse(text, 1000, 10, 5, 5, 1, 3)
+	if err != nil || len(decision.Decisions) != 1 || decision.Decisions[0].Action != "wait" {
+		t.Errorf("text fallback failed: %+v, %v", decision, err)
+	}
+}
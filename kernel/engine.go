@@ -4,15 +4,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"nofx/logger"
 	"nofx/market"
 	"nofx/mcp"
+	"nofx/provider/news"
 	"nofx/provider/nofxos"
+	"nofx/provider/sentiment"
 	"nofx/security"
 	"nofx/store"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -51,6 +56,8 @@ type PositionInfo struct {
 	LiquidationPrice float64 `json:"liquidation_price"`
 	MarginUsed       float64 `json:"margin_used"`
 	UpdateTime       int64   `json:"update_time"` // Position update timestamp (milliseconds)
+	FundingRate      float64 `json:"funding_rate,omitempty"`
+	NextFundingTime  int64   `json:"next_funding_time,omitempty"` // Next funding settlement (milliseconds), 0 if unknown
 }
 
 // AccountInfo account information
@@ -104,33 +111,53 @@ type RecentOrder struct {
 	HoldDuration string  `json:"hold_duration"` // Hold duration, e.g. "2h30m"
 }
 
+// Reflection the AI's own review of a closed trade (for AI input)
+type Reflection struct {
+	Symbol string  `json:"symbol"`
+	Side   string  `json:"side"`    // long/short
+	PnLPct float64 `json:"pnl_pct"` // Outcome of the trade
+	Reason string  `json:"reason"`  // How it closed: ai, drawdown, stop_loss, take_profit, exchange
+	Time   string  `json:"time"`    // Close time
+	Text   string  `json:"text"`    // Lesson, length-limited
+}
+
 // Context trading context (complete information passed to AI)
 type Context struct {
-	CurrentTime     string                             `json:"current_time"`
-	RuntimeMinutes  int                                `json:"runtime_minutes"`
-	CallCount       int                                `json:"call_count"`
-	Account         AccountInfo                        `json:"account"`
-	Positions       []PositionInfo                     `json:"positions"`
-	CandidateCoins  []CandidateCoin                    `json:"candidate_coins"`
-	PromptVariant   string                             `json:"prompt_variant,omitempty"`
-	TradingStats    *TradingStats                      `json:"trading_stats,omitempty"`
-	RecentOrders    []RecentOrder                      `json:"recent_orders,omitempty"`
-	MarketDataMap   map[string]*market.Data            `json:"-"`
-	MultiTFMarket   map[string]map[string]*market.Data `json:"-"`
-	OITopDataMap    map[string]*OITopData              `json:"-"`
-	QuantDataMap    map[string]*QuantData              `json:"-"`
-	OIRankingData      *nofxos.OIRankingData      `json:"-"` // Market-wide OI ranking data
-	NetFlowRankingData *nofxos.NetFlowRankingData `json:"-"` // Market-wide fund flow ranking data
-	PriceRankingData   *nofxos.PriceRankingData   `json:"-"` // Market-wide price gainers/losers
-	BTCETHLeverage     int                          `json:"-"`
-	AltcoinLeverage int                                `json:"-"`
-	Timeframes      []string                           `json:"-"`
+	CurrentTime        string                             `json:"current_time"`
+	RuntimeMinutes     int                                `json:"runtime_minutes"`
+	CallCount          int                                `json:"call_count"`
+	Account            AccountInfo                        `json:"account"`
+	Positions          []PositionInfo                     `json:"positions"`
+	CandidateCoins     []CandidateCoin                    `json:"candidate_coins"`
+	PromptVariant      string                             `json:"prompt_variant,omitempty"`
+	TradingStats       *TradingStats                      `json:"trading_stats,omitempty"`
+	RecentOrders       []RecentOrder                      `json:"recent_orders,omitempty"`
+	Reflections        []Reflection                       `json:"reflections,omitempty"` // Newest first
+	PendingOrders      []PendingOrder                     `json:"pending_orders,omitempty"`
+	MarketDataMap      map[string]*market.Data            `json:"-"`
+	MultiTFMarket      map[string]map[string]*market.Data `json:"-"`
+	OITopDataMap       map[string]*OITopData              `json:"-"`
+	QuantDataMap       map[string]*QuantData              `json:"-"`
+	OrderBookMap       map[string]*market.DepthMetrics    `json:"-"` // Order book liquidity of candidate coins
+	News               *news.Snapshot                     `json:"-"` // Headlines and economic calendar (nil: disabled)
+	Sentiment          *sentiment.Snapshot                `json:"-"` // Fear & Greed index and social sentiment (nil: disabled)
+	OIRankingData      *nofxos.OIRankingData              `json:"-"` // Market-wide OI ranking data
+	NetFlowRankingData *nofxos.NetFlowRankingData         `json:"-"` // Market-wide fund flow ranking data
+	PriceRankingData   *nofxos.PriceRankingData           `json:"-"` // Market-wide price gainers/losers
+	BTCETHLeverage     int                                `json:"-"`
+	AltcoinLeverage    int                                `json:"-"`
+	Timeframes         []string                           `json:"-"`
+	Regimes            map[string]*market.RegimeInfo      `json:"-"` // Market regime per symbol (scales leverage/position caps)
+	Exchange           string                             `json:"-"` // Exchange type, selects the margin model of the liquidation guard
+	Contract           *ContractInfo                      `json:"-"` // Inverse contract terms (nil: USDT-margined linear contracts)
+	MarketDataSkipped  map[string]string                  `json:"-"` // Symbols whose market data failed or timed out this cycle, with reason
 }
 
 // Decision AI trading decision
 type Decision struct {
 	Symbol string `json:"symbol"`
 	Action string `json:"action"` // Standard: "open_long", "open_short", "close_long", "close_short", "hold", "wait"
+	// Limit entry: "open_long_limit", "open_short_limit" (uses Price and ExpiryMinutes)
 	// Grid actions: "place_buy_limit", "place_sell_limit", "cancel_order", "cancel_all_orders", "pause_grid", "resume_grid", "adjust_grid"
 
 	// Opening position parameters
@@ -138,9 +165,15 @@ type Decision struct {
 	PositionSizeUSD float64 `json:"position_size_usd,omitempty"`
 	StopLoss        float64 `json:"stop_loss,omitempty"`
 	TakeProfit      float64 `json:"take_profit,omitempty"`
+	// Partial take profits of market entries (replace TakeProfit), the rest runs on with TrailingStopPct
+	TakeProfitLevels []TakeProfitLevel `json:"take_profit_levels,omitempty"`
+	TrailingStopPct  float64           `json:"trailing_stop_pct,omitempty"` // Runner stop distance from the best price, 0 = fixed stop loss
+
+	// Limit entry parameters
+	ExpiryMinutes int `json:"expiry_minutes,omitempty"` // Cancel unfilled limit entry after this many minutes
 
 	// Grid trading parameters
-	Price      float64 `json:"price,omitempty"`       // Limit order price (for grid)
+	Price      float64 `json:"price,omitempty"`       // Limit order price (for grid and limit entries)
 	Quantity   float64 `json:"quantity,omitempty"`    // Order quantity (for grid)
 	LevelIndex int     `json:"level_index,omitempty"` // Grid level index
 	OrderID    string  `json:"order_id,omitempty"`    // Order ID (for cancel)
@@ -151,6 +184,44 @@ type Decision struct {
 	Reasoning  string  `json:"reasoning"`
 }
 
+// TakeProfitLevel one step of a partial take-profit ladder
+type TakeProfitLevel struct {
+	Price    float64 `json:"price"`
+	ClosePct float64 `json:"close_pct"` // % of the opened quantity closed at Price
+}
+
+// Limit entry expiry bounds (minutes)
+const (
+	DefaultLimitEntryExpiryMinutes = 60
+	MaxLimitEntryExpiryMinutes     = 24 * 60
+)
+
+// IsLimitEntry returns whether the action opens a position with a limit order
+func (d *Decision) IsLimitEntry() bool {
+	return d.Action == "open_long_limit" || d.Action == "open_short_limit"
+}
+
+// IsOpen returns whether the action opens a position (market or limit)
+func (d *Decision) IsOpen() bool {
+	return d.Action == "open_long" || d.Action == "open_short" || d.IsLimitEntry()
+}
+
+// IsLong returns whether the opening action is long
+func (d *Decision) IsLong() bool {
+	return d.Action == "open_long" || d.Action == "open_long_limit"
+}
+
+// PendingOrder unfilled AI limit entry carried across cycles
+type PendingOrder struct {
+	Symbol          string  `json:"symbol"`
+	Side            string  `json:"side"` // long/short
+	Price           float64 `json:"price"`
+	Quantity        float64 `json:"quantity"`
+	StopLoss        float64 `json:"stop_loss"`
+	TakeProfit      float64 `json:"take_profit"`
+	MinutesToExpiry int     `json:"minutes_to_expiry"`
+}
+
 // FullDecision AI's complete decision (including chain of thought)
 type FullDecision struct {
 	SystemPrompt        string     `json:"system_prompt"`
@@ -160,6 +231,7 @@ type FullDecision struct {
 	RawResponse         string     `json:"raw_response"`
 	Timestamp           time.Time  `json:"timestamp"`
 	AIRequestDurationMs int64      `json:"ai_request_duration_ms,omitempty"`
+	PromptTrimmed       string     `json:"prompt_trimmed,omitempty"` // What was cut to fit the prompt token budget
 }
 
 // QuantData quantitative data structure (fund flow, position changes, price changes)
@@ -200,6 +272,20 @@ type OIDeltaData struct {
 type StrategyEngine struct {
 	config       *store.StrategyConfig
 	nofxosClient *nofxos.Client
+	coinPool     CoinPoolSource // Optional: custom pools and per-trader pin/blacklist
+	warmup       *marketWarmup  // Pending cold-start prefetch (nil: none)
+	warmupMu     sync.Mutex
+}
+
+// CoinPoolSource resolves user-defined coin pools and per-trader overrides
+type CoinPoolSource interface {
+	CustomPoolSymbols(poolID string) ([]string, error)
+	TraderOverrides() (pinned, blacklisted []string, err error)
+}
+
+// SetCoinPoolSource sets the source for custom pools and trader pin/blacklist overrides
+func (e *StrategyEngine) SetCoinPoolSource(source CoinPoolSource) {
+	e.coinPool = source
 }
 
 // NewStrategyEngine creates strategy execution engine
@@ -224,15 +310,11 @@ func (e *StrategyEngine) GetRiskControlConfig() store.RiskControlConfig {
 
 // GetLanguage returns the language from config or falls back to auto-detection
 func (e *StrategyEngine) GetLanguage() Language {
-	switch e.config.Language {
-	case "zh":
-		return LangChinese
-	case "en":
-		return LangEnglish
-	default:
-		// Fall back to auto-detection from prompt content for backward compatibility
-		return detectLanguage(e.config.PromptSections.RoleDefinition)
+	if lang, ok := ParseLanguage(e.config.Language); ok {
+		return lang
 	}
+	// Fall back to auto-detection from prompt content for backward compatibility
+	return detectLanguage(e.config.PromptSections.RoleDefinition)
 }
 
 // GetConfig gets complete strategy configuration
@@ -287,27 +369,35 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 
 	// 2. Build System Prompt using strategy engine
 	riskConfig := engine.GetRiskControlConfig()
-	systemPrompt := engine.BuildSystemPrompt(ctx.Account.TotalEquity, variant)
+	systemPrompt := engine.BuildSystemPromptWithRegimes(ctx.EquityUSD(), variant, ctx.Regimes)
 
-	// 3. Build User Prompt using strategy engine
-	userPrompt := engine.BuildUserPrompt(ctx)
+	// 3. Build User Prompt using strategy engine, trimmed to the model's token budget
+	userPrompt, trimmed := engine.buildUserPromptWithinBudget(ctx, systemPrompt, engine.PromptTokenBudget(mcpClient))
+	if trimmed != "" {
+		logger.Warnf("✂️ %s", trimmed)
+	}
 
 	// 4. Call AI API
 	aiCallStart := time.Now()
-	aiResponse, err := mcpClient.CallWithMessages(systemPrompt, userPrompt)
+	aiResponse, structured, err := callAIForDecisions(mcpClient, systemPrompt, userPrompt)
 	aiCallDuration := time.Since(aiCallStart)
 	if err != nil {
 		return nil, fmt.Errorf("AI API call failed: %w", err)
 	}
 
-	// 5. Parse AI response
-	decision, err := parseFullDecisionResponse(
+	// 5. Parse AI response (schema-constrained JSON, or free text)
+	parse := parseFullDecisionResponse
+	if structured {
+		parse = parseStructuredDecisionResponse
+	}
+	decision, err := parse(
 		aiResponse,
-		ctx.Account.TotalEquity,
+		ctx.EquityUSD(),
 		riskConfig.BTCETHMaxLeverage,
 		riskConfig.AltcoinMaxLeverage,
 		riskConfig.BTCETHMaxPositionValueRatio,
 		riskConfig.AltcoinMaxPositionValueRatio,
+		minRiskRewardRatio(riskConfig),
 	)
 
 	if decision != nil {
@@ -316,11 +406,17 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 		decision.UserPrompt = userPrompt
 		decision.AIRequestDurationMs = aiCallDuration.Milliseconds()
 		decision.RawResponse = aiResponse
+		decision.PromptTrimmed = trimmed
 	}
 
 	if err != nil {
 		return decision, fmt.Errorf("failed to parse AI response: %w", err)
 	}
+	_, primaryTimeframe, _ := engine.klineSettings()
+	applyRiskSizing(decision.Decisions, ctx.EquityUSD(), ctx.MarketDataMap, riskConfig, primaryTimeframe)
+	applyRegimeLimits(decision.Decisions, ctx.EquityUSD(), ctx.Regimes, riskConfig)
+	applyLiquidationGuard(decision.Decisions, ctx.Exchange, ctx.MarketDataMap, riskConfig.LiquidationBufferPct, ctx.Inverse())
+	applyContractRules(decision.Decisions, ctx.Contract)
 
 	return decision, nil
 }
@@ -329,78 +425,98 @@ func GetFullDecisionWithStrategy(ctx *Context, mcpClient mcp.AIClient, engine *S
 // Market Data Fetching
 // ============================================================================
 
+// DefaultMinOIValueMillions candidate coins with less open interest value (million USD) are skipped
+const DefaultMinOIValueMillions = 15.0
+
+var (
+	minOIValueMu       sync.RWMutex
+	minOIValueMillions = DefaultMinOIValueMillions
+)
+
+// SetMinOIValueMillions sets the liquidity filter of candidate coins, 0 disables it (negative restores the default)
+// Safe to call while traders run, the next cycle uses it.
+func SetMinOIValueMillions(millions float64) {
+	if millions < 0 {
+		millions = DefaultMinOIValueMillions
+	}
+	minOIValueMu.Lock()
+	defer minOIValueMu.Unlock()
+	minOIValueMillions = millions
+}
+
+// MinOIValueMillions current liquidity filter of candidate coins
+func MinOIValueMillions() float64 {
+	minOIValueMu.RLock()
+	defer minOIValueMu.RUnlock()
+	return minOIValueMillions
+}
+
 // fetchMarketDataWithStrategy fetches market data using strategy config (multiple timeframes)
 func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
-	config := engine.GetConfig()
 	ctx.MarketDataMap = make(map[string]*market.Data)
 
-	timeframes := config.Indicators.Klines.SelectedTimeframes
-	primaryTimeframe := config.Indicators.Klines.PrimaryTimeframe
-	klineCount := config.Indicators.Klines.PrimaryCount
+	timeframes, primaryTimeframe, klineCount := engine.klineSettings()
+	logger.Infof("📊 Strategy timeframes: %v, Primary: %s, Kline count: %d", timeframes, primaryTimeframe, klineCount)
 
-	// Compatible with old configuration
-	if len(timeframes) == 0 {
-		if primaryTimeframe != "" {
-			timeframes = append(timeframes, primaryTimeframe)
-		} else {
-			timeframes = append(timeframes, "3m")
-		}
-		if config.Indicators.Klines.LongerTimeframe != "" {
-			timeframes = append(timeframes, config.Indicators.Klines.LongerTimeframe)
+	// Position coins (must fetch) first, then candidate coins
+	positionSymbols := make(map[string]bool)
+	symbols := make([]string, 0, len(ctx.Positions)+len(ctx.CandidateCoins))
+	for _, pos := range ctx.Positions {
+		if !positionSymbols[pos.Symbol] {
+			positionSymbols[pos.Symbol] = true
+			symbols = append(symbols, pos.Symbol)
 		}
 	}
-	if primaryTimeframe == "" {
-		primaryTimeframe = timeframes[0]
-	}
-	if klineCount <= 0 {
-		klineCount = 30
+	seen := make(map[string]bool, len(ctx.CandidateCoins))
+	for _, coin := range ctx.CandidateCoins {
+		if !positionSymbols[coin.Symbol] && !seen[coin.Symbol] {
+			seen[coin.Symbol] = true
+			symbols = append(symbols, coin.Symbol)
+		}
 	}
 
-	logger.Infof("📊 Strategy timeframes: %v, Primary: %s, Kline count: %d", timeframes, primaryTimeframe, klineCount)
-
-	// 1. First fetch data for position coins (must fetch)
-	for _, pos := range ctx.Positions {
-		data, err := market.GetWithTimeframes(pos.Symbol, timeframes, primaryTimeframe, klineCount)
-		if err != nil {
-			logger.Infof("⚠️  Failed to fetch market data for position %s: %v", pos.Symbol, err)
-			continue
+	// Reuse data of the cold-start warmup, fetch the rest concurrently
+	fetched := make(map[string]*market.Data, len(symbols))
+	warmup := engine.takeWarmup()
+	var missing []string
+	for _, symbol := range symbols {
+		if data, ok := warmup[symbol]; ok {
+			fetched[symbol] = data
+		} else {
+			missing = append(missing, symbol)
 		}
-		ctx.MarketDataMap[pos.Symbol] = data
 	}
-
-	// 2. Fetch data for all candidate coins
-	positionSymbols := make(map[string]bool)
-	for _, pos := range ctx.Positions {
-		positionSymbols[pos.Symbol] = true
+	if len(warmup) > 0 {
+		logger.Infof("🔥 Using warmup market data for %d/%d symbols", len(fetched), len(symbols))
 	}
+	data, skipped := engine.fetchMarketDataConcurrently(missing)
+	for symbol, d := range data {
+		fetched[symbol] = d
+	}
+	ctx.MarketDataSkipped = skipped
 
-	const minOIThresholdMillions = 15.0 // 15M USD minimum open interest value
-
-	for _, coin := range ctx.CandidateCoins {
-		if _, exists := ctx.MarketDataMap[coin.Symbol]; exists {
-			continue
-		}
+	minOIThresholdMillions := MinOIValueMillions()
 
-		data, err := market.GetWithTimeframes(coin.Symbol, timeframes, primaryTimeframe, klineCount)
-		if err != nil {
-			logger.Infof("⚠️  Failed to fetch market data for %s: %v", coin.Symbol, err)
+	for _, symbol := range symbols {
+		data, ok := fetched[symbol]
+		if !ok {
 			continue
 		}
 
 		// Liquidity filter (skip for xyz dex assets - they don't have OI data from Binance)
-		isExistingPosition := positionSymbols[coin.Symbol]
-		isXyzAsset := market.IsXyzDexAsset(coin.Symbol)
+		isExistingPosition := positionSymbols[symbol]
+		isXyzAsset := market.IsXyzDexAsset(symbol)
 		if !isExistingPosition && !isXyzAsset && data.OpenInterest != nil && data.CurrentPrice > 0 {
 			oiValue := data.OpenInterest.Latest * data.CurrentPrice
 			oiValueInMillions := oiValue / 1_000_000
 			if oiValueInMillions < minOIThresholdMillions {
 				logger.Infof("⚠️  %s OI value too low (%.2fM USD < %.1fM), skipping coin",
-					coin.Symbol, oiValueInMillions, minOIThresholdMillions)
+					symbol, oiValueInMillions, minOIThresholdMillions)
 				continue
 			}
 		}
 
-		ctx.MarketDataMap[coin.Symbol] = data
+		ctx.MarketDataMap[symbol] = data
 	}
 
 	logger.Infof("📊 Successfully fetched multi-timeframe market data for %d coins", len(ctx.MarketDataMap))
@@ -411,8 +527,18 @@ func fetchMarketDataWithStrategy(ctx *Context, engine *StrategyEngine) error {
 // Candidate Coins
 // ============================================================================
 
-// GetCandidateCoins gets candidate coins based on strategy configuration
+// GetCandidateCoins gets candidate coins based on strategy configuration,
+// then applies the trader's pinned / blacklisted symbols
 func (e *StrategyEngine) GetCandidateCoins() ([]CandidateCoin, error) {
+	candidates, err := e.getSourceCandidates()
+	if err != nil {
+		return nil, err
+	}
+	return e.applyCoinOverrides(candidates), nil
+}
+
+// getSourceCandidates gets candidate coins from the configured coin source
+func (e *StrategyEngine) getSourceCandidates() ([]CandidateCoin, error) {
 	var candidates []CandidateCoin
 	symbolSources := make(map[string][]string)
 
@@ -490,6 +616,13 @@ func (e *StrategyEngine) GetCandidateCoins() ([]CandidateCoin, error) {
 		// 空列表是正常情况，直接返回
 		return e.filterExcludedCoins(coins), nil
 
+	case "custom":
+		coins, err := e.getCustomPoolCoins(coinSource.CustomPoolID)
+		if err != nil {
+			return nil, err
+		}
+		return e.filterExcludedCoins(coins), nil
+
 	case "mixed":
 		if coinSource.UseAI500 {
 			poolCoins, err := e.getAI500Coins(coinSource.AI500Limit)
@@ -524,6 +657,17 @@ func (e *StrategyEngine) GetCandidateCoins() ([]CandidateCoin, error) {
 			}
 		}
 
+		if coinSource.CustomPoolID != "" {
+			customCoins, err := e.getCustomPoolCoins(coinSource.CustomPoolID)
+			if err != nil {
+				logger.Infof("⚠️  Failed to get custom pool coins: %v", err)
+			} else {
+				for _, coin := range customCoins {
+					symbolSources[coin.Symbol] = append(symbolSources[coin.Symbol], "custom")
+				}
+			}
+		}
+
 		for _, symbol := range coinSource.StaticCoins {
 			symbol = market.Normalize(symbol)
 			if _, exists := symbolSources[symbol]; !exists {
@@ -572,6 +716,79 @@ func (e *StrategyEngine) filterExcludedCoins(candidates []CandidateCoin) []Candi
 	return filtered
 }
 
+// applyCoinOverrides adds the trader's pinned symbols and removes blacklisted ones (blacklist wins)
+func (e *StrategyEngine) applyCoinOverrides(candidates []CandidateCoin) []CandidateCoin {
+	if e.coinPool == nil {
+		return candidates
+	}
+	pinned, blacklisted, err := e.coinPool.TraderOverrides()
+	if err != nil {
+		logger.Infof("⚠️  Failed to load coin overrides: %v", err)
+		return candidates
+	}
+	if len(pinned) == 0 && len(blacklisted) == 0 {
+		return candidates
+	}
+
+	blocked := make(map[string]bool, len(blacklisted))
+	for _, symbol := range blacklisted {
+		blocked[market.Normalize(symbol)] = true
+	}
+
+	// Pinned coins go first so they survive downstream candidate limits
+	result := make([]CandidateCoin, 0, len(candidates)+len(pinned))
+	pinnedSet := make(map[string]bool, len(pinned))
+	for _, symbol := range pinned {
+		symbol = market.Normalize(symbol)
+		if blocked[symbol] || pinnedSet[symbol] {
+			continue
+		}
+		pinnedSet[symbol] = true
+		sources := []string{"pinned"}
+		for _, c := range candidates {
+			if c.Symbol == symbol {
+				sources = append(sources, c.Sources...)
+				break
+			}
+		}
+		result = append(result, CandidateCoin{Symbol: symbol, Sources: sources})
+	}
+	for _, c := range candidates {
+		if blocked[c.Symbol] {
+			logger.Infof("🚫 Blacklisted coin: %s", c.Symbol)
+			continue
+		}
+		if pinnedSet[c.Symbol] {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// getCustomPoolCoins gets coins from a user-defined pool
+func (e *StrategyEngine) getCustomPoolCoins(poolID string) ([]CandidateCoin, error) {
+	if poolID == "" {
+		return nil, fmt.Errorf("custom coin source requires custom_pool_id")
+	}
+	if e.coinPool == nil {
+		return nil, fmt.Errorf("custom coin pool %s unavailable (no coin pool source)", poolID)
+	}
+	symbols, err := e.coinPool.CustomPoolSymbols(poolID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]CandidateCoin, 0, len(symbols))
+	for _, symbol := range symbols {
+		candidates = append(candidates, CandidateCoin{
+			Symbol:  market.Normalize(symbol),
+			Sources: []string{"custom"},
+		})
+	}
+	return candidates, nil
+}
+
 func (e *StrategyEngine) getAI500Coins(limit int) ([]CandidateCoin, error) {
 	if limit <= 0 {
 		limit = 30
@@ -920,12 +1137,18 @@ func (e *StrategyEngine) FetchPriceRankingData() *nofxos.PriceRankingData {
 
 // BuildSystemPrompt builds System Prompt according to strategy configuration
 func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string) string {
+	return e.BuildSystemPromptWithRegimes(accountEquity, variant, nil)
+}
+
+// BuildSystemPromptWithRegimes builds System Prompt with leverage/position caps adjusted to each symbol's market regime
+func (e *StrategyEngine) BuildSystemPromptWithRegimes(accountEquity float64, variant string, regimes map[string]*market.RegimeInfo) string {
 	var sb strings.Builder
 	riskControl := e.config.RiskControl
 	promptSections := e.config.PromptSections
 
 	// 0. Data Dictionary & Schema (ensure AI understands all fields)
 	lang := e.GetLanguage()
+	text := getPromptText(lang)
 	schemaPrompt := GetSchemaPrompt(lang)
 	sb.WriteString(schemaPrompt)
 	sb.WriteString("\n\n")
@@ -936,18 +1159,17 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 		sb.WriteString(promptSections.RoleDefinition)
 		sb.WriteString("\n\n")
 	} else {
-		sb.WriteString("# You are a professional cryptocurrency trading AI\n\n")
-		sb.WriteString("Your task is to make trading decisions based on provided market data.\n\n")
+		sb.WriteString(text.DefaultRole)
 	}
 
 	// 2. Trading mode variant
 	switch strings.ToLower(strings.TrimSpace(variant)) {
 	case "aggressive":
-		sb.WriteString("## Mode: Aggressive\n- Prioritize capturing trend breakouts, can build positions in batches when confidence ≥ 70\n- Allow higher positions, but must strictly set stop-loss and explain risk-reward ratio\n\n")
+		sb.WriteString(text.ModeAggressive)
 	case "conservative":
-		sb.WriteString("## Mode: Conservative\n- Only open positions when multiple signals resonate\n- Prioritize cash preservation, must pause for multiple periods after consecutive losses\n\n")
+		sb.WriteString(text.ModeConservative)
 	case "scalping":
-		sb.WriteString("## Mode: Scalping\n- Focus on short-term momentum, smaller profit targets but require quick action\n- If price doesn't move as expected within two bars, immediately reduce position or stop-loss\n\n")
+		sb.WriteString(text.ModeScalping)
 	}
 
 	// 3. Hard constraints (risk control)
@@ -960,55 +1182,49 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 		altcoinPosValueRatio = 1.0
 	}
 
-	sb.WriteString("# Hard Constraints (Risk Control)\n\n")
-	sb.WriteString("## CODE ENFORCED (Backend validation, cannot be bypassed):\n")
-	sb.WriteString(fmt.Sprintf("- Max Positions: %d coins simultaneously\n", riskControl.MaxPositions))
-	sb.WriteString(fmt.Sprintf("- Position Value Limit (Altcoins): max %.0f USDT (= equity %.0f × %.1fx)\n",
+	sb.WriteString(text.HardConstraints)
+	sb.WriteString(text.CodeEnforced)
+	sb.WriteString(fmt.Sprintf(text.MaxPositions, riskControl.MaxPositions))
+	sb.WriteString(fmt.Sprintf(text.AltcoinValueLimit,
 		accountEquity*altcoinPosValueRatio, accountEquity, altcoinPosValueRatio))
-	sb.WriteString(fmt.Sprintf("- Position Value Limit (BTC/ETH): max %.0f USDT (= equity %.0f × %.1fx)\n",
+	sb.WriteString(fmt.Sprintf(text.BTCETHValueLimit,
 		accountEquity*btcEthPosValueRatio, accountEquity, btcEthPosValueRatio))
-	sb.WriteString(fmt.Sprintf("- Max Margin Usage: ≤%.0f%%\n", riskControl.MaxMarginUsage*100))
-	sb.WriteString(fmt.Sprintf("- Min Position Size: ≥%.0f USDT\n\n", riskControl.MinPositionSize))
+	sb.WriteString(fmt.Sprintf(text.MaxMarginUsage, riskControl.MaxMarginUsage*100))
+	sb.WriteString(fmt.Sprintf(text.MinPositionSize, riskControl.MinPositionSize))
+	if minRiskReward := minRiskRewardRatio(riskControl); minRiskReward > 0 {
+		sb.WriteString(fmt.Sprintf(text.RiskRewardGuide, minRiskReward))
+	}
 
-	sb.WriteString("## AI GUIDED (Recommended, you should follow):\n")
-	sb.WriteString(fmt.Sprintf("- Trading Leverage: Altcoins max %dx | BTC/ETH max %dx\n",
+	sb.WriteString(text.AIGuided)
+	sb.WriteString(fmt.Sprintf(text.LeverageGuide,
 		riskControl.AltcoinMaxLeverage, riskControl.BTCETHMaxLeverage))
-	sb.WriteString(fmt.Sprintf("- Risk-Reward Ratio: ≥1:%.1f (take_profit / stop_loss)\n", riskControl.MinRiskRewardRatio))
-	sb.WriteString(fmt.Sprintf("- Min Confidence: ≥%d to open position\n\n", riskControl.MinConfidence))
+	sb.WriteString(fmt.Sprintf(text.MinConfidenceGuide, riskControl.MinConfidence))
 
 	// Position sizing guidance
-	sb.WriteString("## Position Sizing Guidance\n")
-	sb.WriteString("Calculate `position_size_usd` based on your confidence and the Position Value Limits above:\n")
-	sb.WriteString("- High confidence (≥85): Use 80-100%% of max position value limit\n")
-	sb.WriteString("- Medium confidence (70-84): Use 50-80%% of max position value limit\n")
-	sb.WriteString("- Low confidence (60-69): Use 30-50%% of max position value limit\n")
-	sb.WriteString(fmt.Sprintf("- Example: With equity %.0f and BTC/ETH ratio %.1fx, max is %.0f USDT\n",
+	sb.WriteString(fmt.Sprintf(text.PositionSizing,
 		accountEquity, btcEthPosValueRatio, accountEquity*btcEthPosValueRatio))
-	sb.WriteString("- **DO NOT** just use available_balance as position_size_usd. Use the Position Value Limits!\n\n")
+
+	// Regime-adjusted caps (tighter in ranging/volatile markets)
+	e.writeRegimeConstraints(&sb, accountEquity, regimes)
 
 	// 4. Trading frequency (editable)
 	if promptSections.TradingFrequency != "" {
 		sb.WriteString(promptSections.TradingFrequency)
 		sb.WriteString("\n\n")
 	} else {
-		sb.WriteString("# ⏱️ Trading Frequency Awareness\n\n")
-		sb.WriteString("- Excellent traders: 2-4 trades/day ≈ 0.1-0.2 trades/hour\n")
-		sb.WriteString("- >2 trades/hour = Overtrading\n")
-		sb.WriteString("- Single position hold time ≥ 30-60 minutes\n")
-		sb.WriteString("If you find yourself trading every period → standards too low; if closing positions < 30 minutes → too impatient.\n\n")
+		sb.WriteString(text.DefaultFrequency)
 	}
 
 	// 5. Entry standards (editable)
 	if promptSections.EntryStandards != "" {
 		sb.WriteString(promptSections.EntryStandards)
-		sb.WriteString("\n\nYou have the following indicator data:\n")
+		sb.WriteString(text.IndicatorIntro)
 		e.writeAvailableIndicators(&sb)
-		sb.WriteString(fmt.Sprintf("\n**Confidence ≥ %d** required to open positions.\n\n", riskControl.MinConfidence))
+		sb.WriteString(fmt.Sprintf(text.ConfidenceRequired, riskControl.MinConfidence))
 	} else {
-		sb.WriteString("# 🎯 Entry Standards (Strict)\n\n")
-		sb.WriteString("Only open positions when multiple signals resonate. You have:\n")
+		sb.WriteString(text.DefaultEntryHeader)
 		e.writeAvailableIndicators(&sb)
-		sb.WriteString(fmt.Sprintf("\nFeel free to use any effective analysis method, but **confidence ≥ %d** required to open positions; avoid low-quality behaviors such as single indicators, contradictory signals, sideways consolidation, reopening immediately after closing, etc.\n\n", riskControl.MinConfidence))
+		sb.WriteString(fmt.Sprintf(text.DefaultEntryFooter, riskControl.MinConfidence))
 	}
 
 	// 6. Decision process (editable)
@@ -1016,22 +1232,11 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 		sb.WriteString(promptSections.DecisionProcess)
 		sb.WriteString("\n\n")
 	} else {
-		sb.WriteString("# 📋 Decision Process\n\n")
-		sb.WriteString("1. Check positions → Should we take profit/stop-loss\n")
-		sb.WriteString("2. Scan candidate coins + multi-timeframe → Are there strong signals\n")
-		sb.WriteString("3. Write chain of thought first, then output structured JSON\n\n")
+		sb.WriteString(text.DefaultProcess)
 	}
 
 	// 7. Output format
-	sb.WriteString("# Output Format (Strictly Follow)\n\n")
-	sb.WriteString("**Must use XML tags <reasoning> and <decision> to separate chain of thought and decision JSON, avoiding parsing errors**\n\n")
-	sb.WriteString("## Format Requirements\n\n")
-	sb.WriteString("<reasoning>\n")
-	sb.WriteString("Your chain of thought analysis...\n")
-	sb.WriteString("- Briefly analyze your thinking process \n")
-	sb.WriteString("</reasoning>\n\n")
-	sb.WriteString("<decision>\n")
-	sb.WriteString("Step 2: JSON decision array\n\n")
+	sb.WriteString(text.OutputFormatHeader)
 	sb.WriteString("```json\n[\n")
 	// Use the actual configured position value ratio for BTC/ETH in the example
 	examplePositionSize := accountEquity * btcEthPosValueRatio
@@ -1040,18 +1245,28 @@ func (e *StrategyEngine) BuildSystemPrompt(accountEquity float64, variant string
 	sb.WriteString("  {\"symbol\": \"ETHUSDT\", \"action\": \"close_long\"}\n")
 	sb.WriteString("]\n```\n")
 	sb.WriteString("</decision>\n\n")
-	sb.WriteString("## Field Description\n\n")
-	sb.WriteString("- `action`: open_long | open_short | close_long | close_short | hold | wait\n")
-	sb.WriteString(fmt.Sprintf("- `confidence`: 0-100 (opening recommended ≥ %d)\n", riskControl.MinConfidence))
-	sb.WriteString("- Required when opening: leverage, position_size_usd, stop_loss, take_profit, confidence, risk_usd\n")
-	sb.WriteString("- **IMPORTANT**: All numeric values must be calculated numbers, NOT formulas/expressions (e.g., use `27.76` not `3000 * 0.01`)\n\n")
-
-	// 8. Custom Prompt
-	if e.config.CustomPrompt != "" {
-		sb.WriteString("# 📌 Personalized Trading Strategy\n\n")
-		sb.WriteString(e.config.CustomPrompt)
-		sb.WriteString("\n\n")
-		sb.WriteString("Note: The above personalized strategy is a supplement to the basic rules and cannot violate the basic risk control principles.\n")
+	sb.WriteString(text.FieldDescription)
+	sb.WriteString(text.ActionField)
+	sb.WriteString(fmt.Sprintf(text.LimitEntryField,
+		DefaultLimitEntryExpiryMinutes, MaxLimitEntryExpiryMinutes))
+	sb.WriteString(text.TakeProfitLevels)
+	sb.WriteString(fmt.Sprintf(text.ConfidenceField, riskControl.MinConfidence))
+	sb.WriteString(text.RequiredWhenOpening)
+	sb.WriteString(text.NumericValues)
+
+	// 8. Response language (reasoning in user's language, JSON stays machine-readable)
+	sb.WriteString(text.ResponseLanguage)
+
+	// 9. Prompt template and custom prompt
+	if e.config.TemplatePrompt != "" || e.config.CustomPrompt != "" {
+		sb.WriteString(text.CustomPromptHeader)
+		for _, prompt := range []string{e.config.TemplatePrompt, e.config.CustomPrompt} {
+			if prompt != "" {
+				sb.WriteString(prompt)
+				sb.WriteString("\n\n")
+			}
+		}
+		sb.WriteString(text.CustomPromptFooter)
 	}
 
 	return sb.String()
@@ -1068,6 +1283,14 @@ func (e *StrategyEngine) writeAvailableIndicators(sb *strings.Builder) {
 		sb.WriteString("\n")
 	}
 
+	if weights := e.summaryWeights(); len(weights) > 0 {
+		parts := make([]string, len(weights))
+		for i, w := range weights {
+			parts[i] = fmt.Sprintf("%s %.0f%%", w.Timeframe, w.Weight*100)
+		}
+		sb.WriteString(fmt.Sprintf("- Multi-timeframe summary, weigh each horizon accordingly (%s)\n", strings.Join(parts, ", ")))
+	}
+
 	if indicators.EnableEMA {
 		sb.WriteString("- EMA indicators")
 		if len(indicators.EMAPeriods) > 0 {
@@ -1104,6 +1327,26 @@ func (e *StrategyEngine) writeAvailableIndicators(sb *strings.Builder) {
 		sb.WriteString("\n")
 	}
 
+	if indicators.EnableVWAP {
+		sb.WriteString("- VWAP (anchored at UTC day start)\n")
+	}
+
+	if indicators.EnableSuperTrend {
+		sb.WriteString("- SuperTrend (10, 3) line and trend direction\n")
+	}
+
+	if indicators.EnableIchimoku {
+		sb.WriteString("- Ichimoku cloud (9, 26, 52): Tenkan/Kijun, current and future cloud\n")
+	}
+
+	if indicators.EnableOBV {
+		sb.WriteString("- On-balance volume (OBV)\n")
+	}
+
+	if indicators.EnableStochRSI {
+		sb.WriteString("- Stochastic RSI (14, 14, 3, 3) %K/%D\n")
+	}
+
 	if indicators.EnableVolume {
 		sb.WriteString("- Volume data\n")
 	}
@@ -1116,6 +1359,22 @@ func (e *StrategyEngine) writeAvailableIndicators(sb *strings.Builder) {
 		sb.WriteString("- Funding rate\n")
 	}
 
+	if indicators.EnableOrderBook {
+		sb.WriteString("- Order book liquidity: spread, bid/ask depth and imbalance of the top levels\n")
+	}
+
+	if indicators.EnableNews {
+		sb.WriteString("- Latest crypto news headlines\n")
+	}
+
+	if indicators.EnableSentiment {
+		sb.WriteString("- Market sentiment: Crypto Fear & Greed index (0-100) and social sentiment scores (-1..1)\n")
+	}
+
+	if indicators.EnableEconomicCalendar {
+		sb.WriteString("- Economic calendar: upcoming medium/high impact macro events (CPI, FOMC, NFP...)\n")
+	}
+
 	if len(e.config.CoinSource.StaticCoins) > 0 || e.config.CoinSource.UseAI500 || e.config.CoinSource.UseOITop {
 		sb.WriteString("- AI500 / OI_Top filter tags (if available)\n")
 	}
@@ -1132,9 +1391,10 @@ func (e *StrategyEngine) writeAvailableIndicators(sb *strings.Builder) {
 // BuildUserPrompt builds User Prompt based on strategy configuration
 func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 	var sb strings.Builder
+	text := getPromptText(e.GetLanguage())
 
 	// System status
-	sb.WriteString(fmt.Sprintf("Time: %s | Period: #%d | Runtime: %d minutes\n\n",
+	sb.WriteString(fmt.Sprintf(text.StatusLine,
 		ctx.CurrentTime, ctx.CallCount, ctx.RuntimeMinutes))
 
 	// BTC market
@@ -1145,23 +1405,39 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 	}
 
 	// Account information
-	sb.WriteString(fmt.Sprintf("Account: Equity %.2f | Balance %.2f (%.1f%%) | PnL %+.2f%% | Margin %.1f%% | Positions %d\n\n",
+	sb.WriteString(fmt.Sprintf(text.AccountLine,
 		ctx.Account.TotalEquity,
 		ctx.Account.AvailableBalance,
 		(ctx.Account.AvailableBalance/ctx.Account.TotalEquity)*100,
 		ctx.Account.TotalPnLPct,
 		ctx.Account.MarginUsedPct,
 		ctx.Account.PositionCount))
+	if ctx.Contract != nil {
+		symbol := ctx.Contract.MarginAsset + "USDT"
+		sb.WriteString(fmt.Sprintf(text.InverseAccount,
+			ctx.Contract.MarginAsset, ctx.Contract.MarginAsset, ctx.Contract.AssetPrice, ctx.EquityUSD(),
+			symbol, ctx.Contract.ContractSizes[symbol]))
+	}
+
+	// Pending limit entries (not yet filled, auto-cancelled on expiry)
+	if len(ctx.PendingOrders) > 0 {
+		sb.WriteString(text.PendingEntries)
+		for i, po := range ctx.PendingOrders {
+			sb.WriteString(fmt.Sprintf(text.PendingEntryLine,
+				i+1, po.Symbol, strings.ToUpper(po.Side), po.Price, po.Quantity, po.StopLoss, po.TakeProfit, po.MinutesToExpiry))
+		}
+		sb.WriteString("\n")
+	}
 
 	// Recently completed orders (placed before positions to ensure visibility)
 	if len(ctx.RecentOrders) > 0 {
-		sb.WriteString("## Recent Completed Trades\n")
+		sb.WriteString(text.RecentTrades)
 		for i, order := range ctx.RecentOrders {
-			resultStr := "Profit"
+			resultStr := text.TradeProfit
 			if order.RealizedPnL < 0 {
-				resultStr = "Loss"
+				resultStr = text.TradeLoss
 			}
-			sb.WriteString(fmt.Sprintf("%d. %s %s | Entry %.4f Exit %.4f | %s: %+.2f USDT (%+.2f%%) | %s→%s (%s)\n",
+			sb.WriteString(fmt.Sprintf(text.TradeLine,
 				i+1, order.Symbol, order.Side,
 				order.EntryPrice, order.ExitPrice,
 				resultStr, order.RealizedPnL, order.PnLPct,
@@ -1170,6 +1446,15 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 		sb.WriteString("\n")
 	}
 
+	// Lessons from the AI's reviews of its recent closed trades
+	if len(ctx.Reflections) > 0 {
+		sb.WriteString(text.Reflections)
+		for i, r := range ctx.Reflections {
+			sb.WriteString(fmt.Sprintf(text.ReflectionLine, i+1, r.Time, r.Symbol, r.Side, r.PnLPct, r.Reason, r.Text))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Historical trading statistics (helps AI understand past performance)
 	if ctx.TradingStats != nil && ctx.TradingStats.TotalTrades > 0 {
 		// Get language from strategy config
@@ -1233,12 +1518,12 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 
 	// Position information
 	if len(ctx.Positions) > 0 {
-		sb.WriteString("## Current Positions\n")
+		sb.WriteString(text.CurrentPositions)
 		for i, pos := range ctx.Positions {
 			sb.WriteString(e.formatPositionInfo(i+1, pos, ctx))
 		}
 	} else {
-		sb.WriteString("Current Positions: None\n\n")
+		sb.WriteString(text.NoPositions)
 	}
 
 	// Candidate coins (exclude coins already in positions to avoid duplicate data)
@@ -1249,7 +1534,7 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 		positionSymbols[normalizedSymbol] = true
 	}
 
-	sb.WriteString(fmt.Sprintf("## Candidate Coins (%d coins)\n\n", len(ctx.MarketDataMap)))
+	sb.WriteString(fmt.Sprintf(text.CandidateCoins, len(ctx.MarketDataMap)))
 	displayedCount := 0
 	for _, coin := range ctx.CandidateCoins {
 		// Skip if this coin is already a position (data already shown in positions section)
@@ -1267,6 +1552,8 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 		sourceTags := e.formatCoinSourceTag(coin.Sources)
 		sb.WriteString(fmt.Sprintf("### %d. %s%s\n\n", displayedCount, coin.Symbol, sourceTags))
 		sb.WriteString(e.formatMarketData(marketData))
+		sb.WriteString(e.formatTimeframeSummaries(marketData, ctx.MultiTFMarket[coin.Symbol]))
+		sb.WriteString(formatDepthMetrics(ctx.OrderBookMap[coin.Symbol]))
 
 		if ctx.QuantDataMap != nil {
 			if quantData, hasQuant := ctx.QuantDataMap[coin.Symbol]; hasQuant {
@@ -1298,8 +1585,14 @@ func (e *StrategyEngine) BuildUserPrompt(ctx *Context) string {
 		sb.WriteString(nofxos.FormatPriceRankingForAI(ctx.PriceRankingData, nofxosLang))
 	}
 
+	// Market sentiment (Fear & Greed, social scores)
+	sb.WriteString(formatSentiment(ctx.Sentiment))
+
+	// News headlines and upcoming macro events
+	sb.WriteString(e.formatNewsContext(ctx.News, time.Now()))
+
 	sb.WriteString("---\n\n")
-	sb.WriteString("Now please analyze and output your decision (Chain of Thought + JSON)\n")
+	sb.WriteString(text.FinalInstruction)
 
 	return sb.String()
 }
@@ -1320,6 +1613,14 @@ func (e *StrategyEngine) formatPositionInfo(index int, pos PositionInfo, ctx *Co
 		}
 	}
 
+	if e.config != nil {
+		if _, stale := HoldingTimeExceeded(e.config.RiskControl, pos.UpdateTime, time.Now()); stale {
+			holdingDuration += fmt.Sprintf(" | ⏰ TIME STOP: held over the %gh maximum, close it unless the setup is still valid", e.config.RiskControl.MaxHoldingHours)
+		}
+	}
+
+	holdingDuration += e.formatPositionFunding(pos, ctx)
+
 	positionValue := pos.Quantity * pos.MarkPrice
 	if positionValue < 0 {
 		positionValue = -positionValue
@@ -1332,6 +1633,7 @@ func (e *StrategyEngine) formatPositionInfo(index int, pos PositionInfo, ctx *Co
 
 	if marketData, ok := ctx.MarketDataMap[pos.Symbol]; ok {
 		sb.WriteString(e.formatMarketData(marketData))
+		sb.WriteString(e.formatTimeframeSummaries(marketData, ctx.MultiTFMarket[pos.Symbol]))
 
 		if ctx.QuantDataMap != nil {
 			if quantData, hasQuant := ctx.QuantDataMap[pos.Symbol]; hasQuant {
@@ -1344,6 +1646,36 @@ func (e *StrategyEngine) formatPositionInfo(index int, pos PositionInfo, ctx *Co
 	return sb.String()
 }
 
+// formatPositionFunding next funding settlement of a position and what it is projected to receive or pay there,
+// falling back to the symbol's market data when the position carries no funding info
+func (e *StrategyEngine) formatPositionFunding(pos PositionInfo, ctx *Context) string {
+	rate, nextFundingMs := pos.FundingRate, pos.NextFundingTime
+	if nextFundingMs <= 0 {
+		if data, ok := ctx.MarketDataMap[pos.Symbol]; ok && !data.NextFundingTime.IsZero() {
+			rate, nextFundingMs = data.FundingRate, data.NextFundingTime.UnixMilli()
+		}
+	}
+	if nextFundingMs <= 0 {
+		return ""
+	}
+	var rc store.RiskControlConfig
+	if e.config != nil {
+		rc = e.config.RiskControl
+	}
+	until, _ := FundingAvoidanceDue(rc, pos.Side, rate, nextFundingMs, time.Now())
+	if until <= 0 {
+		return ""
+	}
+	untilMin := int(until.Minutes())
+	out := fmt.Sprintf(" | Next Funding %s UTC (in %dh %dm) Rate %.4f%% Est. Payment %+.2f USDT",
+		time.UnixMilli(nextFundingMs).UTC().Format("15:04"), untilMin/60, untilMin%60, rate*100,
+		ProjectedFundingPayment(pos.Side, pos.Quantity, pos.MarkPrice, rate))
+	if paidRatePct := -ProjectedFundingPayment(pos.Side, 1, 1, rate) * 100; rc.AvoidFundingRatePct > 0 && paidRatePct >= rc.AvoidFundingRatePct {
+		out += fmt.Sprintf(" | 💸 FUNDING: pays over the %g%% limit, it is closed shortly before settlement", rc.AvoidFundingRatePct)
+	}
+	return out
+}
+
 func (e *StrategyEngine) formatCoinSourceTag(sources []string) string {
 	if len(sources) > 1 {
 		// 多信号源组合
@@ -1426,8 +1758,9 @@ func (e *StrategyEngine) formatMarketData(data *market.Data) string {
 
 	if len(data.TimeframeData) > 0 {
 		timeframeOrder := []string{"1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h", "1d", "3d", "1w"}
+		summaryOnly := e.summaryOnlyTimeframes()
 		for _, tf := range timeframeOrder {
-			if tfData, ok := data.TimeframeData[tf]; ok {
+			if tfData, ok := data.TimeframeData[tf]; ok && !summaryOnly[tf] {
 				sb.WriteString(fmt.Sprintf("=== %s Timeframe (oldest → latest) ===\n\n", strings.ToUpper(tf)))
 				e.formatTimeframeSeriesData(&sb, tfData, indicators)
 			}
@@ -1552,6 +1885,8 @@ func (e *StrategyEngine) formatTimeframeSeriesData(sb *strings.Builder, data *ma
 		sb.WriteString(fmt.Sprintf("BOLL Lower: %s\n", formatFloatSlice(data.BOLLLower)))
 	}
 
+	formatExtendedIndicators(sb, data.Indicators(indicatorSelection(indicators)))
+
 	sb.WriteString("\n")
 }
 
@@ -1671,7 +2006,7 @@ func formatFloatSlice(values []float64) string {
 // AI Response Parsing
 // ============================================================================
 
-func parseFullDecisionResponse(aiResponse string, accountEquity float64, btcEthLeverage, altcoinLeverage int, btcEthPosRatio, altcoinPosRatio float64) (*FullDecision, error) {
+func parseFullDecisionResponse(aiResponse string, accountEquity float64, btcEthLeverage, altcoinLeverage int, btcEthPosRatio, altcoinPosRatio, minRiskReward float64) (*FullDecision, error) {
 	cotTrace := extractCoTTrace(aiResponse)
 
 	decisions, err := extractDecisions(aiResponse)
@@ -1682,7 +2017,7 @@ func parseFullDecisionResponse(aiResponse string, accountEquity float64, btcEthL
 		}, fmt.Errorf("failed to extract decisions: %w", err)
 	}
 
-	if err := validateDecisions(decisions, accountEquity, btcEthLeverage, altcoinLeverage, btcEthPosRatio, altcoinPosRatio); err != nil {
+	if err := validateDecisions(decisions, accountEquity, btcEthLeverage, altcoinLeverage, btcEthPosRatio, altcoinPosRatio, minRiskReward); err != nil {
 		return &FullDecision{
 			CoTTrace:  cotTrace,
 			Decisions: decisions,
@@ -1695,6 +2030,20 @@ func parseFullDecisionResponse(aiResponse string, accountEquity float64, btcEthL
 	}, nil
 }
 
+// ParseAIResponse parses and validates a free text AI response against the strategy's risk control limits
+func (e *StrategyEngine) ParseAIResponse(aiResponse string, accountEquity float64) (*FullDecision, error) {
+	riskConfig := e.GetRiskControlConfig()
+	return parseFullDecisionResponse(
+		aiResponse,
+		accountEquity,
+		riskConfig.BTCETHMaxLeverage,
+		riskConfig.AltcoinMaxLeverage,
+		riskConfig.BTCETHMaxPositionValueRatio,
+		riskConfig.AltcoinMaxPositionValueRatio,
+		minRiskRewardRatio(riskConfig),
+	)
+}
+
 func extractCoTTrace(response string) string {
 	if match := reReasoningTag.FindStringSubmatch(response); match != nil && len(match) > 1 {
 		logger.Infof("✓ Extracted reasoning chain using <reasoning> tag")
@@ -1848,30 +2197,87 @@ func compactArrayOpen(s string) string {
 // Decision Validation
 // ============================================================================
 
-func validateDecisions(decisions []Decision, accountEquity float64, btcEthLeverage, altcoinLeverage int, btcEthPosRatio, altcoinPosRatio float64) error {
+// defaultMinRiskRewardRatio minimum take profit / stop loss distance ratio of strategies that don't set one
+const defaultMinRiskRewardRatio = 3.0
+
+// minRiskRewardRatio the ratio new positions must reach, 0 when the strategy disables the check
+func minRiskRewardRatio(rc store.RiskControlConfig) float64 {
+	if rc.DisableRiskRewardCheck {
+		return 0
+	}
+	if rc.MinRiskRewardRatio <= 0 {
+		return defaultMinRiskRewardRatio
+	}
+	return rc.MinRiskRewardRatio
+}
+
+// HoldingTimeExceeded how long a position opened at entryMs has been held, and whether that is past
+// the strategy's max holding time (never when it is unset or the entry time unknown)
+func HoldingTimeExceeded(rc store.RiskControlConfig, entryMs int64, now time.Time) (time.Duration, bool) {
+	if entryMs <= 0 {
+		return 0, false
+	}
+	held := now.Sub(time.UnixMilli(entryMs))
+	return held, rc.MaxHoldingHours > 0 && held >= time.Duration(rc.MaxHoldingHours*float64(time.Hour))
+}
+
+// defaultAvoidFundingWindow how long before settlement positions paying a high funding rate are closed
+const defaultAvoidFundingWindow = 30 * time.Minute
+
+// ProjectedFundingPayment what a position receives (+) or pays (-) at the next settlement, longs pay
+// shorts when the rate is positive
+func ProjectedFundingPayment(side string, quantity, markPrice, rate float64) float64 {
+	payment := -rate * math.Abs(quantity*markPrice)
+	if side == "short" {
+		payment = -payment
+	}
+	return payment
+}
+
+// FundingAvoidanceDue how long until the next funding settlement, and whether a side pays at least the
+// strategy's funding limit at a settlement inside the avoidance window (never when it is off or the time unknown)
+func FundingAvoidanceDue(rc store.RiskControlConfig, side string, rate float64, nextFundingMs int64, now time.Time) (time.Duration, bool) {
+	if nextFundingMs <= 0 {
+		return 0, false
+	}
+	until := time.UnixMilli(nextFundingMs).Sub(now)
+	if rc.AvoidFundingRatePct <= 0 || until <= 0 {
+		return until, false
+	}
+	window := defaultAvoidFundingWindow
+	if rc.AvoidFundingWindowMins > 0 {
+		window = time.Duration(rc.AvoidFundingWindowMins) * time.Minute
+	}
+	paidRatePct := -ProjectedFundingPayment(side, 1, 1, rate) * 100
+	return until, until <= window && paidRatePct >= rc.AvoidFundingRatePct
+}
+
+func validateDecisions(decisions []Decision, accountEquity float64, btcEthLeverage, altcoinLeverage int, btcEthPosRatio, altcoinPosRatio, minRiskReward float64) error {
 	for i := range decisions {
-		if err := validateDecision(&decisions[i], accountEquity, btcEthLeverage, altcoinLeverage, btcEthPosRatio, altcoinPosRatio); err != nil {
+		if err := validateDecision(&decisions[i], accountEquity, btcEthLeverage, altcoinLeverage, btcEthPosRatio, altcoinPosRatio, minRiskReward); err != nil {
 			return fmt.Errorf("decision #%d validation failed: %w", i+1, err)
 		}
 	}
 	return nil
 }
 
-func validateDecision(d *Decision, accountEquity float64, btcEthLeverage, altcoinLeverage int, btcEthPosRatio, altcoinPosRatio float64) error {
+func validateDecision(d *Decision, accountEquity float64, btcEthLeverage, altcoinLeverage int, btcEthPosRatio, altcoinPosRatio, minRiskReward float64) error {
 	validActions := map[string]bool{
-		"open_long":   true,
-		"open_short":  true,
-		"close_long":  true,
-		"close_short": true,
-		"hold":        true,
-		"wait":        true,
+		"open_long":        true,
+		"open_short":       true,
+		"open_long_limit":  true,
+		"open_short_limit": true,
+		"close_long":       true,
+		"close_short":      true,
+		"hold":             true,
+		"wait":             true,
 	}
 
 	if !validActions[d.Action] {
 		return fmt.Errorf("invalid action: %s", d.Action)
 	}
 
-	if d.Action == "open_long" || d.Action == "open_short" {
+	if d.IsOpen() {
 		maxLeverage := altcoinLeverage
 		posRatio := altcoinPosRatio
 		maxPositionValue := accountEquity * posRatio
@@ -1914,11 +2320,31 @@ func validateDecision(d *Decision, accountEquity float64, btcEthLeverage, altcoi
 				return fmt.Errorf("altcoin single coin position value cannot exceed %.0f USDT (%.1fx account equity), actual: %.0f", maxPositionValue, posRatio, d.PositionSizeUSD)
 			}
 		}
+		if err := validateTakeProfitLevels(d); err != nil {
+			return err
+		}
 		if d.StopLoss <= 0 || d.TakeProfit <= 0 {
 			return fmt.Errorf("stop loss and take profit must be greater than 0")
 		}
 
-		if d.Action == "open_long" {
+		if d.IsLimitEntry() {
+			if d.Price <= 0 {
+				return fmt.Errorf("limit entry price must be greater than 0")
+			}
+			if d.IsLong() && (d.Price <= d.StopLoss || d.Price >= d.TakeProfit) {
+				return fmt.Errorf("for long limit entries, price must be between stop loss and take profit")
+			}
+			if !d.IsLong() && (d.Price >= d.StopLoss || d.Price <= d.TakeProfit) {
+				return fmt.Errorf("for short limit entries, price must be between take profit and stop loss")
+			}
+			if d.ExpiryMinutes <= 0 {
+				d.ExpiryMinutes = DefaultLimitEntryExpiryMinutes
+			} else if d.ExpiryMinutes > MaxLimitEntryExpiryMinutes {
+				d.ExpiryMinutes = MaxLimitEntryExpiryMinutes
+			}
+		}
+
+		if d.IsLong() {
 			if d.StopLoss >= d.TakeProfit {
 				return fmt.Errorf("for long positions, stop loss price must be less than take profit price")
 			}
@@ -1929,14 +2355,16 @@ func validateDecision(d *Decision, accountEquity float64, btcEthLeverage, altcoi
 		}
 
 		var entryPrice float64
-		if d.Action == "open_long" {
+		if d.IsLimitEntry() {
+			entryPrice = d.Price // Known entry price
+		} else if d.IsLong() {
 			entryPrice = d.StopLoss + (d.TakeProfit-d.StopLoss)*0.2
 		} else {
 			entryPrice = d.StopLoss - (d.StopLoss-d.TakeProfit)*0.2
 		}
 
 		var riskPercent, rewardPercent, riskRewardRatio float64
-		if d.Action == "open_long" {
+		if d.IsLong() {
 			riskPercent = (entryPrice - d.StopLoss) / entryPrice * 100
 			rewardPercent = (d.TakeProfit - entryPrice) / entryPrice * 100
 			if riskPercent > 0 {
@@ -1950,15 +2378,49 @@ func validateDecision(d *Decision, accountEquity float64, btcEthLeverage, altcoi
 			}
 		}
 
-		if riskRewardRatio < 3.0 {
-			return fmt.Errorf("risk/reward ratio too low (%.2f:1), must be ≥3.0:1 [risk: %.2f%% reward: %.2f%%] [stop loss: %.2f take profit: %.2f]",
-				riskRewardRatio, riskPercent, rewardPercent, d.StopLoss, d.TakeProfit)
+		if minRiskReward > 0 && riskRewardRatio < minRiskReward {
+			return fmt.Errorf("risk/reward ratio too low (%.2f:1), must be ≥%.1f:1 [risk: %.2f%% reward: %.2f%%] [stop loss: %.2f take profit: %.2f]",
+				riskRewardRatio, minRiskReward, riskPercent, rewardPercent, d.StopLoss, d.TakeProfit)
 		}
 	}
 
 	return nil
 }
 
+// validateTakeProfitLevels checks a take-profit ladder and sorts it nearest level first
+// Without a take_profit, the farthest level is used for the stop loss / take profit checks.
+func validateTakeProfitLevels(d *Decision) error {
+	if len(d.TakeProfitLevels) == 0 {
+		return nil
+	}
+	if d.TrailingStopPct < 0 || d.TrailingStopPct >= 50 {
+		return fmt.Errorf("trailing stop must be between 0 and 50%%, got %.2f", d.TrailingStopPct)
+	}
+	total := 0.0
+	for _, level := range d.TakeProfitLevels {
+		if level.Price <= 0 || level.ClosePct <= 0 {
+			return fmt.Errorf("take profit levels need a positive price and close_pct")
+		}
+		if d.StopLoss > 0 && (d.IsLong() && level.Price <= d.StopLoss || !d.IsLong() && level.Price >= d.StopLoss) {
+			return fmt.Errorf("take profit level %.4f is on the stop loss side", level.Price)
+		}
+		total += level.ClosePct
+	}
+	if total > 100.0001 {
+		return fmt.Errorf("take profit levels close %.1f%% of the position, at most 100%%", total)
+	}
+	sort.SliceStable(d.TakeProfitLevels, func(i, j int) bool {
+		if d.IsLong() {
+			return d.TakeProfitLevels[i].Price < d.TakeProfitLevels[j].Price
+		}
+		return d.TakeProfitLevels[i].Price > d.TakeProfitLevels[j].Price
+	})
+	if d.TakeProfit <= 0 {
+		d.TakeProfit = d.TakeProfitLevels[len(d.TakeProfitLevels)-1].Price
+	}
+	return nil
+}
+
 // ============================================================================
 // Helper Functions
 // ============================================================================
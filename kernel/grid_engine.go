@@ -37,6 +37,7 @@ type GridContext struct {
 	CurrentPrice float64   `json:"current_price"`
 
 	// Grid configuration
+	MarketType      string  `json:"market_type,omitempty"` // "spot" or "futures"
 	GridCount       int     `json:"grid_count"`
 	TotalInvestment float64 `json:"total_investment"`
 	Leverage        int     `json:"leverage"`
@@ -75,6 +76,12 @@ type GridContext struct {
 	CurrentPosition  float64 `json:"current_position"` // Net position size
 	UnrealizedPnL    float64 `json:"unrealized_pnl"`
 
+	// Spot wallet (spot grids only, free amounts not held by open orders)
+	BaseAsset    string  `json:"base_asset,omitempty"`
+	QuoteAsset   string  `json:"quote_asset,omitempty"`
+	BaseBalance  float64 `json:"base_balance,omitempty"`
+	QuoteBalance float64 `json:"quote_balance,omitempty"`
+
 	// Performance
 	TotalProfit   float64 `json:"total_profit"`
 	TotalTrades   int     `json:"total_trades"`
@@ -96,11 +103,35 @@ type GridContext struct {
 // BuildGridSystemPrompt builds the system prompt for grid trading AI
 func BuildGridSystemPrompt(config *store.GridStrategyConfig, lang string) string {
 	if lang == "zh" {
+		if config.IsSpot() {
+			return buildGridSystemPromptZh(config) + gridSpotRulesZh
+		}
 		return buildGridSystemPromptZh(config)
 	}
+	if config.IsSpot() {
+		return buildGridSystemPromptEn(config) + gridSpotRulesEn
+	}
 	return buildGridSystemPromptEn(config)
 }
 
+// Spot grid rules appended to the system prompt
+const (
+	gridSpotRulesZh = `
+## 现货网格规则
+- 本网格交易现货，不使用杠杆，不能做空
+- 买单消耗计价货币（可用余额），卖单只能卖出钱包中已持有的币
+- 卖出数量不得超过"可用币数量"，买入金额不得超过"可用计价货币"
+- 平仓只能使用 close_long（卖出网格买入的币），不要使用 close_short
+`
+	gridSpotRulesEn = `
+## Spot Grid Rules
+- This grid trades the spot market: no leverage, no short selling
+- Buy orders spend the quote currency (available balance); sell orders can only sell coins held in the wallet
+- Sell quantity must not exceed the free coin balance, buy value must not exceed the free quote balance
+- To exit, use close_long (sells the coins the grid bought); never use close_short
+`
+)
+
 func buildGridSystemPromptZh(config *store.GridStrategyConfig) string {
 	return fmt.Sprintf(`# 你是一个专业的网格交易AI
 
@@ -272,6 +303,10 @@ func buildGridUserPromptZh(ctx *GridContext) string {
 	sb.WriteString(fmt.Sprintf("- 总权益: $%.2f\n", ctx.TotalEquity))
 	sb.WriteString(fmt.Sprintf("- 可用余额: $%.2f\n", ctx.AvailableBalance))
 	sb.WriteString(fmt.Sprintf("- 当前持仓: %.4f (净头寸)\n", ctx.CurrentPosition))
+	if ctx.MarketType == store.GridMarketSpot {
+		sb.WriteString(fmt.Sprintf("- 可用币数量: %.8f %s\n", ctx.BaseBalance, ctx.BaseAsset))
+		sb.WriteString(fmt.Sprintf("- 可用计价货币: %.2f %s\n", ctx.QuoteBalance, ctx.QuoteAsset))
+	}
 	sb.WriteString(fmt.Sprintf("- 未实现盈亏: $%.2f\n", ctx.UnrealizedPnL))
 	sb.WriteString("\n")
 
@@ -383,6 +418,10 @@ func buildGridUserPromptEn(ctx *GridContext) string {
 	sb.WriteString(fmt.Sprintf("- Total Equity: $%.2f\n", ctx.TotalEquity))
 	sb.WriteString(fmt.Sprintf("- Available Balance: $%.2f\n", ctx.AvailableBalance))
 	sb.WriteString(fmt.Sprintf("- Current Position: %.4f (net)\n", ctx.CurrentPosition))
+	if ctx.MarketType == store.GridMarketSpot {
+		sb.WriteString(fmt.Sprintf("- Free Coin Balance: %.8f %s\n", ctx.BaseBalance, ctx.BaseAsset))
+		sb.WriteString(fmt.Sprintf("- Free Quote Balance: %.2f %s\n", ctx.QuoteBalance, ctx.QuoteAsset))
+	}
 	sb.WriteString(fmt.Sprintf("- Unrealized PnL: $%.2f\n", ctx.UnrealizedPnL))
 	sb.WriteString("\n")
 
@@ -561,6 +600,7 @@ func BuildGridContextFromMarketData(mktData *market.Data, config *store.GridStra
 		CurrentPrice: mktData.CurrentPrice,
 
 		// Grid config
+		MarketType:      config.MarketType,
 		GridCount:       config.GridCount,
 		TotalInvestment: config.TotalInvestment,
 		Leverage:        config.Leverage,
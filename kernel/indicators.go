@@ -0,0 +1,67 @@
+package kernel
+
+import (
+	"fmt"
+	"nofx/market"
+	"nofx/store"
+	"strings"
+)
+
+// indicatorSelection maps a strategy's indicator switches to the extended indicator engine's selection
+func indicatorSelection(indicators store.IndicatorConfig) market.IndicatorSelection {
+	sel := market.IndicatorSelection{
+		VWAP:       indicators.EnableVWAP,
+		SuperTrend: indicators.EnableSuperTrend,
+		Ichimoku:   indicators.EnableIchimoku,
+		OBV:        indicators.EnableOBV,
+		StochRSI:   indicators.EnableStochRSI,
+	}
+	if indicators.EnableBOLL {
+		sel.BOLLPeriods = indicators.BOLLPeriods
+	}
+	return sel
+}
+
+// formatExtendedIndicators writes the extended indicator series of one timeframe
+func formatExtendedIndicators(sb *strings.Builder, ext *market.ExtendedIndicators) {
+	if ext == nil {
+		return
+	}
+
+	for _, boll := range ext.BOLL {
+		sb.WriteString(fmt.Sprintf("BOLL%d Upper: %s\n", boll.Period, formatFloatSlice(boll.Upper)))
+		sb.WriteString(fmt.Sprintf("BOLL%d Middle: %s\n", boll.Period, formatFloatSlice(boll.Middle)))
+		sb.WriteString(fmt.Sprintf("BOLL%d Lower: %s\n", boll.Period, formatFloatSlice(boll.Lower)))
+	}
+
+	if len(ext.VWAP) > 0 {
+		sb.WriteString(fmt.Sprintf("VWAP (UTC day): %s\n", formatFloatSlice(ext.VWAP)))
+	}
+
+	if n := len(ext.SuperTrendUp); n > 0 {
+		trend := "down"
+		if ext.SuperTrendUp[n-1] {
+			trend = "up"
+		}
+		// Count bars since the last direction flip
+		bars := 1
+		for i := n - 2; i >= 0 && ext.SuperTrendUp[i] == ext.SuperTrendUp[n-1]; i-- {
+			bars++
+		}
+		sb.WriteString(fmt.Sprintf("SuperTrend(10,3): %s | trend: %s for %d bars\n", formatFloatSlice(ext.SuperTrend), trend, bars))
+	}
+
+	if ich := ext.Ichimoku; ich != nil {
+		sb.WriteString(fmt.Sprintf("Ichimoku(9,26,52): Tenkan %.4f | Kijun %.4f | Cloud A %.4f / B %.4f | Future cloud A %.4f / B %.4f\n",
+			ich.Tenkan, ich.Kijun, ich.SenkouA, ich.SenkouB, ich.FutureA, ich.FutureB))
+	}
+
+	if len(ext.OBV) > 0 {
+		sb.WriteString(fmt.Sprintf("OBV: %s\n", formatFloatSlice(ext.OBV)))
+	}
+
+	if len(ext.StochRSIK) > 0 {
+		sb.WriteString(fmt.Sprintf("StochRSI %%K: %s\n", formatFloatSlice(ext.StochRSIK)))
+		sb.WriteString(fmt.Sprintf("StochRSI %%D: %s\n", formatFloatSlice(ext.StochRSID)))
+	}
+}
@@ -0,0 +1,166 @@
+package kernel
+
+import (
+	"fmt"
+	"math"
+	"nofx/logger"
+	"nofx/market"
+)
+
+// ============================================================================
+// Liquidation Distance Guard
+// ============================================================================
+// The theoretical liquidation price of each opening decision is computed with
+// the isolated margin model of its exchange (USDT-margined linear or
+// coin-margined inverse contracts, lowest maintenance margin tier, fees
+// ignored). Cross margin liquidates later
+// than this, so the check is conservative. A stop loss beyond liquidation, or
+// within the configured buffer of it, gets the leverage lowered until the stop
+// is reachable; if even 1x is not enough the decision is rejected.
+// ============================================================================
+
+// DefaultLiquidationBufferPct min distance between stop loss and liquidation price, % of entry price
+const DefaultLiquidationBufferPct = 1.0
+
+// maintenanceMarginRates maintenance margin rate of the first tier per exchange: BTC/ETH and altcoins
+var maintenanceMarginRates = map[string][2]float64{
+	"binance":     {0.004, 0.01},
+	"bybit":       {0.005, 0.01},
+	"okx":         {0.004, 0.01},
+	"bitget":      {0.004, 0.01},
+	"gate":        {0.005, 0.01},
+	"kucoin":      {0.004, 0.01},
+	"aster":       {0.004, 0.01},
+	"hyperliquid": {0.0125, 0.025}, // Half of the initial margin at max leverage (40x BTC, ~20x alts)
+}
+
+// defaultMaintenanceMarginRates applies to exchanges without a known margin model
+var defaultMaintenanceMarginRates = [2]float64{0.005, 0.01}
+
+// maintenanceMarginRate returns the maintenance margin rate of a symbol on an exchange
+func maintenanceMarginRate(exchange, symbol string) float64 {
+	rates, ok := maintenanceMarginRates[exchange]
+	if !ok {
+		rates = defaultMaintenanceMarginRates
+	}
+	if symbol == "BTCUSDT" || symbol == "ETHUSDT" {
+		return rates[0]
+	}
+	return rates[1]
+}
+
+// liquidationPrice theoretical isolated margin liquidation price of a position
+func liquidationPrice(entryPrice float64, leverage int, isLong bool, mmr float64) float64 {
+	if entryPrice <= 0 || leverage <= 0 {
+		return 0
+	}
+	if isLong {
+		return entryPrice * (1 - 1/float64(leverage)) / (1 - mmr)
+	}
+	return entryPrice * (1 + 1/float64(leverage)) / (1 + mmr)
+}
+
+// maxSafeLeverage highest leverage whose liquidation price stays beyond limitPrice
+// Returns 0 if no leverage (not even 1x) keeps liquidation beyond it
+func maxSafeLeverage(entryPrice, limitPrice float64, isLong bool, mmr float64) int {
+	var inverse float64 // 1/leverage needed to put liquidation exactly at limitPrice
+	if isLong {
+		inverse = 1 - limitPrice*(1-mmr)/entryPrice
+	} else {
+		inverse = limitPrice*(1+mmr)/entryPrice - 1
+	}
+	if inverse <= 0 {
+		return math.MaxInt32
+	}
+	return int(math.Floor(1 / inverse))
+}
+
+// inverseLiquidationPrice theoretical isolated margin liquidation price of an inverse (coin-margined) position
+// Margin and PnL are in the coin, so a long liquidates when 1/L + 1 = (1+mmr)·entry/price.
+// A 1x short can't be liquidated (+Inf).
+func inverseLiquidationPrice(entryPrice float64, leverage int, isLong bool, mmr float64) float64 {
+	if entryPrice <= 0 || leverage <= 0 {
+		return 0
+	}
+	if isLong {
+		return entryPrice * (1 + mmr) / (1 + 1/float64(leverage))
+	}
+	if denominator := 1 - 1/float64(leverage); denominator > 0 {
+		return entryPrice * (1 - mmr) / denominator
+	}
+	return math.Inf(1)
+}
+
+// inverseMaxSafeLeverage highest leverage whose inverse liquidation price stays beyond limitPrice
+// Returns 0 if no leverage (not even 1x) keeps liquidation beyond it
+func inverseMaxSafeLeverage(entryPrice, limitPrice float64, isLong bool, mmr float64) int {
+	if limitPrice <= 0 {
+		return math.MaxInt32 // Longs never liquidate at or below zero
+	}
+	var inverse float64 // 1/leverage needed to put liquidation exactly at limitPrice
+	if isLong {
+		inverse = entryPrice*(1+mmr)/limitPrice - 1
+	} else {
+		inverse = 1 - entryPrice*(1-mmr)/limitPrice
+	}
+	if inverse <= 0 {
+		return math.MaxInt32
+	}
+	return int(math.Floor(1 / inverse))
+}
+
+// applyLiquidationGuard lowers leverage of opening decisions whose stop loss is beyond (or within bufferPct of)
+// the liquidation price, and turns decisions into wait when no leverage is safe. Decisions without a known
+// entry price (no limit price, no market data) are left unchanged. inverse selects the coin-margined model.
+func applyLiquidationGuard(decisions []Decision, exchange string, marketData map[string]*market.Data, bufferPct float64, inverse bool) {
+	if bufferPct <= 0 {
+		bufferPct = DefaultLiquidationBufferPct
+	}
+	liquidationAt, safeLeverageFor := liquidationPrice, maxSafeLeverage
+	if inverse {
+		liquidationAt, safeLeverageFor = inverseLiquidationPrice, inverseMaxSafeLeverage
+	}
+	for i := range decisions {
+		d := &decisions[i]
+		if !d.IsOpen() || d.Leverage <= 0 || d.StopLoss <= 0 {
+			continue
+		}
+
+		entryPrice := d.Price
+		if !d.IsLimitEntry() {
+			entryPrice = 0
+			if data, ok := marketData[d.Symbol]; ok && data != nil {
+				entryPrice = data.CurrentPrice
+			}
+		}
+		if entryPrice <= 0 {
+			continue
+		}
+
+		mmr := maintenanceMarginRate(exchange, d.Symbol)
+		buffer := entryPrice * bufferPct / 100
+		isLong := d.IsLong()
+		limitPrice := d.StopLoss + buffer // Shorts: liquidation must stay above stop + buffer
+		if isLong {
+			limitPrice = d.StopLoss - buffer // Longs: liquidation must stay below stop - buffer
+		}
+
+		liqPrice := liquidationAt(entryPrice, d.Leverage, isLong, mmr)
+		if (isLong && liqPrice <= limitPrice) || (!isLong && liqPrice >= limitPrice) {
+			continue
+		}
+
+		safeLeverage := safeLeverageFor(entryPrice, limitPrice, isLong, mmr)
+		if safeLeverage < 1 {
+			reason := fmt.Sprintf("stop loss %.4f is within %.1f%% of liquidation even at 1x (entry %.4f)", d.StopLoss, bufferPct, entryPrice)
+			logger.Warnf("🛑 [Liquidation Guard] %s %s rejected: %s", d.Symbol, d.Action, reason)
+			d.Reasoning = fmt.Sprintf("[rejected %s: %s] %s", d.Action, reason, d.Reasoning)
+			d.Action = "wait"
+			continue
+		}
+
+		logger.Infof("🛑 [Liquidation Guard] %s %dx liquidates at %.4f, past stop loss %.4f (buffer %.1f%%), adjusted to %dx (liquidation %.4f)",
+			d.Symbol, d.Leverage, liqPrice, d.StopLoss, bufferPct, safeLeverage, liquidationAt(entryPrice, safeLeverage, isLong, mmr))
+		d.Leverage = safeLeverage
+	}
+}
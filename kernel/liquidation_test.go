@@ -0,0 +1,53 @@
+package kernel
+
+import (
+	"math"
+	"nofx/market"
+	"strings"
+	"testing"
+)
+
+func TestLiquidationPrice(t *testing.T) {
+	// 10x long at 100 with 0.4% maintenance margin liquidates ~9.6% below entry
+	if got := liquidationPrice(100, 10, true, 0.004); math.Abs(got-90.3614) > 1e-3 {
+		t.Errorf("long liquidation = %.4f, want 90.3614", got)
+	}
+	if got := liquidationPrice(100, 10, false, 0.004); math.Abs(got-109.5618) > 1e-3 {
+		t.Errorf("short liquidation = %.4f, want 109.5618", got)
+	}
+}
+
+func TestApplyLiquidationGuard(t *testing.T) {
+	marketData := map[string]*market.Data{
+		"BTCUSDT": {Symbol: "BTCUSDT", CurrentPrice: 100},
+		"SOLUSDT": {Symbol: "SOLUSDT", CurrentPrice: 100},
+	}
+	decisions := []Decision{
+		// Stop 5% below entry: safe at 10x (liquidation ~9.6% below)
+		{Symbol: "BTCUSDT", Action: "open_long", Leverage: 10, StopLoss: 95, TakeProfit: 120},
+		// Stop 8% above entry: 20x liquidates ~4.4% above, lowered until liquidation clears stop + 1%
+		{Symbol: "SOLUSDT", Action: "open_short", Leverage: 20, StopLoss: 108, TakeProfit: 80},
+		// Limit long with stop 99.5% below entry: not even 1x keeps a 1% buffer
+		{Symbol: "BTCUSDT", Action: "open_long_limit", Price: 100, Leverage: 5, StopLoss: 0.5, TakeProfit: 200},
+		// No market data: left unchanged
+		{Symbol: "DOGEUSDT", Action: "open_long", Leverage: 20, StopLoss: 0.05, TakeProfit: 0.2},
+	}
+
+	applyLiquidationGuard(decisions, "binance", marketData, 0, false)
+
+	if decisions[0].Leverage != 10 {
+		t.Errorf("safe decision should keep 10x, got %dx", decisions[0].Leverage)
+	}
+	if decisions[1].Leverage != 9 {
+		t.Errorf("short leverage should be lowered to 9x, got %dx", decisions[1].Leverage)
+	}
+	if liq := liquidationPrice(100, decisions[1].Leverage, false, 0.01); liq < 109 {
+		t.Errorf("adjusted short liquidates at %.4f, inside stop loss + buffer", liq)
+	}
+	if decisions[2].Action != "wait" || !strings.Contains(decisions[2].Reasoning, "rejected open_long_limit") {
+		t.Errorf("unsafe decision should be rejected, got %s: %s", decisions[2].Action, decisions[2].Reasoning)
+	}
+	if decisions[3].Leverage != 20 || decisions[3].Action != "open_long" {
+		t.Errorf("decision without entry price should be unchanged, got %s %dx", decisions[3].Action, decisions[3].Leverage)
+	}
+}
@@ -0,0 +1,110 @@
+package kernel
+
+import (
+	"fmt"
+	"nofx/provider/news"
+	"strings"
+	"time"
+)
+
+// DefaultNewsHeadlineLimit headlines shown in the prompt when no limit is configured
+const DefaultNewsHeadlineLimit = 10
+
+// newsEventLookahead calendar events further away than this are left out of the prompt
+const newsEventLookahead = 48 * time.Hour
+
+// maxPromptEvents upper bound on calendar events shown in the prompt
+const maxPromptEvents = 8
+
+// EventBlackout returns why new entries are blocked at now, empty when no high-impact
+// event is within windowMins minutes before or after now
+func EventBlackout(snap *news.Snapshot, windowMins int, now time.Time) string {
+	window := time.Duration(windowMins) * time.Minute
+	ev := snap.HighImpactNear(now, window)
+	if ev == nil {
+		return ""
+	}
+	return fmt.Sprintf("high-impact event %s %s at %s UTC (entries blocked %d min before/after, until %s UTC)",
+		ev.Country, ev.Title, ev.Time.UTC().Format("01-02 15:04"), windowMins, ev.Time.Add(window).UTC().Format("15:04"))
+}
+
+// formatNewsContext formats the enabled news sections for the user prompt
+func (e *StrategyEngine) formatNewsContext(snap *news.Snapshot, now time.Time) string {
+	indicators := e.config.Indicators
+	if snap == nil || (!indicators.EnableNews && !indicators.EnableEconomicCalendar) {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## 📰 News & Macro Calendar\n\n")
+
+	if indicators.EnableEconomicCalendar {
+		sb.WriteString("Upcoming economic events (UTC):\n")
+		shown := 0
+		for _, ev := range snap.Events {
+			if ev.Impact == news.ImpactLow || ev.Time.After(now.Add(newsEventLookahead)) {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("- %s %s %s [%s] %s", ev.Time.UTC().Format("01-02 15:04"), ev.Country,
+				ev.Title, strings.ToUpper(ev.Impact), relativeTime(ev.Time, now)))
+			if ev.Forecast != "" {
+				sb.WriteString(" | forecast " + ev.Forecast)
+			}
+			if ev.Previous != "" {
+				sb.WriteString(" | previous " + ev.Previous)
+			}
+			sb.WriteString("\n")
+			if shown++; shown >= maxPromptEvents {
+				break
+			}
+		}
+		if shown == 0 {
+			sb.WriteString("- No medium/high impact events in the next 48h\n")
+		}
+		if reason := EventBlackout(snap, e.config.RiskControl.BlockEntriesNearEventMins, now); reason != "" {
+			sb.WriteString("⛔ New entries are blocked: " + reason + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if indicators.EnableNews && len(snap.Headlines) > 0 {
+		limit := indicators.NewsHeadlineLimit
+		if limit <= 0 {
+			limit = DefaultNewsHeadlineLimit
+		}
+		sb.WriteString("Latest headlines:\n")
+		for i, h := range snap.Headlines {
+			if i >= limit {
+				break
+			}
+			if h.PublishedAt.IsZero() {
+				sb.WriteString(fmt.Sprintf("- %s (%s)\n", h.Title, h.Source))
+			} else {
+				sb.WriteString(fmt.Sprintf("- [%s] %s (%s)\n", relativeTime(h.PublishedAt, now), h.Title, h.Source))
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// relativeTime formats t relative to now, e.g. "in 3h20m" or "45m ago"
+func relativeTime(t, now time.Time) string {
+	d := t.Sub(now)
+	suffix, prefix := "", "in "
+	if d < 0 {
+		d = -d
+		suffix, prefix = " ago", ""
+	}
+	d = d.Round(time.Minute)
+	var s string
+	switch {
+	case d < time.Hour:
+		s = fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		s = fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		s = fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+	return prefix + s + suffix
+}
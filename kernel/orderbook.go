@@ -0,0 +1,97 @@
+package kernel
+
+import (
+	"fmt"
+	"nofx/logger"
+	"nofx/market"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultMaxDepthPct position size warning threshold, % of the visible depth an open consumes
+const DefaultMaxDepthPct = 10.0
+
+// FetchOrderBookBatch fetches order book liquidity metrics of symbols concurrently
+// Symbols whose order book cannot be fetched are left out.
+func (e *StrategyEngine) FetchOrderBookBatch(symbols []string) map[string]*market.DepthMetrics {
+	result := make(map[string]*market.DepthMetrics, len(symbols))
+	indicators := e.config.Indicators
+	if !indicators.EnableOrderBook {
+		return result
+	}
+
+	var mu sync.Mutex
+	var g errgroup.Group
+	g.SetLimit(marketDataConcurrency)
+	for _, symbol := range symbols {
+		g.Go(func() error {
+			metrics, err := market.GetDepthMetrics(symbol, indicators.OrderBookLevels)
+			if err != nil {
+				logger.Infof("⚠️  Failed to fetch order book for %s: %v", symbol, err)
+				return nil
+			}
+			mu.Lock()
+			result[symbol] = metrics
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return result
+}
+
+// formatDepthMetrics formats a symbol's order book liquidity for the user prompt
+func formatDepthMetrics(m *market.DepthMetrics) string {
+	if m == nil {
+		return ""
+	}
+	bias := "balanced"
+	switch {
+	case m.Imbalance >= 0.2:
+		bias = "bid heavy"
+	case m.Imbalance <= -0.2:
+		bias = "ask heavy"
+	}
+	return fmt.Sprintf("Order book (top %d levels): Bid %.4f / Ask %.4f | Spread %.2f bps | Bid depth %s | Ask depth %s | Imbalance %+.2f (%s)\n\n",
+		m.Levels, m.BestBid, m.BestAsk, m.SpreadBps, formatUSD(m.BidDepthUSD), formatUSD(m.AskDepthUSD), m.Imbalance, bias)
+}
+
+// formatUSD formats a notional amount compactly (e.g. 1.25M USDT)
+func formatUSD(v float64) string {
+	switch {
+	case v >= 1_000_000:
+		return fmt.Sprintf("%.2fM USDT", v/1_000_000)
+	case v >= 1_000:
+		return fmt.Sprintf("%.1fK USDT", v/1_000)
+	default:
+		return fmt.Sprintf("%.0f USDT", v)
+	}
+}
+
+// CheckDepth returns a warning when an opening decision's size exceeds maxPct of the visible
+// depth it would consume, empty otherwise
+func CheckDepth(d *Decision, m *market.DepthMetrics, maxPct float64) string {
+	if m == nil || !d.IsOpen() || d.PositionSizeUSD <= 0 {
+		return ""
+	}
+	if maxPct <= 0 {
+		maxPct = DefaultMaxDepthPct
+	}
+
+	side, bookSide := "long", "ask"
+	if strings.HasPrefix(d.Action, "open_short") {
+		side, bookSide = "short", "bid"
+	}
+	depth := m.SideDepthUSD(side)
+	if depth <= 0 {
+		return fmt.Sprintf("%s %s: no visible order book depth", d.Symbol, d.Action)
+	}
+	pct := d.PositionSizeUSD / depth * 100
+	if pct <= maxPct {
+		return ""
+	}
+	return fmt.Sprintf("%s %s size %.2f USDT is %.1f%% of visible %s-side depth (%s in top %d levels, limit %.0f%%): expect slippage",
+		d.Symbol, d.Action, d.PositionSizeUSD, pct, bookSide, formatUSD(depth), m.Levels, maxPct)
+}
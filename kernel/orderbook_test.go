@@ -0,0 +1,28 @@
+package kernel
+
+import (
+	"nofx/market"
+	"strings"
+	"testing"
+)
+
+func TestCheckDepth(t *testing.T) {
+	m := &market.DepthMetrics{Symbol: "SOLUSDT", Levels: 20, BidDepthUSD: 50_000, AskDepthUSD: 10_000}
+
+	if w := CheckDepth(&Decision{Symbol: "SOLUSDT", Action: "open_long", PositionSizeUSD: 900}, m, 0); w != "" {
+		t.Errorf("9%% of ask depth is within the default limit, got %q", w)
+	}
+	w := CheckDepth(&Decision{Symbol: "SOLUSDT", Action: "open_long", PositionSizeUSD: 2_000}, m, 0)
+	if !strings.Contains(w, "20.0% of visible ask-side depth") {
+		t.Errorf("expected ask-side warning, got %q", w)
+	}
+	if w := CheckDepth(&Decision{Symbol: "SOLUSDT", Action: "open_short_limit", PositionSizeUSD: 2_000}, m, 0); w != "" {
+		t.Errorf("short consumes the deeper bid side, got %q", w)
+	}
+	if w := CheckDepth(&Decision{Symbol: "SOLUSDT", Action: "open_short", PositionSizeUSD: 2_000}, m, 2); w == "" {
+		t.Error("expected warning with a 2% limit")
+	}
+	if w := CheckDepth(&Decision{Symbol: "SOLUSDT", Action: "close_long", PositionSizeUSD: 1e9}, m, 0); w != "" {
+		t.Errorf("closes are not checked, got %q", w)
+	}
+}
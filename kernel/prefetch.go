@@ -0,0 +1,221 @@
+package kernel
+
+import (
+	"fmt"
+	"nofx/logger"
+	"nofx/market"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// ============================================================================
+// Concurrent Market Data Fetching & Cold-Start Warmup
+// ============================================================================
+// Multi-timeframe data of all position and candidate symbols is fetched
+// concurrently (bounded parallelism, per-symbol timeout); symbols that fail or
+// time out are skipped and reported instead of failing the cycle. On trader start a
+// warmup fetch runs in the background while the rest of the startup (exchange
+// syncs, account queries) proceeds; the first decision cycle consumes it.
+// ============================================================================
+
+const (
+	// DefaultMarketDataConcurrency max symbols fetched in parallel (bounded to stay within data API rate limits)
+	DefaultMarketDataConcurrency = 8
+	// DefaultMarketDataSymbolTimeout max time to fetch all timeframes of one symbol
+	DefaultMarketDataSymbolTimeout = 20 * time.Second
+	// warmupDataTTL warmup data older than this is discarded and fetched again
+	warmupDataTTL = 2 * time.Minute
+)
+
+var (
+	marketDataConcurrency   = DefaultMarketDataConcurrency
+	marketDataSymbolTimeout = DefaultMarketDataSymbolTimeout
+)
+
+// SetMarketDataFetchLimits sets the concurrency limit and per-symbol timeout of market data fetching
+// Non-positive values keep the defaults. Call at startup, before traders run.
+func SetMarketDataFetchLimits(concurrency int, symbolTimeout time.Duration) {
+	if concurrency > 0 {
+		marketDataConcurrency = concurrency
+	}
+	if symbolTimeout > 0 {
+		marketDataSymbolTimeout = symbolTimeout
+	}
+}
+
+// getMarketData fetches multi-timeframe data of one symbol (replaceable in tests)
+var getMarketData = market.GetWithTimeframesFrom
+
+// marketWarmup a background prefetch started on trader start
+type marketWarmup struct {
+	done      chan struct{}
+	data      map[string]*market.Data
+	fetchedAt time.Time
+}
+
+// klineSettings returns the timeframes, primary timeframe and kline count of the strategy
+func (e *StrategyEngine) klineSettings() (timeframes []string, primaryTimeframe string, klineCount int) {
+	klines := e.config.Indicators.Klines
+	timeframes = append([]string(nil), klines.SelectedTimeframes...)
+	primaryTimeframe = klines.PrimaryTimeframe
+	klineCount = klines.PrimaryCount
+
+	// Compatible with old configuration
+	if len(timeframes) == 0 {
+		if primaryTimeframe != "" {
+			timeframes = append(timeframes, primaryTimeframe)
+		} else {
+			timeframes = append(timeframes, "3m")
+		}
+		if klines.LongerTimeframe != "" {
+			timeframes = append(timeframes, klines.LongerTimeframe)
+		}
+	}
+	if primaryTimeframe == "" {
+		primaryTimeframe = timeframes[0]
+	}
+	if klineCount <= 0 {
+		klineCount = 30
+	}
+	return timeframes, primaryTimeframe, klineCount
+}
+
+// fetchMarketDataConcurrently fetches market data of symbols concurrently, tolerating partial failure
+// Returns the fetched data and the reason of each skipped symbol (fetch error or timeout)
+func (e *StrategyEngine) fetchMarketDataConcurrently(symbols []string) (map[string]*market.Data, map[string]string) {
+	timeframes, primaryTimeframe, klineCount := e.klineSettings()
+	timeframes = e.WithSummaryTimeframes(timeframes)
+	result := make(map[string]*market.Data, len(symbols))
+	skipped := make(map[string]string)
+	if len(symbols) == 0 {
+		return result, skipped
+	}
+
+	start := time.Now()
+	timeout := marketDataSymbolTimeout
+	var mu sync.Mutex
+	var g errgroup.Group
+	// A slot is held until the fetch returns, also when it was abandoned past its timeout,
+	// so slow symbols never push the requests in flight beyond the limit
+	slots := make(chan struct{}, marketDataConcurrency)
+	for _, symbol := range symbols {
+		if !acquireFetchSlot(slots, timeout) {
+			mu.Lock()
+			skipped[symbol] = fmt.Sprintf("no fetch slot freed within %v", timeout)
+			mu.Unlock()
+			continue
+		}
+		g.Go(func() error {
+			data, err := fetchSymbolWithTimeout(symbol, timeframes, primaryTimeframe, klineCount, e.config.Indicators.MarketDataProviders, timeout, func() { <-slots })
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				skipped[symbol] = err.Error()
+			} else {
+				result[symbol] = data
+			}
+			return nil // Partial failure tolerated, the symbol is skipped
+		})
+	}
+	_ = g.Wait()
+
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if len(skipped) > 0 {
+		logger.Infof("⚠️  Market data: fetched %d/%d symbols in %v, skipped: %s", len(result), len(symbols), elapsed, FormatSkippedSymbols(skipped))
+	} else {
+		logger.Infof("📊 Market data: fetched %d symbols in %v", len(result), elapsed)
+	}
+	return result, skipped
+}
+
+// acquireFetchSlot waits up to timeout for a free fetch slot
+// Only abandoned fetches still running can keep all slots busy that long.
+func acquireFetchSlot(slots chan struct{}, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// fetchSymbolWithTimeout fetches one symbol, giving up after timeout
+// (the abandoned request finishes in the background and its result is dropped).
+// release is called once the request has returned, timed out or not.
+func fetchSymbolWithTimeout(symbol string, timeframes []string, primaryTimeframe string, klineCount int, providers []string, timeout time.Duration, release func()) (*market.Data, error) {
+	type fetchResult struct {
+		data *market.Data
+		err  error
+	}
+	done := make(chan fetchResult, 1)
+	fetch := getMarketData
+	go func() {
+		defer release()
+		data, err := fetch(symbol, timeframes, primaryTimeframe, klineCount, providers)
+		done <- fetchResult{data: data, err: err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.data, r.err
+	case <-timer.C:
+		return nil, fmt.Errorf("timed out after %v", timeout)
+	}
+}
+
+// FormatSkippedSymbols formats skipped symbols and their reasons in a stable order
+func FormatSkippedSymbols(skipped map[string]string) string {
+	symbols := make([]string, 0, len(skipped))
+	for symbol := range skipped {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	parts := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		parts[i] = fmt.Sprintf("%s (%s)", symbol, skipped[symbol])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// StartWarmup prefetches market data in the background for the next decision cycle
+// symbols is resolved inside the background goroutine (it may query the coin source and exchange)
+func (e *StrategyEngine) StartWarmup(symbols func() []string) {
+	warmup := &marketWarmup{done: make(chan struct{})}
+	e.warmupMu.Lock()
+	e.warmup = warmup
+	e.warmupMu.Unlock()
+
+	go func() {
+		defer close(warmup.done)
+		start := time.Now()
+		list := symbols()
+		warmup.data, _ = e.fetchMarketDataConcurrently(list) // Skipped symbols are retried by the cycle
+		warmup.fetchedAt = time.Now()
+		logger.Infof("🔥 Market data warmup: %d/%d symbols in %v", len(warmup.data), len(list), time.Since(start).Round(time.Millisecond))
+	}()
+}
+
+// takeWarmup waits for a pending warmup and returns its data once (nil if none or stale)
+func (e *StrategyEngine) takeWarmup() map[string]*market.Data {
+	e.warmupMu.Lock()
+	warmup := e.warmup
+	e.warmup = nil
+	e.warmupMu.Unlock()
+	if warmup == nil {
+		return nil
+	}
+
+	<-warmup.done
+	if time.Since(warmup.fetchedAt) > warmupDataTTL {
+		return nil
+	}
+	return warmup.data
+}
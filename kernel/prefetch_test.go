@@ -0,0 +1,157 @@
+package kernel
+
+import (
+	"fmt"
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stubMarketData replaces the market data fetcher for the duration of a test
+func stubMarketData(t *testing.T, fetch func(symbol string) (*market.Data, error)) {
+	t.Helper()
+	orig := getMarketData
+	getMarketData = func(symbol string, _ []string, _ string, _ int, _ []string) (*market.Data, error) {
+		return fetch(symbol)
+	}
+	t.Cleanup(func() { getMarketData = orig })
+}
+
+func TestFetchMarketDataConcurrently_BoundedParallelism(t *testing.T) {
+	var inFlight, maxInFlight int32
+	stubMarketData(t, func(symbol string) (*market.Data, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		if symbol == "BADUSDT" {
+			return nil, fmt.Errorf("no data")
+		}
+		return &market.Data{Symbol: symbol, CurrentPrice: 1}, nil
+	})
+
+	symbols := []string{"BADUSDT"}
+	for i := 0; i < 30; i++ {
+		symbols = append(symbols, fmt.Sprintf("C%dUSDT", i))
+	}
+	config := store.GetDefaultStrategyConfig("en")
+	data, skipped := NewStrategyEngine(&config).fetchMarketDataConcurrently(symbols)
+
+	if len(data) != 30 {
+		t.Errorf("expected 30 symbols (failed one skipped), got %d", len(data))
+	}
+	if skipped["BADUSDT"] != "no data" || len(skipped) != 1 {
+		t.Errorf("failed symbol should be reported as skipped, got %v", skipped)
+	}
+	if maxInFlight > DefaultMarketDataConcurrency || maxInFlight < 2 {
+		t.Errorf("parallelism should be bounded by %d, got %d", DefaultMarketDataConcurrency, maxInFlight)
+	}
+}
+
+func TestFetchMarketDataConcurrently_SymbolTimeout(t *testing.T) {
+	SetMarketDataFetchLimits(0, 50*time.Millisecond)
+	t.Cleanup(func() { SetMarketDataFetchLimits(DefaultMarketDataConcurrency, DefaultMarketDataSymbolTimeout) })
+	stubMarketData(t, func(symbol string) (*market.Data, error) {
+		if symbol == "SLOWUSDT" {
+			time.Sleep(time.Second)
+		}
+		return &market.Data{Symbol: symbol, CurrentPrice: 1}, nil
+	})
+
+	config := store.GetDefaultStrategyConfig("en")
+	start := time.Now()
+	data, skipped := NewStrategyEngine(&config).fetchMarketDataConcurrently([]string{"BTCUSDT", "SLOWUSDT"})
+
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("slow symbol should not hold up the cycle, took %v", time.Since(start))
+	}
+	if _, ok := data["BTCUSDT"]; !ok || len(data) != 1 {
+		t.Errorf("expected only BTCUSDT, got %d symbols", len(data))
+	}
+	if !strings.Contains(skipped["SLOWUSDT"], "timed out") {
+		t.Errorf("slow symbol should be skipped with a timeout, got %v", skipped)
+	}
+	if got := FormatSkippedSymbols(skipped); got != "SLOWUSDT (timed out after 50ms)" {
+		t.Errorf("unexpected skipped summary: %s", got)
+	}
+}
+
+func TestFetchMarketDataConcurrently_TimedOutFetchHoldsSlot(t *testing.T) {
+	SetMarketDataFetchLimits(1, 50*time.Millisecond)
+	t.Cleanup(func() { SetMarketDataFetchLimits(DefaultMarketDataConcurrency, DefaultMarketDataSymbolTimeout) })
+	var inFlight, maxInFlight int32
+	stubMarketData(t, func(symbol string) (*market.Data, error) {
+		if n := atomic.AddInt32(&inFlight, 1); n > atomic.LoadInt32(&maxInFlight) {
+			atomic.StoreInt32(&maxInFlight, n)
+		}
+		defer atomic.AddInt32(&inFlight, -1)
+		if symbol == "SLOWUSDT" {
+			time.Sleep(300 * time.Millisecond)
+		}
+		return &market.Data{Symbol: symbol, CurrentPrice: 1}, nil
+	})
+
+	config := store.GetDefaultStrategyConfig("en")
+	start := time.Now()
+	data, skipped := NewStrategyEngine(&config).fetchMarketDataConcurrently([]string{"SLOWUSDT", "BTCUSDT"})
+
+	// The abandoned slow request still occupies the only slot, so BTCUSDT is not fetched alongside it
+	if time.Since(start) > 250*time.Millisecond {
+		t.Errorf("waiting for a slot should be bounded by the timeout, took %v", time.Since(start))
+	}
+	if len(data) != 0 || !strings.Contains(skipped["BTCUSDT"], "no fetch slot") {
+		t.Errorf("expected BTCUSDT skipped for lack of a slot, got %d symbols, skipped %v", len(data), skipped)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if n := atomic.LoadInt32(&maxInFlight); n != 1 {
+		t.Errorf("requests in flight should stay within the limit, got %d", n)
+	}
+}
+
+func TestFetchMarketDataWithStrategy_UsesWarmup(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	stubMarketData(t, func(symbol string) (*market.Data, error) {
+		mu.Lock()
+		calls[symbol]++
+		mu.Unlock()
+		return &market.Data{Symbol: symbol, CurrentPrice: 1}, nil
+	})
+
+	config := store.GetDefaultStrategyConfig("en")
+	engine := NewStrategyEngine(&config)
+	engine.StartWarmup(func() []string { return []string{"BTCUSDT", "ETHUSDT"} })
+
+	ctx := &Context{
+		Positions:      []PositionInfo{{Symbol: "BTCUSDT"}},
+		CandidateCoins: []CandidateCoin{{Symbol: "BTCUSDT"}, {Symbol: "ETHUSDT"}, {Symbol: "SOLUSDT"}},
+	}
+	if err := fetchMarketDataWithStrategy(ctx, engine); err != nil {
+		t.Fatal(err)
+	}
+	if len(ctx.MarketDataMap) != 3 {
+		t.Errorf("expected 3 symbols, got %d", len(ctx.MarketDataMap))
+	}
+	for symbol, n := range calls {
+		if n != 1 {
+			t.Errorf("%s fetched %d times, warmup data should be reused", symbol, n)
+		}
+	}
+
+	// Warmup is consumed once, the next cycle fetches fresh data
+	if err := fetchMarketDataWithStrategy(ctx, engine); err != nil {
+		t.Fatal(err)
+	}
+	if calls["BTCUSDT"] != 2 {
+		t.Errorf("second cycle should fetch again, BTCUSDT fetched %d times", calls["BTCUSDT"])
+	}
+}
@@ -0,0 +1,143 @@
+package kernel
+
+import (
+	"fmt"
+	"nofx/market"
+	"nofx/mcp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ============================================================================
+// Prompt Token Budget
+// ============================================================================
+// Large candidate sets can produce prompts beyond the model's context window,
+// which providers truncate or reject. Before calling the AI, the estimated size
+// of system + user prompt is checked against the strategy's budget (default:
+// the model's context window minus room for the response). Oversized prompts
+// are rebuilt from a trimmed copy of the context, least valuable data first:
+//   1. recent closed trades and trade reflections (oldest first),
+//   2. kline/indicator series length (halved down to minPromptKlines),
+//   3. candidate coins (lowest ranked first; positions are always kept).
+// Every trim is reported so it lands in the cycle's execution log.
+// ============================================================================
+
+const (
+	// promptResponseReserve tokens of the context window left for the AI response
+	promptResponseReserve = 8000
+	// minPromptKlines series are never trimmed below this many points
+	minPromptKlines = 10
+)
+
+// EstimateTokens roughly estimates the token count of a prompt
+// Deliberately conservative: ~3 ASCII characters (numbers tokenize poorly) or one other character (CJK) per token.
+func EstimateTokens(s string) int {
+	ascii := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] < utf8.RuneSelf {
+			ascii++
+		}
+	}
+	other := utf8.RuneCountInString(s) - ascii
+	return (ascii+2)/3 + other
+}
+
+// PromptTokenBudget returns the prompt token budget for a model client
+func (e *StrategyEngine) PromptTokenBudget(client mcp.AIClient) int {
+	if budget := e.config.PromptBudget.MaxPromptTokens; budget > 0 {
+		return budget
+	}
+	return mcp.ContextWindowOf(client) - promptResponseReserve
+}
+
+// buildUserPromptWithinBudget builds the user prompt, trimming context until it fits with the system prompt in budget tokens
+// Returns the prompt and a summary of what was trimmed (empty when it fit); ctx itself is never modified.
+func (e *StrategyEngine) buildUserPromptWithinBudget(ctx *Context, systemPrompt string, budget int) (string, string) {
+	userPrompt := e.BuildUserPrompt(ctx)
+	systemTokens := EstimateTokens(systemPrompt)
+	fits := func() bool { return systemTokens+EstimateTokens(userPrompt) <= budget }
+	if budget <= 0 || fits() {
+		return userPrompt, ""
+	}
+
+	before := systemTokens + EstimateTokens(userPrompt)
+	trimmed := *ctx
+	var notes []string
+
+	// 1. Recent closed trades, listed newest first
+	if total := len(trimmed.RecentOrders); total > 0 {
+		for len(trimmed.RecentOrders) > 0 && !fits() {
+			trimmed.RecentOrders = trimmed.RecentOrders[:len(trimmed.RecentOrders)/2]
+			userPrompt = e.BuildUserPrompt(&trimmed)
+		}
+		notes = append(notes, fmt.Sprintf("recent trades %d → %d", total, len(trimmed.RecentOrders)))
+	}
+	if total := len(trimmed.Reflections); total > 0 && !fits() {
+		for len(trimmed.Reflections) > 0 && !fits() {
+			trimmed.Reflections = trimmed.Reflections[:len(trimmed.Reflections)/2]
+			userPrompt = e.BuildUserPrompt(&trimmed)
+		}
+		notes = append(notes, fmt.Sprintf("reflections %d → %d", total, len(trimmed.Reflections)))
+	}
+
+	// 2. Series length of every timeframe
+	if longest := longestSeries(trimmed.MarketDataMap); !fits() && longest > minPromptKlines {
+		limit := longest
+		source := trimmed.MarketDataMap
+		for limit > minPromptKlines && !fits() {
+			limit = max(limit/2, minPromptKlines)
+			trimmed.MarketDataMap = make(map[string]*market.Data, len(source))
+			for symbol, data := range source {
+				trimmed.MarketDataMap[symbol] = data.WithSeriesLimit(limit)
+			}
+			userPrompt = e.BuildUserPrompt(&trimmed)
+		}
+		notes = append(notes, fmt.Sprintf("klines per series %d → %d", longest, limit))
+	}
+
+	// 3. Candidate coins, lowest ranked first
+	if !fits() {
+		held := make(map[string]bool, len(trimmed.Positions))
+		for _, pos := range trimmed.Positions {
+			held[market.Normalize(pos.Symbol)] = true
+		}
+		var dropped []string
+		coins := append([]CandidateCoin(nil), trimmed.CandidateCoins...)
+		dataMap := make(map[string]*market.Data, len(trimmed.MarketDataMap))
+		for symbol, data := range trimmed.MarketDataMap {
+			dataMap[symbol] = data
+		}
+		trimmed.MarketDataMap = dataMap
+		for i := len(coins) - 1; i >= 0 && !fits(); i-- {
+			if held[market.Normalize(coins[i].Symbol)] {
+				continue
+			}
+			dropped = append(dropped, coins[i].Symbol)
+			delete(dataMap, coins[i].Symbol)
+			coins = append(coins[:i], coins[i+1:]...)
+			trimmed.CandidateCoins = coins
+			userPrompt = e.BuildUserPrompt(&trimmed)
+		}
+		if len(dropped) > 0 {
+			notes = append(notes, fmt.Sprintf("dropped %d candidate coins (%s)", len(dropped), strings.Join(dropped, ", ")))
+		}
+	}
+
+	after := systemTokens + EstimateTokens(userPrompt)
+	summary := fmt.Sprintf("Prompt trimmed to fit %d token budget (~%d → ~%d tokens): %s", budget, before, after, strings.Join(notes, ", "))
+	if after > budget {
+		summary = fmt.Sprintf("Prompt still over %d token budget after trimming (~%d → ~%d tokens): %s", budget, before, after, strings.Join(notes, ", "))
+	}
+	return userPrompt, summary
+}
+
+// longestSeries returns the most points of any series in the market data
+func longestSeries(dataMap map[string]*market.Data) int {
+	longest := 0
+	for _, data := range dataMap {
+		for _, series := range data.TimeframeData {
+			longest = max(longest, max(len(series.Klines), len(series.MidPrices)))
+		}
+	}
+	return longest
+}
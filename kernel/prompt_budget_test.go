@@ -0,0 +1,73 @@
+package kernel
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"nofx/market"
+	"nofx/store"
+)
+
+func budgetTestContext(coins, klines int) *Context {
+	ctx := &Context{
+		Account:       AccountInfo{TotalEquity: 1000, AvailableBalance: 1000},
+		Positions:     []PositionInfo{{Symbol: "SOLUSDT", Side: "long", EntryPrice: 100, MarkPrice: 101, Quantity: 1, Leverage: 2}},
+		MarketDataMap: map[string]*market.Data{},
+	}
+	for i := 0; i < 20; i++ {
+		ctx.RecentOrders = append(ctx.RecentOrders, RecentOrder{Symbol: "BTCUSDT", Side: "long", EntryPrice: 100, ExitPrice: 101})
+	}
+	symbols := []string{"SOLUSDT"}
+	for i := 0; i < coins; i++ {
+		symbols = append(symbols, fmt.Sprintf("COIN%dUSDT", i))
+	}
+	for _, symbol := range symbols {
+		series := &market.TimeframeSeriesData{Timeframe: "5m"}
+		for k := 0; k < klines; k++ {
+			series.Klines = append(series.Klines, market.KlineBar{Time: int64(k) * 300000, Open: 1, High: 2, Low: 0.5, Close: 1.5, Volume: 1000})
+		}
+		ctx.MarketDataMap[symbol] = &market.Data{Symbol: symbol, CurrentPrice: 1.5, TimeframeData: map[string]*market.TimeframeSeriesData{"5m": series}}
+		ctx.CandidateCoins = append(ctx.CandidateCoins, CandidateCoin{Symbol: symbol, Sources: []string{"ai500"}})
+	}
+	return ctx
+}
+
+func TestBuildUserPromptWithinBudget(t *testing.T) {
+	config := store.GetDefaultStrategyConfig("en")
+	engine := NewStrategyEngine(&config)
+	ctx := budgetTestContext(30, 100)
+
+	full := engine.BuildUserPrompt(ctx)
+	if prompt, trimmed := engine.buildUserPromptWithinBudget(ctx, "system", EstimateTokens(full)+10); prompt != full || trimmed != "" {
+		t.Fatalf("a prompt within budget must not be trimmed, got %q", trimmed)
+	}
+
+	budget := EstimateTokens(full) / 10
+	prompt, trimmed := engine.buildUserPromptWithinBudget(ctx, "system", budget)
+	if EstimateTokens("system")+EstimateTokens(prompt) > budget {
+		t.Errorf("trimmed prompt ~%d tokens exceeds budget %d: %s", EstimateTokens(prompt), budget, trimmed)
+	}
+	for _, want := range []string{"recent trades 20 → 0", "klines per series 100 → ", "candidate coins"} {
+		if !strings.Contains(trimmed, want) {
+			t.Errorf("trim summary %q should mention %q", trimmed, want)
+		}
+	}
+	if !strings.Contains(prompt, "SOLUSDT") {
+		t.Error("position symbols must never be trimmed")
+	}
+
+	// The caller's context is left intact for execution
+	if len(ctx.RecentOrders) != 20 || len(ctx.CandidateCoins) != 31 || len(ctx.MarketDataMap["COIN0USDT"].TimeframeData["5m"].Klines) != 100 {
+		t.Error("context must not be modified by trimming")
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	if got := EstimateTokens("abcdef"); got != 2 {
+		t.Errorf("ascii estimate = %d, want 2", got)
+	}
+	if got := EstimateTokens("持仓"); got != 2 {
+		t.Errorf("CJK estimate = %d, want 2", got)
+	}
+}
@@ -0,0 +1,196 @@
+package kernel
+
+import "strings"
+
+// ============================================================================
+// Localized prompt templates for the directional (AI trading) engine
+// ============================================================================
+// The strategy's Language setting selects the template set. JSON keys and
+// action values stay in English for every language so decisions parse the same.
+// ============================================================================
+
+// promptText localized fixed text of system/user prompts
+type promptText struct {
+	// System prompt
+	DefaultRole         string
+	ModeAggressive      string
+	ModeConservative    string
+	ModeScalping        string
+	HardConstraints     string
+	CodeEnforced        string
+	MaxPositions        string // %d
+	AltcoinValueLimit   string // %.0f %.0f %.1f
+	BTCETHValueLimit    string // %.0f %.0f %.1f
+	MaxMarginUsage      string // %.0f
+	MinPositionSize     string // %.0f
+	AIGuided            string
+	LeverageGuide       string // %d %d
+	RiskRewardGuide     string // %.1f
+	MinConfidenceGuide  string // %d
+	PositionSizing      string // %.0f %.1f %.0f
+	RegimeConstraints   string
+	RegimeLine          string // %s %s %.1f %.1f %d %.0f
+	DefaultFrequency    string
+	IndicatorIntro      string
+	ConfidenceRequired  string // %d
+	DefaultEntryHeader  string
+	DefaultEntryFooter  string // %d
+	DefaultProcess      string
+	OutputFormatHeader  string
+	FieldDescription    string
+	ActionField         string
+	LimitEntryField     string // %d %d
+	TakeProfitLevels    string
+	ConfidenceField     string // %d
+	RequiredWhenOpening string
+	NumericValues       string
+	CustomPromptHeader  string
+	CustomPromptFooter  string
+	ResponseLanguage    string
+	FinalInstruction    string
+	// User prompt
+	StatusLine       string // %s %d %d
+	AccountLine      string // %.2f %.2f %.1f %+.2f %.1f %d
+	InverseAccount   string // %s %s %.2f %.2f %s %.0f
+	PendingEntries   string
+	PendingEntryLine string // %d %s %s %.4f %.4f %.4f %.4f %d
+	RecentTrades     string
+	TradeProfit      string
+	TradeLoss        string
+	TradeLine        string // %d %s %s %.4f %.4f %s %+.2f %+.2f %s %s %s
+	Reflections      string
+	ReflectionLine   string // %d %s %s %s %+.2f %s %s
+	CurrentPositions string
+	NoPositions      string
+	CandidateCoins   string // %d
+}
+
+var promptTexts = map[Language]*promptText{
+	LangEnglish: {
+		DefaultRole:         "# You are a professional cryptocurrency trading AI\n\nYour task is to make trading decisions based on provided market data.\n\n",
+		ModeAggressive:      "## Mode: Aggressive\n- Prioritize capturing trend breakouts, can build positions in batches when confidence ≥ 70\n- Allow higher positions, but must strictly set stop-loss and explain risk-reward ratio\n\n",
+		ModeConservative:    "## Mode: Conservative\n- Only open positions when multiple signals resonate\n- Prioritize cash preservation, must pause for multiple periods after consecutive losses\n\n",
+		ModeScalping:        "## Mode: Scalping\n- Focus on short-term momentum, smaller profit targets but require quick action\n- If price doesn't move as expected within two bars, immediately reduce position or stop-loss\n\n",
+		HardConstraints:     "# Hard Constraints (Risk Control)\n\n",
+		CodeEnforced:        "## CODE ENFORCED (Backend validation, cannot be bypassed):\n",
+		MaxPositions:        "- Max Positions: %d coins simultaneously\n",
+		AltcoinValueLimit:   "- Position Value Limit (Altcoins): max %.0f USDT (= equity %.0f × %.1fx)\n",
+		BTCETHValueLimit:    "- Position Value Limit (BTC/ETH): max %.0f USDT (= equity %.0f × %.1fx)\n",
+		MaxMarginUsage:      "- Max Margin Usage: ≤%.0f%%\n",
+		MinPositionSize:     "- Min Position Size: ≥%.0f USDT\n\n",
+		AIGuided:            "## AI GUIDED (Recommended, you should follow):\n",
+		LeverageGuide:       "- Trading Leverage: Altcoins max %dx | BTC/ETH max %dx\n",
+		RiskRewardGuide:     "- Risk-Reward Ratio: ≥1:%.1f (take_profit / stop_loss)\n",
+		MinConfidenceGuide:  "- Min Confidence: ≥%d to open position\n\n",
+		PositionSizing:      "## Position Sizing Guidance\nCalculate `position_size_usd` based on your confidence and the Position Value Limits above:\n- High confidence (≥85): Use 80-100%% of max position value limit\n- Medium confidence (70-84): Use 50-80%% of max position value limit\n- Low confidence (60-69): Use 30-50%% of max position value limit\n- Example: With equity %.0f and BTC/ETH ratio %.1fx, max is %.0f USDT\n- **DO NOT** just use available_balance as position_size_usd. Use the Position Value Limits!\n\n",
+		RegimeConstraints:   "## Market Regime Limits (CODE ENFORCED, override the limits above for these coins):\n",
+		RegimeLine:          "- %s: %s (Bollinger width %.1f%%, ATR %.1f%%) → leverage max %dx | position value max %.0f USDT\n",
+		DefaultFrequency:    "# ⏱️ Trading Frequency Awareness\n\n- Excellent traders: 2-4 trades/day ≈ 0.1-0.2 trades/hour\n- >2 trades/hour = Overtrading\n- Single position hold time ≥ 30-60 minutes\nIf you find yourself trading every period → standards too low; if closing positions < 30 minutes → too impatient.\n\n",
+		IndicatorIntro:      "\n\nYou have the following indicator data:\n",
+		ConfidenceRequired:  "\n**Confidence ≥ %d** required to open positions.\n\n",
+		DefaultEntryHeader:  "# 🎯 Entry Standards (Strict)\n\nOnly open positions when multiple signals resonate. You have:\n",
+		DefaultEntryFooter:  "\nFeel free to use any effective analysis method, but **confidence ≥ %d** required to open positions; avoid low-quality behaviors such as single indicators, contradictory signals, sideways consolidation, reopening immediately after closing, etc.\n\n",
+		DefaultProcess:      "# 📋 Decision Process\n\n1. Check positions → Should we take profit/stop-loss\n2. Scan candidate coins + multi-timeframe → Are there strong signals\n3. Write chain of thought first, then output structured JSON\n\n",
+		OutputFormatHeader:  "# Output Format (Strictly Follow)\n\n**Must use XML tags <reasoning> and <decision> to separate chain of thought and decision JSON, avoiding parsing errors**\n\n## Format Requirements\n\n<reasoning>\nYour chain of thought analysis...\n- Briefly analyze your thinking process \n</reasoning>\n\n<decision>\nStep 2: JSON decision array\n\n",
+		FieldDescription:    "## Field Description\n\n",
+		ActionField:         "- `action`: open_long | open_short | open_long_limit | open_short_limit | close_long | close_short | hold | wait\n",
+		LimitEntryField:     "- `open_long_limit` / `open_short_limit`: enter with a limit order instead of market; also requires `price` (limit entry price, long below / short above current price) and optional `expiry_minutes` (default %d, max %d). Stop loss/take profit are placed after the order fills\n",
+		TakeProfitLevels:    "- `take_profit_levels` (optional, market entries): partial take profits `[{\"price\": 0, \"close_pct\": 50}, ...]`, each closing `close_pct` percent of the position; when they add up to less than 100 the rest runs on, with `trailing_stop_pct` (optional, percent below the peak for longs / above the low for shorts) trailing its stop loss once the first level fills\n",
+		ConfidenceField:     "- `confidence`: 0-100 (opening recommended ≥ %d)\n",
+		RequiredWhenOpening: "- Required when opening: leverage, position_size_usd, stop_loss, take_profit, confidence, risk_usd\n",
+		NumericValues:       "- **IMPORTANT**: All numeric values must be calculated numbers, NOT formulas/expressions (e.g., use `27.76` not `3000 * 0.01`)\n\n",
+		CustomPromptHeader:  "# 📌 Personalized Trading Strategy\n\n",
+		CustomPromptFooter:  "Note: The above personalized strategy is a supplement to the basic rules and cannot violate the basic risk control principles.\n",
+		ResponseLanguage:    "# Response Language\n\nWrite the <reasoning> section and every `reasoning` field in English. Keep JSON keys and `action` values exactly as specified.\n\n",
+		FinalInstruction:    "Now please analyze and output your decision (Chain of Thought + JSON)\n",
+
+		StatusLine:       "Time: %s | Period: #%d | Runtime: %d minutes\n\n",
+		AccountLine:      "Account: Equity %.2f | Balance %.2f (%.1f%%) | PnL %+.2f%% | Margin %.1f%% | Positions %d\n\n",
+		InverseAccount:   "Inverse (coin-margined) contracts: account amounts are in %s (1 %s = %.2f USD, equity ≈ %.2f USD). Only %s can be opened; position_size_usd is in USD, rounded down to whole %.0f USD contracts\n\n",
+		PendingEntries:   "## Pending Limit Entries\n",
+		PendingEntryLine: "%d. %s %s | Limit %.4f | Qty %.4f | SL %.4f TP %.4f | Expires in %d min\n",
+		RecentTrades:     "## Recent Completed Trades\n",
+		TradeProfit:      "Profit",
+		TradeLoss:        "Loss",
+		TradeLine:        "%d. %s %s | Entry %.4f Exit %.4f | %s: %+.2f USDT (%+.2f%%) | %s→%s (%s)\n",
+		Reflections:      "## Lessons From Your Recent Trades\nYour own reviews of recently closed positions, newest first. Apply them, do not repeat the mistakes.\n",
+		ReflectionLine:   "%d. [%s] %s %s %+.2f%% (%s): %s\n",
+		CurrentPositions: "## Current Positions\n",
+		NoPositions:      "Current Positions: None\n\n",
+		CandidateCoins:   "## Candidate Coins (%d coins)\n\n",
+	},
+	LangChinese: {
+		DefaultRole:         "# 你是一名专业的加密货币交易AI\n\n你的任务是根据提供的市场数据做出交易决策。\n\n",
+		ModeAggressive:      "## 模式：激进\n- 优先捕捉趋势突破，信心度 ≥ 70 时可分批建仓\n- 允许更高仓位，但必须严格设置止损并说明风险回报比\n\n",
+		ModeConservative:    "## 模式：保守\n- 仅在多个信号共振时开仓\n- 优先保住本金，连续亏损后必须暂停多个周期\n\n",
+		ModeScalping:        "## 模式：剥头皮\n- 专注短期动量，止盈目标更小但需快速行动\n- 若价格在两根K线内未按预期运行，立即减仓或止损\n\n",
+		HardConstraints:     "# 硬性约束（风控）\n\n",
+		CodeEnforced:        "## 代码强制（后端校验，无法绕过）：\n",
+		MaxPositions:        "- 最大持仓数：同时最多 %d 个币种\n",
+		AltcoinValueLimit:   "- 仓位价值上限（山寨币）：最多 %.0f USDT（= 净值 %.0f × %.1f 倍）\n",
+		BTCETHValueLimit:    "- 仓位价值上限（BTC/ETH）：最多 %.0f USDT（= 净值 %.0f × %.1f 倍）\n",
+		MaxMarginUsage:      "- 最大保证金使用率：≤%.0f%%\n",
+		MinPositionSize:     "- 最小开仓金额：≥%.0f USDT\n\n",
+		AIGuided:            "## AI 指导（建议遵守）：\n",
+		LeverageGuide:       "- 交易杠杆：山寨币最高 %d 倍 | BTC/ETH 最高 %d 倍\n",
+		RiskRewardGuide:     "- 风险回报比：≥1:%.1f（止盈 / 止损）\n",
+		MinConfidenceGuide:  "- 最低信心度：≥%d 才可开仓\n\n",
+		PositionSizing:      "## 仓位计算指引\n根据信心度和上述仓位价值上限计算 `position_size_usd`：\n- 高信心（≥85）：使用仓位价值上限的 80-100%%\n- 中等信心（70-84）：使用仓位价值上限的 50-80%%\n- 低信心（60-69）：使用仓位价值上限的 30-50%%\n- 示例：净值 %.0f、BTC/ETH 倍数 %.1f 倍时，上限为 %.0f USDT\n- **不要**直接把 available_balance 当作 position_size_usd，请使用仓位价值上限！\n\n",
+		RegimeConstraints:   "## 市场状态限制（代码强制，对以下币种优先于上述上限）：\n",
+		RegimeLine:          "- %s：%s（布林带宽 %.1f%%，ATR %.1f%%）→ 杠杆最高 %d 倍 | 仓位价值最多 %.0f USDT\n",
+		DefaultFrequency:    "# ⏱️ 交易频率意识\n\n- 优秀交易员：每天 2-4 笔 ≈ 每小时 0.1-0.2 笔\n- 每小时 >2 笔 = 过度交易\n- 单笔持仓时间 ≥ 30-60 分钟\n如果你发现每个周期都在交易 → 标准太低；如果持仓不到 30 分钟就平仓 → 太急躁。\n\n",
+		IndicatorIntro:      "\n\n你拥有以下指标数据：\n",
+		ConfidenceRequired:  "\n开仓需要 **信心度 ≥ %d**。\n\n",
+		DefaultEntryHeader:  "# 🎯 开仓标准（严格）\n\n仅在多个信号共振时开仓。你拥有：\n",
+		DefaultEntryFooter:  "\n可使用任何有效的分析方法，但开仓需要 **信心度 ≥ %d**；避免单一指标、信号矛盾、横盘震荡、平仓后立即重新开仓等低质量行为。\n\n",
+		DefaultProcess:      "# 📋 决策流程\n\n1. 检查持仓 → 是否需要止盈/止损\n2. 扫描候选币种 + 多周期 → 是否有强信号\n3. 先写思维链，再输出结构化 JSON\n\n",
+		OutputFormatHeader:  "# 输出格式（严格遵守）\n\n**必须使用 XML 标签 <reasoning> 和 <decision> 分隔思维链与决策 JSON，避免解析错误**\n\n## 格式要求\n\n<reasoning>\n你的思维链分析...\n- 简要分析你的思考过程\n</reasoning>\n\n<decision>\n第二步：JSON 决策数组\n\n",
+		FieldDescription:    "## 字段说明\n\n",
+		ActionField:         "- `action`：open_long | open_short | open_long_limit | open_short_limit | close_long | close_short | hold | wait\n",
+		LimitEntryField:     "- `open_long_limit` / `open_short_limit`：以限价单而非市价单入场；还需提供 `price`（限价入场价，做多低于当前价 / 做空高于当前价）和可选的 `expiry_minutes`（默认 %d，最大 %d）。止损/止盈在订单成交后设置\n",
+		TakeProfitLevels:    "- `take_profit_levels`（可选，市价开仓）：分批止盈 `[{\"price\": 0, \"close_pct\": 50}, ...]`，每档平掉仓位的 `close_pct`%；合计不足 100 时剩余仓位继续持有，`trailing_stop_pct`（可选，做多为距最高价的百分比 / 做空为距最低价的百分比）在第一档成交后移动其止损\n",
+		ConfidenceField:     "- `confidence`：0-100（建议开仓 ≥ %d）\n",
+		RequiredWhenOpening: "- 开仓时必填：leverage, position_size_usd, stop_loss, take_profit, confidence, risk_usd\n",
+		NumericValues:       "- **重要**：所有数值必须是计算后的数字，不能是公式/表达式（例如使用 `27.76` 而不是 `3000 * 0.01`）\n\n",
+		CustomPromptHeader:  "# 📌 个性化交易策略\n\n",
+		CustomPromptFooter:  "注意：以上个性化策略是对基础规则的补充，不能违反基础风控原则。\n",
+		ResponseLanguage:    "# 回复语言\n\n<reasoning> 部分和每个 `reasoning` 字段必须使用简体中文。JSON 键名和 `action` 取值必须与规定完全一致（保持英文）。\n\n",
+		FinalInstruction:    "现在请分析并输出你的决策（思维链 + JSON）\n",
+
+		StatusLine:       "时间：%s | 周期：#%d | 运行时长：%d 分钟\n\n",
+		AccountLine:      "账户：净值 %.2f | 可用余额 %.2f (%.1f%%) | 盈亏 %+.2f%% | 保证金 %.1f%% | 持仓数 %d\n\n",
+		InverseAccount:   "币本位反向合约：账户金额单位为 %s（1 %s = %.2f USD，净值约 %.2f USD）。只能开仓 %s；position_size_usd 以 USD 计，向下取整为 %.0f USD 的整数张合约\n\n",
+		PendingEntries:   "## 挂单中的限价入场\n",
+		PendingEntryLine: "%d. %s %s | 限价 %.4f | 数量 %.4f | 止损 %.4f 止盈 %.4f | %d 分钟后过期\n",
+		RecentTrades:     "## 最近完成的交易\n",
+		TradeProfit:      "盈利",
+		TradeLoss:        "亏损",
+		TradeLine:        "%d. %s %s | 入场 %.4f 出场 %.4f | %s：%+.2f USDT (%+.2f%%) | %s→%s (%s)\n",
+		Reflections:      "## 近期交易复盘\n你对最近平仓交易的复盘（最新在前），请吸取经验，避免重复犯错。\n",
+		ReflectionLine:   "%d. [%s] %s %s %+.2f%%（%s）：%s\n",
+		CurrentPositions: "## 当前持仓\n",
+		NoPositions:      "当前持仓：无\n\n",
+		CandidateCoins:   "## 候选币种（%d 个）\n\n",
+	},
+}
+
+// getPromptText returns the template set for a language (English fallback)
+func getPromptText(lang Language) *promptText {
+	if t, ok := promptTexts[lang]; ok {
+		return t
+	}
+	return promptTexts[LangEnglish]
+}
+
+// ParseLanguage maps a strategy language setting ("zh", "zh-CN", "en", "en-US", ...) to a Language
+// Returns false for empty or unsupported values
+func ParseLanguage(value string) (Language, bool) {
+	switch strings.ToLower(strings.ReplaceAll(strings.TrimSpace(value), "_", "-")) {
+	case "zh", "zh-cn", "zh-hans", "cn":
+		return LangChinese, true
+	case "en", "en-us", "en-gb":
+		return LangEnglish, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,64 @@
+package kernel
+
+import (
+	"nofx/store"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Every language must use the same format verbs as English, otherwise Sprintf output breaks
+func TestPromptTexts_FormatVerbsMatch(t *testing.T) {
+	verb := regexp.MustCompile(`%[+\-# 0]*[0-9]*(\.[0-9]+)?[a-zA-Z%]`)
+	en := reflect.ValueOf(*promptTexts[LangEnglish])
+	for lang, text := range promptTexts {
+		v := reflect.ValueOf(*text)
+		for i := 0; i < v.NumField(); i++ {
+			name := v.Type().Field(i).Name
+			got := strings.Join(verb.FindAllString(v.Field(i).String(), -1), " ")
+			want := strings.Join(verb.FindAllString(en.Field(i).String(), -1), " ")
+			if got != want {
+				t.Errorf("%s.%s: format verbs %q, want %q", lang, name, got, want)
+			}
+			if v.Field(i).String() == "" {
+				t.Errorf("%s.%s: empty template", lang, name)
+			}
+		}
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	cases := map[string]Language{"zh": LangChinese, "zh_CN": LangChinese, "ZH-cn": LangChinese, "en": LangEnglish, "en-US": LangEnglish}
+	for in, want := range cases {
+		if got, ok := ParseLanguage(in); !ok || got != want {
+			t.Errorf("ParseLanguage(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+	if _, ok := ParseLanguage("fr"); ok {
+		t.Error("ParseLanguage(fr) should be unsupported")
+	}
+}
+
+func TestBuildSystemPrompt_Localized(t *testing.T) {
+	zh := store.GetDefaultStrategyConfig("zh")
+	zh.PromptSections = store.PromptSectionsConfig{}
+	prompt := NewStrategyEngine(&zh).BuildSystemPrompt(1000, "")
+	for _, want := range []string{"# 硬性约束（风控）", "# 回复语言", "简体中文", "open_long_limit"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("zh system prompt missing %q", want)
+		}
+	}
+	if strings.Contains(prompt, "# Hard Constraints") {
+		t.Error("zh system prompt should not contain English headings")
+	}
+
+	en := store.GetDefaultStrategyConfig("en")
+	en.PromptSections = store.PromptSectionsConfig{}
+	prompt = NewStrategyEngine(&en).BuildSystemPrompt(1000, "")
+	for _, want := range []string{"# Hard Constraints (Risk Control)", "# Response Language", "in English"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("en system prompt missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package kernel
+
+import (
+	"fmt"
+	"math"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"sort"
+	"strings"
+)
+
+// ============================================================================
+// Market Regime Exposure Limits
+// ============================================================================
+// Leverage and position value caps of the risk control config are scaled by
+// the market regime of each symbol: full exposure in trends, reduced in ranges,
+// halved in volatile markets. The scaled caps are shown in the System Prompt
+// and enforced on the parsed decisions.
+// ============================================================================
+
+// regimeExposure multipliers applied to the risk control leverage and position value caps
+type regimeExposure struct {
+	Leverage      float64
+	PositionValue float64
+}
+
+var regimeExposures = map[market.MarketRegime]regimeExposure{
+	market.RegimeTrending: {Leverage: 1.0, PositionValue: 1.0},
+	market.RegimeRanging:  {Leverage: 0.75, PositionValue: 0.75},
+	market.RegimeVolatile: {Leverage: 0.5, PositionValue: 0.5},
+}
+
+// regimeCaps returns the regime-adjusted max leverage and position value ratio of a symbol
+// ok is false when the symbol has no regime classification (base caps apply)
+func regimeCaps(symbol string, regimes map[string]*market.RegimeInfo, riskControl store.RiskControlConfig) (maxLeverage int, posRatio float64, ok bool) {
+	info, found := regimes[symbol]
+	if !found || info == nil {
+		return 0, 0, false
+	}
+	exposure, found := regimeExposures[info.Regime]
+	if !found {
+		return 0, 0, false
+	}
+
+	maxLeverage = riskControl.AltcoinMaxLeverage
+	posRatio = riskControl.AltcoinMaxPositionValueRatio
+	if posRatio <= 0 {
+		posRatio = 1.0
+	}
+	if symbol == "BTCUSDT" || symbol == "ETHUSDT" {
+		maxLeverage = riskControl.BTCETHMaxLeverage
+		posRatio = riskControl.BTCETHMaxPositionValueRatio
+		if posRatio <= 0 {
+			posRatio = 5.0
+		}
+	}
+
+	maxLeverage = int(math.Floor(float64(maxLeverage) * exposure.Leverage))
+	if maxLeverage < 1 {
+		maxLeverage = 1
+	}
+	return maxLeverage, posRatio * exposure.PositionValue, true
+}
+
+// sortedRegimeSymbols returns the classified symbols in a stable order for prompts
+func sortedRegimeSymbols(regimes map[string]*market.RegimeInfo) []string {
+	symbols := make([]string, 0, len(regimes))
+	for symbol, info := range regimes {
+		if info != nil {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// writeRegimeConstraints writes the regime-adjusted caps of each classified symbol
+func (e *StrategyEngine) writeRegimeConstraints(sb *strings.Builder, accountEquity float64, regimes map[string]*market.RegimeInfo) {
+	if len(regimes) == 0 {
+		return
+	}
+	text := getPromptText(e.GetLanguage())
+	riskControl := e.config.RiskControl
+
+	sb.WriteString(text.RegimeConstraints)
+	for _, symbol := range sortedRegimeSymbols(regimes) {
+		maxLeverage, posRatio, ok := regimeCaps(symbol, regimes, riskControl)
+		if !ok {
+			continue
+		}
+		info := regimes[symbol]
+		sb.WriteString(fmt.Sprintf(text.RegimeLine,
+			symbol, info.Regime, info.BollingerWidthPct, info.ATRPct,
+			maxLeverage, accountEquity*posRatio))
+	}
+	sb.WriteString("\n")
+}
+
+// applyRegimeLimits clamps leverage and position size of opening decisions to their symbol's regime caps
+func applyRegimeLimits(decisions []Decision, accountEquity float64, regimes map[string]*market.RegimeInfo, riskControl store.RiskControlConfig) {
+	for i := range decisions {
+		d := &decisions[i]
+		if !d.IsOpen() {
+			continue
+		}
+		maxLeverage, posRatio, ok := regimeCaps(d.Symbol, regimes, riskControl)
+		if !ok {
+			continue
+		}
+		regime := regimes[d.Symbol].Regime
+		if d.Leverage > maxLeverage {
+			logger.Infof("🧭 [Regime Limit] %s (%s) leverage %dx > %dx, adjusted to %dx", d.Symbol, regime, d.Leverage, maxLeverage, maxLeverage)
+			d.Leverage = maxLeverage
+		}
+		if maxPositionValue := accountEquity * posRatio; d.PositionSizeUSD > maxPositionValue {
+			logger.Infof("🧭 [Regime Limit] %s (%s) position %.0f USDT > %.0f USDT, adjusted to %.0f USDT", d.Symbol, regime, d.PositionSizeUSD, maxPositionValue, maxPositionValue)
+			d.PositionSizeUSD = maxPositionValue
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package kernel
+
+import (
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"testing"
+)
+
+func TestApplyRegimeLimits(t *testing.T) {
+	riskControl := store.RiskControlConfig{
+		BTCETHMaxLeverage: 10, AltcoinMaxLeverage: 5,
+		BTCETHMaxPositionValueRatio: 5, AltcoinMaxPositionValueRatio: 1,
+	}
+	regimes := map[string]*market.RegimeInfo{
+		"BTCUSDT": {Symbol: "BTCUSDT", Regime: market.RegimeVolatile},
+		"SOLUSDT": {Symbol: "SOLUSDT", Regime: market.RegimeTrending},
+	}
+	decisions := []Decision{
+		{Symbol: "BTCUSDT", Action: "open_long", Leverage: 10, PositionSizeUSD: 5000},
+		{Symbol: "SOLUSDT", Action: "open_short", Leverage: 5, PositionSizeUSD: 1000},
+		{Symbol: "DOGEUSDT", Action: "open_long", Leverage: 5, PositionSizeUSD: 1000},
+		{Symbol: "BTCUSDT", Action: "close_long"},
+	}
+
+	applyRegimeLimits(decisions, 1000, regimes, riskControl)
+
+	if decisions[0].Leverage != 5 || decisions[0].PositionSizeUSD != 2500 {
+		t.Errorf("volatile BTC should be halved to 5x / 2500, got %dx / %.0f", decisions[0].Leverage, decisions[0].PositionSizeUSD)
+	}
+	if decisions[1].Leverage != 5 || decisions[1].PositionSizeUSD != 1000 {
+		t.Errorf("trending SOL should keep full caps, got %dx / %.0f", decisions[1].Leverage, decisions[1].PositionSizeUSD)
+	}
+	if decisions[2].Leverage != 5 || decisions[2].PositionSizeUSD != 1000 {
+		t.Errorf("unclassified symbol should be unchanged, got %dx / %.0f", decisions[2].Leverage, decisions[2].PositionSizeUSD)
+	}
+}
+
+func TestBuildSystemPromptWithRegimes(t *testing.T) {
+	config := store.GetDefaultStrategyConfig("en")
+	engine := NewStrategyEngine(&config)
+	regimes := map[string]*market.RegimeInfo{
+		"BTCUSDT": {Symbol: "BTCUSDT", Regime: market.RegimeRanging, BollingerWidthPct: 2.5, ATRPct: 1.2},
+	}
+
+	prompt := engine.BuildSystemPromptWithRegimes(1000, "", regimes)
+	if !strings.Contains(prompt, "Market Regime Limits") || !strings.Contains(prompt, "BTCUSDT: ranging") {
+		t.Errorf("system prompt should list regime limits:\n%s", prompt)
+	}
+	if strings.Contains(engine.BuildSystemPrompt(1000, ""), "Market Regime Limits") {
+		t.Error("system prompt without regimes should not have a regime section")
+	}
+}
@@ -0,0 +1,131 @@
+package kernel
+
+import (
+	"fmt"
+	"math"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+)
+
+// ============================================================================
+// Volatility-Based Position Sizing
+// ============================================================================
+// With risk sizing enabled, the position size suggested by the AI is replaced
+// server-side by the size whose loss between entry and stop loss equals
+// RiskPerTradePct of equity. Decisions without a usable stop loss are sized on
+// an ATR stop distance (ATRStopMultiplier × ATR14 of the primary timeframe).
+// The result is still capped by the position value ratio of the symbol.
+// ============================================================================
+
+// Risk sizing defaults
+const (
+	DefaultRiskPerTradePct   = 1.0
+	DefaultATRStopMultiplier = 2.0
+)
+
+// symbolATR returns the ATR14 of a symbol on the primary timeframe, falling back to the legacy series
+func symbolATR(data *market.Data, primaryTimeframe string) float64 {
+	if data == nil {
+		return 0
+	}
+	if tf, ok := data.TimeframeData[primaryTimeframe]; ok && tf != nil && tf.ATR14 > 0 {
+		return tf.ATR14
+	}
+	if data.IntradaySeries != nil && data.IntradaySeries.ATR14 > 0 {
+		return data.IntradaySeries.ATR14
+	}
+	if data.LongerTermContext != nil && data.LongerTermContext.ATR14 > 0 {
+		return data.LongerTermContext.ATR14
+	}
+	return 0
+}
+
+// stopDistance returns the entry-to-stop distance used for sizing and where it came from
+// The stop loss is used when it sits on the losing side of the entry, otherwise ATR × multiplier
+func stopDistance(d *Decision, entryPrice, atr, atrMultiplier float64) (float64, string) {
+	if d.StopLoss > 0 {
+		if d.IsLong() && d.StopLoss < entryPrice {
+			return entryPrice - d.StopLoss, "stop loss"
+		}
+		if !d.IsLong() && d.StopLoss > entryPrice {
+			return d.StopLoss - entryPrice, "stop loss"
+		}
+	}
+	if atr > 0 {
+		return atr * atrMultiplier, fmt.Sprintf("%.1f×ATR", atrMultiplier)
+	}
+	return 0, ""
+}
+
+// applyRiskSizing rescales position_size_usd of opening decisions so their stop loss risks RiskPerTradePct of equity
+// Decisions without an entry price or stop distance keep the AI size; sizes below MinPositionSize become wait.
+func applyRiskSizing(decisions []Decision, accountEquity float64, marketData map[string]*market.Data, riskControl store.RiskControlConfig, primaryTimeframe string) {
+	if !riskControl.RiskSizingEnabled || accountEquity <= 0 {
+		return
+	}
+	riskPct := riskControl.RiskPerTradePct
+	if riskPct <= 0 {
+		riskPct = DefaultRiskPerTradePct
+	}
+	atrMultiplier := riskControl.ATRStopMultiplier
+	if atrMultiplier <= 0 {
+		atrMultiplier = DefaultATRStopMultiplier
+	}
+	riskUSD := accountEquity * riskPct / 100
+
+	for i := range decisions {
+		d := &decisions[i]
+		if !d.IsOpen() {
+			continue
+		}
+
+		data := marketData[d.Symbol]
+		entryPrice := d.Price
+		if !d.IsLimitEntry() {
+			entryPrice = 0
+			if data != nil {
+				entryPrice = data.CurrentPrice
+			}
+		}
+		if entryPrice <= 0 {
+			continue
+		}
+
+		distance, source := stopDistance(d, entryPrice, symbolATR(data, primaryTimeframe), atrMultiplier)
+		if distance <= 0 {
+			logger.Infof("📐 [Risk Sizing] %s has no stop loss or ATR, keeping AI size %.0f USDT", d.Symbol, d.PositionSizeUSD)
+			continue
+		}
+
+		size := riskUSD / (distance / entryPrice)
+		posRatio := riskControl.AltcoinMaxPositionValueRatio
+		if posRatio <= 0 {
+			posRatio = 1.0
+		}
+		if d.Symbol == "BTCUSDT" || d.Symbol == "ETHUSDT" {
+			posRatio = riskControl.BTCETHMaxPositionValueRatio
+			if posRatio <= 0 {
+				posRatio = 5.0
+			}
+		}
+		if maxSize := accountEquity * posRatio; size > maxSize {
+			size = maxSize
+		}
+		size = math.Floor(size*100) / 100
+
+		if riskControl.MinPositionSize > 0 && size < riskControl.MinPositionSize {
+			reason := fmt.Sprintf("%.2f USDT risk over a %.4f stop distance sizes %.2f USDT, below the %.0f USDT minimum",
+				riskUSD, distance, size, riskControl.MinPositionSize)
+			logger.Warnf("📐 [Risk Sizing] %s %s rejected: %s", d.Symbol, d.Action, reason)
+			d.Reasoning = fmt.Sprintf("[rejected %s: %s] %s", d.Action, reason, d.Reasoning)
+			d.Action = "wait"
+			continue
+		}
+
+		logger.Infof("📐 [Risk Sizing] %s AI size %.0f USDT → %.0f USDT (%.1f%% equity risk, %s distance %.4f)",
+			d.Symbol, d.PositionSizeUSD, size, riskPct, source, distance)
+		d.PositionSizeUSD = size
+		d.RiskUSD = size * distance / entryPrice
+	}
+}
@@ -0,0 +1,85 @@
+package kernel
+
+import (
+	"math"
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"testing"
+)
+
+func TestApplyRiskSizing(t *testing.T) {
+	riskControl := store.RiskControlConfig{
+		RiskSizingEnabled:            true,
+		RiskPerTradePct:              1,
+		ATRStopMultiplier:            2,
+		BTCETHMaxPositionValueRatio:  5,
+		AltcoinMaxPositionValueRatio: 1,
+		MinPositionSize:              12,
+	}
+	marketData := map[string]*market.Data{
+		"BTCUSDT": {Symbol: "BTCUSDT", CurrentPrice: 100000},
+		"SOLUSDT": {Symbol: "SOLUSDT", CurrentPrice: 100, TimeframeData: map[string]*market.TimeframeSeriesData{
+			"15m": {ATR14: 1},
+		}},
+		"DOGEUSDT": {Symbol: "DOGEUSDT", CurrentPrice: 0.2},
+	}
+	decisions := []Decision{
+		// 2% stop on 10000 equity risking 1%: 100 / 0.02 = 5000
+		{Symbol: "BTCUSDT", Action: "open_long", Leverage: 5, PositionSizeUSD: 20000, StopLoss: 98000},
+		// No stop: 2×ATR = 2 (2% of entry) → 5000, below the 1× equity altcoin cap
+		{Symbol: "SOLUSDT", Action: "open_short", Leverage: 3, PositionSizeUSD: 500},
+		// No stop, no ATR: AI size kept
+		{Symbol: "DOGEUSDT", Action: "open_long", Leverage: 3, PositionSizeUSD: 700},
+		{Symbol: "BTCUSDT", Action: "close_short"},
+	}
+
+	applyRiskSizing(decisions, 10000, marketData, riskControl, "15m")
+
+	if got := decisions[0].PositionSizeUSD; math.Abs(got-5000) > 0.01 {
+		t.Errorf("BTC should be sized to 5000 USDT, got %.2f", got)
+	}
+	if got := decisions[0].RiskUSD; math.Abs(got-100) > 0.01 {
+		t.Errorf("BTC risk should be 100 USDT, got %.2f", got)
+	}
+	if got := decisions[1].PositionSizeUSD; math.Abs(got-5000) > 0.01 {
+		t.Errorf("SOL should be sized on 2×ATR to 5000 USDT, got %.2f", got)
+	}
+	if decisions[2].PositionSizeUSD != 700 {
+		t.Errorf("DOGE without stop distance should keep AI size, got %.2f", decisions[2].PositionSizeUSD)
+	}
+}
+
+func TestApplyRiskSizingCapsAndMinimum(t *testing.T) {
+	riskControl := store.RiskControlConfig{
+		RiskSizingEnabled:            true,
+		RiskPerTradePct:              1,
+		AltcoinMaxPositionValueRatio: 1,
+		MinPositionSize:              12,
+	}
+	marketData := map[string]*market.Data{
+		"SOLUSDT": {Symbol: "SOLUSDT", CurrentPrice: 100},
+	}
+	decisions := []Decision{
+		// 0.1% stop would size 1000× equity, capped at 1× equity
+		{Symbol: "SOLUSDT", Action: "open_long", Leverage: 3, PositionSizeUSD: 100, StopLoss: 99.9},
+		// 50% stop on 100 equity: 1 / 0.5 = 2 USDT, below the 12 USDT minimum
+		{Symbol: "SOLUSDT", Action: "open_long", Leverage: 3, PositionSizeUSD: 100, StopLoss: 50, Reasoning: "dip"},
+	}
+
+	applyRiskSizing(decisions[:1], 1000, marketData, riskControl, "")
+	if decisions[0].PositionSizeUSD != 1000 {
+		t.Errorf("size should be capped at 1000 USDT, got %.2f", decisions[0].PositionSizeUSD)
+	}
+
+	applyRiskSizing(decisions[1:], 100, marketData, riskControl, "")
+	if decisions[1].Action != "wait" || !strings.HasPrefix(decisions[1].Reasoning, "[rejected open_long") {
+		t.Errorf("size below minimum should be rejected, got %s: %s", decisions[1].Action, decisions[1].Reasoning)
+	}
+
+	disabled := []Decision{{Symbol: "SOLUSDT", Action: "open_long", PositionSizeUSD: 100, StopLoss: 99}}
+	applyRiskSizing(disabled, 1000, marketData, store.RiskControlConfig{}, "")
+	if disabled[0].PositionSizeUSD != 100 {
+		t.Error("sizing should be off unless enabled")
+	}
+}
@@ -0,0 +1,46 @@
+package kernel
+
+import (
+	"fmt"
+	"nofx/provider/sentiment"
+	"sort"
+	"strings"
+)
+
+// formatSentiment formats the market sentiment section of the user prompt
+func formatSentiment(snap *sentiment.Snapshot) string {
+	if snap == nil || (snap.FearGreed == nil && len(snap.Social) == 0) {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## 🧠 Market Sentiment\n\n")
+	if fg := snap.FearGreed; fg != nil {
+		sb.WriteString(fmt.Sprintf("Crypto Fear & Greed Index: %.0f (%s)", fg.Value, fg.Label))
+		if prev := snap.FearGreedPrev; prev != nil {
+			sb.WriteString(fmt.Sprintf(" | previous day %.0f (%s), change %+.0f", prev.Value, prev.Label, fg.Value-prev.Value))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(snap.Social) > 0 {
+		symbols := make([]string, 0, len(snap.Social))
+		for symbol := range snap.Social {
+			symbols = append(symbols, symbol)
+		}
+		sort.Strings(symbols)
+
+		parts := make([]string, 0, len(symbols))
+		for _, symbol := range symbols {
+			r := snap.Social[symbol]
+			part := fmt.Sprintf("%s %+.2f", symbol, r.Value)
+			if r.Mentions > 0 {
+				part += fmt.Sprintf(" (%d mentions)", r.Mentions)
+			}
+			parts = append(parts, part)
+		}
+		sb.WriteString("Social sentiment (-1 bearish .. 1 bullish): " + strings.Join(parts, " | ") + "\n")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
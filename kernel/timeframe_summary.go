@@ -0,0 +1,137 @@
+package kernel
+
+import (
+	"fmt"
+	"strings"
+
+	"nofx/market"
+	"nofx/store"
+)
+
+// ============================================================================
+// Multi-Timeframe Summary
+// ============================================================================
+// Strategies list the horizons the AI should weigh (e.g. 5m/1h/4h/1d) with a
+// weight each. Every horizon is rendered as one compact line per coin (trend,
+// change, RSI, ATR, range position) plus a weighted trend score, so adding a
+// horizon costs a line instead of a kline table. Horizons that are not also
+// selected timeframes are fetched for the summary only.
+// Summaries come from ctx.MultiTFMarket (backtests) or the coin's timeframe data.
+// ============================================================================
+
+// summaryWeights returns the summary timeframes with weights normalized to sum 1 (nil: disabled)
+// Non-positive weights count as 1, repeated timeframes keep the first entry.
+func (e *StrategyEngine) summaryWeights() []store.TimeframeWeight {
+	configured := e.config.Indicators.Klines.TimeframeWeights
+	if len(configured) == 0 {
+		return nil
+	}
+	weights := make([]store.TimeframeWeight, 0, len(configured))
+	seen := make(map[string]bool, len(configured))
+	total := 0.0
+	for _, w := range configured {
+		tf := strings.ToLower(strings.TrimSpace(w.Timeframe))
+		if tf == "" || seen[tf] {
+			continue
+		}
+		seen[tf] = true
+		if w.Weight <= 0 {
+			w.Weight = 1
+		}
+		weights = append(weights, store.TimeframeWeight{Timeframe: tf, Weight: w.Weight})
+		total += w.Weight
+	}
+	for i := range weights {
+		weights[i].Weight /= total
+	}
+	return weights
+}
+
+// WithSummaryTimeframes returns timeframes plus the summary timeframes missing from it
+func (e *StrategyEngine) WithSummaryTimeframes(timeframes []string) []string {
+	result := append([]string(nil), timeframes...)
+	have := make(map[string]bool, len(timeframes))
+	for _, tf := range timeframes {
+		have[tf] = true
+	}
+	for _, w := range e.summaryWeights() {
+		if !have[w.Timeframe] {
+			have[w.Timeframe] = true
+			result = append(result, w.Timeframe)
+		}
+	}
+	return result
+}
+
+// summaryOnlyTimeframes summary timeframes that are not rendered as series
+func (e *StrategyEngine) summaryOnlyTimeframes() map[string]bool {
+	weights := e.summaryWeights()
+	if len(weights) == 0 {
+		return nil
+	}
+	timeframes, primaryTimeframe, _ := e.klineSettings()
+	series := map[string]bool{primaryTimeframe: true}
+	for _, tf := range timeframes {
+		series[tf] = true
+	}
+	only := make(map[string]bool)
+	for _, w := range weights {
+		if !series[w.Timeframe] {
+			only[w.Timeframe] = true
+		}
+	}
+	return only
+}
+
+// formatTimeframeSummaries renders the weighted multi-timeframe summary of a coin ("" when disabled or no data)
+func (e *StrategyEngine) formatTimeframeSummaries(data *market.Data, multiTF map[string]*market.Data) string {
+	weights := e.summaryWeights()
+	if len(weights) == 0 {
+		return ""
+	}
+
+	var rows strings.Builder
+	score, covered := 0.0, 0.0
+	for _, w := range weights {
+		var summary *market.TimeframeSummary
+		if tfData, ok := multiTF[w.Timeframe]; ok {
+			summary = tfData.Summary(w.Timeframe)
+		} else if series, ok := data.TimeframeData[w.Timeframe]; ok {
+			summary = series.Summary()
+		}
+		if summary == nil {
+			continue
+		}
+		score += w.Weight * float64(summary.Trend)
+		covered += w.Weight
+
+		rows.WriteString(fmt.Sprintf("%-4s w%3.0f%% %-5s %+.2f%%/%d bars", w.Timeframe, w.Weight*100, trendLabel(summary.Trend), summary.ChangePct, summary.Bars))
+		if summary.RSI14 > 0 {
+			rows.WriteString(fmt.Sprintf(" RSI14 %.1f", summary.RSI14))
+		}
+		if summary.ATRPct > 0 {
+			rows.WriteString(fmt.Sprintf(" ATR %.2f%%", summary.ATRPct))
+		}
+		rows.WriteString(fmt.Sprintf(" range %.0f%%\n", summary.RangePct))
+	}
+	if covered == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Multi-timeframe summary (weight, trend, change, RSI14, ATR % of price, close position in range):\n")
+	sb.WriteString(rows.String())
+	sb.WriteString(fmt.Sprintf("Weighted trend score: %+.2f (-1 all bearish, +1 all bullish)\n\n", score/covered))
+	return sb.String()
+}
+
+func trendLabel(trend int) string {
+	switch {
+	case trend > 0:
+		return "up"
+	case trend < 0:
+		return "down"
+	default:
+		return "flat"
+	}
+}
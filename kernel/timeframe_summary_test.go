@@ -0,0 +1,63 @@
+package kernel
+
+import (
+	"strings"
+	"testing"
+
+	"nofx/market"
+	"nofx/store"
+)
+
+func risingSeries(tf string, n int) *market.TimeframeSeriesData {
+	series := &market.TimeframeSeriesData{Timeframe: tf}
+	for i := 0; i < n; i++ {
+		price := 100 + float64(i)
+		series.Klines = append(series.Klines, market.KlineBar{Time: int64(i) * 60000, Open: price - 0.5, High: price + 1, Low: price - 1, Close: price, Volume: 10})
+	}
+	return series
+}
+
+func TestFormatTimeframeSummaries(t *testing.T) {
+	config := store.GetDefaultStrategyConfig("en")
+	config.Indicators.Klines.SelectedTimeframes = []string{"5m"}
+	config.Indicators.Klines.PrimaryTimeframe = "5m"
+	config.Indicators.Klines.TimeframeWeights = []store.TimeframeWeight{{Timeframe: "5m", Weight: 1}, {Timeframe: "4h", Weight: 3}, {Timeframe: "1d", Weight: 0}}
+	engine := NewStrategyEngine(&config)
+
+	data := &market.Data{Symbol: "BTCUSDT", CurrentPrice: 159, TimeframeData: map[string]*market.TimeframeSeriesData{
+		"5m": risingSeries("5m", 60),
+		"4h": risingSeries("4h", 60),
+	}}
+	got := engine.formatTimeframeSummaries(data, nil)
+	for _, want := range []string{"5m   w 20% up", "4h   w 60% up", "Weighted trend score: +1.00"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary should contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "1d") {
+		t.Error("timeframes without data must be skipped")
+	}
+
+	// Summary-only timeframes are fetched but not rendered as kline tables
+	if tfs := engine.WithSummaryTimeframes([]string{"5m"}); strings.Join(tfs, ",") != "5m,4h,1d" {
+		t.Errorf("fetch timeframes = %v, want 5m,4h,1d", tfs)
+	}
+	if rendered := engine.formatMarketData(data); strings.Contains(rendered, "=== 4H Timeframe") || !strings.Contains(rendered, "=== 5M Timeframe") {
+		t.Errorf("only selected timeframes should render as series:\n%s", rendered)
+	}
+
+	// Backtests provide per-timeframe data in MultiTFMarket
+	multiTF := map[string]*market.Data{"1d": {IntradaySeries: &market.IntradayData{MidPrices: []float64{110, 105, 100}, RSI14Values: []float64{35}}}}
+	if got := engine.formatTimeframeSummaries(data, multiTF); !strings.Contains(got, "1d   w 20% down  -9.09%/3 bars RSI14 35.0") {
+		t.Errorf("summary should use MultiTFMarket data, got:\n%s", got)
+	}
+}
+
+func TestFormatTimeframeSummariesDisabled(t *testing.T) {
+	config := store.GetDefaultStrategyConfig("en")
+	engine := NewStrategyEngine(&config)
+	data := &market.Data{Symbol: "BTCUSDT", TimeframeData: map[string]*market.TimeframeSeriesData{"5m": risingSeries("5m", 30)}}
+	if got := engine.formatTimeframeSummaries(data, nil); got != "" {
+		t.Errorf("no summary expected without timeframe weights, got %q", got)
+	}
+}
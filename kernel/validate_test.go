@@ -1,6 +1,7 @@
 package kernel
 
 import (
+	"nofx/store"
 	"testing"
 )
 
@@ -84,7 +85,7 @@ func TestLeverageFallback(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Use default position value ratios for testing (10x for BTC/ETH, 1.5x for altcoins)
-			err := validateDecision(&tt.decision, tt.accountEquity, tt.btcEthLeverage, tt.altcoinLeverage, 10.0, 1.5)
+			err := validateDecision(&tt.decision, tt.accountEquity, tt.btcEthLeverage, tt.altcoinLeverage, 10.0, 1.5, 3.0)
 
 			// Check error status
 			if (err != nil) != tt.wantError {
@@ -100,6 +101,69 @@ func TestLeverageFallback(t *testing.T) {
 	}
 }
 
+// TestLimitEntryValidation tests price and expiry checks for limit entry actions
+func TestLimitEntryValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		decision   Decision
+		wantExpiry int
+		wantError  bool
+	}{
+		{
+			name: "Long limit below market - default expiry",
+			decision: Decision{
+				Symbol: "SOLUSDT", Action: "open_long_limit", Leverage: 5, PositionSizeUSD: 100,
+				Price: 100, StopLoss: 95, TakeProfit: 120,
+			},
+			wantExpiry: DefaultLimitEntryExpiryMinutes,
+		},
+		{
+			name: "Short limit - expiry clamped",
+			decision: Decision{
+				Symbol: "SOLUSDT", Action: "open_short_limit", Leverage: 5, PositionSizeUSD: 100,
+				Price: 100, StopLoss: 105, TakeProfit: 80, ExpiryMinutes: 5000,
+			},
+			wantExpiry: MaxLimitEntryExpiryMinutes,
+		},
+		{
+			name: "Missing price - should error",
+			decision: Decision{
+				Symbol: "SOLUSDT", Action: "open_long_limit", Leverage: 5, PositionSizeUSD: 100,
+				StopLoss: 95, TakeProfit: 120,
+			},
+			wantError: true,
+		},
+		{
+			name: "Price outside stop loss - should error",
+			decision: Decision{
+				Symbol: "SOLUSDT", Action: "open_long_limit", Leverage: 5, PositionSizeUSD: 100,
+				Price: 90, StopLoss: 95, TakeProfit: 120,
+			},
+			wantError: true,
+		},
+		{
+			name: "Risk/reward too low at limit price - should error",
+			decision: Decision{
+				Symbol: "SOLUSDT", Action: "open_long_limit", Leverage: 5, PositionSizeUSD: 100,
+				Price: 110, StopLoss: 95, TakeProfit: 120,
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDecision(&tt.decision, 100, 10, 5, 10.0, 1.5, 3.0)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("validateDecision() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !tt.wantError && tt.decision.ExpiryMinutes != tt.wantExpiry {
+				t.Errorf("ExpiryMinutes = %d, want %d", tt.decision.ExpiryMinutes, tt.wantExpiry)
+			}
+		})
+	}
+}
+
 
 // contains checks if string contains substring (helper function)
 func contains(s, substr string) bool {
@@ -115,3 +179,61 @@ func stringContains(s, substr string) bool {
 	}
 	return false
 }
+
+// TestRiskRewardRatioConfig tests the strategy's minimum risk/reward ratio is enforced, or skipped when disabled
+func TestRiskRewardRatioConfig(t *testing.T) {
+	// 1:1.6 at the limit price
+	decision := Decision{
+		Symbol: "SOLUSDT", Action: "open_long_limit", Leverage: 5, PositionSizeUSD: 100,
+		Price: 100, StopLoss: 95, TakeProfit: 108,
+	}
+	for _, tt := range []struct {
+		name      string
+		config    store.RiskControlConfig
+		wantError bool
+	}{
+		{"unset defaults to 1:3", store.RiskControlConfig{}, true},
+		{"scalping 1:1.5", store.RiskControlConfig{MinRiskRewardRatio: 1.5}, false},
+		{"stricter 1:2", store.RiskControlConfig{MinRiskRewardRatio: 2}, true},
+		{"check disabled", store.RiskControlConfig{MinRiskRewardRatio: 3, DisableRiskRewardCheck: true}, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			d := decision
+			err := validateDecision(&d, 100, 10, 5, 10.0, 1.5, minRiskRewardRatio(tt.config))
+			if (err != nil) != tt.wantError {
+				t.Fatalf("validateDecision() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestTakeProfitLevelsValidation(t *testing.T) {
+	levels := func(l ...TakeProfitLevel) []TakeProfitLevel { return l }
+	tests := []struct {
+		name      string
+		levels    []TakeProfitLevel
+		trailing  float64
+		wantError bool
+	}{
+		{"ladder with runner", levels(TakeProfitLevel{Price: 160, ClosePct: 25}, TakeProfitLevel{Price: 130, ClosePct: 50}), 2, false},
+		{"more than 100%", levels(TakeProfitLevel{Price: 130, ClosePct: 60}, TakeProfitLevel{Price: 160, ClosePct: 60}), 0, true},
+		{"level on the stop loss side", levels(TakeProfitLevel{Price: 80, ClosePct: 50}), 0, true},
+		{"missing close_pct", levels(TakeProfitLevel{Price: 130}), 0, true},
+		{"trailing stop too wide", levels(TakeProfitLevel{Price: 130, ClosePct: 50}), 60, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Decision{
+				Symbol: "SOLUSDT", Action: "open_long", Leverage: 5, PositionSizeUSD: 100,
+				StopLoss: 90, TakeProfitLevels: tt.levels, TrailingStopPct: tt.trailing,
+			}
+			err := validateDecision(&d, 100, 10, 5, 10.0, 1.5, 0)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("validateDecision() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err == nil && (d.TakeProfitLevels[0].Price != 130 || d.TakeProfit != 160) {
+				t.Errorf("levels should be sorted nearest first with take_profit the farthest, got %+v tp %.0f", d.TakeProfitLevels, d.TakeProfit)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Package lifecycle shuts the application's subsystems down in order on exit
+//
+// Subsystems registered with OnShutdown are stopped one at a time, in registration
+// order, each with its own timeout; one that overruns is logged and left behind so
+// the rest still get to stop. Background workers started with Go are signalled
+// last, after every subsystem that might still hand them work has stopped, and
+// the ones still running at the deadline are logged by name.
+package lifecycle
+
+import (
+	"context"
+	"nofx/logger"
+	"sort"
+	"sync"
+	"time"
+)
+
+// step one subsystem stopped during Shutdown
+type step struct {
+	name    string
+	timeout time.Duration
+	stop    func(ctx context.Context) error
+}
+
+// Manager ordered shutdown of subsystems and background workers
+type Manager struct {
+	mu      sync.Mutex
+	steps   []step
+	running map[string]int // Worker name -> goroutines still running
+	wg      sync.WaitGroup
+	done    chan struct{}
+	closed  bool
+}
+
+// New creates a lifecycle manager
+func New() *Manager {
+	return &Manager{
+		running: make(map[string]int),
+		done:    make(chan struct{}),
+	}
+}
+
+// OnShutdown registers a subsystem to stop during Shutdown, after the ones registered before it
+// ctx expires after timeout; stop should return by then.
+func (m *Manager) OnShutdown(name string, timeout time.Duration, stop func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.steps = append(m.steps, step{name: name, timeout: timeout, stop: stop})
+}
+
+// Stopper adapts a Stop method without context or error for OnShutdown
+func Stopper(stop func()) func(ctx context.Context) error {
+	return func(context.Context) error {
+		stop()
+		return nil
+	}
+}
+
+// Go runs a background worker until Shutdown; fn must return soon after done is closed
+func (m *Manager) Go(name string, fn func(done <-chan struct{})) {
+	m.mu.Lock()
+	m.running[name]++
+	m.wg.Add(1)
+	m.mu.Unlock()
+
+	go func() {
+		defer func() {
+			m.mu.Lock()
+			if m.running[name]--; m.running[name] <= 0 {
+				delete(m.running, name)
+			}
+			m.mu.Unlock()
+			m.wg.Done()
+		}()
+		fn(m.done)
+	}()
+}
+
+// Shutdown stops the registered subsystems in order, then signals the workers and waits up to workerTimeout for them
+func (m *Manager) Shutdown(workerTimeout time.Duration) {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	steps := append([]step(nil), m.steps...)
+	m.mu.Unlock()
+
+	for _, s := range steps {
+		runStep(s)
+	}
+
+	close(m.done)
+	finished := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(workerTimeout):
+		logger.Warnf("⚠️ Shutdown: background workers still running after %v: %v", workerTimeout, m.Stragglers())
+	}
+}
+
+// Stragglers names of the background workers still running, sorted
+func (m *Manager) Stragglers() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.running))
+	for name := range m.running {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runStep stops one subsystem, giving up on it (with a warning) once its timeout passes
+func runStep(s step) {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	result := make(chan error, 1) // Buffered: a straggler must be able to finish later
+	go func() { result <- s.stop(ctx) }()
+	select {
+	case err := <-result:
+		if err != nil {
+			logger.Warnf("⚠️ Shutdown: %s: %v", s.name, err)
+			return
+		}
+		logger.Infof("✓ Shutdown: %s stopped (%v)", s.name, time.Since(start).Round(time.Millisecond))
+	case <-ctx.Done():
+		logger.Warnf("⚠️ Shutdown: %s still running after %v, continuing without it", s.name, s.timeout)
+	}
+}
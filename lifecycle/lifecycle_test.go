@@ -0,0 +1,88 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShutdownStopsSubsystemsInOrder(t *testing.T) {
+	m := New()
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+	m.OnShutdown("api", time.Second, record("api"))
+	m.OnShutdown("traders", time.Second, record("traders"))
+	m.OnShutdown("failing", time.Second, func(context.Context) error { return errors.New("boom") })
+	m.OnShutdown("services", time.Second, Stopper(func() { _ = record("services")(nil) }))
+
+	workerStopped := make(chan []string, 1)
+	m.Go("writer", func(done <-chan struct{}) {
+		<-done
+		mu.Lock()
+		workerStopped <- append([]string(nil), order...)
+		mu.Unlock()
+	})
+
+	m.Shutdown(time.Second)
+
+	want := []string{"api", "traders", "services"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("expected stop order %v, got %v", want, order)
+	}
+	if seen := <-workerStopped; !reflect.DeepEqual(seen, want) {
+		t.Errorf("workers should be signalled after all subsystems stopped, saw %v", seen)
+	}
+	if stragglers := m.Stragglers(); len(stragglers) != 0 {
+		t.Errorf("expected no stragglers, got %v", stragglers)
+	}
+}
+
+func TestShutdownContinuesPastStragglers(t *testing.T) {
+	m := New()
+	release := make(chan struct{})
+	defer close(release)
+
+	m.OnShutdown("stuck", 20*time.Millisecond, func(ctx context.Context) error {
+		<-release // Ignores its deadline
+		return nil
+	})
+	nextStopped := false
+	m.OnShutdown("next", time.Second, Stopper(func() { nextStopped = true }))
+	m.Go("stuck worker", func(<-chan struct{}) { <-release })
+	m.Go("good worker", func(done <-chan struct{}) { <-done })
+
+	start := time.Now()
+	m.Shutdown(20 * time.Millisecond)
+
+	if !nextStopped {
+		t.Error("subsystems after a straggler should still be stopped")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("shutdown should be bounded by the timeouts, took %v", elapsed)
+	}
+	if stragglers := m.Stragglers(); !reflect.DeepEqual(stragglers, []string{"stuck worker"}) {
+		t.Errorf("expected the stuck worker reported, got %v", stragglers)
+	}
+}
+
+func TestShutdownRunsOnce(t *testing.T) {
+	m := New()
+	calls := 0
+	m.OnShutdown("api", time.Second, Stopper(func() { calls++ }))
+	m.Shutdown(time.Second)
+	m.Shutdown(time.Second)
+	if calls != 1 {
+		t.Errorf("expected one stop call, got %d", calls)
+	}
+}
@@ -0,0 +1,257 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ============================================================================
+// Per-source log capture
+// ============================================================================
+// Log lines attributed to a registered source (a trader) are kept in a ring
+// buffer per source and fanned out to live subscribers, so a trader's activity
+// can be watched through the API. A line belongs to a source when it carries
+// the source's ID in a "trader_id" field or contains one of the source's tags
+// (e.g. "[My Trader]", the prefix trader logs already use).
+// ============================================================================
+
+// DefaultCaptureLines lines kept per source when no buffer size is set
+const DefaultCaptureLines = 1000
+
+// subscriberBuffer lines queued per live subscriber before new lines are dropped
+const subscriberBuffer = 256
+
+// Entry one captured log line
+type Entry struct {
+	Seq     int64     `json:"seq"` // Increasing per process, for resuming streams
+	Source  string    `json:"source"`
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+}
+
+// captureRing fixed-size ring buffer of entries
+type captureRing struct {
+	entries []Entry
+	next    int
+	full    bool
+}
+
+func (r *captureRing) add(e Entry) {
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// all returns the buffered entries, oldest first
+func (r *captureRing) all() []Entry {
+	if !r.full {
+		return append([]Entry(nil), r.entries[:r.next]...)
+	}
+	result := make([]Entry, 0, len(r.entries))
+	result = append(result, r.entries[r.next:]...)
+	return append(result, r.entries[:r.next]...)
+}
+
+// captureHook logrus hook routing lines to source buffers and subscribers
+type captureHook struct {
+	mu       sync.RWMutex
+	lines    int
+	seq      int64
+	tags     map[string][]string // source -> tags
+	buffers  map[string]*captureRing
+	subs     map[string]map[chan Entry]struct{}
+	sink     func(Entry) // Receives every captured line (nil: not persisted)
+	disabled bool
+}
+
+var capture = &captureHook{
+	lines:   DefaultCaptureLines,
+	tags:    make(map[string][]string),
+	buffers: make(map[string]*captureRing),
+	subs:    make(map[string]map[chan Entry]struct{}),
+}
+
+// Levels captures every level
+func (h *captureHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire attributes the entry to a source and records it, must not log itself
+func (h *captureHook) Fire(entry *logrus.Entry) error {
+	h.mu.RLock()
+	if h.disabled || len(h.tags) == 0 {
+		h.mu.RUnlock()
+		return nil
+	}
+	source := ""
+	if id, ok := entry.Data["trader_id"].(string); ok {
+		if _, registered := h.tags[id]; registered {
+			source = id
+		}
+	}
+	if source == "" {
+		for id, tags := range h.tags {
+			for _, tag := range tags {
+				if strings.Contains(entry.Message, tag) {
+					source = id
+					break
+				}
+			}
+			if source != "" {
+				break
+			}
+		}
+	}
+	h.mu.RUnlock()
+	if source == "" {
+		return nil
+	}
+
+	h.mu.Lock()
+	h.seq++
+	e := Entry{
+		Seq:     h.seq,
+		Source:  source,
+		Time:    entry.Time,
+		Level:   entry.Level.String(),
+		Message: strings.TrimSpace(entry.Message),
+	}
+	ring := h.buffers[source]
+	if ring == nil {
+		ring = &captureRing{entries: make([]Entry, h.lines)}
+		h.buffers[source] = ring
+	}
+	ring.add(e)
+	for ch := range h.subs[source] {
+		select {
+		case ch <- e:
+		default: // Slow subscriber: drop rather than block logging
+		}
+	}
+	sink := h.sink
+	h.mu.Unlock()
+
+	if sink != nil {
+		sink(e)
+	}
+	return nil
+}
+
+// SetCaptureLines sets the lines kept per source, applies to buffers created afterwards
+func SetCaptureLines(n int) {
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	if n <= 0 {
+		capture.disabled = true
+		return
+	}
+	capture.disabled = false
+	capture.lines = n
+}
+
+// SetCaptureSink sets the function receiving every captured line, e.g. to persist it
+// The sink runs on the logging goroutine and must not block or log.
+func SetCaptureSink(sink func(Entry)) {
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	capture.sink = sink
+}
+
+// RegisterSource starts capturing lines of a source (e.g. a trader ID) matching any of tags
+// Registering again replaces the tags and keeps the buffered lines.
+func RegisterSource(id string, tags ...string) {
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	var kept []string
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) != "" {
+			kept = append(kept, tag)
+		}
+	}
+	capture.tags[id] = kept
+}
+
+// ForgetSource stops capturing a source and drops its buffered lines
+func ForgetSource(id string) {
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+	delete(capture.tags, id)
+	delete(capture.buffers, id)
+	for ch := range capture.subs[id] {
+		close(ch)
+	}
+	delete(capture.subs, id)
+}
+
+// Recent returns up to limit buffered lines of a source at or above minLevel (empty: all), oldest first
+func Recent(id string, limit int, minLevel string) []Entry {
+	capture.mu.RLock()
+	ring := capture.buffers[id]
+	var entries []Entry
+	if ring != nil {
+		entries = ring.all()
+	}
+	capture.mu.RUnlock()
+
+	filtered := entries[:0]
+	for _, e := range entries {
+		if LevelAtLeast(e.Level, minLevel) {
+			filtered = append(filtered, e)
+		}
+	}
+	if limit > 0 && len(filtered) > limit {
+		filtered = filtered[len(filtered)-limit:]
+	}
+	return filtered
+}
+
+// Subscribe streams new lines of a source until cancel is called
+// The channel is closed by cancel, or when the source is forgotten.
+func Subscribe(id string) (<-chan Entry, func()) {
+	ch := make(chan Entry, subscriberBuffer)
+	capture.mu.Lock()
+	if capture.subs[id] == nil {
+		capture.subs[id] = make(map[chan Entry]struct{})
+	}
+	capture.subs[id][ch] = struct{}{}
+	capture.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			capture.mu.Lock()
+			defer capture.mu.Unlock()
+			if _, ok := capture.subs[id][ch]; ok {
+				delete(capture.subs[id], ch)
+				close(ch)
+			}
+		})
+	}
+	return ch, cancel
+}
+
+// LevelAtLeast reports whether a level name is at or above min severity (empty or unknown names pass)
+func LevelAtLeast(level, min string) bool {
+	l, err := logrus.ParseLevel(level)
+	if err != nil {
+		return true
+	}
+	m, err := logrus.ParseLevel(min)
+	if err != nil {
+		return true
+	}
+	// logrus orders levels from panic (0) to trace (6)
+	return l <= m
+}
+
+// ValidLevel reports whether name is a log level name (e.g. "warning", "info")
+func ValidLevel(name string) bool {
+	_, err := logrus.ParseLevel(name)
+	return err == nil
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCaptureAttributesByTagAndField(t *testing.T) {
+	RegisterSource("cap_a", "[Alpha]")
+	RegisterSource("cap_b", "[Beta]")
+	defer ForgetSource("cap_a")
+	defer ForgetSource("cap_b")
+
+	Infof("🚀 [Alpha] started")
+	Warnf("⚠️ [Beta] thin liquidity")
+	Info("unrelated system line")
+	WithField("trader_id", "cap_a").Info("tagged by field")
+
+	a := Recent("cap_a", 0, "")
+	if len(a) != 2 || a[0].Message != "🚀 [Alpha] started" || a[1].Message != "tagged by field" {
+		t.Fatalf("unexpected cap_a lines: %+v", a)
+	}
+	if a[0].Seq >= a[1].Seq || a[0].Source != "cap_a" {
+		t.Errorf("expected increasing seqs with source set, got %+v", a)
+	}
+	b := Recent("cap_b", 0, "warning")
+	if len(b) != 1 || b[0].Level != "warning" {
+		t.Fatalf("unexpected cap_b lines: %+v", b)
+	}
+	if got := Recent("cap_a", 0, "warning"); len(got) != 0 {
+		t.Errorf("expected info lines filtered out, got %+v", got)
+	}
+}
+
+func TestCaptureRingBufferKeepsNewest(t *testing.T) {
+	SetCaptureLines(3)
+	defer SetCaptureLines(DefaultCaptureLines)
+	RegisterSource("cap_ring", "[Ring]")
+	defer ForgetSource("cap_ring")
+
+	for i := 1; i <= 5; i++ {
+		Infof("[Ring] line %d", i)
+	}
+	lines := Recent("cap_ring", 0, "")
+	if len(lines) != 3 || lines[0].Message != "[Ring] line 3" || lines[2].Message != "[Ring] line 5" {
+		t.Fatalf("expected the newest 3 lines oldest first, got %+v", lines)
+	}
+	if last := Recent("cap_ring", 1, ""); len(last) != 1 || last[0].Message != "[Ring] line 5" {
+		t.Errorf("expected limit to keep the newest line, got %+v", last)
+	}
+}
+
+func TestCaptureSubscribe(t *testing.T) {
+	RegisterSource("cap_sub", "[Sub]")
+	live, cancel := Subscribe("cap_sub")
+	defer cancel()
+
+	Infof("[Sub] live line")
+	select {
+	case e := <-live:
+		if e.Message != "[Sub] live line" {
+			t.Errorf("unexpected line: %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the line to be delivered")
+	}
+
+	// Forgetting the source ends the stream
+	ForgetSource("cap_sub")
+	if _, ok := <-live; ok {
+		t.Error("expected the channel to be closed")
+	}
+	cancel() // Safe after close
+}
@@ -55,6 +55,7 @@ func init() {
 	Log.SetLevel(logrus.InfoLevel)
 	Log.SetFormatter(&compactFormatter{})
 	Log.SetOutput(os.Stdout)
+	Log.AddHook(capture)
 }
 
 // ============================================================================
@@ -101,6 +102,7 @@ func Init(cfg *Config) error {
 	}
 
 	Log.SetReportCaller(true)
+	Log.AddHook(capture)
 
 	return nil
 }
@@ -1,22 +1,14 @@
 package main
 
 import (
-	"nofx/api"
-	"nofx/auth"
-	"nofx/backtest"
+	"nofx/app"
 	"nofx/config"
-	"nofx/crypto"
-	"nofx/experience"
 	"nofx/logger"
-	"nofx/manager"
-	"nofx/mcp"
-	"nofx/store"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"syscall"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 )
 
@@ -36,106 +28,20 @@ func main() {
 	cfg := config.Get()
 	logger.Info("✅ Configuration loaded")
 
-	// Initialize encryption service BEFORE database (so EncryptedString can decrypt on read)
-	logger.Info("🔐 Initializing encryption service...")
-	cryptoService, err := crypto.NewCryptoService()
-	if err != nil {
-		logger.Fatalf("❌ Failed to initialize encryption service: %v", err)
-	}
-	crypto.SetGlobalCryptoService(cryptoService)
-	logger.Info("✅ Encryption service initialized successfully")
-
-	// Initialize database from configuration
 	// For backward compatibility: command line arg overrides config (SQLite only)
 	if len(os.Args) > 1 {
 		cfg.DBPath = os.Args[1]
 	}
-	// Ensure data directory exists (for SQLite)
-	if cfg.DBType == "sqlite" {
-		if dir := filepath.Dir(cfg.DBPath); dir != "." {
-			if err := os.MkdirAll(dir, 0755); err != nil {
-				logger.Errorf("Failed to create data directory: %v", err)
-			}
-		}
-	}
 
-	logger.Infof("📋 Initializing database (%s)...", cfg.DBType)
-	dbType := store.DBTypeSQLite
-	if cfg.DBType == "postgres" {
-		dbType = store.DBTypePostgres
-	}
-	st, err := store.NewWithConfig(store.DBConfig{
-		Type:     dbType,
-		Path:     cfg.DBPath,
-		Host:     cfg.DBHost,
-		Port:     cfg.DBPort,
-		User:     cfg.DBUser,
-		Password: cfg.DBPassword,
-		DBName:   cfg.DBName,
-		SSLMode:  cfg.DBSSLMode,
-	})
+	instance, err := app.New(app.Config{})
 	if err != nil {
-		logger.Fatalf("❌ Failed to initialize database: %v", err)
+		logger.Fatalf("❌ %v", err)
 	}
-	defer st.Close()
-	backtest.UseDatabaseWithType(st.DB(), st.DBType() == store.DBTypePostgres)
-
-	// Initialize installation ID for experience improvement (anonymous statistics)
-	initInstallationID(st)
-
-	// Set JWT secret
-	auth.SetJWTSecret(cfg.JWTSecret)
-	logger.Info("🔑 JWT secret configured")
-
-	// WebSocket market monitor is NO LONGER USED
-	// All K-line data now comes from CoinAnk API instead of Binance WebSocket cache
-	// Commented out to reduce unnecessary connections:
-	// go market.NewWSMonitor(150).Start(nil)
-	// logger.Info("📊 WebSocket market monitor started")
-	// time.Sleep(500 * time.Millisecond)
-	logger.Info("📊 Using CoinAnk API for all market data (WebSocket cache disabled)")
-
-	// Create TraderManager and BacktestManager
-	traderManager := manager.NewTraderManager()
-	mcpClient := newSharedMCPClient()
-	backtestManager := backtest.NewManager(mcpClient)
-	if err := backtestManager.RestoreRuns(); err != nil {
-		logger.Warnf("⚠️ Failed to restore backtest history: %v", err)
-	}
-
-	// Load all traders from database to memory (may auto-start traders with IsRunning=true)
-	if err := traderManager.LoadTradersFromStore(st); err != nil {
-		logger.Fatalf("❌ Failed to load traders: %v", err)
+	if err := instance.Start(); err != nil {
+		instance.Stop(5 * time.Second)
+		logger.Fatalf("❌ %v", err)
 	}
 
-	// Display loaded trader information
-	traders, err := st.Trader().List("default")
-	if err != nil {
-		logger.Fatalf("❌ Failed to get trader list: %v", err)
-	}
-
-	logger.Info("🤖 AI Trader Configurations in Database:")
-	if len(traders) == 0 {
-		logger.Info("  (No trader configurations, please create via Web interface)")
-	} else {
-		for _, t := range traders {
-			status := "❌ Stopped"
-			if t.IsRunning {
-				status = "✅ Running"
-			}
-			logger.Infof("  • %s [%s] %s - AI Model: %s, Exchange: %s",
-				t.Name, t.ID[:8], status, t.AIModelID, t.ExchangeID)
-		}
-	}
-
-	// Start API server
-	server := api.NewServer(traderManager, st, cryptoService, backtestManager, cfg.APIServerPort)
-	go func() {
-		if err := server.Start(); err != nil {
-			logger.Fatalf("❌ Failed to start API server: %v", err)
-		}
-	}()
-
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -143,44 +49,17 @@ func main() {
 	logger.Info("✅ System started successfully, waiting for trading commands...")
 	logger.Info("📌 Tip: Use Ctrl+C to stop the system")
 
-	<-quit
-	logger.Info("📴 Shutdown signal received, closing system...")
-
-	// Stop all traders
-	traderManager.StopAll()
-	logger.Info("✅ System shut down safely")
-}
-
-// newSharedMCPClient creates a shared MCP AI client (for backtesting)
-func newSharedMCPClient() mcp.AIClient {
-	apiKey := os.Getenv("DEEPSEEK_API_KEY")
-	if apiKey == "" {
-		logger.Warn("⚠️ DEEPSEEK_API_KEY not set, AI features will be unavailable")
-		return nil
-	}
-	return mcp.NewDeepSeekClient()
-}
-
-// initInstallationID initializes the anonymous installation ID for experience improvement
-// This ID is persisted in database and used for anonymous usage statistics
-func initInstallationID(st *store.Store) {
-	const key = "installation_id"
-
-	// Try to load from database
-	installationID, err := st.GetSystemConfig(key)
-	if err != nil {
-		logger.Warnf("⚠️ Failed to load installation ID: %v", err)
+	failed := false
+	select {
+	case <-quit:
+		logger.Info("📴 Shutdown signal received, closing system...")
+	case err := <-instance.Err():
+		logger.Errorf("❌ API server stopped: %v", err)
+		failed = true
 	}
-
-	// Generate new ID if not exists
-	if installationID == "" {
-		installationID = uuid.New().String()
-		if err := st.SetSystemConfig(key, installationID); err != nil {
-			logger.Warnf("⚠️ Failed to save installation ID: %v", err)
-		}
-		logger.Infof("📊 Generated new installation ID: %s", installationID[:8]+"...")
+	instance.Stop(5 * time.Second)
+	if failed {
+		os.Exit(1)
 	}
-
-	// Set installation ID in experience module
-	experience.SetInstallationID(installationID)
+	logger.Info("✅ System shut down safely")
 }
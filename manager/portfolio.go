@@ -0,0 +1,366 @@
+package manager
+
+import (
+	"context"
+	"math"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"nofx/trader"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Consolidated Portfolio
+// ============================================================================
+// Aggregates all of a user's traders into one view: total equity and margin,
+// per-symbol exposure netted across traders and exchanges, and a combined
+// equity curve. Loaded traders are queried live from their exchange, others
+// (and traders whose exchange times out) fall back to their latest equity
+// snapshot. Paper traders are listed but never counted in the totals.
+// ============================================================================
+
+const (
+	portfolioCacheTTL     = 30 * time.Second // Portfolio served from cache within this age
+	portfolioCurvePoints  = 500              // Max points of the combined equity curve
+	portfolioQueryTimeout = 10 * time.Second // Per-trader exchange query timeout
+)
+
+// Portfolio consolidated view of a user's traders
+type Portfolio struct {
+	TotalEquity       float64 `json:"total_equity"`
+	AvailableBalance  float64 `json:"available_balance"`
+	UnrealizedPnL     float64 `json:"unrealized_pnl"`
+	InitialBalance    float64 `json:"initial_balance"`
+	TotalPnL          float64 `json:"total_pnl"`
+	TotalPnLPct       float64 `json:"total_pnl_pct"`
+	MarginUsed        float64 `json:"margin_used"`
+	MarginUsedPct     float64 `json:"margin_used_pct"`
+	GrossExposure     float64 `json:"gross_exposure"`     // Long + short notional of all positions
+	NetExposure       float64 `json:"net_exposure"`       // Sum of |net notional| per symbol, after offsetting longs and shorts
+	EffectiveLeverage float64 `json:"effective_leverage"` // Net exposure / total equity
+	PositionCount     int     `json:"position_count"`
+
+	Traders     []PortfolioTrader `json:"traders"`
+	Exposures   []SymbolExposure  `json:"exposures"` // Largest net exposure first
+	EquityCurve []PortfolioPoint  `json:"equity_curve"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// PortfolioTrader one trader's contribution to the portfolio
+type PortfolioTrader struct {
+	TraderID       string  `json:"trader_id"`
+	Name           string  `json:"name"`
+	Exchange       string  `json:"exchange"`
+	IsRunning      bool    `json:"is_running"`
+	PaperMode      bool    `json:"paper_mode"` // Simulated, excluded from the totals
+	Live           bool    `json:"live"`       // false: values from the latest equity snapshot
+	TotalEquity    float64 `json:"total_equity"`
+	InitialBalance float64 `json:"initial_balance"`
+	UnrealizedPnL  float64 `json:"unrealized_pnl"`
+	MarginUsed     float64 `json:"margin_used"`
+	PositionCount  int     `json:"position_count"`
+	Error          string  `json:"error,omitempty"`
+}
+
+// SymbolExposure exposure of one symbol netted across traders and exchanges
+type SymbolExposure struct {
+	Symbol         string   `json:"symbol"`
+	LongNotional   float64  `json:"long_notional"`
+	ShortNotional  float64  `json:"short_notional"`
+	NetNotional    float64  `json:"net_notional"` // Long minus short
+	NetPctOfEquity float64  `json:"net_pct_of_equity"`
+	UnrealizedPnL  float64  `json:"unrealized_pnl"`
+	TraderIDs      []string `json:"trader_ids"`
+	Exchanges      []string `json:"exchanges"`
+}
+
+// PortfolioPoint one point of the combined equity curve
+type PortfolioPoint struct {
+	Timestamp   time.Time `json:"timestamp"`
+	TotalEquity float64   `json:"total_equity"`
+	PnL         float64   `json:"pnl"`     // Equity minus initial balance of the traders with history at this point
+	PnLPct      float64   `json:"pnl_pct"` // PnL / initial balance × 100
+}
+
+// portfolioCache computed portfolios by user ID
+type portfolioCache struct {
+	entries map[string]*Portfolio
+	mu      sync.Mutex
+}
+
+// portfolioSource account data of one trader feeding the portfolio
+type portfolioSource struct {
+	trader    PortfolioTrader
+	available float64
+	positions []map[string]interface{} // AutoTrader.GetPositions output
+}
+
+// GetPortfolio returns the consolidated portfolio of a user's traders (cached for 30s unless refresh)
+func (tm *TraderManager) GetPortfolio(st *store.Store, userID string, refresh bool) (*Portfolio, error) {
+	tm.portfolios.mu.Lock()
+	cached := tm.portfolios.entries[userID]
+	tm.portfolios.mu.Unlock()
+	if !refresh && cached != nil && time.Since(cached.UpdatedAt) < portfolioCacheTTL {
+		return cached, nil
+	}
+
+	traderCfgs, err := st.Trader().List(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tm.mu.RLock()
+	loaded := make([]*trader.AutoTrader, len(traderCfgs))
+	for i, cfg := range traderCfgs {
+		if at, ok := tm.traders[cfg.ID]; ok && at != nil && at.GetUserID() == userID {
+			loaded[i] = at
+		}
+	}
+	tm.mu.RUnlock()
+
+	sources := make([]portfolioSource, len(traderCfgs))
+	var wg sync.WaitGroup
+	for i, cfg := range traderCfgs {
+		wg.Add(1)
+		go func(i int, cfg *store.Trader) {
+			defer wg.Done()
+			sources[i] = tm.portfolioSourceOf(st, cfg, loaded[i])
+		}(i, cfg)
+	}
+	wg.Wait()
+
+	portfolio := aggregatePortfolio(sources)
+
+	series := make(map[string][]*store.EquitySnapshot)
+	initial := make(map[string]float64)
+	for _, src := range sources {
+		if src.trader.PaperMode {
+			continue
+		}
+		snapshots, err := st.Equity().GetSeries(src.trader.TraderID, time.Time{}, time.Time{}, portfolioCurvePoints)
+		if err != nil {
+			logger.Warnf("⚠️ Failed to load equity history of trader %s: %v", src.trader.TraderID, err)
+			continue
+		}
+		series[src.trader.TraderID] = snapshots
+		initial[src.trader.TraderID] = src.trader.InitialBalance
+	}
+	portfolio.EquityCurve = mergeEquityCurves(series, initial, portfolioCurvePoints)
+	portfolio.UpdatedAt = time.Now()
+
+	tm.portfolios.mu.Lock()
+	tm.portfolios.entries[userID] = portfolio
+	tm.portfolios.mu.Unlock()
+	return portfolio, nil
+}
+
+// portfolioSourceOf gets a trader's account and positions live, or its latest equity snapshot
+func (tm *TraderManager) portfolioSourceOf(st *store.Store, cfg *store.Trader, at *trader.AutoTrader) portfolioSource {
+	src := portfolioSource{trader: PortfolioTrader{
+		TraderID:       cfg.ID,
+		Name:           cfg.Name,
+		IsRunning:      cfg.IsRunning,
+		PaperMode:      cfg.PaperMode,
+		InitialBalance: cfg.InitialBalance,
+	}}
+
+	if at != nil {
+		src.trader.Exchange = at.GetExchange()
+		if running, ok := at.GetStatus()["is_running"].(bool); ok {
+			src.trader.IsRunning = running
+		}
+
+		type liveData struct {
+			account   map[string]interface{}
+			positions []map[string]interface{}
+			err       error
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), portfolioQueryTimeout)
+		defer cancel()
+		liveChan := make(chan liveData, 1)
+		go func() {
+			account, err := at.GetAccountInfo()
+			if err != nil {
+				liveChan <- liveData{err: err}
+				return
+			}
+			positions, err := at.GetPositions()
+			liveChan <- liveData{account: account, positions: positions, err: err}
+		}()
+
+		select {
+		case live := <-liveChan:
+			if live.err == nil {
+				src.trader.Live = true
+				src.trader.TotalEquity, _ = live.account["total_equity"].(float64)
+				src.trader.UnrealizedPnL, _ = live.account["unrealized_profit"].(float64)
+				src.trader.MarginUsed, _ = live.account["margin_used"].(float64)
+				src.trader.PositionCount, _ = live.account["position_count"].(int)
+				src.available, _ = live.account["available_balance"].(float64)
+				src.positions = live.positions
+				return src
+			}
+			logger.Infof("⚠️ Portfolio: failed to get account of trader %s: %v", cfg.Name, live.err)
+			src.trader.Error = "Failed to get account data"
+		case <-ctx.Done():
+			logger.Infof("⏰ Portfolio: timeout getting account of trader %s", cfg.Name)
+			src.trader.Error = "Request timeout"
+		}
+	}
+
+	// Not loaded or exchange unavailable: last recorded equity, positions unknown
+	if latest, err := st.Equity().GetLatest(cfg.ID, 1); err == nil && len(latest) > 0 {
+		snap := latest[0]
+		src.trader.TotalEquity = snap.TotalEquity
+		src.trader.UnrealizedPnL = snap.UnrealizedPnL
+		src.trader.PositionCount = snap.PositionCount
+		src.trader.MarginUsed = snap.TotalEquity * snap.MarginUsedPct / 100
+	}
+	return src
+}
+
+// aggregatePortfolio sums trader accounts and nets live positions per symbol (paper traders excluded)
+func aggregatePortfolio(sources []portfolioSource) *Portfolio {
+	p := &Portfolio{Traders: make([]PortfolioTrader, 0, len(sources)), Exposures: []SymbolExposure{}}
+	exposures := make(map[string]*SymbolExposure)
+	seenTrader := make(map[string]map[string]bool)
+	seenExchange := make(map[string]map[string]bool)
+
+	for _, src := range sources {
+		p.Traders = append(p.Traders, src.trader)
+		if src.trader.PaperMode {
+			continue
+		}
+		p.TotalEquity += src.trader.TotalEquity
+		p.AvailableBalance += src.available
+		p.UnrealizedPnL += src.trader.UnrealizedPnL
+		p.InitialBalance += src.trader.InitialBalance
+		p.MarginUsed += src.trader.MarginUsed
+		p.PositionCount += src.trader.PositionCount
+
+		for _, pos := range src.positions {
+			symbol, _ := pos["symbol"].(string)
+			side, _ := pos["side"].(string)
+			quantity, _ := pos["quantity"].(float64)
+			markPrice, _ := pos["mark_price"].(float64)
+			pnl, _ := pos["unrealized_pnl"].(float64)
+			if symbol == "" {
+				continue
+			}
+			symbol = market.Normalize(symbol)
+			exp, ok := exposures[symbol]
+			if !ok {
+				exp = &SymbolExposure{Symbol: symbol}
+				exposures[symbol] = exp
+				seenTrader[symbol] = make(map[string]bool)
+				seenExchange[symbol] = make(map[string]bool)
+			}
+			notional := math.Abs(quantity) * markPrice
+			if side == "short" {
+				exp.ShortNotional += notional
+			} else {
+				exp.LongNotional += notional
+			}
+			exp.UnrealizedPnL += pnl
+			p.GrossExposure += notional
+			if !seenTrader[symbol][src.trader.TraderID] {
+				seenTrader[symbol][src.trader.TraderID] = true
+				exp.TraderIDs = append(exp.TraderIDs, src.trader.TraderID)
+			}
+			if src.trader.Exchange != "" && !seenExchange[symbol][src.trader.Exchange] {
+				seenExchange[symbol][src.trader.Exchange] = true
+				exp.Exchanges = append(exp.Exchanges, src.trader.Exchange)
+			}
+		}
+	}
+
+	for _, exp := range exposures {
+		exp.NetNotional = exp.LongNotional - exp.ShortNotional
+		if p.TotalEquity > 0 {
+			exp.NetPctOfEquity = exp.NetNotional / p.TotalEquity * 100
+		}
+		p.NetExposure += math.Abs(exp.NetNotional)
+		p.Exposures = append(p.Exposures, *exp)
+	}
+	sort.Slice(p.Exposures, func(i, j int) bool {
+		ni, nj := math.Abs(p.Exposures[i].NetNotional), math.Abs(p.Exposures[j].NetNotional)
+		if ni != nj {
+			return ni > nj
+		}
+		return p.Exposures[i].Symbol < p.Exposures[j].Symbol
+	})
+
+	p.TotalPnL = p.TotalEquity - p.InitialBalance
+	if p.InitialBalance > 0 {
+		p.TotalPnLPct = p.TotalPnL / p.InitialBalance * 100
+	}
+	if p.TotalEquity > 0 {
+		p.MarginUsedPct = p.MarginUsed / p.TotalEquity * 100
+		p.EffectiveLeverage = p.NetExposure / p.TotalEquity
+	}
+	return p
+}
+
+// mergeEquityCurves sums trader equity series into one curve of at most maxPoints equal time buckets.
+// Each trader's last known equity is carried forward until its next snapshot; a trader counts from
+// its first snapshot on. Traders without an initial balance use their first snapshot equity.
+func mergeEquityCurves(series map[string][]*store.EquitySnapshot, initial map[string]float64, maxPoints int) []PortfolioPoint {
+	type entry struct {
+		traderID string
+		snap     *store.EquitySnapshot
+	}
+	var all []entry
+	for traderID, snapshots := range series {
+		for _, snap := range snapshots {
+			all = append(all, entry{traderID: traderID, snap: snap})
+		}
+	}
+	if len(all) == 0 {
+		return []PortfolioPoint{}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].snap.Timestamp.Before(all[j].snap.Timestamp) })
+
+	first := all[0].snap.Timestamp
+	var bucket time.Duration
+	if maxPoints > 0 {
+		bucket = all[len(all)-1].snap.Timestamp.Sub(first) / time.Duration(maxPoints)
+	}
+	bucketOf := func(t time.Time) int64 {
+		if bucket <= 0 {
+			return t.UnixNano()
+		}
+		return int64(t.Sub(first) / bucket)
+	}
+
+	latest := make(map[string]float64)
+	base := make(map[string]float64)
+	curve := make([]PortfolioPoint, 0, maxPoints+1)
+	for i, e := range all {
+		latest[e.traderID] = e.snap.TotalEquity
+		if _, ok := base[e.traderID]; !ok {
+			base[e.traderID] = initial[e.traderID]
+			if base[e.traderID] <= 0 {
+				base[e.traderID] = e.snap.TotalEquity
+			}
+		}
+		if i < len(all)-1 && bucketOf(all[i+1].snap.Timestamp) == bucketOf(e.snap.Timestamp) {
+			continue // Not the last snapshot of its bucket
+		}
+
+		point := PortfolioPoint{Timestamp: e.snap.Timestamp}
+		totalBase := 0.0
+		for traderID, equity := range latest {
+			point.TotalEquity += equity
+			totalBase += base[traderID]
+		}
+		point.PnL = point.TotalEquity - totalBase
+		if totalBase > 0 {
+			point.PnLPct = point.PnL / totalBase * 100
+		}
+		curve = append(curve, point)
+	}
+	return curve
+}
@@ -0,0 +1,79 @@
+package manager
+
+import (
+	"math"
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+func TestAggregatePortfolio_NetsExposureAcrossTraders(t *testing.T) {
+	sources := []portfolioSource{
+		{
+			trader: PortfolioTrader{TraderID: "a", Exchange: "binance", TotalEquity: 6000, InitialBalance: 5000, MarginUsed: 600, PositionCount: 2},
+			positions: []map[string]interface{}{
+				{"symbol": "BTCUSDT", "side": "long", "quantity": 0.1, "mark_price": 60000.0, "unrealized_pnl": 100.0},
+				{"symbol": "ETHUSDT", "side": "long", "quantity": 1.0, "mark_price": 3000.0, "unrealized_pnl": 0.0},
+			},
+		},
+		{
+			trader: PortfolioTrader{TraderID: "b", Exchange: "bybit", TotalEquity: 4000, InitialBalance: 5000, MarginUsed: 400, PositionCount: 1},
+			positions: []map[string]interface{}{
+				{"symbol": "BTCUSDT", "side": "short", "quantity": 0.05, "mark_price": 60000.0, "unrealized_pnl": -20.0},
+			},
+		},
+		{
+			// Paper traders are listed but never counted
+			trader:    PortfolioTrader{TraderID: "p", PaperMode: true, TotalEquity: 100000, InitialBalance: 100000},
+			positions: []map[string]interface{}{{"symbol": "BTCUSDT", "side": "long", "quantity": 10.0, "mark_price": 60000.0}},
+		},
+	}
+
+	p := aggregatePortfolio(sources)
+
+	if p.TotalEquity != 10000 || p.TotalPnL != 0 || p.MarginUsedPct != 10 || len(p.Traders) != 3 {
+		t.Fatalf("unexpected totals: equity=%.2f pnl=%.2f margin=%.2f%% traders=%d", p.TotalEquity, p.TotalPnL, p.MarginUsedPct, len(p.Traders))
+	}
+	if len(p.Exposures) != 2 || p.Exposures[0].Symbol != "BTCUSDT" {
+		t.Fatalf("expected BTC first (equal net exposure sorts by symbol), got %+v", p.Exposures)
+	}
+	btc := p.Exposures[0]
+	if btc.LongNotional != 6000 || btc.ShortNotional != 3000 || btc.NetNotional != 3000 || btc.NetPctOfEquity != 30 {
+		t.Errorf("BTC exposure not netted across traders: %+v", btc)
+	}
+	if len(btc.TraderIDs) != 2 || len(btc.Exchanges) != 2 {
+		t.Errorf("BTC exposure should list both traders and exchanges: %+v", btc)
+	}
+	if p.GrossExposure != 12000 || p.NetExposure != 6000 || p.EffectiveLeverage != 0.6 {
+		t.Errorf("unexpected exposure totals: gross=%.2f net=%.2f leverage=%.2f", p.GrossExposure, p.NetExposure, p.EffectiveLeverage)
+	}
+}
+
+func TestMergeEquityCurves_CarriesForward(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	series := map[string][]*store.EquitySnapshot{
+		"a": {
+			{Timestamp: t0, TotalEquity: 1000},
+			{Timestamp: t0.Add(2 * time.Hour), TotalEquity: 1100},
+		},
+		"b": {
+			{Timestamp: t0.Add(time.Hour), TotalEquity: 500},
+		},
+	}
+	curve := mergeEquityCurves(series, map[string]float64{"a": 1000}, 0)
+
+	if len(curve) != 3 {
+		t.Fatalf("expected one point per timestamp, got %d", len(curve))
+	}
+	if curve[0].TotalEquity != 1000 || curve[1].TotalEquity != 1500 || curve[2].TotalEquity != 1600 {
+		t.Errorf("equity not carried forward: %+v", curve)
+	}
+	// b has no initial balance, its first snapshot is the baseline
+	if curve[2].PnL != 100 || math.Abs(curve[2].PnLPct-100.0/15) > 1e-9 {
+		t.Errorf("unexpected final PnL: %+v", curve[2])
+	}
+
+	if resampled := mergeEquityCurves(series, nil, 1); len(resampled) > 2 {
+		t.Errorf("expected at most 2 points when resampled to 1 bucket, got %d", len(resampled))
+	}
+}
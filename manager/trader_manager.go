@@ -6,6 +6,9 @@ import (
 	"nofx/debate"
 	"nofx/kernel"
 	"nofx/logger"
+	"nofx/market"
+	"nofx/provider/news"
+	"nofx/provider/sentiment"
 	"nofx/store"
 	"nofx/trader"
 	"sort"
@@ -46,6 +49,17 @@ type TraderManager struct {
 	traders          map[string]*trader.AutoTrader // key: trader ID
 	loadErrors       map[string]error              // key: trader ID, stores last load error
 	competitionCache *CompetitionCache
+	portfolios       *portfolioCache
+	breakerDefaults  store.CircuitBreakerConfig  // Global circuit breaker defaults
+	privacy          *store.PrivacyStore         // Owner privacy settings for public data (nil: all competition traders public)
+	regimes          *market.RegimeService       // Market regime source for directional exposure caps (nil: disabled)
+	symbolStatus     *market.SymbolStatusService // Delisted symbols and maintenance windows (nil: disabled)
+	news             *news.Service               // Headlines and economic calendar context (nil: disabled)
+	sentiment        *sentiment.Service          // Fear & Greed index and social sentiment (nil: disabled)
+	decisionBus      *trader.DecisionBus         // Executed decisions fanned out to copy trading followers (nil: disabled)
+	killSwitch       *trader.KillSwitch          // System-wide operator halt obeyed by every trader
+	minScanInterval  time.Duration               // System floor for trader scan intervals (0: trader.DefaultMinScanInterval)
+	cycleTimeout     time.Duration               // Max run time of a cycle (0: scan interval minus a buffer)
 	mu               sync.RWMutex
 }
 
@@ -57,7 +71,185 @@ func NewTraderManager() *TraderManager {
 		competitionCache: &CompetitionCache{
 			data: make(map[string]interface{}),
 		},
+		portfolios: &portfolioCache{
+			entries: make(map[string]*Portfolio),
+		},
+		killSwitch: trader.NewKillSwitch(),
+	}
+}
+
+// SetCircuitBreakerDefaults sets global circuit breaker defaults applied to traders loaded afterwards
+func (tm *TraderManager) SetCircuitBreakerDefaults(cfg store.CircuitBreakerConfig) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.breakerDefaults = cfg
+}
+
+// SetPrivacyStore sets the owner privacy settings source used to filter and anonymize competition data
+func (tm *TraderManager) SetPrivacyStore(privacy *store.PrivacyStore) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.privacy = privacy
+}
+
+// SetRegimeService sets the market regime source injected into traders loaded afterwards
+func (tm *TraderManager) SetRegimeService(regimes *market.RegimeService) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.regimes = regimes
+}
+
+// SetSymbolStatusService sets the delisting/maintenance source injected into traders loaded afterwards
+func (tm *TraderManager) SetSymbolStatusService(status *market.SymbolStatusService) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.symbolStatus = status
+}
+
+// SetNewsService sets the headline/economic calendar source injected into traders loaded afterwards
+func (tm *TraderManager) SetNewsService(service *news.Service) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.news = service
+}
+
+// SetSentimentService sets the market sentiment source injected into traders loaded afterwards
+func (tm *TraderManager) SetSentimentService(service *sentiment.Service) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.sentiment = service
+}
+
+// SentimentService returns the market sentiment source (nil: disabled)
+func (tm *TraderManager) SentimentService() *sentiment.Service {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return tm.sentiment
+}
+
+// SetDecisionBus sets the executed decision bus injected into traders loaded afterwards,
+// and copies every published decision to the loaded followers of its leader
+func (tm *TraderManager) SetDecisionBus(bus *trader.DecisionBus) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.decisionBus = bus
+	bus.Subscribe(tm.fanOutDecision)
+}
+
+// SetCopyLink updates the copy trading link of a loaded follower (nil: stop copying)
+func (tm *TraderManager) SetCopyLink(followerID string, link *store.CopyTradeLink) {
+	tm.mu.RLock()
+	at, ok := tm.traders[followerID]
+	tm.mu.RUnlock()
+	if ok {
+		at.SetCopyLink(link)
+	}
+}
+
+// fanOutDecision executes a leader's decision on its followers concurrently,
+// waiting for all of them so each follower sees the leader's decisions in order
+func (tm *TraderManager) fanOutDecision(ev trader.DecisionEvent) {
+	tm.mu.RLock()
+	var followers []*trader.AutoTrader
+	for _, at := range tm.traders {
+		if link := at.CopyLink(); link != nil && link.Enabled && link.LeaderID == ev.TraderID {
+			followers = append(followers, at)
+		}
+	}
+	tm.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, follower := range followers {
+		wg.Add(1)
+		go func(at *trader.AutoTrader) {
+			defer wg.Done()
+			if err := at.ExecuteCopiedDecision(ev); err != nil {
+				logger.Warnf("⚠️ Copy trade %s %s to '%s' failed: %v", ev.Decision.Action, ev.Decision.Symbol, at.GetName(), err)
+			}
+		}(follower)
+	}
+	wg.Wait()
+}
+
+// SymbolStatusService returns the delisting/maintenance source (nil: disabled)
+func (tm *TraderManager) SymbolStatusService() *market.SymbolStatusService {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return tm.symbolStatus
+}
+
+// KillSwitch returns the system-wide kill switch shared by all traders
+func (tm *TraderManager) KillSwitch() *trader.KillSwitch {
+	return tm.killSwitch
+}
+
+// KillSwitchResult what engaging the kill switch did to one trader
+type KillSwitchResult struct {
+	TraderID        string `json:"trader_id"`
+	TraderName      string `json:"trader_name"`
+	OrdersCancelled int    `json:"order_symbols_cancelled"`
+	PositionsClosed int    `json:"positions_closed"`
+	Error           string `json:"error,omitempty"`
+}
+
+// EngageKillSwitch halts all traders and, as requested by state, cancels their open orders and/or
+// closes their positions. Traders are handled in parallel; cycles already past their kill switch
+// check cannot open positions either.
+func (tm *TraderManager) EngageKillSwitch(state trader.KillSwitchState) []KillSwitchResult {
+	state.Active = true
+	tm.killSwitch.Set(state)
+
+	tm.mu.RLock()
+	traders := make([]*trader.AutoTrader, 0, len(tm.traders))
+	for _, t := range tm.traders {
+		traders = append(traders, t)
 	}
+	tm.mu.RUnlock()
+
+	results := make([]KillSwitchResult, len(traders))
+	var wg sync.WaitGroup
+	for i, t := range traders {
+		wg.Add(1)
+		go func(i int, t *trader.AutoTrader) {
+			defer wg.Done()
+			result := KillSwitchResult{TraderID: t.GetID(), TraderName: t.GetName()}
+			var err error
+			result.OrdersCancelled, result.PositionsClosed, err = t.ApplyKillSwitch(state)
+			if err != nil {
+				result.Error = err.Error()
+			}
+			results[i] = result
+		}(i, t)
+	}
+	wg.Wait()
+	return results
+}
+
+// ReleaseKillSwitch lets traders run their cycles again
+func (tm *TraderManager) ReleaseKillSwitch() {
+	tm.killSwitch.Set(trader.KillSwitchState{})
+}
+
+// SetMinScanInterval sets the system floor for scan intervals of traders loaded afterwards
+func (tm *TraderManager) SetMinScanInterval(d time.Duration) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.minScanInterval = d
+}
+
+// SetCycleTimeout sets the cycle deadline of traders loaded afterwards (0: each trader's scan interval minus a buffer)
+func (tm *TraderManager) SetCycleTimeout(d time.Duration) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.cycleTimeout = d
+}
+
+// InvalidateCompetitionCache drops cached competition data (after visibility or privacy changes)
+func (tm *TraderManager) InvalidateCompetitionCache() {
+	tm.competitionCache.mu.Lock()
+	tm.competitionCache.data = make(map[string]interface{})
+	tm.competitionCache.timestamp = time.Time{}
+	tm.competitionCache.mu.Unlock()
 }
 
 // GetLoadError returns the last load error for a trader
@@ -125,9 +317,16 @@ func (tm *TraderManager) StopAll() {
 	defer tm.mu.RUnlock()
 
 	logger.Info("⏹  Stopping all traders...")
+	// In parallel: each Stop waits for the trader's running cycle to finish
+	var wg sync.WaitGroup
 	for _, t := range tm.traders {
-		t.Stop()
+		wg.Add(1)
+		go func(t *trader.AutoTrader) {
+			defer wg.Done()
+			t.Stop()
+		}(t)
 	}
+	wg.Wait()
 }
 
 // AutoStartRunningTraders automatically starts traders marked as running in the database
@@ -228,15 +427,32 @@ func (tm *TraderManager) GetCompetitionData() (map[string]interface{}, error) {
 
 	tm.mu.RLock()
 
-	// Get all trader list (only those with ShowInCompetition = true)
+	// Get all trader list (only those with ShowInCompetition = true whose owner opted in)
+	privacy := tm.privacy
+	settingsByUser := make(map[string]*store.PrivacySettings)
 	allTraders := make([]*trader.AutoTrader, 0, len(tm.traders))
 	for id, t := range tm.traders {
-		if t.GetShowInCompetition() {
-			allTraders = append(allTraders, t)
-			logger.Infof("📋 Competition data includes trader: %s (%s)", t.GetName(), id)
-		} else {
+		if !t.GetShowInCompetition() {
 			logger.Infof("📋 Competition data excludes trader (hidden): %s (%s)", t.GetName(), id)
+			continue
 		}
+		if privacy != nil {
+			settings, ok := settingsByUser[t.GetUserID()]
+			if !ok {
+				var err error
+				if settings, err = privacy.Get(t.GetUserID()); err != nil {
+					logger.Warnf("⚠️ Failed to get privacy settings of trader %s owner: %v", id, err)
+					settings = &store.PrivacySettings{UserID: t.GetUserID()}
+				}
+				settingsByUser[t.GetUserID()] = settings
+			}
+			if !settings.LeaderboardOptIn {
+				logger.Infof("📋 Competition data excludes trader (owner not opted in): %s (%s)", t.GetName(), id)
+				continue
+			}
+		}
+		allTraders = append(allTraders, t)
+		logger.Infof("📋 Competition data includes trader: %s (%s)", t.GetName(), id)
 	}
 	tm.mu.RUnlock()
 
@@ -245,6 +461,12 @@ func (tm *TraderManager) GetCompetitionData() (map[string]interface{}, error) {
 	// Concurrently fetch trader data
 	traders := tm.getConcurrentTraderData(allTraders)
 
+	// Anonymize names of owners who asked for it
+	for i, data := range traders {
+		name, _ := data["trader_name"].(string)
+		data["trader_name"] = settingsByUser[allTraders[i].GetUserID()].DisplayName(allTraders[i].GetID(), name)
+	}
+
 	// Sort by profit rate (descending)
 	sort.Slice(traders, func(i, j int) bool {
 		pnlPctI, okI := traders[i]["total_pnl_pct"].(float64)
@@ -625,6 +847,48 @@ func (tm *TraderManager) LoadTradersFromStore(st *store.Store) error {
 	return nil
 }
 
+// resolveFallbackModels resolves the trader's fallback AI model IDs to enabled models of its owner.
+// Missing, disabled and duplicate-of-primary models are skipped.
+func resolveFallbackModels(traderCfg *store.Trader, primary *store.AIModel, st *store.Store) []trader.AIModelEndpoint {
+	ids := traderCfg.FallbackModelIDList()
+	if len(ids) == 0 {
+		return nil
+	}
+	aiModels, err := st.AIModel().List(traderCfg.UserID)
+	if err != nil {
+		logger.Infof("⚠️  Failed to get AI models for fallback chain of trader %s: %v", traderCfg.Name, err)
+		return nil
+	}
+
+	var endpoints []trader.AIModelEndpoint
+	for _, id := range ids {
+		var model *store.AIModel
+		for _, m := range aiModels {
+			if m.ID == id {
+				model = m
+				break
+			}
+		}
+		switch {
+		case model == nil:
+			logger.Infof("⚠️  Fallback AI model %s for trader %s does not exist, skipping", id, traderCfg.Name)
+		case !model.Enabled:
+			logger.Infof("⚠️  Fallback AI model %s for trader %s is not enabled, skipping", id, traderCfg.Name)
+		case model.ID == primary.ID:
+			// Already the primary model
+		default:
+			endpoints = append(endpoints, trader.AIModelEndpoint{
+				ID:              model.ID,
+				Provider:        model.Provider,
+				APIKey:          string(model.APIKey),
+				CustomAPIURL:    model.CustomAPIURL,
+				CustomModelName: model.CustomModelName,
+			})
+		}
+	}
+	return endpoints
+}
+
 // addTraderFromStore internal method: adds trader from store configuration
 func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg *store.AIModel, exchangeCfg *store.Exchange, st *store.Store) error {
 	if _, exists := tm.traders[traderCfg.ID]; exists {
@@ -644,10 +908,19 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 			return fmt.Errorf("failed to parse strategy config for trader %s: %w", traderCfg.Name, err)
 		}
 		logger.Infof("✓ Trader %s loaded strategy config: %s", traderCfg.Name, strategy.Name)
+		if err := st.ResolvePromptTemplate(traderCfg.UserID, strategyConfig, traderCfg.SystemPromptTemplate); err != nil {
+			logger.Warnf("⚠️ Trader %s: prompt template not loaded, using the strategy prompt only: %v", traderCfg.Name, err)
+		}
 	} else {
 		return fmt.Errorf("trader %s has no strategy configured", traderCfg.Name)
 	}
 
+	// Scan interval stored in DB may predate a raised floor, clamp it again
+	scanInterval, clampReason := trader.ClampScanInterval(traderCfg.ScanInterval(), aiModelCfg.Provider, tm.minScanInterval)
+	if clampReason != "" {
+		logger.Warnf("⚠️ Trader %s: %s", traderCfg.Name, clampReason)
+	}
+
 	// Build AutoTraderConfig (ai500APIURL/oiTopAPIURL obtained from strategy config, used in StrategyEngine)
 	traderConfig := trader.AutoTraderConfig{
 		ID:                    traderCfg.ID,
@@ -664,15 +937,20 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 		QwenKey:               "",
 		CustomAPIURL:          aiModelCfg.CustomAPIURL,
 		CustomModelName:       aiModelCfg.CustomModelName,
-		ScanInterval:         time.Duration(traderCfg.ScanIntervalMinutes) * time.Minute,
+		ScanInterval:         scanInterval,
+		CycleTimeout:         tm.cycleTimeout,
 		InitialBalance:       traderCfg.InitialBalance,
 		IsCrossMargin:        traderCfg.IsCrossMargin,
 		ShowInCompetition:    traderCfg.ShowInCompetition,
+		PaperMode:            traderCfg.PaperMode,
+		ContractType:         traderCfg.ContractType,
+		MarginCoin:           traderCfg.MarginCoin,
 		StrategyConfig:       strategyConfig,
+		CircuitBreaker:       strategyConfig.RiskControl.CircuitBreaker.WithDefaults(tm.breakerDefaults),
 	}
 
-	logger.Infof("📊 Loading trader %s: ScanIntervalMinutes=%d (from DB), ScanInterval=%v",
-		traderCfg.Name, traderCfg.ScanIntervalMinutes, traderConfig.ScanInterval)
+	logger.Infof("📊 Loading trader %s: ScanIntervalMinutes=%d ScanIntervalSeconds=%d (from DB), ScanInterval=%v",
+		traderCfg.Name, traderCfg.ScanIntervalMinutes, traderCfg.ScanIntervalSeconds, traderConfig.ScanInterval)
 
 	// Set API keys based on exchange type (convert EncryptedString to string)
 	switch exchangeCfg.ExchangeType {
@@ -690,6 +968,7 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 		traderConfig.BitgetAPIKey = string(exchangeCfg.APIKey)
 		traderConfig.BitgetSecretKey = string(exchangeCfg.SecretKey)
 		traderConfig.BitgetPassphrase = string(exchangeCfg.Passphrase)
+		traderConfig.BitgetTestnet = exchangeCfg.Testnet
 	case "gate":
 		traderConfig.GateAPIKey = string(exchangeCfg.APIKey)
 		traderConfig.GateSecretKey = string(exchangeCfg.SecretKey)
@@ -723,12 +1002,38 @@ func (tm *TraderManager) addTraderFromStore(traderCfg *store.Trader, aiModelCfg
 		traderConfig.CustomAPIKey = string(aiModelCfg.APIKey)
 	}
 
+	traderConfig.FallbackModels = resolveFallbackModels(traderCfg, aiModelCfg, st)
+
 	// Create trader instance
 	at, err := trader.NewAutoTrader(traderConfig, st, traderCfg.UserID)
 	if err != nil {
 		return fmt.Errorf("failed to create trader: %w", err)
 	}
 
+	if tm.regimes != nil {
+		at.SetRegimeService(tm.regimes)
+	}
+	if tm.symbolStatus != nil {
+		at.SetSymbolStatusService(tm.symbolStatus)
+	}
+	if tm.news != nil {
+		at.SetNewsService(tm.news)
+	}
+	if tm.sentiment != nil {
+		at.SetSentimentService(tm.sentiment)
+	}
+	if tm.decisionBus != nil {
+		at.SetDecisionBus(tm.decisionBus)
+	}
+	at.SetKillSwitch(tm.killSwitch)
+	if st != nil {
+		if link, err := st.CopyTrade().GetByFollower(traderCfg.ID); err != nil {
+			logger.Warnf("⚠️ Failed to load copy trading link of '%s': %v", traderCfg.Name, err)
+		} else if link != nil {
+			at.SetCopyLink(link)
+		}
+	}
+
 	// Set custom prompt (if exists)
 	if traderCfg.CustomPrompt != "" {
 		at.SetCustomPrompt(traderCfg.CustomPrompt)
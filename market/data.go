@@ -19,8 +19,9 @@ import (
 // FundingRateCache is the funding rate cache structure
 // Binance Funding Rate only updates every 8 hours, using 1-hour cache can significantly reduce API calls
 type FundingRateCache struct {
-	Rate      float64
-	UpdatedAt time.Time
+	Rate            float64
+	NextFundingTime time.Time // Next funding settlement, zero if unknown
+	UpdatedAt       time.Time
 }
 
 var (
@@ -191,10 +192,10 @@ func GetWithExchange(symbol, exchange string) (*Data, error) {
 			return nil, fmt.Errorf("Failed to get 5-minute K-line from Hyperliquid: %v", err)
 		}
 	} else {
-		// Use CoinAnk for regular crypto assets with exchange-specific data
-		klines3m, err = getKlinesFromCoinAnk(symbol, "3m", exchange, 100)
+		// Regular crypto assets use the provider chain (CoinAnk with exchange-specific data first by default)
+		klines3m, err = getKlines(symbol, "3m", exchange, 100, nil)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to get 3-minute K-line from CoinAnk (%s): %v", exchange, err)
+			return nil, fmt.Errorf("Failed to get 3-minute K-line (%s): %v", exchange, err)
 		}
 	}
 
@@ -211,9 +212,9 @@ func GetWithExchange(symbol, exchange string) (*Data, error) {
 			return nil, fmt.Errorf("Failed to get 4-hour K-line from Hyperliquid: %v", err)
 		}
 	} else {
-		klines4h, err = getKlinesFromCoinAnk(symbol, "4h", exchange, 100)
+		klines4h, err = getKlines(symbol, "4h", exchange, 100, nil)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to get 4-hour K-line from CoinAnk (%s): %v", exchange, err)
+			return nil, fmt.Errorf("Failed to get 4-hour K-line (%s): %v", exchange, err)
 		}
 	}
 
@@ -258,7 +259,7 @@ func GetWithExchange(symbol, exchange string) (*Data, error) {
 	}
 
 	// Get Funding Rate
-	fundingRate, _ := getFundingRate(symbol)
+	fundingRate, nextFundingTime, _ := GetFundingRate(symbol)
 
 	// Calculate intraday series data
 	intradayData := calculateIntradaySeries(klines3m)
@@ -266,7 +267,7 @@ func GetWithExchange(symbol, exchange string) (*Data, error) {
 	// Calculate longer-term data
 	longerTermData := calculateLongerTermData(klines4h)
 
-	return &Data{
+	data := &Data{
 		Symbol:            symbol,
 		CurrentPrice:      currentPrice,
 		PriceChange1h:     priceChange1h,
@@ -276,9 +277,12 @@ func GetWithExchange(symbol, exchange string) (*Data, error) {
 		CurrentRSI7:       currentRSI7,
 		OpenInterest:      oiData,
 		FundingRate:       fundingRate,
+		NextFundingTime:   nextFundingTime,
 		IntradaySeries:    intradayData,
 		LongerTermContext: longerTermData,
-	}, nil
+	}
+	recordDerivatives(data)
+	return data, nil
 }
 
 // GetWithTimeframes retrieves market data for specified multiple timeframes
@@ -286,6 +290,12 @@ func GetWithExchange(symbol, exchange string) (*Data, error) {
 // primaryTimeframe: primary timeframe (used for calculating current indicators), defaults to timeframes[0]
 // count: number of K-lines for each timeframe
 func GetWithTimeframes(symbol string, timeframes []string, primaryTimeframe string, count int) (*Data, error) {
+	return GetWithTimeframesFrom(symbol, timeframes, primaryTimeframe, count, nil)
+}
+
+// GetWithTimeframesFrom is GetWithTimeframes trying the preferred market data providers first
+// (empty = the server's default order), failing over to the others
+func GetWithTimeframesFrom(symbol string, timeframes []string, primaryTimeframe string, count int, preferredProviders []string) (*Data, error) {
 	symbol = Normalize(symbol)
 
 	if len(timeframes) == 0 {
@@ -329,10 +339,10 @@ func GetWithTimeframes(symbol string, timeframes []string, primaryTimeframe stri
 				continue
 			}
 		} else {
-			// Use CoinAnk for regular crypto assets (default to Binance)
-			klines, err = getKlinesFromCoinAnk(symbol, tf, "binance", 200)
+			// Use the provider chain for regular crypto assets (default to Binance data)
+			klines, err = getKlines(symbol, tf, "binance", 200, preferredProviders)
 			if err != nil {
-				logger.Infof("⚠️ Failed to get %s %s K-line from CoinAnk: %v", symbol, tf, err)
+				logger.Infof("⚠️ Failed to get %s %s K-line: %v", symbol, tf, err)
 				continue
 			}
 		}
@@ -380,20 +390,23 @@ func GetWithTimeframes(symbol string, timeframes []string, primaryTimeframe stri
 	}
 
 	// Get Funding Rate
-	fundingRate, _ := getFundingRate(symbol)
-
-	return &Data{
-		Symbol:        symbol,
-		CurrentPrice:  currentPrice,
-		PriceChange1h: priceChange1h,
-		PriceChange4h: priceChange4h,
-		CurrentEMA20:  currentEMA20,
-		CurrentMACD:   currentMACD,
-		CurrentRSI7:   currentRSI7,
-		OpenInterest:  oiData,
-		FundingRate:   fundingRate,
-		TimeframeData: timeframeData,
-	}, nil
+	fundingRate, nextFundingTime, _ := GetFundingRate(symbol)
+
+	data := &Data{
+		Symbol:          symbol,
+		CurrentPrice:    currentPrice,
+		PriceChange1h:   priceChange1h,
+		PriceChange4h:   priceChange4h,
+		CurrentEMA20:    currentEMA20,
+		CurrentMACD:     currentMACD,
+		CurrentRSI7:     currentRSI7,
+		OpenInterest:    oiData,
+		FundingRate:     fundingRate,
+		NextFundingTime: nextFundingTime,
+		TimeframeData:   timeframeData,
+	}
+	recordDerivatives(data)
+	return data, nil
 }
 
 // calculateTimeframeSeries calculates series data for a single timeframe
@@ -415,6 +428,7 @@ func calculateTimeframeSeries(klines []Kline, timeframe string, count int) *Time
 		BOLLUpper:   make([]float64, 0, count),
 		BOLLMiddle:  make([]float64, 0, count),
 		BOLLLower:   make([]float64, 0, count),
+		history:     klines,
 	}
 
 	// Get latest N data points based on count from config
@@ -822,15 +836,16 @@ func getOpenInterestData(symbol string) (*OIData, error) {
 	}, nil
 }
 
-// getFundingRate retrieves funding rate (optimized: uses 1-hour cache)
-func getFundingRate(symbol string) (float64, error) {
+// GetFundingRate retrieves the Binance funding rate and next funding time of a symbol (optimized: uses 1-hour cache)
+func GetFundingRate(symbol string) (float64, time.Time, error) {
 	// Check cache (1-hour validity)
 	// Funding Rate only updates every 8 hours, 1-hour cache is very reasonable
 	if cached, ok := fundingRateMap.Load(symbol); ok {
 		cache := cached.(*FundingRateCache)
-		if time.Since(cache.UpdatedAt) < frCacheTTL {
+		// The cached next funding time is stale once it has passed
+		if time.Since(cache.UpdatedAt) < frCacheTTL && (cache.NextFundingTime.IsZero() || time.Now().Before(cache.NextFundingTime)) {
 			// Cache hit, return directly
-			return cache.Rate, nil
+			return cache.Rate, cache.NextFundingTime, nil
 		}
 	}
 
@@ -840,13 +855,13 @@ func getFundingRate(symbol string) (float64, error) {
 	apiClient := NewAPIClient()
 	resp, err := apiClient.client.Get(url)
 	if err != nil {
-		return 0, err
+		return 0, time.Time{}, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return 0, err
+		return 0, time.Time{}, err
 	}
 
 	var result struct {
@@ -860,18 +875,23 @@ func getFundingRate(symbol string) (float64, error) {
 	}
 
 	if err := json.Unmarshal(body, &result); err != nil {
-		return 0, err
+		return 0, time.Time{}, err
 	}
 
 	rate, _ := strconv.ParseFloat(result.LastFundingRate, 64)
+	var nextFunding time.Time
+	if result.NextFundingTime > 0 {
+		nextFunding = time.UnixMilli(result.NextFundingTime)
+	}
 
 	// Update cache
 	fundingRateMap.Store(symbol, &FundingRateCache{
-		Rate:      rate,
-		UpdatedAt: time.Now(),
+		Rate:            rate,
+		NextFundingTime: nextFunding,
+		UpdatedAt:       time.Now(),
 	})
 
-	return rate, nil
+	return rate, nextFunding, nil
 }
 
 // Format formats and outputs market data
@@ -1327,7 +1347,7 @@ func GetBoxData(symbol string) (*BoxData, error) {
 	if IsXyzDexAsset(symbol) {
 		klines, err = getKlinesFromHyperliquid(symbol, "1h", LongBoxPeriod)
 	} else {
-		klines, err = getKlinesFromCoinAnk(symbol, "1h", "binance", LongBoxPeriod)
+		klines, err = getKlines(symbol, "1h", "binance", LongBoxPeriod, nil)
 	}
 
 	if err != nil {
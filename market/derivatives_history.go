@@ -0,0 +1,58 @@
+package market
+
+import (
+	"sync"
+	"time"
+)
+
+// DerivativesRecordInterval minimum time between two recorded readings of a symbol
+// Many traders fetch the same symbols every cycle; one reading per interval is enough for charting.
+const DerivativesRecordInterval = 5 * time.Minute
+
+// DerivativesReading funding rate and open interest of a symbol, as fetched for a decision cycle
+type DerivativesReading struct {
+	Symbol       string
+	Time         time.Time // Truncated to DerivativesRecordInterval
+	FundingRate  float64
+	OpenInterest float64 // Contracts (base asset)
+	Price        float64 // Price the open interest value is computed at
+}
+
+var (
+	derivativesMu       sync.Mutex
+	derivativesRecorder func(DerivativesReading)
+	derivativesLast     = make(map[string]time.Time) // symbol -> last recorded slot
+)
+
+// SetDerivativesRecorder registers the function storing funding rate and open interest readings (nil: not recorded)
+// It is called from its own goroutine, at most once per symbol and DerivativesRecordInterval.
+func SetDerivativesRecorder(fn func(DerivativesReading)) {
+	derivativesMu.Lock()
+	defer derivativesMu.Unlock()
+	derivativesRecorder = fn
+}
+
+// recordDerivatives hands the funding rate and open interest of freshly fetched data to the recorder
+func recordDerivatives(data *Data) {
+	if data == nil || data.OpenInterest == nil || (data.FundingRate == 0 && data.OpenInterest.Latest == 0) {
+		return // Both fetches failed (or xyz dex asset without Binance data)
+	}
+
+	slot := time.Now().UTC().Truncate(DerivativesRecordInterval)
+	derivativesMu.Lock()
+	fn := derivativesRecorder
+	if fn == nil || !derivativesLast[data.Symbol].Before(slot) {
+		derivativesMu.Unlock()
+		return
+	}
+	derivativesLast[data.Symbol] = slot
+	derivativesMu.Unlock()
+
+	go fn(DerivativesReading{
+		Symbol:       data.Symbol,
+		Time:         slot,
+		FundingRate:  data.FundingRate,
+		OpenInterest: data.OpenInterest.Latest,
+		Price:        data.CurrentPrice,
+	})
+}
@@ -0,0 +1,34 @@
+package market
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordDerivatives_OncePerInterval(t *testing.T) {
+	readings := make(chan DerivativesReading, 4)
+	SetDerivativesRecorder(func(r DerivativesReading) { readings <- r })
+	defer SetDerivativesRecorder(nil)
+
+	data := &Data{Symbol: "TESTUSDT", CurrentPrice: 2, FundingRate: 0.0001, OpenInterest: &OIData{Latest: 500}}
+	recordDerivatives(data)
+	recordDerivatives(data) // Same interval, dropped
+	recordDerivatives(&Data{Symbol: "EMPTYUSDT", OpenInterest: &OIData{}})
+
+	select {
+	case r := <-readings:
+		if r.Symbol != "TESTUSDT" || r.FundingRate != 0.0001 || r.OpenInterest != 500 || r.Price != 2 {
+			t.Errorf("unexpected reading: %+v", r)
+		}
+		if !r.Time.Equal(r.Time.Truncate(DerivativesRecordInterval)) {
+			t.Errorf("reading time should be aligned to the record interval: %v", r.Time)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a reading")
+	}
+	select {
+	case r := <-readings:
+		t.Errorf("expected one reading per interval, also got %+v", r)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -102,3 +102,62 @@ func GetKlinesRange(symbol string, timeframe string, start, end time.Time) ([]Kl
 
 	return all, nil
 }
+
+// GetWithTimeframesAt builds a symbol's market data as it stood at a past time, from the klines closed by then
+// Open interest and funding rate are not part of klines and are left empty for the caller to fill in.
+func GetWithTimeframesAt(symbol string, timeframes []string, primaryTimeframe string, count int, at time.Time) (*Data, error) {
+	symbol = Normalize(symbol)
+	if len(timeframes) == 0 {
+		return nil, fmt.Errorf("at least one timeframe is required")
+	}
+	if primaryTimeframe == "" {
+		primaryTimeframe = timeframes[0]
+	}
+
+	timeframeData := make(map[string]*TimeframeSeriesData)
+	var primaryKlines []Kline
+	for _, tf := range timeframes {
+		dur, err := TFDuration(tf)
+		if err != nil {
+			return nil, err
+		}
+		// Same history depth as the live data (200 bars)
+		klines, err := GetKlinesRange(symbol, tf, at.Add(-200*dur), at)
+		if err != nil {
+			return nil, fmt.Errorf("fetch %s %s klines: %w", symbol, tf, err)
+		}
+		klines = closedBy(klines, at)
+		if len(klines) == 0 {
+			continue
+		}
+		if tf == primaryTimeframe {
+			primaryKlines = klines
+		}
+		timeframeData[tf] = calculateTimeframeSeries(klines, tf, count)
+	}
+	if len(primaryKlines) == 0 {
+		return nil, fmt.Errorf("no %s %s klines before %s", symbol, primaryTimeframe, at.UTC().Format(time.RFC3339))
+	}
+
+	return &Data{
+		Symbol:        symbol,
+		CurrentPrice:  primaryKlines[len(primaryKlines)-1].Close,
+		PriceChange1h: calculatePriceChangeByBars(primaryKlines, primaryTimeframe, 60),
+		PriceChange4h: calculatePriceChangeByBars(primaryKlines, primaryTimeframe, 240),
+		CurrentEMA20:  calculateEMA(primaryKlines, 20),
+		CurrentMACD:   calculateMACD(primaryKlines),
+		CurrentRSI7:   calculateRSI(primaryKlines, 7),
+		OpenInterest:  &OIData{},
+		TimeframeData: timeframeData,
+	}, nil
+}
+
+// closedBy keeps the klines that had closed at a time, the bar still forming then would leak its future close
+func closedBy(klines []Kline, at time.Time) []Kline {
+	cutoff := at.UnixMilli()
+	n := len(klines)
+	for n > 0 && klines[n-1].CloseTime > cutoff {
+		n--
+	}
+	return klines[:n]
+}
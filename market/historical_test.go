@@ -0,0 +1,19 @@
+package market
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClosedBy(t *testing.T) {
+	at := time.UnixMilli(10_000)
+	klines := []Kline{{CloseTime: 8_999}, {CloseTime: 9_999}, {CloseTime: 10_000}, {CloseTime: 10_999}}
+
+	// The bar closing after the cutoff was still forming
+	if got := closedBy(klines, at); len(got) != 3 {
+		t.Errorf("kept %d klines, want 3", len(got))
+	}
+	if got := closedBy(klines, time.UnixMilli(1_000)); len(got) != 0 {
+		t.Errorf("kept %d klines before the first close", len(got))
+	}
+}
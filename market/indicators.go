@@ -0,0 +1,360 @@
+package market
+
+import (
+	"math"
+	"time"
+)
+
+// ============================================================================
+// Extended Indicator Engine
+// ============================================================================
+// Optional indicators computed server-side from a timeframe's full kline
+// history (not only the bars shown in the prompt), so warmup-heavy indicators
+// such as Ichimoku are already stable on the first displayed bar. Only the
+// indicators a strategy selects are computed.
+// ============================================================================
+
+// Extended indicator parameters (common defaults)
+const (
+	SuperTrendPeriod     = 10
+	SuperTrendMultiplier = 3.0
+	IchimokuTenkan       = 9
+	IchimokuKijun        = 26
+	IchimokuSenkouB      = 52
+	StochRSIPeriod       = 14
+	StochRSISmoothK      = 3
+	StochRSISmoothD      = 3
+)
+
+// IndicatorSelection extended indicators to compute for a timeframe series
+type IndicatorSelection struct {
+	BOLLPeriods []int // Bollinger Band periods besides the built-in 20 (std dev multiplier 2)
+	VWAP        bool  // Volume weighted average price, anchored at the UTC day start
+	SuperTrend  bool
+	Ichimoku    bool
+	OBV         bool // On-balance volume
+	StochRSI    bool
+}
+
+// Any returns whether any extended indicator is selected
+func (s IndicatorSelection) Any() bool {
+	return len(s.BOLLPeriods) > 0 || s.VWAP || s.SuperTrend || s.Ichimoku || s.OBV || s.StochRSI
+}
+
+// BOLLSeries Bollinger Bands of one period
+type BOLLSeries struct {
+	Period int       `json:"period"`
+	Upper  []float64 `json:"upper"`
+	Middle []float64 `json:"middle"`
+	Lower  []float64 `json:"lower"`
+}
+
+// IchimokuData latest Ichimoku cloud values
+type IchimokuData struct {
+	Tenkan  float64 `json:"tenkan"`   // Conversion line (9)
+	Kijun   float64 `json:"kijun"`    // Base line (26)
+	SenkouA float64 `json:"senkou_a"` // Leading span A at the current bar (projected 26 bars ago)
+	SenkouB float64 `json:"senkou_b"` // Leading span B at the current bar (projected 26 bars ago)
+	FutureA float64 `json:"future_a"` // Leading span A projected 26 bars ahead
+	FutureB float64 `json:"future_b"` // Leading span B projected 26 bars ahead
+}
+
+// ExtendedIndicators selected indicator series, oldest → latest
+// Series hold at most the requested number of bars, fewer while an indicator warms up.
+type ExtendedIndicators struct {
+	BOLL         []BOLLSeries  `json:"boll,omitempty"`
+	VWAP         []float64     `json:"vwap,omitempty"`
+	SuperTrend   []float64     `json:"supertrend,omitempty"`
+	SuperTrendUp []bool        `json:"supertrend_up,omitempty"` // true: uptrend (line below price)
+	Ichimoku     *IchimokuData `json:"ichimoku,omitempty"`
+	OBV          []float64     `json:"obv,omitempty"`
+	StochRSIK    []float64     `json:"stoch_rsi_k,omitempty"`
+	StochRSID    []float64     `json:"stoch_rsi_d,omitempty"`
+}
+
+// Indicators computes the selected extended indicators over the series' kline history
+// Returns nil when nothing is selected or no history is available.
+func (d *TimeframeSeriesData) Indicators(sel IndicatorSelection) *ExtendedIndicators {
+	if d == nil || len(d.history) == 0 || !sel.Any() {
+		return nil
+	}
+	return ComputeIndicators(d.history, sel, len(d.Klines))
+}
+
+// ComputeIndicators computes the selected indicators from klines, keeping the latest count values
+func ComputeIndicators(klines []Kline, sel IndicatorSelection, count int) *ExtendedIndicators {
+	if count <= 0 {
+		count = 10
+	}
+	result := &ExtendedIndicators{}
+
+	for _, period := range sel.BOLLPeriods {
+		if period <= 1 || period == 20 {
+			continue
+		}
+		upper, middle, lower := bollSeries(klines, period, 2.0)
+		if len(middle) == 0 {
+			continue
+		}
+		result.BOLL = append(result.BOLL, BOLLSeries{
+			Period: period,
+			Upper:  lastN(upper, count),
+			Middle: lastN(middle, count),
+			Lower:  lastN(lower, count),
+		})
+	}
+	if sel.VWAP {
+		result.VWAP = lastN(vwapSeries(klines), count)
+	}
+	if sel.SuperTrend {
+		line, up := superTrendSeries(klines, SuperTrendPeriod, SuperTrendMultiplier)
+		result.SuperTrend = lastN(line, count)
+		if len(up) > count {
+			up = up[len(up)-count:]
+		}
+		result.SuperTrendUp = up
+	}
+	if sel.Ichimoku {
+		result.Ichimoku = calculateIchimoku(klines)
+	}
+	if sel.OBV {
+		result.OBV = lastN(obvSeries(klines), count)
+	}
+	if sel.StochRSI {
+		k, d := stochRSISeries(klines, StochRSIPeriod, StochRSISmoothK, StochRSISmoothD)
+		result.StochRSIK = lastN(k, count)
+		result.StochRSID = lastN(d, count)
+	}
+	return result
+}
+
+// lastN returns the latest n values
+func lastN(values []float64, n int) []float64 {
+	if len(values) > n {
+		return values[len(values)-n:]
+	}
+	return values
+}
+
+// bollSeries Bollinger Bands for every bar from period-1 on
+func bollSeries(klines []Kline, period int, multiplier float64) (upper, middle, lower []float64) {
+	for i := period - 1; i < len(klines); i++ {
+		u, m, l := calculateBOLL(klines[:i+1], period, multiplier)
+		upper = append(upper, u)
+		middle = append(middle, m)
+		lower = append(lower, l)
+	}
+	return upper, middle, lower
+}
+
+// vwapSeries volume weighted average price of typical price, reset at each UTC day start
+func vwapSeries(klines []Kline) []float64 {
+	values := make([]float64, 0, len(klines))
+	var day int64 = -1
+	var pv, vol float64
+	for _, k := range klines {
+		if d := time.UnixMilli(k.OpenTime).UTC().Truncate(24 * time.Hour).Unix(); d != day {
+			day, pv, vol = d, 0, 0
+		}
+		typical := (k.High + k.Low + k.Close) / 3
+		pv += typical * k.Volume
+		vol += k.Volume
+		if vol > 0 {
+			values = append(values, pv/vol)
+		} else {
+			values = append(values, typical)
+		}
+	}
+	return values
+}
+
+// superTrendSeries SuperTrend line and direction for every bar after the ATR warmup
+func superTrendSeries(klines []Kline, period int, multiplier float64) (line []float64, up []bool) {
+	if len(klines) <= period {
+		return nil, nil
+	}
+
+	// Wilder ATR, first value at index period
+	trs := make([]float64, len(klines))
+	for i := 1; i < len(klines); i++ {
+		trs[i] = math.Max(klines[i].High-klines[i].Low,
+			math.Max(math.Abs(klines[i].High-klines[i-1].Close), math.Abs(klines[i].Low-klines[i-1].Close)))
+	}
+	atr := 0.0
+	for i := 1; i <= period; i++ {
+		atr += trs[i]
+	}
+	atr /= float64(period)
+
+	var finalUpper, finalLower float64
+	uptrend := true
+	for i := period; i < len(klines); i++ {
+		if i > period {
+			atr = (atr*float64(period-1) + trs[i]) / float64(period)
+		}
+		hl2 := (klines[i].High + klines[i].Low) / 2
+		basicUpper := hl2 + multiplier*atr
+		basicLower := hl2 - multiplier*atr
+
+		if i == period {
+			finalUpper, finalLower = basicUpper, basicLower
+			uptrend = klines[i].Close >= hl2
+		} else {
+			prevClose := klines[i-1].Close
+			if basicUpper < finalUpper || prevClose > finalUpper {
+				finalUpper = basicUpper
+			}
+			if basicLower > finalLower || prevClose < finalLower {
+				finalLower = basicLower
+			}
+			if uptrend && klines[i].Close < finalLower {
+				uptrend = false
+			} else if !uptrend && klines[i].Close > finalUpper {
+				uptrend = true
+			}
+		}
+
+		if uptrend {
+			line = append(line, finalLower)
+		} else {
+			line = append(line, finalUpper)
+		}
+		up = append(up, uptrend)
+	}
+	return line, up
+}
+
+// midpoint returns (highest high + lowest low) / 2 of the period bars ending at end (exclusive)
+func midpoint(klines []Kline, end, period int) float64 {
+	high, low := klines[end-period].High, klines[end-period].Low
+	for i := end - period + 1; i < end; i++ {
+		high = math.Max(high, klines[i].High)
+		low = math.Min(low, klines[i].Low)
+	}
+	return (high + low) / 2
+}
+
+// calculateIchimoku latest Ichimoku values, nil without enough history
+func calculateIchimoku(klines []Kline) *IchimokuData {
+	n := len(klines)
+	if n < IchimokuSenkouB+IchimokuKijun {
+		return nil
+	}
+	// Leading spans plotted at the current bar were computed Kijun bars ago
+	past := n - IchimokuKijun
+	pastTenkan := midpoint(klines, past, IchimokuTenkan)
+	pastKijun := midpoint(klines, past, IchimokuKijun)
+
+	data := &IchimokuData{
+		Tenkan:  midpoint(klines, n, IchimokuTenkan),
+		Kijun:   midpoint(klines, n, IchimokuKijun),
+		SenkouA: (pastTenkan + pastKijun) / 2,
+		SenkouB: midpoint(klines, past, IchimokuSenkouB),
+		FutureB: midpoint(klines, n, IchimokuSenkouB),
+	}
+	data.FutureA = (data.Tenkan + data.Kijun) / 2
+	return data
+}
+
+// obvSeries on-balance volume, starting at 0 on the first bar
+func obvSeries(klines []Kline) []float64 {
+	values := make([]float64, 0, len(klines))
+	obv := 0.0
+	for i, k := range klines {
+		if i > 0 {
+			switch {
+			case k.Close > klines[i-1].Close:
+				obv += k.Volume
+			case k.Close < klines[i-1].Close:
+				obv -= k.Volume
+			}
+		}
+		values = append(values, obv)
+	}
+	return values
+}
+
+// rsiSeries Wilder RSI for every bar from period on
+func rsiSeries(klines []Kline, period int) []float64 {
+	if len(klines) <= period {
+		return nil
+	}
+	rsi := func(avgGain, avgLoss float64) float64 {
+		if avgLoss == 0 {
+			return 100
+		}
+		return 100 - 100/(1+avgGain/avgLoss)
+	}
+
+	var avgGain, avgLoss float64
+	for i := 1; i <= period; i++ {
+		if change := klines[i].Close - klines[i-1].Close; change > 0 {
+			avgGain += change
+		} else {
+			avgLoss -= change
+		}
+	}
+	avgGain /= float64(period)
+	avgLoss /= float64(period)
+
+	values := []float64{rsi(avgGain, avgLoss)}
+	for i := period + 1; i < len(klines); i++ {
+		gain, loss := 0.0, 0.0
+		if change := klines[i].Close - klines[i-1].Close; change > 0 {
+			gain = change
+		} else {
+			loss = -change
+		}
+		avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+		avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+		values = append(values, rsi(avgGain, avgLoss))
+	}
+	return values
+}
+
+// smaSeries simple moving average for every value from period-1 on
+func smaSeries(values []float64, period int) []float64 {
+	if len(values) < period {
+		return nil
+	}
+	result := make([]float64, 0, len(values)-period+1)
+	sum := 0.0
+	for i, v := range values {
+		sum += v
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			result = append(result, sum/float64(period))
+		}
+	}
+	return result
+}
+
+// stochRSISeries stochastic RSI %K and %D (0-100)
+func stochRSISeries(klines []Kline, period, smoothK, smoothD int) (k, d []float64) {
+	rsi := rsiSeries(klines, period)
+	if len(rsi) < period {
+		return nil, nil
+	}
+	stoch := make([]float64, 0, len(rsi)-period+1)
+	for i := period - 1; i < len(rsi); i++ {
+		low, high := rsi[i], rsi[i]
+		for _, v := range rsi[i-period+1 : i] {
+			low = math.Min(low, v)
+			high = math.Max(high, v)
+		}
+		if high == low {
+			stoch = append(stoch, 50) // Flat RSI range: neutral
+		} else {
+			stoch = append(stoch, (rsi[i]-low)/(high-low)*100)
+		}
+	}
+	k = smaSeries(stoch, smoothK)
+	d = smaSeries(k, smoothD)
+	// Align %K with %D so both series end and start on the same bars
+	if len(k) > len(d) {
+		k = k[len(k)-len(d):]
+	}
+	return k, d
+}
@@ -0,0 +1,107 @@
+package market
+
+import (
+	"math"
+	"testing"
+)
+
+// trendKlines generates a steady uptrend followed by a steady downtrend
+func trendKlines(up, down int) []Kline {
+	klines := make([]Kline, 0, up+down)
+	price := 100.0
+	for i := 0; i < up+down; i++ {
+		step := 1.0
+		if i >= up {
+			step = -1.0
+		}
+		open := price
+		price += step
+		klines = append(klines, Kline{
+			OpenTime: int64(i) * 3600000, // 1h bars
+			Open:     open,
+			High:     math.Max(open, price) + 0.5,
+			Low:      math.Min(open, price) - 0.5,
+			Close:    price,
+			Volume:   100,
+		})
+	}
+	return klines
+}
+
+func TestComputeIndicators_OnlySelected(t *testing.T) {
+	ext := ComputeIndicators(generateTestKlines(100), IndicatorSelection{OBV: true}, 10)
+	if len(ext.OBV) != 10 {
+		t.Errorf("expected 10 OBV values, got %d", len(ext.OBV))
+	}
+	if ext.VWAP != nil || ext.SuperTrend != nil || ext.Ichimoku != nil || ext.StochRSIK != nil || ext.BOLL != nil {
+		t.Errorf("unselected indicators must not be computed: %+v", ext)
+	}
+
+	var series *TimeframeSeriesData
+	if series.Indicators(IndicatorSelection{OBV: true}) != nil {
+		t.Error("nil series should yield no indicators")
+	}
+}
+
+func TestSuperTrend_FlipsWithTrend(t *testing.T) {
+	ext := ComputeIndicators(trendKlines(60, 60), IndicatorSelection{SuperTrend: true}, 200)
+	n := len(ext.SuperTrendUp)
+	if n == 0 || len(ext.SuperTrend) != n {
+		t.Fatalf("expected aligned SuperTrend series, got %d lines / %d directions", len(ext.SuperTrend), n)
+	}
+	if !ext.SuperTrendUp[40] {
+		t.Error("expected uptrend during the rising leg")
+	}
+	if ext.SuperTrendUp[n-1] {
+		t.Error("expected downtrend at the end of the falling leg")
+	}
+}
+
+func TestOBVAndVWAP(t *testing.T) {
+	klines := trendKlines(5, 0)
+	ext := ComputeIndicators(klines, IndicatorSelection{OBV: true, VWAP: true}, 10)
+	if want := []float64{0, 100, 200, 300, 400}; len(ext.OBV) != len(want) || ext.OBV[4] != want[4] {
+		t.Errorf("expected OBV %v, got %v", want, ext.OBV)
+	}
+	// Equal volumes: VWAP is the mean typical price of the day so far
+	first := (klines[0].High + klines[0].Low + klines[0].Close) / 3
+	if math.Abs(ext.VWAP[0]-first) > 1e-9 {
+		t.Errorf("first VWAP should equal the typical price %.4f, got %.4f", first, ext.VWAP[0])
+	}
+	if ext.VWAP[4] <= ext.VWAP[0] {
+		t.Error("VWAP should rise in an uptrend")
+	}
+}
+
+func TestIchimokuAndStochRSI(t *testing.T) {
+	if ComputeIndicators(trendKlines(50, 0), IndicatorSelection{Ichimoku: true}, 10).Ichimoku != nil {
+		t.Error("Ichimoku needs 78 bars of history")
+	}
+
+	ext := ComputeIndicators(trendKlines(120, 0), IndicatorSelection{Ichimoku: true, StochRSI: true}, 10)
+	ich := ext.Ichimoku
+	if ich == nil {
+		t.Fatal("expected Ichimoku values")
+	}
+	if !(ich.Tenkan > ich.Kijun && ich.Kijun > ich.SenkouA && ich.SenkouA > ich.SenkouB) {
+		t.Errorf("expected bullish Ichimoku ordering in an uptrend, got %+v", ich)
+	}
+	if len(ext.StochRSIK) != 10 || len(ext.StochRSID) != 10 {
+		t.Fatalf("expected 10 StochRSI values, got %d/%d", len(ext.StochRSIK), len(ext.StochRSID))
+	}
+	for _, v := range ext.StochRSIK {
+		if v < 0 || v > 100 {
+			t.Errorf("StochRSI out of range: %v", v)
+		}
+	}
+}
+
+func TestComputeIndicators_BOLLPeriods(t *testing.T) {
+	ext := ComputeIndicators(generateTestKlines(100), IndicatorSelection{BOLLPeriods: []int{20, 50}}, 10)
+	if len(ext.BOLL) != 1 || ext.BOLL[0].Period != 50 {
+		t.Fatalf("expected only the non-default BOLL50 series, got %+v", ext.BOLL)
+	}
+	if b := ext.BOLL[0]; len(b.Middle) != 10 || b.Upper[9] < b.Middle[9] || b.Lower[9] > b.Middle[9] {
+		t.Errorf("unexpected BOLL50 bands: %+v", b)
+	}
+}
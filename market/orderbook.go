@@ -0,0 +1,179 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultOrderBookLevels price levels per side summarized when no level count is configured
+const DefaultOrderBookLevels = 20
+
+// binanceDepthLimits depth snapshot sizes accepted by the Binance futures API
+var binanceDepthLimits = []int{5, 10, 20, 50, 100, 500, 1000}
+
+// OrderBookLevel one price level of an order book side
+type OrderBookLevel struct {
+	Price    float64
+	Quantity float64
+}
+
+// OrderBook order book snapshot, bids descending and asks ascending by price
+type OrderBook struct {
+	Symbol string
+	Bids   []OrderBookLevel
+	Asks   []OrderBookLevel
+	Time   time.Time
+}
+
+// DepthMetrics liquidity summary of the top levels of an order book
+type DepthMetrics struct {
+	Symbol      string    `json:"symbol"`
+	Levels      int       `json:"levels"` // Levels per side summarized
+	BestBid     float64   `json:"best_bid"`
+	BestAsk     float64   `json:"best_ask"`
+	MidPrice    float64   `json:"mid_price"`
+	SpreadBps   float64   `json:"spread_bps"`    // (ask - bid) / mid in basis points
+	BidDepthUSD float64   `json:"bid_depth_usd"` // Notional resting on the bid side (what a sell consumes)
+	AskDepthUSD float64   `json:"ask_depth_usd"` // Notional resting on the ask side (what a buy consumes)
+	Imbalance   float64   `json:"imbalance"`     // (bid - ask) / (bid + ask) depth, -1..1, positive: bid heavy
+	Time        time.Time `json:"time"`
+}
+
+// SideDepthUSD returns the visible notional an order on the given side would consume
+// Opening a long (buy) takes asks, opening a short (sell) takes bids.
+func (m *DepthMetrics) SideDepthUSD(side string) float64 {
+	if side == "short" {
+		return m.BidDepthUSD
+	}
+	return m.AskDepthUSD
+}
+
+// ComputeDepthMetrics summarizes the top levels of each side of an order book
+func ComputeDepthMetrics(book *OrderBook, levels int) (*DepthMetrics, error) {
+	if book == nil || len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return nil, fmt.Errorf("order book is empty")
+	}
+	if levels <= 0 {
+		levels = DefaultOrderBookLevels
+	}
+
+	metrics := &DepthMetrics{
+		Symbol:  book.Symbol,
+		Levels:  levels,
+		BestBid: book.Bids[0].Price,
+		BestAsk: book.Asks[0].Price,
+		Time:    book.Time,
+	}
+	metrics.MidPrice = (metrics.BestBid + metrics.BestAsk) / 2
+	if metrics.MidPrice > 0 {
+		metrics.SpreadBps = (metrics.BestAsk - metrics.BestBid) / metrics.MidPrice * 10000
+	}
+	metrics.BidDepthUSD = sideNotional(book.Bids, levels)
+	metrics.AskDepthUSD = sideNotional(book.Asks, levels)
+	if total := metrics.BidDepthUSD + metrics.AskDepthUSD; total > 0 {
+		metrics.Imbalance = (metrics.BidDepthUSD - metrics.AskDepthUSD) / total
+	}
+	return metrics, nil
+}
+
+// sideNotional sums price × quantity of the top levels of one side
+func sideNotional(side []OrderBookLevel, levels int) float64 {
+	total := 0.0
+	for i := 0; i < len(side) && i < levels; i++ {
+		total += side[i].Price * side[i].Quantity
+	}
+	return total
+}
+
+// GetDepthMetrics fetches an order book snapshot (Binance futures) and summarizes its top levels
+func GetDepthMetrics(symbol string, levels int) (*DepthMetrics, error) {
+	symbol = Normalize(symbol)
+	if IsXyzDexAsset(symbol) {
+		return nil, fmt.Errorf("order book not available for %s", symbol)
+	}
+	if levels <= 0 {
+		levels = DefaultOrderBookLevels
+	}
+
+	book, err := NewAPIClient().GetOrderBook(symbol, levels)
+	if err != nil {
+		return nil, err
+	}
+	return ComputeDepthMetrics(book, levels)
+}
+
+// GetOrderBook gets an order book snapshot with at least levels price levels per side
+func (c *APIClient) GetOrderBook(symbol string, levels int) (*OrderBook, error) {
+	limit := binanceDepthLimits[len(binanceDepthLimits)-1]
+	for _, l := range binanceDepthLimits {
+		if l >= levels {
+			limit = l
+			break
+		}
+	}
+
+	url := fmt.Sprintf("%s/fapi/v1/depth", baseURL)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Add("symbol", symbol)
+	q.Add("limit", strconv.Itoa(limit))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("order book request failed (status %d): %s", resp.StatusCode, string(body))
+	}
+	return parseOrderBook(symbol, body)
+}
+
+// parseOrderBook parses a Binance depth response
+func parseOrderBook(symbol string, body []byte) (*OrderBook, error) {
+	var result struct {
+		Time int64       `json:"T"`
+		Bids [][2]string `json:"bids"`
+		Asks [][2]string `json:"asks"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse order book: %w", err)
+	}
+
+	parseSide := func(raw [][2]string) []OrderBookLevel {
+		levels := make([]OrderBookLevel, 0, len(raw))
+		for _, r := range raw {
+			price, err1 := strconv.ParseFloat(r[0], 64)
+			qty, err2 := strconv.ParseFloat(r[1], 64)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			levels = append(levels, OrderBookLevel{Price: price, Quantity: qty})
+		}
+		return levels
+	}
+
+	book := &OrderBook{
+		Symbol: symbol,
+		Bids:   parseSide(result.Bids),
+		Asks:   parseSide(result.Asks),
+		Time:   time.UnixMilli(result.Time),
+	}
+	if result.Time == 0 {
+		book.Time = time.Now()
+	}
+	return book, nil
+}
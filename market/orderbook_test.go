@@ -0,0 +1,43 @@
+package market
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseOrderBookAndDepthMetrics(t *testing.T) {
+	body := []byte(`{"lastUpdateId":1,"E":1700000000000,"T":1700000000000,
+		"bids":[["99.9","10"],["99.8","20"],["99.7","30"]],
+		"asks":[["100.1","5"],["100.2","5"],["100.3","100"]]}`)
+
+	book, err := parseOrderBook("TESTUSDT", body)
+	if err != nil {
+		t.Fatalf("parseOrderBook: %v", err)
+	}
+	if len(book.Bids) != 3 || len(book.Asks) != 3 || book.Time.IsZero() {
+		t.Fatalf("unexpected book: %+v", book)
+	}
+
+	m, err := ComputeDepthMetrics(book, 2)
+	if err != nil {
+		t.Fatalf("ComputeDepthMetrics: %v", err)
+	}
+	if m.BestBid != 99.9 || m.BestAsk != 100.1 || m.MidPrice != 100 {
+		t.Errorf("unexpected top of book: %+v", m)
+	}
+	if math.Abs(m.SpreadBps-20) > 1e-6 {
+		t.Errorf("expected 20 bps spread, got %.4f", m.SpreadBps)
+	}
+	wantBid := 99.9*10 + 99.8*20
+	wantAsk := 100.1*5 + 100.2*5
+	if math.Abs(m.BidDepthUSD-wantBid) > 1e-6 || math.Abs(m.AskDepthUSD-wantAsk) > 1e-6 {
+		t.Errorf("only the top 2 levels should count, got bid %.2f ask %.2f", m.BidDepthUSD, m.AskDepthUSD)
+	}
+	if m.Imbalance <= 0 || m.SideDepthUSD("long") != m.AskDepthUSD || m.SideDepthUSD("short") != m.BidDepthUSD {
+		t.Errorf("expected bid heavy book with long consuming asks, got %+v", m)
+	}
+
+	if _, err := ComputeDepthMetrics(&OrderBook{Bids: book.Bids}, 2); err == nil {
+		t.Error("one-sided book should be rejected")
+	}
+}
@@ -0,0 +1,266 @@
+package market
+
+import (
+	"fmt"
+	"nofx/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Market data provider names
+const (
+	ProviderCoinAnk = "coinank"
+	ProviderBinance = "binance"
+	ProviderBybit   = "bybit"
+)
+
+const (
+	// providerBaseCooldown time a provider is skipped after its first failure, doubled on each further failure
+	providerBaseCooldown = 30 * time.Second
+	// providerMaxCooldown longest time a failing provider is skipped
+	providerMaxCooldown = 5 * time.Minute
+	// healthCheckSymbol symbol failing providers are probed with
+	healthCheckSymbol = "BTCUSDT"
+)
+
+// MarketDataProvider a source of crypto perpetual klines
+type MarketDataProvider interface {
+	Name() string
+	// GetKlines returns the last limit klines of a symbol, oldest first. exchange selects the venue of
+	// providers that aggregate several (others ignore it).
+	GetKlines(symbol, interval, exchange string, limit int) ([]Kline, error)
+}
+
+// ProviderStatus health of a provider in the failover chain
+type ProviderStatus struct {
+	Name        string    `json:"name"`
+	Healthy     bool      `json:"healthy"`
+	Failures    int       `json:"failures"` // Consecutive
+	LastError   string    `json:"last_error,omitempty"`
+	LastSuccess time.Time `json:"last_success,omitempty"`
+	DownUntil   time.Time `json:"down_until,omitempty"` // Skipped until then unless no other provider works
+}
+
+// providerHealth failure tracking of one provider
+type providerHealth struct {
+	failures    int
+	lastError   string
+	lastSuccess time.Time
+	downUntil   time.Time
+}
+
+// ProviderChain fetches klines from the first healthy provider, failing over to the next one
+// A provider that fails is skipped for an exponentially growing cooldown; when every provider is
+// cooling down they are all tried anyway, so an outage never blinds traders more than necessary.
+type ProviderChain struct {
+	mu        sync.Mutex
+	providers []MarketDataProvider
+	health    map[string]*providerHealth
+	now       func() time.Time
+}
+
+// NewProviderChain creates a chain trying providers in the given order
+func NewProviderChain(providers ...MarketDataProvider) *ProviderChain {
+	c := &ProviderChain{health: make(map[string]*providerHealth), now: time.Now}
+	for _, p := range providers {
+		c.providers = append(c.providers, p)
+		c.health[p.Name()] = &providerHealth{}
+	}
+	return c
+}
+
+// Names the providers of the chain in their default order
+func (c *ProviderChain) Names() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	names := make([]string, len(c.providers))
+	for i, p := range c.providers {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+// SetOrder changes the default order of the providers, unlisted ones are tried last
+func (c *ProviderChain) SetOrder(names []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, name := range names {
+		if _, ok := c.health[name]; !ok {
+			return fmt.Errorf("unknown market data provider %q", name)
+		}
+	}
+	c.providers = c.ordered(names)
+	return nil
+}
+
+// ordered providers with the preferred ones first, in their order (caller holds mu)
+func (c *ProviderChain) ordered(preferred []string) []MarketDataProvider {
+	ordered := make([]MarketDataProvider, 0, len(c.providers))
+	used := make(map[string]bool, len(c.providers))
+	for _, name := range preferred {
+		for _, p := range c.providers {
+			if p.Name() == name && !used[name] {
+				ordered = append(ordered, p)
+				used[name] = true
+			}
+		}
+	}
+	for _, p := range c.providers {
+		if !used[p.Name()] {
+			ordered = append(ordered, p)
+		}
+	}
+	return ordered
+}
+
+// GetKlines fetches klines from the providers in preferred order, then the default order,
+// skipping providers cooling down after failures
+func (c *ProviderChain) GetKlines(symbol, interval, exchange string, limit int, preferred []string) ([]Kline, error) {
+	c.mu.Lock()
+	var healthy, down []MarketDataProvider
+	now := c.now()
+	for _, p := range c.ordered(preferred) {
+		if now.Before(c.health[p.Name()].downUntil) {
+			down = append(down, p)
+		} else {
+			healthy = append(healthy, p)
+		}
+	}
+	c.mu.Unlock()
+
+	var errs []string
+	for _, p := range append(healthy, down...) {
+		klines, err := p.GetKlines(symbol, interval, exchange, limit)
+		if err == nil && len(klines) == 0 {
+			err = fmt.Errorf("no klines returned")
+		}
+		if err != nil {
+			c.recordFailure(p.Name(), err)
+			errs = append(errs, fmt.Sprintf("%s: %v", p.Name(), err))
+			continue
+		}
+		c.recordSuccess(p.Name())
+		if len(errs) > 0 {
+			logger.Infof("📡 %s %s klines served by %s after failover (%s)", symbol, interval, p.Name(), strings.Join(errs, "; "))
+		}
+		return klines, nil
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no market data provider configured")
+	}
+	return nil, fmt.Errorf("all market data providers failed: %s", strings.Join(errs, "; "))
+}
+
+func (c *ProviderChain) recordFailure(name string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h := c.health[name]
+	wasHealthy := h.failures == 0
+	h.failures++
+	h.lastError = err.Error()
+	cooldown := providerBaseCooldown << min(h.failures-1, 10)
+	h.downUntil = c.now().Add(min(cooldown, providerMaxCooldown))
+	if wasHealthy {
+		logger.Warnf("⚠️ Market data provider %s failed, skipped for %v: %v", name, providerBaseCooldown, err)
+	}
+}
+
+func (c *ProviderChain) recordSuccess(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h := c.health[name]
+	if h.failures > 0 {
+		logger.Infof("✅ Market data provider %s recovered after %d failures", name, h.failures)
+	}
+	h.failures = 0
+	h.lastError = ""
+	h.lastSuccess = c.now()
+	h.downUntil = time.Time{}
+}
+
+// Status health of each provider, in default order
+func (c *ProviderChain) Status() []ProviderStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	status := make([]ProviderStatus, 0, len(c.providers))
+	for _, p := range c.providers {
+		h := c.health[p.Name()]
+		status = append(status, ProviderStatus{
+			Name:        p.Name(),
+			Healthy:     !now.Before(h.downUntil),
+			Failures:    h.failures,
+			LastError:   h.lastError,
+			LastSuccess: h.lastSuccess,
+			DownUntil:   h.downUntil,
+		})
+	}
+	return status
+}
+
+// CheckHealth probes the providers that failed, so they return to the chain once they recover
+// even when traders are served by another provider
+func (c *ProviderChain) CheckHealth() {
+	c.mu.Lock()
+	var failing []MarketDataProvider
+	for _, p := range c.providers {
+		if c.health[p.Name()].failures > 0 {
+			failing = append(failing, p)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, p := range failing {
+		klines, err := p.GetKlines(healthCheckSymbol, "1m", ProviderBinance, 2)
+		if err == nil && len(klines) == 0 {
+			err = fmt.Errorf("no klines returned")
+		}
+		if err != nil {
+			c.recordFailure(p.Name(), err)
+		} else {
+			c.recordSuccess(p.Name())
+		}
+	}
+}
+
+// providers chain all crypto klines are fetched through
+var providers = NewProviderChain(coinAnkProvider{}, binanceProvider{}, bybitProvider{})
+
+// Providers the market data provider chain
+func Providers() *ProviderChain {
+	return providers
+}
+
+// IsProvider reports whether name is a known market data provider
+func IsProvider(name string) bool {
+	for _, n := range providers.Names() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// getKlines fetches crypto klines through the provider chain
+func getKlines(symbol, interval, exchange string, limit int, preferred []string) ([]Kline, error) {
+	return providers.GetKlines(symbol, interval, exchange, limit, preferred)
+}
+
+// coinAnkProvider klines from CoinAnk's free API, per exchange
+type coinAnkProvider struct{}
+
+func (coinAnkProvider) Name() string { return ProviderCoinAnk }
+
+func (coinAnkProvider) GetKlines(symbol, interval, exchange string, limit int) ([]Kline, error) {
+	return getKlinesFromCoinAnk(symbol, interval, exchange, limit)
+}
+
+// binanceProvider klines from Binance's public futures API
+type binanceProvider struct{}
+
+func (binanceProvider) Name() string { return ProviderBinance }
+
+func (binanceProvider) GetKlines(symbol, interval, _ string, limit int) ([]Kline, error) {
+	return NewAPIClient().GetKlines(symbol, interval, min(limit, 1500))
+}
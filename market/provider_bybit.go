@@ -0,0 +1,101 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const bybitBaseURL = "https://api.bybit.com"
+
+// bybitIntervals Bybit v5 kline intervals of the supported timeframes (no 8h or 3d)
+var bybitIntervals = map[string]string{
+	"1m": "1", "3m": "3", "5m": "5", "15m": "15", "30m": "30",
+	"1h": "60", "2h": "120", "4h": "240", "6h": "360", "12h": "720",
+	"1d": "D", "1w": "W",
+}
+
+// bybitProvider klines from Bybit's public v5 API (USDT perpetuals)
+type bybitProvider struct{}
+
+func (bybitProvider) Name() string { return ProviderBybit }
+
+func (bybitProvider) GetKlines(symbol, interval, _ string, limit int) ([]Kline, error) {
+	bybitInterval, ok := bybitIntervals[interval]
+	if !ok {
+		return nil, fmt.Errorf("interval %s not available on Bybit", interval)
+	}
+	duration := 7 * 24 * time.Hour // 1w
+	if interval != "1w" {
+		var err error
+		if duration, err = TFDuration(interval); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, bybitBaseURL+"/v5/market/kline", nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Add("category", "linear")
+	q.Add("symbol", symbol)
+	q.Add("interval", bybitInterval)
+	q.Add("limit", strconv.Itoa(min(limit, 1000)))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := NewAPIClient().client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Bybit API HTTP %d", resp.StatusCode)
+	}
+	return parseBybitKlines(body, duration.Milliseconds())
+}
+
+// parseBybitKlines converts a v5 kline response (newest first, string fields) to klines oldest first
+func parseBybitKlines(body []byte, intervalMs int64) ([]Kline, error) {
+	var result struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			List [][]string `json:"list"` // [startTime, open, high, low, close, volume, turnover]
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("invalid Bybit kline response: %w", err)
+	}
+	if result.RetCode != 0 {
+		return nil, fmt.Errorf("Bybit API error %d: %s", result.RetCode, result.RetMsg)
+	}
+
+	klines := make([]Kline, 0, len(result.Result.List))
+	for i := len(result.Result.List) - 1; i >= 0; i-- {
+		row := result.Result.List[i]
+		if len(row) < 7 {
+			continue
+		}
+		openTime, err := strconv.ParseInt(row[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		k := Kline{OpenTime: openTime, CloseTime: openTime + intervalMs - 1}
+		k.Open, _ = strconv.ParseFloat(row[1], 64)
+		k.High, _ = strconv.ParseFloat(row[2], 64)
+		k.Low, _ = strconv.ParseFloat(row[3], 64)
+		k.Close, _ = strconv.ParseFloat(row[4], 64)
+		k.Volume, _ = strconv.ParseFloat(row[5], 64)
+		k.QuoteVolume, _ = strconv.ParseFloat(row[6], 64)
+		klines = append(klines, k)
+	}
+	return klines, nil
+}
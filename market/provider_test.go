@@ -0,0 +1,126 @@
+package market
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeProvider serves one kline, or fails while down
+type fakeProvider struct {
+	name  string
+	down  bool
+	calls int
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) GetKlines(_, _, _ string, _ int) ([]Kline, error) {
+	p.calls++
+	if p.down {
+		return nil, errors.New("outage")
+	}
+	return []Kline{{Close: 1}}, nil
+}
+
+func newTestChain(providers ...*fakeProvider) (*ProviderChain, *time.Time) {
+	list := make([]MarketDataProvider, len(providers))
+	for i, p := range providers {
+		list[i] = p
+	}
+	c := NewProviderChain(list...)
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestProviderChain_FailoverAndCooldown(t *testing.T) {
+	a, b := &fakeProvider{name: "a", down: true}, &fakeProvider{name: "b"}
+	c, now := newTestChain(a, b)
+
+	if _, err := c.GetKlines("BTCUSDT", "1h", "binance", 10, nil); err != nil {
+		t.Fatalf("failover: %v", err)
+	}
+	if a.calls != 1 || b.calls != 1 {
+		t.Fatalf("calls a=%d b=%d, want 1 each", a.calls, b.calls)
+	}
+
+	// a cools down and is skipped
+	c.GetKlines("BTCUSDT", "1h", "binance", 10, nil)
+	if a.calls != 1 {
+		t.Errorf("failing provider should be skipped during its cooldown")
+	}
+	if status := c.Status(); status[0].Healthy || status[0].Failures != 1 || status[0].LastError != "outage" {
+		t.Errorf("status = %+v", status[0])
+	}
+
+	// After the cooldown it is tried again, and a second failure doubles it
+	*now = now.Add(providerBaseCooldown)
+	c.GetKlines("BTCUSDT", "1h", "binance", 10, nil)
+	if a.calls != 2 {
+		t.Fatalf("provider should be retried after its cooldown")
+	}
+	if got := c.Status()[0].DownUntil.Sub(*now); got != 2*providerBaseCooldown {
+		t.Errorf("second cooldown = %v, want %v", got, 2*providerBaseCooldown)
+	}
+
+	// Health checks bring it back once it recovers
+	a.down = false
+	c.CheckHealth()
+	if status := c.Status()[0]; !status.Healthy || status.Failures != 0 {
+		t.Errorf("recovered provider status = %+v", status)
+	}
+}
+
+func TestProviderChain_AllDownStillTried(t *testing.T) {
+	a, b := &fakeProvider{name: "a", down: true}, &fakeProvider{name: "b", down: true}
+	c, _ := newTestChain(a, b)
+
+	if _, err := c.GetKlines("BTCUSDT", "1h", "", 10, nil); err == nil {
+		t.Fatal("expected an error when every provider fails")
+	}
+	b.down = false
+	if _, err := c.GetKlines("BTCUSDT", "1h", "", 10, nil); err != nil {
+		t.Errorf("providers cooling down should still be tried when none is healthy: %v", err)
+	}
+}
+
+func TestProviderChain_PreferenceAndOrder(t *testing.T) {
+	a, b, x := &fakeProvider{name: "a"}, &fakeProvider{name: "b"}, &fakeProvider{name: "x"}
+	c, _ := newTestChain(a, b, x)
+
+	c.GetKlines("BTCUSDT", "1h", "", 10, []string{"x", "unknown"})
+	if x.calls != 1 || a.calls != 0 {
+		t.Errorf("preferred provider should be tried first, calls a=%d x=%d", a.calls, x.calls)
+	}
+
+	if err := c.SetOrder([]string{"b"}); err != nil {
+		t.Fatal(err)
+	}
+	if names := c.Names(); names[0] != "b" || names[1] != "a" || names[2] != "x" {
+		t.Errorf("order = %v, want [b a x]", names)
+	}
+	if err := c.SetOrder([]string{"nope"}); err == nil {
+		t.Error("unknown provider accepted")
+	}
+}
+
+func TestParseBybitKlines(t *testing.T) {
+	body := []byte(`{"retCode":0,"retMsg":"OK","result":{"list":[
+		["1700003600000","101","103","100","102","20","2040"],
+		["1700000000000","100","102","99","101","10","1010"]]}}`)
+	klines, err := parseBybitKlines(body, time.Hour.Milliseconds())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(klines) != 2 || klines[0].OpenTime != 1700000000000 || klines[1].Close != 102 {
+		t.Fatalf("klines should be oldest first: %+v", klines)
+	}
+	if klines[0].CloseTime != 1700003599999 || klines[0].QuoteVolume != 1010 {
+		t.Errorf("kline = %+v", klines[0])
+	}
+
+	if _, err := parseBybitKlines([]byte(`{"retCode":10001,"retMsg":"params error"}`), 0); err == nil {
+		t.Error("API error not reported")
+	}
+}
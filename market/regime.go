@@ -0,0 +1,273 @@
+package market
+
+import (
+	"fmt"
+	"nofx/logger"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Market Regime Detection
+// ============================================================================
+// Classifies each symbol from 1h box (Donchian), Bollinger width and ATR data.
+// Shared by the grid module (regime level, box breakouts) and the directional
+// engine (trending / volatile / ranging exposure limits).
+// ============================================================================
+
+// MarketRegime coarse market state used to scale directional exposure
+type MarketRegime string
+
+const (
+	RegimeTrending MarketRegime = "trending" // Price broke out of the mid or long box
+	RegimeVolatile MarketRegime = "volatile" // Wide Bollinger bands / high ATR without a trend
+	RegimeRanging  MarketRegime = "ranging"  // Price oscillating inside its boxes
+)
+
+// RegimeInfo regime classification of a symbol
+type RegimeInfo struct {
+	Symbol            string        `json:"symbol"`
+	Regime            MarketRegime  `json:"regime"`
+	Level             RegimeLevel   `json:"level"`
+	BollingerWidthPct float64       `json:"bollinger_width_pct"` // (upper - lower) / middle × 100 (20, 2)
+	ATRPct            float64       `json:"atr_pct"`             // ATR14 / price × 100
+	Breakout          BreakoutLevel `json:"breakout"`
+	BreakoutDirection string        `json:"breakout_direction,omitempty"` // "up" or "down"
+	Box               *BoxData      `json:"box"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+}
+
+// ClassifyRegimeLevel determines the regime level based on market indicators
+// bollingerWidth: Bollinger band width as percentage
+// atr14Pct: ATR14 as percentage of current price
+func ClassifyRegimeLevel(bollingerWidth, atr14Pct float64) RegimeLevel {
+	// Narrow: Bollinger < 2%, ATR < 1%
+	if bollingerWidth < 2.0 && atr14Pct < 1.0 {
+		return RegimeLevelNarrow
+	}
+
+	// Standard: Bollinger 2-3%, ATR 1-2%
+	if bollingerWidth <= 3.0 && atr14Pct <= 2.0 {
+		return RegimeLevelStandard
+	}
+
+	// Wide: Bollinger 3-4%, ATR 2-3%
+	if bollingerWidth <= 4.0 && atr14Pct <= 3.0 {
+		return RegimeLevelWide
+	}
+
+	// Volatile: Bollinger > 4%, ATR > 3%
+	return RegimeLevelVolatile
+}
+
+// DetectBoxBreakout checks if price has broken out of any box level
+// Returns the highest breakout level and direction
+func DetectBoxBreakout(box *BoxData) (BreakoutLevel, string) {
+	if box == nil {
+		return BreakoutNone, ""
+	}
+
+	price := box.CurrentPrice
+
+	// Check long box first (highest priority)
+	if price > box.LongUpper {
+		return BreakoutLong, "up"
+	}
+	if price < box.LongLower {
+		return BreakoutLong, "down"
+	}
+
+	// Check mid box
+	if price > box.MidUpper {
+		return BreakoutMid, "up"
+	}
+	if price < box.MidLower {
+		return BreakoutMid, "down"
+	}
+
+	// Check short box
+	if price > box.ShortUpper {
+		return BreakoutShort, "up"
+	}
+	if price < box.ShortLower {
+		return BreakoutShort, "down"
+	}
+
+	return BreakoutNone, ""
+}
+
+// ClassifyMarketRegime maps a regime level and box breakout to a market regime
+// A short box breakout is still treated as range noise; mid/long breakouts are trends
+func ClassifyMarketRegime(level RegimeLevel, breakout BreakoutLevel) MarketRegime {
+	if breakout == BreakoutMid || breakout == BreakoutLong || level == RegimeLevelTrending {
+		return RegimeTrending
+	}
+	if level == RegimeLevelVolatile {
+		return RegimeVolatile
+	}
+	return RegimeRanging
+}
+
+// calculateRegime classifies a symbol from its 1h klines (oldest first)
+func calculateRegime(symbol string, klines []Kline) *RegimeInfo {
+	currentPrice := klines[len(klines)-1].Close
+	info := &RegimeInfo{Symbol: symbol, UpdatedAt: time.Now()}
+
+	// Boxes exclude the current candle, otherwise its close can never be outside them
+	if len(klines) > 1 {
+		info.Box = calculateBoxData(klines[:len(klines)-1], currentPrice)
+	} else {
+		info.Box = calculateBoxData(klines, currentPrice)
+	}
+
+	if upper, middle, lower := calculateBOLL(klines, 20, 2); middle > 0 {
+		info.BollingerWidthPct = (upper - lower) / middle * 100
+	}
+	if currentPrice > 0 {
+		info.ATRPct = calculateATR(klines, 14) / currentPrice * 100
+	}
+
+	info.Level = ClassifyRegimeLevel(info.BollingerWidthPct, info.ATRPct)
+	info.Breakout, info.BreakoutDirection = DetectBoxBreakout(info.Box)
+	info.Regime = ClassifyMarketRegime(info.Level, info.Breakout)
+	return info
+}
+
+// DetectRegime fetches 1h klines of a symbol and classifies its market regime
+func DetectRegime(symbol string) (*RegimeInfo, error) {
+	symbol = Normalize(symbol)
+
+	var klines []Kline
+	var err error
+	if IsXyzDexAsset(symbol) {
+		klines, err = getKlinesFromHyperliquid(symbol, "1h", LongBoxPeriod)
+	} else {
+		klines, err = getKlines(symbol, "1h", "binance", LongBoxPeriod, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get 1h klines: %w", err)
+	}
+	if len(klines) == 0 {
+		return nil, fmt.Errorf("no kline data available")
+	}
+
+	return calculateRegime(symbol, klines), nil
+}
+
+// ============================================================================
+// Regime Service
+// ============================================================================
+
+// RegimeService classifies requested symbols and refreshes them on a schedule
+type RegimeService struct {
+	interval time.Duration
+	detect   func(symbol string) (*RegimeInfo, error)
+	cache    map[string]*RegimeInfo // key: normalized symbol
+	tracked  map[string]time.Time   // key: normalized symbol, value: last requested
+	mu       sync.Mutex
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// regimeUntrackAfter symbols not requested for this many intervals are no longer refreshed
+const regimeUntrackAfter = 4
+
+// NewRegimeService creates a regime service refreshing tracked symbols every interval
+func NewRegimeService(interval time.Duration) *RegimeService {
+	return &RegimeService{
+		interval: interval,
+		detect:   DetectRegime,
+		cache:    make(map[string]*RegimeInfo),
+		tracked:  make(map[string]time.Time),
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start starts the scheduled refresh in the background
+func (s *RegimeService) Start() {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.refresh()
+			case <-s.stopCh:
+				return
+			}
+		}
+	}()
+	logger.Infof("🧭 Market regime service started (refresh every %v)", s.interval)
+}
+
+// Stop stops the scheduled refresh
+func (s *RegimeService) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// Get returns the regime of a symbol and keeps it tracked for scheduled refresh
+// A symbol without a fresh cached result is classified immediately
+func (s *RegimeService) Get(symbol string) (*RegimeInfo, error) {
+	symbol = Normalize(symbol)
+
+	s.mu.Lock()
+	s.tracked[symbol] = time.Now()
+	info, ok := s.cache[symbol]
+	s.mu.Unlock()
+
+	if ok && time.Since(info.UpdatedAt) < 2*s.interval {
+		return info, nil
+	}
+
+	info, err := s.detect(symbol)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.cache[symbol] = info
+	s.mu.Unlock()
+	return info, nil
+}
+
+// GetAll returns the regimes of the given symbols, skipping symbols that cannot be classified
+func (s *RegimeService) GetAll(symbols []string) map[string]*RegimeInfo {
+	regimes := make(map[string]*RegimeInfo, len(symbols))
+	for _, symbol := range symbols {
+		info, err := s.Get(symbol)
+		if err != nil {
+			logger.Infof("⚠️ Failed to detect market regime of %s: %v", symbol, err)
+			continue
+		}
+		regimes[info.Symbol] = info
+	}
+	return regimes
+}
+
+// refresh re-classifies tracked symbols and drops those no longer requested
+func (s *RegimeService) refresh() {
+	s.mu.Lock()
+	symbols := make([]string, 0, len(s.tracked))
+	for symbol, lastRequested := range s.tracked {
+		if time.Since(lastRequested) > regimeUntrackAfter*s.interval {
+			delete(s.tracked, symbol)
+			delete(s.cache, symbol)
+			continue
+		}
+		symbols = append(symbols, symbol)
+	}
+	s.mu.Unlock()
+
+	for _, symbol := range symbols {
+		info, err := s.detect(symbol)
+		if err != nil {
+			logger.Infof("⚠️ Failed to refresh market regime of %s: %v", symbol, err)
+			continue
+		}
+		s.mu.Lock()
+		if prev, ok := s.cache[symbol]; ok && prev.Regime != info.Regime {
+			logger.Infof("🧭 %s market regime changed: %s → %s", symbol, prev.Regime, info.Regime)
+		}
+		s.cache[symbol] = info
+		s.mu.Unlock()
+	}
+}
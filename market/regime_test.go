@@ -0,0 +1,135 @@
+package market
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestClassifyRegimeLevel(t *testing.T) {
+	tests := []struct {
+		name           string
+		bollingerWidth float64
+		atr14Pct       float64
+		expected       RegimeLevel
+	}{
+		{"narrow", 1.5, 0.8, RegimeLevelNarrow},
+		{"standard", 2.5, 1.5, RegimeLevelStandard},
+		{"wide", 3.5, 2.5, RegimeLevelWide},
+		{"volatile", 5.0, 4.0, RegimeLevelVolatile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ClassifyRegimeLevel(tt.bollingerWidth, tt.atr14Pct)
+			if result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestDetectBoxBreakout(t *testing.T) {
+	box := &BoxData{
+		ShortUpper:   100,
+		ShortLower:   90,
+		MidUpper:     105,
+		MidLower:     85,
+		LongUpper:    110,
+		LongLower:    80,
+		CurrentPrice: 95,
+	}
+
+	// No breakout
+	level, direction := DetectBoxBreakout(box)
+	if level != BreakoutNone {
+		t.Errorf("Expected no breakout, got %v", level)
+	}
+
+	// Short breakout up
+	box.CurrentPrice = 101
+	level, direction = DetectBoxBreakout(box)
+	if level != BreakoutShort || direction != "up" {
+		t.Errorf("Expected short breakout up, got %v %v", level, direction)
+	}
+
+	// Mid breakout down
+	box.CurrentPrice = 84
+	level, direction = DetectBoxBreakout(box)
+	if level != BreakoutMid || direction != "down" {
+		t.Errorf("Expected mid breakout down, got %v %v", level, direction)
+	}
+
+	// Long breakout up
+	box.CurrentPrice = 112
+	level, direction = DetectBoxBreakout(box)
+	if level != BreakoutLong || direction != "up" {
+		t.Errorf("Expected long breakout up, got %v %v", level, direction)
+	}
+}
+
+func TestClassifyMarketRegime(t *testing.T) {
+	tests := []struct {
+		level    RegimeLevel
+		breakout BreakoutLevel
+		expected MarketRegime
+	}{
+		{RegimeLevelNarrow, BreakoutNone, RegimeRanging},
+		{RegimeLevelWide, BreakoutShort, RegimeRanging},
+		{RegimeLevelVolatile, BreakoutNone, RegimeVolatile},
+		{RegimeLevelVolatile, BreakoutMid, RegimeTrending},
+		{RegimeLevelStandard, BreakoutLong, RegimeTrending},
+	}
+
+	for _, tt := range tests {
+		if result := ClassifyMarketRegime(tt.level, tt.breakout); result != tt.expected {
+			t.Errorf("%s/%s: expected %v, got %v", tt.level, tt.breakout, tt.expected, result)
+		}
+	}
+}
+
+func TestCalculateRegime_Breakout(t *testing.T) {
+	klines := generateTestKlines(300)
+	last := klines[len(klines)-1]
+	klines = append(klines, Kline{Open: last.Close, High: 130, Low: last.Close, Close: 130})
+
+	info := calculateRegime("BTCUSDT", klines)
+	if info.Breakout != BreakoutLong || info.BreakoutDirection != "up" || info.Regime != RegimeTrending {
+		t.Errorf("expected trending long breakout up, got %+v", info)
+	}
+	if info.BollingerWidthPct <= 0 || info.ATRPct <= 0 {
+		t.Errorf("expected Bollinger width and ATR to be set, got %.2f / %.2f", info.BollingerWidthPct, info.ATRPct)
+	}
+}
+
+func TestRegimeService_CachesAndRefreshes(t *testing.T) {
+	calls := map[string]int{}
+	service := NewRegimeService(time.Minute)
+	service.detect = func(symbol string) (*RegimeInfo, error) {
+		calls[symbol]++
+		if symbol == "FAILUSDT" {
+			return nil, fmt.Errorf("no data")
+		}
+		return &RegimeInfo{Symbol: symbol, Regime: RegimeRanging, UpdatedAt: time.Now()}, nil
+	}
+
+	regimes := service.GetAll([]string{"BTCUSDT", "FAILUSDT"})
+	if len(regimes) != 1 || regimes["BTCUSDT"] == nil {
+		t.Fatalf("expected only BTCUSDT to be classified, got %v", regimes)
+	}
+	if _, err := service.Get("BTCUSDT"); err != nil || calls["BTCUSDT"] != 1 {
+		t.Errorf("cached regime should be reused, detect called %d times (err %v)", calls["BTCUSDT"], err)
+	}
+
+	service.refresh()
+	if calls["BTCUSDT"] != 2 {
+		t.Errorf("tracked symbol should be refreshed, detect called %d times", calls["BTCUSDT"])
+	}
+
+	// Symbols no longer requested are dropped
+	service.tracked["BTCUSDT"] = time.Now().Add(-regimeUntrackAfter * time.Minute * 2)
+	service.refresh()
+	if _, ok := service.cache["BTCUSDT"]; ok || calls["BTCUSDT"] != 2 {
+		t.Errorf("stale symbol should be untracked, detect called %d times", calls["BTCUSDT"])
+	}
+}
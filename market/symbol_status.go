@@ -0,0 +1,238 @@
+package market
+
+import (
+	"fmt"
+	"nofx/logger"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Symbol Status: Delistings and Exchange Maintenance
+// ============================================================================
+// Contract status and scheduled delivery dates are read from the futures
+// exchangeInfo endpoint. Symbols that are not trading (settling, closed, not
+// yet listed) or have a delisting scheduled are restricted: traders drop them
+// from candidate pools and close or flag open positions in them. Maintenance
+// windows are announced by exchanges out of band and are registered by
+// operators; traders skip their decision cycles during a window.
+// ============================================================================
+
+// delistingHorizon perpetual contracts with a delivery date closer than this are scheduled for delisting
+// (perpetuals without a scheduled delisting report a delivery date around the year 2100)
+const delistingHorizon = 365 * 24 * time.Hour
+
+// MaintenanceWindowsConfigKey system config key of the persisted maintenance windows (JSON array)
+const MaintenanceWindowsConfigKey = "exchange_maintenance_windows"
+
+// DefaultDelistCloseBefore positions are closed this long before a scheduled delisting
+const DefaultDelistCloseBefore = 24 * time.Hour
+
+// SymbolRestriction why a symbol must not be opened
+type SymbolRestriction struct {
+	Symbol   string    `json:"symbol"`
+	Status   string    `json:"status"`              // Contract status, e.g. TRADING, SETTLING, CLOSE, PENDING_TRADING
+	DelistAt time.Time `json:"delist_at,omitempty"` // Scheduled delisting / settlement time (zero = none scheduled)
+	Reason   string    `json:"reason"`
+}
+
+// Settling reports whether the contract is already being wound down (positions should be closed now)
+func (r SymbolRestriction) Settling() bool {
+	return r.Status == "SETTLING" || r.Status == "CLOSE" || r.Status == "DELIVERING" || r.Status == "PRE_DELIVERING"
+}
+
+// MaintenanceWindow announced exchange maintenance period
+type MaintenanceWindow struct {
+	Exchange string    `json:"exchange"` // Exchange type, e.g. binance (empty = all exchanges)
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// Active reports whether the window covers an exchange at a point in time
+func (w MaintenanceWindow) Active(exchange string, at time.Time) bool {
+	if w.Exchange != "" && w.Exchange != exchange {
+		return false
+	}
+	return !at.Before(w.Start) && at.Before(w.End)
+}
+
+// SymbolStatusService tracks restricted symbols and maintenance windows
+type SymbolStatusService struct {
+	interval     time.Duration
+	fetch        func() (*ExchangeInfo, error)
+	restrictions map[string]SymbolRestriction // key: symbol
+	windows      []MaintenanceWindow
+	closeBefore  time.Duration
+	updatedAt    time.Time
+	mu           sync.RWMutex
+	stopCh       chan struct{}
+	stopOnce     sync.Once
+}
+
+// NewSymbolStatusService creates a symbol status service refreshing contract status every interval
+func NewSymbolStatusService(interval time.Duration) *SymbolStatusService {
+	return &SymbolStatusService{
+		interval:     interval,
+		fetch:        NewAPIClient().GetExchangeInfo,
+		restrictions: make(map[string]SymbolRestriction),
+		closeBefore:  DefaultDelistCloseBefore,
+		stopCh:       make(chan struct{}),
+	}
+}
+
+// Start loads the contract status and refreshes it in the background
+func (s *SymbolStatusService) Start() {
+	go func() {
+		s.refresh()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.refresh()
+			case <-s.stopCh:
+				return
+			}
+		}
+	}()
+	logger.Infof("📅 Symbol status service started (refresh every %v)", s.interval)
+}
+
+// Stop stops the scheduled refresh
+func (s *SymbolStatusService) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// Restriction returns the restriction of a symbol, ok is false for freely tradable symbols
+func (s *SymbolStatusService) Restriction(symbol string) (SymbolRestriction, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, ok := s.restrictions[Normalize(symbol)]
+	return r, ok
+}
+
+// Restrictions returns all restricted symbols, soonest delisting first
+func (s *SymbolStatusService) Restrictions() []SymbolRestriction {
+	s.mu.RLock()
+	list := make([]SymbolRestriction, 0, len(s.restrictions))
+	for _, r := range s.restrictions {
+		list = append(list, r)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].DelistAt.Equal(list[j].DelistAt) {
+			return list[i].Symbol < list[j].Symbol
+		}
+		if list[i].DelistAt.IsZero() || list[j].DelistAt.IsZero() {
+			return !list[i].DelistAt.IsZero()
+		}
+		return list[i].DelistAt.Before(list[j].DelistAt)
+	})
+	return list
+}
+
+// SetCloseBefore sets how long before a scheduled delisting positions must be closed
+func (s *SymbolStatusService) SetCloseBefore(d time.Duration) {
+	s.mu.Lock()
+	s.closeBefore = d
+	s.mu.Unlock()
+}
+
+// CloseDue reports whether positions in a restricted symbol must be closed now
+// (the contract is settling, or its delisting is within the close horizon)
+func (s *SymbolStatusService) CloseDue(r SymbolRestriction, at time.Time) bool {
+	if r.Settling() {
+		return true
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return !r.DelistAt.IsZero() && r.DelistAt.Sub(at) <= s.closeBefore
+}
+
+// UpdatedAt time of the last successful contract status refresh (zero = never)
+func (s *SymbolStatusService) UpdatedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.updatedAt
+}
+
+// SetMaintenanceWindows replaces the registered maintenance windows
+func (s *SymbolStatusService) SetMaintenanceWindows(windows []MaintenanceWindow) {
+	s.mu.Lock()
+	s.windows = append([]MaintenanceWindow(nil), windows...)
+	s.mu.Unlock()
+}
+
+// MaintenanceWindows returns the registered maintenance windows
+func (s *SymbolStatusService) MaintenanceWindows() []MaintenanceWindow {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]MaintenanceWindow(nil), s.windows...)
+}
+
+// ActiveMaintenance returns the maintenance window covering an exchange now, nil if none
+func (s *SymbolStatusService) ActiveMaintenance(exchange string, at time.Time) *MaintenanceWindow {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, w := range s.windows {
+		if w.Active(exchange, at) {
+			window := w
+			return &window
+		}
+	}
+	return nil
+}
+
+// refresh reloads contract status, keeping the previous restrictions when the exchange can't be reached
+func (s *SymbolStatusService) refresh() {
+	info, err := s.fetch()
+	if err != nil {
+		logger.Warnf("⚠️ Failed to refresh symbol status: %v", err)
+		return
+	}
+	restrictions := restrictionsFromExchangeInfo(info, time.Now())
+
+	s.mu.Lock()
+	for symbol, r := range restrictions {
+		if _, known := s.restrictions[symbol]; !known {
+			logger.Infof("📅 %s restricted: %s", symbol, r.Reason)
+		}
+	}
+	s.restrictions = restrictions
+	s.updatedAt = time.Now()
+	s.mu.Unlock()
+}
+
+// restrictionsFromExchangeInfo derives symbol restrictions from exchangeInfo contract data
+func restrictionsFromExchangeInfo(info *ExchangeInfo, now time.Time) map[string]SymbolRestriction {
+	restrictions := make(map[string]SymbolRestriction)
+	if info == nil {
+		return restrictions
+	}
+	for _, sym := range info.Symbols {
+		r := SymbolRestriction{Symbol: sym.Symbol, Status: sym.Status}
+		if sym.DeliveryDate > 0 {
+			deliveryAt := time.UnixMilli(sym.DeliveryDate).UTC()
+			if sym.ContractType != "PERPETUAL" || deliveryAt.Before(now.Add(delistingHorizon)) {
+				r.DelistAt = deliveryAt
+			}
+		}
+
+		switch {
+		case sym.Status != "" && sym.Status != "TRADING":
+			r.Reason = fmt.Sprintf("contract status %s", sym.Status)
+			if !r.DelistAt.IsZero() {
+				r.Reason += fmt.Sprintf(", delisting at %s", r.DelistAt.Format("2006-01-02 15:04 UTC"))
+			}
+		case sym.ContractType == "PERPETUAL" && !r.DelistAt.IsZero():
+			r.Reason = fmt.Sprintf("delisting scheduled at %s", r.DelistAt.Format("2006-01-02 15:04 UTC"))
+		default:
+			continue
+		}
+		restrictions[sym.Symbol] = r
+	}
+	return restrictions
+}
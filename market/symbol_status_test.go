@@ -0,0 +1,77 @@
+package market
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRestrictionsFromExchangeInfo(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	delistAt := now.Add(72 * time.Hour)
+	info := &ExchangeInfo{Symbols: []SymbolInfo{
+		{Symbol: "BTCUSDT", Status: "TRADING", ContractType: "PERPETUAL", DeliveryDate: 4133404800000},
+		{Symbol: "OLDUSDT", Status: "TRADING", ContractType: "PERPETUAL", DeliveryDate: delistAt.UnixMilli()},
+		{Symbol: "DEADUSDT", Status: "SETTLING", ContractType: "PERPETUAL", DeliveryDate: now.UnixMilli()},
+		{Symbol: "NEWUSDT", Status: "PENDING_TRADING", ContractType: "PERPETUAL", DeliveryDate: 4133404800000},
+	}}
+
+	restrictions := restrictionsFromExchangeInfo(info, now)
+
+	if _, ok := restrictions["BTCUSDT"]; ok {
+		t.Error("trading perpetual without delisting should not be restricted")
+	}
+	if r, ok := restrictions["OLDUSDT"]; !ok || !r.DelistAt.Equal(delistAt) {
+		t.Errorf("scheduled delisting should be restricted at %v, got %+v", delistAt, r)
+	}
+	if r := restrictions["DEADUSDT"]; !r.Settling() {
+		t.Errorf("settling contract should be restricted as settling, got %+v", r)
+	}
+	if r, ok := restrictions["NEWUSDT"]; !ok || !r.DelistAt.IsZero() {
+		t.Errorf("pending contract should be restricted without delisting date, got %+v", r)
+	}
+}
+
+func TestSymbolStatusServiceCloseDueAndMaintenance(t *testing.T) {
+	now := time.Now()
+	s := NewSymbolStatusService(time.Hour)
+	s.fetch = func() (*ExchangeInfo, error) {
+		return &ExchangeInfo{Symbols: []SymbolInfo{
+			{Symbol: "SOONUSDT", Status: "TRADING", ContractType: "PERPETUAL", DeliveryDate: now.Add(6 * time.Hour).UnixMilli()},
+			{Symbol: "LATERUSDT", Status: "TRADING", ContractType: "PERPETUAL", DeliveryDate: now.Add(10 * 24 * time.Hour).UnixMilli()},
+		}}, nil
+	}
+	s.refresh()
+
+	soon, ok := s.Restriction("SOON")
+	if !ok || !s.CloseDue(soon, now) {
+		t.Errorf("delisting within 24h should be due for close, got %+v", soon)
+	}
+	later, _ := s.Restriction("LATERUSDT")
+	if s.CloseDue(later, now) {
+		t.Error("delisting in 10 days should only be flagged")
+	}
+	if list := s.Restrictions(); len(list) != 2 || list[0].Symbol != "SOONUSDT" {
+		t.Errorf("restrictions should be sorted soonest first, got %+v", list)
+	}
+
+	// A failed refresh keeps the last known restrictions
+	s.fetch = func() (*ExchangeInfo, error) { return nil, errors.New("timeout") }
+	s.refresh()
+	if _, ok := s.Restriction("SOONUSDT"); !ok {
+		t.Error("restrictions should survive a failed refresh")
+	}
+
+	s.SetMaintenanceWindows([]MaintenanceWindow{
+		{Exchange: "binance", Start: now.Add(-time.Minute), End: now.Add(time.Hour), Reason: "upgrade"},
+	})
+	if w := s.ActiveMaintenance("binance", now); w == nil || w.Reason != "upgrade" {
+		t.Errorf("binance should be under maintenance, got %+v", w)
+	}
+	if s.ActiveMaintenance("bybit", now) != nil {
+		t.Error("maintenance of another exchange should not apply")
+	}
+	if s.ActiveMaintenance("binance", now.Add(2*time.Hour)) != nil {
+		t.Error("maintenance should end at its end time")
+	}
+}
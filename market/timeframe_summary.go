@@ -0,0 +1,145 @@
+package market
+
+import "math"
+
+// summaryBars bars a timeframe summary measures change and range over
+const summaryBars = 20
+
+// TimeframeSummary compact view of one timeframe, a single prompt line instead of a kline table
+type TimeframeSummary struct {
+	Timeframe string
+	Trend     int     // 1 up, -1 down, 0 sideways
+	Bars      int     // Bars ChangePct and RangePct are measured over
+	ChangePct float64 // Close change over Bars (%)
+	RSI14     float64 // 0 when unavailable
+	ATRPct    float64 // ATR14 relative to the close (%), 0 when unavailable
+	RangePct  float64 // Close position within the high-low range of Bars (0 = low, 100 = high)
+}
+
+// Summary summarizes a timeframe series (nil if it has no prices)
+// Uses the full fetched history when available, so trimmed series still summarize the same.
+func (t *TimeframeSeriesData) Summary() *TimeframeSummary {
+	klines := t.history
+	if len(klines) == 0 {
+		klines = make([]Kline, len(t.Klines))
+		for i, k := range t.Klines {
+			klines[i] = Kline{OpenTime: k.Time, Open: k.Open, High: k.High, Low: k.Low, Close: k.Close, Volume: k.Volume}
+		}
+	}
+	if len(klines) > 0 {
+		return summarizeKlines(t.Timeframe, klines)
+	}
+	return summarizeCloses(t.Timeframe, t.MidPrices, lastValue(t.EMA20Values), lastValue(t.RSI14Values), t.ATR14)
+}
+
+// Summary summarizes the intraday series of single timeframe data (nil if it has no prices)
+func (d *Data) Summary(timeframe string) *TimeframeSummary {
+	if d == nil || d.IntradaySeries == nil {
+		return nil
+	}
+	s := d.IntradaySeries
+	return summarizeCloses(timeframe, s.MidPrices, lastValue(s.EMA20Values), lastValue(s.RSI14Values), s.ATR14)
+}
+
+func summarizeKlines(timeframe string, klines []Kline) *TimeframeSummary {
+	window := klines
+	if len(window) > summaryBars {
+		window = window[len(window)-summaryBars:]
+	}
+	first, last := window[0].Close, window[len(window)-1].Close
+	summary := &TimeframeSummary{Timeframe: timeframe, Bars: len(window)}
+	if first > 0 {
+		summary.ChangePct = (last - first) / first * 100
+	}
+
+	high, low := window[0].High, window[0].Low
+	for _, k := range window {
+		high = math.Max(high, k.High)
+		low = math.Min(low, k.Low)
+	}
+	if high > low {
+		summary.RangePct = (last - low) / (high - low) * 100
+	}
+
+	if len(klines) >= 15 {
+		summary.RSI14 = calculateRSI(klines, 14)
+		if last > 0 {
+			summary.ATRPct = calculateATR(klines, 14) / last * 100
+		}
+	}
+
+	var ema20, ema50 float64
+	if len(klines) >= 20 {
+		ema20 = calculateEMA(klines, 20)
+	}
+	if len(klines) >= 50 {
+		ema50 = calculateEMA(klines, 50)
+	}
+	summary.Trend = trendOf(last, ema20, ema50, summary.ChangePct)
+	return summary
+}
+
+func summarizeCloses(timeframe string, closes []float64, ema20, rsi14, atr14 float64) *TimeframeSummary {
+	if len(closes) == 0 {
+		return nil
+	}
+	window := closes
+	if len(window) > summaryBars {
+		window = window[len(window)-summaryBars:]
+	}
+	first, last := window[0], window[len(window)-1]
+	summary := &TimeframeSummary{Timeframe: timeframe, Bars: len(window), RSI14: rsi14}
+	if first > 0 {
+		summary.ChangePct = (last - first) / first * 100
+	}
+	if last > 0 {
+		summary.ATRPct = atr14 / last * 100
+	}
+
+	high, low := window[0], window[0]
+	for _, c := range window {
+		high = math.Max(high, c)
+		low = math.Min(low, c)
+	}
+	if high > low {
+		summary.RangePct = (last - low) / (high - low) * 100
+	}
+	summary.Trend = trendOf(last, ema20, 0, summary.ChangePct)
+	return summary
+}
+
+// trendOf classifies the trend from the EMA stack, falling back to the direction of change
+// Unavailable EMAs are 0.
+func trendOf(price, ema20, ema50, changePct float64) int {
+	switch {
+	case ema20 > 0 && ema50 > 0:
+		if price > ema20 && ema20 > ema50 {
+			return 1
+		}
+		if price < ema20 && ema20 < ema50 {
+			return -1
+		}
+	case ema20 > 0:
+		if price > ema20 && changePct > 0 {
+			return 1
+		}
+		if price < ema20 && changePct < 0 {
+			return -1
+		}
+	default:
+		if changePct > 0 {
+			return 1
+		}
+		if changePct < 0 {
+			return -1
+		}
+	}
+	return 0
+}
+
+func lastValue(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return values[len(values)-1]
+}
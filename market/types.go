@@ -13,6 +13,7 @@ type Data struct {
 	CurrentRSI7       float64
 	OpenInterest      *OIData
 	FundingRate       float64
+	NextFundingTime   time.Time // Next funding settlement, zero if unknown
 	IntradaySeries    *IntradayData
 	LongerTermContext *LongerTermData
 	// Multi-timeframe data (new)
@@ -45,6 +46,33 @@ type TimeframeSeriesData struct {
 	BOLLUpper  []float64 `json:"boll_upper"`  // Upper band
 	BOLLMiddle []float64 `json:"boll_middle"` // Middle band (SMA)
 	BOLLLower  []float64 `json:"boll_lower"`  // Lower band
+
+	history []Kline // Full fetched klines, input of the extended indicator engine
+}
+
+// Tail returns a copy keeping only the last n points of each series (extended indicators are unaffected)
+func (t *TimeframeSeriesData) Tail(n int) *TimeframeSeriesData {
+	c := *t
+	if len(c.Klines) > n {
+		c.Klines = c.Klines[len(c.Klines)-n:]
+	}
+	for _, series := range []*[]float64{&c.MidPrices, &c.EMA20Values, &c.EMA50Values, &c.MACDValues,
+		&c.RSI7Values, &c.RSI14Values, &c.Volume, &c.BOLLUpper, &c.BOLLMiddle, &c.BOLLLower} {
+		if len(*series) > n {
+			*series = (*series)[len(*series)-n:]
+		}
+	}
+	return &c
+}
+
+// WithSeriesLimit returns a copy whose timeframe series keep at most n points
+func (d *Data) WithSeriesLimit(n int) *Data {
+	c := *d
+	c.TimeframeData = make(map[string]*TimeframeSeriesData, len(d.TimeframeData))
+	for tf, series := range d.TimeframeData {
+		c.TimeframeData[tf] = series.Tail(n)
+	}
+	return &c
 }
 
 // OIData Open Interest data
@@ -89,6 +117,8 @@ type SymbolInfo struct {
 	ContractType      string `json:"contractType"`
 	PricePrecision    int    `json:"pricePrecision"`
 	QuantityPrecision int    `json:"quantityPrecision"`
+	DeliveryDate      int64  `json:"deliveryDate"` // Delivery/delisting time in ms (perpetuals without a schedule: ~2100)
+	OnboardDate       int64  `json:"onboardDate"`
 }
 
 type Kline struct {
@@ -55,8 +55,10 @@ type Client struct {
 	MaxTokens  int  // Maximum tokens for AI response
 
 	httpClient *http.Client
-	logger     Logger // Logger (replaceable)
-	config     *Config // Config object (stores all configurations)
+	logger     Logger         // Logger (replaceable)
+	config     *Config        // Config object (stores all configurations)
+	caller     string         // Caller calls are queued under by the concurrency throttle (e.g. trader ID)
+	cache      *ResponseCache // Answers identical requests without calling the API (nil: disabled)
 
 	// hooks are used to implement dynamic dispatch (polymorphism)
 	// When DeepSeekClient embeds Client, hooks point to DeepSeekClient
@@ -109,6 +111,7 @@ func NewClient(opts ...ClientOption) AIClient {
 		httpClient: cfg.HTTPClient,
 		logger:     cfg.Logger,
 		config:     cfg,
+		cache:      cfg.ResponseCache,
 	}
 
 	// 4. Set default Provider (if not set)
@@ -147,6 +150,13 @@ func (client *Client) SetTimeout(timeout time.Duration) {
 
 // CallWithMessages template method - fixed retry flow (cannot be overridden)
 func (client *Client) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	return client.cachedCall("text", systemPrompt, userPrompt, func() (string, error) {
+		return client.callWithRetry(systemPrompt, userPrompt)
+	})
+}
+
+// callWithRetry calls the API with the retry flow
+func (client *Client) callWithRetry(systemPrompt, userPrompt string) (string, error) {
 	if client.APIKey == "" {
 		return "", fmt.Errorf("AI API key not set, please call SetAPIKey first")
 	}
@@ -317,7 +327,9 @@ func (client *Client) call(systemPrompt, userPrompt string) (string, error) {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Step 5: Send HTTP request (fixed logic)
+	// Step 5: Send HTTP request once a call slot is free (fixed logic)
+	release := client.acquireSlot()
+	defer release()
 	resp, err := client.httpClient.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to send request: %w", err)
@@ -450,7 +462,9 @@ func (client *Client) callWithRequest(req *Request) (string, error) {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Send HTTP request
+	// Send HTTP request once a call slot is free
+	release := client.acquireSlot()
+	defer release()
 	resp, err := client.httpClient.Do(httpReq)
 	if err != nil {
 		return "", fmt.Errorf("failed to send request: %w", err)
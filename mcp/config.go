@@ -33,6 +33,9 @@ type Config struct {
 	// Dependency injection
 	Logger     Logger
 	HTTPClient *http.Client
+
+	// Response cache (nil: disabled)
+	ResponseCache *ResponseCache
 }
 
 // DefaultConfig returns default configuration
@@ -0,0 +1,64 @@
+package mcp
+
+import "strings"
+
+// DefaultContextWindow context window assumed for models not in contextWindows (tokens)
+const DefaultContextWindow = 64000
+
+// contextWindows context window per model name prefix (tokens), longest matching prefix wins
+// Conservative values: a lower limit only trims more context, a higher one gets prompts rejected.
+var contextWindows = map[string]int{
+	"deepseek":        128000,
+	"qwen":            128000,
+	"qwen-turbo":      1000000,
+	"gpt-3.5":         16000,
+	"gpt-4":           8000,
+	"gpt-4-turbo":     128000,
+	"gpt-4o":          128000,
+	"gpt-4.1":         1000000,
+	"gpt-5":           400000,
+	"o1":              200000,
+	"o3":              200000,
+	"o4":              200000,
+	"claude":          200000,
+	"gemini":          1000000,
+	"grok":            131072,
+	"grok-4":          256000,
+	"moonshot-v1-8k":  8192,
+	"moonshot-v1-32k": 32768,
+	"moonshot":        131072,
+	"kimi":            131072,
+}
+
+// ModelNamer is implemented by clients that can report the model they call
+type ModelNamer interface {
+	ModelName() string
+}
+
+// ModelName returns the model this client calls
+func (client *Client) ModelName() string {
+	return client.Model
+}
+
+// ContextWindow returns the context window of a model in tokens, DefaultContextWindow when unknown
+func ContextWindow(model string) int {
+	model = strings.ToLower(model)
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		model = model[i+1:] // OpenRouter style "provider/model"
+	}
+	best, window := 0, DefaultContextWindow
+	for prefix, size := range contextWindows {
+		if strings.HasPrefix(model, prefix) && len(prefix) > best {
+			best, window = len(prefix), size
+		}
+	}
+	return window
+}
+
+// ContextWindowOf returns the context window of the model a client calls
+func ContextWindowOf(client AIClient) int {
+	if namer, ok := client.(ModelNamer); ok {
+		return ContextWindow(namer.ModelName())
+	}
+	return DefaultContextWindow
+}
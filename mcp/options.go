@@ -33,6 +33,17 @@ func WithHTTPClient(client *http.Client) ClientOption {
 	}
 }
 
+// WithResponseCache answers byte-identical requests from a response cache (backtests, replay tooling)
+//
+// Usage example:
+//   cache, _ := mcp.NewResponseCache("backtests/ai_responses")
+//   client := mcp.NewClient(mcp.WithResponseCache(cache))
+func WithResponseCache(cache *ResponseCache) ClientOption {
+	return func(c *Config) {
+		c.ResponseCache = cache
+	}
+}
+
 // ============================================================
 // Timeout and Retry Options
 // ============================================================
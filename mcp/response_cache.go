@@ -0,0 +1,151 @@
+package mcp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// AI Response Cache
+// ============================================================================
+// Content-addressed store of AI responses: the key hashes provider, model and
+// the exact prompts (plus the output schema for structured calls), so only a
+// byte-identical request is ever answered from the cache. Meant for backtests
+// and replay tooling, where sweeps re-send identical market snapshots; live
+// trading prompts carry the current time and never repeat.
+// Entries are one JSON file each, sharded by the first two hex digits of the key.
+// ============================================================================
+
+// ResponseCacher is implemented by clients that can answer identical requests from a response cache
+type ResponseCacher interface {
+	// SetResponseCache sets the cache consulted before each call (nil: disabled)
+	SetResponseCache(cache *ResponseCache)
+}
+
+// ResponseCache on-disk AI response cache
+type ResponseCache struct {
+	dir    string
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// cachedResponse one cache entry
+type cachedResponse struct {
+	Provider  string    `json:"provider"`
+	Model     string    `json:"model"`
+	Response  string    `json:"response"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewResponseCache opens (creating if needed) a response cache stored under dir
+func NewResponseCache(dir string) (*ResponseCache, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("response cache directory is empty")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create response cache directory: %w", err)
+	}
+	return &ResponseCache{dir: dir}, nil
+}
+
+// ResponseCacheKey content address of a request
+// kind distinguishes call types with different response formats (e.g. "text", or the output schema)
+func ResponseCacheKey(provider, model, kind, systemPrompt, userPrompt string) string {
+	h := sha256.New()
+	for _, part := range []string{provider, model, kind, systemPrompt, userPrompt} {
+		// Length prefixes keep part boundaries unambiguous
+		fmt.Fprintf(h, "%d:%s", len(part), part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *ResponseCache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// Get returns the cached response of a request key
+func (c *ResponseCache) Get(key string) (string, bool) {
+	if c == nil || len(key) < 2 {
+		return "", false
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		c.misses.Add(1)
+		return "", false
+	}
+	var entry cachedResponse
+	if err := json.Unmarshal(data, &entry); err != nil {
+		c.misses.Add(1)
+		return "", false
+	}
+	c.hits.Add(1)
+	return entry.Response, true
+}
+
+// Put stores the response of a request key (written atomically, concurrent runs may share the cache)
+func (c *ResponseCache) Put(key, provider, model, response string) error {
+	if c == nil || len(key) < 2 {
+		return nil
+	}
+	data, err := json.Marshal(cachedResponse{Provider: provider, Model: model, Response: response, CreatedAt: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Stats returns the cache hits and misses since it was opened
+func (c *ResponseCache) Stats() (hits, misses int64) {
+	if c == nil {
+		return 0, 0
+	}
+	return c.hits.Load(), c.misses.Load()
+}
+
+// SetResponseCache sets the cache consulted before each call (nil: disabled)
+func (client *Client) SetResponseCache(cache *ResponseCache) {
+	client.cache = cache
+}
+
+// cachedCall answers a request from the response cache, or calls and caches a successful response
+func (client *Client) cachedCall(kind, systemPrompt, userPrompt string, call func() (string, error)) (string, error) {
+	if client.cache == nil {
+		return call()
+	}
+	key := ResponseCacheKey(client.Provider, client.Model, kind, systemPrompt, userPrompt)
+	if response, ok := client.cache.Get(key); ok {
+		client.logger.Infof("💾 [%s] AI response served from cache (%s)", client.String(), key[:12])
+		return response, nil
+	}
+	response, err := call()
+	if err != nil {
+		return "", err
+	}
+	if err := client.cache.Put(key, client.Provider, client.Model, response); err != nil {
+		client.logger.Warnf("⚠️ Failed to cache AI response: %v", err)
+	}
+	return response, nil
+}
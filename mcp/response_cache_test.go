@@ -0,0 +1,68 @@
+package mcp
+
+import "testing"
+
+func TestResponseCache_IdenticalRequestServedFromCache(t *testing.T) {
+	cache, err := NewResponseCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetSuccessResponse("decision")
+	client := NewDeepSeekClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewMockLogger()),
+		WithAPIKey("test-key"),
+		WithResponseCache(cache),
+	)
+
+	for i := 0; i < 2; i++ {
+		result, err := client.CallWithMessages("system", "user")
+		if err != nil {
+			t.Fatalf("call %d should not error: %v", i, err)
+		}
+		if result != "decision" {
+			t.Errorf("call %d: expected cached response, got %q", i, result)
+		}
+	}
+	if n := len(mockHTTP.GetRequests()); n != 1 {
+		t.Errorf("identical request should reach the API once, got %d requests", n)
+	}
+	if hits, misses := cache.Stats(); hits != 1 || misses != 1 {
+		t.Errorf("expected 1 hit and 1 miss, got %d/%d", hits, misses)
+	}
+
+	// A different prompt or model is a different key
+	client.CallWithMessages("system", "other user")
+	client.(*DeepSeekClient).Model = "deepseek-reasoner"
+	client.CallWithMessages("system", "user")
+	if n := len(mockHTTP.GetRequests()); n != 3 {
+		t.Errorf("changed prompt and model should both call the API, got %d requests", n)
+	}
+}
+
+func TestResponseCache_ReplayWithoutAPIKey(t *testing.T) {
+	cache, _ := NewResponseCache(t.TempDir())
+	key := ResponseCacheKey(ProviderDeepSeek, DefaultDeepSeekModel, "text", "system", "user")
+	if err := cache.Put(key, ProviderDeepSeek, DefaultDeepSeekModel, "replayed"); err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+
+	client := NewDeepSeekClientWithOptions(WithLogger(NewMockLogger()), WithResponseCache(cache))
+	result, err := client.CallWithMessages("system", "user")
+	if err != nil || result != "replayed" {
+		t.Errorf("cached request should replay without an API key, got %q, %v", result, err)
+	}
+	if _, err := client.CallWithMessages("system", "new"); err == nil {
+		t.Error("uncached request without an API key should error")
+	}
+}
+
+func TestResponseCacheKey_SeparatesKinds(t *testing.T) {
+	text := ResponseCacheKey("openai", "gpt-4o", "text", "s", "u")
+	schema := ResponseCacheKey("openai", "gpt-4o", "schema:{}", "s", "u")
+	shifted := ResponseCacheKey("openai", "gpt-4o", "text", "su", "")
+	if text == schema || text == shifted {
+		t.Error("keys of different requests must differ")
+	}
+}
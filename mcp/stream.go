@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// StreamingCaller is implemented by clients that can stream the response text as it is generated
+type StreamingCaller interface {
+	// CallWithMessagesStream calls onChunk with each text delta and returns the full response
+	CallWithMessagesStream(systemPrompt, userPrompt string, onChunk func(chunk string)) (string, error)
+}
+
+// streamEvent one server-sent event of a streaming response
+// Covers both OpenAI-compatible chunks (choices[].delta.content) and Anthropic events (delta.text)
+type streamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Text string `json:"text"`
+	} `json:"delta"`
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// CallWithMessagesStream single streaming AI API call (not retried, chunks already delivered can't be taken back)
+func (client *Client) CallWithMessagesStream(systemPrompt, userPrompt string, onChunk func(chunk string)) (string, error) {
+	if client.APIKey == "" {
+		return "", fmt.Errorf("AI API key not set, please call SetAPIKey first")
+	}
+
+	client.logger.Infof("📡 [%s] Request AI Server (stream): BaseURL: %s", client.String(), client.BaseURL)
+
+	requestBody := client.hooks.buildMCPRequestBody(systemPrompt, userPrompt)
+	requestBody["stream"] = true
+
+	jsonData, err := client.hooks.marshalRequestBody(requestBody)
+	if err != nil {
+		return "", err
+	}
+	req, err := client.hooks.buildRequest(client.hooks.buildUrl(), jsonData)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	release := client.acquireSlot()
+	defer release()
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("API returned error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	return readStream(resp.Body, onChunk)
+}
+
+// readStream reads an SSE response body, forwarding text deltas to onChunk
+func readStream(body io.Reader, onChunk func(chunk string)) (string, error) {
+	var full strings.Builder
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue // blank separators, "event:" lines and keep-alive comments
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "[DONE]" {
+			break
+		}
+
+		var event streamEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			continue
+		}
+		if event.Error != nil {
+			return full.String(), fmt.Errorf("AI stream error: %s - %s", event.Error.Type, event.Error.Message)
+		}
+
+		chunk := event.Delta.Text
+		for _, choice := range event.Choices {
+			chunk += choice.Delta.Content
+		}
+		if chunk == "" {
+			continue
+		}
+		full.WriteString(chunk)
+		if onChunk != nil {
+			onChunk(chunk)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return full.String(), fmt.Errorf("failed to read stream: %w", err)
+	}
+	if full.Len() == 0 {
+		return "", fmt.Errorf("AI stream returned no content")
+	}
+	return full.String(), nil
+}
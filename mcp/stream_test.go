@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCallWithMessagesStream_OpenAICompatible(t *testing.T) {
+	var captured map[string]any
+	mockHTTP := structuredMock(
+		"data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n"+
+			": keep-alive\n\n"+
+			"data: {\"choices\":[{\"delta\":{\"content\":\"lo\"}}]}\n\n"+
+			"data: [DONE]\n\n",
+		&captured)
+	client := NewDeepSeekClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewMockLogger()),
+		WithAPIKey("test-key"),
+	).(StreamingCaller)
+
+	var chunks []string
+	result, err := client.CallWithMessagesStream("system", "user", func(chunk string) {
+		chunks = append(chunks, chunk)
+	})
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if result != "Hello" || strings.Join(chunks, "|") != "Hel|lo" {
+		t.Errorf("expected Hello in two chunks, got %q (%v)", result, chunks)
+	}
+	if captured["stream"] != true {
+		t.Error("request should enable streaming")
+	}
+}
+
+func TestCallWithMessagesStream_Claude(t *testing.T) {
+	var captured map[string]any
+	mockHTTP := structuredMock(
+		"event: message_start\ndata: {\"type\":\"message_start\"}\n\n"+
+			"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"Hi\"}}\n\n"+
+			"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n",
+		&captured)
+	client := NewClaudeClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewMockLogger()),
+		WithAPIKey("test-key"),
+	).(StreamingCaller)
+
+	result, err := client.CallWithMessagesStream("system", "user", nil)
+	if err != nil || result != "Hi" {
+		t.Errorf("expected Hi, got %q, %v", result, err)
+	}
+}
+
+func TestCallWithMessagesStream_Error(t *testing.T) {
+	var captured map[string]any
+	mockHTTP := structuredMock("data: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n", &captured)
+	client := NewClaudeClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewMockLogger()),
+		WithAPIKey("test-key"),
+	).(StreamingCaller)
+
+	if _, err := client.CallWithMessagesStream("system", "user", nil); err == nil || !strings.Contains(err.Error(), "Overloaded") {
+		t.Errorf("expected stream error, got %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrStructuredOutputUnsupported the provider has no structured output / function calling mode
+var ErrStructuredOutputUnsupported = errors.New("structured output not supported by provider")
+
+// OutputSchema JSON schema the AI response must conform to
+type OutputSchema struct {
+	Name        string         // Schema / function name (letters, digits, underscores)
+	Description string         // What the output is used for
+	Schema      map[string]any // JSON Schema of the output object (top level must be an object)
+}
+
+// StructuredCaller is implemented by clients that can enforce a JSON schema on the response
+// Callers type-assert an AIClient and fall back to CallWithMessages + text parsing otherwise
+type StructuredCaller interface {
+	// CallWithSchema returns the JSON object produced by the model, or
+	// ErrStructuredOutputUnsupported when the provider lacks the feature
+	CallWithSchema(systemPrompt, userPrompt string, schema *OutputSchema) (string, error)
+}
+
+// structuredMode how a provider enforces a schema
+type structuredMode int
+
+const (
+	structuredNone         structuredMode = iota
+	structuredJSONSchema                  // OpenAI response_format json_schema
+	structuredFunctionCall                // OpenAI-compatible tools + forced tool_choice
+	structuredToolUse                     // Anthropic tools + forced tool_choice
+)
+
+// structuredModes providers with a known structured output mode (custom and Kimi use text parsing)
+var structuredModes = map[string]structuredMode{
+	ProviderOpenAI:   structuredJSONSchema,
+	ProviderClaude:   structuredToolUse,
+	ProviderDeepSeek: structuredFunctionCall,
+	ProviderQwen:     structuredFunctionCall,
+	ProviderGemini:   structuredFunctionCall,
+	ProviderGrok:     structuredFunctionCall,
+}
+
+// CallWithSchema single AI API call with the response constrained to a JSON schema
+// Not retried: on failure callers fall back to CallWithMessages, which has the retry flow
+func (client *Client) CallWithSchema(systemPrompt, userPrompt string, schema *OutputSchema) (string, error) {
+	mode := structuredModes[client.Provider]
+	if mode == structuredNone || schema == nil {
+		return "", ErrStructuredOutputUnsupported
+	}
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize output schema: %w", err)
+	}
+	return client.cachedCall("schema:"+string(schemaJSON), systemPrompt, userPrompt, func() (string, error) {
+		return client.callWithSchema(systemPrompt, userPrompt, schema, mode)
+	})
+}
+
+// callWithSchema single structured API call
+func (client *Client) callWithSchema(systemPrompt, userPrompt string, schema *OutputSchema, mode structuredMode) (string, error) {
+	if client.APIKey == "" {
+		return "", fmt.Errorf("AI API key not set, please call SetAPIKey first")
+	}
+
+	client.logger.Infof("📡 [%s] Request AI Server with output schema %s", client.String(), schema.Name)
+
+	requestBody := client.hooks.buildMCPRequestBody(systemPrompt, userPrompt)
+	applyOutputSchema(requestBody, mode, schema)
+
+	jsonData, err := client.hooks.marshalRequestBody(requestBody)
+	if err != nil {
+		return "", err
+	}
+	req, err := client.hooks.buildRequest(client.hooks.buildUrl(), jsonData)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	release := client.acquireSlot()
+	defer release()
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("API returned error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	if mode == structuredToolUse {
+		return client.parseToolUseResponse(body, schema.Name)
+	}
+	return client.parseStructuredResponse(body, mode)
+}
+
+// applyOutputSchema adds the provider specific schema fields to a request body
+func applyOutputSchema(requestBody map[string]any, mode structuredMode, schema *OutputSchema) {
+	switch mode {
+	case structuredJSONSchema:
+		requestBody["response_format"] = map[string]any{
+			"type": "json_schema",
+			"json_schema": map[string]any{
+				"name":        schema.Name,
+				"description": schema.Description,
+				"schema":      schema.Schema,
+			},
+		}
+	case structuredFunctionCall:
+		requestBody["tools"] = []Tool{{
+			Type:     "function",
+			Function: FunctionDef{Name: schema.Name, Description: schema.Description, Parameters: schema.Schema},
+		}}
+		requestBody["tool_choice"] = map[string]any{
+			"type":     "function",
+			"function": map[string]string{"name": schema.Name},
+		}
+	case structuredToolUse:
+		requestBody["tools"] = []map[string]any{{
+			"name":         schema.Name,
+			"description":  schema.Description,
+			"input_schema": schema.Schema,
+		}}
+		requestBody["tool_choice"] = map[string]any{"type": "tool", "name": schema.Name}
+	}
+}
+
+// parseStructuredResponse extracts the schema output of an OpenAI-compatible response
+// json_schema mode returns it as message content, function calling as tool call arguments
+func (client *Client) parseStructuredResponse(body []byte, mode structuredMode) (string, error) {
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content   string `json:"content"`
+				ToolCalls []struct {
+					Function struct {
+						Name      string `json:"name"`
+						Arguments string `json:"arguments"`
+					} `json:"function"`
+				} `json:"tool_calls"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+			TotalTokens      int `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("API returned empty response")
+	}
+
+	if TokenUsageCallback != nil && result.Usage.TotalTokens > 0 {
+		TokenUsageCallback(TokenUsage{
+			Provider:         client.Provider,
+			Model:            client.Model,
+			PromptTokens:     result.Usage.PromptTokens,
+			CompletionTokens: result.Usage.CompletionTokens,
+			TotalTokens:      result.Usage.TotalTokens,
+		})
+	}
+
+	message := result.Choices[0].Message
+	if mode == structuredFunctionCall {
+		if len(message.ToolCalls) == 0 || message.ToolCalls[0].Function.Arguments == "" {
+			return "", fmt.Errorf("model did not call the output function, body: %s", string(body))
+		}
+		return message.ToolCalls[0].Function.Arguments, nil
+	}
+	if message.Content == "" {
+		return "", fmt.Errorf("API returned empty content")
+	}
+	return message.Content, nil
+}
+
+// parseToolUseResponse extracts the tool input of an Anthropic Messages response
+func (client *Client) parseToolUseResponse(body []byte, toolName string) (string, error) {
+	var response struct {
+		Content []struct {
+			Type  string          `json:"type"`
+			Name  string          `json:"name"`
+			Input json.RawMessage `json:"input"`
+		} `json:"content"`
+		Usage struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+		Error *struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf("failed to parse Claude response: %w", err)
+	}
+	if response.Error != nil {
+		return "", fmt.Errorf("Claude API error: %s - %s", response.Error.Type, response.Error.Message)
+	}
+
+	totalTokens := response.Usage.InputTokens + response.Usage.OutputTokens
+	if TokenUsageCallback != nil && totalTokens > 0 {
+		TokenUsageCallback(TokenUsage{
+			Provider:         client.Provider,
+			Model:            client.Model,
+			PromptTokens:     response.Usage.InputTokens,
+			CompletionTokens: response.Usage.OutputTokens,
+			TotalTokens:      totalTokens,
+		})
+	}
+
+	for _, content := range response.Content {
+		if content.Type == "tool_use" && content.Name == toolName && len(content.Input) > 0 {
+			return string(content.Input), nil
+		}
+	}
+	return "", fmt.Errorf("model did not call the %s tool, body: %s", toolName, string(body))
+}
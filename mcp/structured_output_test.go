@@ -0,0 +1,118 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+var testOutputSchema = &OutputSchema{
+	Name:   "submit_decisions",
+	Schema: map[string]any{"type": "object", "properties": map[string]any{"decisions": map[string]any{"type": "array"}}},
+}
+
+// structuredMock returns a mock HTTP client answering with response and capturing the request body
+func structuredMock(response string, captured *map[string]any) *MockHTTPClient {
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.ResponseFunc = func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		json.Unmarshal(body, captured)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(response)),
+			Header:     make(http.Header),
+		}, nil
+	}
+	return mockHTTP
+}
+
+func TestCallWithSchema_FunctionCalling(t *testing.T) {
+	var captured map[string]any
+	mockHTTP := structuredMock(`{"choices":[{"message":{"content":"","tool_calls":[{"function":{"name":"submit_decisions","arguments":"{\"decisions\":[]}"}}]}}]}`, &captured)
+	client := NewDeepSeekClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewMockLogger()),
+		WithAPIKey("test-key"),
+	).(StructuredCaller)
+
+	result, err := client.CallWithSchema("system", "user", testOutputSchema)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if result != `{"decisions":[]}` {
+		t.Errorf("expected tool call arguments, got %s", result)
+	}
+	if _, ok := captured["tools"]; !ok {
+		t.Error("request should declare the output function in tools")
+	}
+	choice, _ := captured["tool_choice"].(map[string]any)
+	if fn, _ := choice["function"].(map[string]any); fn["name"] != "submit_decisions" {
+		t.Errorf("tool_choice should force the output function, got %v", captured["tool_choice"])
+	}
+}
+
+func TestCallWithSchema_JSONSchema(t *testing.T) {
+	var captured map[string]any
+	mockHTTP := structuredMock(`{"choices":[{"message":{"content":"{\"decisions\":[]}"}}]}`, &captured)
+	client := NewOpenAIClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewMockLogger()),
+		WithAPIKey("test-key"),
+	).(StructuredCaller)
+
+	result, err := client.CallWithSchema("system", "user", testOutputSchema)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if result != `{"decisions":[]}` {
+		t.Errorf("expected message content, got %s", result)
+	}
+	format, _ := captured["response_format"].(map[string]any)
+	if format["type"] != "json_schema" {
+		t.Errorf("response_format should be json_schema, got %v", captured["response_format"])
+	}
+}
+
+func TestCallWithSchema_ClaudeToolUse(t *testing.T) {
+	var captured map[string]any
+	mockHTTP := structuredMock(`{"content":[{"type":"text","text":"thinking"},{"type":"tool_use","name":"submit_decisions","input":{"decisions":[]}}]}`, &captured)
+	client := NewClaudeClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewMockLogger()),
+		WithAPIKey("test-key"),
+	).(StructuredCaller)
+
+	result, err := client.CallWithSchema("system", "user", testOutputSchema)
+	if err != nil {
+		t.Fatalf("should not error: %v", err)
+	}
+	if result != `{"decisions":[]}` {
+		t.Errorf("expected tool input, got %s", result)
+	}
+	if choice, _ := captured["tool_choice"].(map[string]any); choice["type"] != "tool" || choice["name"] != "submit_decisions" {
+		t.Errorf("tool_choice should force the output tool, got %v", captured["tool_choice"])
+	}
+	if captured["system"] != "system" {
+		t.Error("Claude request format should be kept")
+	}
+}
+
+func TestCallWithSchema_Unsupported(t *testing.T) {
+	mockHTTP := NewMockHTTPClient()
+	client := NewKimiClientWithOptions(
+		WithHTTPClient(mockHTTP.ToHTTPClient()),
+		WithLogger(NewMockLogger()),
+		WithAPIKey("test-key"),
+	).(StructuredCaller)
+
+	_, err := client.CallWithSchema("system", "user", testOutputSchema)
+	if !errors.Is(err, ErrStructuredOutputUnsupported) {
+		t.Errorf("expected ErrStructuredOutputUnsupported, got %v", err)
+	}
+	if len(mockHTTP.GetRequests()) != 0 {
+		t.Error("unsupported provider should not send a request")
+	}
+}
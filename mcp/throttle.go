@@ -0,0 +1,200 @@
+package mcp
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Concurrent AI Call Throttle
+// ============================================================================
+// All clients of a provider share a limit on in-flight API calls, so many
+// traders starting their cycles together don't burst into the provider's rate
+// limits. Calls over the limit queue per caller (trader ID) and freed slots are
+// handed out round-robin across callers: a trader with several queued calls
+// (fallbacks, debates) can't starve the others. Each attempt of a retried call
+// takes its own slot, so the retry backoff doesn't hold one.
+// ============================================================================
+
+// slowQueueWait a call queued at least this long is logged as a warning
+const slowQueueWait = 10 * time.Second
+
+// CallerTagger is implemented by clients that can tag their calls with the caller they run for
+type CallerTagger interface {
+	// SetCaller sets the caller (e.g. trader ID) calls are queued under
+	SetCaller(caller string)
+}
+
+// ThrottleStat concurrency and queue wait metrics of one provider
+type ThrottleStat struct {
+	Provider       string         `json:"provider"`
+	Limit          int            `json:"limit"` // 0 = unlimited
+	Active         int            `json:"active"`
+	Queued         int            `json:"queued"`
+	QueuedByCaller map[string]int `json:"queued_by_caller,omitempty"`
+	Calls          int64          `json:"calls"`  // Slots granted since startup
+	Waited         int64          `json:"waited"` // Calls that had to queue
+	AvgWaitMs      float64        `json:"avg_wait_ms"`
+	MaxWaitMs      int64          `json:"max_wait_ms"`
+}
+
+type throttleWaiter struct {
+	ready    chan struct{}
+	queuedAt time.Time
+	wait     time.Duration // Set when the slot is granted
+}
+
+// providerQueue in-flight and queued calls of one provider
+type providerQueue struct {
+	limit   int
+	active  int
+	waiting map[string][]*throttleWaiter // caller -> FIFO of queued calls
+	order   []string                     // Callers with queued calls, next to be served first
+
+	calls     int64
+	waited    int64
+	totalWait time.Duration
+	maxWait   time.Duration
+}
+
+var (
+	throttleMu      sync.Mutex
+	throttleDefault int                    // Limit of providers without their own, 0 = unlimited
+	throttleLimits  = make(map[string]int) // provider -> limit
+	throttleQueues  = make(map[string]*providerQueue)
+)
+
+// SetConcurrencyLimits sets the max in-flight AI calls per provider
+// defaultLimit applies to providers missing from perProvider; 0 = unlimited.
+// Raising a limit immediately hands the new slots to queued calls.
+func SetConcurrencyLimits(defaultLimit int, perProvider map[string]int) {
+	throttleMu.Lock()
+	defer throttleMu.Unlock()
+	throttleDefault = defaultLimit
+	throttleLimits = make(map[string]int, len(perProvider))
+	for provider, limit := range perProvider {
+		throttleLimits[provider] = limit
+	}
+	for provider, q := range throttleQueues {
+		q.limit = providerLimit(provider)
+		q.dispatch()
+	}
+}
+
+// ThrottleStats current concurrency and queue wait metrics of every provider called so far
+func ThrottleStats() []ThrottleStat {
+	throttleMu.Lock()
+	defer throttleMu.Unlock()
+	stats := make([]ThrottleStat, 0, len(throttleQueues))
+	for provider, q := range throttleQueues {
+		stat := ThrottleStat{
+			Provider:  provider,
+			Limit:     q.limit,
+			Active:    q.active,
+			Calls:     q.calls,
+			Waited:    q.waited,
+			MaxWaitMs: q.maxWait.Milliseconds(),
+		}
+		if q.waited > 0 {
+			stat.AvgWaitMs = float64(q.totalWait.Milliseconds()) / float64(q.waited)
+		}
+		for caller, waiters := range q.waiting {
+			if stat.QueuedByCaller == nil {
+				stat.QueuedByCaller = make(map[string]int)
+			}
+			stat.QueuedByCaller[caller] = len(waiters)
+			stat.Queued += len(waiters)
+		}
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Provider < stats[j].Provider })
+	return stats
+}
+
+// providerLimit limit of a provider (throttleMu held)
+func providerLimit(provider string) int {
+	if limit, ok := throttleLimits[provider]; ok {
+		return limit
+	}
+	return throttleDefault
+}
+
+// acquireCallSlot blocks until a call slot of the provider is free
+// Returns the function releasing the slot (safe to call more than once) and how long the call queued.
+func acquireCallSlot(provider, caller string) (func(), time.Duration) {
+	throttleMu.Lock()
+	q := throttleQueues[provider]
+	if q == nil {
+		q = &providerQueue{limit: providerLimit(provider), waiting: make(map[string][]*throttleWaiter)}
+		throttleQueues[provider] = q
+	}
+
+	var wait time.Duration
+	if q.limit <= 0 || (q.active < q.limit && len(q.order) == 0) {
+		q.active++
+		q.calls++
+		throttleMu.Unlock()
+	} else {
+		w := &throttleWaiter{ready: make(chan struct{}), queuedAt: time.Now()}
+		if len(q.waiting[caller]) == 0 {
+			q.order = append(q.order, caller)
+		}
+		q.waiting[caller] = append(q.waiting[caller], w)
+		throttleMu.Unlock()
+		<-w.ready
+		wait = w.wait
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			throttleMu.Lock()
+			defer throttleMu.Unlock()
+			q.active--
+			q.dispatch()
+		})
+	}, wait
+}
+
+// dispatch grants free slots to queued calls, one caller at a time in round-robin order (throttleMu held)
+func (q *providerQueue) dispatch() {
+	for len(q.order) > 0 && (q.limit <= 0 || q.active < q.limit) {
+		caller := q.order[0]
+		q.order = q.order[1:]
+		waiters := q.waiting[caller]
+		w := waiters[0]
+		if len(waiters) > 1 {
+			q.waiting[caller] = waiters[1:]
+			q.order = append(q.order, caller) // Back of the line for its next call
+		} else {
+			delete(q.waiting, caller)
+		}
+
+		w.wait = time.Since(w.queuedAt)
+		q.active++
+		q.calls++
+		q.waited++
+		q.totalWait += w.wait
+		if w.wait > q.maxWait {
+			q.maxWait = w.wait
+		}
+		close(w.ready)
+	}
+}
+
+// SetCaller sets the caller (e.g. trader ID) this client's calls are queued under
+func (client *Client) SetCaller(caller string) {
+	client.caller = caller
+}
+
+// acquireSlot waits for a call slot of the client's provider, returns the function releasing it
+func (client *Client) acquireSlot() func() {
+	release, wait := acquireCallSlot(client.Provider, client.caller)
+	if wait >= slowQueueWait {
+		client.logger.Warnf("⏳ [%s] AI call queued %s for a free %s slot (caller: %s)", client.String(), wait.Round(time.Millisecond), client.Provider, client.caller)
+	} else if wait > 0 {
+		client.logger.Debugf("[%s] AI call queued %s", client.String(), wait.Round(time.Millisecond))
+	}
+	return release
+}
@@ -0,0 +1,160 @@
+package mcp
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// withConcurrencyLimits sets throttle limits for a test, restoring unlimited calls afterwards
+func withConcurrencyLimits(t *testing.T, defaultLimit int, perProvider map[string]int) {
+	t.Helper()
+	SetConcurrencyLimits(defaultLimit, perProvider)
+	t.Cleanup(func() { SetConcurrencyLimits(0, nil) })
+}
+
+// providerStat throttle stats of a provider (zero if it wasn't called yet)
+func providerStat(provider string) ThrottleStat {
+	for _, stat := range ThrottleStats() {
+		if stat.Provider == provider {
+			return stat
+		}
+	}
+	return ThrottleStat{Provider: provider}
+}
+
+// queuedCalls number of calls of the provider waiting for a slot
+func queuedCalls(provider string) int {
+	return providerStat(provider).Queued
+}
+
+// waitQueued waits until the provider has n queued calls
+func waitQueued(t *testing.T, provider string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for queuedCalls(provider) != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d queued calls, got %d", n, queuedCalls(provider))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAcquireCallSlot_EnforcesProviderLimit(t *testing.T) {
+	withConcurrencyLimits(t, 1, map[string]int{"throttle-limit": 3})
+	before := providerStat("throttle-limit")
+
+	var mu sync.Mutex
+	active, maxActive := 0, 0
+	var wg sync.WaitGroup
+	for i := 0; i < 12; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, _ := acquireCallSlot("throttle-limit", "trader")
+			defer release()
+			mu.Lock()
+			active++
+			if active > maxActive {
+				maxActive = active
+			}
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			active--
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if maxActive != 3 {
+		t.Errorf("expected at most 3 calls in flight (per-provider limit), got %d", maxActive)
+	}
+	stat := providerStat("throttle-limit")
+	if stat.Calls-before.Calls != 12 || stat.Active != 0 || stat.Queued != 0 {
+		t.Errorf("unexpected stats after all calls finished: %+v", stat)
+	}
+	if stat.Waited == before.Waited || stat.MaxWaitMs <= 0 {
+		t.Errorf("expected queue wait to be recorded: %+v", stat)
+	}
+}
+
+func TestAcquireCallSlot_RoundRobinAcrossCallers(t *testing.T) {
+	withConcurrencyLimits(t, 1, nil)
+	const provider = "throttle-fair"
+
+	hold, _ := acquireCallSlot(provider, "busy")
+
+	var mu sync.Mutex
+	var served []string
+	var wg sync.WaitGroup
+	queue := func(caller string, n int) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, wait := acquireCallSlot(provider, caller)
+			if wait <= 0 {
+				t.Errorf("call of %s should have queued", caller)
+			}
+			mu.Lock()
+			served = append(served, caller)
+			mu.Unlock()
+			release()
+		}()
+		waitQueued(t, provider, n)
+	}
+	// Trader A queues three calls before trader B queues one
+	queue("A", 1)
+	queue("A", 2)
+	queue("A", 3)
+	queue("B", 4)
+
+	hold()
+	wg.Wait()
+
+	want := []string{"A", "B", "A", "A"}
+	if len(served) != len(want) {
+		t.Fatalf("expected %d calls served, got %v", len(want), served)
+	}
+	for i := range want {
+		if served[i] != want[i] {
+			t.Fatalf("expected round-robin order %v, got %v", want, served)
+		}
+	}
+}
+
+func TestSetConcurrencyLimits_RaisingLimitReleasesQueuedCalls(t *testing.T) {
+	withConcurrencyLimits(t, 1, nil)
+	const provider = "throttle-raise"
+
+	hold, _ := acquireCallSlot(provider, "busy")
+	defer hold()
+
+	done := make(chan struct{})
+	go func() {
+		release, _ := acquireCallSlot(provider, "waiting")
+		release()
+		close(done)
+	}()
+	waitQueued(t, provider, 1)
+
+	SetConcurrencyLimits(2, nil)
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("queued call not released after raising the limit")
+	}
+}
+
+func TestAcquireCallSlot_ReleaseIsIdempotent(t *testing.T) {
+	withConcurrencyLimits(t, 1, nil)
+	const provider = "throttle-idempotent"
+
+	release, _ := acquireCallSlot(provider, "A")
+	release()
+	release()
+
+	if active := providerStat(provider).Active; active != 0 {
+		t.Errorf("expected no active calls after double release, got %d", active)
+	}
+}
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"fmt"
+	"nofx/logger"
+	"nofx/store"
+	"time"
+)
+
+// SendDueDigests sends the daily digest to recipients whose local time has reached hour and
+// who have not received today's digest, returning how many were sent
+// A digest covers the previous local day; a failed send is retried on the next call.
+func (n *Notifier) SendDueDigests(now time.Time, hour int) int {
+	recipients, err := n.store.Notification().ListDigestRecipients()
+	if err != nil {
+		logger.Warnf("⚠️ Daily digest: failed to list recipients: %v", err)
+		return 0
+	}
+
+	sent := 0
+	for _, settings := range recipients {
+		loc := time.UTC
+		if user, err := n.store.User().GetByID(settings.UserID); err == nil {
+			loc = user.Location()
+		}
+		local := now.In(loc)
+		today := local.Format(store.DailyStatsDateLayout)
+		if local.Hour() < hour || settings.LastDigestDate == today {
+			continue
+		}
+
+		if err := n.sendDigest(settings, local.AddDate(0, 0, -1).Format(store.DailyStatsDateLayout)); err != nil {
+			logger.Warnf("⚠️ Daily digest failed for user %s: %v", settings.UserID, err)
+			continue
+		}
+		if err := n.store.Notification().MarkDigestSent(settings.UserID, today); err != nil {
+			logger.Warnf("⚠️ Daily digest: failed to mark sent for user %s: %v", settings.UserID, err)
+		}
+		sent++
+	}
+	return sent
+}
+
+// sendDigest emails the statistics of a user's traders for date
+func (n *Notifier) sendDigest(settings *store.NotificationSettings, date string) error {
+	traders, err := n.store.Trader().List(settings.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to list traders: %w", err)
+	}
+
+	data := digestData{Date: date}
+	for _, t := range traders {
+		stats, err := n.store.DailyStats().List(t.ID, date, date)
+		if err != nil {
+			return fmt.Errorf("failed to get daily statistics of trader %s: %w", t.ID, err)
+		}
+		if len(stats) == 0 {
+			continue
+		}
+		s := stats[0]
+		row := digestRow{
+			Name:           t.Name,
+			Trades:         s.Trades,
+			WinRate:        s.WinRate,
+			NetPnL:         s.PnL - s.Fees,
+			EndEquity:      s.EndEquity,
+			MaxDrawdownPct: s.MaxDrawdownPct,
+		}
+		data.Traders = append(data.Traders, row)
+		data.TotalTrades += row.Trades
+		data.TotalNetPnL += row.NetPnL
+	}
+
+	body, err := render(digestTemplate, data)
+	if err != nil {
+		return err
+	}
+	return n.mailer.Send(settings.Email, fmt.Sprintf("[NOFX] Daily performance %s", date), body)
+}
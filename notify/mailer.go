@@ -0,0 +1,224 @@
+// Package notify notifies users of trader events by email and browser push
+//
+// Users configure an address and the event types they want (store.NotificationSettings);
+// nothing is sent before the address is verified with a code emailed to it. Trader
+// events are queued by the Notifier and sent by its worker, at most one email per
+// trader and event type per throttle window, so a trader failing every cycle does
+// not flood the inbox. Daily digests summarize the previous day's statistics of
+// each of a user's traders, sent once a day at a configured local hour.
+//
+// Position closes, stop losses and trader failures are also pushed to every
+// browser the user subscribed (Web Push), so they arrive with the dashboard closed.
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mailer sends HTML emails
+type Mailer interface {
+	Send(to, subject, htmlBody string) error
+}
+
+// SMTPConfig SMTP server settings
+type SMTPConfig struct {
+	Host     string
+	Port     int // 465 = implicit TLS, otherwise STARTTLS when the server offers it
+	Username string
+	Password string
+	From     string
+}
+
+// smtpTimeout limit for connecting to the SMTP server and delivering one message
+const smtpTimeout = 30 * time.Second
+
+// SMTPMailer Mailer delivering through an SMTP server
+type SMTPMailer struct {
+	cfg SMTPConfig
+}
+
+// NewSMTPMailer creates a mailer for an SMTP server
+func NewSMTPMailer(cfg SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{cfg: cfg}
+}
+
+// Attachment a file attached to an email
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// AttachmentMailer Mailer that can also send files along (performance report PDFs)
+type AttachmentMailer interface {
+	Mailer
+	SendWithAttachments(to, subject, htmlBody string, attachments []Attachment) error
+}
+
+// Send delivers an HTML email to one recipient
+func (m *SMTPMailer) Send(to, subject, htmlBody string) error {
+	msg, err := buildMessage(m.cfg.From, to, subject, htmlBody, time.Now())
+	if err != nil {
+		return err
+	}
+	return m.deliver(to, msg)
+}
+
+// SendWithAttachments delivers an HTML email with attached files to one recipient
+func (m *SMTPMailer) SendWithAttachments(to, subject, htmlBody string, attachments []Attachment) error {
+	msg, err := buildMixedMessage(m.cfg.From, to, subject, htmlBody, attachments, time.Now())
+	if err != nil {
+		return err
+	}
+	return m.deliver(to, msg)
+}
+
+// deliver sends a formatted message through the SMTP server
+func (m *SMTPMailer) deliver(to string, msg []byte) error {
+	var err error
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: m.cfg.Host, MinVersion: tls.VersionTLS12}
+	var conn net.Conn
+	if m.cfg.Port == 465 {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: smtpTimeout}, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = net.DialTimeout("tcp", addr, smtpTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	client, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTP handshake failed: %w", err)
+	}
+	defer client.Close()
+
+	if m.cfg.Port != 465 {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("STARTTLS failed: %w", err)
+			}
+		}
+	}
+	if m.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	if err := client.Mail(m.cfg.From); err != nil {
+		return fmt.Errorf("SMTP sender rejected: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("SMTP recipient rejected: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA failed: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("message rejected: %w", err)
+	}
+	return client.Quit()
+}
+
+// buildMessage formats a quoted-printable HTML message with its headers
+func buildMessage(from, to, subject, htmlBody string, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeHeaders(&buf, from, to, subject, date); err != nil {
+		return nil, err
+	}
+	buf.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	if err := writeQuotedPrintable(&buf, htmlBody); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// buildMixedMessage formats a multipart message of an HTML body and base64 attachments
+func buildMixedMessage(from, to, subject, htmlBody string, attachments []Attachment, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeHeaders(&buf, from, to, subject, date); err != nil {
+		return nil, err
+	}
+	parts := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", parts.Boundary())
+
+	body, err := parts.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := writeQuotedPrintable(body, htmlBody); err != nil {
+		return nil, err
+	}
+
+	for _, a := range attachments {
+		if strings.ContainsAny(a.Filename, "\r\n\"") {
+			return nil, fmt.Errorf("invalid attachment name %q", a.Filename)
+		}
+		part, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {fmt.Sprintf(`attachment; filename="%s"`, a.Filename)},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoded := base64.StdEncoding.EncodeToString(a.Data)
+		for len(encoded) > 76 { // RFC 2045 line length
+			fmt.Fprintf(part, "%s\r\n", encoded[:76])
+			encoded = encoded[76:]
+		}
+		fmt.Fprintf(part, "%s\r\n", encoded)
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeHeaders writes the common headers of a message
+func writeHeaders(buf *bytes.Buffer, from, to, subject string, date time.Time) error {
+	for _, v := range []string{from, to, subject} {
+		if strings.ContainsAny(v, "\r\n") {
+			return fmt.Errorf("invalid email header value %q", v)
+		}
+	}
+	fmt.Fprintf(buf, "From: %s\r\n", from)
+	fmt.Fprintf(buf, "To: %s\r\n", to)
+	fmt.Fprintf(buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	return nil
+}
+
+// writeQuotedPrintable writes an HTML body quoted-printable encoded
+func writeQuotedPrintable(w io.Writer, htmlBody string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(htmlBody)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
@@ -0,0 +1,147 @@
+package notify
+
+import (
+	"fmt"
+	"nofx/logger"
+	"nofx/store"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultThrottle minimum time between emails for the same trader and event type
+	DefaultThrottle = 30 * time.Minute
+	// VerificationCodeTTL how long an address verification code is valid
+	VerificationCodeTTL = 30 * time.Minute
+
+	queueSize = 100
+)
+
+// eventTitles email subjects of the event types users can subscribe to
+var eventTitles = map[string]string{
+	store.TraderEventHalted:          "Trader stopped on error",
+	store.TraderEventCircuitBreaker:  "Circuit breaker tripped",
+	store.TraderEventLiquidationRisk: "Position near liquidation",
+	store.TraderEventCycleTimeout:    "Decision cycles timing out",
+	store.TraderEventError:           "Trader error",
+	store.TraderEventApprovalPending: "Decision awaiting approval",
+	store.TraderEventUnprotected:     "Position without stop loss",
+}
+
+// Alert a trader event to notify the trader's owner of
+type Alert struct {
+	UserID     string
+	TraderID   string
+	TraderName string
+	Type       string
+	Message    string
+	Details    map[string]interface{} // Event details (symbol, side, pnl, ...), may be nil
+	Time       time.Time
+}
+
+// Notifier emails users about trader events and sends daily digests
+type Notifier struct {
+	store    *store.Store
+	mailer   Mailer
+	throttle time.Duration
+	queue    chan Alert
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time // trader_id/event_type -> last queued alert
+}
+
+// NewNotifier creates a notifier sending through mailer
+func NewNotifier(st *store.Store, mailer Mailer) *Notifier {
+	return &Notifier{
+		store:    st,
+		mailer:   mailer,
+		throttle: DefaultThrottle,
+		queue:    make(chan Alert, queueSize),
+		lastSent: make(map[string]time.Time),
+	}
+}
+
+// HandleEvent queues an alert for a trader event, it never blocks
+// Events of types users cannot subscribe to, and repeats within the throttle window, are dropped.
+func (n *Notifier) HandleEvent(alert Alert) {
+	if _, ok := eventTitles[alert.Type]; !ok || alert.UserID == "" {
+		return
+	}
+	if alert.Time.IsZero() {
+		alert.Time = time.Now()
+	}
+
+	key := alert.TraderID + "/" + alert.Type
+	n.mu.Lock()
+	if last, ok := n.lastSent[key]; ok && alert.Time.Sub(last) < n.throttle {
+		n.mu.Unlock()
+		return
+	}
+	n.lastSent[key] = alert.Time
+	n.mu.Unlock()
+
+	select {
+	case n.queue <- alert:
+	default:
+		logger.Warnf("⚠️ Notification queue full, dropping %s alert for trader %s", alert.Type, alert.TraderID)
+	}
+}
+
+// Run sends queued alerts until done is closed
+func (n *Notifier) Run(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case alert := <-n.queue:
+			if err := n.deliver(alert); err != nil {
+				logger.Warnf("⚠️ Failed to email %s alert for trader %s: %v", alert.Type, alert.TraderID, err)
+			}
+		}
+	}
+}
+
+// deliver emails an alert if the user has a verified address subscribed to its type
+func (n *Notifier) deliver(alert Alert) error {
+	settings, err := n.store.Notification().Get(alert.UserID)
+	if err != nil {
+		return err
+	}
+	if !settings.Wants(alert.Type) {
+		return nil
+	}
+
+	loc := time.UTC
+	if user, err := n.store.User().GetByID(alert.UserID); err == nil {
+		loc = user.Location()
+	}
+	title := eventTitles[alert.Type]
+	body, err := render(alertTemplate, alertData{
+		Title:      title,
+		TraderName: alert.TraderName,
+		Message:    alert.Message,
+		Time:       alert.Time.In(loc).Format("2006-01-02 15:04:05 MST"),
+	})
+	if err != nil {
+		return err
+	}
+	return n.mailer.Send(settings.Email, fmt.Sprintf("[NOFX] %s: %s", title, alert.TraderName), body)
+}
+
+// SendVerification emails an address verification code
+func (n *Notifier) SendVerification(to, code string) error {
+	body, err := render(verificationTemplate, verificationData{Code: code, ExpiresMinutes: int(VerificationCodeTTL / time.Minute)})
+	if err != nil {
+		return err
+	}
+	return n.mailer.Send(to, "[NOFX] Verify your notification email", body)
+}
+
+// SendTest emails a test notification
+func (n *Notifier) SendTest(to string) error {
+	body, err := render(testTemplate, nil)
+	if err != nil {
+		return err
+	}
+	return n.mailer.Send(to, "[NOFX] Test notification", body)
+}
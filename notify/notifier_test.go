@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"nofx/store"
+)
+
+type sentMail struct {
+	to, subject, body string
+}
+
+type fakeMailer struct {
+	mu   sync.Mutex
+	sent []sentMail
+}
+
+func (m *fakeMailer) Send(to, subject, htmlBody string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, sentMail{to, subject, htmlBody})
+	return nil
+}
+
+func newTestNotifier(t *testing.T) (*Notifier, *fakeMailer, *store.Store) {
+	t.Helper()
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	mailer := &fakeMailer{}
+	return NewNotifier(st, mailer), mailer, st
+}
+
+func verifyEmail(t *testing.T, st *store.Store, userID, email string) {
+	t.Helper()
+	if err := st.Notification().SetPendingEmail(userID, email, "hash", time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("set email: %v", err)
+	}
+	if ok, err := st.Notification().VerifyEmail(userID, "hash", time.Now()); err != nil || !ok {
+		t.Fatalf("verify email: %v %v", ok, err)
+	}
+}
+
+func TestNotifier_HandleEventThrottlesAndFilters(t *testing.T) {
+	n, mailer, st := newTestNotifier(t)
+	verifyEmail(t, st, "alice", "alice@example.com")
+
+	now := time.Now()
+	n.HandleEvent(Alert{UserID: "alice", TraderID: "t1", TraderName: "Alpha", Type: store.TraderEventHalted, Message: "grid initialization failed", Time: now})
+	n.HandleEvent(Alert{UserID: "alice", TraderID: "t1", TraderName: "Alpha", Type: store.TraderEventHalted, Message: "again", Time: now.Add(time.Minute)})
+	n.HandleEvent(Alert{UserID: "alice", TraderID: "t1", TraderName: "Alpha", Type: store.TraderEventStart, Message: "Trader started", Time: now})
+	n.HandleEvent(Alert{UserID: "alice", TraderID: "t1", TraderName: "Alpha", Type: store.TraderEventError, Message: "not subscribed by default", Time: now})
+	n.HandleEvent(Alert{UserID: "alice", TraderID: "t1", TraderName: "Alpha", Type: store.TraderEventHalted, Message: "after window", Time: now.Add(DefaultThrottle)})
+
+	if len(n.queue) != 3 {
+		t.Fatalf("expected 3 queued alerts (repeat and unsupported type dropped), got %d", len(n.queue))
+	}
+	for len(n.queue) > 0 {
+		if err := n.deliver(<-n.queue); err != nil {
+			t.Fatalf("deliver: %v", err)
+		}
+	}
+	if len(mailer.sent) != 2 {
+		t.Fatalf("expected 2 emails (error events not subscribed), got %d", len(mailer.sent))
+	}
+	mail := mailer.sent[0]
+	if mail.to != "alice@example.com" || !strings.Contains(mail.subject, "Trader stopped on error") || !strings.Contains(mail.body, "grid initialization failed") {
+		t.Errorf("unexpected alert email: %+v", mail)
+	}
+}
+
+func TestNotifier_UnverifiedAddressNotEmailed(t *testing.T) {
+	n, mailer, st := newTestNotifier(t)
+	if err := st.Notification().SetPendingEmail("bob", "bob@example.com", "hash", time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("set email: %v", err)
+	}
+
+	if err := n.deliver(Alert{UserID: "bob", TraderID: "t1", Type: store.TraderEventCircuitBreaker, Time: time.Now()}); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if len(mailer.sent) != 0 {
+		t.Errorf("unverified address should not be emailed, got %d emails", len(mailer.sent))
+	}
+}
+
+func TestNotifier_SendDueDigests(t *testing.T) {
+	n, mailer, st := newTestNotifier(t)
+	verifyEmail(t, st, "alice", "alice@example.com")
+	if err := st.Notification().UpdatePreferences("alice", nil, true); err != nil {
+		t.Fatalf("update preferences: %v", err)
+	}
+	if err := st.Trader().Create(&store.Trader{ID: "t1", UserID: "alice", Name: "Alpha"}); err != nil {
+		t.Fatalf("create trader: %v", err)
+	}
+	if err := st.GormDB().Create(&store.TraderDailyStat{TraderID: "t1", Date: "2026-03-09", Timezone: "UTC", Trades: 4, Wins: 3, Losses: 1, WinRate: 75, PnL: 12.5, Fees: 0.5, EndEquity: 1012}).Error; err != nil {
+		t.Fatalf("save daily stat: %v", err)
+	}
+
+	if sent := n.SendDueDigests(time.Date(2026, 3, 10, 7, 0, 0, 0, time.UTC), 8); sent != 0 {
+		t.Fatalf("digest sent before the digest hour")
+	}
+	if sent := n.SendDueDigests(time.Date(2026, 3, 10, 8, 5, 0, 0, time.UTC), 8); sent != 1 {
+		t.Fatalf("expected 1 digest, got %d", sent)
+	}
+	if sent := n.SendDueDigests(time.Date(2026, 3, 10, 9, 5, 0, 0, time.UTC), 8); sent != 0 {
+		t.Fatalf("digest sent twice on the same day")
+	}
+
+	mail := mailer.sent[0]
+	if !strings.Contains(mail.subject, "2026-03-09") || !strings.Contains(mail.body, "Alpha") || !strings.Contains(mail.body, "12.00") {
+		t.Errorf("unexpected digest: %s\n%s", mail.subject, mail.body)
+	}
+}
+
+func TestBuildMessage_RejectsHeaderInjection(t *testing.T) {
+	if _, err := buildMessage("nofx@example.com", "a@example.com\r\nBcc: b@example.com", "Hi", "<p>x</p>", time.Now()); err == nil {
+		t.Error("recipient with CRLF should be rejected")
+	}
+	msg, err := buildMessage("nofx@example.com", "a@example.com", "Daily performance", "<p>x</p>", time.Now())
+	if err != nil {
+		t.Fatalf("buildMessage: %v", err)
+	}
+	if !strings.Contains(string(msg), "Content-Type: text/html; charset=UTF-8\r\n") {
+		t.Errorf("missing HTML content type:\n%s", msg)
+	}
+}
@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"nofx/logger"
+	"nofx/store"
+	"time"
+)
+
+// pushTitles notification titles of the event types pushed to browsers
+var pushTitles = map[string]string{
+	store.TraderEventPositionClose:   "Position closed",
+	store.TraderEventStopLoss:        "Stop loss triggered",
+	store.TraderEventHalted:          "Trader stopped on error",
+	store.TraderEventCircuitBreaker:  "Circuit breaker tripped",
+	store.TraderEventLiquidationRisk: "Position near liquidation",
+	store.TraderEventApprovalPending: "Decision awaiting approval",
+	store.TraderEventUnprotected:     "Position without stop loss",
+	store.TraderEventKillSwitch:      "Trading halted by operator",
+}
+
+// PushSender delivers a payload to one browser subscription
+type PushSender interface {
+	Send(target PushTarget, payload []byte) error
+}
+
+// pushMessage payload read by the dashboard's service worker
+type pushMessage struct {
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	Tag       string `json:"tag"` // Replaces an earlier notification with the same tag
+	URL       string `json:"url"` // Opened when the notification is clicked
+	TraderID  string `json:"trader_id"`
+	EventType string `json:"event_type"`
+	Timestamp int64  `json:"timestamp"` // Unix ms
+}
+
+// PushDispatcher pushes trader events to every browser subscribed by the trader's owner
+type PushDispatcher struct {
+	store  *store.Store
+	sender PushSender
+	queue  chan Alert
+}
+
+// NewPushDispatcher creates a dispatcher delivering through sender
+func NewPushDispatcher(st *store.Store, sender PushSender) *PushDispatcher {
+	return &PushDispatcher{store: st, sender: sender, queue: make(chan Alert, queueSize)}
+}
+
+// HandleEvent queues a trader event for push delivery, it never blocks
+func (d *PushDispatcher) HandleEvent(alert Alert) {
+	if _, ok := pushTitles[alert.Type]; !ok || alert.UserID == "" {
+		return
+	}
+	if alert.Time.IsZero() {
+		alert.Time = time.Now()
+	}
+	select {
+	case d.queue <- alert:
+	default:
+		logger.Warnf("⚠️ Push queue full, dropping %s notification for trader %s", alert.Type, alert.TraderID)
+	}
+}
+
+// Run pushes queued events until done is closed
+func (d *PushDispatcher) Run(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case alert := <-d.queue:
+			d.deliver(alert)
+		}
+	}
+}
+
+// deliver pushes an event to the user's subscriptions, removing the ones that expired
+func (d *PushDispatcher) deliver(alert Alert) int {
+	subs, err := d.store.PushSubscription().List(alert.UserID)
+	if err != nil {
+		logger.Warnf("⚠️ Failed to list push subscriptions of user %s: %v", alert.UserID, err)
+		return 0
+	}
+	if len(subs) == 0 {
+		return 0
+	}
+
+	title := pushTitles[alert.Type]
+	if alert.TraderName != "" {
+		title += " · " + alert.TraderName
+	}
+	payload, err := json.Marshal(pushMessage{
+		Title:     title,
+		Body:      alert.Message,
+		Tag:       alert.TraderID + "/" + alert.Type,
+		URL:       "/dashboard",
+		TraderID:  alert.TraderID,
+		EventType: alert.Type,
+		Timestamp: alert.Time.UnixMilli(),
+	})
+	if err != nil {
+		return 0
+	}
+
+	sent := 0
+	for _, sub := range subs {
+		err := d.sender.Send(PushTarget{Endpoint: sub.Endpoint, P256dh: sub.P256dh, Auth: sub.Auth}, payload)
+		switch {
+		case err == nil:
+			sent++
+		case errors.Is(err, ErrSubscriptionGone):
+			if err := d.store.PushSubscription().DeleteByEndpoint(sub.Endpoint); err != nil {
+				logger.Warnf("⚠️ Failed to remove expired push subscription: %v", err)
+			}
+		default:
+			logger.Warnf("⚠️ Failed to push %s notification for trader %s: %v", alert.Type, alert.TraderID, err)
+		}
+	}
+	return sent
+}
+
+// SendTest pushes a test notification to one of a user's subscriptions
+func (d *PushDispatcher) SendTest(sub *store.PushSubscription) error {
+	payload, _ := json.Marshal(pushMessage{
+		Title:     "NOFX",
+		Body:      "Push notifications are working.",
+		Tag:       "test",
+		URL:       "/",
+		EventType: "test",
+		Timestamp: time.Now().UnixMilli(),
+	})
+	return d.sender.Send(PushTarget{Endpoint: sub.Endpoint, P256dh: sub.P256dh, Auth: sub.Auth}, payload)
+}
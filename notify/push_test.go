@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"nofx/store"
+)
+
+// decryptPushPayload decrypts an aes128gcm body as the browser would
+func decryptPushPayload(t *testing.T, uaPrivate *ecdh.PrivateKey, authSecret, body []byte) []byte {
+	t.Helper()
+	salt, rs, idLen := body[:16], binary.BigEndian.Uint32(body[16:20]), int(body[20])
+	if rs != pushRecordSize || idLen != 65 {
+		t.Fatalf("unexpected header: rs=%d idlen=%d", rs, idLen)
+	}
+	asPublic, err := ecdh.P256().NewPublicKey(body[21 : 21+idLen])
+	if err != nil {
+		t.Fatalf("sender key: %v", err)
+	}
+	shared, err := uaPrivate.ECDH(asPublic)
+	if err != nil {
+		t.Fatalf("ecdh: %v", err)
+	}
+	keyInfo := "WebPush: info\x00" + string(uaPrivate.PublicKey().Bytes()) + string(asPublic.Bytes())
+	ikm, _ := hkdf.Key(sha256.New, shared, authSecret, keyInfo, 32)
+	cek, _ := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	nonce, _ := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	plain, err := gcm.Open(nil, nonce, body[21+idLen:], nil)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if plain[len(plain)-1] != 0x02 {
+		t.Fatalf("missing last record delimiter")
+	}
+	return plain[:len(plain)-1]
+}
+
+func TestEncryptPushPayload_RoundTrip(t *testing.T) {
+	uaPrivate, _ := ecdh.P256().GenerateKey(rand.Reader)
+	authSecret := make([]byte, 16)
+	rand.Read(authSecret)
+	target := PushTarget{
+		Endpoint: "https://push.example.com/abc",
+		P256dh:   base64.RawURLEncoding.EncodeToString(uaPrivate.PublicKey().Bytes()),
+		Auth:     base64.URLEncoding.EncodeToString(authSecret), // padded, as some browsers send it
+	}
+
+	body, err := encryptPushPayload(target, []byte(`{"title":"hi"}`))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if got := decryptPushPayload(t, uaPrivate, authSecret, body); string(got) != `{"title":"hi"}` {
+		t.Errorf("round trip = %q", got)
+	}
+}
+
+type fakePushSender struct {
+	payloads map[string][]byte
+	gone     map[string]bool
+}
+
+func (f *fakePushSender) Send(target PushTarget, payload []byte) error {
+	if f.gone[target.Endpoint] {
+		return ErrSubscriptionGone
+	}
+	f.payloads[target.Endpoint] = payload
+	return nil
+}
+
+func TestPushDispatcher_DeliverRemovesExpired(t *testing.T) {
+	_, _, st := newTestNotifier(t)
+	for _, endpoint := range []string{"https://push.example.com/a", "https://push.example.com/b"} {
+		if err := st.PushSubscription().Save(&store.PushSubscription{UserID: "alice", Endpoint: endpoint, P256dh: "k", Auth: "a"}); err != nil {
+			t.Fatalf("save subscription: %v", err)
+		}
+	}
+	sender := &fakePushSender{payloads: map[string][]byte{}, gone: map[string]bool{"https://push.example.com/b": true}}
+	d := NewPushDispatcher(st, sender)
+
+	d.HandleEvent(Alert{UserID: "alice", TraderID: "t1", Type: store.TraderEventDecision})
+	if len(d.queue) != 0 {
+		t.Fatalf("decision events should not be pushed")
+	}
+	sent := d.deliver(Alert{UserID: "alice", TraderID: "t1", TraderName: "Alpha", Type: store.TraderEventStopLoss, Message: "BTCUSDT long stop loss triggered", Time: time.Now()})
+	if sent != 1 {
+		t.Fatalf("expected 1 push, got %d", sent)
+	}
+
+	var msg pushMessage
+	if err := json.Unmarshal(sender.payloads["https://push.example.com/a"], &msg); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if msg.Title != "Stop loss triggered · Alpha" || msg.EventType != store.TraderEventStopLoss {
+		t.Errorf("unexpected payload: %+v", msg)
+	}
+	subs, _ := st.PushSubscription().List("alice")
+	if len(subs) != 1 || subs[0].Endpoint != "https://push.example.com/a" {
+		t.Errorf("expired subscription should be removed, got %d subscriptions", len(subs))
+	}
+}
@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"bytes"
+	"html/template"
+)
+
+const layoutHTML = `{{define "layout"}}<!DOCTYPE html>
+<html><body style="margin:0;padding:24px;background:#0b0e11;font-family:Arial,sans-serif;color:#eaecef">
+<div style="max-width:600px;margin:0 auto;background:#1e2329;border-radius:8px;padding:24px">
+<h2 style="margin:0 0 16px;color:#f0b90b">NOFX</h2>
+{{template "content" .}}
+<p style="margin:24px 0 0;font-size:12px;color:#848e9c">You receive this email because notifications are enabled for your NOFX account.</p>
+</div></body></html>{{end}}`
+
+var (
+	alertTemplate = template.Must(template.Must(template.New("alert").Parse(layoutHTML)).Parse(`{{define "content"}}
+<h3 style="margin:0 0 8px">{{.Title}}</h3>
+<p style="margin:0 0 8px;color:#848e9c">Trader <b style="color:#eaecef">{{.TraderName}}</b> · {{.Time}}</p>
+<p style="margin:0;padding:12px;background:#2b3139;border-radius:4px">{{.Message}}</p>
+{{end}}`))
+
+	verificationTemplate = template.Must(template.Must(template.New("verification").Parse(layoutHTML)).Parse(`{{define "content"}}
+<p>Enter this code to verify your notification email address:</p>
+<p style="font-size:28px;letter-spacing:6px;font-weight:bold;color:#f0b90b">{{.Code}}</p>
+<p style="color:#848e9c">The code expires in {{.ExpiresMinutes}} minutes. If you did not request it, ignore this email.</p>
+{{end}}`))
+
+	testTemplate = template.Must(template.Must(template.New("test").Parse(layoutHTML)).Parse(`{{define "content"}}
+<p>This is a test notification. Email notifications are working.</p>
+{{end}}`))
+
+	digestTemplate = template.Must(template.Must(template.New("digest").Parse(layoutHTML)).Parse(`{{define "content"}}
+<h3 style="margin:0 0 16px">Daily performance · {{.Date}}</h3>
+{{if .Traders}}
+<table style="width:100%;border-collapse:collapse;font-size:14px">
+<tr style="color:#848e9c;text-align:right"><th style="text-align:left;padding:6px">Trader</th><th style="padding:6px">Trades</th><th style="padding:6px">Win rate</th><th style="padding:6px">Net PnL</th><th style="padding:6px">Equity</th><th style="padding:6px">Max DD</th></tr>
+{{range .Traders}}<tr style="text-align:right;border-top:1px solid #2b3139">
+<td style="text-align:left;padding:6px">{{.Name}}</td>
+<td style="padding:6px">{{.Trades}}</td>
+<td style="padding:6px">{{printf "%.1f" .WinRate}}%</td>
+<td style="padding:6px;color:{{if lt .NetPnL 0.0}}#f6465d{{else}}#0ecb81{{end}}">{{printf "%+.2f" .NetPnL}}</td>
+<td style="padding:6px">{{printf "%.2f" .EndEquity}}</td>
+<td style="padding:6px">{{printf "%.2f" .MaxDrawdownPct}}%</td>
+</tr>{{end}}
+<tr style="text-align:right;border-top:2px solid #2b3139;font-weight:bold">
+<td style="text-align:left;padding:6px">Total</td><td style="padding:6px">{{.TotalTrades}}</td><td></td>
+<td style="padding:6px;color:{{if lt .TotalNetPnL 0.0}}#f6465d{{else}}#0ecb81{{end}}">{{printf "%+.2f" .TotalNetPnL}}</td><td></td><td></td>
+</tr>
+</table>
+{{else}}<p style="color:#848e9c">No trader activity on this day.</p>{{end}}
+{{end}}`))
+)
+
+// alertData data of an event alert email
+type alertData struct {
+	Title      string
+	TraderName string
+	Message    string
+	Time       string
+}
+
+// verificationData data of an address verification email
+type verificationData struct {
+	Code           string
+	ExpiresMinutes int
+}
+
+// digestData data of a daily digest email
+type digestData struct {
+	Date        string
+	Traders     []digestRow
+	TotalTrades int
+	TotalNetPnL float64
+}
+
+// digestRow one trader's statistics for the day
+type digestRow struct {
+	Name           string
+	Trades         int
+	WinRate        float64
+	NetPnL         float64 // Realized PnL after fees
+	EndEquity      float64
+	MaxDrawdownPct float64
+}
+
+func render(t *template.Template, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, "layout", data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
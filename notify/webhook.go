@@ -0,0 +1,304 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"nofx/logger"
+	"nofx/security"
+	"nofx/store"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	// webhookTimeout time a webhook endpoint has to answer
+	webhookTimeout = 10 * time.Second
+	// maxWebhookPayload largest rendered payload sent
+	maxWebhookPayload = 64 << 10
+)
+
+// DefaultWebhookTemplate payload sent by webhooks without a template
+const DefaultWebhookTemplate = `{"event":"{{.event}}","trader":"{{.trader}}","trader_id":"{{.trader_id}}","symbol":"{{.symbol}}","action":"{{.action}}","pnl":{{.pnl}},"message":"{{.message}}","timestamp":{{.timestamp}}}`
+
+// WebhookVariables variables available in webhook templates
+// String variables are JSON-escaped so they can be placed inside quotes; details is the raw event
+// details object, rendered with {{json .details}}.
+var WebhookVariables = []string{"event", "trader", "trader_id", "message", "symbol", "side", "action", "pnl", "pnl_pct", "price", "time", "timestamp", "details"}
+
+// Webhook request headers
+const (
+	WebhookSignatureHeader = "X-NOFX-Signature" // sha256=<hex HMAC-SHA256 of "<timestamp>.<body>">
+	WebhookTimestampHeader = "X-NOFX-Timestamp" // Unix seconds, reject old deliveries to prevent replays
+	WebhookEventHeader     = "X-NOFX-Event"
+)
+
+// WebhookDispatcher POSTs trader events to the webhooks defined by the trader's owner
+type WebhookDispatcher struct {
+	store       *store.Store
+	client      *http.Client
+	validateURL func(rawURL string) error
+	queue       chan Alert
+}
+
+// NewWebhookDispatcher creates a dispatcher; unless allowPrivate, webhook URLs on private or
+// loopback addresses are refused
+func NewWebhookDispatcher(st *store.Store, allowPrivate bool) *WebhookDispatcher {
+	d := &WebhookDispatcher{
+		store:       st,
+		client:      security.SafeHTTPClient(webhookTimeout),
+		validateURL: security.ValidateURL,
+		queue:       make(chan Alert, queueSize),
+	}
+	if allowPrivate {
+		d.client = &http.Client{Timeout: webhookTimeout}
+		d.validateURL = validateWebhookScheme
+	}
+	return d
+}
+
+// validateWebhookScheme accepts any absolute http(s) URL
+func validateWebhookScheme(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL, expected http(s)://host/...")
+	}
+	return nil
+}
+
+// ValidateURL checks that a webhook URL may be called from this server
+func (d *WebhookDispatcher) ValidateURL(rawURL string) error {
+	return d.validateURL(rawURL)
+}
+
+// HandleEvent queues a trader event for webhook delivery, it never blocks
+func (d *WebhookDispatcher) HandleEvent(alert Alert) {
+	if alert.UserID == "" {
+		return
+	}
+	if alert.Time.IsZero() {
+		alert.Time = time.Now()
+	}
+	select {
+	case d.queue <- alert:
+	default:
+		logger.Warnf("⚠️ Webhook queue full, dropping %s event for trader %s", alert.Type, alert.TraderID)
+	}
+}
+
+// Run delivers queued events until done is closed
+func (d *WebhookDispatcher) Run(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case alert := <-d.queue:
+			d.deliver(alert)
+		}
+	}
+}
+
+// deliver sends an event to each of the user's webhooks subscribed to it, returning how many accepted it
+func (d *WebhookDispatcher) deliver(alert Alert) int {
+	hooks, err := d.store.Webhook().List(alert.UserID)
+	if err != nil {
+		logger.Warnf("⚠️ Failed to list webhooks of user %s: %v", alert.UserID, err)
+		return 0
+	}
+	sent := 0
+	for _, hook := range hooks {
+		if !hook.Wants(alert.TraderID, alert.Type) {
+			continue
+		}
+		if _, err := d.Send(hook, alert); err != nil {
+			logger.Warnf("⚠️ Webhook %d (%s) failed for %s event of trader %s: %v", hook.ID, hook.Name, alert.Type, alert.TraderID, err)
+			continue
+		}
+		sent++
+	}
+	return sent
+}
+
+// Send renders, signs and POSTs an event to a webhook and records the outcome, returning the HTTP status
+func (d *WebhookDispatcher) Send(hook *store.Webhook, alert Alert) (int, error) {
+	status, err := d.post(hook, alert)
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+	}
+	if recErr := d.store.Webhook().RecordDelivery(hook.ID, status, errMsg, time.Now()); recErr != nil {
+		logger.Warnf("⚠️ Failed to record delivery of webhook %d: %v", hook.ID, recErr)
+	}
+	return status, err
+}
+
+// SendTest sends a sample position close event to a webhook
+func (d *WebhookDispatcher) SendTest(hook *store.Webhook) (int, error) {
+	return d.Send(hook, SampleWebhookAlert(hook.UserID))
+}
+
+func (d *WebhookDispatcher) post(hook *store.Webhook, alert Alert) (int, error) {
+	if err := d.validateURL(hook.URL); err != nil {
+		return 0, err
+	}
+	body, err := RenderWebhookPayload(hook.Template, alert)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "NOFX-Webhook/1.0")
+	req.Header.Set(WebhookEventHeader, alert.Type)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	if secret := string(hook.Secret); secret != "" {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookPayload(secret, timestamp, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload hex HMAC-SHA256 of "<timestamp>.<body>", as receivers should compute it
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ParseWebhookTemplate parses a webhook template, the default one when empty
+func ParseWebhookTemplate(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		text = DefaultWebhookTemplate
+	}
+	return template.New("webhook").Option("missingkey=error").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(text)
+}
+
+// RenderWebhookPayload renders an event with a webhook template, the result must be valid JSON
+func RenderWebhookPayload(text string, alert Alert) ([]byte, error) {
+	tmpl, err := ParseWebhookTemplate(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, webhookData(alert)); err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	if buf.Len() > maxWebhookPayload {
+		return nil, fmt.Errorf("rendered payload exceeds %d bytes", maxWebhookPayload)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("template does not render valid JSON")
+	}
+	return buf.Bytes(), nil
+}
+
+// SampleWebhookAlert event used to test webhooks and validate templates
+func SampleWebhookAlert(userID string) Alert {
+	return Alert{
+		UserID:     userID,
+		TraderID:   "test",
+		TraderName: "Test trader",
+		Type:       store.TraderEventPositionClose,
+		Message:    `BTCUSDT long closed at 65000.0000 (+2.50%) "test"`,
+		Time:       time.Now(),
+		Details: map[string]interface{}{
+			"symbol":      "BTCUSDT",
+			"side":        "long",
+			"entry_price": 63414.63,
+			"exit_price":  65000.0,
+			"pnl":         15.85,
+			"pnl_pct":     2.5,
+			"reason":      "take_profit",
+		},
+	}
+}
+
+// webhookData template variables of an event
+func webhookData(alert Alert) map[string]interface{} {
+	details := alert.Details
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	symbol, side := detailString(details, "symbol"), detailString(details, "side")
+	action := detailString(details, "action")
+	if action == "" {
+		action = alert.Type
+		if (alert.Type == store.TraderEventPositionClose || alert.Type == store.TraderEventStopLoss) && side != "" {
+			action = "close_" + side
+		}
+	}
+	price := detailNumber(details, "exit_price")
+	if price == 0 {
+		price = detailNumber(details, "price")
+	}
+
+	return map[string]interface{}{
+		"event":     jsonEscape(alert.Type),
+		"trader":    jsonEscape(alert.TraderName),
+		"trader_id": jsonEscape(alert.TraderID),
+		"message":   jsonEscape(alert.Message),
+		"symbol":    jsonEscape(symbol),
+		"side":      jsonEscape(side),
+		"action":    jsonEscape(action),
+		"pnl":       detailNumber(details, "pnl"),
+		"pnl_pct":   detailNumber(details, "pnl_pct"),
+		"price":     price,
+		"time":      alert.Time.UTC().Format(time.RFC3339),
+		"timestamp": alert.Time.UnixMilli(),
+		"details":   details,
+	}
+}
+
+// jsonEscape a string escaped for use inside a JSON string literal
+func jsonEscape(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data[1 : len(data)-1])
+}
+
+func detailString(details map[string]interface{}, key string) string {
+	if v, ok := details[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func detailNumber(details map[string]interface{}, key string) float64 {
+	switch v := details[key].(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
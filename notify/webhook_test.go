@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nofx/crypto"
+	"nofx/store"
+)
+
+func TestRenderWebhookPayload(t *testing.T) {
+	alert := SampleWebhookAlert("alice")
+
+	body, err := RenderWebhookPayload("", alert)
+	if err != nil {
+		t.Fatalf("default template: %v", err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	// The sample message contains quotes, they must be escaped
+	if payload["message"] != alert.Message || payload["action"] != "close_long" || payload["pnl"] != 15.85 {
+		t.Errorf("unexpected payload: %v", payload)
+	}
+
+	body, err = RenderWebhookPayload(`{"text":"{{.trader}}: {{.action}} {{.symbol}} {{.pnl}}","extra":{{json .details}}}`, alert)
+	if err != nil {
+		t.Fatalf("custom template: %v", err)
+	}
+	if !strings.Contains(string(body), `"text":"Test trader: close_long BTCUSDT 15.85"`) || !strings.Contains(string(body), `"reason":"take_profit"`) {
+		t.Errorf("unexpected payload: %s", body)
+	}
+
+	for _, tmpl := range []string{`{"a":{{.unknown}}}`, `{"a":"{{.symbol}}"`, `{{`} {
+		if _, err := RenderWebhookPayload(tmpl, alert); err == nil {
+			t.Errorf("template %q should be rejected", tmpl)
+		}
+	}
+}
+
+func TestWebhookDispatcher_DeliverSigned(t *testing.T) {
+	_, _, st := newTestNotifier(t)
+
+	var gotBody []byte
+	var gotHeader http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotHeader = r.Header
+	}))
+	defer server.Close()
+
+	hooks := []*store.Webhook{
+		{UserID: "alice", Name: "n8n", URL: server.URL, Secret: crypto.EncryptedString("s3cret"), Enabled: true},
+		{UserID: "alice", Name: "other trader", URL: server.URL, TraderID: "t2", Enabled: true},
+		{UserID: "alice", Name: "decisions only", URL: server.URL, Events: store.TraderEventDecision, Enabled: true},
+	}
+	for _, hook := range hooks {
+		if err := st.Webhook().Create(hook); err != nil {
+			t.Fatalf("create webhook: %v", err)
+		}
+	}
+
+	// httptest listens on loopback
+	d := NewWebhookDispatcher(st, true)
+	alert := Alert{UserID: "alice", TraderID: "t1", TraderName: "Alpha", Type: store.TraderEventStopLoss, Message: "stopped",
+		Details: map[string]interface{}{"symbol": "ETHUSDT", "side": "short", "pnl": -12.5}, Time: time.Now()}
+	if sent := d.deliver(alert); sent != 1 {
+		t.Fatalf("expected 1 delivery, got %d", sent)
+	}
+
+	timestamp := gotHeader.Get(WebhookTimestampHeader)
+	if want := "sha256=" + SignWebhookPayload("s3cret", timestamp, gotBody); gotHeader.Get(WebhookSignatureHeader) != want {
+		t.Errorf("signature = %q, want %q", gotHeader.Get(WebhookSignatureHeader), want)
+	}
+	if gotHeader.Get(WebhookEventHeader) != store.TraderEventStopLoss || !strings.Contains(string(gotBody), `"action":"close_short"`) {
+		t.Errorf("unexpected delivery: %v %s", gotHeader, gotBody)
+	}
+
+	stored, _ := st.Webhook().Get("alice", hooks[0].ID)
+	if stored.LastStatus != http.StatusOK || stored.LastSentAt == nil || string(stored.Secret) != "s3cret" {
+		t.Errorf("delivery not recorded: %+v", stored)
+	}
+}
+
+func TestWebhookDispatcher_RefusesPrivateURLs(t *testing.T) {
+	_, _, st := newTestNotifier(t)
+	d := NewWebhookDispatcher(st, false)
+	for _, u := range []string{"http://127.0.0.1:5678/webhook", "http://localhost/hook", "ftp://example.com/x"} {
+		if err := d.ValidateURL(u); err == nil {
+			t.Errorf("%s should be refused", u)
+		}
+	}
+}
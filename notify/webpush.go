@@ -0,0 +1,183 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ============================================================================
+// Web Push (RFC 8030) with VAPID (RFC 8292) and aes128gcm payloads (RFC 8291)
+// ============================================================================
+// A browser subscription is a push service URL plus the browser's P-256
+// public key and authentication secret. Each message is encrypted for the
+// browser with a fresh ECDH key, and the request is signed with the server's
+// VAPID key so the push service accepts it for subscriptions made with the
+// matching public key.
+// ============================================================================
+
+// ErrSubscriptionGone the push service no longer knows the subscription (expired or unsubscribed)
+var ErrSubscriptionGone = errors.New("push subscription expired")
+
+const (
+	pushRecordSize = 4096
+	pushMessageTTL = 24 * time.Hour
+	vapidTokenTTL  = 12 * time.Hour
+)
+
+// PushTarget a browser subscription to deliver to
+type PushTarget struct {
+	Endpoint string
+	P256dh   string // base64url
+	Auth     string // base64url
+}
+
+// WebPushSender delivers encrypted Web Push messages signed with a VAPID key
+type WebPushSender struct {
+	key     *ecdsa.PrivateKey
+	subject string // Contact for push services, mailto: or https: URL
+	client  *http.Client
+}
+
+// NewWebPushSender creates a sender signing with key
+func NewWebPushSender(key *ecdsa.PrivateKey, subject string) *WebPushSender {
+	return &WebPushSender{key: key, subject: subject, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// PublicKey the VAPID public key browsers subscribe with (applicationServerKey), base64url
+func (w *WebPushSender) PublicKey() string {
+	raw, _ := w.key.PublicKey.Bytes()
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Send delivers payload to a subscription, ErrSubscriptionGone when it has expired
+func (w *WebPushSender) Send(target PushTarget, payload []byte) error {
+	body, err := encryptPushPayload(target, payload)
+	if err != nil {
+		return err
+	}
+	endpoint, err := url.Parse(target.Endpoint)
+	if err != nil || endpoint.Scheme != "https" {
+		return fmt.Errorf("invalid push endpoint")
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": endpoint.Scheme + "://" + endpoint.Host,
+		"exp": time.Now().Add(vapidTokenTTL).Unix(),
+		"sub": w.subject,
+	}).SignedString(w.key)
+	if err != nil {
+		return fmt.Errorf("failed to sign VAPID token: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, target.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "vapid t="+token+", k="+w.PublicKey())
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", fmt.Sprintf("%d", int(pushMessageTTL.Seconds())))
+	req.Header.Set("Urgency", "high")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("push request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrSubscriptionGone
+	case resp.StatusCode >= 300:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("push service returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// encryptPushPayload encrypts payload for a browser as a single aes128gcm record
+func encryptPushPayload(target PushTarget, payload []byte) ([]byte, error) {
+	uaPublicRaw, err := decodeBase64URL(target.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subscription key: %w", err)
+	}
+	authSecret, err := decodeBase64URL(target.Auth)
+	if err != nil || len(authSecret) == 0 {
+		return nil, fmt.Errorf("invalid subscription auth secret")
+	}
+	if len(payload)+1+16 > pushRecordSize {
+		return nil, fmt.Errorf("push payload too large (%d bytes)", len(payload))
+	}
+
+	uaPublic, err := ecdh.P256().NewPublicKey(uaPublicRaw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subscription key: %w", err)
+	}
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	sharedSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+	asPublicRaw := asPrivate.PublicKey().Bytes()
+
+	keyInfo := "WebPush: info\x00" + string(uaPublicRaw) + string(asPublicRaw)
+	ikm, err := hkdf.Key(sha256.New, sharedSecret, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	// 0x02 delimits the last (only) record
+	ciphertext := gcm.Seal(nil, nonce, append(append([]byte{}, payload...), 0x02), nil)
+
+	var buf bytes.Buffer
+	buf.Write(salt)
+	_ = binary.Write(&buf, binary.BigEndian, uint32(pushRecordSize))
+	buf.WriteByte(byte(len(asPublicRaw)))
+	buf.Write(asPublicRaw)
+	buf.Write(ciphertext)
+	return buf.Bytes(), nil
+}
+
+// decodeBase64URL decodes base64url with or without padding, as browsers send either
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
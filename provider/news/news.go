@@ -0,0 +1,215 @@
+package news
+
+import (
+	"nofx/logger"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// News & Economic Calendar Context
+// ============================================================================
+// Crypto headlines and macro calendar events (CPI, FOMC, NFP...) are pulled
+// from pluggable sources on a schedule and cached, so decision cycles read a
+// snapshot instead of calling the sources themselves. A failing source keeps
+// its last good items until the next refresh succeeds.
+// ============================================================================
+
+// Event impact levels
+const (
+	ImpactHigh   = "high"
+	ImpactMedium = "medium"
+	ImpactLow    = "low"
+)
+
+// maxHeadlines headlines kept per refresh (newest first)
+const maxHeadlines = 50
+
+// calendarHorizon calendar events further away than this are dropped
+const calendarHorizon = 7 * 24 * time.Hour
+
+// Headline a news headline
+type Headline struct {
+	Title       string    `json:"title"`
+	Source      string    `json:"source"`
+	URL         string    `json:"url,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// CalendarEvent a scheduled macro economic event
+type CalendarEvent struct {
+	Title    string    `json:"title"`
+	Country  string    `json:"country"` // Currency / region code, e.g. USD
+	Impact   string    `json:"impact"`  // high, medium, low
+	Time     time.Time `json:"time"`
+	Forecast string    `json:"forecast,omitempty"`
+	Previous string    `json:"previous,omitempty"`
+}
+
+// HeadlineSource a provider of news headlines
+type HeadlineSource interface {
+	Name() string
+	FetchHeadlines() ([]Headline, error)
+}
+
+// CalendarSource a provider of economic calendar events
+type CalendarSource interface {
+	Name() string
+	FetchEvents() ([]CalendarEvent, error)
+}
+
+// Snapshot cached headlines and calendar events
+type Snapshot struct {
+	Headlines []Headline      `json:"headlines"` // Newest first
+	Events    []CalendarEvent `json:"events"`    // Soonest first
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// HighImpactNear returns the first high-impact event within window before or after now, nil if none
+func (s *Snapshot) HighImpactNear(now time.Time, window time.Duration) *CalendarEvent {
+	if s == nil || window <= 0 {
+		return nil
+	}
+	for i := range s.Events {
+		ev := &s.Events[i]
+		if ev.Impact != ImpactHigh {
+			continue
+		}
+		if !ev.Time.Before(now.Add(-window)) && !ev.Time.After(now.Add(window)) {
+			return ev
+		}
+	}
+	return nil
+}
+
+// Service refreshes and caches headlines and calendar events
+type Service struct {
+	interval  time.Duration
+	headlines []HeadlineSource
+	calendars []CalendarSource
+
+	mu             sync.RWMutex
+	itemsBySource  map[string][]Headline
+	eventsBySource map[string][]CalendarEvent
+	updatedAt      time.Time
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService creates a news service refreshing its sources every interval
+func NewService(interval time.Duration) *Service {
+	return &Service{
+		interval:       interval,
+		itemsBySource:  make(map[string][]Headline),
+		eventsBySource: make(map[string][]CalendarEvent),
+		stopCh:         make(chan struct{}),
+	}
+}
+
+// AddHeadlineSource registers a headline source, call before Start
+func (s *Service) AddHeadlineSource(src HeadlineSource) {
+	s.headlines = append(s.headlines, src)
+}
+
+// AddCalendarSource registers a calendar source, call before Start
+func (s *Service) AddCalendarSource(src CalendarSource) {
+	s.calendars = append(s.calendars, src)
+}
+
+// HasSources reports whether any source is registered
+func (s *Service) HasSources() bool {
+	return len(s.headlines) > 0 || len(s.calendars) > 0
+}
+
+// Start loads all sources and refreshes them in the background
+func (s *Service) Start() {
+	go func() {
+		s.refresh()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.refresh()
+			case <-s.stopCh:
+				return
+			}
+		}
+	}()
+	logger.Infof("📰 News service started: %d headline and %d calendar sources (refresh every %v)",
+		len(s.headlines), len(s.calendars), s.interval)
+}
+
+// Stop stops the scheduled refresh
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// refresh fetches every source, keeping the previous items of sources that fail
+func (s *Service) refresh() {
+	for _, src := range s.headlines {
+		items, err := src.FetchHeadlines()
+		if err != nil {
+			logger.Warnf("⚠️ News source %s: %v", src.Name(), err)
+			continue
+		}
+		s.mu.Lock()
+		s.itemsBySource[src.Name()] = items
+		s.mu.Unlock()
+	}
+	for _, src := range s.calendars {
+		events, err := src.FetchEvents()
+		if err != nil {
+			logger.Warnf("⚠️ Economic calendar source %s: %v", src.Name(), err)
+			continue
+		}
+		s.mu.Lock()
+		s.eventsBySource[src.Name()] = events
+		s.mu.Unlock()
+	}
+	s.mu.Lock()
+	s.updatedAt = time.Now()
+	s.mu.Unlock()
+}
+
+// Snapshot returns the cached headlines (newest first, deduplicated by title) and
+// the calendar events from the past hour up to a week ahead (soonest first)
+func (s *Service) Snapshot() *Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snap := &Snapshot{UpdatedAt: s.updatedAt}
+	seen := make(map[string]bool)
+	for _, items := range s.itemsBySource {
+		for _, h := range items {
+			key := strings.ToLower(strings.TrimSpace(h.Title))
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			snap.Headlines = append(snap.Headlines, h)
+		}
+	}
+	sort.Slice(snap.Headlines, func(i, j int) bool {
+		return snap.Headlines[i].PublishedAt.After(snap.Headlines[j].PublishedAt)
+	})
+	if len(snap.Headlines) > maxHeadlines {
+		snap.Headlines = snap.Headlines[:maxHeadlines]
+	}
+
+	now := time.Now()
+	for _, events := range s.eventsBySource {
+		for _, ev := range events {
+			if ev.Time.After(now.Add(-time.Hour)) && ev.Time.Before(now.Add(calendarHorizon)) {
+				snap.Events = append(snap.Events, ev)
+			}
+		}
+	}
+	sort.Slice(snap.Events, func(i, j int) bool {
+		return snap.Events[i].Time.Before(snap.Events[j].Time)
+	})
+	return snap
+}
@@ -0,0 +1,119 @@
+package news
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseFeed(t *testing.T) {
+	rss := `<?xml version="1.0"?><rss version="2.0"><channel>
+		<item><title> ETF inflows hit record </title><link>https://example.com/a</link><pubDate>Tue, 13 Oct 2026 08:00:00 +0000</pubDate></item>
+		<item><title></title><link>https://example.com/empty</link></item>
+	</channel></rss>`
+	headlines, err := parseFeed("example.com", []byte(rss))
+	if err != nil {
+		t.Fatalf("parseFeed rss: %v", err)
+	}
+	if len(headlines) != 1 || headlines[0].Title != "ETF inflows hit record" || headlines[0].URL != "https://example.com/a" {
+		t.Fatalf("unexpected rss headlines: %+v", headlines)
+	}
+	if want := time.Date(2026, 10, 13, 8, 0, 0, 0, time.UTC); !headlines[0].PublishedAt.Equal(want) {
+		t.Errorf("expected published at %v, got %v", want, headlines[0].PublishedAt)
+	}
+
+	atom := `<feed xmlns="http://www.w3.org/2005/Atom">
+		<entry><title>Exchange outage</title><link rel="alternate" href="https://example.com/b"/><updated>2026-10-13T09:30:00Z</updated></entry>
+	</feed>`
+	headlines, err = parseFeed("example.com", []byte(atom))
+	if err != nil {
+		t.Fatalf("parseFeed atom: %v", err)
+	}
+	if len(headlines) != 1 || headlines[0].URL != "https://example.com/b" || headlines[0].PublishedAt.IsZero() {
+		t.Fatalf("unexpected atom headlines: %+v", headlines)
+	}
+}
+
+func TestParseCalendar(t *testing.T) {
+	body := `[
+		{"title":"CPI m/m","country":"USD","date":"2026-10-14T08:30:00-04:00","impact":"High","forecast":"0.3%","previous":"0.2%"},
+		{"title":"Bank Holiday","country":"USD","date":"2026-10-12T00:00:00-04:00","impact":"Holiday"},
+		{"title":"GDP q/q","country":"EUR","date":"2026-10-14T05:00:00-04:00","impact":"Medium"}
+	]`
+	events, err := parseCalendar([]byte(body), map[string]bool{"USD": true})
+	if err != nil {
+		t.Fatalf("parseCalendar: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected only the USD CPI event, got %+v", events)
+	}
+	if ev := events[0]; ev.Impact != ImpactHigh || ev.Forecast != "0.3%" || ev.Time.UTC().Hour() != 12 {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+}
+
+func TestHighImpactNear(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	snap := &Snapshot{Events: []CalendarEvent{
+		{Title: "Retail Sales", Impact: ImpactMedium, Time: now.Add(10 * time.Minute)},
+		{Title: "FOMC Statement", Impact: ImpactHigh, Time: now.Add(25 * time.Minute)},
+	}}
+
+	if ev := snap.HighImpactNear(now, 30*time.Minute); ev == nil || ev.Title != "FOMC Statement" {
+		t.Errorf("expected FOMC within 30 min, got %+v", ev)
+	}
+	if ev := snap.HighImpactNear(now, 20*time.Minute); ev != nil {
+		t.Errorf("expected no high-impact event within 20 min, got %+v", ev)
+	}
+	if ev := snap.HighImpactNear(now.Add(50*time.Minute), 30*time.Minute); ev == nil {
+		t.Error("window must also cover the minutes after the event")
+	}
+	if ev := snap.HighImpactNear(now, 0); ev != nil {
+		t.Error("zero window must never block")
+	}
+	var nilSnap *Snapshot
+	if nilSnap.HighImpactNear(now, time.Hour) != nil {
+		t.Error("nil snapshot must never block")
+	}
+}
+
+type stubHeadlines struct {
+	name  string
+	items []Headline
+	err   error
+}
+
+func (s *stubHeadlines) Name() string                        { return s.name }
+func (s *stubHeadlines) FetchHeadlines() ([]Headline, error) { return s.items, s.err }
+
+func TestServiceSnapshot(t *testing.T) {
+	now := time.Now()
+	a := &stubHeadlines{name: "a", items: []Headline{
+		{Title: "Older", PublishedAt: now.Add(-2 * time.Hour)},
+		{Title: "Shared story", PublishedAt: now.Add(-time.Hour)},
+	}}
+	b := &stubHeadlines{name: "b", items: []Headline{
+		{Title: "shared story ", PublishedAt: now.Add(-time.Hour)},
+		{Title: "Newest", PublishedAt: now},
+	}}
+
+	svc := NewService(time.Minute)
+	svc.AddHeadlineSource(a)
+	svc.AddHeadlineSource(b)
+	svc.refresh()
+
+	snap := svc.Snapshot()
+	if len(snap.Headlines) != 3 {
+		t.Fatalf("expected duplicate titles to be merged, got %+v", snap.Headlines)
+	}
+	if snap.Headlines[0].Title != "Newest" || snap.Headlines[2].Title != "Older" {
+		t.Errorf("expected newest first, got %+v", snap.Headlines)
+	}
+
+	// A failing source keeps its last good items
+	a.items, a.err = nil, errors.New("timeout")
+	svc.refresh()
+	if got := len(svc.Snapshot().Headlines); got != 3 {
+		t.Errorf("expected cached items after a failed refresh, got %d", got)
+	}
+}
@@ -0,0 +1,240 @@
+package news
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxResponseBytes upper bound on a source response body
+const maxResponseBytes = 5 << 20
+
+var httpClient = &http.Client{
+	Timeout: 15 * time.Second,
+}
+
+// fetch GETs a source URL and returns the body
+func fetch(rawURL string) ([]byte, error) {
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "nofx-news/1.0")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return body, nil
+}
+
+// ============================================================================
+// RSS / Atom headline source
+// ============================================================================
+
+// FeedSource headlines from an RSS 2.0 or Atom feed
+type FeedSource struct {
+	URL string
+}
+
+// NewFeedSource creates a headline source for an RSS or Atom feed URL
+func NewFeedSource(feedURL string) *FeedSource {
+	return &FeedSource{URL: feedURL}
+}
+
+// Name returns the feed host
+func (f *FeedSource) Name() string {
+	if u, err := url.Parse(f.URL); err == nil && u.Host != "" {
+		return strings.TrimPrefix(u.Host, "www.")
+	}
+	return f.URL
+}
+
+// FetchHeadlines downloads and parses the feed
+func (f *FeedSource) FetchHeadlines() ([]Headline, error) {
+	body, err := fetch(f.URL)
+	if err != nil {
+		return nil, err
+	}
+	return parseFeed(f.Name(), body)
+}
+
+type rssFeed struct {
+	Channel struct {
+		Items []struct {
+			Title   string `xml:"title"`
+			Link    string `xml:"link"`
+			PubDate string `xml:"pubDate"`
+		} `xml:"item"`
+	} `xml:"channel"`
+	// Atom
+	Entries []struct {
+		Title string `xml:"title"`
+		Link  []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+	} `xml:"entry"`
+}
+
+// feedTimeLayouts date formats seen in RSS pubDate and Atom fields
+var feedTimeLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02 15:04:05",
+}
+
+// parseFeedTime parses a feed date, zero time when unrecognized
+func parseFeedTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range feedTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parseFeed parses an RSS 2.0 or Atom document into headlines
+func parseFeed(source string, body []byte) ([]Headline, error) {
+	var feed rssFeed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	var headlines []Headline
+	for _, item := range feed.Channel.Items {
+		if title := strings.TrimSpace(item.Title); title != "" {
+			headlines = append(headlines, Headline{
+				Title:       title,
+				Source:      source,
+				URL:         strings.TrimSpace(item.Link),
+				PublishedAt: parseFeedTime(item.PubDate),
+			})
+		}
+	}
+	for _, entry := range feed.Entries {
+		title := strings.TrimSpace(entry.Title)
+		if title == "" {
+			continue
+		}
+		h := Headline{Title: title, Source: source, PublishedAt: parseFeedTime(entry.Published)}
+		if h.PublishedAt.IsZero() {
+			h.PublishedAt = parseFeedTime(entry.Updated)
+		}
+		for _, link := range entry.Link {
+			if link.Rel == "" || link.Rel == "alternate" {
+				h.URL = link.Href
+				break
+			}
+		}
+		headlines = append(headlines, h)
+	}
+	return headlines, nil
+}
+
+// ============================================================================
+// Economic calendar source
+// ============================================================================
+
+// CalendarFeedSource economic calendar events from a JSON feed in the ForexFactory
+// weekly export format: [{"title","country","date","impact","forecast","previous"}]
+type CalendarFeedSource struct {
+	URL       string
+	Countries map[string]bool // Country codes to keep, empty: all
+}
+
+// NewCalendarFeedSource creates a calendar source keeping events of the given countries (empty: all)
+func NewCalendarFeedSource(feedURL string, countries []string) *CalendarFeedSource {
+	src := &CalendarFeedSource{URL: feedURL, Countries: make(map[string]bool)}
+	for _, c := range countries {
+		if c = strings.ToUpper(strings.TrimSpace(c)); c != "" {
+			src.Countries[c] = true
+		}
+	}
+	return src
+}
+
+// Name returns the feed host
+func (c *CalendarFeedSource) Name() string {
+	if u, err := url.Parse(c.URL); err == nil && u.Host != "" {
+		return strings.TrimPrefix(u.Host, "www.")
+	}
+	return c.URL
+}
+
+// FetchEvents downloads and parses the calendar
+func (c *CalendarFeedSource) FetchEvents() ([]CalendarEvent, error) {
+	body, err := fetch(c.URL)
+	if err != nil {
+		return nil, err
+	}
+	return parseCalendar(body, c.Countries)
+}
+
+// parseCalendar parses a ForexFactory-format calendar, skipping holidays and unknown dates
+func parseCalendar(body []byte, countries map[string]bool) ([]CalendarEvent, error) {
+	var raw []struct {
+		Title    string `json:"title"`
+		Country  string `json:"country"`
+		Date     string `json:"date"`
+		Impact   string `json:"impact"`
+		Forecast string `json:"forecast"`
+		Previous string `json:"previous"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse calendar: %w", err)
+	}
+
+	events := make([]CalendarEvent, 0, len(raw))
+	for _, r := range raw {
+		country := strings.ToUpper(strings.TrimSpace(r.Country))
+		if len(countries) > 0 && !countries[country] {
+			continue
+		}
+		var impact string
+		switch strings.ToLower(strings.TrimSpace(r.Impact)) {
+		case "high":
+			impact = ImpactHigh
+		case "medium":
+			impact = ImpactMedium
+		case "low":
+			impact = ImpactLow
+		default:
+			continue // Holiday, non-economic
+		}
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(r.Date))
+		if err != nil {
+			continue
+		}
+		events = append(events, CalendarEvent{
+			Title:    strings.TrimSpace(r.Title),
+			Country:  country,
+			Impact:   impact,
+			Time:     t,
+			Forecast: r.Forecast,
+			Previous: r.Previous,
+		})
+	}
+	return events, nil
+}
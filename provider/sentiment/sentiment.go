@@ -0,0 +1,201 @@
+package sentiment
+
+import (
+	"nofx/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Market Sentiment
+// ============================================================================
+// The Crypto Fear & Greed index and optional per-symbol social sentiment
+// scores are fetched on a schedule and cached for decision cycles. Every
+// refresh is also handed to a recorder (the sentiment time series store) so
+// history can be charted.
+// ============================================================================
+
+// Metrics, matching the store's sentiment metric names
+const (
+	MetricFearGreed = "fear_greed"
+	MetricSocial    = "social"
+)
+
+// Reading one sentiment value
+type Reading struct {
+	Metric   string    `json:"metric"`
+	Symbol   string    `json:"symbol,omitempty"` // Base asset, e.g. BTC (empty: market-wide)
+	Value    float64   `json:"value"`            // Fear & Greed 0-100, social score -1..1
+	Label    string    `json:"label,omitempty"`  // e.g. "Extreme Fear"
+	Mentions int64     `json:"mentions,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// SocialSource a provider of per-symbol social sentiment scores
+type SocialSource interface {
+	Name() string
+	FetchScores(symbols []string) ([]Reading, error)
+}
+
+// Snapshot latest cached readings
+type Snapshot struct {
+	FearGreed     *Reading            `json:"fear_greed,omitempty"`
+	FearGreedPrev *Reading            `json:"fear_greed_prev,omitempty"` // Previous day's index
+	Social        map[string]*Reading `json:"social,omitempty"`          // Keyed by base asset
+}
+
+// SocialFor returns the social reading of a symbol (BTC, BTCUSDT...), nil if none
+func (s *Snapshot) SocialFor(symbol string) *Reading {
+	if s == nil {
+		return nil
+	}
+	return s.Social[BaseAsset(symbol)]
+}
+
+// BaseAsset strips the quote asset from a trading symbol (BTCUSDT → BTC)
+func BaseAsset(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	for _, quote := range []string{"USDT", "USDC", "USD"} {
+		if base := strings.TrimSuffix(symbol, quote); base != symbol && base != "" {
+			return base
+		}
+	}
+	return symbol
+}
+
+// Service refreshes and caches sentiment readings
+type Service struct {
+	interval  time.Duration
+	fearGreed *FearGreedClient // nil: disabled
+	social    SocialSource     // nil: disabled
+	symbols   []string         // Base assets scored by the social source
+	recorder  func([]Reading)  // Receives every successful refresh (nil: not recorded)
+
+	mu       sync.RWMutex
+	snapshot Snapshot
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewService creates a sentiment service refreshing every interval
+func NewService(interval time.Duration) *Service {
+	return &Service{
+		interval: interval,
+		snapshot: Snapshot{Social: make(map[string]*Reading)},
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// SetFearGreedClient enables the Fear & Greed index, call before Start
+func (s *Service) SetFearGreedClient(client *FearGreedClient) {
+	s.fearGreed = client
+}
+
+// SetSocialSource enables social sentiment of the given symbols, call before Start
+func (s *Service) SetSocialSource(src SocialSource, symbols []string) {
+	s.social = src
+	s.symbols = nil
+	for _, symbol := range symbols {
+		if base := BaseAsset(symbol); base != "" {
+			s.symbols = append(s.symbols, base)
+		}
+	}
+}
+
+// SetRecorder sets the function receiving the readings of every refresh, call before Start
+func (s *Service) SetRecorder(recorder func([]Reading)) {
+	s.recorder = recorder
+}
+
+// Start loads the readings and refreshes them in the background
+func (s *Service) Start() {
+	go func() {
+		s.refresh()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.refresh()
+			case <-s.stopCh:
+				return
+			}
+		}
+	}()
+	logger.Infof("🧠 Sentiment service started: fear & greed %v, social %d symbols (refresh every %v)",
+		s.fearGreed != nil, len(s.symbols), s.interval)
+}
+
+// Stop stops the scheduled refresh
+func (s *Service) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+}
+
+// refresh fetches all sources, keeping the previous readings of sources that fail
+func (s *Service) refresh() {
+	var readings []Reading
+
+	if s.fearGreed != nil {
+		// Two days: today's index and yesterday's for the change
+		points, err := s.fearGreed.Fetch(2)
+		if err != nil {
+			logger.Warnf("⚠️ Fear & Greed index: %v", err)
+		} else if len(points) > 0 {
+			s.mu.Lock()
+			s.snapshot.FearGreed = &points[0]
+			s.snapshot.FearGreedPrev = nil
+			if len(points) > 1 {
+				s.snapshot.FearGreedPrev = &points[1]
+			}
+			s.mu.Unlock()
+			readings = append(readings, points...)
+		}
+	}
+
+	if s.social != nil && len(s.symbols) > 0 {
+		scores, err := s.social.FetchScores(s.symbols)
+		if err != nil {
+			logger.Warnf("⚠️ Social sentiment source %s: %v", s.social.Name(), err)
+		} else {
+			now := time.Now().Truncate(time.Minute)
+			s.mu.Lock()
+			for i := range scores {
+				if scores[i].Time.IsZero() {
+					scores[i].Time = now
+				}
+				scores[i].Metric = MetricSocial
+				scores[i].Symbol = BaseAsset(scores[i].Symbol)
+				s.snapshot.Social[scores[i].Symbol] = &scores[i]
+			}
+			s.mu.Unlock()
+			readings = append(readings, scores...)
+		}
+	}
+
+	if s.recorder != nil && len(readings) > 0 {
+		s.recorder(readings)
+	}
+}
+
+// Snapshot returns a copy of the latest readings
+func (s *Service) Snapshot() *Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snap := &Snapshot{Social: make(map[string]*Reading, len(s.snapshot.Social))}
+	if s.snapshot.FearGreed != nil {
+		fg := *s.snapshot.FearGreed
+		snap.FearGreed = &fg
+	}
+	if s.snapshot.FearGreedPrev != nil {
+		prev := *s.snapshot.FearGreedPrev
+		snap.FearGreedPrev = &prev
+	}
+	for symbol, r := range s.snapshot.Social {
+		reading := *r
+		snap.Social[symbol] = &reading
+	}
+	return snap
+}
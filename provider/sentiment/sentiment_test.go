@@ -0,0 +1,94 @@
+package sentiment
+
+import (
+	"testing"
+)
+
+func TestParseFearGreed(t *testing.T) {
+	body := `{"name":"Fear and Greed Index","data":[
+		{"value":"27","value_classification":"Fear","timestamp":"1760400000"},
+		{"value":"35","value_classification":"Fear","timestamp":"1760313600"},
+		{"value":"n/a","value_classification":"","timestamp":"1760227200"}
+	],"metadata":{"error":null}}`
+	readings, err := parseFearGreed([]byte(body))
+	if err != nil {
+		t.Fatalf("parseFearGreed: %v", err)
+	}
+	if len(readings) != 2 {
+		t.Fatalf("expected invalid values to be skipped, got %+v", readings)
+	}
+	if r := readings[0]; r.Metric != MetricFearGreed || r.Value != 27 || r.Label != "Fear" || r.Time.Unix() != 1760400000 {
+		t.Errorf("unexpected reading: %+v", r)
+	}
+
+	if _, err := parseFearGreed([]byte(`{"data":[],"metadata":{"error":"rate limited"}}`)); err == nil {
+		t.Error("expected the API error to be returned")
+	}
+}
+
+func TestParseSocialScores(t *testing.T) {
+	body := `{"data":[
+		{"symbol":"BTCUSDT","score":0.42,"mentions":1520,"timestamp":1760400000},
+		{"symbol":"eth","score":3.5},
+		{"symbol":"","score":0.1}
+	]}`
+	readings, err := parseSocialScores([]byte(body))
+	if err != nil {
+		t.Fatalf("parseSocialScores: %v", err)
+	}
+	if len(readings) != 2 {
+		t.Fatalf("expected entries without symbol to be skipped, got %+v", readings)
+	}
+	if r := readings[0]; r.Symbol != "BTC" || r.Value != 0.42 || r.Mentions != 1520 {
+		t.Errorf("unexpected reading: %+v", r)
+	}
+	if r := readings[1]; r.Symbol != "ETH" || r.Value != 1 || !r.Time.IsZero() {
+		t.Errorf("expected ETH score clamped to 1 without timestamp, got %+v", r)
+	}
+}
+
+func TestBaseAsset(t *testing.T) {
+	cases := map[string]string{
+		"BTCUSDT": "BTC",
+		"ethusdc": "ETH",
+		" SOL ":   "SOL",
+		"USDT":    "USDT",
+	}
+	for in, want := range cases {
+		if got := BaseAsset(in); got != want {
+			t.Errorf("BaseAsset(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+type stubSocial struct {
+	readings []Reading
+}
+
+func (s *stubSocial) Name() string { return "stub" }
+func (s *stubSocial) FetchScores(symbols []string) ([]Reading, error) {
+	return s.readings, nil
+}
+
+func TestServiceRefresh(t *testing.T) {
+	var recorded []Reading
+	svc := NewService(0)
+	svc.SetSocialSource(&stubSocial{readings: []Reading{{Symbol: "BTC", Value: 0.3}}}, []string{"BTCUSDT"})
+	svc.SetRecorder(func(readings []Reading) { recorded = append(recorded, readings...) })
+	svc.refresh()
+
+	snap := svc.Snapshot()
+	r := snap.SocialFor("BTCUSDT")
+	if r == nil || r.Value != 0.3 || r.Metric != MetricSocial || r.Time.IsZero() {
+		t.Fatalf("expected stamped BTC social reading, got %+v", r)
+	}
+	if len(recorded) != 1 {
+		t.Errorf("expected the refresh to be recorded, got %+v", recorded)
+	}
+
+	// Snapshot is a copy
+	r.Value = -1
+	if svc.Snapshot().SocialFor("BTC").Value != 0.3 {
+		t.Error("snapshot must not share readings with the cache")
+	}
+}
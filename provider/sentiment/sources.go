@@ -0,0 +1,189 @@
+package sentiment
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultFearGreedURL alternative.me Crypto Fear & Greed index API
+const DefaultFearGreedURL = "https://api.alternative.me/fng/"
+
+// maxResponseBytes upper bound on a source response body
+const maxResponseBytes = 1 << 20
+
+var httpClient = &http.Client{
+	Timeout: 15 * time.Second,
+}
+
+// get performs a GET request and returns the body
+func get(req *http.Request) ([]byte, error) {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return body, nil
+}
+
+// ============================================================================
+// Fear & Greed index
+// ============================================================================
+
+// FearGreedClient Crypto Fear & Greed index client (alternative.me API format)
+type FearGreedClient struct {
+	URL string
+}
+
+// NewFearGreedClient creates a Fear & Greed client, empty URL uses the alternative.me API
+func NewFearGreedClient(apiURL string) *FearGreedClient {
+	if apiURL == "" {
+		apiURL = DefaultFearGreedURL
+	}
+	return &FearGreedClient{URL: apiURL}
+}
+
+// Fetch gets the latest days index values, newest first
+func (c *FearGreedClient) Fetch(days int) ([]Reading, error) {
+	req, err := http.NewRequest("GET", c.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	q := req.URL.Query()
+	q.Set("limit", strconv.Itoa(days))
+	req.URL.RawQuery = q.Encode()
+
+	body, err := get(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseFearGreed(body)
+}
+
+// parseFearGreed parses an alternative.me /fng/ response
+func parseFearGreed(body []byte) ([]Reading, error) {
+	var result struct {
+		Data []struct {
+			Value          string `json:"value"`
+			Classification string `json:"value_classification"`
+			Timestamp      string `json:"timestamp"`
+		} `json:"data"`
+		Metadata struct {
+			Error *string `json:"error"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse fear & greed index: %w", err)
+	}
+	if result.Metadata.Error != nil && *result.Metadata.Error != "" {
+		return nil, fmt.Errorf("fear & greed API error: %s", *result.Metadata.Error)
+	}
+
+	readings := make([]Reading, 0, len(result.Data))
+	for _, d := range result.Data {
+		value, err1 := strconv.ParseFloat(d.Value, 64)
+		ts, err2 := strconv.ParseInt(d.Timestamp, 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		readings = append(readings, Reading{
+			Metric: MetricFearGreed,
+			Value:  value,
+			Label:  d.Classification,
+			Time:   time.Unix(ts, 0).UTC(),
+		})
+	}
+	return readings, nil
+}
+
+// ============================================================================
+// Social sentiment (Twitter/X and other social feeds)
+// ============================================================================
+
+// HTTPSocialSource social sentiment scores from a JSON endpoint, e.g. a Twitter/X sentiment
+// aggregator. GET <URL>?symbols=BTC,ETH (Authorization: Bearer <APIKey> when set) must return
+// {"data":[{"symbol":"BTC","score":0.35,"mentions":1520,"timestamp":1760400000}]},
+// score from -1 (bearish) to 1 (bullish), timestamp in unix seconds (optional).
+type HTTPSocialSource struct {
+	URL    string
+	APIKey string
+}
+
+// NewHTTPSocialSource creates a social sentiment source for a JSON endpoint
+func NewHTTPSocialSource(endpoint, apiKey string) *HTTPSocialSource {
+	return &HTTPSocialSource{URL: endpoint, APIKey: apiKey}
+}
+
+// Name returns the endpoint host
+func (h *HTTPSocialSource) Name() string {
+	if u, err := url.Parse(h.URL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return h.URL
+}
+
+// FetchScores gets the scores of symbols (base assets)
+func (h *HTTPSocialSource) FetchScores(symbols []string) ([]Reading, error) {
+	req, err := http.NewRequest("GET", h.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	q := req.URL.Query()
+	q.Set("symbols", strings.Join(symbols, ","))
+	req.URL.RawQuery = q.Encode()
+	if h.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+h.APIKey)
+	}
+
+	body, err := get(req)
+	if err != nil {
+		return nil, err
+	}
+	return parseSocialScores(body)
+}
+
+// parseSocialScores parses a social sentiment response, clamping scores to -1..1
+func parseSocialScores(body []byte) ([]Reading, error) {
+	var result struct {
+		Data []struct {
+			Symbol    string  `json:"symbol"`
+			Score     float64 `json:"score"`
+			Mentions  int64   `json:"mentions"`
+			Timestamp int64   `json:"timestamp"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse social sentiment: %w", err)
+	}
+
+	readings := make([]Reading, 0, len(result.Data))
+	for _, d := range result.Data {
+		if d.Symbol == "" {
+			continue
+		}
+		r := Reading{
+			Metric:   MetricSocial,
+			Symbol:   BaseAsset(d.Symbol),
+			Value:    max(-1, min(1, d.Score)),
+			Mentions: d.Mentions,
+		}
+		if d.Timestamp > 0 {
+			r.Time = time.Unix(d.Timestamp, 0).UTC()
+		}
+		readings = append(readings, r)
+	}
+	return readings, nil
+}
@@ -0,0 +1,134 @@
+package report
+
+import (
+	"fmt"
+	"nofx/logger"
+	"nofx/notify"
+	"nofx/store"
+	"time"
+)
+
+// periodTitles report periods as shown in email subjects
+var periodTitles = map[string]string{store.ReportPeriodWeekly: "Weekly", store.ReportPeriodMonthly: "Monthly"}
+
+// Generator generates, stores and emails performance reports
+type Generator struct {
+	store            *store.Store
+	mailer           notify.Mailer // nil = reports are stored only
+	hour             int           // Local hour after the end of a period its report is generated at
+	aiCostPerMTokens float64
+}
+
+// NewGenerator creates a report generator; mailer may be nil
+func NewGenerator(st *store.Store, mailer notify.Mailer, hour int, aiCostPerMTokens float64) *Generator {
+	return &Generator{store: st, mailer: mailer, hour: hour, aiCostPerMTokens: aiCostPerMTokens}
+}
+
+// GenerateDue generates the report of the last complete period for users who scheduled it, once their
+// local time has passed hour on the day after it, returning how many were generated
+// A period that was missed (server down) is generated on the next call; a failed email is not retried,
+// the report stays available in the dashboard.
+func (g *Generator) GenerateDue(now time.Time) int {
+	scheduled, err := g.store.Report().ListScheduled()
+	if err != nil {
+		logger.Warnf("⚠️ Performance reports: failed to list schedules: %v", err)
+		return 0
+	}
+
+	generated := 0
+	for _, settings := range scheduled {
+		loc := time.UTC
+		if user, err := g.store.User().GetByID(settings.UserID); err == nil {
+			loc = user.Location()
+		}
+		for _, period := range settings.Periods() {
+			start, end, err := LastPeriod(period, now, loc)
+			if err != nil || now.Before(end.Add(time.Duration(g.hour)*time.Hour)) {
+				continue
+			}
+			if exists, err := g.store.Report().Exists(settings.UserID, "", period, start.Format(store.DailyStatsDateLayout)); err != nil || exists {
+				continue
+			}
+
+			report, err := g.Generate(settings.UserID, "", period, start, end)
+			if err != nil {
+				logger.Warnf("⚠️ Performance report %s %s failed for user %s: %v", period, start.Format(store.DailyStatsDateLayout), settings.UserID, err)
+				continue
+			}
+			generated++
+			if settings.Email {
+				if err := g.Email(report); err != nil {
+					logger.Warnf("⚠️ Performance report %d not emailed to user %s: %v", report.ID, settings.UserID, err)
+				}
+			}
+		}
+	}
+	return generated
+}
+
+// Generate builds, renders and stores the report of a user's trader, or of all their traders when
+// traderID is empty, for [start, end)
+func (g *Generator) Generate(userID, traderID, period string, start, end time.Time) (*store.PerformanceReport, error) {
+	data, err := Build(g.store, userID, traderID, period, start, end, g.aiCostPerMTokens)
+	if err != nil {
+		return nil, err
+	}
+	html, err := RenderHTML(data)
+	if err != nil {
+		return nil, err
+	}
+	pdf, err := RenderPDF(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render PDF: %w", err)
+	}
+
+	report := &store.PerformanceReport{
+		UserID:    userID,
+		TraderID:  traderID,
+		Period:    period,
+		StartDate: start.Format(store.DailyStatsDateLayout),
+		EndDate:   data.LastDay().Format(store.DailyStatsDateLayout),
+		Timezone:  data.Timezone,
+		NetPnL:    data.Total.NetPnL,
+		ReturnPct: data.Total.ReturnPct,
+		Trades:    data.Total.Trades,
+		HTML:      html,
+		PDF:       pdf,
+		CreatedAt: data.Generated,
+	}
+	if err := g.store.Report().Save(report); err != nil {
+		return nil, fmt.Errorf("failed to save report: %w", err)
+	}
+	logger.Infof("📊 Generated %s performance report %s for user %s (%d traders)", period, report.StartDate, userID, len(data.Traders))
+	return report, nil
+}
+
+// Email sends a report to its owner's verified notification address, with the PDF attached when the
+// mailer supports attachments
+func (g *Generator) Email(report *store.PerformanceReport) error {
+	if g.mailer == nil {
+		return fmt.Errorf("email is not configured (SMTP_HOST)")
+	}
+	settings, err := g.store.Notification().Get(report.UserID)
+	if err != nil {
+		return err
+	}
+	if !settings.EmailVerified || settings.Email == "" {
+		return fmt.Errorf("no verified notification email address")
+	}
+
+	subject := fmt.Sprintf("[NOFX] %s performance report %s – %s", periodTitles[report.Period], report.StartDate, report.EndDate)
+	if am, ok := g.mailer.(notify.AttachmentMailer); ok {
+		err = am.SendWithAttachments(settings.Email, subject, report.HTML, []notify.Attachment{{
+			Filename:    fmt.Sprintf("nofx-%s-report-%s.pdf", report.Period, report.StartDate),
+			ContentType: "application/pdf",
+			Data:        report.PDF,
+		}})
+	} else {
+		err = g.mailer.Send(settings.Email, subject, report.HTML)
+	}
+	if err != nil {
+		return err
+	}
+	return g.store.Report().MarkEmailed(report.ID, time.Now())
+}
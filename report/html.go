@@ -0,0 +1,130 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// maxTableTrades closed trades listed per trader, the most recent ones
+const maxTableTrades = 100
+
+// Equity chart size in SVG user units
+const (
+	chartWidth  = 640.0
+	chartHeight = 160.0
+)
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"money":  func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"signed": func(v float64) string { return fmt.Sprintf("%+.2f", v) },
+	"pct":    func(v float64) string { return fmt.Sprintf("%.2f%%", v) },
+	"color": func(v float64) string {
+		if v < 0 {
+			return "#d9304e"
+		}
+		return "#0a9d6a"
+	},
+	"factor": func(v float64) string {
+		if v == 0 {
+			return "—"
+		}
+		return fmt.Sprintf("%.2f", v)
+	},
+	"points": chartPoints,
+	"shown":  shownTrades,
+	"hidden": func(trades []Trade) int { return len(trades) - len(shownTrades(trades)) },
+}).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>NOFX {{.Period}} report {{.Start.Format "2006-01-02"}}</title>
+<style>
+body{margin:0;padding:24px;background:#f5f6f8;font-family:Arial,sans-serif;color:#1e2329;font-size:14px}
+.page{max-width:760px;margin:0 auto;background:#fff;border-radius:8px;padding:24px}
+h1{margin:0;color:#c99400;font-size:22px}h2{margin:28px 0 8px;font-size:17px;border-bottom:2px solid #eaecef;padding-bottom:6px}
+.muted{color:#707a8a}.stats{width:100%;border-collapse:collapse;margin:8px 0}.stats td{padding:4px 8px;width:25%}
+.stats td.k{color:#707a8a}.trades{width:100%;border-collapse:collapse;font-size:12px;margin-top:8px}
+.trades th{color:#707a8a;text-align:right;padding:4px;border-bottom:1px solid #eaecef}.trades td{text-align:right;padding:4px;border-bottom:1px solid #f5f6f8}
+.trades .l{text-align:left}@media print{body{background:#fff;padding:0}.page{padding:0}}
+</style></head><body><div class="page">
+<h1>NOFX performance report</h1>
+<p class="muted">{{if eq .Period "weekly"}}Week{{else}}Month{{end}} {{.Start.Format "2006-01-02"}} – {{.LastDay.Format "2006-01-02"}} ({{.Timezone}}) · generated {{.Generated.Format "2006-01-02 15:04"}}</p>
+{{if gt (len .Traders) 1}}<h2>All traders</h2>{{template "stats" .Total}}{{end}}
+{{range .Traders}}
+<h2>{{.Name}} <span class="muted" style="font-size:13px;font-weight:normal">{{.Exchange}}</span></h2>
+{{template "stats" .Stats}}
+{{if gt (len .Equity) 1}}<svg viewBox="0 0 640 160" width="100%" style="background:#fafafa;border-radius:4px">
+<polyline fill="none" stroke="#c99400" stroke-width="2" points="{{points .Equity}}"/></svg>
+{{else}}<p class="muted">Not enough equity snapshots for a curve.</p>{{end}}
+{{if .Trades}}<table class="trades">
+<tr><th class="l">Closed</th><th class="l">Symbol</th><th class="l">Side</th><th>Qty</th><th>Entry</th><th>Exit</th><th>PnL</th><th>Fee</th><th class="l">Reason</th></tr>
+{{range shown .Trades}}<tr><td class="l">{{.ExitTime.Format "01-02 15:04"}}</td><td class="l">{{.Symbol}}</td><td class="l">{{.Side}}</td>
+<td>{{printf "%.4g" .Quantity}}</td><td>{{printf "%.6g" .EntryPrice}}</td><td>{{printf "%.6g" .ExitPrice}}</td>
+<td style="color:{{color .PnL}}">{{signed .PnL}}</td><td>{{money .Fee}}</td><td class="l">{{.CloseReason}}</td></tr>
+{{end}}</table>{{with hidden .Trades}}<p class="muted">{{.}} earlier trades not listed.</p>{{end}}
+{{else}}<p class="muted">No trades closed in this period.</p>{{end}}
+{{else}}<p class="muted">No traders.</p>{{end}}
+</div></body></html>
+{{define "stats"}}<table class="stats">
+<tr><td class="k">Equity</td><td>{{money .StartEquity}} → {{money .EndEquity}}</td><td class="k">Return</td><td style="color:{{color .ReturnPct}}">{{pct .ReturnPct}}</td></tr>
+<tr><td class="k">Net PnL</td><td style="color:{{color .NetPnL}}">{{signed .NetPnL}}</td><td class="k">Max drawdown</td><td>{{pct .MaxDrawdownPct}}</td></tr>
+<tr><td class="k">Trades</td><td>{{.Trades}} ({{.Wins}} W / {{.Losses}} L)</td><td class="k">Win rate</td><td>{{pct .WinRate}}</td></tr>
+<tr><td class="k">Realized PnL</td><td>{{signed .GrossPnL}}</td><td class="k">Profit factor</td><td>{{factor .ProfitFactor}}</td></tr>
+<tr><td class="k">Best / worst</td><td>{{signed .BestTrade}} / {{signed .WorstTrade}}</td><td class="k">Fees</td><td>{{money .Fees}}</td></tr>
+<tr><td class="k">Funding</td><td>{{signed .Funding}}</td><td class="k">Transfers</td><td>{{signed .Transfers}}</td></tr>
+<tr><td class="k">AI calls</td><td>{{.AICalls}} (~{{.AITokens}} tokens)</td><td class="k">AI cost (est.)</td><td>{{if .AICostUSD}}${{money .AICostUSD}}{{else}}—{{end}}</td></tr>
+</table>{{end}}`))
+
+// RenderHTML renders a report as a standalone HTML page
+func RenderHTML(r *Report) (string, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, r); err != nil {
+		return "", fmt.Errorf("failed to render report: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// shownTrades the trades listed in a report table, the most recent maxTableTrades
+func shownTrades(trades []Trade) []Trade {
+	if len(trades) > maxTableTrades {
+		return trades[len(trades)-maxTableTrades:]
+	}
+	return trades
+}
+
+// chartPoints SVG polyline points of an equity curve scaled to the chart, time on the x axis
+func chartPoints(equity []EquityPoint) string {
+	var b strings.Builder
+	for i, p := range scaleCurve(equity, chartWidth, chartHeight) {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%.1f,%.1f", p[0], p[1])
+	}
+	return b.String()
+}
+
+// scaleCurve scales an equity curve into a width x height box with a small margin, y growing downwards
+func scaleCurve(equity []EquityPoint, width, height float64) [][2]float64 {
+	if len(equity) < 2 {
+		return nil
+	}
+	lo, hi := equity[0].Equity, equity[0].Equity
+	for _, p := range equity {
+		lo, hi = min(lo, p.Equity), max(hi, p.Equity)
+	}
+	if hi == lo {
+		hi, lo = hi+1, lo-1
+	}
+	first, span := equity[0].Time, equity[len(equity)-1].Time.Sub(equity[0].Time).Seconds()
+	const margin = 6.0
+	points := make([][2]float64, 0, len(equity))
+	for i, p := range equity {
+		x := float64(i) / float64(len(equity)-1)
+		if span > 0 {
+			x = p.Time.Sub(first).Seconds() / span
+		}
+		y := (hi - p.Equity) / (hi - lo)
+		points = append(points, [2]float64{margin + x*(width-2*margin), margin + y*(height-2*margin)})
+	}
+	return points
+}
@@ -0,0 +1,376 @@
+package report
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+)
+
+// A4 page in points
+const (
+	pdfWidth  = 595.0
+	pdfHeight = 842.0
+	pdfMargin = 40.0
+)
+
+// helveticaWidths glyph widths of Helvetica for ASCII 32-126, in 1/1000 of the font size
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// pdfDoc minimal PDF writer: A4 pages of Helvetica text, lines and filled rectangles
+// Positions are in points from the top left corner; only Latin-1 text is representable, no font is
+// embedded to keep reports small, so Chinese and other scripts need pdfName (full text: HTML report).
+type pdfDoc struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64 // Layout cursor from the top of the page
+}
+
+func newPDF() *pdfDoc {
+	d := &pdfDoc{}
+	d.addPage()
+	return d
+}
+
+func (d *pdfDoc) addPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+	d.y = pdfMargin
+}
+
+// ensure starts a new page unless h points fit below the cursor
+func (d *pdfDoc) ensure(h float64) {
+	if d.y+h > pdfHeight-pdfMargin {
+		d.addPage()
+	}
+}
+
+// color sets the fill and stroke color (0-255 components)
+func (d *pdfDoc) color(r, g, b int) {
+	fmt.Fprintf(d.page, "%.3f %.3f %.3f rg %.3f %.3f %.3f RG\n",
+		float64(r)/255, float64(g)/255, float64(b)/255, float64(r)/255, float64(g)/255, float64(b)/255)
+}
+
+// text draws s with its baseline at y
+func (d *pdfDoc) text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, pdfHeight-y, pdfEscape(s))
+}
+
+// textRight draws s ending at x
+func (d *pdfDoc) textRight(x, y, size float64, s string) {
+	d.text(x-textWidth(s, size), y, size, false, s)
+}
+
+// rect fills a rectangle
+func (d *pdfDoc) rect(x, y, w, h float64) {
+	fmt.Fprintf(d.page, "%.2f %.2f %.2f %.2f re f\n", x, pdfHeight-y-h, w, h)
+}
+
+// line strokes a line
+func (d *pdfDoc) line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.page, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, pdfHeight-y1, x2, pdfHeight-y2)
+}
+
+// polyline strokes connected points
+func (d *pdfDoc) polyline(points [][2]float64, width float64) {
+	if len(points) < 2 {
+		return
+	}
+	fmt.Fprintf(d.page, "%.2f w 1 j %.2f %.2f m", width, points[0][0], pdfHeight-points[0][1])
+	for _, p := range points[1:] {
+		fmt.Fprintf(d.page, " %.2f %.2f l", p[0], pdfHeight-p[1])
+	}
+	d.page.WriteString(" S\n")
+}
+
+// bytes assembles the document: catalog, page tree, the two fonts, then each page and its content stream
+func (d *pdfDoc) bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfWidth, pdfHeight, 6+2*i))
+
+		var content bytes.Buffer
+		zw := zlib.NewWriter(&content)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		obj(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes(), nil
+}
+
+// pdfEscape encodes s as the body of a WinAnsi PDF string, characters outside Latin-1 become '?'
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 32 && r < 127:
+			b.WriteRune(r)
+		case pdfRepresentable(r):
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// pdfRepresentable reports whether r can be written with the WinAnsi Helvetica fonts
+func pdfRepresentable(r rune) bool {
+	return (r >= 32 && r < 127) || (r >= 0xA0 && r <= 0xFF)
+}
+
+// pdfName a trader name as it can be shown in the PDF: names with characters outside Latin-1
+// (e.g. Chinese) would render as '?', so their Latin-1 part is shown with the ID, or the ID alone
+func pdfName(name, id string) string {
+	if strings.IndexFunc(name, func(r rune) bool { return !pdfRepresentable(r) }) < 0 {
+		return name
+	}
+	kept := strings.Join(strings.Fields(strings.Map(func(r rune) rune {
+		if pdfRepresentable(r) {
+			return r
+		}
+		return ' '
+	}, name)), " ")
+	if kept == "" || id == "" {
+		return strings.TrimSpace(kept + " " + id)
+	}
+	return kept + " (" + id + ")"
+}
+
+// textWidth width of s in Helvetica at size points, non-ASCII characters counted as digits
+func textWidth(s string, size float64) float64 {
+	units := 0
+	for _, r := range s {
+		if r >= 32 && r < 127 {
+			units += helveticaWidths[r-32]
+		} else {
+			units += 556
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// truncate shortens s to n characters
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "."
+	}
+	return s
+}
+
+// RenderPDF renders a report as an A4 PDF
+// Trader names outside Latin-1 are shown by their Latin-1 part and ID (see pdfName).
+func RenderPDF(r *Report) ([]byte, error) {
+	d := newPDF()
+	d.color(201, 148, 0)
+	d.text(pdfMargin, d.y+18, 18, true, "NOFX performance report")
+	d.color(112, 122, 138)
+	period := "Week"
+	if r.Period != "weekly" {
+		period = "Month"
+	}
+	d.text(pdfMargin, d.y+36, 10, false, fmt.Sprintf("%s %s - %s (%s), generated %s", period,
+		r.Start.Format("2006-01-02"), r.LastDay().Format("2006-01-02"), r.Timezone, r.Generated.Format("2006-01-02 15:04")))
+	d.y += 48
+
+	if len(r.Traders) > 1 {
+		pdfHeading(d, "All traders", "")
+		pdfStats(d, r.Total)
+	}
+	for _, t := range r.Traders {
+		pdfHeading(d, pdfName(t.Name, t.ID), t.Exchange)
+		pdfStats(d, t.Stats)
+		pdfChart(d, t.Equity)
+		pdfTrades(d, t.Trades)
+	}
+	if len(r.Traders) == 0 {
+		d.color(112, 122, 138)
+		d.text(pdfMargin, d.y+14, 10, false, "No traders.")
+	}
+	return d.bytes()
+}
+
+func pdfHeading(d *pdfDoc, title, subtitle string) {
+	d.ensure(140) // Keep the heading with the statistics below it
+	d.y += 22
+	d.color(30, 35, 41)
+	d.text(pdfMargin, d.y, 13, true, title)
+	if subtitle != "" {
+		d.color(112, 122, 138)
+		d.text(pdfMargin+textWidth(title, 13)*1.05+8, d.y, 9, false, subtitle)
+	}
+	d.color(234, 236, 239)
+	d.line(pdfMargin, d.y+5, pdfWidth-pdfMargin, d.y+5, 1.5)
+	d.y += 8
+}
+
+func pdfStats(d *pdfDoc, s Stats) {
+	factor := "-"
+	if s.ProfitFactor > 0 {
+		factor = fmt.Sprintf("%.2f", s.ProfitFactor)
+	}
+	aiCost := "-"
+	if s.AICostUSD > 0 {
+		aiCost = fmt.Sprintf("$%.2f", s.AICostUSD)
+	}
+	rows := [][4]string{
+		{"Equity", fmt.Sprintf("%.2f -> %.2f", s.StartEquity, s.EndEquity), "Return", fmt.Sprintf("%.2f%%", s.ReturnPct)},
+		{"Net PnL", fmt.Sprintf("%+.2f", s.NetPnL), "Max drawdown", fmt.Sprintf("%.2f%%", s.MaxDrawdownPct)},
+		{"Trades", fmt.Sprintf("%d (%d W / %d L)", s.Trades, s.Wins, s.Losses), "Win rate", fmt.Sprintf("%.2f%%", s.WinRate)},
+		{"Realized PnL", fmt.Sprintf("%+.2f", s.GrossPnL), "Profit factor", factor},
+		{"Best / worst", fmt.Sprintf("%+.2f / %+.2f", s.BestTrade, s.WorstTrade), "Fees", fmt.Sprintf("%.2f", s.Fees)},
+		{"Funding", fmt.Sprintf("%+.2f", s.Funding), "Transfers", fmt.Sprintf("%+.2f", s.Transfers)},
+		{"AI calls", fmt.Sprintf("%d (~%d tokens)", s.AICalls, s.AITokens), "AI cost (est.)", aiCost},
+	}
+	for _, row := range rows {
+		d.y += 14
+		for i, x := range []float64{pdfMargin, pdfMargin + 90, pdfMargin + 260, pdfMargin + 350} {
+			if i%2 == 0 {
+				d.color(112, 122, 138)
+			} else {
+				d.color(30, 35, 41)
+			}
+			d.text(x, d.y, 9.5, false, row[i])
+		}
+	}
+	d.y += 8
+}
+
+func pdfChart(d *pdfDoc, equity []EquityPoint) {
+	const height = 110.0
+	width := pdfWidth - 2*pdfMargin
+	d.ensure(height + 10)
+	if len(equity) < 2 {
+		d.color(112, 122, 138)
+		d.text(pdfMargin, d.y+12, 9, false, "Not enough equity snapshots for a curve.")
+		d.y += 20
+		return
+	}
+	d.color(250, 250, 250)
+	d.rect(pdfMargin, d.y, width, height)
+
+	lo, hi := equity[0].Equity, equity[0].Equity
+	for _, p := range equity {
+		lo, hi = min(lo, p.Equity), max(hi, p.Equity)
+	}
+	d.color(112, 122, 138)
+	d.text(pdfMargin+4, d.y+10, 7, false, fmt.Sprintf("%.2f", hi))
+	d.text(pdfMargin+4, d.y+height-4, 7, false, fmt.Sprintf("%.2f", lo))
+
+	points := scaleCurve(equity, width, height)
+	for i := range points {
+		points[i][0] += pdfMargin
+		points[i][1] += d.y
+	}
+	d.color(201, 148, 0)
+	d.polyline(points, 1.5)
+	d.y += height + 6
+}
+
+// Trade table columns: x of left-aligned (closed, symbol, side, reason) and right-aligned (amounts) cells
+var pdfTradeColumns = []struct {
+	title string
+	x     float64
+	right bool
+}{
+	{"Closed", pdfMargin, false}, {"Symbol", 98, false}, {"Side", 170, false}, {"Qty", 250, true},
+	{"Entry", 310, true}, {"Exit", 370, true}, {"PnL", 430, true}, {"Fee", 475, true}, {"Reason", 485, false},
+}
+
+func pdfTrades(d *pdfDoc, trades []Trade) {
+	if len(trades) == 0 {
+		d.color(112, 122, 138)
+		d.text(pdfMargin, d.y+12, 9, false, "No trades closed in this period.")
+		d.y += 16
+		return
+	}
+	header := func() {
+		d.y += 14
+		d.color(112, 122, 138)
+		for _, col := range pdfTradeColumns {
+			if col.right {
+				d.textRight(col.x, d.y, 8, col.title)
+			} else {
+				d.text(col.x, d.y, 8, false, col.title)
+			}
+		}
+		d.color(234, 236, 239)
+		d.line(pdfMargin, d.y+3, pdfWidth-pdfMargin, d.y+3, 0.5)
+	}
+	d.ensure(40)
+	header()
+
+	shown := shownTrades(trades)
+	for _, t := range shown {
+		if d.y+12 > pdfHeight-pdfMargin {
+			d.addPage()
+			header()
+		}
+		d.y += 12
+		cells := []string{t.ExitTime.Format("01-02 15:04"), truncate(t.Symbol, 14), t.Side,
+			fmt.Sprintf("%.4g", t.Quantity), fmt.Sprintf("%.6g", t.EntryPrice), fmt.Sprintf("%.6g", t.ExitPrice),
+			fmt.Sprintf("%+.2f", t.PnL), fmt.Sprintf("%.2f", t.Fee), truncate(t.CloseReason, 14)}
+		for i, col := range pdfTradeColumns {
+			switch {
+			case i == 6 && t.PnL < 0:
+				d.color(217, 48, 78)
+			case i == 6:
+				d.color(10, 157, 106)
+			default:
+				d.color(30, 35, 41)
+			}
+			if col.right {
+				d.textRight(col.x, d.y, 8, cells[i])
+			} else {
+				d.text(col.x, d.y, 8, false, cells[i])
+			}
+		}
+	}
+	if hidden := len(trades) - len(shown); hidden > 0 {
+		d.y += 12
+		d.color(112, 122, 138)
+		d.text(pdfMargin, d.y, 8, false, fmt.Sprintf("%d earlier trades not listed.", hidden))
+	}
+	d.y += 6
+}
@@ -0,0 +1,312 @@
+// Package report generates weekly and monthly performance reports
+//
+// A report covers one trader or all of a user's traders over a period in the
+// user's timezone: equity curve, closed trades, trading statistics, fees and
+// funding, and the AI calls the decision cycles made with an estimate of their
+// cost. Reports are rendered as a standalone HTML page and a PDF, stored, and
+// for users who scheduled them generated after each period ends and optionally
+// emailed with the PDF attached, so headless installations get summaries
+// without opening the dashboard.
+package report
+
+import (
+	"fmt"
+	"math"
+	"nofx/store"
+	"time"
+)
+
+const (
+	// equityPoints points the equity curve of a trader is resampled to
+	equityPoints = 200
+	// charsPerToken characters per token of the AI usage estimate (as kernel.EstimateTokens for ASCII prompts)
+	charsPerToken = 3
+)
+
+// Report performance of one or several traders of a user over one period
+type Report struct {
+	Period    string
+	Start     time.Time // First local day, inclusive
+	End       time.Time // Local midnight after the last day, exclusive
+	Timezone  string
+	Generated time.Time
+	Traders   []*TraderReport
+	Total     Stats // All traders combined
+}
+
+// LastDay the last local day the report covers
+func (r *Report) LastDay() time.Time {
+	return r.End.AddDate(0, 0, -1)
+}
+
+// TraderReport one trader's section of a report
+type TraderReport struct {
+	ID       string
+	Name     string
+	Exchange string
+	Stats    Stats
+	Equity   []EquityPoint
+	Trades   []Trade // Oldest first
+}
+
+// Stats trading statistics over the period
+type Stats struct {
+	StartEquity    float64
+	EndEquity      float64
+	Transfers      float64 // Net deposits minus withdrawals, excluded from the return
+	ReturnPct      float64
+	Trades         int
+	Wins           int
+	Losses         int
+	WinRate        float64 // Percentage
+	GrossPnL       float64 // Realized, before fees
+	Fees           float64
+	Funding        float64 // Signed, negative = paid
+	NetPnL         float64 // Gross PnL - fees + funding
+	ProfitFactor   float64 // Gross wins / gross losses, 0 without losses
+	BestTrade      float64
+	WorstTrade     float64
+	MaxDrawdownPct float64 // Largest peak-to-trough equity drop
+	AICalls        int
+	AITokens       int64   // Estimated from prompt and response sizes
+	AICostUSD      float64 // Estimated, 0 when no token price is configured
+}
+
+// EquityPoint one point of an equity curve
+type EquityPoint struct {
+	Time   time.Time
+	Equity float64
+}
+
+// Trade a position closed in the period
+type Trade struct {
+	Symbol      string
+	Side        string
+	Quantity    float64
+	EntryPrice  float64
+	ExitPrice   float64
+	EntryTime   time.Time
+	ExitTime    time.Time
+	PnL         float64
+	Fee         float64
+	CloseReason string
+}
+
+// PeriodAt bounds of the report period containing t: local midnight of its first day and of the day after its last
+func PeriodAt(period string, t time.Time, loc *time.Location) (start, end time.Time, err error) {
+	local := t.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	switch period {
+	case store.ReportPeriodWeekly:
+		start = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)) // Monday
+		return start, start.AddDate(0, 0, 7), nil
+	case store.ReportPeriodMonthly:
+		start = time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 1, 0), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("unknown report period %q, expected %s or %s", period, store.ReportPeriodWeekly, store.ReportPeriodMonthly)
+}
+
+// LastPeriod bounds of the last complete report period before now
+func LastPeriod(period string, now time.Time, loc *time.Location) (start, end time.Time, err error) {
+	current, _, err := PeriodAt(period, now, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return PeriodAt(period, current.AddDate(0, 0, -1), loc)
+}
+
+// Build gathers the report of a user's trader, or of all their traders when traderID is empty,
+// for [start, end). aiCostPerMTokens prices the estimated AI tokens in USD per million (0 = not priced).
+func Build(st *store.Store, userID, traderID, period string, start, end time.Time, aiCostPerMTokens float64) (*Report, error) {
+	var traders []*store.Trader
+	if traderID != "" {
+		t, err := st.Trader().Get(userID, traderID)
+		if err != nil {
+			return nil, fmt.Errorf("trader not found: %w", err)
+		}
+		traders = []*store.Trader{t}
+	} else {
+		var err error
+		if traders, err = st.Trader().List(userID); err != nil {
+			return nil, fmt.Errorf("failed to list traders: %w", err)
+		}
+	}
+
+	r := &Report{Period: period, Start: start, End: end, Timezone: start.Location().String(), Generated: time.Now()}
+	for _, t := range traders {
+		section, err := buildTrader(st, t, start, end, aiCostPerMTokens)
+		if err != nil {
+			return nil, fmt.Errorf("trader %s: %w", t.Name, err)
+		}
+		r.Traders = append(r.Traders, section)
+	}
+	r.Total = combine(r.Traders)
+	return r, nil
+}
+
+// buildTrader gathers one trader's section
+func buildTrader(st *store.Store, t *store.Trader, start, end time.Time, aiCostPerMTokens float64) (*TraderReport, error) {
+	fromMs, toMs := start.UnixMilli(), end.UnixMilli()-1
+	section := &TraderReport{ID: t.ID, Name: t.Name, Exchange: t.ExchangeID}
+
+	positions, err := st.Position().GetClosedPositionsInRange(t.ID, fromMs, toMs)
+	if err != nil {
+		return nil, err
+	}
+	for _, pos := range positions {
+		section.Trades = append(section.Trades, Trade{
+			Symbol:      pos.Symbol,
+			Side:        pos.Side,
+			Quantity:    pos.EntryQuantity,
+			EntryPrice:  pos.EntryPrice,
+			ExitPrice:   pos.ExitPrice,
+			EntryTime:   time.UnixMilli(pos.EntryTime).In(start.Location()),
+			ExitTime:    time.UnixMilli(pos.ExitTime).In(start.Location()),
+			PnL:         pos.RealizedPnL,
+			Fee:         pos.Fee,
+			CloseReason: pos.CloseReason,
+		})
+	}
+
+	snapshots, err := st.Equity().GetSeries(t.ID, start, end.Add(-time.Millisecond), equityPoints)
+	if err != nil {
+		return nil, err
+	}
+	for _, snap := range snapshots {
+		if snap.TotalEquity > 0 {
+			section.Equity = append(section.Equity, EquityPoint{Time: snap.Timestamp.In(start.Location()), Equity: snap.TotalEquity})
+		}
+	}
+
+	var funding, transfers float64
+	income, err := st.Income().List(t.ID, fromMs)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range income {
+		if rec.Time > toMs {
+			continue
+		}
+		switch rec.IncomeType {
+		case store.IncomeTypeFunding:
+			funding += rec.Amount
+		case store.IncomeTypeTransfer:
+			transfers += rec.Amount
+		}
+	}
+
+	section.Stats = computeStats(section.Trades, section.Equity, funding, transfers)
+
+	usage, err := st.Decision().GetAIUsage(t.ID, start, end.Add(-time.Millisecond))
+	if err != nil {
+		return nil, err
+	}
+	section.Stats.AICalls = usage.Calls
+	section.Stats.AITokens = (usage.PromptChars + usage.ResponseChars) / charsPerToken
+	section.Stats.AICostUSD = float64(section.Stats.AITokens) / 1e6 * aiCostPerMTokens
+	return section, nil
+}
+
+// computeStats statistics of a trader's closed trades and equity curve
+func computeStats(trades []Trade, equity []EquityPoint, funding, transfers float64) Stats {
+	s := Stats{Funding: funding, Transfers: transfers}
+	var grossWin, grossLoss float64
+	for i, t := range trades {
+		s.Trades++
+		s.GrossPnL += t.PnL
+		s.Fees += t.Fee
+		if t.PnL > 0 {
+			s.Wins++
+			grossWin += t.PnL
+		} else if t.PnL < 0 {
+			s.Losses++
+			grossLoss -= t.PnL
+		}
+		if i == 0 || t.PnL > s.BestTrade {
+			s.BestTrade = t.PnL
+		}
+		if i == 0 || t.PnL < s.WorstTrade {
+			s.WorstTrade = t.PnL
+		}
+	}
+	s.NetPnL = s.GrossPnL - s.Fees + s.Funding
+	if s.Trades > 0 {
+		s.WinRate = float64(s.Wins) / float64(s.Trades) * 100
+	}
+	if grossLoss > 0 {
+		s.ProfitFactor = grossWin / grossLoss
+	}
+
+	if len(equity) > 0 {
+		s.StartEquity = equity[0].Equity
+		s.EndEquity = equity[len(equity)-1].Equity
+		if s.StartEquity > 0 {
+			s.ReturnPct = (s.EndEquity - s.StartEquity - s.Transfers) / s.StartEquity * 100
+		}
+		s.MaxDrawdownPct = maxDrawdownPct(equity)
+	}
+	return s
+}
+
+// maxDrawdownPct largest peak-to-trough drop of an equity curve, in percent
+func maxDrawdownPct(equity []EquityPoint) float64 {
+	peak, maxDD := 0.0, 0.0
+	for _, p := range equity {
+		peak = math.Max(peak, p.Equity)
+		if peak > 0 {
+			maxDD = math.Max(maxDD, (peak-p.Equity)/peak*100)
+		}
+	}
+	return maxDD
+}
+
+// combine statistics of all traders: amounts are summed, the drawdown is the worst trader's
+func combine(traders []*TraderReport) Stats {
+	var total Stats
+	var grossWin, grossLoss float64
+	hasTrades := false
+	for _, t := range traders {
+		s := t.Stats
+		total.StartEquity += s.StartEquity
+		total.EndEquity += s.EndEquity
+		total.Transfers += s.Transfers
+		total.Trades += s.Trades
+		total.Wins += s.Wins
+		total.Losses += s.Losses
+		total.GrossPnL += s.GrossPnL
+		total.Fees += s.Fees
+		total.Funding += s.Funding
+		total.NetPnL += s.NetPnL
+		total.MaxDrawdownPct = math.Max(total.MaxDrawdownPct, s.MaxDrawdownPct)
+		total.AICalls += s.AICalls
+		total.AITokens += s.AITokens
+		total.AICostUSD += s.AICostUSD
+		if s.Trades > 0 {
+			if !hasTrades || s.BestTrade > total.BestTrade {
+				total.BestTrade = s.BestTrade
+			}
+			if !hasTrades || s.WorstTrade < total.WorstTrade {
+				total.WorstTrade = s.WorstTrade
+			}
+			hasTrades = true
+		}
+		for _, trade := range t.Trades {
+			if trade.PnL > 0 {
+				grossWin += trade.PnL
+			} else {
+				grossLoss -= trade.PnL
+			}
+		}
+	}
+	if total.Trades > 0 {
+		total.WinRate = float64(total.Wins) / float64(total.Trades) * 100
+	}
+	if grossLoss > 0 {
+		total.ProfitFactor = grossWin / grossLoss
+	}
+	if total.StartEquity > 0 {
+		total.ReturnPct = (total.EndEquity - total.StartEquity - total.Transfers) / total.StartEquity * 100
+	}
+	return total
+}
@@ -0,0 +1,168 @@
+package report
+
+import (
+	"bytes"
+	"math"
+	"nofx/store"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPeriodAt(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	// Wednesday
+	now := time.Date(2026, 3, 11, 15, 0, 0, 0, loc)
+
+	start, end, err := PeriodAt(store.ReportPeriodWeekly, now, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !start.Equal(time.Date(2026, 3, 9, 0, 0, 0, 0, loc)) || !end.Equal(time.Date(2026, 3, 16, 0, 0, 0, 0, loc)) {
+		t.Errorf("week = %v – %v, want Monday 9th to Monday 16th", start, end)
+	}
+
+	start, end, err = PeriodAt(store.ReportPeriodMonthly, now, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !start.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, loc)) || !end.Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, loc)) {
+		t.Errorf("month = %v – %v, want March", start, end)
+	}
+
+	if _, _, err := PeriodAt("daily", now, loc); err == nil {
+		t.Error("unknown period accepted")
+	}
+}
+
+func TestLastPeriod(t *testing.T) {
+	// Monday 00:30 local: the week that just ended is complete
+	now := time.Date(2026, 3, 16, 0, 30, 0, 0, time.UTC)
+	start, end, _ := LastPeriod(store.ReportPeriodWeekly, now, time.UTC)
+	if !start.Equal(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("last week = %v – %v", start, end)
+	}
+
+	// Sunday is still part of the current week
+	start, _, _ = LastPeriod(store.ReportPeriodWeekly, now.AddDate(0, 0, -1), time.UTC)
+	if !start.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("last week on Sunday starts %v, want 2nd", start)
+	}
+
+	start, end, _ = LastPeriod(store.ReportPeriodMonthly, time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), time.UTC)
+	if !start.Equal(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("last month = %v – %v, want December", start, end)
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	trades := []Trade{{PnL: 30, Fee: 1}, {PnL: -10, Fee: 1}, {PnL: 20, Fee: 1}, {PnL: -5, Fee: 1}}
+	day := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	equity := []EquityPoint{
+		{Time: day, Equity: 1000},
+		{Time: day.Add(time.Hour), Equity: 1200},
+		{Time: day.Add(2 * time.Hour), Equity: 900},
+		{Time: day.Add(3 * time.Hour), Equity: 1130},
+	}
+
+	s := computeStats(trades, equity, -2, 100)
+	if s.Trades != 4 || s.Wins != 2 || s.Losses != 2 || s.WinRate != 50 {
+		t.Errorf("trades = %d, %d W / %d L, win rate %.1f", s.Trades, s.Wins, s.Losses, s.WinRate)
+	}
+	if s.GrossPnL != 35 || s.Fees != 4 || s.NetPnL != 29 {
+		t.Errorf("gross %.2f fees %.2f net %.2f, want 35, 4, 29", s.GrossPnL, s.Fees, s.NetPnL)
+	}
+	if math.Abs(s.ProfitFactor-50.0/15) > 1e-9 {
+		t.Errorf("profit factor = %.4f", s.ProfitFactor)
+	}
+	if s.BestTrade != 30 || s.WorstTrade != -10 {
+		t.Errorf("best/worst = %.2f / %.2f", s.BestTrade, s.WorstTrade)
+	}
+	// The 100 deposited is not counted as return
+	if math.Abs(s.ReturnPct-3) > 1e-9 {
+		t.Errorf("return = %.4f%%, want 3%%", s.ReturnPct)
+	}
+	if math.Abs(s.MaxDrawdownPct-25) > 1e-9 {
+		t.Errorf("max drawdown = %.4f%%, want 25%%", s.MaxDrawdownPct)
+	}
+
+	if s := computeStats(nil, nil, 0, 0); s.Trades != 0 || s.ReturnPct != 0 || s.ProfitFactor != 0 {
+		t.Errorf("empty stats = %+v", s)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	traders := []*TraderReport{
+		{Stats: Stats{Trades: 0, StartEquity: 500, EndEquity: 500}},
+		{Trades: []Trade{{PnL: -4}, {PnL: 8}}, Stats: Stats{Trades: 2, Wins: 1, Losses: 1, BestTrade: 8, WorstTrade: -4, StartEquity: 500, EndEquity: 520, MaxDrawdownPct: 3}},
+	}
+	total := combine(traders)
+	if total.BestTrade != 8 || total.WorstTrade != -4 {
+		t.Errorf("best/worst = %.2f / %.2f, a trader without trades must not count", total.BestTrade, total.WorstTrade)
+	}
+	if total.ProfitFactor != 2 || total.WinRate != 50 || total.MaxDrawdownPct != 3 {
+		t.Errorf("combined = %+v", total)
+	}
+	if math.Abs(total.ReturnPct-2) > 1e-9 {
+		t.Errorf("return = %.4f%%, want 2%%", total.ReturnPct)
+	}
+}
+
+func testReport() *Report {
+	start := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	trader := &TraderReport{
+		ID:       "t1",
+		Name:     "BTC Scalper",
+		Exchange: "binance",
+		Equity:   []EquityPoint{{Time: start, Equity: 1000}, {Time: start.Add(48 * time.Hour), Equity: 1050}},
+		Trades: []Trade{{
+			Symbol: "BTCUSDT", Side: "long", Quantity: 0.01, EntryPrice: 80000, ExitPrice: 85000,
+			EntryTime: start, ExitTime: start.Add(time.Hour), PnL: 50, Fee: 0.5, CloseReason: "take_profit",
+		}},
+	}
+	trader.Stats = computeStats(trader.Trades, trader.Equity, 0, 0)
+	return &Report{
+		Period:    store.ReportPeriodWeekly,
+		Start:     start,
+		End:       start.AddDate(0, 0, 7),
+		Timezone:  "UTC",
+		Generated: start.AddDate(0, 0, 7),
+		Traders:   []*TraderReport{trader},
+		Total:     trader.Stats,
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	html, err := RenderHTML(testReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"BTC Scalper", "2026-03-09 – 2026-03-15", "BTCUSDT", "<polyline"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML report is missing %q", want)
+		}
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	pdf, err := RenderPDF(testReport())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) || !bytes.Contains(pdf, []byte("xref")) || !bytes.HasSuffix(bytes.TrimSpace(pdf), []byte("%%EOF")) {
+		t.Errorf("not a well-formed PDF: %q…", pdf[:min(len(pdf), 64)])
+	}
+}
+
+func TestPDFName(t *testing.T) {
+	for _, tt := range []struct{ name, id, want string }{
+		{"BTC Scalper", "t1", "BTC Scalper"},
+		{"Café ß", "t1", "Café ß"},
+		{"BTC 趋势 跟踪", "t1", "BTC (t1)"},
+		{"趋势跟踪", "t1", "t1"},
+	} {
+		if got := pdfName(tt.name, tt.id); got != tt.want {
+			t.Errorf("pdfName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
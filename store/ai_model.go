@@ -137,6 +137,13 @@ func (s *AIModelStore) firstEnabled(userID string) (*AIModel, error) {
 	return &model, nil
 }
 
+// CountEnabled number of enabled AI models across all users
+func (s *AIModelStore) CountEnabled() (int64, error) {
+	var count int64
+	err := s.db.Model(&AIModel{}).Where("enabled = ?", true).Count(&count).Error
+	return count, err
+}
+
 // Update updates AI model, creates if not exists
 // IMPORTANT: If apiKey is empty string, the existing API key will be preserved (not overwritten)
 func (s *AIModelStore) Update(userID, id string, enabled bool, apiKey, customAPIURL, customModelName string) error {
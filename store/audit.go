@@ -0,0 +1,120 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditStore append-only audit log storage
+// Only insert and query operations are exposed; records are never updated or deleted.
+type AuditStore struct {
+	db *gorm.DB
+}
+
+// AuditLog one mutating API action
+type AuditLog struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Timestamp  time.Time `gorm:"not null;index:idx_audit_time,sort:desc" json:"timestamp"`
+	UserID     string    `gorm:"column:user_id;not null;default:'';index:idx_audit_user_time" json:"user_id"`
+	Email      string    `gorm:"column:email;not null;default:''" json:"email"`
+	IP         string    `gorm:"column:ip;not null;default:''" json:"ip"`
+	Action     string    `gorm:"column:action;not null;index:idx_audit_action" json:"action"` // e.g. trader.create
+	Method     string    `gorm:"column:method;not null" json:"method"`
+	Path       string    `gorm:"column:path;not null" json:"path"`
+	ResourceID string    `gorm:"column:resource_id;not null;default:''" json:"resource_id"`
+	Payload    string    `gorm:"column:payload;type:text;not null;default:''" json:"payload"` // Sanitized request summary
+	StatusCode int       `gorm:"column:status_code;not null;default:0" json:"status_code"`
+	Success    bool      `gorm:"column:success;not null;default:false" json:"success"`
+	Error      string    `gorm:"column:error;not null;default:''" json:"error,omitempty"`
+	DurationMs int64     `gorm:"column:duration_ms;not null;default:0" json:"duration_ms"`
+}
+
+func (AuditLog) TableName() string { return "audit_logs" }
+
+// AuditFilter audit log query filter
+type AuditFilter struct {
+	UserID     string
+	Action     string // Exact action or prefix ending with "." (e.g. "trader.")
+	ResourceID string
+	Success    *bool
+	Start      time.Time
+	End        time.Time
+	Limit      int
+	Offset     int
+}
+
+// NewAuditStore creates a new AuditStore
+func NewAuditStore(db *gorm.DB) *AuditStore {
+	return &AuditStore{db: db}
+}
+
+func (s *AuditStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'audit_logs'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&AuditLog{})
+}
+
+// Append appends an audit record
+func (s *AuditStore) Append(entry *AuditLog) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	} else {
+		entry.Timestamp = entry.Timestamp.UTC()
+	}
+	// Omit ID to let PostgreSQL sequence auto-generate it
+	if err := s.db.Omit("ID").Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to append audit log: %w", err)
+	}
+	return nil
+}
+
+// Query queries audit records (newest first), returns records and total count
+func (s *AuditStore) Query(filter AuditFilter) ([]*AuditLog, int64, error) {
+	q := s.db.Model(&AuditLog{})
+	if filter.UserID != "" {
+		q = q.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Action != "" {
+		if filter.Action[len(filter.Action)-1] == '.' {
+			q = q.Where("action LIKE ?", filter.Action+"%")
+		} else {
+			q = q.Where("action = ?", filter.Action)
+		}
+	}
+	if filter.ResourceID != "" {
+		q = q.Where("resource_id = ?", filter.ResourceID)
+	}
+	if filter.Success != nil {
+		q = q.Where("success = ?", *filter.Success)
+	}
+	if !filter.Start.IsZero() {
+		q = q.Where("timestamp >= ?", filter.Start.UTC())
+	}
+	if !filter.End.IsZero() {
+		q = q.Where("timestamp <= ?", filter.End.UTC())
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	var logs []*AuditLog
+	err := q.Order("timestamp DESC, id DESC").Limit(limit).Offset(filter.Offset).Find(&logs).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query audit logs: %w", err)
+	}
+	return logs, total, nil
+}
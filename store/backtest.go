@@ -209,6 +209,34 @@ func (BacktestDecision) TableName() string {
 	return "backtest_decisions"
 }
 
+// BacktestSweep GORM model, payload holds the sweep's parameters and result matrix
+type BacktestSweep struct {
+	SweepID   string    `gorm:"column:sweep_id;primaryKey"`
+	UserID    string    `gorm:"column:user_id;not null;default:'';index"`
+	State     string    `gorm:"column:state;not null;default:running"`
+	Payload   []byte    `gorm:"column:payload;not null"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func (BacktestSweep) TableName() string {
+	return "backtest_sweeps"
+}
+
+// BacktestWalkForward GORM model, payload holds the windows and their aggregated metrics
+type BacktestWalkForward struct {
+	WalkForwardID string    `gorm:"column:walk_forward_id;primaryKey"`
+	UserID        string    `gorm:"column:user_id;not null;default:'';index"`
+	State         string    `gorm:"column:state;not null;default:running"`
+	Payload       []byte    `gorm:"column:payload;not null"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+	UpdatedAt     time.Time `gorm:"column:updated_at"`
+}
+
+func (BacktestWalkForward) TableName() string {
+	return "backtest_walk_forwards"
+}
+
 // initTables initializes backtest related tables
 func (s *BacktestStore) initTables() error {
 	// For PostgreSQL with existing tables, skip AutoMigrate to avoid type conflicts
@@ -224,6 +252,14 @@ func (s *BacktestStore) initTables() error {
 			s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_backtest_equity_run_ts ON backtest_equity(run_id, ts)`)
 			s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_backtest_trades_run_ts ON backtest_trades(run_id, ts)`)
 			s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_backtest_decisions_run_cycle ON backtest_decisions(run_id, cycle)`)
+			// Tables added after the initial schema
+			for _, table := range []any{&BacktestSweep{}, &BacktestWalkForward{}} {
+				if !s.db.Migrator().HasTable(table) {
+					if err := s.db.AutoMigrate(table); err != nil {
+						return fmt.Errorf("failed to migrate backtest tables: %w", err)
+					}
+				}
+			}
 			return nil
 		}
 	}
@@ -236,6 +272,8 @@ func (s *BacktestStore) initTables() error {
 		&BacktestTrade{},
 		&BacktestMetrics{},
 		&BacktestDecision{},
+		&BacktestSweep{},
+		&BacktestWalkForward{},
 	); err != nil {
 		return fmt.Errorf("failed to migrate backtest tables: %w", err)
 	}
@@ -0,0 +1,146 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultCircuitBreakerCooldownMinutes cooldown applied when a limit is set but no cooldown is configured
+const DefaultCircuitBreakerCooldownMinutes = 60
+
+// CircuitBreakerConfig automatic pause thresholds (part of strategy risk control)
+// Zero values fall back to the global defaults; both zero disables that check.
+type CircuitBreakerConfig struct {
+	// Stop trader when equity drawdown from peak reaches this percentage (e.g. 20 = 20%)
+	MaxDrawdownPct float64 `json:"max_drawdown_pct"`
+	// Stop trader after this many consecutive losing closed trades
+	MaxConsecutiveLosses int `json:"max_consecutive_losses"`
+	// Minutes the trader must stay stopped before it can be restarted
+	CooldownMinutes int `json:"cooldown_minutes"`
+}
+
+// WithDefaults fills unset fields from global defaults
+func (c CircuitBreakerConfig) WithDefaults(global CircuitBreakerConfig) CircuitBreakerConfig {
+	if c.MaxDrawdownPct <= 0 {
+		c.MaxDrawdownPct = global.MaxDrawdownPct
+	}
+	if c.MaxConsecutiveLosses <= 0 {
+		c.MaxConsecutiveLosses = global.MaxConsecutiveLosses
+	}
+	if c.CooldownMinutes <= 0 {
+		c.CooldownMinutes = global.CooldownMinutes
+	}
+	if c.CooldownMinutes <= 0 {
+		c.CooldownMinutes = DefaultCircuitBreakerCooldownMinutes
+	}
+	return c
+}
+
+// Enabled returns whether any limit is configured
+func (c CircuitBreakerConfig) Enabled() bool {
+	return c.MaxDrawdownPct > 0 || c.MaxConsecutiveLosses > 0
+}
+
+// CircuitBreakerState persisted circuit breaker state per trader
+type CircuitBreakerState struct {
+	TraderID      string    `gorm:"primaryKey;column:trader_id" json:"trader_id"`
+	UserID        string    `gorm:"column:user_id;not null;default:'';index" json:"user_id"`
+	PeakEquity    float64   `gorm:"column:peak_equity;default:0" json:"peak_equity"`
+	Tripped       bool      `gorm:"column:tripped;default:false" json:"tripped"`
+	TripReason    string    `gorm:"column:trip_reason;default:''" json:"trip_reason"`
+	TrippedAt     time.Time `gorm:"column:tripped_at" json:"tripped_at"`
+	CooldownUntil time.Time `gorm:"column:cooldown_until" json:"cooldown_until"`
+	ResetAt       time.Time `gorm:"column:reset_at" json:"reset_at"` // Losses before this time are not counted
+	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (CircuitBreakerState) TableName() string { return "trader_circuit_breakers" }
+
+// InCooldown returns whether the trader is tripped and still cooling down
+func (s *CircuitBreakerState) InCooldown(now time.Time) bool {
+	return s != nil && s.Tripped && now.Before(s.CooldownUntil)
+}
+
+// CircuitBreakerStore circuit breaker state storage
+type CircuitBreakerStore struct {
+	db *gorm.DB
+}
+
+// NewCircuitBreakerStore creates a new CircuitBreakerStore
+func NewCircuitBreakerStore(db *gorm.DB) *CircuitBreakerStore {
+	return &CircuitBreakerStore{db: db}
+}
+
+func (s *CircuitBreakerStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'trader_circuit_breakers'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&CircuitBreakerState{})
+}
+
+// Get gets trader circuit breaker state, returns nil if none recorded
+func (s *CircuitBreakerStore) Get(traderID string) (*CircuitBreakerState, error) {
+	var state CircuitBreakerState
+	err := s.db.Where("trader_id = ?", traderID).First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get circuit breaker state: %w", err)
+	}
+	return &state, nil
+}
+
+// Save upserts circuit breaker state
+func (s *CircuitBreakerStore) Save(state *CircuitBreakerState) error {
+	return s.db.Save(state).Error
+}
+
+// Trip marks trader as tripped until cooldownUntil
+func (s *CircuitBreakerStore) Trip(state *CircuitBreakerState, reason string, cooldownUntil time.Time) error {
+	now := time.Now().UTC()
+	state.Tripped = true
+	state.TripReason = reason
+	state.TrippedAt = now
+	state.CooldownUntil = cooldownUntil.UTC()
+	return s.Save(state)
+}
+
+// Reset clears trip state and restarts peak/loss tracking from now
+func (s *CircuitBreakerStore) Reset(traderID, userID string) error {
+	return s.Save(&CircuitBreakerState{
+		TraderID: traderID,
+		UserID:   userID,
+		ResetAt:  time.Now().UTC(),
+	})
+}
+
+// CountConsecutiveLosses counts most recent consecutive losing closed positions
+// Only positions closed at or after sinceMs (Unix milliseconds) are considered.
+func (s *PositionStore) CountConsecutiveLosses(traderID string, sinceMs int64, max int) (int, error) {
+	var pnls []float64
+	err := s.db.Model(&TraderPosition{}).
+		Where("trader_id = ? AND status = ? AND exit_time >= ?", traderID, "CLOSED", sinceMs).
+		Order("exit_time DESC").
+		Limit(max).
+		Pluck("realized_pnl", &pnls).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to query closed positions: %w", err)
+	}
+	count := 0
+	for _, pnl := range pnls {
+		if pnl >= 0 {
+			break
+		}
+		count++
+	}
+	return count, nil
+}
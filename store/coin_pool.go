@@ -0,0 +1,228 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"nofx/market"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Trader coin override modes
+const (
+	CoinOverridePin       = "pin"       // Always include in candidate coins
+	CoinOverrideBlacklist = "blacklist" // Never include in candidate coins
+)
+
+// CoinPool user-defined candidate coin pool (consumed by strategies with source_type "custom")
+type CoinPool struct {
+	ID          string    `gorm:"primaryKey" json:"id"`
+	UserID      string    `gorm:"column:user_id;not null;index" json:"user_id"`
+	Name        string    `gorm:"not null" json:"name"`
+	Description string    `gorm:"default:''" json:"description"`
+	Symbols     string    `gorm:"not null;default:'[]'" json:"-"` // JSON array of normalized symbols
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (CoinPool) TableName() string { return "coin_pools" }
+
+// GetSymbols parses pool symbols
+func (p *CoinPool) GetSymbols() []string {
+	var symbols []string
+	if err := json.Unmarshal([]byte(p.Symbols), &symbols); err != nil {
+		return nil
+	}
+	return symbols
+}
+
+// SetSymbols normalizes, de-duplicates and stores pool symbols
+func (p *CoinPool) SetSymbols(symbols []string) {
+	data, _ := json.Marshal(NormalizeCoinSymbols(symbols))
+	p.Symbols = string(data)
+}
+
+// MarshalJSON exposes symbols as an array
+func (p CoinPool) MarshalJSON() ([]byte, error) {
+	type alias CoinPool
+	return json.Marshal(struct {
+		alias
+		Symbols []string `json:"symbols"`
+	}{alias: alias(p), Symbols: p.GetSymbols()})
+}
+
+// TraderCoinOverride per-trader pinned / blacklisted symbol
+type TraderCoinOverride struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID  string    `gorm:"column:trader_id;not null;uniqueIndex:idx_coin_override_unique,priority:1" json:"trader_id"`
+	Symbol    string    `gorm:"column:symbol;not null;uniqueIndex:idx_coin_override_unique,priority:2" json:"symbol"`
+	Mode      string    `gorm:"column:mode;not null" json:"mode"` // pin/blacklist
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (TraderCoinOverride) TableName() string { return "trader_coin_overrides" }
+
+// NormalizeCoinSymbols normalizes symbols (see market.Normalize) and drops empties and duplicates
+func NormalizeCoinSymbols(symbols []string) []string {
+	seen := make(map[string]bool, len(symbols))
+	result := make([]string, 0, len(symbols))
+	for _, s := range symbols {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		s = market.Normalize(s)
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		result = append(result, s)
+	}
+	return result
+}
+
+// CoinPoolStore coin pool and trader override storage
+type CoinPoolStore struct {
+	db *gorm.DB
+}
+
+// NewCoinPoolStore creates a new CoinPoolStore
+func NewCoinPoolStore(db *gorm.DB) *CoinPoolStore {
+	return &CoinPoolStore{db: db}
+}
+
+func (s *CoinPoolStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'coin_pools'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&CoinPool{}, &TraderCoinOverride{})
+}
+
+// List gets user's custom coin pools
+func (s *CoinPoolStore) List(userID string) ([]*CoinPool, error) {
+	var pools []*CoinPool
+	err := s.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&pools).Error
+	return pools, err
+}
+
+// Get gets a single custom coin pool
+func (s *CoinPoolStore) Get(userID, id string) (*CoinPool, error) {
+	var pool CoinPool
+	err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&pool).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pool, nil
+}
+
+// GetSymbols gets symbols of a user's pool (used by the decision engine)
+func (s *CoinPoolStore) GetSymbols(userID, id string) ([]string, error) {
+	var pool CoinPool
+	if err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&pool).Error; err != nil {
+		return nil, fmt.Errorf("coin pool %s not found: %w", id, err)
+	}
+	return pool.GetSymbols(), nil
+}
+
+// Create creates a custom coin pool
+func (s *CoinPoolStore) Create(pool *CoinPool) error {
+	return s.db.Create(pool).Error
+}
+
+// Update updates name, description and symbols of a custom coin pool
+func (s *CoinPoolStore) Update(pool *CoinPool) error {
+	result := s.db.Model(&CoinPool{}).
+		Where("id = ? AND user_id = ?", pool.ID, pool.UserID).
+		Updates(map[string]interface{}{
+			"name":        pool.Name,
+			"description": pool.Description,
+			"symbols":     pool.Symbols,
+			"updated_at":  time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete deletes a custom coin pool
+func (s *CoinPoolStore) Delete(userID, id string) error {
+	return s.db.Where("id = ? AND user_id = ?", id, userID).Delete(&CoinPool{}).Error
+}
+
+// ListOverrides gets trader's pinned / blacklisted symbols
+func (s *CoinPoolStore) ListOverrides(traderID string) ([]*TraderCoinOverride, error) {
+	var overrides []*TraderCoinOverride
+	err := s.db.Where("trader_id = ?", traderID).Order("symbol ASC").Find(&overrides).Error
+	return overrides, err
+}
+
+// SetOverride pins or blacklists a symbol for a trader (replaces existing mode)
+func (s *CoinPoolStore) SetOverride(traderID, symbol, mode string) error {
+	if mode != CoinOverridePin && mode != CoinOverrideBlacklist {
+		return fmt.Errorf("invalid override mode: %s", mode)
+	}
+	symbols := NormalizeCoinSymbols([]string{symbol})
+	if len(symbols) == 0 {
+		return fmt.Errorf("symbol is required")
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("trader_id = ? AND symbol = ?", traderID, symbols[0]).Delete(&TraderCoinOverride{}).Error; err != nil {
+			return err
+		}
+		return tx.Omit("ID").Create(&TraderCoinOverride{TraderID: traderID, Symbol: symbols[0], Mode: mode}).Error
+	})
+}
+
+// RemoveOverride removes a trader's pin/blacklist for a symbol
+func (s *CoinPoolStore) RemoveOverride(traderID, symbol string) error {
+	symbols := NormalizeCoinSymbols([]string{symbol})
+	if len(symbols) == 0 {
+		return nil
+	}
+	return s.db.Where("trader_id = ? AND symbol = ?", traderID, symbols[0]).Delete(&TraderCoinOverride{}).Error
+}
+
+// ForTrader returns a view resolving custom pools and overrides for one trader's decision engine
+// Only pools owned by userID (the trader's owner) are resolved.
+func (s *CoinPoolStore) ForTrader(userID, traderID string) *TraderCoinPool {
+	return &TraderCoinPool{store: s, userID: userID, traderID: traderID}
+}
+
+// TraderCoinPool trader-bound coin pool view (implements kernel.CoinPoolSource)
+type TraderCoinPool struct {
+	store    *CoinPoolStore
+	userID   string
+	traderID string
+}
+
+// CustomPoolSymbols gets symbols of one of the trader owner's custom pools
+func (v *TraderCoinPool) CustomPoolSymbols(poolID string) ([]string, error) {
+	return v.store.GetSymbols(v.userID, poolID)
+}
+
+// TraderOverrides gets pinned and blacklisted symbols
+func (v *TraderCoinPool) TraderOverrides() (pinned, blacklisted []string, err error) {
+	overrides, err := v.store.ListOverrides(v.traderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, o := range overrides {
+		if o.Mode == CoinOverridePin {
+			pinned = append(pinned, o.Symbol)
+		} else if o.Mode == CoinOverrideBlacklist {
+			blacklisted = append(blacklisted, o.Symbol)
+		}
+	}
+	return pinned, blacklisted, nil
+}
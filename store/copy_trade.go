@@ -0,0 +1,97 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CopyTradeLink follower trader mirroring the executed decisions of a leader trader
+// Opening sizes are scaled by follower equity / leader equity, then capped.
+type CopyTradeLink struct {
+	FollowerID      string    `gorm:"column:follower_id;primaryKey" json:"follower_id"`
+	LeaderID        string    `gorm:"column:leader_id;not null;index" json:"leader_id"`
+	UserID          string    `gorm:"column:user_id;not null;index" json:"user_id"`
+	Enabled         bool      `gorm:"column:enabled;not null" json:"enabled"`
+	MaxPositionUSD  float64   `gorm:"column:max_position_usd;default:0" json:"max_position_usd"`  // Cap per copied position (0 = no cap)
+	MaxLeverage     int       `gorm:"column:max_leverage;default:0" json:"max_leverage"`          // Cap on copied leverage (0 = leader's leverage)
+	SymbolBlacklist string    `gorm:"column:symbol_blacklist;default:''" json:"symbol_blacklist"` // Comma-separated symbols never copied
+	CreatedAt       time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName returns the table name for CopyTradeLink
+func (CopyTradeLink) TableName() string {
+	return "copy_trade_links"
+}
+
+// BlacklistSymbols returns the blacklisted symbols, upper-cased
+func (l *CopyTradeLink) BlacklistSymbols() []string {
+	var symbols []string
+	for _, s := range strings.Split(l.SymbolBlacklist, ",") {
+		if s = strings.ToUpper(strings.TrimSpace(s)); s != "" {
+			symbols = append(symbols, s)
+		}
+	}
+	return symbols
+}
+
+// CopyTradeStore copy trading link storage
+type CopyTradeStore struct {
+	db *gorm.DB
+}
+
+// NewCopyTradeStore creates a new CopyTradeStore
+func NewCopyTradeStore(db *gorm.DB) *CopyTradeStore {
+	return &CopyTradeStore{db: db}
+}
+
+func (s *CopyTradeStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'copy_trade_links'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&CopyTradeLink{})
+}
+
+// GetByFollower gets the link of a follower trader, returns nil if it follows no one
+func (s *CopyTradeStore) GetByFollower(followerID string) (*CopyTradeLink, error) {
+	var link CopyTradeLink
+	err := s.db.Where("follower_id = ?", followerID).First(&link).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get copy trade link: %w", err)
+	}
+	return &link, nil
+}
+
+// ListByLeader gets the links of all followers of a leader trader
+func (s *CopyTradeStore) ListByLeader(leaderID string) ([]*CopyTradeLink, error) {
+	var links []*CopyTradeLink
+	if err := s.db.Where("leader_id = ?", leaderID).Order("created_at ASC").Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("failed to list copy trade followers: %w", err)
+	}
+	return links, nil
+}
+
+// Save upserts a follower's link (a follower has at most one leader)
+func (s *CopyTradeStore) Save(link *CopyTradeLink) error {
+	if err := s.db.Save(link).Error; err != nil {
+		return fmt.Errorf("failed to save copy trade link: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a follower's link
+func (s *CopyTradeStore) Delete(userID, followerID string) error {
+	return s.db.Where("user_id = ? AND follower_id = ?", userID, followerID).Delete(&CopyTradeLink{}).Error
+}
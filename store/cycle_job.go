@@ -0,0 +1,251 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Cycle job kinds
+const (
+	CycleJobDecision = "decision" // AI decision cycle
+	CycleJobGrid     = "grid"     // Grid strategy cycle
+)
+
+// Cycle job statuses
+const (
+	CycleJobPending = "pending" // Waiting until ScheduledAt (new cycles and retries)
+	CycleJobRunning = "running" // Claimed and executing
+	CycleJobDone    = "done"
+	CycleJobFailed  = "failed" // Out of attempts
+)
+
+// CycleJob one scheduled trading cycle of a trader
+// Each cycle is enqueued, claimed, executed and marked done, so a cycle interrupted by a
+// crash is found still running on restart and retried.
+type CycleJob struct {
+	ID          int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID    string     `gorm:"column:trader_id;not null;index:idx_cycle_jobs_trader_status" json:"trader_id"`
+	Kind        string     `gorm:"column:kind;not null" json:"kind"`
+	Status      string     `gorm:"column:status;not null;index:idx_cycle_jobs_trader_status" json:"status"`
+	ScheduledAt time.Time  `gorm:"column:scheduled_at;not null" json:"scheduled_at"`
+	StartedAt   *time.Time `gorm:"column:started_at" json:"started_at,omitempty"`
+	CompletedAt *time.Time `gorm:"column:completed_at" json:"completed_at,omitempty"`
+	Attempts    int        `gorm:"column:attempts;not null;default:0" json:"attempts"`
+	LastError   string     `gorm:"column:last_error;type:text;default:''" json:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName returns the table name for CycleJob
+func (CycleJob) TableName() string { return "trader_cycle_jobs" }
+
+// CycleJobCounts queue overview of a trader
+type CycleJobCounts struct {
+	Pending   int64 `json:"pending"`
+	Running   int64 `json:"running"`
+	Late      int64 `json:"late"`       // Pending jobs overdue by more than the given grace period
+	Failed24h int64 `json:"failed_24h"` // Jobs out of attempts in the last 24 hours
+}
+
+// CycleJobStore persistent decision cycle queue storage
+type CycleJobStore struct {
+	db *gorm.DB
+}
+
+// NewCycleJobStore creates a new CycleJobStore
+func NewCycleJobStore(db *gorm.DB) *CycleJobStore {
+	return &CycleJobStore{db: db}
+}
+
+func (s *CycleJobStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'trader_cycle_jobs'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&CycleJob{})
+}
+
+// Enqueue schedules a cycle unless the trader already has one pending or running
+// Returns whether a job was created.
+func (s *CycleJobStore) Enqueue(traderID, kind string, scheduledAt time.Time) (bool, error) {
+	created := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var open int64
+		if err := tx.Model(&CycleJob{}).
+			Where("trader_id = ? AND status IN ?", traderID, []string{CycleJobPending, CycleJobRunning}).
+			Count(&open).Error; err != nil {
+			return err
+		}
+		if open > 0 {
+			return nil
+		}
+		job := &CycleJob{TraderID: traderID, Kind: kind, Status: CycleJobPending, ScheduledAt: scheduledAt.UTC()}
+		if err := tx.Omit("ID").Create(job).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to enqueue cycle job: %w", err)
+	}
+	return created, nil
+}
+
+// ClaimDue marks the oldest pending job due at now as running, nil if none is due
+func (s *CycleJobStore) ClaimDue(traderID string, now time.Time) (*CycleJob, error) {
+	var job CycleJob
+	err := s.db.Where("trader_id = ? AND status = ? AND scheduled_at <= ?", traderID, CycleJobPending, now.UTC()).
+		Order("scheduled_at ASC").
+		First(&job).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cycle jobs: %w", err)
+	}
+
+	started := now.UTC()
+	result := s.db.Model(&CycleJob{}).
+		Where("id = ? AND status = ?", job.ID, CycleJobPending).
+		Updates(map[string]interface{}{
+			"status":     CycleJobRunning,
+			"started_at": started,
+			"attempts":   gorm.Expr("attempts + 1"),
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to claim cycle job: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil // Claimed elsewhere
+	}
+	job.Status = CycleJobRunning
+	job.StartedAt = &started
+	job.Attempts++
+	return &job, nil
+}
+
+// Complete marks a job done
+func (s *CycleJobStore) Complete(id int64) error {
+	now := time.Now().UTC()
+	err := s.db.Model(&CycleJob{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       CycleJobDone,
+		"completed_at": now,
+		"last_error":   "",
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to complete cycle job: %w", err)
+	}
+	return nil
+}
+
+// Fail records a failed attempt, rescheduling the job at retryAt while it has attempts left
+// Returns whether the job is out of attempts.
+func (s *CycleJobStore) Fail(job *CycleJob, errMsg string, retryAt time.Time, maxAttempts int) (bool, error) {
+	updates := map[string]interface{}{"last_error": errMsg}
+	final := job.Attempts >= maxAttempts
+	if final {
+		updates["status"] = CycleJobFailed
+		updates["completed_at"] = time.Now().UTC()
+	} else {
+		updates["status"] = CycleJobPending
+		updates["scheduled_at"] = retryAt.UTC()
+	}
+	if err := s.db.Model(&CycleJob{}).Where("id = ?", job.ID).Updates(updates).Error; err != nil {
+		return final, fmt.Errorf("failed to record cycle job failure: %w", err)
+	}
+	return final, nil
+}
+
+// RecoverInterrupted requeues a trader's jobs left running by a crash or restart, failing those out of attempts
+// Returns the number of jobs requeued.
+func (s *CycleJobStore) RecoverInterrupted(traderID string, maxAttempts int, now time.Time) (int64, error) {
+	const reason = "interrupted: process stopped mid-cycle"
+	err := s.db.Model(&CycleJob{}).
+		Where("trader_id = ? AND status = ? AND attempts >= ?", traderID, CycleJobRunning, maxAttempts).
+		Updates(map[string]interface{}{"status": CycleJobFailed, "completed_at": now.UTC(), "last_error": reason}).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail interrupted cycle jobs: %w", err)
+	}
+	result := s.db.Model(&CycleJob{}).
+		Where("trader_id = ? AND status = ?", traderID, CycleJobRunning).
+		Updates(map[string]interface{}{"status": CycleJobPending, "scheduled_at": now.UTC(), "last_error": reason})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to requeue interrupted cycle jobs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// NextPending gets a trader's earliest pending job, nil if none
+func (s *CycleJobStore) NextPending(traderID string) (*CycleJob, error) {
+	var job CycleJob
+	err := s.db.Where("trader_id = ? AND status = ?", traderID, CycleJobPending).
+		Order("scheduled_at ASC").
+		First(&job).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cycle jobs: %w", err)
+	}
+	return &job, nil
+}
+
+// List gets a trader's most recent jobs, newest first
+func (s *CycleJobStore) List(traderID string, limit int) ([]*CycleJob, error) {
+	var jobs []*CycleJob
+	err := s.db.Where("trader_id = ?", traderID).
+		Order("scheduled_at DESC, id DESC").
+		Limit(limit).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cycle jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// Counts summarizes a trader's queue, pending jobs due more than lateAfter ago count as late
+func (s *CycleJobStore) Counts(traderID string, lateAfter time.Duration, now time.Time) (*CycleJobCounts, error) {
+	counts := &CycleJobCounts{}
+	base := func() *gorm.DB { return s.db.Model(&CycleJob{}).Where("trader_id = ?", traderID) }
+	if err := base().Where("status = ?", CycleJobPending).Count(&counts.Pending).Error; err != nil {
+		return nil, fmt.Errorf("failed to count cycle jobs: %w", err)
+	}
+	if err := base().Where("status = ?", CycleJobRunning).Count(&counts.Running).Error; err != nil {
+		return nil, fmt.Errorf("failed to count cycle jobs: %w", err)
+	}
+	if err := base().Where("status = ? AND scheduled_at < ?", CycleJobPending, now.Add(-lateAfter).UTC()).
+		Count(&counts.Late).Error; err != nil {
+		return nil, fmt.Errorf("failed to count cycle jobs: %w", err)
+	}
+	if err := base().Where("status = ? AND completed_at >= ?", CycleJobFailed, now.Add(-24*time.Hour).UTC()).
+		Count(&counts.Failed24h).Error; err != nil {
+		return nil, fmt.Errorf("failed to count cycle jobs: %w", err)
+	}
+	return counts, nil
+}
+
+// CancelPending cancels a trader's pending jobs (the trader was stopped on purpose)
+func (s *CycleJobStore) CancelPending(traderID string) error {
+	err := s.db.Where("trader_id = ? AND status = ?", traderID, CycleJobPending).Delete(&CycleJob{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to cancel cycle jobs: %w", err)
+	}
+	return nil
+}
+
+// DeleteFinishedBefore deletes a trader's done and failed jobs that completed before a time
+func (s *CycleJobStore) DeleteFinishedBefore(traderID string, t time.Time) (int64, error) {
+	result := s.db.Where("trader_id = ? AND status IN ? AND completed_at < ?",
+		traderID, []string{CycleJobDone, CycleJobFailed}, t.UTC()).Delete(&CycleJob{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete cycle jobs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,174 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DailyStatsDateLayout date format of daily statistics rows
+const DailyStatsDateLayout = "2006-01-02"
+
+// TraderDailyStat trading statistics of one calendar day of a trader, in its owner's timezone
+type TraderDailyStat struct {
+	TraderID       string    `gorm:"column:trader_id;primaryKey" json:"trader_id"`
+	Date           string    `gorm:"column:date;primaryKey" json:"date"` // YYYY-MM-DD in Timezone
+	Timezone       string    `gorm:"column:timezone;not null;default:'UTC'" json:"timezone"`
+	Trades         int       `gorm:"column:trades;default:0" json:"trades"` // Positions closed that day
+	Wins           int       `gorm:"column:wins;default:0" json:"wins"`
+	Losses         int       `gorm:"column:losses;default:0" json:"losses"`
+	WinRate        float64   `gorm:"column:win_rate;default:0" json:"win_rate"` // Percentage
+	PnL            float64   `gorm:"column:pnl;default:0" json:"pnl"`           // Realized, before fees
+	Fees           float64   `gorm:"column:fees;default:0" json:"fees"`
+	StartEquity    float64   `gorm:"column:start_equity;default:0" json:"start_equity"`
+	EndEquity      float64   `gorm:"column:end_equity;default:0" json:"end_equity"`
+	MaxDrawdownPct float64   `gorm:"column:max_drawdown_pct;default:0" json:"max_drawdown_pct"` // Largest intraday peak-to-trough equity drop
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (TraderDailyStat) TableName() string { return "trader_daily_stats" }
+
+// DailyStatsStore daily statistics rollup storage
+type DailyStatsStore struct {
+	db *gorm.DB
+}
+
+// NewDailyStatsStore creates a new DailyStatsStore
+func NewDailyStatsStore(db *gorm.DB) *DailyStatsStore {
+	return &DailyStatsStore{db: db}
+}
+
+func (s *DailyStatsStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'trader_daily_stats'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&TraderDailyStat{})
+}
+
+// RollupDays aggregates closed positions and equity snapshots into daily statistics
+// Every local day from the day of from up to the day of to gets a row, empty days included.
+func RollupDays(traderID string, loc *time.Location, from, to time.Time, positions []*TraderPosition, snapshots []*EquitySnapshot) []*TraderDailyStat {
+	days := make(map[string]*TraderDailyStat)
+	var ordered []*TraderDailyStat
+	start := time.Date(from.In(loc).Year(), from.In(loc).Month(), from.In(loc).Day(), 0, 0, 0, 0, loc)
+	for day := start; !day.After(to); day = day.AddDate(0, 0, 1) {
+		stat := &TraderDailyStat{TraderID: traderID, Date: day.Format(DailyStatsDateLayout), Timezone: loc.String()}
+		days[stat.Date] = stat
+		ordered = append(ordered, stat)
+	}
+
+	for _, pos := range positions {
+		stat := days[time.UnixMilli(pos.ExitTime).In(loc).Format(DailyStatsDateLayout)]
+		if stat == nil {
+			continue
+		}
+		stat.Trades++
+		stat.PnL += pos.RealizedPnL
+		stat.Fees += pos.Fee
+		if pos.RealizedPnL > 0 {
+			stat.Wins++
+		} else if pos.RealizedPnL < 0 {
+			stat.Losses++
+		}
+	}
+
+	peaks := make(map[string]float64)
+	for _, snap := range snapshots { // Oldest first
+		stat := days[snap.Timestamp.In(loc).Format(DailyStatsDateLayout)]
+		if stat == nil || snap.TotalEquity <= 0 {
+			continue
+		}
+		if stat.StartEquity == 0 {
+			stat.StartEquity = snap.TotalEquity
+		}
+		stat.EndEquity = snap.TotalEquity
+		if snap.TotalEquity > peaks[stat.Date] {
+			peaks[stat.Date] = snap.TotalEquity
+		}
+		if dd := (peaks[stat.Date] - snap.TotalEquity) / peaks[stat.Date] * 100; dd > stat.MaxDrawdownPct {
+			stat.MaxDrawdownPct = dd
+		}
+	}
+
+	for _, stat := range ordered {
+		if stat.Trades > 0 {
+			stat.WinRate = float64(stat.Wins) / float64(stat.Trades) * 100
+		}
+	}
+	return ordered
+}
+
+// Rollup recomputes the trader's daily statistics from the local day of from up to now
+func (s *DailyStatsStore) Rollup(traderID string, loc *time.Location, from, now time.Time) (int, error) {
+	from = time.Date(from.In(loc).Year(), from.In(loc).Month(), from.In(loc).Day(), 0, 0, 0, 0, loc)
+
+	var positions []*TraderPosition
+	err := s.db.Where("trader_id = ? AND status = ? AND exit_time >= ? AND exit_time <= ?",
+		traderID, "CLOSED", from.UnixMilli(), now.UnixMilli()).
+		Find(&positions).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to load closed positions: %w", err)
+	}
+	var snapshots []*EquitySnapshot
+	err = s.db.Where("trader_id = ? AND timestamp >= ? AND timestamp <= ?", traderID, from.UTC(), now.UTC()).
+		Order("timestamp ASC").
+		Find(&snapshots).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to load equity snapshots: %w", err)
+	}
+
+	stats := RollupDays(traderID, loc, from, now, positions, snapshots)
+	if len(stats) == 0 {
+		return 0, nil
+	}
+	for _, stat := range stats {
+		stat.UpdatedAt = now.UTC()
+	}
+	err = s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "trader_id"}, {Name: "date"}},
+		UpdateAll: true,
+	}).CreateInBatches(&stats, 100).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to save daily statistics: %w", err)
+	}
+	return len(stats), nil
+}
+
+// Latest gets the trader's most recent daily statistics row, nil if there is none
+func (s *DailyStatsStore) Latest(traderID string) (*TraderDailyStat, error) {
+	var stats []*TraderDailyStat
+	if err := s.db.Where("trader_id = ?", traderID).Order("date DESC").Limit(1).Find(&stats).Error; err != nil {
+		return nil, err
+	}
+	if len(stats) == 0 {
+		return nil, nil
+	}
+	return stats[0], nil
+}
+
+// List gets the trader's daily statistics between two dates (YYYY-MM-DD, inclusive), oldest first
+func (s *DailyStatsStore) List(traderID, fromDate, toDate string) ([]*TraderDailyStat, error) {
+	var stats []*TraderDailyStat
+	err := s.db.Where("trader_id = ? AND date >= ? AND date <= ?", traderID, fromDate, toDate).
+		Order("date ASC").
+		Find(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daily statistics: %w", err)
+	}
+	return stats, nil
+}
+
+// DeleteByTraders deletes the daily statistics of traders, so the next rollup rebuilds them
+func (s *DailyStatsStore) DeleteByTraders(traderIDs []string) error {
+	if len(traderIDs) == 0 {
+		return nil
+	}
+	return s.db.Where("trader_id IN ?", traderIDs).Delete(&TraderDailyStat{}).Error
+}
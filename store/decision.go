@@ -2,6 +2,7 @@ package store
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -30,6 +31,7 @@ type DecisionRecordDB struct {
 	Success             bool      `gorm:"default:false"`
 	ErrorMessage        string    `gorm:"column:error_message;default:''"`
 	AIRequestDurationMs int64     `gorm:"column:ai_request_duration_ms;default:0"`
+	AIModel             string    `gorm:"column:ai_model;default:''"`
 	CreatedAt           time.Time `json:"created_at"`
 }
 
@@ -51,6 +53,7 @@ type DecisionRecord struct {
 	Success             bool               `json:"success"`
 	ErrorMessage        string             `json:"error_message"`
 	AIRequestDurationMs int64              `json:"ai_request_duration_ms"`
+	AIModel             string             `json:"ai_model"` // Model that produced the decision (differs from trader's model after a fallback)
 	AccountState        AccountSnapshot    `json:"account_state"`
 	Positions           []PositionSnapshot `json:"positions"`
 	Decisions           []DecisionAction   `json:"decisions"`
@@ -93,6 +96,19 @@ type DecisionAction struct {
 	Timestamp  time.Time `json:"timestamp"`
 	Success    bool      `json:"success"`
 	Error      string    `json:"error"`
+
+	// Execution result (filled in after the order is submitted)
+	ExchangeOrderID string  `json:"exchange_order_id,omitempty"` // Exchange order ID (any exchange format)
+	OrderStatus     string  `json:"order_status,omitempty"`      // FILLED/CANCELED/REJECTED/NEW..., empty if unconfirmed
+	FillPrice       float64 `json:"fill_price,omitempty"`        // Average fill price
+	FilledQty       float64 `json:"filled_qty,omitempty"`
+	Fee             float64 `json:"fee,omitempty"`
+	SlippagePct     float64 `json:"slippage_pct,omitempty"` // Fill vs decision-time price, positive = adverse
+
+	// Split execution (TWAP/iceberg): fill fields above total all child orders so far
+	ExecutionAlgo string `json:"execution_algo,omitempty"`
+	ChildOrders   int    `json:"child_orders,omitempty"` // Child orders filled
+	SplitEntryID  int64  `json:"split_entry_id,omitempty"`
 }
 
 // Statistics statistics information
@@ -102,6 +118,18 @@ type Statistics struct {
 	FailedCycles        int `json:"failed_cycles"`
 	TotalOpenPositions  int `json:"total_open_positions"`
 	TotalClosePositions int `json:"total_close_positions"`
+
+	// PnL breakdown (negative funding/commission = paid)
+	RealizedPnL     float64 `json:"realized_pnl"`     // Closed position PnL (price moves only)
+	TotalFunding    float64 `json:"total_funding"`    // Funding payments synced from exchange
+	TotalCommission float64 `json:"total_commission"` // Commissions synced from exchange (falls back to recorded position fees)
+	NetPnL          float64 `json:"net_pnl"`          // RealizedPnL + TotalFunding + TotalCommission
+	// Whether funding and commissions are synced from the exchange, set by the API.
+	// When false (KuCoin, Lighter, paper mode) TotalFunding stays 0 and commissions are the recorded position fees.
+	IncomeSynced bool `json:"income_synced"`
+
+	// Failed exchange calls by error class (rate_limited, auth, ...) since the trader was loaded, set by the API
+	ExchangeErrors map[string]int64 `json:"exchange_errors,omitempty"`
 }
 
 // NewDecisionStore creates a new DecisionStore
@@ -116,7 +144,7 @@ func (s *DecisionStore) initTables() error {
 		var tableExists int64
 		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'decision_records'`).Scan(&tableExists)
 		if tableExists > 0 {
-			return nil
+			return nil // Later columns are added by migrations/postgres
 		}
 	}
 	return s.db.AutoMigrate(&DecisionRecordDB{})
@@ -137,6 +165,7 @@ func (db *DecisionRecordDB) toRecord() *DecisionRecord {
 		Success:             db.Success,
 		ErrorMessage:        db.ErrorMessage,
 		AIRequestDurationMs: db.AIRequestDurationMs,
+		AIModel:             db.AIModel,
 	}
 	json.Unmarshal([]byte(db.CandidateCoins), &record.CandidateCoins)
 	json.Unmarshal([]byte(db.ExecutionLog), &record.ExecutionLog)
@@ -172,6 +201,7 @@ func (s *DecisionStore) LogDecision(record *DecisionRecord) error {
 		Success:             record.Success,
 		ErrorMessage:        record.ErrorMessage,
 		AIRequestDurationMs: record.AIRequestDurationMs,
+		AIModel:             record.AIModel,
 	}
 
 	if err := s.db.Create(dbRecord).Error; err != nil {
@@ -181,6 +211,33 @@ func (s *DecisionStore) LogDecision(record *DecisionRecord) error {
 	return nil
 }
 
+// UpdateAction updates a decision of a trader's cycle record in place, e.g. with the fills of a
+// split entry completing after the cycle was logged. Returns nil if the record or action is gone.
+func (s *DecisionStore) UpdateAction(traderID string, cycle int, symbol, action string, update func(*DecisionAction)) error {
+	var dbRecord DecisionRecordDB
+	err := s.db.Where("trader_id = ? AND cycle_number = ?", traderID, cycle).Order("timestamp DESC").First(&dbRecord).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to query decision record: %w", err)
+	}
+
+	var actions []DecisionAction
+	json.Unmarshal([]byte(dbRecord.Decisions), &actions)
+	for i := range actions {
+		if actions[i].Symbol == symbol && actions[i].Action == action {
+			update(&actions[i])
+			data, _ := json.Marshal(actions)
+			if err := s.db.Model(&DecisionRecordDB{}).Where("id = ?", dbRecord.ID).Update("decisions", string(data)).Error; err != nil {
+				return fmt.Errorf("failed to update decision record: %w", err)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
 // GetLatestRecords gets the latest N records for specified trader (sorted by time in ascending order: old to new)
 func (s *DecisionStore) GetLatestRecords(traderID string, n int) ([]*DecisionRecord, error) {
 	var dbRecords []*DecisionRecordDB
@@ -205,6 +262,15 @@ func (s *DecisionStore) GetLatestRecords(traderID string, n int) ([]*DecisionRec
 	return records, nil
 }
 
+// GetRecord gets a single decision record of a trader
+func (s *DecisionStore) GetRecord(traderID string, id int64) (*DecisionRecord, error) {
+	var dbRecord DecisionRecordDB
+	if err := s.db.Where("trader_id = ? AND id = ?", traderID, id).First(&dbRecord).Error; err != nil {
+		return nil, fmt.Errorf("failed to query decision record: %w", err)
+	}
+	return dbRecord.toRecord(), nil
+}
+
 // GetAllLatestRecords gets the latest N records for all traders
 func (s *DecisionStore) GetAllLatestRecords(n int) ([]*DecisionRecord, error) {
 	var dbRecords []*DecisionRecordDB
@@ -246,6 +312,74 @@ func (s *DecisionStore) GetRecordsByDate(traderID string, date time.Time) ([]*De
 	return records, nil
 }
 
+// GetActionsInRange gets the decision actions of a trader's cycles in [start, end], oldest first
+// Only the actions are loaded (not the prompts); actions without their own timestamp get the cycle's.
+func (s *DecisionStore) GetActionsInRange(traderID string, start, end time.Time) ([]DecisionAction, error) {
+	var dbRecords []*DecisionRecordDB
+	err := s.db.Select("id", "timestamp", "decisions").
+		Where("trader_id = ? AND timestamp >= ? AND timestamp <= ?", traderID, start.UTC(), end.UTC()).
+		Order("timestamp ASC").
+		Find(&dbRecords).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query decision records: %w", err)
+	}
+
+	var actions []DecisionAction
+	for _, db := range dbRecords {
+		for _, action := range db.toRecord().Decisions {
+			if action.Timestamp.IsZero() {
+				action.Timestamp = db.Timestamp
+			}
+			actions = append(actions, action)
+		}
+	}
+	return actions, nil
+}
+
+// AIUsage AI calls of a trader's decision cycles and the size of their prompts and responses
+type AIUsage struct {
+	Calls         int   `json:"calls"`
+	PromptChars   int64 `json:"prompt_chars"`   // System + user prompt
+	ResponseChars int64 `json:"response_chars"` // Raw response
+}
+
+// GetAIUsage sums the AI usage of a trader's cycles in [start, end]
+// Cycles that never reached the AI (no prompt recorded) are not counted.
+func (s *DecisionStore) GetAIUsage(traderID string, start, end time.Time) (*AIUsage, error) {
+	usage := &AIUsage{}
+	err := s.db.Model(&DecisionRecordDB{}).
+		Select("COUNT(*) AS calls, COALESCE(SUM(LENGTH(system_prompt) + LENGTH(input_prompt)), 0) AS prompt_chars, COALESCE(SUM(LENGTH(raw_response)), 0) AS response_chars").
+		Where("trader_id = ? AND timestamp >= ? AND timestamp <= ? AND input_prompt <> ''", traderID, start.UTC(), end.UTC()).
+		Scan(usage).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query AI usage: %w", err)
+	}
+	return usage, nil
+}
+
+// EachPromptedRecord calls fn with a trader's cycles in [start, end] that reached the AI, in cycle order
+// Records are loaded batchSize at a time so long ranges can be streamed; an error from fn stops the iteration.
+func (s *DecisionStore) EachPromptedRecord(traderID string, start, end time.Time, batchSize int, fn func(*DecisionRecord) error) error {
+	var batch []*DecisionRecordDB
+	var fnErr error
+	result := s.db.Where("trader_id = ? AND timestamp >= ? AND timestamp <= ? AND input_prompt <> ''", traderID, start.UTC(), end.UTC()).
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			for _, db := range batch {
+				if fnErr = fn(db.toRecord()); fnErr != nil {
+					return fnErr
+				}
+			}
+			return nil
+		})
+	if fnErr != nil {
+		return fnErr
+	}
+	if result.Error != nil {
+		return fmt.Errorf("failed to query decision records: %w", result.Error)
+	}
+	return nil
+}
+
 // CleanOldRecords cleans old records from N days ago
 func (s *DecisionStore) CleanOldRecords(traderID string, days int) (int64, error) {
 	cutoffTime := time.Now().AddDate(0, 0, -days)
@@ -274,6 +408,18 @@ func (s *DecisionStore) GetStatistics(traderID string) (*Statistics, error) {
 	s.db.Raw("SELECT COUNT(*) FROM trader_positions WHERE trader_id = ?", traderID).Scan(&stats.TotalOpenPositions)
 	s.db.Raw("SELECT COUNT(*) FROM trader_positions WHERE trader_id = ? AND status = 'CLOSED'", traderID).Scan(&stats.TotalClosePositions)
 
+	// Net PnL: realized trade PnL plus funding and commission
+	s.db.Raw("SELECT COALESCE(SUM(realized_pnl), 0) FROM trader_positions WHERE trader_id = ? AND status = 'CLOSED'", traderID).Scan(&stats.RealizedPnL)
+	if totals, err := NewIncomeStore(s.db).GetTotals(traderID); err == nil {
+		stats.TotalFunding = totals.TotalFunding
+		stats.TotalCommission = totals.TotalCommission
+		if totals.CommissionCount == 0 {
+			// Exchange without commission history: use fees recorded on positions
+			s.db.Raw("SELECT -COALESCE(SUM(fee), 0) FROM trader_positions WHERE trader_id = ?", traderID).Scan(&stats.TotalCommission)
+		}
+	}
+	stats.NetPnL = stats.RealizedPnL + stats.TotalFunding + stats.TotalCommission
+
 	return stats, nil
 }
 
@@ -0,0 +1,212 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Pending decision status
+const (
+	PendingDecisionPending  = "PENDING"
+	PendingDecisionApproved = "APPROVED" // Approved, being executed
+	PendingDecisionExecuted = "EXECUTED"
+	PendingDecisionFailed   = "FAILED"
+	PendingDecisionRejected = "REJECTED"
+	PendingDecisionExpired  = "EXPIRED"
+)
+
+// Approval window bounds (minutes)
+const (
+	DefaultApprovalWindowMinutes = 15
+	MaxApprovalWindowMinutes     = 24 * 60
+)
+
+// ErrDecisionNotPending the decision was already approved, rejected or expired
+var ErrDecisionNotPending = errors.New("decision is no longer pending approval")
+
+// ApprovalConfig per-trader human-in-the-loop mode: matching opening decisions wait for approval
+// With both thresholds at 0, every opening decision needs approval.
+type ApprovalConfig struct {
+	TraderID       string    `gorm:"column:trader_id;primaryKey" json:"trader_id"`
+	UserID         string    `gorm:"column:user_id;not null;index" json:"user_id"`
+	Enabled        bool      `gorm:"column:enabled;not null" json:"enabled"`
+	MinNotionalUSD float64   `gorm:"column:min_notional_usd;default:0" json:"min_notional_usd"` // Hold opens of at least this notional (0 = any)
+	MinConfidence  int       `gorm:"column:min_confidence;default:0" json:"min_confidence"`     // Hold opens below this confidence (0 = any)
+	WindowMinutes  int       `gorm:"column:window_minutes;default:15" json:"window_minutes"`    // Time to approve before the decision expires
+	UpdatedAt      time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName returns the table name for ApprovalConfig
+func (ApprovalConfig) TableName() string {
+	return "trader_approval_configs"
+}
+
+// Window returns the approval window, the default when unset
+func (c *ApprovalConfig) Window() time.Duration {
+	if c.WindowMinutes <= 0 {
+		return DefaultApprovalWindowMinutes * time.Minute
+	}
+	return time.Duration(c.WindowMinutes) * time.Minute
+}
+
+// PendingDecision AI opening decision held for approval instead of being executed
+type PendingDecision struct {
+	ID          int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID    string  `gorm:"column:trader_id;not null;index:idx_pending_decisions_trader_status,priority:1" json:"trader_id"`
+	UserID      string  `gorm:"column:user_id;not null;default:''" json:"user_id"`
+	Symbol      string  `gorm:"column:symbol;not null" json:"symbol"`
+	Action      string  `gorm:"column:action;not null" json:"action"`
+	Leverage    int     `gorm:"column:leverage;default:0" json:"leverage"`
+	NotionalUSD float64 `gorm:"column:notional_usd;default:0" json:"notional_usd"`
+	Confidence  int     `gorm:"column:confidence;default:0" json:"confidence"`
+	Reasoning   string  `gorm:"column:reasoning;type:text" json:"reasoning"`
+	Decision    string  `gorm:"column:decision;type:text;not null" json:"-"` // Full decision JSON, executed as is once approved
+	HoldReason  string  `gorm:"column:hold_reason;default:''" json:"hold_reason"`
+	Status      string  `gorm:"column:status;not null;default:PENDING;index:idx_pending_decisions_trader_status,priority:2" json:"status"`
+	Error       string  `gorm:"column:error;default:''" json:"error"`
+	ExpiresAt   int64   `gorm:"column:expires_at;not null" json:"expires_at"`  // Unix milliseconds UTC
+	DecidedAt   int64   `gorm:"column:decided_at;default:0" json:"decided_at"` // Unix milliseconds UTC
+	CreatedAt   int64   `gorm:"column:created_at" json:"created_at"`           // Unix milliseconds UTC
+}
+
+// TableName returns the table name for PendingDecision
+func (PendingDecision) TableName() string {
+	return "trader_pending_decisions"
+}
+
+// DecisionApprovalStore approval mode settings and pending decision storage
+type DecisionApprovalStore struct {
+	db *gorm.DB
+}
+
+// NewDecisionApprovalStore creates a new DecisionApprovalStore
+func NewDecisionApprovalStore(db *gorm.DB) *DecisionApprovalStore {
+	return &DecisionApprovalStore{db: db}
+}
+
+func (s *DecisionApprovalStore) initTables() error {
+	// For PostgreSQL with existing tables, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name IN ('trader_approval_configs', 'trader_pending_decisions')`).Scan(&tableExists)
+		if tableExists == 2 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&ApprovalConfig{}, &PendingDecision{})
+}
+
+// GetConfig gets a trader's approval settings, returns nil if never configured
+func (s *DecisionApprovalStore) GetConfig(traderID string) (*ApprovalConfig, error) {
+	var cfg ApprovalConfig
+	err := s.db.Where("trader_id = ?", traderID).First(&cfg).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approval config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// SaveConfig upserts a trader's approval settings
+func (s *DecisionApprovalStore) SaveConfig(cfg *ApprovalConfig) error {
+	if err := s.db.Save(cfg).Error; err != nil {
+		return fmt.Errorf("failed to save approval config: %w", err)
+	}
+	return nil
+}
+
+// Create queues a decision for approval
+func (s *DecisionApprovalStore) Create(p *PendingDecision) error {
+	p.CreatedAt = time.Now().UTC().UnixMilli()
+	if p.Status == "" {
+		p.Status = PendingDecisionPending
+	}
+	// Omit ID to let PostgreSQL sequence auto-generate it
+	if err := s.db.Omit("ID").Create(p).Error; err != nil {
+		return fmt.Errorf("failed to create pending decision: %w", err)
+	}
+	return nil
+}
+
+// Get gets one of a trader's queued decisions
+func (s *DecisionApprovalStore) Get(traderID string, id int64) (*PendingDecision, error) {
+	var p PendingDecision
+	if err := s.db.Where("id = ? AND trader_id = ?", id, traderID).First(&p).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// List gets trader's recent queued decisions (newest first)
+func (s *DecisionApprovalStore) List(traderID string, limit int) ([]*PendingDecision, error) {
+	var decisions []*PendingDecision
+	err := s.db.Where("trader_id = ?", traderID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&decisions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending decisions: %w", err)
+	}
+	return decisions, nil
+}
+
+// ExpireDue expires trader's pending decisions whose approval window has passed, returns how many expired
+func (s *DecisionApprovalStore) ExpireDue(traderID string, now time.Time) (int64, error) {
+	nowMs := now.UTC().UnixMilli()
+	result := s.db.Model(&PendingDecision{}).
+		Where("trader_id = ? AND status = ? AND expires_at <= ?", traderID, PendingDecisionPending, nowMs).
+		Updates(map[string]interface{}{"status": PendingDecisionExpired, "decided_at": nowMs})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to expire pending decisions: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// Decide approves or rejects a pending decision still inside its approval window
+// Returns ErrDecisionNotPending when it was already decided or has expired.
+func (s *DecisionApprovalStore) Decide(traderID string, id int64, status string, now time.Time) (*PendingDecision, error) {
+	if status != PendingDecisionApproved && status != PendingDecisionRejected {
+		return nil, fmt.Errorf("invalid decision status %q", status)
+	}
+	nowMs := now.UTC().UnixMilli()
+	var decided *PendingDecision
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var p PendingDecision
+		if err := tx.Where("id = ? AND trader_id = ?", id, traderID).First(&p).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&PendingDecision{}).
+			Where("id = ? AND status = ? AND expires_at > ?", id, PendingDecisionPending, nowMs).
+			Updates(map[string]interface{}{"status": status, "decided_at": nowMs})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrDecisionNotPending
+		}
+		p.Status = status
+		p.DecidedAt = nowMs
+		decided = &p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decided, nil
+}
+
+// Finish records the outcome of executing an approved decision
+func (s *DecisionApprovalStore) Finish(id int64, execErr error) error {
+	updates := map[string]interface{}{"status": PendingDecisionExecuted}
+	if execErr != nil {
+		updates = map[string]interface{}{"status": PendingDecisionFailed, "error": execErr.Error()}
+	}
+	return s.db.Model(&PendingDecision{}).
+		Where("id = ? AND status = ?", id, PendingDecisionApproved).
+		Updates(updates).Error
+}
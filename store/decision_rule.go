@@ -0,0 +1,152 @@
+package store
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Decision rule types (user-configured filters applied to AI decisions before execution)
+const (
+	DecisionRuleBlockOpen       = "block_open"        // Veto opening positions (Side: long/short/empty for both) in Symbols (empty: all)
+	DecisionRuleMaxNewPositions = "max_new_positions" // Veto opens beyond Value new positions per cycle
+	DecisionRuleMaxLeverage     = "max_leverage"      // Cap leverage of opens at Value
+	DecisionRuleMaxPositionUSD  = "max_position_usd"  // Cap position size of opens at Value USDT
+	DecisionRuleAllowSymbols    = "allow_symbols"     // Veto opening positions in any symbol not in Symbols
+)
+
+// DecisionRule a per-trader rule evaluated, in Position order, on every AI decision cycle
+type DecisionRule struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID  string    `gorm:"column:trader_id;not null;index" json:"trader_id"`
+	UserID    string    `gorm:"column:user_id;not null;default:''" json:"user_id"`
+	Position  int       `gorm:"column:position;not null;default:0" json:"position"` // Evaluation order, ascending
+	Type      string    `gorm:"column:type;not null" json:"type"`
+	Symbols   string    `gorm:"column:symbols;default:''" json:"symbols"` // Comma-separated, * and ? wildcards allowed (e.g. *PEPE*), empty: all symbols
+	Side      string    `gorm:"column:side;default:''" json:"side"`       // "long", "short" or empty for both
+	Value     float64   `gorm:"column:value;default:0" json:"value"`
+	Enabled   bool      `gorm:"column:enabled;not null" json:"enabled"`
+	Note      string    `gorm:"column:note;default:''" json:"note"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+// TableName returns the table name for DecisionRule
+func (DecisionRule) TableName() string {
+	return "trader_decision_rules"
+}
+
+// SymbolList returns the rule's symbols, empty when the rule applies to all symbols
+func (r *DecisionRule) SymbolList() []string {
+	var symbols []string
+	for _, symbol := range strings.Split(r.Symbols, ",") {
+		if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+// Validate checks the rule type and its parameters
+func (r *DecisionRule) Validate() error {
+	for _, pattern := range r.SymbolList() {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid symbol pattern %q", pattern)
+		}
+	}
+	switch r.Type {
+	case DecisionRuleBlockOpen:
+		if r.Side != "" && r.Side != "long" && r.Side != "short" {
+			return fmt.Errorf("side must be long, short or empty")
+		}
+	case DecisionRuleMaxNewPositions:
+		if r.Value < 0 || r.Value != float64(int(r.Value)) {
+			return fmt.Errorf("%s value must be a non-negative integer", r.Type)
+		}
+	case DecisionRuleMaxLeverage:
+		if r.Value < 1 || r.Value != float64(int(r.Value)) {
+			return fmt.Errorf("%s value must be an integer of at least 1", r.Type)
+		}
+	case DecisionRuleMaxPositionUSD:
+		if r.Value <= 0 {
+			return fmt.Errorf("%s value must be positive", r.Type)
+		}
+	case DecisionRuleAllowSymbols:
+		if len(r.SymbolList()) == 0 {
+			return fmt.Errorf("%s requires at least one symbol", r.Type)
+		}
+		if r.Side != "" {
+			return fmt.Errorf("%s applies to both sides, side must be empty", r.Type)
+		}
+	default:
+		return fmt.Errorf("unknown rule type %q", r.Type)
+	}
+	return nil
+}
+
+// DecisionRuleStore trader decision rule storage
+type DecisionRuleStore struct {
+	db *gorm.DB
+}
+
+// NewDecisionRuleStore creates a new DecisionRuleStore
+func NewDecisionRuleStore(db *gorm.DB) *DecisionRuleStore {
+	return &DecisionRuleStore{db: db}
+}
+
+func (s *DecisionRuleStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'trader_decision_rules'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&DecisionRule{})
+}
+
+// List gets all rules of a trader in evaluation order
+func (s *DecisionRuleStore) List(traderID string) ([]*DecisionRule, error) {
+	var rules []*DecisionRule
+	err := s.db.Where("trader_id = ?", traderID).
+		Order("position ASC, id ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list decision rules: %w", err)
+	}
+	return rules, nil
+}
+
+// ListEnabled gets the enabled rules of a trader in evaluation order
+func (s *DecisionRuleStore) ListEnabled(traderID string) ([]*DecisionRule, error) {
+	var rules []*DecisionRule
+	err := s.db.Where("trader_id = ? AND enabled = ?", traderID, true).
+		Order("position ASC, id ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list decision rules: %w", err)
+	}
+	return rules, nil
+}
+
+// Replace replaces all rules of a user's trader, evaluation order follows the slice order
+func (s *DecisionRuleStore) Replace(userID, traderID string, rules []*DecisionRule) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("trader_id = ?", traderID).Delete(&DecisionRule{}).Error; err != nil {
+			return fmt.Errorf("failed to delete decision rules: %w", err)
+		}
+		for i, rule := range rules {
+			rule.ID = 0
+			rule.TraderID = traderID
+			rule.UserID = userID
+			rule.Position = i
+			if err := tx.Create(rule).Error; err != nil {
+				return fmt.Errorf("failed to create decision rule: %w", err)
+			}
+		}
+		return nil
+	})
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"gorm.io/gorm"
@@ -9,7 +10,8 @@ import (
 
 // EquityStore account equity storage (for plotting return curves)
 type EquityStore struct {
-	db *gorm.DB
+	db     *gorm.DB
+	writer atomic.Pointer[EquityWriter] // Set: Save queues snapshots for the async writer
 }
 
 // EquitySnapshot equity snapshot
@@ -46,12 +48,16 @@ func (s *EquityStore) initTables() error {
 }
 
 // Save saves equity snapshot
+// With an async writer running the snapshot is queued and inserted with the next batch.
 func (s *EquityStore) Save(snapshot *EquitySnapshot) error {
 	if snapshot.Timestamp.IsZero() {
 		snapshot.Timestamp = time.Now().UTC()
 	} else {
 		snapshot.Timestamp = snapshot.Timestamp.UTC()
 	}
+	if w := s.writer.Load(); w != nil && w.add(snapshot) {
+		return nil
+	}
 
 	// Omit ID to let PostgreSQL sequence auto-generate it
 	// Without this, GORM inserts ID=0 which causes duplicate key errors
@@ -61,6 +67,25 @@ func (s *EquityStore) Save(snapshot *EquitySnapshot) error {
 	return nil
 }
 
+// SaveBatch saves equity snapshots with one insert per batch
+func (s *EquityStore) SaveBatch(snapshots []*EquitySnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	for _, snapshot := range snapshots {
+		snapshot.ID = 0
+		if snapshot.Timestamp.IsZero() {
+			snapshot.Timestamp = time.Now().UTC()
+		} else {
+			snapshot.Timestamp = snapshot.Timestamp.UTC()
+		}
+	}
+	if err := s.db.Omit("ID").CreateInBatches(&snapshots, 200).Error; err != nil {
+		return fmt.Errorf("failed to save equity snapshots: %w", err)
+	}
+	return nil
+}
+
 // GetLatest gets the latest N equity records for specified trader (sorted in ascending chronological order: old to new)
 func (s *EquityStore) GetLatest(traderID string, limit int) ([]*EquitySnapshot, error) {
 	var snapshots []*EquitySnapshot
@@ -92,6 +117,58 @@ func (s *EquityStore) GetByTimeRange(traderID string, start, end time.Time) ([]*
 	return snapshots, nil
 }
 
+// GetSeries gets equity records within a time range for charting (ascending).
+// Recent snapshots are dense while compacted history keeps one point per tier interval, so when
+// the range holds more than maxPoints records they are resampled to at most maxPoints: the first
+// and latest snapshots are always kept, the ones between are split into equal time buckets (last
+// snapshot of each bucket wins), so sparse compacted points are kept as is.
+// Zero start/end leave that side of the range open; maxPoints <= 0 disables resampling.
+func (s *EquityStore) GetSeries(traderID string, start, end time.Time, maxPoints int) ([]*EquitySnapshot, error) {
+	query := s.db.Where("trader_id = ?", traderID)
+	if !start.IsZero() {
+		query = query.Where("timestamp >= ?", start)
+	}
+	if !end.IsZero() {
+		query = query.Where("timestamp <= ?", end)
+	}
+	var snapshots []*EquitySnapshot
+	if err := query.Order("timestamp ASC").Find(&snapshots).Error; err != nil {
+		return nil, fmt.Errorf("failed to query equity records: %w", err)
+	}
+	if maxPoints <= 0 || len(snapshots) <= maxPoints {
+		return snapshots, nil
+	}
+	latest := snapshots[len(snapshots)-1]
+	if maxPoints == 1 {
+		return []*EquitySnapshot{latest}, nil
+	}
+
+	first := snapshots[0]
+	inner := snapshots[1 : len(snapshots)-1]
+	buckets := maxPoints - 2
+	result := make([]*EquitySnapshot, 0, maxPoints)
+	result = append(result, first)
+	if buckets > 0 {
+		bucket := latest.Timestamp.Sub(first.Timestamp) / time.Duration(buckets)
+		bucketOf := func(snap *EquitySnapshot) int {
+			if bucket <= 0 {
+				return 0
+			}
+			return min(int(snap.Timestamp.Sub(first.Timestamp)/bucket), buckets-1)
+		}
+		if bucket <= 0 {
+			inner = inner[len(inner)-1:] // All snapshots share one timestamp
+		}
+		for i, snap := range inner {
+			if i+1 < len(inner) && bucketOf(snap) == bucketOf(inner[i+1]) {
+				continue // Not the last snapshot of its bucket
+			}
+			result = append(result, snap)
+		}
+	}
+	return append(result, latest), nil
+}
+
 // GetAllTradersLatest gets latest equity for all traders (for leaderboards)
 func (s *EquityStore) GetAllTradersLatest() (map[string]*EquitySnapshot, error) {
 	// Use raw SQL for this complex query with subquery
@@ -129,6 +206,104 @@ func (s *EquityStore) CleanOldRecords(traderID string, days int) (int64, error)
 	return result.RowsAffected, nil
 }
 
+// EquityRetentionTier downsampling tier: snapshots older than After keep one point per Interval
+type EquityRetentionTier struct {
+	After    time.Duration
+	Interval time.Duration
+}
+
+// EquityRetentionPolicy equity snapshot retention/compaction policy
+type EquityRetentionPolicy struct {
+	Tiers  []EquityRetentionTier // Sorted by After ascending, later tiers are coarser
+	MaxAge time.Duration         // Snapshots older than this are deleted, 0 keeps compacted history forever
+}
+
+// DefaultEquityRetentionPolicy keeps 1 snapshot/min for 7 days, 1/hour after
+func DefaultEquityRetentionPolicy() EquityRetentionPolicy {
+	return EquityRetentionPolicy{
+		Tiers: []EquityRetentionTier{
+			{After: 0, Interval: time.Minute},
+			{After: 7 * 24 * time.Hour, Interval: time.Hour},
+		},
+	}
+}
+
+// equitySnapshotRef timestamp of a snapshot, selected without the payload for compaction
+type equitySnapshotRef struct {
+	ID        int64
+	Timestamp time.Time
+}
+
+// Compact downsamples snapshots of all traders according to the policy and returns the number
+// of deleted records. Within each tier the last snapshot of every interval bucket is kept, so
+// repeated runs are idempotent and points only get coarser as they age into later tiers.
+// The current (still filling) bucket of the newest tier is never touched.
+func (s *EquityStore) Compact(policy EquityRetentionPolicy, now time.Time) (int64, error) {
+	var traderIDs []string
+	if err := s.db.Model(&EquitySnapshot{}).Distinct("trader_id").Pluck("trader_id", &traderIDs).Error; err != nil {
+		return 0, fmt.Errorf("failed to list equity traders: %w", err)
+	}
+
+	var deleted int64
+	if policy.MaxAge > 0 {
+		result := s.db.Where("timestamp < ?", now.Add(-policy.MaxAge)).Delete(&EquitySnapshot{})
+		if result.Error != nil {
+			return 0, fmt.Errorf("failed to delete expired equity records: %w", result.Error)
+		}
+		deleted += result.RowsAffected
+	}
+
+	for _, traderID := range traderIDs {
+		n, err := s.compactTrader(traderID, policy, now)
+		deleted += n
+		if err != nil {
+			return deleted, fmt.Errorf("failed to compact equity records of %s: %w", traderID, err)
+		}
+	}
+	return deleted, nil
+}
+
+func (s *EquityStore) compactTrader(traderID string, policy EquityRetentionPolicy, now time.Time) (int64, error) {
+	var deleted int64
+	for i, tier := range policy.Tiers {
+		if tier.Interval <= 0 {
+			continue
+		}
+		// Tier covers the complete buckets up to now - tier.After, down to where the next tier starts
+		end := now.Add(-tier.After).Truncate(tier.Interval)
+		query := s.db.Model(&EquitySnapshot{}).Select("id, timestamp").
+			Where("trader_id = ? AND timestamp < ?", traderID, end)
+		if i+1 < len(policy.Tiers) {
+			next := policy.Tiers[i+1]
+			query = query.Where("timestamp >= ?", now.Add(-next.After).Truncate(next.Interval))
+		}
+		var refs []equitySnapshotRef
+		if err := query.Order("timestamp ASC, id ASC").Scan(&refs).Error; err != nil {
+			return deleted, err
+		}
+
+		var ids []int64
+		for j := 0; j+1 < len(refs); j++ {
+			if refs[j].Timestamp.Truncate(tier.Interval).Equal(refs[j+1].Timestamp.Truncate(tier.Interval)) {
+				ids = append(ids, refs[j].ID)
+			}
+		}
+		for len(ids) > 0 {
+			batch := ids
+			if len(batch) > 500 {
+				batch = ids[:500]
+			}
+			ids = ids[len(batch):]
+			result := s.db.Where("id IN ?", batch).Delete(&EquitySnapshot{})
+			if result.Error != nil {
+				return deleted, result.Error
+			}
+			deleted += result.RowsAffected
+		}
+	}
+	return deleted, nil
+}
+
 // GetCount gets record count for specified trader
 func (s *EquityStore) GetCount(traderID string) (int, error) {
 	var count int64
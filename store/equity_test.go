@@ -0,0 +1,157 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newEquityTestStore(t *testing.T) *EquityStore {
+	t.Helper()
+	st, err := New(filepath.Join(t.TempDir(), "equity.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st.Equity()
+}
+
+// saveEquityEvery stores one snapshot per step in [from, to)
+func saveEquityEvery(t *testing.T, equity *EquityStore, traderID string, from, to time.Time, step time.Duration) {
+	t.Helper()
+	var batch []*EquitySnapshot
+	for ts := from; ts.Before(to); ts = ts.Add(step) {
+		batch = append(batch, &EquitySnapshot{TraderID: traderID, Timestamp: ts, TotalEquity: float64(ts.Unix())})
+	}
+	if err := equity.SaveBatch(batch); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEquityCompactTiers(t *testing.T) {
+	equity := newEquityTestStore(t)
+	// Not aligned to either interval, so tier boundaries fall inside buckets
+	now := time.Date(2026, 3, 1, 12, 5, 30, 0, time.UTC)
+	policy := EquityRetentionPolicy{Tiers: []EquityRetentionTier{
+		{After: 0, Interval: time.Minute},
+		{After: time.Hour, Interval: 10 * time.Minute},
+	}}
+	saveEquityEvery(t, equity, "t1", now.Add(-3*time.Hour), now, 10*time.Second)
+
+	deleted, err := equity.Compact(policy, now)
+	if err != nil || deleted == 0 {
+		t.Fatalf("compact: %d deleted, %v", deleted, err)
+	}
+	count, _ := equity.GetCount("t1")
+	if again, err := equity.Compact(policy, now); err != nil || again != 0 {
+		t.Errorf("second compaction deleted %d (%v), want 0", again, err)
+	}
+	if n, _ := equity.GetCount("t1"); n != count {
+		t.Errorf("count changed on second compaction: %d -> %d", count, n)
+	}
+
+	snapshots, err := equity.GetByTimeRange("t1", now.Add(-4*time.Hour), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coarseEnd := now.Add(-time.Hour).Truncate(10 * time.Minute)
+	fineEnd := now.Truncate(time.Minute)
+	perBucket := map[time.Time]int{}
+	for _, snap := range snapshots {
+		ts := snap.Timestamp.UTC()
+		switch {
+		case ts.Before(coarseEnd):
+			perBucket[ts.Truncate(10*time.Minute)]++
+		case ts.Before(fineEnd):
+			perBucket[ts.Truncate(time.Minute)]++
+		}
+	}
+	for bucket, n := range perBucket {
+		if n != 1 {
+			t.Errorf("bucket %s kept %d snapshots, want 1", bucket.Format(time.TimeOnly), n)
+		}
+	}
+	// 10:00-11:00 and 11:00-coarseEnd in 10-minute buckets, then 1-minute buckets up to fineEnd
+	want := int(coarseEnd.Sub(now.Add(-3*time.Hour).Truncate(10*time.Minute))/(10*time.Minute)) +
+		int(fineEnd.Sub(coarseEnd)/time.Minute)
+	if len(perBucket) != want {
+		t.Errorf("%d buckets kept, want %d", len(perBucket), want)
+	}
+	// The still filling minute is untouched
+	current := 0
+	for _, snap := range snapshots {
+		if !snap.Timestamp.Before(fineEnd) {
+			current++
+		}
+	}
+	if want := int(now.Sub(fineEnd) / (10 * time.Second)); current != want {
+		t.Errorf("%d snapshots in the current minute, want %d", current, want)
+	}
+
+	// Aging moves the dense tail into the coarse tier, which only gets coarser
+	later := now.Add(2 * time.Hour)
+	if _, err := equity.Compact(policy, later); err != nil {
+		t.Fatal(err)
+	}
+	aged, _ := equity.GetByTimeRange("t1", now.Add(-4*time.Hour), now)
+	seen := map[time.Time]bool{}
+	for _, snap := range aged {
+		bucket := snap.Timestamp.UTC().Truncate(10 * time.Minute)
+		if seen[bucket] {
+			t.Errorf("bucket %s kept several snapshots after aging", bucket.Format(time.TimeOnly))
+		}
+		seen[bucket] = true
+	}
+}
+
+func TestEquityCompactMaxAge(t *testing.T) {
+	equity := newEquityTestStore(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	saveEquityEvery(t, equity, "t1", now.Add(-48*time.Hour), now, time.Hour)
+	saveEquityEvery(t, equity, "t2", now.Add(-48*time.Hour), now.Add(-47*time.Hour), time.Hour)
+
+	policy := EquityRetentionPolicy{MaxAge: 24 * time.Hour}
+	deleted, err := equity.Compact(policy, now)
+	if err != nil || deleted != 25 {
+		t.Fatalf("compact: %d deleted (%v), want 25", deleted, err)
+	}
+	if n, _ := equity.GetCount("t1"); n != 24 {
+		t.Errorf("t1 kept %d snapshots, want 24", n)
+	}
+	if n, _ := equity.GetCount("t2"); n != 0 {
+		t.Errorf("t2 kept %d expired snapshots", n)
+	}
+}
+
+func TestEquityGetSeriesResamples(t *testing.T) {
+	equity := newEquityTestStore(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	// Sparse compacted history followed by a dense recent tail
+	saveEquityEvery(t, equity, "t1", now.Add(-30*24*time.Hour), now.Add(-24*time.Hour), time.Hour)
+	saveEquityEvery(t, equity, "t1", now.Add(-24*time.Hour), now.Add(time.Second), time.Minute)
+	all, _ := equity.GetSeries("t1", time.Time{}, time.Time{}, 0)
+
+	for _, maxPoints := range []int{1, 2, 3, 50, 500} {
+		series, err := equity.GetSeries("t1", time.Time{}, time.Time{}, maxPoints)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(series) == 0 || len(series) > maxPoints {
+			t.Fatalf("maxPoints %d: got %d points", maxPoints, len(series))
+		}
+		if last := series[len(series)-1]; last.ID != all[len(all)-1].ID {
+			t.Errorf("maxPoints %d: latest snapshot dropped", maxPoints)
+		}
+		if maxPoints > 1 && series[0].ID != all[0].ID {
+			t.Errorf("maxPoints %d: first snapshot dropped", maxPoints)
+		}
+		for i := 1; i < len(series); i++ {
+			if !series[i].Timestamp.After(series[i-1].Timestamp) {
+				t.Fatalf("maxPoints %d: points not ascending at %d", maxPoints, i)
+			}
+		}
+	}
+	if series, _ := equity.GetSeries("t1", time.Time{}, time.Time{}, len(all)); len(series) != len(all) {
+		t.Errorf("series within the limit must not be resampled: %d of %d", len(series), len(all))
+	}
+}
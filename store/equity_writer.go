@@ -0,0 +1,186 @@
+package store
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"nofx/logger"
+)
+
+// EquityWriterOptions batching and backpressure of the async equity writer (zero values use the defaults)
+type EquityWriterOptions struct {
+	BatchSize     int           // Snapshots per insert (default 100)
+	FlushInterval time.Duration // Max delay before queued snapshots are inserted (default 1s)
+	QueueSize     int           // Snapshots queued before Save waits (default 1000)
+	MaxWait       time.Duration // How long Save waits for room in a full queue before inserting directly (default 2s)
+}
+
+func (o EquityWriterOptions) withDefaults() EquityWriterOptions {
+	if o.BatchSize <= 0 {
+		o.BatchSize = 100
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = time.Second
+	}
+	if o.QueueSize < o.BatchSize {
+		o.QueueSize = 10 * o.BatchSize
+	}
+	if o.MaxWait <= 0 {
+		o.MaxWait = 2 * time.Second
+	}
+	return o
+}
+
+// EquityWriterStats counters of the async equity writer
+type EquityWriterStats struct {
+	Queued      int     `json:"queued"`        // Snapshots waiting to be inserted
+	QueueSize   int     `json:"queue_size"`    // Queue capacity
+	Written     int64   `json:"written"`       // Snapshots inserted by the writer
+	Batches     int64   `json:"batches"`       // Inserts performed
+	Waits       int64   `json:"waits"`         // Saves that waited for room in a full queue
+	Direct      int64   `json:"direct"`        // Saves inserted directly after waiting too long
+	Dropped     int64   `json:"dropped"`       // Snapshots dropped after failed inserts
+	LastFlushMs float64 `json:"last_flush_ms"` // Duration of the latest insert
+}
+
+// EquityWriter persists equity snapshots asynchronously, in batches
+// Every trader saves a snapshot per cycle; queuing them keeps the insert out of the trading loop
+// and turns simultaneous snapshots into one write transaction instead of one per trader. When
+// the database falls behind and the queue fills up, Save waits for room (backpressure) and after
+// MaxWait inserts the snapshot itself, so snapshots are never silently lost.
+type EquityWriter struct {
+	store    *EquityStore
+	opts     EquityWriterOptions
+	pending  chan *EquitySnapshot
+	stopping chan struct{}
+
+	mu     sync.RWMutex // Held for reading while queuing, for writing while closing
+	closed bool
+
+	written, batches, waits, direct, dropped atomic.Int64
+	lastFlush                                atomic.Int64 // Nanoseconds
+}
+
+// StartWriter routes Save through an async writer; its Run loop must be running
+// Once Run returns, Save inserts directly again.
+func (s *EquityStore) StartWriter(opts EquityWriterOptions) *EquityWriter {
+	opts = opts.withDefaults()
+	w := &EquityWriter{
+		store:    s,
+		opts:     opts,
+		pending:  make(chan *EquitySnapshot, opts.QueueSize),
+		stopping: make(chan struct{}),
+	}
+	s.writer.Store(w)
+	return w
+}
+
+// WriterStats counters of the running async writer (nil: snapshots are inserted directly)
+func (s *EquityStore) WriterStats() *EquityWriterStats {
+	w := s.writer.Load()
+	if w == nil {
+		return nil
+	}
+	stats := w.Stats()
+	return &stats
+}
+
+// add queues a snapshot, false when the caller must insert it directly
+func (w *EquityWriter) add(snapshot *EquitySnapshot) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return false
+	}
+	select {
+	case w.pending <- snapshot:
+		return true
+	default:
+	}
+
+	// Queue full: the database is behind, slow the callers down instead of piling up
+	w.waits.Add(1)
+	timer := time.NewTimer(w.opts.MaxWait)
+	defer timer.Stop()
+	select {
+	case w.pending <- snapshot:
+		return true
+	case <-w.stopping:
+		return false
+	case <-timer.C:
+		w.direct.Add(1)
+		logger.Warnf("⚠️ Equity writer queue full for %v, inserting snapshot of %s directly", w.opts.MaxWait, snapshot.TraderID)
+		return false
+	}
+}
+
+// Run inserts queued snapshots until done is closed, then flushes the rest
+// A failed insert is retried with the next flush while the retained snapshots fit in the queue.
+func (w *EquityWriter) Run(done <-chan struct{}) {
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+	batch := make([]*EquitySnapshot, 0, w.opts.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		start := time.Now()
+		err := w.store.SaveBatch(batch)
+		w.lastFlush.Store(int64(time.Since(start)))
+		if err != nil {
+			if len(batch) < w.opts.QueueSize {
+				logger.Warnf("⚠️ %v, retrying %d snapshots with the next flush", err, len(batch))
+				return
+			}
+			logger.Warnf("⚠️ %v, dropping %d snapshots", err, len(batch))
+			w.dropped.Add(int64(len(batch)))
+		} else {
+			w.written.Add(int64(len(batch)))
+			w.batches.Add(1)
+		}
+		batch = make([]*EquitySnapshot, 0, w.opts.BatchSize)
+	}
+	for {
+		select {
+		case snapshot := <-w.pending:
+			if batch = append(batch, snapshot); len(batch)%w.opts.BatchSize == 0 {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-done:
+			close(w.stopping)
+			w.mu.Lock()
+			w.closed = true
+			w.mu.Unlock()
+			w.store.writer.CompareAndSwap(w, nil)
+			for {
+				select {
+				case snapshot := <-w.pending:
+					batch = append(batch, snapshot)
+				default:
+					flush()
+					if len(batch) > 0 {
+						w.dropped.Add(int64(len(batch)))
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// Stats current counters
+func (w *EquityWriter) Stats() EquityWriterStats {
+	return EquityWriterStats{
+		Queued:      len(w.pending),
+		QueueSize:   w.opts.QueueSize,
+		Written:     w.written.Load(),
+		Batches:     w.batches.Load(),
+		Waits:       w.waits.Load(),
+		Direct:      w.direct.Load(),
+		Dropped:     w.dropped.Load(),
+		LastFlushMs: float64(w.lastFlush.Load()) / float64(time.Millisecond),
+	}
+}
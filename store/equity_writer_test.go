@@ -0,0 +1,49 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEquityWriterBackpressureAndFlush(t *testing.T) {
+	st, err := New(filepath.Join(t.TempDir(), "equity.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	equity := st.Equity()
+
+	// Run not started yet: the queue fills, then Save waits MaxWait and inserts directly
+	writer := equity.StartWriter(EquityWriterOptions{BatchSize: 2, QueueSize: 2, FlushInterval: time.Hour, MaxWait: 20 * time.Millisecond})
+	for i := 0; i < 3; i++ {
+		if err := equity.Save(&EquitySnapshot{TraderID: "t1", TotalEquity: float64(i)}); err != nil {
+			t.Fatalf("save %d: %v", i, err)
+		}
+	}
+	if stats := writer.Stats(); stats.Queued != 2 || stats.Waits != 1 || stats.Direct != 1 {
+		t.Errorf("expected 2 queued and 1 direct insert after waiting, got %+v", stats)
+	}
+	if n, _ := equity.GetCount("t1"); n != 1 {
+		t.Errorf("only the direct insert should be stored yet, got %d", n)
+	}
+
+	// Stopping flushes the queue, later saves are inserted directly
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		writer.Run(done)
+		close(stopped)
+	}()
+	close(done)
+	<-stopped
+	if err := equity.Save(&EquitySnapshot{TraderID: "t1"}); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := equity.GetCount("t1"); n != 4 {
+		t.Errorf("expected all 4 snapshots stored, got %d", n)
+	}
+	if equity.WriterStats() != nil {
+		t.Error("stopped writer should be detached")
+	}
+}
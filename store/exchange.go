@@ -1,15 +1,20 @@
 package store
 
 import (
+	"errors"
 	"fmt"
 	"nofx/crypto"
 	"nofx/logger"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// ErrExchangeExists the user already has an account of this exchange type with the same name
+var ErrExchangeExists = errors.New("exchange account name already exists")
+
 // ExchangeStore exchange storage
 type ExchangeStore struct {
 	db *gorm.DB
@@ -36,6 +41,10 @@ type Exchange struct {
 	LighterPrivateKey       crypto.EncryptedString `gorm:"column:lighter_private_key;default:''" json:"lighterPrivateKey"`
 	LighterAPIKeyPrivateKey crypto.EncryptedString `gorm:"column:lighter_api_key_private_key;default:''" json:"lighterAPIKeyPrivateKey"`
 	LighterAPIKeyIndex      int             `gorm:"column:lighter_api_key_index;default:0" json:"lighterAPIKeyIndex"`
+	// API key permission verification (run asynchronously after keys are saved)
+	KeyVerificationStatus  string     `gorm:"column:key_verification_status;default:''" json:"key_verification_status"`
+	KeyVerificationMessage string     `gorm:"column:key_verification_message;default:''" json:"key_verification_message"`
+	KeyVerifiedAt          *time.Time `gorm:"column:key_verified_at" json:"key_verified_at"`
 	CreatedAt               time.Time       `json:"created_at"`
 	UpdatedAt               time.Time       `json:"updated_at"`
 }
@@ -72,6 +81,39 @@ func (s *ExchangeStore) initTables() error {
 	// Fix empty account_name for existing records
 	s.db.Model(&Exchange{}).Where("account_name = '' OR account_name IS NULL").Update("account_name", "Default")
 
+	// Account names are unique per user and exchange type (existing PostgreSQL tables: migration 0007)
+	if err := s.renameDuplicateAccounts(); err != nil {
+		return fmt.Errorf("failed to rename duplicate exchange accounts: %w", err)
+	}
+	if err := s.db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_exchanges_account ON exchanges(user_id, exchange_type, account_name)`).Error; err != nil {
+		return fmt.Errorf("failed to create unique index: %w", err)
+	}
+
+	return nil
+}
+
+// renameDuplicateAccounts appends the ID prefix to accounts sharing the name of an older account
+// of the same user and exchange type, created before names were enforced unique.
+func (s *ExchangeStore) renameDuplicateAccounts() error {
+	var duplicates []Exchange
+	err := s.db.Select("id", "account_name").
+		Where(`EXISTS (SELECT 1 FROM exchanges o WHERE o.user_id = exchanges.user_id AND o.exchange_type = exchanges.exchange_type
+			AND o.account_name = exchanges.account_name AND (o.created_at < exchanges.created_at OR (o.created_at = exchanges.created_at AND o.id < exchanges.id)))`).
+		Find(&duplicates).Error
+	if err != nil {
+		return err
+	}
+	for _, e := range duplicates {
+		suffix := e.ID
+		if len(suffix) > 8 {
+			suffix = suffix[:8]
+		}
+		name := fmt.Sprintf("%s (%s)", e.AccountName, suffix)
+		if err := s.db.Model(&Exchange{}).Where("id = ?", e.ID).UpdateColumn("account_name", name).Error; err != nil {
+			return err
+		}
+		logger.Warnf("⚠️ Exchange account %s renamed to %q, the name was used by another account", e.ID, name)
+	}
 	return nil
 }
 
@@ -146,6 +188,16 @@ func (s *ExchangeStore) List(userID string) ([]*Exchange, error) {
 	return exchanges, nil
 }
 
+// ListEnabled gets enabled exchanges of all users (used by background health checks)
+func (s *ExchangeStore) ListEnabled() ([]*Exchange, error) {
+	var exchanges []*Exchange
+	err := s.db.Where("enabled = ?", true).Order("user_id, exchange_type, account_name").Find(&exchanges).Error
+	if err != nil {
+		return nil, err
+	}
+	return exchanges, nil
+}
+
 // GetByID gets a specific exchange by UUID
 func (s *ExchangeStore) GetByID(userID, id string) (*Exchange, error) {
 	var exchange Exchange
@@ -178,6 +230,80 @@ func getExchangeNameAndType(exchangeType string) (name string, typ string) {
 	}
 }
 
+// ListByType gets user's accounts of one exchange type (e.g. Binance main + sub-accounts)
+func (s *ExchangeStore) ListByType(userID, exchangeType string) ([]*Exchange, error) {
+	var exchanges []*Exchange
+	err := s.db.Where("user_id = ? AND exchange_type = ?", userID, exchangeType).Order("account_name").Find(&exchanges).Error
+	if err != nil {
+		return nil, err
+	}
+	return exchanges, nil
+}
+
+// Resolve resolves a trader's exchange reference to an account.
+// ref is an account UUID; an exchange type (legacy clients) resolves only if the user has exactly one account of it
+func (s *ExchangeStore) Resolve(userID, ref string) (*Exchange, error) {
+	if exchange, err := s.GetByID(userID, ref); err == nil {
+		return exchange, nil
+	}
+
+	accounts, err := s.ListByType(userID, ref)
+	if err != nil {
+		return nil, err
+	}
+	switch len(accounts) {
+	case 0:
+		return nil, fmt.Errorf("exchange not found: %s", ref)
+	case 1:
+		return accounts[0], nil
+	default:
+		return nil, fmt.Errorf("multiple %s accounts configured, specify the account ID", ref)
+	}
+}
+
+// accountNameTaken checks if an account name is already used by another account of the same exchange type
+// CheckAccountName returns ErrExchangeExists if another account of the exchange's type already uses the name
+func (s *ExchangeStore) CheckAccountName(userID, id, accountName string) error {
+	exchange, err := s.GetByID(userID, id)
+	if err != nil {
+		return fmt.Errorf("exchange not found: id=%s, userID=%s", id, userID)
+	}
+	if taken, err := s.accountNameTaken(userID, exchange.ExchangeType, accountName, id); err != nil {
+		return err
+	} else if taken {
+		return fmt.Errorf("%w: %s %s", ErrExchangeExists, exchange.ExchangeType, accountName)
+	}
+	return nil
+}
+
+// isUniqueViolation whether err is a unique index violation (the name was taken concurrently)
+func isUniqueViolation(err error) bool {
+	return strings.Contains(err.Error(), "UNIQUE constraint failed") || strings.Contains(err.Error(), "duplicate key value")
+}
+
+func (s *ExchangeStore) accountNameTaken(userID, exchangeType, accountName, excludeID string) (bool, error) {
+	var count int64
+	err := s.db.Model(&Exchange{}).
+		Where("user_id = ? AND exchange_type = ? AND account_name = ? AND id <> ?", userID, exchangeType, accountName, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// defaultAccountName picks "Default" for the first account of an exchange type, then "Account 2", "Account 3"...
+func (s *ExchangeStore) defaultAccountName(userID, exchangeType string) (string, error) {
+	name := "Default"
+	for i := 2; ; i++ {
+		taken, err := s.accountNameTaken(userID, exchangeType, name, "")
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return name, nil
+		}
+		name = fmt.Sprintf("Account %d", i)
+	}
+}
+
 // Create creates a new exchange account with UUID
 func (s *ExchangeStore) Create(userID, exchangeType, accountName string, enabled bool,
 	apiKey, secretKey, passphrase string, testnet bool,
@@ -188,7 +314,14 @@ func (s *ExchangeStore) Create(userID, exchangeType, accountName string, enabled
 	name, typ := getExchangeNameAndType(exchangeType)
 
 	if accountName == "" {
-		accountName = "Default"
+		var err error
+		if accountName, err = s.defaultAccountName(userID, exchangeType); err != nil {
+			return "", err
+		}
+	} else if taken, err := s.accountNameTaken(userID, exchangeType, accountName, ""); err != nil {
+		return "", err
+	} else if taken {
+		return "", fmt.Errorf("%w: %s %s", ErrExchangeExists, exchangeType, accountName)
 	}
 
 	logger.Debugf("🔧 ExchangeStore.Create: userID=%s, exchangeType=%s, accountName=%s, id=%s",
@@ -217,6 +350,9 @@ func (s *ExchangeStore) Create(userID, exchangeType, accountName string, enabled
 	}
 
 	if err := s.db.Create(exchange).Error; err != nil {
+		if isUniqueViolation(err) {
+			return "", fmt.Errorf("%w: %s %s", ErrExchangeExists, exchangeType, accountName)
+		}
 		return "", err
 	}
 	return id, nil
@@ -269,8 +405,27 @@ func (s *ExchangeStore) Update(userID, id string, enabled bool, apiKey, secretKe
 	return nil
 }
 
+// UpdateKeyVerification records the result of an API key permission check
+// Does not touch updated_at, the exchange configuration itself is unchanged.
+func (s *ExchangeStore) UpdateKeyVerification(id, status, message string) error {
+	updates := map[string]interface{}{
+		"key_verification_status":  status,
+		"key_verification_message": message,
+		"key_verified_at":          nil,
+	}
+	if status != "pending" {
+		updates["key_verified_at"] = time.Now().UTC()
+	}
+	return s.db.Model(&Exchange{}).Where("id = ?", id).UpdateColumns(updates).Error
+}
+
 // UpdateAccountName updates the account name for an exchange
+// Names are unique per user and exchange type
 func (s *ExchangeStore) UpdateAccountName(userID, id, accountName string) error {
+	if err := s.CheckAccountName(userID, id, accountName); err != nil {
+		return err
+	}
+
 	result := s.db.Model(&Exchange{}).
 		Where("id = ? AND user_id = ?", id, userID).
 		Updates(map[string]interface{}{
@@ -278,6 +433,9 @@ func (s *ExchangeStore) UpdateAccountName(userID, id, accountName string) error
 			"updated_at":   time.Now().UTC(),
 		})
 	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return fmt.Errorf("%w: %s", ErrExchangeExists, accountName)
+		}
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
@@ -0,0 +1,342 @@
+package store
+
+import (
+	"fmt"
+	"nofx/market"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Strategy experiment modes
+const (
+	ExperimentModeAlternate = "alternate" // Variants take turns, one per cycle, full capital
+	ExperimentModeSplit     = "split"     // Both variants run every cycle on their share of capital
+)
+
+// Strategy experiment status
+const (
+	ExperimentStatusRunning = "running"
+	ExperimentStatusStopped = "stopped"
+)
+
+// Strategy experiment variants
+const (
+	ExperimentVariantA = "A"
+	ExperimentVariantB = "B"
+)
+
+// experimentAttributionSlackMs tolerance between a variant's open action and the position entry time
+// recorded from exchange fills (clock skew, order sync delay)
+const experimentAttributionSlackMs = 2 * 60 * 1000
+
+// StrategyExperiment A/B test of two strategies on one trader
+type StrategyExperiment struct {
+	ID          string    `gorm:"primaryKey" json:"id"`
+	UserID      string    `gorm:"column:user_id;not null;index" json:"user_id"`
+	TraderID    string    `gorm:"column:trader_id;not null;index" json:"trader_id"`
+	Name        string    `gorm:"not null" json:"name"`
+	StrategyAID string    `gorm:"column:strategy_a_id;not null" json:"strategy_a_id"`
+	StrategyBID string    `gorm:"column:strategy_b_id;not null" json:"strategy_b_id"`
+	Mode        string    `gorm:"column:mode;not null;default:alternate" json:"mode"`         // alternate/split
+	SplitRatio  float64   `gorm:"column:split_ratio;not null;default:0.5" json:"split_ratio"` // Capital share of variant A (split mode)
+	Status      string    `gorm:"column:status;not null;default:running;index" json:"status"`
+	CyclesA     int       `gorm:"column:cycles_a;default:0" json:"cycles_a"`
+	CyclesB     int       `gorm:"column:cycles_b;default:0" json:"cycles_b"`
+	StartedAt   int64     `gorm:"column:started_at;not null" json:"started_at"`  // Unix milliseconds UTC
+	StoppedAt   int64     `gorm:"column:stopped_at;default:0" json:"stopped_at"` // Unix milliseconds UTC, 0 while running
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (StrategyExperiment) TableName() string { return "strategy_experiments" }
+
+// StrategyID returns the strategy ID of a variant
+func (e *StrategyExperiment) StrategyID(variant string) string {
+	if variant == ExperimentVariantB {
+		return e.StrategyBID
+	}
+	return e.StrategyAID
+}
+
+// CapitalShare returns the share of trader capital a variant trades with
+func (e *StrategyExperiment) CapitalShare(variant string) float64 {
+	if e.Mode != ExperimentModeSplit {
+		return 1
+	}
+	if variant == ExperimentVariantB {
+		return 1 - e.SplitRatio
+	}
+	return e.SplitRatio
+}
+
+// NextVariants returns the variants that should run in the next cycle
+func (e *StrategyExperiment) NextVariants() []string {
+	if e.Mode == ExperimentModeSplit {
+		return []string{ExperimentVariantA, ExperimentVariantB}
+	}
+	if e.CyclesA > e.CyclesB {
+		return []string{ExperimentVariantB}
+	}
+	return []string{ExperimentVariantA}
+}
+
+// ExperimentEntry position opened by an experiment variant (used to attribute trades)
+type ExperimentEntry struct {
+	ID           int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	ExperimentID string `gorm:"column:experiment_id;not null;index" json:"experiment_id"`
+	Variant      string `gorm:"column:variant;not null" json:"variant"`
+	Symbol       string `gorm:"column:symbol;not null" json:"symbol"`
+	Side         string `gorm:"column:side;not null" json:"side"` // long/short
+	Time         int64  `gorm:"column:time;not null" json:"time"` // Unix milliseconds UTC
+}
+
+func (ExperimentEntry) TableName() string { return "experiment_entries" }
+
+// ExperimentVariantStats per-variant experiment statistics
+type ExperimentVariantStats struct {
+	Variant       string  `json:"variant"`
+	StrategyID    string  `json:"strategy_id"`
+	CapitalShare  float64 `json:"capital_share"`
+	Cycles        int     `json:"cycles"`
+	Entries       int     `json:"entries"`
+	OpenPositions int     `json:"open_positions"`
+	ClosedTrades  int     `json:"closed_trades"`
+	WinTrades     int     `json:"win_trades"`
+	LossTrades    int     `json:"loss_trades"`
+	WinRate       float64 `json:"win_rate"`
+	TotalPnL      float64 `json:"total_pnl"`
+	TotalFee      float64 `json:"total_fee"`
+	NetPnL        float64 `json:"net_pnl"`
+	AvgPnL        float64 `json:"avg_pnl"`
+	ProfitFactor  float64 `json:"profit_factor"`
+	// ReturnPct net PnL relative to the variant's capital at experiment start (split mode only)
+	ReturnPct float64 `json:"return_pct,omitempty"`
+}
+
+// ExperimentStore strategy experiment storage
+type ExperimentStore struct {
+	db *gorm.DB
+}
+
+// NewExperimentStore creates a new ExperimentStore
+func NewExperimentStore(db *gorm.DB) *ExperimentStore {
+	return &ExperimentStore{db: db}
+}
+
+func (s *ExperimentStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'strategy_experiments'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&StrategyExperiment{}, &ExperimentEntry{})
+}
+
+// Create starts a new experiment (a trader can only run one experiment at a time)
+func (s *ExperimentStore) Create(exp *StrategyExperiment) error {
+	if exp.Mode == "" {
+		exp.Mode = ExperimentModeAlternate
+	}
+	if exp.Mode != ExperimentModeAlternate && exp.Mode != ExperimentModeSplit {
+		return fmt.Errorf("invalid experiment mode: %s", exp.Mode)
+	}
+	if exp.SplitRatio <= 0 || exp.SplitRatio >= 1 {
+		exp.SplitRatio = 0.5
+	}
+	exp.Status = ExperimentStatusRunning
+	if exp.StartedAt == 0 {
+		exp.StartedAt = time.Now().UTC().UnixMilli()
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var running int64
+		if err := tx.Model(&StrategyExperiment{}).
+			Where("trader_id = ? AND status = ?", exp.TraderID, ExperimentStatusRunning).
+			Count(&running).Error; err != nil {
+			return err
+		}
+		if running > 0 {
+			return fmt.Errorf("trader already has a running experiment")
+		}
+		return tx.Create(exp).Error
+	})
+}
+
+// List gets user's experiments, optionally filtered by trader
+func (s *ExperimentStore) List(userID, traderID string) ([]*StrategyExperiment, error) {
+	var exps []*StrategyExperiment
+	query := s.db.Where("user_id = ?", userID)
+	if traderID != "" {
+		query = query.Where("trader_id = ?", traderID)
+	}
+	err := query.Order("created_at DESC").Find(&exps).Error
+	return exps, err
+}
+
+// Get gets a single experiment
+func (s *ExperimentStore) Get(userID, id string) (*StrategyExperiment, error) {
+	var exp StrategyExperiment
+	if err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&exp).Error; err != nil {
+		return nil, err
+	}
+	return &exp, nil
+}
+
+// GetRunning gets trader's running experiment, nil if none
+func (s *ExperimentStore) GetRunning(traderID string) (*StrategyExperiment, error) {
+	var exps []*StrategyExperiment
+	err := s.db.Where("trader_id = ? AND status = ?", traderID, ExperimentStatusRunning).
+		Order("created_at DESC").Limit(1).Find(&exps).Error
+	if err != nil || len(exps) == 0 {
+		return nil, err
+	}
+	return exps[0], nil
+}
+
+// Stop stops a running experiment (statistics remain available)
+func (s *ExperimentStore) Stop(userID, id string) error {
+	result := s.db.Model(&StrategyExperiment{}).
+		Where("id = ? AND user_id = ? AND status = ?", id, userID, ExperimentStatusRunning).
+		Updates(map[string]interface{}{
+			"status":     ExperimentStatusStopped,
+			"stopped_at": time.Now().UTC().UnixMilli(),
+			"updated_at": time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete deletes an experiment and its recorded entries
+func (s *ExperimentStore) Delete(userID, id string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&StrategyExperiment{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("experiment_id = ?", id).Delete(&ExperimentEntry{}).Error
+	})
+}
+
+// IncrementCycles counts a completed decision cycle for a variant
+func (s *ExperimentStore) IncrementCycles(id, variant string) error {
+	column := "cycles_a"
+	if variant == ExperimentVariantB {
+		column = "cycles_b"
+	}
+	return s.db.Model(&StrategyExperiment{}).Where("id = ?", id).
+		UpdateColumn(column, gorm.Expr(column+" + 1")).Error
+}
+
+// RecordEntry records a position opened by a variant
+func (s *ExperimentStore) RecordEntry(entry *ExperimentEntry) error {
+	entry.Symbol = market.Normalize(entry.Symbol)
+	entry.Side = strings.ToLower(entry.Side)
+	if entry.Time == 0 {
+		entry.Time = time.Now().UTC().UnixMilli()
+	}
+	// Omit ID to let PostgreSQL sequence auto-generate it
+	return s.db.Omit("ID").Create(entry).Error
+}
+
+// ListEntries gets experiment entries in time order
+func (s *ExperimentStore) ListEntries(id string) ([]*ExperimentEntry, error) {
+	var entries []*ExperimentEntry
+	err := s.db.Where("experiment_id = ?", id).Order("time ASC").Find(&entries).Error
+	return entries, err
+}
+
+// AttributeVariant returns the variant that opened a position, "" if not opened by the experiment.
+// The latest variant entry for the same symbol/side before the position entry time wins.
+func AttributeVariant(entries []*ExperimentEntry, symbol, side string, entryTimeMs int64) string {
+	symbol = market.Normalize(symbol)
+	side = strings.ToLower(side)
+	variant := ""
+	for _, e := range entries {
+		if e.Time > entryTimeMs+experimentAttributionSlackMs {
+			break
+		}
+		if e.Symbol == symbol && e.Side == side {
+			variant = e.Variant
+		}
+	}
+	return variant
+}
+
+// GetVariantStats computes per-variant statistics from positions attributed to each variant
+func (s *ExperimentStore) GetVariantStats(exp *StrategyExperiment, initialBalance float64) ([]*ExperimentVariantStats, error) {
+	entries, err := s.ListEntries(exp.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load experiment entries: %w", err)
+	}
+
+	var positions []*TraderPosition
+	query := s.db.Where("trader_id = ? AND entry_time >= ?", exp.TraderID, exp.StartedAt-experimentAttributionSlackMs)
+	if exp.StoppedAt > 0 {
+		query = query.Where("entry_time <= ?", exp.StoppedAt+experimentAttributionSlackMs)
+	}
+	if err := query.Order("entry_time ASC").Find(&positions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load experiment positions: %w", err)
+	}
+
+	stats := map[string]*ExperimentVariantStats{
+		ExperimentVariantA: {Variant: ExperimentVariantA, StrategyID: exp.StrategyAID, CapitalShare: exp.CapitalShare(ExperimentVariantA), Cycles: exp.CyclesA},
+		ExperimentVariantB: {Variant: ExperimentVariantB, StrategyID: exp.StrategyBID, CapitalShare: exp.CapitalShare(ExperimentVariantB), Cycles: exp.CyclesB},
+	}
+	for _, e := range entries {
+		if st, ok := stats[e.Variant]; ok {
+			st.Entries++
+		}
+	}
+
+	grossProfit := map[string]float64{}
+	grossLoss := map[string]float64{}
+	for _, pos := range positions {
+		st, ok := stats[AttributeVariant(entries, pos.Symbol, pos.Side, pos.EntryTime)]
+		if !ok {
+			continue
+		}
+		if pos.Status != "CLOSED" {
+			st.OpenPositions++
+			continue
+		}
+		st.ClosedTrades++
+		st.TotalPnL += pos.RealizedPnL
+		st.TotalFee += pos.Fee
+		if pos.RealizedPnL > 0 {
+			st.WinTrades++
+			grossProfit[st.Variant] += pos.RealizedPnL
+		} else if pos.RealizedPnL < 0 {
+			st.LossTrades++
+			grossLoss[st.Variant] -= pos.RealizedPnL
+		}
+	}
+
+	result := []*ExperimentVariantStats{stats[ExperimentVariantA], stats[ExperimentVariantB]}
+	for _, st := range result {
+		st.NetPnL = st.TotalPnL - st.TotalFee
+		if st.ClosedTrades > 0 {
+			st.WinRate = float64(st.WinTrades) / float64(st.ClosedTrades) * 100
+			st.AvgPnL = st.TotalPnL / float64(st.ClosedTrades)
+		}
+		if grossLoss[st.Variant] > 0 {
+			st.ProfitFactor = grossProfit[st.Variant] / grossLoss[st.Variant]
+		}
+		if exp.Mode == ExperimentModeSplit && initialBalance > 0 {
+			st.ReturnPct = st.NetPnL / (initialBalance * st.CapitalShare) * 100
+		}
+	}
+	return result, nil
+}
@@ -2,6 +2,9 @@ package store
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/driver/postgres"
@@ -18,9 +21,16 @@ func DB() *gorm.DB {
 	return gormDB
 }
 
-// InitGorm initializes GORM with SQLite
+// InitGorm initializes GORM with SQLite using the default options
 func InitGorm(dbPath string) (*gorm.DB, error) {
-	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+	return InitGormSQLite(dbPath, SQLiteOptions{})
+}
+
+// InitGormSQLite initializes GORM with SQLite
+// The options are passed in the DSN, so every connection of the pool gets them.
+func InitGormSQLite(dbPath string, opts SQLiteOptions) (*gorm.DB, error) {
+	opts = opts.withDefaults()
+	db, err := gorm.Open(sqlite.Open(sqliteDSN(dbPath, opts)), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 		// Use UTC for all auto-generated timestamps (autoCreateTime, autoUpdateTime)
 		NowFunc: func() time.Time {
@@ -36,26 +46,39 @@ func InitGorm(dbPath string) (*gorm.DB, error) {
 	if err != nil {
 		return nil, err
 	}
-	sqlDB.SetMaxOpenConns(1)
-	sqlDB.SetMaxIdleConns(1)
-
-	// Enable foreign keys for SQLite
-	db.Exec("PRAGMA foreign_keys = ON")
-	db.Exec("PRAGMA journal_mode = DELETE")
-	db.Exec("PRAGMA synchronous = FULL")
-	db.Exec("PRAGMA busy_timeout = 5000")
+	sqlDB.SetMaxOpenConns(opts.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(opts.MaxOpenConns)
 
 	gormDB = db
 	return db, nil
 }
 
+// sqliteDSN appends the connection options to a SQLite path
+// Transactions take the write lock when they begin (_txlock=immediate): a read transaction
+// upgraded to a write later fails at once with "database is locked" instead of waiting.
+func sqliteDSN(path string, opts SQLiteOptions) string {
+	params := url.Values{}
+	params.Set("_foreign_keys", "1")
+	params.Set("_busy_timeout", strconv.Itoa(opts.BusyTimeoutMs))
+	params.Set("_journal_mode", opts.JournalMode)
+	params.Set("_synchronous", opts.Synchronous)
+	params.Set("_txlock", "immediate")
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + params.Encode()
+}
+
 // InitGormPostgres initializes GORM with PostgreSQL
 func InitGormPostgres(host string, port int, user, password, dbname, sslmode string) (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		host, port, user, password, dbname, sslmode,
-	)
+	return InitGormPostgresDSN(DBConfig{
+		Host: host, Port: port, User: user, Password: password, DBName: dbname, SSLMode: sslmode,
+	}.PostgresDSN())
+}
 
+// InitGormPostgresDSN initializes GORM with PostgreSQL from a connection string
+func InitGormPostgresDSN(dsn string) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 		// Use UTC for all auto-generated timestamps (autoCreateTime, autoUpdateTime)
@@ -84,17 +107,10 @@ func InitGormPostgres(host string, port int, user, password, dbname, sslmode str
 func InitGormWithConfig(cfg DBConfig) (*gorm.DB, error) {
 	switch cfg.Type {
 	case DBTypeSQLite:
-		return InitGorm(cfg.Path)
+		return InitGormSQLite(cfg.Path, cfg.SQLite)
 
 	case DBTypePostgres:
-		return InitGormPostgres(
-			cfg.Host,
-			cfg.Port,
-			cfg.User,
-			cfg.Password,
-			cfg.DBName,
-			cfg.SSLMode,
-		)
+		return InitGormPostgresDSN(cfg.PostgresDSN())
 
 	default:
 		return nil, fmt.Errorf("unsupported DB_TYPE: %s (use 'sqlite' or 'postgres')", cfg.Type)
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Income types (match trader/types IncomeType* values)
+const (
+	IncomeTypeFunding    = "FUNDING_FEE"
+	IncomeTypeCommission = "COMMISSION"
+	IncomeTypeTransfer   = "TRANSFER" // Deposit/withdrawal, an equity adjustment excluded from PnL
+)
+
+// TraderIncome funding payment, commission or transfer synced from exchange
+// Amount is the signed balance change: negative = paid / withdrawn, positive = received / deposited
+type TraderIncome struct {
+	ID         int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID   string  `gorm:"column:trader_id;not null;uniqueIndex:idx_income_unique,priority:1;index:idx_income_trader_time,priority:1" json:"trader_id"`
+	IncomeType string  `gorm:"column:income_type;not null;uniqueIndex:idx_income_unique,priority:2" json:"income_type"`
+	TranID     string  `gorm:"column:tran_id;not null;uniqueIndex:idx_income_unique,priority:3" json:"tran_id"`
+	Symbol     string  `gorm:"column:symbol;default:''" json:"symbol"`
+	Amount     float64 `gorm:"column:amount;not null;default:0" json:"amount"`
+	Asset      string  `gorm:"column:asset;default:USDT" json:"asset"`
+	Time       int64   `gorm:"column:time;not null;index:idx_income_trader_time,priority:2" json:"time"` // Unix milliseconds UTC
+	CreatedAt  int64   `gorm:"column:created_at" json:"created_at"`                                      // Unix milliseconds UTC
+}
+
+// TableName returns the table name for TraderIncome
+func (TraderIncome) TableName() string {
+	return "trader_income"
+}
+
+// IncomeTotals aggregated funding and commission
+type IncomeTotals struct {
+	TotalFunding    float64 `json:"total_funding"`    // Net funding (negative = paid)
+	TotalCommission float64 `json:"total_commission"` // Net commission (negative = paid)
+	FundingCount    int     `json:"funding_count"`
+	CommissionCount int     `json:"commission_count"`
+}
+
+// TransferTotals aggregated deposits and withdrawals
+type TransferTotals struct {
+	Deposits    float64 `json:"deposits"`    // Sum of deposits
+	Withdrawals float64 `json:"withdrawals"` // Sum of withdrawals (positive)
+	Count       int     `json:"count"`
+}
+
+// Net returns deposits minus withdrawals
+func (t TransferTotals) Net() float64 {
+	return t.Deposits - t.Withdrawals
+}
+
+// IncomeStore funding/commission storage
+type IncomeStore struct {
+	db *gorm.DB
+}
+
+// NewIncomeStore creates a new IncomeStore
+func NewIncomeStore(db *gorm.DB) *IncomeStore {
+	return &IncomeStore{db: db}
+}
+
+func (s *IncomeStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'trader_income'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&TraderIncome{})
+}
+
+// SaveBatch inserts income records, skipping ones already synced; returns number inserted
+func (s *IncomeStore) SaveBatch(records []*TraderIncome) (int, error) {
+	if len(records) == 0 {
+		return 0, nil
+	}
+	nowMs := time.Now().UTC().UnixMilli()
+	for _, rec := range records {
+		rec.CreatedAt = nowMs
+	}
+	// Omit ID to let PostgreSQL sequence auto-generate it; the unique index drops already synced records
+	result := s.db.Omit("ID").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "trader_id"}, {Name: "income_type"}, {Name: "tran_id"}},
+		DoNothing: true,
+	}).CreateInBatches(&records, 200)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to save income records: %w", result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
+
+// GetLastTime gets the latest synced income time (ms) for a type, 0 if none
+func (s *IncomeStore) GetLastTime(traderID, incomeType string) (int64, error) {
+	var last *int64
+	err := s.db.Model(&TraderIncome{}).
+		Where("trader_id = ? AND income_type = ?", traderID, incomeType).
+		Select("MAX(time)").
+		Scan(&last).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to query last income time: %w", err)
+	}
+	if last == nil {
+		return 0, nil
+	}
+	return *last, nil
+}
+
+// GetTotals aggregates trader's funding and commission
+func (s *IncomeStore) GetTotals(traderID string) (*IncomeTotals, error) {
+	var rows []struct {
+		IncomeType string
+		Total      float64
+		Cnt        int
+	}
+	err := s.db.Model(&TraderIncome{}).
+		Select("income_type, COALESCE(SUM(amount), 0) as total, COUNT(*) as cnt").
+		Where("trader_id = ?", traderID).
+		Group("income_type").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate income: %w", err)
+	}
+
+	totals := &IncomeTotals{}
+	for _, r := range rows {
+		switch r.IncomeType {
+		case IncomeTypeFunding:
+			totals.TotalFunding = r.Total
+			totals.FundingCount = r.Cnt
+		case IncomeTypeCommission:
+			totals.TotalCommission = r.Total
+			totals.CommissionCount = r.Cnt
+		}
+	}
+	return totals, nil
+}
+
+// GetTransferTotals aggregates trader's deposits and withdrawals since sinceMs
+func (s *IncomeStore) GetTransferTotals(traderID string, sinceMs int64) (*TransferTotals, error) {
+	var row struct {
+		Deposits    float64
+		Withdrawals float64
+		Cnt         int
+	}
+	err := s.db.Model(&TraderIncome{}).
+		Select("COALESCE(SUM(CASE WHEN amount > 0 THEN amount ELSE 0 END), 0) as deposits, "+
+			"COALESCE(SUM(CASE WHEN amount < 0 THEN -amount ELSE 0 END), 0) as withdrawals, COUNT(*) as cnt").
+		Where("trader_id = ? AND income_type = ? AND time >= ?", traderID, IncomeTypeTransfer, sinceMs).
+		Scan(&row).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate transfers: %w", err)
+	}
+	return &TransferTotals{Deposits: row.Deposits, Withdrawals: row.Withdrawals, Count: row.Cnt}, nil
+}
+
+// List gets trader's income records in time order (oldest first)
+func (s *IncomeStore) List(traderID string, sinceMs int64) ([]*TraderIncome, error) {
+	var records []*TraderIncome
+	err := s.db.Where("trader_id = ? AND time >= ?", traderID, sinceMs).
+		Order("time ASC").
+		Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query income records: %w", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Limit entry status
+const (
+	LimitEntryPending   = "PENDING"
+	LimitEntryFilled    = "FILLED"
+	LimitEntryCancelled = "CANCELLED"
+	LimitEntryExpired   = "EXPIRED"
+)
+
+// LimitEntry AI limit-order entry tracked across decision cycles
+// Stop loss / take profit are placed once the entry order (partially) fills.
+type LimitEntry struct {
+	ID           int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID     string  `gorm:"column:trader_id;not null;index:idx_limit_entries_trader_status,priority:1" json:"trader_id"`
+	Symbol       string  `gorm:"column:symbol;not null" json:"symbol"`
+	Side         string  `gorm:"column:side;not null" json:"side"` // long/short
+	OrderID      string  `gorm:"column:order_id;not null" json:"order_id"`
+	Price        float64 `gorm:"column:price;not null" json:"price"`
+	Quantity     float64 `gorm:"column:quantity;not null" json:"quantity"`
+	Leverage     int     `gorm:"column:leverage;default:1" json:"leverage"`
+	StopLoss     float64 `gorm:"column:stop_loss;default:0" json:"stop_loss"`
+	TakeProfit   float64 `gorm:"column:take_profit;default:0" json:"take_profit"`
+	Status       string  `gorm:"column:status;not null;default:PENDING;index:idx_limit_entries_trader_status,priority:2" json:"status"`
+	FilledQty    float64 `gorm:"column:filled_qty;default:0" json:"filled_qty"`
+	AvgPrice     float64 `gorm:"column:avg_price;default:0" json:"avg_price"`
+	ProtectedQty float64 `gorm:"column:protected_qty;default:0" json:"protected_qty"` // Partial fill covered by SL/TP while pending
+	Reason       string  `gorm:"column:reason;default:''" json:"reason"`
+	ExpiresAt    int64   `gorm:"column:expires_at;not null" json:"expires_at"` // Unix milliseconds UTC
+	CreatedAt    int64   `gorm:"column:created_at" json:"created_at"`          // Unix milliseconds UTC
+	UpdatedAt    int64   `gorm:"column:updated_at" json:"updated_at"`          // Unix milliseconds UTC
+}
+
+// TableName returns the table name for LimitEntry
+func (LimitEntry) TableName() string {
+	return "trader_limit_entries"
+}
+
+// LimitEntryStore limit entry storage
+type LimitEntryStore struct {
+	db *gorm.DB
+}
+
+// NewLimitEntryStore creates a new LimitEntryStore
+func NewLimitEntryStore(db *gorm.DB) *LimitEntryStore {
+	return &LimitEntryStore{db: db}
+}
+
+func (s *LimitEntryStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'trader_limit_entries'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&LimitEntry{})
+}
+
+// Create records a newly placed limit entry
+func (s *LimitEntryStore) Create(entry *LimitEntry) error {
+	nowMs := time.Now().UTC().UnixMilli()
+	entry.CreatedAt = nowMs
+	entry.UpdatedAt = nowMs
+	if entry.Status == "" {
+		entry.Status = LimitEntryPending
+	}
+	// Omit ID to let PostgreSQL sequence auto-generate it
+	if err := s.db.Omit("ID").Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create limit entry: %w", err)
+	}
+	return nil
+}
+
+// ListPending gets trader's pending limit entries (oldest first)
+func (s *LimitEntryStore) ListPending(traderID string) ([]*LimitEntry, error) {
+	var entries []*LimitEntry
+	err := s.db.Where("trader_id = ? AND status = ?", traderID, LimitEntryPending).
+		Order("created_at ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending limit entries: %w", err)
+	}
+	return entries, nil
+}
+
+// List gets trader's recent limit entries (newest first)
+func (s *LimitEntryStore) List(traderID string, limit int) ([]*LimitEntry, error) {
+	var entries []*LimitEntry
+	err := s.db.Where("trader_id = ?", traderID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query limit entries: %w", err)
+	}
+	return entries, nil
+}
+
+// UpdateProtected records the partial fill of a pending entry that stop loss/take profit now cover
+func (s *LimitEntryStore) UpdateProtected(id int64, protectedQty, avgPrice float64) error {
+	return s.db.Model(&LimitEntry{}).
+		Where("id = ? AND status = ?", id, LimitEntryPending).
+		Updates(map[string]interface{}{
+			"protected_qty": protectedQty,
+			"filled_qty":    protectedQty,
+			"avg_price":     avgPrice,
+			"updated_at":    time.Now().UTC().UnixMilli(),
+		}).Error
+}
+
+// UpdateStatus transitions a pending entry to a final status
+func (s *LimitEntryStore) UpdateStatus(id int64, status string, filledQty, avgPrice float64, reason string) error {
+	return s.db.Model(&LimitEntry{}).
+		Where("id = ? AND status = ?", id, LimitEntryPending).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"filled_qty": filledQty,
+			"avg_price":  avgPrice,
+			"reason":     reason,
+			"updated_at": time.Now().UTC().UnixMilli(),
+		}).Error
+}
@@ -0,0 +1,160 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FundingRatePoint one recorded funding rate of a symbol
+type FundingRatePoint struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Symbol    string    `gorm:"column:symbol;not null;uniqueIndex:idx_funding_history_point" json:"symbol"`
+	Timestamp time.Time `gorm:"not null;uniqueIndex:idx_funding_history_point" json:"timestamp"`
+	Rate      float64   `gorm:"column:rate;not null;default:0" json:"rate"` // Per funding interval, e.g. 0.0001 = 0.01%
+}
+
+// TableName returns the table name for FundingRatePoint
+func (FundingRatePoint) TableName() string { return "funding_rate_history" }
+
+// OpenInterestPoint one recorded open interest of a symbol
+type OpenInterestPoint struct {
+	ID           int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Symbol       string    `gorm:"column:symbol;not null;uniqueIndex:idx_oi_history_point" json:"symbol"`
+	Timestamp    time.Time `gorm:"not null;uniqueIndex:idx_oi_history_point" json:"timestamp"`
+	OpenInterest float64   `gorm:"column:open_interest;not null;default:0" json:"open_interest"` // Contracts (base asset)
+	ValueUSD     float64   `gorm:"column:value_usd;not null;default:0" json:"value_usd"`         // Open interest × price
+}
+
+// TableName returns the table name for OpenInterestPoint
+func (OpenInterestPoint) TableName() string { return "open_interest_history" }
+
+// MarketHistoryStore funding rate and open interest time series storage
+type MarketHistoryStore struct {
+	db *gorm.DB
+}
+
+// NewMarketHistoryStore creates a new MarketHistoryStore
+func NewMarketHistoryStore(db *gorm.DB) *MarketHistoryStore {
+	return &MarketHistoryStore{db: db}
+}
+
+func (s *MarketHistoryStore) initTables() error {
+	for _, model := range []interface{}{&FundingRatePoint{}, &OpenInterestPoint{}} {
+		// For PostgreSQL with existing table, skip AutoMigrate
+		if s.db.Dialector.Name() == "postgres" && s.db.Migrator().HasTable(model) {
+			continue
+		}
+		if err := s.db.AutoMigrate(model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveFunding stores a funding rate, a reading of the same symbol and timestamp is overwritten
+func (s *MarketHistoryStore) SaveFunding(p *FundingRatePoint) error {
+	p.ID = 0
+	p.Timestamp = p.Timestamp.UTC()
+	err := s.db.Omit("ID").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "symbol"}, {Name: "timestamp"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate"}),
+	}).Create(p).Error
+	if err != nil {
+		return fmt.Errorf("failed to save funding rate: %w", err)
+	}
+	return nil
+}
+
+// SaveOpenInterest stores an open interest reading, a reading of the same symbol and timestamp is overwritten
+func (s *MarketHistoryStore) SaveOpenInterest(p *OpenInterestPoint) error {
+	p.ID = 0
+	p.Timestamp = p.Timestamp.UTC()
+	err := s.db.Omit("ID").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "symbol"}, {Name: "timestamp"}},
+		DoUpdates: clause.AssignmentColumns([]string{"open_interest", "value_usd"}),
+	}).Create(p).Error
+	if err != nil {
+		return fmt.Errorf("failed to save open interest: %w", err)
+	}
+	return nil
+}
+
+// ListFunding gets the funding rates of a symbol since a time, oldest first, at most limit (the most recent ones)
+func (s *MarketHistoryStore) ListFunding(symbol string, since time.Time, limit int) ([]*FundingRatePoint, error) {
+	var points []*FundingRatePoint
+	err := s.db.Where("symbol = ? AND timestamp >= ?", symbol, since.UTC()).
+		Order("timestamp DESC").
+		Limit(limit).
+		Find(&points).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query funding rate history: %w", err)
+	}
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+	return points, nil
+}
+
+// ListOpenInterest gets the open interest of a symbol since a time, oldest first, at most limit (the most recent ones)
+func (s *MarketHistoryStore) ListOpenInterest(symbol string, since time.Time, limit int) ([]*OpenInterestPoint, error) {
+	var points []*OpenInterestPoint
+	err := s.db.Where("symbol = ? AND timestamp >= ?", symbol, since.UTC()).
+		Order("timestamp DESC").
+		Limit(limit).
+		Find(&points).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open interest history: %w", err)
+	}
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+	return points, nil
+}
+
+// ListFundingBetween gets the funding rates of a symbol recorded in [from, to], oldest first, at most limit (the most recent ones)
+func (s *MarketHistoryStore) ListFundingBetween(symbol string, from, to time.Time, limit int) ([]*FundingRatePoint, error) {
+	var points []*FundingRatePoint
+	err := s.db.Where("symbol = ? AND timestamp >= ? AND timestamp <= ?", symbol, from.UTC(), to.UTC()).
+		Order("timestamp DESC").
+		Limit(limit).
+		Find(&points).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query funding rate history: %w", err)
+	}
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+	return points, nil
+}
+
+// ListOpenInterestBetween gets the open interest of a symbol recorded in [from, to], oldest first, at most limit (the most recent ones)
+func (s *MarketHistoryStore) ListOpenInterestBetween(symbol string, from, to time.Time, limit int) ([]*OpenInterestPoint, error) {
+	var points []*OpenInterestPoint
+	err := s.db.Where("symbol = ? AND timestamp >= ? AND timestamp <= ?", symbol, from.UTC(), to.UTC()).
+		Order("timestamp DESC").
+		Limit(limit).
+		Find(&points).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open interest history: %w", err)
+	}
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+	return points, nil
+}
+
+// DeleteBefore deletes funding rate and open interest readings older than a time, returns the number removed
+func (s *MarketHistoryStore) DeleteBefore(t time.Time) (int64, error) {
+	var removed int64
+	for _, model := range []interface{}{&FundingRatePoint{}, &OpenInterestPoint{}} {
+		result := s.db.Where("timestamp < ?", t.UTC()).Delete(model)
+		if result.Error != nil {
+			return removed, fmt.Errorf("failed to delete market history: %w", result.Error)
+		}
+		removed += result.RowsAffected
+	}
+	return removed, nil
+}
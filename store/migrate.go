@@ -0,0 +1,155 @@
+package store
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"nofx/logger"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// PostgreSQL Schema Migrations
+// ============================================================================
+// Tables are created by GORM AutoMigrate; schema changes to existing tables
+// are shipped as versioned SQL files in migrations/postgres, named
+// <version>_<description>.sql. Each file runs once in its own transaction and
+// is recorded in schema_migrations. Initialization holds a PostgreSQL advisory
+// lock so several instances can start against the same database.
+// ============================================================================
+
+//go:embed migrations/postgres/*.sql
+var postgresMigrationFiles embed.FS
+
+// migrationLockKey advisory lock key held while the schema is initialized
+const migrationLockKey int64 = 0x6e6f6678 // "nofx"
+
+// migration one versioned schema change
+type migration struct {
+	Version int64
+	Name    string
+	SQL     string
+}
+
+// SchemaMigration applied migration record
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null;default:''"`
+	AppliedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (SchemaMigration) TableName() string { return "schema_migrations" }
+
+// loadMigrations reads the migration files of a directory, sorted by version
+func loadMigrations(fsys fs.FS, dir string) ([]migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	migrations := make([]migration, 0, len(entries))
+	seen := make(map[int64]string)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".sql")
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration file name %s (expected <version>_<description>.sql)", entry.Name())
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, other, name)
+		}
+		seen[version] = name
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		migrations = append(migrations, migration{Version: version, Name: name, SQL: string(content)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// withSchemaLock runs fn while holding the schema advisory lock (PostgreSQL only)
+// The lock is session-scoped, so it is taken and released on one dedicated connection
+func (s *Store) withSchemaLock(fn func() error) error {
+	if s.gdb.Dialector.Name() != "postgres" {
+		return fn()
+	}
+
+	ctx := context.Background()
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection for schema lock: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire schema lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockKey); err != nil {
+			logger.Warnf("⚠️ Failed to release schema lock: %v", err)
+		}
+	}()
+
+	return fn()
+}
+
+// runMigrations applies pending PostgreSQL migrations in version order
+func (s *Store) runMigrations() error {
+	if s.gdb.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	migrations, err := loadMigrations(postgresMigrationFiles, "migrations/postgres")
+	if err != nil {
+		return err
+	}
+	return applyMigrations(s.gdb, migrations)
+}
+
+// applyMigrations runs the migrations not yet recorded in schema_migrations, in the given order
+func applyMigrations(db *gorm.DB, migrations []migration) error {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var applied []int64
+	if err := db.Model(&SchemaMigration{}).Pluck("version", &applied).Error; err != nil {
+		return fmt.Errorf("failed to load applied migrations: %w", err)
+	}
+	done := make(map[int64]bool, len(applied))
+	for _, v := range applied {
+		done[v] = true
+	}
+
+	for _, m := range migrations {
+		if done[m.Version] {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(m.SQL).Error; err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %s failed: %w", m.Name, err)
+		}
+		logger.Infof("📦 Applied database migration %s", m.Name)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/10_add_index.sql":     {Data: []byte("CREATE INDEX b ON t (b);")},
+		"m/2_add_column.sql":     {Data: []byte("ALTER TABLE t ADD COLUMN b TEXT;")},
+		"m/1_create_table.sql":   {Data: []byte("CREATE TABLE t (a TEXT);")},
+		"m/README.md":            {Data: []byte("not a migration")},
+		"m/nested/3_skipped.sql": {Data: []byte("SELECT 1;")},
+	}
+	migrations, err := loadMigrations(fsys, "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, m := range migrations {
+		names = append(names, m.Name)
+	}
+	// Numeric, not lexical, order; other files and directories are ignored
+	if got := strings.Join(names, ","); got != "1_create_table,2_add_column,10_add_index" {
+		t.Errorf("order = %s", got)
+	}
+	if migrations[1].Version != 2 || migrations[1].SQL != "ALTER TABLE t ADD COLUMN b TEXT;" {
+		t.Errorf("migration = %+v", migrations[1])
+	}
+
+	for name, files := range map[string]fstest.MapFS{
+		"duplicate version": {"m/1_a.sql": {}, "m/01_b.sql": {}},
+		"no version":        {"m/create_table.sql": {}},
+		"zero version":      {"m/0_init.sql": {}},
+		"negative version":  {"m/-1_init.sql": {}},
+	} {
+		if _, err := loadMigrations(files, "m"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := loadMigrations(fstest.MapFS{}, "missing"); err == nil {
+		t.Error("missing directory: expected an error")
+	}
+
+	// The shipped migrations are well-formed
+	if _, err := loadMigrations(postgresMigrationFiles, "migrations/postgres"); err != nil {
+		t.Errorf("embedded migrations: %v", err)
+	}
+}
+
+func TestApplyMigrationsSkipsApplied(t *testing.T) {
+	st, err := New(filepath.Join(t.TempDir(), "migrate.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	db := st.GormDB()
+
+	first := []migration{
+		{Version: 1, Name: "1_create", SQL: "CREATE TABLE migrate_test (a TEXT)"},
+		{Version: 2, Name: "2_insert", SQL: "INSERT INTO migrate_test (a) VALUES ('x')"},
+	}
+	if err := applyMigrations(db, first); err != nil {
+		t.Fatal(err)
+	}
+	// Re-running applies only the new version; 1 and 2 would fail or duplicate the row
+	if err := applyMigrations(db, append(first, migration{Version: 3, Name: "3_insert", SQL: "INSERT INTO migrate_test (a) VALUES ('y')"})); err != nil {
+		t.Fatal(err)
+	}
+	var rows int64
+	db.Table("migrate_test").Count(&rows)
+	var applied int64
+	db.Model(&SchemaMigration{}).Count(&applied)
+	if rows != 2 || applied != 3 {
+		t.Errorf("rows = %d, applied = %d, want 2 and 3", rows, applied)
+	}
+
+	// A failing migration is rolled back and not recorded
+	if err := applyMigrations(db, []migration{{Version: 4, Name: "4_bad", SQL: "INSERT INTO no_such_table VALUES (1)"}}); err == nil {
+		t.Error("expected failing migration to return an error")
+	}
+	db.Model(&SchemaMigration{}).Count(&applied)
+	if applied != 3 {
+		t.Errorf("failed migration was recorded: %d applied", applied)
+	}
+}
@@ -0,0 +1,11 @@
+-- Columns added after the first PostgreSQL release.
+-- Fresh databases already get them from AutoMigrate, so every statement must be idempotent.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS otp_secret TEXT DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS otp_verified BOOLEAN DEFAULT FALSE;
+
+ALTER TABLE decision_records ADD COLUMN IF NOT EXISTS ai_model TEXT DEFAULT '';
+
+ALTER TABLE traders ADD COLUMN IF NOT EXISTS paper_mode BOOLEAN DEFAULT FALSE;
+ALTER TABLE traders ADD COLUMN IF NOT EXISTS fallback_model_ids TEXT DEFAULT '';
+ALTER TABLE traders ADD COLUMN IF NOT EXISTS scan_interval_seconds INTEGER DEFAULT 0;
@@ -0,0 +1,5 @@
+-- API key permission verification results of exchange configs.
+
+ALTER TABLE exchanges ADD COLUMN IF NOT EXISTS key_verification_status TEXT DEFAULT '';
+ALTER TABLE exchanges ADD COLUMN IF NOT EXISTS key_verification_message TEXT DEFAULT '';
+ALTER TABLE exchanges ADD COLUMN IF NOT EXISTS key_verified_at TIMESTAMPTZ;
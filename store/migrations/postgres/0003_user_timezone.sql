@@ -0,0 +1,3 @@
+-- Timezone daily trading statistics of a user's traders are rolled up in.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone TEXT DEFAULT '';
@@ -0,0 +1,4 @@
+-- Contract type of a trader: linear (USDT-margined) or inverse (coin-margined, balances in margin_coin).
+
+ALTER TABLE traders ADD COLUMN IF NOT EXISTS contract_type TEXT DEFAULT 'linear';
+ALTER TABLE traders ADD COLUMN IF NOT EXISTS margin_coin TEXT DEFAULT '';
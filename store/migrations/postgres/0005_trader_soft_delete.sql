@@ -0,0 +1,4 @@
+-- Deleted traders go to the trash (deleted_at set) and are purged after the retention period.
+
+ALTER TABLE traders ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_traders_deleted_at ON traders (deleted_at);
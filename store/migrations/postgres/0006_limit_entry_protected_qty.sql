@@ -0,0 +1,3 @@
+-- Partially filled limit entries are protected before they complete.
+
+ALTER TABLE trader_limit_entries ADD COLUMN IF NOT EXISTS protected_qty DOUBLE PRECISION DEFAULT 0;
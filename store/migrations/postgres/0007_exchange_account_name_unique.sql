@@ -0,0 +1,11 @@
+-- Exchange account names are unique per user and exchange type.
+-- Accounts sharing the name of an older account get their ID prefix appended first.
+
+UPDATE exchanges e SET account_name = e.account_name || ' (' || LEFT(e.id, 8) || ')'
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id, exchange_type, account_name ORDER BY created_at, id) AS rn
+    FROM exchanges
+) d
+WHERE e.id = d.id AND d.rn > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exchanges_account ON exchanges (user_id, exchange_type, account_name);
@@ -0,0 +1,189 @@
+package store
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationEvents trader event types users can be emailed about
+var NotificationEvents = []string{TraderEventHalted, TraderEventCircuitBreaker, TraderEventLiquidationRisk, TraderEventCycleTimeout, TraderEventError, TraderEventApprovalPending, TraderEventUnprotected}
+
+// DefaultNotificationEvents critical event types emailed to users who have not chosen any
+var DefaultNotificationEvents = []string{TraderEventHalted, TraderEventCircuitBreaker, TraderEventLiquidationRisk, TraderEventUnprotected}
+
+var (
+	defaultEventsMu sync.RWMutex
+	defaultEvents   = DefaultNotificationEvents
+)
+
+// SetDefaultNotificationEvents changes the event types of users who have not chosen any, empty restores DefaultNotificationEvents
+func SetDefaultNotificationEvents(events []string) {
+	if len(events) == 0 {
+		events = DefaultNotificationEvents
+	}
+	defaultEventsMu.Lock()
+	defer defaultEventsMu.Unlock()
+	defaultEvents = append([]string(nil), events...)
+}
+
+// DefaultNotificationEventTypes event types of users who have not chosen any
+func DefaultNotificationEventTypes() []string {
+	defaultEventsMu.RLock()
+	defer defaultEventsMu.RUnlock()
+	return defaultEvents
+}
+
+// NotificationSettings a user's email notification channel
+// Emails are only sent once the address is verified; only the hash of a pending verification code is stored.
+type NotificationSettings struct {
+	UserID          string     `gorm:"column:user_id;primaryKey" json:"user_id"`
+	Email           string     `gorm:"column:email;default:''" json:"email"`
+	EmailVerified   bool       `gorm:"column:email_verified;default:false" json:"email_verified"`
+	VerifyCodeHash  string     `gorm:"column:verify_code_hash;default:''" json:"-"`
+	VerifyExpiresAt *time.Time `gorm:"column:verify_expires_at" json:"-"`
+	VerifyAttempts  int        `gorm:"column:verify_attempts;default:0" json:"-"`
+	Events          string     `gorm:"column:events;default:''" json:"-"`                     // Comma-separated trader event types, empty = DefaultNotificationEvents
+	DailyDigest     bool       `gorm:"column:daily_digest;default:false" json:"daily_digest"` // HTML summary of the previous day's performance
+	LastDigestDate  string     `gorm:"column:last_digest_date;default:''" json:"last_digest_date"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+// TableName returns the table name for NotificationSettings
+func (NotificationSettings) TableName() string {
+	return "user_notification_settings"
+}
+
+// EventTypes trader event types the user is notified of
+func (n *NotificationSettings) EventTypes() []string {
+	var types []string
+	for _, t := range strings.Split(n.Events, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	if len(types) == 0 {
+		return DefaultNotificationEventTypes()
+	}
+	return types
+}
+
+// Wants reports whether an event type should be emailed
+func (n *NotificationSettings) Wants(eventType string) bool {
+	if !n.EmailVerified || n.Email == "" {
+		return false
+	}
+	for _, t := range n.EventTypes() {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationStore user notification settings storage
+type NotificationStore struct {
+	db *gorm.DB
+}
+
+// NewNotificationStore creates a new NotificationStore
+func NewNotificationStore(db *gorm.DB) *NotificationStore {
+	return &NotificationStore{db: db}
+}
+
+func (s *NotificationStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'user_notification_settings'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&NotificationSettings{})
+}
+
+// Get returns a user's settings, empty (no email) when never configured
+func (s *NotificationStore) Get(userID string) (*NotificationSettings, error) {
+	var settings NotificationSettings
+	err := s.db.Where("user_id = ?", userID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &NotificationSettings{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// UpdatePreferences sets the event types and daily digest option
+func (s *NotificationStore) UpdatePreferences(userID string, eventTypes []string, dailyDigest bool) error {
+	return s.upsert(&NotificationSettings{UserID: userID, Events: strings.Join(eventTypes, ","), DailyDigest: dailyDigest},
+		"events", "daily_digest")
+}
+
+// MaxVerifyAttempts wrong codes after which a pending verification code is discarded
+const MaxVerifyAttempts = 5
+
+// SetPendingEmail sets a new, unverified address with the hash of the code sent to it
+func (s *NotificationStore) SetPendingEmail(userID, email, codeHash string, expiresAt time.Time) error {
+	return s.upsert(&NotificationSettings{UserID: userID, Email: email, VerifyCodeHash: codeHash, VerifyExpiresAt: &expiresAt},
+		"email", "email_verified", "verify_code_hash", "verify_expires_at", "verify_attempts")
+}
+
+// VerifyEmail marks the address verified if codeHash matches the pending, unexpired code
+// A wrong code counts as an attempt; the pending code is discarded after MaxVerifyAttempts.
+func (s *NotificationStore) VerifyEmail(userID, codeHash string, now time.Time) (bool, error) {
+	verified := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var settings NotificationSettings
+		if err := tx.Where("user_id = ?", userID).First(&settings).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if settings.VerifyCodeHash == "" || settings.VerifyExpiresAt == nil || !now.Before(*settings.VerifyExpiresAt) {
+			return nil
+		}
+
+		updates := map[string]interface{}{"verify_attempts": settings.VerifyAttempts + 1}
+		if settings.VerifyCodeHash == codeHash {
+			verified = true
+			updates = map[string]interface{}{"email_verified": true, "verify_attempts": 0}
+		}
+		if verified || settings.VerifyAttempts+1 >= MaxVerifyAttempts {
+			updates["verify_code_hash"] = ""
+			updates["verify_expires_at"] = nil
+		}
+		return tx.Model(&NotificationSettings{}).Where("user_id = ?", userID).Updates(updates).Error
+	})
+	return verified, err
+}
+
+// ListDigestRecipients settings of users with a verified address and the daily digest enabled
+func (s *NotificationStore) ListDigestRecipients() ([]*NotificationSettings, error) {
+	var settings []*NotificationSettings
+	err := s.db.Where("daily_digest = ? AND email_verified = ? AND email <> ''", true, true).Find(&settings).Error
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// MarkDigestSent records the local date of the last digest sent to a user
+func (s *NotificationStore) MarkDigestSent(userID, date string) error {
+	return s.db.Model(&NotificationSettings{}).Where("user_id = ?", userID).Update("last_digest_date", date).Error
+}
+
+// upsert creates a user's settings or updates the given columns
+func (s *NotificationStore) upsert(settings *NotificationSettings, columns ...string) error {
+	return s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns(append(columns, "updated_at")),
+	}).Create(settings).Error
+}
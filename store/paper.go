@@ -0,0 +1,281 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PaperPosition simulated position of a trader running in paper mode
+// All time fields use int64 millisecond timestamps (UTC)
+type PaperPosition struct {
+	ID          int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID    string  `gorm:"column:trader_id;not null;index:idx_paper_positions_trader" json:"trader_id"`
+	Symbol      string  `gorm:"column:symbol;not null" json:"symbol"`
+	Side        string  `gorm:"column:side;not null" json:"side"` // LONG/SHORT
+	Quantity    float64 `gorm:"column:quantity;not null" json:"quantity"`
+	EntryPrice  float64 `gorm:"column:entry_price;not null" json:"entry_price"`
+	Leverage    int     `gorm:"column:leverage;default:1" json:"leverage"`
+	StopLoss    float64 `gorm:"column:stop_loss;default:0" json:"stop_loss"`
+	TakeProfit  float64 `gorm:"column:take_profit;default:0" json:"take_profit"`
+	EntryTime   int64   `gorm:"column:entry_time;not null" json:"entry_time"` // Unix milliseconds UTC
+	ExitPrice   float64 `gorm:"column:exit_price;default:0" json:"exit_price"`
+	ExitTime    int64   `gorm:"column:exit_time;default:0" json:"exit_time"` // Unix milliseconds UTC, 0 while open
+	RealizedPnL float64 `gorm:"column:realized_pnl;default:0" json:"realized_pnl"`
+	Fee         float64 `gorm:"column:fee;default:0" json:"fee"` // Simulated entry + exit fees
+	Status      string  `gorm:"column:status;default:OPEN;index:idx_paper_positions_status" json:"status"`
+	CloseReason string  `gorm:"column:close_reason;default:''" json:"close_reason"`
+	CreatedAt   int64   `gorm:"column:created_at" json:"created_at"` // Unix milliseconds UTC
+	UpdatedAt   int64   `gorm:"column:updated_at" json:"updated_at"` // Unix milliseconds UTC
+}
+
+// TableName returns the table name
+func (PaperPosition) TableName() string {
+	return "paper_positions"
+}
+
+// Paper order status
+const (
+	PaperOrderNew      = "NEW"
+	PaperOrderFilled   = "FILLED"
+	PaperOrderCanceled = "CANCELED"
+)
+
+// PaperOrder simulated resting limit entry order of a trader in paper mode
+// It fills whole at its price once the mark price reaches it.
+type PaperOrder struct {
+	ID        int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID  string  `gorm:"column:trader_id;not null;index:idx_paper_orders_trader_status,priority:1" json:"trader_id"`
+	Symbol    string  `gorm:"column:symbol;not null" json:"symbol"`
+	Side      string  `gorm:"column:side;not null" json:"side"` // LONG/SHORT
+	Price     float64 `gorm:"column:price;not null" json:"price"`
+	Quantity  float64 `gorm:"column:quantity;not null" json:"quantity"`
+	Leverage  int     `gorm:"column:leverage;default:1" json:"leverage"`
+	Status    string  `gorm:"column:status;not null;default:NEW;index:idx_paper_orders_trader_status,priority:2" json:"status"`
+	CreatedAt int64   `gorm:"column:created_at" json:"created_at"` // Unix milliseconds UTC
+	UpdatedAt int64   `gorm:"column:updated_at" json:"updated_at"` // Unix milliseconds UTC
+}
+
+// TableName returns the table name
+func (PaperOrder) TableName() string {
+	return "paper_orders"
+}
+
+// PaperSummary aggregated paper trading result of a trader
+type PaperSummary struct {
+	OpenPositions int     `json:"open_positions"`
+	ClosedTrades  int     `json:"closed_trades"`
+	WinTrades     int     `json:"win_trades"`
+	RealizedPnL   float64 `json:"realized_pnl"`
+	TotalFee      float64 `json:"total_fee"`
+	OpenFee       float64 `json:"open_fee"` // Entry fees already paid on open positions
+}
+
+// PaperStore simulated position storage
+type PaperStore struct {
+	db *gorm.DB
+}
+
+// NewPaperStore creates a new PaperStore
+func NewPaperStore(db *gorm.DB) *PaperStore {
+	return &PaperStore{db: db}
+}
+
+func (s *PaperStore) initTables() error {
+	tables := []struct {
+		name  string
+		model interface{}
+	}{{"paper_positions", &PaperPosition{}}, {"paper_orders", &PaperOrder{}}}
+	for _, table := range tables {
+		// For PostgreSQL with existing table, skip AutoMigrate
+		if s.db.Dialector.Name() == "postgres" {
+			var tableExists int64
+			s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = ?`, table.name).Scan(&tableExists)
+			if tableExists > 0 {
+				continue
+			}
+		}
+		if err := s.db.AutoMigrate(table.model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Open records a new simulated position
+func (s *PaperStore) Open(pos *PaperPosition) error {
+	nowMs := time.Now().UTC().UnixMilli()
+	pos.Status = "OPEN"
+	if pos.EntryTime == 0 {
+		pos.EntryTime = nowMs
+	}
+	pos.CreatedAt = nowMs
+	pos.UpdatedAt = nowMs
+	return s.db.Omit("ID").Create(pos).Error
+}
+
+// Increase adds to an open simulated position, averaging the entry price
+func (s *PaperStore) Increase(id int64, addQty, addPrice, addFee float64) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var pos PaperPosition
+		if err := tx.First(&pos, id).Error; err != nil {
+			return fmt.Errorf("failed to get paper position: %w", err)
+		}
+		newQty := pos.Quantity + addQty
+		if newQty <= 0 {
+			return fmt.Errorf("invalid paper position quantity: %f", newQty)
+		}
+		return tx.Model(&PaperPosition{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"quantity":    newQty,
+			"entry_price": (pos.EntryPrice*pos.Quantity + addPrice*addQty) / newQty,
+			"fee":         pos.Fee + addFee,
+			"updated_at":  time.Now().UTC().UnixMilli(),
+		}).Error
+	})
+}
+
+// Close closes quantity of an open simulated position at exitPrice.
+// A partial close keeps the remainder open and records the closed part as its own row.
+// realizedPnL and fee cover the closed quantity only (fee includes its share of the entry fee).
+func (s *PaperStore) Close(id int64, quantity, exitPrice, realizedPnL, fee float64, reason string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var pos PaperPosition
+		if err := tx.Where("id = ? AND status = ?", id, "OPEN").First(&pos).Error; err != nil {
+			return fmt.Errorf("failed to get open paper position: %w", err)
+		}
+		nowMs := time.Now().UTC().UnixMilli()
+
+		if quantity <= 0 || quantity >= pos.Quantity {
+			return tx.Model(&PaperPosition{}).Where("id = ?", id).Updates(map[string]interface{}{
+				"exit_price":   exitPrice,
+				"exit_time":    nowMs,
+				"realized_pnl": realizedPnL,
+				"fee":          fee,
+				"status":       "CLOSED",
+				"close_reason": reason,
+				"updated_at":   nowMs,
+			}).Error
+		}
+
+		remainingFee := pos.Fee * (pos.Quantity - quantity) / pos.Quantity
+		if err := tx.Model(&PaperPosition{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"quantity":   pos.Quantity - quantity,
+			"fee":        remainingFee,
+			"updated_at": nowMs,
+		}).Error; err != nil {
+			return err
+		}
+
+		closed := pos
+		closed.ID = 0
+		closed.Quantity = quantity
+		closed.ExitPrice = exitPrice
+		closed.ExitTime = nowMs
+		closed.RealizedPnL = realizedPnL
+		closed.Fee = fee
+		closed.Status = "CLOSED"
+		closed.CloseReason = reason
+		closed.CreatedAt = nowMs
+		closed.UpdatedAt = nowMs
+		return tx.Omit("ID").Create(&closed).Error
+	})
+}
+
+// UpdateProtection sets stop loss and/or take profit of an open simulated position (nil leaves unchanged)
+func (s *PaperStore) UpdateProtection(id int64, stopLoss, takeProfit *float64) error {
+	updates := map[string]interface{}{"updated_at": time.Now().UTC().UnixMilli()}
+	if stopLoss != nil {
+		updates["stop_loss"] = *stopLoss
+	}
+	if takeProfit != nil {
+		updates["take_profit"] = *takeProfit
+	}
+	return s.db.Model(&PaperPosition{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// ListOpen gets trader's open simulated positions
+func (s *PaperStore) ListOpen(traderID string) ([]*PaperPosition, error) {
+	var positions []*PaperPosition
+	err := s.db.Where("trader_id = ? AND status = ?", traderID, "OPEN").
+		Order("entry_time ASC").
+		Find(&positions).Error
+	return positions, err
+}
+
+// List gets trader's simulated positions, newest first
+func (s *PaperStore) List(traderID string, limit int) ([]*PaperPosition, error) {
+	var positions []*PaperPosition
+	query := s.db.Where("trader_id = ?", traderID).Order("entry_time DESC, id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&positions).Error
+	return positions, err
+}
+
+// Summary aggregates trader's simulated trading result
+func (s *PaperStore) Summary(traderID string) (*PaperSummary, error) {
+	var positions []*PaperPosition
+	if err := s.db.Where("trader_id = ?", traderID).Find(&positions).Error; err != nil {
+		return nil, err
+	}
+	summary := &PaperSummary{}
+	for _, pos := range positions {
+		if pos.Status == "OPEN" {
+			summary.OpenPositions++
+			summary.OpenFee += pos.Fee
+			continue
+		}
+		summary.ClosedTrades++
+		if pos.RealizedPnL > 0 {
+			summary.WinTrades++
+		}
+		summary.RealizedPnL += pos.RealizedPnL
+		summary.TotalFee += pos.Fee
+	}
+	return summary, nil
+}
+
+// CreateOrder records a new resting simulated limit order
+func (s *PaperStore) CreateOrder(order *PaperOrder) error {
+	nowMs := time.Now().UTC().UnixMilli()
+	order.Status = PaperOrderNew
+	order.CreatedAt = nowMs
+	order.UpdatedAt = nowMs
+	return s.db.Omit("ID").Create(order).Error
+}
+
+// GetOrder gets a simulated limit order of a trader
+func (s *PaperStore) GetOrder(traderID string, id int64) (*PaperOrder, error) {
+	var order PaperOrder
+	if err := s.db.Where("id = ? AND trader_id = ?", id, traderID).First(&order).Error; err != nil {
+		return nil, fmt.Errorf("failed to get paper order: %w", err)
+	}
+	return &order, nil
+}
+
+// ListOpenOrders gets trader's resting simulated limit orders, oldest first
+func (s *PaperStore) ListOpenOrders(traderID string) ([]*PaperOrder, error) {
+	var orders []*PaperOrder
+	err := s.db.Where("trader_id = ? AND status = ?", traderID, PaperOrderNew).
+		Order("created_at ASC").
+		Find(&orders).Error
+	return orders, err
+}
+
+// FinishOrder moves a resting simulated order to a final status, false if it was no longer resting
+func (s *PaperStore) FinishOrder(id int64, status string) (bool, error) {
+	result := s.db.Model(&PaperOrder{}).
+		Where("id = ? AND status = ?", id, PaperOrderNew).
+		Updates(map[string]interface{}{"status": status, "updated_at": time.Now().UTC().UnixMilli()})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Reset deletes all simulated positions and orders of a trader
+func (s *PaperStore) Reset(traderID string) error {
+	if err := s.db.Where("trader_id = ?", traderID).Delete(&PaperOrder{}).Error; err != nil {
+		return err
+	}
+	return s.db.Where("trader_id = ?", traderID).Delete(&PaperPosition{}).Error
+}
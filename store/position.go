@@ -382,6 +382,43 @@ func (s *PositionStore) GetClosedPositions(traderID string, limit int) ([]*Trade
 	return positions, nil
 }
 
+// GetClosedPositionsInRange gets a trader's positions closed in [fromMs, toMs], oldest first
+func (s *PositionStore) GetClosedPositionsInRange(traderID string, fromMs, toMs int64) ([]*TraderPosition, error) {
+	var positions []*TraderPosition
+	err := s.db.Where("trader_id = ? AND status = ? AND exit_time >= ? AND exit_time <= ?", traderID, "CLOSED", fromMs, toMs).
+		Order("exit_time ASC").
+		Find(&positions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query closed positions: %w", err)
+	}
+
+	for _, pos := range positions {
+		if pos.EntryQuantity == 0 {
+			pos.EntryQuantity = pos.Quantity
+		}
+	}
+	return positions, nil
+}
+
+// GetSymbolPositionsInRange gets a trader's positions in a symbol that were open at some point in [fromMs, toMs]
+// Sorted by entry time.
+func (s *PositionStore) GetSymbolPositionsInRange(traderID, symbol string, fromMs, toMs int64) ([]*TraderPosition, error) {
+	var positions []*TraderPosition
+	err := s.db.Where("trader_id = ? AND symbol = ? AND entry_time <= ? AND (exit_time = 0 OR exit_time IS NULL OR exit_time >= ?)", traderID, symbol, toMs, fromMs).
+		Order("entry_time ASC").
+		Find(&positions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query positions: %w", err)
+	}
+
+	for _, pos := range positions {
+		if pos.EntryQuantity == 0 {
+			pos.EntryQuantity = pos.Quantity
+		}
+	}
+	return positions, nil
+}
+
 // GetAllOpenPositions gets all traders' open positions
 func (s *PositionStore) GetAllOpenPositions() ([]*TraderPosition, error) {
 	var positions []*TraderPosition
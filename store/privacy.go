@@ -0,0 +1,76 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PrivacySettings per-user visibility of traders on public endpoints (leaderboard, top traders, equity history).
+// A trader is public only if its owner opted in and the trader itself is shown in competition.
+type PrivacySettings struct {
+	UserID           string    `gorm:"primaryKey" json:"user_id"`
+	LeaderboardOptIn bool      `gorm:"column:leaderboard_opt_in;not null;default:false" json:"leaderboard_opt_in"`
+	AnonymizeNames   bool      `gorm:"column:anonymize_names;not null;default:false" json:"anonymize_names"` // Replace trader names with a stable alias publicly
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func (PrivacySettings) TableName() string { return "user_privacy_settings" }
+
+// DisplayName returns the public name of a trader owned by this user
+func (p *PrivacySettings) DisplayName(traderID, name string) string {
+	if p == nil || !p.AnonymizeNames {
+		return name
+	}
+	return AnonymousTraderName(traderID)
+}
+
+// AnonymousTraderName stable alias of a trader that doesn't reveal its name or ID
+func AnonymousTraderName(traderID string) string {
+	sum := sha256.Sum256([]byte("trader:" + traderID))
+	return "Trader " + hex.EncodeToString(sum[:])[:6]
+}
+
+// PrivacyStore user privacy settings storage
+type PrivacyStore struct {
+	db *gorm.DB
+}
+
+// NewPrivacyStore creates a new PrivacyStore
+func NewPrivacyStore(db *gorm.DB) *PrivacyStore {
+	return &PrivacyStore{db: db}
+}
+
+func (s *PrivacyStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'user_privacy_settings'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&PrivacySettings{})
+}
+
+// Get gets user's privacy settings, defaults (not opted in) if never saved
+func (s *PrivacyStore) Get(userID string) (*PrivacySettings, error) {
+	var settings PrivacySettings
+	err := s.db.Where("user_id = ?", userID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &PrivacySettings{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get privacy settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// Save upserts user's privacy settings
+func (s *PrivacyStore) Save(settings *PrivacySettings) error {
+	return s.db.Save(settings).Error
+}
@@ -0,0 +1,226 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BuiltinPromptTemplateDefault built-in template: the strategy's own prompt with nothing added
+const BuiltinPromptTemplateDefault = "default"
+
+// maxPromptTemplateLength longest template content accepted
+const maxPromptTemplateLength = 20000
+
+// ErrPromptTemplateInUse the template is still selected by a strategy or trader
+var ErrPromptTemplateInUse = errors.New("prompt template is used by a strategy or trader")
+
+// PromptTemplate a user's named prompt template, added to the system prompt of the strategies and traders
+// that select it. Every content change creates a new version, older versions stay available for pinning.
+type PromptTemplate struct {
+	ID          string    `gorm:"primaryKey" json:"id"`
+	UserID      string    `gorm:"column:user_id;not null;index" json:"-"`
+	Name        string    `gorm:"column:name;not null" json:"name"`
+	Description string    `gorm:"column:description;default:''" json:"description"`
+	Content     string    `gorm:"column:content;type:text;not null" json:"content"` // Content of the latest version
+	Version     int       `gorm:"column:version;not null;default:1" json:"version"`
+	Builtin     bool      `gorm:"-" json:"builtin"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (PromptTemplate) TableName() string { return "prompt_templates" }
+
+// PromptTemplateVersion content of one version of a prompt template
+type PromptTemplateVersion struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement" json:"-"`
+	TemplateID string    `gorm:"column:template_id;not null;index" json:"template_id"`
+	Version    int       `gorm:"column:version;not null" json:"version"`
+	Content    string    `gorm:"column:content;type:text;not null" json:"content"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (PromptTemplateVersion) TableName() string { return "prompt_template_versions" }
+
+// BuiltinPromptTemplates templates every user can select, read-only
+var BuiltinPromptTemplates = []*PromptTemplate{
+	{ID: BuiltinPromptTemplateDefault, Name: "Default", Description: "The strategy's prompt without additions", Version: 1, Builtin: true},
+}
+
+// Validate checks the name and content of a template
+func (t *PromptTemplate) Validate() error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(t.Content) == "" {
+		return fmt.Errorf("content is required")
+	}
+	if len(t.Content) > maxPromptTemplateLength {
+		return fmt.Errorf("content is limited to %d characters", maxPromptTemplateLength)
+	}
+	return nil
+}
+
+// PromptTemplateStore user prompt template storage
+type PromptTemplateStore struct {
+	db *gorm.DB
+}
+
+// NewPromptTemplateStore creates a new PromptTemplateStore
+func NewPromptTemplateStore(db *gorm.DB) *PromptTemplateStore {
+	return &PromptTemplateStore{db: db}
+}
+
+func (s *PromptTemplateStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'prompt_templates'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&PromptTemplate{}, &PromptTemplateVersion{})
+}
+
+// List returns the built-in templates followed by the user's own, by name
+func (s *PromptTemplateStore) List(userID string) ([]*PromptTemplate, error) {
+	var templates []*PromptTemplate
+	if err := s.db.Where("user_id = ?", userID).Order("name ASC").Find(&templates).Error; err != nil {
+		return nil, fmt.Errorf("failed to list prompt templates: %w", err)
+	}
+	return append(append([]*PromptTemplate(nil), BuiltinPromptTemplates...), templates...), nil
+}
+
+// Get gets a built-in template or one of the user's, gorm.ErrRecordNotFound when there is none
+func (s *PromptTemplateStore) Get(userID, id string) (*PromptTemplate, error) {
+	for _, t := range BuiltinPromptTemplates {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	var t PromptTemplate
+	if err := s.db.Where("user_id = ? AND id = ?", userID, id).First(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Create stores a new template as version 1
+func (s *PromptTemplateStore) Create(t *PromptTemplate) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	t.Version = 1
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(t).Error; err != nil {
+			return fmt.Errorf("failed to create prompt template: %w", err)
+		}
+		return tx.Create(&PromptTemplateVersion{TemplateID: t.ID, Version: 1, Content: t.Content}).Error
+	})
+}
+
+// Update saves the name, description and content of a user's template, a changed content becomes a new version
+func (s *PromptTemplateStore) Update(t *PromptTemplate) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var existing PromptTemplate
+		if err := tx.Where("user_id = ? AND id = ?", t.UserID, t.ID).First(&existing).Error; err != nil {
+			return err
+		}
+		t.Version = existing.Version
+		if t.Content != existing.Content {
+			t.Version++
+			if err := tx.Create(&PromptTemplateVersion{TemplateID: t.ID, Version: t.Version, Content: t.Content}).Error; err != nil {
+				return fmt.Errorf("failed to save prompt template version: %w", err)
+			}
+		}
+		return tx.Model(&PromptTemplate{}).Where("user_id = ? AND id = ?", t.UserID, t.ID).Updates(map[string]interface{}{
+			"name":        t.Name,
+			"description": t.Description,
+			"content":     t.Content,
+			"version":     t.Version,
+			"updated_at":  time.Now(),
+		}).Error
+	})
+}
+
+// Delete deletes a user's template and its versions, ErrPromptTemplateInUse while a strategy or trader selects it
+func (s *PromptTemplateStore) Delete(userID, id string) error {
+	var inUse int64
+	s.db.Model(&Strategy{}).Where("user_id = ? AND config LIKE ?", userID, `%"prompt_template_id":"`+id+`"%`).Count(&inUse)
+	if inUse == 0 {
+		s.db.Model(&Trader{}).Where("user_id = ? AND system_prompt_template = ?", userID, id).Count(&inUse)
+	}
+	if inUse > 0 {
+		return ErrPromptTemplateInUse
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND id = ?", userID, id).Delete(&PromptTemplate{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("template_id = ?", id).Delete(&PromptTemplateVersion{}).Error
+	})
+}
+
+// Versions returns all versions of a user's template, newest first
+func (s *PromptTemplateStore) Versions(userID, id string) ([]*PromptTemplateVersion, error) {
+	if _, err := s.Get(userID, id); err != nil {
+		return nil, err
+	}
+	var versions []*PromptTemplateVersion
+	if err := s.db.Where("template_id = ?", id).Order("version DESC").Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list prompt template versions: %w", err)
+	}
+	return versions, nil
+}
+
+// Content returns the content of a template version (0 = latest), empty for built-in templates
+func (s *PromptTemplateStore) Content(userID, id string, version int) (string, error) {
+	t, err := s.Get(userID, id)
+	if err != nil {
+		return "", err
+	}
+	if t.Builtin || version <= 0 || version == t.Version {
+		return t.Content, nil
+	}
+	var v PromptTemplateVersion
+	if err := s.db.Where("template_id = ? AND version = ?", id, version).First(&v).Error; err != nil {
+		return "", fmt.Errorf("version %d of prompt template %s: %w", version, t.Name, err)
+	}
+	return v.Content, nil
+}
+
+// ResolvePromptTemplate loads the content of the template a strategy selects into cfg.TemplatePrompt
+// templateOverride (a trader's system_prompt_template) takes precedence when it names one of the user's templates.
+func (s *Store) ResolvePromptTemplate(userID string, cfg *StrategyConfig, templateOverride string) error {
+	id, version := cfg.PromptTemplateID, cfg.PromptTemplateVersion
+	if templateOverride != "" && templateOverride != BuiltinPromptTemplateDefault {
+		if _, err := s.PromptTemplate().Get(userID, templateOverride); err == nil {
+			id, version = templateOverride, 0
+		}
+	}
+	if id == "" {
+		return nil
+	}
+	content, err := s.PromptTemplate().Content(userID, id, version)
+	if err != nil {
+		return err
+	}
+	cfg.TemplatePrompt = content
+	return nil
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestPromptTemplateVersionsAndResolve(t *testing.T) {
+	st, err := New(filepath.Join(t.TempDir(), "prompts.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	prompts := st.PromptTemplate()
+
+	tpl := &PromptTemplate{UserID: "u1", Name: " Trend ", Content: "Follow the trend."}
+	if err := prompts.Create(tpl); err != nil || tpl.Version != 1 || tpl.Name != "Trend" {
+		t.Fatalf("create: %v %+v", err, tpl)
+	}
+	tpl.Content = "Follow the 4h trend."
+	if err := prompts.Update(tpl); err != nil || tpl.Version != 2 {
+		t.Fatalf("update: %v version %d", err, tpl.Version)
+	}
+	// Only a content change creates a version
+	tpl.Description = "trend following"
+	if err := prompts.Update(tpl); err != nil || tpl.Version != 2 {
+		t.Fatalf("update description: %v version %d", err, tpl.Version)
+	}
+	if versions, _ := prompts.Versions("u1", tpl.ID); len(versions) != 2 || versions[0].Version != 2 {
+		t.Errorf("versions = %+v", versions)
+	}
+	if list, _ := prompts.List("u1"); len(list) != 2 || !list[0].Builtin || list[1].ID != tpl.ID {
+		t.Errorf("list = %+v", list)
+	}
+	if _, err := prompts.Get("u2", tpl.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("other user's template: %v", err)
+	}
+
+	// A pinned version keeps its content, a trader's template overrides the strategy's
+	cfg := &StrategyConfig{PromptTemplateID: tpl.ID, PromptTemplateVersion: 1}
+	if err := st.ResolvePromptTemplate("u1", cfg, ""); err != nil || cfg.TemplatePrompt != "Follow the trend." {
+		t.Errorf("pinned: %v %q", err, cfg.TemplatePrompt)
+	}
+	if err := st.ResolvePromptTemplate("u1", cfg, tpl.ID); err != nil || cfg.TemplatePrompt != "Follow the 4h trend." {
+		t.Errorf("override: %v %q", err, cfg.TemplatePrompt)
+	}
+	if err := st.ResolvePromptTemplate("u1", &StrategyConfig{PromptTemplateID: tpl.ID, PromptTemplateVersion: 9}, ""); err == nil {
+		t.Error("unknown version should fail")
+	}
+
+	// Deleting is refused while a strategy selects the template
+	config, _ := json.Marshal(StrategyConfig{PromptTemplateID: tpl.ID})
+	if err := st.Strategy().Create(&Strategy{ID: "s1", UserID: "u1", Name: "s", Config: string(config)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := prompts.Delete("u1", tpl.ID); !errors.Is(err, ErrPromptTemplateInUse) {
+		t.Errorf("delete in use: %v", err)
+	}
+	if err := st.Strategy().Delete("u1", "s1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := prompts.Delete("u1", tpl.ID); err != nil {
+		t.Errorf("delete: %v", err)
+	}
+	if err := prompts.Delete("u1", tpl.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("delete twice: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Protection kinds
+const (
+	ProtectionOCO    = "oco"    // Stop loss/take profit placed separately, linked by the watcher
+	ProtectionLadder = "ladder" // Partial take profits and the trailing stop of the rest
+)
+
+// TraderProtection emulated protection of an open position followed by the protection watcher
+// Kept so a restarted trader goes on cancelling sibling orders, closing emulated legs and trailing stops.
+type TraderProtection struct {
+	ID        int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID  string `gorm:"column:trader_id;not null;uniqueIndex:idx_trader_protections_key,priority:1" json:"trader_id"`
+	Kind      string `gorm:"column:kind;not null;uniqueIndex:idx_trader_protections_key,priority:2" json:"kind"`
+	Symbol    string `gorm:"column:symbol;not null;uniqueIndex:idx_trader_protections_key,priority:3" json:"symbol"`
+	Side      string `gorm:"column:side;not null;uniqueIndex:idx_trader_protections_key,priority:4" json:"side"` // LONG/SHORT
+	State     string `gorm:"column:state;type:text;not null;default:''" json:"state"`                            // JSON object
+	UpdatedAt int64  `gorm:"column:updated_at" json:"updated_at"`                                                // Unix milliseconds UTC
+}
+
+// TableName returns the table name for TraderProtection
+func (TraderProtection) TableName() string {
+	return "trader_protections"
+}
+
+// Decode unmarshals the saved state into v
+func (p *TraderProtection) Decode(v interface{}) error {
+	if err := json.Unmarshal([]byte(p.State), v); err != nil {
+		return fmt.Errorf("failed to decode %s protection of %s %s: %w", p.Kind, p.Symbol, p.Side, err)
+	}
+	return nil
+}
+
+// ProtectionStore emulated protection storage
+type ProtectionStore struct {
+	db *gorm.DB
+}
+
+// NewProtectionStore creates a new ProtectionStore
+func NewProtectionStore(db *gorm.DB) *ProtectionStore {
+	return &ProtectionStore{db: db}
+}
+
+func (s *ProtectionStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'trader_protections'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&TraderProtection{})
+}
+
+// Save stores the state of a position's protection as JSON, replacing the previous one
+func (s *ProtectionStore) Save(traderID, kind, symbol, side string, state interface{}) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s protection: %w", kind, err)
+	}
+	p := &TraderProtection{TraderID: traderID, Kind: kind, Symbol: symbol, Side: side, State: string(data), UpdatedAt: time.Now().UTC().UnixMilli()}
+	// Omit ID to let PostgreSQL sequence auto-generate it
+	err = s.db.Omit("ID").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "trader_id"}, {Name: "kind"}, {Name: "symbol"}, {Name: "side"}},
+		DoUpdates: clause.AssignmentColumns([]string{"state", "updated_at"}),
+	}).Create(p).Error
+	if err != nil {
+		return fmt.Errorf("failed to save %s protection: %w", kind, err)
+	}
+	return nil
+}
+
+// List gets trader's saved protections of a kind
+func (s *ProtectionStore) List(traderID, kind string) ([]*TraderProtection, error) {
+	var protections []*TraderProtection
+	err := s.db.Where("trader_id = ? AND kind = ?", traderID, kind).
+		Order("id ASC").
+		Find(&protections).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s protections: %w", kind, err)
+	}
+	return protections, nil
+}
+
+// Delete removes the protection of a position once it is no longer followed
+func (s *ProtectionStore) Delete(traderID, kind, symbol, side string) error {
+	err := s.db.Where("trader_id = ? AND kind = ? AND symbol = ? AND side = ?", traderID, kind, symbol, side).
+		Delete(&TraderProtection{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete %s protection: %w", kind, err)
+	}
+	return nil
+}
+
+// DeleteTrader removes all protections of a trader, e.g. once it moved to another exchange
+func (s *ProtectionStore) DeleteTrader(traderID string) error {
+	if err := s.db.Where("trader_id = ?", traderID).Delete(&TraderProtection{}).Error; err != nil {
+		return fmt.Errorf("failed to delete protections: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const vapidKeyConfig = "vapid_private_key"
+
+// PushSubscription a browser's Web Push subscription (one per browser and user)
+type PushSubscription struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    string    `gorm:"column:user_id;not null;index" json:"-"`
+	Endpoint  string    `gorm:"column:endpoint;type:text;not null;uniqueIndex" json:"endpoint"` // Push service URL
+	P256dh    string    `gorm:"column:p256dh;not null" json:"-"`                                // Browser public key, base64url
+	Auth      string    `gorm:"column:auth;not null" json:"-"`                                  // Authentication secret, base64url
+	UserAgent string    `gorm:"column:user_agent;default:''" json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (PushSubscription) TableName() string { return "push_subscriptions" }
+
+// PushSubscriptionStore Web Push subscription storage
+type PushSubscriptionStore struct {
+	db *gorm.DB
+}
+
+// NewPushSubscriptionStore creates a new PushSubscriptionStore
+func NewPushSubscriptionStore(db *gorm.DB) *PushSubscriptionStore {
+	return &PushSubscriptionStore{db: db}
+}
+
+func (s *PushSubscriptionStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'push_subscriptions'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&PushSubscription{})
+}
+
+// Save stores a subscription, moving an endpoint already subscribed to the given user
+func (s *PushSubscriptionStore) Save(sub *PushSubscription) error {
+	return s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "endpoint"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "p256dh", "auth", "user_agent", "updated_at"}),
+	}).Create(sub).Error
+}
+
+// List returns a user's subscriptions
+func (s *PushSubscriptionStore) List(userID string) ([]*PushSubscription, error) {
+	var subs []*PushSubscription
+	err := s.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&subs).Error
+	if err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// Delete removes a user's subscription by endpoint, gorm.ErrRecordNotFound when there is none
+func (s *PushSubscriptionStore) Delete(userID, endpoint string) error {
+	result := s.db.Where("user_id = ? AND endpoint = ?", userID, endpoint).Delete(&PushSubscription{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteByEndpoint removes a subscription the push service reported as expired
+func (s *PushSubscriptionStore) DeleteByEndpoint(endpoint string) error {
+	return s.db.Where("endpoint = ?", endpoint).Delete(&PushSubscription{}).Error
+}
+
+// VAPIDKey gets the installation's Web Push (VAPID) signing key, generating it on first use
+// Browsers bind subscriptions to the public key, so changing it invalidates every subscription.
+// The key is stored encrypted with the data key; one saved in plaintext by older versions is re-encrypted by key rotation.
+func (s *Store) VAPIDKey() (*ecdsa.PrivateKey, error) {
+	value, err := s.getSecretSystemConfig(vapidKeyConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load VAPID key: %w", err)
+	}
+	if value != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil {
+			return nil, errors.New("corrupted VAPID key")
+		}
+		return ecdsa.ParseRawPrivateKey(elliptic.P256(), raw)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate VAPID key: %w", err)
+	}
+	raw, err := key.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setSecretSystemConfig(vapidKeyConfig, base64.RawURLEncoding.EncodeToString(raw)); err != nil {
+		return nil, fmt.Errorf("failed to save VAPID key: %w", err)
+	}
+	return key, nil
+}
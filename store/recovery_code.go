@@ -0,0 +1,88 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecoveryCode one-time code that replaces the authenticator when it is lost
+// Only the SHA-256 hash of the code is stored.
+type RecoveryCode struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    string     `gorm:"column:user_id;not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"column:code_hash;not null" json:"-"`
+	UsedAt    *time.Time `gorm:"column:used_at" json:"used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+// TableName returns the table name for RecoveryCode
+func (RecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
+
+// RecoveryCodeStore OTP recovery code storage
+type RecoveryCodeStore struct {
+	db *gorm.DB
+}
+
+// NewRecoveryCodeStore creates a new RecoveryCodeStore
+func NewRecoveryCodeStore(db *gorm.DB) *RecoveryCodeStore {
+	return &RecoveryCodeStore{db: db}
+}
+
+func (s *RecoveryCodeStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'user_recovery_codes'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&RecoveryCode{})
+}
+
+// Replace discards a user's previous codes and stores the new code hashes
+func (s *RecoveryCodeStore) Replace(userID string, codeHashes []string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+			return fmt.Errorf("failed to delete recovery codes: %w", err)
+		}
+		codes := make([]RecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, RecoveryCode{UserID: userID, CodeHash: hash})
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		if err := tx.Create(&codes).Error; err != nil {
+			return fmt.Errorf("failed to create recovery codes: %w", err)
+		}
+		return nil
+	})
+}
+
+// Consume marks an unused code as used, returns false if the user has no such unused code
+func (s *RecoveryCodeStore) Consume(userID, codeHash string) (bool, error) {
+	result := s.db.Model(&RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to consume recovery code: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CountUnused returns the number of codes the user can still use
+func (s *RecoveryCodeStore) CountUnused(userID string) (int, error) {
+	var count int64
+	err := s.db.Model(&RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return int(count), err
+}
+
+// DeleteByUser removes all codes of a user
+func (s *RecoveryCodeStore) DeleteByUser(userID string) error {
+	return s.db.Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error
+}
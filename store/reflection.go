@@ -0,0 +1,104 @@
+package store
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// Post-trade reflection limits
+const (
+	// MaxReflectionChars longest reflection stored, longer AI answers are cut
+	MaxReflectionChars = 1000
+	// DefaultReflectionPromptEntries reflections included in decision prompts when not configured
+	DefaultReflectionPromptEntries = 5
+	// reflectionsKeptPerTrader older reflections of a trader are deleted
+	reflectionsKeptPerTrader = 100
+)
+
+// ReflectionStore post-trade reflection storage
+type ReflectionStore struct {
+	db *gorm.DB
+}
+
+// TradeReflection the AI's review of a closed position, fed back into later decision prompts
+type TradeReflection struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID   string    `gorm:"column:trader_id;not null;index:idx_trade_reflections_trader_time" json:"trader_id"`
+	CreatedAt  time.Time `gorm:"not null;index:idx_trade_reflections_trader_time,sort:desc" json:"created_at"`
+	Symbol     string    `gorm:"column:symbol;not null" json:"symbol"`
+	Side       string    `gorm:"column:side;not null" json:"side"` // long/short
+	EntryPrice float64   `gorm:"column:entry_price" json:"entry_price"`
+	ExitPrice  float64   `gorm:"column:exit_price" json:"exit_price"`
+	PnLPct     float64   `gorm:"column:pnl_pct" json:"pnl_pct"`
+	Reason     string    `gorm:"column:reason;not null;default:''" json:"reason"` // Close reason: ai, drawdown, stop_loss, take_profit, exchange
+	Reflection string    `gorm:"column:reflection;type:text;not null" json:"reflection"`
+	AIModel    string    `gorm:"column:ai_model;not null;default:''" json:"ai_model"`
+}
+
+func (TradeReflection) TableName() string { return "trade_reflections" }
+
+// NewReflectionStore creates a new ReflectionStore
+func NewReflectionStore(db *gorm.DB) *ReflectionStore {
+	return &ReflectionStore{db: db}
+}
+
+func (s *ReflectionStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'trade_reflections'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&TradeReflection{})
+}
+
+// Create stores a reflection (cut to MaxReflectionChars) and deletes the trader's oldest beyond the retention limit
+func (s *ReflectionStore) Create(r *TradeReflection) error {
+	r.Reflection = TruncateReflection(r.Reflection, MaxReflectionChars)
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now().UTC()
+	}
+	// Omit ID to let PostgreSQL sequence auto-generate it
+	if err := s.db.Omit("ID").Create(r).Error; err != nil {
+		return fmt.Errorf("failed to create trade reflection: %w", err)
+	}
+
+	var cutoff []int64
+	if err := s.db.Model(&TradeReflection{}).Where("trader_id = ?", r.TraderID).
+		Order("created_at DESC, id DESC").Offset(reflectionsKeptPerTrader).Limit(1).Pluck("id", &cutoff).Error; err != nil {
+		return fmt.Errorf("failed to prune trade reflections: %w", err)
+	}
+	if len(cutoff) > 0 {
+		if err := s.db.Where("trader_id = ? AND id <= ?", r.TraderID, cutoff[0]).Delete(&TradeReflection{}).Error; err != nil {
+			return fmt.Errorf("failed to prune trade reflections: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListRecent returns a trader's latest n reflections, newest first
+func (s *ReflectionStore) ListRecent(traderID string, n int) ([]*TradeReflection, error) {
+	if n <= 0 || n > reflectionsKeptPerTrader {
+		n = reflectionsKeptPerTrader
+	}
+	var reflections []*TradeReflection
+	err := s.db.Where("trader_id = ?", traderID).Order("created_at DESC, id DESC").Limit(n).Find(&reflections).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trade reflections: %w", err)
+	}
+	return reflections, nil
+}
+
+// TruncateReflection cuts text to at most maxChars characters, marking the cut with an ellipsis
+func TruncateReflection(text string, maxChars int) string {
+	if maxChars <= 0 || utf8.RuneCountInString(text) <= maxChars {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:maxChars-1]) + "…"
+}
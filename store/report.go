@@ -0,0 +1,177 @@
+package store
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Report periods
+const (
+	ReportPeriodWeekly  = "weekly"  // Monday to Sunday
+	ReportPeriodMonthly = "monthly" // Calendar month
+)
+
+// PerformanceReport a generated performance report, of one trader or of all of a user's traders
+// Dates are local days in Timezone; regenerating a report for the same period replaces it.
+type PerformanceReport struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    string     `gorm:"column:user_id;not null;uniqueIndex:idx_reports_period,priority:1" json:"user_id"`
+	TraderID  string     `gorm:"column:trader_id;not null;default:'';uniqueIndex:idx_reports_period,priority:2" json:"trader_id"` // Empty for a report of all traders
+	Period    string     `gorm:"column:period;not null;uniqueIndex:idx_reports_period,priority:3" json:"period"`
+	StartDate string     `gorm:"column:start_date;not null;uniqueIndex:idx_reports_period,priority:4" json:"start_date"` // YYYY-MM-DD, first day
+	EndDate   string     `gorm:"column:end_date;not null" json:"end_date"`                                               // YYYY-MM-DD, last day
+	Timezone  string     `gorm:"column:timezone;not null;default:'UTC'" json:"timezone"`
+	NetPnL    float64    `gorm:"column:net_pnl;default:0" json:"net_pnl"` // Headline figures for report lists
+	ReturnPct float64    `gorm:"column:return_pct;default:0" json:"return_pct"`
+	Trades    int        `gorm:"column:trades;default:0" json:"trades"`
+	HTML      string     `gorm:"column:html;type:text" json:"-"`
+	PDF       []byte     `gorm:"column:pdf" json:"-"`
+	EmailedAt *time.Time `gorm:"column:emailed_at" json:"emailed_at,omitempty"`
+	CreatedAt time.Time  `gorm:"column:created_at" json:"created_at"`
+}
+
+func (PerformanceReport) TableName() string { return "performance_reports" }
+
+// ReportSettings a user's scheduled performance reports
+type ReportSettings struct {
+	UserID    string    `gorm:"column:user_id;primaryKey" json:"user_id"`
+	Weekly    bool      `gorm:"column:weekly;default:false" json:"weekly"`
+	Monthly   bool      `gorm:"column:monthly;default:false" json:"monthly"`
+	Email     bool      `gorm:"column:email;default:false" json:"email"` // Also email them to the verified notification address
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (ReportSettings) TableName() string { return "user_report_settings" }
+
+// Periods the report periods the user has scheduled
+func (r *ReportSettings) Periods() []string {
+	var periods []string
+	if r.Weekly {
+		periods = append(periods, ReportPeriodWeekly)
+	}
+	if r.Monthly {
+		periods = append(periods, ReportPeriodMonthly)
+	}
+	return periods
+}
+
+// ReportStore performance report storage
+type ReportStore struct {
+	db *gorm.DB
+}
+
+// NewReportStore creates a new ReportStore
+func NewReportStore(db *gorm.DB) *ReportStore {
+	return &ReportStore{db: db}
+}
+
+func (s *ReportStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'performance_reports'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&PerformanceReport{}, &ReportSettings{})
+}
+
+// GetSettings returns a user's report settings, nothing scheduled when never configured
+func (s *ReportStore) GetSettings(userID string) (*ReportSettings, error) {
+	var settings ReportSettings
+	err := s.db.Where("user_id = ?", userID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &ReportSettings{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// UpdateSettings sets a user's scheduled reports
+func (s *ReportStore) UpdateSettings(settings *ReportSettings) error {
+	return s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"weekly", "monthly", "email", "updated_at"}),
+	}).Create(settings).Error
+}
+
+// ListScheduled settings of users with at least one report scheduled
+func (s *ReportStore) ListScheduled() ([]*ReportSettings, error) {
+	var settings []*ReportSettings
+	err := s.db.Where("weekly = ? OR monthly = ?", true, true).Find(&settings).Error
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// Save stores a report, replacing an earlier one of the same user, trader, period and start date
+// The report's ID is set to the stored row's.
+func (s *ReportStore) Save(report *PerformanceReport) error {
+	err := s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "trader_id"}, {Name: "period"}, {Name: "start_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"end_date", "timezone", "net_pnl", "return_pct", "trades",
+			"html", "pdf", "emailed_at", "created_at"}),
+	}).Omit("ID").Create(report).Error
+	if err != nil {
+		return err
+	}
+	return s.db.Model(&PerformanceReport{}).Select("id").
+		Where("user_id = ? AND trader_id = ? AND period = ? AND start_date = ?", report.UserID, report.TraderID, report.Period, report.StartDate).
+		Scan(&report.ID).Error
+}
+
+// Exists reports whether a report of the period starting on startDate has been generated
+func (s *ReportStore) Exists(userID, traderID, period, startDate string) (bool, error) {
+	var count int64
+	err := s.db.Model(&PerformanceReport{}).
+		Where("user_id = ? AND trader_id = ? AND period = ? AND start_date = ?", userID, traderID, period, startDate).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// List gets a user's reports without their content, newest first
+func (s *ReportStore) List(userID string, limit int) ([]*PerformanceReport, error) {
+	var reports []*PerformanceReport
+	err := s.db.Omit("html", "pdf").
+		Where("user_id = ?", userID).
+		Order("start_date DESC, id DESC").
+		Limit(limit).
+		Find(&reports).Error
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// Get gets one of a user's reports with its content
+func (s *ReportStore) Get(userID string, id int64) (*PerformanceReport, error) {
+	var report PerformanceReport
+	if err := s.db.Where("user_id = ? AND id = ?", userID, id).First(&report).Error; err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Delete deletes one of a user's reports
+func (s *ReportStore) Delete(userID string, id int64) error {
+	result := s.db.Where("user_id = ? AND id = ?", userID, id).Delete(&PerformanceReport{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// MarkEmailed records when a report was emailed
+func (s *ReportStore) MarkEmailed(id int64, at time.Time) error {
+	return s.db.Model(&PerformanceReport{}).Where("id = ?", id).Update("emailed_at", at).Error
+}
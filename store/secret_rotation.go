@@ -0,0 +1,145 @@
+package store
+
+import (
+	"fmt"
+	"nofx/crypto"
+	"slices"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// encryptedColumns lists every column stored as crypto.EncryptedString
+var encryptedColumns = map[string][]string{
+	"exchanges": {
+		"api_key",
+		"secret_key",
+		"passphrase",
+		"aster_private_key",
+		"lighter_private_key",
+		"lighter_api_key_private_key",
+	},
+	"ai_models": {
+		"api_key",
+	},
+	"user_webhooks": {
+		"secret",
+	},
+}
+
+// encryptedSystemConfigKeys system_config keys whose value is encrypted (ref ID = key)
+var encryptedSystemConfigKeys = []string{
+	vapidKeyConfig,
+	strategyBundleKeyConfig,
+}
+
+// integerIDTables tables of encryptedColumns with an integer primary key
+var integerIDTables = map[string]bool{
+	"user_webhooks": true,
+}
+
+// SecretRotationSource exposes encrypted columns to crypto.KeyRotator
+// Values are read and written as raw strings so GORM's EncryptedString
+// hooks don't decrypt/re-encrypt behind the rotator's back.
+type SecretRotationSource struct {
+	db *gorm.DB
+}
+
+// NewSecretRotationSource creates rotation source over the given database
+func NewSecretRotationSource(db *gorm.DB) *SecretRotationSource {
+	return &SecretRotationSource{db: db}
+}
+
+// SecretRotation returns rotation source for this store
+func (s *Store) SecretRotation() *SecretRotationSource {
+	return NewSecretRotationSource(s.gdb)
+}
+
+// ForEachSecret implements crypto.RotationSource
+func (s *SecretRotationSource) ForEachSecret(fn func(ref crypto.SecretRef, value string) error) error {
+	for table, columns := range encryptedColumns {
+		for _, column := range columns {
+			type row struct {
+				ID    string
+				IntID int64
+				Value string
+			}
+			idColumn := "id"
+			if integerIDTables[table] {
+				idColumn = "id AS int_id"
+			}
+			var rows []row
+			err := s.db.Table(table).
+				Select(idColumn + ", " + column + " AS value").
+				Where(column + " IS NOT NULL AND " + column + " <> ''").
+				Order("id").
+				Scan(&rows).Error
+			if err != nil {
+				return err
+			}
+			for _, r := range rows {
+				if integerIDTables[table] {
+					r.ID = strconv.FormatInt(r.IntID, 10)
+				}
+				ref := crypto.SecretRef{Table: table, Column: column, ID: r.ID}
+				if err := fn(ref, r.Value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, key := range encryptedSystemConfigKeys {
+		var value string
+		if err := s.db.Raw("SELECT value FROM system_config WHERE key = ?", key).Scan(&value).Error; err != nil {
+			return err
+		}
+		if value == "" {
+			continue
+		}
+		if err := fn(crypto.SecretRef{Table: "system_config", Column: "value", ID: key}, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateSecret implements crypto.RotationSource (compare-and-set on the old value)
+func (s *SecretRotationSource) UpdateSecret(ref crypto.SecretRef, oldValue, newValue string) (bool, error) {
+	if ref.Table == "system_config" {
+		if ref.Column != "value" || !slices.Contains(encryptedSystemConfigKeys, ref.ID) {
+			return false, fmt.Errorf("not an encrypted system config: %s", ref.ID)
+		}
+		result := s.db.Exec("UPDATE system_config SET value = ? WHERE key = ? AND value = ?", newValue, ref.ID, oldValue)
+		if result.Error != nil {
+			return false, result.Error
+		}
+		return result.RowsAffected > 0, nil
+	}
+	if !isEncryptedColumn(ref.Table, ref.Column) {
+		return false, fmt.Errorf("not an encrypted column: %s.%s", ref.Table, ref.Column)
+	}
+	var id interface{} = ref.ID
+	if integerIDTables[ref.Table] {
+		n, err := strconv.ParseInt(ref.ID, 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid id %q of %s", ref.ID, ref.Table)
+		}
+		id = n
+	}
+	result := s.db.Table(ref.Table).
+		Where("id = ? AND "+ref.Column+" = ?", id, oldValue).
+		Update(ref.Column, newValue)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func isEncryptedColumn(table, column string) bool {
+	for _, c := range encryptedColumns[table] {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"nofx/crypto"
+	"path/filepath"
+	"testing"
+)
+
+// newRotationTestService crypto service with fresh keys, previousDataKey (may be empty) stays decryptable
+func newRotationTestService(t *testing.T, dataKey, previousDataKey string) *crypto.CryptoService {
+	t.Helper()
+	privPEM, _, err := crypto.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(crypto.EnvRSAPrivateKey, privPEM)
+	t.Setenv(crypto.EnvDataEncryptionKey, dataKey)
+	t.Setenv(crypto.EnvDataEncryptionKeyPrevious, previousDataKey)
+	cs, err := crypto.NewCryptoService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cs
+}
+
+func TestSecretRotationReencryptsWebhookAndSystemConfigSecrets(t *testing.T) {
+	oldKey, _ := crypto.GenerateDataKey()
+	newKey, _ := crypto.GenerateDataKey()
+	t.Setenv("SECRETS_BACKEND", "env")
+	oldCS := newRotationTestService(t, oldKey, "")
+	crypto.SetGlobalCryptoService(oldCS)
+	defer crypto.SetGlobalCryptoService(nil)
+
+	st, err := New(filepath.Join(t.TempDir(), "rotation.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	hook := &Webhook{UserID: "u1", Name: "ops", URL: "https://example.com/hook", Secret: "signing-secret"}
+	if err := st.Webhook().Create(hook); err != nil {
+		t.Fatal(err)
+	}
+	vapid, err := st.VAPIDKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := st.GetSystemConfig(vapidKeyConfig); !oldCS.IsEncryptedStorageValue(raw) {
+		t.Fatalf("VAPID key stored in plaintext: %q", raw)
+	}
+	bundleKey, err := st.StrategyBundleSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := st.GetSystemConfig(strategyBundleKeyConfig); !oldCS.IsEncryptedStorageValue(raw) {
+		t.Fatalf("strategy bundle signing key stored in plaintext: %q", raw)
+	}
+
+	newCS := newRotationTestService(t, newKey, oldKey)
+	crypto.SetGlobalCryptoService(newCS)
+	progress, err := crypto.NewKeyRotator(newCS, st.SecretRotation()).Rotate()
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if progress.Total != 3 || progress.Reencrypted != 3 || !progress.Verified {
+		t.Fatalf("unexpected progress: %+v", progress)
+	}
+
+	// The previous key is retired, the secrets must still decrypt
+	crypto.SetGlobalCryptoService(newRotationTestService(t, newKey, ""))
+	got, err := st.Webhook().Get("u1", hook.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Secret != "signing-secret" {
+		t.Errorf("webhook secret after rotation = %q", got.Secret)
+	}
+	if again, err := st.VAPIDKey(); err != nil || !again.Equal(vapid) {
+		t.Errorf("VAPID key changed after rotation: %v", err)
+	}
+	if again, err := st.StrategyBundleSigningKey(); err != nil || !again.Equal(bundleKey) {
+		t.Errorf("strategy bundle signing key changed after rotation: %v", err)
+	}
+}
+
+func TestInitSecretSystemConfigKeepsFirstValue(t *testing.T) {
+	st, err := New(filepath.Join(t.TempDir(), "init.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	// Two callers that both found the key unset: the second one must get the first one's value
+	if got, err := st.initSecretSystemConfig(strategyBundleKeyConfig, "first"); err != nil || got != "first" {
+		t.Fatalf("first init = %q, %v", got, err)
+	}
+	if got, err := st.initSecretSystemConfig(strategyBundleKeyConfig, "second"); err != nil || got != "first" {
+		t.Errorf("second init = %q, %v, want the stored value", got, err)
+	}
+}
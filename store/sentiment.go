@@ -0,0 +1,112 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Sentiment metrics
+const (
+	SentimentFearGreed = "fear_greed" // Crypto Fear & Greed index, 0-100, market-wide (empty symbol)
+	SentimentSocial    = "social"     // Social sentiment score, -1..1, per symbol
+)
+
+// SentimentPoint one sentiment reading
+type SentimentPoint struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Metric    string    `gorm:"column:metric;not null;uniqueIndex:idx_sentiment_point" json:"metric"`
+	Symbol    string    `gorm:"column:symbol;not null;default:'';uniqueIndex:idx_sentiment_point" json:"symbol"` // Base asset, e.g. BTC (empty: market-wide)
+	Timestamp time.Time `gorm:"not null;uniqueIndex:idx_sentiment_point" json:"timestamp"`
+	Value     float64   `gorm:"column:value;not null;default:0" json:"value"`
+	Label     string    `gorm:"column:label;default:''" json:"label,omitempty"`      // e.g. "Extreme Fear"
+	Mentions  int64     `gorm:"column:mentions;default:0" json:"mentions,omitempty"` // Social volume behind the score
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for SentimentPoint
+func (SentimentPoint) TableName() string { return "sentiment_points" }
+
+// SentimentStore sentiment time series storage
+type SentimentStore struct {
+	db *gorm.DB
+}
+
+// NewSentimentStore creates a new SentimentStore
+func NewSentimentStore(db *gorm.DB) *SentimentStore {
+	return &SentimentStore{db: db}
+}
+
+func (s *SentimentStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'sentiment_points'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&SentimentPoint{})
+}
+
+// Save stores readings, a reading of the same metric, symbol and timestamp is overwritten
+func (s *SentimentStore) Save(points []*SentimentPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+	for _, p := range points {
+		p.ID = 0
+		p.Timestamp = p.Timestamp.UTC()
+	}
+	err := s.db.Omit("ID").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "metric"}, {Name: "symbol"}, {Name: "timestamp"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "label", "mentions"}),
+	}).Create(&points).Error
+	if err != nil {
+		return fmt.Errorf("failed to save sentiment points: %w", err)
+	}
+	return nil
+}
+
+// List gets the readings of a metric and symbol since a time, oldest first, at most limit
+func (s *SentimentStore) List(metric, symbol string, since time.Time, limit int) ([]*SentimentPoint, error) {
+	var points []*SentimentPoint
+	err := s.db.Where("metric = ? AND symbol = ? AND timestamp >= ?", metric, symbol, since.UTC()).
+		Order("timestamp DESC").
+		Limit(limit).
+		Find(&points).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sentiment points: %w", err)
+	}
+
+	// Oldest first for charting
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+	return points, nil
+}
+
+// Symbols lists the symbols with stored readings of a metric
+func (s *SentimentStore) Symbols(metric string) ([]string, error) {
+	var symbols []string
+	err := s.db.Model(&SentimentPoint{}).
+		Where("metric = ? AND symbol <> ''", metric).
+		Distinct("symbol").
+		Order("symbol").
+		Pluck("symbol", &symbols).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sentiment symbols: %w", err)
+	}
+	return symbols, nil
+}
+
+// DeleteBefore deletes readings older than a time, returns the number removed
+func (s *SentimentStore) DeleteBefore(t time.Time) (int64, error) {
+	result := s.db.Where("timestamp < ?", t.UTC()).Delete(&SentimentPoint{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete sentiment points: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
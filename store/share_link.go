@@ -0,0 +1,104 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ShareLink read-only access to a trader's dashboard via a signed public token
+// The token only carries the link ID; revoking or expiring the link invalidates it.
+type ShareLink struct {
+	ID            string     `gorm:"column:id;primaryKey" json:"id"`
+	TraderID      string     `gorm:"column:trader_id;not null;index" json:"trader_id"`
+	UserID        string     `gorm:"column:user_id;not null;index" json:"user_id"`
+	Label         string     `gorm:"column:label;default:''" json:"label"`
+	RedactPrompts bool       `gorm:"column:redact_prompts;not null" json:"redact_prompts"` // Hide system/input prompts, CoT and raw AI responses
+	ExpiresAt     *time.Time `gorm:"column:expires_at" json:"expires_at"`                  // nil: never expires
+	RevokedAt     *time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+// TableName returns the table name for ShareLink
+func (ShareLink) TableName() string {
+	return "trader_share_links"
+}
+
+// Active reports whether the link still grants access
+func (l *ShareLink) Active(now time.Time) bool {
+	if l.RevokedAt != nil {
+		return false
+	}
+	return l.ExpiresAt == nil || now.Before(*l.ExpiresAt)
+}
+
+// ShareLinkStore trader share link storage
+type ShareLinkStore struct {
+	db *gorm.DB
+}
+
+// NewShareLinkStore creates a new ShareLinkStore
+func NewShareLinkStore(db *gorm.DB) *ShareLinkStore {
+	return &ShareLinkStore{db: db}
+}
+
+func (s *ShareLinkStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'trader_share_links'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&ShareLink{})
+}
+
+// Create stores a new share link
+func (s *ShareLinkStore) Create(link *ShareLink) error {
+	if err := s.db.Create(link).Error; err != nil {
+		return fmt.Errorf("failed to create share link: %w", err)
+	}
+	return nil
+}
+
+// Get gets a share link by ID, returns nil if it does not exist
+func (s *ShareLinkStore) Get(id string) (*ShareLink, error) {
+	var link ShareLink
+	err := s.db.Where("id = ?", id).First(&link).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+	return &link, nil
+}
+
+// ListByTrader gets all share links of a user's trader, newest first
+func (s *ShareLinkStore) ListByTrader(userID, traderID string) ([]*ShareLink, error) {
+	var links []*ShareLink
+	err := s.db.Where("user_id = ? AND trader_id = ?", userID, traderID).
+		Order("created_at DESC").
+		Find(&links).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share links: %w", err)
+	}
+	return links, nil
+}
+
+// Revoke revokes a share link, returns gorm.ErrRecordNotFound if the user's trader has no such active link
+func (s *ShareLinkStore) Revoke(userID, traderID, id string) error {
+	result := s.db.Model(&ShareLink{}).
+		Where("id = ? AND user_id = ? AND trader_id = ? AND revoked_at IS NULL", id, userID, traderID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke share link: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Execution algorithms of opening orders
+const (
+	ExecutionMarket  = "market"  // One market order
+	ExecutionTWAP    = "twap"    // Equal market child orders spread over time
+	ExecutionIceberg = "iceberg" // Limit child orders at the best bid/ask, one resting at a time
+)
+
+// Split entry limits
+const (
+	DefaultExecutionSlices  = 5
+	MaxExecutionSlices      = 20
+	DefaultExecutionMinutes = 10
+)
+
+// Split entry status
+const (
+	SplitEntryActive    = "ACTIVE"
+	SplitEntryDone      = "DONE"
+	SplitEntryCancelled = "CANCELLED"
+)
+
+// SplitEntry an opening order executed as child orders over several minutes
+// It stays one logical position: the decision that started it is updated with the total fill.
+type SplitEntry struct {
+	ID         int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID   string  `gorm:"column:trader_id;not null;index:idx_split_entries_trader_status,priority:1" json:"trader_id"`
+	Cycle      int     `gorm:"column:cycle;not null" json:"cycle"`         // Decision cycle that started the entry
+	KeyIndex   int     `gorm:"column:key_index;not null" json:"key_index"` // Client order key index of the decision
+	Symbol     string  `gorm:"column:symbol;not null" json:"symbol"`
+	Side       string  `gorm:"column:side;not null" json:"side"` // long/short
+	Algo       string  `gorm:"column:algo;not null" json:"algo"`
+	TotalQty   float64 `gorm:"column:total_qty;not null" json:"total_qty"`
+	Slices     int     `gorm:"column:slices;not null" json:"slices"`
+	SlicesDone int     `gorm:"column:slices_done;default:0" json:"slices_done"`
+	FilledQty  float64 `gorm:"column:filled_qty;default:0" json:"filled_qty"`
+	AvgPrice   float64 `gorm:"column:avg_price;default:0" json:"avg_price"`
+	Leverage   int     `gorm:"column:leverage;default:1" json:"leverage"`
+	StopLoss   float64 `gorm:"column:stop_loss;default:0" json:"stop_loss"`
+	TakeProfit float64 `gorm:"column:take_profit;default:0" json:"take_profit"`
+	OrderID    string  `gorm:"column:order_id;default:''" json:"order_id"` // Resting iceberg child order
+	IntervalMs int64   `gorm:"column:interval_ms;not null" json:"interval_ms"`
+	NextAt     int64   `gorm:"column:next_at;not null" json:"next_at"`       // Unix milliseconds UTC of the next child order
+	ExpiresAt  int64   `gorm:"column:expires_at;not null" json:"expires_at"` // Unfilled remainder is abandoned after this
+	Status     string  `gorm:"column:status;not null;default:ACTIVE;index:idx_split_entries_trader_status,priority:2" json:"status"`
+	Reason     string  `gorm:"column:reason;default:''" json:"reason"`
+	CreatedAt  int64   `gorm:"column:created_at" json:"created_at"` // Unix milliseconds UTC
+	UpdatedAt  int64   `gorm:"column:updated_at" json:"updated_at"` // Unix milliseconds UTC
+}
+
+// TableName returns the table name for SplitEntry
+func (SplitEntry) TableName() string {
+	return "trader_split_entries"
+}
+
+// SliceQty quantity of the next child order
+func (e *SplitEntry) SliceQty() float64 {
+	remaining := e.TotalQty - e.FilledQty
+	if e.SlicesDone >= e.Slices-1 {
+		return remaining
+	}
+	return min(e.TotalQty/float64(e.Slices), remaining)
+}
+
+// AddFill accumulates a child order fill into the entry's quantity and average price
+func (e *SplitEntry) AddFill(qty, price float64) {
+	if qty <= 0 {
+		return
+	}
+	if price > 0 {
+		e.AvgPrice = (e.AvgPrice*e.FilledQty + price*qty) / (e.FilledQty + qty)
+	}
+	e.FilledQty += qty
+}
+
+// SplitEntryStore split entry storage
+type SplitEntryStore struct {
+	db *gorm.DB
+}
+
+// NewSplitEntryStore creates a new SplitEntryStore
+func NewSplitEntryStore(db *gorm.DB) *SplitEntryStore {
+	return &SplitEntryStore{db: db}
+}
+
+func (s *SplitEntryStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'trader_split_entries'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&SplitEntry{})
+}
+
+// Create records a newly started split entry
+func (s *SplitEntryStore) Create(entry *SplitEntry) error {
+	nowMs := time.Now().UTC().UnixMilli()
+	entry.CreatedAt = nowMs
+	entry.UpdatedAt = nowMs
+	if entry.Status == "" {
+		entry.Status = SplitEntryActive
+	}
+	// Omit ID to let PostgreSQL sequence auto-generate it
+	if err := s.db.Omit("ID").Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create split entry: %w", err)
+	}
+	return nil
+}
+
+// ListActive gets trader's active split entries (oldest first)
+func (s *SplitEntryStore) ListActive(traderID string) ([]*SplitEntry, error) {
+	var entries []*SplitEntry
+	err := s.db.Where("trader_id = ? AND status = ?", traderID, SplitEntryActive).
+		Order("created_at ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query active split entries: %w", err)
+	}
+	return entries, nil
+}
+
+// Update saves the progress of an entry
+func (s *SplitEntryStore) Update(entry *SplitEntry) error {
+	entry.UpdatedAt = time.Now().UTC().UnixMilli()
+	if err := s.db.Save(entry).Error; err != nil {
+		return fmt.Errorf("failed to update split entry: %w", err)
+	}
+	return nil
+}
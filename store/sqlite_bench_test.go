@@ -0,0 +1,68 @@
+package store
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// BenchmarkEquityWrites concurrent traders saving equity snapshots under each SQLite setup
+// go test ./store -run ^$ -bench EquityWrites -cpu 8
+func BenchmarkEquityWrites(b *testing.B) {
+	cases := []struct {
+		name    string
+		opts    SQLiteOptions
+		batched bool
+	}{
+		{"delete-full", SQLiteOptions{JournalMode: "DELETE", Synchronous: "FULL"}, false},
+		{"wal-normal", SQLiteOptions{}, false},
+		{"wal-normal-batched", SQLiteOptions{}, true},
+	}
+	for _, c := range cases {
+		b.Run(c.name, func(b *testing.B) {
+			st, err := NewWithConfig(DBConfig{Type: DBTypeSQLite, Path: filepath.Join(b.TempDir(), "bench.db"), SQLite: c.opts})
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer st.Close()
+
+			equity := st.Equity()
+			done := make(chan struct{})
+			stopped := make(chan struct{})
+			if c.batched {
+				writer := equity.StartWriter(EquityWriterOptions{FlushInterval: 50 * time.Millisecond})
+				go func() {
+					writer.Run(done)
+					close(stopped)
+				}()
+			} else {
+				close(stopped)
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				traderID := fmt.Sprintf("trader-%p", pb)
+				for pb.Next() {
+					if err := equity.Save(&EquitySnapshot{TraderID: traderID, TotalEquity: 1000, Balance: 1000}); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+			close(done)
+			<-stopped
+			b.StopTimer()
+
+			if n, err := countEquity(st); err != nil || n != int64(b.N) {
+				b.Fatalf("stored %d snapshots, want %d (%v)", n, b.N, err)
+			}
+		})
+	}
+}
+
+func countEquity(st *Store) (int64, error) {
+	var n int64
+	err := st.GormDB().Model(&EquitySnapshot{}).Count(&n).Error
+	return n, err
+}
@@ -3,8 +3,10 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"nofx/crypto"
 	"nofx/logger"
 	"sync"
 
@@ -29,6 +31,33 @@ type Store struct {
 	equity   *EquityStore
 	order    *OrderStore
 	grid     *GridStore
+	audit    *AuditStore
+	breaker  *CircuitBreakerStore
+	limit    *LimitEntryStore
+	income   *IncomeStore
+	coinPool *CoinPoolStore
+	exp      *ExperimentStore
+	paper    *PaperStore
+	privacy  *PrivacyStore
+	events   *TraderEventStore
+	copies   *CopyTradeStore
+	shares   *ShareLinkStore
+	recovery *RecoveryCodeStore
+	rules    *DecisionRuleStore
+	mood     *SentimentStore
+	jobs     *CycleJobStore
+	logs     *TraderLogStore
+	daily    *DailyStatsStore
+	history  *MarketHistoryStore
+	notify   *NotificationStore
+	push     *PushSubscriptionStore
+	approval *DecisionApprovalStore
+	reflect  *ReflectionStore
+	splits   *SplitEntryStore
+	reports  *ReportStore
+	webhooks *WebhookStore
+	prompts  *PromptTemplateStore
+	protect  *ProtectionStore
 
 	mu sync.RWMutex
 }
@@ -79,16 +108,23 @@ func NewWithConfig(cfg DBConfig) (*Store, error) {
 
 	s := &Store{gdb: gdb, db: sqlDB}
 
-	// Initialize all table structures
-	if err := s.initTables(); err != nil {
-		sqlDB.Close()
-		return nil, fmt.Errorf("failed to initialize table structure: %w", err)
-	}
-
-	// Initialize default data
-	if err := s.initDefaultData(); err != nil {
+	// Tables, migrations and default data are initialized under the schema lock,
+	// so instances sharing one PostgreSQL database don't race each other
+	err = s.withSchemaLock(func() error {
+		if err := s.initTables(); err != nil {
+			return fmt.Errorf("failed to initialize table structure: %w", err)
+		}
+		if err := s.runMigrations(); err != nil {
+			return fmt.Errorf("failed to run database migrations: %w", err)
+		}
+		if err := s.initDefaultData(); err != nil {
+			return fmt.Errorf("failed to initialize default data: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
 		sqlDB.Close()
-		return nil, fmt.Errorf("failed to initialize default data: %w", err)
+		return nil, err
 	}
 
 	dbTypeStr := "SQLite"
@@ -160,6 +196,87 @@ func (s *Store) initTables() error {
 	if err := s.Grid().InitTables(); err != nil {
 		return fmt.Errorf("failed to initialize grid tables: %w", err)
 	}
+	if err := s.Audit().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize audit tables: %w", err)
+	}
+	if err := s.CircuitBreaker().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize circuit breaker tables: %w", err)
+	}
+	if err := s.LimitEntry().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize limit entry tables: %w", err)
+	}
+	if err := s.Income().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize income tables: %w", err)
+	}
+	if err := s.CoinPool().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize coin pool tables: %w", err)
+	}
+	if err := s.Experiment().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize experiment tables: %w", err)
+	}
+	if err := s.Paper().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize paper trading tables: %w", err)
+	}
+	if err := s.Privacy().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize privacy tables: %w", err)
+	}
+	if err := s.TraderEvent().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize trader event tables: %w", err)
+	}
+	if err := s.CopyTrade().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize copy trade tables: %w", err)
+	}
+	if err := s.ShareLink().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize share link tables: %w", err)
+	}
+	if err := s.RecoveryCode().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize recovery code tables: %w", err)
+	}
+	if err := s.DecisionRule().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize decision rule tables: %w", err)
+	}
+	if err := s.Sentiment().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize sentiment tables: %w", err)
+	}
+	if err := s.CycleJob().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize cycle job tables: %w", err)
+	}
+	if err := s.TraderLog().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize trader log tables: %w", err)
+	}
+	if err := s.DailyStats().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize daily statistics tables: %w", err)
+	}
+	if err := s.MarketHistory().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize market history tables: %w", err)
+	}
+	if err := s.Notification().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize notification tables: %w", err)
+	}
+	if err := s.PushSubscription().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize push subscription tables: %w", err)
+	}
+	if err := s.DecisionApproval().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize decision approval tables: %w", err)
+	}
+	if err := s.Reflection().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize trade reflection tables: %w", err)
+	}
+	if err := s.SplitEntry().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize split entry tables: %w", err)
+	}
+	if err := s.Report().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize performance report tables: %w", err)
+	}
+	if err := s.Webhook().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize webhook tables: %w", err)
+	}
+	if err := s.PromptTemplate().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize prompt template tables: %w", err)
+	}
+	if err := s.Protection().initTables(); err != nil {
+		return fmt.Errorf("failed to initialize protection tables: %w", err)
+	}
 	return nil
 }
 
@@ -293,6 +410,276 @@ func (s *Store) Grid() *GridStore {
 	return s.grid
 }
 
+// Audit gets audit log storage
+func (s *Store) Audit() *AuditStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.audit == nil {
+		s.audit = NewAuditStore(s.gdb)
+	}
+	return s.audit
+}
+
+// CircuitBreaker gets trader circuit breaker storage
+func (s *Store) CircuitBreaker() *CircuitBreakerStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.breaker == nil {
+		s.breaker = NewCircuitBreakerStore(s.gdb)
+	}
+	return s.breaker
+}
+
+// LimitEntry gets AI limit entry storage
+func (s *Store) LimitEntry() *LimitEntryStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.limit == nil {
+		s.limit = NewLimitEntryStore(s.gdb)
+	}
+	return s.limit
+}
+
+// Income gets funding/commission storage
+func (s *Store) Income() *IncomeStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.income == nil {
+		s.income = NewIncomeStore(s.gdb)
+	}
+	return s.income
+}
+
+// CoinPool gets custom coin pool and trader override storage
+func (s *Store) CoinPool() *CoinPoolStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.coinPool == nil {
+		s.coinPool = NewCoinPoolStore(s.gdb)
+	}
+	return s.coinPool
+}
+
+// Experiment gets strategy A/B experiment storage
+func (s *Store) Experiment() *ExperimentStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.exp == nil {
+		s.exp = NewExperimentStore(s.gdb)
+	}
+	return s.exp
+}
+
+// Paper gets paper mode simulated position storage
+func (s *Store) Paper() *PaperStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.paper == nil {
+		s.paper = NewPaperStore(s.gdb)
+	}
+	return s.paper
+}
+
+// Privacy gets user privacy settings storage
+func (s *Store) Privacy() *PrivacyStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.privacy == nil {
+		s.privacy = NewPrivacyStore(s.gdb)
+	}
+	return s.privacy
+}
+
+// TraderEvent gets trader event timeline storage
+func (s *Store) TraderEvent() *TraderEventStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.events == nil {
+		s.events = NewTraderEventStore(s.gdb)
+	}
+	return s.events
+}
+
+// CopyTrade gets copy trading link storage
+func (s *Store) CopyTrade() *CopyTradeStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.copies == nil {
+		s.copies = NewCopyTradeStore(s.gdb)
+	}
+	return s.copies
+}
+
+// ShareLink gets trader share link storage
+func (s *Store) ShareLink() *ShareLinkStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shares == nil {
+		s.shares = NewShareLinkStore(s.gdb)
+	}
+	return s.shares
+}
+
+// RecoveryCode gets OTP recovery code storage
+func (s *Store) RecoveryCode() *RecoveryCodeStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.recovery == nil {
+		s.recovery = NewRecoveryCodeStore(s.gdb)
+	}
+	return s.recovery
+}
+
+// DecisionRule gets trader decision rule storage
+func (s *Store) DecisionRule() *DecisionRuleStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rules == nil {
+		s.rules = NewDecisionRuleStore(s.gdb)
+	}
+	return s.rules
+}
+
+// Sentiment gets sentiment time series storage
+func (s *Store) Sentiment() *SentimentStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mood == nil {
+		s.mood = NewSentimentStore(s.gdb)
+	}
+	return s.mood
+}
+
+// CycleJob gets the persistent decision cycle queue storage
+func (s *Store) CycleJob() *CycleJobStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.jobs == nil {
+		s.jobs = NewCycleJobStore(s.gdb)
+	}
+	return s.jobs
+}
+
+// TraderLog gets persisted trader log storage
+func (s *Store) TraderLog() *TraderLogStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.logs == nil {
+		s.logs = NewTraderLogStore(s.gdb)
+	}
+	return s.logs
+}
+
+// DailyStats gets daily statistics rollup storage
+func (s *Store) DailyStats() *DailyStatsStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.daily == nil {
+		s.daily = NewDailyStatsStore(s.gdb)
+	}
+	return s.daily
+}
+
+// MarketHistory gets funding rate and open interest history storage
+func (s *Store) MarketHistory() *MarketHistoryStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.history == nil {
+		s.history = NewMarketHistoryStore(s.gdb)
+	}
+	return s.history
+}
+
+// Notification gets user notification settings storage
+func (s *Store) Notification() *NotificationStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.notify == nil {
+		s.notify = NewNotificationStore(s.gdb)
+	}
+	return s.notify
+}
+
+// PushSubscription gets Web Push subscription storage
+func (s *Store) PushSubscription() *PushSubscriptionStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.push == nil {
+		s.push = NewPushSubscriptionStore(s.gdb)
+	}
+	return s.push
+}
+
+// DecisionApproval gets decision approval mode and pending decision storage
+func (s *Store) DecisionApproval() *DecisionApprovalStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.approval == nil {
+		s.approval = NewDecisionApprovalStore(s.gdb)
+	}
+	return s.approval
+}
+
+// Reflection gets post-trade reflection storage
+func (s *Store) Reflection() *ReflectionStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.reflect == nil {
+		s.reflect = NewReflectionStore(s.gdb)
+	}
+	return s.reflect
+}
+
+// SplitEntry gets TWAP/iceberg split entry storage
+func (s *Store) SplitEntry() *SplitEntryStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.splits == nil {
+		s.splits = NewSplitEntryStore(s.gdb)
+	}
+	return s.splits
+}
+
+// Report gets performance report storage
+func (s *Store) Report() *ReportStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.reports == nil {
+		s.reports = NewReportStore(s.gdb)
+	}
+	return s.reports
+}
+
+// Webhook gets outbound webhook storage
+func (s *Store) Webhook() *WebhookStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.webhooks == nil {
+		s.webhooks = NewWebhookStore(s.gdb)
+	}
+	return s.webhooks
+}
+
+// PromptTemplate gets user prompt template storage
+func (s *Store) PromptTemplate() *PromptTemplateStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.prompts == nil {
+		s.prompts = NewPromptTemplateStore(s.gdb)
+	}
+	return s.prompts
+}
+
+// Protection gets emulated OCO/take-profit ladder storage
+func (s *Store) Protection() *ProtectionStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.protect == nil {
+		s.protect = NewProtectionStore(s.gdb)
+	}
+	return s.protect
+}
+
 // Close closes database connection
 func (s *Store) Close() error {
 	if s.driver != nil {
@@ -304,6 +691,18 @@ func (s *Store) Close() error {
 	return nil
 }
 
+// Ping checks that the database answers
+func (s *Store) Ping(ctx context.Context) error {
+	if s.gdb == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	db, err := s.gdb.DB()
+	if err != nil {
+		return err
+	}
+	return db.PingContext(ctx)
+}
+
 // GormDB returns the GORM database connection
 func (s *Store) GormDB() *gorm.DB {
 	return s.gdb
@@ -367,6 +766,46 @@ func (s *Store) SetSystemConfig(key, value string) error {
 	`, key, value).Error
 }
 
+// getSecretSystemConfig gets a system configuration value stored encrypted (see encryptedSystemConfigKeys)
+// Values saved before encryption was enabled are returned as is.
+func (s *Store) getSecretSystemConfig(key string) (string, error) {
+	value, err := s.GetSystemConfig(key)
+	if err != nil || value == "" {
+		return value, err
+	}
+	var secret crypto.EncryptedString
+	if err := secret.Scan(value); err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+// setSecretSystemConfig sets a system configuration value, encrypted with the data key
+func (s *Store) setSecretSystemConfig(key, value string) error {
+	encrypted, err := crypto.EncryptedString(value).Value()
+	if err != nil {
+		return err
+	}
+	return s.SetSystemConfig(key, encrypted.(string))
+}
+
+// initSecretSystemConfig stores an encrypted system configuration value unless the key is already set
+// and returns the stored value, so concurrent first uses settle on the same value.
+func (s *Store) initSecretSystemConfig(key, value string) (string, error) {
+	encrypted, err := crypto.EncryptedString(value).Value()
+	if err != nil {
+		return "", err
+	}
+	err = s.gdb.Exec(`
+		INSERT INTO system_config (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO NOTHING
+	`, key, encrypted.(string)).Error
+	if err != nil {
+		return "", err
+	}
+	return s.getSecretSystemConfig(key)
+}
+
 // Transaction executes transaction with GORM
 func (s *Store) Transaction(fn func(tx *gorm.DB) error) error {
 	return s.gdb.Transaction(fn)
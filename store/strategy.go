@@ -44,19 +44,44 @@ type StrategyConfig struct {
 	Indicators IndicatorConfig `json:"indicators"`
 	// custom prompt (appended at the end)
 	CustomPrompt string `json:"custom_prompt,omitempty"`
+	// prompt template added before the custom prompt (built-in "default" or a user template ID)
+	PromptTemplateID      string `json:"prompt_template_id,omitempty"`
+	PromptTemplateVersion int    `json:"prompt_template_version,omitempty"` // pinned version, 0 = latest
+	// content of the selected template version, loaded by Store.ResolvePromptTemplate
+	TemplatePrompt string `json:"-"`
 	// risk control configuration
 	RiskControl RiskControlConfig `json:"risk_control"`
 	// editable sections of System Prompt
 	PromptSections PromptSectionsConfig `json:"prompt_sections,omitempty"`
+	// prompt size limit, context is trimmed to fit
+	PromptBudget PromptBudgetConfig `json:"prompt_budget,omitempty"`
+	// post-trade reflections fed back into prompts
+	Reflection ReflectionConfig `json:"reflection,omitempty"`
+	// execution algorithm of opening orders
+	Execution ExecutionConfig `json:"execution,omitempty"`
 
 	// Grid trading configuration (only used when StrategyType == "grid_trading")
 	GridConfig *GridStrategyConfig `json:"grid_config,omitempty"`
 }
 
+// Max holding time actions
+const (
+	HoldingActionFlag  = "flag"  // Stale positions are flagged to the AI for re-evaluation (default)
+	HoldingActionClose = "close" // Stale positions are closed by code
+)
+
+// Grid market types
+const (
+	GridMarketFutures = "futures" // Perpetual contracts with leverage (default)
+	GridMarketSpot    = "spot"    // Coins bought and sold without leverage
+)
+
 // GridStrategyConfig grid trading specific configuration
 type GridStrategyConfig struct {
 	// Trading pair (e.g., "BTCUSDT")
 	Symbol string `json:"symbol"`
+	// Market type: "futures" | "spot" (empty = futures)
+	MarketType string `json:"market_type,omitempty"`
 	// Number of grid levels (5-50)
 	GridCount int `json:"grid_count"`
 	// Total investment in USDT
@@ -87,6 +112,34 @@ type GridStrategyConfig struct {
 	DirectionBiasRatio float64 `json:"direction_bias_ratio"`
 }
 
+// IsSpot reports whether the grid trades the spot market
+func (c *GridStrategyConfig) IsSpot() bool {
+	return c.MarketType == GridMarketSpot
+}
+
+// Validate checks grid parameters are usable for building grid levels
+func (c *GridStrategyConfig) Validate() error {
+	if c.MarketType != "" && c.MarketType != GridMarketFutures && c.MarketType != GridMarketSpot {
+		return fmt.Errorf("market type must be %s or %s, got %s", GridMarketFutures, GridMarketSpot, c.MarketType)
+	}
+	if c.IsSpot() && c.Leverage != 1 {
+		return fmt.Errorf("spot grids trade without leverage, leverage must be 1, got %d", c.Leverage)
+	}
+	if c.GridCount < 2 || c.GridCount > 200 {
+		return fmt.Errorf("grid count must be between 2 and 200, got %d", c.GridCount)
+	}
+	if c.TotalInvestment <= 0 {
+		return fmt.Errorf("total investment must be positive")
+	}
+	if c.Leverage < 1 || c.Leverage > 125 {
+		return fmt.Errorf("leverage must be between 1 and 125, got %d", c.Leverage)
+	}
+	if !c.UseATRBounds && (c.LowerPrice <= 0 || c.UpperPrice <= c.LowerPrice) {
+		return fmt.Errorf("upper price must be greater than lower price and both positive")
+	}
+	return nil
+}
+
 // PromptSectionsConfig editable sections of System Prompt
 type PromptSectionsConfig struct {
 	// role definition (title + description)
@@ -99,10 +152,74 @@ type PromptSectionsConfig struct {
 	DecisionProcess string `json:"decision_process,omitempty"`
 }
 
+// PromptBudgetConfig prompt token budget
+// Oversized prompts are trimmed (recent trades, then klines, then candidate coins) instead of failing.
+type PromptBudgetConfig struct {
+	// max tokens of system + user prompt (0 = the AI model's context window minus room for the response)
+	MaxPromptTokens int `json:"max_prompt_tokens,omitempty"`
+}
+
+// ReflectionConfig post-trade self-review
+// After a position closes the AI explains what went right or wrong given the outcome; the latest
+// reflections are summarized in later decision prompts.
+type ReflectionConfig struct {
+	// ask the AI for a reflection after each closed position
+	Enabled bool `json:"enabled"`
+	// reflections included in the prompt (0 = DefaultReflectionPromptEntries)
+	PromptEntries int `json:"prompt_entries,omitempty"`
+	// max characters of each reflection (0 or above MaxReflectionChars = MaxReflectionChars)
+	MaxChars int `json:"max_chars,omitempty"`
+}
+
+// Limits returns the prompt entry count and reflection length to use
+func (c ReflectionConfig) Limits() (entries, maxChars int) {
+	entries, maxChars = c.PromptEntries, c.MaxChars
+	if entries <= 0 {
+		entries = DefaultReflectionPromptEntries
+	}
+	if entries > reflectionsKeptPerTrader {
+		entries = reflectionsKeptPerTrader
+	}
+	if maxChars <= 0 || maxChars > MaxReflectionChars {
+		maxChars = MaxReflectionChars
+	}
+	return entries, maxChars
+}
+
+// ExecutionConfig execution algorithm of opening orders
+// Entries of at least MinSplitUSD, or above RiskControl.MaxDepthPct of the visible order book depth,
+// are split into child orders instead of being opened with one market order.
+type ExecutionConfig struct {
+	// "market" (default, never split), "twap" (market child orders spread over DurationMinutes)
+	// or "iceberg" (limit child orders at the best bid/ask, falls back to twap without limit orders)
+	Algo string `json:"algo,omitempty"`
+	// entries of at least this size in USDT are split (0 = only entries above the depth limit)
+	MinSplitUSD float64 `json:"min_split_usd,omitempty"`
+	// child orders per entry (0 = DefaultExecutionSlices, max MaxExecutionSlices)
+	Slices int `json:"slices,omitempty"`
+	// minutes the child orders are spread over (0 = DefaultExecutionMinutes)
+	DurationMinutes int `json:"duration_minutes,omitempty"`
+}
+
+// SlicesAndDuration returns the child order count and the execution time span to use
+func (c ExecutionConfig) SlicesAndDuration() (int, time.Duration) {
+	slices, minutes := c.Slices, c.DurationMinutes
+	if slices <= 1 {
+		slices = DefaultExecutionSlices
+	}
+	slices = min(slices, MaxExecutionSlices)
+	if minutes <= 0 {
+		minutes = DefaultExecutionMinutes
+	}
+	return slices, time.Duration(minutes) * time.Minute
+}
+
 // CoinSourceConfig coin source configuration
 type CoinSourceConfig struct {
-	// source type: "static" | "ai500" | "oi_top" | "oi_low" | "mixed"
+	// source type: "static" | "ai500" | "oi_top" | "oi_low" | "custom" | "mixed"
 	SourceType string `json:"source_type"`
+	// user-defined coin pool ID (used when source_type = "custom", or merged in "mixed")
+	CustomPoolID string `json:"custom_pool_id,omitempty"`
 	// static coin list (used when source_type = "static")
 	StaticCoins []string `json:"static_coins,omitempty"`
 	// excluded coins list (filtered out from all sources)
@@ -126,6 +243,9 @@ type CoinSourceConfig struct {
 type IndicatorConfig struct {
 	// K-line configuration
 	Klines KlineConfig `json:"klines"`
+	// market data providers tried first for klines, in order ("coinank", "binance", "bybit"), the
+	// others remain failovers; empty = the server's default order
+	MarketDataProviders []string `json:"market_data_providers,omitempty"`
 	// raw kline data (OHLCV) - always enabled, required for AI analysis
 	EnableRawKlines bool `json:"enable_raw_klines"`
 	// technical indicator switches
@@ -137,6 +257,9 @@ type IndicatorConfig struct {
 	EnableVolume      bool `json:"enable_volume"`
 	EnableOI          bool `json:"enable_oi"`           // open interest
 	EnableFundingRate bool `json:"enable_funding_rate"` // funding rate
+	// order book depth (spread, bid/ask imbalance, visible depth) of candidate coins
+	EnableOrderBook bool `json:"enable_order_book"`
+	OrderBookLevels int  `json:"order_book_levels,omitempty"` // levels per side summarized, default 20
 	// EMA period configuration
 	EMAPeriods []int `json:"ema_periods,omitempty"` // default [20, 50]
 	// RSI period configuration
@@ -145,6 +268,18 @@ type IndicatorConfig struct {
 	ATRPeriods []int `json:"atr_periods,omitempty"` // default [14]
 	// BOLL period configuration (period, standard deviation multiplier is fixed at 2)
 	BOLLPeriods []int `json:"boll_periods,omitempty"` // default [20] - can select multiple timeframes
+	// extended indicators (computed server-side from the full kline history of each timeframe)
+	EnableVWAP       bool `json:"enable_vwap"`       // volume weighted average price, anchored at UTC day start
+	EnableSuperTrend bool `json:"enable_supertrend"` // SuperTrend (10, 3)
+	EnableIchimoku   bool `json:"enable_ichimoku"`   // Ichimoku cloud (9, 26, 52)
+	EnableOBV        bool `json:"enable_obv"`        // on-balance volume
+	EnableStochRSI   bool `json:"enable_stoch_rsi"`  // stochastic RSI (14, 14, 3, 3)
+	// news headlines and macro economic calendar (CPI, FOMC...), requires the NEWS_* server settings
+	EnableNews             bool `json:"enable_news"`
+	EnableEconomicCalendar bool `json:"enable_economic_calendar"`
+	NewsHeadlineLimit      int  `json:"news_headline_limit,omitempty"` // headlines shown, default 10
+	// market sentiment: Fear & Greed index and social scores, requires the SENTIMENT_* server settings
+	EnableSentiment bool `json:"enable_sentiment"`
 	// external data sources
 	ExternalDataSources []ExternalDataSource `json:"external_data_sources,omitempty"`
 
@@ -187,6 +322,15 @@ type KlineConfig struct {
 	EnableMultiTimeframe bool `json:"enable_multi_timeframe"`
 	// selected timeframe list (new: supports multi-timeframe selection)
 	SelectedTimeframes []string `json:"selected_timeframes,omitempty"`
+	// horizons shown as one-line summaries with weights, e.g. 5m/1h/4h/1d (empty: disabled)
+	// summary-only timeframes are fetched but not rendered as kline tables
+	TimeframeWeights []TimeframeWeight `json:"timeframe_weights,omitempty"`
+}
+
+// TimeframeWeight weight of a timeframe in the multi-timeframe summary
+type TimeframeWeight struct {
+	Timeframe string  `json:"timeframe"`
+	Weight    float64 `json:"weight"` // relative importance, normalized over all summary timeframes
 }
 
 // ExternalDataSource external data source configuration
@@ -219,11 +363,56 @@ type RiskControlConfig struct {
 	MaxMarginUsage float64 `json:"max_margin_usage"`
 	// Min position size in USDT (CODE ENFORCED)
 	MinPositionSize float64 `json:"min_position_size"`
-
-	// Min take_profit / stop_loss ratio (AI guided)
+	// Min distance between stop loss and liquidation price, % of entry (CODE ENFORCED, default: 1)
+	// Leverage is lowered (or the decision rejected) when the stop loss is closer to liquidation
+	LiquidationBufferPct float64 `json:"liquidation_buffer_pct"`
+	// Free balance kept on top of the initial margin and fees of a new position, % of its margin (CODE ENFORCED, default: 2)
+	// Entries the available balance cannot cover are downsized, or skipped below MinPositionSize
+	MarginBufferPct float64 `json:"margin_buffer_pct,omitempty"`
+
+	// Auto position sizing: position_size_usd is rescaled so the entry-to-stop loss equals
+	// RiskPerTradePct of equity, using ATRStopMultiplier × ATR when there is no stop loss (CODE ENFORCED, default: off)
+	RiskSizingEnabled bool    `json:"risk_sizing_enabled"`
+	RiskPerTradePct   float64 `json:"risk_per_trade_pct"`  // default: 1
+	ATRStopMultiplier float64 `json:"atr_stop_multiplier"` // default: 2
+
+	// Warn when an opening position exceeds this % of the visible order book depth it would consume
+	// (requires order book depth, default: 10)
+	MaxDepthPct float64 `json:"max_depth_pct"`
+
+	// Veto new entries from this many minutes before until this many minutes after a high-impact
+	// economic calendar event (CODE ENFORCED, requires the economic calendar, default: 0 = off)
+	BlockEntriesNearEventMins int `json:"block_entries_near_event_mins"`
+
+	// Min take_profit / stop_loss ratio of new positions (CODE ENFORCED, default: 3, scalping strategies may use 1.5)
 	MinRiskRewardRatio float64 `json:"min_risk_reward_ratio"`
+	// Skip the risk/reward check entirely (default: false)
+	DisableRiskRewardCheck bool `json:"disable_risk_reward_check,omitempty"`
 	// Min AI confidence to open position (AI guided)
 	MinConfidence int `json:"min_confidence"`
+
+	// Positions held longer than this are flagged to the AI or closed, per MaxHoldingAction (CODE ENFORCED, default: 0 = off)
+	MaxHoldingHours  float64 `json:"max_holding_hours,omitempty"`
+	MaxHoldingAction string  `json:"max_holding_action,omitempty"` // flag (default) / close
+
+	// Positions paying a funding rate of at least this % are closed shortly before the funding settlement, and
+	// entries that would pay it are skipped (CODE ENFORCED, requires the funding rate, default: 0 = off)
+	AvoidFundingRatePct    float64 `json:"avoid_funding_rate_pct,omitempty"`
+	AvoidFundingWindowMins int     `json:"avoid_funding_window_mins,omitempty"` // Minutes before settlement, default: 30
+
+	// Default partial take profits of market entries, used when the AI gives no take_profit_levels (CODE ENFORCED, default: off)
+	TakeProfitLadder []TakeProfitStep `json:"take_profit_ladder,omitempty"`
+	// Trailing stop of the part left after the ladder, % from the best price since the first fill (default: 0 = fixed stop loss)
+	RunnerTrailingStopPct float64 `json:"runner_trailing_stop_pct,omitempty"`
+
+	// Automatic pause on drawdown / losing streak (CODE ENFORCED)
+	CircuitBreaker CircuitBreakerConfig `json:"circuit_breaker"`
+}
+
+// TakeProfitStep closes ClosePct % of the opened quantity once the price moves ProfitPct % in favour of the position
+type TakeProfitStep struct {
+	ProfitPct float64 `json:"profit_pct"`
+	ClosePct  float64 `json:"close_pct"`
 }
 
 // NewStrategyStore creates a new StrategyStore
@@ -233,7 +422,7 @@ func NewStrategyStore(db *gorm.DB) *StrategyStore {
 
 func (s *StrategyStore) initTables() error {
 	// AutoMigrate will add missing columns without dropping existing data
-	return s.db.AutoMigrate(&Strategy{})
+	return s.db.AutoMigrate(&Strategy{}, &StrategyImport{})
 }
 
 func (s *StrategyStore) initDefaultData() error {
@@ -309,6 +498,9 @@ func GetDefaultStrategyConfig(lang string) StrategyConfig {
 			AltcoinMaxPositionValueRatio:    1.0, // Altcoin: max position = 1x equity (CODE ENFORCED)
 			MaxMarginUsage:                  0.9, // Max 90% margin usage (CODE ENFORCED)
 			MinPositionSize:                 12,  // Min 12 USDT per position (CODE ENFORCED)
+			LiquidationBufferPct:            1.0, // Stop loss ≥1% of entry away from liquidation (CODE ENFORCED)
+			RiskPerTradePct:                 1.0, // Auto sizing risks 1% of equity per trade when enabled (CODE ENFORCED)
+			ATRStopMultiplier:               2.0, // Auto sizing stop distance without stop loss = 2×ATR (CODE ENFORCED)
 			MinRiskRewardRatio:              3.0, // Min 3:1 profit/loss ratio (AI guided)
 			MinConfidence:                   75,  // Min 75% confidence (AI guided)
 		},
@@ -0,0 +1,258 @@
+package store
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StrategyBundleVersion current strategy bundle format version
+const StrategyBundleVersion = 1
+
+// strategyBundleKeyConfig system config key of the installation's bundle signing key (base64 Ed25519 seed)
+const strategyBundleKeyConfig = "strategy_bundle_signing_key"
+
+// StrategyBundle signed, portable strategy for sharing between installations
+// Config carries the prompt sections, indicator config and risk settings; installation-specific
+// values (NofxOS API key, custom coin pool, data source headers) are stripped on export.
+type StrategyBundle struct {
+	Version          int             `json:"version"`
+	Name             string          `json:"name"`
+	Description      string          `json:"description"`
+	StrategyVersion  string          `json:"strategy_version"`   // Exporter-chosen version label
+	SourceStrategyID string          `json:"source_strategy_id"` // Strategy ID on the exporting installation
+	ExportedAt       time.Time       `json:"exported_at"`
+	Config           json.RawMessage `json:"config"`
+
+	// Signer base64 Ed25519 public key of the exporting installation
+	Signer string `json:"signer"`
+	// Signature base64 Ed25519 signature over the bundle with an empty signature field
+	Signature string `json:"signature"`
+}
+
+// StrategyImport provenance of a strategy created from an imported bundle
+type StrategyImport struct {
+	ID                string    `gorm:"primaryKey" json:"id"`
+	UserID            string    `gorm:"column:user_id;not null;index" json:"user_id"`
+	StrategyID        string    `gorm:"column:strategy_id;not null;index" json:"strategy_id"`
+	BundleName        string    `gorm:"column:bundle_name;not null;default:''" json:"bundle_name"`
+	BundleVersion     int       `gorm:"column:bundle_version;not null;default:1" json:"bundle_version"`
+	StrategyVersion   string    `gorm:"column:strategy_version;not null;default:''" json:"strategy_version"`
+	SourceStrategyID  string    `gorm:"column:source_strategy_id;not null;default:''" json:"source_strategy_id"`
+	SignerKey         string    `gorm:"column:signer_key;not null" json:"signer_key"`
+	SignerFingerprint string    `gorm:"column:signer_fingerprint;not null;index" json:"signer_fingerprint"`
+	SelfSigned        bool      `gorm:"column:self_signed;default:false" json:"self_signed"` // Signed by this installation
+	ContentHash       string    `gorm:"column:content_hash;not null" json:"content_hash"`    // SHA-256 of the signed content
+	Trusted           bool      `gorm:"-" json:"trusted"`                                    // Signer is this installation or pinned, set per request
+	ExportedAt        time.Time `gorm:"column:exported_at" json:"exported_at"`
+	CreatedAt         time.Time `json:"imported_at"`
+}
+
+func (StrategyImport) TableName() string { return "strategy_imports" }
+
+// signedContent bytes covered by the bundle signature
+func (b *StrategyBundle) signedContent() ([]byte, error) {
+	unsigned := *b
+	unsigned.Signature = ""
+	return json.Marshal(&unsigned)
+}
+
+// Sign signs the bundle with an installation key, setting Signer and Signature
+func (b *StrategyBundle) Sign(key ed25519.PrivateKey) error {
+	b.Signer = base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+	content, err := b.signedContent()
+	if err != nil {
+		return fmt.Errorf("failed to serialize strategy bundle: %w", err)
+	}
+	b.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, content))
+	return nil
+}
+
+// Verify checks the bundle version, signature and config, returning the SHA-256 of the signed content
+// The signature is checked against the embedded Signer, see TrustedSigner for whether to trust it.
+func (b *StrategyBundle) Verify() (string, error) {
+	if b.Version <= 0 || b.Version > StrategyBundleVersion {
+		return "", fmt.Errorf("unsupported strategy bundle version: %d", b.Version)
+	}
+	pub, err := base64.StdEncoding.DecodeString(b.Signer)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return "", errors.New("invalid strategy bundle signer key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(b.Signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return "", errors.New("invalid strategy bundle signature")
+	}
+	content, err := b.signedContent()
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize strategy bundle: %w", err)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(pub), content, sig) {
+		return "", errors.New("strategy bundle signature mismatch, the bundle was modified after signing")
+	}
+
+	var cfg StrategyConfig
+	if err := json.Unmarshal(b.Config, &cfg); err != nil {
+		return "", fmt.Errorf("invalid strategy config in bundle: %w", err)
+	}
+	if cfg.GridConfig != nil {
+		if err := cfg.GridConfig.Validate(); err != nil {
+			return "", fmt.Errorf("invalid grid config in bundle: %w", err)
+		}
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// TrustedSigner whether signer (base64 key) is in trusted, given as base64 keys or fingerprints
+// Verify only proves a bundle is unchanged since it was signed by its embedded key; who that key
+// belongs to is established by pinning it here.
+func TrustedSigner(signer string, trusted []string) bool {
+	if signer == "" {
+		return false
+	}
+	fingerprint := SignerFingerprint(signer)
+	for _, t := range trusted {
+		if t == signer || strings.EqualFold(t, fingerprint) {
+			return true
+		}
+	}
+	return false
+}
+
+// SignerFingerprint short, stable identifier of a base64 signer key
+func SignerFingerprint(signer string) string {
+	sum := sha256.Sum256([]byte(signer))
+	return hex.EncodeToString(sum[:8])
+}
+
+// StrategyBundleSigningKey gets the installation's bundle signing key, generating it on first use
+// The seed is kept encrypted in system_config (see encryptedSystemConfigKeys).
+func (s *Store) StrategyBundleSigningKey() (ed25519.PrivateKey, error) {
+	value, err := s.getSecretSystemConfig(strategyBundleKeyConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load strategy bundle signing key: %w", err)
+	}
+	if value == "" {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate strategy bundle signing key: %w", err)
+		}
+		// Another request may have generated a key meanwhile, the first one stored wins
+		value, err = s.initSecretSystemConfig(strategyBundleKeyConfig, base64.StdEncoding.EncodeToString(key.Seed()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to save strategy bundle signing key: %w", err)
+		}
+	}
+
+	seed, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, errors.New("corrupted strategy bundle signing key")
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// ExportBundle exports a strategy as an unsigned bundle, stripping installation-specific settings
+func (s *StrategyStore) ExportBundle(userID, id, version string) (*StrategyBundle, error) {
+	strategy, err := s.Get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := strategy.ParseConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg.Indicators.NofxOSAPIKey = ""
+	cfg.CoinSource.CustomPoolID = ""
+	for i := range cfg.Indicators.ExternalDataSources {
+		cfg.Indicators.ExternalDataSources[i].Headers = nil // May carry API credentials
+	}
+	configJSON, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize strategy configuration: %w", err)
+	}
+
+	if version == "" {
+		version = strategy.UpdatedAt.UTC().Format("2006.01.02-150405")
+	}
+	return &StrategyBundle{
+		Version:          StrategyBundleVersion,
+		Name:             strategy.Name,
+		Description:      strategy.Description,
+		StrategyVersion:  version,
+		SourceStrategyID: strategy.ID,
+		ExportedAt:       time.Now().UTC().Truncate(time.Second),
+		Config:           json.RawMessage(configJSON),
+	}, nil
+}
+
+// ImportBundle verifies a bundle and creates it as a new strategy of user, recording its provenance
+// localSigner is this installation's base64 public key, marking bundles it signed itself.
+func (s *StrategyStore) ImportBundle(userID string, bundle *StrategyBundle, name, localSigner string) (*Strategy, *StrategyImport, error) {
+	contentHash, err := bundle.Verify()
+	if err != nil {
+		return nil, nil, err
+	}
+	if name == "" {
+		name = bundle.Name
+	}
+	if name == "" {
+		return nil, nil, errors.New("strategy name is required")
+	}
+
+	strategy := &Strategy{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		Name:        name,
+		Description: bundle.Description,
+		Config:      string(bundle.Config),
+	}
+	record := &StrategyImport{
+		ID:                uuid.New().String(),
+		UserID:            userID,
+		StrategyID:        strategy.ID,
+		BundleName:        bundle.Name,
+		BundleVersion:     bundle.Version,
+		StrategyVersion:   bundle.StrategyVersion,
+		SourceStrategyID:  bundle.SourceStrategyID,
+		SignerKey:         bundle.Signer,
+		SignerFingerprint: SignerFingerprint(bundle.Signer),
+		SelfSigned:        bundle.Signer == localSigner,
+		ContentHash:       contentHash,
+		ExportedAt:        bundle.ExportedAt.UTC(),
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(strategy).Error; err != nil {
+			return fmt.Errorf("failed to create strategy: %w", err)
+		}
+		if err := tx.Create(record).Error; err != nil {
+			return fmt.Errorf("failed to record strategy import: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return strategy, record, nil
+}
+
+// ListImports gets the user's imported bundles, newest first
+func (s *StrategyStore) ListImports(userID string) ([]*StrategyImport, error) {
+	var imports []*StrategyImport
+	err := s.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&imports).Error
+	if err != nil {
+		return nil, err
+	}
+	return imports, nil
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -19,19 +20,25 @@ func NewTraderStore(db *gorm.DB) *TraderStore {
 
 // Trader trader configuration
 type Trader struct {
-	ID                  string    `gorm:"primaryKey" json:"id"`
-	UserID              string    `gorm:"column:user_id;not null;default:default;index" json:"user_id"`
-	Name                string    `gorm:"column:name;not null" json:"name"`
-	AIModelID           string    `gorm:"column:ai_model_id;not null" json:"ai_model_id"`
-	ExchangeID          string    `gorm:"column:exchange_id;not null" json:"exchange_id"`
-	StrategyID          string    `gorm:"column:strategy_id;default:''" json:"strategy_id"`
-	InitialBalance      float64   `gorm:"column:initial_balance;not null" json:"initial_balance"`
-	ScanIntervalMinutes int       `gorm:"column:scan_interval_minutes;default:3" json:"scan_interval_minutes"`
-	IsRunning           bool      `gorm:"column:is_running;default:false" json:"is_running"`
-	IsCrossMargin       bool      `gorm:"column:is_cross_margin;default:true" json:"is_cross_margin"`
-	ShowInCompetition   bool      `gorm:"column:show_in_competition;default:true" json:"show_in_competition"`
-	CreatedAt           time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
-	UpdatedAt           time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	ID                  string         `gorm:"primaryKey" json:"id"`
+	UserID              string         `gorm:"column:user_id;not null;default:default;index" json:"user_id"`
+	Name                string         `gorm:"column:name;not null" json:"name"`
+	AIModelID           string         `gorm:"column:ai_model_id;not null" json:"ai_model_id"`
+	ExchangeID          string         `gorm:"column:exchange_id;not null" json:"exchange_id"`
+	StrategyID          string         `gorm:"column:strategy_id;default:''" json:"strategy_id"`
+	InitialBalance      float64        `gorm:"column:initial_balance;not null" json:"initial_balance"`
+	ScanIntervalMinutes int            `gorm:"column:scan_interval_minutes;default:3" json:"scan_interval_minutes"`
+	ScanIntervalSeconds int            `gorm:"column:scan_interval_seconds;default:0" json:"scan_interval_seconds"` // Sub-minute scanning, overrides minutes when > 0
+	IsRunning           bool           `gorm:"column:is_running;default:false" json:"is_running"`
+	IsCrossMargin       bool           `gorm:"column:is_cross_margin;default:true" json:"is_cross_margin"`
+	ShowInCompetition   bool           `gorm:"column:show_in_competition;default:true" json:"show_in_competition"`
+	PaperMode           bool           `gorm:"column:paper_mode;default:false" json:"paper_mode"`              // Simulate fills at mark price instead of placing real orders
+	FallbackModelIDs    string         `gorm:"column:fallback_model_ids;default:''" json:"fallback_model_ids"` // Comma-separated AI model IDs tried in order when the primary model fails
+	ContractType        string         `gorm:"column:contract_type;default:'linear'" json:"contract_type"`     // linear (USDT-margined) or inverse (coin-margined), fixed at creation
+	MarginCoin          string         `gorm:"column:margin_coin;default:''" json:"margin_coin"`               // Margin coin of inverse traders (e.g. BTC), balances are in this coin
+	CreatedAt           time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	DeletedAt           gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at,omitempty"` // Set while in the trash, hidden from all other queries
 
 	// Following fields are deprecated, kept for backward compatibility, new traders should use StrategyID
 	BTCETHLeverage       int    `gorm:"column:btc_eth_leverage;default:5" json:"btc_eth_leverage,omitempty"`
@@ -49,6 +56,30 @@ func (Trader) TableName() string {
 	return "traders"
 }
 
+// ScanInterval returns the configured decision cycle interval (seconds take precedence over minutes)
+func (t *Trader) ScanInterval() time.Duration {
+	if t.ScanIntervalSeconds > 0 {
+		return time.Duration(t.ScanIntervalSeconds) * time.Second
+	}
+	return time.Duration(t.ScanIntervalMinutes) * time.Minute
+}
+
+// IsInverse reports whether the trader trades coin-margined inverse contracts
+func (t *Trader) IsInverse() bool {
+	return t.ContractType == "inverse"
+}
+
+// FallbackModelIDList returns the fallback AI model IDs in order
+func (t *Trader) FallbackModelIDList() []string {
+	var ids []string
+	for _, id := range strings.Split(t.FallbackModelIDs, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 // TraderFullConfig trader full configuration (includes AI model, exchange and strategy)
 type TraderFullConfig struct {
 	Trader   *Trader
@@ -63,7 +94,7 @@ func (s *TraderStore) initTables() error {
 		var tableExists int64
 		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'traders'`).Scan(&tableExists)
 		if tableExists > 0 {
-			return nil
+			return nil // Later columns are added by migrations/postgres
 		}
 	}
 	// Use GORM AutoMigrate
@@ -97,6 +128,20 @@ func (s *TraderStore) UpdateStatus(userID, id string, isRunning bool) error {
 		Update("is_running", isRunning).Error
 }
 
+// SwitchExchange moves a trader to another exchange account, only if it is still on fromExchangeID
+func (s *TraderStore) SwitchExchange(userID, id, fromExchangeID, toExchangeID string) error {
+	result := s.db.Model(&Trader{}).
+		Where("id = ? AND user_id = ? AND exchange_id = ?", id, userID, fromExchangeID).
+		Update("exchange_id", toExchangeID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("trader %s is no longer on exchange %s", id, fromExchangeID)
+	}
+	return nil
+}
+
 // UpdateShowInCompetition updates trader competition visibility
 func (s *TraderStore) UpdateShowInCompetition(userID, id string, showInCompetition bool) error {
 	return s.db.Model(&Trader{}).
@@ -104,6 +149,13 @@ func (s *TraderStore) UpdateShowInCompetition(userID, id string, showInCompetiti
 		Update("show_in_competition", showInCompetition).Error
 }
 
+// UpdatePaperMode updates whether trader orders are simulated instead of sent to the exchange
+func (s *TraderStore) UpdatePaperMode(userID, id string, paperMode bool) error {
+	return s.db.Model(&Trader{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("paper_mode", paperMode).Error
+}
+
 // Update updates trader configuration
 func (s *TraderStore) Update(trader *Trader) error {
 	fmt.Printf("📝 TraderStore.Update: ID=%s, Name=%s, AIModelID=%s, StrategyID=%s\n",
@@ -116,6 +168,8 @@ func (s *TraderStore) Update(trader *Trader) error {
 		"strategy_id":    trader.StrategyID,
 		"is_cross_margin": trader.IsCrossMargin,
 		"show_in_competition": trader.ShowInCompetition,
+		"fallback_model_ids": trader.FallbackModelIDs,
+		"scan_interval_seconds": trader.ScanIntervalSeconds,
 	}
 
 	// Only update these if > 0
@@ -151,13 +205,92 @@ func (s *TraderStore) UpdateCustomPrompt(userID, id string, customPrompt string,
 		}).Error
 }
 
-// Delete deletes trader and associated data
+// Delete moves a trader to the trash, stopped and without copy trading links
+// Its history (decisions, equity, positions, events) is kept until it is purged.
 func (s *TraderStore) Delete(userID, id string) error {
-	// Delete associated equity snapshots first
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Trader{}).Where("id = ? AND user_id = ?", id, userID).Update("is_running", false)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("follower_id = ? OR leader_id = ?", id, id).Delete(&CopyTradeLink{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ? AND user_id = ?", id, userID).Delete(&Trader{}).Error
+	})
+}
+
+// ListDeleted gets user's traders in the trash, most recently deleted first
+func (s *TraderStore) ListDeleted(userID string) ([]*Trader, error) {
+	var traders []*Trader
+	err := s.db.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&traders).Error
+	if err != nil {
+		return nil, err
+	}
+	return traders, nil
+}
+
+// Restore moves a trader out of the trash (stopped, copy trading links are not restored)
+func (s *TraderStore) Restore(userID, id string) error {
+	result := s.db.Unscoped().Model(&Trader{}).
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge permanently deletes a trader in the trash and its associated data
+func (s *TraderStore) Purge(userID, id string) error {
+	var trader Trader
+	if err := s.db.Unscoped().Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).First(&trader).Error; err != nil {
+		return err
+	}
+	return s.purge(id)
+}
+
+// PurgeDeletedBefore permanently deletes traders moved to the trash before cutoff, returns how many were purged
+func (s *TraderStore) PurgeDeletedBefore(cutoff time.Time) (int, error) {
+	var ids []string
+	if err := s.db.Unscoped().Model(&Trader{}).Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	for i, id := range ids {
+		if err := s.purge(id); err != nil {
+			return i, fmt.Errorf("failed to purge trader %s: %w", id, err)
+		}
+	}
+	return len(ids), nil
+}
+
+// purge deletes a trader and associated data
+func (s *TraderStore) purge(id string) error {
+	// Delete associated equity snapshots, timeline events, copy trading, share links, decision rules, approvals, reflections, split entries and reports first
 	s.db.Where("trader_id = ?", id).Delete(&EquitySnapshot{})
+	s.db.Where("trader_id = ?", id).Delete(&TraderEvent{})
+	s.db.Where("follower_id = ? OR leader_id = ?", id, id).Delete(&CopyTradeLink{})
+	s.db.Where("trader_id = ?", id).Delete(&ShareLink{})
+	s.db.Where("trader_id = ?", id).Delete(&DecisionRule{})
+	s.db.Where("trader_id = ?", id).Delete(&CycleJob{})
+	s.db.Where("trader_id = ?", id).Delete(&TraderLog{})
+	s.db.Where("trader_id = ?", id).Delete(&ApprovalConfig{})
+	s.db.Where("trader_id = ?", id).Delete(&PendingDecision{})
+	s.db.Where("trader_id = ?", id).Delete(&TradeReflection{})
+	s.db.Where("trader_id = ?", id).Delete(&SplitEntry{})
+	s.db.Where("trader_id = ?", id).Delete(&PerformanceReport{})
 
 	// Delete the trader
-	return s.db.Where("id = ? AND user_id = ?", id, userID).Delete(&Trader{}).Error
+	return s.db.Unscoped().Where("id = ?", id).Delete(&Trader{}).Error
 }
 
 // GetFullConfig gets trader full configuration
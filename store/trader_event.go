@@ -0,0 +1,143 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Trader event types
+const (
+	TraderEventStart            = "start"
+	TraderEventStop             = "stop"
+	TraderEventError            = "error"
+	TraderEventConfigChange     = "config_change"
+	TraderEventBalanceSync      = "balance_sync"
+	TraderEventCircuitBreaker   = "circuit_breaker"
+	TraderEventDecision         = "decision"
+	TraderEventSymbolRestricted = "symbol_restricted"
+	TraderEventCopyTrade        = "copy_trade"
+	TraderEventCycleTimeout     = "cycle_timeout"
+	TraderEventDelete           = "delete"
+	TraderEventRestore          = "restore"
+	TraderEventLiquidationRisk  = "liquidation_risk"
+	TraderEventHalted           = "halted"
+	TraderEventPositionClose    = "position_close"
+	TraderEventStopLoss         = "stop_loss"
+	TraderEventApprovalPending  = "approval_pending"
+	TraderEventUnprotected      = "unprotected_position"
+	TraderEventMigration        = "migration"
+	TraderEventKillSwitch       = "kill_switch"
+)
+
+// TraderEventStore chronological per-trader event feed storage
+type TraderEventStore struct {
+	db *gorm.DB
+}
+
+// TraderEvent one entry of a trader's event timeline
+type TraderEvent struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID  string    `gorm:"column:trader_id;not null;index:idx_trader_events_trader_time" json:"trader_id"`
+	Timestamp time.Time `gorm:"not null;index:idx_trader_events_trader_time,sort:desc" json:"timestamp"`
+	Type      string    `gorm:"column:type;not null;index" json:"type"`
+	Message   string    `gorm:"column:message;type:text;not null;default:''" json:"message"`
+	Details   string    `gorm:"column:details;type:text;not null;default:''" json:"details,omitempty"` // JSON object
+}
+
+func (TraderEvent) TableName() string { return "trader_events" }
+
+// TraderEventFilter trader event query filter
+type TraderEventFilter struct {
+	TraderID string
+	Types    []string // Empty means all types
+	Start    time.Time
+	End      time.Time
+	Limit    int
+	Offset   int
+}
+
+// NewTraderEventStore creates a new TraderEventStore
+func NewTraderEventStore(db *gorm.DB) *TraderEventStore {
+	return &TraderEventStore{db: db}
+}
+
+func (s *TraderEventStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'trader_events'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&TraderEvent{})
+}
+
+// Append appends an event to a trader's timeline, details are stored as JSON (may be nil)
+func (s *TraderEventStore) Append(traderID, eventType, message string, details map[string]interface{}) error {
+	event := &TraderEvent{
+		TraderID:  traderID,
+		Timestamp: time.Now().UTC(),
+		Type:      eventType,
+		Message:   message,
+	}
+	if len(details) > 0 {
+		data, err := json.Marshal(details)
+		if err != nil {
+			return fmt.Errorf("failed to marshal trader event details: %w", err)
+		}
+		event.Details = string(data)
+	}
+	// Omit ID to let PostgreSQL sequence auto-generate it
+	if err := s.db.Omit("ID").Create(event).Error; err != nil {
+		return fmt.Errorf("failed to append trader event: %w", err)
+	}
+	return nil
+}
+
+// LastTime returns the time of a trader's latest event of a type (zero if none)
+func (s *TraderEventStore) LastTime(traderID, eventType string) (time.Time, error) {
+	var events []*TraderEvent
+	err := s.db.Where("trader_id = ? AND type = ?", traderID, eventType).
+		Order("timestamp DESC").Limit(1).Find(&events).Error
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to query trader events: %w", err)
+	}
+	if len(events) == 0 {
+		return time.Time{}, nil
+	}
+	return events[0].Timestamp, nil
+}
+
+// Query queries a trader's events (newest first), returns events and total count
+func (s *TraderEventStore) Query(filter TraderEventFilter) ([]*TraderEvent, int64, error) {
+	q := s.db.Model(&TraderEvent{}).Where("trader_id = ?", filter.TraderID)
+	if len(filter.Types) > 0 {
+		q = q.Where("type IN ?", filter.Types)
+	}
+	if !filter.Start.IsZero() {
+		q = q.Where("timestamp >= ?", filter.Start.UTC())
+	}
+	if !filter.End.IsZero() {
+		q = q.Where("timestamp <= ?", filter.End.UTC())
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count trader events: %w", err)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	var events []*TraderEvent
+	err := q.Order("timestamp DESC, id DESC").Limit(limit).Offset(filter.Offset).Find(&events).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query trader events: %w", err)
+	}
+	return events, total, nil
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TraderLog one persisted log line of a trader
+type TraderLog struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TraderID  string    `gorm:"column:trader_id;not null;index:idx_trader_logs_trader_time" json:"trader_id"`
+	Timestamp time.Time `gorm:"not null;index:idx_trader_logs_trader_time" json:"timestamp"`
+	Level     string    `gorm:"column:level;not null" json:"level"`
+	Message   string    `gorm:"column:message;type:text;not null" json:"message"`
+}
+
+// TableName returns the table name for TraderLog
+func (TraderLog) TableName() string { return "trader_logs" }
+
+// TraderLogStore persisted trader log storage
+type TraderLogStore struct {
+	db *gorm.DB
+}
+
+// NewTraderLogStore creates a new TraderLogStore
+func NewTraderLogStore(db *gorm.DB) *TraderLogStore {
+	return &TraderLogStore{db: db}
+}
+
+func (s *TraderLogStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'trader_logs'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&TraderLog{})
+}
+
+// SaveBatch stores log lines
+func (s *TraderLogStore) SaveBatch(logs []*TraderLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	for _, l := range logs {
+		l.ID = 0
+		l.Timestamp = l.Timestamp.UTC()
+	}
+	if err := s.db.Omit("ID").CreateInBatches(&logs, 200).Error; err != nil {
+		return fmt.Errorf("failed to save trader logs: %w", err)
+	}
+	return nil
+}
+
+// List gets a trader's log lines before a line ID (0: latest), oldest first, at most limit
+func (s *TraderLogStore) List(traderID string, beforeID int64, limit int) ([]*TraderLog, error) {
+	var logs []*TraderLog
+	query := s.db.Where("trader_id = ?", traderID)
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+	if err := query.Order("id DESC").Limit(limit).Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list trader logs: %w", err)
+	}
+	// Reverse to oldest first
+	for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+		logs[i], logs[j] = logs[j], logs[i]
+	}
+	return logs, nil
+}
+
+// DeleteBefore deletes log lines of all traders older than a time
+func (s *TraderLogStore) DeleteBefore(t time.Time) (int64, error) {
+	result := s.db.Where("timestamp < ?", t.UTC()).Delete(&TraderLog{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete trader logs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,172 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TraderTemplateVersion current trader export format version
+const TraderTemplateVersion = 1
+
+// TraderTemplate portable trader configuration for sharing between installations
+// Contains no credentials and no installation-specific IDs (user, AI model, exchange).
+type TraderTemplate struct {
+	Version    int       `json:"version"`
+	Name       string    `json:"name"`
+	ExportedAt time.Time `json:"exported_at"`
+
+	ScanIntervalMinutes  int    `json:"scan_interval_minutes"`
+	ScanIntervalSeconds  int    `json:"scan_interval_seconds,omitempty"` // Sub-minute scanning, overrides minutes when > 0
+	IsCrossMargin        bool   `json:"is_cross_margin"`
+	ShowInCompetition    bool   `json:"show_in_competition"`
+	BTCETHLeverage       int    `json:"btc_eth_leverage"`
+	AltcoinLeverage      int    `json:"altcoin_leverage"`
+	TradingSymbols       string `json:"trading_symbols"`
+	UseAI500             bool   `json:"use_ai500"`
+	UseOITop             bool   `json:"use_oi_top"`
+	CustomPrompt         string `json:"custom_prompt"`
+	OverrideBasePrompt   bool   `json:"override_base_prompt"`
+	SystemPromptTemplate string `json:"system_prompt_template"`
+
+	// Strategy embedded strategy config, recreated as a new strategy on import
+	Strategy *TraderTemplateStrategy `json:"strategy,omitempty"`
+}
+
+// TraderTemplateStrategy strategy section of a trader template
+type TraderTemplateStrategy struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Config      json.RawMessage `json:"config"`
+}
+
+// Validate checks the template can be imported by this version
+func (t *TraderTemplate) Validate() error {
+	if t.Version <= 0 || t.Version > TraderTemplateVersion {
+		return fmt.Errorf("unsupported trader template version: %d", t.Version)
+	}
+	if t.Strategy != nil {
+		var cfg StrategyConfig
+		if err := json.Unmarshal(t.Strategy.Config, &cfg); err != nil {
+			return fmt.Errorf("invalid strategy config in template: %w", err)
+		}
+		if cfg.GridConfig != nil {
+			if err := cfg.GridConfig.Validate(); err != nil {
+				return fmt.Errorf("invalid grid config in template: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// ScanInterval returns the template's decision cycle interval (seconds take precedence over minutes)
+func (t *TraderTemplate) ScanInterval() time.Duration {
+	if t.ScanIntervalSeconds > 0 {
+		return time.Duration(t.ScanIntervalSeconds) * time.Second
+	}
+	return time.Duration(t.ScanIntervalMinutes) * time.Minute
+}
+
+// ApplyTo copies template settings onto trader (identity fields are left untouched)
+// The scan interval is copied as is: callers clamp it to the system and AI provider floors first.
+func (t *TraderTemplate) ApplyTo(trader *Trader) {
+	trader.ScanIntervalMinutes = t.ScanIntervalMinutes
+	trader.ScanIntervalSeconds = t.ScanIntervalSeconds
+	trader.IsCrossMargin = t.IsCrossMargin
+	trader.ShowInCompetition = t.ShowInCompetition
+	trader.BTCETHLeverage = t.BTCETHLeverage
+	trader.AltcoinLeverage = t.AltcoinLeverage
+	trader.TradingSymbols = t.TradingSymbols
+	trader.UseAI500 = t.UseAI500
+	trader.UseOITop = t.UseOITop
+	trader.CustomPrompt = t.CustomPrompt
+	trader.OverrideBasePrompt = t.OverrideBasePrompt
+	trader.SystemPromptTemplate = t.SystemPromptTemplate
+	if trader.SystemPromptTemplate == "" {
+		trader.SystemPromptTemplate = "default"
+	}
+}
+
+// Get gets a single trader owned by user
+func (s *TraderStore) Get(userID, id string) (*Trader, error) {
+	var trader Trader
+	err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&trader).Error
+	if err != nil {
+		return nil, err
+	}
+	return &trader, nil
+}
+
+// ExportTemplate exports trader configuration (including its strategy) as a template
+func (s *TraderStore) ExportTemplate(userID, traderID string) (*TraderTemplate, error) {
+	trader, err := s.Get(userID, traderID)
+	if err != nil {
+		return nil, err
+	}
+
+	tpl := &TraderTemplate{
+		Version:              TraderTemplateVersion,
+		Name:                 trader.Name,
+		ExportedAt:           time.Now().UTC(),
+		ScanIntervalMinutes:  trader.ScanIntervalMinutes,
+		ScanIntervalSeconds:  trader.ScanIntervalSeconds,
+		IsCrossMargin:        trader.IsCrossMargin,
+		ShowInCompetition:    trader.ShowInCompetition,
+		BTCETHLeverage:       trader.BTCETHLeverage,
+		AltcoinLeverage:      trader.AltcoinLeverage,
+		TradingSymbols:       trader.TradingSymbols,
+		UseAI500:             trader.UseAI500,
+		UseOITop:             trader.UseOITop,
+		CustomPrompt:         trader.CustomPrompt,
+		OverrideBasePrompt:   trader.OverrideBasePrompt,
+		SystemPromptTemplate: trader.SystemPromptTemplate,
+	}
+
+	if trader.StrategyID != "" {
+		strategy, err := s.getStrategyByID(userID, trader.StrategyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get strategy: %w", err)
+		}
+		tpl.Strategy = &TraderTemplateStrategy{
+			Name:        strategy.Name,
+			Description: strategy.Description,
+			Config:      json.RawMessage(strategy.Config),
+		}
+	}
+	return tpl, nil
+}
+
+// CreateFromTemplate creates trader from template
+// trader must carry identity fields (ID, UserID, Name, AIModelID, ExchangeID, InitialBalance);
+// the template strategy (if any) is created as a new strategy owned by trader.UserID.
+func (s *TraderStore) CreateFromTemplate(tpl *TraderTemplate, trader *Trader) error {
+	if err := tpl.Validate(); err != nil {
+		return err
+	}
+	tpl.ApplyTo(trader)
+	trader.IsRunning = false
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if tpl.Strategy != nil {
+			name := tpl.Strategy.Name
+			if name == "" {
+				name = trader.Name
+			}
+			strategy := &Strategy{
+				ID:          uuid.New().String(),
+				UserID:      trader.UserID,
+				Name:        name,
+				Description: tpl.Strategy.Description,
+				Config:      string(tpl.Strategy.Config),
+			}
+			if err := tx.Create(strategy).Error; err != nil {
+				return fmt.Errorf("failed to create strategy: %w", err)
+			}
+			trader.StrategyID = strategy.ID
+		}
+		return tx.Create(trader).Error
+	})
+}
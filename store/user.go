@@ -20,6 +20,7 @@ type User struct {
 	PasswordHash string    `gorm:"column:password_hash;not null" json:"-"`
 	OTPSecret    string    `gorm:"column:otp_secret" json:"-"`
 	OTPVerified  bool      `gorm:"column:otp_verified;default:false" json:"otp_verified"`
+	Timezone     string    `gorm:"column:timezone;default:''" json:"timezone"` // IANA name for daily statistics, empty = UTC
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -54,9 +55,7 @@ func (s *UserStore) initTables() error {
 			s.db.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS password_hash TEXT NOT NULL DEFAULT ''`)
 			s.db.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP`)
 			s.db.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP`)
-			// OTP columns (added later)
-			s.db.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS otp_secret TEXT DEFAULT ''`)
-			s.db.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS otp_verified BOOLEAN DEFAULT FALSE`)
+			// Columns added later are managed by migrations/postgres
 
 			// Ensure unique index exists on email (don't care about the name)
 			var indexExists int64
@@ -119,6 +118,35 @@ func (s *UserStore) UpdateOTPVerified(userID string, verified bool) error {
 	return s.db.Model(&User{}).Where("id = ?", userID).Update("otp_verified", verified).Error
 }
 
+// ResetOTP replaces the OTP secret and requires the user to set up the authenticator again
+func (s *UserStore) ResetOTP(userID, otpSecret string) error {
+	return s.db.Model(&User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"otp_secret":   otpSecret,
+		"otp_verified": false,
+		"updated_at":   time.Now().UTC(),
+	}).Error
+}
+
+// UpdateTimezone updates the timezone daily statistics are computed in
+func (s *UserStore) UpdateTimezone(userID, timezone string) error {
+	return s.db.Model(&User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"timezone":   timezone,
+		"updated_at": time.Now().UTC(),
+	}).Error
+}
+
+// Location returns the user's timezone, UTC when unset or unknown
+func (u *User) Location() *time.Location {
+	if u.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
 // UpdatePassword updates password
 func (s *UserStore) UpdatePassword(userID, passwordHash string) error {
 	return s.db.Model(&User{}).Where("id = ?", userID).Updates(map[string]interface{}{
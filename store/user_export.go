@@ -0,0 +1,216 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserExportVersion current user export format version
+const UserExportVersion = 1
+
+// UserExport everything a user configured, for moving to another installation
+// Secrets (API keys, private keys) are in plaintext here; the API only hands it out sealed with
+// a passphrase.
+type UserExport struct {
+	Version    int               `json:"version"`
+	ExportedAt time.Time         `json:"exported_at"`
+	UserID     string            `json:"user_id"` // Owner on the exporting installation
+	AIModels   []*AIModel        `json:"ai_models"`
+	Exchanges  []*Exchange       `json:"exchanges"`
+	Strategies []*Strategy       `json:"strategies"` // Without the system default strategy
+	Traders    []*Trader         `json:"traders"`
+	Decisions  []*DecisionRecord `json:"decisions"`
+}
+
+// UserImportResult counts of imported records and the IDs that had to change
+type UserImportResult struct {
+	AIModels   int               `json:"ai_models"`
+	Exchanges  int               `json:"exchanges"`
+	Strategies int               `json:"strategies"`
+	Traders    int               `json:"traders"`
+	Decisions  int               `json:"decisions"`
+	RenamedIDs map[string]string `json:"renamed_ids"` // Exported ID -> new ID, only for IDs already taken here
+}
+
+// ExportUser collects a user's models, exchanges, strategies and traders with up to
+// decisionsPerTrader latest decisions of each trader (0: no decisions)
+func (s *Store) ExportUser(userID string, decisionsPerTrader int) (*UserExport, error) {
+	export := &UserExport{Version: UserExportVersion, ExportedAt: time.Now().UTC(), UserID: userID}
+
+	var err error
+	if export.AIModels, err = s.AIModel().List(userID); err != nil {
+		return nil, fmt.Errorf("failed to list AI models: %w", err)
+	}
+	if export.Exchanges, err = s.Exchange().List(userID); err != nil {
+		return nil, fmt.Errorf("failed to list exchanges: %w", err)
+	}
+	strategies, err := s.Strategy().List(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list strategies: %w", err)
+	}
+	export.Strategies = make([]*Strategy, 0, len(strategies))
+	for _, st := range strategies {
+		if st.UserID == userID && !st.IsDefault {
+			export.Strategies = append(export.Strategies, st)
+		}
+	}
+	if export.Traders, err = s.Trader().List(userID); err != nil {
+		return nil, fmt.Errorf("failed to list traders: %w", err)
+	}
+
+	export.Decisions = []*DecisionRecord{}
+	if decisionsPerTrader > 0 {
+		for _, t := range export.Traders {
+			records, err := s.Decision().GetLatestRecords(t.ID, decisionsPerTrader)
+			if err != nil {
+				return nil, err
+			}
+			export.Decisions = append(export.Decisions, records...)
+		}
+	}
+	return export, nil
+}
+
+// ImportUser creates the records of an export for userID in one transaction
+// IDs are kept unless already taken on this installation (re-importing creates copies), references
+// between the records follow renamed IDs. Imported traders are stopped, strategies inactive and
+// exchange accounts whose name is taken get an "(imported)" suffix.
+func (s *Store) ImportUser(userID string, export *UserExport) (*UserImportResult, error) {
+	if export == nil || export.Version < 1 || export.Version > UserExportVersion {
+		return nil, errors.New("unsupported export version")
+	}
+
+	result := &UserImportResult{RenamedIDs: map[string]string{}}
+	err := s.gdb.Transaction(func(tx *gorm.DB) error {
+		ids := map[string]string{}
+		// newID keeps id if free, otherwise derives one from it
+		newID := func(model any, id string, derive func() string) (string, error) {
+			if id != "" {
+				var count int64
+				if err := tx.Unscoped().Model(model).Where("id = ?", id).Count(&count).Error; err != nil {
+					return "", err
+				}
+				if count == 0 {
+					return id, nil
+				}
+			}
+			return derive(), nil
+		}
+		remember := func(oldID, id string) {
+			ids[oldID] = id
+			if id != oldID {
+				result.RenamedIDs[oldID] = id
+			}
+		}
+
+		for _, m := range export.AIModels {
+			id := m.ID
+			// Model IDs are usually <user>_<provider>, rebase them onto the importing user
+			if export.UserID != "" && strings.HasPrefix(id, export.UserID+"_") {
+				id = userID + strings.TrimPrefix(id, export.UserID)
+			}
+			id, err := newID(&AIModel{}, id, func() string { return id + "_" + uuid.New().String()[:8] })
+			if err != nil {
+				return err
+			}
+			model := *m
+			model.ID, model.UserID = id, userID
+			if err := tx.Create(&model).Error; err != nil {
+				return fmt.Errorf("failed to import AI model %s: %w", m.ID, err)
+			}
+			remember(m.ID, id)
+			result.AIModels++
+		}
+
+		exchanges := &ExchangeStore{db: tx}
+		for _, e := range export.Exchanges {
+			id, err := newID(&Exchange{}, e.ID, func() string { return uuid.New().String() })
+			if err != nil {
+				return err
+			}
+			exchange := *e
+			exchange.ID, exchange.UserID = id, userID
+			if taken, err := exchanges.accountNameTaken(userID, exchange.ExchangeType, exchange.AccountName, ""); err != nil {
+				return err
+			} else if taken {
+				exchange.AccountName += " (imported)"
+			}
+			if err := tx.Create(&exchange).Error; err != nil {
+				return fmt.Errorf("failed to import exchange %s: %w", e.ID, err)
+			}
+			remember(e.ID, id)
+			result.Exchanges++
+		}
+
+		for _, st := range export.Strategies {
+			id, err := newID(&Strategy{}, st.ID, func() string { return uuid.New().String() })
+			if err != nil {
+				return err
+			}
+			strategy := *st
+			strategy.ID, strategy.UserID = id, userID
+			strategy.IsActive, strategy.IsDefault = false, false
+			if err := tx.Create(&strategy).Error; err != nil {
+				return fmt.Errorf("failed to import strategy %s: %w", st.ID, err)
+			}
+			remember(st.ID, id)
+			result.Strategies++
+		}
+
+		for _, t := range export.Traders {
+			id, err := newID(&Trader{}, t.ID, func() string { return t.ID + "_" + uuid.New().String()[:8] })
+			if err != nil {
+				return err
+			}
+			trader := *t
+			trader.ID, trader.UserID = id, userID
+			trader.IsRunning = false
+			trader.DeletedAt = gorm.DeletedAt{}
+			if mapped, ok := ids[t.AIModelID]; ok {
+				trader.AIModelID = mapped
+			}
+			if mapped, ok := ids[t.ExchangeID]; ok {
+				trader.ExchangeID = mapped
+			}
+			// Strategies that were not exported (system default) fall back to the active/default one
+			trader.StrategyID = ids[t.StrategyID]
+			if fallbacks := t.FallbackModelIDList(); len(fallbacks) > 0 {
+				for i, modelID := range fallbacks {
+					if mapped, ok := ids[modelID]; ok {
+						fallbacks[i] = mapped
+					}
+				}
+				trader.FallbackModelIDs = strings.Join(fallbacks, ",")
+			}
+			if err := tx.Create(&trader).Error; err != nil {
+				return fmt.Errorf("failed to import trader %s: %w", t.ID, err)
+			}
+			remember(t.ID, id)
+			result.Traders++
+		}
+
+		decisions := &DecisionStore{db: tx}
+		for _, d := range export.Decisions {
+			traderID, ok := ids[d.TraderID]
+			if !ok {
+				continue
+			}
+			record := *d
+			record.ID, record.TraderID = 0, traderID
+			if err := decisions.LogDecision(&record); err != nil {
+				return err
+			}
+			result.Decisions++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
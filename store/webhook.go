@@ -0,0 +1,150 @@
+package store
+
+import (
+	"nofx/crypto"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookEvents trader event types webhooks can be sent for
+var WebhookEvents = []string{TraderEventDecision, TraderEventPositionClose, TraderEventStopLoss, TraderEventStart, TraderEventStop,
+	TraderEventHalted, TraderEventError, TraderEventCircuitBreaker, TraderEventLiquidationRisk, TraderEventCycleTimeout,
+	TraderEventApprovalPending, TraderEventUnprotected, TraderEventKillSwitch}
+
+// Webhook a user-defined outbound webhook, POSTing a JSON payload rendered from Template for trader events
+// Payloads are signed with Secret (HMAC-SHA256), the secret is only shown when the webhook is created.
+type Webhook struct {
+	ID         int64                  `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     string                 `gorm:"column:user_id;not null;index" json:"-"`
+	Name       string                 `gorm:"column:name;not null;default:''" json:"name"`
+	URL        string                 `gorm:"column:url;type:text;not null" json:"url"`
+	Secret     crypto.EncryptedString `gorm:"column:secret;default:''" json:"-"`
+	Template   string                 `gorm:"column:template;type:text;not null;default:''" json:"template"` // Empty = default payload
+	Events     string                 `gorm:"column:events;default:''" json:"-"`                             // Comma-separated trader event types, empty = all WebhookEvents
+	TraderID   string                 `gorm:"column:trader_id;default:''" json:"trader_id"`                  // Empty = all of the user's traders
+	Enabled    bool                   `gorm:"column:enabled;default:false" json:"enabled"`
+	LastStatus int                    `gorm:"column:last_status;default:0" json:"last_status"` // HTTP status of the last delivery, 0 = not sent or failed
+	LastError  string                 `gorm:"column:last_error;type:text;default:''" json:"last_error"`
+	LastSentAt *time.Time             `gorm:"column:last_sent_at" json:"last_sent_at,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+}
+
+func (Webhook) TableName() string { return "user_webhooks" }
+
+// EventTypes trader event types the webhook is sent for
+func (w *Webhook) EventTypes() []string {
+	var types []string
+	for _, t := range strings.Split(w.Events, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	if len(types) == 0 {
+		return WebhookEvents
+	}
+	return types
+}
+
+// Wants reports whether the webhook is sent for an event of a trader
+func (w *Webhook) Wants(traderID, eventType string) bool {
+	if !w.Enabled || (w.TraderID != "" && w.TraderID != traderID) {
+		return false
+	}
+	for _, t := range w.EventTypes() {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookStore outbound webhook storage
+type WebhookStore struct {
+	db *gorm.DB
+}
+
+// NewWebhookStore creates a new WebhookStore
+func NewWebhookStore(db *gorm.DB) *WebhookStore {
+	return &WebhookStore{db: db}
+}
+
+func (s *WebhookStore) initTables() error {
+	// For PostgreSQL with existing table, skip AutoMigrate
+	if s.db.Dialector.Name() == "postgres" {
+		var tableExists int64
+		s.db.Raw(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'user_webhooks'`).Scan(&tableExists)
+		if tableExists > 0 {
+			return nil
+		}
+	}
+	return s.db.AutoMigrate(&Webhook{})
+}
+
+// Create stores a new webhook
+func (s *WebhookStore) Create(hook *Webhook) error {
+	// Omit ID to let PostgreSQL sequence auto-generate it
+	return s.db.Omit("ID").Create(hook).Error
+}
+
+// List returns a user's webhooks
+func (s *WebhookStore) List(userID string) ([]*Webhook, error) {
+	var hooks []*Webhook
+	if err := s.db.Where("user_id = ?", userID).Order("id ASC").Find(&hooks).Error; err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+// Count returns how many webhooks a user has
+func (s *WebhookStore) Count(userID string) (int64, error) {
+	var count int64
+	err := s.db.Model(&Webhook{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Get gets one of a user's webhooks
+func (s *WebhookStore) Get(userID string, id int64) (*Webhook, error) {
+	var hook Webhook
+	if err := s.db.Where("user_id = ? AND id = ?", userID, id).First(&hook).Error; err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+// Update saves a webhook's definition (name, URL, secret, template, events, trader, enabled)
+func (s *WebhookStore) Update(hook *Webhook) error {
+	return s.db.Model(&Webhook{}).Where("user_id = ? AND id = ?", hook.UserID, hook.ID).Updates(map[string]interface{}{
+		"name":       hook.Name,
+		"url":        hook.URL,
+		"secret":     hook.Secret,
+		"template":   hook.Template,
+		"events":     hook.Events,
+		"trader_id":  hook.TraderID,
+		"enabled":    hook.Enabled,
+		"updated_at": time.Now(),
+	}).Error
+}
+
+// Delete deletes one of a user's webhooks, gorm.ErrRecordNotFound when there is none
+func (s *WebhookStore) Delete(userID string, id int64) error {
+	result := s.db.Where("user_id = ? AND id = ?", userID, id).Delete(&Webhook{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RecordDelivery records the outcome of the last delivery of a webhook
+func (s *WebhookStore) RecordDelivery(id int64, status int, errMsg string, at time.Time) error {
+	return s.db.Model(&Webhook{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_status":  status,
+		"last_error":   errMsg,
+		"last_sent_at": at,
+	}).Error
+}
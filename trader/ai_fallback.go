@@ -0,0 +1,119 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/mcp"
+	"nofx/store"
+	"strings"
+	"time"
+)
+
+// aiModelAttempts Calls per model before falling back to the next one in the chain
+var aiModelAttempts = 2
+
+// aiModelHold Recorded as the answering model when every model in the chain failed
+const aiModelHold = "hold"
+
+// AIModelEndpoint a fallback AI model (resolved from the user's AI model configuration)
+type AIModelEndpoint struct {
+	ID              string // AI model config ID
+	Provider        string // deepseek/qwen/claude/openai/...
+	APIKey          string
+	CustomAPIURL    string
+	CustomModelName string
+}
+
+// aiModelClient AI client with the name recorded on decisions it produces
+type aiModelClient struct {
+	name   string
+	client mcp.AIClient
+}
+
+// aiModelLabel names a model on decision records, e.g. "openai" or "openai/gpt-4o"
+func aiModelLabel(provider, customModelName string) string {
+	if customModelName == "" {
+		return provider
+	}
+	return provider + "/" + customModelName
+}
+
+// newFallbackClient creates the AI client of a fallback model
+func newFallbackClient(endpoint AIModelEndpoint) mcp.AIClient {
+	var client mcp.AIClient
+	switch endpoint.Provider {
+	case "claude":
+		client = mcp.NewClaudeClient()
+	case "kimi":
+		client = mcp.NewKimiClient()
+	case "gemini":
+		client = mcp.NewGeminiClient()
+	case "grok":
+		client = mcp.NewGrokClient()
+	case "openai":
+		client = mcp.NewOpenAIClient()
+	case "qwen":
+		client = mcp.NewQwenClient()
+	case "custom":
+		client = mcp.New()
+	default: // deepseek or empty
+		client = mcp.NewDeepSeekClient()
+	}
+	client.SetAPIKey(endpoint.APIKey, endpoint.CustomAPIURL, endpoint.CustomModelName)
+	return client
+}
+
+// getDecisionWithFallback asks the trader's model for a decision, then each fallback model in order.
+// Each model gets aiModelAttempts calls (timeouts and unparseable output both count as failures).
+// Without a fallback chain the primary model is called once and its error is returned as before;
+// with a chain, exhausting it escalates to a "wait" decision instead of failing the cycle.
+// Returns the decision and the name of the model that answered.
+func (at *AutoTrader) getDecisionWithFallback(ctx *kernel.Context, engine *kernel.StrategyEngine, record *store.DecisionRecord) (*kernel.FullDecision, string, error) {
+	if len(at.fallbackClients) == 0 {
+		decision, err := kernel.GetFullDecisionWithStrategy(ctx, at.mcpClient, engine, "balanced")
+		return decision, aiModelLabel(at.aiModel, at.config.CustomModelName), err
+	}
+
+	chain := append([]aiModelClient{{name: aiModelLabel(at.aiModel, at.config.CustomModelName), client: at.mcpClient}}, at.fallbackClients...)
+
+	var lastDecision *kernel.FullDecision
+	var failures []string
+	for i, model := range chain {
+		if i > 0 {
+			logger.Infof("🔁 [%s] Falling back to AI model %s", at.name, model.name)
+		}
+		for attempt := 1; attempt <= aiModelAttempts; attempt++ {
+			decision, err := kernel.GetFullDecisionWithStrategy(ctx, model.client, engine, "balanced")
+			if err == nil {
+				if i > 0 {
+					record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("🔁 Decision made by fallback AI model %s", model.name))
+				}
+				return decision, model.name, nil
+			}
+			if decision != nil {
+				lastDecision = decision
+			}
+			logger.Infof("⚠️ [%s] AI model %s failed (attempt %d/%d): %v", at.name, model.name, attempt, aiModelAttempts, err)
+			record.ExecutionLog = append(record.ExecutionLog,
+				fmt.Sprintf("⚠️ AI model %s failed (attempt %d/%d): %v", model.name, attempt, aiModelAttempts, err))
+		}
+		failures = append(failures, model.name)
+	}
+
+	logger.Infof("🛑 [%s] All AI models failed (%s), holding this cycle", at.name, strings.Join(failures, ", "))
+	hold := &kernel.FullDecision{
+		Timestamp: time.Now(),
+		Decisions: []kernel.Decision{{
+			Symbol:    "ALL",
+			Action:    "wait",
+			Reasoning: fmt.Sprintf("All %d AI models failed, holding until the next cycle", len(chain)),
+		}},
+	}
+	if lastDecision != nil {
+		hold.SystemPrompt = lastDecision.SystemPrompt
+		hold.UserPrompt = lastDecision.UserPrompt
+		hold.RawResponse = lastDecision.RawResponse
+	}
+	return hold, aiModelHold, nil
+}
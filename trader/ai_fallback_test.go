@@ -0,0 +1,88 @@
+package trader
+
+import (
+	"errors"
+	"nofx/kernel"
+	"nofx/market"
+	"nofx/mcp"
+	"nofx/store"
+	"testing"
+)
+
+// stubAIClient answers with a fixed response or error and counts calls
+type stubAIClient struct {
+	mcp.AIClient
+	response string
+	err      error
+	calls    int
+}
+
+func (s *stubAIClient) CallWithMessages(systemPrompt, userPrompt string) (string, error) {
+	s.calls++
+	return s.response, s.err
+}
+
+func newFallbackTestContext() (*kernel.Context, *kernel.StrategyEngine) {
+	defaultConfig := store.GetDefaultStrategyConfig("en")
+	ctx := &kernel.Context{
+		Account:       kernel.AccountInfo{TotalEquity: 1000, AvailableBalance: 1000},
+		MarketDataMap: map[string]*market.Data{"BTCUSDT": {Symbol: "BTCUSDT", CurrentPrice: 100}},
+		OITopDataMap:  map[string]*kernel.OITopData{},
+	}
+	return ctx, kernel.NewStrategyEngine(&defaultConfig)
+}
+
+func TestGetDecisionWithFallback_UsesNextModel(t *testing.T) {
+	primary := &stubAIClient{err: errors.New("timeout")}
+	fallback := &stubAIClient{response: `[{"symbol":"BTCUSDT","action":"wait","reasoning":"flat market"}]`}
+	at := &AutoTrader{
+		name:            "test",
+		aiModel:         "deepseek",
+		mcpClient:       primary,
+		fallbackClients: []aiModelClient{{name: "openai", client: fallback}},
+	}
+	ctx, engine := newFallbackTestContext()
+	record := &store.DecisionRecord{}
+
+	decision, model, err := at.getDecisionWithFallback(ctx, engine, record)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if model != "openai" || len(decision.Decisions) != 1 || decision.Decisions[0].Reasoning != "flat market" {
+		t.Errorf("expected decision from openai, got %s: %+v", model, decision.Decisions)
+	}
+	if primary.calls != aiModelAttempts || fallback.calls != 1 {
+		t.Errorf("expected %d primary calls and 1 fallback call, got %d and %d", aiModelAttempts, primary.calls, fallback.calls)
+	}
+}
+
+func TestGetDecisionWithFallback_HoldsWhenAllFail(t *testing.T) {
+	at := &AutoTrader{
+		name:            "test",
+		aiModel:         "deepseek",
+		mcpClient:       &stubAIClient{err: errors.New("timeout")},
+		fallbackClients: []aiModelClient{{name: "openai", client: &stubAIClient{err: errors.New("rate limited")}}},
+	}
+	ctx, engine := newFallbackTestContext()
+
+	decision, model, err := at.getDecisionWithFallback(ctx, engine, &store.DecisionRecord{})
+	if err != nil {
+		t.Fatalf("exhausted chain should hold instead of failing: %v", err)
+	}
+	if model != aiModelHold || len(decision.Decisions) != 1 || decision.Decisions[0].Action != "wait" {
+		t.Errorf("expected a wait decision, got %s: %+v", model, decision.Decisions)
+	}
+}
+
+func TestGetDecisionWithFallback_NoChainReturnsError(t *testing.T) {
+	primary := &stubAIClient{err: errors.New("timeout")}
+	at := &AutoTrader{name: "test", aiModel: "deepseek", mcpClient: primary}
+	ctx, engine := newFallbackTestContext()
+
+	if _, _, err := at.getDecisionWithFallback(ctx, engine, &store.DecisionRecord{}); err == nil {
+		t.Error("without a fallback chain the error should be returned")
+	}
+	if primary.calls != 1 {
+		t.Errorf("expected a single call without fallback chain, got %d", primary.calls)
+	}
+}
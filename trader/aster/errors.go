@@ -0,0 +1,45 @@
+package aster
+
+import (
+	"encoding/json"
+	"fmt"
+	"nofx/trader/types"
+	"strconv"
+)
+
+// asterErrorClasses Aster API error codes by class (Binance-compatible codes)
+var asterErrorClasses = map[int64]types.ErrorClass{
+	-1003: types.ErrClassRateLimited, // Too many requests
+	-1015: types.ErrClassRateLimited, // Too many new orders
+
+	-1002: types.ErrClassAuth, // Unauthorized
+	-1022: types.ErrClassAuth, // Invalid signature
+	-2014: types.ErrClassAuth, // API key format invalid
+	-2015: types.ErrClassAuth, // Invalid API key, IP or permissions
+
+	-2018: types.ErrClassInsufficientMargin, // Balance insufficient
+	-2019: types.ErrClassInsufficientMargin, // Margin insufficient
+
+	-1121: types.ErrClassInvalidSymbol, // Invalid symbol
+	-4141: types.ErrClassInvalidSymbol, // Symbol is closed
+
+	-1001: types.ErrClassNetwork, // Internal error, disconnected
+	-1007: types.ErrClassNetwork, // Timeout waiting for backend response
+	-1008: types.ErrClassNetwork, // Server overloaded
+	-1021: types.ErrClassNetwork, // Timestamp outside recvWindow
+}
+
+// httpError tags a failed Aster HTTP response with its class, by API error code or HTTP status
+func httpError(status int, body []byte) error {
+	err := fmt.Errorf("HTTP %d: %s", status, string(body))
+	var apiErr struct {
+		Code int64  `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Code != 0 {
+		if class, ok := asterErrorClasses[apiErr.Code]; ok {
+			return types.NewExchangeError(class, strconv.FormatInt(apiErr.Code, 10), err)
+		}
+	}
+	return types.NewExchangeError(types.HTTPStatusClass(status), "", err)
+}
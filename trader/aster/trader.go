@@ -397,7 +397,7 @@ func (t *AsterTrader) doRequest(method, endpoint string, params map[string]inter
 
 		body, _ := io.ReadAll(resp.Body)
 		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+			return nil, httpError(resp.StatusCode, body)
 		}
 		return body, nil
 
@@ -423,7 +423,7 @@ func (t *AsterTrader) doRequest(method, endpoint string, params map[string]inter
 
 		body, _ := io.ReadAll(resp.Body)
 		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+			return nil, httpError(resp.StatusCode, body)
 		}
 		return body, nil
 
@@ -576,6 +576,11 @@ func (t *AsterTrader) GetPositions() ([]map[string]interface{}, error) {
 
 // OpenLong Open long position
 func (t *AsterTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenLongWithClientID(symbol, quantity, leverage, types.ClientOrderKey{})
+}
+
+// OpenLongWithClientID Open long position with a deterministic client order ID
+func (t *AsterTrader) OpenLongWithClientID(symbol string, quantity float64, leverage int, key types.ClientOrderKey) (map[string]interface{}, error) {
 	// Cancel all pending orders before opening position to prevent position stacking from residual orders
 	if err := t.CancelAllOrders(symbol); err != nil {
 		logger.Infof("  ⚠ Failed to cancel pending orders (continuing to open position): %v", err)
@@ -633,6 +638,9 @@ func (t *AsterTrader) OpenLong(symbol string, quantity float64, leverage int) (m
 		"quantity":     qtyStr,
 		"price":        priceStr,
 	}
+	if !key.IsZero() {
+		params["newClientOrderId"] = asterClientOrderID(key)
+	}
 
 	body, err := t.request("POST", "/fapi/v3/order", params)
 	if err != nil {
@@ -649,6 +657,11 @@ func (t *AsterTrader) OpenLong(symbol string, quantity float64, leverage int) (m
 
 // OpenShort Open short position
 func (t *AsterTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenShortWithClientID(symbol, quantity, leverage, types.ClientOrderKey{})
+}
+
+// OpenShortWithClientID Open short position with a deterministic client order ID
+func (t *AsterTrader) OpenShortWithClientID(symbol string, quantity float64, leverage int, key types.ClientOrderKey) (map[string]interface{}, error) {
 	// Cancel all pending orders before opening position to prevent position stacking from residual orders
 	if err := t.CancelAllOrders(symbol); err != nil {
 		logger.Infof("  ⚠ Failed to cancel pending orders (continuing to open position): %v", err)
@@ -706,6 +719,9 @@ func (t *AsterTrader) OpenShort(symbol string, quantity float64, leverage int) (
 		"quantity":     qtyStr,
 		"price":        priceStr,
 	}
+	if !key.IsZero() {
+		params["newClientOrderId"] = asterClientOrderID(key)
+	}
 
 	body, err := t.request("POST", "/fapi/v3/order", params)
 	if err != nil {
@@ -720,6 +736,35 @@ func (t *AsterTrader) OpenShort(symbol string, quantity float64, leverage int) (
 	return result, nil
 }
 
+// asterClientOrderID Deterministic client order ID for an idempotent submission
+func asterClientOrderID(key types.ClientOrderKey) string {
+	return "nofx" + key.ID()
+}
+
+// FindOrderByClientID Look up an order submitted with the given client order key
+func (t *AsterTrader) FindOrderByClientID(symbol string, key types.ClientOrderKey) (map[string]interface{}, error) {
+	params := map[string]interface{}{
+		"symbol":            symbol,
+		"origClientOrderId": asterClientOrderID(key),
+	}
+
+	body, err := t.request("GET", "/fapi/v3/order", params)
+	if err != nil {
+		// -2013: Order does not exist
+		if strings.Contains(err.Error(), "-2013") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query order by client ID: %w", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse order response: %w", err)
+	}
+
+	return result, nil
+}
+
 // CloseLong Close long position
 func (t *AsterTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
 	// If quantity is 0, get current position quantity
@@ -1350,6 +1395,64 @@ func (t *AsterTrader) GetClosedPnL(startTime time.Time, limit int) ([]types.Clos
 	return records, nil
 }
 
+// GetFundingHistory gets funding fee income from Aster
+func (t *AsterTrader) GetFundingHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	return t.getIncomeHistory("FUNDING_FEE", types.IncomeTypeFunding, startTime, limit)
+}
+
+// GetCommissionHistory gets trading commission income from Aster
+func (t *AsterTrader) GetCommissionHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	return t.getIncomeHistory("COMMISSION", types.IncomeTypeCommission, startTime, limit)
+}
+
+// GetTransferHistory gets transfers into and out of the Aster futures account
+func (t *AsterTrader) GetTransferHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	return t.getIncomeHistory("TRANSFER", types.IncomeTypeTransfer, startTime, limit)
+}
+
+// getIncomeHistory queries Aster income history (Binance-compatible) for one income type
+func (t *AsterTrader) getIncomeHistory(incomeType, recordType string, startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 1000
+	}
+	params := map[string]interface{}{
+		"incomeType": incomeType,
+		"startTime":  startTime.UnixMilli(),
+		"limit":      limit,
+	}
+
+	body, err := t.request("GET", "/fapi/v3/income", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get income history: %w", err)
+	}
+
+	var incomes []struct {
+		Symbol     string `json:"symbol"`
+		IncomeType string `json:"incomeType"`
+		Income     string `json:"income"`
+		Asset      string `json:"asset"`
+		Time       int64  `json:"time"`
+		TranID     int64  `json:"tranId"`
+	}
+	if err := json.Unmarshal(body, &incomes); err != nil {
+		return nil, fmt.Errorf("failed to parse income history: %w", err)
+	}
+
+	records := make([]types.IncomeRecord, 0, len(incomes))
+	for _, income := range incomes {
+		amount, _ := strconv.ParseFloat(income.Income, 64)
+		records = append(records, types.IncomeRecord{
+			TranID: strconv.FormatInt(income.TranID, 10),
+			Symbol: income.Symbol,
+			Type:   recordType,
+			Amount: amount,
+			Asset:  income.Asset,
+			Time:   time.UnixMilli(income.Time).UTC(),
+		})
+	}
+	return records, nil
+}
+
 // AsterTradeRecord represents a trade from Aster API
 type AsterTradeRecord struct {
 	ID           int64  `json:"id"`
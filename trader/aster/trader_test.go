@@ -23,12 +23,14 @@ import (
 type AsterTraderTestSuite struct {
 	*testutil.TraderTestSuite // Embeds base test suite
 	mockServer              *httptest.Server
+	counter                 *testutil.RequestCounter
 }
 
 // NewAsterTraderTestSuite creates Aster test suite
 func NewAsterTraderTestSuite(t *testing.T) *AsterTraderTestSuite {
-	// Create mock HTTP server
-	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	// Create mock HTTP server (wrapped with request counter for conformance checks)
+	counter := testutil.NewRequestCounter()
+	mockServer := httptest.NewServer(counter.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Return different mock responses based on URL path
 		path := r.URL.Path
 
@@ -187,7 +189,7 @@ func NewAsterTraderTestSuite(t *testing.T) *AsterTraderTestSuite {
 		// Serialize response
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(respBody)
-	}))
+	})))
 
 	// Generate a private key for testing
 	privateKey, _ := crypto.GenerateKey()
@@ -209,6 +211,7 @@ func NewAsterTraderTestSuite(t *testing.T) *AsterTraderTestSuite {
 	return &AsterTraderTestSuite{
 		TraderTestSuite: baseSuite,
 		mockServer:      mockServer,
+		counter:         counter,
 	}
 }
 
@@ -239,6 +242,16 @@ func TestAsterTrader_CommonInterface(t *testing.T) {
 	suite.RunAllTests()
 }
 
+// TestAsterTrader_Conformance runs the Trader contract conformance suite
+func TestAsterTrader_Conformance(t *testing.T) {
+	suite := NewAsterTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	testutil.NewConformanceSuite(t, suite.Trader, testutil.ConformanceOptions{
+		PositionFetches: suite.counter.Count("/fapi/v3/positionRisk"),
+	}).RunConformance()
+}
+
 // ============================================================
 // 3. Aster specific unit tests
 // ============================================================
@@ -9,6 +9,8 @@ import (
 	"nofx/logger"
 	"nofx/market"
 	"nofx/mcp"
+	"nofx/provider/news"
+	"nofx/provider/sentiment"
 	"nofx/store"
 	"nofx/trader/aster"
 	"nofx/trader/binance"
@@ -52,6 +54,7 @@ type AutoTraderConfig struct {
 	BitgetAPIKey    string
 	BitgetSecretKey string
 	BitgetPassphrase string
+	BitgetTestnet    bool
 
 	// Gate API configuration
 	GateAPIKey    string
@@ -89,8 +92,12 @@ type AutoTraderConfig struct {
 	CustomAPIKey    string
 	CustomModelName string
 
+	// Fallback AI models tried in order when the model above times out or returns unparseable output
+	FallbackModels []AIModelEndpoint
+
 	// Scan configuration
 	ScanInterval time.Duration // Scan interval (recommended 3 minutes)
+	CycleTimeout time.Duration // Max run time of a cycle before the watchdog abandons it (0: scan interval minus a buffer)
 
 	// Account configuration
 	InitialBalance float64 // Initial balance (for P&L calculation, must be set manually)
@@ -100,12 +107,22 @@ type AutoTraderConfig struct {
 	MaxDrawdown     float64       // Maximum drawdown percentage (hint)
 	StopTradingTime time.Duration // Pause duration after risk control triggers
 
+	// Circuit breaker (strategy risk control merged with global defaults, code enforced)
+	CircuitBreaker store.CircuitBreakerConfig
+
 	// Position mode
 	IsCrossMargin bool // true=cross margin mode, false=isolated margin mode
 
 	// Competition visibility
 	ShowInCompetition bool // Whether to show in competition page
 
+	// Paper mode: run the full decision cycle but simulate fills at mark price (no real orders)
+	PaperMode bool
+
+	// Contract type: linear (USDT-margined, default) or inverse (coin-margined in MarginCoin, Binance/Bybit only)
+	ContractType string
+	MarginCoin   string
+
 	// Strategy configuration (use complete strategy config)
 	StrategyConfig *store.StrategyConfig // Strategy configuration (includes coin sources, indicators, risk control, prompts, etc.)
 }
@@ -118,9 +135,22 @@ type AutoTrader struct {
 	exchange              string // Trading platform type (binance/bybit/etc)
 	exchangeID            string // Exchange account UUID
 	showInCompetition     bool   // Whether to show in competition page
+	paperMode             bool   // Orders are simulated into paper_positions instead of sent to the exchange
 	config                AutoTraderConfig
 	trader                Trader // Use Trader interface (supports multiple platforms)
 	mcpClient             mcp.AIClient
+	fallbackClients       []aiModelClient // Fallback chain after mcpClient (empty = no fallback)
+	regimeService         *market.RegimeService // Market regime per symbol for exposure caps (nil = disabled)
+	symbolStatus          *market.SymbolStatusService // Delisted symbols and maintenance windows (nil = disabled)
+	killSwitch            *KillSwitch                 // System-wide operator halt (nil = none)
+	delistAlerts          map[string]string           // Restricted position alerts already raised (symbol_side -> date)
+	newsService           *news.Service               // Headlines and economic calendar (nil = disabled)
+	lastCycleJobPrune     time.Time                   // Last cycle job history pruning
+	consecutiveTimeouts   int                         // Cycles abandoned by the watchdog in a row
+	sentimentService      *sentiment.Service          // Fear & Greed index and social sentiment (nil = disabled)
+	decisionBus           *DecisionBus                // Executed decisions published for copy trading (nil = not published)
+	copyLink              *store.CopyTradeLink        // Leader this trader mirrors (nil = trades its own decisions)
+	copyMu                sync.RWMutex                // Protects copyLink
 	store                 *store.Store             // Data storage (decision records, etc.)
 	strategyEngine        *kernel.StrategyEngine // Strategy engine (uses strategy configuration)
 	cycleNumber           int                      // Current cycle number
@@ -140,8 +170,32 @@ type AutoTrader struct {
 	peakPnLCache          map[string]float64 // Peak profit cache (symbol -> peak P&L percentage)
 	peakPnLCacheMutex     sync.RWMutex       // Cache read-write lock
 	lastBalanceSyncTime   time.Time          // Last balance sync time
+	lastIncomeSyncTime    time.Time          // Last funding/commission/transfer sync time
 	userID                string             // User ID
 	gridState             *GridState         // Grid trading state (only used when StrategyType == "grid_trading")
+	gridCycleMutex        sync.Mutex         // Serializes grid cycles with live reconfiguration
+
+	// Deposits/withdrawals since the initial balance, excluded from PnL
+	transfers  store.TransferTotals
+	transferMu sync.RWMutex
+
+	// Emulated OCO stop-loss/take-profit pairs and partial take-profit ladders (symbol_side -> pair/ladder)
+	protectionPairs   map[string]*protectionPair
+	takeProfitLadders map[string]*takeProfitLadder
+	protectionMutex   sync.Mutex
+
+	// Exchange call failures by error class since the trader was created
+	exchangeErrors   map[ErrorClass]int64
+	exchangeErrorsMu sync.Mutex
+
+	// Last liquidation risk alert per position (symbol_side -> time)
+	liquidationAlerts   map[string]time.Time
+	liquidationAlertsMu sync.Mutex
+
+	// Open positions seen by the drawdown monitor and their stops, to report closes (symbol_side -> position)
+	trackedPositions map[string]trackedPosition
+	positionStops    map[string]stopLevels
+	trackedMu        sync.Mutex
 }
 
 // NewAutoTrader creates an automatic trader
@@ -223,6 +277,26 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		logger.Infof("🔧 [%s] Custom config - URL: %s, Model: %s", config.Name, config.CustomAPIURL, config.CustomModelName)
 	}
 
+	var fallbackClients []aiModelClient
+	for _, endpoint := range config.FallbackModels {
+		fallbackClients = append(fallbackClients, aiModelClient{
+			name:   aiModelLabel(endpoint.Provider, endpoint.CustomModelName),
+			client: newFallbackClient(endpoint),
+		})
+	}
+	// Queue this trader's AI calls under its own ID in the shared concurrency throttle
+	if tagger, ok := mcpClient.(mcp.CallerTagger); ok {
+		tagger.SetCaller(config.ID)
+	}
+	for _, fallback := range fallbackClients {
+		if tagger, ok := fallback.client.(mcp.CallerTagger); ok {
+			tagger.SetCaller(config.ID)
+		}
+	}
+	if len(fallbackClients) > 0 {
+		logger.Infof("🔁 [%s] AI fallback chain: %d model(s) after %s", config.Name, len(fallbackClients), aiModel)
+	}
+
 	// Set default trading platform
 	if config.Exchange == "" {
 		config.Exchange = "binance"
@@ -239,11 +313,28 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 	}
 	logger.Infof("📊 [%s] Position mode: %s", config.Name, marginModeStr)
 
+	inverse := config.ContractType == ContractInverse
+	if inverse {
+		if err := validateInverseConfig(&config); err != nil {
+			return nil, fmt.Errorf("[%s] %w", config.Name, err)
+		}
+	}
+
 	switch config.Exchange {
 	case "binance":
+		if inverse {
+			logger.Infof("🏦 [%s] Using Binance COIN-M Futures trading (%s margined)", config.Name, config.MarginCoin)
+			trader = binance.NewInverseFuturesTrader(config.BinanceAPIKey, config.BinanceSecretKey, config.MarginCoin)
+			break
+		}
 		logger.Infof("🏦 [%s] Using Binance Futures trading", config.Name)
 		trader = binance.NewFuturesTrader(config.BinanceAPIKey, config.BinanceSecretKey, userID)
 	case "bybit":
+		if inverse {
+			logger.Infof("🏦 [%s] Using Bybit Inverse Futures trading (%s margined)", config.Name, config.MarginCoin)
+			trader = bybit.NewBybitInverseTrader(config.BybitAPIKey, config.BybitSecretKey, config.MarginCoin)
+			break
+		}
 		logger.Infof("🏦 [%s] Using Bybit Futures trading", config.Name)
 		trader = bybit.NewBybitTrader(config.BybitAPIKey, config.BybitSecretKey)
 	case "okx":
@@ -251,7 +342,7 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		trader = okx.NewOKXTrader(config.OKXAPIKey, config.OKXSecretKey, config.OKXPassphrase)
 	case "bitget":
 		logger.Infof("🏦 [%s] Using Bitget Futures trading", config.Name)
-		trader = bitget.NewBitgetTrader(config.BitgetAPIKey, config.BitgetSecretKey, config.BitgetPassphrase)
+		trader = bitget.NewBitgetTrader(config.BitgetAPIKey, config.BitgetSecretKey, config.BitgetPassphrase, config.BitgetTestnet)
 	case "gate":
 		logger.Infof("🏦 [%s] Using Gate.io Futures trading", config.Name)
 		trader = gate.NewGateTrader(config.GateAPIKey, config.GateSecretKey)
@@ -324,6 +415,18 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		}
 	}
 
+	// Paper mode: keep the real exchange for market data only, simulate all orders
+	if config.PaperMode {
+		if st == nil {
+			return nil, fmt.Errorf("[%s] paper mode requires a database", config.Name)
+		}
+		if config.StrategyConfig != nil && config.StrategyConfig.StrategyType == "grid_trading" {
+			return nil, fmt.Errorf("[%s] paper mode does not support grid trading strategies", config.Name)
+		}
+		trader = newPaperTrader(trader, st.Paper(), config.ID, config.InitialBalance)
+		logger.Infof("📝 [%s] Paper mode enabled: orders are simulated at mark price", config.Name)
+	}
+
 	// Get last cycle number (for recovery)
 	var cycleNumber int
 	if st != nil {
@@ -336,18 +439,24 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		return nil, fmt.Errorf("[%s] strategy not configured", config.Name)
 	}
 	strategyEngine := kernel.NewStrategyEngine(config.StrategyConfig)
+	if st != nil {
+		// Custom coin pools and per-trader pin/blacklist come from the database
+		strategyEngine.SetCoinPoolSource(st.CoinPool().ForTrader(userID, config.ID))
+	}
 	logger.Infof("✓ [%s] Using strategy engine (strategy configuration loaded)", config.Name)
 
-	return &AutoTrader{
+	at := &AutoTrader{
 		id:                    config.ID,
 		name:                  config.Name,
 		aiModel:               config.AIModel,
 		exchange:              config.Exchange,
 		exchangeID:            config.ExchangeID,
 		showInCompetition:     config.ShowInCompetition,
+		paperMode:             config.PaperMode,
 		config:                config,
 		trader:                trader,
 		mcpClient:             mcpClient,
+		fallbackClients:       fallbackClients,
 		store:                 st,
 		strategyEngine:        strategyEngine,
 		cycleNumber:           cycleNumber,
@@ -357,17 +466,26 @@ func NewAutoTrader(config AutoTraderConfig, st *store.Store, userID string) (*Au
 		callCount:             0,
 		isRunning:             false,
 		positionFirstSeenTime: make(map[string]int64),
+		delistAlerts:          make(map[string]string),
 		stopMonitorCh:         make(chan struct{}),
 		monitorWg:             sync.WaitGroup{},
 		peakPnLCache:          make(map[string]float64),
 		peakPnLCacheMutex:     sync.RWMutex{},
 		lastBalanceSyncTime:   time.Now(),
 		userID:                userID,
-	}, nil
+	}
+	// Capture this trader's log lines for the live log API
+	logger.RegisterSource(at.id, "["+at.name+"]")
+	return at, nil
 }
 
 // Run runs the automatic trading main loop
 func (at *AutoTrader) Run() error {
+	if err := at.prepareCircuitBreaker(); err != nil {
+		at.recordHalt(err)
+		return err
+	}
+
 	at.isRunningMutex.Lock()
 	at.isRunning = true
 	at.isRunningMutex.Unlock()
@@ -375,9 +493,19 @@ func (at *AutoTrader) Run() error {
 	at.stopMonitorCh = make(chan struct{})
 	at.startTime = time.Now()
 
-	logger.Info("🚀 AI-driven automatic trading system started")
-	logger.Infof("💰 Initial balance: %.2f USDT", at.initialBalance)
-	logger.Infof("⚙️  Scan interval: %v", at.config.ScanInterval)
+	// Prefetch market data while the exchange syncs start up (grid strategies fetch their own data)
+	if !at.IsGridStrategy() {
+		at.startMarketDataWarmup()
+	}
+
+	logger.Infof("🚀 [%s] AI-driven automatic trading system started", at.name)
+	at.recordEvent(store.TraderEventStart, "Trader started", map[string]interface{}{
+		"scan_interval": at.config.ScanInterval.String(),
+		"paper_mode":    at.paperMode,
+	})
+	logger.Infof("💰 [%s] Initial balance: %.2f USDT", at.name, at.initialBalance)
+	at.loadNetTransfers()
+	logger.Infof("⚙️  [%s] Scan interval: %v", at.name, at.config.ScanInterval)
 	logger.Info("🤖 AI will make full decisions on leverage, position size, stop loss/take profit, etc.")
 	at.monitorWg.Add(1)
 	defer at.monitorWg.Done()
@@ -385,6 +513,14 @@ func (at *AutoTrader) Run() error {
 	// Start drawdown monitoring
 	at.startDrawdownMonitor()
 
+	// Start emulated OCO watcher (cancels the remaining TP/SL leg once a position closes)
+	at.restoreProtection()
+	at.restoreTakeProfitLadders()
+	at.startProtectionWatcher()
+
+	// Start placing the child orders of split (TWAP/iceberg) entries
+	at.startSplitEntryWorker()
+
 	// Start Lighter order sync if using Lighter exchange
 	if at.exchange == "lighter" {
 		if lighterTrader, ok := at.trader.(*lighter.LighterTraderV2); ok && at.store != nil {
@@ -466,20 +602,16 @@ func (at *AutoTrader) Run() error {
 		logger.Infof("🔲 [%s] Grid trading strategy detected, initializing grid...", at.name)
 		if err := at.InitializeGrid(); err != nil {
 			logger.Errorf("❌ [%s] Failed to initialize grid: %v", at.name, err)
-			return fmt.Errorf("grid initialization failed: %w", err)
+			err = fmt.Errorf("grid initialization failed: %w", err)
+			at.recordHalt(err)
+			return err
 		}
 	}
 
-	// Execute immediately on first run
-	if isGridStrategy {
-		if err := at.RunGridCycle(); err != nil {
-			logger.Infof("❌ Grid execution failed: %v", err)
-		}
-	} else {
-		if err := at.runCycle(); err != nil {
-			logger.Infof("❌ Execution failed: %v", err)
-		}
-	}
+	// Retry cycles a crash interrupted, then execute immediately on first run
+	at.recoverCycleJobs()
+	at.enqueueCycle()
+	retryC := at.processCycleJobs()
 
 	for {
 		at.isRunningMutex.RLock()
@@ -492,15 +624,10 @@ func (at *AutoTrader) Run() error {
 
 		select {
 		case <-ticker.C:
-			if isGridStrategy {
-				if err := at.RunGridCycle(); err != nil {
-					logger.Infof("❌ Grid execution failed: %v", err)
-				}
-			} else {
-				if err := at.runCycle(); err != nil {
-					logger.Infof("❌ Execution failed: %v", err)
-				}
-			}
+			at.enqueueCycle()
+			retryC = at.processCycleJobs()
+		case <-retryC:
+			retryC = at.processCycleJobs()
 		case <-at.stopMonitorCh:
 			logger.Infof("[%s] ⏹ Stop signal received, exiting automatic trading main loop", at.name)
 			return nil
@@ -522,15 +649,17 @@ func (at *AutoTrader) Stop() {
 
 	close(at.stopMonitorCh) // Notify monitoring goroutine to stop
 	at.monitorWg.Wait()     // Wait for monitoring goroutine to finish
-	logger.Info("⏹ Automatic trading system stopped")
+	at.cancelCycleJobs()
+	logger.Infof("⏹ [%s] Automatic trading system stopped", at.name)
+	at.recordEvent(store.TraderEventStop, "Trader stopped", nil)
 }
 
 // runCycle runs one trading cycle (using AI full decision-making)
-func (at *AutoTrader) runCycle() error {
+func (at *AutoTrader) runCycle(run *cycleRun) error {
 	at.callCount++
 
 	logger.Info("\n" + strings.Repeat("=", 70) + "\n")
-	logger.Infof("⏰ %s - AI decision cycle #%d", time.Now().Format("2006-01-02 15:04:05"), at.callCount)
+	logger.Infof("⏰ [%s] %s - AI decision cycle #%d", at.name, time.Now().Format("2006-01-02 15:04:05"), at.callCount)
 	logger.Info(strings.Repeat("=", 70))
 
 	// 0. Check if trader is stopped (early exit to prevent trades after Stop() is called)
@@ -538,7 +667,7 @@ func (at *AutoTrader) runCycle() error {
 	running := at.isRunning
 	at.isRunningMutex.RUnlock()
 	if !running {
-		logger.Infof("⏹ Trader is stopped, aborting cycle #%d", at.callCount)
+		logger.Infof("⏹ [%s] Trader is stopped, aborting cycle #%d", at.name, at.callCount)
 		return nil
 	}
 
@@ -547,6 +676,9 @@ func (at *AutoTrader) runCycle() error {
 		ExecutionLog: []string{},
 		Success:      true,
 	}
+	if at.paperMode {
+		record.ExecutionLog = append(record.ExecutionLog, "📝 Paper mode: orders simulated at mark price, no real orders sent")
+	}
 
 	// 1. Check if trading needs to be stopped
 	if time.Now().Before(at.stopUntil) {
@@ -558,6 +690,28 @@ func (at *AutoTrader) runCycle() error {
 		return nil
 	}
 
+	// Operator kill switch: no cycles run until it is released
+	if msg := at.killSwitchReason(); msg != "" {
+		logger.Infof("🛑 [%s] %s, skipping cycle", at.name, msg)
+		record.Success = false
+		record.ErrorMessage = msg
+		at.saveDecision(record)
+		return nil
+	}
+
+	// Exchange maintenance: orders and market data are unreliable, skip the cycle
+	if window := at.activeMaintenance(); window != nil {
+		msg := fmt.Sprintf("Exchange maintenance until %s", window.End.UTC().Format("2006-01-02 15:04 UTC"))
+		if window.Reason != "" {
+			msg += ": " + window.Reason
+		}
+		logger.Infof("🛠 [%s] %s, skipping cycle", at.name, msg)
+		record.Success = false
+		record.ErrorMessage = msg
+		at.saveDecision(record)
+		return nil
+	}
+
 	// 2. Reset daily P&L (reset every day)
 	if time.Since(at.lastResetTime) > 24*time.Hour {
 		at.dailyPnL = 0
@@ -565,6 +719,13 @@ func (at *AutoTrader) runCycle() error {
 		logger.Info("📅 Daily P&L reset")
 	}
 
+	// 3. Reconcile pending limit entries (attach SL/TP on fill, cancel on expiry)
+	at.settlePaper()
+	at.reconcileLimitEntries()
+
+	// Sync funding payments and commissions for net PnL stats (throttled)
+	at.syncIncomeHistory()
+
 	// 4. Collect trading context
 	ctx, err := at.buildTradingContext()
 	if err != nil {
@@ -578,6 +739,40 @@ func (at *AutoTrader) runCycle() error {
 	// NOTE: Must be called BEFORE candidate coins check to ensure equity is always recorded
 	at.saveEquitySnapshot(ctx)
 
+	// Close or flag positions in contracts that are being delisted
+	at.handleRestrictedPositions(ctx.Positions, record)
+
+	// Close or flag positions held longer than the strategy allows
+	at.handleStalePositions(ctx, record)
+
+	// Close positions about to pay a funding rate above the strategy's limit
+	at.handleFundingExposure(ctx, record)
+
+	// Circuit breaker: stop trader on excessive drawdown or losing streak
+	if reason := at.checkCircuitBreaker(ctx.Account.TotalEquity); reason != "" {
+		record.Success = false
+		record.ErrorMessage = "Circuit breaker tripped: " + reason
+		record.ExecutionLog = append(record.ExecutionLog, record.ErrorMessage)
+		at.saveDecision(record)
+		at.haltForCircuitBreaker()
+		return nil
+	}
+
+	// Copy trading followers mirror their leader's executions instead of asking the AI
+	if leaderID := at.copyLeaderID(); leaderID != "" {
+		logger.Infof("📋 [%s] Copy trading leader %s, own AI decisions skipped", at.name, leaderID)
+		record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("Copy trading leader %s, AI decision skipped", leaderID))
+		record.AccountState = store.AccountSnapshot{
+			TotalBalance:          ctx.Account.TotalEquity,
+			AvailableBalance:      ctx.Account.AvailableBalance,
+			TotalUnrealizedProfit: ctx.Account.UnrealizedPnL,
+			PositionCount:         ctx.Account.PositionCount,
+			InitialBalance:        at.initialBalance,
+		}
+		at.saveDecision(record)
+		return nil
+	}
+
 	// 如果没有候选币种，记录但不报错
 	if len(ctx.CandidateCoins) == 0 {
 		logger.Infof("ℹ️  No candidate coins available, skipping this cycle")
@@ -602,9 +797,42 @@ func (at *AutoTrader) runCycle() error {
 	logger.Infof("📊 Account equity: %.2f USDT | Available: %.2f USDT | Positions: %d",
 		ctx.Account.TotalEquity, ctx.Account.AvailableBalance, ctx.Account.PositionCount)
 
-	// 5. Use strategy engine to call AI for decision
+	if run.abortedCheckpoint(at.name, "after building the trading context") {
+		return errCycleAborted
+	}
+
+	// 5. Strategy A/B experiment replaces the single strategy decision
+	if exp := at.runningExperiment(); exp != nil {
+		return at.runExperimentCycle(run, exp, ctx, record)
+	}
+	return at.decideAndExecute(run, ctx, at.strategyEngine, record)
+}
+
+// decideAndExecute calls AI with the given strategy engine, executes decisions and saves the decision record
+func (at *AutoTrader) decideAndExecute(run *cycleRun, ctx *kernel.Context, engine *kernel.StrategyEngine, record *store.DecisionRecord) error {
+	// Plugins may skip the cycle before the AI is asked
+	if reason := at.preDecisionHook(ctx); reason != "" {
+		logger.Infof("🔌 [%s] AI decision skipped: %s", at.name, reason)
+		record.ExecutionLog = append(record.ExecutionLog, "🔌 AI decision skipped: "+reason)
+		at.saveDecision(record)
+		return nil
+	}
+
+	// Use strategy engine to call AI for decision
 	logger.Infof("🤖 Requesting AI analysis and decision... [Strategy Engine]")
-	aiDecision, err := kernel.GetFullDecisionWithStrategy(ctx, at.mcpClient, at.strategyEngine, "balanced")
+	aiDecision, answeredBy, err := at.getDecisionWithFallback(ctx, engine, record)
+	if run.abortedCheckpoint(at.name, "before executing the AI decision") {
+		return errCycleAborted
+	}
+	record.AIModel = answeredBy
+	if len(ctx.MarketDataSkipped) > 0 {
+		record.ExecutionLog = append(record.ExecutionLog,
+			fmt.Sprintf("⚠️ Market data unavailable, skipped: %s", kernel.FormatSkippedSymbols(ctx.MarketDataSkipped)))
+	}
+
+	if aiDecision != nil && aiDecision.PromptTrimmed != "" {
+		record.ExecutionLog = append(record.ExecutionLog, "✂️ "+aiDecision.PromptTrimmed)
+	}
 
 	if aiDecision != nil && aiDecision.AIRequestDurationMs > 0 {
 		record.AIRequestDurationMs = aiDecision.AIRequestDurationMs
@@ -678,8 +906,33 @@ func (at *AutoTrader) runCycle() error {
 	// 8. Sort decisions: ensure close positions first, then open positions (prevent position stacking overflow)
 	logger.Info(strings.Repeat("-", 70))
 
+	// Plugin hook and trader decision rules may veto, modify or annotate decisions
+	decisions := at.applyDecisionMiddleware(aiDecision.Decisions, record)
+
+	// No new entries around high-impact economic events (CPI, FOMC...)
+	if ctx.News != nil {
+		decisions = at.blockEntriesNearEvents(decisions, ctx.News, engine.GetRiskControlConfig().BlockEntriesNearEventMins, record)
+	}
+
+	// No new entries that would pay a high funding rate at the imminent settlement
+	decisions = at.blockEntriesBeforeFunding(decisions, ctx.MarketDataMap, engine.GetRiskControlConfig(), record)
+
+	// Approval mode: large or low-confidence opens wait for the user instead of executing
+	decisions = at.holdForApproval(decisions, record)
+
+	// Warn when an opening size would eat a large share of the visible order book
+	if ctx.OrderBookMap != nil {
+		maxPct := engine.GetRiskControlConfig().MaxDepthPct
+		for i := range decisions {
+			if warning := kernel.CheckDepth(&decisions[i], ctx.OrderBookMap[decisions[i].Symbol], maxPct); warning != "" {
+				logger.Warnf("⚠️ [%s] Thin liquidity: %s", at.name, warning)
+				record.ExecutionLog = append(record.ExecutionLog, "⚠️ Thin liquidity: "+warning)
+			}
+		}
+	}
+
 	// 8. Sort decisions: ensure close positions first, then open positions (prevent position stacking overflow)
-	sortedDecisions := sortDecisionsByPriority(aiDecision.Decisions)
+	sortedDecisions := sortDecisionsByPriority(decisions)
 
 	logger.Info("🔄 Execution order (optimized): Close positions first → Open positions later")
 	for i, d := range sortedDecisions {
@@ -689,7 +942,7 @@ func (at *AutoTrader) runCycle() error {
 
 	// Check if trader is stopped before executing any decisions (prevent trades after Stop())
 	at.isRunningMutex.RLock()
-	running = at.isRunning
+	running := at.isRunning
 	at.isRunningMutex.RUnlock()
 	if !running {
 		logger.Infof("⏹ Trader stopped before decision execution, aborting cycle #%d", at.callCount)
@@ -697,7 +950,7 @@ func (at *AutoTrader) runCycle() error {
 	}
 
 	// Execute decisions and record results
-	for _, d := range sortedDecisions {
+	for i, d := range sortedDecisions {
 		// Check if trader is stopped before each decision (allow immediate stop during execution)
 		at.isRunningMutex.RLock()
 		running = at.isRunning
@@ -706,6 +959,10 @@ func (at *AutoTrader) runCycle() error {
 			logger.Infof("⏹ Trader stopped during decision execution, aborting remaining decisions")
 			break
 		}
+		if run.abortedCheckpoint(at.name, "during decision execution, remaining decisions skipped") {
+			record.ExecutionLog = append(record.ExecutionLog, "⏱️ Cycle deadline exceeded, remaining decisions skipped")
+			break
+		}
 
 		actionRecord := store.DecisionAction{
 			Action:     d.Action,
@@ -721,13 +978,14 @@ func (at *AutoTrader) runCycle() error {
 			Success:    false,
 		}
 
-		if err := at.executeDecisionWithRecord(&d, &actionRecord); err != nil {
+		if err := at.executeDecisionWithRecord(&d, &actionRecord, at.clientOrderKey(i)); err != nil {
 			logger.Infof("❌ Failed to execute decision (%s %s): %v", d.Symbol, d.Action, err)
 			actionRecord.Error = err.Error()
 			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("❌ %s %s failed: %v", d.Symbol, d.Action, err))
 		} else {
 			actionRecord.Success = true
 			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("✓ %s %s succeeded", d.Symbol, d.Action))
+			at.publishDecision(&d, ctx.Account.TotalEquity)
 			// Brief delay after successful execution
 			time.Sleep(1 * time.Second)
 		}
@@ -746,7 +1004,7 @@ func (at *AutoTrader) runCycle() error {
 // buildTradingContext builds trading context
 func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 	// 1. Get account information
-	balance, err := at.trader.GetBalance()
+	balance, err := exchangeCall(at, "Get balance", false, at.trader.GetBalance)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get account balance: %w", err)
 	}
@@ -775,8 +1033,13 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 		totalEquity = totalWalletBalance + totalUnrealizedProfit
 	}
 
+	// Multi-asset margin: every collateral asset counts, at its USD value
+	if collateral := at.collateralInUSD(); collateral != nil && collateral.multiAssetMargin {
+		availableBalance, totalEquity = collateral.margining("")
+	}
+
 	// 2. Get position information
-	positions, err := at.trader.GetPositions()
+	positions, err := exchangeCall(at, "Get positions", false, at.trader.GetPositions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get positions: %w", err)
 	}
@@ -810,7 +1073,7 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 		if lev, ok := pos["leverage"].(float64); ok {
 			leverage = int(lev)
 		}
-		marginUsed := (quantity * markPrice) / float64(leverage)
+		marginUsed := at.positionMargin(quantity, markPrice, leverage)
 		totalMarginUsed += marginUsed
 
 		// Calculate P&L percentage (based on margin, considering leverage)
@@ -848,6 +1111,8 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 		peakPnlPct := at.peakPnLCache[posKey]
 		at.peakPnLCacheMutex.RUnlock()
 
+		fundingRate, nextFundingTime := positionFunding(symbol)
+
 		positionInfos = append(positionInfos, kernel.PositionInfo{
 			Symbol:           symbol,
 			Side:             side,
@@ -861,6 +1126,8 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 			LiquidationPrice: liquidationPrice,
 			MarginUsed:       marginUsed,
 			UpdateTime:       updateTime,
+			FundingRate:      fundingRate,
+			NextFundingTime:  nextFundingTime,
 		})
 	}
 
@@ -881,17 +1148,13 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 			// Log warning but don't fail - equity snapshot should still be saved
 			logger.Infof("⚠️ [%s] Failed to get candidate coins: %v (will use empty list)", at.name, err)
 		} else {
-			candidateCoins = coins
+			candidateCoins = at.filterDisallowedCandidates(at.filterRestrictedCandidates(coins))
 			logger.Infof("📋 [%s] Strategy engine fetched candidate coins: %d", at.name, len(candidateCoins))
 		}
 	}
 
-	// 4. Calculate total P&L
-	totalPnL := totalEquity - at.initialBalance
-	totalPnLPct := 0.0
-	if at.initialBalance > 0 {
-		totalPnLPct = (totalPnL / at.initialBalance) * 100
-	}
+	// 4. Calculate total P&L (deposits/withdrawals excluded)
+	totalPnL, totalPnLPct := at.adjustedPnL(totalEquity)
 
 	marginUsedPct := 0.0
 	if totalEquity > 0 {
@@ -923,6 +1186,11 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 		},
 		Positions:      positionInfos,
 		CandidateCoins: candidateCoins,
+		PendingOrders:  at.pendingOrdersForContext(),
+		Exchange:       at.exchange,
+	}
+	if ctx.Contract, err = at.contractInfo(); err != nil {
+		return nil, err
 	}
 
 	// 7. Add recent closed trades (if store is available)
@@ -957,6 +1225,8 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 				})
 			}
 		}
+		// The AI's reviews of its recent closed trades (empty unless reflections are enabled)
+		ctx.Reflections = at.recentReflections()
 		// Get trading statistics for AI context
 		stats, err := at.store.Position().GetFullStats(at.id)
 		if err != nil {
@@ -1004,7 +1274,36 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 		logger.Infof("📊 [%s] Successfully fetched quantitative data for %d symbols", at.name, len(ctx.QuantDataMap))
 	}
 
-	// 9. Get OI ranking data (market-wide position changes)
+	// Order book liquidity of candidate coins (spread, depth, imbalance)
+	if strategyConfig.Indicators.EnableOrderBook {
+		symbols := make([]string, 0, len(candidateCoins))
+		for _, coin := range candidateCoins {
+			symbols = append(symbols, coin.Symbol)
+		}
+		ctx.OrderBookMap = at.strategyEngine.FetchOrderBookBatch(symbols)
+		logger.Infof("📚 [%s] Order book depth ready for %d/%d symbols", at.name, len(ctx.OrderBookMap), len(symbols))
+	}
+
+	// Headlines and upcoming macro events (shown in the prompt and/or gating entries)
+	ctx.News = at.newsSnapshot(strategyConfig)
+	if strategyConfig.Indicators.EnableSentiment && at.sentimentService != nil {
+		ctx.Sentiment = at.sentimentService.Snapshot()
+	}
+
+	// 9. Get market regimes (leverage/position caps adapt to trending/ranging/volatile markets)
+	if at.regimeService != nil {
+		regimeSymbols := make([]string, 0, len(candidateCoins)+len(positionInfos))
+		for _, coin := range candidateCoins {
+			regimeSymbols = append(regimeSymbols, coin.Symbol)
+		}
+		for _, pos := range positionInfos {
+			regimeSymbols = append(regimeSymbols, pos.Symbol)
+		}
+		ctx.Regimes = at.regimeService.GetAll(regimeSymbols)
+		logger.Infof("🧭 [%s] Market regimes ready for %d symbols", at.name, len(ctx.Regimes))
+	}
+
+	// 10. Get OI ranking data (market-wide position changes)
 	if strategyConfig.Indicators.EnableOIRanking {
 		logger.Infof("📊 [%s] Fetching OI ranking data...", at.name)
 		ctx.OIRankingData = at.strategyEngine.FetchOIRankingData()
@@ -1014,7 +1313,7 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 		}
 	}
 
-	// 10. Get NetFlow ranking data (market-wide fund flow)
+	// 11. Get NetFlow ranking data (market-wide fund flow)
 	if strategyConfig.Indicators.EnableNetFlowRanking {
 		logger.Infof("💰 [%s] Fetching NetFlow ranking data...", at.name)
 		ctx.NetFlowRankingData = at.strategyEngine.FetchNetFlowRankingData()
@@ -1024,7 +1323,7 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 		}
 	}
 
-	// 11. Get Price ranking data (market-wide gainers/losers)
+	// 12. Get Price ranking data (market-wide gainers/losers)
 	if strategyConfig.Indicators.EnablePriceRanking {
 		logger.Infof("📈 [%s] Fetching Price ranking data...", at.name)
 		ctx.PriceRankingData = at.strategyEngine.FetchPriceRankingData()
@@ -1038,12 +1337,20 @@ func (at *AutoTrader) buildTradingContext() (*kernel.Context, error) {
 }
 
 // executeDecisionWithRecord executes AI decision and records detailed information
-func (at *AutoTrader) executeDecisionWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction) error {
+// key identifies open orders for idempotent submission (zero key: no client order ID)
+func (at *AutoTrader) executeDecisionWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction, key ClientOrderKey) error {
+	// The kill switch may be engaged while the AI is deciding, closing stays allowed
+	if msg := at.killSwitchReason(); msg != "" && decision.IsOpen() {
+		return fmt.Errorf("❌ %s", msg)
+	}
+
 	switch decision.Action {
 	case "open_long":
-		return at.executeOpenLongWithRecord(decision, actionRecord)
+		return at.executeOpenLongWithRecord(decision, actionRecord, key)
 	case "open_short":
-		return at.executeOpenShortWithRecord(decision, actionRecord)
+		return at.executeOpenShortWithRecord(decision, actionRecord, key)
+	case "open_long_limit", "open_short_limit":
+		return at.executeOpenLimitWithRecord(decision, actionRecord)
 	case "close_long":
 		return at.executeCloseLongWithRecord(decision, actionRecord)
 	case "close_short":
@@ -1073,18 +1380,23 @@ func (at *AutoTrader) ExecuteDecision(d *kernel.Decision) error {
 	}
 
 	// Execute the decision
-	err := at.executeDecisionWithRecord(d, actionRecord)
+	err := at.executeDecisionWithRecord(d, actionRecord, ClientOrderKey{})
 	if err != nil {
 		logger.Errorf("[%s] External decision execution failed: %v", at.name, err)
 		return err
 	}
+	if at.decisionBus != nil {
+		if balance, err := at.trader.GetBalance(); err == nil {
+			at.publishDecision(d, equityFromBalance(balance))
+		}
+	}
 
 	logger.Infof("[%s] External decision executed successfully: %s %s", at.name, d.Action, d.Symbol)
 	return nil
 }
 
 // executeOpenLongWithRecord executes open long position and records detailed information
-func (at *AutoTrader) executeOpenLongWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction) error {
+func (at *AutoTrader) executeOpenLongWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction, key ClientOrderKey) error {
 	logger.Infof("  📈 Open long: %s", decision.Symbol)
 
 	// ⚠️ Get current positions for multiple checks
@@ -1104,6 +1416,9 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *kernel.Decision, actio
 			return fmt.Errorf("❌ %s already has long position, close it first", decision.Symbol)
 		}
 	}
+	if at.activeSplitEntry(decision.Symbol, "long") != nil {
+		return fmt.Errorf("❌ %s long entry is still being executed", decision.Symbol)
+	}
 
 	// Get current price
 	marketData, err := market.GetWithExchange(decision.Symbol, at.exchange)
@@ -1131,27 +1446,27 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *kernel.Decision, actio
 		equity = availableBalance // Fallback to available balance
 	}
 
+	// Inverse contracts: balances are in the margin coin, position sizes in USD
+	availableBalance, equity, err = at.balancesInUSD(availableBalance, equity)
+	if err != nil {
+		return err
+	}
+	// Collateral other than USDT (USDC/BUSD, multi-asset margin): size from its USD value
+	if collateral := at.collateralInUSD(); collateral != nil {
+		availableBalance, equity = collateral.margining(decision.Symbol)
+	}
+
 	// [CODE ENFORCED] Position Value Ratio Check: position_value <= equity × ratio
 	adjustedPositionSize, wasCapped := at.enforcePositionValueRatio(decision.PositionSizeUSD, equity, decision.Symbol)
 	if wasCapped {
 		decision.PositionSizeUSD = adjustedPositionSize
 	}
 
-	// ⚠️ Auto-adjust position size if insufficient margin
-	// Formula: totalRequired = positionSize/leverage + positionSize*0.001 + positionSize/leverage*0.01
-	//        = positionSize * (1.01/leverage + 0.001)
-	marginFactor := 1.01/float64(decision.Leverage) + 0.001
-	maxAffordablePositionSize := availableBalance / marginFactor
-
-	actualPositionSize := decision.PositionSizeUSD
-	if actualPositionSize > maxAffordablePositionSize {
-		// Use 98% of max to leave buffer for price fluctuation
-		adjustedSize := maxAffordablePositionSize * 0.98
-		logger.Infof("  ⚠️ Position size %.2f exceeds max affordable %.2f, auto-reducing to %.2f",
-			actualPositionSize, maxAffordablePositionSize, adjustedSize)
-		actualPositionSize = adjustedSize
-		decision.PositionSizeUSD = actualPositionSize
+	// [CODE ENFORCED] Margin check: downsize to what the available balance covers, or skip
+	if err := at.fitToMargin(decision, "long", availableBalance); err != nil {
+		return err
 	}
+	actualPositionSize := decision.PositionSizeUSD
 
 	// [CODE ENFORCED] Minimum position size check
 	if err := at.enforceMinPositionSize(decision.PositionSizeUSD); err != nil {
@@ -1169,8 +1484,22 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *kernel.Decision, actio
 		// Continue execution, doesn't affect trading
 	}
 
-	// Open position
-	order, err := at.trader.OpenLong(decision.Symbol, quantity, decision.Leverage)
+	// Large entries may be executed as child orders (TWAP/iceberg)
+	if algo, slices, duration := at.splitPlan(decision); algo != "" {
+		return at.startSplitEntry(algo, slices, duration, "long", decision, quantity, marketData.CurrentPrice, actionRecord, key)
+	}
+
+	// Partial take profits replace the single take profit, the entry is only protected by its stop loss
+	protected := decision
+	ladder := at.newTakeProfitLadder(decision, "LONG", quantity, marketData.CurrentPrice)
+	if ladder != nil {
+		withoutTP := *decision
+		withoutTP.TakeProfit = 0
+		protected = &withoutTP
+	}
+
+	// Open position (with stop loss/take profit attached where the exchange supports brackets)
+	order, bracketed, err := at.submitOpenWithProtection("long", protected, quantity, key)
 	if err != nil {
 		return err
 	}
@@ -1183,25 +1512,27 @@ func (at *AutoTrader) executeOpenLongWithRecord(decision *kernel.Decision, actio
 	logger.Infof("  ✓ Position opened successfully, order ID: %v, quantity: %.4f", order["orderId"], quantity)
 
 	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "open_long", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	fill := at.recordAndConfirmOrder(order, decision.Symbol, "open_long", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	recordExecution(actionRecord, fill, true)
 
 	// Record position opening time
 	posKey := decision.Symbol + "_long"
 	at.positionFirstSeenTime[posKey] = time.Now().UnixMilli()
 
-	// Set stop loss and take profit
-	if err := at.trader.SetStopLoss(decision.Symbol, "LONG", quantity, decision.StopLoss); err != nil {
-		logger.Infof("  ⚠ Failed to set stop loss: %v", err)
+	// Set stop loss and take profit (one-cancels-other), closing the position if the stop loss fails
+	if !bracketed {
+		if err := at.protectOrClose(decision.Symbol, "LONG", quantity, protected.StopLoss, protected.TakeProfit); err != nil {
+			return err
+		}
 	}
-	if err := at.trader.SetTakeProfit(decision.Symbol, "LONG", quantity, decision.TakeProfit); err != nil {
-		logger.Infof("  ⚠ Failed to set take profit: %v", err)
+	if ladder != nil {
+		at.placeTakeProfitLadder(ladder)
 	}
-
 	return nil
 }
 
 // executeOpenShortWithRecord executes open short position and records detailed information
-func (at *AutoTrader) executeOpenShortWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction) error {
+func (at *AutoTrader) executeOpenShortWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction, key ClientOrderKey) error {
 	logger.Infof("  📉 Open short: %s", decision.Symbol)
 
 	// ⚠️ Get current positions for multiple checks
@@ -1221,6 +1552,9 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *kernel.Decision, acti
 			return fmt.Errorf("❌ %s already has short position, close it first", decision.Symbol)
 		}
 	}
+	if at.activeSplitEntry(decision.Symbol, "short") != nil {
+		return fmt.Errorf("❌ %s short entry is still being executed", decision.Symbol)
+	}
 
 	// Get current price
 	marketData, err := market.GetWithExchange(decision.Symbol, at.exchange)
@@ -1248,27 +1582,27 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *kernel.Decision, acti
 		equity = availableBalance // Fallback to available balance
 	}
 
+	// Inverse contracts: balances are in the margin coin, position sizes in USD
+	availableBalance, equity, err = at.balancesInUSD(availableBalance, equity)
+	if err != nil {
+		return err
+	}
+	// Collateral other than USDT (USDC/BUSD, multi-asset margin): size from its USD value
+	if collateral := at.collateralInUSD(); collateral != nil {
+		availableBalance, equity = collateral.margining(decision.Symbol)
+	}
+
 	// [CODE ENFORCED] Position Value Ratio Check: position_value <= equity × ratio
 	adjustedPositionSize, wasCapped := at.enforcePositionValueRatio(decision.PositionSizeUSD, equity, decision.Symbol)
 	if wasCapped {
 		decision.PositionSizeUSD = adjustedPositionSize
 	}
 
-	// ⚠️ Auto-adjust position size if insufficient margin
-	// Formula: totalRequired = positionSize/leverage + positionSize*0.001 + positionSize/leverage*0.01
-	//        = positionSize * (1.01/leverage + 0.001)
-	marginFactor := 1.01/float64(decision.Leverage) + 0.001
-	maxAffordablePositionSize := availableBalance / marginFactor
-
-	actualPositionSize := decision.PositionSizeUSD
-	if actualPositionSize > maxAffordablePositionSize {
-		// Use 98% of max to leave buffer for price fluctuation
-		adjustedSize := maxAffordablePositionSize * 0.98
-		logger.Infof("  ⚠️ Position size %.2f exceeds max affordable %.2f, auto-reducing to %.2f",
-			actualPositionSize, maxAffordablePositionSize, adjustedSize)
-		actualPositionSize = adjustedSize
-		decision.PositionSizeUSD = actualPositionSize
+	// [CODE ENFORCED] Margin check: downsize to what the available balance covers, or skip
+	if err := at.fitToMargin(decision, "short", availableBalance); err != nil {
+		return err
 	}
+	actualPositionSize := decision.PositionSizeUSD
 
 	// [CODE ENFORCED] Minimum position size check
 	if err := at.enforceMinPositionSize(decision.PositionSizeUSD); err != nil {
@@ -1286,8 +1620,22 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *kernel.Decision, acti
 		// Continue execution, doesn't affect trading
 	}
 
-	// Open position
-	order, err := at.trader.OpenShort(decision.Symbol, quantity, decision.Leverage)
+	// Large entries may be executed as child orders (TWAP/iceberg)
+	if algo, slices, duration := at.splitPlan(decision); algo != "" {
+		return at.startSplitEntry(algo, slices, duration, "short", decision, quantity, marketData.CurrentPrice, actionRecord, key)
+	}
+
+	// Partial take profits replace the single take profit, the entry is only protected by its stop loss
+	protected := decision
+	ladder := at.newTakeProfitLadder(decision, "SHORT", quantity, marketData.CurrentPrice)
+	if ladder != nil {
+		withoutTP := *decision
+		withoutTP.TakeProfit = 0
+		protected = &withoutTP
+	}
+
+	// Open position (with stop loss/take profit attached where the exchange supports brackets)
+	order, bracketed, err := at.submitOpenWithProtection("short", protected, quantity, key)
 	if err != nil {
 		return err
 	}
@@ -1300,20 +1648,22 @@ func (at *AutoTrader) executeOpenShortWithRecord(decision *kernel.Decision, acti
 	logger.Infof("  ✓ Position opened successfully, order ID: %v, quantity: %.4f", order["orderId"], quantity)
 
 	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "open_short", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	fill := at.recordAndConfirmOrder(order, decision.Symbol, "open_short", quantity, marketData.CurrentPrice, decision.Leverage, 0)
+	recordExecution(actionRecord, fill, false)
 
 	// Record position opening time
 	posKey := decision.Symbol + "_short"
 	at.positionFirstSeenTime[posKey] = time.Now().UnixMilli()
 
-	// Set stop loss and take profit
-	if err := at.trader.SetStopLoss(decision.Symbol, "SHORT", quantity, decision.StopLoss); err != nil {
-		logger.Infof("  ⚠ Failed to set stop loss: %v", err)
+	// Set stop loss and take profit (one-cancels-other), closing the position if the stop loss fails
+	if !bracketed {
+		if err := at.protectOrClose(decision.Symbol, "SHORT", quantity, protected.StopLoss, protected.TakeProfit); err != nil {
+			return err
+		}
 	}
-	if err := at.trader.SetTakeProfit(decision.Symbol, "SHORT", quantity, decision.TakeProfit); err != nil {
-		logger.Infof("  ⚠ Failed to set take profit: %v", err)
+	if ladder != nil {
+		at.placeTakeProfitLadder(ladder)
 	}
-
 	return nil
 }
 
@@ -1363,8 +1713,11 @@ func (at *AutoTrader) executeCloseLongWithRecord(decision *kernel.Decision, acti
 		logger.Infof("  📊 Using exchange position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
 	}
 
-	// Close position
-	order, err := at.trader.CloseLong(decision.Symbol, 0) // 0 = close all
+	// Close position (the close is reported here, not by the drawdown monitor)
+	at.untrackPosition(decision.Symbol, "long")
+	order, err := exchangeCall(at, "Close long "+decision.Symbol, true, func() (map[string]interface{}, error) {
+		return at.trader.CloseLong(decision.Symbol, 0) // 0 = close all
+	})
 	if err != nil {
 		return err
 	}
@@ -1375,7 +1728,14 @@ func (at *AutoTrader) executeCloseLongWithRecord(decision *kernel.Decision, acti
 	}
 
 	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "close_long", quantity, marketData.CurrentPrice, 0, entryPrice)
+	fill := at.recordAndConfirmOrder(order, decision.Symbol, "close_long", quantity, marketData.CurrentPrice, 0, entryPrice)
+	recordExecution(actionRecord, fill, false)
+	exitPrice := marketData.CurrentPrice
+	if actionRecord.FillPrice > 0 {
+		exitPrice = actionRecord.FillPrice
+	}
+	at.untrackPosition(decision.Symbol, "long")
+	at.recordPositionClose(trackedPosition{Symbol: decision.Symbol, Side: "long", EntryPrice: entryPrice, Quantity: quantity}, exitPrice, "ai")
 
 	logger.Infof("  ✓ Position closed successfully")
 	return nil
@@ -1427,8 +1787,11 @@ func (at *AutoTrader) executeCloseShortWithRecord(decision *kernel.Decision, act
 		logger.Infof("  📊 Using exchange position data: qty=%.8f, entry=%.2f", quantity, entryPrice)
 	}
 
-	// Close position
-	order, err := at.trader.CloseShort(decision.Symbol, 0) // 0 = close all
+	// Close position (the close is reported here, not by the drawdown monitor)
+	at.untrackPosition(decision.Symbol, "short")
+	order, err := exchangeCall(at, "Close short "+decision.Symbol, true, func() (map[string]interface{}, error) {
+		return at.trader.CloseShort(decision.Symbol, 0) // 0 = close all
+	})
 	if err != nil {
 		return err
 	}
@@ -1439,7 +1802,14 @@ func (at *AutoTrader) executeCloseShortWithRecord(decision *kernel.Decision, act
 	}
 
 	// Record order to database and poll for confirmation
-	at.recordAndConfirmOrder(order, decision.Symbol, "close_short", quantity, marketData.CurrentPrice, 0, entryPrice)
+	fill := at.recordAndConfirmOrder(order, decision.Symbol, "close_short", quantity, marketData.CurrentPrice, 0, entryPrice)
+	recordExecution(actionRecord, fill, true)
+	exitPrice := marketData.CurrentPrice
+	if actionRecord.FillPrice > 0 {
+		exitPrice = actionRecord.FillPrice
+	}
+	at.untrackPosition(decision.Symbol, "short")
+	at.recordPositionClose(trackedPosition{Symbol: decision.Symbol, Side: "short", EntryPrice: entryPrice, Quantity: quantity}, exitPrice, "ai")
 
 	logger.Infof("  ✓ Position closed successfully")
 	return nil
@@ -1450,6 +1820,11 @@ func (at *AutoTrader) GetID() string {
 	return at.id
 }
 
+// GetUserID returns the ID of the user owning this trader
+func (at *AutoTrader) GetUserID() string {
+	return at.userID
+}
+
 // GetUnderlyingTrader returns the underlying Trader interface implementation
 // This is used by grid trading and other components that need direct exchange access
 func (at *AutoTrader) GetUnderlyingTrader() Trader {
@@ -1476,11 +1851,28 @@ func (at *AutoTrader) GetShowInCompetition() bool {
 	return at.showInCompetition
 }
 
+// IsPaperMode returns whether orders are simulated instead of sent to the exchange
+func (at *AutoTrader) IsPaperMode() bool {
+	return at.paperMode
+}
+
+// IsRunning returns whether the trader's decision loop is running
+func (at *AutoTrader) IsRunning() bool {
+	at.isRunningMutex.RLock()
+	defer at.isRunningMutex.RUnlock()
+	return at.isRunning
+}
+
 // SetShowInCompetition sets whether trader should be shown in competition
 func (at *AutoTrader) SetShowInCompetition(show bool) {
 	at.showInCompetition = show
 }
 
+// SetRegimeService sets the market regime source used to scale leverage/position caps
+func (at *AutoTrader) SetRegimeService(regimes *market.RegimeService) {
+	at.regimeService = regimes
+}
+
 // SetCustomPrompt sets custom trading strategy prompt
 func (at *AutoTrader) SetCustomPrompt(prompt string) {
 	at.customPrompt = prompt
@@ -1495,6 +1887,9 @@ func (at *AutoTrader) SetOverrideBasePrompt(override bool) {
 func (at *AutoTrader) GetSystemPromptTemplate() string {
 	if at.strategyEngine != nil {
 		config := at.strategyEngine.GetConfig()
+		if config.TemplatePrompt != "" {
+			return "template"
+		}
 		if config.CustomPrompt != "" {
 			return "custom"
 		}
@@ -1504,7 +1899,8 @@ func (at *AutoTrader) GetSystemPromptTemplate() string {
 
 // saveEquitySnapshot saves equity snapshot independently (for drawing profit curve, decoupled from AI decision)
 func (at *AutoTrader) saveEquitySnapshot(ctx *kernel.Context) {
-	if at.store == nil || ctx == nil {
+	// Paper equity is derived from paper_positions, keep the real equity curve clean
+	if at.store == nil || ctx == nil || at.paperMode {
 		return
 	}
 
@@ -1543,6 +1939,7 @@ func (at *AutoTrader) saveDecision(record *store.DecisionRecord) error {
 	}
 
 	logger.Infof("📝 Decision record saved: trader=%s, cycle=%d", at.id, at.cycleNumber)
+	at.recordDecisionEvent(record)
 	return nil
 }
 
@@ -1576,6 +1973,7 @@ func (at *AutoTrader) GetStatus() map[string]interface{} {
 		"stop_until":      at.stopUntil.Format(time.RFC3339),
 		"last_reset_time": at.lastResetTime.Format(time.RFC3339),
 		"ai_provider":     aiProvider,
+		"paper_mode":      at.paperMode,
 	}
 
 	// Add strategy info
@@ -1585,6 +1983,9 @@ func (at *AutoTrader) GetStatus() map[string]interface{} {
 			result["grid_symbol"] = at.config.StrategyConfig.GridConfig.Symbol
 		}
 	}
+	if leaderID := at.copyLeaderID(); leaderID != "" {
+		result["copy_leader_id"] = leaderID
+	}
 
 	return result
 }
@@ -1620,6 +2021,12 @@ func (at *AutoTrader) GetAccountInfo() (map[string]interface{}, error) {
 		totalEquity = totalWalletBalance + totalUnrealizedProfit
 	}
 
+	// Collateral other than USDT: report it by asset, multi-asset margin counts all of it
+	collateral := at.collateralInUSD()
+	if collateral != nil && collateral.multiAssetMargin {
+		availableBalance, totalEquity = collateral.margining("")
+	}
+
 	// Get positions to calculate total margin
 	positions, err := at.trader.GetPositions()
 	if err != nil {
@@ -1641,7 +2048,7 @@ func (at *AutoTrader) GetAccountInfo() (map[string]interface{}, error) {
 		if lev, ok := pos["leverage"].(float64); ok {
 			leverage = int(lev)
 		}
-		marginUsed := (quantity * markPrice) / float64(leverage)
+		marginUsed := at.positionMargin(quantity, markPrice, leverage)
 		totalMarginUsed += marginUsed
 	}
 
@@ -1653,11 +2060,8 @@ func (at *AutoTrader) GetAccountInfo() (map[string]interface{}, error) {
 			totalUnrealizedProfit, totalUnrealizedPnLCalculated, diff)
 	}
 
-	totalPnL := totalEquity - at.initialBalance
-	totalPnLPct := 0.0
-	if at.initialBalance > 0 {
-		totalPnLPct = (totalPnL / at.initialBalance) * 100
-	} else {
+	totalPnL, totalPnLPct := at.adjustedPnL(totalEquity)
+	if at.initialBalance <= 0 {
 		logger.Infof("⚠️ Initial Balance abnormal: %.2f, cannot calculate P&L percentage", at.initialBalance)
 	}
 
@@ -1666,7 +2070,7 @@ func (at *AutoTrader) GetAccountInfo() (map[string]interface{}, error) {
 		marginUsedPct = (totalMarginUsed / totalEquity) * 100
 	}
 
-	return map[string]interface{}{
+	account := map[string]interface{}{
 		// Core fields
 		"total_equity":      totalEquity,           // Account equity = wallet + unrealized
 		"wallet_balance":    totalWalletBalance,    // Wallet balance (excluding unrealized P&L)
@@ -1683,7 +2087,15 @@ func (at *AutoTrader) GetAccountInfo() (map[string]interface{}, error) {
 		"position_count":  len(positions),  // Position count
 		"margin_used":     totalMarginUsed, // Margin used
 		"margin_used_pct": marginUsedPct,   // Margin usage rate
-	}, nil
+	}
+	if inverse := at.inverseTrader(); inverse != nil {
+		account["margin_asset"] = inverse.MarginAsset() // Amounts above are in this coin
+	}
+	if collateral != nil {
+		account["collateral"] = collateral.report()
+		account["multi_asset_margin"] = collateral.multiAssetMargin
+	}
+	return account, nil
 }
 
 // GetPositions gets position list (for API)
@@ -1712,7 +2124,7 @@ func (at *AutoTrader) GetPositions() ([]map[string]interface{}, error) {
 		}
 
 		// Calculate margin used
-		marginUsed := (quantity * markPrice) / float64(leverage)
+		marginUsed := at.positionMargin(quantity, markPrice, leverage)
 
 		// Calculate P&L percentage (based on margin)
 		pnlPct := calculatePnLPercentage(unrealizedPnl, marginUsed)
@@ -1755,7 +2167,7 @@ func sortDecisionsByPriority(decisions []kernel.Decision) []kernel.Decision {
 		switch action {
 		case "close_long", "close_short":
 			return 1 // Highest priority: close positions first
-		case "open_long", "open_short":
+		case "open_long", "open_short", "open_long_limit", "open_short_limit":
 			return 2 // Second priority: open positions later
 		case "hold", "wait":
 			return 3 // Lowest priority: wait
@@ -1811,6 +2223,7 @@ func (at *AutoTrader) checkPositionDrawdown() {
 		logger.Infof("❌ Drawdown monitoring: failed to get positions: %v", err)
 		return
 	}
+	at.trackPositionCloses(positions)
 
 	for _, pos := range positions {
 		symbol := pos["symbol"].(string)
@@ -1822,6 +2235,8 @@ func (at *AutoTrader) checkPositionDrawdown() {
 			quantity = -quantity // Short position quantity is negative, convert to positive
 		}
 
+		at.checkLiquidationRisk(pos)
+
 		// Calculate current P&L percentage
 		leverage := 10 // Default value
 		if lev, ok := pos["leverage"].(float64); ok {
@@ -1868,6 +2283,8 @@ func (at *AutoTrader) checkPositionDrawdown() {
 				logger.Infof("❌ Drawdown close position failed (%s %s): %v", symbol, side, err)
 			} else {
 				logger.Infof("✅ Drawdown close position succeeded: %s %s", symbol, side)
+				at.untrackPosition(symbol, side)
+				at.recordPositionClose(trackedPosition{Symbol: symbol, Side: side, EntryPrice: entryPrice, Quantity: quantity}, markPrice, "drawdown")
 				// Clear cache for this position after closing
 				at.ClearPeakPnLCache(symbol, side)
 			}
@@ -1943,27 +2360,17 @@ func (at *AutoTrader) ClearPeakPnLCache(symbol, side string) {
 // recordAndConfirmOrder polls order status for actual fill data and records position
 // action: open_long, open_short, close_long, close_short
 // entryPrice: entry price when closing (0 when opening)
-func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{}, symbol, action string, quantity float64, price float64, leverage int, entryPrice float64) {
-	if at.store == nil {
-		return
-	}
-
-	// Get order ID (supports multiple types)
-	var orderID string
-	switch v := orderResult["orderId"].(type) {
-	case int64:
-		orderID = fmt.Sprintf("%d", v)
-	case float64:
-		orderID = fmt.Sprintf("%.0f", v)
-	case string:
-		orderID = v
-	default:
-		orderID = fmt.Sprintf("%v", v)
-	}
-
+// Returns the fill data for the decision's execution trace (nil without an order ID)
+func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{}, symbol, action string, quantity float64, price float64, leverage int, entryPrice float64) *orderFill {
+	orderID := orderIDString(orderResult)
 	if orderID == "" || orderID == "0" {
 		logger.Infof("  ⚠️ Order ID is empty, skipping record")
-		return
+		return nil
+	}
+
+	// Paper fills are recorded in paper_positions by the paper trader
+	if at.store == nil || at.paperMode {
+		return at.pollOrderFill(symbol, orderID)
 	}
 
 	// Determine positionSide
@@ -1975,16 +2382,12 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 		positionSide = "SHORT"
 	}
 
-	var actualPrice = price
-	var actualQty = quantity
-	var fee float64
-
 	// Exchanges with OrderSync: Skip immediate order recording, let OrderSync handle it
 	// This ensures accurate data from GetTrades API and avoids duplicate records
-	switch at.exchange {
-	case "binance", "lighter", "hyperliquid", "bybit", "okx", "bitget", "aster", "kucoin", "gate":
+	if HasOrderSync(at.exchange) {
 		logger.Infof("  📝 Order submitted (id: %s), will be synced by OrderSync", orderID)
-		return
+		// Fill data is still polled for the decision's execution trace
+		return at.pollOrderFill(symbol, orderID)
 	}
 
 	// For exchanges without OrderSync (e.g., Binance): record immediately and poll for fill data
@@ -1996,46 +2399,36 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 	}
 
 	// Wait for order to be filled and get actual fill data
-	time.Sleep(500 * time.Millisecond)
-	for i := 0; i < 5; i++ {
-		status, err := at.trader.GetOrderStatus(symbol, orderID)
-		if err == nil {
-			statusStr, _ := status["status"].(string)
-			if statusStr == "FILLED" {
-				// Get actual fill price
-				if avgPrice, ok := status["avgPrice"].(float64); ok && avgPrice > 0 {
-					actualPrice = avgPrice
-				}
-				// Get actual executed quantity
-				if execQty, ok := status["executedQty"].(float64); ok && execQty > 0 {
-					actualQty = execQty
-				}
-				// Get commission/fee
-				if commission, ok := status["commission"].(float64); ok {
-					fee = commission
-				}
-				logger.Infof("  ✅ Order filled: avgPrice=%.6f, qty=%.6f, fee=%.6f", actualPrice, actualQty, fee)
+	var actualPrice = price
+	var actualQty = quantity
+	fill := at.pollOrderFill(symbol, orderID)
+	switch fill.Status {
+	case "FILLED":
+		if fill.AvgPrice > 0 {
+			actualPrice = fill.AvgPrice
+		}
+		if fill.Quantity > 0 {
+			actualQty = fill.Quantity
+		}
+		logger.Infof("  ✅ Order filled: avgPrice=%.6f, qty=%.6f, fee=%.6f", actualPrice, actualQty, fill.Fee)
 
-				// Update order status to FILLED
-				if err := at.store.Order().UpdateOrderStatus(orderRecord.ID, "FILLED", actualQty, actualPrice, fee); err != nil {
-					logger.Infof("  ⚠️ Failed to update order status: %v", err)
-				}
+		// Update order status to FILLED
+		if err := at.store.Order().UpdateOrderStatus(orderRecord.ID, "FILLED", actualQty, actualPrice, fill.Fee); err != nil {
+			logger.Infof("  ⚠️ Failed to update order status: %v", err)
+		}
 
-				// Record fill details
-				at.recordOrderFill(orderRecord.ID, orderID, symbol, action, actualPrice, actualQty, fee)
-				break
-			} else if statusStr == "CANCELED" || statusStr == "EXPIRED" || statusStr == "REJECTED" {
-				logger.Infof("  ⚠️ Order %s, skipping position record", statusStr)
+		// Record fill details
+		at.recordOrderFill(orderRecord.ID, orderID, symbol, action, actualPrice, actualQty, fill.Fee)
+	case "CANCELED", "EXPIRED", "REJECTED":
+		logger.Infof("  ⚠️ Order %s, skipping position record", fill.Status)
 
-				// Update order status
-				if err := at.store.Order().UpdateOrderStatus(orderRecord.ID, statusStr, 0, 0, 0); err != nil {
-					logger.Infof("  ⚠️ Failed to update order status: %v", err)
-				}
-				return
-			}
+		// Update order status
+		if err := at.store.Order().UpdateOrderStatus(orderRecord.ID, fill.Status, 0, 0, 0); err != nil {
+			logger.Infof("  ⚠️ Failed to update order status: %v", err)
 		}
-		time.Sleep(500 * time.Millisecond)
+		return fill
 	}
+	fee := fill.Fee
 
 	// Normalize symbol for position record consistency
 	normalizedSymbolForPosition := market.Normalize(symbol)
@@ -2057,6 +2450,16 @@ func (at *AutoTrader) recordAndConfirmOrder(orderResult map[string]interface{},
 		UserID:    at.userID,
 		TraderID:  at.id,
 	})
+	return fill
+}
+
+// HasOrderSync reports whether the exchange's orders and positions are recorded by OrderSync
+func HasOrderSync(exchange string) bool {
+	switch exchange {
+	case "binance", "lighter", "hyperliquid", "bybit", "okx", "bitget", "aster", "kucoin", "gate":
+		return true
+	}
+	return false
 }
 
 // recordPositionChange records position change (create record on open, update record on close)
@@ -2067,6 +2470,16 @@ func (at *AutoTrader) recordPositionChange(orderID, symbol, side, action string,
 
 	switch action {
 	case "open_long", "open_short":
+		// Child orders of a split entry average into the position opened by the first one
+		if existing, err := at.store.Position().GetOpenPositionBySymbol(at.id, symbol, side); err == nil && existing != nil {
+			if err := at.store.Position().UpdatePositionQuantityAndPrice(existing.ID, quantity, price, fee); err != nil {
+				logger.Infof("  ⚠️ Failed to add to position: %v", err)
+			} else {
+				logger.Infof("  📊 Position increased [%s] %s %s +%.4f @ %.4f", at.id[:8], symbol, side, quantity, price)
+			}
+			return
+		}
+
 		// Open position: create new position record
 		nowMs := time.Now().UTC().UnixMilli()
 		pos := &store.TraderPosition{
@@ -2279,17 +2692,20 @@ func (at *AutoTrader) enforceMinPositionSize(positionSizeUSD float64) error {
 		return nil
 	}
 
-	minSize := at.config.StrategyConfig.RiskControl.MinPositionSize
-	if minSize <= 0 {
-		minSize = 12 // Default: 12 USDT
-	}
-
-	if positionSizeUSD < minSize {
+	if minSize := at.minPositionSize(); positionSizeUSD < minSize {
 		return fmt.Errorf("❌ [RISK CONTROL] Position %.2f USDT below minimum (%.2f USDT)", positionSizeUSD, minSize)
 	}
 	return nil
 }
 
+// minPositionSize smallest position the strategy opens, in USDT
+func (at *AutoTrader) minPositionSize() float64 {
+	if at.config.StrategyConfig != nil && at.config.StrategyConfig.RiskControl.MinPositionSize > 0 {
+		return at.config.StrategyConfig.RiskControl.MinPositionSize
+	}
+	return 12 // Default: 12 USDT
+}
+
 // enforceMaxPositions checks maximum positions count (CODE ENFORCED)
 func (at *AutoTrader) enforceMaxPositions(currentPositionCount int) error {
 	if at.config.StrategyConfig == nil {
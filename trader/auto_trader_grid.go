@@ -100,7 +100,7 @@ const (
 func (at *AutoTrader) checkBreakout() (BreakoutType, float64) {
 	gridConfig := at.config.StrategyConfig.GridConfig
 
-	currentPrice, err := at.trader.GetMarketPrice(gridConfig.Symbol)
+	currentPrice, err := at.gridMarketPrice(gridConfig.Symbol)
 	if err != nil {
 		return BreakoutNone, 0
 	}
@@ -138,21 +138,8 @@ func (at *AutoTrader) checkMaxDrawdown() (bool, float64) {
 	}
 
 	// Get current equity
-	balance, err := at.trader.GetBalance()
-	if err != nil {
-		return false, 0
-	}
-
-	currentEquity := 0.0
-	if equity, ok := balance["total_equity"].(float64); ok {
-		currentEquity = equity
-	} else if total, ok := balance["totalWalletBalance"].(float64); ok {
-		if unrealized, ok := balance["totalUnrealizedProfit"].(float64); ok {
-			currentEquity = total + unrealized
-		}
-	}
-
-	if currentEquity <= 0 {
+	currentEquity, err := at.gridEquity()
+	if err != nil || currentEquity <= 0 {
 		return false, 0
 	}
 
@@ -228,9 +215,12 @@ func (at *AutoTrader) emergencyExit(reason string) error {
 		logger.Errorf("[Grid] Failed to cancel orders in emergency: %v", err)
 	}
 
-	// Close all positions
-	positions, err := at.trader.GetPositions()
-	if err == nil {
+	// Close all positions (spot grids sell the coins they bought)
+	if at.isSpotGrid() {
+		if err := at.sellSpotGridInventory(); err != nil {
+			logger.Errorf("[Grid] Failed to sell spot inventory in emergency: %v", err)
+		}
+	} else if positions, err := at.trader.GetPositions(); err == nil {
 		for _, pos := range positions {
 			if sym, ok := pos["symbol"].(string); ok && sym == gridConfig.Symbol {
 				if size, ok := pos["positionAmt"].(float64); ok && size != 0 {
@@ -307,7 +297,7 @@ func (at *AutoTrader) checkBoxBreakout() error {
 	at.gridState.mu.Unlock()
 
 	// Detect breakout
-	breakoutLevel, direction := detectBoxBreakout(box)
+	breakoutLevel, direction := market.DetectBoxBreakout(box)
 
 	// Get current breakout state
 	state := &BreakoutState{
@@ -412,6 +402,9 @@ func (at *AutoTrader) closeAllPositions() error {
 	if gridConfig == nil {
 		return nil
 	}
+	if at.isSpotGrid() {
+		return at.sellSpotGridInventory()
+	}
 
 	positions, err := at.trader.GetPositions()
 	if err != nil {
@@ -511,10 +504,15 @@ func (at *AutoTrader) InitializeGrid() error {
 	}
 
 	gridConfig := at.config.StrategyConfig.GridConfig
+	if gridConfig.IsSpot() {
+		if _, err := at.spotGridTrader(); err != nil {
+			return err
+		}
+	}
 	at.gridState = NewGridState(gridConfig)
 
 	// Get current market price
-	price, err := at.trader.GetMarketPrice(gridConfig.Symbol)
+	price, err := at.gridMarketPrice(gridConfig.Symbol)
 	if err != nil {
 		return fmt.Errorf("failed to get market price: %w", err)
 	}
@@ -543,8 +541,10 @@ func (at *AutoTrader) InitializeGrid() error {
 
 	at.gridState.IsInitialized = true
 
-	// CRITICAL: Set leverage on exchange before trading
-	if err := at.trader.SetLeverage(gridConfig.Symbol, gridConfig.Leverage); err != nil {
+	// CRITICAL: Set leverage on exchange before trading (spot grids trade without leverage)
+	if gridConfig.IsSpot() {
+		logger.Infof("[Grid] Spot grid for %s, no leverage", gridConfig.Symbol)
+	} else if err := at.trader.SetLeverage(gridConfig.Symbol, gridConfig.Leverage); err != nil {
 		logger.Warnf("[Grid] Failed to set leverage %dx on exchange: %v", gridConfig.Leverage, err)
 		// Not fatal - continue with default leverage
 	} else {
@@ -744,7 +744,7 @@ func (at *AutoTrader) adjustGridDirection(newDirection market.GridDirection) err
 		oldDirection, newDirection, at.gridState.DirectionChangeCount)
 
 	// Get current price for recalculation
-	currentPrice, err := at.trader.GetMarketPrice(at.gridState.Config.Symbol)
+	currentPrice, err := at.gridMarketPrice(at.gridState.Config.Symbol)
 	if err != nil {
 		return fmt.Errorf("failed to get market price: %w", err)
 	}
@@ -766,12 +766,35 @@ func (at *AutoTrader) RunGridCycle() error {
 		return nil
 	}
 
+	if msg := at.killSwitchReason(); msg != "" {
+		logger.Infof("[Grid] %s, skipping grid cycle", msg)
+		return nil
+	}
+
+	// Reconfiguration waits for the cycle to finish
+	at.gridCycleMutex.Lock()
+	defer at.gridCycleMutex.Unlock()
+
 	if at.gridState == nil || !at.gridState.IsInitialized {
 		if err := at.InitializeGrid(); err != nil {
 			return fmt.Errorf("failed to initialize grid: %w", err)
 		}
 	}
 
+	// CRITICAL: Circuit breaker (shared with AI strategy) stops the trader entirely
+	if equity, err := at.gridEquity(); err == nil {
+		if reason := at.checkCircuitBreaker(equity); reason != "" {
+			if err := at.cancelAllGridOrders(); err != nil {
+				logger.Errorf("[Grid] Failed to cancel orders on circuit breaker trip: %v", err)
+			}
+			at.haltForCircuitBreaker()
+			return fmt.Errorf("circuit breaker tripped: %s", reason)
+		}
+	}
+
+	// Sync funding payments and commissions for net PnL stats (throttled)
+	at.syncIncomeHistory()
+
 	// CRITICAL: Check for breakout before executing any trades
 	breakoutType, breakoutPct := at.checkBreakout()
 	if breakoutType != BreakoutNone {
@@ -903,6 +926,12 @@ func (at *AutoTrader) buildGridContext() (*kernel.GridContext, error) {
 	}
 	at.gridState.mu.RUnlock()
 
+	// Spot grids report spot wallet holdings instead of futures margin and positions
+	if gridConfig.IsSpot() {
+		at.fillSpotGridContext(ctx)
+		return ctx, nil
+	}
+
 	// Get account info
 	balance, err := at.trader.GetBalance()
 	if err == nil {
@@ -954,9 +983,15 @@ func (at *AutoTrader) executeGridDecision(d *kernel.Decision) error {
 		return nil
 	// Support standard actions for closing positions
 	case "close_long":
+		if at.isSpotGrid() {
+			return at.sellSpotGridInventory()
+		}
 		_, err := at.trader.CloseLong(d.Symbol, d.Quantity)
 		return err
 	case "close_short":
+		if at.isSpotGrid() {
+			return fmt.Errorf("spot grids hold no short positions")
+		}
 		_, err := at.trader.CloseShort(d.Symbol, d.Quantity)
 		return err
 	default:
@@ -972,12 +1007,19 @@ func (at *AutoTrader) checkTotalPositionLimit(symbol string, additionalValue flo
 
 	// Calculate max allowed total position value
 	// Total position should not exceed: TotalInvestment × Leverage
-	maxTotalPositionValue := gridConfig.TotalInvestment * float64(gridConfig.Leverage)
+	maxTotalPositionValue := gridConfig.TotalInvestment * float64(at.gridLeverage())
 
-	// Get current position value from exchange
+	// Get current position value from exchange (spot: coins bought by the grid)
 	currentPositionValue := 0.0
-	positions, err := at.trader.GetPositions()
-	if err == nil {
+	if gridConfig.IsSpot() {
+		at.gridState.mu.RLock()
+		for _, level := range at.gridState.Levels {
+			if level.State == "filled" && level.Side == "buy" {
+				currentPositionValue += level.PositionSize * level.PositionEntry
+			}
+		}
+		at.gridState.mu.RUnlock()
+	} else if positions, err := at.trader.GetPositions(); err == nil {
 		for _, pos := range positions {
 			if sym, ok := pos["symbol"].(string); ok && sym == symbol {
 				if size, ok := pos["positionAmt"].(float64); ok {
@@ -1025,7 +1067,7 @@ func (at *AutoTrader) placeGridLimitOrder(d *kernel.Decision, side string) error
 		// Calculate max allowed position value per grid level
 		// Each level gets proportional share of total investment
 		maxMarginPerLevel := gridConfig.TotalInvestment / float64(gridConfig.GridCount)
-		maxPositionValuePerLevel := maxMarginPerLevel * float64(gridConfig.Leverage)
+		maxPositionValuePerLevel := maxMarginPerLevel * float64(at.gridLeverage())
 		maxQuantityPerLevel := maxPositionValuePerLevel / d.Price
 
 		// Also get the level's allocated USD for additional validation
@@ -1038,7 +1080,7 @@ func (at *AutoTrader) placeGridLimitOrder(d *kernel.Decision, side string) error
 
 		// Use level-specific allocation if available
 		if levelAllocatedUSD > 0 {
-			levelMaxPositionValue := levelAllocatedUSD * float64(gridConfig.Leverage)
+			levelMaxPositionValue := levelAllocatedUSD * float64(at.gridLeverage())
 			levelMaxQuantity := levelMaxPositionValue / d.Price
 			if levelMaxQuantity < maxQuantityPerLevel {
 				maxQuantityPerLevel = levelMaxQuantity
@@ -1054,7 +1096,7 @@ func (at *AutoTrader) placeGridLimitOrder(d *kernel.Decision, side string) error
 
 		// Safety check: ensure position value is reasonable (within 2x of intended max as absolute limit)
 		positionValue := quantity * d.Price
-		absoluteMaxValue := gridConfig.TotalInvestment * float64(gridConfig.Leverage) * 2 // 2x safety margin
+		absoluteMaxValue := gridConfig.TotalInvestment * float64(at.gridLeverage()) * 2 // 2x safety margin
 		if positionValue > absoluteMaxValue {
 			logger.Errorf("[Grid] CRITICAL: Position value $%.2f exceeds absolute max $%.2f! Rejecting order.",
 				positionValue, absoluteMaxValue)
@@ -1076,13 +1118,19 @@ func (at *AutoTrader) placeGridLimitOrder(d *kernel.Decision, side string) error
 		Side:       side,
 		Price:      d.Price,
 		Quantity:   quantity, // Use validated/capped quantity
-		Leverage:   gridConfig.Leverage,
+		Leverage:   at.gridLeverage(),
 		PostOnly:   gridConfig.UseMakerOnly,
 		ReduceOnly: false,
 		ClientID:   fmt.Sprintf("grid-%d-%d", d.LevelIndex, time.Now().UnixNano()%1000000),
 	}
 
-	result, err := gridTrader.PlaceLimitOrder(req)
+	var result *LimitOrderResult
+	var err error
+	if gridConfig.IsSpot() {
+		result, err = at.placeSpotGridOrder(req)
+	} else {
+		result, err = gridTrader.PlaceLimitOrder(req)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to place limit order: %w", err)
 	}
@@ -1110,7 +1158,15 @@ func (at *AutoTrader) cancelGridOrder(d *kernel.Decision) error {
 		gridTrader = NewGridTraderAdapter(at.trader)
 	}
 
-	if err := gridTrader.CancelOrder(d.Symbol, d.OrderID); err != nil {
+	cancel := gridTrader.CancelOrder
+	if at.isSpotGrid() {
+		spot, err := at.spotGridTrader()
+		if err != nil {
+			return err
+		}
+		cancel = spot.CancelSpotOrder
+	}
+	if err := cancel(d.Symbol, d.OrderID); err != nil {
 		return fmt.Errorf("failed to cancel order: %w", err)
 	}
 
@@ -1134,7 +1190,15 @@ func (at *AutoTrader) cancelGridOrder(d *kernel.Decision) error {
 func (at *AutoTrader) cancelAllGridOrders() error {
 	gridConfig := at.config.StrategyConfig.GridConfig
 
-	if err := at.trader.CancelAllOrders(gridConfig.Symbol); err != nil {
+	cancelAll := at.trader.CancelAllOrders
+	if gridConfig.IsSpot() {
+		spot, err := at.spotGridTrader()
+		if err != nil {
+			return err
+		}
+		cancelAll = spot.CancelAllSpotOrders
+	}
+	if err := cancelAll(gridConfig.Symbol); err != nil {
 		return fmt.Errorf("failed to cancel all orders: %w", err)
 	}
 
@@ -1184,7 +1248,7 @@ func (at *AutoTrader) adjustGrid(d *kernel.Decision) error {
 	gridConfig := at.config.StrategyConfig.GridConfig
 
 	// Get current price
-	price, err := at.trader.GetMarketPrice(gridConfig.Symbol)
+	price, err := at.gridMarketPrice(gridConfig.Symbol)
 	if err != nil {
 		return fmt.Errorf("failed to get market price: %w", err)
 	}
@@ -1196,9 +1260,100 @@ func (at *AutoTrader) adjustGrid(d *kernel.Decision) error {
 	return nil
 }
 
+// ReconfigureGrid applies new grid parameters (bounds, count, investment, leverage) to a running grid.
+// Pending orders are cancelled, levels are rebuilt from the new parameters, filled positions are moved
+// to the closest new level and the current direction is re-applied. The symbol cannot be changed.
+// If the grid has not been initialized yet, the configuration is used on initialization.
+func (at *AutoTrader) ReconfigureGrid(newConfig *store.GridStrategyConfig) error {
+	if !at.IsGridStrategy() {
+		return fmt.Errorf("trader is not using a grid strategy")
+	}
+	if err := newConfig.Validate(); err != nil {
+		return err
+	}
+	oldConfig := at.config.StrategyConfig.GridConfig
+	if newConfig.Symbol != oldConfig.Symbol {
+		return fmt.Errorf("grid symbol cannot be changed while running (%s → %s)", oldConfig.Symbol, newConfig.Symbol)
+	}
+	if newConfig.IsSpot() != oldConfig.IsSpot() {
+		return fmt.Errorf("grid market type cannot be changed while running")
+	}
+
+	// Wait for an in-flight grid cycle so its orders aren't placed against the old levels
+	at.gridCycleMutex.Lock()
+	defer at.gridCycleMutex.Unlock()
+
+	if at.gridState == nil || !at.gridState.IsInitialized {
+		at.config.StrategyConfig.GridConfig = newConfig
+		return nil
+	}
+
+	currentPrice, err := at.gridMarketPrice(newConfig.Symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get market price: %w", err)
+	}
+
+	if err := at.cancelAllGridOrders(); err != nil {
+		return err
+	}
+
+	if newConfig.Leverage != oldConfig.Leverage && !newConfig.IsSpot() {
+		if err := at.trader.SetLeverage(newConfig.Symbol, newConfig.Leverage); err != nil {
+			logger.Warnf("[Grid] Failed to set leverage %dx on exchange: %v", newConfig.Leverage, err)
+		}
+	}
+
+	var mktData *market.Data
+	if newConfig.UseATRBounds {
+		if mktData, err = market.GetWithTimeframes(newConfig.Symbol, []string{"4h"}, "4h", 20); err != nil {
+			logger.Warnf("[Grid] Failed to get market data for ATR during reconfigure: %v, using default bounds", err)
+		}
+	}
+
+	at.gridState.mu.Lock()
+	defer at.gridState.mu.Unlock()
+
+	// Preserve filled positions before rebuilding levels
+	filledPositions := make(map[int]kernel.GridLevelInfo)
+	for i, level := range at.gridState.Levels {
+		if level.State == "filled" {
+			filledPositions[i] = level
+		}
+	}
+
+	at.config.StrategyConfig.GridConfig = newConfig
+	at.gridState.Config = newConfig
+
+	switch {
+	case !newConfig.UseATRBounds:
+		at.gridState.UpperPrice = newConfig.UpperPrice
+		at.gridState.LowerPrice = newConfig.LowerPrice
+	case mktData != nil:
+		at.calculateATRBoundsLocked(currentPrice, mktData, newConfig)
+	default:
+		at.calculateDefaultBoundsLocked(currentPrice, newConfig)
+	}
+	at.gridState.GridSpacing = (at.gridState.UpperPrice - at.gridState.LowerPrice) / float64(newConfig.GridCount-1)
+
+	// Rebuild levels; direction (if enabled) is re-applied from the current direction
+	at.initializeGridLevelsLocked(currentPrice, newConfig)
+	at.restoreFilledLevelsLocked(filledPositions)
+
+	logger.Infof("📊 [Grid] Reconfigured: %d levels, $%.2f - $%.2f, spacing $%.2f, investment $%.2f, leverage %dx",
+		newConfig.GridCount, at.gridState.LowerPrice, at.gridState.UpperPrice, at.gridState.GridSpacing,
+		newConfig.TotalInvestment, newConfig.Leverage)
+	return nil
+}
+
 // syncGridState syncs grid state with exchange
 func (at *AutoTrader) syncGridState() {
 	gridConfig := at.config.StrategyConfig.GridConfig
+	if gridConfig.IsSpot() {
+		at.syncSpotGridState()
+		at.checkAndExecuteStopLoss()
+		at.autoAdjustGrid()
+		return
+	}
 
 	// Get open orders from exchange
 	openOrders, err := at.trader.GetOpenOrders(gridConfig.Symbol)
@@ -1387,7 +1542,7 @@ func (at *AutoTrader) autoAdjustGrid() {
 	gridConfig := at.config.StrategyConfig.GridConfig
 
 	// Get current price
-	currentPrice, err := at.trader.GetMarketPrice(gridConfig.Symbol)
+	currentPrice, err := at.gridMarketPrice(gridConfig.Symbol)
 	if err != nil {
 		logger.Errorf("[Grid] Failed to get price for auto-adjust: %v", err)
 		return
@@ -1454,6 +1609,11 @@ func (at *AutoTrader) autoAdjustGrid() {
 	at.initializeGridLevelsLocked(currentPrice, gridConfig)
 
 	// CRITICAL FIX: Restore filled positions - find closest new level for each filled position
+	at.restoreFilledLevelsLocked(filledPositions)
+}
+
+// restoreFilledLevelsLocked moves filled positions onto the closest level of a rebuilt grid (caller must hold lock)
+func (at *AutoTrader) restoreFilledLevelsLocked(filledPositions map[int]kernel.GridLevelInfo) {
 	for _, filledLevel := range filledPositions {
 		closestIdx := -1
 		closestDist := math.MaxFloat64
@@ -1677,23 +1837,31 @@ func (at *AutoTrader) GetGridRiskInfo() *GridRiskInfo {
 	defer at.gridState.mu.RUnlock()
 
 	// Get current price
-	currentPrice, _ := at.trader.GetMarketPrice(gridConfig.Symbol)
+	currentPrice, _ := at.gridMarketPrice(gridConfig.Symbol)
 
 	// Calculate effective leverage
 	totalInvestment := gridConfig.TotalInvestment
-	leverage := gridConfig.Leverage
+	leverage := at.gridLeverage()
 
-	// Get current position value
-	positions, _ := at.trader.GetPositions()
+	// Get current position value (spot: coins bought by the grid, which can't be liquidated)
 	var currentPositionValue float64
 	var currentPositionSize float64
-	for _, pos := range positions {
-		if sym, _ := pos["symbol"].(string); sym == gridConfig.Symbol {
-			size, _ := pos["positionAmt"].(float64)
-			entry, _ := pos["entryPrice"].(float64)
-			currentPositionValue = math.Abs(size * entry)
-			currentPositionSize = size
-			break
+	if gridConfig.IsSpot() {
+		for _, level := range at.gridState.Levels {
+			if level.State == "filled" && level.Side == "buy" {
+				currentPositionValue += level.PositionSize * level.PositionEntry
+			}
+		}
+	} else {
+		positions, _ := at.trader.GetPositions()
+		for _, pos := range positions {
+			if sym, _ := pos["symbol"].(string); sym == gridConfig.Symbol {
+				size, _ := pos["positionAmt"].(float64)
+				entry, _ := pos["entryPrice"].(float64)
+				currentPositionValue = math.Abs(size * entry)
+				currentPositionSize = size
+				break
+			}
 		}
 	}
 
@@ -1794,12 +1962,20 @@ func (at *AutoTrader) checkAndExecuteStopLoss() {
 		return // Stop loss not configured
 	}
 
-	currentPrice, err := at.trader.GetMarketPrice(gridConfig.Symbol)
+	currentPrice, err := at.gridMarketPrice(gridConfig.Symbol)
 	if err != nil {
 		logger.Warnf("[Grid] Failed to get market price for stop loss check: %v", err)
 		return
 	}
 
+	var spot SpotTrader
+	if gridConfig.IsSpot() {
+		if spot, err = at.spotGridTrader(); err != nil {
+			logger.Warnf("[Grid] %v", err)
+			return
+		}
+	}
+
 	at.gridState.mu.Lock()
 	defer at.gridState.mu.Unlock()
 
@@ -1808,6 +1984,10 @@ func (at *AutoTrader) checkAndExecuteStopLoss() {
 		if level.State != "filled" || level.PositionEntry <= 0 {
 			continue
 		}
+		// Filled spot sells hold quote currency, nothing to stop out
+		if spot != nil && level.Side != "buy" {
+			continue
+		}
 
 		// Calculate loss percentage
 		var lossPct float64
@@ -1826,7 +2006,9 @@ func (at *AutoTrader) checkAndExecuteStopLoss() {
 
 			// Close the position
 			var closeErr error
-			if level.Side == "buy" {
+			if spot != nil {
+				_, closeErr = spot.SpotMarketSell(gridConfig.Symbol, level.PositionSize)
+			} else if level.Side == "buy" {
 				_, closeErr = at.trader.CloseLong(gridConfig.Symbol, level.PositionSize)
 			} else {
 				_, closeErr = at.trader.CloseShort(gridConfig.Symbol, level.PositionSize)
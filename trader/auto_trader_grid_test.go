@@ -0,0 +1,89 @@
+package trader
+
+import (
+	"nofx/store"
+	"testing"
+)
+
+// stubGridTrader serves a fixed price and records cancels/leverage; other calls would panic
+type stubGridTrader struct {
+	Trader
+	price     float64
+	cancelled int
+	leverage  int
+}
+
+func (s *stubGridTrader) GetMarketPrice(symbol string) (float64, error) {
+	return s.price, nil
+}
+
+func (s *stubGridTrader) CancelAllOrders(symbol string) error {
+	s.cancelled++
+	return nil
+}
+
+func (s *stubGridTrader) SetLeverage(symbol string, leverage int) error {
+	s.leverage = leverage
+	return nil
+}
+
+func TestReconfigureGrid_PreservesFilledLevels(t *testing.T) {
+	exchange := &stubGridTrader{price: 100}
+	gridConfig := &store.GridStrategyConfig{
+		Symbol: "BTCUSDT", GridCount: 5, TotalInvestment: 1000, Leverage: 5,
+		UpperPrice: 120, LowerPrice: 80,
+	}
+	at := &AutoTrader{
+		name:   "grid",
+		trader: exchange,
+		config: AutoTraderConfig{StrategyConfig: &store.StrategyConfig{StrategyType: "grid_trading", GridConfig: gridConfig}},
+	}
+	if err := at.InitializeGrid(); err != nil {
+		t.Fatalf("InitializeGrid: %v", err)
+	}
+	at.gridState.Levels[1].State = "filled"
+	at.gridState.Levels[1].PositionEntry = 90
+	at.gridState.Levels[1].PositionSize = 2
+	at.gridState.Levels[3].State = "pending"
+	at.gridState.Levels[3].OrderID = "o1"
+
+	newConfig := *gridConfig
+	newConfig.GridCount = 9
+	newConfig.UpperPrice = 140
+	newConfig.LowerPrice = 60
+	newConfig.Leverage = 3
+	if err := at.ReconfigureGrid(&newConfig); err != nil {
+		t.Fatalf("ReconfigureGrid: %v", err)
+	}
+
+	if exchange.cancelled != 1 || exchange.leverage != 3 {
+		t.Errorf("expected orders cancelled and leverage 3x, got cancelled=%d leverage=%d", exchange.cancelled, exchange.leverage)
+	}
+	if len(at.gridState.Levels) != 9 || at.gridState.GridSpacing != 10 {
+		t.Fatalf("expected 9 levels spaced $10, got %d spaced %.2f", len(at.gridState.Levels), at.gridState.GridSpacing)
+	}
+	filled := 0
+	for _, level := range at.gridState.Levels {
+		if level.State == "pending" {
+			t.Errorf("pending level %d should have been reset", level.Index)
+		}
+		if level.State == "filled" {
+			filled++
+			if level.Price != 90 || level.PositionSize != 2 {
+				t.Errorf("filled position restored to wrong level: %+v", level)
+			}
+		}
+	}
+	if filled != 1 {
+		t.Errorf("expected one filled level, got %d", filled)
+	}
+	if at.config.StrategyConfig.GridConfig.GridCount != 9 {
+		t.Error("trader config should use the new grid parameters")
+	}
+
+	otherSymbol := newConfig
+	otherSymbol.Symbol = "ETHUSDT"
+	if err := at.ReconfigureGrid(&otherSymbol); err == nil {
+		t.Error("changing the grid symbol should be rejected")
+	}
+}
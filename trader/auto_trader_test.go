@@ -0,0 +1,24 @@
+package trader
+
+import (
+	"nofx/store"
+	"path/filepath"
+	"testing"
+)
+
+// newStoreTestTrader creates an AutoTrader without exchange backed by a temporary store
+func newStoreTestTrader(t *testing.T, config AutoTraderConfig) *AutoTrader {
+	t.Helper()
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return &AutoTrader{
+		id:     "test_trader",
+		name:   "test",
+		userID: "user1",
+		store:  st,
+		config: config,
+	}
+}
@@ -0,0 +1,57 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"nofx/logger"
+	"nofx/trader/types"
+	"strconv"
+	"strings"
+)
+
+// GetCollateralBalance gets the futures wallet balance of every asset (CollateralTrader implementation)
+// In single-asset mode USDT, USDC etc. each margin only their own contracts; in multi-assets mode all
+// assets marked as margin available margin every contract, valued at Binance's asset index.
+func (t *FuturesTrader) GetCollateralBalance() (*types.CollateralBalance, error) {
+	account, err := t.client.NewGetAccountService().Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account info: %w", err)
+	}
+
+	result := &types.CollateralBalance{MultiAssetMargin: account.MultiAssetsMargin}
+	for _, a := range account.Assets {
+		asset := types.CollateralAsset{Asset: a.Asset}
+		asset.WalletBalance, _ = strconv.ParseFloat(a.WalletBalance, 64)
+		asset.UnrealizedPnL, _ = strconv.ParseFloat(a.UnrealizedProfit, 64)
+		asset.Available, _ = strconv.ParseFloat(a.AvailableBalance, 64)
+		if asset.WalletBalance == 0 && asset.UnrealizedPnL == 0 {
+			continue
+		}
+		if account.MultiAssetsMargin && !a.MarginAvailable {
+			continue // Held in the wallet but not accepted as margin
+		}
+		result.Assets = append(result.Assets, asset)
+	}
+
+	// Index prices of the non-USDT assets (USDCUSD, BTCUSD...)
+	needIndex := false
+	for _, a := range result.Assets {
+		needIndex = needIndex || a.Asset != "USDT"
+	}
+	if needIndex {
+		indexes, err := t.client.NewAssetIndexService().Do(context.Background())
+		if err != nil {
+			logger.Warnf("⚠️ Failed to get Binance asset index, collateral will be valued at market price: %v", err)
+			return result, nil
+		}
+		prices := make(map[string]float64, len(indexes))
+		for _, index := range indexes {
+			price, _ := strconv.ParseFloat(index.Index, 64)
+			prices[strings.TrimSuffix(index.Symbol, "USD")] = price
+		}
+		for i := range result.Assets {
+			result.Assets[i].IndexPrice = prices[result.Assets[i].Asset]
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package binance
+
+import (
+	"errors"
+	"nofx/trader/types"
+
+	"github.com/adshao/go-binance/v2/common"
+)
+
+// binanceErrorClasses Binance API error codes by class
+var binanceErrorClasses = map[int64]types.ErrorClass{
+	-1003: types.ErrClassRateLimited, // Too many requests
+	-1015: types.ErrClassRateLimited, // Too many new orders
+
+	-1002: types.ErrClassAuth, // Unauthorized
+	-1022: types.ErrClassAuth, // Invalid signature
+	-2014: types.ErrClassAuth, // API key format invalid
+	-2015: types.ErrClassAuth, // Invalid API key, IP or permissions
+
+	-2018: types.ErrClassInsufficientMargin, // Balance insufficient
+	-2019: types.ErrClassInsufficientMargin, // Margin insufficient
+	-2010: types.ErrClassInsufficientMargin, // Spot: account has insufficient balance
+
+	-1121: types.ErrClassInvalidSymbol, // Invalid symbol
+	-4140: types.ErrClassInvalidSymbol, // Invalid symbol status for opening position
+	-4141: types.ErrClassInvalidSymbol, // Symbol is closed
+
+	-1013: types.ErrClassInvalidOrder, // Filter failure (lot size, price filter, min notional)
+	-1111: types.ErrClassInvalidOrder, // Precision is over the maximum defined for this asset
+	-4003: types.ErrClassInvalidOrder, // Quantity less than or equal to zero
+	-4005: types.ErrClassInvalidOrder, // Quantity greater than max quantity
+	-4014: types.ErrClassInvalidOrder, // Price not increased by tick size
+	-4164: types.ErrClassInvalidOrder, // Order's notional below the minimum
+
+	-1001: types.ErrClassNetwork, // Internal error, disconnected
+	-1006: types.ErrClassNetwork, // Unexpected response from the message bus
+	-1007: types.ErrClassNetwork, // Timeout waiting for backend response
+	-1008: types.ErrClassNetwork, // Server overloaded
+	-1021: types.ErrClassNetwork, // Timestamp outside recvWindow
+}
+
+// ClassifyError classifies a Binance error by API error code, falling back to common messages
+func (t *FuturesTrader) ClassifyError(err error) types.ErrorClass {
+	return classifyBinanceError(err)
+}
+
+// ClassifyError classifies a Binance COIN-M error, the error codes are shared with USDT-M futures
+func (t *InverseFuturesTrader) ClassifyError(err error) types.ErrorClass {
+	return classifyBinanceError(err)
+}
+
+func classifyBinanceError(err error) types.ErrorClass {
+	var apiErr *common.APIError
+	if errors.As(err, &apiErr) {
+		if class, ok := binanceErrorClasses[apiErr.Code]; ok {
+			return class
+		}
+	}
+	return types.ClassifyError(err)
+}
@@ -13,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	gobinance "github.com/adshao/go-binance/v2"
 	"github.com/adshao/go-binance/v2/futures"
 )
 
@@ -44,6 +45,15 @@ func getBrOrderID() string {
 	return orderID
 }
 
+// brOrderIDForKey generates a deterministic order ID with br prefix for an idempotent submission
+// Format: x-KzrpZaP9{21-char key digest} (exactly 32 characters), random ID if key is unset
+func brOrderIDForKey(key types.ClientOrderKey) string {
+	if key.IsZero() {
+		return getBrOrderID()
+	}
+	return "x-KzrpZaP9" + key.ID()
+}
+
 // FuturesTrader Binance futures trader
 type FuturesTrader struct {
 	client *futures.Client
@@ -60,6 +70,16 @@ type FuturesTrader struct {
 
 	// Cache validity period (15 seconds)
 	cacheDuration time.Duration
+
+	// Spot market client (spot grids), rules cached per symbol
+	spot             *gobinance.Client
+	spotTimeOnce     sync.Once
+	spotSymbols      map[string]*spotSymbol
+	spotSymbolsMutex sync.RWMutex
+
+	// Trading rules of all symbols (lot size, tick size, min notional)
+	filters     *types.SymbolFiltersCache
+	filtersOnce sync.Once
 }
 
 // NewFuturesTrader creates futures trader
@@ -76,6 +96,7 @@ func NewFuturesTrader(apiKey, secretKey string, userId string) *FuturesTrader {
 	trader := &FuturesTrader{
 		client:        client,
 		cacheDuration: 15 * time.Second, // 15-second cache
+		spot:          gobinance.NewClient(apiKey, secretKey),
 	}
 
 	// Set dual-side position mode (Hedge Mode)
@@ -316,7 +337,40 @@ func (t *FuturesTrader) SetLeverage(symbol string, leverage int) error {
 
 // OpenLong opens a long position
 func (t *FuturesTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
-	// First cancel all pending orders for this symbol (clean up old stop-loss and take-profit orders)
+	return t.OpenLongWithClientID(symbol, quantity, leverage, types.ClientOrderKey{})
+}
+
+// OpenLongWithClientID opens a long position with a deterministic client order ID
+func (t *FuturesTrader) OpenLongWithClientID(symbol string, quantity float64, leverage int, key types.ClientOrderKey) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "LONG", quantity, leverage, key, nil)
+}
+
+// OpenShort opens a short position
+func (t *FuturesTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenShortWithClientID(symbol, quantity, leverage, types.ClientOrderKey{})
+}
+
+// OpenShortWithClientID opens a short position with a deterministic client order ID
+func (t *FuturesTrader) OpenShortWithClientID(symbol string, quantity float64, leverage int, key types.ClientOrderKey) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "SHORT", quantity, leverage, key, nil)
+}
+
+// OpenBracket opens a position with its stop loss/take profit placed before the entry
+// Binance orders cannot carry TP/SL, but close-position algo orders may rest before the position
+// exists: the entry is only sent once they are accepted and they are cancelled if it fails.
+func (t *FuturesTrader) OpenBracket(order *types.BracketOrder) (map[string]interface{}, error) {
+	return t.openPosition(order.Symbol, order.Side, order.Quantity, order.Leverage, order.Key, order)
+}
+
+// openPosition places a market entry for positionSide (LONG/SHORT), after the legs of bracket if given
+func (t *FuturesTrader) openPosition(symbol, positionSide string, quantity float64, leverage int, key types.ClientOrderKey, bracket *types.BracketOrder) (map[string]interface{}, error) {
+	// Round quantity to the lot size and check the symbol's limits before touching existing orders
+	quantityStr, err := t.normalizeMarketOrder(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cancel all pending orders for this symbol (clean up old stop-loss and take-profit orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
 		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
 	}
@@ -328,38 +382,43 @@ func (t *FuturesTrader) OpenLong(symbol string, quantity float64, leverage int)
 
 	// Note: Margin mode should be set by the caller (AutoTrader) before opening position via SetMarginMode
 
-	// Format quantity to correct precision
-	quantityStr, err := t.FormatQuantity(symbol, quantity)
-	if err != nil {
-		return nil, err
-	}
-
-	// Check if formatted quantity is 0 (prevent rounding errors)
-	quantityFloat, parseErr := strconv.ParseFloat(quantityStr, 64)
-	if parseErr != nil || quantityFloat <= 0 {
-		return nil, fmt.Errorf("position size too small, rounded to 0 (original: %.8f → formatted: %s). Suggest increasing position amount or selecting a lower-priced coin", quantity, quantityStr)
+	if bracket != nil {
+		if bracket.StopLoss > 0 {
+			if err := t.SetStopLoss(symbol, positionSide, quantity, bracket.StopLoss); err != nil {
+				return nil, err
+			}
+		}
+		if bracket.TakeProfit > 0 {
+			if err := t.SetTakeProfit(symbol, positionSide, quantity, bracket.TakeProfit); err != nil {
+				t.CancelStopOrders(symbol)
+				return nil, err
+			}
+		}
 	}
 
-	// Check minimum notional value (Binance requires at least 10 USDT)
-	if err := t.CheckMinNotional(symbol, quantityFloat); err != nil {
-		return nil, err
+	side, posSide, name := futures.SideTypeBuy, futures.PositionSideTypeLong, "long"
+	if positionSide == "SHORT" {
+		side, posSide, name = futures.SideTypeSell, futures.PositionSideTypeShort, "short"
 	}
 
-	// Create market buy order (using br ID)
+	// Create market order (using br ID)
 	order, err := t.client.NewCreateOrderService().
 		Symbol(symbol).
-		Side(futures.SideTypeBuy).
-		PositionSide(futures.PositionSideTypeLong).
+		Side(side).
+		PositionSide(posSide).
 		Type(futures.OrderTypeMarket).
 		Quantity(quantityStr).
-		NewClientOrderID(getBrOrderID()).
+		NewClientOrderID(brOrderIDForKey(key)).
 		Do(context.Background())
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to open long position: %w", err)
+		if bracket != nil {
+			t.CancelStopOrders(symbol)
+		}
+		return nil, fmt.Errorf("failed to open %s position: %w", name, err)
 	}
 
-	logger.Infof("✓ Opened long position successfully: %s quantity: %s", symbol, quantityStr)
+	logger.Infof("✓ Opened %s position successfully: %s quantity: %s", name, symbol, quantityStr)
 	logger.Infof("  Order ID: %d", order.OrderID)
 
 	result := make(map[string]interface{})
@@ -369,58 +428,25 @@ func (t *FuturesTrader) OpenLong(symbol string, quantity float64, leverage int)
 	return result, nil
 }
 
-// OpenShort opens a short position
-func (t *FuturesTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
-	// First cancel all pending orders for this symbol (clean up old stop-loss and take-profit orders)
-	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
-	}
-
-	// Set leverage
-	if err := t.SetLeverage(symbol, leverage); err != nil {
-		return nil, err
-	}
-
-	// Note: Margin mode should be set by the caller (AutoTrader) before opening position via SetMarginMode
-
-	// Format quantity to correct precision
-	quantityStr, err := t.FormatQuantity(symbol, quantity)
-	if err != nil {
-		return nil, err
-	}
-
-	// Check if formatted quantity is 0 (prevent rounding errors)
-	quantityFloat, parseErr := strconv.ParseFloat(quantityStr, 64)
-	if parseErr != nil || quantityFloat <= 0 {
-		return nil, fmt.Errorf("position size too small, rounded to 0 (original: %.8f → formatted: %s). Suggest increasing position amount or selecting a lower-priced coin", quantity, quantityStr)
-	}
-
-	// Check minimum notional value (Binance requires at least 10 USDT)
-	if err := t.CheckMinNotional(symbol, quantityFloat); err != nil {
-		return nil, err
-	}
-
-	// Create market sell order (using br ID)
-	order, err := t.client.NewCreateOrderService().
+// FindOrderByClientID looks up an order submitted with the given client order key
+func (t *FuturesTrader) FindOrderByClientID(symbol string, key types.ClientOrderKey) (map[string]interface{}, error) {
+	order, err := t.client.NewGetOrderService().
 		Symbol(symbol).
-		Side(futures.SideTypeSell).
-		PositionSide(futures.PositionSideTypeShort).
-		Type(futures.OrderTypeMarket).
-		Quantity(quantityStr).
-		NewClientOrderID(getBrOrderID()).
+		OrigClientOrderID(brOrderIDForKey(key)).
 		Do(context.Background())
-
 	if err != nil {
-		return nil, fmt.Errorf("failed to open short position: %w", err)
+		// -2013: Order does not exist
+		if strings.Contains(err.Error(), "-2013") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query order by client ID: %w", err)
 	}
 
-	logger.Infof("✓ Opened short position successfully: %s quantity: %s", symbol, quantityStr)
-	logger.Infof("  Order ID: %d", order.OrderID)
-
 	result := make(map[string]interface{})
 	result["orderId"] = order.OrderID
 	result["symbol"] = order.Symbol
 	result["status"] = order.Status
+	result["clientOrderId"] = order.ClientOrderID
 	return result, nil
 }
 
@@ -720,17 +746,17 @@ func (t *FuturesTrader) CancelAllOrders(symbol string) error {
 // PlaceLimitOrder places a limit order for grid trading
 // This implements the GridTrader interface for FuturesTrader
 func (t *FuturesTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*types.LimitOrderResult, error) {
-	// Format quantity to correct precision
-	quantityStr, err := t.FormatQuantity(req.Symbol, req.Quantity)
+	// Round price to the tick size and quantity to the lot size, checking the symbol's limits
+	filters, err := t.symbolFilters(req.Symbol)
 	if err != nil {
-		return nil, fmt.Errorf("failed to format quantity: %w", err)
+		return nil, err
 	}
-
-	// Format price to correct precision
-	priceStr, err := t.FormatPrice(req.Symbol, req.Price)
+	price := filters.NormalizePrice(req.Price)
+	quantityStr, err := filters.NormalizeOrder(req.Quantity, price, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to format price: %w", err)
+		return nil, err
 	}
+	priceStr := filters.FormatPrice(price)
 
 	// Set leverage if specified
 	if req.Leverage > 0 {
@@ -777,7 +803,7 @@ func (t *FuturesTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*types.Li
 		Symbol:       order.Symbol,
 		Side:         string(order.Side),
 		PositionSide: string(order.PositionSide),
-		Price:        req.Price,
+		Price:        price,
 		Quantity:     req.Quantity,
 		Status:       string(order.Status),
 	}, nil
@@ -1049,55 +1075,109 @@ func (t *FuturesTrader) SetTakeProfit(symbol string, positionSide string, quanti
 	return nil
 }
 
-// GetMinNotional gets minimum notional value (Binance requirement)
-func (t *FuturesTrader) GetMinNotional(symbol string) float64 {
-	// Use conservative default value of 10 USDT to ensure order passes exchange validation
-	return 10.0
-}
+// SetPartialTakeProfit places a take-profit algo order for quantity of the position
+// Unlike SetTakeProfit it does not close the whole position, so several can form a ladder.
+func (t *FuturesTrader) SetPartialTakeProfit(symbol string, positionSide string, quantity, price float64) error {
+	side, posSide := futures.SideTypeSell, futures.PositionSideTypeLong
+	if positionSide == "SHORT" {
+		side, posSide = futures.SideTypeBuy, futures.PositionSideTypeShort
+	}
 
-// CheckMinNotional checks if order meets minimum notional value requirement
-func (t *FuturesTrader) CheckMinNotional(symbol string, quantity float64) error {
-	price, err := t.GetMarketPrice(symbol)
+	quantityStr, err := t.FormatQuantity(symbol, quantity)
 	if err != nil {
-		return fmt.Errorf("failed to get market price: %w", err)
+		return err
 	}
-
-	notionalValue := quantity * price
-	minNotional := t.GetMinNotional(symbol)
-
-	if notionalValue < minNotional {
-		return fmt.Errorf(
-			"order amount %.2f USDT is below minimum requirement %.2f USDT (quantity: %.4f, price: %.4f)",
-			notionalValue, minNotional, quantity, price,
-		)
+	_, err = t.client.NewCreateAlgoOrderService().
+		Symbol(symbol).
+		Side(side).
+		PositionSide(posSide).
+		Type(futures.AlgoOrderTypeTakeProfitMarket).
+		TriggerPrice(fmt.Sprintf("%.8f", price)).
+		WorkingType(futures.WorkingTypeContractPrice).
+		Quantity(quantityStr).
+		ClientAlgoId(getBrOrderID()).
+		Do(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to set partial take-profit: %w", err)
 	}
 
+	logger.Infof("  Partial take-profit set (Algo Order): %s at %.4f", quantityStr, price)
 	return nil
 }
 
-// GetSymbolPrecision gets the quantity precision for a trading pair
-func (t *FuturesTrader) GetSymbolPrecision(symbol string) (int, error) {
+// defaultMinNotional minimum order value of symbols whose exchange info has no MIN_NOTIONAL filter (USDT)
+const defaultMinNotional = 5.0
+
+// symbolFilters returns a symbol's trading rules from the exchange info, loaded once for all symbols
+func (t *FuturesTrader) symbolFilters(symbol string) (types.SymbolFilters, error) {
+	t.filtersOnce.Do(func() {
+		t.filters = types.NewSymbolFiltersCache(types.SymbolFiltersTTL, t.loadSymbolFilters)
+	})
+	return t.filters.Get(symbol)
+}
+
+// loadSymbolFilters loads the LOT_SIZE, MARKET_LOT_SIZE, PRICE_FILTER and MIN_NOTIONAL filters of all symbols
+func (t *FuturesTrader) loadSymbolFilters() (map[string]types.SymbolFilters, error) {
 	exchangeInfo, err := t.client.NewExchangeInfoService().Do(context.Background())
 	if err != nil {
-		return 0, fmt.Errorf("failed to get trading rules: %w", err)
-	}
-
-	for _, s := range exchangeInfo.Symbols {
-		if s.Symbol == symbol {
-			// Get precision from LOT_SIZE filter
-			for _, filter := range s.Filters {
-				if filter["filterType"] == "LOT_SIZE" {
-					stepSize := filter["stepSize"].(string)
-					precision := calculatePrecision(stepSize)
-					logger.Infof("  %s quantity precision: %d (stepSize: %s)", symbol, precision, stepSize)
-					return precision, nil
-				}
+		return nil, err
+	}
+
+	filters := make(map[string]types.SymbolFilters, len(exchangeInfo.Symbols))
+	for i := range exchangeInfo.Symbols {
+		s := &exchangeInfo.Symbols[i]
+		f := types.SymbolFilters{Symbol: s.Symbol, MinNotional: defaultMinNotional}
+		if lot := s.LotSizeFilter(); lot != nil {
+			f.StepSize = types.ParseFilterValue(lot.StepSize)
+			f.MinQty = types.ParseFilterValue(lot.MinQuantity)
+			f.MaxQty = types.ParseFilterValue(lot.MaxQuantity)
+		}
+		if lot := s.MarketLotSizeFilter(); lot != nil {
+			f.MaxMarketQty = types.ParseFilterValue(lot.MaxQuantity)
+		}
+		if price := s.PriceFilter(); price != nil {
+			f.TickSize = types.ParseFilterValue(price.TickSize)
+		}
+		if notional := s.MinNotionalFilter(); notional != nil {
+			if v := types.ParseFilterValue(notional.Notional); v > 0 {
+				f.MinNotional = v
 			}
 		}
+		filters[s.Symbol] = f
 	}
+	logger.Infof("✓ Loaded Binance futures trading rules of %d symbols", len(filters))
+	return filters, nil
+}
 
-	logger.Infof("  ⚠ %s precision information not found, using default precision 3", symbol)
-	return 3, nil // Default precision is 3
+// GetMinNotional gets the minimum order value of a symbol (USDT)
+func (t *FuturesTrader) GetMinNotional(symbol string) float64 {
+	if f, err := t.symbolFilters(symbol); err == nil {
+		return f.MinNotional
+	}
+	return defaultMinNotional
+}
+
+// normalizeMarketOrder rounds a market order's quantity to the symbol's lot size and checks it against
+// the quantity limits and minimum notional at the current price, before anything is sent to the exchange
+func (t *FuturesTrader) normalizeMarketOrder(symbol string, quantity float64) (string, error) {
+	filters, err := t.symbolFilters(symbol)
+	if err != nil {
+		return "", err
+	}
+	price, err := t.GetMarketPrice(symbol)
+	if err != nil {
+		return "", fmt.Errorf("failed to get market price: %w", err)
+	}
+	return filters.NormalizeOrder(quantity, price, true)
+}
+
+// GetSymbolPrecision gets the quantity precision for a trading pair
+func (t *FuturesTrader) GetSymbolPrecision(symbol string) (int, error) {
+	filters, err := t.symbolFilters(symbol)
+	if err != nil {
+		return 0, err
+	}
+	return filters.QuantityDecimals(), nil
 }
 
 // calculatePrecision calculates precision from stepSize
@@ -1143,52 +1223,33 @@ func trimTrailingZeros(s string) string {
 	return s
 }
 
-// FormatQuantity formats quantity to correct precision
+// FormatQuantity rounds quantity down to the symbol's lot size
 func (t *FuturesTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
-	precision, err := t.GetSymbolPrecision(symbol)
+	filters, err := t.symbolFilters(symbol)
 	if err != nil {
 		// If retrieval fails, use default format
 		return fmt.Sprintf("%.3f", quantity), nil
 	}
-
-	format := fmt.Sprintf("%%.%df", precision)
-	return fmt.Sprintf(format, quantity), nil
+	return filters.FormatQuantity(quantity), nil
 }
 
 // GetSymbolPricePrecision gets the price precision for a trading pair
 func (t *FuturesTrader) GetSymbolPricePrecision(symbol string) (int, error) {
-	exchangeInfo, err := t.client.NewExchangeInfoService().Do(context.Background())
+	filters, err := t.symbolFilters(symbol)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get trading rules: %w", err)
-	}
-
-	for _, s := range exchangeInfo.Symbols {
-		if s.Symbol == symbol {
-			// Get precision from PRICE_FILTER filter
-			for _, filter := range s.Filters {
-				if filter["filterType"] == "PRICE_FILTER" {
-					tickSize := filter["tickSize"].(string)
-					precision := calculatePrecision(tickSize)
-					return precision, nil
-				}
-			}
-		}
+		return 0, err
 	}
-
-	// Default to 2 decimal places for price
-	return 2, nil
+	return filters.PriceDecimals(), nil
 }
 
-// FormatPrice formats price to correct precision
+// FormatPrice rounds price to the symbol's tick size
 func (t *FuturesTrader) FormatPrice(symbol string, price float64) (string, error) {
-	precision, err := t.GetSymbolPricePrecision(symbol)
+	filters, err := t.symbolFilters(symbol)
 	if err != nil {
 		// If retrieval fails, use default format
 		return fmt.Sprintf("%.2f", price), nil
 	}
-
-	format := fmt.Sprintf("%%.%df", precision)
-	return fmt.Sprintf(format, price), nil
+	return filters.FormatPrice(price), nil
 }
 
 // Helper functions
@@ -1459,6 +1520,50 @@ func (t *FuturesTrader) GetCommissionSymbols(lastSyncTime time.Time) ([]string,
 	return symbols, nil
 }
 
+// GetFundingHistory returns FUNDING_FEE income records since startTime
+func (t *FuturesTrader) GetFundingHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	return t.getIncomeHistory("FUNDING_FEE", types.IncomeTypeFunding, startTime, limit)
+}
+
+// GetCommissionHistory returns COMMISSION income records since startTime
+func (t *FuturesTrader) GetCommissionHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	return t.getIncomeHistory("COMMISSION", types.IncomeTypeCommission, startTime, limit)
+}
+
+// GetTransferHistory returns TRANSFER income records (deposits positive, withdrawals negative) since startTime
+func (t *FuturesTrader) GetTransferHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	return t.getIncomeHistory("TRANSFER", types.IncomeTypeTransfer, startTime, limit)
+}
+
+// getIncomeHistory queries /fapi/v1/income for one income type
+func (t *FuturesTrader) getIncomeHistory(incomeType, recordType string, startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 1000
+	}
+	incomes, err := t.client.NewGetIncomeHistoryService().
+		IncomeType(incomeType).
+		StartTime(startTime.UnixMilli()).
+		Limit(int64(limit)).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s history: %w", strings.ToLower(incomeType), err)
+	}
+
+	records := make([]types.IncomeRecord, 0, len(incomes))
+	for _, income := range incomes {
+		amount, _ := strconv.ParseFloat(income.Income, 64)
+		records = append(records, types.IncomeRecord{
+			TranID: strconv.FormatInt(income.TranID, 10),
+			Symbol: income.Symbol,
+			Type:   recordType,
+			Amount: amount,
+			Asset:  income.Asset,
+			Time:   time.UnixMilli(income.Time).UTC(),
+		})
+	}
+	return records, nil
+}
+
 // GetPnLSymbols returns symbols that have REALIZED_PNL records since lastSyncTime
 // This is a fallback when COMMISSION detection fails (VIP users, BNB fee discount)
 func (t *FuturesTrader) GetPnLSymbols(lastSyncTime time.Time) ([]string, error) {
@@ -24,12 +24,14 @@ import (
 type BinanceFuturesTestSuite struct {
 	*testutil.TraderTestSuite // Embeds base test suite
 	mockServer              *httptest.Server
+	counter                 *testutil.RequestCounter
 }
 
 // NewBinanceFuturesTestSuite Creates Binance Futures test suite
 func NewBinanceFuturesTestSuite(t *testing.T) *BinanceFuturesTestSuite {
-	// Create mock HTTP server
-	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	// Create mock HTTP server (wrapped with request counter for conformance checks)
+	counter := testutil.NewRequestCounter()
+	mockServer := httptest.NewServer(counter.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Return different mock responses based on URL path
 		path := r.URL.Path
 
@@ -264,7 +266,7 @@ func NewBinanceFuturesTestSuite(t *testing.T) *BinanceFuturesTestSuite {
 		// Serialize response
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(respBody)
-	}))
+	})))
 
 	// Create futures.Client and configure to use mock server
 	client := futures.NewClient("test_api_key", "test_secret_key")
@@ -283,6 +285,7 @@ func NewBinanceFuturesTestSuite(t *testing.T) *BinanceFuturesTestSuite {
 	return &BinanceFuturesTestSuite{
 		TraderTestSuite: baseSuite,
 		mockServer:      mockServer,
+		counter:         counter,
 	}
 }
 
@@ -301,6 +304,7 @@ func (s *BinanceFuturesTestSuite) Cleanup() {
 // TestFuturesTrader_InterfaceCompliance tests interface compliance
 func TestFuturesTrader_InterfaceCompliance(t *testing.T) {
 	var _ types.Trader = (*FuturesTrader)(nil)
+	var _ types.BracketTrader = (*FuturesTrader)(nil)
 }
 
 // TestFuturesTrader_CommonInterface runs all common interface tests using test suite
@@ -313,6 +317,16 @@ func TestFuturesTrader_CommonInterface(t *testing.T) {
 	suite.RunAllTests()
 }
 
+// TestFuturesTrader_Conformance runs the Trader contract conformance suite
+func TestFuturesTrader_Conformance(t *testing.T) {
+	suite := NewBinanceFuturesTestSuite(t)
+	defer suite.Cleanup()
+
+	testutil.NewConformanceSuite(t, suite.Trader, testutil.ConformanceOptions{
+		PositionFetches: suite.counter.Count("/fapi/v2/positionRisk"),
+	}).RunConformance()
+}
+
 // ============================================================
 // 3. Binance Futures specific unit tests
 // ============================================================
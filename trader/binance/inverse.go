@@ -0,0 +1,704 @@
+package binance
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"nofx/logger"
+	"nofx/trader/types"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2/delivery"
+)
+
+// InverseFuturesTrader Binance COIN-M (coin-margined) perpetual trader
+// Trades the {COIN}USD_PERP contract of one margin coin. Symbols and quantities are taken and
+// reported as the matching USDT pair and base coin amount (BTCUSDT, 0.01 BTC), balances and PnL
+// are in the margin coin.
+type InverseFuturesTrader struct {
+	client      *delivery.Client
+	apiKey      string
+	secretKey   string
+	marginAsset string
+
+	// Contract specs by contract symbol, loaded from exchange info
+	specs      map[string]types.ContractSpec
+	specsMutex sync.RWMutex
+
+	// Balance cache
+	cachedBalance     map[string]interface{}
+	balanceCacheTime  time.Time
+	balanceCacheMutex sync.RWMutex
+
+	// Position cache
+	cachedPositions     []map[string]interface{}
+	positionsCacheTime  time.Time
+	positionsCacheMutex sync.RWMutex
+
+	cacheDuration time.Duration
+}
+
+// NewInverseFuturesTrader creates a COIN-M trader margined in marginAsset (e.g. BTC)
+func NewInverseFuturesTrader(apiKey, secretKey, marginAsset string) *InverseFuturesTrader {
+	client := delivery.NewClient(apiKey, secretKey)
+
+	if serverTime, err := client.NewServerTimeService().Do(context.Background()); err != nil {
+		logger.Infof("⚠️ Failed to sync Binance COIN-M server time: %v", err)
+	} else {
+		client.TimeOffset = time.Now().UnixMilli() - serverTime
+	}
+
+	trader := &InverseFuturesTrader{
+		client:        client,
+		apiKey:        apiKey,
+		secretKey:     secretKey,
+		marginAsset:   strings.ToUpper(marginAsset),
+		specs:         make(map[string]types.ContractSpec),
+		cacheDuration: 15 * time.Second,
+	}
+
+	// Hedge mode, orders below use PositionSide (LONG/SHORT) like the USDT-M trader
+	if err := client.NewChangePositionModeService().DualSide(true).Do(context.Background()); err != nil &&
+		!strings.Contains(err.Error(), "No need to change position side") {
+		logger.Infof("⚠️ Failed to set COIN-M dual-side position mode: %v (ignore this warning if already in dual-side mode)", err)
+	}
+
+	logger.Infof("🏦 Binance COIN-M trader initialized (margin asset: %s)", trader.marginAsset)
+	return trader
+}
+
+// MarginAsset coin the account is margined in
+func (t *InverseFuturesTrader) MarginAsset() string {
+	return t.marginAsset
+}
+
+// GetContractSpec gets the perpetual contract of a USDT pair (BTCUSDT -> BTCUSD_PERP)
+func (t *InverseFuturesTrader) GetContractSpec(symbol string) (*types.ContractSpec, error) {
+	base, err := types.InverseBaseAsset(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if base != t.marginAsset {
+		return nil, fmt.Errorf("%s is not margined in %s, this trader only trades %sUSD_PERP", symbol, t.marginAsset, t.marginAsset)
+	}
+	contract := base + "USD_PERP"
+
+	t.specsMutex.RLock()
+	spec, ok := t.specs[contract]
+	t.specsMutex.RUnlock()
+	if ok {
+		return &spec, nil
+	}
+
+	info, err := t.client.NewExchangeInfoService().Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get COIN-M trading rules: %w", err)
+	}
+	t.specsMutex.Lock()
+	defer t.specsMutex.Unlock()
+	for _, s := range info.Symbols {
+		if s.ContractType == "PERPETUAL" && s.ContractSize > 0 {
+			t.specs[s.Symbol] = types.ContractSpec{Symbol: s.Symbol, MarginAsset: s.MarginAsset, ContractSize: float64(s.ContractSize)}
+		}
+	}
+	if spec, ok = t.specs[contract]; !ok {
+		return nil, fmt.Errorf("COIN-M perpetual %s not found", contract)
+	}
+	return &spec, nil
+}
+
+// pairSymbol maps a contract symbol back to the USDT pair (BTCUSD_PERP -> BTCUSDT)
+func pairSymbol(contract string) string {
+	return strings.TrimSuffix(contract, "USD_PERP") + "USDT"
+}
+
+// GetBalance gets the margin coin balance (with cache), all amounts in the margin coin
+func (t *InverseFuturesTrader) GetBalance() (map[string]interface{}, error) {
+	t.balanceCacheMutex.RLock()
+	if t.cachedBalance != nil && time.Since(t.balanceCacheTime) < t.cacheDuration {
+		balance := t.cachedBalance
+		t.balanceCacheMutex.RUnlock()
+		return balance, nil
+	}
+	t.balanceCacheMutex.RUnlock()
+
+	account, err := t.client.NewGetAccountService().Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get COIN-M account info: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"totalWalletBalance":    0.0,
+		"availableBalance":      0.0,
+		"totalUnrealizedProfit": 0.0,
+		"totalEquity":           0.0,
+		"marginAsset":           t.marginAsset,
+	}
+	for _, asset := range account.Assets {
+		if asset.Asset != t.marginAsset {
+			continue
+		}
+		wallet, _ := strconv.ParseFloat(asset.WalletBalance, 64)
+		available, _ := strconv.ParseFloat(asset.AvailableBalance, 64)
+		unrealized, _ := strconv.ParseFloat(asset.UnrealizedProfit, 64)
+		equity, _ := strconv.ParseFloat(asset.MarginBalance, 64)
+		result["totalWalletBalance"] = wallet
+		result["availableBalance"] = available
+		result["totalUnrealizedProfit"] = unrealized
+		result["totalEquity"] = equity
+	}
+
+	logger.Infof("✓ Binance COIN-M returned: equity=%.8f %s, available=%.8f, unrealized PnL=%.8f",
+		result["totalEquity"], t.marginAsset, result["availableBalance"], result["totalUnrealizedProfit"])
+
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = result
+	t.balanceCacheTime = time.Now()
+	t.balanceCacheMutex.Unlock()
+	return result, nil
+}
+
+// GetPositions gets positions margined in the margin coin (with cache)
+// positionAmt is the base coin value of the contracts at mark price, "contracts" the exchange size.
+func (t *InverseFuturesTrader) GetPositions() ([]map[string]interface{}, error) {
+	t.positionsCacheMutex.RLock()
+	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
+		positions := t.cachedPositions
+		t.positionsCacheMutex.RUnlock()
+		return positions, nil
+	}
+	t.positionsCacheMutex.RUnlock()
+
+	positions, err := t.client.NewGetPositionRiskService().MarginAsset(t.marginAsset).Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get COIN-M positions: %w", err)
+	}
+
+	var result []map[string]interface{}
+	for _, pos := range positions {
+		contracts, _ := strconv.ParseFloat(pos.PositionAmt, 64)
+		if contracts == 0 || !strings.HasSuffix(pos.Symbol, "USD_PERP") {
+			continue // Skip empty positions and delivery contracts
+		}
+		symbol := pairSymbol(pos.Symbol)
+		spec, err := t.GetContractSpec(symbol)
+		if err != nil {
+			logger.Infof("⚠️ Skipping COIN-M position %s: %v", pos.Symbol, err)
+			continue
+		}
+
+		markPrice, _ := strconv.ParseFloat(pos.MarkPrice, 64)
+		entryPrice, _ := strconv.ParseFloat(pos.EntryPrice, 64)
+		unrealized, _ := strconv.ParseFloat(pos.UnRealizedProfit, 64)
+		leverage, _ := strconv.ParseFloat(pos.Leverage, 64)
+		liqPrice, _ := strconv.ParseFloat(pos.LiquidationPrice, 64)
+
+		side := "long"
+		if pos.PositionSide == "SHORT" || (pos.PositionSide != "LONG" && contracts < 0) {
+			side = "short"
+		}
+		contracts = math.Abs(contracts)
+		quantity := spec.Quantity(contracts, markPrice)
+		if side == "short" {
+			quantity = -quantity
+		}
+
+		result = append(result, map[string]interface{}{
+			"symbol":           symbol,
+			"side":             side,
+			"positionAmt":      quantity,
+			"contracts":        contracts,
+			"entryPrice":       entryPrice,
+			"markPrice":        markPrice,
+			"unRealizedProfit": unrealized,
+			"leverage":         leverage,
+			"liquidationPrice": liqPrice,
+		})
+	}
+
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = result
+	t.positionsCacheTime = time.Now()
+	t.positionsCacheMutex.Unlock()
+	return result, nil
+}
+
+// clearCache drops cached balance and positions after an order
+func (t *InverseFuturesTrader) clearCache() {
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = nil
+	t.balanceCacheMutex.Unlock()
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = nil
+	t.positionsCacheMutex.Unlock()
+}
+
+// contractsFor converts a base coin quantity to whole contracts at the current price
+func (t *InverseFuturesTrader) contractsFor(symbol string, quantity float64) (*types.ContractSpec, int64, error) {
+	spec, err := t.GetContractSpec(symbol)
+	if err != nil {
+		return nil, 0, err
+	}
+	price, err := t.GetMarketPrice(symbol)
+	if err != nil {
+		return nil, 0, err
+	}
+	contracts := spec.Contracts(quantity, price)
+	if contracts < 1 {
+		return nil, 0, fmt.Errorf("position size too small: %.8f %s is %.2f USD, below one %s contract (%.0f USD)",
+			quantity, t.marginAsset, quantity*price, spec.Symbol, spec.ContractSize)
+	}
+	return spec, contracts, nil
+}
+
+// positionContracts exchange size of the open position on a side (0 if none)
+func (t *InverseFuturesTrader) positionContracts(symbol, side string) (float64, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, err
+	}
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			return pos["contracts"].(float64), nil
+		}
+	}
+	return 0, nil
+}
+
+// OpenLong opens a long position, quantity in base coin
+func (t *InverseFuturesTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.open(symbol, quantity, leverage, delivery.SideTypeBuy, delivery.PositionSideTypeLong)
+}
+
+// OpenShort opens a short position, quantity in base coin
+func (t *InverseFuturesTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.open(symbol, quantity, leverage, delivery.SideTypeSell, delivery.PositionSideTypeShort)
+}
+
+func (t *InverseFuturesTrader) open(symbol string, quantity float64, leverage int, side delivery.SideType, positionSide delivery.PositionSideType) (map[string]interface{}, error) {
+	if err := t.CancelAllOrders(symbol); err != nil {
+		logger.Infof("  ⚠ Failed to cancel old pending orders (may not have any): %v", err)
+	}
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	spec, contracts, err := t.contractsFor(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := t.client.NewCreateOrderService().
+		Symbol(spec.Symbol).
+		Side(side).
+		PositionSide(positionSide).
+		Type(delivery.OrderTypeMarket).
+		Quantity(strconv.FormatInt(contracts, 10)).
+		NewClientOrderID(getBrOrderID()).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s position: %w", strings.ToLower(string(positionSide)), err)
+	}
+	t.clearCache()
+
+	logger.Infof("✓ Opened %s position: %s %d contracts (%.0f USD)", strings.ToLower(string(positionSide)), spec.Symbol, contracts, float64(contracts)*spec.ContractSize)
+	return map[string]interface{}{
+		"orderId": order.OrderID,
+		"symbol":  symbol,
+		"status":  order.Status,
+	}, nil
+}
+
+// CloseLong closes a long position (quantity in base coin, 0 = close all)
+func (t *InverseFuturesTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.close(symbol, "long", quantity)
+}
+
+// CloseShort closes a short position (quantity in base coin, 0 = close all)
+func (t *InverseFuturesTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.close(symbol, "short", quantity)
+}
+
+func (t *InverseFuturesTrader) close(symbol, side string, quantity float64) (map[string]interface{}, error) {
+	spec, err := t.GetContractSpec(symbol)
+	if err != nil {
+		return nil, err
+	}
+	held, err := t.positionContracts(symbol, side)
+	if err != nil {
+		return nil, err
+	}
+	if held == 0 {
+		return nil, fmt.Errorf("no %s position found for %s", side, symbol)
+	}
+	contracts := int64(held)
+	if quantity > 0 {
+		if _, contracts, err = t.contractsFor(symbol, quantity); err != nil {
+			return nil, err
+		}
+		contracts = min(contracts, int64(held))
+	}
+
+	orderSide, positionSide := delivery.SideTypeSell, delivery.PositionSideTypeLong
+	if side == "short" {
+		orderSide, positionSide = delivery.SideTypeBuy, delivery.PositionSideTypeShort
+	}
+	order, err := t.client.NewCreateOrderService().
+		Symbol(spec.Symbol).
+		Side(orderSide).
+		PositionSide(positionSide).
+		Type(delivery.OrderTypeMarket).
+		Quantity(strconv.FormatInt(contracts, 10)).
+		NewClientOrderID(getBrOrderID()).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to close %s position: %w", side, err)
+	}
+	t.clearCache()
+	logger.Infof("✓ Closed %s position: %s %d contracts", side, spec.Symbol, contracts)
+
+	if err := t.CancelAllOrders(symbol); err != nil {
+		logger.Infof("  ⚠ Failed to cancel pending orders: %v", err)
+	}
+	return map[string]interface{}{
+		"orderId": order.OrderID,
+		"symbol":  symbol,
+		"status":  order.Status,
+	}, nil
+}
+
+// SetLeverage sets leverage of the contract
+func (t *InverseFuturesTrader) SetLeverage(symbol string, leverage int) error {
+	spec, err := t.GetContractSpec(symbol)
+	if err != nil {
+		return err
+	}
+	if _, err := t.client.NewChangeLeverageService().Symbol(spec.Symbol).Leverage(leverage).Do(context.Background()); err != nil {
+		if contains(err.Error(), "No need to change") {
+			return nil
+		}
+		return fmt.Errorf("failed to set leverage: %w", err)
+	}
+	logger.Infof("  ✓ %s leverage set to %dx", spec.Symbol, leverage)
+	return nil
+}
+
+// SetMarginMode sets cross or isolated margin of the contract
+func (t *InverseFuturesTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	spec, err := t.GetContractSpec(symbol)
+	if err != nil {
+		return err
+	}
+	marginType := delivery.MarginTypeIsolated
+	if isCrossMargin {
+		marginType = delivery.MarginTypeCrossed
+	}
+	err = t.client.NewChangeMarginTypeService().Symbol(spec.Symbol).MarginType(marginType).Do(context.Background())
+	if err != nil && !contains(err.Error(), "No need to change margin type") {
+		if contains(err.Error(), "Margin type cannot be changed if there exists position") {
+			logger.Infof("  ⚠️ %s has open positions, cannot change margin mode, continuing with current mode", spec.Symbol)
+			return nil
+		}
+		logger.Infof("  ⚠️ Failed to set margin mode: %v", err)
+	}
+	return nil
+}
+
+// GetMarketPrice gets the last price of the contract in USD
+func (t *InverseFuturesTrader) GetMarketPrice(symbol string) (float64, error) {
+	spec, err := t.GetContractSpec(symbol)
+	if err != nil {
+		return 0, err
+	}
+	prices, err := t.client.NewListPricesService().Symbol(spec.Symbol).Do(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get price: %w", err)
+	}
+	if len(prices) == 0 {
+		return 0, fmt.Errorf("price not found for %s", spec.Symbol)
+	}
+	return strconv.ParseFloat(prices[0].Price, 64)
+}
+
+// SetStopLoss sets a stop-market order closing the position
+func (t *InverseFuturesTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	return t.setCloseTrigger(symbol, positionSide, delivery.OrderTypeStopMarket, stopPrice)
+}
+
+// SetTakeProfit sets a take-profit-market order closing the position
+func (t *InverseFuturesTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	return t.setCloseTrigger(symbol, positionSide, delivery.OrderTypeTakeProfitMarket, takeProfitPrice)
+}
+
+func (t *InverseFuturesTrader) setCloseTrigger(symbol, positionSide string, orderType delivery.OrderType, triggerPrice float64) error {
+	spec, err := t.GetContractSpec(symbol)
+	if err != nil {
+		return err
+	}
+	side, posSide := delivery.SideTypeSell, delivery.PositionSideTypeLong
+	if positionSide == "SHORT" {
+		side, posSide = delivery.SideTypeBuy, delivery.PositionSideTypeShort
+	}
+	_, err = t.client.NewCreateOrderService().
+		Symbol(spec.Symbol).
+		Side(side).
+		PositionSide(posSide).
+		Type(orderType).
+		StopPrice(strconv.FormatFloat(triggerPrice, 'f', -1, 64)).
+		WorkingType(delivery.WorkingTypeContractPrice).
+		ClosePosition(true).
+		NewClientOrderID(getBrOrderID()).
+		Do(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to set %s: %w", strings.ToLower(string(orderType)), err)
+	}
+	logger.Infof("  %s set: %s @ %.4f", orderType, spec.Symbol, triggerPrice)
+	return nil
+}
+
+// CancelStopLossOrders cancels only stop-loss orders
+func (t *InverseFuturesTrader) CancelStopLossOrders(symbol string) error {
+	return t.cancelOrdersOfType(symbol, "STOP_MARKET", "STOP")
+}
+
+// CancelTakeProfitOrders cancels only take-profit orders
+func (t *InverseFuturesTrader) CancelTakeProfitOrders(symbol string) error {
+	return t.cancelOrdersOfType(symbol, "TAKE_PROFIT_MARKET", "TAKE_PROFIT")
+}
+
+// CancelStopOrders cancels stop-loss and take-profit orders
+func (t *InverseFuturesTrader) CancelStopOrders(symbol string) error {
+	return t.cancelOrdersOfType(symbol, "STOP_MARKET", "STOP", "TAKE_PROFIT_MARKET", "TAKE_PROFIT")
+}
+
+func (t *InverseFuturesTrader) cancelOrdersOfType(symbol string, orderTypes ...string) error {
+	spec, err := t.GetContractSpec(symbol)
+	if err != nil {
+		return err
+	}
+	orders, err := t.client.NewListOpenOrdersService().Symbol(spec.Symbol).Do(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get open orders: %w", err)
+	}
+	var cancelErrors []error
+	for _, order := range orders {
+		for _, orderType := range orderTypes {
+			if string(order.Type) != orderType {
+				continue
+			}
+			if _, err := t.client.NewCancelOrderService().Symbol(spec.Symbol).OrderID(order.OrderID).Do(context.Background()); err != nil {
+				cancelErrors = append(cancelErrors, fmt.Errorf("order ID %d: %w", order.OrderID, err))
+			}
+		}
+	}
+	if len(cancelErrors) > 0 {
+		return fmt.Errorf("failed to cancel orders: %v", cancelErrors)
+	}
+	return nil
+}
+
+// CancelAllOrders cancels all pending orders of the contract
+func (t *InverseFuturesTrader) CancelAllOrders(symbol string) error {
+	spec, err := t.GetContractSpec(symbol)
+	if err != nil {
+		return err
+	}
+	if err := t.client.NewCancelAllOpenOrdersService().Symbol(spec.Symbol).Do(context.Background()); err != nil {
+		return fmt.Errorf("failed to cancel all orders: %w", err)
+	}
+	return nil
+}
+
+// FormatQuantity rounds a base coin quantity to whole contracts at the current price, still in base coin
+func (t *InverseFuturesTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	spec, err := t.GetContractSpec(symbol)
+	if err != nil {
+		return "", err
+	}
+	price, err := t.GetMarketPrice(symbol)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(spec.Quantity(float64(spec.Contracts(quantity, price)), price), 'f', 8, 64), nil
+}
+
+// GetOrderStatus gets order status, executedQty in base coin at the fill price
+func (t *InverseFuturesTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	spec, err := t.GetContractSpec(symbol)
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %s", orderID)
+	}
+	order, err := t.client.NewGetOrderService().Symbol(spec.Symbol).OrderID(id).Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order status: %w", err)
+	}
+	avgPrice, _ := strconv.ParseFloat(order.AvgPrice, 64)
+	contracts, _ := strconv.ParseFloat(order.ExecutedQuantity, 64)
+
+	return map[string]interface{}{
+		"orderId":     order.OrderID,
+		"symbol":      symbol,
+		"status":      string(order.Status),
+		"avgPrice":    avgPrice,
+		"executedQty": spec.Quantity(contracts, avgPrice),
+		"contracts":   contracts,
+		"side":        string(order.Side),
+		"type":        string(order.Type),
+		"time":        order.Time,
+		"updateTime":  order.UpdateTime,
+		"commission":  0.0, // Only reported by userTrades
+	}, nil
+}
+
+// GetOpenOrders gets open orders of the contract, quantities in contracts
+func (t *InverseFuturesTrader) GetOpenOrders(symbol string) ([]types.OpenOrder, error) {
+	spec, err := t.GetContractSpec(symbol)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := t.client.NewListOpenOrdersService().Symbol(spec.Symbol).Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+	result := make([]types.OpenOrder, 0, len(orders))
+	for _, order := range orders {
+		price, _ := strconv.ParseFloat(order.Price, 64)
+		stopPrice, _ := strconv.ParseFloat(order.StopPrice, 64)
+		quantity, _ := strconv.ParseFloat(order.OrigQuantity, 64)
+		result = append(result, types.OpenOrder{
+			OrderID:      strconv.FormatInt(order.OrderID, 10),
+			Symbol:       symbol,
+			Side:         string(order.Side),
+			PositionSide: string(order.PositionSide),
+			Type:         string(order.Type),
+			Price:        price,
+			StopPrice:    stopPrice,
+			Quantity:     quantity,
+			Status:       string(order.Status),
+		})
+	}
+	return result, nil
+}
+
+// coinMTrade one fill of /dapi/v1/userTrades
+type coinMTrade struct {
+	ID           int64  `json:"id"`
+	Symbol       string `json:"symbol"`
+	Side         string `json:"side"`
+	PositionSide string `json:"positionSide"`
+	Price        string `json:"price"`
+	Qty          string `json:"qty"` // Contracts
+	RealizedPnl  string `json:"realizedPnl"`
+	Commission   string `json:"commission"`
+	Time         int64  `json:"time"`
+}
+
+// GetClosedPnL gets recent closing fills of the contract, PnL and fees in the margin coin
+// The COIN-M SDK has no user trades endpoint, so it's called directly.
+func (t *InverseFuturesTrader) GetClosedPnL(startTime time.Time, limit int) ([]types.ClosedPnLRecord, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 500
+	}
+	params := url.Values{}
+	params.Set("pair", t.marginAsset+"USD")
+	params.Set("startTime", strconv.FormatInt(startTime.UnixMilli(), 10))
+	params.Set("limit", strconv.Itoa(limit))
+
+	var trades []coinMTrade
+	if err := t.signedGet("/dapi/v1/userTrades", params, &trades); err != nil {
+		return nil, err
+	}
+
+	var records []types.ClosedPnLRecord
+	for _, trade := range trades {
+		pnl, _ := strconv.ParseFloat(trade.RealizedPnl, 64)
+		if pnl == 0 || !strings.HasSuffix(trade.Symbol, "USD_PERP") {
+			continue // Opening fills and delivery contracts
+		}
+		price, _ := strconv.ParseFloat(trade.Price, 64)
+		contracts, _ := strconv.ParseFloat(trade.Qty, 64)
+		fee, _ := strconv.ParseFloat(trade.Commission, 64)
+		symbol := pairSymbol(trade.Symbol)
+		spec, err := t.GetContractSpec(symbol)
+		if err != nil {
+			continue
+		}
+
+		side := "long"
+		if trade.PositionSide == "SHORT" || (trade.PositionSide == "BOTH" && trade.Side == "BUY") {
+			side = "short"
+		}
+		// Entry price solved from the inverse PnL: pnl = ±contracts × size × (1/entry − 1/exit)
+		var entryPrice float64
+		if notional := contracts * spec.ContractSize; notional > 0 && price > 0 {
+			signed := pnl
+			if side == "short" {
+				signed = -pnl
+			}
+			if inv := signed/notional + 1/price; inv > 0 {
+				entryPrice = 1 / inv
+			}
+		}
+
+		exitTime := time.UnixMilli(trade.Time).UTC()
+		records = append(records, types.ClosedPnLRecord{
+			Symbol:      symbol,
+			Side:        side,
+			EntryPrice:  entryPrice,
+			ExitPrice:   price,
+			Quantity:    spec.Quantity(contracts, price),
+			RealizedPnL: pnl,
+			Fee:         fee,
+			EntryTime:   exitTime, // Approximate
+			ExitTime:    exitTime,
+			OrderID:     strconv.FormatInt(trade.ID, 10),
+			ExchangeID:  strconv.FormatInt(trade.ID, 10),
+			CloseType:   "unknown",
+		})
+	}
+	return records, nil
+}
+
+// signedGet calls a signed COIN-M endpoint and decodes its JSON response into out
+func (t *InverseFuturesTrader) signedGet(endpoint string, params url.Values, out interface{}) error {
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli()-t.client.TimeOffset, 10))
+	params.Set("recvWindow", "5000")
+	query := params.Encode()
+	mac := hmac.New(sha256.New, []byte(t.secretKey))
+	mac.Write([]byte(query))
+	query += "&signature=" + hex.EncodeToString(mac.Sum(nil))
+
+	req, err := http.NewRequest(http.MethodGet, t.client.BaseURL+endpoint+"?"+query, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-MBX-APIKEY", t.apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call Binance COIN-M API: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Binance COIN-M API error (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,24 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"nofx/trader/types"
+)
+
+// GetAPIKeyPermissions gets the restrictions of the API key (PermissionTrader implementation)
+// Binance reports them on the spot API (/sapi/v1/account/apiRestrictions).
+func (t *FuturesTrader) GetAPIKeyPermissions() (*types.APIKeyPermissions, error) {
+	client, err := t.spotClient()
+	if err != nil {
+		return nil, err
+	}
+	restrictions, err := client.NewGetAPIKeyPermission().Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key permissions: %w", err)
+	}
+	return &types.APIKeyPermissions{
+		FuturesTrading: restrictions.EnableFutures,
+		Withdrawal:     restrictions.EnableWithdrawals,
+	}, nil
+}
@@ -0,0 +1,329 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"nofx/logger"
+	"nofx/trader/types"
+	"strconv"
+
+	gobinance "github.com/adshao/go-binance/v2"
+)
+
+// ============================================================================
+// Spot Market (SpotTrader implementation)
+// ============================================================================
+// Spot orders go through the spot REST API with the same API key as futures.
+// The key needs spot trading permission; funds must sit in the spot wallet.
+// ============================================================================
+
+// spotSymbol cached spot trading rules of one symbol
+type spotSymbol struct {
+	baseAsset         string
+	quoteAsset        string
+	quantityPrecision int
+	pricePrecision    int
+	minNotional       float64
+}
+
+// spotClient returns the spot REST client, synced to Binance server time on first use
+func (t *FuturesTrader) spotClient() (*gobinance.Client, error) {
+	if t.spot == nil {
+		return nil, fmt.Errorf("spot trading is not configured for this trader")
+	}
+	t.spotTimeOnce.Do(func() {
+		offset, err := t.spot.NewSetServerTimeService().Do(context.Background())
+		if err != nil {
+			logger.Infof("⚠️ Failed to sync Binance spot server time: %v", err)
+			return
+		}
+		logger.Infof("⏱ Binance spot server time synced, offset %dms", offset)
+	})
+	return t.spot, nil
+}
+
+// getSpotSymbol gets spot trading rules of a symbol (cached, rules rarely change)
+func (t *FuturesTrader) getSpotSymbol(symbol string) (*spotSymbol, error) {
+	t.spotSymbolsMutex.RLock()
+	info, ok := t.spotSymbols[symbol]
+	t.spotSymbolsMutex.RUnlock()
+	if ok {
+		return info, nil
+	}
+
+	client, err := t.spotClient()
+	if err != nil {
+		return nil, err
+	}
+	exchangeInfo, err := client.NewExchangeInfoService().Symbol(symbol).Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot trading rules: %w", err)
+	}
+	for i := range exchangeInfo.Symbols {
+		s := &exchangeInfo.Symbols[i]
+		if s.Symbol != symbol {
+			continue
+		}
+		info = &spotSymbol{baseAsset: s.BaseAsset, quoteAsset: s.QuoteAsset, quantityPrecision: 8, pricePrecision: 8}
+		if lot := s.LotSizeFilter(); lot != nil {
+			info.quantityPrecision = calculatePrecision(lot.StepSize)
+		}
+		if price := s.PriceFilter(); price != nil {
+			info.pricePrecision = calculatePrecision(price.TickSize)
+		}
+		if notional := s.NotionalFilter(); notional != nil {
+			info.minNotional, _ = strconv.ParseFloat(notional.MinNotional, 64)
+		}
+
+		t.spotSymbolsMutex.Lock()
+		if t.spotSymbols == nil {
+			t.spotSymbols = make(map[string]*spotSymbol)
+		}
+		t.spotSymbols[symbol] = info
+		t.spotSymbolsMutex.Unlock()
+		return info, nil
+	}
+	return nil, fmt.Errorf("spot symbol %s not found", symbol)
+}
+
+// formatSpotQuantity rounds quantity down to the lot size, so sells never exceed the free balance
+func formatSpotQuantity(info *spotSymbol, quantity float64) string {
+	factor := math.Pow(10, float64(info.quantityPrecision))
+	return strconv.FormatFloat(math.Floor(quantity*factor)/factor, 'f', info.quantityPrecision, 64)
+}
+
+// GetSpotAssets gets base and quote asset of a spot symbol
+func (t *FuturesTrader) GetSpotAssets(symbol string) (base, quote string, err error) {
+	info, err := t.getSpotSymbol(symbol)
+	if err != nil {
+		return "", "", err
+	}
+	return info.baseAsset, info.quoteAsset, nil
+}
+
+// GetSpotBalances gets spot wallet balances by asset
+func (t *FuturesTrader) GetSpotBalances() (map[string]types.SpotBalance, error) {
+	client, err := t.spotClient()
+	if err != nil {
+		return nil, err
+	}
+	account, err := client.NewGetAccountService().OmitZeroBalances(true).Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot account: %w", err)
+	}
+
+	balances := make(map[string]types.SpotBalance, len(account.Balances))
+	for _, b := range account.Balances {
+		free, _ := strconv.ParseFloat(b.Free, 64)
+		locked, _ := strconv.ParseFloat(b.Locked, 64)
+		balances[b.Asset] = types.SpotBalance{Asset: b.Asset, Free: free, Locked: locked}
+	}
+	return balances, nil
+}
+
+// GetSpotPrice gets latest spot price
+func (t *FuturesTrader) GetSpotPrice(symbol string) (float64, error) {
+	client, err := t.spotClient()
+	if err != nil {
+		return 0, err
+	}
+	prices, err := client.NewListPricesService().Symbol(symbol).Do(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get spot price: %w", err)
+	}
+	if len(prices) == 0 {
+		return 0, fmt.Errorf("spot price not found")
+	}
+	return strconv.ParseFloat(prices[0].Price, 64)
+}
+
+// PlaceSpotLimitOrder places a spot limit order (LIMIT_MAKER when PostOnly)
+func (t *FuturesTrader) PlaceSpotLimitOrder(req *types.LimitOrderRequest) (*types.LimitOrderResult, error) {
+	client, err := t.spotClient()
+	if err != nil {
+		return nil, err
+	}
+	info, err := t.getSpotSymbol(req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	if info.minNotional > 0 && req.Quantity*req.Price < info.minNotional {
+		return nil, fmt.Errorf("order value %.2f below spot minimum notional %.2f", req.Quantity*req.Price, info.minNotional)
+	}
+
+	quantityStr := formatSpotQuantity(info, req.Quantity)
+	priceStr := strconv.FormatFloat(req.Price, 'f', info.pricePrecision, 64)
+
+	side := gobinance.SideTypeBuy
+	if req.Side == "SELL" {
+		side = gobinance.SideTypeSell
+	}
+	orderService := client.NewCreateOrderService().
+		Symbol(req.Symbol).
+		Side(side).
+		Quantity(quantityStr).
+		Price(priceStr)
+	if req.PostOnly {
+		orderService = orderService.Type(gobinance.OrderTypeLimitMaker)
+	} else {
+		orderService = orderService.Type(gobinance.OrderTypeLimit).TimeInForce(gobinance.TimeInForceTypeGTC)
+	}
+	if req.ClientID != "" {
+		orderService = orderService.NewClientOrderID(req.ClientID)
+	}
+
+	order, err := orderService.Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to place spot limit order: %w", err)
+	}
+
+	logger.Infof("✓ [Grid] Placed spot limit order: %s %s @ %s, qty=%s, orderID=%d",
+		req.Symbol, req.Side, priceStr, quantityStr, order.OrderID)
+
+	quantity, _ := strconv.ParseFloat(quantityStr, 64)
+	return &types.LimitOrderResult{
+		OrderID:  fmt.Sprintf("%d", order.OrderID),
+		ClientID: order.ClientOrderID,
+		Symbol:   order.Symbol,
+		Side:     string(order.Side),
+		Price:    req.Price,
+		Quantity: quantity,
+		Status:   string(order.Status),
+	}, nil
+}
+
+// SpotMarketSell sells quantity of the base asset at market price
+func (t *FuturesTrader) SpotMarketSell(symbol string, quantity float64) (map[string]interface{}, error) {
+	client, err := t.spotClient()
+	if err != nil {
+		return nil, err
+	}
+	info, err := t.getSpotSymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+	quantityStr := formatSpotQuantity(info, quantity)
+	if q, _ := strconv.ParseFloat(quantityStr, 64); q <= 0 {
+		return nil, fmt.Errorf("sell quantity %.8f is below the %s lot size", quantity, symbol)
+	}
+
+	order, err := client.NewCreateOrderService().
+		Symbol(symbol).
+		Side(gobinance.SideTypeSell).
+		Type(gobinance.OrderTypeMarket).
+		Quantity(quantityStr).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to place spot market sell: %w", err)
+	}
+
+	logger.Infof("✓ Spot market sell %s %s succeeded, orderID=%d", symbol, quantityStr, order.OrderID)
+	return map[string]interface{}{
+		"orderId": order.OrderID,
+		"symbol":  order.Symbol,
+		"status":  string(order.Status),
+	}, nil
+}
+
+// CancelSpotOrder cancels a specific spot order by ID
+func (t *FuturesTrader) CancelSpotOrder(symbol, orderID string) error {
+	client, err := t.spotClient()
+	if err != nil {
+		return err
+	}
+	orderIDInt, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid order ID: %w", err)
+	}
+	if _, err := client.NewCancelOrderService().Symbol(symbol).OrderID(orderIDInt).Do(context.Background()); err != nil {
+		return fmt.Errorf("failed to cancel spot order: %w", err)
+	}
+	logger.Infof("✓ [Grid] Cancelled spot order: %s/%s", symbol, orderID)
+	return nil
+}
+
+// CancelAllSpotOrders cancels all open spot orders for this symbol
+func (t *FuturesTrader) CancelAllSpotOrders(symbol string) error {
+	client, err := t.spotClient()
+	if err != nil {
+		return err
+	}
+	if _, err := client.NewCancelOpenOrdersService().Symbol(symbol).Do(context.Background()); err != nil {
+		// No open orders is not an error
+		if contains(err.Error(), "Unknown order") {
+			return nil
+		}
+		return fmt.Errorf("failed to cancel spot orders: %w", err)
+	}
+	logger.Infof("✓ Cancelled all spot orders for %s", symbol)
+	return nil
+}
+
+// GetSpotOpenOrders gets open spot orders for this symbol
+func (t *FuturesTrader) GetSpotOpenOrders(symbol string) ([]types.OpenOrder, error) {
+	client, err := t.spotClient()
+	if err != nil {
+		return nil, err
+	}
+	orders, err := client.NewListOpenOrdersService().Symbol(symbol).Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot open orders: %w", err)
+	}
+
+	result := make([]types.OpenOrder, 0, len(orders))
+	for _, order := range orders {
+		price, _ := strconv.ParseFloat(order.Price, 64)
+		stopPrice, _ := strconv.ParseFloat(order.StopPrice, 64)
+		quantity, _ := strconv.ParseFloat(order.OrigQuantity, 64)
+		result = append(result, types.OpenOrder{
+			OrderID:   fmt.Sprintf("%d", order.OrderID),
+			Symbol:    order.Symbol,
+			Side:      string(order.Side),
+			Type:      string(order.Type),
+			Price:     price,
+			StopPrice: stopPrice,
+			Quantity:  quantity,
+			Status:    string(order.Status),
+		})
+	}
+	return result, nil
+}
+
+// GetSpotOrderStatus gets spot order status
+func (t *FuturesTrader) GetSpotOrderStatus(symbol, orderID string) (map[string]interface{}, error) {
+	client, err := t.spotClient()
+	if err != nil {
+		return nil, err
+	}
+	orderIDInt, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %s", orderID)
+	}
+	order, err := client.NewGetOrderService().Symbol(symbol).OrderID(orderIDInt).Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot order status: %w", err)
+	}
+
+	executedQty, _ := strconv.ParseFloat(order.ExecutedQuantity, 64)
+	quoteQty, _ := strconv.ParseFloat(order.CummulativeQuoteQuantity, 64)
+	avgPrice := 0.0
+	if executedQty > 0 {
+		avgPrice = quoteQty / executedQty
+	}
+	return map[string]interface{}{
+		"orderId":     order.OrderID,
+		"symbol":      order.Symbol,
+		"status":      string(order.Status),
+		"avgPrice":    avgPrice,
+		"executedQty": executedQty,
+		"side":        string(order.Side),
+		"type":        string(order.Type),
+		"time":        order.Time,
+		"updateTime":  order.UpdateTime,
+		"commission":  0.0, // Spot commission is only reported per trade
+	}, nil
+}
+
+// Ensure FuturesTrader runs spot grids
+var _ types.SpotTrader = (*FuturesTrader)(nil)
@@ -0,0 +1,37 @@
+package bitget
+
+import (
+	"fmt"
+	"nofx/trader/types"
+)
+
+// bitgetErrorClasses Bitget API error codes by class
+var bitgetErrorClasses = map[string]types.ErrorClass{
+	"429": types.ErrClassRateLimited, // Too many requests
+
+	"40006": types.ErrClassAuth, // Invalid ACCESS_KEY
+	"40009": types.ErrClassAuth, // Signature error
+	"40012": types.ErrClassAuth, // API key or passphrase incorrect
+	"40014": types.ErrClassAuth, // Incorrect permissions
+	"40018": types.ErrClassAuth, // Invalid IP
+	"40037": types.ErrClassAuth, // API key does not exist
+
+	"40754": types.ErrClassInsufficientMargin, // Balance not enough
+	"40762": types.ErrClassInsufficientMargin, // Order amount exceeds the balance
+	"43012": types.ErrClassInsufficientMargin, // Insufficient balance
+
+	"40309": types.ErrClassInvalidSymbol, // Symbol has been removed
+
+	"40008": types.ErrClassNetwork, // Request timestamp expired
+	"40010": types.ErrClassNetwork, // Request timed out
+	"40015": types.ErrClassNetwork, // System is abnormal, try again later
+}
+
+// apiError tags a Bitget API error response with its class
+func apiError(code, msg string) error {
+	class, ok := bitgetErrorClasses[code]
+	if !ok {
+		class = types.ErrClassUnknown
+	}
+	return types.NewExchangeError(class, code, fmt.Errorf("Bitget API error: code=%s, msg=%s", code, msg))
+}
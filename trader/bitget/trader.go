@@ -38,6 +38,8 @@ type BitgetTrader struct {
 	apiKey     string
 	secretKey  string
 	passphrase string
+	testnet    bool   // Demo trading (paptrading header, demo API key required)
+	baseURL    string // API base URL
 
 	// HTTP client
 	httpClient *http.Client
@@ -82,7 +84,8 @@ type BitgetResponse struct {
 }
 
 // NewBitgetTrader creates a Bitget trader
-func NewBitgetTrader(apiKey, secretKey, passphrase string) *BitgetTrader {
+// testnet: use Bitget demo trading (requires an API key created in demo mode)
+func NewBitgetTrader(apiKey, secretKey, passphrase string, testnet bool) *BitgetTrader {
 	httpClient := &http.Client{
 		Timeout:   30 * time.Second,
 		Transport: http.DefaultTransport,
@@ -92,6 +95,8 @@ func NewBitgetTrader(apiKey, secretKey, passphrase string) *BitgetTrader {
 		apiKey:         apiKey,
 		secretKey:      secretKey,
 		passphrase:     passphrase,
+		testnet:        testnet,
+		baseURL:        bitgetBaseURL,
 		httpClient:     httpClient,
 		cacheDuration:  15 * time.Second,
 		contractsCache: make(map[string]*BitgetContract),
@@ -102,7 +107,11 @@ func NewBitgetTrader(apiKey, secretKey, passphrase string) *BitgetTrader {
 		logger.Infof("⚠️ Failed to set Bitget position mode: %v (ignore if already set)", err)
 	}
 
-	logger.Infof("🟢 [Bitget] Trader initialized")
+	if testnet {
+		logger.Infof("🟢 [Bitget] Trader initialized (demo trading)")
+	} else {
+		logger.Infof("🟢 [Bitget] Trader initialized")
+	}
 
 	return trader
 }
@@ -171,7 +180,7 @@ func (t *BitgetTrader) doRequest(method, path string, body interface{}) ([]byte,
 	}
 	signature := t.sign(timestamp, method, path, signBody)
 
-	url := bitgetBaseURL + path
+	url := t.baseURL + path
 	req, err := http.NewRequest(method, url, bytes.NewReader(bodyBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -183,6 +192,9 @@ func (t *BitgetTrader) doRequest(method, path string, body interface{}) ([]byte,
 	req.Header.Set("ACCESS-PASSPHRASE", t.passphrase)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("locale", "en-US")
+	if t.testnet {
+		req.Header.Set("paptrading", "1")
+	}
 	// Channel code only for order endpoints
 	if strings.Contains(path, "/order/") {
 		req.Header.Set("X-CHANNEL-API-CODE", "7fygt")
@@ -201,11 +213,12 @@ func (t *BitgetTrader) doRequest(method, path string, body interface{}) ([]byte,
 
 	var bitgetResp BitgetResponse
 	if err := json.Unmarshal(respBody, &bitgetResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w, body: %s", err, string(respBody))
+		// Non-JSON body (gateway error page): classify by HTTP status
+		return nil, types.NewExchangeError(types.HTTPStatusClass(resp.StatusCode), "", fmt.Errorf("failed to parse response: %w, body: %s", err, string(respBody)))
 	}
 
 	if bitgetResp.Code != "00000" {
-		return nil, fmt.Errorf("Bitget API error: code=%s, msg=%s", bitgetResp.Code, bitgetResp.Msg)
+		return nil, apiError(bitgetResp.Code, bitgetResp.Msg)
 	}
 
 	return bitgetResp.Data, nil
@@ -488,6 +501,11 @@ func (t *BitgetTrader) SetLeverage(symbol string, leverage int) error {
 
 // OpenLong opens long position
 func (t *BitgetTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenLongWithClientID(symbol, quantity, leverage, types.ClientOrderKey{})
+}
+
+// OpenLongWithClientID opens long position with a deterministic client order ID
+func (t *BitgetTrader) OpenLongWithClientID(symbol string, quantity float64, leverage int, key types.ClientOrderKey) (map[string]interface{}, error) {
 	symbol = t.convertSymbol(symbol)
 
 	// Cancel old orders first
@@ -509,7 +527,7 @@ func (t *BitgetTrader) OpenLong(symbol string, quantity float64, leverage int) (
 		"side":        "buy",
 		"orderType":   "market",
 		"size":        qtyStr,
-		"clientOid":   genBitgetClientOid(),
+		"clientOid":   bitgetClientOidForKey(key),
 	}
 
 	logger.Infof("  📊 Bitget OpenLong: symbol=%s, qty=%s, leverage=%d", symbol, qtyStr, leverage)
@@ -542,6 +560,11 @@ func (t *BitgetTrader) OpenLong(symbol string, quantity float64, leverage int) (
 
 // OpenShort opens short position
 func (t *BitgetTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenShortWithClientID(symbol, quantity, leverage, types.ClientOrderKey{})
+}
+
+// OpenShortWithClientID opens short position with a deterministic client order ID
+func (t *BitgetTrader) OpenShortWithClientID(symbol string, quantity float64, leverage int, key types.ClientOrderKey) (map[string]interface{}, error) {
 	symbol = t.convertSymbol(symbol)
 
 	// Cancel old orders first
@@ -563,7 +586,7 @@ func (t *BitgetTrader) OpenShort(symbol string, quantity float64, leverage int)
 		"side":        "sell",
 		"orderType":   "market",
 		"size":        qtyStr,
-		"clientOid":   genBitgetClientOid(),
+		"clientOid":   bitgetClientOidForKey(key),
 	}
 
 	logger.Infof("  📊 Bitget OpenShort: symbol=%s, qty=%s, leverage=%d", symbol, qtyStr, leverage)
@@ -1080,6 +1103,76 @@ func (t *BitgetTrader) GetClosedPnL(startTime time.Time, limit int) ([]types.Clo
 	return records, nil
 }
 
+// GetFundingHistory retrieves funding settlements from Bitget account bills
+// Bitget API: /api/v2/mix/account/bill (businessType=contract_settle_fee)
+func (t *BitgetTrader) GetFundingHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	return t.getBills("contract_settle_fee", types.IncomeTypeFunding, startTime, limit)
+}
+
+// GetCommissionHistory retrieves trading fees from Bitget account bills (fee of each trade bill)
+// Bitget API: /api/v2/mix/account/bill
+func (t *BitgetTrader) GetCommissionHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	return t.getBills("", types.IncomeTypeCommission, startTime, limit)
+}
+
+// getBills queries USDT futures account bills, businessType "" for all bill types
+func (t *BitgetTrader) getBills(businessType, recordType string, startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	params := map[string]interface{}{
+		"productType": "USDT-FUTURES",
+		"limit":       fmt.Sprintf("%d", limit),
+	}
+	if businessType != "" {
+		params["businessType"] = businessType
+	}
+	if !startTime.IsZero() {
+		params["startTime"] = fmt.Sprintf("%d", startTime.UnixMilli())
+	}
+
+	data, err := t.doRequest("GET", "/api/v2/mix/account/bill", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bills: %w", err)
+	}
+
+	var resp struct {
+		Bills []struct {
+			BillID string `json:"billId"`
+			Symbol string `json:"symbol"`
+			Amount string `json:"amount"` // Balance change (funding amount)
+			Fee    string `json:"fee"`    // Negative = charged
+			Coin   string `json:"coin"`
+			CTime  string `json:"cTime"`
+		} `json:"bills"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	records := make([]types.IncomeRecord, 0, len(resp.Bills))
+	for _, bill := range resp.Bills {
+		raw := bill.Amount
+		if recordType == types.IncomeTypeCommission {
+			raw = bill.Fee
+		}
+		amount, _ := strconv.ParseFloat(raw, 64)
+		if amount == 0 {
+			continue
+		}
+		cTime, _ := strconv.ParseInt(bill.CTime, 10, 64)
+		records = append(records, types.IncomeRecord{
+			TranID: bill.BillID,
+			Symbol: bill.Symbol,
+			Type:   recordType,
+			Amount: amount,
+			Asset:  bill.Coin,
+			Time:   time.UnixMilli(cTime).UTC(),
+		})
+	}
+	return records, nil
+}
+
 // clearCache clears all caches
 func (t *BitgetTrader) clearCache() {
 	t.balanceCacheMutex.Lock()
@@ -1098,6 +1191,54 @@ func genBitgetClientOid() string {
 	return fmt.Sprintf("nofx%d%05d", timestamp, rand)
 }
 
+// bitgetClientOidForKey generates a deterministic client order ID for an idempotent submission
+// Falls back to a random ID if key is unset
+func bitgetClientOidForKey(key types.ClientOrderKey) string {
+	if key.IsZero() {
+		return genBitgetClientOid()
+	}
+	return "nofx" + key.ID()
+}
+
+// FindOrderByClientID looks up an order submitted with the given client order key
+func (t *BitgetTrader) FindOrderByClientID(symbol string, key types.ClientOrderKey) (map[string]interface{}, error) {
+	symbol = t.convertSymbol(symbol)
+	clientOid := bitgetClientOidForKey(key)
+
+	params := map[string]interface{}{
+		"symbol":      symbol,
+		"productType": "USDT-FUTURES",
+		"clientOid":   clientOid,
+	}
+
+	data, err := t.doRequest("GET", "/api/v2/mix/order/detail", params)
+	if err != nil {
+		// 40109: The data of the order cannot be found
+		if strings.Contains(err.Error(), "40109") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query order by client ID: %w", err)
+	}
+
+	var order struct {
+		OrderId string `json:"orderId"`
+		State   string `json:"state"`
+	}
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, fmt.Errorf("failed to parse order response: %w", err)
+	}
+	if order.OrderId == "" {
+		return nil, nil
+	}
+
+	return map[string]interface{}{
+		"orderId":       order.OrderId,
+		"symbol":        symbol,
+		"status":        order.State,
+		"clientOrderId": clientOid,
+	}, nil
+}
+
 // GetOpenOrders gets all open/pending orders for a symbol
 func (t *BitgetTrader) GetOpenOrders(symbol string) ([]types.OpenOrder, error) {
 	symbol = t.convertSymbol(symbol)
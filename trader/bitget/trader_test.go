@@ -0,0 +1,131 @@
+package bitget
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"nofx/trader/testutil"
+	"nofx/trader/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTrader creates a trader against a mock server without the constructor's position mode call
+func newTestTrader(serverURL string, testnet bool) *BitgetTrader {
+	return &BitgetTrader{
+		apiKey:         "key",
+		secretKey:      "secret",
+		passphrase:     "pass",
+		testnet:        testnet,
+		baseURL:        serverURL,
+		httpClient:     &http.Client{Timeout: 5 * time.Second},
+		cacheDuration:  15 * time.Second,
+		contractsCache: make(map[string]*BitgetContract),
+	}
+}
+
+func TestDoRequest_SignsWithPassphrase(t *testing.T) {
+	for _, testnet := range []bool{false, true} {
+		var got http.Header
+		var path string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Clone()
+			path = r.URL.RequestURI()
+			w.Write([]byte(`{"code":"00000","msg":"success","data":{}}`))
+		}))
+
+		trader := newTestTrader(server.URL, testnet)
+		_, err := trader.doRequest("GET", bitgetAccountPath, map[string]interface{}{"productType": "USDT-FUTURES"})
+		server.Close()
+		require.NoError(t, err)
+
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(got.Get("ACCESS-TIMESTAMP") + "GET" + path))
+		assert.Equal(t, base64.StdEncoding.EncodeToString(mac.Sum(nil)), got.Get("ACCESS-SIGN"))
+		assert.Equal(t, "key", got.Get("ACCESS-KEY"))
+		assert.Equal(t, "pass", got.Get("ACCESS-PASSPHRASE"))
+		if testnet {
+			assert.Equal(t, "1", got.Get("paptrading"), "demo trading requires the paptrading header")
+		} else {
+			assert.Empty(t, got.Get("paptrading"))
+		}
+	}
+}
+
+func TestGetCommissionHistory_TradeBillFees(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"code":"00000","msg":"success","data":{"bills":[` +
+			`{"billId":"b1","symbol":"BTCUSDT","amount":"0","fee":"-0.12","coin":"USDT","businessType":"open_long","cTime":"1700000000000"},` +
+			`{"billId":"b2","symbol":"BTCUSDT","amount":"-0.3","fee":"0","coin":"USDT","businessType":"contract_settle_fee","cTime":"1700000001000"}]}}`))
+	}))
+	defer server.Close()
+
+	var _ types.IncomeTrader = (*BitgetTrader)(nil)
+	records, err := newTestTrader(server.URL, false).GetCommissionHistory(time.UnixMilli(1700000000000), 0)
+	require.NoError(t, err)
+	assert.Contains(t, query, "startTime=1700000000000")
+	require.Len(t, records, 1)
+	assert.Equal(t, "b1", records[0].TranID)
+	assert.Equal(t, -0.12, records[0].Amount)
+	assert.Equal(t, types.IncomeTypeCommission, records[0].Type)
+}
+
+func TestDoRequest_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":"40037","msg":"Apikey does not exist"}`))
+	}))
+	defer server.Close()
+
+	_, err := newTestTrader(server.URL, false).doRequest("POST", bitgetOrderPath, map[string]interface{}{"symbol": "BTCUSDT"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "40037")
+}
+
+// newConformanceServer mock Bitget V2 API: a BTCUSDT long, no ETHUSDT position, one resting limit order
+func newConformanceServer(counter *testutil.RequestCounter) *httptest.Server {
+	return httptest.NewServer(counter.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data string
+		switch r.URL.Path {
+		case bitgetAccountPath:
+			data = `[{"marginCoin":"USDT","available":"8000","accountEquity":"10100.5","unrealizedPL":"100.5"}]`
+		case bitgetPositionPath:
+			data = `[{"symbol":"BTCUSDT","holdSide":"long","openPriceAvg":"50000","markPrice":"50500","total":"0.5","unrealizedPL":"250","leverage":"10"},` +
+				`{"symbol":"ETHUSDT","holdSide":"short","openPriceAvg":"3000","markPrice":"3000","total":"0"}]`
+		case bitgetContractsPath:
+			data = `[{"symbol":"` + r.URL.Query().Get("symbol") + `","minTradeNum":"0.001","volumePlace":"4","pricePlace":"1"}]`
+		case bitgetTickerPath:
+			if r.URL.Query().Get("symbol") != "BTCUSDT" {
+				w.Write([]byte(`{"code":"40034","msg":"Parameter symbol does not exist"}`))
+				return
+			}
+			data = `[{"lastPr":"50000"}]`
+		case bitgetPendingPath:
+			data = `{"entrustedList":[{"orderId":"1","symbol":"BTCUSDT","side":"buy","posSide":"long","orderType":"limit","price":"49000","size":"0.01"}]}`
+		case "/api/v2/mix/order/orders-plan-pending":
+			data = `{"entrustedList":[]}`
+		case bitgetOrderPath:
+			data = `{"orderId":"2","clientOid":"c2"}`
+		default:
+			data = `{}`
+		}
+		w.Write([]byte(`{"code":"00000","msg":"success","data":` + data + `}`))
+	})))
+}
+
+func TestBitgetTrader_Conformance(t *testing.T) {
+	counter := testutil.NewRequestCounter()
+	server := newConformanceServer(counter)
+	defer server.Close()
+
+	testutil.NewConformanceSuite(t, newTestTrader(server.URL, false), testutil.ConformanceOptions{
+		PositionFetches: counter.CountPath(bitgetPositionPath),
+	}).RunConformance()
+}
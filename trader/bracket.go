@@ -0,0 +1,80 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/store"
+	"strings"
+)
+
+// submitOpenWithProtection opens a position, submitting its stop loss/take profit with the entry
+// on exchanges implementing BracketTrader. bracketed false means the position (if any) is not yet
+// protected and the caller must protectOrClose it once opened.
+// A rejected bracket opens nothing: falling back to a plain entry would only open a position whose
+// stop loss is rejected the same way.
+func (at *AutoTrader) submitOpenWithProtection(side string, decision *kernel.Decision, quantity float64, key ClientOrderKey) (map[string]interface{}, bool, error) {
+	bt, ok := at.trader.(BracketTrader)
+	if !ok || (decision.StopLoss <= 0 && decision.TakeProfit <= 0) {
+		order, err := at.submitOpenOrder(side, decision.Symbol, quantity, decision.Leverage, key)
+		return order, false, err
+	}
+
+	bracket := &BracketOrder{
+		Symbol:     decision.Symbol,
+		Side:       strings.ToUpper(side),
+		Quantity:   quantity,
+		Leverage:   decision.Leverage,
+		StopLoss:   decision.StopLoss,
+		TakeProfit: decision.TakeProfit,
+		Key:        key,
+	}
+	order, err := exchangeCall(at, "Open "+side+" bracket "+decision.Symbol, true, func() (map[string]interface{}, error) {
+		return bt.OpenBracket(bracket)
+	})
+	if err == nil {
+		at.rememberStops(decision.Symbol, bracket.Side, decision.StopLoss, decision.TakeProfit)
+		logger.Infof("  🛡️ Bracket order placed: SL %.4f, TP %.4f", decision.StopLoss, decision.TakeProfit)
+		return order, true, nil
+	}
+
+	// The entry may have filled despite the error; its protection state is unknown then
+	if isAmbiguousOrderError(err) && !key.IsZero() {
+		if cot, ok := at.trader.(ClientOrderTrader); ok {
+			if existing, findErr := cot.FindOrderByClientID(decision.Symbol, key); findErr == nil && existing != nil {
+				logger.Warnf("  ⚠️ [%s] Bracket entry %s was accepted despite %v, placing protection separately", at.name, key, err)
+				return existing, false, nil
+			}
+		}
+	}
+	return nil, false, err
+}
+
+// protectOrClose places the stop loss/take profit of a freshly opened position
+// A position whose stop loss cannot be placed is closed again instead of being left unprotected.
+func (at *AutoTrader) protectOrClose(symbol, positionSide string, quantity, stopLoss, takeProfit float64) error {
+	slErr := at.setProtection(symbol, positionSide, quantity, stopLoss, takeProfit)
+	if slErr == nil {
+		return nil
+	}
+
+	positionSide = strings.ToUpper(positionSide)
+	side := strings.ToLower(positionSide)
+	logger.Errorf("🚨 [%s] Stop loss of %s %s could not be placed, closing the position: %v", at.name, symbol, side, slErr)
+	at.untrackPosition(symbol, side)
+	_, closeErr := exchangeCall(at, "Close unprotected "+side+" "+symbol, true, func() (map[string]interface{}, error) {
+		if positionSide == "SHORT" {
+			return at.trader.CloseShort(symbol, 0)
+		}
+		return at.trader.CloseLong(symbol, 0) // 0 = close all
+	})
+
+	details := map[string]interface{}{"symbol": symbol, "side": positionSide, "quantity": quantity, "stop_loss": stopLoss, "error": slErr.Error()}
+	if closeErr != nil {
+		details["close_error"] = closeErr.Error()
+		at.recordEvent(store.TraderEventUnprotected, fmt.Sprintf("%s %s has no stop loss and could not be closed, close it manually", symbol, side), details)
+		return fmt.Errorf("failed to set stop loss (%v) and to close the unprotected position: %w", slErr, closeErr)
+	}
+	at.recordEvent(store.TraderEventUnprotected, fmt.Sprintf("Stop loss of %s %s could not be placed, position closed", symbol, side), details)
+	return fmt.Errorf("failed to set stop loss, position closed: %w", slErr)
+}
@@ -0,0 +1,79 @@
+package trader
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"nofx/kernel"
+)
+
+// stubUnprotectedTrader rejects stop losses and records closes
+type stubUnprotectedTrader struct {
+	stubProtectionTrader
+	closeErr error
+	closed   []string
+}
+
+func (s *stubUnprotectedTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	return errors.New("order would immediately trigger")
+}
+
+func (s *stubUnprotectedTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	s.closed = append(s.closed, symbol+"_LONG")
+	return map[string]interface{}{"orderId": int64(2)}, s.closeErr
+}
+
+// stubBracketTrader opens positions with their protection attached
+type stubBracketTrader struct {
+	stubProtectionTrader
+	brackets []*BracketOrder
+}
+
+func (s *stubBracketTrader) OpenBracket(order *BracketOrder) (map[string]interface{}, error) {
+	s.brackets = append(s.brackets, order)
+	return map[string]interface{}{"orderId": int64(1)}, nil
+}
+
+func TestSubmitOpenWithProtection_UsesBracket(t *testing.T) {
+	exchange := &stubBracketTrader{}
+	at := &AutoTrader{name: "test", trader: exchange}
+
+	decision := &kernel.Decision{Symbol: "BTCUSDT", Leverage: 5, StopLoss: 90, TakeProfit: 120}
+	_, bracketed, err := at.submitOpenWithProtection("long", decision, 0.5, ClientOrderKey{})
+	if err != nil || !bracketed {
+		t.Fatalf("expected a bracketed entry, got bracketed=%v err=%v", bracketed, err)
+	}
+	if len(exchange.brackets) != 1 || exchange.brackets[0].Side != "LONG" || exchange.brackets[0].StopLoss != 90 {
+		t.Errorf("unexpected bracket: %+v", exchange.brackets)
+	}
+	if len(exchange.stopLosses) != 0 {
+		t.Errorf("bracketed entries must not place separate stops: %v", exchange.stopLosses)
+	}
+}
+
+func TestProtectOrClose_ClosesPositionWithoutStopLoss(t *testing.T) {
+	exchange := &stubUnprotectedTrader{}
+	at := &AutoTrader{name: "test", trader: exchange}
+
+	err := at.protectOrClose("BTCUSDT", "LONG", 1, 90, 110)
+	if err == nil || !strings.Contains(err.Error(), "position closed") {
+		t.Fatalf("expected the position to be closed, got %v", err)
+	}
+	if len(exchange.closed) != 1 {
+		t.Errorf("expected one close, got %v", exchange.closed)
+	}
+	if len(exchange.takeProfits) != 1 {
+		t.Errorf("take profit is still attempted: %v", exchange.takeProfits)
+	}
+
+	exchange.closeErr = errors.New("exchange unavailable")
+	if err := at.protectOrClose("BTCUSDT", "LONG", 1, 90, 0); err == nil || !strings.Contains(err.Error(), "unprotected") {
+		t.Errorf("failed rollback must be reported, got %v", err)
+	}
+
+	// Nothing requested: nothing to roll back
+	if err := at.protectOrClose("BTCUSDT", "LONG", 1, 0, 110); err != nil {
+		t.Errorf("take-profit-only entries are not closed: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package bybit
+
+import (
+	"context"
+	"fmt"
+	"nofx/trader/types"
+	"strconv"
+)
+
+// GetCollateralBalance gets the balance of every coin of the unified account (CollateralTrader implementation)
+// The unified account margins all contracts with every coin enabled as collateral, each coin is valued
+// at Bybit's USD value of it.
+func (t *BybitTrader) GetCollateralBalance() (*types.CollateralBalance, error) {
+	params := map[string]interface{}{
+		"accountType": "UNIFIED",
+	}
+	result, err := t.client.NewUtaBybitServiceWithParams(params).GetAccountWallet(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Bybit balance: %w", err)
+	}
+	if result.RetCode != 0 {
+		return nil, apiError("Bybit API error", result.RetCode, result.RetMsg)
+	}
+	resultData, ok := result.Result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Bybit balance return format error")
+	}
+	return parseCollateralBalance(resultData), nil
+}
+
+// parseCollateralBalance extracts the collateral coins from a wallet balance response
+func parseCollateralBalance(resultData map[string]interface{}) *types.CollateralBalance {
+	balance := &types.CollateralBalance{MultiAssetMargin: true}
+	list, _ := resultData["list"].([]interface{})
+	if len(list) == 0 {
+		return balance
+	}
+	account, _ := list[0].(map[string]interface{})
+	coins, _ := account["coin"].([]interface{})
+
+	parse := func(coin map[string]interface{}, key string) float64 {
+		s, _ := coin[key].(string)
+		v, _ := strconv.ParseFloat(s, 64)
+		return v
+	}
+	for _, c := range coins {
+		coin, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// Coins the account does not accept as margin don't back positions
+		if enabled, ok := coin["marginCollateral"].(bool); ok && !enabled {
+			continue
+		}
+		if enabled, ok := coin["collateralSwitch"].(bool); ok && !enabled {
+			continue
+		}
+		asset := types.CollateralAsset{
+			Asset:         fmt.Sprint(coin["coin"]),
+			WalletBalance: parse(coin, "walletBalance"),
+			UnrealizedPnL: parse(coin, "unrealisedPnl"),
+		}
+		if asset.WalletBalance == 0 && asset.UnrealizedPnL == 0 {
+			continue
+		}
+		// Free = wallet balance less the initial margin of positions and orders
+		asset.Available = asset.WalletBalance - parse(coin, "totalPositionIM") - parse(coin, "totalOrderIM")
+		if asset.Available < 0 {
+			asset.Available = 0
+		}
+		if equity, usd := parse(coin, "equity"), parse(coin, "usdValue"); equity > 0 && usd > 0 {
+			asset.IndexPrice = usd / equity
+		}
+		balance.Assets = append(balance.Assets, asset)
+	}
+	return balance
+}
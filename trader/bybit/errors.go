@@ -0,0 +1,39 @@
+package bybit
+
+import (
+	"fmt"
+	"nofx/trader/types"
+	"strconv"
+)
+
+// bybitErrorClasses Bybit V5 API retCodes by class
+var bybitErrorClasses = map[int]types.ErrorClass{
+	10006: types.ErrClassRateLimited, // Too many visits
+	10018: types.ErrClassRateLimited, // Exceeded IP rate limit
+
+	10003: types.ErrClassAuth, // Invalid API key
+	10004: types.ErrClassAuth, // Signature error
+	10005: types.ErrClassAuth, // Permission denied
+	10007: types.ErrClassAuth, // User authentication failed
+	10010: types.ErrClassAuth, // Unmatched IP
+	33004: types.ErrClassAuth, // API key expired
+
+	110004: types.ErrClassInsufficientMargin, // Wallet balance insufficient
+	110007: types.ErrClassInsufficientMargin, // Available balance insufficient
+	110012: types.ErrClassInsufficientMargin, // Insufficient available balance
+
+	110094: types.ErrClassInvalidOrder, // Order value below the minimum notional
+
+	10000: types.ErrClassNetwork, // Server timeout
+	10002: types.ErrClassNetwork, // Request time exceeds the time window
+	10016: types.ErrClassNetwork, // Server error
+}
+
+// apiError tags a failed Bybit response with the class of its retCode
+func apiError(prefix string, retCode int, retMsg string) error {
+	class, ok := bybitErrorClasses[retCode]
+	if !ok {
+		class = types.ErrClassUnknown
+	}
+	return types.NewExchangeError(class, strconv.Itoa(retCode), fmt.Errorf("%s: %s", prefix, retMsg))
+}
@@ -0,0 +1,715 @@
+package bybit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"nofx/logger"
+	"nofx/trader/endpoint"
+	"nofx/trader/types"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	bybit "github.com/bybit-exchange/bybit.go.api"
+)
+
+// bybitInverseContractSize USD face value of one Bybit inverse perpetual contract
+const bybitInverseContractSize = 1.0
+
+// BybitInverseTrader Bybit inverse (coin-margined) perpetual trader
+// Trades the {COIN}USD perpetual of one margin coin, where qty is a count of 1 USD contracts.
+// Symbols and quantities are taken and reported as the USDT pair and base coin amount like
+// BybitTrader; balances and PnL are in the margin coin.
+type BybitInverseTrader struct {
+	client     *bybit.Client
+	apiKey     string
+	secretKey  string
+	marginCoin string
+
+	// Balance cache
+	cachedBalance     map[string]interface{}
+	balanceCacheTime  time.Time
+	balanceCacheMutex sync.RWMutex
+
+	// Position cache
+	cachedPositions     []map[string]interface{}
+	positionsCacheTime  time.Time
+	positionsCacheMutex sync.RWMutex
+
+	cacheDuration time.Duration
+}
+
+// NewBybitInverseTrader creates a Bybit inverse trader margined in marginCoin (e.g. BTC)
+func NewBybitInverseTrader(apiKey, secretKey, marginCoin string) *BybitInverseTrader {
+	trader := &BybitInverseTrader{
+		client:        newBybitClient(apiKey, secretKey),
+		apiKey:        apiKey,
+		secretKey:     secretKey,
+		marginCoin:    strings.ToUpper(marginCoin),
+		cacheDuration: 15 * time.Second,
+	}
+	logger.Infof("🔵 [Bybit] Inverse trader initialized (margin coin: %s)", trader.marginCoin)
+	return trader
+}
+
+// MarginAsset coin the account is margined in
+func (t *BybitInverseTrader) MarginAsset() string {
+	return t.marginCoin
+}
+
+// GetContractSpec gets the inverse perpetual of a USDT pair (BTCUSDT -> BTCUSD)
+func (t *BybitInverseTrader) GetContractSpec(symbol string) (*types.ContractSpec, error) {
+	base, err := types.InverseBaseAsset(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if base != t.marginCoin {
+		return nil, fmt.Errorf("%s is not margined in %s, this trader only trades %sUSD", symbol, t.marginCoin, t.marginCoin)
+	}
+	return &types.ContractSpec{Symbol: base + "USD", MarginAsset: base, ContractSize: bybitInverseContractSize}, nil
+}
+
+// contract maps a USDT pair to the inverse contract symbol
+func (t *BybitInverseTrader) contract(symbol string) (string, error) {
+	spec, err := t.GetContractSpec(symbol)
+	if err != nil {
+		return "", err
+	}
+	return spec.Symbol, nil
+}
+
+// call runs one SDK request and returns its result map, tagging failed retCodes
+func call(prefix string, do func(ctx context.Context, opts ...bybit.RequestOption) (*bybit.ServerResponse, error)) (map[string]interface{}, error) {
+	result, err := do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", prefix, err)
+	}
+	if result.RetCode != 0 {
+		return nil, apiError(prefix, result.RetCode, result.RetMsg)
+	}
+	data, ok := result.Result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: return format error", prefix)
+	}
+	return data, nil
+}
+
+// parseFloatField parses a Bybit string number field
+func parseFloatField(m map[string]interface{}, key string) float64 {
+	s, _ := m[key].(string)
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
+
+// GetBalance gets the margin coin balance of the unified account (with cache), amounts in the margin coin
+func (t *BybitInverseTrader) GetBalance() (map[string]interface{}, error) {
+	t.balanceCacheMutex.RLock()
+	if t.cachedBalance != nil && time.Since(t.balanceCacheTime) < t.cacheDuration {
+		balance := t.cachedBalance
+		t.balanceCacheMutex.RUnlock()
+		return balance, nil
+	}
+	t.balanceCacheMutex.RUnlock()
+
+	params := map[string]interface{}{"accountType": "UNIFIED", "coin": t.marginCoin}
+	data, err := call("failed to get Bybit balance", t.client.NewUtaBybitServiceWithParams(params).GetAccountWallet)
+	if err != nil {
+		return nil, err
+	}
+
+	var equity, wallet, unrealized, available float64
+	list, _ := data["list"].([]interface{})
+	if len(list) > 0 {
+		account, _ := list[0].(map[string]interface{})
+		coins, _ := account["coin"].([]interface{})
+		for _, item := range coins {
+			coin, _ := item.(map[string]interface{})
+			if coin == nil || coin["coin"] != t.marginCoin {
+				continue
+			}
+			equity = parseFloatField(coin, "equity")
+			wallet = parseFloatField(coin, "walletBalance")
+			unrealized = parseFloatField(coin, "unrealisedPnl")
+			available = equity - parseFloatField(coin, "totalPositionIM") - parseFloatField(coin, "totalOrderIM")
+		}
+	}
+
+	balance := map[string]interface{}{
+		"totalEquity":           equity,
+		"totalWalletBalance":    wallet,
+		"availableBalance":      math.Max(available, 0),
+		"totalUnrealizedProfit": unrealized,
+		"balance":               equity,
+		"marginAsset":           t.marginCoin,
+	}
+
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = balance
+	t.balanceCacheTime = time.Now()
+	t.balanceCacheMutex.Unlock()
+	return balance, nil
+}
+
+// GetPositions gets inverse positions settled in the margin coin (with cache)
+// positionAmt is the base coin value of the contracts at mark price, "contracts" the exchange size.
+func (t *BybitInverseTrader) GetPositions() ([]map[string]interface{}, error) {
+	t.positionsCacheMutex.RLock()
+	if t.cachedPositions != nil && time.Since(t.positionsCacheTime) < t.cacheDuration {
+		positions := t.cachedPositions
+		t.positionsCacheMutex.RUnlock()
+		return positions, nil
+	}
+	t.positionsCacheMutex.RUnlock()
+
+	params := map[string]interface{}{"category": "inverse", "settleCoin": t.marginCoin}
+	data, err := call("failed to get Bybit positions", t.client.NewUtaBybitServiceWithParams(params).GetPositionList)
+	if err != nil {
+		return nil, err
+	}
+
+	var positions []map[string]interface{}
+	list, _ := data["list"].([]interface{})
+	for _, item := range list {
+		pos, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		contracts := parseFloatField(pos, "size")
+		contractSymbol, _ := pos["symbol"].(string)
+		if contracts == 0 || !strings.HasSuffix(contractSymbol, "USD") {
+			continue // Skip empty positions and dated futures
+		}
+
+		markPrice := parseFloatField(pos, "markPrice")
+		var quantity float64
+		if markPrice > 0 {
+			quantity = contracts * bybitInverseContractSize / markPrice
+		}
+		side := "long"
+		if strings.EqualFold(fmt.Sprint(pos["side"]), "sell") {
+			side = "short"
+			quantity = -quantity
+		}
+		createdTime, _ := strconv.ParseInt(fmt.Sprint(pos["createdTime"]), 10, 64)
+		updatedTime, _ := strconv.ParseInt(fmt.Sprint(pos["updatedTime"]), 10, 64)
+
+		positions = append(positions, map[string]interface{}{
+			"symbol":           contractSymbol + "T",
+			"side":             side,
+			"positionAmt":      quantity,
+			"contracts":        contracts,
+			"entryPrice":       parseFloatField(pos, "avgPrice"),
+			"markPrice":        markPrice,
+			"unRealizedProfit": parseFloatField(pos, "unrealisedPnl"),
+			"unrealizedPnL":    parseFloatField(pos, "unrealisedPnl"),
+			"liquidationPrice": parseFloatField(pos, "liqPrice"),
+			"leverage":         parseFloatField(pos, "leverage"),
+			"createdTime":      createdTime,
+			"updatedTime":      updatedTime,
+		})
+	}
+
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = positions
+	t.positionsCacheTime = time.Now()
+	t.positionsCacheMutex.Unlock()
+	return positions, nil
+}
+
+func (t *BybitInverseTrader) clearCache() {
+	t.balanceCacheMutex.Lock()
+	t.cachedBalance = nil
+	t.balanceCacheMutex.Unlock()
+	t.positionsCacheMutex.Lock()
+	t.cachedPositions = nil
+	t.positionsCacheMutex.Unlock()
+}
+
+// contractsFor converts a base coin quantity to whole contracts at the current price
+func (t *BybitInverseTrader) contractsFor(symbol string, quantity float64) (string, int64, error) {
+	spec, err := t.GetContractSpec(symbol)
+	if err != nil {
+		return "", 0, err
+	}
+	price, err := t.GetMarketPrice(symbol)
+	if err != nil {
+		return "", 0, err
+	}
+	contracts := spec.Contracts(quantity, price)
+	if contracts < 1 {
+		return "", 0, fmt.Errorf("position size too small: %.8f %s is below one %s contract (%.0f USD)",
+			quantity, t.marginCoin, spec.Symbol, spec.ContractSize)
+	}
+	return spec.Symbol, contracts, nil
+}
+
+// OpenLong opens a long position, quantity in base coin
+func (t *BybitInverseTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.open(symbol, quantity, leverage, "Buy")
+}
+
+// OpenShort opens a short position, quantity in base coin
+func (t *BybitInverseTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.open(symbol, quantity, leverage, "Sell")
+}
+
+func (t *BybitInverseTrader) open(symbol string, quantity float64, leverage int, side string) (map[string]interface{}, error) {
+	if err := t.CancelAllOrders(symbol); err != nil {
+		logger.Infof("⚠️ [Bybit] Failed to cancel old pending orders: %v", err)
+	}
+	if err := t.CancelStopOrders(symbol); err != nil {
+		logger.Infof("⚠️ [Bybit] Failed to cancel old stop orders: %v", err)
+	}
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		logger.Infof("⚠️ [Bybit] Failed to set leverage: %v", err)
+	}
+
+	contract, contracts, err := t.contractsFor(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]interface{}{
+		"category":    "inverse",
+		"symbol":      contract,
+		"side":        side,
+		"orderType":   "Market",
+		"qty":         strconv.FormatInt(contracts, 10),
+		"positionIdx": 0, // One-way position mode
+	}
+	data, err := call("Bybit open order failed", t.client.NewUtaBybitServiceWithParams(params).PlaceOrder)
+	if err != nil {
+		return nil, err
+	}
+	t.clearCache()
+
+	logger.Infof("  ✓ [Bybit] Opened %s %s %d contracts", side, contract, contracts)
+	return map[string]interface{}{
+		"orderId": data["orderId"],
+		"symbol":  symbol,
+		"status":  "NEW",
+	}, nil
+}
+
+// CloseLong closes a long position (quantity in base coin, 0 = close all)
+func (t *BybitInverseTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.close(symbol, "long", quantity)
+}
+
+// CloseShort closes a short position (quantity in base coin, 0 = close all)
+func (t *BybitInverseTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return t.close(symbol, "short", quantity)
+}
+
+func (t *BybitInverseTrader) close(symbol, side string, quantity float64) (map[string]interface{}, error) {
+	contract, err := t.contract(symbol)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := t.GetPositions()
+	if err != nil {
+		return nil, err
+	}
+	var held float64
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			held = pos["contracts"].(float64)
+		}
+	}
+	if held == 0 {
+		return nil, fmt.Errorf("no %s position to close", side)
+	}
+	contracts := int64(held)
+	if quantity > 0 {
+		if _, contracts, err = t.contractsFor(symbol, quantity); err != nil {
+			return nil, err
+		}
+		contracts = min(contracts, int64(held))
+	}
+
+	orderSide := "Sell" // Close long with Sell
+	if side == "short" {
+		orderSide = "Buy"
+	}
+	params := map[string]interface{}{
+		"category":    "inverse",
+		"symbol":      contract,
+		"side":        orderSide,
+		"orderType":   "Market",
+		"qty":         strconv.FormatInt(contracts, 10),
+		"positionIdx": 0,
+		"reduceOnly":  true,
+	}
+	data, err := call("Bybit close "+side+" failed", t.client.NewUtaBybitServiceWithParams(params).PlaceOrder)
+	if err != nil {
+		return nil, err
+	}
+	t.clearCache()
+
+	return map[string]interface{}{
+		"orderId": data["orderId"],
+		"symbol":  symbol,
+		"status":  "NEW",
+	}, nil
+}
+
+// SetLeverage sets leverage of the contract
+func (t *BybitInverseTrader) SetLeverage(symbol string, leverage int) error {
+	contract, err := t.contract(symbol)
+	if err != nil {
+		return err
+	}
+	params := map[string]interface{}{
+		"category":     "inverse",
+		"symbol":       contract,
+		"buyLeverage":  strconv.Itoa(leverage),
+		"sellLeverage": strconv.Itoa(leverage),
+	}
+	result, err := t.client.NewUtaBybitServiceWithParams(params).SetPositionLeverage(context.Background())
+	if err != nil {
+		if strings.Contains(err.Error(), "leverage not modified") {
+			return nil
+		}
+		return fmt.Errorf("failed to set leverage: %w", err)
+	}
+	if result.RetCode != 0 && result.RetCode != 110043 { // 110043 = leverage not modified
+		return apiError("failed to set leverage", result.RetCode, result.RetMsg)
+	}
+	return nil
+}
+
+// SetMarginMode sets position margin mode of the contract
+func (t *BybitInverseTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	contract, err := t.contract(symbol)
+	if err != nil {
+		return err
+	}
+	tradeMode := 1 // Isolated margin
+	if isCrossMargin {
+		tradeMode = 0
+	}
+	params := map[string]interface{}{"category": "inverse", "symbol": contract, "tradeMode": tradeMode}
+	result, err := t.client.NewUtaBybitServiceWithParams(params).SwitchPositionMargin(context.Background())
+	if err != nil {
+		if strings.Contains(err.Error(), "Cross/isolated margin mode is not modified") {
+			return nil
+		}
+		return fmt.Errorf("failed to set margin mode: %w", err)
+	}
+	if result.RetCode != 0 && result.RetCode != 110026 { // already in target mode
+		return apiError("failed to set margin mode", result.RetCode, result.RetMsg)
+	}
+	return nil
+}
+
+// GetMarketPrice gets the last price of the contract in USD
+func (t *BybitInverseTrader) GetMarketPrice(symbol string) (float64, error) {
+	contract, err := t.contract(symbol)
+	if err != nil {
+		return 0, err
+	}
+	params := map[string]interface{}{"category": "inverse", "symbol": contract}
+	data, err := call("failed to get market price", t.client.NewUtaBybitServiceWithParams(params).GetMarketTickers)
+	if err != nil {
+		return 0, err
+	}
+	list, _ := data["list"].([]interface{})
+	if len(list) == 0 {
+		return 0, fmt.Errorf("price data not found for %s", contract)
+	}
+	ticker, _ := list[0].(map[string]interface{})
+	lastPrice := parseFloatField(ticker, "lastPrice")
+	if lastPrice <= 0 {
+		return 0, fmt.Errorf("failed to parse price of %s", contract)
+	}
+	return lastPrice, nil
+}
+
+// SetStopLoss sets a conditional market order closing the position
+func (t *BybitInverseTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	return t.setCloseTrigger(symbol, positionSide, quantity, stopPrice, "stop loss")
+}
+
+// SetTakeProfit sets a conditional market order closing the position
+func (t *BybitInverseTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	return t.setCloseTrigger(symbol, positionSide, quantity, takeProfitPrice, "take profit")
+}
+
+func (t *BybitInverseTrader) setCloseTrigger(symbol, positionSide string, quantity, triggerPrice float64, what string) error {
+	contract, contracts, err := t.contractsFor(symbol, quantity)
+	if err != nil {
+		return err
+	}
+	currentPrice, err := t.GetMarketPrice(symbol)
+	if err != nil {
+		return err
+	}
+	side := "Sell"
+	if positionSide == "SHORT" {
+		side = "Buy"
+	}
+	triggerDirection := 2 // Price fall trigger
+	if triggerPrice > currentPrice {
+		triggerDirection = 1 // Price rise trigger
+	}
+	params := map[string]interface{}{
+		"category":         "inverse",
+		"symbol":           contract,
+		"side":             side,
+		"orderType":        "Market",
+		"qty":              strconv.FormatInt(contracts, 10),
+		"triggerPrice":     fmt.Sprintf("%v", triggerPrice),
+		"triggerDirection": triggerDirection,
+		"triggerBy":        "LastPrice",
+		"reduceOnly":       true,
+	}
+	if _, err := call("failed to set "+what, t.client.NewUtaBybitServiceWithParams(params).PlaceOrder); err != nil {
+		return err
+	}
+	logger.Infof("  ✓ [Bybit] %s order set: %s @ %.2f", what, contract, triggerPrice)
+	return nil
+}
+
+// CancelStopLossOrders cancels stop loss orders
+func (t *BybitInverseTrader) CancelStopLossOrders(symbol string) error {
+	return t.cancelConditionalOrders(symbol, "StopLoss", "Stop")
+}
+
+// CancelTakeProfitOrders cancels take profit orders
+func (t *BybitInverseTrader) CancelTakeProfitOrders(symbol string) error {
+	return t.cancelConditionalOrders(symbol, "TakeProfit", "PartialTakeProfit")
+}
+
+// CancelStopOrders cancels all stop loss and take profit orders
+func (t *BybitInverseTrader) CancelStopOrders(symbol string) error {
+	return t.cancelConditionalOrders(symbol, "StopLoss", "Stop", "TakeProfit", "PartialTakeProfit")
+}
+
+// CancelAllOrders cancels all pending orders of the contract
+func (t *BybitInverseTrader) CancelAllOrders(symbol string) error {
+	contract, err := t.contract(symbol)
+	if err != nil {
+		return err
+	}
+	params := map[string]interface{}{"category": "inverse", "symbol": contract}
+	if _, err := t.client.NewUtaBybitServiceWithParams(params).CancelAllOrders(context.Background()); err != nil {
+		return fmt.Errorf("failed to cancel all orders: %w", err)
+	}
+	return nil
+}
+
+// conditionalOrders open conditional orders of the contract
+func (t *BybitInverseTrader) conditionalOrders(contract string) ([]map[string]interface{}, error) {
+	params := map[string]interface{}{"category": "inverse", "symbol": contract, "orderFilter": "StopOrder"}
+	data, err := call("failed to get conditional orders", t.client.NewUtaBybitServiceWithParams(params).GetOpenOrders)
+	if err != nil {
+		return nil, err
+	}
+	var orders []map[string]interface{}
+	list, _ := data["list"].([]interface{})
+	for _, item := range list {
+		if order, ok := item.(map[string]interface{}); ok {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (t *BybitInverseTrader) cancelConditionalOrders(symbol string, stopOrderTypes ...string) error {
+	contract, err := t.contract(symbol)
+	if err != nil {
+		return err
+	}
+	orders, err := t.conditionalOrders(contract)
+	if err != nil {
+		return err
+	}
+	for _, order := range orders {
+		orderID, _ := order["orderId"].(string)
+		for _, stopOrderType := range stopOrderTypes {
+			if order["stopOrderType"] != stopOrderType || orderID == "" {
+				continue
+			}
+			params := map[string]interface{}{"category": "inverse", "symbol": contract, "orderId": orderID}
+			if _, err := call("failed to cancel order "+orderID, t.client.NewUtaBybitServiceWithParams(params).CancelOrder); err != nil {
+				logger.Infof("⚠️ [Bybit] %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// FormatQuantity rounds a base coin quantity to whole contracts at the current price, still in base coin
+func (t *BybitInverseTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	spec, err := t.GetContractSpec(symbol)
+	if err != nil {
+		return "", err
+	}
+	price, err := t.GetMarketPrice(symbol)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(spec.Quantity(float64(spec.Contracts(quantity, price)), price), 'f', 8, 64), nil
+}
+
+// GetOrderStatus gets order status, executedQty in base coin at the fill price
+func (t *BybitInverseTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	contract, err := t.contract(symbol)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]interface{}{"category": "inverse", "symbol": contract, "orderId": orderID}
+	data, err := call("failed to get order status", t.client.NewUtaBybitServiceWithParams(params).GetOrderHistory)
+	if err != nil {
+		return nil, err
+	}
+	list, _ := data["list"].([]interface{})
+	if len(list) == 0 {
+		return nil, fmt.Errorf("order %s not found", orderID)
+	}
+	order, _ := list[0].(map[string]interface{})
+
+	avgPrice := parseFloatField(order, "avgPrice")
+	contracts := parseFloatField(order, "cumExecQty")
+	var executedQty float64
+	if avgPrice > 0 {
+		executedQty = contracts * bybitInverseContractSize / avgPrice
+	}
+
+	status, _ := order["orderStatus"].(string)
+	switch status {
+	case "Filled":
+		status = "FILLED"
+	case "New", "Created":
+		status = "NEW"
+	case "Cancelled", "Rejected":
+		status = "CANCELED"
+	case "PartiallyFilled":
+		status = "PARTIALLY_FILLED"
+	}
+
+	return map[string]interface{}{
+		"orderId":     orderID,
+		"status":      status,
+		"avgPrice":    avgPrice,
+		"executedQty": executedQty,
+		"contracts":   contracts,
+		"commission":  parseFloatField(order, "cumExecFee"), // In margin coin
+	}, nil
+}
+
+// GetOpenOrders gets conditional orders of the contract, quantities in contracts
+func (t *BybitInverseTrader) GetOpenOrders(symbol string) ([]types.OpenOrder, error) {
+	contract, err := t.contract(symbol)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := t.conditionalOrders(contract)
+	if err != nil {
+		return nil, err
+	}
+	var result []types.OpenOrder
+	for _, order := range orders {
+		orderID, _ := order["orderId"].(string)
+		side, _ := order["side"].(string)
+		displayType, _ := order["orderType"].(string)
+		if stopOrderType, _ := order["stopOrderType"].(string); stopOrderType != "" {
+			displayType = stopOrderType
+		}
+		result = append(result, types.OpenOrder{
+			OrderID:   orderID,
+			Symbol:    symbol,
+			Side:      side,
+			Type:      displayType,
+			StopPrice: parseFloatField(order, "triggerPrice"),
+			Quantity:  parseFloatField(order, "qty"),
+			Status:    "NEW",
+		})
+	}
+	return result, nil
+}
+
+// GetClosedPnL gets closed inverse positions, PnL in the margin coin
+func (t *BybitInverseTrader) GetClosedPnL(startTime time.Time, limit int) ([]types.ClosedPnLRecord, error) {
+	contract := t.marginCoin + "USD"
+	queryParams := fmt.Sprintf("category=inverse&symbol=%s&startTime=%d&limit=%d", contract, startTime.UnixMilli(), limit)
+	timestamp := fmt.Sprintf("%d", time.Now().UnixMilli())
+	recvWindow := "5000"
+
+	h := hmac.New(sha256.New, []byte(t.secretKey))
+	h.Write([]byte(timestamp + t.apiKey + recvWindow + queryParams))
+
+	req, err := http.NewRequest("GET", endpoint.BaseURL(endpoint.Bybit)+"/v5/position/closed-pnl?"+queryParams, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-BAPI-API-KEY", t.apiKey)
+	req.Header.Set("X-BAPI-SIGN", hex.EncodeToString(h.Sum(nil)))
+	req.Header.Set("X-BAPI-SIGN-TYPE", "2")
+	req.Header.Set("X-BAPI-TIMESTAMP", timestamp)
+	req.Header.Set("X-BAPI-RECV-WINDOW", recvWindow)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Bybit API: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var result struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			List []map[string]interface{} `json:"list"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if result.RetCode != 0 {
+		return nil, apiError("Bybit API error", result.RetCode, result.RetMsg)
+	}
+
+	records := make([]types.ClosedPnLRecord, 0, len(result.Result.List))
+	for _, pnl := range result.Result.List {
+		orderID, _ := pnl["orderId"].(string)
+		exitPrice := parseFloatField(pnl, "avgExitPrice")
+		side := "long"
+		if pnl["side"] == "Sell" {
+			side = "short"
+		}
+		var quantity float64
+		if exitPrice > 0 {
+			quantity = parseFloatField(pnl, "qty") * bybitInverseContractSize / exitPrice
+		}
+		createdTime, _ := strconv.ParseInt(fmt.Sprint(pnl["createdTime"]), 10, 64)
+		updatedTime, _ := strconv.ParseInt(fmt.Sprint(pnl["updatedTime"]), 10, 64)
+		leverage, _ := strconv.Atoi(fmt.Sprint(pnl["leverage"]))
+
+		records = append(records, types.ClosedPnLRecord{
+			Symbol:      contract + "T",
+			Side:        side,
+			EntryPrice:  parseFloatField(pnl, "avgEntryPrice"),
+			ExitPrice:   exitPrice,
+			Quantity:    quantity,
+			RealizedPnL: parseFloatField(pnl, "closedPnl"),
+			Leverage:    leverage,
+			EntryTime:   time.UnixMilli(createdTime).UTC(),
+			ExitTime:    time.UnixMilli(updatedTime).UTC(),
+			OrderID:     orderID,
+			CloseType:   "unknown",
+			ExchangeID:  orderID,
+		})
+	}
+	return records, nil
+}
@@ -11,6 +11,7 @@ import (
 	"nofx/logger"
 	"nofx/market"
 	"nofx/store"
+	"nofx/trader/endpoint"
 	"sort"
 	"strconv"
 	"strings"
@@ -43,7 +44,7 @@ func (t *BybitTrader) GetTrades(startTime time.Time, limit int) ([]BybitTrade, e
 func (t *BybitTrader) getTradesViaHTTP(startTime time.Time, limit int) ([]BybitTrade, error) {
 	// Build query string
 	queryParams := fmt.Sprintf("category=linear&startTime=%d&limit=%d", startTime.UnixMilli(), limit)
-	url := "https://api.bybit.com/v5/execution/list?" + queryParams
+	url := endpoint.BaseURL(endpoint.Bybit) + "/v5/execution/list?" + queryParams
 
 	// Generate timestamp
 	timestamp := fmt.Sprintf("%d", time.Now().UnixMilli())
@@ -96,7 +97,7 @@ func (t *BybitTrader) getTradesViaHTTP(startTime time.Time, limit int) ([]BybitT
 	}
 
 	if result.RetCode != 0 {
-		return nil, fmt.Errorf("Bybit API error: %s", result.RetMsg)
+		return nil, apiError("Bybit API error", result.RetCode, result.RetMsg)
 	}
 
 	return t.parseTradesResult(result.Result.List)
@@ -0,0 +1,80 @@
+package bybit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"nofx/trader/endpoint"
+	"nofx/trader/types"
+	"time"
+)
+
+// GetAPIKeyPermissions gets the permissions of the API key (PermissionTrader implementation)
+func (t *BybitTrader) GetAPIKeyPermissions() (*types.APIKeyPermissions, error) {
+	url := endpoint.BaseURL(endpoint.Bybit) + "/v5/user/query-api"
+
+	timestamp := fmt.Sprintf("%d", time.Now().UnixMilli())
+	recvWindow := "5000"
+	h := hmac.New(sha256.New, []byte(t.secretKey))
+	h.Write([]byte(timestamp + t.apiKey + recvWindow))
+	signature := hex.EncodeToString(h.Sum(nil))
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-BAPI-API-KEY", t.apiKey)
+	req.Header.Set("X-BAPI-SIGN", signature)
+	req.Header.Set("X-BAPI-SIGN-TYPE", "2")
+	req.Header.Set("X-BAPI-TIMESTAMP", timestamp)
+	req.Header.Set("X-BAPI-RECV-WINDOW", recvWindow)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Bybit API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return parseBybitAPIKeyInfo(body)
+}
+
+// parseBybitAPIKeyInfo parses a /v5/user/query-api response into key permissions
+// Futures trading needs ContractTrade "Order" (classic account) or Derivatives "DerivativesTrade" (unified account).
+func parseBybitAPIKeyInfo(body []byte) (*types.APIKeyPermissions, error) {
+	var result struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			ReadOnly    int                 `json:"readOnly"`
+			Permissions map[string][]string `json:"permissions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if result.RetCode != 0 {
+		return nil, apiError("Bybit API error", result.RetCode, result.RetMsg)
+	}
+
+	has := func(group, perm string) bool {
+		for _, p := range result.Result.Permissions[group] {
+			if p == perm {
+				return true
+			}
+		}
+		return false
+	}
+	return &types.APIKeyPermissions{
+		FuturesTrading: result.Result.ReadOnly == 0 && (has("ContractTrade", "Order") || has("Derivatives", "DerivativesTrade")),
+		Withdrawal:     has("Wallet", "Withdraw"),
+	}, nil
+}
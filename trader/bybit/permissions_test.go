@@ -0,0 +1,28 @@
+package bybit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBybitAPIKeyInfo(t *testing.T) {
+	perms, err := parseBybitAPIKeyInfo([]byte(`{"retCode":0,"result":{"readOnly":0,"permissions":{"ContractTrade":["Order","Position"],"Wallet":["AccountTransfer"]}}}`))
+	assert.NoError(t, err)
+	assert.True(t, perms.FuturesTrading)
+	assert.False(t, perms.Withdrawal)
+
+	// Unified account key with withdrawals
+	perms, err = parseBybitAPIKeyInfo([]byte(`{"retCode":0,"result":{"readOnly":0,"permissions":{"Derivatives":["DerivativesTrade"],"Wallet":["AccountTransfer","Withdraw"]}}}`))
+	assert.NoError(t, err)
+	assert.True(t, perms.FuturesTrading)
+	assert.True(t, perms.Withdrawal)
+
+	// Read-only keys never trade, whatever permissions are listed
+	perms, err = parseBybitAPIKeyInfo([]byte(`{"retCode":0,"result":{"readOnly":1,"permissions":{"ContractTrade":["Order"]}}}`))
+	assert.NoError(t, err)
+	assert.False(t, perms.FuturesTrading)
+
+	_, err = parseBybitAPIKeyInfo([]byte(`{"retCode":10003,"retMsg":"API key is invalid."}`))
+	assert.Error(t, err)
+}
@@ -8,9 +8,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"math"
 	"net/http"
 	"nofx/logger"
+	"nofx/trader/endpoint"
 	"strconv"
 	"strings"
 	"sync"
@@ -36,9 +36,9 @@ type BybitTrader struct {
 	positionsCacheTime  time.Time
 	positionsCacheMutex sync.RWMutex
 
-	// Trading pair precision cache (symbol -> qtyStep)
-	qtyStepCache      map[string]float64
-	qtyStepCacheMutex sync.RWMutex
+	// Trading rules of all linear symbols (lot size, tick size, min notional)
+	filters     *types.SymbolFiltersCache
+	filtersOnce sync.Once
 
 	// Cache duration (15 seconds)
 	cacheDuration time.Duration
@@ -46,6 +46,21 @@ type BybitTrader struct {
 
 // NewBybitTrader creates a Bybit trader
 func NewBybitTrader(apiKey, secretKey string) *BybitTrader {
+	trader := &BybitTrader{
+		client:        newBybitClient(apiKey, secretKey),
+		apiKey:        apiKey,
+		secretKey:     secretKey,
+		cacheDuration: 15 * time.Second,
+	}
+
+	logger.Infof("🔵 [Bybit] Trader initialized")
+
+	return trader
+}
+
+// newBybitClient creates a mainnet client with the referer header set, sending requests to the
+// lowest-latency Bybit endpoint
+func newBybitClient(apiKey, secretKey string) *bybit.Client {
 	const src = "Up000938"
 
 	client := bybit.NewBybitHttpClient(apiKey, secretKey, bybit.WithBaseURL(bybit.MAINNET))
@@ -58,22 +73,11 @@ func NewBybitTrader(apiKey, secretKey string) *BybitTrader {
 		}
 
 		client.HTTPClient.Transport = &headerRoundTripper{
-			base:      defaultTransport,
+			base:      endpoint.Transport(endpoint.Bybit, defaultTransport),
 			refererID: src,
 		}
 	}
-
-	trader := &BybitTrader{
-		client:        client,
-		apiKey:        apiKey,
-		secretKey:     secretKey,
-		cacheDuration: 15 * time.Second,
-		qtyStepCache:  make(map[string]float64),
-	}
-
-	logger.Infof("🔵 [Bybit] Trader initialized")
-
-	return trader
+	return client
 }
 
 // headerRoundTripper HTTP RoundTripper for adding custom headers
@@ -109,7 +113,7 @@ func (t *BybitTrader) GetBalance() (map[string]interface{}, error) {
 	}
 
 	if result.RetCode != 0 {
-		return nil, fmt.Errorf("Bybit API error: %s", result.RetMsg)
+		return nil, apiError("Bybit API error", result.RetCode, result.RetMsg)
 	}
 
 	// Extract balance information
@@ -185,7 +189,7 @@ func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
 	}
 
 	if result.RetCode != 0 {
-		return nil, fmt.Errorf("Bybit API error: %s", result.RetMsg)
+		return nil, apiError("Bybit API error", result.RetCode, result.RetMsg)
 	}
 
 	resultData, ok := result.Result.(map[string]interface{})
@@ -279,9 +283,58 @@ func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
 
 // OpenLong opens a long position
 func (t *BybitTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
-	logger.Infof("[Bybit] ===== OpenLong called: symbol=%s, qty=%.6f, leverage=%d =====", symbol, quantity, leverage)
+	return t.OpenLongWithClientID(symbol, quantity, leverage, types.ClientOrderKey{})
+}
+
+// OpenLongWithClientID opens a long position with a deterministic order link ID
+func (t *BybitTrader) OpenLongWithClientID(symbol string, quantity float64, leverage int, key types.ClientOrderKey) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "Buy", quantity, leverage, key, nil)
+}
+
+// OpenShort opens a short position
+func (t *BybitTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenShortWithClientID(symbol, quantity, leverage, types.ClientOrderKey{})
+}
 
-	// First cancel all pending orders for this symbol (clean up old orders)
+// OpenShortWithClientID opens a short position with a deterministic order link ID
+func (t *BybitTrader) OpenShortWithClientID(symbol string, quantity float64, leverage int, key types.ClientOrderKey) (map[string]interface{}, error) {
+	return t.openPosition(symbol, "Sell", quantity, leverage, key, nil)
+}
+
+// OpenBracket opens a position with position-level TP/SL attached to the market order
+// Bybit sets them atomically with the fill, so the position never exists without them.
+func (t *BybitTrader) OpenBracket(order *types.BracketOrder) (map[string]interface{}, error) {
+	side := "Buy"
+	if order.Side == "SHORT" {
+		side = "Sell"
+	}
+	protection := map[string]interface{}{"tpslMode": "Full"}
+	if order.StopLoss > 0 {
+		protection["stopLoss"] = fmt.Sprintf("%v", order.StopLoss)
+		protection["slTriggerBy"] = "LastPrice"
+	}
+	if order.TakeProfit > 0 {
+		protection["takeProfit"] = fmt.Sprintf("%v", order.TakeProfit)
+		protection["tpTriggerBy"] = "LastPrice"
+	}
+	return t.openPosition(order.Symbol, side, order.Quantity, order.Leverage, order.Key, protection)
+}
+
+// openPosition places a market entry (Buy: long, Sell: short) with optional extra order params
+func (t *BybitTrader) openPosition(symbol, side string, quantity float64, leverage int, key types.ClientOrderKey, extra map[string]interface{}) (map[string]interface{}, error) {
+	action := "OpenLong"
+	if side == "Sell" {
+		action = "OpenShort"
+	}
+	logger.Infof("[Bybit] ===== %s called: symbol=%s, qty=%.6f, leverage=%d =====", action, symbol, quantity, leverage)
+
+	// Round quantity to the lot size and check the symbol's limits before touching existing orders
+	qtyStr, err := t.normalizeMarketOrder(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cancel all pending orders for this symbol (clean up old orders)
 	if err := t.CancelAllOrders(symbol); err != nil {
 		logger.Infof("⚠️ [Bybit] Failed to cancel old pending orders: %v", err)
 	}
@@ -295,22 +348,28 @@ func (t *BybitTrader) OpenLong(symbol string, quantity float64, leverage int) (m
 		logger.Infof("⚠️ [Bybit] Failed to set leverage: %v", err)
 	}
 
-	// Use FormatQuantity to format quantity
-	qtyStr, _ := t.FormatQuantity(symbol, quantity)
-
 	params := map[string]interface{}{
 		"category":    "linear",
 		"symbol":      symbol,
-		"side":        "Buy",
+		"side":        side,
 		"orderType":   "Market",
 		"qty":         qtyStr,
 		"positionIdx": 0, // One-way position mode
 	}
+	if !key.IsZero() {
+		params["orderLinkId"] = bybitOrderLinkID(key)
+	}
+	for k, v := range extra {
+		params[k] = v
+	}
 
-	logger.Infof("[Bybit] OpenLong placing order: %+v", params)
+	logger.Infof("[Bybit] %s placing order: %+v", action, params)
 
 	result, err := t.client.NewUtaBybitServiceWithParams(params).PlaceOrder(context.Background())
 	if err != nil {
+		if side == "Sell" {
+			return nil, fmt.Errorf("Bybit open short failed: %w", err)
+		}
 		return nil, fmt.Errorf("Bybit open long failed: %w", err)
 	}
 
@@ -320,47 +379,48 @@ func (t *BybitTrader) OpenLong(symbol string, quantity float64, leverage int) (m
 	return t.parseOrderResult(result)
 }
 
-// OpenShort opens a short position
-func (t *BybitTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
-	logger.Infof("[Bybit] ===== OpenShort called: symbol=%s, qty=%.6f, leverage=%d =====", symbol, quantity, leverage)
-
-	// First cancel all pending orders for this symbol (clean up old orders)
-	if err := t.CancelAllOrders(symbol); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to cancel old pending orders: %v", err)
-	}
-	// Also cancel conditional orders (stop-loss/take-profit) - Bybit keeps them separate
-	if err := t.CancelStopOrders(symbol); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to cancel old stop orders: %v", err)
-	}
-
-	// Set leverage first
-	if err := t.SetLeverage(symbol, leverage); err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to set leverage: %v", err)
-	}
-
-	// Use FormatQuantity to format quantity
-	qtyStr, _ := t.FormatQuantity(symbol, quantity)
+// bybitOrderLinkID Deterministic order link ID for an idempotent submission
+func bybitOrderLinkID(key types.ClientOrderKey) string {
+	return "nofx" + key.ID()
+}
 
+// FindOrderByClientID looks up an order submitted with the given client order key
+// Checks real-time orders first (includes recently closed orders), then order history
+func (t *BybitTrader) FindOrderByClientID(symbol string, key types.ClientOrderKey) (map[string]interface{}, error) {
 	params := map[string]interface{}{
 		"category":    "linear",
 		"symbol":      symbol,
-		"side":        "Sell",
-		"orderType":   "Market",
-		"qty":         qtyStr,
-		"positionIdx": 0, // One-way position mode
+		"orderLinkId": bybitOrderLinkID(key),
 	}
 
-	logger.Infof("[Bybit] OpenShort placing order: %+v", params)
+	service := t.client.NewUtaBybitServiceWithParams(params)
+	for _, query := range []func(context.Context, ...bybit.RequestOption) (*bybit.ServerResponse, error){service.GetOpenOrders, service.GetOrderHistory} {
+		result, err := query(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to query order by client ID: %w", err)
+		}
+		if result.RetCode != 0 {
+			return nil, apiError("API error", result.RetCode, result.RetMsg)
+		}
 
-	result, err := t.client.NewUtaBybitServiceWithParams(params).PlaceOrder(context.Background())
-	if err != nil {
-		return nil, fmt.Errorf("Bybit open short failed: %w", err)
-	}
+		resultData, _ := result.Result.(map[string]interface{})
+		list, _ := resultData["list"].([]interface{})
+		if len(list) == 0 {
+			continue
+		}
 
-	// Clear cache
-	t.clearCache()
+		order, _ := list[0].(map[string]interface{})
+		orderId, _ := order["orderId"].(string)
+		status, _ := order["orderStatus"].(string)
+		return map[string]interface{}{
+			"orderId":       orderId,
+			"symbol":        symbol,
+			"status":        status,
+			"clientOrderId": bybitOrderLinkID(key),
+		}, nil
+	}
 
-	return t.parseOrderResult(result)
+	return nil, nil
 }
 
 // CloseLong closes a long position
@@ -472,7 +532,7 @@ func (t *BybitTrader) SetLeverage(symbol string, leverage int) error {
 	}
 
 	if result.RetCode != 0 && result.RetCode != 110043 { // 110043 = leverage not modified
-		return fmt.Errorf("failed to set leverage: %s", result.RetMsg)
+		return apiError("failed to set leverage", result.RetCode, result.RetMsg)
 	}
 
 	return nil
@@ -500,7 +560,7 @@ func (t *BybitTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 	}
 
 	if result.RetCode != 0 && result.RetCode != 110026 { // already in target mode
-		return fmt.Errorf("failed to set margin mode: %s", result.RetMsg)
+		return apiError("failed to set margin mode", result.RetCode, result.RetMsg)
 	}
 
 	return nil
@@ -519,7 +579,7 @@ func (t *BybitTrader) GetMarketPrice(symbol string) (float64, error) {
 	}
 
 	if result.RetCode != 0 {
-		return 0, fmt.Errorf("API error: %s", result.RetMsg)
+		return 0, apiError("API error", result.RetCode, result.RetMsg)
 	}
 
 	resultData, ok := result.Result.(map[string]interface{})
@@ -582,7 +642,7 @@ func (t *BybitTrader) SetStopLoss(symbol string, positionSide string, quantity,
 	}
 
 	if result.RetCode != 0 {
-		return fmt.Errorf("failed to set stop loss: %s", result.RetMsg)
+		return apiError("failed to set stop loss", result.RetCode, result.RetMsg)
 	}
 
 	logger.Infof("  ✓ [Bybit] Stop loss order set: %s @ %.2f", symbol, stopPrice)
@@ -628,13 +688,45 @@ func (t *BybitTrader) SetTakeProfit(symbol string, positionSide string, quantity
 	}
 
 	if result.RetCode != 0 {
-		return fmt.Errorf("failed to set take profit: %s", result.RetMsg)
+		return apiError("failed to set take profit", result.RetCode, result.RetMsg)
 	}
 
 	logger.Infof("  ✓ [Bybit] Take profit order set: %s @ %.2f", symbol, takeProfitPrice)
 	return nil
 }
 
+// SetPartialTakeProfit take profits are reduce-only orders of their own quantity, so several can be open at once
+func (t *BybitTrader) SetPartialTakeProfit(symbol string, positionSide string, quantity, price float64) error {
+	return t.SetTakeProfit(symbol, positionSide, quantity, price)
+}
+
+// SetStopLossTakeProfit sets position-level TP/SL via trading-stop
+// Full mode TP/SL close the whole position, and Bybit cancels the other leg once one triggers
+func (t *BybitTrader) SetStopLossTakeProfit(symbol string, positionSide string, quantity, stopPrice, takeProfitPrice float64) error {
+	params := map[string]interface{}{
+		"category":    "linear",
+		"symbol":      symbol,
+		"tpslMode":    "Full",
+		"stopLoss":    fmt.Sprintf("%v", stopPrice),
+		"takeProfit":  fmt.Sprintf("%v", takeProfitPrice),
+		"slTriggerBy": "LastPrice",
+		"tpTriggerBy": "LastPrice",
+		"positionIdx": 0, // One-way position mode
+	}
+
+	result, err := t.client.NewUtaBybitServiceWithParams(params).SetPositionTradingStop(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to set stop loss/take profit: %w", err)
+	}
+
+	if result.RetCode != 0 {
+		return apiError("failed to set stop loss/take profit", result.RetCode, result.RetMsg)
+	}
+
+	logger.Infof("  ✓ [Bybit] Position TP/SL set: %s SL @ %.2f, TP @ %.2f", symbol, stopPrice, takeProfitPrice)
+	return nil
+}
+
 // CancelStopLossOrders cancels stop loss orders
 func (t *BybitTrader) CancelStopLossOrders(symbol string) error {
 	return t.cancelConditionalOrders(symbol, "StopLoss")
@@ -671,86 +763,103 @@ func (t *BybitTrader) CancelStopOrders(symbol string) error {
 	return nil
 }
 
-// getQtyStep retrieves the quantity step for a trading pair
-func (t *BybitTrader) getQtyStep(symbol string) float64 {
-	// Check cache first
-	t.qtyStepCacheMutex.RLock()
-	if step, ok := t.qtyStepCache[symbol]; ok {
-		t.qtyStepCacheMutex.RUnlock()
-		return step
-	}
-	t.qtyStepCacheMutex.RUnlock()
+// symbolFilters returns a symbol's trading rules from the instruments info, loaded once for all symbols
+func (t *BybitTrader) symbolFilters(symbol string) (types.SymbolFilters, error) {
+	t.filtersOnce.Do(func() {
+		t.filters = types.NewSymbolFiltersCache(types.SymbolFiltersTTL, func() (map[string]types.SymbolFilters, error) {
+			return loadSymbolFilters("linear")
+		})
+	})
+	return t.filters.Get(symbol)
+}
 
-	// Call public API directly to get contract information
-	url := fmt.Sprintf("https://api.bybit.com/v5/market/instruments-info?category=linear&symbol=%s", symbol)
-	resp, err := http.Get(url)
-	if err != nil {
-		logger.Infof("⚠️ [Bybit] Failed to get precision info for %s: %v", symbol, err)
-		return 1 // Default to integer
-	}
-	defer resp.Body.Close()
+// loadSymbolFilters loads the lot size and price filters of all instruments of a category from the public API
+func loadSymbolFilters(category string) (map[string]types.SymbolFilters, error) {
+	filters := make(map[string]types.SymbolFilters)
+	cursor := ""
+	for {
+		url := fmt.Sprintf("%s/v5/market/instruments-info?category=%s&limit=1000&cursor=%s", endpoint.BaseURL(endpoint.Bybit), category, cursor)
+		resp, err := http.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 1
-	}
+		var result struct {
+			RetCode int    `json:"retCode"`
+			RetMsg  string `json:"retMsg"`
+			Result  struct {
+				List []struct {
+					Symbol        string `json:"symbol"`
+					LotSizeFilter struct {
+						QtyStep          string `json:"qtyStep"`
+						MinOrderQty      string `json:"minOrderQty"`
+						MaxOrderQty      string `json:"maxOrderQty"`
+						MaxMktOrderQty   string `json:"maxMktOrderQty"`
+						MinNotionalValue string `json:"minNotionalValue"`
+					} `json:"lotSizeFilter"`
+					PriceFilter struct {
+						TickSize string `json:"tickSize"`
+					} `json:"priceFilter"`
+				} `json:"list"`
+				NextPageCursor string `json:"nextPageCursor"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse instruments info: %w", err)
+		}
+		if result.RetCode != 0 {
+			return nil, apiError("failed to get instruments info", result.RetCode, result.RetMsg)
+		}
 
-	var result struct {
-		RetCode int `json:"retCode"`
-		Result  struct {
-			List []struct {
-				LotSizeFilter struct {
-					QtyStep string `json:"qtyStep"`
-				} `json:"lotSizeFilter"`
-			} `json:"list"`
-		} `json:"result"`
+		for _, inst := range result.Result.List {
+			lot := inst.LotSizeFilter
+			filters[inst.Symbol] = types.SymbolFilters{
+				Symbol:       inst.Symbol,
+				StepSize:     types.ParseFilterValue(lot.QtyStep),
+				MinQty:       types.ParseFilterValue(lot.MinOrderQty),
+				MaxQty:       types.ParseFilterValue(lot.MaxOrderQty),
+				MaxMarketQty: types.ParseFilterValue(lot.MaxMktOrderQty),
+				TickSize:     types.ParseFilterValue(inst.PriceFilter.TickSize),
+				MinNotional:  types.ParseFilterValue(lot.MinNotionalValue),
+			}
+		}
+		cursor = result.Result.NextPageCursor
+		if cursor == "" || len(result.Result.List) == 0 {
+			break
+		}
 	}
 
-	if err := json.Unmarshal(body, &result); err != nil {
-		return 1
-	}
+	logger.Infof("🔵 [Bybit] Loaded %s trading rules of %d symbols", category, len(filters))
+	return filters, nil
+}
 
-	if result.RetCode != 0 || len(result.Result.List) == 0 {
-		return 1
+// normalizeMarketOrder rounds a market order's quantity to the symbol's lot size and checks it against
+// the quantity limits and minimum notional at the current price, before anything is sent to the exchange
+func (t *BybitTrader) normalizeMarketOrder(symbol string, quantity float64) (string, error) {
+	filters, err := t.symbolFilters(symbol)
+	if err != nil {
+		return "", err
 	}
-
-	qtyStep, _ := strconv.ParseFloat(result.Result.List[0].LotSizeFilter.QtyStep, 64)
-	if qtyStep <= 0 {
-		qtyStep = 1
+	price, err := t.GetMarketPrice(symbol)
+	if err != nil {
+		return "", fmt.Errorf("failed to get market price: %w", err)
 	}
-
-	// Cache result
-	t.qtyStepCacheMutex.Lock()
-	t.qtyStepCache[symbol] = qtyStep
-	t.qtyStepCacheMutex.Unlock()
-
-	logger.Infof("🔵 [Bybit] %s qtyStep: %v", symbol, qtyStep)
-
-	return qtyStep
+	return filters.NormalizeOrder(quantity, price, true)
 }
 
-// FormatQuantity formats quantity
+// FormatQuantity rounds quantity down to the symbol's lot size
 func (t *BybitTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
-	// Get qtyStep for this symbol
-	qtyStep := t.getQtyStep(symbol)
-
-	// Align quantity according to qtyStep (round down to nearest step)
-	alignedQty := math.Floor(quantity/qtyStep) * qtyStep
-
-	// Calculate required decimal places
-	decimals := 0
-	if qtyStep < 1 {
-		stepStr := strconv.FormatFloat(qtyStep, 'f', -1, 64)
-		if idx := strings.Index(stepStr, "."); idx >= 0 {
-			decimals = len(stepStr) - idx - 1
-		}
+	filters, err := t.symbolFilters(symbol)
+	if err != nil {
+		logger.Infof("⚠️ [Bybit] Failed to get trading rules of %s, using 3 decimals: %v", symbol, err)
+		filters = types.SymbolFilters{Symbol: symbol, StepSize: 0.001}
 	}
-
-	// Format
-	format := fmt.Sprintf("%%.%df", decimals)
-	formatted := fmt.Sprintf(format, alignedQty)
-
-	return formatted, nil
+	return filters.FormatQuantity(quantity), nil
 }
 
 // Helper methods
@@ -767,7 +876,7 @@ func (t *BybitTrader) clearCache() {
 
 func (t *BybitTrader) parseOrderResult(result *bybit.ServerResponse) (map[string]interface{}, error) {
 	if result.RetCode != 0 {
-		return nil, fmt.Errorf("order placement failed: %s", result.RetMsg)
+		return nil, apiError("order placement failed", result.RetCode, result.RetMsg)
 	}
 
 	resultData, ok := result.Result.(map[string]interface{})
@@ -797,7 +906,7 @@ func (t *BybitTrader) GetOrderStatus(symbol string, orderID string) (map[string]
 	}
 
 	if result.RetCode != 0 {
-		return nil, fmt.Errorf("API error: %s", result.RetMsg)
+		return nil, apiError("API error", result.RetCode, result.RetMsg)
 	}
 
 	resultData, ok := result.Result.(map[string]interface{})
@@ -910,7 +1019,7 @@ func (t *BybitTrader) GetClosedPnL(startTime time.Time, limit int) ([]types.Clos
 func (t *BybitTrader) getClosedPnLViaHTTP(startTime time.Time, limit int) ([]types.ClosedPnLRecord, error) {
 	// Build query string
 	queryParams := fmt.Sprintf("category=linear&startTime=%d&limit=%d", startTime.UnixMilli(), limit)
-	url := "https://api.bybit.com/v5/position/closed-pnl?" + queryParams
+	url := endpoint.BaseURL(endpoint.Bybit) + "/v5/position/closed-pnl?" + queryParams
 
 	// Generate timestamp
 	timestamp := fmt.Sprintf("%d", time.Now().UnixMilli())
@@ -961,12 +1070,104 @@ func (t *BybitTrader) getClosedPnLViaHTTP(startTime time.Time, limit int) ([]typ
 	}
 
 	if result.RetCode != 0 {
-		return nil, fmt.Errorf("Bybit API error: %s", result.RetMsg)
+		return nil, apiError("Bybit API error", result.RetCode, result.RetMsg)
 	}
 
 	return t.parseClosedPnLResult(result.Result)
 }
 
+// GetFundingHistory retrieves funding settlements from Bybit transaction log
+func (t *BybitTrader) GetFundingHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	return t.getTransactionLog("SETTLEMENT", types.IncomeTypeFunding, startTime, limit)
+}
+
+// GetCommissionHistory retrieves trading fees from Bybit transaction log
+func (t *BybitTrader) GetCommissionHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	return t.getTransactionLog("TRADE", types.IncomeTypeCommission, startTime, limit)
+}
+
+// getTransactionLog makes direct HTTP call to Bybit /v5/account/transaction-log (unified account)
+// Bybit reports funding and fee as positive when paid, so amounts are negated to balance changes
+func (t *BybitTrader) getTransactionLog(logType, recordType string, startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 50
+	}
+	queryParams := fmt.Sprintf("accountType=UNIFIED&category=linear&type=%s&startTime=%d&limit=%d",
+		logType, startTime.UnixMilli(), limit)
+	url := endpoint.BaseURL(endpoint.Bybit) + "/v5/account/transaction-log?" + queryParams
+
+	timestamp := fmt.Sprintf("%d", time.Now().UnixMilli())
+	recvWindow := "5000"
+	h := hmac.New(sha256.New, []byte(t.secretKey))
+	h.Write([]byte(timestamp + t.apiKey + recvWindow + queryParams))
+	signature := hex.EncodeToString(h.Sum(nil))
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-BAPI-API-KEY", t.apiKey)
+	req.Header.Set("X-BAPI-SIGN", signature)
+	req.Header.Set("X-BAPI-SIGN-TYPE", "2")
+	req.Header.Set("X-BAPI-TIMESTAMP", timestamp)
+	req.Header.Set("X-BAPI-RECV-WINDOW", recvWindow)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Bybit API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var result struct {
+		RetCode int    `json:"retCode"`
+		RetMsg  string `json:"retMsg"`
+		Result  struct {
+			List []struct {
+				ID              string `json:"id"`
+				Symbol          string `json:"symbol"`
+				Currency        string `json:"currency"`
+				Funding         string `json:"funding"`
+				Fee             string `json:"fee"`
+				TransactionTime string `json:"transactionTime"`
+			} `json:"list"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if result.RetCode != 0 {
+		return nil, apiError("Bybit API error", result.RetCode, result.RetMsg)
+	}
+
+	records := make([]types.IncomeRecord, 0, len(result.Result.List))
+	for _, item := range result.Result.List {
+		raw := item.Fee
+		if recordType == types.IncomeTypeFunding {
+			raw = item.Funding
+		}
+		amount, _ := strconv.ParseFloat(raw, 64)
+		if amount == 0 {
+			continue
+		}
+		ts, _ := strconv.ParseInt(item.TransactionTime, 10, 64)
+		records = append(records, types.IncomeRecord{
+			TranID: item.ID,
+			Symbol: item.Symbol,
+			Type:   recordType,
+			Amount: -amount,
+			Asset:  item.Currency,
+			Time:   time.UnixMilli(ts).UTC(),
+		})
+	}
+	return records, nil
+}
+
 // parseClosedPnLResult parses the closed PnL result from Bybit API
 func (t *BybitTrader) parseClosedPnLResult(resultData interface{}) ([]types.ClosedPnLRecord, error) {
 	data, ok := resultData.(map[string]interface{})
@@ -1110,14 +1311,16 @@ func (t *BybitTrader) GetOpenOrders(symbol string) ([]types.OpenOrder, error) {
 // PlaceLimitOrder places a limit order for grid trading
 // Implements GridTrader interface
 func (t *BybitTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*types.LimitOrderResult, error) {
-	// Format quantity
-	qtyStr, err := t.FormatQuantity(req.Symbol, req.Quantity)
+	// Round price to the tick size and quantity to the lot size, checking the symbol's limits
+	filters, err := t.symbolFilters(req.Symbol)
 	if err != nil {
-		return nil, fmt.Errorf("failed to format quantity: %w", err)
+		return nil, err
 	}
-
-	// Format price
-	priceStr := fmt.Sprintf("%.8f", req.Price)
+	qtyStr, err := filters.NormalizeOrder(req.Quantity, filters.NormalizePrice(req.Price), false)
+	if err != nil {
+		return nil, err
+	}
+	priceStr := filters.FormatPrice(req.Price)
 
 	// Set leverage if specified
 	if req.Leverage > 0 {
@@ -1164,7 +1367,7 @@ func (t *BybitTrader) PlaceLimitOrder(req *types.LimitOrderRequest) (*types.Limi
 			}
 		}
 	} else {
-		return nil, fmt.Errorf("Bybit order failed: %s", result.RetMsg)
+		return nil, apiError("Bybit order failed", result.RetCode, result.RetMsg)
 	}
 
 	logger.Infof("✓ [Bybit] Limit order placed: %s %s @ %s, qty=%s, orderID=%s",
@@ -1197,7 +1400,7 @@ func (t *BybitTrader) CancelOrder(symbol, orderID string) error {
 	}
 
 	if result.RetCode != 0 {
-		return fmt.Errorf("Bybit cancel order failed: %s", result.RetMsg)
+		return apiError("Bybit cancel order failed", result.RetCode, result.RetMsg)
 	}
 
 	logger.Infof("✓ [Bybit] Order cancelled: %s %s", symbol, orderID)
@@ -1212,7 +1415,7 @@ func (t *BybitTrader) GetOrderBook(symbol string, depth int) (bids, asks [][]flo
 	}
 
 	// Use HTTP request directly since the SDK doesn't expose GetOrderbook
-	url := fmt.Sprintf("https://api.bybit.com/v5/market/orderbook?category=linear&symbol=%s&limit=%d", symbol, depth)
+	url := fmt.Sprintf("%s/v5/market/orderbook?category=linear&symbol=%s&limit=%d", endpoint.BaseURL(endpoint.Bybit), symbol, depth)
 	resp, err := http.Get(url)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get order book: %w", err)
@@ -1239,7 +1442,7 @@ func (t *BybitTrader) GetOrderBook(symbol string, depth int) (bids, asks [][]flo
 	}
 
 	if result.RetCode != 0 {
-		return nil, nil, fmt.Errorf("Bybit get orderbook failed: %s", result.RetMsg)
+		return nil, nil, apiError("Bybit get orderbook failed", result.RetCode, result.RetMsg)
 	}
 
 	// Parse bids
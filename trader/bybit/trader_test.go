@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	bybit "github.com/bybit-exchange/bybit.go.api"
 	"github.com/stretchr/testify/assert"
 	"nofx/trader/testutil"
 	"nofx/trader/types"
@@ -22,14 +23,16 @@ import (
 type BybitTraderTestSuite struct {
 	*testutil.TraderTestSuite // Embeds base test suite
 	mockServer              *httptest.Server
+	counter                 *testutil.RequestCounter
 }
 
 // NewBybitTraderTestSuite Create Bybit test suite
-// Note: Due to Bybit SDK encapsulation design, cannot easily inject mock HTTP client
-// Therefore this test suite is mainly used for interface compliance verification, not API call testing
+// The trader's SDK client is pointed at the mock server; trading rules are preloaded
+// because they are fetched from the public API outside the SDK client.
 func NewBybitTraderTestSuite(t *testing.T) *BybitTraderTestSuite {
-	// Create mock HTTP server (for response format verification)
-	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	// Create mock HTTP server (wrapped with request counter for conformance checks)
+	counter := testutil.NewRequestCounter()
+	mockServer := httptest.NewServer(counter.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		path := r.URL.Path
 		var respBody interface{}
 
@@ -55,6 +58,54 @@ func NewBybitTraderTestSuite(t *testing.T) *BybitTraderTestSuite {
 					},
 				},
 			}
+		case path == "/v5/position/list":
+			respBody = map[string]interface{}{
+				"retCode": 0,
+				"retMsg":  "OK",
+				"result": map[string]interface{}{
+					"list": []map[string]interface{}{
+						{
+							"symbol":        "BTCUSDT",
+							"side":          "Buy",
+							"size":          "0.5",
+							"avgPrice":      "50000.00",
+							"markPrice":     "50500.00",
+							"unrealisedPnl": "250.00",
+							"liqPrice":      "45000.00",
+							"leverage":      "10",
+						},
+					},
+				},
+			}
+
+		case path == "/v5/market/tickers":
+			list := []map[string]interface{}{}
+			switch r.URL.Query().Get("symbol") {
+			case "BTCUSDT":
+				list = append(list, map[string]interface{}{"symbol": "BTCUSDT", "lastPrice": "50000.00"})
+			case "ETHUSDT":
+				list = append(list, map[string]interface{}{"symbol": "ETHUSDT", "lastPrice": "3000.00"})
+			}
+			respBody = map[string]interface{}{
+				"retCode": 0,
+				"retMsg":  "OK",
+				"result":  map[string]interface{}{"category": "linear", "list": list},
+			}
+
+		case path == "/v5/order/create":
+			respBody = map[string]interface{}{
+				"retCode": 0,
+				"retMsg":  "OK",
+				"result":  map[string]interface{}{"orderId": "123456", "orderLinkId": ""},
+			}
+
+		case path == "/v5/order/realtime":
+			respBody = map[string]interface{}{
+				"retCode": 0,
+				"retMsg":  "OK",
+				"result":  map[string]interface{}{"list": []map[string]interface{}{}},
+			}
+
 		default:
 			respBody = map[string]interface{}{
 				"retCode": 0,
@@ -65,10 +116,19 @@ func NewBybitTraderTestSuite(t *testing.T) *BybitTraderTestSuite {
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(respBody)
-	}))
+	})))
 
-	// Create real Bybit trader (for interface compliance testing)
+	// Create real Bybit trader and route its SDK client to the mock server
 	traderInstance := NewBybitTrader("test_api_key", "test_secret_key")
+	traderInstance.client = bybit.NewBybitHttpClient("test_api_key", "test_secret_key", bybit.WithBaseURL(mockServer.URL))
+	traderInstance.filtersOnce.Do(func() {
+		traderInstance.filters = types.NewSymbolFiltersCache(types.SymbolFiltersTTL, func() (map[string]types.SymbolFilters, error) {
+			return map[string]types.SymbolFilters{
+				"BTCUSDT": {Symbol: "BTCUSDT", StepSize: 0.001, MinQty: 0.001, TickSize: 0.1, MinNotional: 5},
+				"ETHUSDT": {Symbol: "ETHUSDT", StepSize: 0.01, MinQty: 0.01, TickSize: 0.01, MinNotional: 5},
+			}, nil
+		})
+	})
 
 	// Create base suite
 	baseSuite := testutil.NewTraderTestSuite(t, traderInstance)
@@ -76,6 +136,7 @@ func NewBybitTraderTestSuite(t *testing.T) *BybitTraderTestSuite {
 	return &BybitTraderTestSuite{
 		TraderTestSuite: baseSuite,
 		mockServer:      mockServer,
+		counter:         counter,
 	}
 }
 
@@ -94,6 +155,17 @@ func (s *BybitTraderTestSuite) Cleanup() {
 // TestBybitTrader_InterfaceCompliance Test interface compliance
 func TestBybitTrader_InterfaceCompliance(t *testing.T) {
 	var _ types.Trader = (*BybitTrader)(nil)
+	var _ types.BracketTrader = (*BybitTrader)(nil)
+}
+
+// TestBybitTrader_Conformance runs the Trader contract conformance suite
+func TestBybitTrader_Conformance(t *testing.T) {
+	suite := NewBybitTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	testutil.NewConformanceSuite(t, suite.Trader, testutil.ConformanceOptions{
+		PositionFetches: suite.counter.Count("/v5/position/list"),
+	}).RunConformance()
 }
 
 // ============================================================
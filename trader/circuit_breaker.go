@@ -0,0 +1,129 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+	"nofx/logger"
+	"nofx/store"
+	"time"
+)
+
+// ErrCircuitBreakerCooldown returned by Run while the trader is still cooling down after a trip
+var ErrCircuitBreakerCooldown = errors.New("circuit breaker cooldown active")
+
+// equityFromBalance extracts total equity from exchange balance info
+func equityFromBalance(balance map[string]interface{}) float64 {
+	if equity, ok := balance["total_equity"].(float64); ok {
+		return equity
+	}
+	if total, ok := balance["totalWalletBalance"].(float64); ok {
+		if unrealized, ok := balance["totalUnrealizedProfit"].(float64); ok {
+			return total + unrealized
+		}
+		return total
+	}
+	return 0
+}
+
+// prepareCircuitBreaker refuses to start during cooldown; once cooldown has passed,
+// peak equity and losing streak tracking restart from now
+func (at *AutoTrader) prepareCircuitBreaker() error {
+	if at.store == nil {
+		return nil
+	}
+	cbStore := at.store.CircuitBreaker()
+	state, err := cbStore.Get(at.id)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to load circuit breaker state: %v", at.name, err)
+		return nil
+	}
+	if state.InCooldown(time.Now()) {
+		return fmt.Errorf("%w until %s (%s)", ErrCircuitBreakerCooldown,
+			state.CooldownUntil.Format(time.RFC3339), state.TripReason)
+	}
+	if state == nil || state.Tripped {
+		if err := cbStore.Reset(at.id, at.userID); err != nil {
+			logger.Warnf("⚠️ [%s] Failed to reset circuit breaker: %v", at.name, err)
+		}
+	}
+	return nil
+}
+
+// checkCircuitBreaker updates peak equity and trips the breaker when a limit is exceeded
+// Returns the trip reason, empty when trading may continue
+func (at *AutoTrader) checkCircuitBreaker(equity float64) string {
+	cfg := at.config.CircuitBreaker
+	if !cfg.Enabled() || at.store == nil || equity <= 0 {
+		return ""
+	}
+
+	cbStore := at.store.CircuitBreaker()
+	state, err := cbStore.Get(at.id)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Circuit breaker: %v", at.name, err)
+		return ""
+	}
+	if state == nil {
+		state = &store.CircuitBreakerState{TraderID: at.id, UserID: at.userID, ResetAt: time.Now().UTC()}
+	}
+
+	changed := false
+	if equity > state.PeakEquity {
+		state.PeakEquity = equity
+		changed = true
+	}
+
+	var reason string
+	details := map[string]interface{}{"equity": equity, "peak_equity": state.PeakEquity}
+	if cfg.MaxDrawdownPct > 0 && state.PeakEquity > 0 {
+		drawdown := (state.PeakEquity - equity) / state.PeakEquity * 100
+		if drawdown >= cfg.MaxDrawdownPct {
+			reason = fmt.Sprintf("equity drawdown %.2f%% from peak %.2f USDT reached limit %.2f%%",
+				drawdown, state.PeakEquity, cfg.MaxDrawdownPct)
+			details["drawdown_pct"] = drawdown
+			details["max_drawdown_pct"] = cfg.MaxDrawdownPct
+		}
+	}
+	if reason == "" && cfg.MaxConsecutiveLosses > 0 {
+		losses, err := at.store.Position().CountConsecutiveLosses(at.id, state.ResetAt.UnixMilli(), cfg.MaxConsecutiveLosses)
+		if err != nil {
+			logger.Warnf("⚠️ [%s] Circuit breaker: %v", at.name, err)
+		} else if losses >= cfg.MaxConsecutiveLosses {
+			reason = fmt.Sprintf("%d consecutive losing trades reached limit %d", losses, cfg.MaxConsecutiveLosses)
+			details["consecutive_losses"] = losses
+			details["max_consecutive_losses"] = cfg.MaxConsecutiveLosses
+		}
+	}
+
+	if reason == "" {
+		if changed {
+			if err := cbStore.Save(state); err != nil {
+				logger.Warnf("⚠️ [%s] Failed to save circuit breaker state: %v", at.name, err)
+			}
+		}
+		return ""
+	}
+
+	cooldownUntil := time.Now().Add(time.Duration(cfg.CooldownMinutes) * time.Minute)
+	if err := cbStore.Trip(state, reason, cooldownUntil); err != nil {
+		logger.Errorf("❌ [%s] Failed to persist circuit breaker trip: %v", at.name, err)
+	}
+	logger.Errorf("🚨 [%s] Circuit breaker tripped: %s | Trader stopped, restart allowed after %s",
+		at.name, reason, cooldownUntil.Format("2006-01-02 15:04:05"))
+	// Notify the user (timeline, email/push, webhooks) before the trading loop stops the trader
+	details["reason"] = reason
+	details["cooldown_until"] = cooldownUntil.UTC()
+	at.recordEvent(store.TraderEventCircuitBreaker, "Circuit breaker tripped: "+reason, details)
+	return reason
+}
+
+// haltForCircuitBreaker stops the trader after a trip (called from the trading loop)
+func (at *AutoTrader) haltForCircuitBreaker() {
+	if at.store != nil {
+		if err := at.store.Trader().UpdateStatus(at.userID, at.id, false); err != nil {
+			logger.Warnf("⚠️ [%s] Failed to update trader status: %v", at.name, err)
+		}
+	}
+	// Stop waits for the main loop to exit, and the main loop is our caller
+	go at.Stop()
+}
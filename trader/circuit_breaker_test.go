@@ -0,0 +1,106 @@
+package trader
+
+import (
+	"errors"
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+func newCircuitBreakerTestTrader(t *testing.T, cfg store.CircuitBreakerConfig) *AutoTrader {
+	return newStoreTestTrader(t, AutoTraderConfig{CircuitBreaker: cfg.WithDefaults(store.CircuitBreakerConfig{})})
+}
+
+func TestCircuitBreaker_Drawdown(t *testing.T) {
+	at := newCircuitBreakerTestTrader(t, store.CircuitBreakerConfig{MaxDrawdownPct: 10, CooldownMinutes: 30})
+	if err := at.prepareCircuitBreaker(); err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+
+	for _, equity := range []float64{1000, 1100, 1000} {
+		if reason := at.checkCircuitBreaker(equity); reason != "" {
+			t.Fatalf("unexpected trip at equity %.0f: %s", equity, reason)
+		}
+	}
+	var events []Event
+	SetEventListener(func(e Event) { events = append(events, e) })
+	defer SetEventListener(nil)
+
+	// 1100 -> 980 is a 10.9% drawdown
+	if reason := at.checkCircuitBreaker(980); reason == "" {
+		t.Fatal("expected breaker to trip")
+	}
+	if len(events) != 1 || events[0].Type != store.TraderEventCircuitBreaker || events[0].UserID != "user1" {
+		t.Fatalf("expected one circuit breaker event for the user, got %+v", events)
+	}
+	if d := events[0].Details; d["peak_equity"] != 1100.0 || d["equity"] != 980.0 || d["drawdown_pct"] == nil || d["reason"] == "" {
+		t.Errorf("event details missing drawdown figures: %+v", d)
+	}
+
+	state, err := at.store.CircuitBreaker().Get(at.id)
+	if err != nil || state == nil {
+		t.Fatalf("state not persisted: %v", err)
+	}
+	if !state.InCooldown(time.Now()) || state.CooldownUntil.Before(time.Now().Add(29*time.Minute)) {
+		t.Fatalf("unexpected cooldown: %+v", state)
+	}
+	if err := at.prepareCircuitBreaker(); !errors.Is(err, ErrCircuitBreakerCooldown) {
+		t.Fatalf("expected cooldown error, got %v", err)
+	}
+
+	// After cooldown, restart resets peak tracking
+	state.CooldownUntil = time.Now().Add(-time.Minute)
+	if err := at.store.CircuitBreaker().Save(state); err != nil {
+		t.Fatal(err)
+	}
+	if err := at.prepareCircuitBreaker(); err != nil {
+		t.Fatalf("prepare after cooldown: %v", err)
+	}
+	if reason := at.checkCircuitBreaker(980); reason != "" {
+		t.Fatalf("peak should have been reset, got trip: %s", reason)
+	}
+}
+
+func TestCircuitBreaker_ConsecutiveLosses(t *testing.T) {
+	at := newCircuitBreakerTestTrader(t, store.CircuitBreakerConfig{MaxConsecutiveLosses: 3})
+	if err := at.prepareCircuitBreaker(); err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+
+	now := time.Now().UnixMilli()
+	for i, pnl := range []float64{-5, 8, -1, -2} {
+		pos := &store.TraderPosition{
+			TraderID:    at.id,
+			Symbol:      "BTCUSDT",
+			Side:        "LONG",
+			Quantity:    0.01,
+			EntryPrice:  50000,
+			EntryTime:   now,
+			ExitTime:    now + int64(i+1)*1000,
+			RealizedPnL: pnl,
+			Status:      "CLOSED",
+		}
+		if err := at.store.GormDB().Create(pos).Error; err != nil {
+			t.Fatalf("create position: %v", err)
+		}
+	}
+	if reason := at.checkCircuitBreaker(1000); reason != "" {
+		t.Fatalf("two losses must not trip: %s", reason)
+	}
+
+	if err := at.store.GormDB().Create(&store.TraderPosition{
+		TraderID: at.id, Symbol: "ETHUSDT", Side: "SHORT", Quantity: 1, EntryPrice: 3000,
+		EntryTime: now, ExitTime: now + 10000, RealizedPnL: -3, Status: "CLOSED",
+	}).Error; err != nil {
+		t.Fatalf("create position: %v", err)
+	}
+	var events []Event
+	SetEventListener(func(e Event) { events = append(events, e) })
+	defer SetEventListener(nil)
+	if reason := at.checkCircuitBreaker(1000); reason == "" {
+		t.Fatal("expected trip after three consecutive losses")
+	}
+	if len(events) != 1 || events[0].Details["consecutive_losses"] != 3 {
+		t.Errorf("expected circuit breaker event with the losing streak, got %+v", events)
+	}
+}
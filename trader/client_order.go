@@ -0,0 +1,101 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"strings"
+	"time"
+)
+
+// clientOrderMaxAttempts Lookups after an ambiguous submission
+const clientOrderMaxAttempts = 3
+
+// clientOrderRetryDelay Base delay before each lookup (multiplied by attempt)
+var clientOrderRetryDelay = 2 * time.Second
+
+// clientOrderKey builds the client order key of a decision in the current cycle.
+// The cycle number is persisted with decision records, so keys stay unique across restarts;
+// without a store the cycle never advances, so idempotent submission is disabled.
+func (at *AutoTrader) clientOrderKey(index int) ClientOrderKey {
+	if at.store == nil {
+		return ClientOrderKey{}
+	}
+	return ClientOrderKey{TraderID: at.id, Cycle: at.cycleNumber + 1, Index: index}
+}
+
+// submitOpenOrder opens a position, idempotently when the exchange supports client order IDs.
+// If the submission fails ambiguously (timeout, connection reset, 5xx, duplicate ID) the order
+// may have reached the exchange, so it is looked up by client order ID before resubmitting with
+// the same ID. A failed lookup never resubmits, to avoid opening the position twice.
+// Exchanges without client order IDs (Lighter orders are keyed by signed nonce) submit once.
+func (at *AutoTrader) submitOpenOrder(side, symbol string, quantity float64, leverage int, key ClientOrderKey) (map[string]interface{}, error) {
+	op := "Open " + side + " " + symbol
+	cot, ok := at.trader.(ClientOrderTrader)
+	if !ok || key.IsZero() {
+		return exchangeCall(at, op, true, func() (map[string]interface{}, error) {
+			if side == "short" {
+				return at.trader.OpenShort(symbol, quantity, leverage)
+			}
+			return at.trader.OpenLong(symbol, quantity, leverage)
+		})
+	}
+
+	// Rate-limited submissions are retried here, ambiguous failures are resolved below
+	submit := func() (map[string]interface{}, error) {
+		return exchangeCall(at, op, true, func() (map[string]interface{}, error) {
+			if side == "short" {
+				return cot.OpenShortWithClientID(symbol, quantity, leverage, key)
+			}
+			return cot.OpenLongWithClientID(symbol, quantity, leverage, key)
+		})
+	}
+
+	order, err := submit()
+	if err == nil || !isAmbiguousOrderError(err) {
+		return order, err
+	}
+
+	lastErr := err
+	for attempt := 1; attempt <= clientOrderMaxAttempts; attempt++ {
+		logger.Warnf("  ⚠️ [%s] Open %s %s failed ambiguously (order %s), checking exchange (attempt %d/%d): %v",
+			at.name, side, symbol, key, attempt, clientOrderMaxAttempts, lastErr)
+		time.Sleep(time.Duration(attempt) * clientOrderRetryDelay)
+
+		existing, findErr := cot.FindOrderByClientID(symbol, key)
+		if findErr != nil {
+			lastErr = findErr
+			continue
+		}
+		if existing != nil {
+			logger.Infof("  ✓ [%s] Order %s was accepted by exchange (order ID: %v), not resubmitting", at.name, key, existing["orderId"])
+			return existing, nil
+		}
+
+		// Not on exchange: safe to resubmit with the same client order ID
+		order, err = submit()
+		if err == nil {
+			return order, nil
+		}
+		if !isAmbiguousOrderError(err) {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("open %s %s not confirmed after %d attempts: %w", side, symbol, clientOrderMaxAttempts, lastErr)
+}
+
+// isAmbiguousOrderError reports whether an order submission error leaves the order state unknown
+func isAmbiguousOrderError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, keyword := range []string{
+		"timeout", "deadline exceeded", "connection reset", "eof", "broken pipe",
+		"http 500", "http 502", "http 503", "http 504",
+		"duplicate", "-4116", "51016", "110072", // Client order ID already used (Binance/OKX/Bybit)
+	} {
+		if strings.Contains(msg, keyword) {
+			return true
+		}
+	}
+	return false
+}
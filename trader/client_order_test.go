@@ -0,0 +1,128 @@
+package trader
+
+import (
+	"errors"
+	"nofx/trader/aster"
+	"nofx/trader/binance"
+	"nofx/trader/bitget"
+	"nofx/trader/bybit"
+	"nofx/trader/gate"
+	"nofx/trader/hyperliquid"
+	"nofx/trader/kucoin"
+	"nofx/trader/okx"
+	"testing"
+)
+
+var (
+	_ ClientOrderTrader = (*binance.FuturesTrader)(nil)
+	_ ClientOrderTrader = (*aster.AsterTrader)(nil)
+	_ ClientOrderTrader = (*bybit.BybitTrader)(nil)
+	_ ClientOrderTrader = (*okx.OKXTrader)(nil)
+	_ ClientOrderTrader = (*bitget.BitgetTrader)(nil)
+	_ ClientOrderTrader = (*gate.GateTrader)(nil)
+	_ ClientOrderTrader = (*kucoin.KuCoinTrader)(nil)
+	_ ClientOrderTrader = (*hyperliquid.HyperliquidTrader)(nil)
+)
+
+// stubClientOrderTrader simulates an exchange that accepts orders by client order ID
+type stubClientOrderTrader struct {
+	Trader
+	submitErrs []error                   // Error returned by each submission (nil: accepted)
+	orders     map[string]ClientOrderKey // Accepted orders by client order ID
+	submits    int
+	findErr    error
+}
+
+func (s *stubClientOrderTrader) submit(key ClientOrderKey) (map[string]interface{}, error) {
+	s.submits++
+	if _, exists := s.orders[key.ID()]; exists {
+		return nil, errors.New("<APIError> code=-4116, msg=ClientOrderId is duplicated")
+	}
+	var err error
+	if len(s.submitErrs) > 0 {
+		err, s.submitErrs = s.submitErrs[0], s.submitErrs[1:]
+	}
+	// A timeout after the exchange received the order still opens the position
+	if err == nil || err.Error() == "context deadline exceeded (Client.Timeout exceeded while awaiting headers)" {
+		s.orders[key.ID()] = key
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"orderId": int64(s.submits), "status": "NEW"}, nil
+}
+
+func (s *stubClientOrderTrader) OpenLongWithClientID(symbol string, quantity float64, leverage int, key ClientOrderKey) (map[string]interface{}, error) {
+	return s.submit(key)
+}
+
+func (s *stubClientOrderTrader) OpenShortWithClientID(symbol string, quantity float64, leverage int, key ClientOrderKey) (map[string]interface{}, error) {
+	return s.submit(key)
+}
+
+func (s *stubClientOrderTrader) FindOrderByClientID(symbol string, key ClientOrderKey) (map[string]interface{}, error) {
+	if s.findErr != nil {
+		return nil, s.findErr
+	}
+	if _, ok := s.orders[key.ID()]; ok {
+		return map[string]interface{}{"orderId": int64(99), "status": "FILLED"}, nil
+	}
+	return nil, nil
+}
+
+func TestClientOrderKey_Deterministic(t *testing.T) {
+	key := ClientOrderKey{TraderID: "trader-1", Cycle: 42, Index: 1}
+	if key.ID() != (ClientOrderKey{TraderID: "trader-1", Cycle: 42, Index: 1}).ID() {
+		t.Fatal("same key must yield the same client order ID")
+	}
+	if len(key.ID()) != 21 {
+		t.Fatalf("unexpected ID length %d", len(key.ID()))
+	}
+	for _, other := range []ClientOrderKey{
+		{TraderID: "trader-2", Cycle: 42, Index: 1},
+		{TraderID: "trader-1", Cycle: 43, Index: 1},
+		{TraderID: "trader-1", Cycle: 42, Index: 2},
+	} {
+		if other.ID() == key.ID() {
+			t.Errorf("%s and %s must not share a client order ID", key, other)
+		}
+	}
+	if !(ClientOrderKey{}).IsZero() || key.IsZero() {
+		t.Error("IsZero mismatch")
+	}
+}
+
+func TestSubmitOpenOrder_Idempotent(t *testing.T) {
+	clientOrderRetryDelay = 0
+	timeout := errors.New("context deadline exceeded (Client.Timeout exceeded while awaiting headers)")
+	key := ClientOrderKey{TraderID: "t1", Cycle: 7, Index: 0}
+
+	// Timeout after the exchange accepted the order: found by client ID, not resubmitted
+	stub := &stubClientOrderTrader{submitErrs: []error{timeout}, orders: map[string]ClientOrderKey{}}
+	at := &AutoTrader{name: "t1", trader: stub}
+	order, err := at.submitOpenOrder("long", "BTCUSDT", 0.01, 10, key)
+	if err != nil || order["orderId"] != int64(99) || stub.submits != 1 || len(stub.orders) != 1 {
+		t.Fatalf("expected existing order, got %v, %v (submits=%d)", order, err, stub.submits)
+	}
+
+	// Connection reset before the exchange received it: not found, resubmitted once with the same ID
+	stub = &stubClientOrderTrader{submitErrs: []error{errors.New("read: connection reset by peer")}, orders: map[string]ClientOrderKey{}}
+	at.trader = stub
+	if _, err := at.submitOpenOrder("short", "BTCUSDT", 0.01, 10, key); err != nil || stub.submits != 2 || len(stub.orders) != 1 {
+		t.Fatalf("expected single resubmission, got err=%v submits=%d orders=%d", err, stub.submits, len(stub.orders))
+	}
+
+	// Lookup failing: never resubmit blindly
+	stub = &stubClientOrderTrader{submitErrs: []error{errors.New("read: connection reset by peer")}, orders: map[string]ClientOrderKey{}, findErr: errors.New("EOF")}
+	at.trader = stub
+	if _, err := at.submitOpenOrder("long", "BTCUSDT", 0.01, 10, key); err == nil || stub.submits != 1 {
+		t.Fatalf("expected failure without resubmission, got err=%v submits=%d", err, stub.submits)
+	}
+
+	// Definite rejection: returned as-is without lookup
+	stub = &stubClientOrderTrader{submitErrs: []error{errors.New("<APIError> code=-2019, msg=Margin is insufficient.")}, orders: map[string]ClientOrderKey{}}
+	at.trader = stub
+	if _, err := at.submitOpenOrder("long", "BTCUSDT", 0.01, 10, key); err == nil || stub.submits != 1 {
+		t.Fatalf("expected rejection, got err=%v submits=%d", err, stub.submits)
+	}
+}
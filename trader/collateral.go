@@ -0,0 +1,107 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"strings"
+)
+
+// settlementQuotes quote assets linear contracts are settled in
+var settlementQuotes = []string{"FDUSD", "USDT", "USDC", "BUSD"}
+
+// settlementAsset asset a linear contract is settled and margined in (BTCUSDC -> USDC), USDT if unknown
+func settlementAsset(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	for _, quote := range settlementQuotes {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return quote
+		}
+	}
+	return "USDT"
+}
+
+// collateralUSD collateral assets of the account with their USD prices
+type collateralUSD struct {
+	multiAssetMargin bool
+	assets           []CollateralAsset // IndexPrice always set
+}
+
+// collateralInUSD values the account's collateral assets in USD, nil if the exchange does not report
+// per-asset collateral (its balance figures are then taken as USD)
+func (at *AutoTrader) collateralInUSD() *collateralUSD {
+	if at.inverseTrader() != nil {
+		return nil // Coin-margined, converted by balancesInUSD
+	}
+	ct, ok := at.trader.(CollateralTrader)
+	if !ok {
+		return nil
+	}
+	balance, err := ct.GetCollateralBalance()
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to get collateral balances, using account totals: %v", at.name, err)
+		return nil
+	}
+
+	collateral := &collateralUSD{multiAssetMargin: balance.MultiAssetMargin}
+	for _, asset := range balance.Assets {
+		if asset.IndexPrice <= 0 {
+			price, err := at.collateralPrice(asset.Asset)
+			if err != nil {
+				// Left out rather than overstating the collateral
+				logger.Warnf("⚠️ [%s] Collateral %s not counted: %v", at.name, asset.Asset, err)
+				continue
+			}
+			asset.IndexPrice = price
+		}
+		collateral.assets = append(collateral.assets, asset)
+	}
+	return collateral
+}
+
+// collateralPrice USD price of a collateral asset the exchange reported no index price for
+func (at *AutoTrader) collateralPrice(asset string) (float64, error) {
+	if asset == "USDT" {
+		return 1, nil
+	}
+	price, err := at.trader.GetMarketPrice(asset + "USDT")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get %s price: %w", asset, err)
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("invalid %s price: %v", asset, price)
+	}
+	return price, nil
+}
+
+// margining USD-equivalent available balance and equity that can margin a contract of symbol,
+// or the whole account if symbol is empty
+func (c *collateralUSD) margining(symbol string) (available, equity float64) {
+	settle := ""
+	if symbol != "" && !c.multiAssetMargin {
+		settle = settlementAsset(symbol)
+	}
+	for _, asset := range c.assets {
+		if settle != "" && asset.Asset != settle {
+			continue
+		}
+		available += asset.Available * asset.IndexPrice
+		equity += asset.Equity() * asset.IndexPrice
+	}
+	return available, equity
+}
+
+// report per-asset balances for the account API
+func (c *collateralUSD) report() []map[string]interface{} {
+	assets := make([]map[string]interface{}, 0, len(c.assets))
+	for _, asset := range c.assets {
+		assets = append(assets, map[string]interface{}{
+			"asset":          asset.Asset,
+			"wallet_balance": asset.WalletBalance,
+			"unrealized_pnl": asset.UnrealizedPnL,
+			"available":      asset.Available,
+			"index_price":    asset.IndexPrice,
+			"usd_value":      asset.Equity() * asset.IndexPrice,
+		})
+	}
+	return assets
+}
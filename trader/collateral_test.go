@@ -0,0 +1,62 @@
+package trader
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// stubCollateralTrader reports per-asset collateral; only BTC has a market price
+type stubCollateralTrader struct {
+	Trader
+	balance *CollateralBalance
+}
+
+func (s *stubCollateralTrader) GetCollateralBalance() (*CollateralBalance, error) {
+	return s.balance, nil
+}
+
+func (s *stubCollateralTrader) GetMarketPrice(symbol string) (float64, error) {
+	if symbol == "BTCUSDT" {
+		return 60000, nil
+	}
+	return 0, errors.New("no market")
+}
+
+func TestCollateralInUSD_Margining(t *testing.T) {
+	exchange := &stubCollateralTrader{balance: &CollateralBalance{Assets: []CollateralAsset{
+		{Asset: "USDT", WalletBalance: 500, UnrealizedPnL: -20, Available: 400},
+		{Asset: "USDC", WalletBalance: 1000, Available: 1000, IndexPrice: 0.999},
+		{Asset: "BTC", WalletBalance: 0.01, Available: 0.01},
+		{Asset: "XYZ", WalletBalance: 50, Available: 50}, // Unpriced: not counted
+	}}}
+	at := &AutoTrader{name: "test", trader: exchange}
+
+	collateral := at.collateralInUSD()
+	if collateral == nil || len(collateral.assets) != 3 {
+		t.Fatalf("expected 3 priced assets, got %+v", collateral)
+	}
+
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-6 }
+	// Single-asset mode: a contract is margined only by its settlement asset
+	if available, equity := collateral.margining("ETHUSDC"); !near(available, 999) || !near(equity, 999) {
+		t.Errorf("USDC contract: got %.2f/%.2f", available, equity)
+	}
+	if available, equity := collateral.margining("ETHUSDT"); !near(available, 400) || !near(equity, 480) {
+		t.Errorf("USDT contract: got %.2f/%.2f", available, equity)
+	}
+
+	// Multi-asset margin: every asset counts
+	collateral.multiAssetMargin = true
+	if available, equity := collateral.margining("ETHUSDT"); !near(available, 400+999+600) || !near(equity, 480+999+600) {
+		t.Errorf("multi-asset margin: got %.2f/%.2f", available, equity)
+	}
+}
+
+func TestSettlementAsset(t *testing.T) {
+	for symbol, want := range map[string]string{"BTCUSDT": "USDT", "ethusdc": "USDC", "SOLFDUSD": "FDUSD", "BTCUSD": "USDT"} {
+		if got := settlementAsset(symbol); got != want {
+			t.Errorf("settlementAsset(%s) = %s, want %s", symbol, got, want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"time"
+)
+
+// ============================================================================
+// Copy Trading
+// ============================================================================
+// A follower trader skips its own AI decisions and mirrors the decisions its
+// leader executed. Opening sizes are scaled by follower equity / leader
+// equity, then capped by the follower's link (max position, max leverage);
+// blacklisted symbols are never copied. The follower's own risk rules (max
+// positions, position value ratio, margin) still apply when executing.
+// Copied executions are not published again, so links never chain.
+// ============================================================================
+
+// SetDecisionBus sets the bus executed decisions are published on
+func (at *AutoTrader) SetDecisionBus(bus *DecisionBus) {
+	at.decisionBus = bus
+}
+
+// SetCopyLink sets the leader this trader mirrors (nil: trade its own decisions)
+func (at *AutoTrader) SetCopyLink(link *store.CopyTradeLink) {
+	at.copyMu.Lock()
+	defer at.copyMu.Unlock()
+	at.copyLink = link
+}
+
+// CopyLink returns the copy trading link of this trader, nil if it follows no one
+func (at *AutoTrader) CopyLink() *store.CopyTradeLink {
+	at.copyMu.RLock()
+	defer at.copyMu.RUnlock()
+	return at.copyLink
+}
+
+// copyLeaderID returns the leader trader ID while copying is enabled, empty otherwise
+func (at *AutoTrader) copyLeaderID() string {
+	if link := at.CopyLink(); link != nil && link.Enabled {
+		return link.LeaderID
+	}
+	return ""
+}
+
+// publishDecision publishes a successfully executed trading decision for followers
+func (at *AutoTrader) publishDecision(d *kernel.Decision, equity float64) {
+	if at.decisionBus == nil || at.copyLeaderID() != "" {
+		return
+	}
+	if !d.IsOpen() && d.Action != "close_long" && d.Action != "close_short" {
+		return
+	}
+	at.decisionBus.Publish(DecisionEvent{
+		TraderID:  at.id,
+		Decision:  *d,
+		Equity:    equity,
+		Timestamp: time.Now(),
+	})
+}
+
+// ExecuteCopiedDecision mirrors a leader's executed decision on this follower
+// Events from traders other than the enabled leader, or arriving while stopped, are ignored.
+func (at *AutoTrader) ExecuteCopiedDecision(ev DecisionEvent) error {
+	link := at.CopyLink()
+	if link == nil || !link.Enabled || link.LeaderID != ev.TraderID {
+		return nil
+	}
+	at.isRunningMutex.RLock()
+	running := at.isRunning
+	at.isRunningMutex.RUnlock()
+	if !running {
+		return nil
+	}
+
+	d := ev.Decision
+	details := map[string]interface{}{
+		"leader_id": ev.TraderID,
+		"action":    d.Action,
+		"symbol":    d.Symbol,
+	}
+	if isCopyBlacklisted(link, d.Symbol) {
+		logger.Infof("📋 [%s] Not copying %s %s: symbol blacklisted", at.name, d.Action, d.Symbol)
+		return nil
+	}
+	if window := at.activeMaintenance(); window != nil {
+		err := fmt.Errorf("exchange maintenance until %s", window.End.UTC().Format("2006-01-02 15:04 UTC"))
+		at.recordCopyFailure(d, details, err)
+		return err
+	}
+
+	if d.IsOpen() {
+		balance, err := at.trader.GetBalance()
+		if err != nil {
+			err = fmt.Errorf("failed to get account balance: %w", err)
+			at.recordCopyFailure(d, details, err)
+			return err
+		}
+		leaderSize := d.PositionSizeUSD
+		if d, err = scaleCopiedDecision(d, link, equityFromBalance(balance), ev.Equity); err != nil {
+			at.recordCopyFailure(d, details, err)
+			return err
+		}
+		details["leader_size_usd"] = leaderSize
+		details["size_usd"] = d.PositionSizeUSD
+		details["leverage"] = d.Leverage
+	}
+	d.Reasoning = fmt.Sprintf("Copied from leader %s: %s", ev.TraderID, d.Reasoning)
+
+	actionRecord := &store.DecisionAction{
+		Symbol:     d.Symbol,
+		Action:     d.Action,
+		Leverage:   d.Leverage,
+		StopLoss:   d.StopLoss,
+		TakeProfit: d.TakeProfit,
+		Confidence: d.Confidence,
+		Reasoning:  d.Reasoning,
+	}
+	if err := at.executeDecisionWithRecord(&d, actionRecord, ClientOrderKey{}); err != nil {
+		at.recordCopyFailure(d, details, err)
+		return err
+	}
+
+	msg := fmt.Sprintf("Copied %s %s from leader", d.Action, d.Symbol)
+	if d.IsOpen() {
+		msg += fmt.Sprintf(" ($%.2f, %dx)", d.PositionSizeUSD, d.Leverage)
+	}
+	logger.Infof("📋 [%s] %s %s", at.name, msg, ev.TraderID)
+	at.recordEvent(store.TraderEventCopyTrade, msg, details)
+	return nil
+}
+
+// recordCopyFailure logs a copied decision that could not be executed on the timeline
+func (at *AutoTrader) recordCopyFailure(d kernel.Decision, details map[string]interface{}, err error) {
+	msg := fmt.Sprintf("Failed to copy %s %s: %v", d.Action, d.Symbol, err)
+	logger.Warnf("⚠️ [%s] %s", at.name, msg)
+	details["error"] = err.Error()
+	at.recordEvent(store.TraderEventCopyTrade, msg, details)
+}
+
+// scaleCopiedDecision scales an opening decision from leader to follower equity and applies the link caps
+func scaleCopiedDecision(d kernel.Decision, link *store.CopyTradeLink, followerEquity, leaderEquity float64) (kernel.Decision, error) {
+	if leaderEquity <= 0 || followerEquity <= 0 {
+		return d, fmt.Errorf("cannot scale position: leader equity %.2f, follower equity %.2f", leaderEquity, followerEquity)
+	}
+	ratio := followerEquity / leaderEquity
+	d.PositionSizeUSD *= ratio
+	d.RiskUSD *= ratio
+	if link.MaxPositionUSD > 0 && d.PositionSizeUSD > link.MaxPositionUSD {
+		d.PositionSizeUSD = link.MaxPositionUSD
+	}
+	if link.MaxLeverage > 0 && d.Leverage > link.MaxLeverage {
+		d.Leverage = link.MaxLeverage
+	}
+	return d, nil
+}
+
+// isCopyBlacklisted reports whether the follower never copies trades in symbol
+func isCopyBlacklisted(link *store.CopyTradeLink, symbol string) bool {
+	normalized := market.Normalize(symbol)
+	for _, s := range link.BlacklistSymbols() {
+		if market.Normalize(s) == normalized {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package trader
+
+import (
+	"nofx/kernel"
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+func TestScaleCopiedDecision(t *testing.T) {
+	leader := kernel.Decision{Symbol: "BTCUSDT", Action: "open_long", PositionSizeUSD: 1000, Leverage: 10, RiskUSD: 50}
+
+	d, err := scaleCopiedDecision(leader, &store.CopyTradeLink{}, 2000, 10000)
+	if err != nil {
+		t.Fatalf("scaleCopiedDecision: %v", err)
+	}
+	if d.PositionSizeUSD != 200 || d.RiskUSD != 10 || d.Leverage != 10 {
+		t.Errorf("expected size scaled by equity ratio 0.2, got %+v", d)
+	}
+
+	d, _ = scaleCopiedDecision(leader, &store.CopyTradeLink{MaxPositionUSD: 150, MaxLeverage: 3}, 2000, 10000)
+	if d.PositionSizeUSD != 150 || d.Leverage != 3 {
+		t.Errorf("expected follower caps applied, got size=%.2f leverage=%d", d.PositionSizeUSD, d.Leverage)
+	}
+
+	if _, err := scaleCopiedDecision(leader, &store.CopyTradeLink{}, 2000, 0); err == nil {
+		t.Error("unknown leader equity should not be scaled")
+	}
+}
+
+func TestIsCopyBlacklisted(t *testing.T) {
+	link := &store.CopyTradeLink{SymbolBlacklist: " doge , PEPEUSDT"}
+	if !isCopyBlacklisted(link, "DOGEUSDT") || !isCopyBlacklisted(link, "pepe") {
+		t.Error("blacklisted symbols should match with or without the USDT suffix")
+	}
+	if isCopyBlacklisted(link, "BTCUSDT") {
+		t.Error("BTCUSDT is not blacklisted")
+	}
+}
+
+func TestDecisionBus_DeliversInOrder(t *testing.T) {
+	bus := NewDecisionBus()
+	received := make(chan string, 3)
+	bus.Subscribe(func(ev DecisionEvent) { received <- ev.Decision.Action })
+	bus.Start()
+	defer bus.Stop()
+
+	for _, action := range []string{"close_short", "open_long", "close_long"} {
+		bus.Publish(DecisionEvent{TraderID: "leader", Decision: kernel.Decision{Action: action}})
+	}
+	for _, want := range []string{"close_short", "open_long", "close_long"} {
+		select {
+		case got := <-received:
+			if got != want {
+				t.Fatalf("expected %s, got %s", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+}
+
+func TestExecuteCopiedDecision_IgnoresOtherTraders(t *testing.T) {
+	at := &AutoTrader{name: "follower", isRunning: true}
+	at.SetCopyLink(&store.CopyTradeLink{FollowerID: "f", LeaderID: "leader", Enabled: true})
+
+	// A trader that is not the leader never reaches the exchange (nil trader would panic)
+	if err := at.ExecuteCopiedDecision(DecisionEvent{TraderID: "other", Decision: kernel.Decision{Action: "open_long", Symbol: "BTCUSDT"}}); err != nil {
+		t.Errorf("events of other traders should be ignored, got %v", err)
+	}
+
+	at.SetCopyLink(&store.CopyTradeLink{FollowerID: "f", LeaderID: "leader", Enabled: false})
+	if err := at.ExecuteCopiedDecision(DecisionEvent{TraderID: "leader", Decision: kernel.Decision{Action: "open_long", Symbol: "BTCUSDT"}}); err != nil {
+		t.Errorf("paused link should ignore leader events, got %v", err)
+	}
+	if at.copyLeaderID() != "" {
+		t.Error("paused link should let the trader make its own decisions")
+	}
+}
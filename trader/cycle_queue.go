@@ -0,0 +1,163 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+	"nofx/logger"
+	"nofx/store"
+	"time"
+)
+
+// ============================================================================
+// Persistent Cycle Queue
+// ============================================================================
+// Every scan tick enqueues a cycle job in the database; the trader claims due
+// jobs, runs them and marks them done. A job still running at startup was
+// interrupted by a crash or restart and is retried first; failed cycles are
+// retried with exponential backoff until cycleJobMaxAttempts. Without a store
+// cycles run directly on the ticker as before.
+// ============================================================================
+
+const (
+	cycleJobMaxAttempts = 3                // Attempts per cycle, including the first run
+	cycleJobRetryBase   = 30 * time.Second // First retry delay, doubled per attempt
+	cycleJobRetention   = 7 * 24 * time.Hour
+	cycleJobPruneEvery  = 24 * time.Hour
+)
+
+// cycleJobKind returns the job kind of the trader's strategy
+func (at *AutoTrader) cycleJobKind() string {
+	if at.IsGridStrategy() {
+		return store.CycleJobGrid
+	}
+	return store.CycleJobDecision
+}
+
+// executeCycle runs one grid or AI decision cycle, recording failures on the trader timeline
+func (at *AutoTrader) executeCycle(run *cycleRun) error {
+	if at.IsGridStrategy() {
+		if err := at.RunGridCycle(); err != nil {
+			logger.Infof("❌ Grid execution failed: %v", err)
+			at.recordEvent(store.TraderEventError, "Grid cycle failed: "+err.Error(), nil)
+			return err
+		}
+		return nil
+	}
+	if err := at.runCycle(run); err != nil {
+		if errors.Is(err, errCycleAborted) {
+			return err // Already logged as timed out by the watchdog
+		}
+		logger.Infof("❌ Execution failed: %v", err)
+		at.recordEvent(store.TraderEventError, "Decision cycle failed: "+err.Error(), nil)
+		return err
+	}
+	return nil
+}
+
+// recoverCycleJobs requeues cycles interrupted by a crash and prunes old job history
+func (at *AutoTrader) recoverCycleJobs() {
+	if at.store == nil {
+		return
+	}
+	jobs := at.store.CycleJob()
+	now := time.Now()
+	if n, err := jobs.RecoverInterrupted(at.id, cycleJobMaxAttempts, now); err != nil {
+		logger.Warnf("⚠️ [%s] Failed to recover interrupted cycles: %v", at.name, err)
+	} else if n > 0 {
+		logger.Infof("♻️ [%s] Retrying %d cycle(s) interrupted by a restart", at.name, n)
+		at.recordEvent(store.TraderEventError, fmt.Sprintf("Recovered %d cycle(s) interrupted mid-run, retrying", n), nil)
+	}
+	at.pruneCycleJobs(now)
+}
+
+// pruneCycleJobs deletes finished jobs past retention, at most once per cycleJobPruneEvery
+func (at *AutoTrader) pruneCycleJobs(now time.Time) {
+	if now.Sub(at.lastCycleJobPrune) < cycleJobPruneEvery {
+		return
+	}
+	at.lastCycleJobPrune = now
+	if _, err := at.store.CycleJob().DeleteFinishedBefore(at.id, now.Add(-cycleJobRetention)); err != nil {
+		logger.Warnf("⚠️ [%s] Failed to prune cycle jobs: %v", at.name, err)
+	}
+}
+
+// enqueueCycle schedules a cycle now, unless one is already queued or running
+func (at *AutoTrader) enqueueCycle() {
+	if at.store == nil {
+		return
+	}
+	if _, err := at.store.CycleJob().Enqueue(at.id, at.cycleJobKind(), time.Now()); err != nil {
+		logger.Warnf("⚠️ [%s] %v", at.name, err)
+	}
+}
+
+// processCycleJobs runs the trader's due cycle job, if any
+// Returns a channel firing when the next pending retry is due, nil when nothing is pending.
+func (at *AutoTrader) processCycleJobs() <-chan time.Time {
+	if at.store == nil {
+		_ = at.runWithDeadline(at.executeCycle)
+		return nil
+	}
+
+	jobs := at.store.CycleJob()
+	now := time.Now()
+	job, err := jobs.ClaimDue(at.id, now)
+	if err != nil {
+		// Queue unavailable: keep trading on the timer rather than skip the cycle
+		logger.Warnf("⚠️ [%s] %v, running cycle without the queue", at.name, err)
+		_ = at.runWithDeadline(at.executeCycle)
+		return nil
+	}
+
+	if job != nil {
+		if late := now.Sub(job.ScheduledAt); late > at.config.ScanInterval {
+			logger.Warnf("⏰ [%s] Cycle job #%d running %v late", at.name, job.ID, late.Round(time.Second))
+		}
+		if execErr := at.runWithDeadline(at.executeCycle); execErr == nil {
+			if err := jobs.Complete(job.ID); err != nil {
+				logger.Warnf("⚠️ [%s] %v", at.name, err)
+			}
+		} else {
+			retryAt := time.Now().Add(cycleJobBackoff(job.Attempts))
+			maxAttempts := cycleJobMaxAttempts
+			if errors.Is(execErr, ErrCycleTimeout) {
+				maxAttempts = job.Attempts // Not retried: the next tick starts from fresh context
+			}
+			final, err := jobs.Fail(job, execErr.Error(), retryAt, maxAttempts)
+			switch {
+			case err != nil:
+				logger.Warnf("⚠️ [%s] %v", at.name, err)
+			case final:
+				logger.Warnf("❌ [%s] Cycle job #%d failed after %d attempts", at.name, job.ID, job.Attempts)
+			default:
+				logger.Infof("🔁 [%s] Cycle job #%d will retry at %s (attempt %d/%d)",
+					at.name, job.ID, retryAt.Format("15:04:05"), job.Attempts+1, cycleJobMaxAttempts)
+			}
+		}
+	}
+	at.pruneCycleJobs(now)
+
+	next, err := jobs.NextPending(at.id)
+	if err != nil || next == nil {
+		return nil
+	}
+	return time.After(max(time.Until(next.ScheduledAt), 0))
+}
+
+// cancelCycleJobs drops queued cycles when the trader is stopped on purpose
+func (at *AutoTrader) cancelCycleJobs() {
+	if at.store == nil {
+		return
+	}
+	if err := at.store.CycleJob().CancelPending(at.id); err != nil {
+		logger.Warnf("⚠️ [%s] %v", at.name, err)
+	}
+}
+
+// cycleJobBackoff retry delay after the given number of attempts (30s, 1m, 2m...)
+func cycleJobBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return cycleJobRetryBase << (attempts - 1)
+}
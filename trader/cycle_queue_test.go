@@ -0,0 +1,83 @@
+package trader
+
+import (
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+func TestCycleQueueRecoversInterruptedJob(t *testing.T) {
+	at := newStoreTestTrader(t, AutoTraderConfig{ScanInterval: 3 * time.Minute})
+	jobs := at.store.CycleJob()
+
+	at.enqueueCycle()
+	at.enqueueCycle() // Deduplicated while one is pending
+	job, err := jobs.ClaimDue(at.id, time.Now())
+	if err != nil || job == nil {
+		t.Fatalf("expected a due job, got %v %v", job, err)
+	}
+	if job.Kind != store.CycleJobDecision || job.Attempts != 1 {
+		t.Errorf("unexpected claimed job: %+v", job)
+	}
+	if next, _ := jobs.ClaimDue(at.id, time.Now()); next != nil {
+		t.Fatalf("expected a single job, claimed another: %+v", next)
+	}
+
+	// Process dies mid-cycle: the job stays running until the trader starts again
+	at.recoverCycleJobs()
+	retry, err := jobs.ClaimDue(at.id, time.Now())
+	if err != nil || retry == nil || retry.ID != job.ID || retry.Attempts != 2 {
+		t.Fatalf("expected the interrupted job to be retried, got %+v %v", retry, err)
+	}
+}
+
+func TestCycleQueueRetriesWithBackoff(t *testing.T) {
+	at := newStoreTestTrader(t, AutoTraderConfig{ScanInterval: 3 * time.Minute})
+	jobs := at.store.CycleJob()
+	at.enqueueCycle()
+
+	now := time.Now()
+	for attempt := 1; attempt <= cycleJobMaxAttempts; attempt++ {
+		job, err := jobs.ClaimDue(at.id, now)
+		if err != nil || job == nil {
+			t.Fatalf("attempt %d: expected a due job, got %v %v", attempt, job, err)
+		}
+		retryAt := now.Add(cycleJobBackoff(job.Attempts))
+		final, err := jobs.Fail(job, "AI timeout", retryAt, cycleJobMaxAttempts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if final != (attempt == cycleJobMaxAttempts) {
+			t.Fatalf("attempt %d: final = %v", attempt, final)
+		}
+		if !final {
+			if early, _ := jobs.ClaimDue(at.id, now); early != nil {
+				t.Fatalf("attempt %d: retry claimed before its backoff elapsed", attempt)
+			}
+		}
+		now = retryAt
+	}
+
+	counts, err := jobs.Counts(at.id, at.config.ScanInterval, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts.Pending != 0 || counts.Failed24h != 1 {
+		t.Errorf("expected one failed job and nothing pending, got %+v", counts)
+	}
+
+	// A failed job no longer blocks new cycles
+	at.enqueueCycle()
+	if job, _ := jobs.ClaimDue(at.id, time.Now()); job == nil {
+		t.Error("expected a new cycle after the failed one")
+	}
+}
+
+func TestCycleJobBackoff(t *testing.T) {
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute}
+	for i, d := range want {
+		if got := cycleJobBackoff(i + 1); got != d {
+			t.Errorf("cycleJobBackoff(%d) = %v, want %v", i+1, got, d)
+		}
+	}
+}
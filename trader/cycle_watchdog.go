@@ -0,0 +1,105 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+	"nofx/logger"
+	"nofx/store"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// Cycle Deadline & Watchdog
+// ============================================================================
+// Each cycle runs under a deadline (CYCLE_TIMEOUT_SECONDS, default the scan
+// interval minus a buffer). A cycle still running at its deadline is abandoned:
+// the main loop moves on, the cycle is logged as failed, and the abandoned run
+// stops at its next checkpoint (before the AI decision is executed and before
+// each order) instead of trading on stale context. Grid cycles hold the grid
+// mutex, so an abandoned one finishes before the next grid cycle proceeds.
+// Repeated timeouts raise a cycle_timeout alert on the trader timeline.
+// ============================================================================
+
+const (
+	cycleDeadlineBuffer    = 15 * time.Second // Left between a cycle's deadline and the next tick
+	minCycleDeadline       = 30 * time.Second
+	cycleTimeoutAlertAfter = 3 // Consecutive timeouts before an alert is raised
+)
+
+// ErrCycleTimeout a cycle overran its deadline and was abandoned
+var ErrCycleTimeout = errors.New("cycle deadline exceeded")
+
+// errCycleAborted returned by an abandoned cycle reaching a checkpoint
+var errCycleAborted = errors.New("cycle aborted by watchdog")
+
+// cycleRun one running cycle, flagged when the watchdog abandons it
+type cycleRun struct {
+	aborted atomic.Bool
+}
+
+// abortedCheckpoint reports whether the watchdog abandoned the cycle, logging where it stopped
+func (r *cycleRun) abortedCheckpoint(traderName, where string) bool {
+	if r == nil || !r.aborted.Load() {
+		return false
+	}
+	logger.Warnf("⏱️ [%s] Abandoned cycle stopped %s", traderName, where)
+	return true
+}
+
+// CycleDeadline max run time of a cycle: configured > 0 wins, otherwise the scan interval
+// minus cycleDeadlineBuffer, never less than minCycleDeadline
+func CycleDeadline(scanInterval, configured time.Duration) time.Duration {
+	if configured > 0 {
+		return configured
+	}
+	return max(scanInterval-cycleDeadlineBuffer, minCycleDeadline)
+}
+
+// runWithDeadline runs one cycle under the watchdog
+// Returns ErrCycleTimeout (wrapped) when the cycle overran; it keeps running in the background until its next checkpoint.
+func (at *AutoTrader) runWithDeadline(cycle func(run *cycleRun) error) error {
+	deadline := CycleDeadline(at.config.ScanInterval, at.config.CycleTimeout)
+	run := &cycleRun{}
+
+	done := make(chan error, 1) // Buffered: an abandoned cycle must be able to finish
+	go func() { done <- cycle(run) }()
+
+	timer := time.NewTimer(deadline)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		at.consecutiveTimeouts = 0 // Finished in time, failed or not
+		return err
+	case <-timer.C:
+		run.aborted.Store(true)
+		at.handleCycleTimeout(deadline)
+		return fmt.Errorf("%w after %v", ErrCycleTimeout, deadline)
+	}
+}
+
+// handleCycleTimeout logs an abandoned cycle as failed and alerts on repeated timeouts
+func (at *AutoTrader) handleCycleTimeout(deadline time.Duration) {
+	at.consecutiveTimeouts++
+	msg := fmt.Sprintf("Cycle timed out after %v (deadline), abandoned", deadline)
+	logger.Errorf("⏱️ [%s] %s (%d in a row)", at.name, msg, at.consecutiveTimeouts)
+
+	record := &store.DecisionRecord{
+		Success:      false,
+		ErrorMessage: msg,
+		ExecutionLog: []string{fmt.Sprintf("⏱️ Watchdog: cycle still running %v after it started, no further actions taken", deadline)},
+	}
+	if err := at.saveDecision(record); err != nil {
+		logger.Warnf("⚠️ [%s] Failed to record cycle timeout: %v", at.name, err)
+	}
+
+	if at.consecutiveTimeouts == cycleTimeoutAlertAfter {
+		at.recordEvent(store.TraderEventCycleTimeout,
+			fmt.Sprintf("%d consecutive cycles timed out, exchange or AI provider may be unresponsive", at.consecutiveTimeouts),
+			map[string]interface{}{
+				"deadline":             deadline.String(),
+				"consecutive_timeouts": at.consecutiveTimeouts,
+				"scan_interval":        at.config.ScanInterval.String(),
+			})
+	}
+}
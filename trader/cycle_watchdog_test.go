@@ -0,0 +1,67 @@
+package trader
+
+import (
+	"errors"
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+func TestCycleDeadline(t *testing.T) {
+	cases := []struct {
+		scan, configured, want time.Duration
+	}{
+		{3 * time.Minute, 0, 3*time.Minute - cycleDeadlineBuffer},
+		{30 * time.Second, 0, minCycleDeadline},
+		{3 * time.Minute, 90 * time.Second, 90 * time.Second},
+	}
+	for _, tc := range cases {
+		if got := CycleDeadline(tc.scan, tc.configured); got != tc.want {
+			t.Errorf("CycleDeadline(%v, %v) = %v, want %v", tc.scan, tc.configured, got, tc.want)
+		}
+	}
+}
+
+func TestRunWithDeadlineAbandonsOverrunningCycle(t *testing.T) {
+	at := newStoreTestTrader(t, AutoTraderConfig{ScanInterval: 3 * time.Minute, CycleTimeout: 20 * time.Millisecond})
+
+	release := make(chan struct{})
+	stopped := make(chan bool, cycleTimeoutAlertAfter)
+	hang := func(run *cycleRun) error {
+		<-release
+		stopped <- run.abortedCheckpoint(at.name, "in test")
+		return errCycleAborted
+	}
+
+	for i := 0; i < cycleTimeoutAlertAfter; i++ {
+		if err := at.runWithDeadline(hang); !errors.Is(err, ErrCycleTimeout) {
+			t.Fatalf("timeout %d: expected ErrCycleTimeout, got %v", i+1, err)
+		}
+	}
+	close(release)
+	for i := 0; i < cycleTimeoutAlertAfter; i++ {
+		if !<-stopped {
+			t.Error("abandoned cycle should stop at its checkpoint")
+		}
+	}
+
+	records, err := at.store.Decision().GetLatestRecords(at.id, 10)
+	if err != nil {
+		t.Fatalf("GetLatestRecords: %v", err)
+	}
+	if len(records) != cycleTimeoutAlertAfter || records[0].Success {
+		t.Fatalf("expected %d failed decision records, got %+v", cycleTimeoutAlertAfter, records)
+	}
+	events, _, err := at.store.TraderEvent().Query(store.TraderEventFilter{TraderID: at.id, Types: []string{store.TraderEventCycleTimeout}})
+	if err != nil || len(events) != 1 {
+		t.Fatalf("expected one cycle_timeout alert, got %v %v", events, err)
+	}
+
+	// A cycle finishing in time resets the streak
+	if err := at.runWithDeadline(func(*cycleRun) error { return nil }); err != nil {
+		t.Fatalf("expected the cycle to finish, got %v", err)
+	}
+	if at.consecutiveTimeouts != 0 {
+		t.Errorf("expected timeout streak reset, got %d", at.consecutiveTimeouts)
+	}
+}
@@ -0,0 +1,144 @@
+package trader
+
+import (
+	"encoding/json"
+	"fmt"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/store"
+	"time"
+)
+
+// ============================================================================
+// Decision Approval (human-in-the-loop)
+// ============================================================================
+// In approval mode, opening decisions above the trader's notional threshold or
+// below its confidence threshold are queued instead of executed. The user
+// approves or rejects each one through the API within the approval window;
+// approved decisions execute immediately, unanswered ones expire. Closes and
+// holds never wait, so exits are never delayed by a missing approval.
+// ============================================================================
+
+// holdForApproval queues the opening decisions that need approval and returns the ones to execute now
+func (at *AutoTrader) holdForApproval(decisions []kernel.Decision, record *store.DecisionRecord) []kernel.Decision {
+	if at.store == nil {
+		return decisions
+	}
+	approvals := at.store.DecisionApproval()
+	now := time.Now()
+	if n, err := approvals.ExpireDue(at.id, now); err != nil {
+		logger.Warnf("⚠️ [%s] %v", at.name, err)
+	} else if n > 0 {
+		logger.Infof("⌛ [%s] %d pending decision(s) expired without approval", at.name, n)
+		record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("⌛ %d pending decision(s) expired without approval", n))
+	}
+
+	cfg, err := approvals.GetConfig(at.id)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to load approval settings: %v", at.name, err)
+		return decisions
+	}
+	if cfg == nil || !cfg.Enabled {
+		return decisions
+	}
+
+	kept := make([]kernel.Decision, 0, len(decisions))
+	for _, d := range decisions {
+		reason := approvalHoldReason(cfg, &d)
+		if reason == "" {
+			kept = append(kept, d)
+			continue
+		}
+
+		pending, err := at.queueForApproval(d, reason, now.Add(cfg.Window()))
+		if err != nil {
+			// Never execute a decision that needed approval
+			logger.Warnf("⚠️ [%s] %s %s not executed, failed to queue for approval: %v", at.name, d.Symbol, d.Action, err)
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("❌ %s %s not executed, failed to queue for approval", d.Symbol, d.Action))
+			continue
+		}
+		note := fmt.Sprintf("%s %s held for approval (#%d): %s", d.Symbol, d.Action, pending.ID, reason)
+		logger.Infof("⏸ [%s] %s", at.name, note)
+		record.ExecutionLog = append(record.ExecutionLog, "⏸ "+note)
+	}
+	return kept
+}
+
+// approvalHoldReason explains why an opening decision needs approval, empty when it can execute
+func approvalHoldReason(cfg *store.ApprovalConfig, d *kernel.Decision) string {
+	if !d.IsOpen() {
+		return ""
+	}
+	if cfg.MinNotionalUSD <= 0 && cfg.MinConfidence <= 0 {
+		return "every opening decision needs approval"
+	}
+	if cfg.MinNotionalUSD > 0 && d.PositionSizeUSD >= cfg.MinNotionalUSD {
+		return fmt.Sprintf("notional %.2f USDT at or above %.2f USDT", d.PositionSizeUSD, cfg.MinNotionalUSD)
+	}
+	if cfg.MinConfidence > 0 && d.Confidence < cfg.MinConfidence {
+		return fmt.Sprintf("confidence %d below %d", d.Confidence, cfg.MinConfidence)
+	}
+	return ""
+}
+
+// queueForApproval stores a held decision and notifies the user on the timeline
+func (at *AutoTrader) queueForApproval(d kernel.Decision, reason string, expiresAt time.Time) (*store.PendingDecision, error) {
+	raw, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	pending := &store.PendingDecision{
+		TraderID:    at.id,
+		UserID:      at.userID,
+		Symbol:      d.Symbol,
+		Action:      d.Action,
+		Leverage:    d.Leverage,
+		NotionalUSD: d.PositionSizeUSD,
+		Confidence:  d.Confidence,
+		Reasoning:   d.Reasoning,
+		Decision:    string(raw),
+		HoldReason:  reason,
+		ExpiresAt:   expiresAt.UTC().UnixMilli(),
+	}
+	if err := at.store.DecisionApproval().Create(pending); err != nil {
+		return nil, err
+	}
+
+	at.recordEvent(store.TraderEventApprovalPending,
+		fmt.Sprintf("%s %s ($%.2f, %dx) awaiting approval until %s: %s",
+			d.Symbol, d.Action, d.PositionSizeUSD, d.Leverage, expiresAt.UTC().Format("15:04 UTC"), reason),
+		map[string]interface{}{
+			"pending_id":   pending.ID,
+			"symbol":       d.Symbol,
+			"action":       d.Action,
+			"notional_usd": d.PositionSizeUSD,
+			"confidence":   d.Confidence,
+			"expires_at":   pending.ExpiresAt,
+		})
+	return pending, nil
+}
+
+// ExecuteApproved executes a decision the user approved, recording the outcome on the pending decision
+func (at *AutoTrader) ExecuteApproved(p *store.PendingDecision) error {
+	var d kernel.Decision
+	err := json.Unmarshal([]byte(p.Decision), &d)
+	if err != nil {
+		err = fmt.Errorf("invalid stored decision: %w", err)
+	} else if !at.IsRunning() {
+		err = fmt.Errorf("trader is not running")
+	} else {
+		err = at.ExecuteDecision(&d)
+	}
+
+	if finishErr := at.store.DecisionApproval().Finish(p.ID, err); finishErr != nil {
+		logger.Warnf("⚠️ [%s] Failed to record approved decision #%d outcome: %v", at.name, p.ID, finishErr)
+	}
+	details := map[string]interface{}{"pending_id": p.ID, "symbol": p.Symbol, "action": p.Action}
+	if err != nil {
+		details["error"] = err.Error()
+		at.recordEvent(store.TraderEventError, fmt.Sprintf("Approved %s %s failed: %v", p.Symbol, p.Action, err), details)
+		return err
+	}
+	at.recordEvent(store.TraderEventDecision, fmt.Sprintf("Approved %s %s executed", p.Symbol, p.Action), details)
+	return nil
+}
@@ -0,0 +1,33 @@
+package trader
+
+import (
+	"testing"
+
+	"nofx/kernel"
+	"nofx/store"
+)
+
+func TestApprovalHoldReason(t *testing.T) {
+	cfg := &store.ApprovalConfig{Enabled: true, MinNotionalUSD: 1000, MinConfidence: 70}
+	tests := []struct {
+		name string
+		d    kernel.Decision
+		hold bool
+	}{
+		{"large open", kernel.Decision{Action: "open_long", PositionSizeUSD: 1500, Confidence: 90}, true},
+		{"low confidence open", kernel.Decision{Action: "open_short", PositionSizeUSD: 200, Confidence: 55}, true},
+		{"small confident open", kernel.Decision{Action: "open_long", PositionSizeUSD: 200, Confidence: 80}, false},
+		{"large limit entry", kernel.Decision{Action: "open_long_limit", PositionSizeUSD: 1000, Confidence: 90}, true},
+		{"close never waits", kernel.Decision{Action: "close_long", Confidence: 10}, false},
+	}
+	for _, tt := range tests {
+		if got := approvalHoldReason(cfg, &tt.d) != ""; got != tt.hold {
+			t.Errorf("%s: hold = %v, want %v", tt.name, got, tt.hold)
+		}
+	}
+
+	all := &store.ApprovalConfig{Enabled: true}
+	if approvalHoldReason(all, &kernel.Decision{Action: "open_long", PositionSizeUSD: 10, Confidence: 99}) == "" {
+		t.Error("without thresholds every open should need approval")
+	}
+}
@@ -0,0 +1,92 @@
+package trader
+
+import (
+	"nofx/kernel"
+	"nofx/logger"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Decision Event Bus
+// ============================================================================
+// Traders publish every trading decision they executed successfully. The bus
+// queues events and delivers them to subscribers from a single goroutine, so
+// subscribers see a leader's decisions in execution order (close before open)
+// and a slow subscriber never blocks the publishing trader's cycle.
+// ============================================================================
+
+// decisionBusQueueSize events buffered before publishing starts dropping
+const decisionBusQueueSize = 256
+
+// DecisionEvent a trading decision a trader executed successfully
+type DecisionEvent struct {
+	TraderID  string
+	Decision  kernel.Decision // As executed (position size after the trader's own caps)
+	Equity    float64         // Publisher account equity when the decision was made
+	Timestamp time.Time
+}
+
+// DecisionBus in-process fan-out of executed decisions
+type DecisionBus struct {
+	events   chan DecisionEvent
+	mu       sync.RWMutex
+	handlers []func(DecisionEvent)
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewDecisionBus creates a decision bus, call Start to begin delivery
+func NewDecisionBus() *DecisionBus {
+	return &DecisionBus{
+		events: make(chan DecisionEvent, decisionBusQueueSize),
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Subscribe registers a handler called for every published event
+func (b *DecisionBus) Subscribe(handler func(DecisionEvent)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish queues an event, returns false if the queue is full and the event was dropped
+func (b *DecisionBus) Publish(ev DecisionEvent) bool {
+	select {
+	case b.events <- ev:
+		return true
+	default:
+		logger.Warnf("⚠️ Decision bus full, dropped %s %s from trader %s", ev.Decision.Action, ev.Decision.Symbol, ev.TraderID)
+		return false
+	}
+}
+
+// Start starts delivering queued events to subscribers
+func (b *DecisionBus) Start() {
+	go func() {
+		for {
+			select {
+			case ev := <-b.events:
+				b.deliver(ev)
+			case <-b.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops delivery, queued events are discarded
+func (b *DecisionBus) Stop() {
+	b.stopOnce.Do(func() { close(b.stopCh) })
+}
+
+// deliver calls every subscriber with the event, in subscription order
+func (b *DecisionBus) deliver(ev DecisionEvent) {
+	b.mu.RLock()
+	handlers := append([]func(DecisionEvent){}, b.handlers...)
+	b.mu.RUnlock()
+	for _, handler := range handlers {
+		handler(ev)
+	}
+}
@@ -0,0 +1,194 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/hook"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"path"
+	"strings"
+)
+
+// ============================================================================
+// Decision Middleware
+// ============================================================================
+// AI decisions pass through a middleware chain before execution:
+//   1. the POST_DECISION hook (Go plugins registered via the hook package),
+//   2. the trader's own decision rules, in their configured order.
+// Each step may veto, modify or annotate decisions; every change is written to
+// the cycle's execution log. User rules run last so their limits always hold.
+// Only opening decisions are filtered, closes and holds always pass through.
+// Symbols blocked for both sides (block_open, allow_symbols) are also dropped
+// from the candidate coins, so the AI is not shown coins it may not trade.
+// The PRE_DECISION hook may skip a cycle before the AI is asked at all.
+// ============================================================================
+
+// preDecisionHook asks the PRE_DECISION hook whether this cycle's AI decision should be skipped
+// Returns the skip reason, empty to continue
+func (at *AutoTrader) preDecisionHook(ctx *kernel.Context) string {
+	res := hook.HookExec[hook.PreDecisionResult](hook.PRE_DECISION, at.id, ctx)
+	if res == nil || res.Error() != nil || !res.Skip {
+		return ""
+	}
+	if res.Reason == "" {
+		return "skipped by pre-decision hook"
+	}
+	return res.Reason
+}
+
+// applyDecisionMiddleware runs the AI decisions through the plugin hook and the trader's rules
+func (at *AutoTrader) applyDecisionMiddleware(decisions []kernel.Decision, record *store.DecisionRecord) []kernel.Decision {
+	if res := hook.HookExec[hook.PostDecisionResult](hook.POST_DECISION, at.id, &decisions); res != nil {
+		for _, note := range res.GetResult() {
+			record.ExecutionLog = append(record.ExecutionLog, "🔌 "+note)
+		}
+	}
+
+	if at.store == nil {
+		return decisions
+	}
+	rules, err := at.store.DecisionRule().ListEnabled(at.id)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to load decision rules: %v", at.name, err)
+		return decisions
+	}
+
+	decisions, notes := applyDecisionRules(rules, decisions)
+	for _, note := range notes {
+		logger.Infof("🧩 [%s] %s", at.name, note)
+		record.ExecutionLog = append(record.ExecutionLog, "🧩 "+note)
+	}
+	return decisions
+}
+
+// applyDecisionRules applies rules in order, returns the decisions left to execute and a note per veto or change
+func applyDecisionRules(rules []*store.DecisionRule, decisions []kernel.Decision) ([]kernel.Decision, []string) {
+	var notes []string
+	for i, rule := range rules {
+		label := fmt.Sprintf("rule #%d (%s)", i+1, rule.Type)
+		if rule.Note != "" {
+			label += " " + rule.Note
+		}
+
+		kept := make([]kernel.Decision, 0, len(decisions))
+		opens := 0
+		for _, d := range decisions {
+			if !d.IsOpen() {
+				kept = append(kept, d)
+				continue
+			}
+			if rule.Type == store.DecisionRuleAllowSymbols {
+				if !ruleMatchesSymbol(rule, d.Symbol) {
+					notes = append(notes, fmt.Sprintf("%s %s vetoed by %s: symbol not allowed", d.Symbol, d.Action, label))
+					continue
+				}
+				kept = append(kept, d)
+				continue
+			}
+			if !ruleMatchesSymbol(rule, d.Symbol) {
+				kept = append(kept, d)
+				continue
+			}
+
+			switch rule.Type {
+			case store.DecisionRuleBlockOpen:
+				if rule.Side == "" || rule.Side == openSide(d.Action) {
+					notes = append(notes, fmt.Sprintf("%s %s vetoed by %s", d.Symbol, d.Action, label))
+					continue
+				}
+			case store.DecisionRuleMaxNewPositions:
+				if opens >= int(rule.Value) {
+					notes = append(notes, fmt.Sprintf("%s %s vetoed by %s: max %d new positions per cycle",
+						d.Symbol, d.Action, label, int(rule.Value)))
+					continue
+				}
+				opens++
+			case store.DecisionRuleMaxLeverage:
+				if d.Leverage > int(rule.Value) {
+					notes = append(notes, fmt.Sprintf("%s %s leverage %dx capped to %dx by %s",
+						d.Symbol, d.Action, d.Leverage, int(rule.Value), label))
+					d.Leverage = int(rule.Value)
+				}
+			case store.DecisionRuleMaxPositionUSD:
+				if d.PositionSizeUSD > rule.Value {
+					notes = append(notes, fmt.Sprintf("%s %s position %.2f USDT capped to %.2f USDT by %s",
+						d.Symbol, d.Action, d.PositionSizeUSD, rule.Value, label))
+					d.PositionSizeUSD = rule.Value
+				}
+			}
+			kept = append(kept, d)
+		}
+		decisions = kept
+	}
+	return decisions, notes
+}
+
+// ruleMatchesSymbol reports whether a rule applies to symbol (rules without symbols apply to all)
+func ruleMatchesSymbol(rule *store.DecisionRule, symbol string) bool {
+	symbols := rule.SymbolList()
+	if len(symbols) == 0 {
+		return true
+	}
+	normalized := market.Normalize(symbol)
+	for _, s := range symbols {
+		if strings.ContainsAny(s, "*?[") {
+			// Patterns match the normalized symbol, e.g. *PEPE* or 1000*
+			if ok, _ := path.Match(s, normalized); ok {
+				return true
+			}
+			continue
+		}
+		if market.Normalize(s) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// filterDisallowedCandidates drops candidate coins the trader's rules forbid opening on either side
+func (at *AutoTrader) filterDisallowedCandidates(coins []kernel.CandidateCoin) []kernel.CandidateCoin {
+	if at.store == nil {
+		return coins
+	}
+	rules, err := at.store.DecisionRule().ListEnabled(at.id)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to load decision rules: %v", at.name, err)
+		return coins
+	}
+	filtered := coins[:0]
+	for _, coin := range coins {
+		if rule := symbolBlockedBy(rules, coin.Symbol); rule != nil {
+			logger.Infof("🧩 [%s] Skipping candidate %s: not allowed by %s rule", at.name, coin.Symbol, rule.Type)
+			continue
+		}
+		filtered = append(filtered, coin)
+	}
+	return filtered
+}
+
+// symbolBlockedBy returns the rule forbidding both long and short opens in symbol, nil if none
+func symbolBlockedBy(rules []*store.DecisionRule, symbol string) *store.DecisionRule {
+	for _, rule := range rules {
+		switch rule.Type {
+		case store.DecisionRuleAllowSymbols:
+			if !ruleMatchesSymbol(rule, symbol) {
+				return rule
+			}
+		case store.DecisionRuleBlockOpen:
+			if rule.Side == "" && ruleMatchesSymbol(rule, symbol) {
+				return rule
+			}
+		}
+	}
+	return nil
+}
+
+// openSide returns "long" or "short" for an opening action
+func openSide(action string) string {
+	if strings.HasPrefix(action, "open_short") {
+		return "short"
+	}
+	return "long"
+}
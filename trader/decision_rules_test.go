@@ -0,0 +1,89 @@
+package trader
+
+import (
+	"nofx/kernel"
+	"nofx/store"
+	"testing"
+)
+
+func TestApplyDecisionRules(t *testing.T) {
+	rules := []*store.DecisionRule{
+		{Type: store.DecisionRuleBlockOpen, Symbols: "btc", Side: "short"},
+		{Type: store.DecisionRuleMaxLeverage, Value: 5},
+		{Type: store.DecisionRuleMaxNewPositions, Value: 1},
+	}
+	decisions := []kernel.Decision{
+		{Symbol: "BTCUSDT", Action: "close_long"},
+		{Symbol: "BTCUSDT", Action: "open_short", Leverage: 10},
+		{Symbol: "ETHUSDT", Action: "open_long", Leverage: 10},
+		{Symbol: "SOLUSDT", Action: "open_long_limit", Leverage: 3},
+		{Symbol: "SOLUSDT", Action: "hold"},
+	}
+
+	kept, notes := applyDecisionRules(rules, decisions)
+	if len(kept) != 3 {
+		t.Fatalf("expected close, first open and hold to remain, got %+v", kept)
+	}
+	if kept[0].Action != "close_long" || kept[2].Action != "hold" {
+		t.Errorf("closes and holds must pass through unchanged, got %+v", kept)
+	}
+	if kept[1].Symbol != "ETHUSDT" || kept[1].Leverage != 5 {
+		t.Errorf("expected ETHUSDT open with leverage capped to 5x, got %+v", kept[1])
+	}
+	if len(notes) != 3 {
+		t.Errorf("expected a note for the veto, the cap and the per-cycle limit, got %v", notes)
+	}
+	if decisions[2].Leverage != 10 {
+		t.Error("input decisions must not be modified")
+	}
+}
+
+func TestApplyDecisionRules_MaxPositionUSD(t *testing.T) {
+	rules := []*store.DecisionRule{{Type: store.DecisionRuleMaxPositionUSD, Symbols: "ETHUSDT", Value: 200}}
+	kept, _ := applyDecisionRules(rules, []kernel.Decision{
+		{Symbol: "ETHUSDT", Action: "open_long", PositionSizeUSD: 500},
+		{Symbol: "BTCUSDT", Action: "open_long", PositionSizeUSD: 500},
+	})
+	if kept[0].PositionSizeUSD != 200 || kept[1].PositionSizeUSD != 500 {
+		t.Errorf("only ETHUSDT should be capped, got %+v", kept)
+	}
+}
+
+func TestApplyDecisionRules_SymbolPermissions(t *testing.T) {
+	rules := []*store.DecisionRule{
+		{Type: store.DecisionRuleAllowSymbols, Symbols: "BTCUSDT, eth, *PEPE*"},
+		{Type: store.DecisionRuleBlockOpen, Symbols: "1000*"},
+	}
+	kept, notes := applyDecisionRules(rules, []kernel.Decision{
+		{Symbol: "BTCUSDT", Action: "open_long"},
+		{Symbol: "ETHUSDT", Action: "open_short"},
+		{Symbol: "PEPEUSDT", Action: "open_long"},
+		{Symbol: "1000PEPEUSDT", Action: "open_long"},
+		{Symbol: "SOLUSDT", Action: "open_long"},
+		{Symbol: "SOLUSDT", Action: "close_long"},
+	})
+	if len(kept) != 4 || kept[2].Symbol != "PEPEUSDT" || kept[3].Action != "close_long" {
+		t.Fatalf("expected BTC, ETH, PEPE opens and the SOL close, got %+v", kept)
+	}
+	if len(notes) != 2 {
+		t.Errorf("expected vetoes of SOLUSDT and 1000PEPEUSDT, got %v", notes)
+	}
+
+	for symbol, blocked := range map[string]bool{"BTCUSDT": false, "SOLUSDT": true, "1000PEPEUSDT": true} {
+		if got := symbolBlockedBy(rules, symbol) != nil; got != blocked {
+			t.Errorf("%s blocked = %v, want %v", symbol, got, blocked)
+		}
+	}
+}
+
+func TestDecisionRuleValidate_SymbolPatterns(t *testing.T) {
+	if err := (&store.DecisionRule{Type: store.DecisionRuleAllowSymbols}).Validate(); err == nil {
+		t.Error("allow_symbols without symbols should be rejected")
+	}
+	if err := (&store.DecisionRule{Type: store.DecisionRuleBlockOpen, Symbols: "[PEPE"}).Validate(); err == nil {
+		t.Error("malformed pattern should be rejected")
+	}
+	if err := (&store.DecisionRule{Type: store.DecisionRuleAllowSymbols, Symbols: "BTC*,ETHUSDT"}).Validate(); err != nil {
+		t.Errorf("valid allowlist rejected: %v", err)
+	}
+}
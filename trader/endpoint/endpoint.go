@@ -0,0 +1,275 @@
+package endpoint
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"nofx/logger"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Exchanges with several interchangeable API hosts
+const (
+	Bybit = "bybit"
+	OKX   = "okx"
+)
+
+const (
+	// probeSamples requests timed per endpoint, the median is kept
+	probeSamples = 3
+	// switchMargin a healthy selected endpoint is only replaced by one at least this much faster (avoids flapping)
+	switchMargin = 0.2
+	probeTimeout = 5 * time.Second
+)
+
+// EndpointStatus latency of one base URL at the last benchmark
+type EndpointStatus struct {
+	URL       string    `json:"url"`
+	Selected  bool      `json:"selected"`
+	Healthy   bool      `json:"healthy"`
+	LatencyMs float64   `json:"latency_ms"` // Median of the probes, 0 until measured or when unhealthy
+	Failures  int       `json:"failures"`   // Consecutive failed benchmarks
+	LastError string    `json:"last_error,omitempty"`
+	CheckedAt time.Time `json:"checked_at,omitempty"`
+}
+
+// ExchangeStatus endpoints of one exchange and the one requests are sent to
+type ExchangeStatus struct {
+	Exchange  string           `json:"exchange"`
+	Selected  string           `json:"selected"`
+	Switches  int              `json:"switches"` // Times the selection changed since startup
+	Endpoints []EndpointStatus `json:"endpoints"`
+}
+
+// group candidate base URLs of one exchange, the first is the default
+type group struct {
+	probePath string
+	endpoints []*EndpointStatus
+	selected  int
+	switches  int
+}
+
+// Selector benchmarks the base URLs of each exchange and routes requests to the fastest healthy one
+type Selector struct {
+	mu     sync.RWMutex
+	groups map[string]*group
+	client *http.Client
+}
+
+// NewSelector creates a selector probing endpoints with client
+func NewSelector(client *http.Client) *Selector {
+	return &Selector{groups: make(map[string]*group), client: client}
+}
+
+// Register adds the candidate base URLs of an exchange, probed with a GET of probePath (a cheap public endpoint).
+// The first URL stays selected until a benchmark finds a faster healthy one.
+func (s *Selector) Register(exchange, probePath string, baseURLs ...string) {
+	g := &group{probePath: probePath}
+	for _, u := range baseURLs {
+		g.endpoints = append(g.endpoints, &EndpointStatus{URL: u})
+	}
+	s.mu.Lock()
+	s.groups[exchange] = g
+	s.mu.Unlock()
+}
+
+// BaseURL the selected base URL of an exchange, "" when it isn't registered
+func (s *Selector) BaseURL(exchange string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	g, ok := s.groups[exchange]
+	if !ok || len(g.endpoints) == 0 {
+		return ""
+	}
+	return g.endpoints[g.selected].URL
+}
+
+// Benchmark measures every endpoint of every exchange concurrently and updates the selections
+func (s *Selector) Benchmark() {
+	s.mu.RLock()
+	exchanges := make([]string, 0, len(s.groups))
+	for name := range s.groups {
+		exchanges = append(exchanges, name)
+	}
+	s.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, name := range exchanges {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			s.benchmark(name)
+		}(name)
+	}
+	wg.Wait()
+}
+
+// benchmark measures the endpoints of one exchange and selects the fastest healthy one
+func (s *Selector) benchmark(exchange string) {
+	s.mu.RLock()
+	g := s.groups[exchange]
+	urls := make([]string, len(g.endpoints))
+	for i, e := range g.endpoints {
+		urls[i] = e.URL
+	}
+	probePath := g.probePath
+	s.mu.RUnlock()
+
+	latencies := make([]time.Duration, len(urls))
+	errs := make([]error, len(urls))
+	for i, u := range urls {
+		latencies[i], errs[i] = s.measure(u + probePath)
+	}
+	now := time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	best := -1
+	for i, e := range g.endpoints {
+		e.CheckedAt = now
+		if errs[i] != nil {
+			e.Healthy = false
+			e.LatencyMs = 0
+			e.Failures++
+			e.LastError = errs[i].Error()
+			continue
+		}
+		e.Healthy = true
+		e.LatencyMs = float64(latencies[i].Microseconds()) / 1000
+		e.Failures = 0
+		e.LastError = ""
+		if best < 0 || e.LatencyMs < g.endpoints[best].LatencyMs {
+			best = i
+		}
+	}
+	if best < 0 || best == g.selected {
+		if best < 0 {
+			logger.Warnf("⚠️ No %s API endpoint answered the latency benchmark, keeping %s", exchange, g.endpoints[g.selected].URL)
+		}
+		return
+	}
+	current := g.endpoints[g.selected]
+	if current.Healthy && g.endpoints[best].LatencyMs > current.LatencyMs*(1-switchMargin) {
+		return
+	}
+	logger.Infof("🌐 %s API endpoint switched %s (%.0fms) -> %s (%.0fms)", exchange,
+		current.URL, current.LatencyMs, g.endpoints[best].URL, g.endpoints[best].LatencyMs)
+	g.selected = best
+	g.switches++
+}
+
+// measure median latency of probeSamples requests, failing if any of them fails
+func (s *Selector) measure(target string) (time.Duration, error) {
+	samples := make([]time.Duration, 0, probeSamples)
+	for i := 0; i < probeSamples; i++ {
+		start := time.Now()
+		resp, err := s.client.Get(target)
+		if err != nil {
+			return 0, err
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return 0, fmt.Errorf("HTTP %d", resp.StatusCode)
+		}
+		samples = append(samples, time.Since(start))
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	return samples[len(samples)/2], nil
+}
+
+// isCandidate reports whether host is one of the endpoints of exchange
+func (s *Selector) isCandidate(exchange, host string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	g, ok := s.groups[exchange]
+	if !ok {
+		return false
+	}
+	for _, e := range g.endpoints {
+		if u, err := url.Parse(e.URL); err == nil && u.Host == host {
+			return true
+		}
+	}
+	return false
+}
+
+// Transport routes requests sent to any endpoint of exchange to the selected one, for SDK clients
+// whose base URL is fixed when they are created
+func (s *Selector) Transport(exchange string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &rewriteTransport{selector: s, exchange: exchange, base: base}
+}
+
+type rewriteTransport struct {
+	selector *Selector
+	exchange string
+	base     http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target, err := url.Parse(t.selector.BaseURL(t.exchange))
+	if err != nil || target.Host == "" || target.Host == req.URL.Host || !t.selector.isCandidate(t.exchange, req.URL.Host) {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.URL.Scheme = target.Scheme
+	req.URL.Host = target.Host
+	req.Host = ""
+	return t.base.RoundTrip(req)
+}
+
+// Status endpoints of each exchange, sorted by exchange
+func (s *Selector) Status() []ExchangeStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	status := make([]ExchangeStatus, 0, len(s.groups))
+	for name, g := range s.groups {
+		es := ExchangeStatus{Exchange: name, Selected: g.endpoints[g.selected].URL, Switches: g.switches}
+		for i, e := range g.endpoints {
+			copied := *e
+			copied.Selected = i == g.selected
+			es.Endpoints = append(es.Endpoints, copied)
+		}
+		status = append(status, es)
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Exchange < status[j].Exchange })
+	return status
+}
+
+// selector endpoints of the exchanges offering more than one API host
+var selector = newDefaultSelector()
+
+func newDefaultSelector() *Selector {
+	s := NewSelector(&http.Client{Timeout: probeTimeout})
+	// api.bytick.com is Bybit's alternative domain for the same global accounts
+	s.Register(Bybit, "/v5/market/time", "https://api.bybit.com", "https://api.bytick.com")
+	// aws.okx.com is served from AWS regions, usually faster for servers hosted there
+	s.Register(OKX, "/api/v5/public/time", "https://www.okx.com", "https://aws.okx.com")
+	return s
+}
+
+// BaseURL the selected base URL of an exchange
+func BaseURL(exchange string) string {
+	return selector.BaseURL(exchange)
+}
+
+// Transport routes an SDK client's requests to the selected endpoint of exchange
+func Transport(exchange string, base http.RoundTripper) http.RoundTripper {
+	return selector.Transport(exchange, base)
+}
+
+// Benchmark re-measures all exchange endpoints
+func Benchmark() {
+	selector.Benchmark()
+}
+
+// Status endpoint latencies and selections of all exchanges
+func Status() []ExchangeStatus {
+	return selector.Status()
+}
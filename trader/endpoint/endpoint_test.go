@@ -0,0 +1,90 @@
+package endpoint
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestServer answers probes after delay, with status
+func newTestServer(t *testing.T, delay time.Duration, status *int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.WriteHeader(*status)
+		io.WriteString(w, r.URL.Path)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSelector_PicksFastestHealthy(t *testing.T) {
+	ok, down := http.StatusOK, http.StatusServiceUnavailable
+	slow := newTestServer(t, 60*time.Millisecond, &ok)
+	fast := newTestServer(t, 0, &ok)
+
+	s := NewSelector(&http.Client{Timeout: time.Second})
+	s.Register("ex", "/time", slow.URL, fast.URL)
+	if s.BaseURL("ex") != slow.URL {
+		t.Fatal("first endpoint should be selected before benchmarking")
+	}
+
+	s.Benchmark()
+	if s.BaseURL("ex") != fast.URL {
+		t.Fatalf("selected %s, want the faster %s", s.BaseURL("ex"), fast.URL)
+	}
+
+	// The selected endpoint failing moves requests back to the slower healthy one
+	unhealthy := newTestServer(t, 0, &down)
+	s.Register("ex", "/time", unhealthy.URL, slow.URL)
+	s.Benchmark()
+	status := s.Status()[0]
+	if status.Selected != slow.URL || status.Switches != 1 {
+		t.Fatalf("status = %+v", status)
+	}
+	if status.Endpoints[0].Healthy || status.Endpoints[0].Failures != 1 || status.Endpoints[0].LastError != "HTTP 503" {
+		t.Errorf("failed endpoint = %+v", status.Endpoints[0])
+	}
+	if !status.Endpoints[1].Selected || status.Endpoints[1].LatencyMs < 60 {
+		t.Errorf("selected endpoint = %+v", status.Endpoints[1])
+	}
+}
+
+func TestSelector_KeepsSelectionWithinMargin(t *testing.T) {
+	ok := http.StatusOK
+	a := newTestServer(t, 50*time.Millisecond, &ok)
+	b := newTestServer(t, 45*time.Millisecond, &ok)
+
+	s := NewSelector(&http.Client{Timeout: time.Second})
+	s.Register("ex", "/time", a.URL, b.URL)
+	s.Benchmark()
+	if s.BaseURL("ex") != a.URL {
+		t.Error("an endpoint only slightly faster should not take over")
+	}
+}
+
+func TestTransport_RewritesToSelected(t *testing.T) {
+	ok := http.StatusOK
+	def := newTestServer(t, 0, &ok)
+	alt := newTestServer(t, 0, &ok)
+
+	s := NewSelector(&http.Client{Timeout: time.Second})
+	s.Register("ex", "/time", def.URL, alt.URL)
+	s.groups["ex"].selected = 1
+
+	client := &http.Client{Transport: s.Transport("ex", nil)}
+	resp, err := client.Get(def.URL + "/v5/order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.Request.URL.Host != alt.Listener.Addr().String() || string(body) != "/v5/order" {
+		t.Errorf("request served by %s (%s), want %s", resp.Request.URL.Host, body, alt.URL)
+	}
+
+	if s.isCandidate("ex", "example.com") {
+		t.Error("hosts of other services must not be rerouted")
+	}
+}
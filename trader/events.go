@@ -0,0 +1,84 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"nofx/store"
+	"strings"
+	"sync"
+)
+
+// Event a timeline event, as passed to the event listener
+type Event struct {
+	UserID     string
+	TraderID   string
+	TraderName string
+	Type       string
+	Message    string
+	Details    map[string]interface{}
+}
+
+var (
+	eventListenerMu sync.RWMutex
+	eventListener   func(Event)
+)
+
+// SetEventListener registers the function receiving every recorded trader event, e.g. to notify users (nil: none)
+// It is called on the trader's goroutine and must not block.
+func SetEventListener(fn func(Event)) {
+	eventListenerMu.Lock()
+	defer eventListenerMu.Unlock()
+	eventListener = fn
+}
+
+// recordEvent appends an entry to the trader's event timeline (best effort, failures are only logged)
+func (at *AutoTrader) recordEvent(eventType, message string, details map[string]interface{}) {
+	if at.store == nil {
+		return
+	}
+	if err := at.store.TraderEvent().Append(at.id, eventType, message, details); err != nil {
+		logger.Warnf("⚠️ [%s] Failed to record %s event: %v", at.name, eventType, err)
+	}
+
+	eventListenerMu.RLock()
+	listener := eventListener
+	eventListenerMu.RUnlock()
+	if listener != nil {
+		listener(Event{UserID: at.userID, TraderID: at.id, TraderName: at.name, Type: eventType, Message: message, Details: details})
+	}
+}
+
+// recordHalt records that the trader stopped because of an error
+func (at *AutoTrader) recordHalt(err error) {
+	at.recordEvent(store.TraderEventHalted, "Trader stopped on error: "+err.Error(), map[string]interface{}{"error": err.Error()})
+}
+
+// recordDecisionEvent summarizes a saved decision record on the timeline
+func (at *AutoTrader) recordDecisionEvent(record *store.DecisionRecord) {
+	actions := make([]string, 0, len(record.Decisions))
+	for _, d := range record.Decisions {
+		if d.Action == "hold" || d.Action == "wait" {
+			continue
+		}
+		status := "ok"
+		if !d.Success {
+			status = "failed"
+		}
+		actions = append(actions, fmt.Sprintf("%s %s (%s)", d.Action, d.Symbol, status))
+	}
+
+	message := fmt.Sprintf("Cycle #%d: no trades", record.CycleNumber)
+	if len(actions) > 0 {
+		message = fmt.Sprintf("Cycle #%d: %s", record.CycleNumber, strings.Join(actions, ", "))
+	}
+	details := map[string]interface{}{
+		"decision_id":  record.ID,
+		"cycle_number": record.CycleNumber,
+		"success":      record.Success,
+	}
+	if record.ErrorMessage != "" {
+		message += " | " + record.ErrorMessage
+		details["error"] = record.ErrorMessage
+	}
+	at.recordEvent(store.TraderEventDecision, message, details)
+}
@@ -0,0 +1,87 @@
+package trader
+
+import (
+	"nofx/logger"
+	"nofx/trader/types"
+	"time"
+)
+
+// ============================================================================
+// Exchange Error Classes & Retry Policies
+// ============================================================================
+// Every exchange error is classified (rate limited, auth, insufficient margin,
+// invalid symbol, invalid order, network) by the backend's native error codes, or by common
+// messages when the backend has none. Failed calls are retried per class:
+// rate-limited calls are always safe to repeat since the exchange rejected
+// them outright; network failures are only retried for reads, because an
+// order may have reached the exchange (submitOpenOrder resolves those by
+// client order ID). Auth, margin, symbol and order errors are never retried.
+// ============================================================================
+
+// exchangeRetryPolicy how calls failing with one error class are retried
+type exchangeRetryPolicy struct {
+	attempts int           // Total attempts including the first
+	delay    time.Duration // Delay before the first retry, doubled per retry
+	orders   bool          // Also retry order submissions
+}
+
+// exchangeRetryPolicies retried error classes, other classes fail immediately
+var exchangeRetryPolicies = map[ErrorClass]exchangeRetryPolicy{
+	types.ErrClassRateLimited: {attempts: 4, delay: 2 * time.Second, orders: true},
+	types.ErrClassNetwork:     {attempts: 3, delay: time.Second},
+}
+
+// classifyExchangeError classifies an error of the trader's exchange
+func (at *AutoTrader) classifyExchangeError(err error) ErrorClass {
+	if classifier, ok := at.trader.(ErrorClassifier); ok {
+		return classifier.ClassifyError(err)
+	}
+	return types.ClassifyError(err)
+}
+
+// recordExchangeError counts a failed exchange call under its class
+func (at *AutoTrader) recordExchangeError(class ErrorClass) {
+	at.exchangeErrorsMu.Lock()
+	defer at.exchangeErrorsMu.Unlock()
+	if at.exchangeErrors == nil {
+		at.exchangeErrors = make(map[ErrorClass]int64)
+	}
+	at.exchangeErrors[class]++
+}
+
+// ExchangeErrorCounts failed exchange calls by error class since the trader was created (retried attempts included)
+func (at *AutoTrader) ExchangeErrorCounts() map[string]int64 {
+	at.exchangeErrorsMu.Lock()
+	defer at.exchangeErrorsMu.Unlock()
+	counts := make(map[string]int64, len(types.ErrorClasses))
+	for _, class := range types.ErrorClasses {
+		counts[string(class)] = at.exchangeErrors[class]
+	}
+	return counts
+}
+
+// exchangeCall runs an exchange call, retrying failures per their class's policy
+// order marks order submissions, which are only retried when the exchange rejected them outright.
+func exchangeCall[T any](at *AutoTrader, op string, order bool, call func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := call()
+		if err == nil {
+			return result, nil
+		}
+		class := at.classifyExchangeError(err)
+		at.recordExchangeError(class)
+
+		policy, ok := exchangeRetryPolicies[class]
+		if !ok || attempt >= policy.attempts || (order && !policy.orders) {
+			return result, err
+		}
+		delay := policy.delay << (attempt - 1)
+		logger.Warnf("🔁 [%s] %s failed (%s), retrying in %v (attempt %d/%d): %v",
+			at.name, op, class, delay, attempt+1, policy.attempts, err)
+		select {
+		case <-time.After(delay):
+		case <-at.stopMonitorCh:
+			return result, err // Trader stopping: don't hold up shutdown
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+	"nofx/trader/binance"
+	"nofx/trader/gate"
+	"nofx/trader/hyperliquid"
+	"nofx/trader/lighter"
+	"nofx/trader/types"
+	"testing"
+	"time"
+
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/gateio/gateapi-go/v6"
+)
+
+var (
+	_ ErrorClassifier = (*binance.FuturesTrader)(nil)
+	_ ErrorClassifier = (*gate.GateTrader)(nil)
+	_ ErrorClassifier = (*hyperliquid.HyperliquidTrader)(nil)
+	_ ErrorClassifier = (*lighter.LighterTraderV2)(nil)
+)
+
+func TestClassifyExchangeErrors(t *testing.T) {
+	cases := []struct {
+		name       string
+		classifier func(error) ErrorClass
+		err        error
+		want       ErrorClass
+	}{
+		{"binance margin", (&binance.FuturesTrader{}).ClassifyError,
+			fmt.Errorf("failed to open long: %w", &common.APIError{Code: -2019, Message: "Margin is insufficient."}),
+			types.ErrClassInsufficientMargin},
+		{"binance rate limit", (&binance.FuturesTrader{}).ClassifyError,
+			&common.APIError{Code: -1003, Message: "Too many requests"}, types.ErrClassRateLimited},
+		{"binance min notional", (&binance.FuturesTrader{}).ClassifyError,
+			&common.APIError{Code: -4164, Message: "Order's notional must be no smaller than 5"}, types.ErrClassInvalidOrder},
+		{"gate auth", (&gate.GateTrader{}).ClassifyError,
+			fmt.Errorf("failed to get balance: %w", gateapi.GateAPIError{Label: "INVALID_KEY"}), types.ErrClassAuth},
+		{"hyperliquid margin", (&hyperliquid.HyperliquidTrader{}).ClassifyError,
+			errors.New("Insufficient margin to place order. asset=0"), types.ErrClassInsufficientMargin},
+		{"lighter status", (&lighter.LighterTraderV2{}).ClassifyError,
+			errors.New("failed to get account (status 429): slow down"), types.ErrClassRateLimited},
+		{"tagged", types.ClassifyError,
+			types.NewExchangeError(types.ErrClassInvalidSymbol, "51001", errors.New("OKX API error: code=51001")),
+			types.ErrClassInvalidSymbol},
+		{"untagged code falls back to message", types.ClassifyError,
+			types.NewExchangeError(types.ErrClassUnknown, "1", errors.New("read tcp: connection reset by peer")),
+			types.ErrClassNetwork},
+		{"http status", types.ClassifyError, errors.New("HTTP 503: Service Unavailable"), types.ErrClassNetwork},
+		{"unknown", types.ClassifyError, errors.New("order price out of range"), types.ErrClassUnknown},
+	}
+	for _, tc := range cases {
+		if got := tc.classifier(tc.err); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestExchangeCallRetriesByClass(t *testing.T) {
+	saved := exchangeRetryPolicies
+	defer func() { exchangeRetryPolicies = saved }()
+	exchangeRetryPolicies = map[ErrorClass]exchangeRetryPolicy{
+		types.ErrClassRateLimited: {attempts: 3, delay: time.Millisecond, orders: true},
+		types.ErrClassNetwork:     {attempts: 3, delay: time.Millisecond},
+	}
+
+	failing := func(errs ...error) (func() (int, error), *int) {
+		calls := 0
+		return func() (int, error) {
+			calls++
+			if calls <= len(errs) {
+				return 0, errs[calls-1]
+			}
+			return calls, nil
+		}, &calls
+	}
+	rateLimited := errors.New("HTTP 429: too many requests")
+	timeout := errors.New("request failed: i/o timeout")
+	auth := errors.New("Invalid API-key, IP, or permissions for action")
+
+	at := &AutoTrader{name: "retry"}
+	call, calls := failing(rateLimited, rateLimited)
+	if _, err := exchangeCall(at, "Open long", true, call); err != nil || *calls != 3 {
+		t.Errorf("rate-limited order: expected success on 3rd attempt, got %v after %d calls", err, *calls)
+	}
+	call, calls = failing(timeout)
+	if _, err := exchangeCall(at, "Open long", true, call); err == nil || *calls != 1 {
+		t.Errorf("network order error must not be resubmitted, got %v after %d calls", err, *calls)
+	}
+	call, calls = failing(timeout, timeout)
+	if _, err := exchangeCall(at, "Get positions", false, call); err != nil || *calls != 3 {
+		t.Errorf("network read: expected success on 3rd attempt, got %v after %d calls", err, *calls)
+	}
+	call, calls = failing(timeout, timeout, timeout)
+	if _, err := exchangeCall(at, "Get positions", false, call); err == nil || *calls != 3 {
+		t.Errorf("network read: expected failure after 3 attempts, got %v after %d calls", err, *calls)
+	}
+	call, calls = failing(auth)
+	if _, err := exchangeCall(at, "Get balance", false, call); err == nil || *calls != 1 {
+		t.Errorf("auth error must not be retried, got %v after %d calls", err, *calls)
+	}
+
+	counts := at.ExchangeErrorCounts()
+	if counts["rate_limited"] != 2 || counts["network"] != 6 || counts["auth"] != 1 || counts["unknown"] != 0 {
+		t.Errorf("unexpected error counts: %+v", counts)
+	}
+}
@@ -0,0 +1,83 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/store"
+	"time"
+)
+
+// orderFill fill data of a submitted order, as reported by the exchange
+type orderFill struct {
+	OrderID  string
+	Status   string // FILLED/CANCELED/EXPIRED/REJECTED, empty if not confirmed in time
+	AvgPrice float64
+	Quantity float64
+	Fee      float64
+}
+
+// orderIDString extracts the exchange order ID from an order result (supports multiple types)
+func orderIDString(orderResult map[string]interface{}) string {
+	switch v := orderResult["orderId"].(type) {
+	case nil:
+		return ""
+	case int64:
+		return fmt.Sprintf("%d", v)
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// pollOrderFill polls the order status until it is filled or ended (up to ~3s)
+func (at *AutoTrader) pollOrderFill(symbol, orderID string) *orderFill {
+	fill := &orderFill{OrderID: orderID}
+	time.Sleep(500 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		status, err := at.trader.GetOrderStatus(symbol, orderID)
+		if err == nil {
+			statusStr, _ := status["status"].(string)
+			if statusStr == "FILLED" {
+				fill.Status = statusStr
+				if avgPrice, ok := status["avgPrice"].(float64); ok && avgPrice > 0 {
+					fill.AvgPrice = avgPrice
+				}
+				if execQty, ok := status["executedQty"].(float64); ok && execQty > 0 {
+					fill.Quantity = execQty
+				}
+				if commission, ok := status["commission"].(float64); ok {
+					fill.Fee = commission
+				}
+				return fill
+			} else if statusStr == "CANCELED" || statusStr == "EXPIRED" || statusStr == "REJECTED" {
+				fill.Status = statusStr
+				return fill
+			}
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	return fill
+}
+
+// recordExecution writes the exchange order ID and fill data into the decision action
+// Slippage is measured against the action's reference price (market price at decision time),
+// positive when the fill is worse for the trader (buy above / sell below the reference)
+func recordExecution(actionRecord *store.DecisionAction, fill *orderFill, isBuy bool) {
+	if fill == nil {
+		return
+	}
+	actionRecord.ExchangeOrderID = fill.OrderID
+	actionRecord.OrderStatus = fill.Status
+	actionRecord.FillPrice = fill.AvgPrice
+	actionRecord.FilledQty = fill.Quantity
+	actionRecord.Fee = fill.Fee
+	if fill.AvgPrice > 0 && actionRecord.Price > 0 {
+		slippage := (fill.AvgPrice - actionRecord.Price) / actionRecord.Price * 100
+		if !isBuy {
+			slippage = -slippage
+		}
+		actionRecord.SlippagePct = slippage
+	}
+}
@@ -0,0 +1,30 @@
+package trader
+
+import (
+	"math"
+	"nofx/store"
+	"testing"
+)
+
+func TestRecordExecution_Slippage(t *testing.T) {
+	buy := store.DecisionAction{Action: "open_long", Price: 100}
+	recordExecution(&buy, &orderFill{OrderID: "42", Status: "FILLED", AvgPrice: 100.5, Quantity: 2, Fee: 0.1}, true)
+	if buy.ExchangeOrderID != "42" || buy.OrderStatus != "FILLED" || buy.FillPrice != 100.5 || buy.FilledQty != 2 || buy.Fee != 0.1 {
+		t.Fatalf("fill not recorded: %+v", buy)
+	}
+	if math.Abs(buy.SlippagePct-0.5) > 1e-9 {
+		t.Errorf("buying above the decision price is adverse slippage, got %.4f%%", buy.SlippagePct)
+	}
+
+	sell := store.DecisionAction{Action: "close_long", Price: 100}
+	recordExecution(&sell, &orderFill{OrderID: "43", Status: "FILLED", AvgPrice: 99}, false)
+	if math.Abs(sell.SlippagePct-1) > 1e-9 {
+		t.Errorf("selling below the decision price is adverse slippage, got %.4f%%", sell.SlippagePct)
+	}
+
+	unconfirmed := store.DecisionAction{Action: "open_short", Price: 100}
+	recordExecution(&unconfirmed, &orderFill{OrderID: "44"}, false)
+	if unconfirmed.ExchangeOrderID != "44" || unconfirmed.SlippagePct != 0 {
+		t.Errorf("unconfirmed fill should only record the order ID: %+v", unconfirmed)
+	}
+}
@@ -0,0 +1,182 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/store"
+	"strings"
+)
+
+// runningExperiment gets the trader's running strategy A/B experiment, nil if none
+func (at *AutoTrader) runningExperiment() *store.StrategyExperiment {
+	if at.store == nil {
+		return nil
+	}
+	exp, err := at.store.Experiment().GetRunning(at.id)
+	if err != nil {
+		logger.Infof("⚠️ [%s] Failed to load strategy experiment: %v", at.name, err)
+		return nil
+	}
+	return exp
+}
+
+// experimentEngine builds the strategy engine of an experiment variant
+func (at *AutoTrader) experimentEngine(exp *store.StrategyExperiment, variant string) (*kernel.StrategyEngine, string, error) {
+	strategy, err := at.store.Strategy().Get(exp.UserID, exp.StrategyID(variant))
+	if err != nil {
+		return nil, "", fmt.Errorf("strategy of variant %s not found: %w", variant, err)
+	}
+	config, err := strategy.ParseConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse strategy of variant %s: %w", variant, err)
+	}
+	engine := kernel.NewStrategyEngine(config)
+	engine.SetCoinPoolSource(at.store.CoinPool().ForTrader(at.userID, at.id))
+	return engine, strategy.Name, nil
+}
+
+// runExperimentCycle runs the decision phase once per variant scheduled for this cycle
+func (at *AutoTrader) runExperimentCycle(run *cycleRun, exp *store.StrategyExperiment, ctx *kernel.Context, baseRecord *store.DecisionRecord) error {
+	entries, err := at.store.Experiment().ListEntries(exp.ID)
+	if err != nil {
+		logger.Infof("⚠️ [%s] Failed to load experiment entries: %v", at.name, err)
+	}
+
+	var lastErr error
+	for _, variant := range exp.NextVariants() {
+		engine, strategyName, err := at.experimentEngine(exp, variant)
+		if err != nil {
+			logger.Infof("❌ [%s] Experiment %s: %v", at.name, exp.Name, err)
+			lastErr = err
+			continue
+		}
+
+		variantCtx, err := at.experimentContext(ctx, engine, exp, variant, entries)
+		if err != nil {
+			logger.Infof("❌ [%s] Experiment %s variant %s: %v", at.name, exp.Name, variant, err)
+			lastErr = err
+			continue
+		}
+
+		record := &store.DecisionRecord{
+			ExecutionLog: append([]string{fmt.Sprintf("🧪 Experiment %s | variant %s (%s) | capital share %.0f%%",
+				exp.Name, variant, strategyName, exp.CapitalShare(variant)*100)}, baseRecord.ExecutionLog...),
+			Success: true,
+		}
+		for _, coin := range variantCtx.CandidateCoins {
+			record.CandidateCoins = append(record.CandidateCoins, coin.Symbol)
+		}
+
+		logger.Infof("🧪 [%s] Experiment %s: running variant %s (%s)", at.name, exp.Name, variant, strategyName)
+		if err := at.decideAndExecute(run, variantCtx, engine, record); err != nil {
+			lastErr = err
+		}
+
+		at.recordExperimentEntries(exp, variant, record.Decisions)
+		if err := at.store.Experiment().IncrementCycles(exp.ID, variant); err != nil {
+			logger.Infof("⚠️ [%s] Failed to update experiment cycles: %v", at.name, err)
+		}
+	}
+	return lastErr
+}
+
+// experimentContext derives a variant's trading context from the cycle context.
+// Candidate coins and market data follow the variant's strategy; in split mode the variant
+// only sees its capital share and the positions it opened (unattributed positions go to A).
+func (at *AutoTrader) experimentContext(base *kernel.Context, engine *kernel.StrategyEngine, exp *store.StrategyExperiment, variant string, entries []*store.ExperimentEntry) (*kernel.Context, error) {
+	candidates, err := engine.GetCandidateCoins()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candidate coins: %w", err)
+	}
+
+	config := engine.GetConfig()
+	ctx := *base
+	ctx.CandidateCoins = candidates
+	ctx.BTCETHLeverage = config.RiskControl.BTCETHMaxLeverage
+	ctx.AltcoinLeverage = config.RiskControl.AltcoinMaxLeverage
+	ctx.MarketDataMap = nil
+	ctx.QuantDataMap = nil
+	ctx.OrderBookMap = nil
+	ctx.OIRankingData = nil
+	ctx.NetFlowRankingData = nil
+	ctx.PriceRankingData = nil
+
+	if exp.Mode == store.ExperimentModeSplit {
+		share := exp.CapitalShare(variant)
+		ctx.Account.TotalEquity *= share
+		ctx.Account.AvailableBalance *= share
+
+		ctx.Positions = nil
+		ctx.Account.MarginUsed = 0
+		for _, pos := range base.Positions {
+			owner := store.AttributeVariant(entries, pos.Symbol, pos.Side, pos.UpdateTime)
+			if owner == variant || (owner == "" && variant == store.ExperimentVariantA) {
+				ctx.Positions = append(ctx.Positions, pos)
+				ctx.Account.MarginUsed += pos.MarginUsed
+			}
+		}
+		ctx.Account.PositionCount = len(ctx.Positions)
+		ctx.Account.MarginUsedPct = 0
+		if ctx.Account.TotalEquity > 0 {
+			ctx.Account.MarginUsedPct = ctx.Account.MarginUsed / ctx.Account.TotalEquity * 100
+		}
+	}
+
+	symbols := make([]string, 0, len(candidates)+len(ctx.Positions))
+	for _, coin := range candidates {
+		symbols = append(symbols, coin.Symbol)
+	}
+	for _, pos := range ctx.Positions {
+		symbols = append(symbols, pos.Symbol)
+	}
+	if config.Indicators.EnableQuantData {
+		ctx.QuantDataMap = engine.FetchQuantDataBatch(symbols)
+	}
+	if config.Indicators.EnableOrderBook {
+		candidateSymbols := make([]string, 0, len(candidates))
+		for _, coin := range candidates {
+			candidateSymbols = append(candidateSymbols, coin.Symbol)
+		}
+		ctx.OrderBookMap = engine.FetchOrderBookBatch(candidateSymbols)
+	}
+	if config.Indicators.EnableOIRanking {
+		ctx.OIRankingData = engine.FetchOIRankingData()
+	}
+	if config.Indicators.EnableNetFlowRanking {
+		ctx.NetFlowRankingData = engine.FetchNetFlowRankingData()
+	}
+	if config.Indicators.EnablePriceRanking {
+		ctx.PriceRankingData = engine.FetchPriceRankingData()
+	}
+	ctx.News = at.newsSnapshot(config)
+	ctx.Sentiment = nil
+	if config.Indicators.EnableSentiment && at.sentimentService != nil {
+		ctx.Sentiment = at.sentimentService.Snapshot()
+	}
+
+	return &ctx, nil
+}
+
+// recordExperimentEntries records successful open actions so trades can be attributed to the variant
+func (at *AutoTrader) recordExperimentEntries(exp *store.StrategyExperiment, variant string, actions []store.DecisionAction) {
+	for _, action := range actions {
+		if !action.Success || !strings.HasPrefix(action.Action, "open_") {
+			continue
+		}
+		side := "long"
+		if strings.HasPrefix(action.Action, "open_short") {
+			side = "short"
+		}
+		entry := &store.ExperimentEntry{
+			ExperimentID: exp.ID,
+			Variant:      variant,
+			Symbol:       action.Symbol,
+			Side:         side,
+			Time:         action.Timestamp.UnixMilli(),
+		}
+		if err := at.store.Experiment().RecordEntry(entry); err != nil {
+			logger.Infof("⚠️ [%s] Failed to record experiment entry: %v", at.name, err)
+		}
+	}
+}
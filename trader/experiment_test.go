@@ -0,0 +1,91 @@
+package trader
+
+import (
+	"nofx/kernel"
+	"nofx/store"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExperiment_AttributionAndSplitContext(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+
+	now := time.Now().UTC()
+	exp := &store.StrategyExperiment{
+		ID: "exp1", UserID: "u1", TraderID: "t1", Name: "ab",
+		StrategyAID: "sa", StrategyBID: "sb", Mode: store.ExperimentModeSplit, SplitRatio: 0.6,
+		StartedAt: now.Add(-time.Hour).UnixMilli(),
+	}
+	if err := st.Experiment().Create(exp); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := st.Experiment().Create(&store.StrategyExperiment{ID: "exp2", UserID: "u1", TraderID: "t1", Name: "dup", StrategyAID: "sa", StrategyBID: "sb"}); err == nil {
+		t.Fatal("expected second running experiment on same trader to be rejected")
+	}
+
+	at := &AutoTrader{id: "t1", name: "t1", store: st}
+	at.recordExperimentEntries(exp, store.ExperimentVariantA, []store.DecisionAction{
+		{Action: "open_long", Symbol: "BTCUSDT", Success: true, Timestamp: now.Add(-50 * time.Minute)},
+		{Action: "open_short", Symbol: "SOLUSDT", Success: false, Timestamp: now.Add(-50 * time.Minute)},
+	})
+	at.recordExperimentEntries(exp, store.ExperimentVariantB, []store.DecisionAction{
+		{Action: "open_short_limit", Symbol: "ETHUSDT", Success: true, Timestamp: now.Add(-40 * time.Minute)},
+		{Action: "close_long", Symbol: "BTCUSDT", Success: true, Timestamp: now.Add(-30 * time.Minute)},
+	})
+
+	positions := []*store.TraderPosition{
+		{TraderID: "t1", Symbol: "BTCUSDT", Side: "LONG", Quantity: 1, EntryPrice: 1, EntryTime: now.Add(-50 * time.Minute).UnixMilli(), RealizedPnL: 12, Fee: 2, Status: "CLOSED"},
+		{TraderID: "t1", Symbol: "ETHUSDT", Side: "SHORT", Quantity: 1, EntryPrice: 1, EntryTime: now.Add(-20 * time.Minute).UnixMilli(), RealizedPnL: -5, Fee: 1, Status: "CLOSED"},
+		{TraderID: "t1", Symbol: "ETHUSDT", Side: "SHORT", Quantity: 1, EntryPrice: 1, EntryTime: now.Add(-10 * time.Minute).UnixMilli(), Status: "OPEN"},
+		// Opened before the experiment: not attributed
+		{TraderID: "t1", Symbol: "BTCUSDT", Side: "LONG", Quantity: 1, EntryPrice: 1, EntryTime: now.Add(-2 * time.Hour).UnixMilli(), RealizedPnL: 100, Status: "CLOSED"},
+	}
+	for _, pos := range positions {
+		if err := st.GormDB().Create(pos).Error; err != nil {
+			t.Fatalf("create position: %v", err)
+		}
+	}
+
+	stats, err := st.Experiment().GetVariantStats(exp, 1000)
+	if err != nil {
+		t.Fatalf("GetVariantStats: %v", err)
+	}
+	a, b := stats[0], stats[1]
+	if a.Entries != 1 || a.ClosedTrades != 1 || a.NetPnL != 10 || a.ReturnPct != 10.0/600*100 {
+		t.Errorf("unexpected variant A stats: %+v", a)
+	}
+	if b.Entries != 1 || b.ClosedTrades != 1 || b.OpenPositions != 1 || b.NetPnL != -6 || b.LossTrades != 1 {
+		t.Errorf("unexpected variant B stats: %+v", b)
+	}
+
+	// Split mode: variant B sees its capital share and only its own positions
+	entries, _ := st.Experiment().ListEntries(exp.ID)
+	engine := kernel.NewStrategyEngine(&store.StrategyConfig{
+		CoinSource: store.CoinSourceConfig{SourceType: "static", StaticCoins: []string{"BTCUSDT"}},
+	})
+	base := &kernel.Context{
+		Account: kernel.AccountInfo{TotalEquity: 1000, AvailableBalance: 800, PositionCount: 2},
+		Positions: []kernel.PositionInfo{
+			{Symbol: "BTCUSDT", Side: "long", MarginUsed: 50, UpdateTime: now.Add(-50 * time.Minute).UnixMilli()},
+			{Symbol: "ETHUSDT", Side: "short", MarginUsed: 30, UpdateTime: now.Add(-10 * time.Minute).UnixMilli()},
+		},
+	}
+	ctx, err := at.experimentContext(base, engine, exp, store.ExperimentVariantB, entries)
+	if err != nil {
+		t.Fatalf("experimentContext: %v", err)
+	}
+	if ctx.Account.TotalEquity != 400 || ctx.Account.AvailableBalance != 320 {
+		t.Errorf("unexpected split account: %+v", ctx.Account)
+	}
+	if len(ctx.Positions) != 1 || ctx.Positions[0].Symbol != "ETHUSDT" || ctx.Account.MarginUsed != 30 {
+		t.Errorf("variant B should only see ETHUSDT position, got %+v", ctx.Positions)
+	}
+	if base.Account.TotalEquity != 1000 || len(base.Positions) != 2 {
+		t.Error("base context must not be modified")
+	}
+}
@@ -0,0 +1,101 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"time"
+)
+
+// positionFunding the (Binance reference) funding rate and next settlement of a position's symbol, zero when unknown
+func positionFunding(symbol string) (float64, int64) {
+	if market.IsXyzDexAsset(symbol) {
+		return 0, 0
+	}
+	rate, next, err := market.GetFundingRate(market.Normalize(symbol))
+	if err != nil || next.IsZero() {
+		return 0, 0
+	}
+	return rate, next.UnixMilli()
+}
+
+// handleFundingExposure closes positions that would pay a funding rate above the strategy's limit at a settlement
+// that is about to happen (and drops them from ctx, so the AI only sees what is left). The decision record notes it.
+func (at *AutoTrader) handleFundingExposure(ctx *kernel.Context, record *store.DecisionRecord) {
+	if at.config.StrategyConfig == nil || at.config.StrategyConfig.RiskControl.AvoidFundingRatePct <= 0 {
+		return
+	}
+	rc := at.config.StrategyConfig.RiskControl
+	now := time.Now()
+
+	remaining := ctx.Positions[:0]
+	for _, pos := range ctx.Positions {
+		until, due := kernel.FundingAvoidanceDue(rc, pos.Side, pos.FundingRate, pos.NextFundingTime, now)
+		if !due {
+			remaining = append(remaining, pos)
+			continue
+		}
+		payment := kernel.ProjectedFundingPayment(pos.Side, pos.Quantity, pos.MarkPrice, pos.FundingRate)
+		reason := fmt.Sprintf("funding rate %.4f%% (est. %+.2f USDT) due in %dm, limit %g%%",
+			pos.FundingRate*100, payment, int(until.Minutes()), rc.AvoidFundingRatePct)
+
+		logger.Infof("💸 [%s] Funding guard: closing %s %s (%s)", at.name, pos.Symbol, pos.Side, reason)
+		action := store.DecisionAction{
+			Action:    "close_" + pos.Side,
+			Symbol:    pos.Symbol,
+			Quantity:  pos.Quantity,
+			Leverage:  pos.Leverage,
+			Price:     pos.MarkPrice,
+			Reasoning: "Funding guard: " + reason,
+			Timestamp: now.UTC(),
+		}
+		if err := at.emergencyClosePosition(pos.Symbol, pos.Side); err != nil {
+			action.Error = err.Error()
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("❌ Funding guard: failed to close %s %s: %v", pos.Symbol, pos.Side, err))
+			record.Decisions = append(record.Decisions, action)
+			remaining = append(remaining, pos)
+			continue
+		}
+		action.Success = true
+		record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("💸 Funding guard: closed %s %s (%s)", pos.Symbol, pos.Side, reason))
+		record.Decisions = append(record.Decisions, action)
+		at.untrackPosition(pos.Symbol, pos.Side)
+		at.recordPositionClose(trackedPosition{Symbol: pos.Symbol, Side: pos.Side, EntryPrice: pos.EntryPrice, Quantity: pos.Quantity}, pos.MarkPrice, "funding_guard")
+		at.ClearPeakPnLCache(pos.Symbol, pos.Side)
+	}
+	ctx.Positions = remaining
+	ctx.Account.PositionCount = len(remaining)
+}
+
+// blockEntriesBeforeFunding vetoes opening decisions that would pay a funding rate above the strategy's limit
+// at a settlement inside the avoidance window, they would be closed again by handleFundingExposure
+func (at *AutoTrader) blockEntriesBeforeFunding(decisions []kernel.Decision, marketData map[string]*market.Data, rc store.RiskControlConfig, record *store.DecisionRecord) []kernel.Decision {
+	if rc.AvoidFundingRatePct <= 0 {
+		return decisions
+	}
+	now := time.Now()
+	kept := make([]kernel.Decision, 0, len(decisions))
+	for _, d := range decisions {
+		data, ok := marketData[d.Symbol]
+		if !d.IsOpen() || !ok || data.NextFundingTime.IsZero() {
+			kept = append(kept, d)
+			continue
+		}
+		side := "short"
+		if d.IsLong() {
+			side = "long"
+		}
+		until, due := kernel.FundingAvoidanceDue(rc, side, data.FundingRate, data.NextFundingTime.UnixMilli(), now)
+		if !due {
+			kept = append(kept, d)
+			continue
+		}
+		note := fmt.Sprintf("%s %s vetoed: pays funding rate %.4f%% in %dm, limit %g%%",
+			d.Symbol, d.Action, data.FundingRate*100, int(until.Minutes()), rc.AvoidFundingRatePct)
+		logger.Infof("💸 [%s] %s", at.name, note)
+		record.ExecutionLog = append(record.ExecutionLog, "💸 "+note)
+	}
+	return kept
+}
@@ -0,0 +1,57 @@
+package trader
+
+import (
+	"nofx/kernel"
+	"nofx/market"
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+func TestHandleFundingExposure(t *testing.T) {
+	exchange := &stubLadderTrader{}
+	at := &AutoTrader{name: "test", trader: exchange}
+	at.config.StrategyConfig = &store.StrategyConfig{RiskControl: store.RiskControlConfig{AvoidFundingRatePct: 0.05}}
+
+	soon := time.Now().Add(10 * time.Minute).UnixMilli()
+	ctx := &kernel.Context{Positions: []kernel.PositionInfo{
+		// Longs pay a positive rate: 0.1% > 0.05% limit, settlement inside the default 30m window
+		{Symbol: "BTCUSDT", Side: "long", Quantity: 2, MarkPrice: 100, FundingRate: 0.001, NextFundingTime: soon},
+		// Shorts receive a positive rate
+		{Symbol: "ETHUSDT", Side: "short", Quantity: 1, MarkPrice: 10, FundingRate: 0.001, NextFundingTime: soon},
+		// Pays over the limit, but settlement is hours away
+		{Symbol: "SOLUSDT", Side: "long", Quantity: 1, MarkPrice: 10, FundingRate: 0.001, NextFundingTime: time.Now().Add(3 * time.Hour).UnixMilli()},
+	}}
+	record := &store.DecisionRecord{}
+	at.handleFundingExposure(ctx, record)
+
+	if len(exchange.closes) != 1 || len(ctx.Positions) != 2 || ctx.Positions[0].Symbol != "ETHUSDT" || ctx.Account.PositionCount != 2 {
+		t.Fatalf("closes %v, positions %+v", exchange.closes, ctx.Positions)
+	}
+	if len(record.Decisions) != 1 || record.Decisions[0].Action != "close_long" || !record.Decisions[0].Success {
+		t.Errorf("decision record = %+v", record.Decisions)
+	}
+	if got := kernel.ProjectedFundingPayment("long", 2, 100, 0.001); got != -0.2 {
+		t.Errorf("long projected payment = %v, want -0.2", got)
+	}
+}
+
+func TestBlockEntriesBeforeFunding(t *testing.T) {
+	at := &AutoTrader{name: "test"}
+	rc := store.RiskControlConfig{AvoidFundingRatePct: 0.05, AvoidFundingWindowMins: 20}
+	marketData := map[string]*market.Data{
+		"BTCUSDT": {Symbol: "BTCUSDT", FundingRate: -0.002, NextFundingTime: time.Now().Add(15 * time.Minute)},
+		"ETHUSDT": {Symbol: "ETHUSDT", FundingRate: -0.002, NextFundingTime: time.Now().Add(25 * time.Minute)},
+	}
+	decisions := []kernel.Decision{
+		{Symbol: "BTCUSDT", Action: "open_short"},      // Shorts pay a negative rate: vetoed
+		{Symbol: "BTCUSDT", Action: "open_long_limit"}, // Longs receive it
+		{Symbol: "ETHUSDT", Action: "open_short"},      // Outside the 20m window
+		{Symbol: "BTCUSDT", Action: "close_long"},
+	}
+	record := &store.DecisionRecord{}
+	kept := at.blockEntriesBeforeFunding(decisions, marketData, rc, record)
+	if len(kept) != 3 || kept[0].Action != "open_long_limit" || len(record.ExecutionLog) != 1 {
+		t.Errorf("kept %+v, log %v", kept, record.ExecutionLog)
+	}
+}
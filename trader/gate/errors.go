@@ -0,0 +1,42 @@
+package gate
+
+import (
+	"errors"
+	"nofx/trader/types"
+
+	"github.com/gateio/gateapi-go/v6"
+)
+
+// gateErrorClasses Gate API error labels by class
+var gateErrorClasses = map[string]types.ErrorClass{
+	"TOO_MANY_REQUESTS": types.ErrClassRateLimited,
+
+	"INVALID_KEY":             types.ErrClassAuth,
+	"INVALID_SIGNATURE":       types.ErrClassAuth,
+	"INVALID_CREDENTIALS":     types.ErrClassAuth,
+	"MISSING_REQUIRED_HEADER": types.ErrClassAuth,
+	"FORBIDDEN":               types.ErrClassAuth,
+	"READ_ONLY":               types.ErrClassAuth,
+	"IP_FORBIDDEN":            types.ErrClassAuth,
+
+	"INSUFFICIENT_AVAILABLE": types.ErrClassInsufficientMargin,
+	"BALANCE_NOT_ENOUGH":     types.ErrClassInsufficientMargin,
+
+	"CONTRACT_NOT_FOUND":    types.ErrClassInvalidSymbol,
+	"INVALID_CURRENCY_PAIR": types.ErrClassInvalidSymbol,
+
+	"SERVER_ERROR":    types.ErrClassNetwork,
+	"TOO_BUSY":        types.ErrClassNetwork,
+	"REQUEST_EXPIRED": types.ErrClassNetwork,
+}
+
+// ClassifyError classifies a Gate error by API error label, falling back to common messages
+func (t *GateTrader) ClassifyError(err error) types.ErrorClass {
+	var apiErr gateapi.GateAPIError
+	if errors.As(err, &apiErr) {
+		if class, ok := gateErrorClasses[apiErr.Label]; ok {
+			return class
+		}
+	}
+	return types.ClassifyError(err)
+}
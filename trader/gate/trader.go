@@ -150,7 +150,7 @@ func (t *GateTrader) GetPositions() ([]map[string]interface{}, error) {
 		}
 
 		result = append(result, map[string]interface{}{
-			"symbol":           pos.Contract,
+			"symbol":           t.revertSymbol(pos.Contract),
 			"positionAmt":      positionAmt,
 			"entryPrice":       entryPrice,
 			"markPrice":        markPrice,
@@ -244,6 +244,11 @@ func (t *GateTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 
 // OpenLong opens a long position
 func (t *GateTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenLongWithClientID(symbol, quantity, leverage, types.ClientOrderKey{})
+}
+
+// OpenLongWithClientID opens a long position tagged with a deterministic order text
+func (t *GateTrader) OpenLongWithClientID(symbol string, quantity float64, leverage int, key types.ClientOrderKey) (map[string]interface{}, error) {
 	symbol = t.convertSymbol(symbol)
 
 	// Cancel old orders first
@@ -273,7 +278,7 @@ func (t *GateTrader) OpenLong(symbol string, quantity float64, leverage int) (ma
 		Size:     size, // Positive for long
 		Price:    "0",  // Market order
 		Tif:      "ioc",
-		Text:     "t-nofx",
+		Text:     gateOrderText(key),
 	}
 
 	logger.Infof("  [Gate] OpenLong: symbol=%s, size=%d, leverage=%d", symbol, size, leverage)
@@ -302,6 +307,11 @@ func (t *GateTrader) OpenLong(symbol string, quantity float64, leverage int) (ma
 
 // OpenShort opens a short position
 func (t *GateTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenShortWithClientID(symbol, quantity, leverage, types.ClientOrderKey{})
+}
+
+// OpenShortWithClientID opens a short position tagged with a deterministic order text
+func (t *GateTrader) OpenShortWithClientID(symbol string, quantity float64, leverage int, key types.ClientOrderKey) (map[string]interface{}, error) {
 	symbol = t.convertSymbol(symbol)
 
 	// Cancel old orders first
@@ -330,7 +340,7 @@ func (t *GateTrader) OpenShort(symbol string, quantity float64, leverage int) (m
 		Size:     -size, // Negative for short
 		Price:    "0",   // Market order
 		Tif:      "ioc",
-		Text:     "t-nofx",
+		Text:     gateOrderText(key),
 	}
 
 	logger.Infof("  [Gate] OpenShort: symbol=%s, size=%d, leverage=%d", symbol, -size, leverage)
@@ -357,6 +367,46 @@ func (t *GateTrader) OpenShort(symbol string, quantity float64, leverage int) (m
 	}, nil
 }
 
+// gateOrderText Order text for open orders, deterministic for an idempotent submission
+// Gate allows custom text of at most 28 bytes after the "t-" prefix
+func gateOrderText(key types.ClientOrderKey) string {
+	if key.IsZero() {
+		return "t-nofx"
+	}
+	return "t-nofx" + key.ID()
+}
+
+// FindOrderByClientID looks up an order submitted with the given client order key
+// Gate only resolves custom text for orders still in the order book, so recent finished orders are scanned
+func (t *GateTrader) FindOrderByClientID(symbol string, key types.ClientOrderKey) (map[string]interface{}, error) {
+	symbol = t.convertSymbol(symbol)
+	text := gateOrderText(key)
+
+	for _, status := range []string{"open", "finished"} {
+		opts := &gateapi.ListFuturesOrdersOpts{
+			Contract: optional.NewString(symbol),
+			Limit:    optional.NewInt32(100),
+		}
+		orders, _, err := t.client.FuturesApi.ListFuturesOrders(t.ctx, "usdt", status, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query order by client ID: %w", err)
+		}
+		for _, order := range orders {
+			if order.Text != text {
+				continue
+			}
+			return map[string]interface{}{
+				"orderId":       fmt.Sprintf("%d", order.Id),
+				"symbol":        t.revertSymbol(symbol),
+				"status":        order.Status,
+				"clientOrderId": text,
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
+
 // CloseLong closes a long position
 func (t *GateTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
 	symbol = t.convertSymbol(symbol)
@@ -684,18 +734,16 @@ func (t *GateTrader) CancelStopOrders(symbol string) error {
 }
 
 // FormatQuantity formats quantity to correct precision
+// The quantity stays in base currency, rounded down to whole contracts (quanto_multiplier)
 func (t *GateTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
 	contract, err := t.getContract(symbol)
 	if err != nil {
 		return fmt.Sprintf("%.4f", quantity), nil
 	}
 
-	// Gate uses quanto_multiplier for contract size
 	quantoMultiplier, _ := strconv.ParseFloat(contract.QuantoMultiplier, 64)
 	if quantoMultiplier > 0 {
-		// Calculate number of contracts
-		numContracts := quantity / quantoMultiplier
-		return fmt.Sprintf("%.0f", math.Floor(numContracts)), nil
+		return types.SymbolFilters{Symbol: symbol, StepSize: quantoMultiplier}.FormatQuantity(quantity), nil
 	}
 
 	return fmt.Sprintf("%.4f", quantity), nil
@@ -799,6 +847,52 @@ func (t *GateTrader) GetClosedPnL(startTime time.Time, limit int) ([]types.Close
 	return records, nil
 }
 
+// GetFundingHistory retrieves funding payments from the Gate futures account book
+func (t *GateTrader) GetFundingHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	return t.getAccountBook("fund", types.IncomeTypeFunding, startTime, limit)
+}
+
+// GetCommissionHistory retrieves trading fees from the Gate futures account book
+func (t *GateTrader) GetCommissionHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	return t.getAccountBook("fee", types.IncomeTypeCommission, startTime, limit)
+}
+
+// getAccountBook queries USDT futures account changes of one type (change is the signed balance change)
+func (t *GateTrader) getAccountBook(bookType, recordType string, startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 1000
+	}
+	opts := &gateapi.ListFuturesAccountBookOpts{
+		Limit: optional.NewInt32(int32(limit)),
+		Type_: optional.NewString(bookType),
+	}
+	if !startTime.IsZero() {
+		opts.From = optional.NewInt64(startTime.Unix())
+	}
+
+	entries, _, err := t.client.FuturesApi.ListFuturesAccountBook(t.ctx, "usdt", opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account book: %w", err)
+	}
+
+	records := make([]types.IncomeRecord, 0, len(entries))
+	for _, entry := range entries {
+		amount, _ := strconv.ParseFloat(entry.Change, 64)
+		if amount == 0 {
+			continue
+		}
+		records = append(records, types.IncomeRecord{
+			TranID: entry.Id,
+			Symbol: t.revertSymbol(entry.Contract),
+			Type:   recordType,
+			Amount: amount,
+			Asset:  "USDT",
+			Time:   time.UnixMilli(int64(entry.Time * 1000)).UTC(),
+		})
+	}
+	return records, nil
+}
+
 // GetOpenOrders gets open/pending orders
 func (t *GateTrader) GetOpenOrders(symbol string) ([]types.OpenOrder, error) {
 	symbol = t.convertSymbol(symbol)
@@ -22,12 +22,14 @@ import (
 type GateTraderTestSuite struct {
 	*testutil.TraderTestSuite
 	mockServer *httptest.Server
+	counter    *testutil.RequestCounter
 }
 
 // NewGateTraderTestSuite creates Gate test suite with mock server
 func NewGateTraderTestSuite(t *testing.T) *GateTraderTestSuite {
-	// Create mock HTTP server
-	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	// Create mock HTTP server (wrapped with request counter for conformance checks)
+	counter := testutil.NewRequestCounter()
+	mockServer := httptest.NewServer(counter.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		path := r.URL.Path
 		var respBody interface{}
 
@@ -42,7 +44,7 @@ func NewGateTraderTestSuite(t *testing.T) *GateTraderTestSuite {
 			}
 
 		// Mock GetPositions - /api/v4/futures/usdt/positions
-		case strings.Contains(path, "/futures/usdt/positions"):
+		case strings.HasSuffix(path, "/futures/usdt/positions"):
 			respBody = []map[string]interface{}{
 				{
 					"contract":       "BTC_USDT",
@@ -84,10 +86,18 @@ func NewGateTraderTestSuite(t *testing.T) *GateTraderTestSuite {
 			if contract == "" {
 				contract = "BTC_USDT"
 			}
-			price := "50000.00"
-			if contract == "ETH_USDT" {
+			price := ""
+			switch contract {
+			case "BTC_USDT":
+				price = "50000.00"
+			case "ETH_USDT":
 				price = "3000.00"
 			}
+			if price == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				respBody = map[string]interface{}{"label": "CONTRACT_NOT_FOUND", "message": "contract not found"}
+				break
+			}
 			respBody = []map[string]interface{}{
 				{
 					"contract": contract,
@@ -128,7 +138,7 @@ func NewGateTraderTestSuite(t *testing.T) *GateTraderTestSuite {
 		// Mock UpdatePositionLeverage
 		case strings.Contains(path, "/futures/usdt/positions/") && strings.Contains(path, "/leverage"):
 			respBody = map[string]interface{}{
-				"leverage": 10,
+				"leverage": "10",
 			}
 
 		// Mock ListPriceTriggeredOrders
@@ -139,6 +149,16 @@ func NewGateTraderTestSuite(t *testing.T) *GateTraderTestSuite {
 		case strings.Contains(path, "/futures/usdt/position_close"):
 			respBody = []map[string]interface{}{}
 
+		// Mock ListFuturesAccountBook - funding entries of type=fund
+		case strings.Contains(path, "/futures/usdt/account_book"):
+			respBody = []map[string]interface{}{}
+			if r.URL.Query().Get("type") == "fund" {
+				respBody = []map[string]interface{}{
+					{"id": "41", "time": 1700000000.5, "change": "-0.25", "type": "fund", "contract": "BTC_USDT"},
+					{"id": "42", "time": 1700028800.0, "change": "0", "type": "fund", "contract": "ETH_USDT"},
+				}
+			}
+
 		// Default: empty response
 		default:
 			respBody = map[string]interface{}{}
@@ -146,10 +166,11 @@ func NewGateTraderTestSuite(t *testing.T) *GateTraderTestSuite {
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(respBody)
-	}))
+	})))
 
-	// Create trader instance (will need to override URL in actual usage)
+	// Create trader instance and route its API client to the mock server
 	traderInstance := NewGateTrader("test_api_key", "test_secret_key")
+	traderInstance.client.GetConfig().BasePath = mockServer.URL + "/api/v4"
 
 	// Create base suite
 	baseSuite := testutil.NewTraderTestSuite(t, traderInstance)
@@ -157,6 +178,7 @@ func NewGateTraderTestSuite(t *testing.T) *GateTraderTestSuite {
 	return &GateTraderTestSuite{
 		TraderTestSuite: baseSuite,
 		mockServer:      mockServer,
+		counter:         counter,
 	}
 }
 
@@ -175,6 +197,35 @@ func (s *GateTraderTestSuite) Cleanup() {
 // TestGateTrader_InterfaceCompliance tests interface compliance
 func TestGateTrader_InterfaceCompliance(t *testing.T) {
 	var _ types.Trader = (*GateTrader)(nil)
+	var _ types.IncomeTrader = (*GateTrader)(nil)
+}
+
+// TestGateTrader_GetFundingHistory funding comes from the account book, zero changes are skipped
+func TestGateTrader_GetFundingHistory(t *testing.T) {
+	suite := NewGateTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	records, err := suite.Trader.(*GateTrader).GetFundingHistory(time.Unix(1700000000, 0), 0)
+	if err != nil {
+		t.Fatalf("GetFundingHistory: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %+v", records)
+	}
+	r := records[0]
+	if r.TranID != "41" || r.Symbol != "BTCUSDT" || r.Amount != -0.25 || r.Type != types.IncomeTypeFunding || r.Time.UnixMilli() != 1700000000500 {
+		t.Errorf("unexpected record: %+v", r)
+	}
+}
+
+// TestGateTrader_Conformance runs the Trader contract conformance suite
+func TestGateTrader_Conformance(t *testing.T) {
+	suite := NewGateTraderTestSuite(t)
+	defer suite.Cleanup()
+
+	testutil.NewConformanceSuite(t, suite.Trader, testutil.ConformanceOptions{
+		PositionFetches: suite.counter.CountPath("/api/v4/futures/usdt/positions"),
+	}).RunConformance()
 }
 
 // ============================================================
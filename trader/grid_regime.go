@@ -7,31 +7,9 @@ import (
 )
 
 // ============================================================================
-// Task 6: Regime Level Classification
+// Task 6: Regime Level Limits
 // ============================================================================
-
-// classifyRegimeLevel determines the regime level based on market indicators
-// bollingerWidth: Bollinger band width as percentage
-// atr14Pct: ATR14 as percentage of current price
-func classifyRegimeLevel(bollingerWidth, atr14Pct float64) market.RegimeLevel {
-	// Narrow: Bollinger < 2%, ATR < 1%
-	if bollingerWidth < 2.0 && atr14Pct < 1.0 {
-		return market.RegimeLevelNarrow
-	}
-
-	// Standard: Bollinger 2-3%, ATR 1-2%
-	if bollingerWidth <= 3.0 && atr14Pct <= 2.0 {
-		return market.RegimeLevelStandard
-	}
-
-	// Wide: Bollinger 3-4%, ATR 2-3%
-	if bollingerWidth <= 4.0 && atr14Pct <= 3.0 {
-		return market.RegimeLevelWide
-	}
-
-	// Volatile: Bollinger > 4%, ATR > 3%
-	return market.RegimeLevelVolatile
-}
+// Regime levels are classified by market.ClassifyRegimeLevel
 
 // getRegimeLeverageLimit returns the effective leverage limit for a regime level
 func getRegimeLeverageLimit(level market.RegimeLevel, config *store.GridConfigModel) int {
@@ -89,46 +67,6 @@ func getRegimePositionLimit(level market.RegimeLevel, config *store.GridConfigMo
 	}
 }
 
-// ============================================================================
-// Task 7: Breakout Detection
-// ============================================================================
-
-// detectBoxBreakout checks if price has broken out of any box level
-// Returns the highest breakout level and direction
-func detectBoxBreakout(box *market.BoxData) (market.BreakoutLevel, string) {
-	if box == nil {
-		return market.BreakoutNone, ""
-	}
-
-	price := box.CurrentPrice
-
-	// Check long box first (highest priority)
-	if price > box.LongUpper {
-		return market.BreakoutLong, "up"
-	}
-	if price < box.LongLower {
-		return market.BreakoutLong, "down"
-	}
-
-	// Check mid box
-	if price > box.MidUpper {
-		return market.BreakoutMid, "up"
-	}
-	if price < box.MidLower {
-		return market.BreakoutMid, "down"
-	}
-
-	// Check short box
-	if price > box.ShortUpper {
-		return market.BreakoutShort, "up"
-	}
-	if price < box.ShortLower {
-		return market.BreakoutShort, "down"
-	}
-
-	return market.BreakoutNone, ""
-}
-
 // ============================================================================
 // Task 8: Breakout Confirmation Logic
 // ============================================================================
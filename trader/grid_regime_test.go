@@ -5,68 +5,6 @@ import (
 	"testing"
 )
 
-func TestClassifyRegimeLevel(t *testing.T) {
-	tests := []struct {
-		name           string
-		bollingerWidth float64
-		atr14Pct       float64
-		expected       market.RegimeLevel
-	}{
-		{"narrow", 1.5, 0.8, market.RegimeLevelNarrow},
-		{"standard", 2.5, 1.5, market.RegimeLevelStandard},
-		{"wide", 3.5, 2.5, market.RegimeLevelWide},
-		{"volatile", 5.0, 4.0, market.RegimeLevelVolatile},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := classifyRegimeLevel(tt.bollingerWidth, tt.atr14Pct)
-			if result != tt.expected {
-				t.Errorf("Expected %v, got %v", tt.expected, result)
-			}
-		})
-	}
-}
-
-func TestDetectBoxBreakout(t *testing.T) {
-	box := &market.BoxData{
-		ShortUpper:   100,
-		ShortLower:   90,
-		MidUpper:     105,
-		MidLower:     85,
-		LongUpper:    110,
-		LongLower:    80,
-		CurrentPrice: 95,
-	}
-
-	// No breakout
-	level, direction := detectBoxBreakout(box)
-	if level != market.BreakoutNone {
-		t.Errorf("Expected no breakout, got %v", level)
-	}
-
-	// Short breakout up
-	box.CurrentPrice = 101
-	level, direction = detectBoxBreakout(box)
-	if level != market.BreakoutShort || direction != "up" {
-		t.Errorf("Expected short breakout up, got %v %v", level, direction)
-	}
-
-	// Mid breakout down
-	box.CurrentPrice = 84
-	level, direction = detectBoxBreakout(box)
-	if level != market.BreakoutMid || direction != "down" {
-		t.Errorf("Expected mid breakout down, got %v %v", level, direction)
-	}
-
-	// Long breakout up
-	box.CurrentPrice = 112
-	level, direction = detectBoxBreakout(box)
-	if level != market.BreakoutLong || direction != "up" {
-		t.Errorf("Expected long breakout up, got %v %v", level, direction)
-	}
-}
-
 func TestBreakoutConfirmation(t *testing.T) {
 	state := &BreakoutState{
 		Level:        market.BreakoutNone,
@@ -0,0 +1,302 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/logger"
+)
+
+// ============================================================================
+// Spot Grid
+// ============================================================================
+// A spot grid buys and sells the coin itself: buy levels spend the quote
+// currency, sell levels sell base currency held in the spot wallet, there is
+// no leverage and nothing can be shorted. Orders are checked against the free
+// spot balances before they are sent. On exit only the coins bought by the
+// grid are sold, holdings the user had before the grid started are kept.
+// ============================================================================
+
+// spotBuyFeeBuffer quote kept aside for the taker commission of a spot buy
+const spotBuyFeeBuffer = 0.001
+
+// isSpotGrid reports whether the trader runs a spot market grid
+func (at *AutoTrader) isSpotGrid() bool {
+	return at.config.StrategyConfig != nil && at.config.StrategyConfig.GridConfig != nil &&
+		at.config.StrategyConfig.GridConfig.IsSpot()
+}
+
+// spotGridTrader returns the spot interface of the exchange, error if it has no spot support
+func (at *AutoTrader) spotGridTrader() (SpotTrader, error) {
+	spot, ok := at.trader.(SpotTrader)
+	if !ok {
+		return nil, fmt.Errorf("exchange %s does not support spot grid trading", at.exchange)
+	}
+	return spot, nil
+}
+
+// gridLeverage leverage of grid orders (spot grids never borrow)
+func (at *AutoTrader) gridLeverage() int {
+	if at.isSpotGrid() {
+		return 1
+	}
+	return at.config.StrategyConfig.GridConfig.Leverage
+}
+
+// gridMarketPrice gets the grid symbol price on the market the grid trades
+func (at *AutoTrader) gridMarketPrice(symbol string) (float64, error) {
+	if !at.isSpotGrid() {
+		return at.trader.GetMarketPrice(symbol)
+	}
+	spot, err := at.spotGridTrader()
+	if err != nil {
+		return 0, err
+	}
+	return spot.GetSpotPrice(symbol)
+}
+
+// spotGridHoldings gets the spot wallet balances of the grid symbol's base and quote assets
+func (at *AutoTrader) spotGridHoldings(spot SpotTrader, symbol string) (base, quote SpotBalance, err error) {
+	baseAsset, quoteAsset, err := spot.GetSpotAssets(symbol)
+	if err != nil {
+		return base, quote, err
+	}
+	balances, err := spot.GetSpotBalances()
+	if err != nil {
+		return base, quote, err
+	}
+	base = balances[baseAsset]
+	base.Asset = baseAsset
+	quote = balances[quoteAsset]
+	quote.Asset = quoteAsset
+	return base, quote, nil
+}
+
+// gridEquity account equity used by grid risk checks
+// Spot grids value the base and quote holdings of the grid symbol in the quote currency
+func (at *AutoTrader) gridEquity() (float64, error) {
+	if !at.isSpotGrid() {
+		balance, err := at.trader.GetBalance()
+		if err != nil {
+			return 0, err
+		}
+		return equityFromBalance(balance), nil
+	}
+
+	spot, err := at.spotGridTrader()
+	if err != nil {
+		return 0, err
+	}
+	symbol := at.config.StrategyConfig.GridConfig.Symbol
+	base, quote, err := at.spotGridHoldings(spot, symbol)
+	if err != nil {
+		return 0, err
+	}
+	price, err := spot.GetSpotPrice(symbol)
+	if err != nil {
+		return 0, err
+	}
+	return quote.Total() + base.Total()*price, nil
+}
+
+// checkSpotBalance verifies the free spot balance can fund an order: quote currency for buys, coins for sells
+func checkSpotBalance(side string, price, quantity float64, base, quote SpotBalance) error {
+	if side == "BUY" {
+		cost := price * quantity * (1 + spotBuyFeeBuffer)
+		if quote.Free < cost {
+			return fmt.Errorf("insufficient %s: buy needs %.4f, free %.4f", quote.Asset, cost, quote.Free)
+		}
+		return nil
+	}
+	if base.Free < quantity {
+		return fmt.Errorf("insufficient %s: sell needs %.8f, free %.8f", base.Asset, quantity, base.Free)
+	}
+	return nil
+}
+
+// spotGridInventoryLocked coins bought by filled buy levels and not yet sold by filled sell levels
+// Caller must hold gridState.mu
+func (at *AutoTrader) spotGridInventoryLocked() float64 {
+	inventory := 0.0
+	for _, level := range at.gridState.Levels {
+		if level.State != "filled" {
+			continue
+		}
+		if level.Side == "buy" {
+			inventory += level.PositionSize
+		} else {
+			inventory -= level.PositionSize
+		}
+	}
+	if inventory < 0 {
+		return 0
+	}
+	return inventory
+}
+
+// fillSpotGridContext fills grid context account fields from the spot wallet and the grid's inventory
+func (at *AutoTrader) fillSpotGridContext(ctx *kernel.GridContext) {
+	spot, err := at.spotGridTrader()
+	if err != nil {
+		logger.Warnf("[Grid] %v", err)
+		return
+	}
+	base, quote, err := at.spotGridHoldings(spot, ctx.Symbol)
+	if err != nil {
+		logger.Warnf("[Grid] Failed to get spot balances: %v", err)
+		return
+	}
+	ctx.BaseAsset = base.Asset
+	ctx.QuoteAsset = quote.Asset
+	ctx.BaseBalance = base.Free
+	ctx.QuoteBalance = quote.Free
+	ctx.TotalEquity = quote.Total() + base.Total()*ctx.CurrentPrice
+	ctx.AvailableBalance = quote.Free
+
+	// Unsold inventory is valued against the average price of the filled buys
+	at.gridState.mu.RLock()
+	inventory := at.spotGridInventoryLocked()
+	bought, cost := 0.0, 0.0
+	for _, level := range at.gridState.Levels {
+		if level.State == "filled" && level.Side == "buy" {
+			bought += level.PositionSize
+			cost += level.PositionSize * level.PositionEntry
+		}
+	}
+	at.gridState.mu.RUnlock()
+
+	ctx.CurrentPosition = inventory
+	if inventory > 0 && bought > 0 {
+		ctx.UnrealizedPnL = inventory * (ctx.CurrentPrice - cost/bought)
+	}
+}
+
+// placeSpotGridOrder checks spot balances and places a spot grid limit order
+func (at *AutoTrader) placeSpotGridOrder(req *LimitOrderRequest) (*LimitOrderResult, error) {
+	spot, err := at.spotGridTrader()
+	if err != nil {
+		return nil, err
+	}
+	base, quote, err := at.spotGridHoldings(spot, req.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot balances: %w", err)
+	}
+	if err := checkSpotBalance(req.Side, req.Price, req.Quantity, base, quote); err != nil {
+		return nil, err
+	}
+	req.Leverage = 0
+	return spot.PlaceSpotLimitOrder(req)
+}
+
+// sellSpotGridInventory market sells the coins the grid bought (capped by the free balance)
+func (at *AutoTrader) sellSpotGridInventory() error {
+	spot, err := at.spotGridTrader()
+	if err != nil {
+		return err
+	}
+	symbol := at.config.StrategyConfig.GridConfig.Symbol
+
+	at.gridState.mu.RLock()
+	inventory := at.spotGridInventoryLocked()
+	at.gridState.mu.RUnlock()
+	if inventory <= 0 {
+		return nil
+	}
+
+	base, _, err := at.spotGridHoldings(spot, symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get spot balances: %w", err)
+	}
+	quantity := inventory
+	if base.Free < quantity {
+		quantity = base.Free
+	}
+	if quantity <= 0 {
+		return nil
+	}
+	if _, err := spot.SpotMarketSell(symbol, quantity); err != nil {
+		return err
+	}
+
+	at.gridState.mu.Lock()
+	for i := range at.gridState.Levels {
+		if at.gridState.Levels[i].State == "filled" {
+			at.gridState.Levels[i].State = "empty"
+			at.gridState.Levels[i].PositionSize = 0
+			at.gridState.Levels[i].PositionEntry = 0
+		}
+	}
+	at.gridState.mu.Unlock()
+
+	logger.Infof("[Grid] Sold spot grid inventory: %.8f %s", quantity, base.Asset)
+	return nil
+}
+
+// syncSpotGridState resolves pending spot orders that left the order book from their order status
+func (at *AutoTrader) syncSpotGridState() {
+	spot, err := at.spotGridTrader()
+	if err != nil {
+		logger.Warnf("[Grid] %v", err)
+		return
+	}
+	symbol := at.config.StrategyConfig.GridConfig.Symbol
+
+	openOrders, err := spot.GetSpotOpenOrders(symbol)
+	if err != nil {
+		logger.Warnf("[Grid] Failed to get spot open orders: %v", err)
+		return
+	}
+	activeOrderIDs := make(map[string]bool, len(openOrders))
+	for _, order := range openOrders {
+		activeOrderIDs[order.OrderID] = true
+	}
+
+	at.gridState.mu.RLock()
+	var gone []string
+	for _, level := range at.gridState.Levels {
+		if level.State == "pending" && level.OrderID != "" && !activeOrderIDs[level.OrderID] {
+			gone = append(gone, level.OrderID)
+		}
+	}
+	at.gridState.mu.RUnlock()
+
+	// Query outside the lock, unknown statuses are retried next cycle
+	statuses := make(map[string]map[string]interface{}, len(gone))
+	for _, orderID := range gone {
+		status, err := spot.GetSpotOrderStatus(symbol, orderID)
+		if err != nil {
+			logger.Warnf("[Grid] Failed to get spot order %s status: %v", orderID, err)
+			continue
+		}
+		statuses[orderID] = status
+	}
+
+	at.gridState.mu.Lock()
+	for i := range at.gridState.Levels {
+		level := &at.gridState.Levels[i]
+		status, ok := statuses[level.OrderID]
+		if level.State != "pending" || !ok {
+			continue
+		}
+		executedQty, _ := status["executedQty"].(float64)
+		avgPrice, _ := status["avgPrice"].(float64)
+		if executedQty > 0 {
+			level.State = "filled"
+			level.PositionSize = executedQty
+			level.PositionEntry = level.Price
+			if avgPrice > 0 {
+				level.PositionEntry = avgPrice
+			}
+			at.gridState.TotalTrades++
+			logger.Infof("[Grid] Level %d spot %s filled: %.8f @ $%.4f", i, level.Side, executedQty, level.PositionEntry)
+		} else {
+			level.State = "empty"
+			level.OrderQuantity = 0
+			logger.Infof("[Grid] Level %d spot order %v", i, status["status"])
+		}
+		delete(at.gridState.OrderBook, level.OrderID)
+		level.OrderID = ""
+	}
+	at.gridState.mu.Unlock()
+
+	logger.Debugf("[Grid] Synced spot state: orders=%d", len(openOrders))
+}
@@ -0,0 +1,78 @@
+package trader
+
+import (
+	"nofx/kernel"
+	"nofx/store"
+	"testing"
+)
+
+// stubSpotTrader serves fixed spot balances and records placed orders; other calls would panic
+type stubSpotTrader struct {
+	SpotTrader
+	balances map[string]SpotBalance
+	placed   []*LimitOrderRequest
+}
+
+func (s *stubSpotTrader) GetSpotAssets(symbol string) (string, string, error) {
+	return "BTC", "USDT", nil
+}
+
+func (s *stubSpotTrader) GetSpotBalances() (map[string]SpotBalance, error) {
+	return s.balances, nil
+}
+
+func (s *stubSpotTrader) PlaceSpotLimitOrder(req *LimitOrderRequest) (*LimitOrderResult, error) {
+	s.placed = append(s.placed, req)
+	return &LimitOrderResult{OrderID: "s1", Symbol: req.Symbol, Side: req.Side, Price: req.Price, Quantity: req.Quantity}, nil
+}
+
+func TestCheckSpotBalance(t *testing.T) {
+	base := SpotBalance{Asset: "BTC", Free: 0.5}
+	quote := SpotBalance{Asset: "USDT", Free: 1000}
+
+	if err := checkSpotBalance("BUY", 100, 9, base, quote); err != nil {
+		t.Errorf("buy within free quote should pass: %v", err)
+	}
+	if err := checkSpotBalance("BUY", 100, 10, base, quote); err == nil {
+		t.Error("buy spending all free quote should be rejected (fee buffer)")
+	}
+	if err := checkSpotBalance("SELL", 100, 0.5, base, quote); err != nil {
+		t.Errorf("sell of held coins should pass: %v", err)
+	}
+	if err := checkSpotBalance("SELL", 100, 0.6, base, quote); err == nil {
+		t.Error("sell of more coins than held should be rejected")
+	}
+}
+
+func TestPlaceGridLimitOrder_SpotChecksBalances(t *testing.T) {
+	exchange := &stubSpotTrader{balances: map[string]SpotBalance{
+		"USDT": {Asset: "USDT", Free: 500},
+	}}
+	gridConfig := &store.GridStrategyConfig{
+		Symbol: "BTCUSDT", MarketType: store.GridMarketSpot, GridCount: 5, TotalInvestment: 1000, Leverage: 1,
+	}
+	at := &AutoTrader{
+		name:      "spot-grid",
+		trader:    exchange,
+		config:    AutoTraderConfig{StrategyConfig: &store.StrategyConfig{StrategyType: "grid_trading", GridConfig: gridConfig}},
+		gridState: NewGridState(gridConfig),
+	}
+	at.gridState.Levels = []kernel.GridLevelInfo{{Index: 0, Price: 100, Side: "buy"}, {Index: 1, Price: 110, Side: "sell"}}
+
+	if err := at.placeGridLimitOrder(&kernel.Decision{Symbol: "BTCUSDT", Price: 100, Quantity: 1, LevelIndex: 0}, "BUY"); err != nil {
+		t.Fatalf("funded spot buy should be placed: %v", err)
+	}
+	if len(exchange.placed) != 1 || exchange.placed[0].Leverage != 0 {
+		t.Fatalf("expected one spot order without leverage, got %+v", exchange.placed)
+	}
+	if at.gridState.Levels[0].State != "pending" {
+		t.Errorf("buy level should be pending, got %s", at.gridState.Levels[0].State)
+	}
+
+	if err := at.placeGridLimitOrder(&kernel.Decision{Symbol: "BTCUSDT", Price: 110, Quantity: 1, LevelIndex: 1}, "SELL"); err == nil {
+		t.Error("spot sell without BTC holdings should be rejected")
+	}
+	if len(exchange.placed) != 1 {
+		t.Errorf("rejected sell must not reach the exchange, got %d orders", len(exchange.placed))
+	}
+}
@@ -0,0 +1,28 @@
+package hyperliquid
+
+import (
+	"nofx/trader/types"
+	"strings"
+)
+
+// ClassifyError classifies a Hyperliquid error by the exchange's messages, falling back to common messages
+// Hyperliquid reports failures as plain messages without error codes.
+func (t *HyperliquidTrader) ClassifyError(err error) types.ErrorClass {
+	if err == nil {
+		return ""
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "too many cumulative requests"):
+		return types.ErrClassRateLimited
+	case strings.Contains(msg, "api wallet") && strings.Contains(msg, "does not exist"),
+		strings.Contains(msg, "must deposit before performing actions"):
+		return types.ErrClassAuth
+	case strings.Contains(msg, "insufficient margin"), strings.Contains(msg, "insufficient spot balance"):
+		return types.ErrClassInsufficientMargin
+	case strings.Contains(msg, "asset not found"), strings.Contains(msg, "coin not found"),
+		strings.Contains(msg, "invalid asset"):
+		return types.ErrClassInvalidSymbol
+	}
+	return types.ClassifyError(err)
+}
@@ -700,6 +700,11 @@ func (t *HyperliquidTrader) refreshMetaIfNeeded(coin string) error {
 
 // OpenLong opens a long position (supports both crypto and xyz dex)
 func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenLongWithClientID(symbol, quantity, leverage, types.ClientOrderKey{})
+}
+
+// OpenLongWithClientID opens a long position with a deterministic cloid (crypto assets only)
+func (t *HyperliquidTrader) OpenLongWithClientID(symbol string, quantity float64, leverage int, key types.ClientOrderKey) (map[string]interface{}, error) {
 	// First cancel all pending orders for this coin
 	if err := t.CancelAllOrders(symbol); err != nil {
 		logger.Infof("  ⚠ Failed to cancel old pending orders: %v", err)
@@ -751,7 +756,8 @@ func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage i
 					Tif: hyperliquid.TifIoc,
 				},
 			},
-			ReduceOnly: false,
+			ReduceOnly:    false,
+			ClientOrderID: hyperliquidCloid(key),
 		}
 
 		_, err = t.exchange.Order(t.ctx, order, defaultBuilder)
@@ -770,8 +776,45 @@ func (t *HyperliquidTrader) OpenLong(symbol string, quantity float64, leverage i
 	return result, nil
 }
 
+// hyperliquidCloid Deterministic cloid (16-byte hex) for an idempotent submission, nil if key is unset
+func hyperliquidCloid(key types.ClientOrderKey) *string {
+	if key.IsZero() {
+		return nil
+	}
+	cloid := "0x" + key.Hex(32)
+	return &cloid
+}
+
+// FindOrderByClientID looks up an order submitted with the given client order key
+func (t *HyperliquidTrader) FindOrderByClientID(symbol string, key types.ClientOrderKey) (map[string]interface{}, error) {
+	cloid := hyperliquidCloid(key)
+	if cloid == nil {
+		return nil, nil
+	}
+
+	result, err := t.exchange.Info().QueryOrderByCloid(t.ctx, t.walletAddr, *cloid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order by client ID: %w", err)
+	}
+	if result.Status != hyperliquid.OrderQueryStatusSuccess {
+		return nil, nil
+	}
+
+	return map[string]interface{}{
+		"orderId":       result.Order.Order.Oid,
+		"symbol":        symbol,
+		"status":        string(result.Order.Status),
+		"clientOrderId": *cloid,
+	}, nil
+}
+
 // OpenShort opens a short position (supports both crypto and xyz dex)
 func (t *HyperliquidTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenShortWithClientID(symbol, quantity, leverage, types.ClientOrderKey{})
+}
+
+// OpenShortWithClientID opens a short position with a deterministic cloid (crypto assets only)
+func (t *HyperliquidTrader) OpenShortWithClientID(symbol string, quantity float64, leverage int, key types.ClientOrderKey) (map[string]interface{}, error) {
 	// First cancel all pending orders for this coin
 	if err := t.CancelAllOrders(symbol); err != nil {
 		logger.Infof("  ⚠ Failed to cancel old pending orders: %v", err)
@@ -823,7 +866,8 @@ func (t *HyperliquidTrader) OpenShort(symbol string, quantity float64, leverage
 					Tif: hyperliquid.TifIoc,
 				},
 			},
-			ReduceOnly: false,
+			ReduceOnly:    false,
+			ClientOrderID: hyperliquidCloid(key),
 		}
 
 		_, err = t.exchange.Order(t.ctx, order, defaultBuilder)
@@ -2073,6 +2117,56 @@ func (t *HyperliquidTrader) GetTrades(startTime time.Time, limit int) ([]types.T
 	return trades, nil
 }
 
+// GetFundingHistory retrieves funding payments from Hyperliquid (settled hourly in USDC)
+func (t *HyperliquidTrader) GetFundingHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	history, err := t.exchange.Info().UserFundingHistory(t.ctx, t.walletAddr, startTime.UnixMilli(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user funding: %w", err)
+	}
+
+	records := make([]types.IncomeRecord, 0, len(history))
+	for _, h := range history {
+		amount, _ := strconv.ParseFloat(h.Delta.USDC, 64)
+		records = append(records, types.IncomeRecord{
+			// Funding hashes are zero, so time+coin identifies the payment
+			TranID: fmt.Sprintf("%d_%s", h.Time, h.Delta.Coin),
+			Symbol: h.Delta.Coin,
+			Type:   types.IncomeTypeFunding,
+			Amount: amount,
+			Asset:  "USDC",
+			Time:   time.UnixMilli(h.Time).UTC(),
+		})
+		if limit > 0 && len(records) >= limit {
+			break
+		}
+	}
+	return records, nil
+}
+
+// GetCommissionHistory retrieves trading fees from Hyperliquid fills
+func (t *HyperliquidTrader) GetCommissionHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	trades, err := t.GetTrades(startTime, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]types.IncomeRecord, 0, len(trades))
+	for _, trade := range trades {
+		if trade.Fee == 0 {
+			continue
+		}
+		records = append(records, types.IncomeRecord{
+			TranID: trade.TradeID,
+			Symbol: trade.Symbol,
+			Type:   types.IncomeTypeCommission,
+			Amount: -trade.Fee, // Fill fee is positive when paid
+			Asset:  "USDC",
+			Time:   trade.Time,
+		})
+	}
+	return records, nil
+}
+
 // defaultBuilder is the builder info for order routing
 // Set to nil to avoid requiring builder fee approval
 //
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/crypto"
@@ -25,6 +26,7 @@ type HyperliquidTestSuite struct {
 	*testutil.TraderTestSuite // Embeds base test suite
 	mockServer              *httptest.Server
 	privateKey              *ecdsa.PrivateKey
+	stateFetches            atomic.Int64 // clearinghouseState requests (positions and balance)
 }
 
 // NewHyperliquidTestSuite Create Hyperliquid test suite
@@ -35,6 +37,8 @@ func NewHyperliquidTestSuite(t *testing.T) *HyperliquidTestSuite {
 		t.Fatalf("Failed to create test private key: %v", err)
 	}
 
+	suite := &HyperliquidTestSuite{privateKey: privateKey}
+
 	// Create mock HTTP server
 	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Return different mock responses based on request path
@@ -82,6 +86,7 @@ func NewHyperliquidTestSuite(t *testing.T) *HyperliquidTestSuite {
 
 		// Mock UserState - Get user account state (for GetBalance and GetPositions)
 		case "clearinghouseState":
+			suite.stateFetches.Add(1)
 			user, _ := reqBody["user"].(string)
 
 			// Check if querying Agent wallet balance (for security check)
@@ -229,11 +234,9 @@ func NewHyperliquidTestSuite(t *testing.T) *HyperliquidTestSuite {
 	// Create base suite
 	baseSuite := testutil.NewTraderTestSuite(t, traderInstance)
 
-	return &HyperliquidTestSuite{
-		TraderTestSuite: baseSuite,
-		mockServer:      mockServer,
-		privateKey:      privateKey,
-	}
+	suite.TraderTestSuite = baseSuite
+	suite.mockServer = mockServer
+	return suite
 }
 
 // Cleanup Clean up resources
@@ -263,6 +266,17 @@ func TestHyperliquidTrader_CommonInterface(t *testing.T) {
 	suite.RunAllTests()
 }
 
+// TestHyperliquidTrader_Conformance runs the Trader contract conformance suite
+// All info requests share one path, so position fetches are counted by request type
+func TestHyperliquidTrader_Conformance(t *testing.T) {
+	suite := NewHyperliquidTestSuite(t)
+	defer suite.Cleanup()
+
+	testutil.NewConformanceSuite(t, suite.Trader, testutil.ConformanceOptions{
+		PositionFetches: func() int { return int(suite.stateFetches.Load()) },
+	}).RunConformance()
+}
+
 // ============================================================
 // Part 3: Hyperliquid-specific feature unit tests
 // ============================================================
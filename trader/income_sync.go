@@ -0,0 +1,165 @@
+package trader
+
+import (
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"time"
+)
+
+// incomeSyncInterval minimum interval between funding/commission syncs
+const incomeSyncInterval = 10 * time.Minute
+
+// incomeAttributionSlack tolerance between exchange income timestamps and the recorded position entry/exit times
+const incomeAttributionSlack = time.Minute
+
+// SyncsIncome reports whether funding and commissions are synced from the exchange (it implements IncomeTrader)
+func (at *AutoTrader) SyncsIncome() bool {
+	_, ok := at.trader.(IncomeTrader)
+	return ok
+}
+
+// syncIncomeHistory pulls funding payments and commissions from exchanges implementing IncomeTrader,
+// and deposits/withdrawals (equity adjustments excluded from PnL) from exchanges implementing TransferTrader.
+// Each sync resumes from the latest stored record; duplicates are skipped by the store.
+// Exchanges report funding and commission per account, so only those of positions this trader held are kept.
+func (at *AutoTrader) syncIncomeHistory() {
+	if at.store == nil || time.Since(at.lastIncomeSyncTime) < incomeSyncInterval {
+		return
+	}
+	fetchers := make(map[string]func(time.Time, int) ([]IncomeRecord, error))
+	if incomeTrader, ok := at.trader.(IncomeTrader); ok {
+		fetchers[store.IncomeTypeFunding] = incomeTrader.GetFundingHistory
+		fetchers[store.IncomeTypeCommission] = incomeTrader.GetCommissionHistory
+	}
+	if transferTrader, ok := at.trader.(TransferTrader); ok {
+		fetchers[store.IncomeTypeTransfer] = transferTrader.GetTransferHistory
+	}
+	if len(fetchers) == 0 {
+		return
+	}
+	at.lastIncomeSyncTime = time.Now()
+
+	for incomeType, fetch := range fetchers {
+		startTime := at.startTime
+		lastMs, err := at.store.Income().GetLastTime(at.id, incomeType)
+		if err != nil {
+			logger.Infof("⚠️ [%s] %v", at.name, err)
+			continue
+		}
+		if lastMs > 0 {
+			startTime = time.UnixMilli(lastMs)
+		}
+
+		records, err := fetch(startTime, 0)
+		if err != nil {
+			logger.Infof("⚠️ [%s] Failed to sync %s history: %v", at.name, incomeType, err)
+			continue
+		}
+		if incomeType != store.IncomeTypeTransfer {
+			records = at.ownIncome(records, startTime)
+		}
+
+		rows := make([]*store.TraderIncome, 0, len(records))
+		for _, r := range records {
+			if r.TranID == "" {
+				continue
+			}
+			rows = append(rows, &store.TraderIncome{
+				TraderID:   at.id,
+				IncomeType: incomeType,
+				TranID:     r.TranID,
+				Symbol:     r.Symbol,
+				Amount:     r.Amount,
+				Asset:      r.Asset,
+				Time:       r.Time.UTC().UnixMilli(),
+			})
+		}
+		inserted, err := at.store.Income().SaveBatch(rows)
+		if err != nil {
+			logger.Infof("⚠️ [%s] Failed to save %s history: %v", at.name, incomeType, err)
+			continue
+		}
+		if inserted > 0 {
+			logger.Infof("💸 [%s] Synced %d %s records", at.name, inserted, incomeType)
+		}
+		if inserted > 0 && incomeType == store.IncomeTypeTransfer {
+			at.loadNetTransfers()
+		}
+	}
+}
+
+// ownIncome keeps the records of symbols this trader had a position in at the record's time
+// Other traders sharing the exchange account (or manual trades) pay their own funding and fees.
+func (at *AutoTrader) ownIncome(records []IncomeRecord, since time.Time) []IncomeRecord {
+	slack := incomeAttributionSlack.Milliseconds()
+	fromMs, toMs := since.UnixMilli()-slack, time.Now().UnixMilli()+slack
+	for _, r := range records {
+		if t := r.Time.UnixMilli() + slack; t > toMs {
+			toMs = t
+		}
+	}
+	held := make(map[string][]*store.TraderPosition)
+
+	own := records[:0]
+	for _, r := range records {
+		symbol := market.Normalize(r.Symbol)
+		positions, ok := held[symbol]
+		if !ok {
+			var err error
+			positions, err = at.store.Position().GetSymbolPositionsInRange(at.id, symbol, fromMs, toMs)
+			if err != nil {
+				logger.Infof("⚠️ [%s] %v", at.name, err)
+			}
+			held[symbol] = positions
+		}
+		t := r.Time.UnixMilli()
+		for _, pos := range positions {
+			if pos.EntryTime-slack <= t && (pos.ExitTime == 0 || t <= pos.ExitTime+slack) {
+				own = append(own, r)
+				break
+			}
+		}
+	}
+	return own
+}
+
+// loadNetTransfers refreshes the deposits/withdrawals excluded from PnL
+// Only transfers after the latest initial balance sync count, earlier ones are part of the synced balance.
+func (at *AutoTrader) loadNetTransfers() {
+	if at.store == nil {
+		return
+	}
+	since, err := at.store.TraderEvent().LastTime(at.id, store.TraderEventBalanceSync)
+	if err != nil {
+		logger.Infof("⚠️ [%s] %v", at.name, err)
+		return
+	}
+	var sinceMs int64
+	if !since.IsZero() {
+		sinceMs = since.UnixMilli()
+	}
+	totals, err := at.store.Income().GetTransferTotals(at.id, sinceMs)
+	if err != nil {
+		logger.Infof("⚠️ [%s] %v", at.name, err)
+		return
+	}
+	at.transferMu.Lock()
+	at.transfers = *totals
+	at.transferMu.Unlock()
+}
+
+// adjustedPnL returns total PnL excluding deposits/withdrawals and its percentage
+// The percentage is relative to the capital put in: initial balance plus deposits.
+func (at *AutoTrader) adjustedPnL(totalEquity float64) (float64, float64) {
+	at.transferMu.RLock()
+	transfers := at.transfers
+	at.transferMu.RUnlock()
+
+	totalPnL := totalEquity - at.initialBalance - transfers.Net()
+	totalPnLPct := 0.0
+	if capital := at.initialBalance + transfers.Deposits; capital > 0 {
+		totalPnLPct = totalPnL / capital * 100
+	}
+	return totalPnL, totalPnLPct
+}
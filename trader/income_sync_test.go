@@ -0,0 +1,146 @@
+package trader
+
+import (
+	"nofx/store"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type stubIncomeTrader struct {
+	Trader
+	funding    []IncomeRecord
+	commission []IncomeRecord
+}
+
+func (s *stubIncomeTrader) GetFundingHistory(time.Time, int) ([]IncomeRecord, error) {
+	return s.funding, nil
+}
+
+func (s *stubIncomeTrader) GetCommissionHistory(time.Time, int) ([]IncomeRecord, error) {
+	return s.commission, nil
+}
+
+func TestSyncIncomeHistory_NetPnL(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+
+	now := time.Now().UTC()
+	stub := &stubIncomeTrader{
+		funding: []IncomeRecord{
+			{TranID: "f1", Symbol: "BTCUSDT", Amount: -1.5, Asset: "USDT", Time: now},
+			{TranID: "f2", Symbol: "BTCUSDT", Amount: 0.5, Asset: "USDT", Time: now.Add(time.Hour)},
+			// Paid by other positions on the same exchange account
+			{TranID: "f3", Symbol: "ETHUSDT", Amount: -4, Asset: "USDT", Time: now},
+			{TranID: "f4", Symbol: "BTCUSDT", Amount: -8, Asset: "USDT", Time: now.Add(3 * time.Hour)},
+		},
+		commission: []IncomeRecord{
+			{TranID: "c1", Symbol: "BTCUSDT", Amount: -2, Asset: "USDT", Time: now},
+			{TranID: "c2", Symbol: "ETHUSDT", Amount: -5, Asset: "USDT", Time: now},
+		},
+	}
+	at := &AutoTrader{id: "income_trader", name: "income", store: st, trader: stub, startTime: now}
+
+	if err := st.GormDB().Create(&store.TraderPosition{
+		TraderID: at.id, Symbol: "BTCUSDT", Side: "LONG", Quantity: 0.01, EntryPrice: 50000,
+		EntryTime: now.UnixMilli(), ExitTime: now.Add(2 * time.Hour).UnixMilli(), RealizedPnL: 10, Fee: 3, Status: "CLOSED",
+	}).Error; err != nil {
+		t.Fatalf("create position: %v", err)
+	}
+
+	at.syncIncomeHistory()
+	// Second sync must not duplicate records already stored
+	at.lastIncomeSyncTime = time.Time{}
+	at.syncIncomeHistory()
+
+	stats, err := st.Decision().GetStatistics(at.id)
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if stats.RealizedPnL != 10 || stats.TotalFunding != -1 || stats.TotalCommission != -2 {
+		t.Fatalf("unexpected breakdown: %+v", stats)
+	}
+	if stats.NetPnL != 7 {
+		t.Errorf("NetPnL = %.2f, want 7", stats.NetPnL)
+	}
+}
+
+func TestStatistics_CommissionFallsBackToPositionFees(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+
+	if err := st.GormDB().Create(&store.TraderPosition{
+		TraderID: "t1", Symbol: "ETHUSDT", Side: "SHORT", Quantity: 1, EntryPrice: 3000,
+		RealizedPnL: 20, Fee: 1.2, Status: "CLOSED",
+	}).Error; err != nil {
+		t.Fatalf("create position: %v", err)
+	}
+
+	stats, err := st.Decision().GetStatistics("t1")
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if stats.TotalCommission != -1.2 || stats.NetPnL != 18.8 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+type stubTransferTrader struct {
+	Trader
+	transfers []IncomeRecord
+}
+
+func (s *stubTransferTrader) GetTransferHistory(time.Time, int) ([]IncomeRecord, error) {
+	return s.transfers, nil
+}
+
+func TestSyncIncomeHistory_TransfersExcludedFromPnL(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+
+	now := time.Now().UTC()
+	stub := &stubTransferTrader{transfers: []IncomeRecord{
+		{TranID: "d1", Amount: 1000, Asset: "USDT", Time: now.Add(-2 * time.Hour)},
+		{TranID: "w1", Amount: -200, Asset: "USDT", Time: now.Add(-time.Hour)},
+	}}
+	at := &AutoTrader{id: "transfer_trader", name: "transfer", store: st, trader: stub, startTime: now.Add(-3 * time.Hour), initialBalance: 1000}
+
+	at.syncIncomeHistory()
+
+	// 1000 initial + 1000 deposit - 200 withdrawal + 90 profit
+	totalPnL, totalPnLPct := at.adjustedPnL(1890)
+	if totalPnL != 90 {
+		t.Errorf("PnL = %.2f, want 90 (transfers excluded)", totalPnL)
+	}
+	if totalPnLPct != 4.5 {
+		t.Errorf("PnL%% = %.2f, want 4.5 (of 2000 capital put in)", totalPnLPct)
+	}
+
+	// Transfers before an initial balance sync are part of the synced balance
+	if err := st.TraderEvent().Append(at.id, store.TraderEventBalanceSync, "Initial balance synced", nil); err != nil {
+		t.Fatalf("append event: %v", err)
+	}
+	at.initialBalance = 1800
+	at.loadNetTransfers()
+	if totalPnL, _ := at.adjustedPnL(1890); totalPnL != 90 {
+		t.Errorf("PnL after balance sync = %.2f, want 90", totalPnL)
+	}
+}
+
+func TestSyncsIncome(t *testing.T) {
+	if !(&AutoTrader{trader: &stubIncomeTrader{}}).SyncsIncome() {
+		t.Error("exchange implementing IncomeTrader should report synced income")
+	}
+	if (&AutoTrader{trader: &stubTransferTrader{}}).SyncsIncome() {
+		t.Error("exchange without income history should report income not synced")
+	}
+}
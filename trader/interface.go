@@ -8,13 +8,39 @@ import (
 
 // Re-export types for backward compatibility
 type (
-	ClosedPnLRecord   = types.ClosedPnLRecord
-	TradeRecord       = types.TradeRecord
-	Trader            = types.Trader
-	OpenOrder         = types.OpenOrder
-	LimitOrderRequest = types.LimitOrderRequest
-	LimitOrderResult  = types.LimitOrderResult
-	GridTrader        = types.GridTrader
+	ClosedPnLRecord         = types.ClosedPnLRecord
+	TradeRecord             = types.TradeRecord
+	Trader                  = types.Trader
+	OpenOrder               = types.OpenOrder
+	LimitOrderRequest       = types.LimitOrderRequest
+	LimitOrderResult        = types.LimitOrderResult
+	GridTrader              = types.GridTrader
+	IncomeRecord            = types.IncomeRecord
+	IncomeTrader            = types.IncomeTrader
+	TransferTrader          = types.TransferTrader
+	ClientOrderKey          = types.ClientOrderKey
+	ClientOrderTrader       = types.ClientOrderTrader
+	OCOTrader               = types.OCOTrader
+	BracketOrder            = types.BracketOrder
+	BracketTrader           = types.BracketTrader
+	PartialTakeProfitTrader = types.PartialTakeProfitTrader
+	SpotBalance             = types.SpotBalance
+	SpotTrader              = types.SpotTrader
+	CollateralAsset         = types.CollateralAsset
+	CollateralBalance       = types.CollateralBalance
+	CollateralTrader        = types.CollateralTrader
+	APIKeyPermissions       = types.APIKeyPermissions
+	PermissionTrader        = types.PermissionTrader
+	InverseTrader           = types.InverseTrader
+	ContractSpec            = types.ContractSpec
+	ErrorClassifier         = types.ErrorClassifier
+	ErrorClass              = types.ErrorClass
+)
+
+// Contract types
+const (
+	ContractLinear  = types.ContractLinear
+	ContractInverse = types.ContractInverse
 )
 
 // GridTraderAdapter wraps a basic Trader to provide GridTrader interface
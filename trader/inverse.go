@@ -0,0 +1,101 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/logger"
+)
+
+// ============================================================================
+// Inverse (Coin-Margined) Contracts
+// ============================================================================
+// An inverse trader (ContractType "inverse") trades the coin-margined
+// perpetual of its margin coin on Binance COIN-M or Bybit Inverse. The
+// exchange trader reports balances, margin and PnL in the margin coin and
+// takes quantities in base coin, converting them to whole USD contracts, so
+// the cycle keeps its account in the coin and converts to USD only where
+// position sizes (which are in USD) are compared with the balance.
+// ============================================================================
+
+// validateInverseConfig checks an inverse trader's configuration before its exchange trader is created
+func validateInverseConfig(config *AutoTraderConfig) error {
+	if config.Exchange != "binance" && config.Exchange != "bybit" {
+		return fmt.Errorf("inverse contracts are not supported on %s", config.Exchange)
+	}
+	if config.MarginCoin == "" {
+		config.MarginCoin = "BTC"
+	}
+	if config.PaperMode {
+		return fmt.Errorf("paper mode does not support inverse contracts")
+	}
+	if config.StrategyConfig != nil && config.StrategyConfig.StrategyType == "grid_trading" {
+		return fmt.Errorf("grid trading does not support inverse contracts")
+	}
+	return nil
+}
+
+// inverseTrader returns the exchange trader as an InverseTrader, nil for linear contracts
+func (at *AutoTrader) inverseTrader() InverseTrader {
+	if at.config.ContractType != ContractInverse {
+		return nil
+	}
+	inverse, _ := at.trader.(InverseTrader)
+	return inverse
+}
+
+// marginAssetPrice USD price of one unit of the margin asset (1 for USDT-margined traders)
+func (at *AutoTrader) marginAssetPrice() (float64, error) {
+	inverse := at.inverseTrader()
+	if inverse == nil {
+		return 1, nil
+	}
+	price, err := inverse.GetMarketPrice(inverse.MarginAsset() + "USDT")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get %s price: %w", inverse.MarginAsset(), err)
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("invalid %s price: %v", inverse.MarginAsset(), price)
+	}
+	return price, nil
+}
+
+// balancesInUSD converts available balance and equity to USD for position sizing
+func (at *AutoTrader) balancesInUSD(availableBalance, equity float64) (float64, float64, error) {
+	price, err := at.marginAssetPrice()
+	if err != nil {
+		return 0, 0, err
+	}
+	return availableBalance * price, equity * price, nil
+}
+
+// positionMargin estimated margin of a position, in the account's margin asset
+// Inverse positions hold their base coin quantity as margin, divided by leverage.
+func (at *AutoTrader) positionMargin(quantity, markPrice float64, leverage int) float64 {
+	if at.inverseTrader() != nil {
+		return quantity / float64(leverage)
+	}
+	return (quantity * markPrice) / float64(leverage)
+}
+
+// contractInfo contract terms of an inverse trader for the decision context, nil for linear contracts
+func (at *AutoTrader) contractInfo() (*kernel.ContractInfo, error) {
+	inverse := at.inverseTrader()
+	if inverse == nil {
+		return nil, nil
+	}
+	price, err := at.marginAssetPrice()
+	if err != nil {
+		return nil, err
+	}
+	symbol := inverse.MarginAsset() + "USDT"
+	spec, err := inverse.GetContractSpec(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s contract: %w", symbol, err)
+	}
+	logger.Infof("🪙 [%s] Inverse contract %s: %.0f USD per contract, %s = %.2f USD", at.name, spec.Symbol, spec.ContractSize, inverse.MarginAsset(), price)
+	return &kernel.ContractInfo{
+		MarginAsset:   inverse.MarginAsset(),
+		AssetPrice:    price,
+		ContractSizes: map[string]float64{symbol: spec.ContractSize},
+	}, nil
+}
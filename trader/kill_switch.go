@@ -0,0 +1,183 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"nofx/store"
+	"sync"
+	"time"
+)
+
+// KillSwitchConfigKey system config key of the persisted kill switch state
+const KillSwitchConfigKey = "kill_switch"
+
+// KillSwitchState an operator's system-wide halt of trading
+type KillSwitchState struct {
+	Active         bool      `json:"active"`
+	Reason         string    `json:"reason"`
+	EngagedBy      string    `json:"engaged_by"` // Operator email
+	EngagedAt      time.Time `json:"engaged_at"`
+	CancelOrders   bool      `json:"cancel_orders"`
+	ClosePositions bool      `json:"close_positions"`
+}
+
+// Message describes the halt for decision records and trader events
+func (s KillSwitchState) Message() string {
+	msg := "Trading halted by operator kill switch"
+	if s.Reason != "" {
+		msg += ": " + s.Reason
+	}
+	return msg
+}
+
+// KillSwitch the emergency halt shared by all traders: while engaged no decision or grid cycle runs
+// and no position is opened, closing positions stays possible
+type KillSwitch struct {
+	mu    sync.RWMutex
+	state KillSwitchState
+}
+
+// NewKillSwitch creates a released kill switch
+func NewKillSwitch() *KillSwitch {
+	return &KillSwitch{}
+}
+
+// Set replaces the kill switch state
+func (k *KillSwitch) Set(state KillSwitchState) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.state = state
+}
+
+// State returns the current kill switch state
+func (k *KillSwitch) State() KillSwitchState {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.state
+}
+
+// SetKillSwitch sets the system-wide kill switch the trader obeys
+func (at *AutoTrader) SetKillSwitch(k *KillSwitch) {
+	at.killSwitch = k
+}
+
+// killSwitchReason returns why trading is halted system-wide, empty if it is not
+func (at *AutoTrader) killSwitchReason() string {
+	if at.killSwitch == nil {
+		return ""
+	}
+	state := at.killSwitch.State()
+	if !state.Active {
+		return ""
+	}
+	return state.Message()
+}
+
+// ApplyKillSwitch records an engaged kill switch in the trader's timeline and, as requested by it,
+// cancels the trader's open orders and/or closes all its positions.
+// Cancelling orders also removes the stop loss/take profit of positions that are kept.
+func (at *AutoTrader) ApplyKillSwitch(state KillSwitchState) (cancelled, closed int, err error) {
+	details := map[string]interface{}{"reason": state.Reason, "cancel_orders": state.CancelOrders, "close_positions": state.ClosePositions}
+	defer func() {
+		details["order_symbols_cancelled"], details["positions_closed"] = cancelled, closed
+		if err != nil {
+			details["error"] = err.Error()
+		}
+		at.recordEvent(store.TraderEventKillSwitch, state.Message(), details)
+	}()
+	if !state.CancelOrders && !state.ClosePositions {
+		return 0, 0, nil
+	}
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	var errs []error
+	if state.CancelOrders {
+		if at.IsGridStrategy() && at.gridState != nil {
+			if err := at.cancelAllGridOrders(); err != nil {
+				errs = append(errs, fmt.Errorf("grid orders: %w", err))
+			}
+		}
+		for _, symbol := range at.orderSymbols(positions) {
+			if err := at.trader.CancelAllOrders(symbol); err != nil {
+				errs = append(errs, fmt.Errorf("cancel %s orders: %w", symbol, err))
+				continue
+			}
+			cancelled++
+		}
+		at.cancelSplitEntries("kill switch")
+	}
+
+	if state.ClosePositions {
+		if at.isSpotGrid() {
+			if err := at.sellSpotGridInventory(); err != nil {
+				errs = append(errs, fmt.Errorf("spot inventory: %w", err))
+			}
+		}
+		for _, pos := range positions {
+			symbol, _ := pos["symbol"].(string)
+			side, _ := pos["side"].(string)
+			if err := at.emergencyClosePosition(symbol, side); err != nil {
+				errs = append(errs, fmt.Errorf("close %s %s: %w", symbol, side, err))
+				continue
+			}
+			closed++
+		}
+	}
+
+	logger.Warnf("🛑 [%s] Kill switch: orders cancelled in %d symbols, %d positions closed (%d failures)", at.name, cancelled, closed, len(errs))
+	if len(errs) > 0 {
+		return cancelled, closed, fmt.Errorf("%d kill switch actions failed, first: %w", len(errs), errs[0])
+	}
+	return cancelled, closed, nil
+}
+
+// orderSymbols returns the symbols the trader may have open orders in: positions, pending limit entries,
+// split entries and the grid symbol
+func (at *AutoTrader) orderSymbols(positions []map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var symbols []string
+	add := func(symbol string) {
+		if symbol != "" && !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+	for _, pos := range positions {
+		symbol, _ := pos["symbol"].(string)
+		add(symbol)
+	}
+	if at.store != nil {
+		if pending, err := at.store.LimitEntry().ListPending(at.id); err == nil {
+			for _, entry := range pending {
+				add(entry.Symbol)
+			}
+		}
+		if entries, err := at.store.SplitEntry().ListActive(at.id); err == nil {
+			for _, entry := range entries {
+				add(entry.Symbol)
+			}
+		}
+	}
+	if at.IsGridStrategy() && !at.isSpotGrid() {
+		add(at.config.StrategyConfig.GridConfig.Symbol)
+	}
+	return symbols
+}
+
+// cancelSplitEntries abandons the remainder of all active split entries
+func (at *AutoTrader) cancelSplitEntries(reason string) {
+	if at.store == nil {
+		return
+	}
+	entries, err := at.store.SplitEntry().ListActive(at.id)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		at.finishSplitEntry(entry, store.SplitEntryCancelled, reason)
+	}
+}
@@ -0,0 +1,57 @@
+package trader
+
+import (
+	"strings"
+	"testing"
+
+	"nofx/kernel"
+	"nofx/store"
+)
+
+// stubFlattenTrader records order cancels and closes
+type stubFlattenTrader struct {
+	stubProtectionTrader
+	calls []string
+}
+
+func (s *stubFlattenTrader) CancelAllOrders(symbol string) error {
+	s.calls = append(s.calls, "cancel "+symbol)
+	return nil
+}
+
+func (s *stubFlattenTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	s.calls = append(s.calls, "close_short "+symbol)
+	return map[string]interface{}{"orderId": int64(1)}, nil
+}
+
+func TestKillSwitch_BlocksOpensAndFlattens(t *testing.T) {
+	exchange := &stubFlattenTrader{stubProtectionTrader: stubProtectionTrader{
+		positions: []map[string]interface{}{{"symbol": "ETHUSDT", "side": "short", "positionAmt": -2.0}},
+	}}
+	killSwitch := NewKillSwitch()
+	at := &AutoTrader{name: "test", trader: exchange}
+	at.SetKillSwitch(killSwitch)
+
+	open := &kernel.Decision{Symbol: "BTCUSDT", Action: "open_long"}
+	if at.killSwitchReason() != "" {
+		t.Fatal("a released kill switch must not halt")
+	}
+
+	state := KillSwitchState{Active: true, Reason: "exchange API incident", CancelOrders: true, ClosePositions: true}
+	killSwitch.Set(state)
+	err := at.executeDecisionWithRecord(open, &store.DecisionAction{}, ClientOrderKey{})
+	if err == nil || !strings.Contains(err.Error(), "exchange API incident") {
+		t.Errorf("opens must be rejected while halted, got %v", err)
+	}
+	if err := at.executeDecisionWithRecord(&kernel.Decision{Symbol: "BTCUSDT", Action: "hold"}, &store.DecisionAction{}, ClientOrderKey{}); err != nil {
+		t.Errorf("non-opening decisions stay allowed: %v", err)
+	}
+
+	cancelled, closed, err := at.ApplyKillSwitch(state)
+	if err != nil || cancelled != 1 || closed != 1 {
+		t.Fatalf("expected 1 cancel and 1 close, got %d/%d %v", cancelled, closed, err)
+	}
+	if got := strings.Join(exchange.calls, ","); got != "cancel ETHUSDT,close_short ETHUSDT" {
+		t.Errorf("unexpected exchange calls: %s", got)
+	}
+}
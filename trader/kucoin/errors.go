@@ -0,0 +1,34 @@
+package kucoin
+
+import (
+	"fmt"
+	"nofx/trader/types"
+)
+
+// kucoinErrorClasses KuCoin Futures API error codes by class
+var kucoinErrorClasses = map[string]types.ErrorClass{
+	"429000": types.ErrClassRateLimited, // Too many requests
+
+	"400001": types.ErrClassAuth, // Missing authentication headers
+	"400003": types.ErrClassAuth, // KC-API-KEY does not exist
+	"400004": types.ErrClassAuth, // Invalid KC-API-PASSPHRASE
+	"400005": types.ErrClassAuth, // Invalid signature
+	"400006": types.ErrClassAuth, // IP not in API key whitelist
+	"400007": types.ErrClassAuth, // Access denied, missing permission
+	"411100": types.ErrClassAuth, // User is frozen
+
+	"200004": types.ErrClassInsufficientMargin, // Balance insufficient
+	"300003": types.ErrClassInsufficientMargin, // Balance not enough
+
+	"400002": types.ErrClassNetwork, // Invalid KC-API-TIMESTAMP (clock drift, re-synced on failure)
+	"500000": types.ErrClassNetwork, // Internal server error
+}
+
+// apiError tags a KuCoin API error response with its class
+func apiError(code, msg string) error {
+	class, ok := kucoinErrorClasses[code]
+	if !ok {
+		class = types.ErrClassUnknown
+	}
+	return types.NewExchangeError(class, code, fmt.Errorf("KuCoin API error: code=%s, msg=%s", code, msg))
+}
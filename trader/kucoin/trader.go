@@ -240,7 +240,8 @@ func (t *KuCoinTrader) doRequest(method, path string, body interface{}) ([]byte,
 
 	var kcResp KuCoinResponse
 	if err := json.Unmarshal(respBody, &kcResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w, body: %s", err, string(respBody))
+		// Non-JSON body (gateway error page): classify by HTTP status
+		return nil, types.NewExchangeError(types.HTTPStatusClass(resp.StatusCode), "", fmt.Errorf("failed to parse response: %w, body: %s", err, string(respBody)))
 	}
 
 	if kcResp.Code != "200000" {
@@ -251,7 +252,7 @@ func (t *KuCoinTrader) doRequest(method, path string, body interface{}) ([]byte,
 				logger.Warnf("⚠️ Failed to re-sync server time: %v", err)
 			}
 		}
-		return nil, fmt.Errorf("KuCoin API error: code=%s, msg=%s", kcResp.Code, kcResp.Msg)
+		return nil, apiError(kcResp.Code, kcResp.Msg)
 	}
 
 	return kcResp.Data, nil
@@ -527,6 +528,38 @@ func (t *KuCoinTrader) quantityToLots(symbol string, quantity float64) (int64, e
 	return lotsInt, nil
 }
 
+// symbolFilters trading rules of a contract in base asset units: one lot is multiplier × lotSize coins
+func (c *KuCoinContract) symbolFilters(symbol string) types.SymbolFilters {
+	lot := c.Multiplier
+	if c.LotSize > 0 {
+		lot *= c.LotSize
+	}
+	return types.SymbolFilters{
+		Symbol:   symbol,
+		StepSize: lot,
+		MinQty:   lot,
+		MaxQty:   c.MaxOrderQty * c.Multiplier,
+		TickSize: c.TickSize,
+	}
+}
+
+// normalizeLots converts an order quantity (in base asset) to whole lots, rounding down and
+// rejecting quantities below one lot or above the contract's maximum order size
+func (t *KuCoinTrader) normalizeLots(symbol string, quantity float64) (int64, error) {
+	contract, err := t.getContract(symbol)
+	if err != nil {
+		return 0, err
+	}
+	if contract.Multiplier <= 0 {
+		return 0, fmt.Errorf("invalid contract multiplier for %s: %v", symbol, contract.Multiplier)
+	}
+	normalized, err := contract.symbolFilters(symbol).NormalizeQuantity(quantity, true)
+	if err != nil {
+		return 0, err
+	}
+	return int64(math.Round(normalized / contract.Multiplier)), nil
+}
+
 // SetMarginMode sets margin mode
 func (t *KuCoinTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
 	// KuCoin sets margin mode per position, handled automatically
@@ -559,6 +592,17 @@ func (t *KuCoinTrader) SetLeverage(symbol string, leverage int) error {
 
 // OpenLong opens long position
 func (t *KuCoinTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenLongWithClientID(symbol, quantity, leverage, types.ClientOrderKey{})
+}
+
+// OpenLongWithClientID opens long position with a deterministic client order ID
+func (t *KuCoinTrader) OpenLongWithClientID(symbol string, quantity float64, leverage int, key types.ClientOrderKey) (map[string]interface{}, error) {
+	// Round quantity to whole lots and check the contract's limits before touching existing orders
+	lots, err := t.normalizeLots(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
 	// Cancel old orders
 	t.CancelAllOrders(symbol)
 
@@ -569,14 +613,8 @@ func (t *KuCoinTrader) OpenLong(symbol string, quantity float64, leverage int) (
 
 	kcSymbol := t.convertSymbol(symbol)
 
-	// Convert quantity to lots
-	lots, err := t.quantityToLots(symbol, quantity)
-	if err != nil {
-		return nil, fmt.Errorf("failed to calculate lots: %w", err)
-	}
-
 	body := map[string]interface{}{
-		"clientOid":  fmt.Sprintf("nfx%d", time.Now().UnixNano()),
+		"clientOid":  kucoinClientOid(key),
 		"symbol":     kcSymbol,
 		"side":       "buy",
 		"type":       "market",
@@ -614,6 +652,17 @@ func (t *KuCoinTrader) OpenLong(symbol string, quantity float64, leverage int) (
 
 // OpenShort opens short position
 func (t *KuCoinTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenShortWithClientID(symbol, quantity, leverage, types.ClientOrderKey{})
+}
+
+// OpenShortWithClientID opens short position with a deterministic client order ID
+func (t *KuCoinTrader) OpenShortWithClientID(symbol string, quantity float64, leverage int, key types.ClientOrderKey) (map[string]interface{}, error) {
+	// Round quantity to whole lots and check the contract's limits before touching existing orders
+	lots, err := t.normalizeLots(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
 	// Cancel old orders
 	t.CancelAllOrders(symbol)
 
@@ -624,14 +673,8 @@ func (t *KuCoinTrader) OpenShort(symbol string, quantity float64, leverage int)
 
 	kcSymbol := t.convertSymbol(symbol)
 
-	// Convert quantity to lots
-	lots, err := t.quantityToLots(symbol, quantity)
-	if err != nil {
-		return nil, fmt.Errorf("failed to calculate lots: %w", err)
-	}
-
 	body := map[string]interface{}{
-		"clientOid":  fmt.Sprintf("nfx%d", time.Now().UnixNano()),
+		"clientOid":  kucoinClientOid(key),
 		"symbol":     kcSymbol,
 		"side":       "sell",
 		"type":       "market",
@@ -667,6 +710,41 @@ func (t *KuCoinTrader) OpenShort(symbol string, quantity float64, leverage int)
 	}, nil
 }
 
+// kucoinClientOid Client order ID for open orders, deterministic for an idempotent submission
+func kucoinClientOid(key types.ClientOrderKey) string {
+	if key.IsZero() {
+		return fmt.Sprintf("nfx%d", time.Now().UnixNano())
+	}
+	return "nfx" + key.ID()
+}
+
+// FindOrderByClientID looks up an order submitted with the given client order key
+func (t *KuCoinTrader) FindOrderByClientID(symbol string, key types.ClientOrderKey) (map[string]interface{}, error) {
+	clientOid := kucoinClientOid(key)
+	path := fmt.Sprintf("%s/byClientOid?clientOid=%s", kucoinOrderPath, clientOid)
+
+	data, err := t.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order by client ID: %w", err)
+	}
+
+	var order struct {
+		Id     string `json:"id"`
+		Status string `json:"status"`
+	}
+	// Unknown clientOid returns null data
+	if err := json.Unmarshal(data, &order); err != nil || order.Id == "" {
+		return nil, nil
+	}
+
+	return map[string]interface{}{
+		"orderId":       order.Id,
+		"symbol":        symbol,
+		"status":        order.Status,
+		"clientOrderId": clientOid,
+	}, nil
+}
+
 // queryOrderFillPrice queries order status and returns fill price
 func (t *KuCoinTrader) queryOrderFillPrice(orderId string) float64 {
 	// Wait a bit for order to fill
@@ -0,0 +1,29 @@
+package lighter
+
+import (
+	"nofx/trader/types"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// lighterStatusPattern HTTP status in Lighter request errors, e.g. "(status 429)"
+var lighterStatusPattern = regexp.MustCompile(`status (\d{3})`)
+
+// ClassifyError classifies a Lighter error by HTTP status and message, falling back to common messages
+func (t *LighterTraderV2) ClassifyError(err error) types.ErrorClass {
+	if err == nil {
+		return ""
+	}
+	msg := err.Error()
+	if strings.Contains(msg, "TxClient not initialized") {
+		return types.ErrClassAuth // No API key configured
+	}
+	if m := lighterStatusPattern.FindStringSubmatch(msg); m != nil {
+		status, _ := strconv.Atoi(m[1])
+		if class := types.HTTPStatusClass(status); class != "" {
+			return class
+		}
+	}
+	return types.ClassifyError(err)
+}
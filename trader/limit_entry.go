@@ -0,0 +1,297 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/store"
+	"strings"
+	"time"
+)
+
+// executeOpenLimitWithRecord places a resting limit entry order for an AI open_long_limit/open_short_limit decision.
+// Stop loss / take profit are attached by reconcileLimitEntries once the order (partially) fills.
+func (at *AutoTrader) executeOpenLimitWithRecord(decision *kernel.Decision, actionRecord *store.DecisionAction) error {
+	side := "short"
+	if decision.IsLong() {
+		side = "long"
+	}
+	logger.Infof("  📌 Limit %s entry: %s @ %.4f", side, decision.Symbol, decision.Price)
+
+	gridTrader, ok := at.trader.(GridTrader)
+	if !ok {
+		return fmt.Errorf("limit orders not supported on %s", at.exchange)
+	}
+	if at.store == nil {
+		return fmt.Errorf("store is nil, cannot track limit entry")
+	}
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		return fmt.Errorf("failed to get positions: %w", err)
+	}
+	pending, err := at.store.LimitEntry().ListPending(at.id)
+	if err != nil {
+		return err
+	}
+
+	// [CODE ENFORCED] Pending entries count toward max positions
+	if err := at.enforceMaxPositions(len(positions) + len(pending)); err != nil {
+		return err
+	}
+
+	for _, pos := range positions {
+		if pos["symbol"] == decision.Symbol && pos["side"] == side {
+			return fmt.Errorf("❌ %s already has %s position, close it first", decision.Symbol, side)
+		}
+	}
+	for _, entry := range pending {
+		if entry.Symbol == decision.Symbol && entry.Side == side {
+			return fmt.Errorf("❌ %s already has pending %s limit entry @ %.4f", decision.Symbol, side, entry.Price)
+		}
+	}
+
+	balance, err := at.trader.GetBalance()
+	if err != nil {
+		return fmt.Errorf("failed to get account balance: %w", err)
+	}
+	availableBalance := 0.0
+	if avail, ok := balance["availableBalance"].(float64); ok {
+		availableBalance = avail
+	}
+	equity := equityFromBalance(balance)
+	if equity <= 0 {
+		equity = availableBalance
+	}
+	if collateral := at.collateralInUSD(); collateral != nil {
+		availableBalance, equity = collateral.margining(decision.Symbol)
+	}
+
+	// [CODE ENFORCED] Position Value Ratio Check: position_value <= equity × ratio
+	if adjusted, wasCapped := at.enforcePositionValueRatio(decision.PositionSizeUSD, equity, decision.Symbol); wasCapped {
+		decision.PositionSizeUSD = adjusted
+	}
+
+	// [CODE ENFORCED] Margin check as for market entries
+	if err := at.fitToMargin(decision, side, availableBalance); err != nil {
+		return err
+	}
+
+	// [CODE ENFORCED] Minimum position size check
+	if err := at.enforceMinPositionSize(decision.PositionSizeUSD); err != nil {
+		return err
+	}
+
+	quantity := decision.PositionSizeUSD / decision.Price
+	actionRecord.Quantity = quantity
+	actionRecord.Price = decision.Price
+
+	if err := at.trader.SetMarginMode(decision.Symbol, at.config.IsCrossMargin); err != nil {
+		logger.Infof("  ⚠️ Failed to set margin mode: %v", err)
+	}
+
+	req := &LimitOrderRequest{
+		Symbol:       decision.Symbol,
+		Side:         "SELL",
+		PositionSide: "SHORT",
+		Price:        decision.Price,
+		Quantity:     quantity,
+		Leverage:     decision.Leverage,
+	}
+	if side == "long" {
+		req.Side = "BUY"
+		req.PositionSide = "LONG"
+	}
+	result, err := gridTrader.PlaceLimitOrder(req)
+	if err != nil {
+		return fmt.Errorf("failed to place limit order: %w", err)
+	}
+	actionRecord.ExchangeOrderID = result.OrderID
+	actionRecord.OrderStatus = result.Status
+
+	expiryMinutes := decision.ExpiryMinutes
+	if expiryMinutes <= 0 {
+		expiryMinutes = kernel.DefaultLimitEntryExpiryMinutes
+	}
+	entry := &store.LimitEntry{
+		TraderID:   at.id,
+		Symbol:     decision.Symbol,
+		Side:       side,
+		OrderID:    result.OrderID,
+		Price:      decision.Price,
+		Quantity:   quantity,
+		Leverage:   decision.Leverage,
+		StopLoss:   decision.StopLoss,
+		TakeProfit: decision.TakeProfit,
+		ExpiresAt:  time.Now().Add(time.Duration(expiryMinutes) * time.Minute).UTC().UnixMilli(),
+	}
+	if err := at.store.LimitEntry().Create(entry); err != nil {
+		// Order is live on exchange but untracked; cancel to avoid an unprotected fill
+		if cancelErr := gridTrader.CancelOrder(decision.Symbol, result.OrderID); cancelErr != nil {
+			logger.Errorf("  ❌ Failed to cancel untracked limit order %s: %v", result.OrderID, cancelErr)
+		}
+		return err
+	}
+
+	logger.Infof("  ✓ Limit entry placed, order ID: %s, quantity: %.4f, expires in %d min",
+		result.OrderID, quantity, expiryMinutes)
+	return nil
+}
+
+// reconcileLimitEntries checks pending limit entries: protects filled and partially filled ones
+// with SL/TP, and cancels those past their expiry
+func (at *AutoTrader) reconcileLimitEntries() {
+	if at.store == nil {
+		return
+	}
+	pending, err := at.store.LimitEntry().ListPending(at.id)
+	if err != nil {
+		logger.Infof("⚠️ [%s] Failed to load pending limit entries: %v", at.name, err)
+		return
+	}
+
+	nowMs := time.Now().UTC().UnixMilli()
+	for _, entry := range pending {
+		status, err := at.trader.GetOrderStatus(entry.Symbol, entry.OrderID)
+		if err != nil {
+			logger.Infof("⚠️ [%s] Failed to query limit entry %s status: %v", at.name, entry.OrderID, err)
+			continue
+		}
+		orderStatus, _ := status["status"].(string)
+		executedQty, _ := status["executedQty"].(float64)
+		avgPrice, _ := status["avgPrice"].(float64)
+
+		switch strings.ToUpper(orderStatus) {
+		case "FILLED":
+			at.onLimitEntryFilled(entry, executedQty, avgPrice, "filled")
+			continue
+		case "CANCELED", "CANCELLED", "EXPIRED", "REJECTED":
+			reason := "order " + strings.ToLower(orderStatus) + " on exchange"
+			if executedQty > 0 {
+				at.onLimitEntryFilled(entry, executedQty, avgPrice, reason+" after partial fill")
+			} else if err := at.store.LimitEntry().UpdateStatus(entry.ID, store.LimitEntryCancelled, 0, 0, reason); err != nil {
+				logger.Infof("⚠️ [%s] Failed to update limit entry %d: %v", at.name, entry.ID, err)
+			}
+			continue
+		}
+
+		// Protect a partial fill right away, the rest of the order keeps resting
+		if executedQty > entry.ProtectedQty && !at.onLimitEntryPartiallyFilled(entry, executedQty, avgPrice) {
+			continue
+		}
+
+		if nowMs < entry.ExpiresAt {
+			continue
+		}
+
+		gridTrader, ok := at.trader.(GridTrader)
+		if !ok {
+			continue
+		}
+		if err := gridTrader.CancelOrder(entry.Symbol, entry.OrderID); err != nil {
+			logger.Infof("⚠️ [%s] Failed to cancel expired limit entry %s: %v", at.name, entry.OrderID, err)
+			continue
+		}
+		if executedQty > 0 {
+			at.onLimitEntryFilled(entry, executedQty, avgPrice, "expired after partial fill")
+			continue
+		}
+		logger.Infof("⌛ [%s] Limit entry %s %s @ %.4f expired, cancelled", at.name, entry.Symbol, entry.Side, entry.Price)
+		if err := at.store.LimitEntry().UpdateStatus(entry.ID, store.LimitEntryExpired, 0, 0, "expired"); err != nil {
+			logger.Infof("⚠️ [%s] Failed to update limit entry %d: %v", at.name, entry.ID, err)
+		}
+	}
+}
+
+// onLimitEntryFilled places stop loss / take profit for the filled quantity and closes out the entry
+func (at *AutoTrader) onLimitEntryFilled(entry *store.LimitEntry, filledQty, avgPrice float64, reason string) {
+	if filledQty <= 0 {
+		filledQty = entry.Quantity
+	}
+	if avgPrice <= 0 {
+		avgPrice = entry.Price
+	}
+	logger.Infof("✅ [%s] Limit entry %s %s filled: %.4f @ %.4f", at.name, entry.Symbol, entry.Side, filledQty, avgPrice)
+
+	if filledQty > entry.ProtectedQty {
+		if err := at.protectLimitEntry(entry, filledQty); err != nil {
+			reason = err.Error()
+		}
+	}
+
+	if err := at.store.LimitEntry().UpdateStatus(entry.ID, store.LimitEntryFilled, filledQty, avgPrice, reason); err != nil {
+		logger.Infof("⚠️ [%s] Failed to update limit entry %d: %v", at.name, entry.ID, err)
+	}
+}
+
+// onLimitEntryPartiallyFilled protects the filled part of a still resting entry order
+// Returns false when the entry ended because the stop loss could not be placed: the position is
+// closed and the rest of the order cancelled.
+func (at *AutoTrader) onLimitEntryPartiallyFilled(entry *store.LimitEntry, filledQty, avgPrice float64) bool {
+	if avgPrice <= 0 {
+		avgPrice = entry.Price
+	}
+	logger.Infof("🔸 [%s] Limit entry %s %s partially filled: %.4f of %.4f @ %.4f", at.name, entry.Symbol, entry.Side, filledQty, entry.Quantity, avgPrice)
+
+	if err := at.protectLimitEntry(entry, filledQty); err != nil {
+		if gridTrader, ok := at.trader.(GridTrader); ok {
+			if cancelErr := gridTrader.CancelOrder(entry.Symbol, entry.OrderID); cancelErr != nil {
+				logger.Errorf("❌ [%s] Failed to cancel rest of limit entry %s: %v", at.name, entry.OrderID, cancelErr)
+			}
+		}
+		if updateErr := at.store.LimitEntry().UpdateStatus(entry.ID, store.LimitEntryFilled, filledQty, avgPrice, err.Error()); updateErr != nil {
+			logger.Infof("⚠️ [%s] Failed to update limit entry %d: %v", at.name, entry.ID, updateErr)
+		}
+		return false
+	}
+
+	entry.ProtectedQty, entry.FilledQty, entry.AvgPrice = filledQty, filledQty, avgPrice
+	if err := at.store.LimitEntry().UpdateProtected(entry.ID, filledQty, avgPrice); err != nil {
+		logger.Infof("⚠️ [%s] Failed to update limit entry %d: %v", at.name, entry.ID, err)
+	}
+	return true
+}
+
+// protectLimitEntry places stop loss / take profit for the filled quantity of an entry,
+// replacing those placed for an earlier partial fill
+func (at *AutoTrader) protectLimitEntry(entry *store.LimitEntry, filledQty float64) error {
+	if entry.ProtectedQty > 0 {
+		if err := at.trader.CancelStopOrders(entry.Symbol); err != nil {
+			logger.Infof("⚠️ [%s] Failed to cancel stop orders of %s before extending them: %v", at.name, entry.Symbol, err)
+		}
+	} else {
+		at.positionFirstSeenTime[entry.Symbol+"_"+entry.Side] = time.Now().UnixMilli()
+	}
+	return at.protectOrClose(entry.Symbol, entry.Side, filledQty, entry.StopLoss, entry.TakeProfit)
+}
+
+// pendingOrdersForContext converts pending limit entries into AI context
+func (at *AutoTrader) pendingOrdersForContext() []kernel.PendingOrder {
+	if at.store == nil {
+		return nil
+	}
+	pending, err := at.store.LimitEntry().ListPending(at.id)
+	if err != nil {
+		logger.Infof("⚠️ [%s] Failed to load pending limit entries: %v", at.name, err)
+		return nil
+	}
+
+	nowMs := time.Now().UTC().UnixMilli()
+	orders := make([]kernel.PendingOrder, 0, len(pending))
+	for _, entry := range pending {
+		minutesLeft := int((entry.ExpiresAt - nowMs) / 60000)
+		if minutesLeft < 0 {
+			minutesLeft = 0
+		}
+		orders = append(orders, kernel.PendingOrder{
+			Symbol:          entry.Symbol,
+			Side:            entry.Side,
+			Price:           entry.Price,
+			Quantity:        entry.Quantity,
+			StopLoss:        entry.StopLoss,
+			TakeProfit:      entry.TakeProfit,
+			MinutesToExpiry: minutesLeft,
+		})
+	}
+	return orders
+}
@@ -0,0 +1,158 @@
+package trader
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nofx/store"
+)
+
+// stubLimitEntryTrader reports a fixed status for resting limit orders
+type stubLimitEntryTrader struct {
+	stubProtectionTrader
+	status       map[string]interface{}
+	cancelledIDs []string
+}
+
+func (s *stubLimitEntryTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	return s.status, nil
+}
+
+func (s *stubLimitEntryTrader) PlaceLimitOrder(req *LimitOrderRequest) (*LimitOrderResult, error) {
+	return &LimitOrderResult{OrderID: "1", Status: "NEW"}, nil
+}
+
+func (s *stubLimitEntryTrader) CancelOrder(symbol, orderID string) error {
+	s.cancelledIDs = append(s.cancelledIDs, orderID)
+	return nil
+}
+
+func (s *stubLimitEntryTrader) GetOrderBook(symbol string, depth int) ([][]float64, [][]float64, error) {
+	return nil, nil, nil
+}
+
+func newLimitEntryTest(t *testing.T, expiresAt time.Time) (*AutoTrader, *stubLimitEntryTrader, *store.Store) {
+	t.Helper()
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+
+	exchange := &stubLimitEntryTrader{}
+	at := &AutoTrader{id: "trader-limit", name: "test", exchange: "binance", store: st, trader: exchange, positionFirstSeenTime: map[string]int64{}}
+	entry := &store.LimitEntry{TraderID: at.id, Symbol: "BTCUSDT", Side: "long", OrderID: "42", Price: 100, Quantity: 10,
+		Leverage: 5, StopLoss: 90, TakeProfit: 120, ExpiresAt: expiresAt.UTC().UnixMilli()}
+	if err := st.LimitEntry().Create(entry); err != nil {
+		t.Fatal(err)
+	}
+	return at, exchange, st
+}
+
+func latestLimitEntry(t *testing.T, st *store.Store, traderID string) *store.LimitEntry {
+	t.Helper()
+	entries, err := st.LimitEntry().List(traderID, 1)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one limit entry, got %v %v", entries, err)
+	}
+	return entries[0]
+}
+
+func TestReconcileLimitEntries_Filled(t *testing.T) {
+	at, exchange, st := newLimitEntryTest(t, time.Now().Add(time.Hour))
+	exchange.status = map[string]interface{}{"status": "FILLED", "executedQty": 10.0, "avgPrice": 99.5}
+
+	at.reconcileLimitEntries()
+
+	entry := latestLimitEntry(t, st, at.id)
+	if entry.Status != store.LimitEntryFilled || entry.FilledQty != 10 || entry.AvgPrice != 99.5 {
+		t.Errorf("expected a filled entry, got %+v", entry)
+	}
+	if len(exchange.stopLosses) != 1 || len(exchange.takeProfits) != 1 {
+		t.Errorf("filled entry should be protected: sl=%v tp=%v", exchange.stopLosses, exchange.takeProfits)
+	}
+}
+
+func TestReconcileLimitEntries_PartialFillProtectedBeforeCompletion(t *testing.T) {
+	at, exchange, st := newLimitEntryTest(t, time.Now().Add(time.Hour))
+
+	// The filled part is protected while the rest of the order keeps resting
+	exchange.status = map[string]interface{}{"status": "PARTIALLY_FILLED", "executedQty": 4.0, "avgPrice": 100.0}
+	at.reconcileLimitEntries()
+	entry := latestLimitEntry(t, st, at.id)
+	if entry.Status != store.LimitEntryPending || entry.ProtectedQty != 4 {
+		t.Fatalf("expected a pending entry protecting 4, got %+v", entry)
+	}
+	if len(exchange.stopLosses) != 1 || len(exchange.cancelledIDs) != 0 {
+		t.Fatalf("partial fill should be protected without cancelling the order: sl=%v cancelled=%v", exchange.stopLosses, exchange.cancelledIDs)
+	}
+
+	// No new fill: nothing is placed again
+	at.reconcileLimitEntries()
+	if len(exchange.stopLosses) != 1 {
+		t.Errorf("unchanged fill should not place stops again: %v", exchange.stopLosses)
+	}
+
+	// More fills replace the stops to cover the larger quantity
+	exchange.status["executedQty"] = 7.0
+	at.reconcileLimitEntries()
+	if entry := latestLimitEntry(t, st, at.id); entry.ProtectedQty != 7 {
+		t.Errorf("expected 7 protected, got %+v", entry)
+	}
+	if len(exchange.stopLosses) != 2 || len(exchange.cancelled) != 1 {
+		t.Errorf("stops should be replaced for the larger fill: sl=%v cancelled=%v", exchange.stopLosses, exchange.cancelled)
+	}
+
+	// Completion extends the protection to the full quantity
+	exchange.status = map[string]interface{}{"status": "FILLED", "executedQty": 10.0, "avgPrice": 100.0}
+	at.reconcileLimitEntries()
+	if entry := latestLimitEntry(t, st, at.id); entry.Status != store.LimitEntryFilled || entry.FilledQty != 10 {
+		t.Errorf("expected a filled entry, got %+v", entry)
+	}
+	if len(exchange.stopLosses) != 3 || len(exchange.cancelled) != 2 {
+		t.Errorf("stops should cover the full fill: sl=%v cancelled=%v", exchange.stopLosses, exchange.cancelled)
+	}
+}
+
+func TestReconcileLimitEntries_CancelledOnExchange(t *testing.T) {
+	at, exchange, st := newLimitEntryTest(t, time.Now().Add(time.Hour))
+	exchange.status = map[string]interface{}{"status": "CANCELED", "executedQty": 0.0}
+
+	at.reconcileLimitEntries()
+
+	if entry := latestLimitEntry(t, st, at.id); entry.Status != store.LimitEntryCancelled {
+		t.Errorf("expected a cancelled entry, got %+v", entry)
+	}
+	if len(exchange.stopLosses) != 0 {
+		t.Errorf("unfilled entry must not place stops: %v", exchange.stopLosses)
+	}
+}
+
+func TestReconcileLimitEntries_Expired(t *testing.T) {
+	at, exchange, st := newLimitEntryTest(t, time.Now().Add(-time.Minute))
+	exchange.status = map[string]interface{}{"status": "NEW", "executedQty": 0.0}
+
+	at.reconcileLimitEntries()
+
+	if entry := latestLimitEntry(t, st, at.id); entry.Status != store.LimitEntryExpired {
+		t.Errorf("expected an expired entry, got %+v", entry)
+	}
+	if len(exchange.cancelledIDs) != 1 || exchange.cancelledIDs[0] != "42" {
+		t.Errorf("expired order should be cancelled, got %v", exchange.cancelledIDs)
+	}
+}
+
+func TestReconcileLimitEntries_ExpiredAfterPartialFill(t *testing.T) {
+	at, exchange, st := newLimitEntryTest(t, time.Now().Add(-time.Minute))
+	exchange.status = map[string]interface{}{"status": "PARTIALLY_FILLED", "executedQty": 3.0, "avgPrice": 100.0}
+
+	at.reconcileLimitEntries()
+
+	if entry := latestLimitEntry(t, st, at.id); entry.Status != store.LimitEntryFilled || entry.FilledQty != 3 {
+		t.Errorf("expected the partial fill to be kept, got %+v", entry)
+	}
+	if len(exchange.cancelledIDs) != 1 || len(exchange.stopLosses) != 1 {
+		t.Errorf("expected the rest cancelled and one stop for the fill: cancelled=%v sl=%v", exchange.cancelledIDs, exchange.stopLosses)
+	}
+}
@@ -0,0 +1,60 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"nofx/store"
+	"time"
+)
+
+const (
+	// liquidationRiskDistance positions whose mark price is within this fraction of the liquidation price raise an alert
+	liquidationRiskDistance = 0.05
+	// liquidationAlertInterval minimum time between alerts for the same position
+	liquidationAlertInterval = time.Hour
+)
+
+// checkLiquidationRisk records a liquidation_risk event when a position's mark price nears its liquidation price
+// Called from the drawdown monitor for every position; positions without a liquidation price are skipped.
+func (at *AutoTrader) checkLiquidationRisk(pos map[string]interface{}) {
+	symbol, _ := pos["symbol"].(string)
+	side, _ := pos["side"].(string)
+	markPrice, _ := pos["markPrice"].(float64)
+	liqPrice, _ := pos["liquidationPrice"].(float64)
+	if markPrice <= 0 || liqPrice <= 0 {
+		return
+	}
+
+	distance := (markPrice - liqPrice) / markPrice
+	if side == "short" {
+		distance = (liqPrice - markPrice) / markPrice
+	}
+	if distance >= liquidationRiskDistance {
+		return
+	}
+
+	posKey := symbol + "_" + side
+
+	at.liquidationAlertsMu.Lock()
+	if at.liquidationAlerts == nil {
+		at.liquidationAlerts = make(map[string]time.Time)
+	}
+	if last, ok := at.liquidationAlerts[posKey]; ok && time.Since(last) < liquidationAlertInterval {
+		at.liquidationAlertsMu.Unlock()
+		return
+	}
+	at.liquidationAlerts[posKey] = time.Now()
+	at.liquidationAlertsMu.Unlock()
+
+	logger.Warnf("🚨 [%s] %s %s is %.2f%% from liquidation (mark %.4f, liquidation %.4f)",
+		at.name, symbol, side, distance*100, markPrice, liqPrice)
+	at.recordEvent(store.TraderEventLiquidationRisk,
+		fmt.Sprintf("%s %s is %.2f%% from liquidation (mark %.4f, liquidation %.4f)", symbol, side, distance*100, markPrice, liqPrice),
+		map[string]interface{}{
+			"symbol":            symbol,
+			"side":              side,
+			"mark_price":        markPrice,
+			"liquidation_price": liqPrice,
+			"distance_pct":      distance * 100,
+		})
+}
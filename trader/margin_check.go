@@ -0,0 +1,99 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/logger"
+)
+
+// defaultMarginBufferPct free balance kept on top of a new position's margin and fees, % of the margin
+const defaultMarginBufferPct = 2.0
+
+// marginFormula how an exchange charges an opening market order against the available balance
+type marginFormula struct {
+	takerFee float64 // Opening fee rate (VIP 0 taker)
+	closeFee bool    // The fee to close at the bankruptcy price is reserved as well (Bybit order cost)
+}
+
+// exchangeMarginFormulas initial margin is notional / leverage on every exchange, they differ in what else is reserved
+var exchangeMarginFormulas = map[string]marginFormula{
+	"binance":     {takerFee: 0.0005},
+	"bybit":       {takerFee: 0.00055, closeFee: true},
+	"okx":         {takerFee: 0.0005},
+	"bitget":      {takerFee: 0.0006},
+	"gate":        {takerFee: 0.0005},
+	"kucoin":      {takerFee: 0.0006},
+	"hyperliquid": {takerFee: 0.00045},
+	"aster":       {takerFee: 0.0004},
+	"lighter":     {}, // Standard accounts trade without fees
+}
+
+// defaultMarginFormula exchanges without a known formula, a conservative 0.1% fee
+var defaultMarginFormula = marginFormula{takerFee: 0.001}
+
+func marginFormulaOf(exchange string) marginFormula {
+	if f, ok := exchangeMarginFormulas[exchange]; ok {
+		return f
+	}
+	return defaultMarginFormula
+}
+
+// costRate balance required per unit of notional: initial margin plus buffer, opening fee and the reserved close fee
+func (f marginFormula) costRate(leverage int, side string, bufferPct float64) float64 {
+	lev := float64(max(leverage, 1))
+	rate := (1/lev)*(1+bufferPct/100) + f.takerFee
+	if f.closeFee {
+		// Closing at the bankruptcy price: entry × (1 - 1/leverage) for longs, entry × (1 + 1/leverage) for shorts
+		bankruptcy := 1 - 1/lev
+		if side == "short" {
+			bankruptcy = 1 + 1/lev
+		}
+		rate += bankruptcy * f.takerFee
+	}
+	return rate
+}
+
+// required balance an opening order of notional USD consumes
+func (f marginFormula) required(notional float64, leverage int, side string, bufferPct float64) float64 {
+	return notional * f.costRate(leverage, side, bufferPct)
+}
+
+// maxNotional largest opening order the available balance covers
+func (f marginFormula) maxNotional(available float64, leverage int, side string, bufferPct float64) float64 {
+	if available <= 0 {
+		return 0
+	}
+	return available / f.costRate(leverage, side, bufferPct)
+}
+
+func (at *AutoTrader) marginBufferPct() float64 {
+	if at.config.StrategyConfig != nil && at.config.StrategyConfig.RiskControl.MarginBufferPct > 0 {
+		return at.config.StrategyConfig.RiskControl.MarginBufferPct
+	}
+	return defaultMarginBufferPct
+}
+
+// fitToMargin [CODE ENFORCED] downsizes an opening order to what the available balance can margin on this
+// exchange, instead of sending an order the exchange rejects. Returns an error (the entry is skipped) when
+// not even the minimum position size fits.
+func (at *AutoTrader) fitToMargin(decision *kernel.Decision, side string, availableBalance float64) error {
+	formula := marginFormulaOf(at.exchange)
+	buffer := at.marginBufferPct()
+	required := formula.required(decision.PositionSizeUSD, decision.Leverage, side, buffer)
+	if required <= availableBalance {
+		return nil
+	}
+
+	affordable := formula.maxNotional(availableBalance, decision.Leverage, side, buffer)
+	if minSize := at.minPositionSize(); affordable < minSize {
+		logger.Infof("  ⏭ Insufficient margin for %s %s: %.2f USDT at %dx needs %.2f (margin, %s fees, %.0f%% buffer), available %.2f, max affordable %.2f is below the minimum %.2f, skipping",
+			decision.Symbol, side, decision.PositionSizeUSD, decision.Leverage, required, at.exchange, buffer, availableBalance, affordable, minSize)
+		return fmt.Errorf("❌ [RISK CONTROL] Insufficient margin: %s %s needs %.2f USDT at %dx, available %.2f (max affordable position %.2f < minimum %.2f)",
+			decision.Symbol, side, required, decision.Leverage, availableBalance, affordable, minSize)
+	}
+
+	logger.Infof("  ⚠️ Insufficient margin for %s %s: %.2f USDT at %dx needs %.2f (margin, %s fees, %.0f%% buffer), available %.2f, reducing to %.2f",
+		decision.Symbol, side, decision.PositionSizeUSD, decision.Leverage, required, at.exchange, buffer, availableBalance, affordable)
+	decision.PositionSizeUSD = affordable
+	return nil
+}
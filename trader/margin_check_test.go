@@ -0,0 +1,57 @@
+package trader
+
+import (
+	"math"
+	"nofx/kernel"
+	"nofx/store"
+	"strings"
+	"testing"
+)
+
+func TestMarginFormulaRequired(t *testing.T) {
+	// Binance: 1000 USDT at 10x = 100 margin + 2% buffer + 0.5 opening fee
+	binance := marginFormulaOf("binance")
+	if got := binance.required(1000, 10, "long", 2); math.Abs(got-102.5) > 1e-9 {
+		t.Errorf("binance required %v, want 102.5", got)
+	}
+
+	// Bybit reserves the close fee at the bankruptcy price, more for shorts
+	bybit := marginFormulaOf("bybit")
+	long, short := bybit.required(1000, 10, "long", 0), bybit.required(1000, 10, "short", 0)
+	if math.Abs(long-(100+0.55+0.495)) > 1e-9 || math.Abs(short-(100+0.55+0.605)) > 1e-9 {
+		t.Errorf("bybit required long %v, short %v", long, short)
+	}
+
+	// The largest affordable order uses exactly the available balance
+	if got := binance.required(binance.maxNotional(50, 5, "long", 2), 5, "long", 2); math.Abs(got-50) > 1e-9 {
+		t.Errorf("max notional requires %v, want 50", got)
+	}
+	if binance.maxNotional(-5, 5, "long", 2) != 0 {
+		t.Error("a negative balance affords nothing")
+	}
+	if marginFormulaOf("unknown") != defaultMarginFormula {
+		t.Error("unknown exchanges should use the conservative default")
+	}
+}
+
+func TestFitToMargin(t *testing.T) {
+	at := &AutoTrader{exchange: "binance", config: AutoTraderConfig{StrategyConfig: &store.StrategyConfig{
+		RiskControl: store.RiskControlConfig{MinPositionSize: 20},
+	}}}
+
+	fits := &kernel.Decision{Symbol: "BTCUSDT", PositionSizeUSD: 1000, Leverage: 10}
+	if err := at.fitToMargin(fits, "long", 200); err != nil || fits.PositionSizeUSD != 1000 {
+		t.Errorf("a covered order should be unchanged: %v, size %v", err, fits.PositionSizeUSD)
+	}
+
+	downsized := &kernel.Decision{Symbol: "BTCUSDT", PositionSizeUSD: 1000, Leverage: 10}
+	if err := at.fitToMargin(downsized, "long", 51.25); err != nil || math.Abs(downsized.PositionSizeUSD-500) > 1e-9 {
+		t.Errorf("should downsize to 500: %v, size %v", err, downsized.PositionSizeUSD)
+	}
+
+	skipped := &kernel.Decision{Symbol: "BTCUSDT", PositionSizeUSD: 1000, Leverage: 10}
+	err := at.fitToMargin(skipped, "short", 1)
+	if err == nil || !strings.Contains(err.Error(), "Insufficient margin") || skipped.PositionSizeUSD != 1000 {
+		t.Errorf("an order below the minimum should be skipped: %v, size %v", err, skipped.PositionSizeUSD)
+	}
+}
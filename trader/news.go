@@ -0,0 +1,45 @@
+package trader
+
+import (
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/provider/news"
+	"nofx/store"
+	"time"
+)
+
+// SetNewsService sets the source of news headlines and economic calendar events
+func (at *AutoTrader) SetNewsService(service *news.Service) {
+	at.newsService = service
+}
+
+// newsSnapshot returns the cached news when the strategy shows it or blocks entries around events, nil otherwise
+func (at *AutoTrader) newsSnapshot(config *store.StrategyConfig) *news.Snapshot {
+	if at.newsService == nil {
+		return nil
+	}
+	if !config.Indicators.EnableNews && !config.Indicators.EnableEconomicCalendar &&
+		config.RiskControl.BlockEntriesNearEventMins <= 0 {
+		return nil
+	}
+	return at.newsService.Snapshot()
+}
+
+// blockEntriesNearEvents vetoes opening decisions while a high-impact economic event is near
+func (at *AutoTrader) blockEntriesNearEvents(decisions []kernel.Decision, snap *news.Snapshot, windowMins int, record *store.DecisionRecord) []kernel.Decision {
+	reason := kernel.EventBlackout(snap, windowMins, time.Now())
+	if reason == "" {
+		return decisions
+	}
+	kept := make([]kernel.Decision, 0, len(decisions))
+	for _, d := range decisions {
+		if !d.IsOpen() {
+			kept = append(kept, d)
+			continue
+		}
+		note := d.Symbol + " " + d.Action + " vetoed: " + reason
+		logger.Infof("📰 [%s] %s", at.name, note)
+		record.ExecutionLog = append(record.ExecutionLog, "📰 "+note)
+	}
+	return kept
+}
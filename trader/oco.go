@@ -0,0 +1,241 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"nofx/logger"
+	"nofx/store"
+	"strings"
+	"time"
+)
+
+// protectionWatchInterval How often emulated OCO pairs are checked against open positions
+var protectionWatchInterval = 10 * time.Second
+
+// protectionGracePeriod Positions may not be reported right after opening, so a freshly placed
+// pair is never treated as closed before this period has passed
+var protectionGracePeriod = 30 * time.Second
+
+// protectionPair stop-loss/take-profit placed as independent orders, linked by emulated OCO
+type protectionPair struct {
+	Symbol     string
+	Side       string // LONG/SHORT
+	Quantity   float64
+	StopLoss   float64
+	TakeProfit float64
+	PlacedAt   time.Time
+}
+
+// setProtection places stop loss and take profit for a position as one-cancels-other.
+// Exchanges implementing OCOTrader link the legs natively; otherwise both orders are placed
+// independently and tracked, and the protection watcher cancels the remaining leg once the
+// position is gone (one leg filled, liquidation or manual close).
+// Returns the error of a requested stop loss that could not be placed; a failed take profit is only logged.
+func (at *AutoTrader) setProtection(symbol, positionSide string, quantity, stopLoss, takeProfit float64) error {
+	positionSide = strings.ToUpper(positionSide)
+	at.rememberStops(symbol, positionSide, stopLoss, takeProfit)
+
+	if stopLoss > 0 && takeProfit > 0 {
+		if oco, ok := at.trader.(OCOTrader); ok {
+			err := oco.SetStopLossTakeProfit(symbol, positionSide, quantity, stopLoss, takeProfit)
+			if err == nil {
+				return nil
+			}
+			logger.Infof("  ⚠ Failed to set linked stop loss/take profit, placing separate orders: %v", err)
+		}
+	}
+
+	var slErr error
+	if stopLoss > 0 {
+		_, slErr = exchangeCall(at, "Set stop loss "+symbol, true, func() (struct{}, error) {
+			return struct{}{}, at.trader.SetStopLoss(symbol, positionSide, quantity, stopLoss)
+		})
+		if slErr != nil {
+			logger.Infof("  ⚠ Failed to set stop loss: %v", slErr)
+		}
+	}
+	slPlaced := stopLoss > 0 && slErr == nil
+	tpPlaced := false
+	if takeProfit > 0 {
+		if err := at.trader.SetTakeProfit(symbol, positionSide, quantity, takeProfit); err != nil {
+			logger.Infof("  ⚠ Failed to set take profit: %v", err)
+		} else {
+			tpPlaced = true
+		}
+	}
+
+	// A single leg has no sibling to cancel
+	if slPlaced && tpPlaced {
+		at.trackProtectionPair(&protectionPair{
+			Symbol:     symbol,
+			Side:       positionSide,
+			Quantity:   quantity,
+			StopLoss:   stopLoss,
+			TakeProfit: takeProfit,
+			PlacedAt:   time.Now(),
+		})
+	}
+	return slErr
+}
+
+func (at *AutoTrader) trackProtectionPair(pair *protectionPair) {
+	at.protectionMutex.Lock()
+	defer at.protectionMutex.Unlock()
+	if at.protectionPairs == nil {
+		at.protectionPairs = make(map[string]*protectionPair)
+	}
+	at.protectionPairs[pair.Symbol+"_"+strings.ToLower(pair.Side)] = pair
+	at.saveProtection(store.ProtectionOCO, pair.Symbol, pair.Side, pair)
+}
+
+// saveProtection stores an emulated protection so a restarted trader keeps following it
+// Called with protectionMutex held, so the stored state follows the tracked one.
+func (at *AutoTrader) saveProtection(kind, symbol, side string, state interface{}) {
+	if at.store == nil {
+		return
+	}
+	if err := at.store.Protection().Save(at.id, kind, symbol, side, state); err != nil {
+		logger.Infof("⚠️ [%s] %v", at.name, err)
+	}
+}
+
+// deleteProtection forgets the stored protection of a position no longer followed
+func (at *AutoTrader) deleteProtection(kind, symbol, side string) {
+	if at.store == nil {
+		return
+	}
+	if err := at.store.Protection().Delete(at.id, kind, symbol, side); err != nil {
+		logger.Infof("⚠️ [%s] %v", at.name, err)
+	}
+}
+
+// positionQuantities absolute quantity of each open position by symbol_side
+func positionQuantities(positions []map[string]interface{}) map[string]float64 {
+	quantities := make(map[string]float64, len(positions))
+	for _, pos := range positions {
+		symbol, _ := pos["symbol"].(string)
+		side, _ := pos["side"].(string)
+		qty, _ := pos["positionAmt"].(float64)
+		quantities[symbol+"_"+strings.ToLower(side)] = math.Abs(qty)
+	}
+	return quantities
+}
+
+// restoreProtection loads the emulated protections saved before a restart. Their orders are still on
+// the exchange, only the tracking is rebuilt; pairs take the current quantity of positions partly closed
+// meanwhile, and those of positions closed meanwhile are cleaned up by the watcher.
+func (at *AutoTrader) restoreProtection() {
+	if at.store == nil {
+		return
+	}
+	saved, err := at.store.Protection().List(at.id, store.ProtectionOCO)
+	if err != nil {
+		logger.Infof("⚠️ [%s] %v", at.name, err)
+		return
+	}
+	if len(saved) == 0 {
+		return
+	}
+	var quantities map[string]float64
+	if positions, err := at.trader.GetPositions(); err == nil {
+		quantities = positionQuantities(positions)
+	} else {
+		logger.Infof("⚠️ [%s] Restoring stop loss/take profit tracking without current positions: %v", at.name, err)
+	}
+
+	at.protectionMutex.Lock()
+	defer at.protectionMutex.Unlock()
+	if at.protectionPairs == nil {
+		at.protectionPairs = make(map[string]*protectionPair)
+	}
+	for _, p := range saved {
+		pair := &protectionPair{}
+		if err := p.Decode(pair); err != nil {
+			logger.Infof("⚠️ [%s] %v", at.name, err)
+			continue
+		}
+		key := pair.Symbol + "_" + strings.ToLower(pair.Side)
+		if qty := quantities[key]; qty > 0 {
+			pair.Quantity = qty
+		}
+		at.protectionPairs[key] = pair
+	}
+	logger.Infof("🔗 [%s] Restored %d emulated stop loss/take profit pairs", at.name, len(at.protectionPairs))
+}
+
+// startProtectionWatcher starts the emulated OCO and take-profit ladder watcher
+// (in paper mode it also applies simulated fills and stop loss/take profit triggers)
+func (at *AutoTrader) startProtectionWatcher() {
+	at.monitorWg.Add(1)
+	go func() {
+		defer at.monitorWg.Done()
+
+		ticker := time.NewTicker(protectionWatchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				at.settlePaper()
+				at.checkProtectionPairs()
+				at.checkTakeProfitLadders()
+			case <-at.stopMonitorCh:
+				return
+			}
+		}
+	}()
+}
+
+// checkProtectionPairs cancels the remaining leg of emulated pairs whose position has closed.
+// Stop orders can only be cancelled per symbol, so pairs of the opposite side still open on the
+// same symbol (hedge mode) are placed again afterwards.
+func (at *AutoTrader) checkProtectionPairs() {
+	at.protectionMutex.Lock()
+	if len(at.protectionPairs) == 0 {
+		at.protectionMutex.Unlock()
+		return
+	}
+	at.protectionMutex.Unlock()
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Infof("❌ OCO watcher: failed to get positions: %v", err)
+		return
+	}
+	quantities := positionQuantities(positions)
+
+	at.protectionMutex.Lock()
+	closedSymbols := make(map[string]bool)
+	for key, pair := range at.protectionPairs {
+		if _, open := quantities[key]; !open && time.Since(pair.PlacedAt) >= protectionGracePeriod {
+			closedSymbols[pair.Symbol] = true
+			delete(at.protectionPairs, key)
+			at.deleteProtection(store.ProtectionOCO, pair.Symbol, pair.Side)
+		}
+	}
+	var replace []*protectionPair
+	for key, pair := range at.protectionPairs {
+		if closedSymbols[pair.Symbol] {
+			// Placed again for what is left of the position, not the quantity originally protected
+			if qty := quantities[key]; qty > 0 {
+				pair.Quantity = qty
+			}
+			replace = append(replace, pair)
+		}
+	}
+	at.protectionMutex.Unlock()
+
+	for symbol := range closedSymbols {
+		logger.Infof("🔗 [%s] %s position closed, cancelling remaining stop loss/take profit", at.name, symbol)
+		if err := at.trader.CancelStopOrders(symbol); err != nil {
+			logger.Infof("⚠️ [%s] Failed to cancel remaining orders for %s: %v", at.name, symbol, err)
+		}
+	}
+	for _, pair := range replace {
+		logger.Infof("🔗 [%s] Restoring stop loss/take profit for %s %s", at.name, pair.Symbol, pair.Side)
+		if err := at.setProtection(pair.Symbol, pair.Side, pair.Quantity, pair.StopLoss, pair.TakeProfit); err != nil {
+			at.recordEvent(store.TraderEventUnprotected, fmt.Sprintf("Stop loss of %s %s could not be restored", pair.Symbol, strings.ToLower(pair.Side)),
+				map[string]interface{}{"symbol": pair.Symbol, "side": pair.Side, "stop_loss": pair.StopLoss, "error": err.Error()})
+		}
+	}
+}
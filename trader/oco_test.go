@@ -0,0 +1,143 @@
+package trader
+
+import (
+	"nofx/store"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// stubProtectionTrader records stop orders; any other call would panic on the nil embedded Trader
+type stubProtectionTrader struct {
+	Trader
+	positions   []map[string]interface{}
+	stopLosses  []string
+	takeProfits []string
+	cancelled   []string
+}
+
+func (s *stubProtectionTrader) GetPositions() ([]map[string]interface{}, error) {
+	return s.positions, nil
+}
+
+func (s *stubProtectionTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	s.stopLosses = append(s.stopLosses, symbol+"_"+positionSide)
+	return nil
+}
+
+func (s *stubProtectionTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	s.takeProfits = append(s.takeProfits, symbol+"_"+positionSide)
+	return nil
+}
+
+func (s *stubProtectionTrader) CancelStopOrders(symbol string) error {
+	s.cancelled = append(s.cancelled, symbol)
+	return nil
+}
+
+// stubOCOTrader links both legs natively
+type stubOCOTrader struct {
+	stubProtectionTrader
+	linked int
+}
+
+func (s *stubOCOTrader) SetStopLossTakeProfit(symbol string, positionSide string, quantity, stopPrice, takeProfitPrice float64) error {
+	s.linked++
+	return nil
+}
+
+func TestSetProtection_UsesNativeOCO(t *testing.T) {
+	exchange := &stubOCOTrader{}
+	at := &AutoTrader{name: "test", trader: exchange}
+
+	at.setProtection("BTCUSDT", "LONG", 1, 90, 110)
+
+	if exchange.linked != 1 || len(exchange.stopLosses) != 0 || len(exchange.takeProfits) != 0 {
+		t.Fatalf("expected one linked order, got linked=%d sl=%v tp=%v", exchange.linked, exchange.stopLosses, exchange.takeProfits)
+	}
+	if len(at.protectionPairs) != 0 {
+		t.Errorf("native OCO pairs should not be tracked: %v", at.protectionPairs)
+	}
+}
+
+func TestCheckProtectionPairs_CancelsSiblingAfterClose(t *testing.T) {
+	exchange := &stubProtectionTrader{positions: []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "long"},
+		{"symbol": "BTCUSDT", "side": "short"},
+	}}
+	at := &AutoTrader{name: "test", trader: exchange}
+
+	at.setProtection("BTCUSDT", "LONG", 1, 90, 110)
+	at.setProtection("BTCUSDT", "SHORT", 1, 110, 90)
+	if len(at.protectionPairs) != 2 {
+		t.Fatalf("expected two emulated pairs, got %d", len(at.protectionPairs))
+	}
+
+	// Still open: nothing to cancel
+	at.checkProtectionPairs()
+	if len(exchange.cancelled) != 0 {
+		t.Fatalf("open positions must keep their orders, cancelled %v", exchange.cancelled)
+	}
+
+	// Long leg filled; pairs are past the grace period
+	for _, pair := range at.protectionPairs {
+		pair.PlacedAt = time.Now().Add(-protectionGracePeriod)
+	}
+	exchange.positions = exchange.positions[1:]
+	exchange.stopLosses, exchange.takeProfits = nil, nil
+	at.checkProtectionPairs()
+
+	if len(exchange.cancelled) != 1 || exchange.cancelled[0] != "BTCUSDT" {
+		t.Fatalf("expected remaining BTCUSDT orders to be cancelled, got %v", exchange.cancelled)
+	}
+	if _, ok := at.protectionPairs["BTCUSDT_long"]; ok {
+		t.Error("closed pair should no longer be tracked")
+	}
+	// Symbol-wide cancel also removed the short's orders, which must be placed again
+	if len(exchange.stopLosses) != 1 || exchange.stopLosses[0] != "BTCUSDT_SHORT" ||
+		len(exchange.takeProfits) != 1 || exchange.takeProfits[0] != "BTCUSDT_SHORT" {
+		t.Errorf("expected short protection to be restored, got sl=%v tp=%v", exchange.stopLosses, exchange.takeProfits)
+	}
+}
+
+func TestRestoreProtection_UsesCurrentQuantity(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+
+	exchange := &stubProtectionTrader{positions: []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "long", "positionAmt": 1.0},
+		{"symbol": "BTCUSDT", "side": "short", "positionAmt": -1.0},
+	}}
+	at := &AutoTrader{id: "trader-oco", name: "test", store: st, trader: exchange}
+	at.setProtection("BTCUSDT", "LONG", 1, 90, 110)
+	at.setProtection("BTCUSDT", "SHORT", 1, 110, 90)
+
+	// Restarted while the short was partly closed
+	exchange.positions[1]["positionAmt"] = -0.4
+	restarted := &AutoTrader{id: "trader-oco", name: "test", store: st, trader: exchange}
+	restarted.restoreProtection()
+	if len(restarted.protectionPairs) != 2 {
+		t.Fatalf("expected both pairs restored, got %d", len(restarted.protectionPairs))
+	}
+	if qty := restarted.protectionPairs["BTCUSDT_short"].Quantity; qty != 0.4 {
+		t.Errorf("restored short quantity = %v, want 0.4", qty)
+	}
+
+	// The long closes: its pair is forgotten for good, the short is placed again for what is left
+	for _, pair := range restarted.protectionPairs {
+		pair.PlacedAt = time.Now().Add(-protectionGracePeriod)
+	}
+	exchange.positions = exchange.positions[1:]
+	restarted.checkProtectionPairs()
+	saved, _ := st.Protection().List(at.id, store.ProtectionOCO)
+	if len(saved) != 1 || saved[0].Side != "SHORT" {
+		t.Fatalf("expected only the short pair stored, got %+v", saved)
+	}
+	pair := &protectionPair{}
+	if err := saved[0].Decode(pair); err != nil || pair.Quantity != 0.4 {
+		t.Errorf("stored short pair = %+v %v, want quantity 0.4", pair, err)
+	}
+}
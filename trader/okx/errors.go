@@ -0,0 +1,44 @@
+package okx
+
+import (
+	"fmt"
+	"nofx/trader/types"
+)
+
+// okxErrorClasses OKX API error codes by class
+var okxErrorClasses = map[string]types.ErrorClass{
+	"50011": types.ErrClassRateLimited, // Rate limit reached
+	"50061": types.ErrClassRateLimited, // Sub-account rate limit exceeded
+
+	"50100": types.ErrClassAuth, // API frozen
+	"50101": types.ErrClassAuth, // APIKey does not match current environment
+	"50103": types.ErrClassAuth, // OK-ACCESS-KEY header required
+	"50104": types.ErrClassAuth, // OK-ACCESS-PASSPHRASE header required
+	"50105": types.ErrClassAuth, // Incorrect passphrase
+	"50111": types.ErrClassAuth, // Invalid OK-ACCESS-KEY
+	"50113": types.ErrClassAuth, // Invalid signature
+	"50119": types.ErrClassAuth, // API key doesn't exist
+	"50120": types.ErrClassAuth, // API key doesn't have permission
+
+	"51008": types.ErrClassInsufficientMargin, // Insufficient balance or margin
+	"51127": types.ErrClassInsufficientMargin, // Available balance is 0
+	"51131": types.ErrClassInsufficientMargin, // Insufficient balance
+
+	"51001": types.ErrClassInvalidSymbol, // Instrument ID does not exist
+	"51021": types.ErrClassInvalidSymbol, // Instrument is not listed yet
+
+	"50001": types.ErrClassNetwork, // Service temporarily unavailable
+	"50004": types.ErrClassNetwork, // Endpoint request timeout
+	"50013": types.ErrClassNetwork, // System is busy
+	"50026": types.ErrClassNetwork, // System error
+	"50102": types.ErrClassNetwork, // Timestamp request expired
+}
+
+// apiError tags an OKX API error response with its class
+func apiError(code, msg string) error {
+	class, ok := okxErrorClasses[code]
+	if !ok {
+		class = types.ErrClassUnknown
+	}
+	return types.NewExchangeError(class, code, fmt.Errorf("OKX API error: code=%s, msg=%s", code, msg))
+}
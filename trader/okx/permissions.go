@@ -0,0 +1,38 @@
+package okx
+
+import (
+	"encoding/json"
+	"fmt"
+	"nofx/trader/types"
+	"strings"
+)
+
+// GetAPIKeyPermissions gets the permissions of the API key (PermissionTrader implementation)
+// OKX reports them in the account config as "read_only,trade,withdraw".
+func (t *OKXTrader) GetAPIKeyPermissions() (*types.APIKeyPermissions, error) {
+	data, err := t.doRequest("GET", okxAccountConfigPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account config: %w", err)
+	}
+
+	var configs []struct {
+		Perm string `json:"perm"`
+	}
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse account config: %w", err)
+	}
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("empty account config")
+	}
+
+	perms := &types.APIKeyPermissions{}
+	for _, perm := range strings.Split(configs[0].Perm, ",") {
+		switch strings.TrimSpace(perm) {
+		case "trade":
+			perms.FuturesTrading = true
+		case "withdraw":
+			perms.Withdrawal = true
+		}
+	}
+	return perms, nil
+}
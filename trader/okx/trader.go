@@ -12,6 +12,7 @@ import (
 	"io"
 	"net/http"
 	"nofx/logger"
+	"nofx/trader/endpoint"
 	"strconv"
 	"strings"
 	"sync"
@@ -21,7 +22,6 @@ import (
 
 // OKX API endpoints
 const (
-	okxBaseURL           = "https://www.okx.com"
 	okxAccountPath       = "/api/v5/account/balance"
 	okxPositionPath      = "/api/v5/account/positions"
 	okxOrderPath         = "/api/v5/trade/order"
@@ -104,6 +104,19 @@ func genOkxClOrdID() string {
 	return orderID
 }
 
+// okxClOrdIDForKey generates a deterministic OKX order ID for an idempotent submission
+// Falls back to a random ID if key is unset
+func okxClOrdIDForKey(key types.ClientOrderKey) string {
+	if key.IsZero() {
+		return genOkxClOrdID()
+	}
+	orderID := okxTag + key.ID()
+	if len(orderID) > 32 {
+		orderID = orderID[:32]
+	}
+	return orderID
+}
+
 // NewOKXTrader creates OKX trader
 func NewOKXTrader(apiKey, secretKey, passphrase string) *OKXTrader {
 	// Use default transport which respects system proxy settings
@@ -205,7 +218,7 @@ func (t *OKXTrader) doRequest(method, path string, body interface{}) ([]byte, er
 	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
 	signature := t.sign(timestamp, method, path, string(bodyBytes))
 
-	req, err := http.NewRequest(method, okxBaseURL+path, bytes.NewReader(bodyBytes))
+	req, err := http.NewRequest(method, endpoint.BaseURL(endpoint.OKX)+path, bytes.NewReader(bodyBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -231,13 +244,14 @@ func (t *OKXTrader) doRequest(method, path string, body interface{}) ([]byte, er
 
 	var okxResp OKXResponse
 	if err := json.Unmarshal(respBody, &okxResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		// Non-JSON body (gateway error page): classify by HTTP status
+		return nil, types.NewExchangeError(types.HTTPStatusClass(resp.StatusCode), "", fmt.Errorf("failed to parse response: %w", err))
 	}
 
 	// code=1 indicates partial success, need to check specific results in data
 	// code=2 indicates complete failure
 	if okxResp.Code != "0" && okxResp.Code != "1" {
-		return nil, fmt.Errorf("OKX API error: code=%s, msg=%s", okxResp.Code, okxResp.Msg)
+		return nil, apiError(okxResp.Code, okxResp.Msg)
 	}
 
 	return okxResp.Data, nil
@@ -576,6 +590,11 @@ func (t *OKXTrader) SetLeverage(symbol string, leverage int) error {
 
 // OpenLong opens long position
 func (t *OKXTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenLongWithClientID(symbol, quantity, leverage, types.ClientOrderKey{})
+}
+
+// OpenLongWithClientID opens long position with a deterministic client order ID
+func (t *OKXTrader) OpenLongWithClientID(symbol string, quantity float64, leverage int, key types.ClientOrderKey) (map[string]interface{}, error) {
 	// Cancel old orders
 	t.CancelAllOrders(symbol)
 
@@ -613,7 +632,7 @@ func (t *OKXTrader) OpenLong(symbol string, quantity float64, leverage int) (map
 		"posSide": "long",
 		"ordType": "market",
 		"sz":      szStr,
-		"clOrdId": genOkxClOrdID(),
+		"clOrdId": okxClOrdIDForKey(key),
 		"tag":     okxTag,
 	}
 
@@ -653,6 +672,11 @@ func (t *OKXTrader) OpenLong(symbol string, quantity float64, leverage int) (map
 
 // OpenShort opens short position
 func (t *OKXTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return t.OpenShortWithClientID(symbol, quantity, leverage, types.ClientOrderKey{})
+}
+
+// OpenShortWithClientID opens short position with a deterministic client order ID
+func (t *OKXTrader) OpenShortWithClientID(symbol string, quantity float64, leverage int, key types.ClientOrderKey) (map[string]interface{}, error) {
 	// Cancel old orders
 	t.CancelAllOrders(symbol)
 
@@ -690,7 +714,7 @@ func (t *OKXTrader) OpenShort(symbol string, quantity float64, leverage int) (ma
 		"posSide": "short",
 		"ordType": "market",
 		"sz":      szStr,
-		"clOrdId": genOkxClOrdID(),
+		"clOrdId": okxClOrdIDForKey(key),
 		"tag":     okxTag,
 	}
 
@@ -728,6 +752,40 @@ func (t *OKXTrader) OpenShort(symbol string, quantity float64, leverage int) (ma
 	}, nil
 }
 
+// FindOrderByClientID looks up an order submitted with the given client order key
+func (t *OKXTrader) FindOrderByClientID(symbol string, key types.ClientOrderKey) (map[string]interface{}, error) {
+	instId := t.convertSymbol(symbol)
+	clOrdId := okxClOrdIDForKey(key)
+	path := fmt.Sprintf("/api/v5/trade/order?instId=%s&clOrdId=%s", instId, clOrdId)
+
+	data, err := t.doRequest("GET", path, nil)
+	if err != nil {
+		// 51603: Order does not exist
+		if strings.Contains(err.Error(), "51603") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query order by client ID: %w", err)
+	}
+
+	var orders []struct {
+		OrdId string `json:"ordId"`
+		State string `json:"state"`
+	}
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return nil, fmt.Errorf("failed to parse order response: %w", err)
+	}
+	if len(orders) == 0 {
+		return nil, nil
+	}
+
+	return map[string]interface{}{
+		"orderId":       orders[0].OrdId,
+		"symbol":        symbol,
+		"status":        orders[0].State,
+		"clientOrderId": clOrdId,
+	}, nil
+}
+
 // CloseLong closes long position
 func (t *OKXTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
 	instId := t.convertSymbol(symbol)
@@ -1280,6 +1338,85 @@ var okxTag = func() string {
 	return string(b)
 }()
 
+// GetFundingHistory retrieves funding fee bills from OKX
+// OKX API: /api/v5/account/bills (type=8 funding fee)
+func (t *OKXTrader) GetFundingHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	return t.getBills("SWAP", "8", types.IncomeTypeFunding, startTime, limit)
+}
+
+// GetCommissionHistory retrieves trading fees from OKX trade bills
+// OKX API: /api/v5/account/bills (type=2 trade)
+func (t *OKXTrader) GetCommissionHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	return t.getBills("SWAP", "2", types.IncomeTypeCommission, startTime, limit)
+}
+
+// GetTransferHistory retrieves transfers into and out of the trading account from OKX
+// OKX API: /api/v5/account/bills (type=1 transfer, not tied to an instrument type)
+func (t *OKXTrader) GetTransferHistory(startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	return t.getBills("", "1", types.IncomeTypeTransfer, startTime, limit)
+}
+
+// getBills queries account bills of one bill type (last 7 days), instType "" for all instrument types
+func (t *OKXTrader) getBills(instType, billType, recordType string, startTime time.Time, limit int) ([]types.IncomeRecord, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	path := fmt.Sprintf("/api/v5/account/bills?type=%s&limit=%d", billType, limit)
+	if instType != "" {
+		path += "&instType=" + instType
+	}
+	if !startTime.IsZero() {
+		path += fmt.Sprintf("&begin=%d", startTime.UnixMilli())
+	}
+
+	data, err := t.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bills: %w", err)
+	}
+
+	var resp struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			BillID string `json:"billId"`
+			InstID string `json:"instId"`
+			BalChg string `json:"balChg"` // Balance change (funding / transfer amount)
+			Fee    string `json:"fee"`    // Negative = charged, positive = rebate
+			Ccy    string `json:"ccy"`
+			Ts     string `json:"ts"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if resp.Code != "0" {
+		return nil, fmt.Errorf("OKX API error: %s - %s", resp.Code, resp.Msg)
+	}
+
+	records := make([]types.IncomeRecord, 0, len(resp.Data))
+	for _, bill := range resp.Data {
+		var amount float64
+		if recordType != types.IncomeTypeCommission {
+			amount, _ = strconv.ParseFloat(bill.BalChg, 64)
+		} else {
+			amount, _ = strconv.ParseFloat(bill.Fee, 64)
+		}
+		if amount == 0 {
+			continue
+		}
+		ts, _ := strconv.ParseInt(bill.Ts, 10, 64)
+		records = append(records, types.IncomeRecord{
+			TranID: bill.BillID,
+			Symbol: t.convertSymbolBack(bill.InstID),
+			Type:   recordType,
+			Amount: amount,
+			Asset:  bill.Ccy,
+			Time:   time.UnixMilli(ts).UTC(),
+		})
+	}
+	return records, nil
+}
+
 // GetClosedPnL retrieves closed position PnL records from OKX
 // OKX API: /api/v5/account/positions-history
 func (t *OKXTrader) GetClosedPnL(startTime time.Time, limit int) ([]types.ClosedPnLRecord, error) {
@@ -0,0 +1,582 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"nofx/logger"
+	"nofx/store"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// paperFeeRate Simulated taker fee rate applied to every paper fill
+const paperFeeRate = 0.0005
+
+// paperTrader routes orders of a trader in paper mode to simulated fills at mark price.
+// Market data and symbol precision still come from the real exchange; positions and balance
+// are computed from the paper_positions table, so the real account is never touched.
+type paperTrader struct {
+	exchange       Trader // Real exchange trader (read-only use: prices, precision)
+	paper          *store.PaperStore
+	traderID       string
+	initialBalance float64
+	mu             sync.Mutex
+}
+
+// newPaperTrader wraps the real exchange trader for paper mode
+func newPaperTrader(exchange Trader, paper *store.PaperStore, traderID string, initialBalance float64) *paperTrader {
+	return &paperTrader{
+		exchange:       exchange,
+		paper:          paper,
+		traderID:       traderID,
+		initialBalance: initialBalance,
+	}
+}
+
+// GetBalance returns the simulated account: initial balance plus net realized PnL and open PnL
+func (p *paperTrader) GetBalance() (map[string]interface{}, error) {
+	positions, err := p.GetPositions()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	summary, err := p.paper.Summary(p.traderID)
+	p.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paper trading summary: %w", err)
+	}
+
+	unrealized := 0.0
+	marginUsed := 0.0
+	for _, pos := range positions {
+		unrealized += pos["unRealizedProfit"].(float64)
+		qty := math.Abs(pos["positionAmt"].(float64))
+		marginUsed += qty * pos["entryPrice"].(float64) / math.Max(pos["leverage"].(float64), 1)
+	}
+
+	wallet := p.initialBalance + summary.RealizedPnL - summary.TotalFee - summary.OpenFee
+	return map[string]interface{}{
+		"totalWalletBalance":    wallet,
+		"availableBalance":      wallet + unrealized - marginUsed,
+		"totalUnrealizedProfit": unrealized,
+		"totalEquity":           wallet + unrealized,
+	}, nil
+}
+
+// GetPositions returns open simulated positions priced at current mark price.
+// It only reads: stop loss, take profit and liquidation are applied by settle.
+func (p *paperTrader) GetPositions() ([]map[string]interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	open, err := p.paper.ListOpen(p.traderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paper positions: %w", err)
+	}
+
+	var result []map[string]interface{}
+	for _, pos := range open {
+		price, err := p.exchange.GetMarketPrice(pos.Symbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get mark price for %s: %w", pos.Symbol, err)
+		}
+
+		amt := pos.Quantity
+		if pos.Side == "SHORT" {
+			amt = -amt
+		}
+		result = append(result, map[string]interface{}{
+			"symbol":           pos.Symbol,
+			"side":             strings.ToLower(pos.Side),
+			"positionAmt":      amt,
+			"entryPrice":       pos.EntryPrice,
+			"markPrice":        price,
+			"unRealizedProfit": paperPnL(pos.Side, pos.EntryPrice, price, pos.Quantity),
+			"leverage":         float64(pos.Leverage),
+			"liquidationPrice": paperLiquidationPrice(pos),
+			"createdTime":      pos.EntryTime,
+		})
+	}
+	return result, nil
+}
+
+// OpenLong simulates a market buy at mark price
+func (p *paperTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return p.open(symbol, "LONG", quantity, leverage)
+}
+
+// OpenShort simulates a market sell at mark price
+func (p *paperTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return p.open(symbol, "SHORT", quantity, leverage)
+}
+
+// CloseLong closes a simulated long position (quantity=0 means close all)
+func (p *paperTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	return p.close(symbol, "LONG", quantity)
+}
+
+// CloseShort closes a simulated short position (quantity=0 means close all)
+func (p *paperTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return p.close(symbol, "SHORT", quantity)
+}
+
+func (p *paperTrader) open(symbol, side string, quantity float64, leverage int) (map[string]interface{}, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("invalid quantity: %f", quantity)
+	}
+	if leverage <= 0 {
+		leverage = 1
+	}
+	// Round like the exchange would, so simulated sizes match what a real order would fill
+	if formatted, err := p.exchange.FormatQuantity(symbol, quantity); err == nil {
+		if q, err := strconv.ParseFloat(formatted, 64); err == nil && q > 0 {
+			quantity = q
+		}
+	}
+	price, err := p.exchange.GetMarketPrice(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mark price: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	id, err := p.openLocked(symbol, side, quantity, price, leverage)
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("  📝 [paper] Open %s %s qty=%.6f at %.6f (%dx)", side, symbol, quantity, price, leverage)
+	return paperOrderResult(id, symbol, price, quantity), nil
+}
+
+// openLocked opens or adds to the simulated position at price, returns the position ID
+func (p *paperTrader) openLocked(symbol, side string, quantity, price float64, leverage int) (int64, error) {
+	fee := quantity * price * paperFeeRate
+	existing, err := p.findOpenLocked(symbol, side)
+	if err != nil {
+		return 0, err
+	}
+	if existing != nil {
+		if err := p.paper.Increase(existing.ID, quantity, price, fee); err != nil {
+			return 0, fmt.Errorf("failed to add to paper position: %w", err)
+		}
+		return existing.ID, nil
+	}
+	pos := &store.PaperPosition{
+		TraderID:   p.traderID,
+		Symbol:     symbol,
+		Side:       side,
+		Quantity:   quantity,
+		EntryPrice: price,
+		Leverage:   leverage,
+		Fee:        fee,
+	}
+	if err := p.paper.Open(pos); err != nil {
+		return 0, fmt.Errorf("failed to record paper position: %w", err)
+	}
+	return pos.ID, nil
+}
+
+func (p *paperTrader) close(symbol, side string, quantity float64) (map[string]interface{}, error) {
+	price, err := p.exchange.GetMarketPrice(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mark price: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pos, err := p.findOpenLocked(symbol, side)
+	if err != nil {
+		return nil, err
+	}
+	if pos == nil {
+		return nil, fmt.Errorf("no %s position for %s", strings.ToLower(side), symbol)
+	}
+	closedQty, err := p.closeLocked(pos, quantity, price, "manual")
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("  📝 [paper] Close %s %s qty=%.6f at %.6f", side, symbol, closedQty, price)
+	return paperOrderResult(pos.ID, symbol, price, closedQty), nil
+}
+
+// closeLocked closes quantity of pos at price (0 or more than held closes all), returns closed quantity
+func (p *paperTrader) closeLocked(pos *store.PaperPosition, quantity, price float64, reason string) (float64, error) {
+	if quantity <= 0 || quantity > pos.Quantity {
+		quantity = pos.Quantity
+	}
+	entryFee := pos.Fee * quantity / pos.Quantity
+	fee := entryFee + quantity*price*paperFeeRate
+	pnl := paperPnL(pos.Side, pos.EntryPrice, price, quantity)
+	if err := p.paper.Close(pos.ID, quantity, price, pnl, fee, reason); err != nil {
+		return 0, fmt.Errorf("failed to close paper position: %w", err)
+	}
+	return quantity, nil
+}
+
+func (p *paperTrader) findOpenLocked(symbol, side string) (*store.PaperPosition, error) {
+	open, err := p.paper.ListOpen(p.traderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paper positions: %w", err)
+	}
+	for _, pos := range open {
+		if pos.Symbol == symbol && pos.Side == side {
+			return pos, nil
+		}
+	}
+	return nil, nil
+}
+
+// SetLeverage is a no-op in paper mode (leverage is recorded on each simulated position)
+func (p *paperTrader) SetLeverage(symbol string, leverage int) error {
+	return nil
+}
+
+// SetMarginMode is a no-op in paper mode
+func (p *paperTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	return nil
+}
+
+// GetMarketPrice returns the real exchange mark price
+func (p *paperTrader) GetMarketPrice(symbol string) (float64, error) {
+	return p.exchange.GetMarketPrice(symbol)
+}
+
+// SetStopLoss stores the stop price on the simulated position
+func (p *paperTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	return p.setProtection(symbol, positionSide, &stopPrice, nil)
+}
+
+// SetTakeProfit stores the take-profit price on the simulated position
+func (p *paperTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	return p.setProtection(symbol, positionSide, nil, &takeProfitPrice)
+}
+
+// SetStopLossTakeProfit stores both prices on the simulated position; the first to trigger closes it
+func (p *paperTrader) SetStopLossTakeProfit(symbol string, positionSide string, quantity, stopPrice, takeProfitPrice float64) error {
+	return p.setProtection(symbol, positionSide, &stopPrice, &takeProfitPrice)
+}
+
+// CancelStopLossOrders clears stop prices of the symbol's simulated positions
+func (p *paperTrader) CancelStopLossOrders(symbol string) error {
+	zero := 0.0
+	return p.clearProtection(symbol, &zero, nil)
+}
+
+// CancelTakeProfitOrders clears take-profit prices of the symbol's simulated positions
+func (p *paperTrader) CancelTakeProfitOrders(symbol string) error {
+	zero := 0.0
+	return p.clearProtection(symbol, nil, &zero)
+}
+
+// CancelAllOrders clears stop and take-profit prices of the symbol's simulated positions
+func (p *paperTrader) CancelAllOrders(symbol string) error {
+	zero := 0.0
+	return p.clearProtection(symbol, &zero, &zero)
+}
+
+// CancelStopOrders clears stop and take-profit prices of the symbol's simulated positions
+func (p *paperTrader) CancelStopOrders(symbol string) error {
+	return p.CancelAllOrders(symbol)
+}
+
+func (p *paperTrader) setProtection(symbol, positionSide string, stopLoss, takeProfit *float64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pos, err := p.findOpenLocked(symbol, strings.ToUpper(positionSide))
+	if err != nil {
+		return err
+	}
+	if pos == nil {
+		return fmt.Errorf("no %s position for %s", strings.ToLower(positionSide), symbol)
+	}
+	return p.paper.UpdateProtection(pos.ID, stopLoss, takeProfit)
+}
+
+func (p *paperTrader) clearProtection(symbol string, stopLoss, takeProfit *float64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	open, err := p.paper.ListOpen(p.traderID)
+	if err != nil {
+		return fmt.Errorf("failed to get paper positions: %w", err)
+	}
+	for _, pos := range open {
+		if pos.Symbol != symbol {
+			continue
+		}
+		if err := p.paper.UpdateProtection(pos.ID, stopLoss, takeProfit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatQuantity uses the real exchange precision
+func (p *paperTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	return p.exchange.FormatQuantity(symbol, quantity)
+}
+
+// GetOrderStatus reports the state of a simulated limit order; market orders fill immediately
+func (p *paperTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	id, ok := paperLimitOrderID(orderID)
+	if !ok {
+		return map[string]interface{}{"orderId": orderID, "status": "FILLED"}, nil
+	}
+	order, err := p.paper.GetOrder(p.traderID, id)
+	if err != nil {
+		return nil, err
+	}
+	status := map[string]interface{}{"orderId": orderID, "status": order.Status, "executedQty": 0.0, "avgPrice": 0.0}
+	if order.Status == store.PaperOrderFilled {
+		status["executedQty"], status["avgPrice"] = order.Quantity, order.Price
+	}
+	return status, nil
+}
+
+// PlaceLimitOrder rests a simulated limit entry order, filled by settle once the mark price reaches it
+func (p *paperTrader) PlaceLimitOrder(req *LimitOrderRequest) (*LimitOrderResult, error) {
+	if req.ReduceOnly {
+		return nil, fmt.Errorf("reduce-only limit orders are not simulated in paper mode")
+	}
+	if req.Quantity <= 0 || req.Price <= 0 {
+		return nil, fmt.Errorf("invalid limit order: quantity %f at %f", req.Quantity, req.Price)
+	}
+	side := strings.ToUpper(req.PositionSide)
+	if side == "" {
+		side = "SHORT"
+		if strings.ToUpper(req.Side) == "BUY" {
+			side = "LONG"
+		}
+	}
+	quantity := req.Quantity
+	if formatted, err := p.exchange.FormatQuantity(req.Symbol, quantity); err == nil {
+		if q, err := strconv.ParseFloat(formatted, 64); err == nil && q > 0 {
+			quantity = q
+		}
+	}
+	leverage := req.Leverage
+	if leverage <= 0 {
+		leverage = 1
+	}
+
+	order := &store.PaperOrder{TraderID: p.traderID, Symbol: req.Symbol, Side: side, Price: req.Price, Quantity: quantity, Leverage: leverage}
+	if err := p.paper.CreateOrder(order); err != nil {
+		return nil, fmt.Errorf("failed to record paper limit order: %w", err)
+	}
+	logger.Infof("  📝 [paper] Limit %s %s qty=%.6f at %.6f", side, req.Symbol, quantity, req.Price)
+	return &LimitOrderResult{
+		OrderID:      fmt.Sprintf("%s%d", paperLimitOrderPrefix, order.ID),
+		ClientID:     req.ClientID,
+		Symbol:       req.Symbol,
+		Side:         req.Side,
+		PositionSide: side,
+		Price:        req.Price,
+		Quantity:     quantity,
+		Status:       store.PaperOrderNew,
+	}, nil
+}
+
+// CancelOrder cancels a resting simulated limit order
+func (p *paperTrader) CancelOrder(symbol, orderID string) error {
+	id, ok := paperLimitOrderID(orderID)
+	if !ok {
+		return fmt.Errorf("paper order %s is not a resting limit order", orderID)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cancelled, err := p.paper.FinishOrder(id, store.PaperOrderCanceled)
+	if err != nil {
+		return fmt.Errorf("failed to cancel paper limit order: %w", err)
+	}
+	if !cancelled {
+		return fmt.Errorf("paper order %s is no longer open", orderID)
+	}
+	return nil
+}
+
+// GetOrderBook uses the real exchange book where available, else the mark price on both sides
+func (p *paperTrader) GetOrderBook(symbol string, depth int) ([][]float64, [][]float64, error) {
+	if book, ok := p.exchange.(GridTrader); ok {
+		return book.GetOrderBook(symbol, depth)
+	}
+	price, err := p.exchange.GetMarketPrice(symbol)
+	if err != nil {
+		return nil, nil, err
+	}
+	return [][]float64{{price, 0}}, [][]float64{{price, 0}}, nil
+}
+
+// settle fills resting limit orders the mark price has reached (at their limit price) and closes
+// positions past their stop loss, take profit or liquidation price. The trader runs it on its own
+// schedule, so reading positions or balance never changes the simulated account.
+func (p *paperTrader) settle() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	orders, err := p.paper.ListOpenOrders(p.traderID)
+	if err != nil {
+		return fmt.Errorf("failed to get paper orders: %w", err)
+	}
+	for _, order := range orders {
+		price, err := p.exchange.GetMarketPrice(order.Symbol)
+		if err != nil {
+			return fmt.Errorf("failed to get mark price for %s: %w", order.Symbol, err)
+		}
+		if order.Side == "LONG" && price > order.Price || order.Side == "SHORT" && price < order.Price {
+			continue
+		}
+		if filled, err := p.paper.FinishOrder(order.ID, store.PaperOrderFilled); err != nil || !filled {
+			continue
+		}
+		if _, err := p.openLocked(order.Symbol, order.Side, order.Quantity, order.Price, order.Leverage); err != nil {
+			return err
+		}
+		logger.Infof("  📝 [paper] Limit %s %s filled qty=%.6f at %.6f", order.Side, order.Symbol, order.Quantity, order.Price)
+	}
+
+	open, err := p.paper.ListOpen(p.traderID)
+	if err != nil {
+		return fmt.Errorf("failed to get paper positions: %w", err)
+	}
+	for _, pos := range open {
+		price, err := p.exchange.GetMarketPrice(pos.Symbol)
+		if err != nil {
+			return fmt.Errorf("failed to get mark price for %s: %w", pos.Symbol, err)
+		}
+		reason := paperTriggerReason(pos, price)
+		if reason == "" {
+			continue
+		}
+		if _, err := p.closeLocked(pos, 0, price, reason); err != nil {
+			return err
+		}
+		logger.Infof("  📝 [paper] %s %s closed by %s at %.6f", pos.Symbol, pos.Side, reason, price)
+	}
+	return nil
+}
+
+// settlePaper applies simulated fills and triggers of a trader in paper mode
+func (at *AutoTrader) settlePaper() {
+	paper, ok := at.trader.(*paperTrader)
+	if !ok {
+		return
+	}
+	if err := paper.settle(); err != nil {
+		logger.Infof("⚠️ [%s] Paper mode: %v", at.name, err)
+	}
+}
+
+// paperLimitOrderPrefix order ID prefix of simulated limit orders
+const paperLimitOrderPrefix = "paper-limit-"
+
+// paperLimitOrderID parses the paper_orders ID of a simulated limit order ID
+func paperLimitOrderID(orderID string) (int64, bool) {
+	if !strings.HasPrefix(orderID, paperLimitOrderPrefix) {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(orderID, paperLimitOrderPrefix), 10, 64)
+	return id, err == nil
+}
+
+// GetClosedPnL returns no exchange records: paper closes are recorded in paper_positions directly
+func (p *paperTrader) GetClosedPnL(startTime time.Time, limit int) ([]ClosedPnLRecord, error) {
+	return nil, nil
+}
+
+// GetOpenOrders lists stop loss and take profit of simulated positions as pending orders
+func (p *paperTrader) GetOpenOrders(symbol string) ([]OpenOrder, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	open, err := p.paper.ListOpen(p.traderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paper positions: %w", err)
+	}
+	var orders []OpenOrder
+	for _, pos := range open {
+		if symbol != "" && pos.Symbol != symbol {
+			continue
+		}
+		closeSide := "SELL"
+		if pos.Side == "SHORT" {
+			closeSide = "BUY"
+		}
+		if pos.StopLoss > 0 {
+			orders = append(orders, OpenOrder{
+				OrderID: fmt.Sprintf("paper-%d-sl", pos.ID), Symbol: pos.Symbol, Side: closeSide, PositionSide: pos.Side,
+				Type: "STOP_MARKET", StopPrice: pos.StopLoss, Quantity: pos.Quantity, Status: "NEW",
+			})
+		}
+		if pos.TakeProfit > 0 {
+			orders = append(orders, OpenOrder{
+				OrderID: fmt.Sprintf("paper-%d-tp", pos.ID), Symbol: pos.Symbol, Side: closeSide, PositionSide: pos.Side,
+				Type: "TAKE_PROFIT_MARKET", StopPrice: pos.TakeProfit, Quantity: pos.Quantity, Status: "NEW",
+			})
+		}
+	}
+	return orders, nil
+}
+
+// paperOrderResult builds an order result in the shape exchange traders return
+func paperOrderResult(positionID int64, symbol string, price, quantity float64) map[string]interface{} {
+	return map[string]interface{}{
+		"orderId":     fmt.Sprintf("paper-%d-%d", positionID, time.Now().UnixMilli()),
+		"symbol":      symbol,
+		"status":      "FILLED",
+		"avgPrice":    price,
+		"executedQty": quantity,
+		"paper":       true,
+	}
+}
+
+// paperPnL PnL of quantity moved from entry to price
+func paperPnL(side string, entry, price, quantity float64) float64 {
+	if side == "SHORT" {
+		return (entry - price) * quantity
+	}
+	return (price - entry) * quantity
+}
+
+// paperLiquidationPrice approximates liquidation as the price where loss equals initial margin
+func paperLiquidationPrice(pos *store.PaperPosition) float64 {
+	if pos.Leverage <= 0 {
+		return 0
+	}
+	if pos.Side == "SHORT" {
+		return pos.EntryPrice * (1 + 1/float64(pos.Leverage))
+	}
+	return pos.EntryPrice * (1 - 1/float64(pos.Leverage))
+}
+
+// paperTriggerReason returns the close reason if price crossed the position's stop loss,
+// take profit or liquidation price, empty otherwise
+func paperTriggerReason(pos *store.PaperPosition, price float64) string {
+	liq := paperLiquidationPrice(pos)
+	if pos.Side == "SHORT" {
+		switch {
+		case liq > 0 && price >= liq:
+			return "liquidation"
+		case pos.StopLoss > 0 && price >= pos.StopLoss:
+			return "stop_loss"
+		case pos.TakeProfit > 0 && price <= pos.TakeProfit:
+			return "take_profit"
+		}
+		return ""
+	}
+	switch {
+	case liq > 0 && price <= liq:
+		return "liquidation"
+	case pos.StopLoss > 0 && price <= pos.StopLoss:
+		return "stop_loss"
+	case pos.TakeProfit > 0 && price >= pos.TakeProfit:
+		return "take_profit"
+	}
+	return ""
+}
@@ -0,0 +1,168 @@
+package trader
+
+import (
+	"errors"
+	"math"
+	"nofx/store"
+	"path/filepath"
+	"testing"
+)
+
+// stubPriceTrader serves mark prices; any order call would panic on the nil embedded Trader
+type stubPriceTrader struct {
+	Trader
+	prices map[string]float64
+}
+
+func (s *stubPriceTrader) GetMarketPrice(symbol string) (float64, error) {
+	return s.prices[symbol], nil
+}
+
+func (s *stubPriceTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	return "", errors.New("no precision")
+}
+
+func newPaperTestTrader(t *testing.T) (*paperTrader, *stubPriceTrader, *store.Store) {
+	t.Helper()
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	exchange := &stubPriceTrader{prices: map[string]float64{"BTCUSDT": 100}}
+	return newPaperTrader(exchange, st.Paper(), "paper_trader", 1000), exchange, st
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestPaperTrader_FillsAtMarkPrice(t *testing.T) {
+	p, exchange, st := newPaperTestTrader(t)
+
+	if _, err := p.OpenLong("BTCUSDT", 2, 5); err != nil {
+		t.Fatalf("OpenLong: %v", err)
+	}
+	exchange.prices["BTCUSDT"] = 110
+
+	positions, err := p.GetPositions()
+	if err != nil || len(positions) != 1 {
+		t.Fatalf("expected one position, got %v (%v)", positions, err)
+	}
+	if pnl := positions[0]["unRealizedProfit"].(float64); !approxEqual(pnl, 20) {
+		t.Errorf("unrealized PnL = %f, want 20", pnl)
+	}
+
+	// Partial close keeps the remainder open
+	if _, err := p.CloseLong("BTCUSDT", 1); err != nil {
+		t.Fatalf("CloseLong: %v", err)
+	}
+	balance, err := p.GetBalance()
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	entryFee := 2 * 100 * paperFeeRate
+	exitFee := 1 * 110 * paperFeeRate
+	wantWallet := 1000 + 10 - entryFee - exitFee
+	if got := balance["totalWalletBalance"].(float64); !approxEqual(got, wantWallet) {
+		t.Errorf("wallet = %f, want %f", got, wantWallet)
+	}
+	if got := balance["totalEquity"].(float64); !approxEqual(got, wantWallet+10) {
+		t.Errorf("equity = %f, want %f", got, wantWallet+10)
+	}
+
+	summary, _ := st.Paper().Summary("paper_trader")
+	if summary.OpenPositions != 1 || summary.ClosedTrades != 1 || summary.WinTrades != 1 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestPaperTrader_StopLossTriggers(t *testing.T) {
+	p, exchange, st := newPaperTestTrader(t)
+
+	if _, err := p.OpenShort("BTCUSDT", 1, 10); err != nil {
+		t.Fatalf("OpenShort: %v", err)
+	}
+	if err := p.SetStopLoss("BTCUSDT", "SHORT", 1, 105); err != nil {
+		t.Fatalf("SetStopLoss: %v", err)
+	}
+	orders, _ := p.GetOpenOrders("BTCUSDT")
+	if len(orders) != 1 || orders[0].Type != "STOP_MARKET" || orders[0].Side != "BUY" {
+		t.Fatalf("unexpected open orders: %+v", orders)
+	}
+
+	exchange.prices["BTCUSDT"] = 106
+	// Reading positions alone never closes them
+	if positions, err := p.GetPositions(); err != nil || len(positions) != 1 {
+		t.Fatalf("GetPositions should not trigger the stop, got %v (%v)", positions, err)
+	}
+	if err := p.settle(); err != nil {
+		t.Fatalf("settle: %v", err)
+	}
+	positions, err := p.GetPositions()
+	if err != nil || len(positions) != 0 {
+		t.Fatalf("stop loss should close the short, got %v (%v)", positions, err)
+	}
+
+	closed, _ := st.Paper().List("paper_trader", 10)
+	if len(closed) != 1 || closed[0].CloseReason != "stop_loss" || !approxEqual(closed[0].RealizedPnL, -6) {
+		t.Fatalf("unexpected closed position: %+v", closed[0])
+	}
+}
+
+func TestPaperTrader_RestingLimitOrder(t *testing.T) {
+	p, exchange, st := newPaperTestTrader(t)
+
+	result, err := p.PlaceLimitOrder(&LimitOrderRequest{Symbol: "BTCUSDT", Side: "BUY", PositionSide: "LONG", Price: 95, Quantity: 2, Leverage: 5})
+	if err != nil {
+		t.Fatalf("PlaceLimitOrder: %v", err)
+	}
+	if result.Status != "NEW" {
+		t.Fatalf("status = %s, want NEW", result.Status)
+	}
+
+	// Above the limit price the order keeps resting
+	if err := p.settle(); err != nil {
+		t.Fatalf("settle: %v", err)
+	}
+	status, _ := p.GetOrderStatus("BTCUSDT", result.OrderID)
+	if status["status"] != store.PaperOrderNew {
+		t.Fatalf("order should still rest, got %v", status)
+	}
+
+	exchange.prices["BTCUSDT"] = 94
+	if err := p.settle(); err != nil {
+		t.Fatalf("settle: %v", err)
+	}
+	status, _ = p.GetOrderStatus("BTCUSDT", result.OrderID)
+	if status["status"] != store.PaperOrderFilled || status["executedQty"] != 2.0 || status["avgPrice"] != 95.0 {
+		t.Fatalf("unexpected fill status: %v", status)
+	}
+	open, _ := st.Paper().ListOpen("paper_trader")
+	if len(open) != 1 || open[0].Side != "LONG" || open[0].EntryPrice != 95 || open[0].Quantity != 2 {
+		t.Fatalf("unexpected position: %+v", open)
+	}
+	if err := p.CancelOrder("BTCUSDT", result.OrderID); err == nil {
+		t.Error("cancelling a filled order should fail")
+	}
+
+	// A cancelled order never fills
+	short, err := p.PlaceLimitOrder(&LimitOrderRequest{Symbol: "BTCUSDT", Side: "SELL", Price: 120, Quantity: 1})
+	if err != nil {
+		t.Fatalf("PlaceLimitOrder: %v", err)
+	}
+	if err := p.CancelOrder("BTCUSDT", short.OrderID); err != nil {
+		t.Fatalf("CancelOrder: %v", err)
+	}
+	exchange.prices["BTCUSDT"] = 125
+	if err := p.settle(); err != nil {
+		t.Fatalf("settle: %v", err)
+	}
+	status, _ = p.GetOrderStatus("BTCUSDT", short.OrderID)
+	if status["status"] != store.PaperOrderCanceled {
+		t.Fatalf("unexpected status after cancel: %v", status)
+	}
+	if open, _ := st.Paper().ListOpen("paper_trader"); len(open) != 1 {
+		t.Fatalf("cancelled order opened a position: %+v", open)
+	}
+}
@@ -0,0 +1,143 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/logger"
+	"nofx/store"
+	"strings"
+)
+
+// stopTriggerTolerance a position gone while the price is within this fraction of its stop loss
+// (or past it) is reported as stopped out
+const stopTriggerTolerance = 0.005
+
+// trackedPosition an open position as last seen by the drawdown monitor
+type trackedPosition struct {
+	Symbol     string
+	Side       string // long/short
+	EntryPrice float64
+	MarkPrice  float64
+	Quantity   float64
+	StopLoss   float64 // Protective stop placed with the entry, 0 = unknown
+	TakeProfit float64
+}
+
+// stopLevels stop loss/take profit placed for a position
+type stopLevels struct {
+	StopLoss   float64
+	TakeProfit float64
+}
+
+// rememberStops records the protective levels of a position, to classify its close later
+func (at *AutoTrader) rememberStops(symbol, side string, stopLoss, takeProfit float64) {
+	at.trackedMu.Lock()
+	defer at.trackedMu.Unlock()
+	if at.positionStops == nil {
+		at.positionStops = make(map[string]stopLevels)
+	}
+	at.positionStops[symbol+"_"+strings.ToLower(side)] = stopLevels{StopLoss: stopLoss, TakeProfit: takeProfit}
+}
+
+// untrackPosition stops watching a position the trader is about to close itself, so the close
+// is only reported once (by the close path)
+func (at *AutoTrader) untrackPosition(symbol, side string) {
+	at.trackedMu.Lock()
+	defer at.trackedMu.Unlock()
+	delete(at.trackedPositions, symbol+"_"+side)
+	delete(at.positionStops, symbol+"_"+side)
+}
+
+// trackPositionCloses compares the open positions with the previous check and records a
+// position_close (or stop_loss) event for each position closed outside of a decision cycle:
+// stop loss or take profit fills, liquidations and manual closes on the exchange
+func (at *AutoTrader) trackPositionCloses(positions []map[string]interface{}) {
+	current := make(map[string]trackedPosition, len(positions))
+	for _, pos := range positions {
+		p := trackedPosition{}
+		p.Symbol, _ = pos["symbol"].(string)
+		p.Side, _ = pos["side"].(string)
+		p.EntryPrice, _ = pos["entryPrice"].(float64)
+		p.MarkPrice, _ = pos["markPrice"].(float64)
+		p.Quantity, _ = pos["positionAmt"].(float64)
+		if p.Quantity < 0 {
+			p.Quantity = -p.Quantity
+		}
+		current[p.Symbol+"_"+p.Side] = p
+	}
+
+	at.trackedMu.Lock()
+	var closed []trackedPosition
+	for key, prev := range at.trackedPositions {
+		if _, open := current[key]; open {
+			continue
+		}
+		if levels, ok := at.positionStops[key]; ok {
+			prev.StopLoss, prev.TakeProfit = levels.StopLoss, levels.TakeProfit
+			delete(at.positionStops, key)
+		}
+		closed = append(closed, prev)
+	}
+	at.trackedPositions = current
+	at.trackedMu.Unlock()
+
+	for _, p := range closed {
+		price := p.MarkPrice
+		if latest, err := at.trader.GetMarketPrice(p.Symbol); err == nil && latest > 0 {
+			price = latest
+		}
+		at.recordPositionClose(p, price, closeReasonAt(p, price))
+	}
+}
+
+// closeReasonAt guesses why a position closed from the price when it was found closed
+func closeReasonAt(p trackedPosition, price float64) string {
+	long := p.Side == "long"
+	switch {
+	case p.StopLoss > 0 && ((long && price <= p.StopLoss*(1+stopTriggerTolerance)) || (!long && price >= p.StopLoss*(1-stopTriggerTolerance))):
+		return "stop_loss"
+	case p.TakeProfit > 0 && ((long && price >= p.TakeProfit*(1-stopTriggerTolerance)) || (!long && price <= p.TakeProfit*(1+stopTriggerTolerance))):
+		return "take_profit"
+	default:
+		return "exchange"
+	}
+}
+
+// recordPositionClose records a closed position on the timeline, as a stop_loss event when stopped out
+// reason is "ai" for closes by a decision, "drawdown" for drawdown protection closes, "time_stop" for
+// positions past the max holding time, and "stop_loss", "take_profit" or "exchange" for closes found
+// by the drawdown monitor.
+// With reflections enabled the AI then reviews the trade in the background.
+func (at *AutoTrader) recordPositionClose(p trackedPosition, exitPrice float64, reason string) {
+	var pnlPct, pnl float64
+	if p.EntryPrice > 0 && exitPrice > 0 {
+		pnlPct = (exitPrice - p.EntryPrice) / p.EntryPrice * 100
+		pnl = (exitPrice - p.EntryPrice) * p.Quantity
+		if p.Side == "short" {
+			pnlPct, pnl = -pnlPct, -pnl
+		}
+	}
+
+	eventType := store.TraderEventPositionClose
+	message := fmt.Sprintf("%s %s closed at %.4f (%+.2f%%)", p.Symbol, p.Side, exitPrice, pnlPct)
+	switch reason {
+	case "stop_loss":
+		eventType = store.TraderEventStopLoss
+		message = fmt.Sprintf("%s %s stop loss triggered at %.4f (%+.2f%%)", p.Symbol, p.Side, exitPrice, pnlPct)
+	case "take_profit":
+		message = fmt.Sprintf("%s %s take profit hit at %.4f (%+.2f%%)", p.Symbol, p.Side, exitPrice, pnlPct)
+	case "time_stop":
+		message = fmt.Sprintf("%s %s closed by time stop at %.4f (%+.2f%%)", p.Symbol, p.Side, exitPrice, pnlPct)
+	}
+	logger.Infof("📕 [%s] %s", at.name, message)
+	at.recordEvent(eventType, message, map[string]interface{}{
+		"symbol":      p.Symbol,
+		"side":        p.Side,
+		"entry_price": p.EntryPrice,
+		"exit_price":  exitPrice,
+		"pnl_pct":     pnlPct,
+		"pnl":         pnl, // Before fees
+		"stop_loss":   p.StopLoss,
+		"reason":      reason,
+	})
+	at.reflectOnClose(p, exitPrice, pnlPct, reason)
+}
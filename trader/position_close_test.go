@@ -0,0 +1,65 @@
+package trader
+
+import (
+	"path/filepath"
+	"testing"
+
+	"nofx/store"
+)
+
+// stubClosingTrader reports a fixed market price for positions found closed
+type stubClosingTrader struct {
+	Trader
+	price float64
+}
+
+func (s *stubClosingTrader) GetMarketPrice(symbol string) (float64, error) {
+	return s.price, nil
+}
+
+func TestTrackPositionCloses(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+	exchange := &stubClosingTrader{price: 94.8}
+	at := &AutoTrader{id: "t1", name: "test", trader: exchange, store: st}
+
+	open := []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "long", "entryPrice": 100.0, "markPrice": 97.0, "positionAmt": 1.0},
+		{"symbol": "ETHUSDT", "side": "short", "entryPrice": 50.0, "markPrice": 49.0, "positionAmt": -2.0},
+		{"symbol": "SOLUSDT", "side": "long", "entryPrice": 20.0, "markPrice": 21.0, "positionAmt": 5.0},
+	}
+	at.rememberStops("BTCUSDT", "LONG", 95, 120)
+	at.trackPositionCloses(open)
+
+	// SOLUSDT is closed by a decision, BTCUSDT stops out, ETHUSDT is still open
+	at.untrackPosition("SOLUSDT", "long")
+	at.trackPositionCloses(open[1:2])
+
+	events, _, err := st.TraderEvent().Query(store.TraderEventFilter{TraderID: "t1"})
+	if err != nil {
+		t.Fatalf("query events: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != store.TraderEventStopLoss {
+		t.Fatalf("expected a single stop_loss event, got %+v", events)
+	}
+
+	// Closed without a known stop: reported as an exchange-side close
+	at.trackPositionCloses(nil)
+	events, _, _ = st.TraderEvent().Query(store.TraderEventFilter{TraderID: "t1", Types: []string{store.TraderEventPositionClose}})
+	if len(events) != 1 {
+		t.Fatalf("expected a position_close event for ETHUSDT, got %d", len(events))
+	}
+}
+
+func TestCloseReasonAt(t *testing.T) {
+	short := trackedPosition{Side: "short", StopLoss: 110, TakeProfit: 90}
+	cases := map[float64]string{111: "stop_loss", 109.6: "stop_loss", 100: "exchange", 90.2: "take_profit"}
+	for price, want := range cases {
+		if got := closeReasonAt(short, price); got != want {
+			t.Errorf("short closed at %.1f: got %s, want %s", price, got, want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/store"
+	"strings"
+)
+
+// reflectionDecisionLookback decision records searched for the reasoning behind a closed trade
+const reflectionDecisionLookback = 50
+
+// reflectionConfig returns the reflection config when post-trade reflections are on
+func (at *AutoTrader) reflectionConfig() (store.ReflectionConfig, bool) {
+	if at.store == nil || at.mcpClient == nil || at.strategyEngine == nil {
+		return store.ReflectionConfig{}, false
+	}
+	config := at.strategyEngine.GetConfig()
+	if config == nil || !config.Reflection.Enabled {
+		return store.ReflectionConfig{}, false
+	}
+	return config.Reflection, true
+}
+
+// reflectOnClose asks the AI, in the background, to review a closed position against its outcome
+// and stores the answer for later decision prompts. Does nothing unless reflections are enabled.
+func (at *AutoTrader) reflectOnClose(p trackedPosition, exitPrice, pnlPct float64, reason string) {
+	config, ok := at.reflectionConfig()
+	if !ok {
+		return
+	}
+	_, maxChars := config.Limits()
+	go at.writeReflection(p, exitPrice, pnlPct, reason, maxChars)
+}
+
+// writeReflection asks the AI for the review of a closed position and stores it
+func (at *AutoTrader) writeReflection(p trackedPosition, exitPrice, pnlPct float64, reason string, maxChars int) {
+	lang := at.strategyEngine.GetLanguage()
+	entryReasoning, exitReasoning := at.tradeReasoning(p.Symbol, p.Side, reason)
+	systemPrompt, userPrompt := buildReflectionPrompt(lang, p, exitPrice, pnlPct, reason, entryReasoning, exitReasoning, maxChars)
+	answer, err := at.mcpClient.CallWithMessages(systemPrompt, userPrompt)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Reflection on %s %s failed: %v", at.name, p.Symbol, p.Side, err)
+		return
+	}
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return
+	}
+	reflection := &store.TradeReflection{
+		TraderID:   at.id,
+		Symbol:     p.Symbol,
+		Side:       p.Side,
+		EntryPrice: p.EntryPrice,
+		ExitPrice:  exitPrice,
+		PnLPct:     pnlPct,
+		Reason:     reason,
+		Reflection: store.TruncateReflection(answer, maxChars),
+		AIModel:    aiModelLabel(at.aiModel, at.config.CustomModelName),
+	}
+	if err := at.store.Reflection().Create(reflection); err != nil {
+		logger.Warnf("⚠️ [%s] Failed to store reflection: %v", at.name, err)
+		return
+	}
+	logger.Infof("🪞 [%s] Stored reflection on %s %s (%+.2f%%)", at.name, p.Symbol, p.Side, pnlPct)
+}
+
+// tradeReasoning returns the AI's reasoning for opening (and, for AI closes, closing) a position
+func (at *AutoTrader) tradeReasoning(symbol, side, reason string) (entry, exit string) {
+	records, err := at.store.Decision().GetLatestRecords(at.id, reflectionDecisionLookback)
+	if err != nil {
+		return "", ""
+	}
+	openAction, closeAction := "open_"+side, "close_"+side
+	// Records are oldest first, the latest matching decisions belong to this position
+	for i := len(records) - 1; i >= 0 && entry == ""; i-- {
+		for _, d := range records[i].Decisions {
+			if d.Symbol != symbol || !d.Success {
+				continue
+			}
+			if exit == "" && reason == "ai" && d.Action == closeAction {
+				exit = d.Reasoning
+			}
+			if d.Action == openAction || d.Action == openAction+"_limit" {
+				entry = d.Reasoning
+			}
+		}
+	}
+	return entry, exit
+}
+
+// buildReflectionPrompt builds the prompts asking for a short review of a closed trade
+func buildReflectionPrompt(lang kernel.Language, p trackedPosition, exitPrice, pnlPct float64, reason, entryReasoning, exitReasoning string, maxChars int) (string, string) {
+	var sb strings.Builder
+	if lang == kernel.LangChinese {
+		sb.WriteString(fmt.Sprintf("交易: %s %s | 入场 %.4f 出场 %.4f | 结果 %+.2f%% | 平仓方式: %s\n", p.Symbol, p.Side, p.EntryPrice, exitPrice, pnlPct, reason))
+		if p.StopLoss > 0 || p.TakeProfit > 0 {
+			sb.WriteString(fmt.Sprintf("止损 %.4f 止盈 %.4f\n", p.StopLoss, p.TakeProfit))
+		}
+		if entryReasoning != "" {
+			sb.WriteString("开仓理由: " + entryReasoning + "\n")
+		}
+		if exitReasoning != "" {
+			sb.WriteString("平仓理由: " + exitReasoning + "\n")
+		}
+		return fmt.Sprintf("你是一名交易员，正在复盘自己刚平仓的交易。结合实际结果，说明哪些判断正确、哪些错误，并给出一条今后可执行的经验。只输出复盘内容，不超过 %d 个字符。", maxChars), sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("Trade: %s %s | Entry %.4f Exit %.4f | Outcome %+.2f%% | Closed by: %s\n", p.Symbol, p.Side, p.EntryPrice, exitPrice, pnlPct, reason))
+	if p.StopLoss > 0 || p.TakeProfit > 0 {
+		sb.WriteString(fmt.Sprintf("Stop loss %.4f Take profit %.4f\n", p.StopLoss, p.TakeProfit))
+	}
+	if entryReasoning != "" {
+		sb.WriteString("Entry reasoning: " + entryReasoning + "\n")
+	}
+	if exitReasoning != "" {
+		sb.WriteString("Exit reasoning: " + exitReasoning + "\n")
+	}
+	return fmt.Sprintf("You are a trader reviewing a position you just closed. Given the actual outcome, explain what went right and what went wrong, and state one actionable lesson for future trades. Reply with the review only, at most %d characters.", maxChars), sb.String()
+}
+
+// recentReflections returns the reflections to include in the decision prompt, newest first
+func (at *AutoTrader) recentReflections() []kernel.Reflection {
+	config, ok := at.reflectionConfig()
+	if !ok {
+		return nil
+	}
+	entries, maxChars := config.Limits()
+	stored, err := at.store.Reflection().ListRecent(at.id, entries)
+	if err != nil {
+		logger.Infof("⚠️ [%s] Failed to get reflections: %v", at.name, err)
+		return nil
+	}
+	reflections := make([]kernel.Reflection, 0, len(stored))
+	for _, r := range stored {
+		reflections = append(reflections, kernel.Reflection{
+			Symbol: r.Symbol,
+			Side:   r.Side,
+			PnLPct: r.PnLPct,
+			Reason: r.Reason,
+			Time:   r.CreatedAt.UTC().Format("01-02 15:04 UTC"),
+			Text:   store.TruncateReflection(strings.Join(strings.Fields(r.Reflection), " "), maxChars),
+		})
+	}
+	return reflections
+}
@@ -0,0 +1,63 @@
+package trader
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nofx/kernel"
+	"nofx/store"
+)
+
+func TestReflectionsAreStoredAndFedBack(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+
+	config := store.GetDefaultStrategyConfig("en")
+	engine := kernel.NewStrategyEngine(&config)
+	ai := &stubAIClient{response: " Entered on a breakout that failed;\nwait for a retest next time. "}
+	at := &AutoTrader{id: "t1", name: "test", store: st, mcpClient: ai, strategyEngine: engine}
+
+	// Disabled by default: no AI call, nothing in the prompt
+	at.reflectOnClose(trackedPosition{Symbol: "BTCUSDT", Side: "long", EntryPrice: 100}, 95, -5, "stop_loss")
+	if ai.calls != 0 || at.recentReflections() != nil {
+		t.Fatalf("reflections should be off unless enabled")
+	}
+
+	config.Reflection = store.ReflectionConfig{Enabled: true, PromptEntries: 2, MaxChars: 30}
+	if err := st.Decision().LogDecision(&store.DecisionRecord{TraderID: "t1", Success: true, Decisions: []store.DecisionAction{
+		{Action: "open_long", Symbol: "BTCUSDT", Success: true, Reasoning: "breakout above range"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if entry, exit := at.tradeReasoning("BTCUSDT", "long", "stop_loss"); entry != "breakout above range" || exit != "" {
+		t.Errorf("unexpected trade reasoning %q / %q", entry, exit)
+	}
+	for i := 0; i < 3; i++ {
+		at.writeReflection(trackedPosition{Symbol: "BTCUSDT", Side: "long", EntryPrice: 100}, 95, -5, "stop_loss", 30)
+	}
+
+	reflections := at.recentReflections()
+	if len(reflections) != 2 {
+		t.Fatalf("expected the 2 configured prompt entries, got %d", len(reflections))
+	}
+	if n := len([]rune(reflections[0].Text)); n > 30 || strings.Contains(reflections[0].Text, "\n") {
+		t.Errorf("reflection should be cut to 30 characters on one line, got %q", reflections[0].Text)
+	}
+	prompt := engine.BuildUserPrompt(&kernel.Context{Account: kernel.AccountInfo{TotalEquity: 1000}, Reflections: reflections})
+	if !strings.Contains(prompt, "Lessons From Your Recent Trades") || !strings.Contains(prompt, "BTCUSDT long -5.00% (stop_loss)") {
+		t.Errorf("prompt should summarize the reflections:\n%s", prompt)
+	}
+}
+
+func TestTruncateReflection(t *testing.T) {
+	if got := store.TruncateReflection("复盘内容很长", 3); got != "复盘…" {
+		t.Errorf("got %q", got)
+	}
+	if got := store.TruncateReflection("short", 10); got != "short" {
+		t.Errorf("got %q", got)
+	}
+}
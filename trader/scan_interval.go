@@ -0,0 +1,62 @@
+package trader
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultMinScanInterval system floor for scan intervals when none is configured
+const DefaultMinScanInterval = 3 * time.Minute
+
+// aiModelScanLimit rate limit guard and rough cost of one decision call of an AI provider
+type aiModelScanLimit struct {
+	MinInterval    time.Duration // Shortest scan interval the provider sustains (latency + rate limits)
+	CostPerCallUSD float64       // Approximate cost of one decision call (~20k prompt + ~2k output tokens), 0 = unknown
+}
+
+var aiModelScanLimits = map[string]aiModelScanLimit{
+	"deepseek": {MinInterval: 60 * time.Second, CostPerCallUSD: 0.008},
+	"qwen":     {MinInterval: 60 * time.Second, CostPerCallUSD: 0.01},
+	"kimi":     {MinInterval: 60 * time.Second, CostPerCallUSD: 0.02},
+	"claude":   {MinInterval: 60 * time.Second, CostPerCallUSD: 0.09},
+	"openai":   {MinInterval: 30 * time.Second, CostPerCallUSD: 0.07},
+	"gemini":   {MinInterval: 30 * time.Second, CostPerCallUSD: 0.03},
+	"grok":     {MinInterval: 30 * time.Second, CostPerCallUSD: 0.07},
+}
+
+// defaultAIModelScanLimit applies to custom and unknown providers
+var defaultAIModelScanLimit = aiModelScanLimit{MinInterval: 60 * time.Second}
+
+// ClampScanInterval raises a scan interval to the system floor and the AI provider's rate limit floor.
+// Returns the effective interval and, when it was raised, the reason.
+func ClampScanInterval(requested time.Duration, provider string, systemMin time.Duration) (time.Duration, string) {
+	if systemMin <= 0 {
+		systemMin = DefaultMinScanInterval
+	}
+	limit, ok := aiModelScanLimits[provider]
+	if !ok {
+		limit = defaultAIModelScanLimit
+	}
+
+	if requested < systemMin && systemMin >= limit.MinInterval {
+		return systemMin, fmt.Sprintf("scan interval raised to the system minimum of %v", systemMin)
+	}
+	if requested < limit.MinInterval {
+		return limit.MinInterval, fmt.Sprintf("scan interval raised to %v, the shortest interval %s rate limits allow", limit.MinInterval, provider)
+	}
+	return requested, ""
+}
+
+// ScanIntervalCostWarning estimates AI usage of intervals below the default floor
+// Returns an empty string for intervals of DefaultMinScanInterval or longer
+func ScanIntervalCostWarning(interval time.Duration, provider string) string {
+	if interval <= 0 || interval >= DefaultMinScanInterval {
+		return ""
+	}
+	callsPerDay := int(24 * time.Hour / interval)
+	warning := fmt.Sprintf("scanning every %v makes ~%d AI calls per day", interval, callsPerDay)
+	if limit, ok := aiModelScanLimits[provider]; ok && limit.CostPerCallUSD > 0 {
+		warning += fmt.Sprintf(" (≈$%.2f/day with %s at typical prompt sizes)", float64(callsPerDay)*limit.CostPerCallUSD, provider)
+	}
+	return warning
+}
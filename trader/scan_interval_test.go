@@ -0,0 +1,48 @@
+package trader
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClampScanInterval(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested time.Duration
+		provider  string
+		systemMin time.Duration
+		want      time.Duration
+		clamped   bool
+	}{
+		{"default floor", time.Minute, "deepseek", 0, 3 * time.Minute, true},
+		{"above floor", 5 * time.Minute, "deepseek", 0, 5 * time.Minute, false},
+		{"lowered system floor", 45 * time.Second, "openai", 30 * time.Second, 45 * time.Second, false},
+		{"provider floor", 30 * time.Second, "deepseek", 10 * time.Second, 60 * time.Second, true},
+		{"unknown provider", 10 * time.Second, "custom", 10 * time.Second, 60 * time.Second, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := ClampScanInterval(tt.requested, tt.provider, tt.systemMin)
+			if got != tt.want {
+				t.Errorf("interval = %v, want %v", got, tt.want)
+			}
+			if (reason != "") != tt.clamped {
+				t.Errorf("reason = %q, clamped want %v", reason, tt.clamped)
+			}
+		})
+	}
+}
+
+func TestScanIntervalCostWarning(t *testing.T) {
+	if w := ScanIntervalCostWarning(3*time.Minute, "claude"); w != "" {
+		t.Errorf("no warning expected at the default floor, got %q", w)
+	}
+	w := ScanIntervalCostWarning(time.Minute, "claude")
+	if !strings.Contains(w, "1440 AI calls") || !strings.Contains(w, "$129.60/day") {
+		t.Errorf("unexpected warning: %q", w)
+	}
+	if w := ScanIntervalCostWarning(time.Minute, "custom"); strings.Contains(w, "$") {
+		t.Errorf("unknown provider should have no cost estimate, got %q", w)
+	}
+}
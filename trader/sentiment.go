@@ -0,0 +1,8 @@
+package trader
+
+import "nofx/provider/sentiment"
+
+// SetSentimentService sets the source of the Fear & Greed index and social sentiment scores
+func (at *AutoTrader) SetSentimentService(service *sentiment.Service) {
+	at.sentimentService = service
+}
@@ -0,0 +1,435 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Split Entries (TWAP / Iceberg)
+// ============================================================================
+// Strategies may execute large entries as child orders instead of one market
+// order (StrategyConfig.Execution). An entry is split when it is at least
+// MinSplitUSD, or larger than RiskControl.MaxDepthPct of the visible depth:
+//   - twap: the first child is a market order placed with the decision (with
+//     its stop loss/take profit), the rest follow at equal intervals,
+//   - iceberg: one limit child at a time rests at the best bid/ask and is
+//     repriced once per interval until filled.
+// Progress is stored, so entries survive restarts, and written back into the
+// decision that started them. Stop loss/take profit are extended to the filled
+// quantity after each child; the remainder is abandoned when the position
+// closes meanwhile or the entry expires (twice the configured duration).
+// ============================================================================
+
+// splitEntryInterval how often active split entries are advanced
+var splitEntryInterval = 10 * time.Second
+
+// splitChildKeyStride client order key index offset between the child orders of one decision
+const splitChildKeyStride = 1000
+
+// splitPlan returns the execution algorithm, child order count and time span for an opening
+// decision, an empty algorithm when it is opened with one order
+func (at *AutoTrader) splitPlan(decision *kernel.Decision) (string, int, time.Duration) {
+	if at.store == nil || at.strategyEngine == nil {
+		return "", 0, 0
+	}
+	config := at.strategyEngine.GetConfig()
+	algo := config.Execution.Algo
+	if algo != store.ExecutionTWAP && algo != store.ExecutionIceberg {
+		return "", 0, 0
+	}
+
+	large := config.Execution.MinSplitUSD > 0 && decision.PositionSizeUSD >= config.Execution.MinSplitUSD
+	if !large {
+		depth := at.strategyEngine.FetchOrderBookBatch([]string{decision.Symbol})[decision.Symbol]
+		large = depth != nil && kernel.CheckDepth(decision, depth, config.RiskControl.MaxDepthPct) != ""
+	}
+	if !large {
+		return "", 0, 0
+	}
+
+	slices, duration := config.Execution.SlicesAndDuration()
+	// Every child must still be a valid order
+	for slices > 1 && at.enforceMinPositionSize(decision.PositionSizeUSD/float64(slices)) != nil {
+		slices--
+	}
+	if slices < 2 {
+		return "", 0, 0
+	}
+	if _, ok := at.trader.(GridTrader); algo == store.ExecutionIceberg && !ok {
+		logger.Infof("  ⚠️ Limit orders not supported on %s, executing iceberg entry as TWAP", at.exchange)
+		algo = store.ExecutionTWAP
+	}
+	return algo, slices, duration
+}
+
+// startSplitEntry opens a position as child orders, see splitPlan
+// TWAP entries place their first child right away; the worker places the rest.
+func (at *AutoTrader) startSplitEntry(algo string, slices int, duration time.Duration, side string, decision *kernel.Decision,
+	quantity, price float64, actionRecord *store.DecisionAction, key ClientOrderKey) error {
+	now := time.Now().UTC()
+	entry := &store.SplitEntry{
+		TraderID:   at.id,
+		Cycle:      key.Cycle,
+		KeyIndex:   key.Index,
+		Symbol:     decision.Symbol,
+		Side:       side,
+		Algo:       algo,
+		TotalQty:   quantity,
+		Slices:     slices,
+		Leverage:   decision.Leverage,
+		StopLoss:   decision.StopLoss,
+		TakeProfit: decision.TakeProfit,
+		IntervalMs: (duration / time.Duration(slices)).Milliseconds(),
+		NextAt:     now.UnixMilli(),
+		ExpiresAt:  now.Add(2 * duration).UnixMilli(),
+	}
+	actionRecord.ExecutionAlgo = algo
+	logger.Infof("  🧊 Splitting %s %s entry into %d %s child orders over %v", decision.Symbol, side, slices, algo, duration)
+
+	if algo == store.ExecutionTWAP {
+		childQty := entry.SliceQty()
+		order, bracketed, err := at.submitOpenWithProtection(side, decision, childQty, key)
+		if err != nil {
+			return err
+		}
+		if orderID, ok := order["orderId"].(int64); ok {
+			actionRecord.OrderID = orderID
+		}
+		fill := at.recordAndConfirmOrder(order, decision.Symbol, "open_"+side, childQty, price, decision.Leverage, 0)
+		recordExecution(actionRecord, fill, side == "long")
+		at.positionFirstSeenTime[decision.Symbol+"_"+side] = time.Now().UnixMilli()
+
+		entry.AddFill(childFill(fill, childQty, price))
+		entry.SlicesDone = 1
+		entry.NextAt = now.UnixMilli() + entry.IntervalMs
+		actionRecord.ChildOrders = 1
+		if !bracketed {
+			if err := at.protectOrClose(decision.Symbol, strings.ToUpper(side), entry.FilledQty, decision.StopLoss, decision.TakeProfit); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := at.store.SplitEntry().Create(entry); err != nil {
+		if entry.FilledQty > 0 {
+			// The first child is open and protected, only the remainder is lost
+			logger.Warnf("  ⚠️ Failed to track split entry, %s stays at the first child order: %v", decision.Symbol, err)
+			return nil
+		}
+		return err
+	}
+	actionRecord.SplitEntryID = entry.ID
+	return nil
+}
+
+// childFill returns the filled quantity and price of a child market order, assuming it filled
+// as submitted when the fill could not be confirmed
+func childFill(fill *orderFill, quantity, price float64) (float64, float64) {
+	if fill == nil {
+		return quantity, price
+	}
+	switch fill.Status {
+	case "CANCELED", "EXPIRED", "REJECTED":
+		return 0, 0
+	}
+	if fill.Quantity > 0 {
+		quantity = fill.Quantity
+	}
+	if fill.AvgPrice > 0 {
+		price = fill.AvgPrice
+	}
+	return quantity, price
+}
+
+// activeSplitEntry returns an active split entry of the trader in symbol/side, nil if none
+func (at *AutoTrader) activeSplitEntry(symbol, side string) *store.SplitEntry {
+	if at.store == nil {
+		return nil
+	}
+	entries, err := at.store.SplitEntry().ListActive(at.id)
+	if err != nil {
+		return nil
+	}
+	for _, entry := range entries {
+		if entry.Symbol == symbol && entry.Side == side {
+			return entry
+		}
+	}
+	return nil
+}
+
+// startSplitEntryWorker starts advancing active split entries
+func (at *AutoTrader) startSplitEntryWorker() {
+	at.monitorWg.Add(1)
+	go func() {
+		defer at.monitorWg.Done()
+
+		ticker := time.NewTicker(splitEntryInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				at.advanceSplitEntries()
+			case <-at.stopMonitorCh:
+				return
+			}
+		}
+	}()
+}
+
+// advanceSplitEntries places the child orders that are due and finishes completed entries
+func (at *AutoTrader) advanceSplitEntries() {
+	if at.store == nil || at.killSwitchReason() != "" {
+		return
+	}
+	entries, err := at.store.SplitEntry().ListActive(at.id)
+	if err != nil {
+		logger.Infof("⚠️ [%s] Failed to load split entries: %v", at.name, err)
+		return
+	}
+	if len(entries) == 0 {
+		return
+	}
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Infof("⚠️ [%s] Failed to get positions for split entries: %v", at.name, err)
+		return
+	}
+	for _, entry := range entries {
+		at.advanceSplitEntry(entry, positions, time.Now().UTC().UnixMilli())
+	}
+}
+
+// advanceSplitEntry advances one entry: collects the fill of its resting iceberg child, places
+// the next child when due and finishes the entry once filled, expired or its position is gone
+func (at *AutoTrader) advanceSplitEntry(entry *store.SplitEntry, positions []map[string]interface{}, nowMs int64) {
+	if entry.FilledQty > 0 && !hasPosition(positions, entry.Symbol, entry.Side) {
+		at.finishSplitEntry(entry, store.SplitEntryCancelled, "position closed before the entry completed")
+		return
+	}
+
+	if entry.OrderID != "" {
+		at.collectIcebergChild(entry, nowMs)
+	}
+	if entry.FilledQty >= entry.TotalQty*0.999 || (entry.SlicesDone >= entry.Slices && entry.OrderID == "") {
+		at.finishSplitEntry(entry, store.SplitEntryDone, "")
+		return
+	}
+	if nowMs >= entry.ExpiresAt {
+		status := store.SplitEntryDone
+		if entry.FilledQty == 0 {
+			status = store.SplitEntryCancelled
+		}
+		at.finishSplitEntry(entry, status, fmt.Sprintf("expired with %.4f of %.4f filled", entry.FilledQty, entry.TotalQty))
+		return
+	}
+	if entry.OrderID != "" || nowMs < entry.NextAt {
+		if err := at.store.SplitEntry().Update(entry); err != nil {
+			logger.Infof("⚠️ [%s] Failed to update split entry %d: %v", at.name, entry.ID, err)
+		}
+		return
+	}
+
+	key := ClientOrderKey{TraderID: at.id, Cycle: entry.Cycle, Index: entry.KeyIndex + splitChildKeyStride*(entry.SlicesDone+1)}
+	if entry.Algo == store.ExecutionIceberg {
+		at.placeIcebergChild(entry, key)
+	} else {
+		at.placeTWAPChild(entry, key)
+	}
+	entry.NextAt = nowMs + entry.IntervalMs
+	if err := at.store.SplitEntry().Update(entry); err != nil {
+		logger.Infof("⚠️ [%s] Failed to update split entry %d: %v", at.name, entry.ID, err)
+	}
+	at.recordSplitProgress(entry)
+}
+
+// placeTWAPChild places the next market child order of a TWAP entry
+func (at *AutoTrader) placeTWAPChild(entry *store.SplitEntry, key ClientOrderKey) {
+	childQty := entry.SliceQty()
+	price, _ := at.trader.GetMarketPrice(entry.Symbol)
+	order, err := at.submitOpenOrder(entry.Side, entry.Symbol, childQty, entry.Leverage, key)
+	if err != nil {
+		// Counted as done so a persistently rejected entry ends instead of retrying forever
+		entry.SlicesDone++
+		logger.Warnf("⚠️ [%s] TWAP child %d/%d of %s %s failed: %v", at.name, entry.SlicesDone, entry.Slices, entry.Symbol, entry.Side, err)
+		// The failed open may already have cancelled the stop orders of the filled part (Binance
+		// cancels every order of the symbol before opening), so place them again
+		if entry.FilledQty > 0 {
+			at.protectSplitEntry(entry, true)
+		}
+		return
+	}
+	fill := at.recordAndConfirmOrder(order, entry.Symbol, "open_"+entry.Side, childQty, price, entry.Leverage, 0)
+	entry.SlicesDone++
+	if qty, avg := childFill(fill, childQty, price); qty > 0 {
+		at.onSplitChildFilled(entry, qty, avg)
+	}
+}
+
+// placeIcebergChild places the next limit child of an iceberg entry at the best bid (long) or ask (short)
+func (at *AutoTrader) placeIcebergChild(entry *store.SplitEntry, key ClientOrderKey) {
+	gridTrader, ok := at.trader.(GridTrader)
+	if !ok {
+		entry.Algo = store.ExecutionTWAP
+		at.placeTWAPChild(entry, key)
+		return
+	}
+	bids, asks, err := gridTrader.GetOrderBook(entry.Symbol, 5)
+	if err != nil || len(bids) == 0 || len(asks) == 0 {
+		logger.Infof("⚠️ [%s] No order book for iceberg child of %s: %v", at.name, entry.Symbol, err)
+		return
+	}
+	req := &LimitOrderRequest{
+		Symbol:       entry.Symbol,
+		Side:         "BUY",
+		PositionSide: "LONG",
+		Price:        bids[0][0],
+		Quantity:     entry.SliceQty(),
+		Leverage:     entry.Leverage,
+		ClientID:     key.ID(),
+	}
+	if entry.Side == "short" {
+		req.Side, req.PositionSide, req.Price = "SELL", "SHORT", asks[0][0]
+	}
+	result, err := gridTrader.PlaceLimitOrder(req)
+	if err != nil {
+		logger.Warnf("⚠️ [%s] Failed to place iceberg child of %s %s: %v", at.name, entry.Symbol, entry.Side, err)
+		return
+	}
+	entry.OrderID = result.OrderID
+	logger.Infof("🧊 [%s] Iceberg child %s %s %.4f @ %.4f placed (%s)", at.name, entry.Symbol, entry.Side, req.Quantity, req.Price, result.OrderID)
+}
+
+// collectIcebergChild checks the resting iceberg child: its fill is added once it is done, and
+// it is cancelled (keeping any partial fill) when it rested longer than one interval
+func (at *AutoTrader) collectIcebergChild(entry *store.SplitEntry, nowMs int64) {
+	childQty := entry.SliceQty()
+	status, err := at.trader.GetOrderStatus(entry.Symbol, entry.OrderID)
+	if err != nil {
+		logger.Infof("⚠️ [%s] Failed to query iceberg child %s: %v", at.name, entry.OrderID, err)
+		return
+	}
+	orderStatus, _ := status["status"].(string)
+	switch strings.ToUpper(orderStatus) {
+	case "FILLED", "CANCELED", "CANCELLED", "EXPIRED", "REJECTED":
+	default:
+		if nowMs < entry.NextAt && nowMs < entry.ExpiresAt {
+			return
+		}
+		// Not filled in time: reprice at the current best price
+		gridTrader, ok := at.trader.(GridTrader)
+		if !ok {
+			return
+		}
+		if err := gridTrader.CancelOrder(entry.Symbol, entry.OrderID); err != nil {
+			logger.Infof("⚠️ [%s] Failed to cancel iceberg child %s: %v", at.name, entry.OrderID, err)
+			return
+		}
+		if latest, err := at.trader.GetOrderStatus(entry.Symbol, entry.OrderID); err == nil {
+			status = latest
+		}
+	}
+
+	executedQty, _ := status["executedQty"].(float64)
+	avgPrice, _ := status["avgPrice"].(float64)
+	if strings.ToUpper(orderStatus) == "FILLED" && executedQty <= 0 {
+		executedQty = childQty
+	}
+	orderID := entry.OrderID
+	entry.OrderID = ""
+	entry.NextAt = nowMs // Replenish right away
+	if executedQty <= 0 {
+		return
+	}
+	entry.SlicesDone++
+	logger.Infof("✅ [%s] Iceberg child %s %s filled: %.4f @ %.4f", at.name, entry.Symbol, entry.Side, executedQty, avgPrice)
+	if !HasOrderSync(at.exchange) && !at.paperMode {
+		at.recordPositionChange(orderID, market.Normalize(entry.Symbol), strings.ToUpper(entry.Side), "open_"+entry.Side,
+			executedQty, avgPrice, entry.Leverage, 0, 0)
+	}
+	at.onSplitChildFilled(entry, executedQty, avgPrice)
+}
+
+// onSplitChildFilled adds a child fill and replaces stop loss/take profit to cover the filled quantity
+func (at *AutoTrader) onSplitChildFilled(entry *store.SplitEntry, qty, price float64) {
+	first := entry.FilledQty == 0
+	entry.AddFill(qty, price)
+	if first {
+		at.positionFirstSeenTime[entry.Symbol+"_"+entry.Side] = time.Now().UnixMilli()
+	}
+	// Later children replace the existing stop orders (Binance cancels them on every entry)
+	at.protectSplitEntry(entry, !first)
+}
+
+// protectSplitEntry places stop loss/take profit covering the filled quantity, cancelling the
+// existing stop orders first when replace is set
+// A position whose stop loss cannot be placed is closed, which also ends the entry.
+func (at *AutoTrader) protectSplitEntry(entry *store.SplitEntry, replace bool) {
+	if replace {
+		if err := at.trader.CancelStopOrders(entry.Symbol); err != nil {
+			logger.Infof("⚠️ [%s] Failed to cancel stop orders of %s before extending them: %v", at.name, entry.Symbol, err)
+		}
+	}
+	if err := at.protectOrClose(entry.Symbol, strings.ToUpper(entry.Side), entry.FilledQty, entry.StopLoss, entry.TakeProfit); err != nil {
+		entry.Slices = entry.SlicesDone
+		entry.ExpiresAt = 0
+		logger.Errorf("❌ [%s] Split entry %s %s stopped: %v", at.name, entry.Symbol, entry.Side, err)
+	}
+}
+
+// finishSplitEntry ends an entry, cancelling its resting child order
+func (at *AutoTrader) finishSplitEntry(entry *store.SplitEntry, status, reason string) {
+	if entry.OrderID != "" {
+		if gridTrader, ok := at.trader.(GridTrader); ok {
+			if err := gridTrader.CancelOrder(entry.Symbol, entry.OrderID); err != nil {
+				logger.Infof("⚠️ [%s] Failed to cancel iceberg child %s: %v", at.name, entry.OrderID, err)
+			}
+		}
+		entry.OrderID = ""
+	}
+	entry.Status, entry.Reason = status, reason
+	if err := at.store.SplitEntry().Update(entry); err != nil {
+		logger.Infof("⚠️ [%s] Failed to update split entry %d: %v", at.name, entry.ID, err)
+	}
+	logger.Infof("🧊 [%s] Split entry %s %s %s: %.4f of %.4f filled @ %.4f in %d child orders %s",
+		at.name, entry.Symbol, entry.Side, strings.ToLower(status), entry.FilledQty, entry.TotalQty, entry.AvgPrice, entry.SlicesDone, reason)
+	at.recordSplitProgress(entry)
+}
+
+// recordSplitProgress writes the total fill of an entry into the decision that started it
+func (at *AutoTrader) recordSplitProgress(entry *store.SplitEntry) {
+	err := at.store.Decision().UpdateAction(at.id, entry.Cycle, entry.Symbol, "open_"+entry.Side, func(a *store.DecisionAction) {
+		a.FilledQty = entry.FilledQty
+		a.FillPrice = entry.AvgPrice
+		a.ChildOrders = entry.SlicesDone
+		if entry.Status != store.SplitEntryActive {
+			a.OrderStatus = entry.Status
+		}
+		if entry.AvgPrice > 0 && a.Price > 0 {
+			a.SlippagePct = (entry.AvgPrice - a.Price) / a.Price * 100
+			if entry.Side == "short" {
+				a.SlippagePct = -a.SlippagePct
+			}
+		}
+	})
+	if err != nil {
+		logger.Infof("⚠️ [%s] Failed to record split entry progress: %v", at.name, err)
+	}
+}
+
+// hasPosition reports whether positions contain symbol/side
+func hasPosition(positions []map[string]interface{}, symbol, side string) bool {
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package trader
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"nofx/kernel"
+	"nofx/store"
+)
+
+// stubSplitTrader fills market orders at 100 and keeps the position they open
+type stubSplitTrader struct {
+	stubProtectionTrader
+	opened []float64
+}
+
+func (s *stubSplitTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	s.opened = append(s.opened, quantity)
+	s.positions = []map[string]interface{}{{"symbol": symbol, "side": "long"}}
+	return map[string]interface{}{"orderId": int64(len(s.opened))}, nil
+}
+
+func (s *stubSplitTrader) GetMarketPrice(symbol string) (float64, error) {
+	return 100, nil
+}
+
+func (s *stubSplitTrader) GetOrderStatus(symbol string, orderID string) (map[string]interface{}, error) {
+	return map[string]interface{}{"status": "FILLED", "avgPrice": 100.0}, nil
+}
+
+func TestSplitEntry_TWAPChildOrders(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+
+	config := store.GetDefaultStrategyConfig("en")
+	config.Execution = store.ExecutionConfig{Algo: store.ExecutionTWAP, MinSplitUSD: 1000, Slices: 4, DurationMinutes: 4}
+	exchange := &stubSplitTrader{}
+	at := &AutoTrader{
+		id: "trader-split", name: "test", exchange: "binance", store: st, trader: exchange,
+		strategyEngine: kernel.NewStrategyEngine(&config), positionFirstSeenTime: map[string]int64{},
+	}
+
+	decision := &kernel.Decision{Symbol: "BTCUSDT", Action: "open_long", PositionSizeUSD: 2000, Leverage: 5, StopLoss: 90, TakeProfit: 120}
+	algo, slices, duration := at.splitPlan(decision)
+	if algo != store.ExecutionTWAP || slices != 4 || duration.Minutes() != 4 {
+		t.Fatalf("unexpected plan %q %d %v", algo, slices, duration)
+	}
+	if algo, _, _ := at.splitPlan(&kernel.Decision{Symbol: "BTCUSDT", Action: "open_long", PositionSizeUSD: 500}); algo != "" {
+		t.Errorf("entries below min_split_usd should open with one order, got %q", algo)
+	}
+
+	if err := st.Decision().LogDecision(&store.DecisionRecord{TraderID: at.id, CycleNumber: 3, Success: true, Decisions: []store.DecisionAction{
+		{Action: "open_long", Symbol: "BTCUSDT", Price: 100},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	action := &store.DecisionAction{Action: "open_long", Symbol: "BTCUSDT", Price: 100}
+	if err := at.startSplitEntry(algo, slices, duration, "long", decision, 20, 100, action, ClientOrderKey{TraderID: at.id, Cycle: 3}); err != nil {
+		t.Fatalf("startSplitEntry: %v", err)
+	}
+	if len(exchange.opened) != 1 || exchange.opened[0] != 5 || action.ExecutionAlgo != store.ExecutionTWAP || action.SplitEntryID == 0 {
+		t.Fatalf("expected the first of 4 child orders, got %v %+v", exchange.opened, action)
+	}
+	if at.activeSplitEntry("BTCUSDT", "long") == nil {
+		t.Fatal("entry should be active")
+	}
+
+	// Advance past every interval: the remaining children are placed and the decision updated
+	for i := 0; i < 4; i++ {
+		entries, _ := st.SplitEntry().ListActive(at.id)
+		for _, entry := range entries {
+			at.advanceSplitEntry(entry, exchange.positions, entry.NextAt)
+		}
+	}
+	if len(exchange.opened) != 4 {
+		t.Fatalf("expected 4 child orders, got %v", exchange.opened)
+	}
+	if at.activeSplitEntry("BTCUSDT", "long") != nil {
+		t.Error("entry should be done")
+	}
+	if len(exchange.stopLosses) != 4 || len(exchange.cancelled) != 3 {
+		t.Errorf("stops should be replaced after every child: %v / %v", exchange.stopLosses, exchange.cancelled)
+	}
+	records, _ := st.Decision().GetLatestRecords(at.id, 1)
+	if got := records[0].Decisions[0]; got.FilledQty != 20 || got.ChildOrders != 4 || got.OrderStatus != store.SplitEntryDone {
+		t.Errorf("decision should record the total fill, got %+v", got)
+	}
+}
+
+func TestSplitEntry_CancelledWhenPositionGone(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+
+	at := &AutoTrader{id: "trader-split", name: "test", store: st, trader: &stubSplitTrader{}}
+	entry := &store.SplitEntry{TraderID: at.id, Symbol: "ETHUSDT", Side: "short", Algo: store.ExecutionTWAP,
+		TotalQty: 3, Slices: 3, SlicesDone: 1, FilledQty: 1, IntervalMs: 1000}
+	if err := st.SplitEntry().Create(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	at.advanceSplitEntry(entry, nil, entry.NextAt)
+	if entry.Status != store.SplitEntryCancelled {
+		t.Errorf("entry of a closed position should be cancelled, got %s", entry.Status)
+	}
+}
+
+func TestSplitEntry_SliceQtyAndAddFill(t *testing.T) {
+	entry := &store.SplitEntry{TotalQty: 10, Slices: 3}
+	if q := entry.SliceQty(); q < 3.33 || q > 3.34 {
+		t.Errorf("expected a third, got %v", q)
+	}
+	entry.AddFill(4, 100)
+	entry.AddFill(4, 110)
+	entry.SlicesDone = 2
+	if entry.AvgPrice != 105 || entry.SliceQty() != 2 {
+		t.Errorf("expected avg 105 and remainder 2, got %v / %v", entry.AvgPrice, entry.SliceQty())
+	}
+}
+
+// stubFailingSplitTrader rejects market orders after the first failAfter fills
+type stubFailingSplitTrader struct {
+	stubSplitTrader
+	failAfter int
+}
+
+func (s *stubFailingSplitTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	if len(s.opened) >= s.failAfter {
+		// Like Binance, the stop orders of the symbol are already gone when the open fails
+		s.stopLosses, s.takeProfits = nil, nil
+		return nil, errors.New("insufficient margin")
+	}
+	return s.stubSplitTrader.OpenLong(symbol, quantity, leverage)
+}
+
+func TestSplitEntry_FailedChildReprotectsFilledQuantity(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer st.Close()
+
+	exchange := &stubFailingSplitTrader{failAfter: 2}
+	at := &AutoTrader{id: "trader-split", name: "test", exchange: "binance", store: st, trader: exchange, positionFirstSeenTime: map[string]int64{}}
+	entry := &store.SplitEntry{TraderID: at.id, Symbol: "BTCUSDT", Side: "long", Algo: store.ExecutionTWAP,
+		TotalQty: 12, Slices: 4, Leverage: 5, StopLoss: 90, TakeProfit: 120, IntervalMs: 1000, ExpiresAt: 1 << 60}
+	if err := st.SplitEntry().Create(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two children fill, the third is rejected
+	for i := 0; i < 3; i++ {
+		at.advanceSplitEntry(entry, exchange.positions, entry.NextAt)
+	}
+	if entry.FilledQty != 6 || entry.SlicesDone != 3 {
+		t.Fatalf("expected 6 filled in 3 attempted children, got %v in %d", entry.FilledQty, entry.SlicesDone)
+	}
+	if len(exchange.stopLosses) != 1 || len(exchange.takeProfits) != 1 {
+		t.Errorf("the filled part should be protected again after the failed child: sl=%v tp=%v", exchange.stopLosses, exchange.takeProfits)
+	}
+}
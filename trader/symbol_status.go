@@ -0,0 +1,97 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/market"
+	"nofx/store"
+	"time"
+)
+
+// SetSymbolStatusService sets the source of delisted symbols and exchange maintenance windows
+func (at *AutoTrader) SetSymbolStatusService(status *market.SymbolStatusService) {
+	at.symbolStatus = status
+}
+
+// activeMaintenance returns the maintenance window currently covering the trader's exchange, nil if none
+func (at *AutoTrader) activeMaintenance() *market.MaintenanceWindow {
+	if at.symbolStatus == nil {
+		return nil
+	}
+	return at.symbolStatus.ActiveMaintenance(at.exchange, time.Now())
+}
+
+// filterRestrictedCandidates drops candidate coins that are delisting or not trading
+func (at *AutoTrader) filterRestrictedCandidates(coins []kernel.CandidateCoin) []kernel.CandidateCoin {
+	if at.symbolStatus == nil {
+		return coins
+	}
+	filtered := coins[:0]
+	for _, coin := range coins {
+		if r, restricted := at.symbolStatus.Restriction(coin.Symbol); restricted {
+			logger.Infof("📅 [%s] Skipping candidate %s: %s", at.name, coin.Symbol, r.Reason)
+			continue
+		}
+		filtered = append(filtered, coin)
+	}
+	return filtered
+}
+
+// handleRestrictedPositions closes positions in contracts that are settling or about to be delisted,
+// and raises a timeline alert (once per symbol per day) for positions in contracts with a later delisting.
+// Contract status comes from Binance, so positions on other exchanges are only flagged, never closed.
+func (at *AutoTrader) handleRestrictedPositions(positions []kernel.PositionInfo, record *store.DecisionRecord) {
+	if at.symbolStatus == nil {
+		return
+	}
+	now := time.Now()
+	for _, pos := range positions {
+		r, restricted := at.symbolStatus.Restriction(pos.Symbol)
+		if !restricted {
+			continue
+		}
+
+		if at.exchange == "binance" && at.symbolStatus.CloseDue(r, now) {
+			logger.Warnf("🚨 [%s] Closing %s %s before delisting: %s", at.name, pos.Symbol, pos.Side, r.Reason)
+			msg := fmt.Sprintf("Closed %s %s before delisting (%s)", pos.Symbol, pos.Side, r.Reason)
+			if err := at.emergencyClosePosition(pos.Symbol, pos.Side); err != nil {
+				msg = fmt.Sprintf("Failed to close %s %s before delisting (%s): %v", pos.Symbol, pos.Side, r.Reason, err)
+				logger.Errorf("❌ [%s] %s", at.name, msg)
+			} else {
+				at.ClearPeakPnLCache(pos.Symbol, pos.Side)
+			}
+			record.ExecutionLog = append(record.ExecutionLog, msg)
+			at.recordEvent(store.TraderEventSymbolRestricted, msg, restrictionDetails(r, pos))
+			continue
+		}
+
+		alertKey := pos.Symbol + "_" + pos.Side
+		today := now.Format("2006-01-02")
+		if at.delistAlerts[alertKey] == today {
+			continue
+		}
+		if at.delistAlerts == nil {
+			at.delistAlerts = make(map[string]string)
+		}
+		at.delistAlerts[alertKey] = today
+		msg := fmt.Sprintf("Open %s %s position in restricted contract: %s", pos.Symbol, pos.Side, r.Reason)
+		logger.Warnf("⚠️ [%s] %s", at.name, msg)
+		record.ExecutionLog = append(record.ExecutionLog, msg)
+		at.recordEvent(store.TraderEventSymbolRestricted, msg, restrictionDetails(r, pos))
+	}
+}
+
+// restrictionDetails timeline event details of a restricted position
+func restrictionDetails(r market.SymbolRestriction, pos kernel.PositionInfo) map[string]interface{} {
+	details := map[string]interface{}{
+		"symbol": r.Symbol,
+		"side":   pos.Side,
+		"status": r.Status,
+		"reason": r.Reason,
+	}
+	if !r.DelistAt.IsZero() {
+		details["delist_at"] = r.DelistAt
+	}
+	return details
+}
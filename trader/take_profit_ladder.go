@@ -0,0 +1,339 @@
+package trader
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/store"
+	"strings"
+	"time"
+)
+
+// ladderFillTolerance share of a leg's quantity the position may differ by and still count the leg as filled
+// (exchange quantity rounding)
+const ladderFillTolerance = 0.02
+
+// takeProfitLeg one level of a take-profit ladder
+type takeProfitLeg struct {
+	Price    float64
+	Quantity float64
+	Native   bool // Placed as a reduce-only order, otherwise closed at market by the watcher
+	Filled   bool
+}
+
+// takeProfitLadder partial take profits of a position and the trailing stop of the part left after them
+type takeProfitLadder struct {
+	Symbol      string
+	Side        string  // LONG/SHORT
+	Quantity    float64 // Opened quantity
+	Legs        []*takeProfitLeg
+	StopLoss    float64 // Current stop loss, moved by the trailing stop
+	TrailingPct float64 // 0 = fixed stop loss
+	BestPrice   float64 // Best price since the first fill, 0 before
+	PlacedAt    time.Time
+}
+
+// closedQuantity quantity the filled legs have closed
+func (l *takeProfitLadder) closedQuantity() float64 {
+	closed := 0.0
+	for _, leg := range l.Legs {
+		if leg.Filled {
+			closed += leg.Quantity
+		}
+	}
+	return closed
+}
+
+// hasOpenNativeLeg reports whether a leg placed on the exchange has not filled
+func (l *takeProfitLadder) hasOpenNativeLeg() bool {
+	for _, leg := range l.Legs {
+		if leg.Native && !leg.Filled {
+			return true
+		}
+	}
+	return false
+}
+
+// reached reports whether price is at or past a take profit level
+func (l *takeProfitLadder) reached(price, level float64) bool {
+	if l.Side == "SHORT" {
+		return price <= level
+	}
+	return price >= level
+}
+
+// newTakeProfitLadder builds the ladder of a market entry: the decision's take_profit_levels, else the
+// strategy's default ladder measured from the entry price. nil when neither applies.
+// Without a trailing stop, whatever the levels leave open is closed at the decision's take profit.
+func (at *AutoTrader) newTakeProfitLadder(decision *kernel.Decision, positionSide string, quantity, entryPrice float64) *takeProfitLadder {
+	levels, trailingPct := decision.TakeProfitLevels, decision.TrailingStopPct
+	if len(levels) == 0 && at.config.StrategyConfig != nil && entryPrice > 0 {
+		rc := at.config.StrategyConfig.RiskControl
+		for _, step := range rc.TakeProfitLadder {
+			if step.ProfitPct <= 0 || step.ClosePct <= 0 {
+				continue
+			}
+			price := entryPrice * (1 + step.ProfitPct/100)
+			if positionSide == "SHORT" {
+				price = entryPrice * (1 - step.ProfitPct/100)
+			}
+			levels = append(levels, kernel.TakeProfitLevel{Price: price, ClosePct: step.ClosePct})
+		}
+		trailingPct = rc.RunnerTrailingStopPct
+	}
+	if len(levels) == 0 || quantity <= 0 {
+		return nil
+	}
+
+	ladder := &takeProfitLadder{
+		Symbol:      decision.Symbol,
+		Side:        positionSide,
+		Quantity:    quantity,
+		StopLoss:    decision.StopLoss,
+		TrailingPct: trailingPct,
+		PlacedAt:    time.Now(),
+	}
+	remaining := quantity
+	for _, level := range levels {
+		legQty := math.Min(quantity*level.ClosePct/100, remaining)
+		if remaining-legQty < quantity*ladderFillTolerance {
+			legQty = remaining // No dust left behind by rounding
+		}
+		if legQty <= 0 {
+			break
+		}
+		ladder.Legs = append(ladder.Legs, &takeProfitLeg{Price: level.Price, Quantity: legQty})
+		remaining -= legQty
+	}
+	if remaining > 0 && trailingPct <= 0 && decision.TakeProfit > 0 {
+		last := ladder.Legs[len(ladder.Legs)-1]
+		price := decision.TakeProfit
+		if !ladder.reached(price, last.Price) {
+			price = last.Price
+		}
+		ladder.Legs = append(ladder.Legs, &takeProfitLeg{Price: price, Quantity: remaining})
+	}
+	return ladder
+}
+
+// placeTakeProfitLadder places the legs of a freshly protected position and tracks it.
+// Exchanges implementing PartialTakeProfitTrader get one reduce-only order per leg; other legs
+// (and legs the exchange rejects) are closed at market by the protection watcher.
+func (at *AutoTrader) placeTakeProfitLadder(ladder *takeProfitLadder) {
+	partial, native := at.trader.(PartialTakeProfitTrader)
+	for _, leg := range ladder.Legs {
+		if native {
+			if err := partial.SetPartialTakeProfit(ladder.Symbol, ladder.Side, leg.Quantity, leg.Price); err != nil {
+				logger.Infof("  ⚠ Failed to set partial take profit at %.4f, closing it at market instead: %v", leg.Price, err)
+			} else {
+				leg.Native = true
+			}
+		}
+		logger.Infof("  🎯 Take profit %.4f: close %.4f of %.4f", leg.Price, leg.Quantity, ladder.Quantity)
+	}
+	at.rememberStops(ladder.Symbol, ladder.Side, ladder.StopLoss, ladder.Legs[len(ladder.Legs)-1].Price)
+
+	at.protectionMutex.Lock()
+	defer at.protectionMutex.Unlock()
+	if at.takeProfitLadders == nil {
+		at.takeProfitLadders = make(map[string]*takeProfitLadder)
+	}
+	at.takeProfitLadders[ladder.Symbol+"_"+strings.ToLower(ladder.Side)] = ladder
+	at.saveProtection(store.ProtectionLadder, ladder.Symbol, ladder.Side, ladder)
+}
+
+// restoreTakeProfitLadders loads the ladders saved before a restart, with their filled legs and trailing stop.
+// Native legs are still on the exchange; ladders of positions closed meanwhile are dropped by the watcher.
+func (at *AutoTrader) restoreTakeProfitLadders() {
+	if at.store == nil {
+		return
+	}
+	saved, err := at.store.Protection().List(at.id, store.ProtectionLadder)
+	if err != nil {
+		logger.Infof("⚠️ [%s] %v", at.name, err)
+		return
+	}
+	if len(saved) == 0 {
+		return
+	}
+
+	at.protectionMutex.Lock()
+	defer at.protectionMutex.Unlock()
+	if at.takeProfitLadders == nil {
+		at.takeProfitLadders = make(map[string]*takeProfitLadder)
+	}
+	for _, p := range saved {
+		ladder := &takeProfitLadder{}
+		if err := p.Decode(ladder); err != nil {
+			logger.Infof("⚠️ [%s] %v", at.name, err)
+			continue
+		}
+		if len(ladder.Legs) == 0 {
+			continue
+		}
+		at.takeProfitLadders[ladder.Symbol+"_"+strings.ToLower(ladder.Side)] = ladder
+	}
+	logger.Infof("🎯 [%s] Restored %d take profit ladders", at.name, len(at.takeProfitLadders))
+}
+
+// ladderState the saved form of a ladder, to tell whether a check changed it
+func ladderState(ladder *takeProfitLadder) string {
+	data, _ := json.Marshal(ladder)
+	return string(data)
+}
+
+// checkTakeProfitLadders follows the ladders of open positions: closes emulated legs the price has
+// reached, marks legs filled from the position's quantity, and trails the stop loss of the rest once
+// the first leg has filled. Ladders of closed positions are dropped with their remaining orders.
+func (at *AutoTrader) checkTakeProfitLadders() {
+	at.protectionMutex.Lock()
+	if len(at.takeProfitLadders) == 0 {
+		at.protectionMutex.Unlock()
+		return
+	}
+	at.protectionMutex.Unlock()
+
+	positions, err := at.trader.GetPositions()
+	if err != nil {
+		logger.Infof("❌ Take profit ladder: failed to get positions: %v", err)
+		return
+	}
+	type positionState struct{ quantity, markPrice float64 }
+	open := make(map[string]positionState, len(positions))
+	for _, pos := range positions {
+		symbol, _ := pos["symbol"].(string)
+		side, _ := pos["side"].(string)
+		qty, _ := pos["positionAmt"].(float64)
+		mark, _ := pos["markPrice"].(float64)
+		open[symbol+"_"+strings.ToLower(side)] = positionState{quantity: math.Abs(qty), markPrice: mark}
+	}
+
+	at.protectionMutex.Lock()
+	defer at.protectionMutex.Unlock()
+	for key, ladder := range at.takeProfitLadders {
+		pos, ok := open[key]
+		if !ok {
+			if time.Since(ladder.PlacedAt) >= protectionGracePeriod {
+				delete(at.takeProfitLadders, key)
+				at.dropTakeProfitLadder(ladder)
+				at.deleteProtection(store.ProtectionLadder, ladder.Symbol, ladder.Side)
+			}
+			continue
+		}
+		before := ladderState(ladder)
+		at.closeReachedLegs(ladder, pos.markPrice)
+		at.markFilledLegs(ladder, pos.quantity)
+		at.trailRunnerStop(ladder, pos.quantity, pos.markPrice)
+		// Filled legs and the trailing stop are kept, so a restart neither closes a leg twice nor loosens the stop
+		if ladderState(ladder) != before {
+			at.saveProtection(store.ProtectionLadder, ladder.Symbol, ladder.Side, ladder)
+		}
+	}
+}
+
+// closeReachedLegs closes emulated legs whose level the price has reached
+func (at *AutoTrader) closeReachedLegs(ladder *takeProfitLadder, markPrice float64) {
+	for _, leg := range ladder.Legs {
+		if leg.Filled || leg.Native || markPrice <= 0 || !ladder.reached(markPrice, leg.Price) {
+			continue
+		}
+		_, err := exchangeCall(at, "Take profit "+ladder.Symbol, true, func() (map[string]interface{}, error) {
+			if ladder.Side == "SHORT" {
+				return at.trader.CloseShort(ladder.Symbol, leg.Quantity)
+			}
+			return at.trader.CloseLong(ladder.Symbol, leg.Quantity)
+		})
+		if err != nil {
+			logger.Infof("⚠️ [%s] Failed to take profit on %s %s at %.4f: %v", at.name, ladder.Symbol, strings.ToLower(ladder.Side), markPrice, err)
+			return
+		}
+		leg.Filled = true
+		logger.Infof("🎯 [%s] %s %s reached %.4f, closed %.4f", at.name, ladder.Symbol, strings.ToLower(ladder.Side), leg.Price, leg.Quantity)
+	}
+}
+
+// markFilledLegs marks native legs filled once the position has shrunk by their quantity, nearest level first
+func (at *AutoTrader) markFilledLegs(ladder *takeProfitLadder, quantity float64) {
+	reduced := ladder.Quantity - quantity
+	closed := ladder.closedQuantity()
+	for _, leg := range ladder.Legs {
+		if leg.Filled || !leg.Native {
+			continue
+		}
+		if closed+leg.Quantity*(1-ladderFillTolerance) > reduced {
+			return
+		}
+		leg.Filled = true
+		closed += leg.Quantity
+		logger.Infof("🎯 [%s] %s %s take profit at %.4f filled (%.4f)", at.name, ladder.Symbol, strings.ToLower(ladder.Side), leg.Price, leg.Quantity)
+	}
+}
+
+// trailRunnerStop moves the stop loss of the rest of the position after the first fill, keeping it
+// TrailingPct from the best price since, and only ever towards profit
+func (at *AutoTrader) trailRunnerStop(ladder *takeProfitLadder, quantity, markPrice float64) {
+	if ladder.TrailingPct <= 0 || markPrice <= 0 || quantity <= 0 || ladder.closedQuantity() == 0 {
+		return
+	}
+	if ladder.BestPrice == 0 || ladder.reached(markPrice, ladder.BestPrice) {
+		ladder.BestPrice = markPrice
+	}
+	stop := ladder.BestPrice * (1 - ladder.TrailingPct/100)
+	if ladder.Side == "SHORT" {
+		stop = ladder.BestPrice * (1 + ladder.TrailingPct/100)
+	}
+	// Moves smaller than a tenth of the trail distance are not worth replacing the order
+	step := ladder.BestPrice * ladder.TrailingPct / 1000
+	if ladder.StopLoss > 0 && (ladder.Side == "SHORT" && stop > ladder.StopLoss-step || ladder.Side != "SHORT" && stop < ladder.StopLoss+step) {
+		return
+	}
+
+	if err := at.trader.CancelStopLossOrders(ladder.Symbol); err != nil {
+		logger.Infof("⚠️ [%s] Trailing stop: failed to cancel the stop loss of %s: %v", at.name, ladder.Symbol, err)
+		return
+	}
+	if err := at.trader.SetStopLoss(ladder.Symbol, ladder.Side, quantity, stop); err != nil {
+		logger.Errorf("🚨 [%s] Trailing stop of %s %s could not be placed: %v", at.name, ladder.Symbol, strings.ToLower(ladder.Side), err)
+		details := map[string]interface{}{"symbol": ladder.Symbol, "side": ladder.Side, "stop_loss": stop, "error": err.Error()}
+		if ladder.StopLoss > 0 {
+			if restoreErr := at.trader.SetStopLoss(ladder.Symbol, ladder.Side, quantity, ladder.StopLoss); restoreErr == nil {
+				return
+			}
+		}
+		at.recordEvent(store.TraderEventUnprotected, fmt.Sprintf("Trailing stop of %s %s could not be placed", ladder.Symbol, strings.ToLower(ladder.Side)), details)
+		ladder.StopLoss = 0
+		return
+	}
+	logger.Infof("📈 [%s] %s %s trailing stop %.4f -> %.4f (best %.4f)", at.name, ladder.Symbol, strings.ToLower(ladder.Side), ladder.StopLoss, stop, ladder.BestPrice)
+	ladder.StopLoss = stop
+	at.rememberStops(ladder.Symbol, ladder.Side, stop, ladder.Legs[len(ladder.Legs)-1].Price)
+}
+
+// dropTakeProfitLadder cancels the orders left once the position is gone: the unfilled legs, and the stop
+// loss too when the legs closed the whole position. Orders can only be cancelled per symbol, so they are
+// left alone while the opposite side (hedge mode) is tracked too; being reduce-only they cannot open a position.
+func (at *AutoTrader) dropTakeProfitLadder(ladder *takeProfitLadder) {
+	for _, other := range at.takeProfitLadders {
+		if other.Symbol == ladder.Symbol {
+			return
+		}
+	}
+	for _, pair := range at.protectionPairs {
+		if pair.Symbol == ladder.Symbol {
+			return
+		}
+	}
+
+	cancel, what := at.trader.CancelTakeProfitOrders, "take profits"
+	if ladder.closedQuantity() >= ladder.Quantity*(1-ladderFillTolerance) {
+		cancel, what = at.trader.CancelStopOrders, "stop loss/take profits"
+	} else if !ladder.hasOpenNativeLeg() {
+		return
+	}
+	logger.Infof("🎯 [%s] %s position closed, cancelling remaining %s", at.name, ladder.Symbol, what)
+	if err := cancel(ladder.Symbol); err != nil {
+		logger.Infof("⚠️ [%s] Failed to cancel remaining %s for %s: %v", at.name, what, ladder.Symbol, err)
+	}
+}
@@ -0,0 +1,184 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"nofx/kernel"
+	"nofx/store"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// stubLadderTrader closes at market and moves stop losses
+type stubLadderTrader struct {
+	stubProtectionTrader
+	closes      []float64
+	stopPrices  []float64
+	tpCancelled int
+}
+
+func (s *stubLadderTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	s.closes = append(s.closes, quantity)
+	return map[string]interface{}{}, nil
+}
+
+func (s *stubLadderTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	s.stopPrices = append(s.stopPrices, stopPrice)
+	return nil
+}
+
+func (s *stubLadderTrader) CancelStopLossOrders(symbol string) error { return nil }
+
+func (s *stubLadderTrader) CancelTakeProfitOrders(symbol string) error {
+	s.tpCancelled++
+	return nil
+}
+
+// stubPartialTPTrader places partial take profits natively
+type stubPartialTPTrader struct {
+	stubLadderTrader
+	partials []string
+}
+
+func (s *stubPartialTPTrader) SetPartialTakeProfit(symbol string, positionSide string, quantity, price float64) error {
+	s.partials = append(s.partials, fmt.Sprintf("%.2f@%.0f", quantity, price))
+	return nil
+}
+
+func legsOf(l *takeProfitLadder) string {
+	out := ""
+	for _, leg := range l.Legs {
+		out += fmt.Sprintf("%.2f@%.0f ", leg.Quantity, leg.Price)
+	}
+	return out
+}
+
+func TestNewTakeProfitLadder(t *testing.T) {
+	at := &AutoTrader{}
+	decision := &kernel.Decision{Symbol: "BTCUSDT", StopLoss: 95, TakeProfit: 106,
+		TakeProfitLevels: []kernel.TakeProfitLevel{{Price: 102, ClosePct: 50}, {Price: 104, ClosePct: 25}}}
+
+	// The rest closes at the take profit without a trailing stop
+	if got := legsOf(at.newTakeProfitLadder(decision, "LONG", 1, 100)); got != "0.50@102 0.25@104 0.25@106 " {
+		t.Errorf("legs = %s", got)
+	}
+	decision.TrailingStopPct = 1
+	if got := legsOf(at.newTakeProfitLadder(decision, "LONG", 1, 100)); got != "0.50@102 0.25@104 " {
+		t.Errorf("legs with a runner = %s", got)
+	}
+
+	// Strategy default ladder, measured from the entry price
+	at.config.StrategyConfig = &store.StrategyConfig{RiskControl: store.RiskControlConfig{
+		TakeProfitLadder: []store.TakeProfitStep{{ProfitPct: 2, ClosePct: 60}, {ProfitPct: 4, ClosePct: 40}},
+	}}
+	short := &kernel.Decision{Symbol: "BTCUSDT", StopLoss: 105, TakeProfit: 90}
+	ladder := at.newTakeProfitLadder(short, "SHORT", 2, 100)
+	if got := legsOf(ladder); got != "1.20@98 0.80@96 " || ladder.TrailingPct != 0 {
+		t.Errorf("strategy legs = %s", got)
+	}
+
+	at.config.StrategyConfig = nil
+	if at.newTakeProfitLadder(short, "SHORT", 2, 100) != nil {
+		t.Error("a decision without levels should keep its single take profit")
+	}
+}
+
+func TestTakeProfitLadder_EmulatedWithTrailingStop(t *testing.T) {
+	exchange := &stubLadderTrader{}
+	at := &AutoTrader{name: "test", trader: exchange}
+	decision := &kernel.Decision{Symbol: "BTCUSDT", StopLoss: 95, TakeProfit: 104, TrailingStopPct: 1,
+		TakeProfitLevels: []kernel.TakeProfitLevel{{Price: 102, ClosePct: 50}, {Price: 104, ClosePct: 25}}}
+	at.placeTakeProfitLadder(at.newTakeProfitLadder(decision, "LONG", 1, 100))
+
+	tick := func(qty, mark float64) {
+		exchange.positions = []map[string]interface{}{{"symbol": "BTCUSDT", "side": "long", "positionAmt": qty, "markPrice": mark}}
+		at.checkTakeProfitLadders()
+	}
+
+	tick(1, 101)
+	if len(exchange.closes) != 0 || len(exchange.stopPrices) != 0 {
+		t.Fatalf("nothing should happen below the first level: %v %v", exchange.closes, exchange.stopPrices)
+	}
+	tick(1, 102.5)
+	if len(exchange.closes) != 1 || exchange.closes[0] != 0.5 {
+		t.Fatalf("first level should close half, got %v", exchange.closes)
+	}
+	// The runner's stop trails 1% under the best price once a level filled, never moving back
+	tick(0.5, 103)
+	tick(0.5, 102.5)
+	if len(exchange.stopPrices) != 2 || math.Abs(exchange.stopPrices[1]-101.97) > 1e-9 {
+		t.Fatalf("trailing stops = %v", exchange.stopPrices)
+	}
+	tick(0.5, 104)
+	if len(exchange.closes) != 2 || exchange.closes[1] != 0.25 {
+		t.Errorf("second level should close a quarter, got %v", exchange.closes)
+	}
+	if last := exchange.stopPrices[len(exchange.stopPrices)-1]; math.Abs(last-102.96) > 1e-9 {
+		t.Errorf("stop should follow the new high, got %v", last)
+	}
+}
+
+func TestTakeProfitLadder_NativeLegs(t *testing.T) {
+	exchange := &stubPartialTPTrader{}
+	at := &AutoTrader{name: "test", trader: exchange}
+	decision := &kernel.Decision{Symbol: "BTCUSDT", StopLoss: 95, TakeProfit: 106,
+		TakeProfitLevels: []kernel.TakeProfitLevel{{Price: 102, ClosePct: 50}, {Price: 104, ClosePct: 50}}}
+	at.placeTakeProfitLadder(at.newTakeProfitLadder(decision, "LONG", 1, 100))
+	if fmt.Sprint(exchange.partials) != "[0.50@102 0.50@104]" {
+		t.Fatalf("partial take profits = %v", exchange.partials)
+	}
+
+	// Fills are read from the position shrinking, the watcher never closes native legs itself
+	exchange.positions = []map[string]interface{}{{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.5, "markPrice": 102.5}}
+	at.checkTakeProfitLadders()
+	ladder := at.takeProfitLadders["BTCUSDT_long"]
+	if !ladder.Legs[0].Filled || ladder.Legs[1].Filled || len(exchange.closes) != 0 {
+		t.Fatalf("legs = %+v %+v, closes %v", *ladder.Legs[0], *ladder.Legs[1], exchange.closes)
+	}
+
+	// Stopped out: the remaining take profit is cancelled once past the grace period
+	exchange.positions = nil
+	ladder.PlacedAt = time.Now().Add(-protectionGracePeriod)
+	at.checkTakeProfitLadders()
+	if exchange.tpCancelled != 1 || len(at.takeProfitLadders) != 0 {
+		t.Errorf("cancelled %d, tracked %d", exchange.tpCancelled, len(at.takeProfitLadders))
+	}
+}
+
+func TestTakeProfitLadder_RestoredAfterRestart(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+
+	exchange := &stubLadderTrader{}
+	at := &AutoTrader{id: "trader-ladder", name: "test", store: st, trader: exchange}
+	decision := &kernel.Decision{Symbol: "BTCUSDT", StopLoss: 95, TakeProfit: 104, TrailingStopPct: 1,
+		TakeProfitLevels: []kernel.TakeProfitLevel{{Price: 102, ClosePct: 50}, {Price: 104, ClosePct: 25}}}
+	at.placeTakeProfitLadder(at.newTakeProfitLadder(decision, "LONG", 1, 100))
+	exchange.positions = []map[string]interface{}{{"symbol": "BTCUSDT", "side": "long", "positionAmt": 1.0, "markPrice": 103.0}}
+	at.checkTakeProfitLadders()
+
+	// The restarted trader neither closes the first level again nor moves the stop back
+	restarted := &AutoTrader{id: "trader-ladder", name: "test", store: st, trader: exchange}
+	restarted.restoreTakeProfitLadders()
+	ladder := restarted.takeProfitLadders["BTCUSDT_long"]
+	if ladder == nil || !ladder.Legs[0].Filled || ladder.Legs[1].Filled || ladder.BestPrice != 103 {
+		t.Fatalf("restored ladder = %+v", ladder)
+	}
+	exchange.positions[0]["positionAmt"], exchange.positions[0]["markPrice"] = 0.5, 102.5
+	restarted.checkTakeProfitLadders()
+	if len(exchange.closes) != 1 || len(exchange.stopPrices) != 1 {
+		t.Errorf("closes = %v, stops = %v", exchange.closes, exchange.stopPrices)
+	}
+
+	// Closed position: the ladder is forgotten
+	exchange.positions = nil
+	ladder.PlacedAt = time.Now().Add(-protectionGracePeriod)
+	restarted.checkTakeProfitLadders()
+	if saved, _ := st.Protection().List(at.id, store.ProtectionLadder); len(saved) != 0 {
+		t.Errorf("expected no stored ladder, got %d", len(saved))
+	}
+}
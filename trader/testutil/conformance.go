@@ -0,0 +1,226 @@
+package testutil
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"nofx/trader/types"
+)
+
+// ConformanceOptions Exchange-specific knobs for the conformance suite
+type ConformanceOptions struct {
+	// Symbol is a tradable symbol the mock server knows about (default BTCUSDT)
+	Symbol string
+	// UnknownSymbol must be rejected by GetMarketPrice (default INVALIDUSDT)
+	UnknownSymbol string
+	// FlatSymbol is a symbol with no open position on the mock server (default ETHUSDT)
+	FlatSymbol string
+	// PositionFetches returns how many times the adapter hit the positions endpoint.
+	// When set, the suite verifies that mutating calls invalidate the position cache.
+	PositionFetches func() int
+}
+
+// ConformanceSuite Trader interface contract suite
+// Unlike TraderTestSuite (which only checks that calls succeed), this suite checks
+// the invariants the AutoTrader relies on: field types, side/sign conventions,
+// quantity formatting, close-without-position semantics and cache invalidation.
+// Aster, Binance, Bitget, Bybit, Gate and Hyperliquid run it against their mock servers.
+// Not covered yet:
+//   - OKX and KuCoin: base URLs are not injectable, FormatQuantity returns contract counts
+//     and closing a flat symbol returns a NO_POSITION status instead of an error
+//   - Lighter: orders are SDK-signed transactions that a mock server cannot accept
+type ConformanceSuite struct {
+	T      *testing.T
+	Trader types.Trader
+	Opts   ConformanceOptions
+}
+
+// NewConformanceSuite Create conformance suite with default options filled in
+func NewConformanceSuite(t *testing.T, trader types.Trader, opts ConformanceOptions) *ConformanceSuite {
+	if opts.Symbol == "" {
+		opts.Symbol = "BTCUSDT"
+	}
+	if opts.UnknownSymbol == "" {
+		opts.UnknownSymbol = "INVALIDUSDT"
+	}
+	if opts.FlatSymbol == "" {
+		opts.FlatSymbol = "ETHUSDT"
+	}
+	return &ConformanceSuite{T: t, Trader: trader, Opts: opts}
+}
+
+// RunConformance Run all contract checks
+func (s *ConformanceSuite) RunConformance() {
+	s.T.Run("BalanceContract", func(t *testing.T) { s.testBalanceContract(t) })
+	s.T.Run("PositionContract", func(t *testing.T) { s.testPositionContract(t) })
+	s.T.Run("MarketPriceContract", func(t *testing.T) { s.testMarketPriceContract(t) })
+	s.T.Run("FormatQuantityContract", func(t *testing.T) { s.testFormatQuantityContract(t) })
+	s.T.Run("CloseWithoutPosition", func(t *testing.T) { s.testCloseWithoutPosition(t) })
+	s.T.Run("OpenOrdersContract", func(t *testing.T) { s.testOpenOrdersContract(t) })
+	if s.Opts.PositionFetches != nil {
+		s.T.Run("CacheInvalidation", func(t *testing.T) { s.testCacheInvalidation(t) })
+	}
+}
+
+// testBalanceContract balance values must be float64 (AutoTrader type-asserts them)
+func (s *ConformanceSuite) testBalanceContract(t *testing.T) {
+	balance, err := s.Trader.GetBalance()
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, key := range []string{"totalWalletBalance", "availableBalance"} {
+		v, ok := balance[key].(float64)
+		if assert.Truef(t, ok, "%s must be float64, got %T", key, balance[key]) {
+			assert.GreaterOrEqualf(t, v, 0.0, "%s must not be negative", key)
+			assert.Falsef(t, math.IsNaN(v), "%s must not be NaN", key)
+		}
+	}
+}
+
+// testPositionContract side must be long/short and quantity sign must agree with side
+func (s *ConformanceSuite) testPositionContract(t *testing.T) {
+	positions, err := s.Trader.GetPositions()
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, pos := range positions {
+		symbol, _ := pos["symbol"].(string)
+		assert.NotEmpty(t, symbol, "position symbol must be set")
+		assert.Falsef(t, strings.ContainsAny(symbol, "-_/"), "symbol %q must be in BASEQUOTE form", symbol)
+
+		side, _ := pos["side"].(string)
+		assert.Containsf(t, []string{"long", "short"}, side, "side must be lowercase long/short, got %q", side)
+
+		amt, ok := pos["positionAmt"].(float64)
+		if assert.Truef(t, ok, "positionAmt must be float64, got %T", pos["positionAmt"]) {
+			assert.NotZero(t, amt, "flat positions must be filtered out")
+			if side == "long" {
+				assert.Greater(t, amt, 0.0, "long positionAmt must be positive")
+			}
+		}
+
+		for _, key := range []string{"entryPrice", "markPrice"} {
+			v, ok := pos[key].(float64)
+			if assert.Truef(t, ok, "%s must be float64, got %T", key, pos[key]) {
+				assert.Greaterf(t, v, 0.0, "%s must be positive", key)
+			}
+		}
+	}
+}
+
+// testMarketPriceContract known symbol returns a positive price, unknown symbol errors
+func (s *ConformanceSuite) testMarketPriceContract(t *testing.T) {
+	price, err := s.Trader.GetMarketPrice(s.Opts.Symbol)
+	if assert.NoError(t, err) {
+		assert.Greater(t, price, 0.0)
+	}
+	_, err = s.Trader.GetMarketPrice(s.Opts.UnknownSymbol)
+	assert.Error(t, err, "unknown symbol must return an error instead of a zero price")
+}
+
+// testFormatQuantityContract formatted quantity must be plain decimal and close to the input
+func (s *ConformanceSuite) testFormatQuantityContract(t *testing.T) {
+	for _, qty := range []float64{1.23456789, 0.001, 0.00001234} {
+		formatted, err := s.Trader.FormatQuantity(s.Opts.Symbol, qty)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		assert.NotContainsf(t, strings.ToLower(formatted), "e", "quantity %q must not use scientific notation", formatted)
+		parsed, err := strconv.ParseFloat(formatted, 64)
+		if assert.NoErrorf(t, err, "quantity %q must parse as a number", formatted) && qty >= 0.001 {
+			assert.LessOrEqualf(t, math.Abs(parsed-qty)/qty, 0.01,
+				"formatted quantity %q drifts more than 1%% from %v", formatted, qty)
+		}
+	}
+}
+
+// testCloseWithoutPosition close-all on a flat symbol must error instead of opening a reverse position
+func (s *ConformanceSuite) testCloseWithoutPosition(t *testing.T) {
+	_, err := s.Trader.CloseLong(s.Opts.FlatSymbol, 0)
+	assert.Error(t, err, "CloseLong(qty=0) without a long position must error")
+	_, err = s.Trader.CloseShort(s.Opts.FlatSymbol, 0)
+	assert.Error(t, err, "CloseShort(qty=0) without a short position must error")
+}
+
+// testOpenOrdersContract open orders must carry symbol and BUY/SELL side
+func (s *ConformanceSuite) testOpenOrdersContract(t *testing.T) {
+	orders, err := s.Trader.GetOpenOrders(s.Opts.Symbol)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, o := range orders {
+		assert.NotEmpty(t, o.OrderID)
+		assert.NotEmpty(t, o.Symbol)
+		assert.Containsf(t, []string{"BUY", "SELL"}, o.Side, "side must be BUY/SELL, got %q", o.Side)
+		assert.GreaterOrEqual(t, o.Quantity, 0.0)
+	}
+}
+
+// testCacheInvalidation positions fetched after a trade must not come from a stale cache
+func (s *ConformanceSuite) testCacheInvalidation(t *testing.T) {
+	if _, err := s.Trader.GetPositions(); !assert.NoError(t, err) {
+		return
+	}
+	before := s.Opts.PositionFetches()
+
+	if _, err := s.Trader.OpenLong(s.Opts.Symbol, 0.01, 10); !assert.NoError(t, err) {
+		return
+	}
+	if _, err := s.Trader.GetPositions(); !assert.NoError(t, err) {
+		return
+	}
+	assert.Greater(t, s.Opts.PositionFetches(), before,
+		"GetPositions after OpenLong must refetch from the exchange")
+}
+
+// RequestCounter counts mock server requests by path
+// Wrap the mock handler with Wrap and pass Count(path) as PositionFetches
+type RequestCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// NewRequestCounter Create request counter
+func NewRequestCounter() *RequestCounter {
+	return &RequestCounter{counts: make(map[string]int)}
+}
+
+// Wrap returns a handler that records every request path before delegating
+func (c *RequestCounter) Wrap(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		c.counts[r.URL.Path]++
+		c.mu.Unlock()
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Count returns a func reporting the number of requests whose path contains substr
+func (c *RequestCounter) Count(substr string) func() int {
+	return func() int {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		total := 0
+		for path, n := range c.counts {
+			if strings.Contains(path, substr) {
+				total += n
+			}
+		}
+		return total
+	}
+}
+
+// CountPath returns a func reporting the number of requests to exactly path
+// Use it instead of Count when other endpoints extend the path (e.g. /positions/{symbol}/leverage)
+func (c *RequestCounter) CountPath(path string) func() int {
+	return func() int {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.counts[path]
+	}
+}
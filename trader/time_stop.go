@@ -0,0 +1,61 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/kernel"
+	"nofx/logger"
+	"nofx/store"
+	"time"
+)
+
+// handleStalePositions applies the strategy's max holding time to the open positions. Stale positions
+// are closed when MaxHoldingAction is "close" (and dropped from ctx, so the AI only sees what is left),
+// otherwise the prompt flags them for the AI to re-evaluate. Either way the decision record notes the time stop.
+func (at *AutoTrader) handleStalePositions(ctx *kernel.Context, record *store.DecisionRecord) {
+	if at.config.StrategyConfig == nil || at.config.StrategyConfig.RiskControl.MaxHoldingHours <= 0 {
+		return
+	}
+	rc := at.config.StrategyConfig.RiskControl
+	now := time.Now()
+
+	remaining := ctx.Positions[:0]
+	for _, pos := range ctx.Positions {
+		held, stale := kernel.HoldingTimeExceeded(rc, pos.UpdateTime, now)
+		if !stale {
+			remaining = append(remaining, pos)
+			continue
+		}
+		heldFor := fmt.Sprintf("held %.1fh, max %gh", held.Hours(), rc.MaxHoldingHours)
+		if rc.MaxHoldingAction != store.HoldingActionClose {
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("⏰ Time stop: %s %s %s, flagged for re-evaluation", pos.Symbol, pos.Side, heldFor))
+			remaining = append(remaining, pos)
+			continue
+		}
+
+		logger.Infof("⏰ [%s] Time stop: closing %s %s (%s)", at.name, pos.Symbol, pos.Side, heldFor)
+		action := store.DecisionAction{
+			Action:    "close_" + pos.Side,
+			Symbol:    pos.Symbol,
+			Quantity:  pos.Quantity,
+			Leverage:  pos.Leverage,
+			Price:     pos.MarkPrice,
+			Reasoning: "Time stop: " + heldFor,
+			Timestamp: now.UTC(),
+		}
+		if err := at.emergencyClosePosition(pos.Symbol, pos.Side); err != nil {
+			action.Error = err.Error()
+			record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("❌ Time stop: failed to close %s %s: %v", pos.Symbol, pos.Side, err))
+			record.Decisions = append(record.Decisions, action)
+			remaining = append(remaining, pos)
+			continue
+		}
+		action.Success = true
+		record.ExecutionLog = append(record.ExecutionLog, fmt.Sprintf("⏰ Time stop: closed %s %s (%s)", pos.Symbol, pos.Side, heldFor))
+		record.Decisions = append(record.Decisions, action)
+		at.untrackPosition(pos.Symbol, pos.Side)
+		at.recordPositionClose(trackedPosition{Symbol: pos.Symbol, Side: pos.Side, EntryPrice: pos.EntryPrice, Quantity: pos.Quantity}, pos.MarkPrice, "time_stop")
+		at.ClearPeakPnLCache(pos.Symbol, pos.Side)
+	}
+	ctx.Positions = remaining
+	ctx.Account.PositionCount = len(remaining)
+}
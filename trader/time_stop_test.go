@@ -0,0 +1,40 @@
+package trader
+
+import (
+	"nofx/kernel"
+	"nofx/store"
+	"testing"
+	"time"
+)
+
+func TestHandleStalePositions(t *testing.T) {
+	exchange := &stubLadderTrader{}
+	at := &AutoTrader{name: "test", trader: exchange}
+	at.config.StrategyConfig = &store.StrategyConfig{RiskControl: store.RiskControlConfig{MaxHoldingHours: 48}}
+
+	newContext := func() *kernel.Context {
+		return &kernel.Context{Positions: []kernel.PositionInfo{
+			{Symbol: "BTCUSDT", Side: "long", Quantity: 1, MarkPrice: 100, UpdateTime: time.Now().Add(-50 * time.Hour).UnixMilli()},
+			{Symbol: "ETHUSDT", Side: "long", Quantity: 1, MarkPrice: 10, UpdateTime: time.Now().Add(-time.Hour).UnixMilli()},
+		}}
+	}
+
+	// Flag (default): positions stay, the record notes the time stop
+	ctx, record := newContext(), &store.DecisionRecord{}
+	at.handleStalePositions(ctx, record)
+	if len(ctx.Positions) != 2 || len(exchange.closes) != 0 || len(record.ExecutionLog) != 1 || len(record.Decisions) != 0 {
+		t.Fatalf("flag mode: positions %d, closes %v, log %v", len(ctx.Positions), exchange.closes, record.ExecutionLog)
+	}
+
+	// Close: the stale position is closed and recorded as a time stop decision
+	at.config.StrategyConfig.RiskControl.MaxHoldingAction = store.HoldingActionClose
+	ctx, record = newContext(), &store.DecisionRecord{}
+	at.handleStalePositions(ctx, record)
+	if len(exchange.closes) != 1 || len(ctx.Positions) != 1 || ctx.Positions[0].Symbol != "ETHUSDT" || ctx.Account.PositionCount != 1 {
+		t.Fatalf("close mode: closes %v, positions %+v", exchange.closes, ctx.Positions)
+	}
+	if len(record.Decisions) != 1 || record.Decisions[0].Action != "close_long" || !record.Decisions[0].Success ||
+		record.Decisions[0].Reasoning != "Time stop: held 50.0h, max 48h" {
+		t.Errorf("decision record = %+v", record.Decisions)
+	}
+}
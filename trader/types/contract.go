@@ -0,0 +1,80 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Contract types a trader can trade
+const (
+	ContractLinear  = "linear"  // USDT-margined, quantity in base coin, margin and PnL in USDT
+	ContractInverse = "inverse" // Coin-margined, quantity in USD contracts, margin and PnL in the base coin
+)
+
+// ContractSpec contract terms of an inverse (coin-margined) perpetual
+type ContractSpec struct {
+	Symbol       string  // Exchange contract symbol (e.g. BTCUSD_PERP on Binance, BTCUSD on Bybit)
+	MarginAsset  string  // Coin margin and PnL are denominated in (e.g. BTC)
+	ContractSize float64 // USD face value of one contract
+}
+
+// Contracts whole contracts of a base coin quantity at price (nearest, never negative)
+func (s ContractSpec) Contracts(quantity, price float64) int64 {
+	if s.ContractSize <= 0 || quantity <= 0 || price <= 0 {
+		return 0
+	}
+	return int64(math.Round(quantity * price / s.ContractSize))
+}
+
+// Quantity base coin quantity of contracts at price
+func (s ContractSpec) Quantity(contracts, price float64) float64 {
+	if price <= 0 {
+		return 0
+	}
+	return contracts * s.ContractSize / price
+}
+
+// InversePnL PnL in the margin coin of an inverse position closed at exitPrice
+// Long: contracts × size × (1/entry − 1/exit); short is the negation.
+func InversePnL(isLong bool, contracts, contractSize, entryPrice, exitPrice float64) float64 {
+	if entryPrice <= 0 || exitPrice <= 0 {
+		return 0
+	}
+	pnl := contracts * contractSize * (1/entryPrice - 1/exitPrice)
+	if !isLong {
+		pnl = -pnl
+	}
+	return pnl
+}
+
+// InverseMargin initial margin in the margin coin of an inverse position opened at price
+func InverseMargin(contracts, contractSize, price float64, leverage int) float64 {
+	if price <= 0 || leverage <= 0 {
+		return 0
+	}
+	return contracts * contractSize / price / float64(leverage)
+}
+
+// InverseBaseAsset base coin of a USDT pair an inverse trader accepts (BTCUSDT -> BTC)
+func InverseBaseAsset(symbol string) (string, error) {
+	base := strings.TrimSuffix(strings.ToUpper(symbol), "USDT")
+	if base == "" || base == strings.ToUpper(symbol) {
+		return "", fmt.Errorf("%s is not a USDT pair, inverse contracts are addressed as {COIN}USDT", symbol)
+	}
+	return base, nil
+}
+
+// InverseTrader extends Trader interface for coin-margined inverse contracts
+// Symbols stay in the USDT pair form used everywhere else (BTCUSDT) and quantities in base coin:
+// the trader maps them to its contract symbol and whole contracts. Balances, margin and PnL are
+// reported in MarginAsset, and only the contract margined in it can be traded.
+type InverseTrader interface {
+	Trader
+
+	// MarginAsset coin the account is margined in (e.g. BTC)
+	MarginAsset() string
+
+	// GetContractSpec Get the contract terms of a symbol (e.g. BTCUSDT -> BTCUSD_PERP, 100 USD per contract)
+	GetContractSpec(symbol string) (*ContractSpec, error)
+}
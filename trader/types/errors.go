@@ -0,0 +1,128 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+)
+
+// ErrorClass category of an exchange error, deciding whether a failed call is retried
+type ErrorClass string
+
+// Exchange error classes
+const (
+	ErrClassRateLimited        ErrorClass = "rate_limited"        // Request rejected by rate limits, retry after a delay
+	ErrClassAuth               ErrorClass = "auth"                // Invalid API key, signature or permissions
+	ErrClassInsufficientMargin ErrorClass = "insufficient_margin" // Not enough balance or margin for the order
+	ErrClassInvalidSymbol      ErrorClass = "invalid_symbol"      // Symbol unknown, delisted or not tradable
+	ErrClassInvalidOrder       ErrorClass = "invalid_order"       // Quantity or price outside the symbol's filters (lot size, tick size, min notional)
+	ErrClassNetwork            ErrorClass = "network"             // Timeout, connection failure or exchange-side outage
+	ErrClassUnknown            ErrorClass = "unknown"
+)
+
+// ErrorClasses all classes, in display order
+var ErrorClasses = []ErrorClass{
+	ErrClassRateLimited, ErrClassAuth, ErrClassInsufficientMargin, ErrClassInvalidSymbol, ErrClassInvalidOrder, ErrClassNetwork,
+	ErrClassUnknown,
+}
+
+// ExchangeError exchange error tagged with its class and the exchange's native error code
+// The message is the wrapped error's, so tagging an error does not change what is logged.
+type ExchangeError struct {
+	Class ErrorClass
+	Code  string // Native error code, e.g. "-2019" (Binance) or "51008" (OKX), empty if none
+	Err   error
+}
+
+func (e *ExchangeError) Error() string { return e.Err.Error() }
+
+func (e *ExchangeError) Unwrap() error { return e.Err }
+
+// NewExchangeError tags err with a class and native error code
+func NewExchangeError(class ErrorClass, code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ExchangeError{Class: class, Code: code, Err: err}
+}
+
+// ErrorClassifier extends Trader interface with exchange-specific error classification
+// Exchanges whose SDK returns native error codes should implement this interface
+type ErrorClassifier interface {
+	// ClassifyError classifies an error returned by any of the trader's calls
+	ClassifyError(err error) ErrorClass
+}
+
+// ClassifyError classifies an exchange error
+// A tagged ExchangeError keeps its class, other errors are matched against messages common to all exchanges.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ""
+	}
+	var exErr *ExchangeError
+	if errors.As(err, &exErr) && exErr.Class != "" && exErr.Class != ErrClassUnknown {
+		return exErr.Class
+	}
+	return ClassifyMessage(err)
+}
+
+// ClassifyMessage classifies an error by the messages and HTTP statuses exchanges commonly return
+func ClassifyMessage(err error) ErrorClass {
+	if err == nil {
+		return ""
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrClassNetwork
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ErrClassNetwork
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case containsAny(msg, "http 429", "http 418", "429 too many", "too many requests", "too many visits",
+		"rate limit", "too frequent", "too many cumulative requests"):
+		return ErrClassRateLimited
+	case containsAny(msg, "http 401", "http 403", "401 unauthorized", "403 forbidden", "invalid api", "api key",
+		"api-key", "apikey", "signature", "unauthorized", "permission denied", "invalid key", "passphrase"):
+		return ErrClassAuth
+	case containsAny(msg, "insufficient", "not enough", "margin is insufficient", "exceeds available"):
+		return ErrClassInsufficientMargin
+	case containsAny(msg, "invalid symbol", "unknown symbol", "symbol not found", "symbol not exist",
+		"symbol does not exist", "symbol invalid", "symbol is invalid", "contract not found", "unknown asset",
+		"instrument id does not exist", "delisted"):
+		return ErrClassInvalidSymbol
+	case containsAny(msg, "below minimum", "min notional", "minimum notional", "minimum order", "lot size",
+		"precision is over", "quantity less than", "qty invalid"):
+		return ErrClassInvalidOrder
+	case containsAny(msg, "timeout", "timed out", "deadline exceeded", "connection reset", "connection refused",
+		"broken pipe", "no such host", "eof", "tls handshake", "http 500", "http 502", "http 503", "http 504",
+		"service unavailable", "bad gateway", "system busy", "server busy"):
+		return ErrClassNetwork
+	}
+	return ErrClassUnknown
+}
+
+// HTTPStatusClass class of an HTTP error status, empty for statuses without one
+func HTTPStatusClass(status int) ErrorClass {
+	switch {
+	case status == 429 || status == 418:
+		return ErrClassRateLimited
+	case status == 401 || status == 403:
+		return ErrClassAuth
+	case status >= 500:
+		return ErrClassNetwork
+	}
+	return ""
+}
+
+func containsAny(s string, keywords ...string) bool {
+	for _, k := range keywords {
+		if strings.Contains(s, k) {
+			return true
+		}
+	}
+	return false
+}
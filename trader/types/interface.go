@@ -1,6 +1,8 @@
 package types
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"nofx/logger"
 	"time"
@@ -159,6 +161,234 @@ type GridTrader interface {
 	GetOrderBook(symbol string, depth int) (bids, asks [][]float64, err error)
 }
 
+// SpotBalance free and locked amount of one spot wallet asset
+type SpotBalance struct {
+	Asset  string  `json:"asset"`
+	Free   float64 `json:"free"`
+	Locked float64 `json:"locked"` // Held by open orders
+}
+
+// Total free plus locked amount
+func (b SpotBalance) Total() float64 {
+	return b.Free + b.Locked
+}
+
+// SpotTrader extends Trader interface with spot market trading (no leverage, no positions)
+// Exchanges whose spot market can run grid strategies should implement this interface
+type SpotTrader interface {
+	Trader
+
+	// GetSpotAssets Get base and quote asset of a spot symbol (e.g. BTCUSDT -> BTC, USDT)
+	GetSpotAssets(symbol string) (base, quote string, err error)
+
+	// GetSpotBalances Get spot wallet balances by asset (assets without balance may be omitted)
+	GetSpotBalances() (map[string]SpotBalance, error)
+
+	// GetSpotPrice Get latest spot price
+	GetSpotPrice(symbol string) (float64, error)
+
+	// PlaceSpotLimitOrder places a spot limit order (Leverage, PositionSide and ReduceOnly are ignored)
+	PlaceSpotLimitOrder(req *LimitOrderRequest) (*LimitOrderResult, error)
+
+	// SpotMarketSell sells quantity of the base asset at market price
+	SpotMarketSell(symbol string, quantity float64) (map[string]interface{}, error)
+
+	// CancelSpotOrder cancels a specific spot order by ID
+	CancelSpotOrder(symbol, orderID string) error
+
+	// CancelAllSpotOrders cancels all open spot orders for this symbol
+	CancelAllSpotOrders(symbol string) error
+
+	// GetSpotOpenOrders Get open spot orders for this symbol
+	GetSpotOpenOrders(symbol string) ([]OpenOrder, error)
+
+	// GetSpotOrderStatus Get spot order status, same fields as GetOrderStatus
+	GetSpotOrderStatus(symbol, orderID string) (map[string]interface{}, error)
+}
+
+// CollateralAsset balance of one collateral asset of a futures account, amounts in the asset
+type CollateralAsset struct {
+	Asset         string  `json:"asset"`
+	WalletBalance float64 `json:"wallet_balance"`
+	UnrealizedPnL float64 `json:"unrealized_pnl"`
+	Available     float64 `json:"available"`   // Free to margin new positions
+	IndexPrice    float64 `json:"index_price"` // USD price of one unit, 0 if the exchange does not report it
+}
+
+// Equity wallet balance plus unrealized PnL
+func (a CollateralAsset) Equity() float64 {
+	return a.WalletBalance + a.UnrealizedPnL
+}
+
+// CollateralBalance collateral of a futures account by asset
+type CollateralBalance struct {
+	// MultiAssetMargin every asset margins every contract, otherwise an asset only margins the
+	// contracts settled in it (USDC balance for BTCUSDC)
+	MultiAssetMargin bool              `json:"multi_asset_margin"`
+	Assets           []CollateralAsset `json:"assets"`
+}
+
+// CollateralTrader extends Trader interface with per-asset collateral balances
+// Exchanges whose futures account may hold collateral other than USDT (USDC/BUSD, multi-asset margin)
+// should implement this interface
+type CollateralTrader interface {
+	Trader
+
+	// GetCollateralBalance Get the balances of all collateral assets (assets without balance may be omitted)
+	GetCollateralBalance() (*CollateralBalance, error)
+}
+
+// Income types
+const (
+	IncomeTypeFunding    = "FUNDING_FEE"
+	IncomeTypeCommission = "COMMISSION"
+	IncomeTypeTransfer   = "TRANSFER"
+)
+
+// IncomeRecord represents a funding payment, trading commission or transfer from exchange
+// Amount is the signed balance change: negative = paid / withdrawn, positive = received / deposited
+type IncomeRecord struct {
+	TranID string    // Exchange transaction/trade ID (unique per account and type)
+	Symbol string    // Trading pair (e.g., "BTCUSDT")
+	Type   string    // IncomeTypeFunding, IncomeTypeCommission or IncomeTypeTransfer
+	Amount float64   // Signed balance change
+	Asset  string    // Settlement asset (e.g., "USDT")
+	Time   time.Time // Settlement time
+}
+
+// IncomeTrader extends Trader interface with funding and commission history
+// Exchanges that expose account income history should implement this interface.
+// Not implemented for KuCoin (funding history is per symbol only) and Lighter (no account income endpoint);
+// their statistics report income_synced=false.
+type IncomeTrader interface {
+	Trader
+
+	// GetFundingHistory Get funding payments since startTime
+	GetFundingHistory(startTime time.Time, limit int) ([]IncomeRecord, error)
+
+	// GetCommissionHistory Get trading commissions since startTime
+	GetCommissionHistory(startTime time.Time, limit int) ([]IncomeRecord, error)
+}
+
+// TransferTrader extends Trader interface with deposit/withdrawal history of the trading account
+// Exchanges that expose transfer history should implement this interface, so cash flows can be
+// excluded from PnL
+type TransferTrader interface {
+	Trader
+
+	// GetTransferHistory Get transfers into (positive) and out of (negative) the trading account since startTime
+	GetTransferHistory(startTime time.Time, limit int) ([]IncomeRecord, error)
+}
+
+// ClientOrderIDLen Length of the client order ID body derived from a ClientOrderKey.
+// Exchanges prepend their broker tag, the result stays within every exchange's limit (32 chars for Binance/OKX)
+const ClientOrderIDLen = 21
+
+// ClientOrderKey identifies one order submission of a trader (trader + decision cycle + decision index).
+// The same key always yields the same client order ID, so a retried submission is recognized by the exchange.
+type ClientOrderKey struct {
+	TraderID string
+	Cycle    int
+	Index    int
+}
+
+// IsZero Whether the key is unset (exchanges then fall back to random client order IDs)
+func (k ClientOrderKey) IsZero() bool {
+	return k.TraderID == "" && k.Cycle == 0 && k.Index == 0
+}
+
+// Hex Deterministic lowercase hex digest of the key, truncated to n characters (max 64)
+func (k ClientOrderKey) Hex(n int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%d:%d", k.TraderID, k.Cycle, k.Index)))
+	h := hex.EncodeToString(sum[:])
+	if n > 0 && n < len(h) {
+		return h[:n]
+	}
+	return h
+}
+
+// ID Deterministic alphanumeric client order ID body of ClientOrderIDLen characters
+func (k ClientOrderKey) ID() string {
+	return k.Hex(ClientOrderIDLen)
+}
+
+// String Human-readable form for logs
+func (k ClientOrderKey) String() string {
+	return fmt.Sprintf("%s#%d.%d", k.TraderID, k.Cycle, k.Index)
+}
+
+// ClientOrderTrader extends Trader interface with idempotent open orders
+// Exchanges that accept caller-assigned client order IDs should implement this interface
+type ClientOrderTrader interface {
+	Trader
+
+	// OpenLongWithClientID Open long position with a client order ID derived from key
+	OpenLongWithClientID(symbol string, quantity float64, leverage int, key ClientOrderKey) (map[string]interface{}, error)
+
+	// OpenShortWithClientID Open short position with a client order ID derived from key
+	OpenShortWithClientID(symbol string, quantity float64, leverage int, key ClientOrderKey) (map[string]interface{}, error)
+
+	// FindOrderByClientID Look up an order previously submitted with key
+	// Returns nil, nil if the exchange has no such order
+	FindOrderByClientID(symbol string, key ClientOrderKey) (map[string]interface{}, error)
+}
+
+// OCOTrader extends Trader interface with linked stop-loss/take-profit (one-cancels-other)
+// Exchanges that attach TP/SL to the position, so a fill of one leg cancels the other, should implement this interface
+type OCOTrader interface {
+	Trader
+
+	// SetStopLossTakeProfit Set stop-loss and take-profit as one linked pair for the position
+	SetStopLossTakeProfit(symbol string, positionSide string, quantity, stopPrice, takeProfitPrice float64) error
+}
+
+// BracketOrder market entry submitted together with its stop loss and take profit (0 = leg not requested)
+type BracketOrder struct {
+	Symbol     string
+	Side       string // LONG/SHORT
+	Quantity   float64
+	Leverage   int
+	StopLoss   float64
+	TakeProfit float64
+	Key        ClientOrderKey // Zero key: no client order ID
+}
+
+// BracketTrader extends Trader interface with entries that are never unprotected
+// Implementations either attach the stop loss/take profit to the entry order itself, or place them
+// before the entry and cancel them when it fails. An error means no position was opened, apart from
+// ambiguous entry failures (timeouts), which callers resolve by client order ID.
+type BracketTrader interface {
+	Trader
+
+	// OpenBracket Open a position with its stop loss and take profit in place
+	OpenBracket(order *BracketOrder) (map[string]interface{}, error)
+}
+
+// PartialTakeProfitTrader extends Trader interface with take profits closing part of a position
+// Several of them may be open for one position. Exchanges whose SetTakeProfit closes the whole
+// position, or keeps a single take profit, must not implement it: their ladders are emulated.
+type PartialTakeProfitTrader interface {
+	Trader
+
+	// SetPartialTakeProfit Place a reduce-only take profit closing quantity of the position at price
+	SetPartialTakeProfit(symbol string, positionSide string, quantity, price float64) error
+}
+
+// APIKeyPermissions permissions granted to an exchange API key
+type APIKeyPermissions struct {
+	FuturesTrading bool // Key can place futures/perpetual orders
+	Withdrawal     bool // Key can withdraw funds (never needed by a trader)
+}
+
+// PermissionTrader extends Trader interface with API key permission lookup
+// Exchanges that expose the permissions of the calling key should implement this interface
+type PermissionTrader interface {
+	Trader
+
+	// GetAPIKeyPermissions Get the permissions of the API key the trader was created with
+	GetAPIKeyPermissions() (*APIKeyPermissions, error)
+}
+
 // GridTraderAdapter wraps a basic Trader to provide GridTrader interface
 // Uses stop orders as a fallback when limit orders aren't directly available
 type GridTraderAdapter struct {
@@ -0,0 +1,204 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Symbol Filters & Order Normalization
+// ============================================================================
+// Exchanges reject orders whose quantity is not a multiple of the lot step,
+// whose price is off the tick grid, or whose value is below a minimum
+// notional, each with its own error codes. Exchange traders load these rules
+// for all symbols in one request (SymbolFiltersCache), round quantities down
+// to the step and prices to the tick before submission, and reject orders
+// outside the limits with an ErrClassInvalidOrder error such as
+// "BTCUSDT quantity 0.0004 below minimum 0.001" instead of sending them.
+// ============================================================================
+
+// SymbolFilters trading rules of a symbol, zero fields are not enforced
+type SymbolFilters struct {
+	Symbol       string
+	StepSize     float64 // Quantity increment
+	MinQty       float64
+	MaxQty       float64 // Largest limit order quantity
+	MaxMarketQty float64 // Largest market order quantity, MaxQty when zero
+	TickSize     float64 // Price increment
+	MinNotional  float64 // Smallest order value (quantity × price) in quote currency
+}
+
+// NormalizeQuantity rounds quantity down to the step size and checks it against the quantity limits
+func (f SymbolFilters) NormalizeQuantity(quantity float64, market bool) (float64, error) {
+	rounded := floorToStep(quantity, f.StepSize)
+	if rounded <= 0 || rounded < f.MinQty {
+		minQty := f.MinQty
+		if minQty <= 0 {
+			minQty = f.StepSize
+		}
+		return 0, f.filterError("quantity %s below minimum %s", formatNumber(quantity), formatNumber(minQty))
+	}
+	maxQty := f.MaxQty
+	if market && f.MaxMarketQty > 0 {
+		maxQty = f.MaxMarketQty
+	}
+	if maxQty > 0 && rounded > maxQty {
+		return 0, f.filterError("quantity %s above maximum %s", formatNumber(rounded), formatNumber(maxQty))
+	}
+	return rounded, nil
+}
+
+// NormalizePrice rounds price to the nearest tick
+func (f SymbolFilters) NormalizePrice(price float64) float64 {
+	if f.TickSize <= 0 {
+		return price
+	}
+	return roundToDecimals(math.Round(price/f.TickSize)*f.TickSize, stepDecimals(f.TickSize))
+}
+
+// CheckNotional checks the order value of quantity at price against the minimum notional
+func (f SymbolFilters) CheckNotional(quantity, price float64) error {
+	if f.MinNotional <= 0 || price <= 0 {
+		return nil
+	}
+	if notional := quantity * price; notional < f.MinNotional {
+		return f.filterError("order value %.2f below minimum notional %s (quantity %s at %s)",
+			notional, formatNumber(f.MinNotional), formatNumber(quantity), formatNumber(price))
+	}
+	return nil
+}
+
+// NormalizeOrder normalizes the quantity of an order at price (the limit price, or the current price of
+// a market order) and checks its value, returning the quantity formatted for submission
+func (f SymbolFilters) NormalizeOrder(quantity, price float64, market bool) (string, error) {
+	rounded, err := f.NormalizeQuantity(quantity, market)
+	if err != nil {
+		return "", err
+	}
+	if err := f.CheckNotional(rounded, price); err != nil {
+		return "", err
+	}
+	return f.FormatQuantity(rounded), nil
+}
+
+// FormatQuantity formats quantity rounded down to the step size, with the step's decimals
+func (f SymbolFilters) FormatQuantity(quantity float64) string {
+	return strconv.FormatFloat(floorToStep(quantity, f.StepSize), 'f', stepDecimals(f.StepSize), 64)
+}
+
+// FormatPrice formats price rounded to the tick size, with the tick's decimals
+func (f SymbolFilters) FormatPrice(price float64) string {
+	if f.TickSize <= 0 {
+		return formatNumber(price)
+	}
+	return strconv.FormatFloat(f.NormalizePrice(price), 'f', stepDecimals(f.TickSize), 64)
+}
+
+// QuantityDecimals decimal places of quantities (of the step size)
+func (f SymbolFilters) QuantityDecimals() int {
+	return stepDecimals(f.StepSize)
+}
+
+// PriceDecimals decimal places of prices (of the tick size)
+func (f SymbolFilters) PriceDecimals() int {
+	return stepDecimals(f.TickSize)
+}
+
+func (f SymbolFilters) filterError(format string, args ...interface{}) error {
+	return NewExchangeError(ErrClassInvalidOrder, "", fmt.Errorf("%s "+format, append([]interface{}{f.Symbol}, args...)...))
+}
+
+// floorToStep rounds value down to a multiple of step, tolerating float error just below a multiple
+func floorToStep(value, step float64) float64 {
+	if step <= 0 {
+		return value
+	}
+	return roundToDecimals(math.Floor(value/step+1e-9)*step, stepDecimals(step))
+}
+
+// stepDecimals decimal places of a step or tick size (0.001 -> 3, 5 -> 0)
+func stepDecimals(step float64) int {
+	s := strconv.FormatFloat(step, 'f', -1, 64)
+	if idx := strings.IndexByte(s, '.'); idx >= 0 {
+		return len(s) - idx - 1
+	}
+	return 0
+}
+
+func roundToDecimals(value float64, decimals int) float64 {
+	pow := math.Pow10(decimals)
+	return math.Round(value*pow) / pow
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// ParseFilterValue parses a filter value returned by an exchange as a string ("" and invalid values are 0)
+func ParseFilterValue(s string) float64 {
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
+
+// SymbolFiltersTTL how long exchange traders cache symbol filters, which rarely change
+const SymbolFiltersTTL = time.Hour
+
+// symbolFiltersRetryDelay minimum delay between load attempts once filters are loaded
+const symbolFiltersRetryDelay = time.Minute
+
+// SymbolFiltersLoader loads the filters of all tradable symbols of an exchange, keyed by symbol
+type SymbolFiltersLoader func() (map[string]SymbolFilters, error)
+
+// SymbolFiltersCache filters of an exchange's symbols, loaded in one request and reloaded after the TTL
+// A failed reload keeps serving the previous filters; a symbol missing from them (e.g. a new listing)
+// triggers an early reload.
+type SymbolFiltersCache struct {
+	load SymbolFiltersLoader
+	ttl  time.Duration
+
+	mu          sync.Mutex
+	filters     map[string]SymbolFilters
+	loadedAt    time.Time
+	attemptedAt time.Time // Last load attempt, failed or not
+}
+
+// NewSymbolFiltersCache creates a cache loading filters with load, reloading after ttl
+func NewSymbolFiltersCache(ttl time.Duration, load SymbolFiltersLoader) *SymbolFiltersCache {
+	return &SymbolFiltersCache{load: load, ttl: ttl}
+}
+
+// Get returns the filters of a symbol, loading them when the cache is empty or expired
+func (c *SymbolFiltersCache) Get(symbol string) (SymbolFilters, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.filters == nil || (time.Since(c.loadedAt) >= c.ttl && time.Since(c.attemptedAt) >= symbolFiltersRetryDelay) {
+		if err := c.reload(); err != nil && c.filters == nil {
+			return SymbolFilters{}, err
+		}
+	}
+	f, ok := c.filters[symbol]
+	if !ok && time.Since(c.attemptedAt) >= symbolFiltersRetryDelay && c.reload() == nil {
+		f, ok = c.filters[symbol]
+	}
+	if !ok {
+		return SymbolFilters{}, NewExchangeError(ErrClassInvalidSymbol, "", fmt.Errorf("no trading rules for symbol %s", symbol))
+	}
+	return f, nil
+}
+
+// reload loads the filters, the previous ones are kept when loading fails
+func (c *SymbolFiltersCache) reload() error {
+	c.attemptedAt = time.Now()
+	filters, err := c.load()
+	if err != nil {
+		return fmt.Errorf("failed to load trading rules: %w", err)
+	}
+	c.filters = filters
+	c.loadedAt = c.attemptedAt
+	return nil
+}
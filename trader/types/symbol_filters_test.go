@@ -0,0 +1,86 @@
+package types
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSymbolFiltersNormalizeOrder(t *testing.T) {
+	f := SymbolFilters{Symbol: "BTCUSDT", StepSize: 0.001, MinQty: 0.001, MaxQty: 1000, MaxMarketQty: 120, TickSize: 0.1, MinNotional: 100}
+
+	cases := []struct {
+		quantity, price float64
+		market          bool
+		want, wantErr   string
+	}{
+		{0.12345, 60000, true, "0.123", ""},
+		{0.3, 60000, true, "0.300", ""}, // 0.3/0.001 is 299.99999999999994 in float
+		{0.0004, 60000, true, "", "BTCUSDT quantity 0.0004 below minimum 0.001"},
+		{0.0015, 60000, true, "", "order value 60.00 below minimum notional 100"},
+		{150, 60000, true, "", "quantity 150 above maximum 120"},
+		{150, 60000, false, "150.000", ""},
+	}
+	for _, tc := range cases {
+		got, err := f.NormalizeOrder(tc.quantity, tc.price, tc.market)
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("NormalizeOrder(%v) error = %v, want %q", tc.quantity, err, tc.wantErr)
+			} else if ClassifyError(err) != ErrClassInvalidOrder {
+				t.Errorf("NormalizeOrder(%v) error class = %s, want %s", tc.quantity, ClassifyError(err), ErrClassInvalidOrder)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("NormalizeOrder(%v) = %q, %v, want %q", tc.quantity, got, err, tc.want)
+		}
+	}
+
+	if got := f.FormatPrice(60123.4567); got != "60123.5" {
+		t.Errorf("FormatPrice = %s, want 60123.5", got)
+	}
+	if got := (SymbolFilters{StepSize: 5}).FormatQuantity(12); got != "10" {
+		t.Errorf("FormatQuantity with integer step = %s, want 10", got)
+	}
+}
+
+func TestSymbolFiltersCache(t *testing.T) {
+	loads := 0
+	var loadErr error
+	cache := NewSymbolFiltersCache(time.Hour, func() (map[string]SymbolFilters, error) {
+		loads++
+		if loadErr != nil {
+			return nil, loadErr
+		}
+		return map[string]SymbolFilters{"BTCUSDT": {Symbol: "BTCUSDT", StepSize: 0.001}}, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if f, err := cache.Get("BTCUSDT"); err != nil || f.StepSize != 0.001 {
+			t.Fatalf("Get = %+v, %v", f, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("filters should be loaded once, got %d loads", loads)
+	}
+
+	// Unknown symbols reload at most once per retry delay
+	if _, err := cache.Get("NEWUSDT"); ClassifyError(err) != ErrClassInvalidSymbol {
+		t.Errorf("unknown symbol error class = %s, want %s", ClassifyError(err), ErrClassInvalidSymbol)
+	}
+	if loads != 1 {
+		t.Errorf("a fresh cache should not reload for an unknown symbol, got %d loads", loads)
+	}
+
+	// Expired: a failed reload keeps the previous filters
+	cache.loadedAt = time.Now().Add(-2 * time.Hour)
+	cache.attemptedAt = cache.loadedAt
+	loadErr = errors.New("connection reset")
+	if f, err := cache.Get("BTCUSDT"); err != nil || f.StepSize != 0.001 {
+		t.Errorf("stale filters should be served when reloading fails, got %+v, %v", f, err)
+	}
+	if _, err := cache.Get("BTCUSDT"); err != nil || loads != 2 {
+		t.Errorf("a failed reload should not be retried immediately, got %d loads, %v", loads, err)
+	}
+}
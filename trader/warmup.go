@@ -0,0 +1,47 @@
+package trader
+
+import (
+	"nofx/logger"
+)
+
+// startMarketDataWarmup prefetches market data of positions and candidate coins in the background,
+// so the first decision cycle after start doesn't pay the full multi-timeframe fetch latency
+func (at *AutoTrader) startMarketDataWarmup() {
+	if at.strategyEngine == nil {
+		return
+	}
+	at.strategyEngine.StartWarmup(func() []string {
+		var symbols []string
+		if positions, err := at.trader.GetPositions(); err == nil {
+			for _, pos := range positions {
+				if symbol, ok := pos["symbol"].(string); ok && symbol != "" {
+					symbols = append(symbols, symbol)
+				}
+			}
+		} else {
+			logger.Infof("⚠️ [%s] Warmup: failed to get positions: %v", at.name, err)
+		}
+
+		coins, err := at.strategyEngine.GetCandidateCoins()
+		if err != nil {
+			logger.Infof("⚠️ [%s] Warmup: failed to get candidate coins: %v", at.name, err)
+		}
+		for _, coin := range coins {
+			symbols = append(symbols, coin.Symbol)
+		}
+		return dedupeSymbols(symbols)
+	})
+}
+
+// dedupeSymbols removes duplicate symbols, keeping first-seen order
+func dedupeSymbols(symbols []string) []string {
+	seen := make(map[string]bool, len(symbols))
+	result := symbols[:0]
+	for _, symbol := range symbols {
+		if !seen[symbol] {
+			seen[symbol] = true
+			result = append(result, symbol)
+		}
+	}
+	return result
+}
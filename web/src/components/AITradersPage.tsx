@@ -102,6 +102,16 @@ interface AITradersPageProps {
   onTraderSelect?: (traderId: string) => void
 }
 
+// API key permission check badges (hover shows the verification message)
+const KEY_VERIFICATION_BADGES: Record<string, { label: string; className: string }> = {
+  pending: { label: 'VERIFYING', className: 'border-zinc-700 text-zinc-400' },
+  verified: { label: 'VERIFIED', className: 'border-green-500/30 text-green-400' },
+  missing_permission: { label: 'MISSING PERMISSION', className: 'border-yellow-500/30 text-yellow-400' },
+  withdrawal_enabled: { label: 'WITHDRAWAL ENABLED', className: 'border-red-500/50 text-red-400 bg-red-500/10' },
+  unverifiable: { label: 'UNVERIFIED', className: 'border-zinc-700 text-zinc-400' },
+  failed: { label: 'KEY ERROR', className: 'border-red-500/30 text-red-400' },
+}
+
 // Helper function to get exchange display name from exchange ID (UUID)
 function getExchangeDisplayName(exchangeId: string | undefined, exchanges: Exchange[]): string {
   if (!exchangeId) return 'Unknown'
@@ -969,6 +979,14 @@ export function AITradersPage({ onTraderSelect }: AITradersPageProps) {
                         </div>
                         <div className="text-[10px] text-zinc-500 font-mono flex items-center gap-2">
                           {exchange.type?.toUpperCase() || 'CEX'}
+                          {exchange.key_verification_status && KEY_VERIFICATION_BADGES[exchange.key_verification_status] && (
+                            <span
+                              className={`px-1 rounded border ${KEY_VERIFICATION_BADGES[exchange.key_verification_status].className}`}
+                              title={exchange.key_verification_message}
+                            >
+                              {KEY_VERIFICATION_BADGES[exchange.key_verification_status].label}
+                            </span>
+                          )}
                         </div>
                       </div>
                     </div>
@@ -52,6 +52,7 @@ import type {
   BacktestTradeEvent,
   BacktestMetrics,
   BacktestKlinesResponse,
+  BacktestSlippageModel,
   DecisionRecord,
   AIModel,
   Strategy,
@@ -63,6 +64,7 @@ type ViewTab = 'overview' | 'chart' | 'trades' | 'decisions' | 'compare'
 
 const TIMEFRAME_OPTIONS = ['1m', '3m', '5m', '15m', '30m', '1h', '4h', '1d']
 const POPULAR_SYMBOLS = ['BTCUSDT', 'ETHUSDT', 'SOLUSDT', 'BNBUSDT', 'XRPUSDT', 'DOGEUSDT']
+const BACKTEST_FEE_EXCHANGES = ['binance', 'bybit', 'okx', 'bitget', 'gate', 'kucoin', 'hyperliquid', 'aster', 'lighter']
 
 // ============ Helper Functions ============
 const toLocalInput = (date: Date) => {
@@ -770,6 +772,8 @@ export function BacktestPage() {
     balance: 1000,
     fee: 5,
     slippage: 2,
+    feeExchange: '', // '' = flat fee above, otherwise the exchange's maker/taker tier
+    slippageModel: 'fixed' as BacktestSlippageModel,
     btcEthLeverage: 5,
     altcoinLeverage: 5,
     fill: 'next_open',
@@ -939,6 +943,11 @@ export function BacktestPage() {
         initial_balance: formState.balance,
         fee_bps: formState.fee,
         slippage_bps: formState.slippage,
+        fees: formState.feeExchange ? { exchange: formState.feeExchange } : undefined,
+        slippage:
+          formState.slippageModel === 'volume_impact'
+            ? { model: 'volume_impact', bps: formState.slippage }
+            : undefined,
         fill_policy: formState.fill,
         prompt_variant: formState.prompt,
         prompt_template: formState.promptTemplate,
@@ -1525,6 +1534,41 @@ export function BacktestPage() {
                         </div>
                       </div>
 
+                      <div className="grid grid-cols-1 sm:grid-cols-2 gap-2">
+                        <div>
+                          <label className="block text-xs mb-1" style={{ color: '#848E9C' }}>
+                            {language === 'zh' ? '费率表' : 'Fee Schedule'}
+                          </label>
+                          <select
+                            className="w-full p-2 rounded-lg text-xs"
+                            style={{ background: '#0B0E11', border: '1px solid #2B3139', color: '#EAECEF' }}
+                            value={formState.feeExchange}
+                            onChange={(e) => handleFormChange('feeExchange', e.target.value)}
+                          >
+                            <option value="">{language === 'zh' ? '固定费率' : 'Flat fee'}</option>
+                            {BACKTEST_FEE_EXCHANGES.map((ex) => (
+                              <option key={ex} value={ex}>
+                                {ex} (taker)
+                              </option>
+                            ))}
+                          </select>
+                        </div>
+                        <div>
+                          <label className="block text-xs mb-1" style={{ color: '#848E9C' }}>
+                            {language === 'zh' ? '滑点模型' : 'Slippage Model'}
+                          </label>
+                          <select
+                            className="w-full p-2 rounded-lg text-xs"
+                            style={{ background: '#0B0E11', border: '1px solid #2B3139', color: '#EAECEF' }}
+                            value={formState.slippageModel}
+                            onChange={(e) => handleFormChange('slippageModel', e.target.value)}
+                          >
+                            <option value="fixed">{language === 'zh' ? '固定 bps' : 'Fixed bps'}</option>
+                            <option value="volume_impact">{language === 'zh' ? '成交量冲击' : 'Volume impact'}</option>
+                          </select>
+                        </div>
+                      </div>
+
                       <div>
                         <label className="block text-xs mb-1" style={{ color: '#848E9C' }}>
                           {language === 'zh' ? '策略风格' : 'Strategy Style'}
@@ -1908,6 +1952,20 @@ export function BacktestPage() {
                               </div>
                             </div>
                           )}
+
+                          {metrics?.cost_assumptions && (
+                            <div className="mt-3 text-xs" style={{ color: '#848E9C' }}>
+                              {language === 'zh' ? '成本' : 'Costs'}: {language === 'zh' ? '手续费' : 'fees'} $
+                              {(metrics.total_fees ?? 0).toFixed(2)} · {language === 'zh' ? '滑点' : 'slippage'} $
+                              {(metrics.total_slippage ?? 0).toFixed(2)} ·{' '}
+                              {metrics.cost_assumptions.exchange || (language === 'zh' ? '固定费率' : 'flat')}{' '}
+                              {metrics.cost_assumptions.liquidity} {metrics.cost_assumptions.fee_bps} bps ·{' '}
+                              {metrics.cost_assumptions.slippage_model} {metrics.cost_assumptions.slippage_bps} bps
+                              {metrics.cost_assumptions.slippage_model === 'volume_impact' &&
+                                ` (+${metrics.cost_assumptions.impact_bps} bps @1% vol, max ${metrics.cost_assumptions.max_slippage_bps})`}{' '}
+                              · {metrics.cost_assumptions.fill_policy}
+                            </div>
+                          )}
                         </motion.div>
                       )}
 
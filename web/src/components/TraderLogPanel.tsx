@@ -0,0 +1,137 @@
+import { useEffect, useRef, useState } from 'react'
+import { useAuth } from '../contexts/AuthContext'
+import type { Language } from '../i18n/translations'
+import type { TraderLogEntry } from '../types'
+
+const API_BASE = import.meta.env.VITE_API_BASE || ''
+const MAX_LINES = 500
+
+const levelColor: Record<string, string> = {
+  error: 'text-red-400',
+  fatal: 'text-red-400',
+  panic: 'text-red-400',
+  warning: 'text-yellow-400',
+  info: 'text-nofx-text-main',
+  debug: 'text-nofx-text-muted',
+  trace: 'text-nofx-text-muted',
+}
+
+interface TraderLogPanelProps {
+  traderId: string
+  language: Language
+}
+
+// Live log lines of a trader, streamed as server-sent events
+export function TraderLogPanel({ traderId, language }: TraderLogPanelProps) {
+  const { token } = useAuth()
+  const [lines, setLines] = useState<TraderLogEntry[]>([])
+  const [follow, setFollow] = useState(true)
+  const [level, setLevel] = useState('')
+  const [connected, setConnected] = useState(false)
+  const scrollRef = useRef<HTMLDivElement>(null)
+
+  useEffect(() => {
+    if (!token || !traderId) return
+    const controller = new AbortController()
+    setLines([])
+
+    const run = async () => {
+      const params = new URLSearchParams({ limit: '200' })
+      if (level) params.set('level', level)
+      if (follow) params.set('follow', 'true')
+      try {
+        const response = await fetch(`${API_BASE}/api/traders/${traderId}/logs?${params}`, {
+          headers: { Authorization: `Bearer ${token}` },
+          signal: controller.signal,
+        })
+        if (!response.ok) throw new Error('Failed to load trader logs')
+        if (!follow || !response.body) {
+          const data = await response.json()
+          setLines(data.logs ?? [])
+          return
+        }
+
+        setConnected(true)
+        const reader = response.body.getReader()
+        const decoder = new TextDecoder()
+        let buffer = ''
+        for (;;) {
+          const { done, value } = await reader.read()
+          if (done) break
+          buffer += decoder.decode(value, { stream: true })
+          const events = buffer.split('\n\n')
+          buffer = events.pop() ?? ''
+          const received: TraderLogEntry[] = []
+          for (const raw of events) {
+            const event = raw.match(/^event: (.*)$/m)?.[1]
+            const dataLine = raw.match(/^data: (.*)$/m)?.[1]
+            if (event !== 'log' || !dataLine) continue
+            received.push(JSON.parse(dataLine))
+          }
+          if (received.length > 0) {
+            setLines((prev) => [...prev, ...received].slice(-MAX_LINES))
+          }
+        }
+      } catch (err) {
+        if (!controller.signal.aborted) console.error(err)
+      } finally {
+        setConnected(false)
+      }
+    }
+    run()
+    return () => controller.abort()
+  }, [token, traderId, follow, level])
+
+  // Keep the newest line in view
+  useEffect(() => {
+    const el = scrollRef.current
+    if (el) el.scrollTop = el.scrollHeight
+  }, [lines])
+
+  return (
+    <div className="nofx-glass p-6 animate-slide-in">
+      <div className="flex items-center justify-between mb-4">
+        <h2 className="text-xl font-bold flex items-center gap-2 text-nofx-text-main">
+          <span className="text-green-500">▤</span> {language === 'zh' ? '运行日志' : 'Live Logs'}
+          {connected && <span className="w-2 h-2 rounded-full bg-green-500 animate-pulse" />}
+        </h2>
+        <div className="flex items-center gap-2 text-xs">
+          <select
+            value={level}
+            onChange={(e) => setLevel(e.target.value)}
+            className="px-2 py-1 rounded bg-black/40 text-nofx-text-main border border-white/10 focus:outline-none"
+          >
+            <option value="">{language === 'zh' ? '全部' : 'All'}</option>
+            <option value="info">Info+</option>
+            <option value="warning">Warning+</option>
+            <option value="error">Error</option>
+          </select>
+          <button
+            onClick={() => setFollow(!follow)}
+            className={`px-2 py-1 rounded transition-colors ${follow ? 'bg-green-500/20 text-green-400' : 'bg-white/5 text-nofx-text-muted hover:bg-white/10'}`}
+          >
+            {follow ? (language === 'zh' ? '实时' : 'Following') : language === 'zh' ? '已暂停' : 'Paused'}
+          </button>
+        </div>
+      </div>
+      <div
+        ref={scrollRef}
+        className="font-mono text-xs leading-relaxed overflow-y-auto custom-scrollbar bg-black/40 rounded-lg p-3"
+        style={{ maxHeight: 360 }}
+      >
+        {lines.length > 0 ? (
+          lines.map((line) => (
+            <div key={line.seq} className={`whitespace-pre-wrap break-all ${levelColor[line.level] ?? 'text-nofx-text-main'}`}>
+              <span className="text-nofx-text-muted mr-2">{new Date(line.time).toLocaleTimeString()}</span>
+              {line.message}
+            </div>
+          ))
+        ) : (
+          <div className="py-8 text-center text-nofx-text-muted opacity-60">
+            {language === 'zh' ? '暂无日志' : 'No log lines yet'}
+          </div>
+        )}
+      </div>
+    </div>
+  )
+}
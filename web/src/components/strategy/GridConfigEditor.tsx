@@ -11,6 +11,7 @@ interface GridConfigEditorProps {
 // Default grid config
 export const defaultGridConfig: GridStrategyConfig = {
   symbol: 'BTCUSDT',
+  market_type: 'futures',
   grid_count: 10,
   total_investment: 1000,
   leverage: 5,
@@ -44,6 +45,11 @@ export function GridConfigEditor({
       // Trading pair
       symbol: { zh: '交易对', en: 'Trading Pair' },
       symbolDesc: { zh: '选择要进行网格交易的交易对', en: 'Select trading pair for grid trading' },
+      marketType: { zh: '市场类型', en: 'Market Type' },
+      marketTypeDesc: { zh: '现货网格买卖真实币种，无杠杆、不可做空', en: 'Spot grids buy and sell the coin itself, no leverage or shorting' },
+      marketFutures: { zh: '永续合约', en: 'Perpetual Futures' },
+      marketSpot: { zh: '现货', en: 'Spot' },
+      spotLeverageNote: { zh: '现货网格固定为 1 倍', en: 'Spot grids always use 1x' },
 
       // Investment
       totalInvestment: { zh: '投资金额 (USDT)', en: 'Investment (USDT)' },
@@ -107,6 +113,19 @@ export function GridConfigEditor({
     }
   }
 
+  const isSpot = config.market_type === 'spot'
+
+  const updateMarketType = (marketType: 'futures' | 'spot') => {
+    if (!disabled) {
+      // Spot grids never borrow, so leverage is pinned to 1x
+      onChange({
+        ...config,
+        market_type: marketType,
+        leverage: marketType === 'spot' ? 1 : config.leverage,
+      })
+    }
+  }
+
   const inputStyle = {
     background: '#1E2329',
     border: '1px solid #2B3139',
@@ -129,7 +148,7 @@ export function GridConfigEditor({
           </h3>
         </div>
 
-        <div className="grid grid-cols-1 md:grid-cols-3 gap-4">
+        <div className="grid grid-cols-1 md:grid-cols-2 lg:grid-cols-4 gap-4">
           {/* Symbol */}
           <div className="p-4 rounded-lg" style={sectionStyle}>
             <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
@@ -154,6 +173,26 @@ export function GridConfigEditor({
             </select>
           </div>
 
+          {/* Market Type */}
+          <div className="p-4 rounded-lg" style={sectionStyle}>
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('marketType')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('marketTypeDesc')}
+            </p>
+            <select
+              value={config.market_type || 'futures'}
+              onChange={(e) => updateMarketType(e.target.value as 'futures' | 'spot')}
+              disabled={disabled}
+              className="w-full px-3 py-2 rounded"
+              style={inputStyle}
+            >
+              <option value="futures">{t('marketFutures')}</option>
+              <option value="spot">{t('marketSpot')}</option>
+            </select>
+          </div>
+
           {/* Investment */}
           <div className="p-4 rounded-lg" style={sectionStyle}>
             <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
@@ -180,13 +219,13 @@ export function GridConfigEditor({
               {t('leverage')}
             </label>
             <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
-              {t('leverageDesc')}
+              {isSpot ? t('spotLeverageNote') : t('leverageDesc')}
             </p>
             <input
               type="number"
-              value={config.leverage}
+              value={isSpot ? 1 : config.leverage}
               onChange={(e) => updateField('leverage', parseInt(e.target.value) || 5)}
-              disabled={disabled}
+              disabled={disabled || isSpot}
               min={1}
               max={5}
               className="w-full px-3 py-2 rounded"
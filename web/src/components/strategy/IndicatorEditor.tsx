@@ -72,12 +72,30 @@ export function IndicatorEditor({
       atrDesc: { zh: '真实波幅均值', en: 'Average True Range' },
       boll: { zh: 'BOLL 布林带', en: 'Bollinger Bands' },
       bollDesc: { zh: '布林带指标（上中下轨）', en: 'Upper/Middle/Lower Bands' },
+      vwap: { zh: 'VWAP', en: 'VWAP' },
+      vwapDesc: { zh: '成交量加权均价（UTC 日内）', en: 'Volume weighted price (UTC day)' },
+      supertrend: { zh: 'SuperTrend', en: 'SuperTrend' },
+      supertrendDesc: { zh: '超级趋势 (10, 3)', en: 'Trend line and direction (10, 3)' },
+      ichimoku: { zh: 'Ichimoku 一目均衡表', en: 'Ichimoku' },
+      ichimokuDesc: { zh: '转换线/基准线/云层 (9, 26, 52)', en: 'Tenkan/Kijun/Cloud (9, 26, 52)' },
+      obv: { zh: 'OBV', en: 'OBV' },
+      obvDesc: { zh: '能量潮', en: 'On-Balance Volume' },
+      stochRsi: { zh: 'StochRSI', en: 'Stochastic RSI' },
+      stochRsiDesc: { zh: '随机相对强弱 %K/%D', en: 'Stochastic RSI %K/%D' },
       volume: { zh: '成交量', en: 'Volume' },
       volumeDesc: { zh: '交易量分析', en: 'Trading volume analysis' },
       oi: { zh: '持仓量', en: 'Open Interest' },
       oiDesc: { zh: '合约未平仓量', en: 'Futures open interest' },
       fundingRate: { zh: '资金费率', en: 'Funding Rate' },
       fundingRateDesc: { zh: '永续合约资金费率', en: 'Perpetual funding rate' },
+      orderBook: { zh: '订单簿深度', en: 'Order Book' },
+      orderBookDesc: { zh: '价差、挂单深度与买卖失衡', en: 'Spread, depth and bid/ask imbalance' },
+      news: { zh: '新闻快讯', en: 'News' },
+      newsDesc: { zh: '最新加密货币新闻标题', en: 'Latest crypto headlines' },
+      economicCalendar: { zh: '经济日历', en: 'Economic Calendar' },
+      economicCalendarDesc: { zh: 'CPI、FOMC 等重要宏观事件', en: 'Upcoming CPI, FOMC and other macro events' },
+      sentiment: { zh: '市场情绪', en: 'Sentiment' },
+      sentimentDesc: { zh: '恐惧贪婪指数与社交媒体情绪', en: 'Fear & Greed index and social sentiment' },
 
       // OI Ranking
       oiRanking: { zh: 'OI 排行', en: 'OI Ranking' },
@@ -673,6 +691,11 @@ export function IndicatorEditor({
               { key: 'enable_rsi', label: 'rsi', desc: 'rsiDesc', color: '#F6465D', periodKey: 'rsi_periods', defaultPeriods: '7,14' },
               { key: 'enable_atr', label: 'atr', desc: 'atrDesc', color: '#60a5fa', periodKey: 'atr_periods', defaultPeriods: '14' },
               { key: 'enable_boll', label: 'boll', desc: 'bollDesc', color: '#ec4899', periodKey: 'boll_periods', defaultPeriods: '20' },
+              { key: 'enable_vwap', label: 'vwap', desc: 'vwapDesc', color: '#2dd4bf' },
+              { key: 'enable_supertrend', label: 'supertrend', desc: 'supertrendDesc', color: '#f97316' },
+              { key: 'enable_ichimoku', label: 'ichimoku', desc: 'ichimokuDesc', color: '#818cf8' },
+              { key: 'enable_obv', label: 'obv', desc: 'obvDesc', color: '#84cc16' },
+              { key: 'enable_stoch_rsi', label: 'stochRsi', desc: 'stochRsiDesc', color: '#fb7185' },
             ].map(({ key, label, desc, color, periodKey, defaultPeriods }) => (
               <div
                 key={key}
@@ -736,6 +759,10 @@ export function IndicatorEditor({
               { key: 'enable_volume', label: 'volume', desc: 'volumeDesc', color: '#c084fc' },
               { key: 'enable_oi', label: 'oi', desc: 'oiDesc', color: '#34d399' },
               { key: 'enable_funding_rate', label: 'fundingRate', desc: 'fundingRateDesc', color: '#fbbf24' },
+              { key: 'enable_order_book', label: 'orderBook', desc: 'orderBookDesc', color: '#38bdf8' },
+              { key: 'enable_news', label: 'news', desc: 'newsDesc', color: '#f472b6' },
+              { key: 'enable_economic_calendar', label: 'economicCalendar', desc: 'economicCalendarDesc', color: '#fb923c' },
+              { key: 'enable_sentiment', label: 'sentiment', desc: 'sentimentDesc', color: '#a3e635' },
             ].map(({ key, label, desc, color }) => (
               <div
                 key={key}
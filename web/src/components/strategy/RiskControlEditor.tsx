@@ -1,5 +1,5 @@
 import { Shield, AlertTriangle } from 'lucide-react'
-import type { RiskControlConfig } from '../../types'
+import type { RiskControlConfig, TakeProfitStep } from '../../types'
 
 interface RiskControlEditorProps {
   config: RiskControlConfig
@@ -8,6 +8,17 @@ interface RiskControlEditorProps {
   language: string
 }
 
+// Take profit ladder as "profit%:close%" pairs
+const formatLadder = (ladder?: TakeProfitStep[]) =>
+  (ladder ?? []).map((step) => `${step.profit_pct}:${step.close_pct}`).join(', ')
+
+const parseLadder = (text: string): TakeProfitStep[] =>
+  text
+    .split(',')
+    .map((pair) => pair.split(':').map((v) => parseFloat(v)))
+    .filter(([profit, close]) => profit > 0 && close > 0)
+    .map(([profit_pct, close_pct]) => ({ profit_pct, close_pct }))
+
 export function RiskControlEditor({
   config,
   onChange,
@@ -34,7 +45,18 @@ export function RiskControlEditor({
       altcoinPositionValueRatioDesc: { zh: '单仓最大名义价值 = 净值 × 此值（代码强制）', en: 'Max position value = equity × this ratio (CODE ENFORCED)' },
       riskParameters: { zh: '风险参数', en: 'Risk Parameters' },
       minRiskReward: { zh: '最小风险回报比', en: 'Min Risk/Reward Ratio' },
-      minRiskRewardDesc: { zh: '开仓要求的最低盈亏比', en: 'Minimum profit ratio for opening' },
+      minRiskRewardDesc: { zh: '开仓要求的最低盈亏比（代码强制，剥头皮策略可设 1:1.5）', en: 'Minimum profit ratio for opening (CODE ENFORCED, scalping may use 1:1.5)' },
+      disableRiskRewardCheck: { zh: '不检查盈亏比', en: 'Disable ratio check' },
+      takeProfitLadder: { zh: '分批止盈（代码强制）', en: 'Take Profit Ladder (CODE ENFORCED)' },
+      takeProfitLadderDesc: { zh: '格式 盈利%:平仓%，如 2:50, 4:25（AI 未给出 take_profit_levels 时使用，留空关闭）', en: 'profit%:close%, e.g. 2:50, 4:25 (used when the AI gives no take_profit_levels, empty = off)' },
+      runnerTrailingStop: { zh: '剩余仓位移动止损', en: 'Runner trailing stop' },
+      maxHolding: { zh: '最长持仓时间（代码强制）', en: 'Max Holding Time (CODE ENFORCED)' },
+      maxHoldingDesc: { zh: '超过时长的仓位交给 AI 重新评估或直接平仓（0 = 关闭）', en: 'Positions held longer are flagged for AI re-evaluation or closed (0 = off)' },
+      holdingFlag: { zh: '提醒 AI', en: 'Flag to AI' },
+      holdingClose: { zh: '自动平仓', en: 'Auto close' },
+      avoidFunding: { zh: '资金费率规避（代码强制）', en: 'Funding Avoidance (CODE ENFORCED)' },
+      avoidFundingDesc: { zh: '需支付的资金费率达到该值时，结算前 N 分钟平仓并跳过同向开仓（0 = 关闭）', en: 'Positions paying at least this funding rate are closed N minutes before settlement, matching entries skipped (0 = off)' },
+      minutesBefore: { zh: '分钟前', en: 'min before' },
       maxMarginUsage: { zh: '最大保证金使用率（代码强制）', en: 'Max Margin Usage (CODE ENFORCED)' },
       maxMarginUsageDesc: { zh: '保证金使用率上限，由代码强制执行', en: 'Maximum margin utilization, enforced by code' },
       entryRequirements: { zh: '开仓要求', en: 'Entry Requirements' },
@@ -42,6 +64,16 @@ export function RiskControlEditor({
       minPositionSizeDesc: { zh: 'USDT 最小名义价值', en: 'Minimum notional value in USDT' },
       minConfidence: { zh: '最小信心度', en: 'Min Confidence' },
       minConfidenceDesc: { zh: 'AI 开仓信心度阈值', en: 'AI confidence threshold for entry' },
+      maxDepthPct: { zh: '深度占比警告', en: 'Depth Warning' },
+      maxDepthPctDesc: { zh: '开仓金额超过可见订单簿深度的该比例时告警（需启用订单簿深度）', en: 'Warn when an open exceeds this % of visible order book depth (requires Order Book data)' },
+      blockNearEvents: { zh: '重大事件禁开仓', en: 'Event Entry Blackout' },
+      blockNearEventsDesc: { zh: '高影响经济事件（CPI、FOMC 等）前后该分钟数内禁止开新仓，0 为关闭', en: 'Block new entries this many minutes before and after high-impact events (CPI, FOMC...), 0 = off' },
+      riskSizing: { zh: '按波动率自动定仓（代码强制）', en: 'Volatility Position Sizing (CODE ENFORCED)' },
+      riskSizingDesc: { zh: '按止损距离重新计算 AI 给出的仓位，使每笔亏损等于净值的固定比例；无止损时使用 ATR 距离', en: 'Rescale AI position size so the stop loss risks a fixed % of equity; uses an ATR distance when there is no stop loss' },
+      riskPerTrade: { zh: '单笔风险', en: 'Risk Per Trade' },
+      riskPerTradeDesc: { zh: '止损时亏损占净值的百分比', en: 'Equity % lost when the stop loss is hit' },
+      atrStopMultiplier: { zh: 'ATR 止损倍数', en: 'ATR Stop Multiplier' },
+      atrStopMultiplierDesc: { zh: '无止损时的止损距离 = 倍数 × ATR14', en: 'Stop distance without stop loss = multiplier × ATR14' },
     }
     return translations[key]?.[language] || key
   }
@@ -270,7 +302,7 @@ export function RiskControlEditor({
                 onChange={(e) =>
                   updateField('min_risk_reward_ratio', parseFloat(e.target.value) || 3)
                 }
-                disabled={disabled}
+                disabled={disabled || config.disable_risk_reward_check}
                 min={1}
                 max={10}
                 step={0.5}
@@ -282,6 +314,15 @@ export function RiskControlEditor({
                 }}
               />
             </div>
+            <label className="flex items-center gap-2 mt-2 text-xs cursor-pointer" style={{ color: '#848E9C' }}>
+              <input
+                type="checkbox"
+                checked={config.disable_risk_reward_check ?? false}
+                onChange={(e) => updateField('disable_risk_reward_check', e.target.checked)}
+                disabled={disabled}
+              />
+              {t('disableRiskRewardCheck')}
+            </label>
           </div>
 
           <div
@@ -311,6 +352,148 @@ export function RiskControlEditor({
               </span>
             </div>
           </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('takeProfitLadder')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('takeProfitLadderDesc')}
+            </p>
+            <input
+              type="text"
+              key={formatLadder(config.take_profit_ladder)}
+              defaultValue={formatLadder(config.take_profit_ladder)}
+              onBlur={(e) => updateField('take_profit_ladder', parseLadder(e.target.value))}
+              disabled={disabled}
+              placeholder="2:50, 4:25"
+              className="w-full px-3 py-2 rounded"
+              style={{
+                background: '#1E2329',
+                border: '1px solid #2B3139',
+                color: '#EAECEF',
+              }}
+            />
+            <div className="flex items-center gap-2 mt-2 text-xs" style={{ color: '#848E9C' }}>
+              {t('runnerTrailingStop')}
+              <input
+                type="number"
+                value={config.runner_trailing_stop_pct ?? 0}
+                onChange={(e) =>
+                  updateField('runner_trailing_stop_pct', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                max={20}
+                step={0.5}
+                className="w-16 px-2 py-1 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              %
+            </div>
+          </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('maxHolding')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('maxHoldingDesc')}
+            </p>
+            <div className="flex items-center gap-2">
+              <input
+                type="number"
+                value={config.max_holding_hours ?? 0}
+                onChange={(e) =>
+                  updateField('max_holding_hours', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                step={1}
+                className="w-20 px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span style={{ color: '#848E9C' }}>h</span>
+              <select
+                value={config.max_holding_action ?? 'flag'}
+                onChange={(e) =>
+                  updateField('max_holding_action', e.target.value as 'flag' | 'close')
+                }
+                disabled={disabled || !config.max_holding_hours}
+                className="px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              >
+                <option value="flag">{t('holdingFlag')}</option>
+                <option value="close">{t('holdingClose')}</option>
+              </select>
+            </div>
+          </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('avoidFunding')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('avoidFundingDesc')}
+            </p>
+            <div className="flex items-center gap-2">
+              <input
+                type="number"
+                value={config.avoid_funding_rate_pct ?? 0}
+                onChange={(e) =>
+                  updateField('avoid_funding_rate_pct', parseFloat(e.target.value) || 0)
+                }
+                disabled={disabled}
+                min={0}
+                step={0.01}
+                className="w-20 px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span style={{ color: '#848E9C' }}>%</span>
+              <input
+                type="number"
+                value={config.avoid_funding_window_mins ?? 30}
+                onChange={(e) =>
+                  updateField('avoid_funding_window_mins', parseInt(e.target.value) || 0)
+                }
+                disabled={disabled || !config.avoid_funding_rate_pct}
+                min={1}
+                step={5}
+                className="w-20 px-3 py-2 rounded"
+                style={{
+                  background: '#1E2329',
+                  border: '1px solid #2B3139',
+                  color: '#EAECEF',
+                }}
+              />
+              <span style={{ color: '#848E9C' }}>{t('minutesBefore')}</span>
+            </div>
+          </div>
         </div>
       </div>
 
@@ -384,7 +567,154 @@ export function RiskControlEditor({
               </span>
             </div>
           </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('maxDepthPct')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('maxDepthPctDesc')}
+            </p>
+            <div className="flex items-center gap-2">
+              <input
+                type="range"
+                value={config.max_depth_pct ?? 10}
+                onChange={(e) =>
+                  updateField('max_depth_pct', parseFloat(e.target.value))
+                }
+                disabled={disabled}
+                min={1}
+                max={50}
+                step={1}
+                className="flex-1 accent-green-500"
+              />
+              <span className="w-12 text-center font-mono" style={{ color: '#0ECB81' }}>
+                {(config.max_depth_pct ?? 10).toFixed(0)}%
+              </span>
+            </div>
+          </div>
+
+          <div
+            className="p-4 rounded-lg"
+            style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+          >
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('blockNearEvents')}
+            </label>
+            <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+              {t('blockNearEventsDesc')}
+            </p>
+            <div className="flex items-center gap-2">
+              <input
+                type="range"
+                value={config.block_entries_near_event_mins ?? 0}
+                onChange={(e) =>
+                  updateField('block_entries_near_event_mins', parseInt(e.target.value))
+                }
+                disabled={disabled}
+                min={0}
+                max={240}
+                step={15}
+                className="flex-1 accent-green-500"
+              />
+              <span className="w-16 text-center font-mono" style={{ color: '#0ECB81' }}>
+                {config.block_entries_near_event_mins ?? 0}m
+              </span>
+            </div>
+          </div>
+        </div>
+      </div>
+
+      {/* Volatility Position Sizing */}
+      <div>
+        <div
+          className="p-4 rounded-lg flex items-center justify-between mb-4"
+          style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+        >
+          <div className="flex-1 pr-4">
+            <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+              {t('riskSizing')}
+            </label>
+            <p className="text-xs" style={{ color: '#848E9C' }}>
+              {t('riskSizingDesc')}
+            </p>
+          </div>
+          <label className="relative inline-flex items-center cursor-pointer">
+            <input
+              type="checkbox"
+              checked={config.risk_sizing_enabled ?? false}
+              onChange={(e) => updateField('risk_sizing_enabled', e.target.checked)}
+              disabled={disabled}
+              className="sr-only peer"
+            />
+            <div className="w-11 h-6 bg-gray-600 peer-focus:outline-none rounded-full peer peer-checked:after:translate-x-full rtl:peer-checked:after:-translate-x-full peer-checked:after:border-white after:content-[''] after:absolute after:top-[2px] after:start-[2px] after:bg-white after:rounded-full after:h-5 after:w-5 after:transition-all peer-checked:bg-[#0ECB81]"></div>
+          </label>
         </div>
+
+        {config.risk_sizing_enabled && (
+          <div className="grid grid-cols-2 gap-4">
+            <div
+              className="p-4 rounded-lg"
+              style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+            >
+              <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+                {t('riskPerTrade')}
+              </label>
+              <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+                {t('riskPerTradeDesc')}
+              </p>
+              <div className="flex items-center gap-2">
+                <input
+                  type="range"
+                  value={config.risk_per_trade_pct ?? 1}
+                  onChange={(e) =>
+                    updateField('risk_per_trade_pct', parseFloat(e.target.value))
+                  }
+                  disabled={disabled}
+                  min={0.1}
+                  max={5}
+                  step={0.1}
+                  className="flex-1 accent-green-500"
+                />
+                <span className="w-12 text-center font-mono" style={{ color: '#0ECB81' }}>
+                  {(config.risk_per_trade_pct ?? 1).toFixed(1)}%
+                </span>
+              </div>
+            </div>
+
+            <div
+              className="p-4 rounded-lg"
+              style={{ background: '#0B0E11', border: '1px solid #2B3139' }}
+            >
+              <label className="block text-sm mb-1" style={{ color: '#EAECEF' }}>
+                {t('atrStopMultiplier')}
+              </label>
+              <p className="text-xs mb-2" style={{ color: '#848E9C' }}>
+                {t('atrStopMultiplierDesc')}
+              </p>
+              <div className="flex items-center gap-2">
+                <input
+                  type="range"
+                  value={config.atr_stop_multiplier ?? 2}
+                  onChange={(e) =>
+                    updateField('atr_stop_multiplier', parseFloat(e.target.value))
+                  }
+                  disabled={disabled}
+                  min={0.5}
+                  max={5}
+                  step={0.5}
+                  className="flex-1 accent-green-500"
+                />
+                <span className="w-12 text-center font-mono" style={{ color: '#0ECB81' }}>
+                  {(config.atr_stop_multiplier ?? 2).toFixed(1)}×
+                </span>
+              </div>
+            </div>
+          </div>
+        )}
       </div>
     </div>
   )
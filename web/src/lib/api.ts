@@ -3,13 +3,42 @@ import type {
   AccountInfo,
   Position,
   DecisionRecord,
+  DecisionDiff,
   Statistics,
+  DailyStatsResponse,
+  FundingRatePoint,
+  MarketDataProviderStatus,
+  GridSuggestRequest,
+  GridSuggestion,
+  OpenInterestPoint,
+  MarketHistoryResponse,
   TraderInfo,
+  TraderTrashResponse,
+  MigrateTraderRequest,
+  MigrateTraderResult,
+  NotificationSettings,
+  NotificationEventType,
+  PushConfig,
+  Webhook,
+  WebhookList,
+  WebhookRequest,
+  CreatedWebhook,
+  PromptTemplate,
+  PromptTemplateVersion,
+  PromptTemplateRequest,
+  PerformanceReport,
+  ReportSettings,
+  GenerateReportRequest,
+  GenerateReportResult,
+  ApprovalConfig,
+  PendingDecision,
   TraderConfigData,
   AIModel,
   Exchange,
   CreateTraderRequest,
   CreateExchangeRequest,
+  RotateExchangeKeysRequest,
+  ExchangeKeyRotationReport,
   UpdateModelConfigRequest,
   UpdateExchangeConfigRequest,
   CompetitionData,
@@ -21,8 +50,16 @@ import type {
   BacktestMetrics,
   BacktestRunMetadata,
   BacktestKlinesResponse,
+  BacktestSweep,
+  BacktestSweepConfig,
+  BacktestSweepsResponse,
+  BacktestWalkForward,
+  BacktestWalkForwardConfig,
+  BacktestWalkForwardsResponse,
   Strategy,
+  StrategyBundle,
   StrategyConfig,
+  StrategyImport,
   DebateSession,
   DebateSessionWithDetails,
   CreateDebateRequest,
@@ -99,6 +136,29 @@ export const api = {
     if (!result.success) throw new Error('删除交易员失败')
   },
 
+  // 回收站：已删除的交易员，可恢复或永久删除
+  async getTraderTrash(): Promise<TraderTrashResponse> {
+    const result = await httpClient.get<TraderTrashResponse>(
+      `${API_BASE}/traders/trash`
+    )
+    if (!result.success) throw new Error('获取回收站失败')
+    return result.data!
+  },
+
+  async restoreTrader(traderId: string): Promise<void> {
+    const result = await httpClient.post(
+      `${API_BASE}/traders/${traderId}/restore`
+    )
+    if (!result.success) throw new Error('恢复交易员失败')
+  },
+
+  async purgeTrader(traderId: string): Promise<void> {
+    const result = await httpClient.delete(
+      `${API_BASE}/traders/trash/${traderId}`
+    )
+    if (!result.success) throw new Error('永久删除交易员失败')
+  },
+
   async startTrader(traderId: string): Promise<void> {
     const result = await httpClient.post(
       `${API_BASE}/traders/${traderId}/start`
@@ -111,6 +171,12 @@ export const api = {
     if (!result.success) throw new Error('停止交易员失败')
   },
 
+  async migrateTrader(traderId: string, request: MigrateTraderRequest): Promise<MigrateTraderResult> {
+    const result = await httpClient.post<MigrateTraderResult>(`${API_BASE}/traders/${traderId}/migrate`, request)
+    if (!result.success) throw new Error('迁移交易员失败')
+    return result.data!
+  },
+
   async toggleCompetition(traderId: string, showInCompetition: boolean): Promise<void> {
     const result = await httpClient.put(
       `${API_BASE}/traders/${traderId}/competition`,
@@ -175,14 +241,51 @@ export const api = {
     return result.data!
   },
 
-  async getPromptTemplates(): Promise<string[]> {
-    const res = await fetch(`${API_BASE}/prompt-templates`)
-    if (!res.ok) throw new Error('获取提示词模板失败')
-    const data = await res.json()
-    if (Array.isArray(data.templates)) {
-      return data.templates.map((item: { name: string }) => item.name)
-    }
-    return []
+  // 提示词模板（内置 + 用户自定义）
+  async getPromptTemplates(): Promise<PromptTemplate[]> {
+    const result = await httpClient.get<{ templates: PromptTemplate[] }>(
+      `${API_BASE}/prompt-templates`
+    )
+    if (!result.success) throw new Error('获取提示词模板失败')
+    return result.data?.templates ?? []
+  },
+
+  async createPromptTemplate(
+    request: PromptTemplateRequest
+  ): Promise<PromptTemplate> {
+    const result = await httpClient.post<PromptTemplate>(
+      `${API_BASE}/prompt-templates`,
+      request
+    )
+    if (!result.success) throw new Error(result.message || '创建提示词模板失败')
+    return result.data!
+  },
+
+  // 内容变化时生成新版本
+  async updatePromptTemplate(
+    id: string,
+    request: PromptTemplateRequest
+  ): Promise<PromptTemplate> {
+    const result = await httpClient.put<PromptTemplate>(
+      `${API_BASE}/prompt-templates/${id}`,
+      request
+    )
+    if (!result.success) throw new Error(result.message || '更新提示词模板失败')
+    return result.data!
+  },
+
+  // 被策略或交易员使用时无法删除
+  async deletePromptTemplate(id: string): Promise<void> {
+    const result = await httpClient.delete(`${API_BASE}/prompt-templates/${id}`)
+    if (!result.success) throw new Error(result.message || '删除提示词模板失败')
+  },
+
+  async getPromptTemplateVersions(id: string): Promise<PromptTemplateVersion[]> {
+    const result = await httpClient.get<{ versions: PromptTemplateVersion[] }>(
+      `${API_BASE}/prompt-templates/${id}/versions`
+    )
+    if (!result.success) throw new Error('获取提示词模板版本失败')
+    return result.data?.versions ?? []
   },
 
   async updateModelConfigs(request: UpdateModelConfigRequest): Promise<void> {
@@ -331,6 +434,35 @@ export const api = {
     if (!result.success) throw new Error('更新交易所配置失败')
   },
 
+  // 轮换交易所API密钥，重新验证所有使用该账户的交易员并恢复验证通过的交易员（自动检测是否启用加密）
+  async rotateExchangeKeys(
+    exchangeId: string,
+    request: RotateExchangeKeysRequest
+  ): Promise<ExchangeKeyRotationReport> {
+    const url = `${API_BASE}/exchanges/${exchangeId}/rotate-keys`
+    const config = await CryptoService.fetchCryptoConfig()
+
+    if (!config.transport_encryption) {
+      const result = await httpClient.post<ExchangeKeyRotationReport>(url, request)
+      if (!result.success) throw new Error('轮换交易所密钥失败')
+      return result.data!
+    }
+
+    const publicKey = await CryptoService.fetchPublicKey()
+    await CryptoService.initialize(publicKey)
+    const userId = localStorage.getItem('user_id') || ''
+    const sessionId = sessionStorage.getItem('session_id') || ''
+    const encryptedPayload = await CryptoService.encryptSensitiveData(
+      JSON.stringify(request),
+      userId,
+      sessionId
+    )
+
+    const result = await httpClient.post<ExchangeKeyRotationReport>(url, encryptedPayload)
+    if (!result.success) throw new Error('轮换交易所密钥失败')
+    return result.data!
+  },
+
   // 获取系统状态（支持trader_id）
   async getStatus(traderId?: string): Promise<SystemStatus> {
     const url = traderId
@@ -390,6 +522,32 @@ export const api = {
     return result.data!
   },
 
+  // 获取相邻决策周期的差异（最新在前）
+  async getDecisionDiffs(traderId: string, limit: number = 10): Promise<DecisionDiff[]> {
+    const params = new URLSearchParams({ trader_id: traderId, limit: limit.toString() })
+    const result = await httpClient.get<{ trader_id: string; diffs: DecisionDiff[] }>(
+      `${API_BASE}/decisions/diff?${params}`
+    )
+    if (!result.success) throw new Error('获取决策差异失败')
+    return result.data!.diffs
+  },
+
+  // 导出提示词与AI原始响应（JSONL，start/end为RFC3339，redact隐藏账户标识）
+  async exportTranscripts(
+    traderId: string,
+    options: { start?: string; end?: string; redact?: boolean } = {}
+  ): Promise<Blob> {
+    const params = new URLSearchParams({ trader_id: traderId })
+    if (options.start) params.set('start', options.start)
+    if (options.end) params.set('end', options.end)
+    if (options.redact) params.set('redact', 'true')
+    const res = await fetch(`${API_BASE}/decisions/transcripts?${params}`, {
+      headers: getAuthHeaders(),
+    })
+    if (!res.ok) throw new Error('导出决策记录失败')
+    return res.blob()
+  },
+
   // 获取统计信息（支持trader_id）
   async getStatistics(traderId?: string): Promise<Statistics> {
     const url = traderId
@@ -400,6 +558,262 @@ export const api = {
     return result.data!
   },
 
+  // 获取每日统计（日历热力图，按用户时区）
+  async getDailyStatistics(traderId: string, from?: string, to?: string): Promise<DailyStatsResponse> {
+    const params = new URLSearchParams({ trader_id: traderId })
+    if (from) params.set('from', from)
+    if (to) params.set('to', to)
+    const result = await httpClient.get<DailyStatsResponse>(`${API_BASE}/statistics/daily?${params}`)
+    if (!result.success) throw new Error('获取每日统计失败')
+    return result.data!
+  },
+
+  // 获取资金费率 / 持仓量历史（与决策叠加绘图）
+  async getFundingHistory(symbol: string, hours = 168): Promise<MarketHistoryResponse<FundingRatePoint>> {
+    const result = await httpClient.get<MarketHistoryResponse<FundingRatePoint>>(
+      `${API_BASE}/market/funding-history?symbol=${encodeURIComponent(symbol)}&hours=${hours}`
+    )
+    if (!result.success) throw new Error('获取资金费率历史失败')
+    return result.data!
+  },
+
+  async getMarketDataProviders(): Promise<MarketDataProviderStatus[]> {
+    const result = await httpClient.get<{ providers: MarketDataProviderStatus[] }>(
+      `${API_BASE}/market/providers`
+    )
+    if (!result.success) throw new Error('获取行情数据源状态失败')
+    return result.data?.providers ?? []
+  },
+
+  async getOIHistory(symbol: string, hours = 168): Promise<MarketHistoryResponse<OpenInterestPoint>> {
+    const result = await httpClient.get<MarketHistoryResponse<OpenInterestPoint>>(
+      `${API_BASE}/market/oi-history?symbol=${encodeURIComponent(symbol)}&hours=${hours}`
+    )
+    if (!result.success) throw new Error('获取持仓量历史失败')
+    return result.data!
+  },
+
+  async getTimezone(): Promise<string> {
+    const result = await httpClient.get<{ timezone: string }>(`${API_BASE}/timezone`)
+    if (!result.success) throw new Error('获取时区失败')
+    return result.data!.timezone
+  },
+
+  async updateTimezone(timezone: string): Promise<string> {
+    const result = await httpClient.put<{ timezone: string }>(`${API_BASE}/timezone`, { timezone })
+    if (!result.success) throw new Error(result.message || '更新时区失败')
+    return result.data!.timezone
+  },
+
+  // 邮件通知设置
+  async getNotificationSettings(): Promise<NotificationSettings> {
+    const result = await httpClient.get<NotificationSettings>(
+      `${API_BASE}/notifications/settings`
+    )
+    if (!result.success) throw new Error('获取通知设置失败')
+    return result.data!
+  },
+
+  async updateNotificationSettings(
+    events: NotificationEventType[],
+    dailyDigest: boolean
+  ): Promise<NotificationSettings> {
+    const result = await httpClient.put<NotificationSettings>(
+      `${API_BASE}/notifications/settings`,
+      { events, daily_digest: dailyDigest }
+    )
+    if (!result.success) throw new Error(result.message || '更新通知设置失败')
+    return result.data!
+  },
+
+  // 设置通知邮箱并发送验证码
+  async setNotificationEmail(email: string): Promise<void> {
+    const result = await httpClient.post(`${API_BASE}/notifications/email`, {
+      email,
+    })
+    if (!result.success) throw new Error(result.message || '发送验证码失败')
+  },
+
+  async verifyNotificationEmail(code: string): Promise<NotificationSettings> {
+    const result = await httpClient.post<NotificationSettings>(
+      `${API_BASE}/notifications/email/verify`,
+      { code }
+    )
+    if (!result.success) throw new Error(result.message || '验证码无效或已过期')
+    return result.data!
+  },
+
+  async sendTestNotification(): Promise<void> {
+    const result = await httpClient.post(`${API_BASE}/notifications/test`)
+    if (!result.success) throw new Error(result.message || '发送测试邮件失败')
+  },
+
+  // 浏览器推送订阅
+  async getPushConfig(): Promise<PushConfig> {
+    const result = await httpClient.get<PushConfig>(`${API_BASE}/push/config`)
+    if (!result.success) throw new Error('获取推送设置失败')
+    return result.data!
+  },
+
+  async subscribePush(subscription: PushSubscriptionJSON): Promise<void> {
+    const result = await httpClient.post(
+      `${API_BASE}/push/subscriptions`,
+      subscription
+    )
+    if (!result.success) throw new Error(result.message || '订阅推送失败')
+  },
+
+  async unsubscribePush(endpoint: string): Promise<void> {
+    const result = await httpClient.post(`${API_BASE}/push/unsubscribe`, {
+      endpoint,
+    })
+    if (!result.success) throw new Error(result.message || '取消推送订阅失败')
+  },
+
+  async sendTestPush(endpoint: string): Promise<void> {
+    const result = await httpClient.post(`${API_BASE}/push/test`, { endpoint })
+    if (!result.success) throw new Error(result.message || '发送测试推送失败')
+  },
+
+  // 自定义 Webhook
+  async getWebhooks(): Promise<WebhookList> {
+    const result = await httpClient.get<WebhookList>(`${API_BASE}/webhooks`)
+    if (!result.success) throw new Error('获取 Webhook 列表失败')
+    return result.data!
+  },
+
+  async createWebhook(request: WebhookRequest): Promise<CreatedWebhook> {
+    const result = await httpClient.post<CreatedWebhook>(
+      `${API_BASE}/webhooks`,
+      request
+    )
+    if (!result.success) throw new Error(result.message || '创建 Webhook 失败')
+    return result.data!
+  },
+
+  async updateWebhook(id: number, request: WebhookRequest): Promise<Webhook> {
+    const result = await httpClient.put<Webhook>(
+      `${API_BASE}/webhooks/${id}`,
+      request
+    )
+    if (!result.success) throw new Error(result.message || '更新 Webhook 失败')
+    return result.data!
+  },
+
+  async deleteWebhook(id: number): Promise<void> {
+    const result = await httpClient.delete(`${API_BASE}/webhooks/${id}`)
+    if (!result.success) throw new Error(result.message || '删除 Webhook 失败')
+  },
+
+  async testWebhook(id: number): Promise<{ status: number }> {
+    const result = await httpClient.post<{ status: number }>(
+      `${API_BASE}/webhooks/${id}/test`
+    )
+    if (!result.success) throw new Error(result.message || 'Webhook 测试失败')
+    return result.data!
+  },
+
+  // 周报 / 月报
+  async getReports(): Promise<PerformanceReport[]> {
+    const result = await httpClient.get<PerformanceReport[]>(`${API_BASE}/reports`)
+    if (!result.success) throw new Error('获取报告列表失败')
+    return result.data ?? []
+  },
+
+  async getReportSettings(): Promise<ReportSettings> {
+    const result = await httpClient.get<ReportSettings>(
+      `${API_BASE}/reports/settings`
+    )
+    if (!result.success) throw new Error('获取报告设置失败')
+    return result.data!
+  },
+
+  async updateReportSettings(
+    settings: Pick<ReportSettings, 'weekly' | 'monthly' | 'email'>
+  ): Promise<ReportSettings> {
+    const result = await httpClient.put<ReportSettings>(
+      `${API_BASE}/reports/settings`,
+      settings
+    )
+    if (!result.success) throw new Error(result.message || '更新报告设置失败')
+    return result.data!
+  },
+
+  async generateReport(
+    request: GenerateReportRequest
+  ): Promise<GenerateReportResult> {
+    const result = await httpClient.post<GenerateReportResult>(
+      `${API_BASE}/reports`,
+      request
+    )
+    if (!result.success) throw new Error(result.message || '生成报告失败')
+    return result.data!
+  },
+
+  async downloadReport(id: number, format: 'html' | 'pdf'): Promise<Blob> {
+    const res = await fetch(`${API_BASE}/reports/${id}?format=${format}`, {
+      headers: getAuthHeaders(),
+    })
+    if (!res.ok) throw new Error('下载报告失败')
+    return res.blob()
+  },
+
+  async deleteReport(id: number): Promise<void> {
+    const result = await httpClient.delete(`${API_BASE}/reports/${id}`)
+    if (!result.success) throw new Error(result.message || '删除报告失败')
+  },
+
+  // 人工确认模式
+  async getApprovalMode(traderId: string): Promise<ApprovalConfig> {
+    const result = await httpClient.get<ApprovalConfig>(
+      `${API_BASE}/traders/${traderId}/approval-mode`
+    )
+    if (!result.success) throw new Error('获取确认模式设置失败')
+    return result.data!
+  },
+
+  async updateApprovalMode(
+    traderId: string,
+    config: Omit<ApprovalConfig, 'trader_id' | 'updated_at'>
+  ): Promise<ApprovalConfig> {
+    const result = await httpClient.put<ApprovalConfig>(
+      `${API_BASE}/traders/${traderId}/approval-mode`,
+      config
+    )
+    if (!result.success) throw new Error(result.message || '更新确认模式失败')
+    return result.data!
+  },
+
+  async getPendingDecisions(traderId: string): Promise<PendingDecision[]> {
+    const result = await httpClient.get<{ decisions: PendingDecision[] }>(
+      `${API_BASE}/traders/${traderId}/pending-decisions`
+    )
+    if (!result.success) throw new Error('获取待确认决策失败')
+    return result.data!.decisions
+  },
+
+  async approvePendingDecision(
+    traderId: string,
+    decisionId: number
+  ): Promise<PendingDecision> {
+    const result = await httpClient.post<{ decision: PendingDecision }>(
+      `${API_BASE}/traders/${traderId}/pending-decisions/${decisionId}/approve`
+    )
+    if (!result.success) throw new Error(result.message || '批准决策失败')
+    return result.data!.decision
+  },
+
+  async rejectPendingDecision(
+    traderId: string,
+    decisionId: number
+  ): Promise<PendingDecision> {
+    const result = await httpClient.post<{ decision: PendingDecision }>(
+      `${API_BASE}/traders/${traderId}/pending-decisions/${decisionId}/reject`
+    )
+    if (!result.success) throw new Error(result.message || '拒绝决策失败')
+    return result.data!.decision
+  },
+
   // 获取收益率历史数据（支持trader_id）
   async getEquityHistory(traderId?: string): Promise<any[]> {
     const url = traderId
@@ -623,6 +1037,90 @@ export const api = {
     return handleJSONResponse<DecisionRecord[]>(res)
   },
 
+  async startBacktestSweep(config: BacktestSweepConfig): Promise<BacktestSweep> {
+    const res = await fetch(`${API_BASE}/backtest/sweeps`, {
+      method: 'POST',
+      headers: getAuthHeaders(),
+      body: JSON.stringify(config),
+    })
+    return handleJSONResponse<BacktestSweep>(res)
+  },
+
+  async getBacktestSweeps(): Promise<BacktestSweepsResponse> {
+    const res = await fetch(`${API_BASE}/backtest/sweeps`, {
+      headers: getAuthHeaders(),
+    })
+    return handleJSONResponse<BacktestSweepsResponse>(res)
+  },
+
+  async getBacktestSweep(sweepId: string): Promise<BacktestSweep> {
+    const res = await fetch(`${API_BASE}/backtest/sweeps/${sweepId}`, {
+      headers: getAuthHeaders(),
+    })
+    return handleJSONResponse<BacktestSweep>(res)
+  },
+
+  async stopBacktestSweep(sweepId: string): Promise<BacktestSweep> {
+    const res = await fetch(`${API_BASE}/backtest/sweeps/${sweepId}/stop`, {
+      method: 'POST',
+      headers: getAuthHeaders(),
+    })
+    return handleJSONResponse<BacktestSweep>(res)
+  },
+
+  async deleteBacktestSweep(sweepId: string): Promise<void> {
+    const res = await fetch(`${API_BASE}/backtest/sweeps/${sweepId}`, {
+      method: 'DELETE',
+      headers: getAuthHeaders(),
+    })
+    if (!res.ok) {
+      throw new Error(await res.text())
+    }
+  },
+
+  async startWalkForward(
+    config: BacktestWalkForwardConfig
+  ): Promise<BacktestWalkForward> {
+    const res = await fetch(`${API_BASE}/backtest/walk-forward`, {
+      method: 'POST',
+      headers: getAuthHeaders(),
+      body: JSON.stringify(config),
+    })
+    return handleJSONResponse<BacktestWalkForward>(res)
+  },
+
+  async getWalkForwards(): Promise<BacktestWalkForwardsResponse> {
+    const res = await fetch(`${API_BASE}/backtest/walk-forward`, {
+      headers: getAuthHeaders(),
+    })
+    return handleJSONResponse<BacktestWalkForwardsResponse>(res)
+  },
+
+  async getWalkForward(id: string): Promise<BacktestWalkForward> {
+    const res = await fetch(`${API_BASE}/backtest/walk-forward/${id}`, {
+      headers: getAuthHeaders(),
+    })
+    return handleJSONResponse<BacktestWalkForward>(res)
+  },
+
+  async stopWalkForward(id: string): Promise<BacktestWalkForward> {
+    const res = await fetch(`${API_BASE}/backtest/walk-forward/${id}/stop`, {
+      method: 'POST',
+      headers: getAuthHeaders(),
+    })
+    return handleJSONResponse<BacktestWalkForward>(res)
+  },
+
+  async deleteWalkForward(id: string): Promise<void> {
+    const res = await fetch(`${API_BASE}/backtest/walk-forward/${id}`, {
+      method: 'DELETE',
+      headers: getAuthHeaders(),
+    })
+    if (!res.ok) {
+      throw new Error(await res.text())
+    }
+  },
+
   async exportBacktest(runId: string): Promise<Blob> {
     const res = await fetch(`${API_BASE}/backtest/export?run_id=${runId}`, {
       headers: getAuthHeaders(),
@@ -710,6 +1208,37 @@ export const api = {
     return result.data!
   },
 
+  async suggestGridConfig(request: GridSuggestRequest): Promise<GridSuggestion> {
+    const result = await httpClient.post<GridSuggestion>(`${API_BASE}/strategies/grid/suggest`, request)
+    if (!result.success) throw new Error('生成网格参数建议失败')
+    return result.data!
+  },
+
+  async exportStrategyBundle(strategyId: string, version?: string): Promise<StrategyBundle> {
+    const query = version ? `?version=${encodeURIComponent(version)}` : ''
+    const result = await httpClient.get<StrategyBundle>(`${API_BASE}/strategies/${strategyId}/export${query}`)
+    if (!result.success) throw new Error('导出策略包失败')
+    return result.data!
+  },
+
+  async importStrategyBundle(
+    bundle: StrategyBundle,
+    name?: string
+  ): Promise<{ id: string; name: string; provenance: StrategyImport }> {
+    const result = await httpClient.post<{ id: string; name: string; provenance: StrategyImport }>(
+      `${API_BASE}/strategies/import`,
+      { bundle, name }
+    )
+    if (!result.success) throw new Error(result.message || '导入策略包失败')
+    return result.data!
+  },
+
+  async getStrategyImports(): Promise<StrategyImport[]> {
+    const result = await httpClient.get<{ imports: StrategyImport[] }>(`${API_BASE}/strategies/imports`)
+    if (!result.success) throw new Error('获取导入记录失败')
+    return Array.isArray(result.data?.imports) ? result.data!.imports : []
+  },
+
   // Debate Arena APIs
   async getDebates(): Promise<DebateSession[]> {
     const result = await httpClient.get<DebateSession[]>(`${API_BASE}/debates`)
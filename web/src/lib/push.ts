@@ -0,0 +1,56 @@
+import { api } from './api'
+
+// 浏览器推送（Web Push）：注册 service worker 并向服务器登记订阅
+const SERVICE_WORKER_URL = '/push-sw.js'
+
+export function isPushSupported(): boolean {
+  return (
+    typeof window !== 'undefined' &&
+    window.isSecureContext &&
+    'serviceWorker' in navigator &&
+    'PushManager' in window &&
+    'Notification' in window
+  )
+}
+
+function urlBase64ToUint8Array(base64: string): Uint8Array {
+  const padding = '='.repeat((4 - (base64.length % 4)) % 4)
+  const raw = atob((base64 + padding).replace(/-/g, '+').replace(/_/g, '/'))
+  return Uint8Array.from(raw, (c) => c.charCodeAt(0))
+}
+
+// 当前浏览器的订阅（未订阅时为 null）
+export async function getCurrentPushSubscription(): Promise<PushSubscription | null> {
+  if (!isPushSupported()) return null
+  const registration = await navigator.serviceWorker.getRegistration(SERVICE_WORKER_URL)
+  return registration ? registration.pushManager.getSubscription() : null
+}
+
+// 请求通知权限并订阅，成功后返回订阅
+export async function subscribeToPush(): Promise<PushSubscription> {
+  if (!isPushSupported()) throw new Error('当前浏览器不支持推送通知（需要 HTTPS）')
+  const config = await api.getPushConfig()
+  if (!config.enabled || !config.public_key) throw new Error('服务器未启用推送通知')
+
+  const permission = await Notification.requestPermission()
+  if (permission !== 'granted') throw new Error('通知权限被拒绝')
+
+  const registration = await navigator.serviceWorker.register(SERVICE_WORKER_URL)
+  await navigator.serviceWorker.ready
+  let subscription = await registration.pushManager.getSubscription()
+  if (!subscription) {
+    subscription = await registration.pushManager.subscribe({
+      userVisibleOnly: true,
+      applicationServerKey: urlBase64ToUint8Array(config.public_key),
+    })
+  }
+  await api.subscribePush(subscription.toJSON())
+  return subscription
+}
+
+export async function unsubscribeFromPush(): Promise<void> {
+  const subscription = await getCurrentPushSubscription()
+  if (!subscription) return
+  await api.unsubscribePush(subscription.endpoint)
+  await subscription.unsubscribe()
+}
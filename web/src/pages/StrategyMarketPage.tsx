@@ -219,9 +219,17 @@ export function StrategyMarketPage() {
     if (config.indicators.enable_rsi) indicators.push('RSI')
     if (config.indicators.enable_atr) indicators.push('ATR')
     if (config.indicators.enable_boll) indicators.push('BOLL')
+    if (config.indicators.enable_vwap) indicators.push('VWAP')
+    if (config.indicators.enable_supertrend) indicators.push('ST')
+    if (config.indicators.enable_ichimoku) indicators.push('ICHI')
+    if (config.indicators.enable_obv) indicators.push('OBV')
+    if (config.indicators.enable_stoch_rsi) indicators.push('SRSI')
     if (config.indicators.enable_volume) indicators.push('VOL')
     if (config.indicators.enable_oi) indicators.push('OI')
     if (config.indicators.enable_funding_rate) indicators.push('FR')
+    if (config.indicators.enable_news) indicators.push('NEWS')
+    if (config.indicators.enable_economic_calendar) indicators.push('CAL')
+    if (config.indicators.enable_sentiment) indicators.push('FNG')
     return indicators
   }
 
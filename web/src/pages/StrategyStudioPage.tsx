@@ -89,7 +89,11 @@ export function StrategyStudioPage() {
     decisions?: unknown[]
     error?: string
     duration_ms?: number
+    progress?: string
+    notes?: string[]
   } | null>(null)
+  // Optional past time to replay the test run at (datetime-local value, empty = now)
+  const [testAsOf, setTestAsOf] = useState<string>('')
   const [isRunningAiTest, setIsRunningAiTest] = useState(false)
 
   const toggleSection = (section: keyof typeof expandedSections) => {
@@ -451,13 +455,15 @@ export function StrategyStudioPage() {
     }
   }
 
-  // Run AI test with real AI model
+  // Run AI test with real AI model, streamed as server-sent events
   const runAiTest = async () => {
     if (!token || !editingConfig || !selectedModelId) return
     setIsRunningAiTest(true)
-    setAiTestResult(null)
+    setAiTestResult({ progress: '...' })
+    const update = (patch: NonNullable<typeof aiTestResult>) =>
+      setAiTestResult((prev) => ({ ...prev, ...patch }))
     try {
-      const response = await fetch(`${API_BASE}/api/strategies/test-run`, {
+      const response = await fetch(`${API_BASE}/api/strategies/test-run/stream`, {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
@@ -468,11 +474,63 @@ export function StrategyStudioPage() {
           prompt_variant: selectedVariant,
           ai_model_id: selectedModelId,
           run_real_ai: true,
+          ...(testAsOf && { as_of: new Date(testAsOf).toISOString() }),
         }),
       })
-      if (!response.ok) throw new Error('Failed to run AI test')
-      const data = await response.json()
-      setAiTestResult(data)
+      if (!response.ok || !response.body) throw new Error('Failed to run AI test')
+
+      const reader = response.body.getReader()
+      const decoder = new TextDecoder()
+      let buffer = ''
+      let aiText = ''
+      for (;;) {
+        const { done, value } = await reader.read()
+        if (done) break
+        buffer += decoder.decode(value, { stream: true })
+        const events = buffer.split('\n\n')
+        buffer = events.pop() ?? ''
+        for (const raw of events) {
+          const event = raw.match(/^event: (.*)$/m)?.[1]
+          const dataLine = raw.match(/^data: (.*)$/m)?.[1]
+          if (!event || !dataLine) continue
+          const data = JSON.parse(dataLine)
+          switch (event) {
+            case 'candidates':
+              update({ progress: `${t('candidatesSelected')}: ${data.count}` })
+              break
+            case 'market_data':
+              update({ progress: `${t('fetchingMarketData')} ${data.done}/${data.total} (${data.symbol})` })
+              break
+            case 'prompt_built':
+              update({
+                system_prompt: data.system_prompt,
+                user_prompt: data.user_prompt,
+                progress: `${t('promptBuilt')}: ~${data.estimated_tokens} tokens`,
+                notes: data.notes ?? undefined,
+              })
+              break
+            case 'ai_start':
+              update({ progress: t('waitingForAI') })
+              break
+            case 'ai_token':
+              aiText += data.text
+              update({ ai_response: aiText })
+              break
+            case 'ai_response':
+              update({ ai_response: data.text, duration_ms: data.duration_ms })
+              break
+            case 'decisions':
+              update({ reasoning: data.reasoning, decisions: data.decisions })
+              break
+            case 'validation':
+              update({ progress: data.valid ? undefined : `${t('validationFailed')}: ${data.error}` })
+              break
+            case 'error':
+              update({ error: data.error, progress: undefined })
+              break
+          }
+        }
+      }
     } catch (err) {
       setAiTestResult({
         error: err instanceof Error ? err.message : 'Unknown error',
@@ -518,11 +576,18 @@ export function StrategyStudioPage() {
       runTest: { zh: '运行 AI 测试', en: 'Run AI Test' },
       running: { zh: '运行中...', en: 'Running...' },
       aiOutput: { zh: 'AI 输出', en: 'AI Output' },
+      candidatesSelected: { zh: '候选币种', en: 'Candidates' },
+      fetchingMarketData: { zh: '获取行情数据', en: 'Fetching market data' },
+      promptBuilt: { zh: 'Prompt 已生成', en: 'Prompt built' },
+      waitingForAI: { zh: '等待 AI 响应...', en: 'Waiting for AI...' },
+      validationFailed: { zh: '决策校验失败', en: 'Decision validation failed' },
       reasoning: { zh: '思维链', en: 'Reasoning' },
       decisions: { zh: '决策', en: 'Decisions' },
       duration: { zh: '耗时', en: 'Duration' },
       noModel: { zh: '请先配置 AI 模型', en: 'Please configure AI model first' },
       testNote: { zh: '使用真实 AI 模型测试，不执行交易', en: 'Test with real AI, no trading' },
+      asOf: { zh: '历史时刻', en: 'As of' },
+      asOfHint: { zh: '留空使用当前行情，或选择过去的时间回放当时的行情', en: 'Leave empty for live data, or pick a past time to replay its market' },
       publishSettings: { zh: '发布设置', en: 'Publish' },
     }
     return translations[key]?.[language] || key
@@ -1094,6 +1159,17 @@ export function StrategyStudioPage() {
                       )}
                     </button>
                   </div>
+                  <div className="flex items-center gap-2">
+                    <Clock className="w-3 h-3 text-nofx-text-muted" />
+                    <span className="text-xs text-nofx-text-muted">{t('asOf')}</span>
+                    <input
+                      type="datetime-local"
+                      value={testAsOf}
+                      onChange={(e) => setTestAsOf(e.target.value)}
+                      title={t('asOfHint')}
+                      className="flex-1 px-2 py-1 rounded text-xs bg-nofx-bg border border-nofx-gold/20 text-nofx-text"
+                    />
+                  </div>
                   <p className="text-[10px] text-nofx-text-muted">{t('testNote')}</p>
                 </div>
 
@@ -1106,6 +1182,19 @@ export function StrategyStudioPage() {
                       </div>
                     ) : (
                       <>
+                        {aiTestResult.progress && (
+                          <div className="flex items-center gap-2">
+                            {isRunningAiTest && <Loader2 className="w-3 h-3 animate-spin text-nofx-text-muted" />}
+                            <span className="text-xs text-nofx-text-muted">{aiTestResult.progress}</span>
+                          </div>
+                        )}
+
+                        {aiTestResult.notes?.map((note) => (
+                          <p key={note} className="text-[10px] text-nofx-text-muted">
+                            ⓘ {note}
+                          </p>
+                        ))}
+
                         {aiTestResult.duration_ms && (
                           <div className="flex items-center gap-2">
                             <Clock className="w-3 h-3 text-nofx-text-muted" />
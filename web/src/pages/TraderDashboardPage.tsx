@@ -11,6 +11,7 @@ import { t, type Language } from '../i18n/translations'
 import { LogOut, Loader2, Eye, EyeOff, Copy, Check } from 'lucide-react'
 import { DeepVoidBackground } from '../components/DeepVoidBackground'
 import { GridRiskPanel } from '../components/strategy/GridRiskPanel'
+import { TraderLogPanel } from '../components/TraderLogPanel'
 import type {
     SystemStatus,
     AccountInfo,
@@ -738,6 +739,9 @@ export function TraderDashboardPage({
                                 </div>
                             )}
                         </div>
+
+                        {/* Live trader logs */}
+                        {selectedTraderId && <TraderLogPanel traderId={selectedTraderId} language={language} />}
                     </div>
 
                     {/* Right Column: Recent Decisions */}
@@ -27,6 +27,17 @@ export interface AccountInfo {
   position_count: number
   margin_used: number
   margin_used_pct: number
+  collateral?: CollateralAsset[] // Per-asset balances (USDC/BUSD, multi-asset margin)
+  multi_asset_margin?: boolean
+}
+
+export interface CollateralAsset {
+  asset: string
+  wallet_balance: number
+  unrealized_pnl: number
+  available: number
+  index_price: number
+  usd_value: number
 }
 
 export interface Position {
@@ -53,6 +64,9 @@ export interface DecisionAction {
   confidence?: number     // AI confidence (0-100)
   reasoning?: string      // Brief reasoning
   order_id: number
+  execution_algo?: 'twap' | 'iceberg'  // Set when executed as child orders
+  child_orders?: number   // Child orders filled so far
+  split_entry_id?: number
   timestamp: string
   success: boolean
   error?: string
@@ -82,14 +96,114 @@ export interface DecisionRecord {
   error_message?: string
 }
 
+// Position held in a decision cycle
+export interface PositionSnapshot {
+  symbol: string
+  side: string
+  position_amt: number
+  entry_price: number
+  mark_price: number
+  unrealized_profit: number
+  leverage: number
+  liquidation_price: number
+}
+
+// What changed between two consecutive decision cycles (lists are omitted when unchanged)
+export interface DecisionDiff {
+  from_id: number
+  to_id: number
+  from_cycle: number
+  to_cycle: number
+  from_time: string
+  to_time: string
+  positions_opened?: PositionSnapshot[]
+  positions_closed?: PositionSnapshot[]
+  positions_resized?: { symbol: string; side: string; from_amt: number; to_amt: number }[]
+  // Empty action: the symbol was not decided on in that cycle
+  action_changes?: {
+    symbol: string
+    from_action: string
+    to_action: string
+    from_confidence: number
+    to_confidence: number
+    confidence_delta: number
+  }[]
+  candidates_added?: string[]
+  candidates_removed?: string[]
+  topics_added?: string[]
+  topics_dropped?: string[]
+  equity_change: number
+}
+
 export interface Statistics {
   total_cycles: number
   successful_cycles: number
   failed_cycles: number
   total_open_positions: number
   total_close_positions: number
+  realized_pnl: number
+  total_funding: number
+  total_commission: number
+  net_pnl: number
+  // False when the exchange's funding and commissions are not synced (funding stays 0)
+  income_synced: boolean
+  // Failed exchange calls by error class since the trader was loaded
+  exchange_errors?: Partial<Record<ExchangeErrorClass, number>>
+}
+
+// One calendar day of a trader, in the owner's timezone (calendar heatmaps)
+export interface DailyStat {
+  trader_id: string
+  date: string // YYYY-MM-DD
+  timezone: string
+  trades: number
+  wins: number
+  losses: number
+  win_rate: number
+  pnl: number
+  fees: number
+  start_equity: number
+  end_equity: number
+  max_drawdown_pct: number
+  updated_at: string
+}
+
+export interface DailyStatsResponse {
+  trader_id: string
+  timezone: string
+  from: string
+  to: string
+  days: DailyStat[]
+}
+
+// Funding rate / open interest recorded by decision cycles (one point per 5 minutes)
+export interface FundingRatePoint {
+  symbol: string
+  timestamp: string
+  rate: number
+}
+
+export interface OpenInterestPoint {
+  symbol: string
+  timestamp: string
+  open_interest: number
+  value_usd: number
 }
 
+export interface MarketHistoryResponse<T> {
+  symbol: string
+  points: T[]
+}
+
+export type ExchangeErrorClass =
+  | 'rate_limited'
+  | 'auth'
+  | 'insufficient_margin'
+  | 'invalid_symbol'
+  | 'invalid_order'
+  | 'network'
+  | 'unknown'
+
 // AI Trading相关类型
 export interface TraderInfo {
   trader_id: string
@@ -100,12 +214,235 @@ export interface TraderInfo {
   show_in_competition?: boolean
   strategy_id?: string
   strategy_name?: string
+  contract_type?: ContractType
+  margin_coin?: string
   custom_prompt?: string
   use_ai500?: boolean
   use_oi_top?: boolean
   system_prompt_template?: string
 }
 
+// 回收站中的交易员（删除后保留历史，可恢复，purge_at 后永久删除）
+export interface DeletedTrader {
+  trader_id: string
+  trader_name: string
+  ai_model_id: string
+  exchange_id: string
+  paper_mode: boolean
+  created_at: string
+  deleted_at: string
+  purge_at: string | null // null 表示不自动清除
+}
+
+export interface TraderTrashResponse {
+  traders: DeletedTrader[]
+  retention_days: number
+}
+
+// 交易员迁移：将持仓镜像到（或仅在源交易所平掉）另一个交易所账户后切换交易员的交易所
+export interface MigrateTraderRequest {
+  exchange_id: string
+  mode?: 'mirror' | 'close' // 默认 mirror
+  max_price_deviation_pct?: number // 两个交易所价差上限，默认 0.5%
+  dry_run?: boolean // 仅检查并返回迁移计划
+}
+
+export interface MigrationStep {
+  step: 'plan' | 'open' | 'protect' | 'close' | 'rollback' | 'switch'
+  symbol?: string
+  side?: string
+  quantity?: number
+  price?: number
+  success: boolean
+  message?: string
+  time: string
+}
+
+export interface MigrateTraderResult {
+  message: string
+  exchange_id?: string
+  restarted?: boolean
+  dry_run?: boolean
+  steps: MigrationStep[]
+}
+
+// 邮件通知：关键事件提醒（出错停止、熔断、临近强平）与每日绩效摘要
+export type NotificationEventType =
+  | 'halted'
+  | 'circuit_breaker'
+  | 'liquidation_risk'
+  | 'cycle_timeout'
+  | 'error'
+  | 'approval_pending'
+  | 'unprotected_position' // 止损下单失败（已尝试平仓）
+
+export interface NotificationSettings {
+  enabled: boolean // 服务器是否配置了 SMTP
+  email: string
+  email_verified: boolean
+  pending: boolean // 已发送验证码，等待验证
+  events: NotificationEventType[]
+  available_events: NotificationEventType[]
+  daily_digest: boolean
+  digest_hour: number // 用户时区内的发送时间（小时）
+}
+
+// 自定义 Webhook：事件以 JSON 模板渲染后 POST，HMAC-SHA256 签名
+export interface Webhook {
+  id: number
+  name: string
+  url: string
+  template: string // 为空使用默认模板
+  events: string[]
+  trader_id: string // 为空表示全部交易员
+  enabled: boolean
+  has_secret: boolean
+  last_status: number
+  last_error: string
+  last_sent_at?: string
+  created_at: string
+  updated_at: string
+}
+
+export interface WebhookList {
+  enabled: boolean
+  webhooks: Webhook[]
+  available_events: string[]
+  variables: string[] // 模板变量，如 {{.trader}} {{.symbol}} {{.pnl}} {{.action}}
+  default_template: string
+}
+
+export interface WebhookRequest {
+  name: string
+  url: string
+  secret?: string // 创建时为空则自动生成，更新时为空则保留
+  template?: string
+  events?: string[]
+  trader_id?: string
+  enabled?: boolean
+}
+
+// 仅在创建时返回签名密钥
+export interface CreatedWebhook extends Webhook {
+  secret: string
+}
+
+// 提示词模板：内容每次修改生成新版本，策略可固定使用某一版本
+export interface PromptTemplate {
+  id: string
+  name: string
+  description: string
+  content: string // 最新版本内容
+  version: number
+  builtin: boolean // 内置模板只读
+  created_at: string
+  updated_at: string
+}
+
+export interface PromptTemplateVersion {
+  template_id: string
+  version: number
+  content: string
+  created_at: string
+}
+
+export interface PromptTemplateRequest {
+  name: string
+  description?: string
+  content: string
+}
+
+// 周报 / 月报（HTML/PDF）
+export type ReportPeriod = 'weekly' | 'monthly'
+
+export interface PerformanceReport {
+  id: number
+  user_id: string
+  trader_id: string // 为空表示全部交易员
+  period: ReportPeriod
+  start_date: string // YYYY-MM-DD，用户时区
+  end_date: string
+  timezone: string
+  net_pnl: number
+  return_pct: number
+  trades: number
+  emailed_at?: string
+  created_at: string
+}
+
+export interface ReportSettings {
+  user_id: string
+  weekly: boolean
+  monthly: boolean
+  email: boolean // 同时发送到已验证的通知邮箱
+  updated_at: string
+}
+
+export interface GenerateReportRequest {
+  period: ReportPeriod
+  trader_id?: string
+  date?: string // YYYY-MM-DD，为空则生成上一个完整周期
+  email?: boolean
+}
+
+export interface GenerateReportResult {
+  report: PerformanceReport
+  email_error?: string
+}
+
+// 浏览器推送（Web Push）
+export interface PushSubscriptionInfo {
+  id: number
+  endpoint: string
+  user_agent: string
+  created_at: string
+  updated_at: string
+}
+
+export interface PushConfig {
+  enabled: boolean
+  public_key: string // VAPID 公钥（applicationServerKey）
+  subscriptions: PushSubscriptionInfo[]
+}
+
+// 人工确认模式：大额或低置信度的开仓决策需在时间窗口内批准，否则自动过期
+export interface ApprovalConfig {
+  trader_id: string
+  enabled: boolean
+  min_notional_usd: number // 名义价值不低于此值的开仓需确认（0 = 不限）
+  min_confidence: number // 置信度低于此值的开仓需确认（0 = 不限）
+  window_minutes: number
+  updated_at?: string
+}
+
+export type PendingDecisionStatus =
+  | 'PENDING'
+  | 'APPROVED'
+  | 'EXECUTED'
+  | 'FAILED'
+  | 'REJECTED'
+  | 'EXPIRED'
+
+export interface PendingDecision {
+  id: number
+  trader_id: string
+  symbol: string
+  action: string
+  leverage: number
+  notional_usd: number
+  confidence: number
+  reasoning: string
+  hold_reason: string
+  status: PendingDecisionStatus
+  error: string
+  expires_at: number // Unix 毫秒
+  decided_at: number
+  created_at: number
+}
+
+// 合约类型：linear（U本位）或 inverse（币本位，余额与盈亏以 margin_coin 计）
+export type ContractType = 'linear' | 'inverse'
+
 export interface AIModel {
   id: string
   name: string
@@ -138,6 +475,40 @@ export interface Exchange {
   lighterPrivateKey?: string
   lighterApiKeyPrivateKey?: string
   lighterApiKeyIndex?: number
+  // API key permission check (futures trading allowed, withdrawals disabled)
+  key_verification_status?: '' | 'pending' | 'verified' | 'missing_permission' | 'withdrawal_enabled' | 'unverifiable' | 'failed'
+  key_verification_message?: string
+  key_verified_at?: string
+}
+
+// Key rotation: empty fields keep their current value
+export interface RotateExchangeKeysRequest {
+  api_key?: string
+  secret_key?: string
+  passphrase?: string
+  aster_signer?: string
+  aster_private_key?: string
+  lighter_api_key_private_key?: string
+  lighter_api_key_index?: number
+}
+
+export interface TraderKeyCheck {
+  trader_id: string
+  name: string
+  symbol: string                 // Symbol the open order query ran for
+  balance_ok: boolean
+  open_orders_ok: boolean
+  error?: string
+  was_running: boolean
+  resumed: boolean
+  skip_reason?: string           // Why a trader that was running stays stopped
+}
+
+export interface ExchangeKeyRotationReport {
+  exchange_id: string
+  key_verification_status: string
+  key_verification_message: string
+  traders: TraderKeyCheck[]
 }
 
 export interface CreateExchangeRequest {
@@ -167,6 +538,8 @@ export interface CreateTraderRequest {
   scan_interval_minutes?: number
   is_cross_margin?: boolean
   show_in_competition?: boolean // 是否在竞技场显示
+  contract_type?: ContractType // 默认 linear；inverse 仅支持 Binance/Bybit，创建后不可修改
+  margin_coin?: string // 币本位保证金币种，默认 BTC
   // 以下字段为向后兼容保留，新版使用策略配置
   btc_eth_leverage?: number
   altcoin_leverage?: number
@@ -244,6 +617,8 @@ export interface TraderConfigData {
   scan_interval_minutes: number
   initial_balance: number
   is_running: boolean
+  contract_type?: ContractType
+  margin_coin?: string
   // 以下为旧版字段（向后兼容）
   btc_eth_leverage?: number
   altcoin_leverage?: number
@@ -353,6 +728,9 @@ export interface BacktestMetrics {
   best_symbol: string;
   worst_symbol: string;
   liquidated: boolean;
+  total_fees?: number;
+  total_slippage?: number;
+  cost_assumptions?: BacktestCostAssumptions;
   symbol_stats?: Record<
     string,
     {
@@ -366,6 +744,21 @@ export interface BacktestMetrics {
   >;
 }
 
+export type BacktestSlippageModel = 'fixed' | 'volume_impact';
+
+export interface BacktestCostAssumptions {
+  exchange?: string;
+  maker_bps: number;
+  taker_bps: number;
+  liquidity: 'taker' | 'maker';
+  fee_bps: number;
+  slippage_model: BacktestSlippageModel;
+  slippage_bps: number;
+  impact_bps?: number;
+  max_slippage_bps?: number;
+  fill_policy: string;
+}
+
 export interface BacktestStartConfig {
   run_id?: string;
   ai_model_id?: string;
@@ -379,6 +772,18 @@ export interface BacktestStartConfig {
   initial_balance: number;
   fee_bps: number;
   slippage_bps: number;
+  fees?: {
+    exchange?: string; // Default maker/taker tier of the exchange
+    maker_bps?: number;
+    taker_bps?: number;
+    liquidity?: 'taker' | 'maker';
+  };
+  slippage?: {
+    model: BacktestSlippageModel;
+    bps?: number;
+    impact_bps?: number; // volume_impact: extra bps at 1% of bar volume
+    max_bps?: number;
+  };
   fill_policy: string;
   prompt_variant?: string;
   prompt_template?: string;
@@ -386,6 +791,7 @@ export interface BacktestStartConfig {
   override_prompt?: boolean;
   cache_ai?: boolean;
   replay_only?: boolean;
+  no_response_cache?: boolean; // 不复用相同提示词的 AI 响应（默认复用）
   checkpoint_interval_bars?: number;
   checkpoint_interval_seconds?: number;
   replay_decision_dir?: string;
@@ -403,6 +809,132 @@ export interface BacktestStartConfig {
   };
 }
 
+export type BacktestSweepParamName =
+  | 'leverage'
+  | 'btc_eth_leverage'
+  | 'altcoin_leverage'
+  | 'grid_count'
+  | 'atr_multiplier'
+  | 'min_confidence'
+  | 'decision_cadence_nbars';
+
+// Swept parameter: explicit values, or a min/max/step range
+export interface BacktestSweepParam {
+  name: BacktestSweepParamName;
+  values?: number[];
+  min?: number;
+  max?: number;
+  step?: number;
+}
+
+export interface BacktestSweepConfig {
+  sweep_id?: string;
+  label?: string;
+  base: BacktestStartConfig;
+  params: BacktestSweepParam[];
+  parallelism?: number;
+  rank_by?: 'sharpe' | 'max_drawdown' | 'return';
+}
+
+export interface BacktestSweepResult {
+  run_id: string;
+  params: Record<string, number>;
+  state: string;
+  rank?: number;
+  error?: string;
+  metrics?: BacktestMetrics;
+}
+
+export interface BacktestSweep {
+  sweep_id: string;
+  user_id?: string;
+  label?: string;
+  state: string;
+  rank_by: string;
+  parallelism: number;
+  params: BacktestSweepParam[];
+  symbols: string[];
+  start_ts: number;
+  end_ts: number;
+  results: BacktestSweepResult[];
+  created_at: string;
+  updated_at: string;
+}
+
+export interface BacktestSweepsResponse {
+  total: number;
+  items: BacktestSweep[];
+}
+
+export interface BacktestWalkForwardConfig {
+  walk_forward_id?: string;
+  label?: string;
+  base: BacktestStartConfig; // start_ts/end_ts span all windows
+  train_hours: number;
+  test_hours: number;
+  step_hours?: number;
+  anchored?: boolean;
+  params?: BacktestSweepParam[]; // Optimized on every train window
+  parallelism?: number;
+  rank_by?: 'sharpe' | 'max_drawdown' | 'return';
+}
+
+export interface BacktestWalkForwardWindow {
+  index: number;
+  train_start_ts: number;
+  train_end_ts: number;
+  test_start_ts: number;
+  test_end_ts: number;
+  state: string;
+  error?: string;
+  best_params?: Record<string, number>;
+  train_run_id?: string;
+  test_run_id?: string;
+  train_metrics?: BacktestMetrics;
+  test_metrics?: BacktestMetrics;
+  candidates?: BacktestSweepResult[];
+}
+
+export interface BacktestWalkForwardSummary {
+  windows: number;
+  completed_windows: number;
+  profitable_windows: number;
+  profitable_ratio: number;
+  avg_test_return_pct: number;
+  compounded_test_return_pct: number;
+  avg_test_sharpe: number;
+  worst_test_drawdown_pct: number;
+  avg_train_return_pct: number;
+  avg_train_sharpe: number;
+  efficiency: number;
+  param_stability?: number;
+}
+
+export interface BacktestWalkForward {
+  walk_forward_id: string;
+  user_id?: string;
+  label?: string;
+  state: string;
+  train_hours: number;
+  test_hours: number;
+  step_hours: number;
+  anchored: boolean;
+  params?: BacktestSweepParam[];
+  rank_by: string;
+  symbols: string[];
+  start_ts: number;
+  end_ts: number;
+  windows: BacktestWalkForwardWindow[];
+  summary: BacktestWalkForwardSummary;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface BacktestWalkForwardsResponse {
+  total: number;
+  items: BacktestWalkForward[];
+}
+
 // Kline data for backtest chart
 export interface BacktestKline {
   time: number;
@@ -437,6 +969,35 @@ export interface Strategy {
   updated_at: string;
 }
 
+// 签名策略包（跨实例分享）
+export interface StrategyBundle {
+  version: number;
+  name: string;
+  description: string;
+  strategy_version: string;
+  source_strategy_id: string;
+  exported_at: string;
+  config: StrategyConfig;
+  signer: string;               // 导出实例的 Ed25519 公钥 (base64)
+  signature: string;
+}
+
+// 导入策略包的来源记录
+export interface StrategyImport {
+  id: string;
+  strategy_id: string;
+  bundle_name: string;
+  bundle_version: number;
+  strategy_version: string;
+  source_strategy_id: string;
+  signer_key: string;
+  signer_fingerprint: string;
+  self_signed: boolean;         // 由本实例签名
+  content_hash: string;
+  exported_at: string;
+  imported_at: string;
+}
+
 // 策略使用统计
 export interface StrategyStats {
   clone_count: number;          // 被克隆次数
@@ -463,6 +1024,33 @@ export interface PromptSectionsConfig {
   decision_process?: string;
 }
 
+// Prompt token budget: oversized prompts are trimmed (recent trades, klines, candidate coins) to fit
+export interface PromptBudgetConfig {
+  // Max tokens of system + user prompt (0 = the AI model's context window minus room for the response)
+  max_prompt_tokens?: number;
+}
+
+// Post-trade reflections: after a position closes the AI reviews it, recent reviews are included in later prompts
+export interface ReflectionConfig {
+  enabled: boolean;
+  // Reflections included in the prompt (0 = 5)
+  prompt_entries?: number;
+  // Max characters of each reflection (0 = 1000, also the upper limit)
+  max_chars?: number;
+}
+
+// Execution algorithm of opening orders: large entries are split into child orders
+export interface ExecutionConfig {
+  // "market" (default, never split), "twap" (market child orders over time) or "iceberg" (limit child orders at the best bid/ask)
+  algo?: 'market' | 'twap' | 'iceberg';
+  // Entries of at least this size in USDT are split (0 = only entries above the order book depth limit)
+  min_split_usd?: number;
+  // Child orders per entry (0 = 5, max 20)
+  slices?: number;
+  // Minutes the child orders are spread over (0 = 10)
+  duration_minutes?: number;
+}
+
 export interface StrategyConfig {
   // Strategy type: "ai_trading" (default) or "grid_trading"
   strategy_type?: 'ai_trading' | 'grid_trading';
@@ -472,8 +1060,14 @@ export interface StrategyConfig {
   coin_source: CoinSourceConfig;
   indicators: IndicatorConfig;
   custom_prompt?: string;
+  // Prompt template added before custom_prompt; version 0/empty = latest
+  prompt_template_id?: string;
+  prompt_template_version?: number;
   risk_control: RiskControlConfig;
   prompt_sections?: PromptSectionsConfig;
+  prompt_budget?: PromptBudgetConfig;
+  reflection?: ReflectionConfig;
+  execution?: ExecutionConfig;
   // Grid trading configuration (only used when strategy_type is 'grid_trading')
   grid_config?: GridStrategyConfig;
 }
@@ -482,6 +1076,8 @@ export interface StrategyConfig {
 export interface GridStrategyConfig {
   // Trading pair (e.g., "BTCUSDT")
   symbol: string;
+  // Market the grid trades: "futures" (default) or "spot" (no leverage, no shorting)
+  market_type?: 'futures' | 'spot';
   // Number of grid levels (5-50)
   grid_count: number;
   // Total investment in USDT
@@ -512,6 +1108,45 @@ export interface GridStrategyConfig {
   direction_bias_ratio?: number;
 }
 
+export interface GridSuggestRequest {
+  symbol: string
+  investment: number // USDT
+  market_type?: 'futures' | 'spot'
+  max_leverage?: number // Default 5
+  ai_model_id?: string // Empty = heuristics only
+}
+
+// Market data a grid suggestion is based on (1h klines)
+export interface GridSuggestMarket {
+  symbol: string
+  regime: 'trending' | 'volatile' | 'ranging'
+  level: string // narrow | standard | wide | volatile | trending
+  bollinger_width_pct: number
+  atr_pct: number // ATR14 / price × 100
+  breakout: string
+  breakout_direction?: 'up' | 'down'
+  box: {
+    short_upper: number
+    short_lower: number
+    mid_upper: number
+    mid_lower: number
+    long_upper: number
+    long_lower: number
+    current_price: number
+  }
+  updated_at: string
+}
+
+export interface GridSuggestion {
+  config: GridStrategyConfig // Draft ready to save (AI refined when source is "ai")
+  heuristic: GridStrategyConfig
+  source: 'ai' | 'heuristic'
+  reasoning: string[]
+  ai_reasoning?: string
+  ai_error?: string // AI call or its answer failed, config holds the heuristics
+  market: GridSuggestMarket
+}
+
 export interface CoinSourceConfig {
   source_type: 'static' | 'ai500' | 'oi_top' | 'oi_low' | 'mixed';
   static_coins?: string[];
@@ -525,8 +1160,21 @@ export interface CoinSourceConfig {
   // Note: API URLs are now built automatically using nofxos_api_key from IndicatorConfig
 }
 
+export type MarketDataProviderName = 'coinank' | 'binance' | 'bybit'
+
+// Kline provider health in the failover chain
+export interface MarketDataProviderStatus {
+  name: MarketDataProviderName
+  healthy: boolean
+  failures: number // Consecutive failures
+  last_error?: string
+  last_success?: string
+  down_until?: string // Skipped until then unless no other provider works
+}
+
 export interface IndicatorConfig {
   klines: KlineConfig;
+  market_data_providers?: MarketDataProviderName[]; // Tried first, in order; the others remain failovers (empty = server default)
   // Raw OHLCV kline data - required for AI analysis
   enable_raw_klines: boolean;
   // Technical indicators (optional)
@@ -538,10 +1186,22 @@ export interface IndicatorConfig {
   enable_volume: boolean;
   enable_oi: boolean;
   enable_funding_rate: boolean;
+  enable_order_book?: boolean;     // Order book spread/depth/imbalance of candidate coins
+  order_book_levels?: number;      // Levels per side summarized (default: 20)
+  enable_news?: boolean;           // Latest crypto headlines (requires NEWS_FEED_URLS on the server)
+  enable_economic_calendar?: boolean; // Upcoming macro events (requires ECONOMIC_CALENDAR_URL on the server)
+  news_headline_limit?: number;    // Headlines shown (default: 10)
+  enable_sentiment?: boolean;      // Fear & Greed index and social sentiment (requires SENTIMENT_* on the server)
   ema_periods?: number[];
   rsi_periods?: number[];
   atr_periods?: number[];
   boll_periods?: number[];
+  // Extended indicators (computed server-side)
+  enable_vwap?: boolean;
+  enable_supertrend?: boolean;
+  enable_ichimoku?: boolean;
+  enable_obv?: boolean;
+  enable_stoch_rsi?: boolean;
   external_data_sources?: ExternalDataSource[];
 
   // ========== NofxOS 数据源统一配置 ==========
@@ -577,6 +1237,13 @@ export interface KlineConfig {
   enable_multi_timeframe: boolean;
   // 新增：支持选择多个时间周期
   selected_timeframes?: string[];
+  // 多周期摘要：每个周期一行摘要及其权重（为空则不启用）
+  timeframe_weights?: TimeframeWeight[];
+}
+
+export interface TimeframeWeight {
+  timeframe: string;
+  weight: number; // 相对权重，按所有摘要周期归一化
 }
 
 export interface ExternalDataSource {
@@ -605,8 +1272,28 @@ export interface RiskControlConfig {
   // Risk Parameters
   max_margin_usage: number;        // Max margin utilization, e.g. 0.9 = 90% (CODE ENFORCED)
   min_position_size: number;       // Min position size in USDT (CODE ENFORCED)
-  min_risk_reward_ratio: number;   // Min take_profit / stop_loss ratio (AI guided)
+  liquidation_buffer_pct?: number; // Min stop loss distance from liquidation, % of entry (CODE ENFORCED, default: 1)
+  margin_buffer_pct?: number;      // Free balance kept on top of a new position's margin and fees, % of its margin (CODE ENFORCED, default: 2)
+  risk_sizing_enabled?: boolean;   // Rescale AI position size so the stop loss risks risk_per_trade_pct of equity (CODE ENFORCED)
+  risk_per_trade_pct?: number;     // Equity % risked per trade by auto sizing (default: 1)
+  atr_stop_multiplier?: number;    // Auto sizing stop distance = this × ATR when there is no stop loss (default: 2)
+  max_depth_pct?: number;          // Warn when an open exceeds this % of visible order book depth (default: 10)
+  block_entries_near_event_mins?: number; // Veto opens within N min of a high-impact economic event (CODE ENFORCED, 0 = off)
+  min_risk_reward_ratio: number;   // Min take_profit / stop_loss ratio of new positions (CODE ENFORCED, default: 3)
+  disable_risk_reward_check?: boolean; // Skip the risk/reward check
   min_confidence: number;          // Min AI confidence to open position (AI guided)
+  max_holding_hours?: number;      // Positions held longer are flagged to the AI or closed (CODE ENFORCED, 0 = off)
+  max_holding_action?: 'flag' | 'close';
+  avoid_funding_rate_pct?: number;    // Positions paying at least this funding rate % are closed before settlement (CODE ENFORCED, 0 = off)
+  avoid_funding_window_mins?: number; // Minutes before settlement (default: 30)
+  take_profit_ladder?: TakeProfitStep[]; // Default partial take profits of market entries (CODE ENFORCED)
+  runner_trailing_stop_pct?: number;     // Trailing stop of the rest after the ladder, % from the best price (0 = off)
+}
+
+// One step of a take profit ladder: close close_pct % of the position at +profit_pct %
+export interface TakeProfitStep {
+  profit_pct: number;
+  close_pct: number;
 }
 
 // Debate Arena Types
@@ -825,3 +1512,81 @@ export interface GridRiskInfo {
   breakout_level: string
   breakout_direction: string
 }
+
+// Captured trader log line (GET /api/traders/:id/logs)
+export interface TraderLogEntry {
+  seq: number
+  source: string
+  time: string
+  level: string
+  message: string
+}
+
+// 交易图表：K线 + 交易员的开平仓标记与止损/止盈价位 (GET /api/traders/:id/chart)
+export interface ChartMarker {
+  time: number // Unix 毫秒
+  type: 'entry' | 'exit'
+  side: 'LONG' | 'SHORT'
+  price: number
+  quantity: number
+  position_id: number
+  realized_pnl?: number // 仅平仓
+  reason?: string // 平仓原因
+}
+
+export interface ChartLevel {
+  type: 'stop_loss' | 'take_profit'
+  side: 'LONG' | 'SHORT'
+  price: number
+  from: number // Unix 毫秒
+  to: number // 0 = 持仓中
+  position_id: number
+}
+
+export interface TraderChart {
+  symbol: string
+  timeframe: string
+  exchange: string
+  klines: {
+    openTime: number
+    open: number
+    high: number
+    low: number
+    close: number
+    volume: number
+    closeTime: number
+  }[]
+  markers: ChartMarker[]
+  levels: ChartLevel[]
+}
+
+// IP 白名单（CIDR 或单个 IP，空列表表示不限制）
+export interface IPAllowlists {
+  trading: string[] // 所有需要登录的接口
+  admin: string[] // 管理接口（在 trading 之外额外校验）
+}
+
+// 用户数据导出（口令加密的归档，可导入到其他部署）
+export interface UserArchive {
+  format: string // nofx-user-export
+  version: number
+  exported_at: string
+  envelope: {
+    kdf: string
+    n: number
+    r: number
+    p: number
+    salt: string
+    nonce: string
+    ciphertext: string
+  }
+}
+
+export interface UserImportResult {
+  ai_models: number
+  exchanges: number
+  strategies: number
+  traders: number
+  decisions: number
+  renamed_ids: Record<string, string> // 原 ID -> 新 ID（仅 ID 冲突时）
+}